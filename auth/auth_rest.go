@@ -5,49 +5,79 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"schneider.vip/problem"
 )
 
 type loginModel struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username        string `json:"username"`
+	Password        string `json:"password"`
+	CaptchaResponse string `json:"captchaResponse,omitempty"`
 }
 
 type loginResponseModel struct {
 	AccessToken string `json:"access_token"`
 }
 
+type sessionModel struct {
+	Username       string     `json:"username"`
+	Name           string     `json:"name"`
+	OrganizationID string     `json:"organizationId"`
+	Roles          []string   `json:"roles"`
+	Impersonated   bool       `json:"impersonated"`
+	ImpersonatedBy string     `json:"impersonatedBy,omitempty"`
+	ExpiresAt      string     `json:"expiresAt,omitempty"`
+	Links          *hal.Links `json:"_links"`
+}
+
 type AuthRestHandlers struct {
-	config      *shared.Config
-	authService *AuthService
-	tokenAuth   *jwtauth.JWTAuth
+	config       *shared.Config
+	authService  *AuthService
+	tokenAuth    *jwtauth.JWTAuth
+	captchaGuard *shared.CaptchaGuard
 }
 
-func NewAuthRestHandlers(config *shared.Config, authService *AuthService, tokenAuth *jwtauth.JWTAuth) *AuthRestHandlers {
+func NewAuthRestHandlers(config *shared.Config, authService *AuthService, tokenAuth *jwtauth.JWTAuth, captchaGuard *shared.CaptchaGuard) *AuthRestHandlers {
 	return &AuthRestHandlers{
-		config:      config,
-		authService: authService,
-		tokenAuth:   tokenAuth,
+		config:       config,
+		authService:  authService,
+		tokenAuth:    tokenAuth,
+		captchaGuard: captchaGuard,
 	}
 }
 
 func (a *AuthRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/auth/session", a.HandleGetSession())
 }
 
 func (a *AuthRestHandlers) RegisterOpen(r chi.Router) {
 	r.Post("/auth/login", a.HandleLogin())
+	r.Post("/client-portal/login", a.HandleClientLogin())
 }
 
 // HandleLogin handles the authentication request of a user
 func (a *AuthRestHandlers) HandleLogin() http.HandlerFunc {
+	return a.handleLogin(a.authService.Authenticate)
+}
+
+// HandleClientLogin handles the authentication request of a client user to the client portal
+func (a *AuthRestHandlers) HandleClientLogin() http.HandlerFunc {
+	return a.handleLogin(a.authService.AuthenticateClient)
+}
+
+func (a *AuthRestHandlers) handleLogin(authenticate func(ctx context.Context, username, password string) (*shared.Principal, error)) http.HandlerFunc {
+	isProduction := a.config.IsProduction()
 	tokenAuth := a.tokenAuth
 	expiryDuration := a.config.ExpiryDuration()
 	authService := a.authService
+	captchaGuard := a.captchaGuard
 	return func(w http.ResponseWriter, r *http.Request) {
 		var loginModel loginModel
 		err := json.NewDecoder(r.Body).Decode(&loginModel)
@@ -56,12 +86,28 @@ func (a *AuthRestHandlers) HandleLogin() http.HandlerFunc {
 			return
 		}
 
-		principal, err := authService.Authenticate(r.Context(), loginModel.Username, loginModel.Password)
+		captchaKeys := loginCaptchaKeys(r, loginModel.Username)
+		err = captchaGuard.Check(r.Context(), loginModel.CaptchaResponse, shared.RemoteIP(r), captchaKeys...)
 		if err != nil {
-			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusForbidden)
+			shared.RenderProblemJSON(w, isProduction, err)
 			return
 		}
 
+		principal, err := authenticate(r.Context(), loginModel.Username, loginModel.Password)
+		if errors.Is(err, ErrSSORequired) {
+			captchaGuard.Reset(captchaKeys[0])
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+		if err != nil {
+			captchaGuard.RecordAttempt(captchaKeys...)
+			shared.RenderProblemJSON(w, isProduction, shared.ErrLoginFailed)
+			return
+		}
+
+		captchaGuard.Reset(captchaKeys[0])
+		authService.RecordLogin(r.Context(), principal, r.UserAgent(), shared.RemoteIP(r))
+
 		cookie := authService.CreateCookie(tokenAuth, expiryDuration, principal)
 		http.SetCookie(w, &cookie)
 
@@ -70,17 +116,48 @@ func (a *AuthRestHandlers) HandleLogin() http.HandlerFunc {
 	}
 }
 
+// HandleGetSession reads the session of the principal, an impersonated session shows a banner to the instance admin
+func (a *AuthRestHandlers) HandleGetSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		sessionModel := &sessionModel{
+			Username:       principal.Username,
+			Name:           principal.Name,
+			OrganizationID: principal.OrganizationID.String(),
+			Roles:          principal.Roles,
+			Impersonated:   principal.IsImpersonated(),
+			ImpersonatedBy: principal.ImpersonatedBy,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		if token, _, _ := jwtauth.FromContext(r.Context()); token != nil && !token.Expiration().IsZero() {
+			sessionModel.ExpiresAt = token.Expiration().Format(time.RFC3339)
+		}
+
+		shared.RenderJSON(w, sessionModel)
+	}
+}
+
 func (a *AuthRestHandlers) JWTVerifier() func(next http.Handler) http.Handler {
 	return jwtauth.Verifier(a.tokenAuth)
 }
 
-// JWTPrincipalMiddleware sets up the user principal from the JWT
+// JWTPrincipalMiddleware sets up the user principal from the JWT unless a service account is authenticated
 func (a *AuthRestHandlers) JWTPrincipalMiddleware() func(next http.Handler) http.Handler {
+	isProduction := a.config.IsProduction()
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// service accounts are already authenticated by their api token
+			if principal, ok := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal); ok && principal.IsServiceAccount() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			token, claims, _ := jwtauth.FromContext(r.Context())
 			if token == nil {
-				w.WriteHeader(http.StatusUnauthorized)
+				shared.RenderProblemJSON(w, isProduction, shared.ErrUnauthorized)
 				return
 			}
 
@@ -93,19 +170,27 @@ func (a *AuthRestHandlers) JWTPrincipalMiddleware() func(next http.Handler) http
 }
 
 func mapPrincipalToClaims(principal *shared.Principal) map[string]interface{} {
-	return map[string]interface{}{
+	claims := map[string]interface{}{
 		"name":           principal.Name,
 		"username":       principal.Username,
 		"organizationId": principal.OrganizationID.String(),
 		"roles":          strings.Join(principal.Roles, ","),
 	}
+	if principal.IsImpersonated() {
+		claims["impersonatedBy"] = principal.ImpersonatedBy
+	}
+	return claims
 }
 
 func mapPrincipalFromClaims(claims map[string]interface{}) *shared.Principal {
-	return &shared.Principal{
+	principal := &shared.Principal{
 		Name:           claims["name"].(string),
 		Username:       claims["username"].(string),
 		OrganizationID: uuid.MustParse(claims["organizationId"].(string)),
 		Roles:          strings.Split(claims["roles"].(string), ","),
 	}
+	if impersonatedBy, ok := claims["impersonatedBy"].(string); ok {
+		principal.ImpersonatedBy = impersonatedBy
+	}
+	return principal
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/baralga/shared"
 	"github.com/baralga/user"
 	"github.com/go-chi/jwtauth/v5"
+	"github.com/google/uuid"
 	"github.com/matryer/is"
 )
 
@@ -24,11 +25,13 @@ func TestHandleLogin(t *testing.T) {
 	}
 
 	a := &AuthRestHandlers{
-		config:    config,
-		tokenAuth: tokenAuth,
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       config,
+		tokenAuth:    tokenAuth,
 		authService: &AuthService{
 			config:         config,
 			userRepository: user.NewInMemUserRepository(),
+			passwordHasher: shared.NewBcryptPasswordHasher(10),
 		},
 	}
 
@@ -56,10 +59,12 @@ func TestHandleInvalidLogin(t *testing.T) {
 
 	tokenAuth := jwtauth.New("HS256", []byte("secret"), nil)
 	a := &AuthRestHandlers{
-		config:    &shared.Config{},
-		tokenAuth: tokenAuth,
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
+		tokenAuth:    tokenAuth,
 		authService: &AuthService{
 			userRepository: user.NewInMemUserRepository(),
+			passwordHasher: shared.NewBcryptPasswordHasher(10),
 		},
 	}
 
@@ -75,15 +80,49 @@ func TestHandleInvalidLogin(t *testing.T) {
 	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
 }
 
+func TestHandleClientLogin(t *testing.T) {
+	is := is.New(t)
+
+	tokenAuth := jwtauth.New("HS256", []byte("secret"), nil)
+	config := &shared.Config{
+		JWTExpiry: "1h",
+	}
+
+	a := &AuthRestHandlers{
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       config,
+		tokenAuth:    tokenAuth,
+		authService: &AuthService{
+			config:         config,
+			userRepository: user.NewInMemUserRepository(),
+			passwordHasher: shared.NewBcryptPasswordHasher(10),
+		},
+	}
+
+	login := func(handler http.HandlerFunc, username string) int {
+		httpRec := httptest.NewRecorder()
+		body := `{"username": "` + username + `", "password": "adm1n"}`
+		r, _ := http.NewRequest("POST", "/api/client-portal/login", strings.NewReader(body))
+		handler(httpRec, r)
+		return httpRec.Result().StatusCode
+	}
+
+	is.Equal(login(a.HandleClientLogin(), "client@acme.com"), http.StatusOK)
+	is.Equal(login(a.HandleClientLogin(), "admin@baralga.com"), http.StatusForbidden)
+	is.Equal(login(a.HandleLogin(), "client@acme.com"), http.StatusForbidden)
+}
+
 func TestHandleLoginWithInvalidDuration(t *testing.T) {
 	tokenAuth := jwtauth.New("HS256", []byte("secret"), nil)
 	a := &AuthRestHandlers{
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
 		config: &shared.Config{
 			JWTExpiry: "invalid",
 		},
 		tokenAuth: tokenAuth,
 		authService: &AuthService{
 			userRepository: user.NewInMemUserRepository(),
+			passwordHasher: shared.NewBcryptPasswordHasher(10),
 		},
 	}
 
@@ -116,7 +155,8 @@ func TestJWTPrincipalHandlerWithoutJWT(t *testing.T) {
 	httpRec := httptest.NewRecorder()
 
 	a := &AuthRestHandlers{
-		config: &shared.Config{},
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
 	}
 
 	r, _ := http.NewRequest("GET", "/api/projects", nil)
@@ -128,3 +168,22 @@ func TestJWTPrincipalHandlerWithoutJWT(t *testing.T) {
 
 	is.Equal(httpRec.Result().StatusCode, http.StatusUnauthorized)
 }
+
+func TestJWTPrincipalHandlerWithServiceAccount(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &AuthRestHandlers{
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
+	}
+
+	r, _ := http.NewRequest("GET", "/api/projects", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{ServiceAccountID: uuid.New()}))
+
+	a.JWTPrincipalMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusIMUsed)
+	})).ServeHTTP(httpRec, r)
+
+	is.Equal(httpRec.Result().StatusCode, http.StatusIMUsed)
+}
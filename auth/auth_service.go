@@ -2,30 +2,68 @@ package auth
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/baralga/shared"
 	"github.com/baralga/user"
 	"github.com/go-chi/jwtauth/v5"
-	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/pkg/errors"
-	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrClientLoginRequired = errors.New("client users have to log in to the client portal")
+	ErrNoClientUser        = errors.New("user is no client user")
 )
 
 type AuthService struct {
-	config         *shared.Config
-	userRepository user.UserRepository
+	config              *shared.Config
+	repositoryTxer      shared.RepositoryTxer
+	userRepository      user.UserRepository
+	passwordHasher      shared.PasswordHasher
+	loginAlertService   *LoginAlertService
+	ssoPolicyRepository SSOPolicyRepository
 }
 
-func NewAuthService(config *shared.Config, UserRepository user.UserRepository) *AuthService {
+func NewAuthService(config *shared.Config, repositoryTxer shared.RepositoryTxer, UserRepository user.UserRepository, passwordHasher shared.PasswordHasher, loginAlertService *LoginAlertService, ssoPolicyRepository SSOPolicyRepository) *AuthService {
 	return &AuthService{
-		config:         config,
-		userRepository: UserRepository,
+		config:              config,
+		repositoryTxer:      repositoryTxer,
+		userRepository:      UserRepository,
+		passwordHasher:      passwordHasher,
+		loginAlertService:   loginAlertService,
+		ssoPolicyRepository: ssoPolicyRepository,
 	}
 }
 
+// Authenticate authenticates a user by username and password, client users have to use the client portal login
 func (a *AuthService) Authenticate(ctx context.Context, username, password string) (*shared.Principal, error) {
+	principal, err := a.authenticate(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if principal.HasRole("ROLE_CLIENT") {
+		return nil, ErrClientLoginRequired
+	}
+	return principal, nil
+}
+
+// AuthenticateClient authenticates a client user for the client portal by username and password
+func (a *AuthService) AuthenticateClient(ctx context.Context, username, password string) (*shared.Principal, error) {
+	principal, err := a.authenticate(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if !principal.HasRole("ROLE_CLIENT") {
+		return nil, ErrNoClientUser
+	}
+	return principal, nil
+}
+
+func (a *AuthService) authenticate(ctx context.Context, username, password string) (*shared.Principal, error) {
 	u, err := a.userRepository.FindUserByUsername(ctx, username)
 	if errors.Is(err, user.ErrUserNotFound) {
 		return nil, err
@@ -34,11 +72,27 @@ func (a *AuthService) Authenticate(ctx context.Context, username, password strin
 		return nil, err
 	}
 
-	passwdErr := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
-	if passwdErr != nil {
+	// the login of a custom domain is only for the users of the organization of the domain
+	customDomain := shared.CustomDomainOf(ctx)
+	if customDomain != nil && customDomain.OrganizationID != u.OrganizationID {
+		return nil, user.ErrUserNotFound
+	}
+
+	if !a.passwordHasher.Verify(u.Password, password) {
+		a.recordFailedLogin(ctx, u)
 		return nil, errors.New("password invalid")
 	}
 
+	// the policy is checked after the password, so it does not tell others which users must use single sign-on
+	err = a.checkPasswordLoginAllowed(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.passwordHasher.NeedsRehash(u.Password) {
+		a.rehashPassword(ctx, u, password)
+	}
+
 	roles, err := a.userRepository.FindRolesByUserID(ctx, u.OrganizationID, u.ID)
 	if err != nil {
 		return nil, err
@@ -48,6 +102,121 @@ func (a *AuthService) Authenticate(ctx context.Context, username, password strin
 	return principal, nil
 }
 
+// checkPasswordLoginAllowed fails with ErrSSORequired if a single sign-on policy of the
+// user's organization enforces the login with the provider for the user
+func (a *AuthService) checkPasswordLoginAllowed(ctx context.Context, u *user.User) error {
+	if a.ssoPolicyRepository == nil {
+		return nil
+	}
+
+	emailDomain := EMailDomainOf(u.EMail)
+	if emailDomain == "" {
+		return nil
+	}
+
+	policy, err := a.ssoPolicyRepository.FindSSOPolicy(ctx, u.OrganizationID, emailDomain)
+	if errors.Is(err, ErrSSOPolicyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if policy.Enforces(u) {
+		return ErrSSORequired
+	}
+	return nil
+}
+
+// AuthenticateSSO authenticates the user with the verified email address of the provider, if a single sign-on
+// policy of the user's organization is set up for the email domain and provider
+func (a *AuthService) AuthenticateSSO(ctx context.Context, provider, email string) (*shared.Principal, error) {
+	emailDomain := EMailDomainOf(email)
+	if a.ssoPolicyRepository == nil || emailDomain == "" {
+		return nil, user.ErrUserNotFound
+	}
+
+	policies, err := a.ssoPolicyRepository.FindSSOPoliciesByEMailDomain(ctx, emailDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	customDomain := shared.CustomDomainOf(ctx)
+	for _, policy := range policies {
+		if policy.Provider != provider {
+			continue
+		}
+		if customDomain != nil && customDomain.OrganizationID != policy.OrganizationID {
+			continue
+		}
+
+		u, err := a.userRepository.FindUserByEMail(ctx, policy.OrganizationID, email)
+		if errors.Is(err, user.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		roles, err := a.userRepository.FindRolesByUserID(ctx, u.OrganizationID, u.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		principal := mapUserToPrincipal(u, roles)
+		if principal.HasRole("ROLE_CLIENT") {
+			return nil, ErrClientLoginRequired
+		}
+		return principal, nil
+	}
+
+	return nil, user.ErrUserNotFound
+}
+
+// rehashPassword replaces the password hash of the user after a successful login with a hash of the
+// configured algorithm and parameters, the login succeeds even if the hash could not be replaced
+func (a *AuthService) rehashPassword(ctx context.Context, u *user.User, password string) {
+	encryptedPassword, err := a.passwordHasher.Hash(password)
+	if err != nil {
+		log.Printf("could not rehash password of user %v: %v", u.ID, err)
+		return
+	}
+
+	err = a.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return a.userRepository.UpdatePassword(ctx, u.OrganizationID, u.ID, encryptedPassword)
+		},
+	)
+	if err != nil {
+		log.Printf("could not rehash password of user %v: %v", u.ID, err)
+	}
+}
+
+// RecordLogin remembers the device of the login to notify the user about logins from new devices,
+// the login succeeds even if the device could not be recorded
+func (a *AuthService) RecordLogin(ctx context.Context, principal *shared.Principal, userAgent, remoteIP string) {
+	if a.loginAlertService == nil {
+		return
+	}
+
+	err := a.loginAlertService.RecordSuccessfulLogin(ctx, principal, userAgent, remoteIP, time.Now())
+	if err != nil {
+		log.Printf("could not record login of user %v: %v", principal.Username, err)
+	}
+}
+
+func (a *AuthService) recordFailedLogin(ctx context.Context, u *user.User) {
+	if a.loginAlertService == nil {
+		return
+	}
+
+	err := a.loginAlertService.RecordFailedLogin(ctx, u.OrganizationID, u.Username, time.Now())
+	if err != nil {
+		log.Printf("could not record failed login of user %v: %v", u.ID, err)
+	}
+}
+
 func mapUserToPrincipal(user *user.User, roles []string) *shared.Principal {
 	principal := &shared.Principal{
 		Name:           user.Name,
@@ -76,19 +245,23 @@ func (a *AuthService) AuthenticateTrusted(ctx context.Context, username string)
 	}
 
 	principal := mapUserToPrincipal(u, roles)
+	if principal.HasRole("ROLE_CLIENT") {
+		return nil, ErrClientLoginRequired
+	}
 	return principal, nil
 }
 
 func (a *AuthService) CreateCookie(tokenAuth *jwtauth.JWTAuth, expiryDuration time.Duration, principal *shared.Principal) http.Cookie {
 	claims := mapPrincipalToClaims(principal)
-	claims[jwt.ExpirationKey] = expiryDuration
+	jwtauth.SetExpiry(claims, time.Now().Add(expiryDuration))
 
 	_, tokenString, _ := tokenAuth.Encode(claims)
 
 	return http.Cookie{
-		Name:     "jwt",
+		Name:     shared.SessionCookieName,
 		Value:    tokenString,
 		Expires:  time.Now().Add(expiryDuration),
+		HttpOnly: true,
 		SameSite: http.SameSiteLaxMode,
 		Secure:   a.config.IsProduction(),
 		Path:     "/",
@@ -97,9 +270,10 @@ func (a *AuthService) CreateCookie(tokenAuth *jwtauth.JWTAuth, expiryDuration ti
 
 func (a *AuthService) CreateExpiredCookie() http.Cookie {
 	return http.Cookie{
-		Name:     "jwt",
+		Name:     shared.SessionCookieName,
 		Value:    "",
 		Expires:  time.Now(),
+		HttpOnly: true,
 		SameSite: http.SameSiteLaxMode,
 		Secure:   a.config.IsProduction(),
 		Path:     "/",
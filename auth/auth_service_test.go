@@ -2,11 +2,13 @@ package auth
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/baralga/shared"
 	"github.com/baralga/user"
+	"github.com/google/uuid"
 	"github.com/matryer/is"
 	"github.com/pkg/errors"
 )
@@ -18,6 +20,7 @@ func TestAuthenticateTrustedWithExistingUser(t *testing.T) {
 	a := &AuthService{
 		config:         &shared.Config{},
 		userRepository: user.NewInMemUserRepository(),
+		passwordHasher: shared.NewBcryptPasswordHasher(10),
 	}
 	username := "admin@baralga.com"
 
@@ -35,6 +38,7 @@ func TestAuthenticateTrustedWithMissingUser(t *testing.T) {
 	a := &AuthService{
 		config:         &shared.Config{},
 		userRepository: user.NewInMemUserRepository(),
+		passwordHasher: shared.NewBcryptPasswordHasher(10),
 	}
 	username := "not.found@baralga.com"
 
@@ -60,3 +64,194 @@ func TestCreateExpiredCookie(t *testing.T) {
 	is.Equal("jwt", cookie.Name)
 	is.Equal("/", cookie.Path)
 }
+
+func TestAuthenticateClient(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+	a := &AuthService{
+		config:         &shared.Config{},
+		userRepository: user.NewInMemUserRepository(),
+		passwordHasher: shared.NewBcryptPasswordHasher(10),
+	}
+
+	// Act
+	principal, err := a.AuthenticateClient(context.Background(), "client@acme.com", "adm1n")
+
+	// Assert
+	is.NoErr(err)
+	is.True(principal.HasRole("ROLE_CLIENT"))
+
+	_, err = a.Authenticate(context.Background(), "client@acme.com", "adm1n")
+	is.True(errors.Is(err, ErrClientLoginRequired))
+
+	_, err = a.AuthenticateClient(context.Background(), "admin@baralga.com", "adm1n")
+	is.True(errors.Is(err, ErrNoClientUser))
+
+	_, err = a.AuthenticateTrusted(context.Background(), "client@acme.com")
+	is.True(errors.Is(err, ErrClientLoginRequired))
+}
+
+func TestAuthenticateRehashesPassword(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	argon2idHasher, err := shared.NewArgon2idPasswordHasher(1, 64, 1)
+	is.NoErr(err)
+
+	userRepository := user.NewInMemUserRepository()
+	a := &AuthService{
+		config:         &shared.Config{},
+		repositoryTxer: shared.NewInMemRepositoryTxer(),
+		userRepository: userRepository,
+		passwordHasher: shared.NewMigratingPasswordHasher(argon2idHasher, shared.NewBcryptPasswordHasher(10)),
+	}
+	username := "admin@baralga.com"
+
+	// Act
+	_, err = a.Authenticate(context.Background(), username, "adm1n")
+
+	// Assert
+	is.NoErr(err)
+
+	u, err := userRepository.FindUserByUsername(context.Background(), username)
+	is.NoErr(err)
+	is.True(strings.HasPrefix(u.Password, "$argon2id$"))
+
+	_, err = a.Authenticate(context.Background(), username, "adm1n")
+	is.NoErr(err)
+}
+
+func TestAuthenticateRecordsFailedLogin(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	loginAlertRepository := NewInMemLoginAlertRepository()
+	config := &shared.Config{LoginAlerts: true, LoginFailureAlertThreshold: 20, LoginFailureAlertWindow: "15m"}
+	a := &AuthService{
+		config:            config,
+		userRepository:    user.NewInMemUserRepository(),
+		passwordHasher:    shared.NewBcryptPasswordHasher(10),
+		loginAlertService: newInMemLoginAlertService(config, shared.NewInMemMailResource(), loginAlertRepository),
+	}
+
+	// Act
+	_, err := a.Authenticate(context.Background(), "admin@baralga.com", "-invalid-")
+
+	// Assert
+	is.True(err != nil)
+
+	failedLogins, err := loginAlertRepository.CountFailedLogins(context.Background(), shared.OrganizationIDSample, time.Now().Add(-time.Minute))
+	is.NoErr(err)
+	is.Equal(failedLogins, 1)
+}
+
+func TestAuthenticateOnCustomDomain(t *testing.T) {
+	is := is.New(t)
+	a := &AuthService{
+		config:         &shared.Config{},
+		userRepository: user.NewInMemUserRepository(),
+		passwordHasher: shared.NewBcryptPasswordHasher(10),
+	}
+
+	t.Run("user of organization of custom domain", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), shared.ContextKeyCustomDomain, &shared.CustomDomain{OrganizationID: shared.OrganizationIDSample})
+
+		principal, err := a.Authenticate(ctx, "admin@baralga.com", "adm1n")
+		is.NoErr(err)
+		is.Equal(principal.OrganizationID, shared.OrganizationIDSample)
+	})
+
+	t.Run("user of other organization", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), shared.ContextKeyCustomDomain, &shared.CustomDomain{OrganizationID: uuid.New()})
+
+		_, err := a.Authenticate(ctx, "admin@baralga.com", "adm1n")
+		is.True(errors.Is(err, user.ErrUserNotFound))
+	})
+}
+
+func TestAuthenticateWithSSOPolicy(t *testing.T) {
+	is := is.New(t)
+
+	ssoPolicyRepository := NewInMemSSOPolicyRepository()
+	a := &AuthService{
+		config:              &shared.Config{},
+		userRepository:      user.NewInMemUserRepository(),
+		passwordHasher:      shared.NewBcryptPasswordHasher(10),
+		ssoPolicyRepository: ssoPolicyRepository,
+	}
+	policy := &SSOPolicy{
+		OrganizationID: shared.OrganizationIDSample,
+		EMailDomain:    "baralga.com",
+		Provider:       SSOProviderGoogle,
+	}
+	err := ssoPolicyRepository.UpsertSSOPolicy(context.Background(), policy)
+	is.NoErr(err)
+
+	t.Run("password login of email domain blocked", func(t *testing.T) {
+		_, err := a.Authenticate(context.Background(), "admin@baralga.com", "adm1n")
+		is.True(errors.Is(err, ErrSSORequired))
+	})
+
+	t.Run("invalid password does not reveal policy", func(t *testing.T) {
+		_, err := a.Authenticate(context.Background(), "admin@baralga.com", "-invalid-")
+		is.True(err != nil)
+		is.True(!errors.Is(err, ErrSSORequired))
+	})
+
+	t.Run("password login of other email domain", func(t *testing.T) {
+		_, err := a.AuthenticateClient(context.Background(), "client@acme.com", "adm1n")
+		is.NoErr(err)
+	})
+
+	t.Run("password login of exception", func(t *testing.T) {
+		policy.Exceptions = []string{"admin@baralga.com"}
+		err := ssoPolicyRepository.UpsertSSOPolicy(context.Background(), policy)
+		is.NoErr(err)
+
+		_, err = a.Authenticate(context.Background(), "admin@baralga.com", "adm1n")
+		is.NoErr(err)
+	})
+}
+
+func TestAuthenticateSSO(t *testing.T) {
+	is := is.New(t)
+
+	ssoPolicyRepository := NewInMemSSOPolicyRepository()
+	a := &AuthService{
+		config:              &shared.Config{},
+		userRepository:      user.NewInMemUserRepository(),
+		passwordHasher:      shared.NewBcryptPasswordHasher(10),
+		ssoPolicyRepository: ssoPolicyRepository,
+	}
+
+	t.Run("without policy", func(t *testing.T) {
+		_, err := a.AuthenticateSSO(context.Background(), SSOProviderGoogle, "admin@baralga.com")
+		is.True(errors.Is(err, user.ErrUserNotFound))
+	})
+
+	err := ssoPolicyRepository.UpsertSSOPolicy(context.Background(), &SSOPolicy{
+		OrganizationID: shared.OrganizationIDSample,
+		EMailDomain:    "baralga.com",
+		Provider:       SSOProviderGoogle,
+	})
+	is.NoErr(err)
+
+	t.Run("user of policy", func(t *testing.T) {
+		principal, err := a.AuthenticateSSO(context.Background(), SSOProviderGoogle, "Admin@Baralga.com")
+		is.NoErr(err)
+		is.Equal(principal.Username, "admin@baralga.com")
+		is.Equal(principal.OrganizationID, shared.OrganizationIDSample)
+	})
+
+	t.Run("unknown user of policy", func(t *testing.T) {
+		_, err := a.AuthenticateSSO(context.Background(), SSOProviderGoogle, "new@baralga.com")
+		is.True(errors.Is(err, user.ErrUserNotFound))
+	})
+
+	t.Run("other custom domain", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), shared.ContextKeyCustomDomain, &shared.CustomDomain{OrganizationID: uuid.New()})
+
+		_, err := a.AuthenticateSSO(ctx, SSOProviderGoogle, "admin@baralga.com")
+		is.True(errors.Is(err, user.ErrUserNotFound))
+	})
+}
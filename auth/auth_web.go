@@ -38,21 +38,24 @@ type loginParams struct {
 	errorMessage string
 	infoMessage  string
 	redirect     string
+	captcha      g.Node
 }
 
 type AuthWebHandlers struct {
-	config      *shared.Config
-	authService *AuthService
-	userService *user.UserService
-	tokenAuth   *jwtauth.JWTAuth
+	config       *shared.Config
+	authService  *AuthService
+	userService  *user.UserService
+	tokenAuth    *jwtauth.JWTAuth
+	captchaGuard *shared.CaptchaGuard
 }
 
-func NewAuthWebHandlers(config *shared.Config, authService *AuthService, userService *user.UserService, tokenAuth *jwtauth.JWTAuth) *AuthWebHandlers {
+func NewAuthWebHandlers(config *shared.Config, authService *AuthService, userService *user.UserService, tokenAuth *jwtauth.JWTAuth, captchaGuard *shared.CaptchaGuard) *AuthWebHandlers {
 	return &AuthWebHandlers{
-		config:      config,
-		authService: authService,
-		userService: userService,
-		tokenAuth:   tokenAuth,
+		config:       config,
+		authService:  authService,
+		userService:  userService,
+		tokenAuth:    tokenAuth,
+		captchaGuard: captchaGuard,
 	}
 
 }
@@ -76,34 +79,64 @@ func (a *AuthWebHandlers) HandleLoginForm() http.HandlerFunc {
 	expiryDuration := a.config.ExpiryDuration()
 	authService := a.authService
 	tokenAuth := a.tokenAuth
+	captchaGuard := a.captchaGuard
 	return func(w http.ResponseWriter, r *http.Request) {
 		err := r.ParseForm()
 		if err != nil {
 			formModel := loginFormModel{}
 			formModel.CSRFToken = csrf.Token(r)
-			shared.RenderHTML(w, a.LoginPage(r.URL.Path, formModel, &loginParams{}))
+			shared.RenderHTML(w, a.LoginPage(r, formModel, &loginParams{}))
 			return
 		}
 
 		var formModel loginFormModel
-		err = schema.NewDecoder().Decode(&formModel, r.PostForm)
+		err = schema.NewDecoder().Decode(&formModel, shared.WithoutCaptchaResponse(r.PostForm))
 
 		if err != nil {
 			formModel.CSRFToken = csrf.Token(r)
-			shared.RenderHTML(w, a.LoginPage(r.URL.Path, formModel, &loginParams{}))
+			shared.RenderHTML(w, a.LoginPage(r, formModel, &loginParams{}))
+			return
+		}
+
+		captchaKeys := loginCaptchaKeys(r, formModel.EMail)
+		err = captchaGuard.CheckRequest(r, captchaKeys...)
+		if err != nil {
+			formModel.CSRFToken = csrf.Token(r)
+			loginParams := &loginParams{
+				errorMessage: "Please confirm that you are not a robot.",
+				captcha:      captchaGuard.Widget(captchaKeys...),
+			}
+			shared.RenderHTML(w, a.LoginPage(r, formModel, loginParams))
 			return
 		}
 
 		principal, err := authService.Authenticate(r.Context(), formModel.EMail, formModel.Password)
+		if errors.Is(err, ErrSSORequired) {
+			captchaGuard.Reset(captchaKeys[0])
+
+			formModel.CSRFToken = csrf.Token(r)
+			loginParams := &loginParams{
+				errorMessage: "Your organization requires you to sign in with Google.",
+			}
+			shared.RenderHTML(w, a.LoginPage(r, formModel, loginParams))
+			return
+		}
 		if err != nil {
+			captchaGuard.RecordAttempt(captchaKeys...)
+
 			formModel.CSRFToken = csrf.Token(r)
 			loginParams := &loginParams{
 				errorMessage: "Login failed. Please check your credentials and try again.",
+				captcha:      captchaGuard.Widget(captchaKeys...),
 			}
-			shared.RenderHTML(w, a.LoginPage(r.URL.Path, formModel, loginParams))
+			shared.RenderHTML(w, a.LoginPage(r, formModel, loginParams))
 			return
 		}
 
+		// attempts of the ip address are kept, so that a valid login does not unlock others
+		captchaGuard.Reset(captchaKeys[0])
+		authService.RecordLogin(r.Context(), principal, r.UserAgent(), shared.RemoteIP(r))
+
 		cookie := authService.CreateCookie(tokenAuth, expiryDuration, principal)
 		http.SetCookie(w, &cookie)
 
@@ -117,17 +150,24 @@ func (a *AuthWebHandlers) HandleLoginForm() http.HandlerFunc {
 }
 
 func (a *AuthWebHandlers) HandleLoginPage() http.HandlerFunc {
+	captchaGuard := a.captchaGuard
 	return func(w http.ResponseWriter, r *http.Request) {
 		loginParams := loginParamsFromQueryParams(r.URL.Query())
+		loginParams.captcha = captchaGuard.Widget(shared.RemoteIP(r))
 
 		formModel := loginFormModel{
 			Redirect: loginParams.redirect,
 		}
 		formModel.CSRFToken = csrf.Token(r)
-		shared.RenderHTML(w, a.LoginPage(r.URL.Path, formModel, loginParams))
+		shared.RenderHTML(w, a.LoginPage(r, formModel, loginParams))
 	}
 }
 
+// loginCaptchaKeys are the keys to count failed logins for, the username and the ip address
+func loginCaptchaKeys(r *http.Request, username string) []string {
+	return []string{strings.ToLower(username), shared.RemoteIP(r)}
+}
+
 func loginParamsFromQueryParams(params url.Values) *loginParams {
 	loginParams := &loginParams{}
 	if len(params["info"]) == 1 && params["info"][0] == "confirm_successfull" {
@@ -209,6 +249,7 @@ func (a *AuthWebHandlers) IssueCookieForGithub() http.Handler {
 				return
 			}
 		}
+		authService.RecordLogin(ctx, principal, r.UserAgent(), shared.RemoteIP(r))
 
 		cookie := authService.CreateCookie(tokenAuth, expiryDuration, principal)
 		http.SetCookie(w, &cookie)
@@ -231,7 +272,14 @@ func (a *AuthWebHandlers) IssueCookieForGoogle() http.Handler {
 			return
 		}
 
-		principal, err := authService.AuthenticateTrusted(ctx, fmt.Sprintf("%v", googleUser.Id))
+		// users of an email domain with a single sign-on policy log in to their existing user
+		var principal *shared.Principal
+		if googleUser.VerifiedEmail != nil && *googleUser.VerifiedEmail {
+			principal, err = authService.AuthenticateSSO(ctx, SSOProviderGoogle, googleUser.Email)
+		}
+		if principal == nil {
+			principal, err = authService.AuthenticateTrusted(ctx, fmt.Sprintf("%v", googleUser.Id))
+		}
 		if errors.Is(err, user.ErrUserNotFound) {
 			user := &user.User{
 				Username: fmt.Sprintf("%v", googleUser.Id),
@@ -251,6 +299,7 @@ func (a *AuthWebHandlers) IssueCookieForGoogle() http.Handler {
 				return
 			}
 		}
+		authService.RecordLogin(ctx, principal, r.UserAgent(), shared.RemoteIP(r))
 
 		cookie := authService.CreateCookie(tokenAuth, expiryDuration, principal)
 		http.SetCookie(w, &cookie)
@@ -260,10 +309,18 @@ func (a *AuthWebHandlers) IssueCookieForGoogle() http.Handler {
 	return http.HandlerFunc(fn)
 }
 
-func (a *AuthWebHandlers) LoginPage(currentPath string, formModel loginFormModel, loginParams *loginParams) g.Node {
+// LoginPage renders the login with the branding of the custom domain the request was sent to
+func (a *AuthWebHandlers) LoginPage(r *http.Request, formModel loginFormModel, loginParams *loginParams) g.Node {
+	appearance := shared.AppearanceOf(r.Context())
+	logoURL := "/assets/baralga_192.png"
+	if appearance.LogoURL != "" {
+		logoURL = appearance.LogoURL
+	}
+
 	return shared.Page(
 		"Sign In",
-		currentPath,
+		r.URL.Path,
+		appearance,
 		[]g.Node{
 			Section(
 				Class("full-center"),
@@ -274,7 +331,8 @@ func (a *AuthWebHandlers) LoginPage(currentPath string, formModel loginFormModel
 						Img(
 							Alt("Baralga"),
 							Class("img-responsive"),
-							Src("/assets/baralga_192.png"),
+							StyleAttr("max-height: 192px"),
+							Src(logoURL),
 						),
 						Div(
 							Class("ms-4"),
@@ -379,6 +437,7 @@ func LoginForm(formModel loginFormModel, loginParams *loginParams) g.Node {
 				g.Text("Password"),
 			),
 		),
+		loginParams.captcha,
 		Div(
 			Class("container-fluid text-center"),
 			Button(
@@ -1,11 +1,13 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/baralga/shared"
 	"github.com/baralga/user"
@@ -18,7 +20,8 @@ func TestHandleLoginPage(t *testing.T) {
 	httpRec := httptest.NewRecorder()
 
 	a := &AuthWebHandlers{
-		config: &shared.Config{},
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
 	}
 
 	r, _ := http.NewRequest("GET", "/login", nil)
@@ -39,11 +42,13 @@ func TestHandleLoginFormWithSuccessfullLogin(t *testing.T) {
 
 	userRepository := user.NewInMemUserRepository()
 	a := &AuthWebHandlers{
-		config:    config,
-		tokenAuth: tokenAuth,
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       config,
+		tokenAuth:    tokenAuth,
 		authService: &AuthService{
 			config:         config,
 			userRepository: userRepository,
+			passwordHasher: shared.NewBcryptPasswordHasher(10),
 		},
 	}
 
@@ -67,11 +72,13 @@ func TestHandleLoginFormWithSuccessfullLoginAndRedirect(t *testing.T) {
 	config := &shared.Config{}
 
 	a := &AuthWebHandlers{
-		config:    config,
-		tokenAuth: tokenAuth,
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       config,
+		tokenAuth:    tokenAuth,
 		authService: &AuthService{
 			config:         config,
 			userRepository: user.NewInMemUserRepository(),
+			passwordHasher: shared.NewBcryptPasswordHasher(10),
 		},
 	}
 
@@ -97,11 +104,13 @@ func TestHandleLoginFormWithInvalidLogin(t *testing.T) {
 
 	userRepository := user.NewInMemUserRepository()
 	a := &AuthWebHandlers{
-		config:    config,
-		tokenAuth: tokenAuth,
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       config,
+		tokenAuth:    tokenAuth,
 		authService: &AuthService{
 			config:         config,
 			userRepository: userRepository,
+			passwordHasher: shared.NewBcryptPasswordHasher(10),
 		},
 	}
 
@@ -119,15 +128,61 @@ func TestHandleLoginFormWithInvalidLogin(t *testing.T) {
 	is.True(strings.Contains(htmlBody, "Sign In # Baralga"))
 }
 
+func TestHandleLoginFormWithCaptchaAfterFailedLogins(t *testing.T) {
+	is := is.New(t)
+
+	tokenAuth := jwtauth.New("HS256", []byte("secret"), nil)
+	config := &shared.Config{CaptchaProvider: shared.CaptchaProviderHCaptcha, CaptchaSiteKey: "site-key"}
+
+	captchaVerifier, err := shared.NewCaptchaVerifier(config)
+	is.NoErr(err)
+
+	a := &AuthWebHandlers{
+		config:    config,
+		tokenAuth: tokenAuth,
+		authService: &AuthService{
+			config:         config,
+			userRepository: user.NewInMemUserRepository(),
+			passwordHasher: shared.NewBcryptPasswordHasher(10),
+		},
+		captchaGuard: shared.NewCaptchaGuard(captchaVerifier, 2, time.Minute),
+	}
+
+	login := func(password string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+
+		data := url.Values{}
+		data["EMail"] = []string{"admin@baralga.com"}
+		data["Password"] = []string{password}
+
+		r, _ := http.NewRequest("POST", "/login", strings.NewReader(data.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		a.HandleLoginForm()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := login("-just-wrong-")
+	is.True(!strings.Contains(httpRec.Body.String(), "h-captcha"))
+
+	httpRec = login("-just-wrong-")
+	is.True(strings.Contains(httpRec.Body.String(), "h-captcha"))
+
+	httpRec = login("adm1n")
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.True(strings.Contains(httpRec.Body.String(), "not a robot"))
+}
+
 func TestHandleLoginFormWithInvalidFormData(t *testing.T) {
 	is := is.New(t)
 	httpRec := httptest.NewRecorder()
 
 	tokenAuth := jwtauth.New("HS256", []byte("secret"), nil)
 	a := &AuthWebHandlers{
-		config:      &shared.Config{},
-		tokenAuth:   tokenAuth,
-		userService: user.NewInMemUserService(),
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
+		tokenAuth:    tokenAuth,
+		userService:  user.NewInMemUserService(),
 	}
 
 	data := url.Values{}
@@ -149,9 +204,10 @@ func TestHandleLoginFormWithInvalidBodyData(t *testing.T) {
 
 	tokenAuth := jwtauth.New("HS256", []byte("secret"), nil)
 	a := &AuthWebHandlers{
-		config:      &shared.Config{},
-		tokenAuth:   tokenAuth,
-		userService: user.NewInMemUserService(),
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
+		tokenAuth:    tokenAuth,
+		userService:  user.NewInMemUserService(),
 	}
 
 	data := url.Values{}
@@ -221,3 +277,42 @@ func TestLoginParamsFromQueryParams(t *testing.T) {
 		is.Equal(filter.redirect, "/reports")
 	})
 }
+
+func TestHandleLoginFormWithSSORequired(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	config := &shared.Config{GoogleClientId: "google-client-id"}
+	ssoPolicyRepository := NewInMemSSOPolicyRepository()
+	_ = ssoPolicyRepository.UpsertSSOPolicy(context.Background(), &SSOPolicy{
+		OrganizationID: shared.OrganizationIDSample,
+		EMailDomain:    "baralga.com",
+		Provider:       SSOProviderGoogle,
+	})
+
+	a := &AuthWebHandlers{
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       config,
+		tokenAuth:    jwtauth.New("HS256", []byte("secret"), nil),
+		authService: &AuthService{
+			config:              config,
+			userRepository:      user.NewInMemUserRepository(),
+			passwordHasher:      shared.NewBcryptPasswordHasher(10),
+			ssoPolicyRepository: ssoPolicyRepository,
+		},
+	}
+
+	data := url.Values{}
+	data["EMail"] = []string{"admin@baralga.com"}
+	data["Password"] = []string{"adm1n"}
+
+	r, _ := http.NewRequest("POST", "/login", strings.NewReader(data.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	a.HandleLoginForm()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	htmlBody := httpRec.Body.String()
+	is.True(strings.Contains(htmlBody, "requires you to sign in with Google"))
+	is.True(strings.Contains(htmlBody, "/google/login"))
+}
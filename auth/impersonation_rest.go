@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+)
+
+type impersonationModel struct {
+	Username string `json:"username"`
+}
+
+type ImpersonationRestHandlers struct {
+	config               *shared.Config
+	authService          *AuthService
+	impersonationService *ImpersonationService
+	tokenAuth            *jwtauth.JWTAuth
+}
+
+func NewImpersonationRestHandlers(config *shared.Config, authService *AuthService, impersonationService *ImpersonationService, tokenAuth *jwtauth.JWTAuth) *ImpersonationRestHandlers {
+	return &ImpersonationRestHandlers{
+		config:               config,
+		authService:          authService,
+		impersonationService: impersonationService,
+		tokenAuth:            tokenAuth,
+	}
+}
+
+func (a *ImpersonationRestHandlers) RegisterProtected(r chi.Router) {
+	r.Post("/admin/impersonation", a.HandleStartImpersonation())
+	r.Delete("/admin/impersonation", a.HandleStopImpersonation())
+}
+
+func (a *ImpersonationRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleStartImpersonation starts a support session of an instance admin acting as the user,
+// the session expires after the impersonation expiry
+func (a *ImpersonationRestHandlers) HandleStartImpersonation() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	tokenAuth := a.tokenAuth
+	expiryDuration := a.config.ImpersonationExpiryDuration()
+	authService := a.authService
+	impersonationService := a.impersonationService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var impersonationModel impersonationModel
+		err := json.NewDecoder(r.Body).Decode(&impersonationModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "impersonation not valid", err)
+			return
+		}
+		if impersonationModel.Username == "" {
+			shared.RenderValidationProblemJSON(w, "impersonation not valid", shared.NewInvalidParam("username", "required", "is required"))
+			return
+		}
+
+		impersonated, err := impersonationService.StartImpersonation(r.Context(), principal, impersonationModel.Username)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		cookie := authService.CreateCookie(tokenAuth, expiryDuration, impersonated)
+		http.SetCookie(w, &cookie)
+
+		shared.RenderJSON(w, &loginResponseModel{AccessToken: cookie.Value})
+	}
+}
+
+// HandleStopImpersonation ends the support session and returns to the session of the instance admin
+func (a *ImpersonationRestHandlers) HandleStopImpersonation() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	tokenAuth := a.tokenAuth
+	expiryDuration := a.config.ExpiryDuration()
+	authService := a.authService
+	impersonationService := a.impersonationService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		instanceAdmin, err := impersonationService.StopImpersonation(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		cookie := authService.CreateCookie(tokenAuth, expiryDuration, instanceAdmin)
+		http.SetCookie(w, &cookie)
+
+		shared.RenderJSON(w, &loginResponseModel{AccessToken: cookie.Value})
+	}
+}
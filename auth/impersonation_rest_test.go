@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleImpersonation(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{InstanceAdmins: "ops@baralga.com", JWTExpiry: "1h", ImpersonationExpiry: "30m"}
+	impersonationService, featureService, _ := newImpersonationServiceForTest(config)
+	_, err := featureService.UpdateFeatureFlag(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample}, shared.FeatureSupportAccess, true)
+	is.NoErr(err)
+
+	tokenAuth := jwtauth.New("HS256", []byte("secret"), nil)
+	a := NewImpersonationRestHandlers(config, impersonationService.authService, impersonationService, tokenAuth)
+	authRestHandlers := NewAuthRestHandlers(config, impersonationService.authService, tokenAuth, shared.NewCaptchaGuard(nil, 0, 0))
+
+	// start impersonation
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("POST", "/api/admin/impersonation", strings.NewReader(`{"username": "admin@baralga.com"}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{Username: "ops@baralga.com", Roles: []string{"ROLE_ADMIN"}}))
+
+	a.HandleStartImpersonation()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	loginResponse := make(map[string]string)
+	err = json.NewDecoder(httpRec.Body).Decode(&loginResponse)
+	is.NoErr(err)
+
+	// read session with the token of the impersonation
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("GET", "/api/auth/session", nil)
+	r.Header.Set("Authorization", "Bearer "+loginResponse["access_token"])
+
+	handler := authRestHandlers.JWTVerifier()(authRestHandlers.JWTPrincipalMiddleware()(authRestHandlers.HandleGetSession()))
+	handler.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	sessionModel := &sessionModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(sessionModel)
+	is.NoErr(err)
+	is.Equal(sessionModel.Username, "admin@baralga.com")
+	is.True(sessionModel.Impersonated)
+	is.Equal(sessionModel.ImpersonatedBy, "ops@baralga.com")
+	is.True(sessionModel.ExpiresAt != "")
+
+	// start impersonation without username
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("POST", "/api/admin/impersonation", strings.NewReader(`{}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{Username: "ops@baralga.com", Roles: []string{"ROLE_ADMIN"}}))
+
+	a.HandleStartImpersonation()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/baralga/shared"
+)
+
+var (
+	ErrSupportAccessNotAllowed = shared.NewDomainError("impersonation:support-access-not-allowed", http.StatusForbidden, "organization has not allowed support access")
+	ErrImpersonationActive     = shared.NewDomainError("impersonation:active", http.StatusConflict, "impersonation already active")
+	ErrImpersonationNotActive  = shared.NewDomainError("impersonation:not-active", http.StatusConflict, "no impersonation active")
+)
+
+// ImpersonationService lets instance admins act as a user for support, if the organization of the user consents
+type ImpersonationService struct {
+	config         *shared.Config
+	authService    *AuthService
+	featureService *shared.FeatureService
+	auditService   *shared.AuditService
+}
+
+// NewImpersonationService creates a new service for support sessions of instance admins
+func NewImpersonationService(config *shared.Config, authService *AuthService, featureService *shared.FeatureService, auditService *shared.AuditService) *ImpersonationService {
+	return &ImpersonationService{
+		config:         config,
+		authService:    authService,
+		featureService: featureService,
+		auditService:   auditService,
+	}
+}
+
+// StartImpersonation starts a support session of the instance admin acting as the user,
+// the organization of the user has to allow support access
+func (s *ImpersonationService) StartImpersonation(ctx context.Context, principal *shared.Principal, username string) (*shared.Principal, error) {
+	if principal.IsImpersonated() {
+		return nil, ErrImpersonationActive
+	}
+	if !s.config.IsInstanceAdmin(principal.Username) {
+		return nil, shared.ErrForbidden
+	}
+
+	impersonated, err := s.authService.AuthenticateTrusted(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	supportAccess, err := s.featureService.IsEnabled(ctx, impersonated.OrganizationID, shared.FeatureSupportAccess)
+	if err != nil {
+		return nil, err
+	}
+	if !supportAccess {
+		return nil, ErrSupportAccessNotAllowed
+	}
+
+	impersonated.ImpersonatedBy = principal.Username
+	err = s.auditService.RecordAuditEntry(ctx, &shared.AuditEntry{
+		OrganizationID: impersonated.OrganizationID,
+		Username:       impersonated.Username,
+		ImpersonatedBy: impersonated.ImpersonatedBy,
+		Action:         shared.AuditActionImpersonationStarted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return impersonated, nil
+}
+
+// StopImpersonation ends the support session and returns to the instance admin
+func (s *ImpersonationService) StopImpersonation(ctx context.Context, principal *shared.Principal) (*shared.Principal, error) {
+	if !principal.IsImpersonated() {
+		return nil, ErrImpersonationNotActive
+	}
+
+	instanceAdmin, err := s.authService.AuthenticateTrusted(ctx, principal.ImpersonatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.RecordAuditEntry(ctx, &shared.AuditEntry{
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		ImpersonatedBy: principal.ImpersonatedBy,
+		Action:         shared.AuditActionImpersonationStopped,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return instanceAdmin, nil
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/paged"
+	"github.com/baralga/user"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+func newImpersonationServiceForTest(config *shared.Config) (*ImpersonationService, *shared.FeatureService, *shared.AuditService) {
+	userRepository := user.NewInMemUserRepository()
+	_, _ = userRepository.InsertUserWithConfirmationID(context.Background(), &user.User{
+		ID:             uuid.New(),
+		Username:       "ops@baralga.com",
+		OrganizationID: uuid.New(),
+	}, uuid.New())
+
+	authService := &AuthService{
+		config:         config,
+		userRepository: userRepository,
+		passwordHasher: shared.NewBcryptPasswordHasher(10),
+	}
+	featureService := shared.NewFeatureService(config, shared.NewInMemRepositoryTxer(), shared.NewInMemFeatureFlagRepository())
+	auditService := shared.NewAuditService(shared.NewInMemRepositoryTxer(), shared.NewInMemAuditRepository())
+	return NewImpersonationService(config, authService, featureService, auditService), featureService, auditService
+}
+
+func TestImpersonation(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{InstanceAdmins: "ops@baralga.com"}
+	impersonationService, featureService, auditService := newImpersonationServiceForTest(config)
+	ops := &shared.Principal{Username: "ops@baralga.com", Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := impersonationService.StartImpersonation(context.Background(), &shared.Principal{Username: "admin@baralga.com"}, "client@acme.com")
+	is.True(errors.Is(err, shared.ErrForbidden))
+
+	_, err = impersonationService.StartImpersonation(context.Background(), ops, "admin@baralga.com")
+	is.True(errors.Is(err, ErrSupportAccessNotAllowed))
+
+	_, err = featureService.UpdateFeatureFlag(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample}, shared.FeatureSupportAccess, true)
+	is.NoErr(err)
+
+	impersonated, err := impersonationService.StartImpersonation(context.Background(), ops, "admin@baralga.com")
+	is.NoErr(err)
+	is.Equal(impersonated.Username, "admin@baralga.com")
+	is.Equal(impersonated.OrganizationID, shared.OrganizationIDSample)
+	is.Equal(impersonated.ImpersonatedBy, "ops@baralga.com")
+	is.True(impersonated.IsImpersonated())
+
+	_, err = impersonationService.StartImpersonation(context.Background(), impersonated, "admin@baralga.com")
+	is.True(errors.Is(err, ErrImpersonationActive))
+
+	instanceAdmin, err := impersonationService.StopImpersonation(context.Background(), impersonated)
+	is.NoErr(err)
+	is.Equal(instanceAdmin.Username, "ops@baralga.com")
+	is.True(!instanceAdmin.IsImpersonated())
+
+	_, err = impersonationService.StopImpersonation(context.Background(), instanceAdmin)
+	is.True(errors.Is(err, ErrImpersonationNotActive))
+
+	auditEntriesPaged, err := auditService.ReadAuditEntries(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}}, &paged.PageParams{Size: 50})
+	is.NoErr(err)
+	is.Equal(len(auditEntriesPaged.AuditEntries), 2)
+}
+
+func TestImpersonationClaims(t *testing.T) {
+	is := is.New(t)
+
+	principal := &shared.Principal{
+		Name:           "Admin",
+		Username:       "admin@baralga.com",
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+		ImpersonatedBy: "ops@baralga.com",
+	}
+	is.Equal(mapPrincipalFromClaims(mapPrincipalToClaims(principal)), principal)
+
+	principal.ImpersonatedBy = ""
+	claims := mapPrincipalToClaims(principal)
+	_, ok := claims["impersonatedBy"]
+	is.True(!ok)
+	is.Equal(mapPrincipalFromClaims(claims), principal)
+}
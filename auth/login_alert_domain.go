@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginDevice is a browser on a network a user logged in from, a login from an unknown device is reported to the user
+type LoginDevice struct {
+	OrganizationID uuid.UUID
+	Username       string
+	Fingerprint    string
+	Description    string
+	Network        string
+	FirstSeenAt    time.Time
+	LastSeenAt     time.Time
+}
+
+// LoginAlertRecipient is a user receiving login alerts
+type LoginAlertRecipient struct {
+	OrganizationID uuid.UUID
+	Username       string
+	Name           string
+	EMail          string
+	OptOut         bool
+}
+
+type LoginAlertRepository interface {
+	HasLoginDevice(ctx context.Context, organizationID uuid.UUID, username, fingerprint string) (bool, error)
+	HasLoginDevices(ctx context.Context, organizationID uuid.UUID, username string) (bool, error)
+	InsertLoginDevice(ctx context.Context, device *LoginDevice) error
+	UpdateLoginDeviceLastSeen(ctx context.Context, device *LoginDevice) error
+	FindLoginAlertRecipient(ctx context.Context, organizationID uuid.UUID, username string) (*LoginAlertRecipient, error)
+	FindLoginAlertAdmins(ctx context.Context, organizationID uuid.UUID) ([]*LoginAlertRecipient, error)
+	UpdateLoginAlertOptOut(ctx context.Context, organizationID uuid.UUID, username string, optOut bool) error
+	InsertFailedLogin(ctx context.Context, organizationID uuid.UUID, username string, failedAt time.Time) error
+	CountFailedLogins(ctx context.Context, organizationID uuid.UUID, since time.Time) (int, error)
+	DeleteFailedLoginsBefore(ctx context.Context, before time.Time) error
+	FindLastFailedLoginAlert(ctx context.Context, organizationID uuid.UUID) (*time.Time, error)
+	InsertFailedLoginAlert(ctx context.Context, organizationID uuid.UUID, alertedAt time.Time) error
+}
+
+// NewLoginDevice creates the device of a login by the user agent and the remote ip address. The device is
+// identified by the browser, the operating system and the network of the ip address, so that browser updates
+// or a new address of the same provider are no new device.
+func NewLoginDevice(organizationID uuid.UUID, username, userAgent, remoteIP string, now time.Time) *LoginDevice {
+	description := describeUserAgent(userAgent)
+	network := networkOf(remoteIP)
+	fingerprint := sha256.Sum256([]byte(description + "|" + network))
+
+	return &LoginDevice{
+		OrganizationID: organizationID,
+		Username:       username,
+		Fingerprint:    hex.EncodeToString(fingerprint[:]),
+		Description:    description,
+		Network:        network,
+		FirstSeenAt:    now,
+		LastSeenAt:     now,
+	}
+}
+
+// describeUserAgent describes the browser and operating system of the user agent like Firefox on Windows
+func describeUserAgent(userAgent string) string {
+	browser := "Unknown browser"
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	case userAgent != "":
+		browser, _, _ = strings.Cut(userAgent, "/")
+	}
+
+	system := ""
+	switch {
+	case strings.Contains(userAgent, "Windows"):
+		system = "Windows"
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"):
+		system = "iOS"
+	case strings.Contains(userAgent, "Mac OS X"):
+		system = "macOS"
+	case strings.Contains(userAgent, "Android"):
+		system = "Android"
+	case strings.Contains(userAgent, "Linux"):
+		system = "Linux"
+	}
+
+	if system == "" {
+		return browser
+	}
+	return browser + " on " + system
+}
+
+// networkOf is the network of the ip address, the /24 network of IPv4 and the /48 network of IPv6 addresses
+func networkOf(remoteIP string) string {
+	ip := net.ParseIP(remoteIP)
+	if ip == nil {
+		return remoteIP
+	}
+
+	if ipv4 := ip.To4(); ipv4 != nil {
+		network := &net.IPNet{IP: ipv4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}
+		return network.String()
+	}
+	network := &net.IPNet{IP: ip.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}
+	return network.String()
+}
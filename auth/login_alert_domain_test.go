@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+const (
+	userAgentFirefoxWindows = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0"
+	userAgentChromeMac      = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+func TestNewLoginDevice(t *testing.T) {
+	is := is.New(t)
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	device := NewLoginDevice(shared.OrganizationIDSample, "user1", userAgentFirefoxWindows, "192.168.1.17", now)
+	is.Equal(device.Description, "Firefox on Windows")
+	is.Equal(device.Network, "192.168.1.0/24")
+	is.Equal(len(device.Fingerprint), 64)
+	is.Equal(device.FirstSeenAt, now)
+
+	sameNetwork := NewLoginDevice(shared.OrganizationIDSample, "user1", userAgentFirefoxWindows, "192.168.1.42", now)
+	is.Equal(sameNetwork.Fingerprint, device.Fingerprint)
+
+	otherNetwork := NewLoginDevice(shared.OrganizationIDSample, "user1", userAgentFirefoxWindows, "10.0.0.1", now)
+	is.True(otherNetwork.Fingerprint != device.Fingerprint)
+
+	otherBrowser := NewLoginDevice(shared.OrganizationIDSample, "user1", userAgentChromeMac, "192.168.1.17", now)
+	is.Equal(otherBrowser.Description, "Chrome on macOS")
+	is.True(otherBrowser.Fingerprint != device.Fingerprint)
+}
+
+func TestDescribeUserAgent(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(describeUserAgent(userAgentFirefoxWindows), "Firefox on Windows")
+	is.Equal(describeUserAgent(userAgentChromeMac), "Chrome on macOS")
+	is.Equal(describeUserAgent("Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1"), "Safari on iOS")
+	is.Equal(describeUserAgent("curl/8.4.0"), "curl")
+	is.Equal(describeUserAgent(""), "Unknown browser")
+}
+
+func TestNetworkOf(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(networkOf("203.0.113.77"), "203.0.113.0/24")
+	is.Equal(networkOf("2001:db8:abcd:12::1"), "2001:db8:abcd::/48")
+	is.Equal(networkOf("not an ip"), "not an ip")
+}
@@ -0,0 +1,247 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/user"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbLoginAlertRepository is a SQL database repository for login devices and failed logins
+type DbLoginAlertRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ LoginAlertRepository = (*DbLoginAlertRepository)(nil)
+
+// NewDbLoginAlertRepository creates a new SQL database repository for login devices and failed logins
+func NewDbLoginAlertRepository(connPool *pgxpool.Pool) *DbLoginAlertRepository {
+	return &DbLoginAlertRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbLoginAlertRepository) HasLoginDevice(ctx context.Context, organizationID uuid.UUID, username, fingerprint string) (bool, error) {
+	var exists bool
+	err := r.connPool.QueryRow(
+		ctx,
+		`SELECT EXISTS (
+		   SELECT 1 FROM login_devices
+		   WHERE org_id = $1 AND username = $2 AND fingerprint = $3
+		 )`,
+		organizationID, username, fingerprint,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *DbLoginAlertRepository) HasLoginDevices(ctx context.Context, organizationID uuid.UUID, username string) (bool, error) {
+	var exists bool
+	err := r.connPool.QueryRow(
+		ctx,
+		`SELECT EXISTS (
+		   SELECT 1 FROM login_devices
+		   WHERE org_id = $1 AND username = $2
+		 )`,
+		organizationID, username,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *DbLoginAlertRepository) InsertLoginDevice(ctx context.Context, device *LoginDevice) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO login_devices
+		   (org_id, username, fingerprint, description, network, first_seen_at, last_seen_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (org_id, username, fingerprint) DO UPDATE
+		 SET last_seen_at = EXCLUDED.last_seen_at`,
+		device.OrganizationID,
+		device.Username,
+		device.Fingerprint,
+		device.Description,
+		device.Network,
+		device.FirstSeenAt,
+		device.LastSeenAt,
+	)
+	return err
+}
+
+func (r *DbLoginAlertRepository) UpdateLoginDeviceLastSeen(ctx context.Context, device *LoginDevice) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`UPDATE login_devices
+		 SET last_seen_at = $4
+		 WHERE org_id = $1 AND username = $2 AND fingerprint = $3`,
+		device.OrganizationID,
+		device.Username,
+		device.Fingerprint,
+		device.LastSeenAt,
+	)
+	return err
+}
+
+// FindLoginAlertRecipient reads the enabled user with the opt out of the login alerts
+func (r *DbLoginAlertRepository) FindLoginAlertRecipient(ctx context.Context, organizationID uuid.UUID, username string) (*LoginAlertRecipient, error) {
+	row, err := shared.SelectOne[loginAlertRecipientRow](
+		ctx,
+		r.connPool,
+		`SELECT u.org_id, u.username, u.name, u.email, COALESCE(s.opted_out, false) as opted_out
+		 FROM users u
+		 LEFT JOIN login_alert_settings s ON s.org_id = u.org_id AND s.username = u.username
+		 WHERE u.org_id = $1 AND u.username = $2 AND u.enabled = 1`,
+		organizationID, username,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toLoginAlertRecipient(), nil
+}
+
+// FindLoginAlertAdmins reads the enabled admins of the organization
+func (r *DbLoginAlertRepository) FindLoginAlertAdmins(ctx context.Context, organizationID uuid.UUID) ([]*LoginAlertRecipient, error) {
+	rows, err := shared.SelectAll[loginAlertRecipientRow](
+		ctx,
+		r.connPool,
+		`SELECT u.org_id, u.username, u.name, u.email, false as opted_out
+		 FROM users u
+		 WHERE u.org_id = $1 AND u.enabled = 1
+		   AND EXISTS (SELECT 1 FROM roles r WHERE r.user_id = u.user_id AND r.role = 'ROLE_ADMIN')
+		 ORDER BY u.name, u.username`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]*LoginAlertRecipient, len(rows))
+	for i, row := range rows {
+		recipients[i] = row.toLoginAlertRecipient()
+	}
+	return recipients, nil
+}
+
+// UpdateLoginAlertOptOut sets whether the user opted out of the notifications about logins from new devices
+func (r *DbLoginAlertRepository) UpdateLoginAlertOptOut(ctx context.Context, organizationID uuid.UUID, username string, optOut bool) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO login_alert_settings
+		   (org_id, username, opted_out)
+		 VALUES
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id, username) DO UPDATE
+		 SET opted_out = EXCLUDED.opted_out`,
+		organizationID,
+		username,
+		optOut,
+	)
+	return err
+}
+
+func (r *DbLoginAlertRepository) InsertFailedLogin(ctx context.Context, organizationID uuid.UUID, username string, failedAt time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO failed_logins
+		   (org_id, username, failed_at)
+		 VALUES
+		   ($1, $2, $3)`,
+		organizationID,
+		username,
+		failedAt,
+	)
+	return err
+}
+
+func (r *DbLoginAlertRepository) CountFailedLogins(ctx context.Context, organizationID uuid.UUID, since time.Time) (int, error) {
+	var count int
+	err := r.connPool.QueryRow(
+		ctx,
+		`SELECT count(*)
+		 FROM failed_logins
+		 WHERE org_id = $1 AND failed_at > $2`,
+		organizationID, since,
+	).Scan(&count)
+	return count, err
+}
+
+// DeleteFailedLoginsBefore deletes the failed logins of all organizations which are no longer counted
+func (r *DbLoginAlertRepository) DeleteFailedLoginsBefore(ctx context.Context, before time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`DELETE FROM failed_logins
+		 WHERE failed_at <= $1`,
+		before,
+	)
+	return err
+}
+
+func (r *DbLoginAlertRepository) FindLastFailedLoginAlert(ctx context.Context, organizationID uuid.UUID) (*time.Time, error) {
+	var alertedAt *time.Time
+	err := r.connPool.QueryRow(
+		ctx,
+		`SELECT max(alerted_at)
+		 FROM failed_login_alerts
+		 WHERE org_id = $1`,
+		organizationID,
+	).Scan(&alertedAt)
+	return alertedAt, err
+}
+
+func (r *DbLoginAlertRepository) InsertFailedLoginAlert(ctx context.Context, organizationID uuid.UUID, alertedAt time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO failed_login_alerts
+		   (org_id, alerted_at)
+		 VALUES
+		   ($1, $2)`,
+		organizationID,
+		alertedAt,
+	)
+	return err
+}
+
+type loginAlertRecipientRow struct {
+	OrganizationID uuid.UUID `db:"org_id"`
+	Username       string    `db:"username"`
+	Name           *string   `db:"name"`
+	EMail          *string   `db:"email"`
+	OptOut         bool      `db:"opted_out"`
+}
+
+func (r *loginAlertRecipientRow) toLoginAlertRecipient() *LoginAlertRecipient {
+	recipient := &LoginAlertRecipient{
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		Name:           r.Username,
+		OptOut:         r.OptOut,
+	}
+	if r.Name != nil && *r.Name != "" {
+		recipient.Name = *r.Name
+	}
+	if r.EMail != nil {
+		recipient.EMail = *r.EMail
+	}
+	return recipient
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestLoginAlertRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	loginAlertRepository := NewDbLoginAlertRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	username := "admin@baralga.com"
+
+	t.Run("LoginDevices", func(t *testing.T) {
+		device := NewLoginDevice(shared.OrganizationIDSample, username, userAgentFirefoxWindows, "192.168.1.17", now)
+
+		hasDevices, err := loginAlertRepository.HasLoginDevices(ctx, shared.OrganizationIDSample, username)
+		is.NoErr(err)
+		is.True(!hasDevices)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return loginAlertRepository.InsertLoginDevice(ctx, device)
+			},
+		)
+		is.NoErr(err)
+
+		hasDevice, err := loginAlertRepository.HasLoginDevice(ctx, shared.OrganizationIDSample, username, device.Fingerprint)
+		is.NoErr(err)
+		is.True(hasDevice)
+
+		hasDevice, err = loginAlertRepository.HasLoginDevice(ctx, shared.OrganizationIDSample, "user1", device.Fingerprint)
+		is.NoErr(err)
+		is.True(!hasDevice)
+
+		device.LastSeenAt = now.Add(time.Hour)
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return loginAlertRepository.UpdateLoginDeviceLastSeen(ctx, device)
+			},
+		)
+		is.NoErr(err)
+	})
+
+	t.Run("LoginAlertOptOut", func(t *testing.T) {
+		recipient, err := loginAlertRepository.FindLoginAlertRecipient(ctx, shared.OrganizationIDSample, username)
+		is.NoErr(err)
+		is.Equal(recipient.Username, username)
+		is.True(!recipient.OptOut)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return loginAlertRepository.UpdateLoginAlertOptOut(ctx, shared.OrganizationIDSample, username, true)
+			},
+		)
+		is.NoErr(err)
+
+		recipient, err = loginAlertRepository.FindLoginAlertRecipient(ctx, shared.OrganizationIDSample, username)
+		is.NoErr(err)
+		is.True(recipient.OptOut)
+
+		admins, err := loginAlertRepository.FindLoginAlertAdmins(ctx, shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(len(admins) > 0)
+	})
+
+	t.Run("FailedLogins", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				err := loginAlertRepository.InsertFailedLogin(ctx, shared.OrganizationIDSample, username, now.Add(-time.Hour))
+				if err != nil {
+					return err
+				}
+				return loginAlertRepository.InsertFailedLogin(ctx, shared.OrganizationIDSample, username, now)
+			},
+		)
+		is.NoErr(err)
+
+		count, err := loginAlertRepository.CountFailedLogins(ctx, shared.OrganizationIDSample, now.Add(-time.Minute))
+		is.NoErr(err)
+		is.Equal(count, 1)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return loginAlertRepository.DeleteFailedLoginsBefore(ctx, now)
+			},
+		)
+		is.NoErr(err)
+
+		count, err = loginAlertRepository.CountFailedLogins(ctx, shared.OrganizationIDSample, now.Add(-24*time.Hour))
+		is.NoErr(err)
+		is.Equal(count, 0)
+
+		lastAlert, err := loginAlertRepository.FindLastFailedLoginAlert(ctx, shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(lastAlert == nil)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return loginAlertRepository.InsertFailedLoginAlert(ctx, shared.OrganizationIDSample, now)
+			},
+		)
+		is.NoErr(err)
+
+		lastAlert, err = loginAlertRepository.FindLastFailedLoginAlert(ctx, shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(lastAlert.Equal(now))
+	})
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/user"
+	"github.com/google/uuid"
+)
+
+type InMemLoginAlertRepository struct {
+	mu                sync.Mutex
+	devices           []*LoginDevice
+	optOuts           map[string]bool
+	failedLogins      []*failedLogin
+	failedLoginAlerts map[uuid.UUID]time.Time
+	recipients        []*LoginAlertRecipient
+}
+
+// failedLogin is a failed login of a user of the organization
+type failedLogin struct {
+	organizationID uuid.UUID
+	username       string
+	failedAt       time.Time
+}
+
+var _ LoginAlertRepository = (*InMemLoginAlertRepository)(nil)
+
+func NewInMemLoginAlertRepository() *InMemLoginAlertRepository {
+	return &InMemLoginAlertRepository{
+		optOuts:           make(map[string]bool),
+		failedLoginAlerts: make(map[uuid.UUID]time.Time),
+		recipients: []*LoginAlertRecipient{
+			{
+				OrganizationID: shared.OrganizationIDSample,
+				Username:       "admin@baralga.com",
+				Name:           "Admin",
+				EMail:          "admin@baralga.com",
+			},
+		},
+	}
+}
+
+func (r *InMemLoginAlertRepository) HasLoginDevice(ctx context.Context, organizationID uuid.UUID, username, fingerprint string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, device := range r.devices {
+		if device.OrganizationID == organizationID && device.Username == username && device.Fingerprint == fingerprint {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *InMemLoginAlertRepository) HasLoginDevices(ctx context.Context, organizationID uuid.UUID, username string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, device := range r.devices {
+		if device.OrganizationID == organizationID && device.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *InMemLoginAlertRepository) InsertLoginDevice(ctx context.Context, device *LoginDevice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *device
+	r.devices = append(r.devices, &inserted)
+	return nil
+}
+
+func (r *InMemLoginAlertRepository) UpdateLoginDeviceLastSeen(ctx context.Context, device *LoginDevice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.devices {
+		if d.OrganizationID == device.OrganizationID && d.Username == device.Username && d.Fingerprint == device.Fingerprint {
+			d.LastSeenAt = device.LastSeenAt
+		}
+	}
+	return nil
+}
+
+func (r *InMemLoginAlertRepository) FindLoginAlertRecipient(ctx context.Context, organizationID uuid.UUID, username string) (*LoginAlertRecipient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, recipient := range r.recipients {
+		if recipient.OrganizationID == organizationID && recipient.Username == username {
+			found := *recipient
+			found.OptOut = r.optOuts[organizationID.String()+"/"+username]
+			return &found, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (r *InMemLoginAlertRepository) FindLoginAlertAdmins(ctx context.Context, organizationID uuid.UUID) ([]*LoginAlertRecipient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var admins []*LoginAlertRecipient
+	for _, recipient := range r.recipients {
+		if recipient.OrganizationID == organizationID {
+			admins = append(admins, recipient)
+		}
+	}
+	return admins, nil
+}
+
+func (r *InMemLoginAlertRepository) UpdateLoginAlertOptOut(ctx context.Context, organizationID uuid.UUID, username string, optOut bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.optOuts[organizationID.String()+"/"+username] = optOut
+	return nil
+}
+
+func (r *InMemLoginAlertRepository) InsertFailedLogin(ctx context.Context, organizationID uuid.UUID, username string, failedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failedLogins = append(r.failedLogins, &failedLogin{
+		organizationID: organizationID,
+		username:       username,
+		failedAt:       failedAt,
+	})
+	return nil
+}
+
+func (r *InMemLoginAlertRepository) CountFailedLogins(ctx context.Context, organizationID uuid.UUID, since time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, f := range r.failedLogins {
+		if f.organizationID == organizationID && f.failedAt.After(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *InMemLoginAlertRepository) DeleteFailedLoginsBefore(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var failedLogins []*failedLogin
+	for _, f := range r.failedLogins {
+		if f.failedAt.After(before) {
+			failedLogins = append(failedLogins, f)
+		}
+	}
+	r.failedLogins = failedLogins
+	return nil
+}
+
+func (r *InMemLoginAlertRepository) FindLastFailedLoginAlert(ctx context.Context, organizationID uuid.UUID) (*time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alertedAt, ok := r.failedLoginAlerts[organizationID]
+	if !ok {
+		return nil, nil
+	}
+	return &alertedAt, nil
+}
+
+func (r *InMemLoginAlertRepository) InsertFailedLoginAlert(ctx context.Context, organizationID uuid.UUID, alertedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failedLoginAlerts[organizationID] = alertedAt
+	return nil
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type loginAlertSettingsModel struct {
+	OptOut *bool      `json:"optOut"`
+	Links  *hal.Links `json:"_links,omitempty"`
+}
+
+type LoginAlertRestHandlers struct {
+	config            *shared.Config
+	loginAlertService *LoginAlertService
+}
+
+func NewLoginAlertRestHandlers(config *shared.Config, loginAlertService *LoginAlertService) *LoginAlertRestHandlers {
+	return &LoginAlertRestHandlers{
+		config:            config,
+		loginAlertService: loginAlertService,
+	}
+}
+
+func (a *LoginAlertRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/login-alerts", a.HandleGetLoginAlertSettings())
+	r.Put("/login-alerts", a.HandleUpdateLoginAlertSettings())
+}
+
+func (a *LoginAlertRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetLoginAlertSettings reads whether the principal is notified about logins from new devices
+func (a *LoginAlertRestHandlers) HandleGetLoginAlertSettings() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	loginAlertService := a.loginAlertService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		optOut, err := loginAlertService.ReadLoginAlertOptOut(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, &loginAlertSettingsModel{
+			OptOut: &optOut,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleUpdateLoginAlertSettings opts the principal in or out of the notifications about logins from new devices
+func (a *LoginAlertRestHandlers) HandleUpdateLoginAlertSettings() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	loginAlertService := a.loginAlertService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var loginAlertSettingsModel loginAlertSettingsModel
+		err := json.NewDecoder(r.Body).Decode(&loginAlertSettingsModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "login alert settings not valid", err)
+			return
+		}
+
+		if loginAlertSettingsModel.OptOut == nil {
+			shared.RenderValidationProblemJSON(w, "login alert settings not valid", shared.NewInvalidParam("optOut", "required", "optOut is required"))
+			return
+		}
+
+		err = loginAlertService.UpdateLoginAlertOptOut(r.Context(), principal, *loginAlertSettingsModel.OptOut)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		loginAlertSettingsModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+		)
+		shared.RenderJSON(w, loginAlertSettingsModel)
+	}
+}
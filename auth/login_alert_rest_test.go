@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleUpdateLoginAlertSettings(t *testing.T) {
+	is := is.New(t)
+
+	a := NewLoginAlertRestHandlers(&shared.Config{}, newInMemLoginAlertService(&shared.Config{}, shared.NewInMemMailResource(), NewInMemLoginAlertRepository()))
+	principal := &shared.Principal{Username: "admin@baralga.com", OrganizationID: shared.OrganizationIDSample}
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("PUT", "/api/login-alerts", strings.NewReader(`{"optOut": true}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+	a.HandleUpdateLoginAlertSettings()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("GET", "/api/login-alerts", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+	a.HandleGetLoginAlertSettings()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	loginAlertSettingsModel := &loginAlertSettingsModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(loginAlertSettingsModel)
+	is.NoErr(err)
+	is.True(*loginAlertSettingsModel.OptOut)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("PUT", "/api/login-alerts", strings.NewReader(`{}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+	a.HandleUpdateLoginAlertSettings()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"text/template"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+var newLoginTemplate = template.Must(template.New("new-login").Parse(
+	`Hello {{ .Recipient.Name }},
+
+your account {{ .Recipient.Username }} was signed in from a new device:
+
+Device:  {{ .Device.Description }}
+Network: {{ .Device.Network }}
+Time:    {{ .Device.FirstSeenAt.Format "2006-01-02 15:04 MST" }}
+
+If this was you, there is nothing to do. If not, change your password right away and tell your admin.
+
+Turn off these notifications at {{ .SettingsLink }}
+`))
+
+var failedLoginsTemplate = template.Must(template.New("failed-logins").Parse(
+	`Hello {{ .Recipient.Name }},
+
+there were {{ .FailedLogins }} failed logins to users of your organization within the last {{ .Window }}.
+Someone may be guessing the passwords of your users, ask them to use strong passwords.
+`))
+
+// newLoginMail is the mail to a user about a login from a new device
+type newLoginMail struct {
+	Recipient    *LoginAlertRecipient
+	Device       *LoginDevice
+	SettingsLink string
+}
+
+// failedLoginsMail is the mail to an admin about a burst of failed logins in the organization
+type failedLoginsMail struct {
+	Recipient    *LoginAlertRecipient
+	FailedLogins int
+	Window       time.Duration
+}
+
+// LoginAlertService notifies users about logins from new devices and admins about bursts of failed logins
+type LoginAlertService struct {
+	config               *shared.Config
+	repositoryTxer       shared.RepositoryTxer
+	outbox               shared.Outbox
+	loginAlertRepository LoginAlertRepository
+}
+
+// NewLoginAlertService creates a new service for login alerts
+func NewLoginAlertService(config *shared.Config, repositoryTxer shared.RepositoryTxer, outbox shared.Outbox, loginAlertRepository LoginAlertRepository) *LoginAlertService {
+	return &LoginAlertService{
+		config:               config,
+		repositoryTxer:       repositoryTxer,
+		outbox:               outbox,
+		loginAlertRepository: loginAlertRepository,
+	}
+}
+
+// RecordSuccessfulLogin remembers the device of the login and notifies the user about a login from a new device,
+// the first device of a user is not reported
+func (s *LoginAlertService) RecordSuccessfulLogin(ctx context.Context, principal *shared.Principal, userAgent, remoteIP string, now time.Time) error {
+	if !s.config.LoginAlerts {
+		return nil
+	}
+
+	device := NewLoginDevice(principal.OrganizationID, principal.Username, userAgent, remoteIP, now)
+	knownDevice, err := s.loginAlertRepository.HasLoginDevice(ctx, device.OrganizationID, device.Username, device.Fingerprint)
+	if err != nil {
+		return err
+	}
+
+	if knownDevice {
+		return s.repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return s.loginAlertRepository.UpdateLoginDeviceLastSeen(ctx, device)
+			},
+		)
+	}
+
+	hasDevices, err := s.loginAlertRepository.HasLoginDevices(ctx, device.OrganizationID, device.Username)
+	if err != nil {
+		return err
+	}
+
+	recipient, err := s.loginAlertRepository.FindLoginAlertRecipient(ctx, device.OrganizationID, device.Username)
+	if err != nil {
+		return err
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.loginAlertRepository.InsertLoginDevice(ctx, device)
+		},
+		func(ctx context.Context) error {
+			if !hasDevices || recipient.OptOut || recipient.EMail == "" {
+				return nil
+			}
+
+			body := &bytes.Buffer{}
+			err := newLoginTemplate.Execute(body, &newLoginMail{
+				Recipient:    recipient,
+				Device:       device,
+				SettingsLink: fmt.Sprintf("%s/api/login-alerts", s.config.Webroot),
+			})
+			if err != nil {
+				return err
+			}
+
+			return s.outbox.SendMail(ctx, device.OrganizationID, recipient.EMail, "New sign-in to your account", body.String())
+		},
+	)
+}
+
+// RecordFailedLogin counts the failed login of a user of the organization and alerts the admins once per window
+// if the failed logins of the window reach the threshold
+func (s *LoginAlertService) RecordFailedLogin(ctx context.Context, organizationID uuid.UUID, username string, now time.Time) error {
+	if !s.config.LoginAlerts {
+		return nil
+	}
+
+	window := s.config.LoginFailureAlertWindowDuration()
+	since := now.Add(-window)
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.loginAlertRepository.DeleteFailedLoginsBefore(ctx, since)
+		},
+		func(ctx context.Context) error {
+			return s.loginAlertRepository.InsertFailedLogin(ctx, organizationID, username, now)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	failedLogins, err := s.loginAlertRepository.CountFailedLogins(ctx, organizationID, since)
+	if err != nil {
+		return err
+	}
+	if failedLogins < s.config.LoginFailureAlertThreshold {
+		return nil
+	}
+
+	lastAlert, err := s.loginAlertRepository.FindLastFailedLoginAlert(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	if lastAlert != nil && lastAlert.After(since) {
+		return nil
+	}
+
+	admins, err := s.loginAlertRepository.FindLoginAlertAdmins(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.loginAlertRepository.InsertFailedLoginAlert(ctx, organizationID, now)
+		},
+		func(ctx context.Context) error {
+			for _, admin := range admins {
+				if admin.EMail == "" {
+					continue
+				}
+
+				body := &bytes.Buffer{}
+				err := failedLoginsTemplate.Execute(body, &failedLoginsMail{
+					Recipient:    admin,
+					FailedLogins: failedLogins,
+					Window:       window,
+				})
+				if err != nil {
+					return err
+				}
+
+				err = s.outbox.SendMail(ctx, organizationID, admin.EMail, "Many failed logins in your organization", body.String())
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("alerted admins of organization %v about %v failed logins", organizationID, failedLogins)
+	return nil
+}
+
+// ReadLoginAlertOptOut reads whether the principal opted out of the notifications about logins from new devices
+func (s *LoginAlertService) ReadLoginAlertOptOut(ctx context.Context, principal *shared.Principal) (bool, error) {
+	recipient, err := s.loginAlertRepository.FindLoginAlertRecipient(ctx, principal.OrganizationID, principal.Username)
+	if err != nil {
+		return false, err
+	}
+	return recipient.OptOut, nil
+}
+
+// UpdateLoginAlertOptOut opts the principal in or out of the notifications about logins from new devices
+func (s *LoginAlertService) UpdateLoginAlertOptOut(ctx context.Context, principal *shared.Principal, optOut bool) error {
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.loginAlertRepository.UpdateLoginAlertOptOut(ctx, principal.OrganizationID, principal.Username, optOut)
+		},
+	)
+}
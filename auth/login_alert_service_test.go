@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newInMemLoginAlertService(config *shared.Config, mailResource shared.MailResource, loginAlertRepository LoginAlertRepository) *LoginAlertService {
+	return NewLoginAlertService(
+		config,
+		shared.NewInMemRepositoryTxer(),
+		shared.NewInMemOutbox(mailResource),
+		loginAlertRepository,
+	)
+}
+
+func TestRecordSuccessfulLogin(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := shared.NewInMemMailResource()
+	loginAlertRepository := NewInMemLoginAlertRepository()
+	s := newInMemLoginAlertService(&shared.Config{LoginAlerts: true}, mailResource, loginAlertRepository)
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin@baralga.com",
+	}
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	t.Run("first device is not reported", func(t *testing.T) {
+		err := s.RecordSuccessfulLogin(context.Background(), principal, userAgentFirefoxWindows, "192.168.1.17", now)
+		is.NoErr(err)
+		is.Equal(len(mailResource.Mails), 0)
+	})
+
+	t.Run("known device is not reported", func(t *testing.T) {
+		err := s.RecordSuccessfulLogin(context.Background(), principal, userAgentFirefoxWindows, "192.168.1.42", now.Add(time.Hour))
+		is.NoErr(err)
+		is.Equal(len(mailResource.Mails), 0)
+	})
+
+	t.Run("new device is reported", func(t *testing.T) {
+		err := s.RecordSuccessfulLogin(context.Background(), principal, userAgentChromeMac, "203.0.113.77", now.Add(2*time.Hour))
+		is.NoErr(err)
+		is.Equal(len(mailResource.Mails), 1)
+		is.True(strings.HasPrefix(mailResource.Mails[0], "admin@baralga.com"))
+		is.True(strings.Contains(mailResource.Mails[0], "Chrome on macOS"))
+		is.True(strings.Contains(mailResource.Mails[0], "203.0.113.0/24"))
+	})
+
+	t.Run("new device is not reported after opt out", func(t *testing.T) {
+		err := s.UpdateLoginAlertOptOut(context.Background(), principal, true)
+		is.NoErr(err)
+
+		err = s.RecordSuccessfulLogin(context.Background(), principal, userAgentChromeMac, "10.0.0.1", now.Add(3*time.Hour))
+		is.NoErr(err)
+		is.Equal(len(mailResource.Mails), 1)
+	})
+}
+
+func TestRecordSuccessfulLoginWithoutLoginAlerts(t *testing.T) {
+	is := is.New(t)
+
+	loginAlertRepository := NewInMemLoginAlertRepository()
+	s := newInMemLoginAlertService(&shared.Config{}, shared.NewInMemMailResource(), loginAlertRepository)
+
+	err := s.RecordSuccessfulLogin(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin@baralga.com"}, userAgentFirefoxWindows, "192.168.1.17", time.Now())
+	is.NoErr(err)
+
+	hasDevices, err := loginAlertRepository.HasLoginDevices(context.Background(), shared.OrganizationIDSample, "admin@baralga.com")
+	is.NoErr(err)
+	is.True(!hasDevices)
+}
+
+func TestRecordFailedLogin(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := shared.NewInMemMailResource()
+	config := &shared.Config{
+		LoginAlerts:                true,
+		LoginFailureAlertThreshold: 3,
+		LoginFailureAlertWindow:    "15m",
+	}
+	s := newInMemLoginAlertService(config, mailResource, NewInMemLoginAlertRepository())
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		err := s.RecordFailedLogin(context.Background(), shared.OrganizationIDSample, "user1", now.Add(time.Duration(i)*time.Minute))
+		is.NoErr(err)
+	}
+	is.Equal(len(mailResource.Mails), 0)
+
+	// failed logins of an earlier window are not counted
+	err := s.RecordFailedLogin(context.Background(), shared.OrganizationIDSample, "user1", now.Add(30*time.Minute))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 0)
+
+	for i := 1; i < 3; i++ {
+		err := s.RecordFailedLogin(context.Background(), shared.OrganizationIDSample, "user2", now.Add(30*time.Minute+time.Duration(i)*time.Minute))
+		is.NoErr(err)
+	}
+	is.Equal(len(mailResource.Mails), 1)
+	is.True(strings.HasPrefix(mailResource.Mails[0], "admin@baralga.com"))
+	is.True(strings.Contains(mailResource.Mails[0], "3 failed logins"))
+
+	// admins are alerted once per window
+	err = s.RecordFailedLogin(context.Background(), shared.OrganizationIDSample, "user2", now.Add(35*time.Minute))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 1)
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	maxServiceAccountNameLength = 100
+	maxServiceAccountScopes     = 50
+
+	// serviceAccountTokenPrefix tells api tokens of service accounts apart from the JWTs of users
+	serviceAccountTokenPrefix = "bsa_"
+)
+
+// serviceAccountScopePattern is a scope like activities:read or projects:write
+var serviceAccountScopePattern = regexp.MustCompile(`^[a-z][a-z-]*:(read|write)$`)
+
+var (
+	ErrServiceAccountNotFound     = shared.NewDomainError("service-account:not-found", http.StatusNotFound, "service account not found")
+	ErrServiceAccountUnauthorized = shared.NewDomainError("service-account:unauthorized", http.StatusUnauthorized, "service account token not valid")
+)
+
+// ServiceAccount is a non-human account of an integration, it's authenticated by its api token only
+// and restricted to the api resources of its scopes
+type ServiceAccount struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Name           string
+	Role           string
+	Scopes         []string
+	TokenHash      string
+	CreatedBy      string
+	CreatedAt      time.Time
+	LastUsedAt     *time.Time
+
+	// Token is only known right after the token is issued as just the hash is stored
+	Token string
+}
+
+type ServiceAccountRepository interface {
+	FindServiceAccounts(ctx context.Context, organizationID uuid.UUID) ([]*ServiceAccount, error)
+	FindServiceAccountByTokenHash(ctx context.Context, tokenHash string) (*ServiceAccount, error)
+	InsertServiceAccount(ctx context.Context, serviceAccount *ServiceAccount) error
+	UpdateServiceAccountTokenHash(ctx context.Context, organizationID, serviceAccountID uuid.UUID, tokenHash string) error
+	MarkServiceAccountUsed(ctx context.Context, organizationID, serviceAccountID uuid.UUID, usedAt time.Time) error
+	DeleteServiceAccount(ctx context.Context, organizationID, serviceAccountID uuid.UUID) error
+}
+
+// Principal is the principal of requests authenticated by the token of the service account
+func (s *ServiceAccount) Principal() *shared.Principal {
+	return &shared.Principal{
+		Name:             s.Name,
+		Username:         s.ID.String(),
+		OrganizationID:   s.OrganizationID,
+		Roles:            []string{s.Role},
+		ServiceAccountID: s.ID,
+		Scopes:           s.Scopes,
+	}
+}
+
+// ValidateServiceAccount checks the name, the role and the scopes of a service account
+func ValidateServiceAccount(name, role string, scopes []string) error {
+	if name == "" {
+		return shared.NewInvalidParam("name", "required", "name is required")
+	}
+	if utf8.RuneCountInString(name) > maxServiceAccountNameLength {
+		return shared.NewInvalidParam("name", "max", "name must not be longer than 100 characters")
+	}
+	if role != "ROLE_USER" && role != "ROLE_ADMIN" {
+		return shared.NewInvalidParam("role", "oneof", "role must be ROLE_USER or ROLE_ADMIN")
+	}
+	if len(scopes) == 0 {
+		return shared.NewInvalidParam("scopes", "required", "at least one scope is required")
+	}
+	if len(scopes) > maxServiceAccountScopes {
+		return shared.NewInvalidParam("scopes", "max", "at most 50 scopes are allowed")
+	}
+	for _, scope := range scopes {
+		if !serviceAccountScopePattern.MatchString(scope) {
+			return shared.NewInvalidParam("scopes", "pattern", "scopes must be like activities:read or projects:write")
+		}
+	}
+	return nil
+}
+
+// isServiceAccountToken checks whether the bearer token is the api token of a service account
+func isServiceAccountToken(token string) bool {
+	return strings.HasPrefix(token, serviceAccountTokenPrefix)
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestValidateServiceAccount(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(ValidateServiceAccount("CRM Sync", "ROLE_USER", []string{"activities:read", "projects:write"}))
+	is.True(ValidateServiceAccount("", "ROLE_USER", []string{"activities:read"}) != nil)
+	is.True(ValidateServiceAccount("CRM Sync", "ROLE_CLIENT", []string{"activities:read"}) != nil)
+	is.True(ValidateServiceAccount("CRM Sync", "ROLE_USER", nil) != nil)
+	is.True(ValidateServiceAccount("CRM Sync", "ROLE_USER", []string{"activities"}) != nil)
+	is.True(ValidateServiceAccount("CRM Sync", "ROLE_USER", []string{"activities:delete"}) != nil)
+}
+
+func TestServiceAccountPrincipal(t *testing.T) {
+	is := is.New(t)
+
+	serviceAccount := &ServiceAccount{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Name:           "CRM Sync",
+		Role:           "ROLE_USER",
+		Scopes:         []string{"activities:read"},
+	}
+
+	principal := serviceAccount.Principal()
+	is.True(principal.IsServiceAccount())
+	is.Equal(principal.Name, "CRM Sync")
+	is.Equal(principal.Username, serviceAccount.ID.String())
+	is.True(principal.HasRole("ROLE_USER"))
+	is.True(principal.HasScope("activities", false))
+	is.True(!principal.HasScope("activities", true))
+}
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbServiceAccountRepository is a SQL database repository for the service accounts of organizations
+type DbServiceAccountRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ ServiceAccountRepository = (*DbServiceAccountRepository)(nil)
+
+// NewDbServiceAccountRepository creates a new SQL database repository for service accounts
+func NewDbServiceAccountRepository(connPool *pgxpool.Pool) *DbServiceAccountRepository {
+	return &DbServiceAccountRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbServiceAccountRepository) FindServiceAccounts(ctx context.Context, organizationID uuid.UUID) ([]*ServiceAccount, error) {
+	rows, err := shared.SelectAll[serviceAccountRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[serviceAccountRow]()+`
+		 FROM service_accounts
+		 WHERE org_id = $1
+		 ORDER BY name ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceAccounts := make([]*ServiceAccount, len(rows))
+	for i, row := range rows {
+		serviceAccounts[i] = row.toServiceAccount()
+	}
+	return serviceAccounts, nil
+}
+
+// FindServiceAccountByTokenHash reads the service account of the token across all organizations
+func (r *DbServiceAccountRepository) FindServiceAccountByTokenHash(ctx context.Context, tokenHash string) (*ServiceAccount, error) {
+	row, err := shared.SelectOne[serviceAccountRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[serviceAccountRow]()+`
+		 FROM service_accounts
+		 WHERE token_hash = $1`,
+		tokenHash,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrServiceAccountNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toServiceAccount(), nil
+}
+
+func (r *DbServiceAccountRepository) InsertServiceAccount(ctx context.Context, serviceAccount *ServiceAccount) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO service_accounts
+		   (service_account_id, org_id, name, role, scopes, token_hash, created_by, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		serviceAccount.ID,
+		serviceAccount.OrganizationID,
+		serviceAccount.Name,
+		serviceAccount.Role,
+		strings.Join(serviceAccount.Scopes, ","),
+		serviceAccount.TokenHash,
+		serviceAccount.CreatedBy,
+		serviceAccount.CreatedAt,
+	)
+	return err
+}
+
+func (r *DbServiceAccountRepository) UpdateServiceAccountTokenHash(ctx context.Context, organizationID, serviceAccountID uuid.UUID, tokenHash string) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE service_accounts
+		 SET token_hash = $3
+		 WHERE service_account_id = $1 AND org_id = $2`,
+		serviceAccountID, organizationID, tokenHash,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrServiceAccountNotFound
+	}
+	return nil
+}
+
+func (r *DbServiceAccountRepository) MarkServiceAccountUsed(ctx context.Context, organizationID, serviceAccountID uuid.UUID, usedAt time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE service_accounts
+		 SET last_used_at = $3
+		 WHERE service_account_id = $1 AND org_id = $2`,
+		serviceAccountID, organizationID, usedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrServiceAccountNotFound
+	}
+	return nil
+}
+
+func (r *DbServiceAccountRepository) DeleteServiceAccount(ctx context.Context, organizationID, serviceAccountID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM service_accounts
+		 WHERE service_account_id = $1 AND org_id = $2`,
+		serviceAccountID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrServiceAccountNotFound
+	}
+	return nil
+}
+
+type serviceAccountRow struct {
+	ID             uuid.UUID  `db:"service_account_id"`
+	OrganizationID uuid.UUID  `db:"org_id"`
+	Name           string     `db:"name"`
+	Role           string     `db:"role"`
+	Scopes         string     `db:"scopes"`
+	TokenHash      string     `db:"token_hash"`
+	CreatedBy      string     `db:"created_by"`
+	CreatedAt      time.Time  `db:"created_at"`
+	LastUsedAt     *time.Time `db:"last_used_at"`
+}
+
+func (r *serviceAccountRow) toServiceAccount() *ServiceAccount {
+	var scopes []string
+	if r.Scopes != "" {
+		scopes = strings.Split(r.Scopes, ",")
+	}
+
+	return &ServiceAccount{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Name:           r.Name,
+		Role:           r.Role,
+		Scopes:         scopes,
+		TokenHash:      r.TokenHash,
+		CreatedBy:      r.CreatedBy,
+		CreatedAt:      r.CreatedAt,
+		LastUsedAt:     r.LastUsedAt,
+	}
+}
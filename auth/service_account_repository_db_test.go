@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+func TestServiceAccountRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	serviceAccountRepository := NewDbServiceAccountRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	t.Run("InsertAndAuthenticateServiceAccount", func(t *testing.T) {
+		serviceAccount := &ServiceAccount{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			Name:           "CRM Sync",
+			Role:           "ROLE_USER",
+			Scopes:         []string{"activities:read", "projects:write"},
+			TokenHash:      shared.HashToken("bsa_sample"),
+			CreatedBy:      "admin@baralga.com",
+			CreatedAt:      now,
+		}
+
+		err := repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return serviceAccountRepository.InsertServiceAccount(ctx, serviceAccount)
+			},
+			func(ctx context.Context) error {
+				return serviceAccountRepository.MarkServiceAccountUsed(ctx, shared.OrganizationIDSample, serviceAccount.ID, now)
+			},
+		)
+		is.NoErr(err)
+
+		found, err := serviceAccountRepository.FindServiceAccountByTokenHash(ctx, shared.HashToken("bsa_sample"))
+		is.NoErr(err)
+		is.Equal(found.Name, "CRM Sync")
+		is.Equal(found.Scopes, []string{"activities:read", "projects:write"})
+		is.True(found.LastUsedAt != nil)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return serviceAccountRepository.UpdateServiceAccountTokenHash(ctx, shared.OrganizationIDSample, serviceAccount.ID, shared.HashToken("bsa_rotated"))
+			},
+		)
+		is.NoErr(err)
+
+		_, err = serviceAccountRepository.FindServiceAccountByTokenHash(ctx, shared.HashToken("bsa_sample"))
+		is.True(errors.Is(err, ErrServiceAccountNotFound))
+
+		serviceAccounts, err := serviceAccountRepository.FindServiceAccounts(ctx, shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(serviceAccounts), 1)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return serviceAccountRepository.DeleteServiceAccount(ctx, shared.OrganizationIDSample, serviceAccount.ID)
+			},
+		)
+		is.NoErr(err)
+	})
+}
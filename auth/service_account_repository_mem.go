@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemServiceAccountRepository struct {
+	mu              sync.Mutex
+	serviceAccounts []*ServiceAccount
+}
+
+var _ ServiceAccountRepository = (*InMemServiceAccountRepository)(nil)
+
+func NewInMemServiceAccountRepository() *InMemServiceAccountRepository {
+	return &InMemServiceAccountRepository{}
+}
+
+func (r *InMemServiceAccountRepository) FindServiceAccounts(ctx context.Context, organizationID uuid.UUID) ([]*ServiceAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var serviceAccounts []*ServiceAccount
+	for _, serviceAccount := range r.serviceAccounts {
+		if serviceAccount.OrganizationID == organizationID {
+			found := *serviceAccount
+			serviceAccounts = append(serviceAccounts, &found)
+		}
+	}
+
+	sort.Slice(serviceAccounts, func(i, j int) bool {
+		return serviceAccounts[i].Name < serviceAccounts[j].Name
+	})
+	return serviceAccounts, nil
+}
+
+func (r *InMemServiceAccountRepository) FindServiceAccountByTokenHash(ctx context.Context, tokenHash string) (*ServiceAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, serviceAccount := range r.serviceAccounts {
+		if serviceAccount.TokenHash == tokenHash {
+			found := *serviceAccount
+			return &found, nil
+		}
+	}
+	return nil, ErrServiceAccountNotFound
+}
+
+func (r *InMemServiceAccountRepository) InsertServiceAccount(ctx context.Context, serviceAccount *ServiceAccount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *serviceAccount
+	inserted.Token = ""
+	r.serviceAccounts = append(r.serviceAccounts, &inserted)
+	return nil
+}
+
+func (r *InMemServiceAccountRepository) UpdateServiceAccountTokenHash(ctx context.Context, organizationID, serviceAccountID uuid.UUID, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, serviceAccount := range r.serviceAccounts {
+		if serviceAccount.OrganizationID == organizationID && serviceAccount.ID == serviceAccountID {
+			serviceAccount.TokenHash = tokenHash
+			return nil
+		}
+	}
+	return ErrServiceAccountNotFound
+}
+
+func (r *InMemServiceAccountRepository) MarkServiceAccountUsed(ctx context.Context, organizationID, serviceAccountID uuid.UUID, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, serviceAccount := range r.serviceAccounts {
+		if serviceAccount.OrganizationID == organizationID && serviceAccount.ID == serviceAccountID {
+			serviceAccount.LastUsedAt = &usedAt
+			return nil
+		}
+	}
+	return ErrServiceAccountNotFound
+}
+
+func (r *InMemServiceAccountRepository) DeleteServiceAccount(ctx context.Context, organizationID, serviceAccountID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, serviceAccount := range r.serviceAccounts {
+		if serviceAccount.OrganizationID == organizationID && serviceAccount.ID == serviceAccountID {
+			r.serviceAccounts = append(r.serviceAccounts[:i], r.serviceAccounts[i+1:]...)
+			return nil
+		}
+	}
+	return ErrServiceAccountNotFound
+}
@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type serviceAccountModel struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	Scopes     []string   `json:"scopes"`
+	Token      string     `json:"token,omitempty"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  string     `json:"createdAt"`
+	LastUsedAt string     `json:"lastUsedAt,omitempty"`
+	Links      *hal.Links `json:"_links"`
+}
+
+type serviceAccountsModel struct {
+	Embedded *embeddedServiceAccounts `json:"_embedded"`
+	Links    *hal.Links               `json:"_links"`
+}
+
+type embeddedServiceAccounts struct {
+	ServiceAccountModels []*serviceAccountModel `json:"serviceAccounts"`
+}
+
+type serviceAccountTokenModel struct {
+	Token string `json:"token"`
+}
+
+type ServiceAccountRestHandlers struct {
+	config                *shared.Config
+	serviceAccountService *ServiceAccountService
+}
+
+func NewServiceAccountRestHandlers(config *shared.Config, serviceAccountService *ServiceAccountService) *ServiceAccountRestHandlers {
+	return &ServiceAccountRestHandlers{
+		config:                config,
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+func (a *ServiceAccountRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/service-accounts", a.HandleGetServiceAccounts())
+	r.Post("/service-accounts", a.HandleCreateServiceAccount())
+	r.Post("/service-accounts/{service-account-id}/token", a.HandleRotateServiceAccountToken())
+	r.Delete("/service-accounts/{service-account-id}", a.HandleDeleteServiceAccount())
+}
+
+func (a *ServiceAccountRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// ServiceAccountMiddleware authenticates service accounts by the api token in the bearer token, requests
+// with other tokens are left to the JWT verification of users
+func (a *ServiceAccountRestHandlers) ServiceAccountMiddleware() func(next http.Handler) http.Handler {
+	isProduction := a.config.IsProduction()
+	serviceAccountService := a.serviceAccountService
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !isServiceAccountToken(token) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := serviceAccountService.AuthenticateServiceAccount(r.Context(), token, time.Now())
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// HandleGetServiceAccounts reads the service accounts of the organization
+func (a *ServiceAccountRestHandlers) HandleGetServiceAccounts() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	serviceAccountService := a.serviceAccountService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		serviceAccounts, err := serviceAccountService.ReadServiceAccounts(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		serviceAccountModels := make([]*serviceAccountModel, len(serviceAccounts))
+		for i, serviceAccount := range serviceAccounts {
+			serviceAccountModels[i] = mapToServiceAccountModel(serviceAccount)
+		}
+
+		shared.RenderJSON(w, &serviceAccountsModel{
+			Embedded: &embeddedServiceAccounts{
+				ServiceAccountModels: serviceAccountModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleCreateServiceAccount creates a service account, its api token is only shown in the response
+func (a *ServiceAccountRestHandlers) HandleCreateServiceAccount() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	serviceAccountService := a.serviceAccountService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var serviceAccountModel serviceAccountModel
+		err := json.NewDecoder(r.Body).Decode(&serviceAccountModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "service account not valid", err)
+			return
+		}
+
+		if serviceAccountModel.Role == "" {
+			serviceAccountModel.Role = "ROLE_USER"
+		}
+		err = ValidateServiceAccount(serviceAccountModel.Name, serviceAccountModel.Role, serviceAccountModel.Scopes)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "service account not valid", err)
+			return
+		}
+
+		serviceAccount, err := serviceAccountService.CreateServiceAccount(
+			r.Context(),
+			principal,
+			serviceAccountModel.Name,
+			serviceAccountModel.Role,
+			serviceAccountModel.Scopes,
+			time.Now(),
+		)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToServiceAccountModel(serviceAccount))
+	}
+}
+
+// HandleRotateServiceAccountToken issues a new api token for a service account, it's only shown in the response
+func (a *ServiceAccountRestHandlers) HandleRotateServiceAccountToken() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	serviceAccountService := a.serviceAccountService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		serviceAccountID, err := uuid.Parse(chi.URLParam(r, "service-account-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		token, err := serviceAccountService.RotateServiceAccountToken(r.Context(), principal, serviceAccountID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, &serviceAccountTokenModel{Token: token})
+	}
+}
+
+// HandleDeleteServiceAccount removes a service account
+func (a *ServiceAccountRestHandlers) HandleDeleteServiceAccount() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	serviceAccountService := a.serviceAccountService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		serviceAccountID, err := uuid.Parse(chi.URLParam(r, "service-account-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = serviceAccountService.DeleteServiceAccount(r.Context(), principal, serviceAccountID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+func mapToServiceAccountModel(serviceAccount *ServiceAccount) *serviceAccountModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/service-accounts/%s", serviceAccount.ID))
+	serviceAccountModel := &serviceAccountModel{
+		ID:        serviceAccount.ID.String(),
+		Name:      serviceAccount.Name,
+		Role:      serviceAccount.Role,
+		Scopes:    serviceAccount.Scopes,
+		Token:     serviceAccount.Token,
+		CreatedBy: serviceAccount.CreatedBy,
+		CreatedAt: serviceAccount.CreatedAt.UTC().Format(time.RFC3339),
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("token", fmt.Sprintf("/api/service-accounts/%s/token", serviceAccount.ID)),
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+	if serviceAccount.LastUsedAt != nil {
+		serviceAccountModel.LastUsedAt = serviceAccount.LastUsedAt.UTC().Format(time.RFC3339)
+	}
+	return serviceAccountModel
+}
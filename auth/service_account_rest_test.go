@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleServiceAccounts(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{}
+	a := NewServiceAccountRestHandlers(config, NewServiceAccountService(shared.NewInMemRepositoryTxer(), NewInMemServiceAccountRepository()))
+	principal := &shared.Principal{Username: "admin@baralga.com", OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}}
+
+	r := chi.NewRouter()
+	a.RegisterProtected(r)
+
+	httpRec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/service-accounts", strings.NewReader(`{"name": "CRM Sync", "scopes": ["activities:read"]}`))
+	req = req.WithContext(context.WithValue(req.Context(), shared.ContextKeyPrincipal, principal))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	serviceAccountModel := &serviceAccountModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(serviceAccountModel)
+	is.NoErr(err)
+	is.Equal(serviceAccountModel.Role, "ROLE_USER")
+	is.True(serviceAccountModel.Token != "")
+
+	httpRec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/service-accounts", strings.NewReader(`{"name": "CRM Sync", "scopes": ["everything"]}`))
+	req = req.WithContext(context.WithValue(req.Context(), shared.ContextKeyPrincipal, principal))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/service-accounts", nil)
+	req = req.WithContext(context.WithValue(req.Context(), shared.ContextKeyPrincipal, principal))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	serviceAccountsModel := &serviceAccountsModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(serviceAccountsModel)
+	is.NoErr(err)
+	is.Equal(len(serviceAccountsModel.Embedded.ServiceAccountModels), 1)
+	is.Equal(serviceAccountsModel.Embedded.ServiceAccountModels[0].Token, "")
+
+	httpRec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/service-accounts/"+serviceAccountModel.ID+"/token", nil)
+	req = req.WithContext(context.WithValue(req.Context(), shared.ContextKeyPrincipal, principal))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/service-accounts/"+serviceAccountModel.ID, nil)
+	req = req.WithContext(context.WithValue(req.Context(), shared.ContextKeyPrincipal, principal))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+}
+
+func TestServiceAccountMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{}
+	serviceAccountService := NewServiceAccountService(shared.NewInMemRepositoryTxer(), NewInMemServiceAccountRepository())
+	a := NewServiceAccountRestHandlers(config, serviceAccountService)
+	admin := &shared.Principal{Username: "admin@baralga.com", OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}}
+	serviceAccount, err := serviceAccountService.CreateServiceAccount(context.Background(), admin, "CRM Sync", "ROLE_USER", []string{"activities:read"}, time.Now())
+	is.NoErr(err)
+
+	var principal *shared.Principal
+	handler := a.ServiceAccountMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+	}))
+
+	request := func(authorization string) int {
+		principal = nil
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/activities", nil)
+		r.Header.Set("Authorization", authorization)
+		handler.ServeHTTP(httpRec, r)
+		return httpRec.Result().StatusCode
+	}
+
+	is.Equal(request("Bearer "+serviceAccount.Token), http.StatusOK)
+	is.Equal(principal.ServiceAccountID, serviceAccount.ID)
+
+	is.Equal(request("Bearer "+serviceAccountTokenPrefix+"invalid"), http.StatusUnauthorized)
+
+	is.Equal(request("Bearer eyJhbGciOiJIUzI1NiJ9.e30.x"), http.StatusOK)
+	is.True(principal == nil)
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// serviceAccountTokenBytes is the length of the random api tokens
+const serviceAccountTokenBytes = 32
+
+// ServiceAccountService manages the service accounts of an organization and authenticates them by their api token
+type ServiceAccountService struct {
+	repositoryTxer           shared.RepositoryTxer
+	serviceAccountRepository ServiceAccountRepository
+}
+
+// NewServiceAccountService creates a new service for service accounts
+func NewServiceAccountService(repositoryTxer shared.RepositoryTxer, serviceAccountRepository ServiceAccountRepository) *ServiceAccountService {
+	return &ServiceAccountService{
+		repositoryTxer:           repositoryTxer,
+		serviceAccountRepository: serviceAccountRepository,
+	}
+}
+
+// ReadServiceAccounts reads the service accounts of the organization, only admins manage service accounts
+func (s *ServiceAccountService) ReadServiceAccounts(ctx context.Context, principal *shared.Principal) ([]*ServiceAccount, error) {
+	if !canManageServiceAccounts(principal) {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.serviceAccountRepository.FindServiceAccounts(ctx, principal.OrganizationID)
+}
+
+// CreateServiceAccount creates a service account with a new api token, the token is only returned here
+func (s *ServiceAccountService) CreateServiceAccount(ctx context.Context, principal *shared.Principal, name, role string, scopes []string, now time.Time) (*ServiceAccount, error) {
+	if !canManageServiceAccounts(principal) {
+		return nil, shared.ErrForbidden
+	}
+
+	token, err := newServiceAccountToken()
+	if err != nil {
+		return nil, err
+	}
+
+	serviceAccount := &ServiceAccount{
+		ID:             uuid.New(),
+		OrganizationID: principal.OrganizationID,
+		Name:           name,
+		Role:           role,
+		Scopes:         scopes,
+		TokenHash:      shared.HashToken(token),
+		CreatedBy:      principal.Username,
+		CreatedAt:      now,
+		Token:          token,
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.serviceAccountRepository.InsertServiceAccount(ctx, serviceAccount)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return serviceAccount, nil
+}
+
+// RotateServiceAccountToken issues a new api token for the service account, the previous token is no longer valid
+func (s *ServiceAccountService) RotateServiceAccountToken(ctx context.Context, principal *shared.Principal, serviceAccountID uuid.UUID) (string, error) {
+	if !canManageServiceAccounts(principal) {
+		return "", shared.ErrForbidden
+	}
+
+	token, err := newServiceAccountToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.serviceAccountRepository.UpdateServiceAccountTokenHash(ctx, principal.OrganizationID, serviceAccountID, shared.HashToken(token))
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// DeleteServiceAccount removes the service account, its token is no longer valid
+func (s *ServiceAccountService) DeleteServiceAccount(ctx context.Context, principal *shared.Principal, serviceAccountID uuid.UUID) error {
+	if !canManageServiceAccounts(principal) {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.serviceAccountRepository.DeleteServiceAccount(ctx, principal.OrganizationID, serviceAccountID)
+		},
+	)
+}
+
+// AuthenticateServiceAccount authenticates the service account by its api token and remembers its last use
+func (s *ServiceAccountService) AuthenticateServiceAccount(ctx context.Context, token string, now time.Time) (*shared.Principal, error) {
+	if !isServiceAccountToken(token) {
+		return nil, ErrServiceAccountUnauthorized
+	}
+
+	serviceAccount, err := s.serviceAccountRepository.FindServiceAccountByTokenHash(ctx, shared.HashToken(token))
+	if errors.Is(err, ErrServiceAccountNotFound) {
+		return nil, ErrServiceAccountUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = shared.WithOrganizationID(ctx, serviceAccount.OrganizationID)
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.serviceAccountRepository.MarkServiceAccountUsed(ctx, serviceAccount.OrganizationID, serviceAccount.ID, now)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return serviceAccount.Principal(), nil
+}
+
+// canManageServiceAccounts checks whether the principal is an admin, service accounts never manage
+// service accounts so an integration can't create accounts outliving it
+func canManageServiceAccounts(principal *shared.Principal) bool {
+	return principal.HasRole("ROLE_ADMIN") && !principal.IsServiceAccount()
+}
+
+func newServiceAccountToken() (string, error) {
+	token := make([]byte, serviceAccountTokenBytes)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return serviceAccountTokenPrefix + base64.RawURLEncoding.EncodeToString(token), nil
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+func TestServiceAccountService(t *testing.T) {
+	is := is.New(t)
+
+	s := NewServiceAccountService(shared.NewInMemRepositoryTxer(), NewInMemServiceAccountRepository())
+	admin := &shared.Principal{Username: "admin@baralga.com", OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}}
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	serviceAccount, err := s.CreateServiceAccount(context.Background(), admin, "CRM Sync", "ROLE_USER", []string{"activities:read"}, now)
+	is.NoErr(err)
+	is.True(strings.HasPrefix(serviceAccount.Token, serviceAccountTokenPrefix))
+	is.Equal(serviceAccount.CreatedBy, "admin@baralga.com")
+
+	t.Run("authenticate by token", func(t *testing.T) {
+		principal, err := s.AuthenticateServiceAccount(context.Background(), serviceAccount.Token, now)
+		is.NoErr(err)
+		is.Equal(principal.ServiceAccountID, serviceAccount.ID)
+		is.Equal(principal.OrganizationID, shared.OrganizationIDSample)
+		is.Equal(principal.Scopes, []string{"activities:read"})
+
+		serviceAccounts, err := s.ReadServiceAccounts(context.Background(), admin)
+		is.NoErr(err)
+		is.Equal(len(serviceAccounts), 1)
+		is.Equal(*serviceAccounts[0].LastUsedAt, now)
+		is.Equal(serviceAccounts[0].Token, "")
+	})
+
+	t.Run("authenticate by invalid token", func(t *testing.T) {
+		_, err := s.AuthenticateServiceAccount(context.Background(), serviceAccountTokenPrefix+"invalid", now)
+		is.True(errors.Is(err, ErrServiceAccountUnauthorized))
+	})
+
+	t.Run("rotate token", func(t *testing.T) {
+		token, err := s.RotateServiceAccountToken(context.Background(), admin, serviceAccount.ID)
+		is.NoErr(err)
+
+		_, err = s.AuthenticateServiceAccount(context.Background(), serviceAccount.Token, now)
+		is.True(errors.Is(err, ErrServiceAccountUnauthorized))
+
+		_, err = s.AuthenticateServiceAccount(context.Background(), token, now)
+		is.NoErr(err)
+	})
+
+	t.Run("service accounts only managed by admins", func(t *testing.T) {
+		user := &shared.Principal{Username: "user1@baralga.com", OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_USER"}}
+		_, err := s.ReadServiceAccounts(context.Background(), user)
+		is.True(errors.Is(err, shared.ErrForbidden))
+
+		adminServiceAccount := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}, ServiceAccountID: serviceAccount.ID}
+		_, err = s.CreateServiceAccount(context.Background(), adminServiceAccount, "Other", "ROLE_ADMIN", []string{"service-accounts:write"}, now)
+		is.True(errors.Is(err, shared.ErrForbidden))
+	})
+
+	t.Run("delete service account", func(t *testing.T) {
+		err := s.DeleteServiceAccount(context.Background(), admin, serviceAccount.ID)
+		is.NoErr(err)
+
+		err = s.DeleteServiceAccount(context.Background(), admin, serviceAccount.ID)
+		is.True(errors.Is(err, ErrServiceAccountNotFound))
+	})
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/user"
+	"github.com/google/uuid"
+)
+
+// SSOProviderGoogle is the OpenID Connect provider Google, configured by the Google client id and secret
+const SSOProviderGoogle = "google"
+
+var (
+	ErrSSOPolicyNotFound        = shared.NewDomainError("sso-policy:not-found", http.StatusNotFound, "single sign-on policy not found")
+	ErrSSOPolicyNotValid        = shared.NewDomainError("sso-policy:not-valid", http.StatusBadRequest, "single sign-on policy not valid")
+	ErrSSOProviderNotConfigured = shared.NewDomainError("sso-policy:provider-not-configured", http.StatusBadRequest, "single sign-on provider is not configured")
+	ErrSSORequired              = shared.NewDomainError("auth:sso-required", http.StatusForbidden, "login with single sign-on required")
+)
+
+var emailDomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// SSOPolicy forces the users of an organization with an email address of the domain to log in with
+// the provider instead of their password, the usernames of the exceptions keep logging in with password
+type SSOPolicy struct {
+	OrganizationID uuid.UUID
+	EMailDomain    string
+	Provider       string
+	Exceptions     []string
+	UpdatedAt      time.Time
+}
+
+type SSOPolicyRepository interface {
+	FindSSOPolicies(ctx context.Context, organizationID uuid.UUID) ([]*SSOPolicy, error)
+	FindSSOPolicy(ctx context.Context, organizationID uuid.UUID, emailDomain string) (*SSOPolicy, error)
+	FindSSOPoliciesByEMailDomain(ctx context.Context, emailDomain string) ([]*SSOPolicy, error)
+	UpsertSSOPolicy(ctx context.Context, policy *SSOPolicy) error
+	DeleteSSOPolicy(ctx context.Context, organizationID uuid.UUID, emailDomain string) error
+}
+
+// Enforces checks whether the user has to log in with the provider of the policy
+func (p *SSOPolicy) Enforces(u *user.User) bool {
+	return EMailDomainOf(u.EMail) == p.EMailDomain && !slices.Contains(p.Exceptions, u.Username)
+}
+
+// EMailDomainOf is the lowercased domain of the email address, empty if it's no email address
+func EMailDomainOf(email string) string {
+	_, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(domain))
+}
+
+// IsValidEMailDomain checks whether the domain is a domain of email addresses like example.com
+func IsValidEMailDomain(emailDomain string) bool {
+	return emailDomainPattern.MatchString(emailDomain)
+}
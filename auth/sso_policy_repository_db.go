@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbSSOPolicyRepository is a SQL database repository for the single sign-on policies of organizations
+type DbSSOPolicyRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ SSOPolicyRepository = (*DbSSOPolicyRepository)(nil)
+
+// NewDbSSOPolicyRepository creates a new SQL database repository for the single sign-on policies of organizations
+func NewDbSSOPolicyRepository(connPool *pgxpool.Pool) *DbSSOPolicyRepository {
+	return &DbSSOPolicyRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbSSOPolicyRepository) FindSSOPolicies(ctx context.Context, organizationID uuid.UUID) ([]*SSOPolicy, error) {
+	rows, err := shared.SelectAll[ssoPolicyRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[ssoPolicyRow]()+`
+		 FROM sso_policies
+		 WHERE org_id = $1
+		 ORDER BY email_domain`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSSOPolicies(rows), nil
+}
+
+func (r *DbSSOPolicyRepository) FindSSOPolicy(ctx context.Context, organizationID uuid.UUID, emailDomain string) (*SSOPolicy, error) {
+	row, err := shared.SelectOne[ssoPolicyRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[ssoPolicyRow]()+`
+		 FROM sso_policies
+		 WHERE org_id = $1 AND email_domain = $2`,
+		organizationID, emailDomain,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSSOPolicyNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toSSOPolicy(), nil
+}
+
+// FindSSOPoliciesByEMailDomain reads the policies of all organizations for the email domain, it's used before login
+func (r *DbSSOPolicyRepository) FindSSOPoliciesByEMailDomain(ctx context.Context, emailDomain string) ([]*SSOPolicy, error) {
+	rows, err := shared.SelectAll[ssoPolicyRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[ssoPolicyRow]()+`
+		 FROM sso_policies
+		 WHERE email_domain = $1`,
+		emailDomain,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSSOPolicies(rows), nil
+}
+
+func (r *DbSSOPolicyRepository) UpsertSSOPolicy(ctx context.Context, policy *SSOPolicy) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO sso_policies
+		   (org_id, email_domain, provider, exceptions, updated_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5)
+		 ON CONFLICT (org_id, email_domain) DO UPDATE
+		 SET provider = EXCLUDED.provider, exceptions = EXCLUDED.exceptions, updated_at = EXCLUDED.updated_at`,
+		policy.OrganizationID,
+		policy.EMailDomain,
+		policy.Provider,
+		strings.Join(policy.Exceptions, ","),
+		policy.UpdatedAt,
+	)
+	return err
+}
+
+func (r *DbSSOPolicyRepository) DeleteSSOPolicy(ctx context.Context, organizationID uuid.UUID, emailDomain string) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM sso_policies
+		 WHERE org_id = $1 AND email_domain = $2`,
+		organizationID, emailDomain,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrSSOPolicyNotFound
+	}
+	return nil
+}
+
+type ssoPolicyRow struct {
+	OrganizationID uuid.UUID `db:"org_id"`
+	EMailDomain    string    `db:"email_domain"`
+	Provider       string    `db:"provider"`
+	Exceptions     string    `db:"exceptions"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (r *ssoPolicyRow) toSSOPolicy() *SSOPolicy {
+	var exceptions []string
+	if r.Exceptions != "" {
+		exceptions = strings.Split(r.Exceptions, ",")
+	}
+
+	return &SSOPolicy{
+		OrganizationID: r.OrganizationID,
+		EMailDomain:    r.EMailDomain,
+		Provider:       r.Provider,
+		Exceptions:     exceptions,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
+
+func toSSOPolicies(rows []*ssoPolicyRow) []*SSOPolicy {
+	policies := make([]*SSOPolicy, len(rows))
+	for i, row := range rows {
+		policies[i] = row.toSSOPolicy()
+	}
+	return policies
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+func TestSSOPolicyRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	ssoPolicyRepository := NewDbSSOPolicyRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	t.Run("UpsertAndDeleteSSOPolicy", func(t *testing.T) {
+		policy := &SSOPolicy{
+			OrganizationID: shared.OrganizationIDSample,
+			EMailDomain:    "baralga.com",
+			Provider:       SSOProviderGoogle,
+			Exceptions:     []string{"admin@baralga.com", "user1@baralga.com"},
+			UpdatedAt:      now,
+		}
+
+		err := repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return ssoPolicyRepository.UpsertSSOPolicy(ctx, policy)
+			},
+		)
+		is.NoErr(err)
+
+		policy.Exceptions = nil
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return ssoPolicyRepository.UpsertSSOPolicy(ctx, policy)
+			},
+		)
+		is.NoErr(err)
+
+		found, err := ssoPolicyRepository.FindSSOPolicy(ctx, shared.OrganizationIDSample, "baralga.com")
+		is.NoErr(err)
+		is.Equal(len(found.Exceptions), 0)
+
+		policies, err := ssoPolicyRepository.FindSSOPolicies(ctx, shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(policies), 1)
+
+		policies, err = ssoPolicyRepository.FindSSOPoliciesByEMailDomain(ctx, "baralga.com")
+		is.NoErr(err)
+		is.Equal(len(policies), 1)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return ssoPolicyRepository.DeleteSSOPolicy(ctx, shared.OrganizationIDSample, "baralga.com")
+			},
+		)
+		is.NoErr(err)
+
+		_, err = ssoPolicyRepository.FindSSOPolicy(ctx, shared.OrganizationIDSample, "baralga.com")
+		is.True(errors.Is(err, ErrSSOPolicyNotFound))
+	})
+}
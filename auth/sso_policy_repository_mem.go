@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemSSOPolicyRepository struct {
+	mu       sync.Mutex
+	policies []*SSOPolicy
+}
+
+var _ SSOPolicyRepository = (*InMemSSOPolicyRepository)(nil)
+
+func NewInMemSSOPolicyRepository() *InMemSSOPolicyRepository {
+	return &InMemSSOPolicyRepository{}
+}
+
+func (r *InMemSSOPolicyRepository) FindSSOPolicies(ctx context.Context, organizationID uuid.UUID) ([]*SSOPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var policies []*SSOPolicy
+	for _, p := range r.policies {
+		if p.OrganizationID == organizationID {
+			policy := *p
+			policies = append(policies, &policy)
+		}
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].EMailDomain < policies[j].EMailDomain
+	})
+	return policies, nil
+}
+
+func (r *InMemSSOPolicyRepository) FindSSOPolicy(ctx context.Context, organizationID uuid.UUID, emailDomain string) (*SSOPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.policies {
+		if p.OrganizationID == organizationID && p.EMailDomain == emailDomain {
+			policy := *p
+			return &policy, nil
+		}
+	}
+	return nil, ErrSSOPolicyNotFound
+}
+
+func (r *InMemSSOPolicyRepository) FindSSOPoliciesByEMailDomain(ctx context.Context, emailDomain string) ([]*SSOPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var policies []*SSOPolicy
+	for _, p := range r.policies {
+		if p.EMailDomain == emailDomain {
+			policy := *p
+			policies = append(policies, &policy)
+		}
+	}
+	return policies, nil
+}
+
+func (r *InMemSSOPolicyRepository) UpsertSSOPolicy(ctx context.Context, policy *SSOPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	upserted := *policy
+	for i, p := range r.policies {
+		if p.OrganizationID == policy.OrganizationID && p.EMailDomain == policy.EMailDomain {
+			r.policies[i] = &upserted
+			return nil
+		}
+	}
+
+	r.policies = append(r.policies, &upserted)
+	return nil
+}
+
+func (r *InMemSSOPolicyRepository) DeleteSSOPolicy(ctx context.Context, organizationID uuid.UUID, emailDomain string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, p := range r.policies {
+		if p.OrganizationID == organizationID && p.EMailDomain == emailDomain {
+			r.policies = append(r.policies[:i], r.policies[i+1:]...)
+			return nil
+		}
+	}
+	return ErrSSOPolicyNotFound
+}
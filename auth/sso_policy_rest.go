@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type ssoPolicyModel struct {
+	EMailDomain string     `json:"emailDomain"`
+	Provider    string     `json:"provider" validate:"required,oneof=google"`
+	Exceptions  []string   `json:"exceptions" validate:"max=100,dive,max=100"`
+	UpdatedAt   string     `json:"updatedAt,omitempty"`
+	Links       *hal.Links `json:"_links"`
+}
+
+type embeddedSSOPolicies struct {
+	SSOPolicyModels []*ssoPolicyModel `json:"ssoPolicies"`
+}
+
+type ssoPoliciesModel struct {
+	Embedded *embeddedSSOPolicies `json:"_embedded"`
+	Links    *hal.Links           `json:"_links"`
+}
+
+type SSOPolicyRestHandlers struct {
+	config           *shared.Config
+	ssoPolicyService *SSOPolicyService
+}
+
+func NewSSOPolicyRestHandlers(config *shared.Config, ssoPolicyService *SSOPolicyService) *SSOPolicyRestHandlers {
+	return &SSOPolicyRestHandlers{
+		config:           config,
+		ssoPolicyService: ssoPolicyService,
+	}
+}
+
+func (a *SSOPolicyRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/sso-policies", a.HandleGetSSOPolicies())
+	r.Put("/sso-policies/{email-domain}", a.HandleUpdateSSOPolicy())
+	r.Delete("/sso-policies/{email-domain}", a.HandleDeleteSSOPolicy())
+}
+
+func (a *SSOPolicyRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetSSOPolicies reads the single sign-on policies of the principal's organization
+func (a *SSOPolicyRestHandlers) HandleGetSSOPolicies() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	ssoPolicyService := a.ssoPolicyService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		policies, err := ssoPolicyService.ReadSSOPolicies(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		ssoPolicyModels := make([]*ssoPolicyModel, len(policies))
+		for i, policy := range policies {
+			ssoPolicyModels[i] = mapToSSOPolicyModel(policy)
+		}
+
+		shared.RenderJSON(w, &ssoPoliciesModel{
+			Embedded: &embeddedSSOPolicies{
+				SSOPolicyModels: ssoPolicyModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleUpdateSSOPolicy creates or replaces the single sign-on policy of the email domain
+func (a *SSOPolicyRestHandlers) HandleUpdateSSOPolicy() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	ssoPolicyService := a.ssoPolicyService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var ssoPolicyModel ssoPolicyModel
+		err := json.NewDecoder(r.Body).Decode(&ssoPolicyModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "single sign-on policy not valid", err)
+			return
+		}
+
+		err = validator.Struct(ssoPolicyModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "single sign-on policy not valid", err)
+			return
+		}
+
+		policy, err := ssoPolicyService.UpdateSSOPolicy(
+			r.Context(),
+			principal,
+			chi.URLParam(r, "email-domain"),
+			ssoPolicyModel.Provider,
+			ssoPolicyModel.Exceptions,
+			time.Now(),
+		)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToSSOPolicyModel(policy))
+	}
+}
+
+// HandleDeleteSSOPolicy removes the single sign-on policy of the email domain
+func (a *SSOPolicyRestHandlers) HandleDeleteSSOPolicy() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	ssoPolicyService := a.ssoPolicyService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		err := ssoPolicyService.DeleteSSOPolicy(r.Context(), principal, chi.URLParam(r, "email-domain"))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func mapToSSOPolicyModel(policy *SSOPolicy) *ssoPolicyModel {
+	exceptions := policy.Exceptions
+	if exceptions == nil {
+		exceptions = []string{}
+	}
+
+	return &ssoPolicyModel{
+		EMailDomain: policy.EMailDomain,
+		Provider:    policy.Provider,
+		Exceptions:  exceptions,
+		UpdatedAt:   policy.UpdatedAt.UTC().Format(time.RFC3339),
+		Links: hal.NewLinks(
+			hal.NewSelfLink(fmt.Sprintf("/api/sso-policies/%s", policy.EMailDomain)),
+		),
+	}
+}
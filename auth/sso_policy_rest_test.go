@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleSSOPolicies(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{GoogleClientId: "google-client-id"}
+	a := NewSSOPolicyRestHandlers(config, NewSSOPolicyService(config, shared.NewInMemRepositoryTxer(), NewInMemSSOPolicyRepository()))
+	principal := &shared.Principal{Username: "admin@baralga.com", OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}}
+
+	r := chi.NewRouter()
+	a.RegisterProtected(r)
+
+	httpRec := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/sso-policies/baralga.com", strings.NewReader(`{"provider": "google", "exceptions": ["admin@baralga.com"]}`))
+	req = req.WithContext(context.WithValue(req.Context(), shared.ContextKeyPrincipal, principal))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sso-policies", nil)
+	req = req.WithContext(context.WithValue(req.Context(), shared.ContextKeyPrincipal, principal))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	ssoPoliciesModel := &ssoPoliciesModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(ssoPoliciesModel)
+	is.NoErr(err)
+	is.Equal(len(ssoPoliciesModel.Embedded.SSOPolicyModels), 1)
+	is.Equal(ssoPoliciesModel.Embedded.SSOPolicyModels[0].EMailDomain, "baralga.com")
+	is.Equal(ssoPoliciesModel.Embedded.SSOPolicyModels[0].Exceptions, []string{"admin@baralga.com"})
+
+	httpRec = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/sso-policies/baralga.com", strings.NewReader(`{"provider": "github"}`))
+	req = req.WithContext(context.WithValue(req.Context(), shared.ContextKeyPrincipal, principal))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/sso-policies/baralga.com", nil)
+	req = req.WithContext(context.WithValue(req.Context(), shared.ContextKeyPrincipal, principal))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+)
+
+// SSOPolicyService lets admins force the users of an email domain to log in with single sign-on
+type SSOPolicyService struct {
+	config              *shared.Config
+	repositoryTxer      shared.RepositoryTxer
+	ssoPolicyRepository SSOPolicyRepository
+}
+
+// NewSSOPolicyService creates a new service for the single sign-on policies of organizations
+func NewSSOPolicyService(config *shared.Config, repositoryTxer shared.RepositoryTxer, ssoPolicyRepository SSOPolicyRepository) *SSOPolicyService {
+	return &SSOPolicyService{
+		config:              config,
+		repositoryTxer:      repositoryTxer,
+		ssoPolicyRepository: ssoPolicyRepository,
+	}
+}
+
+// ReadSSOPolicies reads the single sign-on policies of the principal's organization
+func (s *SSOPolicyService) ReadSSOPolicies(ctx context.Context, principal *shared.Principal) ([]*SSOPolicy, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.ssoPolicyRepository.FindSSOPolicies(ctx, principal.OrganizationID)
+}
+
+// UpdateSSOPolicy creates or replaces the single sign-on policy of the email domain in the principal's organization,
+// the usernames of the exceptions keep logging in with their password
+func (s *SSOPolicyService) UpdateSSOPolicy(ctx context.Context, principal *shared.Principal, emailDomain, provider string, exceptions []string, now time.Time) (*SSOPolicy, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	emailDomain = strings.ToLower(strings.TrimSpace(emailDomain))
+	if !IsValidEMailDomain(emailDomain) || provider != SSOProviderGoogle {
+		return nil, ErrSSOPolicyNotValid
+	}
+
+	if s.config.GoogleClientId == "" {
+		return nil, ErrSSOProviderNotConfigured
+	}
+
+	var usernames []string
+	for _, exception := range exceptions {
+		username := strings.TrimSpace(exception)
+		if username == "" {
+			continue
+		}
+		if strings.Contains(username, ",") {
+			return nil, ErrSSOPolicyNotValid
+		}
+		usernames = append(usernames, username)
+	}
+
+	policy := &SSOPolicy{
+		OrganizationID: principal.OrganizationID,
+		EMailDomain:    emailDomain,
+		Provider:       provider,
+		Exceptions:     usernames,
+		UpdatedAt:      now,
+	}
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.ssoPolicyRepository.UpsertSSOPolicy(ctx, policy)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// DeleteSSOPolicy removes the single sign-on policy of the email domain, so its users may log in with password again
+func (s *SSOPolicyService) DeleteSSOPolicy(ctx context.Context, principal *shared.Principal, emailDomain string) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.ssoPolicyRepository.DeleteSSOPolicy(ctx, principal.OrganizationID, strings.ToLower(emailDomain))
+		},
+	)
+}
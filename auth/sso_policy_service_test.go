@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+func TestSSOPolicyService(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{GoogleClientId: "google-client-id"}
+	s := NewSSOPolicyService(config, shared.NewInMemRepositoryTxer(), NewInMemSSOPolicyRepository())
+	admin := &shared.Principal{Username: "admin@baralga.com", OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}}
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	t.Run("update policy", func(t *testing.T) {
+		policy, err := s.UpdateSSOPolicy(context.Background(), admin, " Baralga.com", SSOProviderGoogle, []string{"admin@baralga.com", " "}, now)
+		is.NoErr(err)
+		is.Equal(policy.EMailDomain, "baralga.com")
+		is.Equal(policy.Exceptions, []string{"admin@baralga.com"})
+
+		policies, err := s.ReadSSOPolicies(context.Background(), admin)
+		is.NoErr(err)
+		is.Equal(len(policies), 1)
+	})
+
+	t.Run("update policy not valid", func(t *testing.T) {
+		_, err := s.UpdateSSOPolicy(context.Background(), admin, "not a domain", SSOProviderGoogle, nil, now)
+		is.True(errors.Is(err, ErrSSOPolicyNotValid))
+
+		_, err = s.UpdateSSOPolicy(context.Background(), admin, "baralga.com", "github", nil, now)
+		is.True(errors.Is(err, ErrSSOPolicyNotValid))
+	})
+
+	t.Run("update policy without configured provider", func(t *testing.T) {
+		s := NewSSOPolicyService(&shared.Config{}, shared.NewInMemRepositoryTxer(), NewInMemSSOPolicyRepository())
+
+		_, err := s.UpdateSSOPolicy(context.Background(), admin, "baralga.com", SSOProviderGoogle, nil, now)
+		is.True(errors.Is(err, ErrSSOProviderNotConfigured))
+	})
+
+	t.Run("policies only for admins", func(t *testing.T) {
+		principal := &shared.Principal{Username: "user1@baralga.com", OrganizationID: shared.OrganizationIDSample}
+
+		_, err := s.ReadSSOPolicies(context.Background(), principal)
+		is.True(errors.Is(err, shared.ErrForbidden))
+
+		_, err = s.UpdateSSOPolicy(context.Background(), principal, "baralga.com", SSOProviderGoogle, nil, now)
+		is.True(errors.Is(err, shared.ErrForbidden))
+
+		err = s.DeleteSSOPolicy(context.Background(), principal, "baralga.com")
+		is.True(errors.Is(err, shared.ErrForbidden))
+	})
+
+	t.Run("delete policy", func(t *testing.T) {
+		err := s.DeleteSSOPolicy(context.Background(), admin, "baralga.com")
+		is.NoErr(err)
+
+		err = s.DeleteSSOPolicy(context.Background(), admin, "baralga.com")
+		is.True(errors.Is(err, ErrSSOPolicyNotFound))
+	})
+}
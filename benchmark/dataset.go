@@ -0,0 +1,91 @@
+// Package benchmark generates large datasets and benchmarks the repositories and reports on them,
+// so performance regressions in paging and aggregation are caught before release.
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/tracking"
+	"github.com/baralga/user"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DatasetSize is the number of users and months of activities of a generated dataset
+type DatasetSize struct {
+	Name   string
+	Users  int
+	Months int
+}
+
+var (
+	DatasetSmall = &DatasetSize{Name: "small", Users: 5, Months: 3}
+	DatasetLarge = &DatasetSize{Name: "large", Users: 50, Months: 24}
+)
+
+// Dataset is a generated organization with its users, projects and activities in the database
+type Dataset struct {
+	OrganizationID uuid.UUID
+	Usernames      []string
+	Start          time.Time
+	End            time.Time
+	Activities     int
+}
+
+// DatasetSizeOf is the dataset size of the name, like from the environment variable BARALGA_BENCHSIZE
+func DatasetSizeOf(name string) (*DatasetSize, error) {
+	switch name {
+	case "", DatasetSmall.Name:
+		return DatasetSmall, nil
+	case DatasetLarge.Name:
+		return DatasetLarge, nil
+	default:
+		return nil, fmt.Errorf("unknown dataset size %v", name)
+	}
+}
+
+// DatasetSizeFromEnv is the dataset size configured by the environment variable BARALGA_BENCHSIZE, small by default
+func DatasetSizeFromEnv() (*DatasetSize, error) {
+	return DatasetSizeOf(os.Getenv("BARALGA_BENCHSIZE"))
+}
+
+// SeedDataset generates a new organization with a dataset of the size into the database,
+// the same seed generates the same data
+func SeedDataset(ctx context.Context, connPool *pgxpool.Pool, size *DatasetSize, seed int64, until time.Time) (*Dataset, error) {
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+	userService := user.NewUserService(&shared.Config{}, repositoryTxer, nil, user.NewDbUserRepository(connPool), user.NewDbOrganizationRepository(connPool), nil, nil)
+	demoService := tracking.NewDemoService(tracking.NewDbProjectRepository(connPool), tracking.NewDbActivityRepository(connPool))
+
+	organization := &user.Organization{
+		ID:    uuid.New(),
+		Title: fmt.Sprintf("Benchmark %v %v", size.Name, seed),
+	}
+	users := user.NewDemoUsers(organization.ID, seed, size.Users, "")
+	usernames := make([]string, len(users))
+	for i, u := range users {
+		usernames[i] = u.Username
+	}
+
+	err := userService.SetUpDemoOrganization(ctx, organization, users, demoService.OrganizationSeeder(usernames, seed, size.Months, until))
+	if err != nil {
+		return nil, err
+	}
+
+	var activities int
+	err = connPool.QueryRow(ctx, `SELECT count(*) FROM activities WHERE org_id = $1`, organization.ID).Scan(&activities)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dataset{
+		OrganizationID: organization.ID,
+		Usernames:      usernames,
+		Start:          until.AddDate(0, -size.Months, 0),
+		End:            until,
+		Activities:     activities,
+	}, nil
+}
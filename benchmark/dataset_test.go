@@ -0,0 +1,22 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestDatasetSizeOf(t *testing.T) {
+	is := is.New(t)
+
+	size, err := DatasetSizeOf("")
+	is.NoErr(err)
+	is.Equal(size, DatasetSmall)
+
+	size, err = DatasetSizeOf("large")
+	is.NoErr(err)
+	is.Equal(size, DatasetLarge)
+
+	_, err = DatasetSizeOf("huge")
+	is.True(err != nil)
+}
@@ -0,0 +1,22 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/tracking"
+)
+
+func BenchmarkGenerateDemoData(b *testing.B) {
+	usernames := make([]string, DatasetLarge.Users)
+	for i := range usernames {
+		usernames[i] = "user"
+	}
+	until := time.Date(2021, 11, 5, 0, 0, 0, 0, time.UTC)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tracking.GenerateDemoData(shared.OrganizationIDSample, usernames, benchmarkSeed, DatasetLarge.Months, until)
+	}
+}
@@ -0,0 +1,54 @@
+// Load test of paging and reports against a running instance seeded with `baralga seed-demo`.
+//
+//   k6 run -e BASE_URL=http://localhost:8080 -e USERNAME=demo1-1 -e PASSWORD=demo benchmark/k6/scenario.js
+import http from 'k6/http';
+import { check, sleep } from 'k6';
+
+const baseUrl = __ENV.BASE_URL || 'http://localhost:8080';
+
+export const options = {
+  scenarios: {
+    browse: {
+      executor: 'ramping-vus',
+      startVUs: 1,
+      stages: [
+        { duration: '30s', target: 20 },
+        { duration: '1m', target: 20 },
+        { duration: '15s', target: 0 },
+      ],
+    },
+  },
+  thresholds: {
+    'http_req_failed': ['rate<0.01'],
+    'http_req_duration{name:activities}': ['p(95)<500'],
+    'http_req_duration{name:reports}': ['p(95)<1000'],
+  },
+};
+
+export function setup() {
+  const res = http.post(
+    `${baseUrl}/api/auth/login`,
+    JSON.stringify({ username: __ENV.USERNAME || 'demo1-1', password: __ENV.PASSWORD || 'demo' }),
+    { headers: { 'Content-Type': 'application/json' } },
+  );
+  check(res, { 'logged in': (r) => r.status === 200 });
+  return { token: res.json('access_token') };
+}
+
+export default function (data) {
+  const params = (name) => ({
+    headers: { Authorization: `Bearer ${data.token}`, Accept: 'application/json' },
+    tags: { name },
+  });
+
+  const page = Math.floor(Math.random() * 10);
+  let res = http.get(`${baseUrl}/api/activities?t=year&page=${page}&size=50`, params('activities'));
+  check(res, { 'activities read': (r) => r.status === 200 });
+
+  for (const report of ['utilization', 'anomalies']) {
+    res = http.get(`${baseUrl}/api/reports/${report}?t=year`, params('reports'));
+    check(res, { 'report read': (r) => r.status === 200 });
+  }
+
+  sleep(1);
+}
@@ -0,0 +1,194 @@
+package benchmark
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/paged"
+	"github.com/baralga/tracking"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// benchmarkSeed is the seed of the benchmark dataset, so runs are comparable
+const benchmarkSeed = 1
+
+var (
+	benchmarkConnPool *pgxpool.Pool
+	benchmarkDataset  *Dataset
+)
+
+// TestMain seeds the benchmark database once for all benchmarks. The database of the environment variable
+// BARALGA_BENCHDB is used if set, else a database is started in docker. Short runs skip the database.
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	cleanupFunc, err := setupBenchmarkDatabase()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code := m.Run()
+
+	err = cleanupFunc()
+	if err != nil {
+		log.Print(err)
+	}
+	os.Exit(code)
+}
+
+func setupBenchmarkDatabase() (func() error, error) {
+	noCleanup := func() error { return nil }
+	if !isBenchmarkRun() {
+		return noCleanup, nil
+	}
+
+	ctx := context.Background()
+	cleanupFunc := noCleanup
+	if dbURL := os.Getenv("BARALGA_BENCHDB"); dbURL != "" {
+		connPool, err := shared.Connect(dbURL, &shared.DbPoolConfig{MaxConns: 4})
+		if err != nil {
+			return nil, err
+		}
+		benchmarkConnPool = connPool
+		cleanupFunc = func() error {
+			connPool.Close()
+			return nil
+		}
+	} else {
+		testCleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+		if err != nil {
+			return nil, err
+		}
+		benchmarkConnPool = connPool
+		cleanupFunc = testCleanupFunc
+	}
+
+	size, err := DatasetSizeFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	benchmarkDataset, err = SeedDataset(ctx, benchmarkConnPool, size, benchmarkSeed, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("seeded %v dataset with %v activities in %v", size.Name, benchmarkDataset.Activities, time.Since(start))
+
+	return cleanupFunc, nil
+}
+
+// isBenchmarkRun checks whether benchmarks run, benchmarks against the database are skipped in short mode
+func isBenchmarkRun() bool {
+	bench := flag.Lookup("test.bench")
+	return bench != nil && bench.Value.String() != "" && !testing.Short()
+}
+
+// skipWithoutDatabase skips the benchmark without the seeded benchmark database
+func skipWithoutDatabase(b *testing.B) {
+	if benchmarkConnPool == nil {
+		b.Skip("benchmark database not set up")
+	}
+}
+
+func newBenchmarkFilter() *tracking.ActivitiesFilter {
+	return &tracking.ActivitiesFilter{
+		Start:          benchmarkDataset.Start,
+		End:            benchmarkDataset.End,
+		SortBy:         "start",
+		SortOrder:      "desc",
+		OrganizationID: benchmarkDataset.OrganizationID,
+	}
+}
+
+func BenchmarkFindActivitiesFirstPage(b *testing.B) {
+	skipWithoutDatabase(b)
+	activityRepository := tracking.NewDbActivityRepository(benchmarkConnPool)
+	ctx := shared.WithOrganizationID(context.Background(), benchmarkDataset.OrganizationID)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, err := activityRepository.FindActivities(ctx, newBenchmarkFilter(), &paged.PageParams{Page: 0, Size: 50})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindActivitiesLastPage(b *testing.B) {
+	skipWithoutDatabase(b)
+	activityRepository := tracking.NewDbActivityRepository(benchmarkConnPool)
+	ctx := shared.WithOrganizationID(context.Background(), benchmarkDataset.OrganizationID)
+	lastPage := benchmarkDataset.Activities / 50
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, err := activityRepository.FindActivities(ctx, newBenchmarkFilter(), &paged.PageParams{Page: lastPage, Size: 50})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStreamActivities(b *testing.B) {
+	skipWithoutDatabase(b)
+	activityRepository := tracking.NewDbActivityRepository(benchmarkConnPool)
+	ctx := shared.WithOrganizationID(context.Background(), benchmarkDataset.OrganizationID)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := activityRepository.StreamActivities(ctx, newBenchmarkFilter(), func(activity *tracking.Activity, project *tracking.Project) error {
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTimeReportByMonth(b *testing.B) {
+	skipWithoutDatabase(b)
+	activityRepository := tracking.NewDbActivityRepository(benchmarkConnPool)
+	ctx := shared.WithOrganizationID(context.Background(), benchmarkDataset.OrganizationID)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := activityRepository.TimeReportByMonth(ctx, newBenchmarkFilter())
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkProjectReport(b *testing.B) {
+	skipWithoutDatabase(b)
+	activityRepository := tracking.NewDbActivityRepository(benchmarkConnPool)
+	ctx := shared.WithOrganizationID(context.Background(), benchmarkDataset.OrganizationID)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := activityRepository.ProjectReport(ctx, newBenchmarkFilter())
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUtilizationReportByUser(b *testing.B) {
+	skipWithoutDatabase(b)
+	activityRepository := tracking.NewDbActivityRepository(benchmarkConnPool)
+	ctx := shared.WithOrganizationID(context.Background(), benchmarkDataset.OrganizationID)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := activityRepository.UtilizationReportByUser(ctx, newBenchmarkFilter())
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
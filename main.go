@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"embed"
+	"flag"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/baralga/auth"
@@ -17,18 +22,52 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/go-http-utils/etag"
-	"github.com/gorilla/csrf"
+	"github.com/google/uuid"
 	"github.com/hellofresh/health-go/v5"
 	healthPgx "github.com/hellofresh/health-go/v5/checks/pgx5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/unrolled/secure"
 )
 
 //go:embed shared/assets
 var assets embed.FS
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "rotate-encryption-keys" {
+		err := rotateEncryptionKeys()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 2 && os.Args[1] == "restore-backup" {
+		err := restoreBackup(os.Args[2])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "check-integrity" {
+		err := checkIntegrity(len(os.Args) > 2 && os.Args[2] == "--repair")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "seed-demo" {
+		err := seedDemo(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	config, connPool, router, err := newApp()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
@@ -43,9 +82,157 @@ func main() {
 	}
 }
 
+// rotateEncryptionKeys re-encrypts all values encrypted at rest with the current encryption key
+func rotateEncryptionKeys() error {
+	config, err := shared.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	encrypter, err := shared.NewEncrypter(config.EncryptionKeys)
+	if err != nil {
+		return err
+	}
+
+	connPool, err := shared.Connect(config.Db, config.DbPoolConfig())
+	if err != nil {
+		return err
+	}
+	defer connPool.Close()
+
+	rotated, err := shared.RotateEncryptionKeys(context.Background(), connPool, encrypter)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("re-encrypted %v values with the current encryption key", rotated)
+	return nil
+}
+
+// restoreBackup replaces all organizations by the backup archive after verifying its integrity
+func restoreBackup(archiveFile string) error {
+	config, err := shared.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	connPool, err := shared.Connect(config.Db, config.DbPoolConfig())
+	if err != nil {
+		return err
+	}
+	defer connPool.Close()
+
+	storage, err := shared.NewStorage(config)
+	if err != nil {
+		return err
+	}
+
+	backupService := shared.NewBackupService(config, storage, shared.NewDbBackupRepository(connPool))
+
+	var manifest *shared.BackupManifest
+	if key, ok := strings.CutPrefix(archiveFile, "storage:"); ok {
+		manifest, err = backupService.RestoreStoredBackup(context.Background(), key)
+	} else {
+		manifest, err = backupService.RestoreBackup(context.Background(), func() (io.ReadCloser, error) {
+			return os.Open(archiveFile)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("restored %v tables of the backup from %v", len(manifest.Tables), manifest.CreatedAt.Format(time.RFC3339))
+	return nil
+}
+
+// checkIntegrity reports the integrity problems of the data across all organizations, repairing the repairable ones if requested
+func checkIntegrity(repair bool) error {
+	config, err := shared.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	connPool, err := shared.Connect(config.Db, config.DbPoolConfig())
+	if err != nil {
+		return err
+	}
+	defer connPool.Close()
+
+	integrityService := shared.NewIntegrityService(config, shared.NewDbRepositoryTxer(connPool), shared.NewDbIntegrityRepository(connPool))
+	report, err := integrityService.RunIntegrityCheck(context.Background(), repair)
+	if err != nil {
+		return err
+	}
+
+	for _, problem := range report.Problems {
+		status := ""
+		if problem.Repaired {
+			status = " (repaired)"
+		}
+		log.Printf("%s %s %v of organization %v: %s%s", problem.Type, problem.EntityType, problem.EntityID, problem.OrganizationID, problem.Detail, status)
+	}
+	log.Printf("found %v integrity problems, repaired %v", len(report.Problems), report.Repaired())
+	return nil
+}
+
+// seedDemo creates a new organization with demo users, projects and months of activities,
+// the same seed generates the same data
+func seedDemo(args []string) error {
+	flags := flag.NewFlagSet("seed-demo", flag.ContinueOnError)
+	seed := flags.Int64("seed", 1, "seed of the generated data")
+	users := flags.Int("users", 5, "number of users")
+	months := flags.Int("months", 6, "number of months of activities")
+	password := flags.String("password", "demo", "password of the users")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	config, err := shared.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	connPool, err := shared.Connect(config.Db, config.DbPoolConfig())
+	if err != nil {
+		return err
+	}
+	defer connPool.Close()
+
+	passwordHasher, err := shared.NewPasswordHasher(config)
+	if err != nil {
+		return err
+	}
+
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+	userService := user.NewUserService(config, repositoryTxer, nil, user.NewDbUserRepository(connPool), user.NewDbOrganizationRepository(connPool), passwordHasher, nil)
+	demoService := tracking.NewDemoService(tracking.NewDbProjectRepository(connPool), tracking.NewDbActivityRepository(connPool))
+
+	organization := &user.Organization{
+		ID:    uuid.New(),
+		Title: fmt.Sprintf("Demo Organization %v", *seed),
+	}
+	encryptedPassword, err := userService.EncryptPassword(*password)
+	if err != nil {
+		return err
+	}
+	demoUsers := user.NewDemoUsers(organization.ID, *seed, *users, encryptedPassword)
+	usernames := make([]string, len(demoUsers))
+	for i, demoUser := range demoUsers {
+		usernames[i] = demoUser.Username
+	}
+
+	err = userService.SetUpDemoOrganization(context.Background(), organization, demoUsers, demoService.OrganizationSeeder(usernames, *seed, *months, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("seeded demo organization %v with the users %v", organization.ID, strings.Join(usernames, ", "))
+	return nil
+}
+
 func newApp() (*shared.Config, *pgxpool.Pool, *chi.Mux, error) {
-	var config shared.Config
-	err := envconfig.Process("baralga", &config)
+	config, err := shared.LoadConfig()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -54,7 +241,42 @@ func newApp() (*shared.Config, *pgxpool.Pool, *chi.Mux, error) {
 		config.BindPort = port
 	}
 
-	connPool, err := shared.Connect(config.Db, config.DbMaxConns)
+	err = config.Validate()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	connPool, err := shared.Connect(config.Db, config.DbPoolConfig())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// values stored before their column was encrypted or with an older key are encrypted before serving
+	encrypter, err := shared.NewEncrypter(config.EncryptionKeys)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rotated, err := shared.RotateEncryptionKeys(context.Background(), connPool, encrypter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if rotated > 0 {
+		log.Printf("encrypted %v values with the current encryption key", rotated)
+	}
+
+	storage, err := shared.NewStorage(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	scanner, err := shared.NewScanner(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	eventPublisher, err := shared.NewEventPublisher(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	passwordHasher, err := shared.NewPasswordHasher(config)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -67,35 +289,225 @@ func newApp() (*shared.Config, *pgxpool.Pool, *chi.Mux, error) {
 		config.SMTPPassword,
 	)
 
+	jobRepository := shared.NewDbJobRepository(connPool)
+	jobService := shared.NewJobService(repositoryTxer, jobRepository)
+	jobRestHandlers := shared.NewJobRestHandlers(config, jobRepository)
+	configRestHandlers := shared.NewConfigRestHandlers(config)
+
+	featureFlagRepository := shared.NewDbFeatureFlagRepository(connPool)
+	featureService := shared.NewFeatureService(config, repositoryTxer, featureFlagRepository)
+	featureRestHandlers := shared.NewFeatureRestHandlers(config, featureService)
+	brandingRepository := shared.NewDbBrandingRepository(connPool)
+	outbox := shared.NewBrandedOutbox(shared.NewDbOutbox(jobService, mailResource, eventPublisher), brandingRepository)
+
+	lifecycleService := shared.NewLifecycleService(config, repositoryTxer, jobService, shared.NewDbLifecycleRepository(connPool))
+	lifecycleRestHandlers := shared.NewLifecycleRestHandlers(config, lifecycleService)
+	apiUsageService := shared.NewAPIUsageService(repositoryTxer, jobService, shared.NewDbAPIUsageRepository(connPool))
+	apiUsageRestHandlers := shared.NewAPIUsageRestHandlers(config, apiUsageService)
+	auditRepository := shared.NewDbAuditRepository(connPool)
+	auditService := shared.NewAuditService(repositoryTxer, auditRepository)
+	auditRestHandlers := shared.NewAuditRestHandlers(config, auditService)
+	instanceService := shared.NewInstanceService(config, repositoryTxer, shared.NewDbInstanceRepository(connPool), lifecycleService)
+	instanceRestHandlers := shared.NewInstanceRestHandlers(config, instanceService)
+	maintenanceService := shared.NewMaintenanceService(config)
+	preferenceService := shared.NewPreferenceService(repositoryTxer, shared.NewDbPreferenceRepository(connPool))
+	preferenceRestHandlers := shared.NewPreferenceRestHandlers(config, preferenceService)
+	maintenanceRestHandlers := shared.NewMaintenanceRestHandlers(config, maintenanceService)
+	scanService := shared.NewScanService(config, repositoryTxer, outbox, storage, scanner)
+	scanRestHandlers := shared.NewScanRestHandlers(config, scanService)
+	brandingService := shared.NewBrandingService(repositoryTxer, storage, scanService, brandingRepository)
+	brandingRestHandlers := shared.NewBrandingRestHandlers(config, brandingService)
+	brandingWebHandlers := shared.NewBrandingWebHandlers(config, brandingService)
+	customDomainService := shared.NewCustomDomainService(config, repositoryTxer, shared.NewDbCustomDomainRepository(connPool), net.DefaultResolver, shared.NewCertificateHook(config))
+	customDomainRestHandlers := shared.NewCustomDomainRestHandlers(config, customDomainService)
+	reportCache := tracking.NewCachedActivityRepository(tracking.NewDbActivityRepository(connPool), config.ReportCacheExpiryDuration())
+	integrityRestHandlers := shared.NewIntegrityRestHandlers(config, shared.NewIntegrityService(config, repositoryTxer, tracking.NewReportInvalidatingIntegrityRepository(shared.NewDbIntegrityRepository(connPool), reportCache)))
+	backupRestHandlers := shared.NewBackupRestHandlers(config, shared.NewBackupService(config, storage, shared.NewDbBackupRepository(connPool)), scanService)
+
 	// Tracking
-	projectRepository := tracking.NewDbProjectRepository(connPool)
-	projectService := tracking.NewProjectService(repositoryTxer, projectRepository)
-	projectRestHandlers := tracking.NewProjectController(&config, projectRepository, projectService)
-	projectWebHandlers := tracking.NewProjectWebHandlers(&config, projectService, projectRepository)
+	projectRepository := tracking.NewEventPublishingProjectRepository(
+		tracking.NewAuditedProjectRepository(
+			tracking.NewReportInvalidatingProjectRepository(tracking.NewDbProjectRepository(connPool), reportCache),
+			auditRepository,
+		),
+		outbox,
+	)
+	planService := shared.NewPlanService(config, shared.NewDbPlanRepository(connPool))
+	billingService := shared.NewBillingService(config, repositoryTxer, shared.NewDbSubscriptionRepository(connPool), planService, lifecycleService, shared.NewStripeClient(config))
+	billingRestHandlers := shared.NewBillingRestHandlers(config, billingService)
+	projectService := tracking.NewProjectService(repositoryTxer, projectRepository, planService)
+	projectRestHandlers := tracking.NewProjectController(config, projectRepository, projectService)
+	projectWebHandlers := tracking.NewProjectWebHandlers(config, projectService, projectRepository)
+	projectTemplateRestHandlers := tracking.NewProjectTemplateRestHandlers(config, tracking.NewProjectTemplateService(repositoryTxer, tracking.NewDbProjectTemplateRepository(connPool), projectService))
 
-	activityRepository := tracking.NewDbActivityRepository(connPool)
-	activityService := tracking.NewActitivityService(repositoryTxer, activityRepository)
-	activityRestHandlers := tracking.NewActivityRestHandlers(&config, activityService, activityRepository)
-	activityWebHandlers := tracking.NewActivityWebHandlers(&config, activityService, activityRepository, projectRepository)
+	activityRepository := tracking.NewEventPublishingActivityRepository(
+		tracking.NewAuditedActivityRepository(reportCache, auditRepository),
+		outbox,
+	)
+	projectAssignmentRepository := tracking.NewDbProjectAssignmentRepository(connPool)
+	compliancePolicyRepository := tracking.NewDbCompliancePolicyRepository(connPool)
+	breakPolicyRepository := tracking.NewDbBreakPolicyRepository(connPool)
+	activityLinkRepository := tracking.NewDbActivityLinkRepository(connPool)
+	categorizationRuleRepository := tracking.NewDbCategorizationRuleRepository(connPool)
+	activityService := tracking.NewActitivityService(repositoryTxer, activityRepository, tracking.NewDbLocationPolicyRepository(connPool), projectAssignmentRepository, compliancePolicyRepository, breakPolicyRepository, activityLinkRepository, categorizationRuleRepository)
+	projectAssignmentService := tracking.NewProjectAssignmentService(repositoryTxer, projectAssignmentRepository, projectRepository)
+	projectAssignmentRestHandlers := tracking.NewProjectAssignmentRestHandlers(config, projectAssignmentService)
+	categorizationRuleService := tracking.NewCategorizationRuleService(repositoryTxer, categorizationRuleRepository, projectRepository)
+	categorizationRestHandlers := tracking.NewCategorizationRestHandlers(config, categorizationRuleService)
+	locationRestHandlers := tracking.NewLocationRestHandlers(config, activityService)
+	complianceRestHandlers := tracking.NewComplianceRestHandlers(config, activityService)
+	breakRestHandlers := tracking.NewBreakRestHandlers(config, activityService)
+	suggestionRestHandlers := tracking.NewSuggestionRestHandlers(config, activityService)
+	goalRepository := tracking.NewDbGoalRepository(connPool)
+	undoService := tracking.NewUndoService(config, repositoryTxer, tracking.NewDbUndoRepository(connPool), activityRepository, goalRepository, activityLinkRepository, projectRepository)
+	undoRestHandlers := tracking.NewUndoRestHandlers(config, undoService)
+	historyRestHandlers := tracking.NewHistoryRestHandlers(config, tracking.NewHistoryService(auditRepository, activityRepository))
+	activityRestHandlers := tracking.NewActivityRestHandlers(config, activityService, undoService, activityRepository, projectRepository)
+	quickAddService := tracking.NewQuickAddService(activityService, projectRepository)
+	quickAddRestHandlers := tracking.NewQuickAddRestHandlers(config, quickAddService)
+	projectBadgeService := tracking.NewProjectBadgeService(repositoryTxer, tracking.NewDbProjectBadgeRepository(connPool, encrypter), projectRepository, activityRepository)
+	projectBadgeRestHandlers := tracking.NewProjectBadgeRestHandlers(config, projectBadgeService)
+	activityWebHandlers := tracking.NewActivityWebHandlers(config, activityService, activityRepository, projectRepository)
+
+	reportRestHandlers := tracking.NewReportRestHandlers(config, activityService, projectRepository)
+	fixedPriceRepository := tracking.NewDbFixedPriceRepository(connPool)
+	fixedPriceRestHandlers := tracking.NewFixedPriceRestHandlers(config, tracking.NewFixedPriceService(repositoryTxer, fixedPriceRepository, projectRepository))
+	profitabilityRestHandlers := tracking.NewProfitabilityRestHandlers(config, tracking.NewProfitabilityService(repositoryTxer, tracking.NewDbCostRateRepository(connPool), fixedPriceRepository))
+	reportWebHandlers := tracking.NewReportWebHandlers(config, activityService)
+
+	clientRepository := tracking.NewDbClientRepository(connPool)
+	clientService := tracking.NewClientService(repositoryTxer, brandingService, clientRepository, projectRepository)
+	clientRestHandlers := tracking.NewClientRestHandlers(config, clientService)
+	retainerService := tracking.NewRetainerService(config, repositoryTxer, outbox, jobService, tracking.NewDbRetainerRepository(connPool), clientRepository)
+	retainerRestHandlers := tracking.NewRetainerRestHandlers(config, retainerService)
+	accountingService := tracking.NewAccountingService(repositoryTxer, tracking.NewDbAccountingRepository(connPool), tracking.NewDbEInvoiceRepository(connPool), tracking.NewDbTaxRepository(connPool), clientRepository)
+	accountingRestHandlers := tracking.NewAccountingRestHandlers(config, accountingService)
+	invoiceService := tracking.NewInvoiceService(config, repositoryTxer, outbox, jobService, tracking.NewDbInvoiceRepository(connPool), clientRepository, accountingService)
+	invoiceRestHandlers := tracking.NewInvoiceRestHandlers(config, invoiceService)
+	clientPortalService := tracking.NewClientPortalService(repositoryTxer, clientRepository, tracking.NewDbClientPortalRepository(connPool), passwordHasher)
+	clientPortalRestHandlers := tracking.NewClientPortalRestHandlers(config, clientPortalService, clientService)
+
+	exportJobRepository := tracking.NewDbExportJobRepository(connPool)
+	exportService := tracking.NewExportService(config, repositoryTxer, outbox, jobService, storage, exportJobRepository, activityRepository, activityService)
+	exportRestHandlers := tracking.NewExportRestHandlers(config, exportService)
 
-	reportWebHandlers := tracking.NewReportWebHandlers(&config, activityService)
+	digestService := tracking.NewDigestService(config, repositoryTxer, outbox, jobService, tracking.NewDbDigestRepository(connPool), activityRepository)
+	digestRestHandlers := tracking.NewDigestRestHandlers(config, digestService)
+	managerDigestService := tracking.NewManagerDigestService(config, repositoryTxer, outbox, jobService, tracking.NewDbManagerDigestRepository(connPool), activityRepository, compliancePolicyRepository)
+	managerDigestRestHandlers := tracking.NewManagerDigestRestHandlers(config, managerDigestService)
+	workingTimeService := tracking.NewWorkingTimeService(config, repositoryTxer, outbox, jobService, tracking.NewDbWorkingTimeRepository(connPool), activityRepository, breakPolicyRepository)
+	workingTimeRestHandlers := tracking.NewWorkingTimeRestHandlers(config, workingTimeService)
+	attendanceService := tracking.NewAttendanceService(repositoryTxer, featureService, tracking.NewDbAttendanceRepository(connPool))
+	attendanceRestHandlers := tracking.NewAttendanceRestHandlers(config, attendanceService)
+	scanTagService := tracking.NewScanTagService(repositoryTxer, tracking.NewDbScanTagRepository(connPool), projectRepository, activityService)
+	scanTagRestHandlers := tracking.NewScanTagRestHandlers(config, scanTagService)
+	kioskRestHandlers := tracking.NewKioskRestHandlers(config, tracking.NewKioskService(repositoryTxer, tracking.NewDbKioskRepository(connPool), attendanceService, scanTagService))
+	syncRepository := tracking.NewDbSyncRepository(connPool)
+	syncService := tracking.NewSyncService(repositoryTxer, syncRepository, activityService)
+	dashboardService := tracking.NewDashboardService(config, repositoryTxer, tracking.NewDbDashboardRepository(connPool), activityRepository, projectRepository, syncRepository)
+	dashboardRestHandlers := tracking.NewDashboardRestHandlers(config, dashboardService)
+	dashboardWebHandlers := tracking.NewDashboardWebHandlers(config, dashboardService)
+	syncRestHandlers := tracking.NewSyncRestHandlers(config, syncService)
+	extensionRestHandlers := tracking.NewExtensionRestHandlers(config, tracking.NewExtensionService(syncService, activityService, projectRepository))
+	incomingWebhookRestHandlers := tracking.NewIncomingWebhookRestHandlers(config, tracking.NewIncomingWebhookService(repositoryTxer, tracking.NewDbIncomingWebhookRepository(connPool), activityService, projectService, projectRepository))
+	smsRestHandlers := tracking.NewSMSRestHandlers(config, tracking.NewSMSService(config, repositoryTxer, tracking.NewDbSMSRepository(connPool), quickAddService))
+	receiptRestHandlers := tracking.NewReceiptRestHandlers(config, tracking.NewReceiptService(scanService, tracking.NewTextRecognizer(config)))
+	screenshotService := tracking.NewScreenshotService(repositoryTxer, jobService, storage, scanService, auditService, tracking.NewDbScreenshotRepository(connPool), activityRepository)
+	screenshotRestHandlers := tracking.NewScreenshotRestHandlers(config, screenshotService)
+	activityLinkService := tracking.NewActivityLinkService(repositoryTxer, jobService, outbox, shared.NewLinkUnfurler(config), activityLinkRepository, activityRepository)
+	activityLinkRestHandlers := tracking.NewActivityLinkRestHandlers(config, activityLinkService)
+	goalRestHandlers := tracking.NewGoalRestHandlers(config, tracking.NewGoalService(repositoryTxer, goalRepository, activityRepository))
+	absenceRepository := tracking.NewDbAbsenceRepository(connPool, encrypter)
+	absenceRestHandlers := tracking.NewAbsenceRestHandlers(config, tracking.NewAbsenceService(repositoryTxer, absenceRepository))
+	availabilityRestHandlers := tracking.NewAvailabilityRestHandlers(config, tracking.NewAvailabilityService(config, repositoryTxer, tracking.NewDbAllocationRepository(connPool), absenceRepository, projectRepository))
+	emailInRestHandlers := tracking.NewEmailInRestHandlers(config, tracking.NewEmailInService(config, repositoryTxer, outbox, tracking.NewDbEmailInRepository(connPool, encrypter), quickAddService, scanService))
 
 	// User
 	userRepository := user.NewDbUserRepository(connPool)
 	organizationRepository := user.NewDbOrganizationRepository(connPool)
-	userService := user.NewUserService(&config, repositoryTxer, mailResource, userRepository, organizationRepository, projectService.OrganizationInitializer())
-	userWeb := user.NewUserWeb(&config, userService, userRepository)
+	captchaVerifier, err := shared.NewCaptchaVerifier(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	signupCaptchaGuard := shared.NewCaptchaGuard(captchaVerifier, config.CaptchaThreshold, config.CaptchaWindowDuration())
+	loginCaptchaGuard := shared.NewCaptchaGuard(captchaVerifier, config.CaptchaThreshold, config.CaptchaWindowDuration())
+
+	userService := user.NewUserService(config, repositoryTxer, outbox, userRepository, organizationRepository, passwordHasher, initializeOrganization(projectService.OrganizationInitializer(), lifecycleService.OrganizationInitializer()))
+	userWeb := user.NewUserWeb(config, userService, userRepository, signupCaptchaGuard)
 
 	// Auth
 	tokenAuth := jwtauth.New("HS256", []byte(config.JWTSecret), nil)
-	authService := auth.NewAuthService(&config, userRepository)
-	authController := auth.NewAuthRestHandlers(&config, authService, tokenAuth)
-	authWeb := auth.NewAuthWebHandlers(&config, authService, userService, tokenAuth)
+	loginAlertService := auth.NewLoginAlertService(config, repositoryTxer, outbox, auth.NewDbLoginAlertRepository(connPool))
+	loginAlertRestHandlers := auth.NewLoginAlertRestHandlers(config, loginAlertService)
+	ssoPolicyRepository := auth.NewDbSSOPolicyRepository(connPool)
+	ssoPolicyRestHandlers := auth.NewSSOPolicyRestHandlers(config, auth.NewSSOPolicyService(config, repositoryTxer, ssoPolicyRepository))
+	authService := auth.NewAuthService(config, repositoryTxer, userRepository, passwordHasher, loginAlertService, ssoPolicyRepository)
+	serviceAccountRestHandlers := auth.NewServiceAccountRestHandlers(config, auth.NewServiceAccountService(repositoryTxer, auth.NewDbServiceAccountRepository(connPool)))
+	authController := auth.NewAuthRestHandlers(config, authService, tokenAuth, loginCaptchaGuard)
+	authWeb := auth.NewAuthWebHandlers(config, authService, userService, tokenAuth, loginCaptchaGuard)
+	impersonationRestHandlers := auth.NewImpersonationRestHandlers(config, authService, auth.NewImpersonationService(config, authService, featureService, auditService), tokenAuth)
 
 	apiHandlers := []shared.DomainHandler{
 		authController,
+		impersonationRestHandlers,
+		loginAlertRestHandlers,
+		ssoPolicyRestHandlers,
+		serviceAccountRestHandlers,
+		preferenceRestHandlers,
+		brandingRestHandlers,
+		customDomainRestHandlers,
 		activityRestHandlers,
+		undoRestHandlers,
+		dashboardRestHandlers,
+		historyRestHandlers,
+		quickAddRestHandlers,
+		projectBadgeRestHandlers,
 		projectRestHandlers,
+		projectTemplateRestHandlers,
+		reportRestHandlers,
+		profitabilityRestHandlers,
+		fixedPriceRestHandlers,
+		retainerRestHandlers,
+		accountingRestHandlers,
+		invoiceRestHandlers,
+		exportRestHandlers,
+		digestRestHandlers,
+		managerDigestRestHandlers,
+		workingTimeRestHandlers,
+		locationRestHandlers,
+		complianceRestHandlers,
+		breakRestHandlers,
+		suggestionRestHandlers,
+		attendanceRestHandlers,
+		absenceRestHandlers,
+		availabilityRestHandlers,
+		kioskRestHandlers,
+		scanTagRestHandlers,
+		syncRestHandlers,
+		extensionRestHandlers,
+		emailInRestHandlers,
+		receiptRestHandlers,
+		screenshotRestHandlers,
+		activityLinkRestHandlers,
+		goalRestHandlers,
+		smsRestHandlers,
+		incomingWebhookRestHandlers,
+		clientRestHandlers,
+		clientPortalRestHandlers,
+		projectAssignmentRestHandlers,
+		categorizationRestHandlers,
+		jobRestHandlers,
+		configRestHandlers,
+		featureRestHandlers,
+		billingRestHandlers,
+		lifecycleRestHandlers,
+		apiUsageRestHandlers,
+		auditRestHandlers,
+		instanceRestHandlers,
+		maintenanceRestHandlers,
+		backupRestHandlers,
+		integrityRestHandlers,
+		scanRestHandlers,
 	}
 	webHandlers := []shared.DomainHandler{
 		userWeb,
@@ -103,13 +515,32 @@ func newApp() (*shared.Config, *pgxpool.Pool, *chi.Mux, error) {
 		authWeb,
 		projectWebHandlers,
 		reportWebHandlers,
+		dashboardWebHandlers,
+		brandingWebHandlers,
 	}
 
+	go jobService.Run(context.Background())
+	go apiUsageService.Run(context.Background())
+
 	router := chi.NewRouter()
-	registerRoutes(&config, router, authController, authWeb, apiHandlers, webHandlers)
-	registerHealthcheck(&config, router)
+	registerRoutes(config, router, planService, lifecycleService, apiUsageService, auditService, maintenanceService, preferenceService, brandingService, customDomainService, authController, serviceAccountRestHandlers, authWeb, apiHandlers, webHandlers)
+	registerHealthcheck(config, router)
+	router.Get("/metrics", shared.HandlePoolMetrics(connPool))
+
+	return config, connPool, router, nil
+}
 
-	return &config, connPool, router, nil
+// initializeOrganization initializes new organizations with all initializers
+func initializeOrganization(initializers ...func(ctxWithTx context.Context, organizationID uuid.UUID) error) func(ctxWithTx context.Context, organizationID uuid.UUID) error {
+	return func(ctxWithTx context.Context, organizationID uuid.UUID) error {
+		for _, initializer := range initializers {
+			err := initializer(ctxWithTx, organizationID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 }
 
 func registerHealthcheck(config *shared.Config, router *chi.Mux) {
@@ -126,25 +557,42 @@ func registerHealthcheck(config *shared.Config, router *chi.Mux) {
 	router.Get("/health", h.HandlerFunc)
 }
 
-func registerRoutes(config *shared.Config, router *chi.Mux, authController *auth.AuthRestHandlers, authWeb *auth.AuthWebHandlers, apiHandlers []shared.DomainHandler, webHandlers []shared.DomainHandler) {
+func registerRoutes(config *shared.Config, router *chi.Mux, planService *shared.PlanService, lifecycleService *shared.LifecycleService, apiUsageService *shared.APIUsageService, auditService *shared.AuditService, maintenanceService *shared.MaintenanceService, preferenceService *shared.PreferenceService, brandingService *shared.BrandingService, customDomainService *shared.CustomDomainService, authController *auth.AuthRestHandlers, serviceAccountRestHandlers *auth.ServiceAccountRestHandlers, authWeb *auth.AuthWebHandlers, apiHandlers []shared.DomainHandler, webHandlers []shared.DomainHandler) {
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.Compress(5))
+	router.Use(customDomainService.CustomDomainMiddleware())
+	router.Use(maintenanceService.ReadOnlyMiddleware("/instance/maintenance", "/auth/login", "/client-portal/login", "/login"))
 
-	router.Mount("/api", apiRouteHandler(authController, apiHandlers))
-	registerWebRoutes(config, router, authController, authWeb, webHandlers)
+	// the unversioned api is kept for existing clients until they moved to a versioned api
+	router.Mount("/api", apiRouteHandler(config, planService, lifecycleService, apiUsageService, auditService, authController, serviceAccountRestHandlers, apiHandlers, shared.APIVersion1, shared.DeprecationMiddleware("/api/v1")))
+	router.Mount("/api/v1", apiRouteHandler(config, planService, lifecycleService, apiUsageService, auditService, authController, serviceAccountRestHandlers, apiHandlers, shared.APIVersion1))
+	router.Mount("/api/v2", apiRouteHandler(config, planService, lifecycleService, apiUsageService, auditService, authController, serviceAccountRestHandlers, apiHandlers, shared.APIVersion2))
+	registerWebRoutes(config, router, lifecycleService, auditService, preferenceService, brandingService, authController, authWeb, webHandlers)
 }
 
-func apiRouteHandler(authController *auth.AuthRestHandlers, apiHandlers []shared.DomainHandler) http.Handler {
+func apiRouteHandler(config *shared.Config, planService *shared.PlanService, lifecycleService *shared.LifecycleService, apiUsageService *shared.APIUsageService, auditService *shared.AuditService, authController *auth.AuthRestHandlers, serviceAccountRestHandlers *auth.ServiceAccountRestHandlers, apiHandlers []shared.DomainHandler, version string, middlewares ...func(http.Handler) http.Handler) http.Handler {
 	r := chi.NewRouter()
+	r.Use(shared.CORSMiddleware(config))
+	r.Use(shared.APIVersionMiddleware(version))
+	r.Use(middlewares...)
 
 	for _, apiHandler := range apiHandlers {
 		apiHandler.RegisterOpen(r)
 	}
 
 	r.Group(func(r chi.Router) {
+		r.Use(shared.CSRFSessionMiddleware(config))
 		r.Use(authController.JWTVerifier())
+		r.Use(serviceAccountRestHandlers.ServiceAccountMiddleware())
 		r.Use(authController.JWTPrincipalMiddleware())
+		r.Use(apiUsageService.UsageMiddleware())
+		r.Use(auditService.RequestAuditMiddleware())
+		r.Use(lifecycleService.DisabledMiddleware())
+		r.Use(shared.ClientPortalMiddleware(config))
+		r.Use(shared.ServiceAccountScopeMiddleware(config))
+		r.Use(planService.RateLimitMiddleware())
+		r.Use(lifecycleService.ReadOnlyMiddleware("/billing/checkout"))
 
 		for _, apiHandler := range apiHandlers {
 			apiHandler.RegisterProtected(r)
@@ -154,38 +602,25 @@ func apiRouteHandler(authController *auth.AuthRestHandlers, apiHandlers []shared
 	return r
 }
 
-func registerWebRoutes(config *shared.Config, router *chi.Mux, authController *auth.AuthRestHandlers, authWeb *auth.AuthWebHandlers, webHandlers []shared.DomainHandler) {
+func registerWebRoutes(config *shared.Config, router *chi.Mux, lifecycleService *shared.LifecycleService, auditService *shared.AuditService, preferenceService *shared.PreferenceService, brandingService *shared.BrandingService, authController *auth.AuthRestHandlers, authWeb *auth.AuthWebHandlers, webHandlers []shared.DomainHandler) {
 	assetsDir, _ := fs.Sub(assets, "shared")
 	router.Mount("/assets/", etag.Handler(http.FileServer(http.FS(assetsDir)), true))
 	router.Get("/manifest.webmanifest", shared.HandleWebManifest())
 
-	secureMiddleware := secure.New(secure.Options{
-		HostsProxyHeaders:     []string{"X-Forwarded-Host"},
-		SSLProxyHeaders:       map[string]string{"X-Forwarded-Proto": "https"},
-		ForceSTSHeader:        true,
-		IsDevelopment:         !config.IsProduction(),
-		STSSeconds:            31536000,
-		STSIncludeSubdomains:  true,
-		STSPreload:            true,
-		FrameDeny:             true,
-		ContentTypeNosniff:    true,
-		BrowserXssFilter:      true,
-		ContentSecurityPolicy: "base-uri 'self'; object-src 'none';",
-		ReferrerPolicy:        "same-origin",
-		PermissionsPolicy:     "fullscreen=*",
-	})
-
-	cookieName := "__Secure-csrf"
-	if !config.IsProduction() {
-		cookieName = "__Insecure-csrf"
-	}
+	secureMiddleware := shared.SecurityHeadersMiddleware(config)
 
-	CSRF := csrf.Protect([]byte(config.CSRFSecret), csrf.CookieName(cookieName), csrf.FieldName("CSRFToken"), csrf.Secure(config.IsProduction()))
+	CSRF := shared.CSRFMiddleware(config)
 	router.Group(func(r chi.Router) {
 		r.Use(authWeb.WebVerifier())
 		r.Use(authController.JWTPrincipalMiddleware())
+		r.Use(auditService.RequestAuditMiddleware())
+		r.Use(lifecycleService.DisabledMiddleware())
+		r.Use(shared.ClientPortalMiddleware(config))
 		r.Use(CSRF)
-		r.Use(secureMiddleware.Handler)
+		r.Use(secureMiddleware)
+		r.Use(lifecycleService.ReadOnlyMiddleware())
+		r.Use(preferenceService.AppearanceMiddleware())
+		r.Use(brandingService.BrandingMiddleware())
 
 		for _, apiHandler := range webHandlers {
 			apiHandler.RegisterProtected(r)
@@ -194,7 +629,8 @@ func registerWebRoutes(config *shared.Config, router *chi.Mux, authController *a
 
 	router.Group(func(r chi.Router) {
 		r.Use(CSRF)
-		r.Use(secureMiddleware.Handler)
+		r.Use(secureMiddleware)
+		r.Use(brandingService.BrandingMiddleware())
 
 		for _, apiHandler := range webHandlers {
 			apiHandler.RegisterOpen(r)
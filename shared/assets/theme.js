@@ -0,0 +1,8 @@
+(function() {
+    var media = window.matchMedia('(prefers-color-scheme: dark)');
+    var applyTheme = function () {
+        document.documentElement.setAttribute('data-bs-theme', media.matches ? 'dark' : 'light');
+    };
+    applyTheme();
+    media.addEventListener('change', applyTheme);
+})();
@@ -0,0 +1,111 @@
+package shared
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionImpersonationStarted = "impersonation:started"
+	AuditActionImpersonationStopped = "impersonation:stopped"
+	AuditActionRequest              = "request"
+
+	// auditMaxPathLength is the maximum length of the path of an audited request
+	auditMaxPathLength = 1000
+
+	// auditMaxValueLength is the maximum length of the old and new value of a changed field
+	auditMaxValueLength = 4000
+)
+
+// AuditEntry is an entry in the audit log of an organization, entries of support sessions are
+// marked with the instance admin acting as the user and entries of integrations with the name of
+// their service account. Entries about the change of an entity carry the changed fields.
+type AuditEntry struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Username       string
+	ImpersonatedBy string
+	ServiceAccount string
+	Action         string
+	Method         string
+	Path           string
+	Status         int
+	EntityType     string
+	EntityID       uuid.UUID
+	Changes        []*AuditChange
+	CreatedAt      time.Time
+}
+
+// AuditChange is the old and new value of a changed field of an entity
+type AuditChange struct {
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+type AuditEntriesPaged struct {
+	AuditEntries []*AuditEntry
+	Page         *paged.Page
+}
+
+type AuditRepository interface {
+	FindAuditEntries(ctx context.Context, organizationID uuid.UUID, pageParams *paged.PageParams) (*AuditEntriesPaged, error)
+	FindAuditEntriesOfEntity(ctx context.Context, organizationID uuid.UUID, entityType string, entityID uuid.UUID) ([]*AuditEntry, error)
+	InsertAuditEntry(ctx context.Context, auditEntry *AuditEntry) error
+}
+
+// NewEntityAuditEntry is the entry about the change of an entity by the principal, the changes are
+// the differences of the fields before and after, empty fields before a creation or after a deletion
+func NewEntityAuditEntry(principal *Principal, action, entityType string, entityID uuid.UUID, before, after map[string]string, now time.Time) *AuditEntry {
+	return &AuditEntry{
+		ID:             uuid.New(),
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		ImpersonatedBy: principal.ImpersonatedBy,
+		ServiceAccount: ServiceAccountOf(principal),
+		Action:         action,
+		EntityType:     entityType,
+		EntityID:       entityID,
+		Changes:        AuditChangesOf(before, after),
+		CreatedAt:      now,
+	}
+}
+
+// ServiceAccountOf is the name of the service account of the principal, empty for users
+func ServiceAccountOf(principal *Principal) string {
+	if !principal.IsServiceAccount() {
+		return ""
+	}
+	return principal.Name
+}
+
+// AuditChangesOf are the fields with different values before and after, sorted by field
+func AuditChangesOf(before, after map[string]string) []*AuditChange {
+	fields := make(map[string]bool)
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	var changes []*AuditChange
+	for field := range fields {
+		if before[field] == after[field] {
+			continue
+		}
+		changes = append(changes, &AuditChange{
+			Field:    field,
+			OldValue: before[field],
+			NewValue: after[field],
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
@@ -0,0 +1,49 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestAuditChangesOf(t *testing.T) {
+	is := is.New(t)
+
+	changes := AuditChangesOf(
+		map[string]string{"description": "Old", "end": "11:00", "start": "10:00"},
+		map[string]string{"description": "New", "end": "12:00", "start": "10:00"},
+	)
+	is.Equal(len(changes), 2)
+	is.Equal(*changes[0], AuditChange{Field: "description", OldValue: "Old", NewValue: "New"})
+	is.Equal(*changes[1], AuditChange{Field: "end", OldValue: "11:00", NewValue: "12:00"})
+
+	changes = AuditChangesOf(nil, map[string]string{"description": "New"})
+	is.Equal(len(changes), 1)
+	is.Equal(changes[0].OldValue, "")
+
+	is.Equal(len(AuditChangesOf(map[string]string{"start": "10:00"}, map[string]string{"start": "10:00"})), 0)
+}
+
+func TestNewEntityAuditEntry(t *testing.T) {
+	is := is.New(t)
+
+	entityID := uuid.New()
+	now := time.Now()
+	auditEntry := NewEntityAuditEntry(
+		&Principal{OrganizationID: OrganizationIDSample, Username: "user1", ImpersonatedBy: "ops@baralga.com"},
+		"activity:deleted",
+		"activity",
+		entityID,
+		map[string]string{"description": "Old"},
+		nil,
+		now,
+	)
+	is.Equal(auditEntry.Username, "user1")
+	is.Equal(auditEntry.ImpersonatedBy, "ops@baralga.com")
+	is.Equal(auditEntry.EntityID, entityID)
+	is.Equal(auditEntry.CreatedAt, now)
+	is.Equal(len(auditEntry.Changes), 1)
+	is.Equal(auditEntry.Changes[0].NewValue, "")
+}
@@ -0,0 +1,227 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DbAuditRepository is a SQL database repository for the audit log
+type DbAuditRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ AuditRepository = (*DbAuditRepository)(nil)
+
+type auditEntryRow struct {
+	ID             uuid.UUID      `db:"audit_id"`
+	OrganizationID uuid.UUID      `db:"org_id"`
+	Username       string         `db:"username"`
+	ImpersonatedBy sql.NullString `db:"impersonated_by"`
+	ServiceAccount sql.NullString `db:"service_account"`
+	Action         string         `db:"action"`
+	Method         string         `db:"method"`
+	Path           string         `db:"path"`
+	Status         int            `db:"status"`
+	EntityType     sql.NullString `db:"entity_type"`
+	EntityID       *uuid.UUID     `db:"entity_id"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+type auditChangeRow struct {
+	ID       uuid.UUID `db:"audit_id"`
+	Position int       `db:"position"`
+	Field    string    `db:"field"`
+	OldValue string    `db:"old_value"`
+	NewValue string    `db:"new_value"`
+}
+
+// NewDbAuditRepository creates a new SQL database repository for the audit log
+func NewDbAuditRepository(connPool *pgxpool.Pool) *DbAuditRepository {
+	return &DbAuditRepository{
+		connPool: connPool,
+	}
+}
+
+// FindAuditEntries reads the audit log of the organization, latest entries first
+func (r *DbAuditRepository) FindAuditEntries(ctx context.Context, organizationID uuid.UUID, pageParams *paged.PageParams) (*AuditEntriesPaged, error) {
+	var rows []*auditEntryRow
+	var total int
+	err := InSnapshot(ctx, r.connPool, func(tx pgx.Tx) error {
+		var err error
+		rows, err = SelectAll[auditEntryRow](
+			ctx,
+			tx,
+			`SELECT `+Columns[auditEntryRow]()+` 
+			 FROM audit_log 
+			 WHERE org_id = $1 
+			 ORDER BY created_at DESC 
+			 LIMIT $2 OFFSET $3`,
+			organizationID, pageParams.Size, pageParams.Offset(),
+		)
+		if err != nil {
+			return err
+		}
+
+		row := tx.QueryRow(
+			ctx,
+			`SELECT count(*) as total 
+			 FROM audit_log 
+			 WHERE org_id = $1`,
+			organizationID,
+		)
+		return row.Scan(&total)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	auditEntries := make([]*AuditEntry, len(rows))
+	for i, row := range rows {
+		auditEntries[i] = row.toAuditEntry()
+	}
+
+	return &AuditEntriesPaged{
+		AuditEntries: auditEntries,
+		Page:         pageParams.PageOfTotal(total),
+	}, nil
+}
+
+// FindAuditEntriesOfEntity reads the entries about the changes of the entity with their changed fields, oldest entries first
+func (r *DbAuditRepository) FindAuditEntriesOfEntity(ctx context.Context, organizationID uuid.UUID, entityType string, entityID uuid.UUID) ([]*AuditEntry, error) {
+	var rows []*auditEntryRow
+	var changeRows []*auditChangeRow
+	err := InSnapshot(ctx, r.connPool, func(tx pgx.Tx) error {
+		var err error
+		rows, err = SelectAll[auditEntryRow](
+			ctx,
+			tx,
+			`SELECT `+Columns[auditEntryRow]()+` 
+			 FROM audit_log 
+			 WHERE org_id = $1 AND entity_type = $2 AND entity_id = $3 
+			 ORDER BY created_at ASC`,
+			organizationID, entityType, entityID,
+		)
+		if err != nil {
+			return err
+		}
+
+		changeRows, err = SelectAll[auditChangeRow](
+			ctx,
+			tx,
+			`SELECT c.audit_id, c.position, c.field, c.old_value, c.new_value 
+			 FROM audit_changes c 
+			 JOIN audit_log a ON a.audit_id = c.audit_id 
+			 WHERE a.org_id = $1 AND a.entity_type = $2 AND a.entity_id = $3 
+			 ORDER BY c.audit_id, c.position`,
+			organizationID, entityType, entityID,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changesByAuditID := make(map[uuid.UUID][]*AuditChange)
+	for _, changeRow := range changeRows {
+		changesByAuditID[changeRow.ID] = append(changesByAuditID[changeRow.ID], &AuditChange{
+			Field:    changeRow.Field,
+			OldValue: changeRow.OldValue,
+			NewValue: changeRow.NewValue,
+		})
+	}
+
+	auditEntries := make([]*AuditEntry, len(rows))
+	for i, row := range rows {
+		auditEntries[i] = row.toAuditEntry()
+		auditEntries[i].Changes = changesByAuditID[row.ID]
+	}
+
+	return auditEntries, nil
+}
+
+// InsertAuditEntry adds the entry with its changed fields to the audit log
+func (r *DbAuditRepository) InsertAuditEntry(ctx context.Context, auditEntry *AuditEntry) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	var entityID *uuid.UUID
+	if auditEntry.EntityID != uuid.Nil {
+		entityID = &auditEntry.EntityID
+	}
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO audit_log 
+		   (audit_id, org_id, username, impersonated_by, service_account, action, method, path, status, entity_type, entity_id, created_at) 
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		auditEntry.ID,
+		auditEntry.OrganizationID,
+		auditEntry.Username,
+		sql.NullString{String: auditEntry.ImpersonatedBy, Valid: auditEntry.ImpersonatedBy != ""},
+		sql.NullString{String: auditEntry.ServiceAccount, Valid: auditEntry.ServiceAccount != ""},
+		auditEntry.Action,
+		auditEntry.Method,
+		auditEntry.Path,
+		auditEntry.Status,
+		sql.NullString{String: auditEntry.EntityType, Valid: auditEntry.EntityType != ""},
+		entityID,
+		auditEntry.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, change := range auditEntry.Changes {
+		_, err := tx.Exec(
+			ctx,
+			`INSERT INTO audit_changes 
+			   (audit_id, org_id, position, field, old_value, new_value) 
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			auditEntry.ID,
+			auditEntry.OrganizationID,
+			i,
+			change.Field,
+			truncateAuditValue(change.OldValue),
+			truncateAuditValue(change.NewValue),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func truncateAuditValue(value string) string {
+	if len(value) > auditMaxValueLength {
+		return value[:auditMaxValueLength]
+	}
+	return value
+}
+
+func (r *auditEntryRow) toAuditEntry() *AuditEntry {
+	var entityID uuid.UUID
+	if r.EntityID != nil {
+		entityID = *r.EntityID
+	}
+
+	return &AuditEntry{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		ImpersonatedBy: r.ImpersonatedBy.String,
+		ServiceAccount: r.ServiceAccount.String,
+		Action:         r.Action,
+		Method:         r.Method,
+		Path:           r.Path,
+		Status:         r.Status,
+		EntityType:     r.EntityType.String,
+		EntityID:       entityID,
+		CreatedAt:      r.CreatedAt,
+	}
+}
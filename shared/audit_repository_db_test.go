@@ -0,0 +1,97 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestAuditRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	auditRepository := NewDbAuditRepository(connPool)
+	repositoryTxer := NewDbRepositoryTxer(connPool)
+
+	t.Run("InsertAuditEntry", func(t *testing.T) {
+		for _, impersonatedBy := range []string{"", "ops@baralga.com"} {
+			auditEntry := &AuditEntry{
+				ID:             uuid.New(),
+				OrganizationID: OrganizationIDSample,
+				Username:       "user1",
+				ImpersonatedBy: impersonatedBy,
+				Action:         AuditActionRequest,
+				Method:         "GET",
+				Path:           "/api/activities",
+				Status:         200,
+				CreatedAt:      time.Now(),
+			}
+			err := repositoryTxer.InTx(
+				context.Background(),
+				func(ctx context.Context) error {
+					return auditRepository.InsertAuditEntry(ctx, auditEntry)
+				},
+			)
+			is.NoErr(err)
+		}
+
+		auditEntriesPaged, err := auditRepository.FindAuditEntries(context.Background(), OrganizationIDSample, &paged.PageParams{Size: 1})
+		is.NoErr(err)
+		is.Equal(len(auditEntriesPaged.AuditEntries), 1)
+		is.Equal(auditEntriesPaged.Page.TotalElements, 2)
+		is.Equal(auditEntriesPaged.AuditEntries[0].ImpersonatedBy, "ops@baralga.com")
+	})
+	t.Run("FindAuditEntriesOfEntity", func(t *testing.T) {
+		entityID := uuid.New()
+		auditEntry := &AuditEntry{
+			ID:             uuid.New(),
+			OrganizationID: OrganizationIDSample,
+			Username:       "user1",
+			Action:         "activity:updated",
+			EntityType:     "activity",
+			EntityID:       entityID,
+			Changes: []*AuditChange{
+				{Field: "description", OldValue: "Old", NewValue: "New"},
+				{Field: "end", OldValue: "2021-11-05T11:00:00Z", NewValue: "2021-11-05T12:00:00Z"},
+			},
+			CreatedAt: time.Now(),
+		}
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return auditRepository.InsertAuditEntry(ctx, auditEntry)
+			},
+		)
+		is.NoErr(err)
+
+		auditEntries, err := auditRepository.FindAuditEntriesOfEntity(context.Background(), OrganizationIDSample, "activity", entityID)
+		is.NoErr(err)
+		is.Equal(len(auditEntries), 1)
+		is.Equal(auditEntries[0].EntityID, entityID)
+		is.Equal(len(auditEntries[0].Changes), 2)
+		is.Equal(auditEntries[0].Changes[0].Field, "description")
+		is.Equal(auditEntries[0].Changes[1].NewValue, "2021-11-05T12:00:00Z")
+	})
+}
@@ -0,0 +1,69 @@
+package shared
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+)
+
+type InMemAuditRepository struct {
+	mu           sync.Mutex
+	auditEntries []*AuditEntry
+}
+
+var _ AuditRepository = (*InMemAuditRepository)(nil)
+
+func NewInMemAuditRepository() *InMemAuditRepository {
+	return &InMemAuditRepository{}
+}
+
+func (r *InMemAuditRepository) FindAuditEntries(ctx context.Context, organizationID uuid.UUID, pageParams *paged.PageParams) (*AuditEntriesPaged, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var auditEntries []*AuditEntry
+	for _, a := range r.auditEntries {
+		if a.OrganizationID == organizationID {
+			auditEntry := *a
+			auditEntries = append(auditEntries, &auditEntry)
+		}
+	}
+	sort.SliceStable(auditEntries, func(i, j int) bool {
+		return auditEntries[i].CreatedAt.After(auditEntries[j].CreatedAt)
+	})
+
+	return &AuditEntriesPaged{
+		AuditEntries: auditEntries,
+		Page:         pageParams.PageOfTotal(len(auditEntries)),
+	}, nil
+}
+
+func (r *InMemAuditRepository) FindAuditEntriesOfEntity(ctx context.Context, organizationID uuid.UUID, entityType string, entityID uuid.UUID) ([]*AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var auditEntries []*AuditEntry
+	for _, a := range r.auditEntries {
+		if a.OrganizationID == organizationID && a.EntityType == entityType && a.EntityID == entityID {
+			auditEntry := *a
+			auditEntries = append(auditEntries, &auditEntry)
+		}
+	}
+	sort.SliceStable(auditEntries, func(i, j int) bool {
+		return auditEntries[i].CreatedAt.Before(auditEntries[j].CreatedAt)
+	})
+
+	return auditEntries, nil
+}
+
+func (r *InMemAuditRepository) InsertAuditEntry(ctx context.Context, auditEntry *AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *auditEntry
+	r.auditEntries = append(r.auditEntries, &stored)
+	return nil
+}
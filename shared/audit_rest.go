@@ -0,0 +1,128 @@
+package shared
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/baralga/shared/paged"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type auditEntryModel struct {
+	ID             string              `json:"id"`
+	Username       string              `json:"username"`
+	ImpersonatedBy string              `json:"impersonatedBy,omitempty"`
+	ServiceAccount string              `json:"serviceAccount,omitempty"`
+	Action         string              `json:"action"`
+	Method         string              `json:"method,omitempty"`
+	Path           string              `json:"path,omitempty"`
+	Status         int                 `json:"status,omitempty"`
+	EntityType     string              `json:"entityType,omitempty"`
+	EntityID       string              `json:"entityId,omitempty"`
+	Changes        []*AuditChangeModel `json:"changes,omitempty"`
+	CreatedAt      string              `json:"createdAt"`
+}
+
+// AuditChangeModel is the old and new value of a changed field of an entity
+type AuditChangeModel struct {
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+type EmbeddedAuditEntries struct {
+	AuditEntryModels []*auditEntryModel `json:"auditEntries"`
+}
+
+type auditEntriesModel struct {
+	*EmbeddedAuditEntries `json:"_embedded"`
+	*paged.Page           `json:"page"`
+	Links                 *hal.Links `json:"_links"`
+}
+
+type AuditRestHandlers struct {
+	config       *Config
+	auditService *AuditService
+}
+
+func NewAuditRestHandlers(config *Config, auditService *AuditService) *AuditRestHandlers {
+	return &AuditRestHandlers{
+		config:       config,
+		auditService: auditService,
+	}
+}
+
+func (a *AuditRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/admin/audit-log", a.HandleGetAuditEntries())
+}
+
+func (a *AuditRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetAuditEntries reads the audit log of the organization, latest entries first
+func (a *AuditRestHandlers) HandleGetAuditEntries() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	auditService := a.auditService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+		pageParams := paged.PageParamsOf(r)
+
+		auditEntriesPaged, err := auditService.ReadAuditEntries(r.Context(), principal, pageParams)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		auditEntryModels := make([]*auditEntryModel, len(auditEntriesPaged.AuditEntries))
+		for i, auditEntry := range auditEntriesPaged.AuditEntries {
+			auditEntryModels[i] = mapToAuditEntryModel(auditEntry)
+		}
+
+		RenderJSON(w, &auditEntriesModel{
+			EmbeddedAuditEntries: &EmbeddedAuditEntries{
+				AuditEntryModels: auditEntryModels,
+			},
+			Page: auditEntriesPaged.Page,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+func mapToAuditEntryModel(auditEntry *AuditEntry) *auditEntryModel {
+	var entityID string
+	if auditEntry.EntityID != uuid.Nil {
+		entityID = auditEntry.EntityID.String()
+	}
+
+	return &auditEntryModel{
+		ID:             auditEntry.ID.String(),
+		Username:       auditEntry.Username,
+		ImpersonatedBy: auditEntry.ImpersonatedBy,
+		ServiceAccount: auditEntry.ServiceAccount,
+		Action:         auditEntry.Action,
+		Method:         auditEntry.Method,
+		Path:           auditEntry.Path,
+		Status:         auditEntry.Status,
+		EntityType:     auditEntry.EntityType,
+		EntityID:       entityID,
+		Changes:        MapToAuditChangeModels(auditEntry.Changes),
+		CreatedAt:      auditEntry.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// MapToAuditChangeModels maps the changed fields of an audit entry to their models
+func MapToAuditChangeModels(changes []*AuditChange) []*AuditChangeModel {
+	changeModels := make([]*AuditChangeModel, len(changes))
+	for i, change := range changes {
+		changeModels[i] = &AuditChangeModel{
+			Field:    change.Field,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		}
+	}
+	return changeModels
+}
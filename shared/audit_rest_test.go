@@ -0,0 +1,42 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestHandleGetAuditEntries(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	auditService := NewAuditService(NewInMemRepositoryTxer(), NewInMemAuditRepository())
+	is.NoErr(auditService.RecordAuditEntry(context.Background(), &AuditEntry{
+		OrganizationID: OrganizationIDSample,
+		Username:       "user1",
+		ImpersonatedBy: "ops@baralga.com",
+		Action:         AuditActionImpersonationStarted,
+	}))
+
+	a := NewAuditRestHandlers(&Config{}, auditService)
+
+	r, _ := http.NewRequest("GET", "/api/admin/audit-log", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleGetAuditEntries()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	auditEntriesModel := &auditEntriesModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(auditEntriesModel)
+	is.NoErr(err)
+	is.Equal(len(auditEntriesModel.AuditEntryModels), 1)
+	is.Equal(auditEntriesModel.AuditEntryModels[0].ImpersonatedBy, "ops@baralga.com")
+	is.Equal(auditEntriesModel.AuditEntryModels[0].Action, AuditActionImpersonationStarted)
+}
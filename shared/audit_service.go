@@ -0,0 +1,87 @@
+package shared
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// AuditService keeps the audit log of organizations, so organizations see what support did on their behalf
+type AuditService struct {
+	repositoryTxer  RepositoryTxer
+	auditRepository AuditRepository
+}
+
+// NewAuditService creates a new service for the audit log of organizations
+func NewAuditService(repositoryTxer RepositoryTxer, auditRepository AuditRepository) *AuditService {
+	return &AuditService{
+		repositoryTxer:  repositoryTxer,
+		auditRepository: auditRepository,
+	}
+}
+
+// RecordAuditEntry adds the entry to the audit log of the organization of the entry
+func (s *AuditService) RecordAuditEntry(ctx context.Context, auditEntry *AuditEntry) error {
+	auditEntry.ID = uuid.New()
+	auditEntry.CreatedAt = time.Now()
+	if len(auditEntry.Path) > auditMaxPathLength {
+		auditEntry.Path = auditEntry.Path[:auditMaxPathLength]
+	}
+
+	return s.repositoryTxer.InTx(
+		WithOrganizationID(ctx, auditEntry.OrganizationID),
+		func(ctx context.Context) error {
+			return s.auditRepository.InsertAuditEntry(ctx, auditEntry)
+		},
+	)
+}
+
+// RequestAuditMiddleware records every request of an instance admin acting as a user and every
+// changing request of a service account in the audit log
+func (s *AuditService) RequestAuditMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+			if !principal.IsImpersonated() && !(principal.IsServiceAccount() && isChangingRequest(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			err := s.RecordAuditEntry(context.Background(), &AuditEntry{
+				OrganizationID: principal.OrganizationID,
+				Username:       principal.Username,
+				ImpersonatedBy: principal.ImpersonatedBy,
+				ServiceAccount: ServiceAccountOf(principal),
+				Action:         AuditActionRequest,
+				Method:         r.Method,
+				Path:           r.URL.RequestURI(),
+				Status:         ww.Status(),
+			})
+			if err != nil {
+				log.Printf("could not record request of %s: %s", principal.Username, err)
+			}
+		})
+	}
+}
+
+func isChangingRequest(r *http.Request) bool {
+	return r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions
+}
+
+// ReadAuditEntries reads the audit log of the organization of the principal, only admins see the audit log
+func (s *AuditService) ReadAuditEntries(ctx context.Context, principal *Principal, pageParams *paged.PageParams) (*AuditEntriesPaged, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrForbidden
+	}
+
+	return s.auditRepository.FindAuditEntries(ctx, principal.OrganizationID, pageParams)
+}
@@ -0,0 +1,91 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestRequestAuditMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	auditRepository := NewInMemAuditRepository()
+	auditService := NewAuditService(NewInMemRepositoryTxer(), auditRepository)
+
+	handler := auditService.RequestAuditMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	request := func(impersonatedBy string) *http.Request {
+		r, _ := http.NewRequest("POST", "/api/activities?contentType=application/json", nil)
+		return r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+			OrganizationID: OrganizationIDSample,
+			Username:       "user1",
+			ImpersonatedBy: impersonatedBy,
+		}))
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), request(""))
+	handler.ServeHTTP(httptest.NewRecorder(), request("ops@baralga.com"))
+
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	auditEntriesPaged, err := auditService.ReadAuditEntries(context.Background(), admin, &paged.PageParams{Size: 50})
+	is.NoErr(err)
+	is.Equal(len(auditEntriesPaged.AuditEntries), 1)
+
+	auditEntry := auditEntriesPaged.AuditEntries[0]
+	is.Equal(auditEntry.Username, "user1")
+	is.Equal(auditEntry.ImpersonatedBy, "ops@baralga.com")
+	is.Equal(auditEntry.Action, AuditActionRequest)
+	is.Equal(auditEntry.Method, "POST")
+	is.Equal(auditEntry.Path, "/api/activities?contentType=application/json")
+	is.Equal(auditEntry.Status, http.StatusCreated)
+}
+
+func TestReadAuditEntriesForbidden(t *testing.T) {
+	is := is.New(t)
+
+	auditService := NewAuditService(NewInMemRepositoryTxer(), NewInMemAuditRepository())
+	_, err := auditService.ReadAuditEntries(context.Background(), &Principal{OrganizationID: OrganizationIDSample, Roles: []string{"ROLE_USER"}}, &paged.PageParams{Size: 50})
+	is.True(errors.Is(err, ErrForbidden))
+}
+
+func TestRequestAuditMiddlewareWithServiceAccount(t *testing.T) {
+	is := is.New(t)
+
+	auditRepository := NewInMemAuditRepository()
+	auditService := NewAuditService(NewInMemRepositoryTxer(), auditRepository)
+
+	handler := auditService.RequestAuditMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serviceAccount := &Principal{
+		OrganizationID:   OrganizationIDSample,
+		Name:             "CRM Sync",
+		Username:         "2c1ab2e6-1d0e-4f57-bd51-b8d4b4b1cd35",
+		ServiceAccountID: uuid.MustParse("2c1ab2e6-1d0e-4f57-bd51-b8d4b4b1cd35"),
+	}
+	request := func(method string) *http.Request {
+		r, _ := http.NewRequest(method, "/api/activities", nil)
+		return r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, serviceAccount))
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), request("GET"))
+	handler.ServeHTTP(httptest.NewRecorder(), request("POST"))
+
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	auditEntriesPaged, err := auditService.ReadAuditEntries(context.Background(), admin, &paged.PageParams{Size: 50})
+	is.NoErr(err)
+	is.Equal(len(auditEntriesPaged.AuditEntries), 1)
+
+	auditEntry := auditEntriesPaged.AuditEntries[0]
+	is.Equal(auditEntry.ServiceAccount, "CRM Sync")
+	is.Equal(auditEntry.Method, "POST")
+}
@@ -0,0 +1,153 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// backupFormatVersion is the version of the format of the backup archives
+	backupFormatVersion = 1
+
+	backupManifestFile  = "manifest.json"
+	backupTablesDir     = "tables/"
+	backupStoragePrefix = "backups/"
+)
+
+var (
+	ErrBackupNotValid       = NewDomainError("backup:not-valid", http.StatusUnprocessableEntity, "backup not valid")
+	ErrBackupSchemaMismatch = NewDomainError("backup:schema-mismatch", http.StatusConflict, "backup is of another schema version")
+	ErrBackupForeignSchemas = NewDomainError("backup:foreign-schemas", http.StatusConflict, "database has tables outside the public schema which are not backed up")
+)
+
+// backupAttachmentColumns are the columns with binary files, which are listed in the manifest of the backup
+var backupAttachmentColumns = []*BackupAttachmentColumn{
+	{Table: "export_jobs", IDColumn: "export_job_id", Column: "content"},
+}
+
+// BackupManifest describes the content of a backup archive to verify its integrity before a restore
+type BackupManifest struct {
+	FormatVersion int                 `json:"formatVersion"`
+	SchemaVersion uint                `json:"schemaVersion"`
+	CreatedAt     time.Time           `json:"createdAt"`
+	CreatedBy     string              `json:"createdBy,omitempty"`
+	Tables        []*BackupTable      `json:"tables"`
+	Attachments   []*BackupAttachment `json:"attachments"`
+}
+
+// BackupTable is a table of the backup, its rows are stored as CSV in the file of the table
+type BackupTable struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Rows   int64  `json:"rows"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupAttachmentColumn is a column of a table with binary files
+type BackupAttachmentColumn struct {
+	Table    string
+	IDColumn string
+	Column   string
+}
+
+// BackupAttachment is a binary file stored in a column of a table of the backup
+type BackupAttachment struct {
+	Table  string `json:"table"`
+	ID     string `json:"id"`
+	Column string `json:"column"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupTableWriter writes the rows of the table copied by the copy function to the backup
+type BackupTableWriter func(table string, copyTable func(w io.Writer) (int64, error)) error
+
+// BackupTableReader reads the rows of the table from the backup
+type BackupTableReader func(table string) (io.Reader, error)
+
+type BackupRepository interface {
+	// ReadBackup copies all tables within one snapshot of the database
+	ReadBackup(ctx context.Context, writeTable BackupTableWriter) (*BackupManifest, error)
+
+	// RestoreBackup replaces the content of all tables within one transaction
+	RestoreBackup(ctx context.Context, manifest *BackupManifest, readTable BackupTableReader) error
+}
+
+// backupFileOf is the file of the table in the backup archive
+func backupFileOf(table string) string {
+	return backupTablesDir + table + ".csv"
+}
+
+// Table is the table of the manifest with the name, nil if the backup does not contain the table
+func (m *BackupManifest) Table(name string) *BackupTable {
+	for _, table := range m.Tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// Validate checks whether the manifest is complete and of a known format
+func (m *BackupManifest) Validate() error {
+	if m.FormatVersion != backupFormatVersion {
+		return errors.Wrapf(ErrBackupNotValid, "format version %v not supported", m.FormatVersion)
+	}
+	if len(m.Tables) == 0 {
+		return errors.Wrap(ErrBackupNotValid, "no tables")
+	}
+	for _, table := range m.Tables {
+		if table.Name == "" || table.File != backupFileOf(table.Name) || table.SHA256 == "" {
+			return errors.Wrapf(ErrBackupNotValid, "table %s not valid", table.Name)
+		}
+	}
+	return nil
+}
+
+// SortTablesByDependencies sorts the tables so that tables referenced by foreign keys come before the tables
+// referencing them, tables without dependencies between them are sorted by name
+func SortTablesByDependencies(tables []string, references map[string][]string) []string {
+	remaining := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		remaining[table] = true
+	}
+
+	sorted := make([]string, 0, len(tables))
+	for len(remaining) > 0 {
+		var ready []string
+		for table := range remaining {
+			if !referencesRemaining(table, references[table], remaining) {
+				ready = append(ready, table)
+			}
+		}
+
+		// cyclic references are added by name
+		if len(ready) == 0 {
+			for table := range remaining {
+				ready = append(ready, table)
+			}
+		}
+
+		sort.Strings(ready)
+		for _, table := range ready {
+			delete(remaining, table)
+		}
+		sorted = append(sorted, ready...)
+	}
+	return sorted
+}
+
+func referencesRemaining(table string, referencedTables []string, remaining map[string]bool) bool {
+	for _, referencedTable := range referencedTables {
+		if referencedTable != table && remaining[referencedTable] {
+			return true
+		}
+	}
+	return false
+}
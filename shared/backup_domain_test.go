@@ -0,0 +1,55 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestSortTablesByDependencies(t *testing.T) {
+	is := is.New(t)
+
+	tables := SortTablesByDependencies(
+		[]string{"activities", "projects", "organizations", "users", "roles"},
+		map[string][]string{
+			"activities": {"projects", "organizations"},
+			"projects":   {"organizations", "projects"},
+			"users":      {"organizations"},
+			"roles":      {"users"},
+		},
+	)
+	is.Equal(tables, []string{"organizations", "projects", "users", "activities", "roles"})
+}
+
+func TestSortTablesByDependenciesWithCycle(t *testing.T) {
+	is := is.New(t)
+
+	tables := SortTablesByDependencies(
+		[]string{"b", "a"},
+		map[string][]string{
+			"a": {"b"},
+			"b": {"a"},
+		},
+	)
+	is.Equal(tables, []string{"a", "b"})
+}
+
+func TestValidateBackupManifest(t *testing.T) {
+	is := is.New(t)
+
+	manifest := &BackupManifest{
+		FormatVersion: backupFormatVersion,
+		Tables: []*BackupTable{
+			{Name: "organizations", File: "tables/organizations.csv", SHA256: "abc"},
+		},
+	}
+	is.NoErr(manifest.Validate())
+	is.Equal(manifest.Table("organizations").File, "tables/organizations.csv")
+	is.Equal(manifest.Table("users"), nil)
+
+	manifest.Tables[0].File = "../organizations.csv"
+	is.True(DomainErrorOf(manifest.Validate()) == ErrBackupNotValid)
+
+	manifest.FormatVersion = 2
+	is.True(DomainErrorOf(manifest.Validate()) == ErrBackupNotValid)
+}
@@ -0,0 +1,270 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbBackupRepository is a SQL database repository for logical backups of all organizations
+type DbBackupRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ BackupRepository = (*DbBackupRepository)(nil)
+
+// NewDbBackupRepository creates a new SQL database repository for backups
+func NewDbBackupRepository(connPool *pgxpool.Pool) *DbBackupRepository {
+	return &DbBackupRepository{
+		connPool: connPool,
+	}
+}
+
+// ReadBackup copies all tables of the public schema as CSV within one snapshot of the database,
+// the context must not be bound to an organization so that the rows of all organizations are copied.
+// Databases with tables in other schemas are refused as their rows would be missing.
+func (r *DbBackupRepository) ReadBackup(ctx context.Context, writeTable BackupTableWriter) (*BackupManifest, error) {
+	manifest := &BackupManifest{}
+	err := InSnapshot(ctx, r.connPool, func(tx pgx.Tx) error {
+		err := WithoutStatementTimeout(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		manifest.SchemaVersion, err = schemaVersionOf(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		foreignSchemas, err := foreignSchemasOf(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if len(foreignSchemas) > 0 {
+			return errors.Wrapf(ErrBackupForeignSchemas, "schemas %s", strings.Join(foreignSchemas, ", "))
+		}
+
+		tables, err := backupTablesOf(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		for _, table := range tables {
+			backupTable := &BackupTable{Name: table}
+			err = writeTable(table, func(w io.Writer) (int64, error) {
+				commandTag, err := tx.Conn().PgConn().CopyTo(
+					ctx,
+					w,
+					fmt.Sprintf("COPY %s TO STDOUT WITH (FORMAT csv, HEADER true)", pgx.Identifier{table}.Sanitize()),
+				)
+				if err != nil {
+					return 0, err
+				}
+				backupTable.Rows = commandTag.RowsAffected()
+				return backupTable.Rows, nil
+			})
+			if err != nil {
+				return err
+			}
+			manifest.Tables = append(manifest.Tables, backupTable)
+		}
+
+		manifest.Attachments, err = backupAttachmentsOf(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// RestoreBackup replaces the content of all tables of the manifest within one transaction, row level security
+// is lifted for the restoring transaction as the rows of all organizations are copied
+func (r *DbBackupRepository) RestoreBackup(ctx context.Context, manifest *BackupManifest, readTable BackupTableReader) error {
+	return pgx.BeginTxFunc(ctx, r.connPool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		err := WithoutStatementTimeout(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		schemaVersion, err := schemaVersionOf(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if schemaVersion != manifest.SchemaVersion {
+			return ErrBackupSchemaMismatch
+		}
+
+		tables := make([]string, len(manifest.Tables))
+		for i, table := range manifest.Tables {
+			tables[i] = pgx.Identifier{table.Name}.Sanitize()
+		}
+
+		forcedTables, err := forcedRowLevelSecurityTablesOf(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for _, table := range forcedTables {
+			_, err = tx.Exec(ctx, fmt.Sprintf("ALTER TABLE %s NO FORCE ROW LEVEL SECURITY", pgx.Identifier{table}.Sanitize()))
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := len(tables) - 1; i >= 0; i-- {
+			_, err = tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s", tables[i]))
+			if err != nil {
+				return err
+			}
+		}
+
+		for i, table := range manifest.Tables {
+			rows, err := readTable(table.Name)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Conn().PgConn().CopyFrom(
+				ctx,
+				rows,
+				fmt.Sprintf("COPY %s FROM STDIN WITH (FORMAT csv, HEADER true)", tables[i]),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, table := range forcedTables {
+			_, err = tx.Exec(ctx, fmt.Sprintf("ALTER TABLE %s FORCE ROW LEVEL SECURITY", pgx.Identifier{table}.Sanitize()))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// schemaVersionOf is the version of the migrations of the database
+func schemaVersionOf(ctx context.Context, tx pgx.Tx) (uint, error) {
+	var version int64
+	err := tx.QueryRow(ctx, `SELECT version FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return uint(version), nil
+}
+
+// foreignSchemasOf are the schemas besides the public and system schemas which contain tables
+func foreignSchemasOf(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	rows, err := tx.Query(
+		ctx,
+		`SELECT DISTINCT schemaname
+		 FROM pg_tables
+		 WHERE schemaname NOT IN ('public', 'pg_catalog', 'information_schema') AND schemaname NOT LIKE 'pg\_%'
+		 ORDER BY schemaname`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// backupTablesOf are the tables of the public schema except the migrations, sorted by their foreign keys
+func backupTablesOf(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	rows, err := tx.Query(
+		ctx,
+		`SELECT tablename
+		 FROM pg_tables
+		 WHERE schemaname = 'public' AND tablename <> 'schema_migrations'`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query(
+		ctx,
+		`SELECT child.relname, parent.relname
+		 FROM pg_constraint c
+		 JOIN pg_class child ON child.oid = c.conrelid
+		 JOIN pg_class parent ON parent.oid = c.confrelid
+		 JOIN pg_namespace n ON n.oid = child.relnamespace
+		 WHERE c.contype = 'f' AND n.nspname = 'public'`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	references := make(map[string][]string)
+	for rows.Next() {
+		var table, referencedTable string
+		err = rows.Scan(&table, &referencedTable)
+		if err != nil {
+			return nil, err
+		}
+		references[table] = append(references[table], referencedTable)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return SortTablesByDependencies(tables, references), nil
+}
+
+// backupAttachmentsOf lists the binary files of the attachment columns with their size and checksum
+func backupAttachmentsOf(ctx context.Context, tx pgx.Tx) ([]*BackupAttachment, error) {
+	attachments := []*BackupAttachment{}
+	for _, column := range backupAttachmentColumns {
+		rows, err := tx.Query(
+			ctx,
+			fmt.Sprintf(
+				"SELECT %s::text, length(%s), encode(sha256(%s), 'hex') FROM %s WHERE %s IS NOT NULL ORDER BY 1",
+				column.IDColumn, column.Column, column.Column, column.Table, column.Column,
+			),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			attachment := &BackupAttachment{Table: column.Table, Column: column.Column}
+			err = rows.Scan(&attachment.ID, &attachment.Size, &attachment.SHA256)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			attachments = append(attachments, attachment)
+		}
+		rows.Close()
+		if rows.Err() != nil {
+			return nil, rows.Err()
+		}
+	}
+	return attachments, nil
+}
+
+// forcedRowLevelSecurityTablesOf are the tables of the public schema which force row level security on their owner
+func forcedRowLevelSecurityTablesOf(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	rows, err := tx.Query(
+		ctx,
+		`SELECT c.relname
+		 FROM pg_class c
+		 JOIN pg_namespace n ON n.oid = c.relnamespace
+		 WHERE n.nspname = 'public' AND c.relkind = 'r' AND c.relforcerowsecurity`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
@@ -0,0 +1,65 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+func TestBackupRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	backupService := NewBackupService(&Config{InstanceAdmins: "ops@baralga.com"}, NewInMemStorage(), NewDbBackupRepository(connPool))
+
+	archive := &bytes.Buffer{}
+	manifest, err := backupService.WriteBackup(ctx, newInstanceAdminPrincipal(), archive)
+	is.NoErr(err)
+	is.True(manifest.SchemaVersion > 0)
+	is.Equal(manifest.Tables[0].Name, "organizations")
+	is.True(manifest.Table("activities").Rows > 0)
+
+	t.Run("RestoreBackup", func(t *testing.T) {
+		_, err = connPool.Exec(ctx, "DELETE FROM roles")
+		is.NoErr(err)
+
+		restoredManifest, err := backupService.RestoreBackup(ctx, func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(archive.Bytes())), nil
+		})
+		is.NoErr(err)
+		is.Equal(len(restoredManifest.Tables), len(manifest.Tables))
+
+		var roles int64
+		is.NoErr(connPool.QueryRow(ctx, "SELECT count(*) FROM roles").Scan(&roles))
+		is.Equal(roles, manifest.Table("roles").Rows)
+	})
+	t.Run("ReadBackupWithForeignSchemas", func(t *testing.T) {
+		_, err = connPool.Exec(ctx, "CREATE SCHEMA archive; CREATE TABLE archive.projects (id uuid)")
+		is.NoErr(err)
+
+		_, err := backupService.WriteBackup(ctx, newInstanceAdminPrincipal(), &bytes.Buffer{})
+		is.True(errors.Is(err, ErrBackupForeignSchemas))
+	})
+}
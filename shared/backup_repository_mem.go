@@ -0,0 +1,87 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+)
+
+type InMemBackupRepository struct {
+	mu            sync.Mutex
+	schemaVersion uint
+	tables        []string
+	rowsByTable   map[string]string
+}
+
+var _ BackupRepository = (*InMemBackupRepository)(nil)
+
+func NewInMemBackupRepository() *InMemBackupRepository {
+	return &InMemBackupRepository{
+		schemaVersion: 1,
+		tables:        []string{"organizations", "users"},
+		rowsByTable: map[string]string{
+			"organizations": "org_id,title\n" + OrganizationIDSample.String() + ",Test Organization\n",
+			"users":         "user_id,username,org_id\neeeeeb80-33f3-4d3f-befe-58694d2ac841,admin," + OrganizationIDSample.String() + "\n",
+		},
+	}
+}
+
+func (r *InMemBackupRepository) ReadBackup(ctx context.Context, writeTable BackupTableWriter) (*BackupManifest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	manifest := &BackupManifest{
+		SchemaVersion: r.schemaVersion,
+		Attachments:   []*BackupAttachment{},
+	}
+	for _, table := range r.tables {
+		backupTable := &BackupTable{Name: table}
+		err := writeTable(table, func(w io.Writer) (int64, error) {
+			rows := r.rowsByTable[table]
+			_, err := io.WriteString(w, rows)
+			if err != nil {
+				return 0, err
+			}
+			backupTable.Rows = int64(strings.Count(rows, "\n") - 1)
+			return backupTable.Rows, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		manifest.Tables = append(manifest.Tables, backupTable)
+	}
+	return manifest, nil
+}
+
+func (r *InMemBackupRepository) RestoreBackup(ctx context.Context, manifest *BackupManifest, readTable BackupTableReader) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if manifest.SchemaVersion != r.schemaVersion {
+		return ErrBackupSchemaMismatch
+	}
+
+	tables := make([]string, len(manifest.Tables))
+	rowsByTable := make(map[string]string, len(manifest.Tables))
+	for i, table := range manifest.Tables {
+		rows, err := readTable(table.Name)
+		if err != nil {
+			return err
+		}
+
+		buf := &bytes.Buffer{}
+		_, err = io.Copy(buf, rows)
+		if err != nil {
+			return err
+		}
+
+		tables[i] = table.Name
+		rowsByTable[table.Name] = buf.String()
+	}
+
+	r.tables = tables
+	r.rowsByTable = rowsByTable
+	return nil
+}
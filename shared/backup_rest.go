@@ -0,0 +1,203 @@
+package shared
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+)
+
+type backupManifestModel struct {
+	SchemaVersion uint                `json:"schemaVersion"`
+	CreatedAt     string              `json:"createdAt"`
+	CreatedBy     string              `json:"createdBy,omitempty"`
+	Tables        []*BackupTable      `json:"tables"`
+	Attachments   []*BackupAttachment `json:"attachments"`
+	Links         *hal.Links          `json:"_links"`
+}
+
+type storedBackupModel struct {
+	Key       string `json:"key"`
+	Size      int64  `json:"size"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type storedBackupsModel struct {
+	EmbeddedStoredBackups *embeddedStoredBackupsModel `json:"_embedded"`
+	Links                 *hal.Links                  `json:"_links"`
+}
+
+type embeddedStoredBackupsModel struct {
+	StoredBackups []*storedBackupModel `json:"backups"`
+}
+
+type BackupRestHandlers struct {
+	config        *Config
+	backupService *BackupService
+	scanService   *ScanService
+}
+
+func NewBackupRestHandlers(config *Config, backupService *BackupService, scanService *ScanService) *BackupRestHandlers {
+	return &BackupRestHandlers{
+		config:        config,
+		backupService: backupService,
+		scanService:   scanService,
+	}
+}
+
+func (a *BackupRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/instance/backup", a.HandleGetBackup())
+	r.Post("/instance/backup/verification", a.HandleVerifyBackup())
+	r.Get("/instance/backups", a.HandleGetStoredBackups())
+	r.Post("/instance/backups", a.HandleStoreBackup())
+}
+
+func (a *BackupRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetBackup streams the backup archive of all organizations
+func (a *BackupRestHandlers) HandleGetBackup() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	config := a.config
+	backupService := a.backupService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		err := AuthorizeInstanceAdmin(config, principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"baralga-backup-%s.tar.gz\"", time.Now().Format("20060102-1504")))
+
+		manifest, err := backupService.WriteBackup(r.Context(), principal, w)
+		if errors.Is(err, ErrBackupForeignSchemas) {
+			// refused before the first table so nothing is streamed yet
+			w.Header().Del("Content-Disposition")
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+		if err != nil {
+			// the archive is already streamed, it lacks the manifest and fails the verification
+			log.Printf("could not write backup: %v", err)
+			return
+		}
+
+		log.Printf("backup of %v tables written by %s", len(manifest.Tables), principal.Username)
+	}
+}
+
+// HandleGetStoredBackups reads the backup archives in the storage
+func (a *BackupRestHandlers) HandleGetStoredBackups() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	backupService := a.backupService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		storedBackups, err := backupService.ReadStoredBackups(r.Context(), principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		storedBackupModels := make([]*storedBackupModel, len(storedBackups))
+		for i, storedBackup := range storedBackups {
+			storedBackupModels[i] = mapToStoredBackupModel(storedBackup)
+		}
+
+		RenderJSON(w, &storedBackupsModel{
+			EmbeddedStoredBackups: &embeddedStoredBackupsModel{
+				StoredBackups: storedBackupModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleStoreBackup writes the backup archive of all organizations to the storage
+func (a *BackupRestHandlers) HandleStoreBackup() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	backupService := a.backupService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		storedBackup, err := backupService.StoreBackup(r.Context(), principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		log.Printf("backup %s stored by %s", storedBackup.Key, principal.Username)
+
+		w.WriteHeader(http.StatusCreated)
+		RenderJSON(w, mapToStoredBackupModel(storedBackup))
+	}
+}
+
+// HandleVerifyBackup verifies the integrity of the uploaded backup archive
+func (a *BackupRestHandlers) HandleVerifyBackup() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	config := a.config
+	backupService := a.backupService
+	scanService := a.scanService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		err := AuthorizeInstanceAdmin(config, principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		archive, err := BufferUpload(r.Body)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+		defer archive.Close()
+
+		err = scanService.ScanUpload(r.Context(), &Upload{
+			OrganizationID: principal.OrganizationID,
+			Username:       principal.Username,
+			FileName:       "backup.tar.gz",
+			Content:        archive,
+		})
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		manifest, err := backupService.VerifyBackup(archive)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, &backupManifestModel{
+			SchemaVersion: manifest.SchemaVersion,
+			CreatedAt:     manifest.CreatedAt.Format(time.RFC3339),
+			CreatedBy:     manifest.CreatedBy,
+			Tables:        manifest.Tables,
+			Attachments:   manifest.Attachments,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+func mapToStoredBackupModel(storedBackup *StorageObject) *storedBackupModel {
+	return &storedBackupModel{
+		Key:       storedBackup.Key,
+		Size:      storedBackup.Size,
+		CreatedAt: storedBackup.LastModified.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,120 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestHandleGetBackupAndVerify(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	backupService, _ := newTestBackupService()
+	a := NewBackupRestHandlers(&Config{InstanceAdmins: "ops@baralga.com"}, backupService, newTestScanService(nil))
+
+	r := httptest.NewRequest("GET", "/api/instance/backup", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+	a.HandleGetBackup()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(httpRec.Header().Get("Content-Type"), "application/gzip")
+
+	t.Run("HandleVerifyBackup", func(t *testing.T) {
+		verifyRec := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/api/instance/backup/verification", bytes.NewReader(httpRec.Body.Bytes()))
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+		a.HandleVerifyBackup()(verifyRec, r)
+		is.Equal(verifyRec.Result().StatusCode, http.StatusOK)
+
+		manifestModel := &backupManifestModel{}
+		err := json.NewDecoder(verifyRec.Body).Decode(manifestModel)
+		is.NoErr(err)
+		is.Equal(len(manifestModel.Tables), 2)
+	})
+
+	t.Run("HandleVerifyBackupNotValid", func(t *testing.T) {
+		verifyRec := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/api/instance/backup/verification", bytes.NewReader([]byte("no archive")))
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+		a.HandleVerifyBackup()(verifyRec, r)
+		is.Equal(verifyRec.Result().StatusCode, http.StatusUnprocessableEntity)
+	})
+}
+
+func TestHandleGetBackupAsOrgAdmin(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	backupService, _ := newTestBackupService()
+	a := NewBackupRestHandlers(&Config{InstanceAdmins: "ops@baralga.com"}, backupService, newTestScanService(nil))
+
+	r := httptest.NewRequest("GET", "/api/instance/backup", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		Username:       "admin",
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleGetBackup()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
+
+func TestHandleStoreBackupAndGetStoredBackups(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	backupService, _ := newTestBackupService()
+	a := NewBackupRestHandlers(&Config{InstanceAdmins: "ops@baralga.com"}, backupService, newTestScanService(nil))
+
+	r := httptest.NewRequest("POST", "/api/instance/backups", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+	a.HandleStoreBackup()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	var storedBackup storedBackupModel
+	err := json.NewDecoder(httpRec.Body).Decode(&storedBackup)
+	is.NoErr(err)
+
+	t.Run("HandleGetStoredBackups", func(t *testing.T) {
+		listRec := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/instance/backups", nil)
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+		a.HandleGetStoredBackups()(listRec, r)
+		is.Equal(listRec.Result().StatusCode, http.StatusOK)
+
+		var storedBackups storedBackupsModel
+		err := json.NewDecoder(listRec.Body).Decode(&storedBackups)
+		is.NoErr(err)
+		is.Equal(len(storedBackups.EmbeddedStoredBackups.StoredBackups), 1)
+		is.Equal(storedBackups.EmbeddedStoredBackups.StoredBackups[0].Key, storedBackup.Key)
+	})
+}
+
+func TestHandleVerifyInfectedBackup(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	backupService, _ := newTestBackupService()
+	scanService := newTestScanService(nil)
+	a := NewBackupRestHandlers(&Config{InstanceAdmins: "ops@baralga.com"}, backupService, scanService)
+
+	r := httptest.NewRequest("POST", "/api/instance/backup/verification", bytes.NewReader([]byte(eicarSignature)))
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+	a.HandleVerifyBackup()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusUnprocessableEntity)
+
+	quarantine, err := scanService.ReadQuarantine(context.Background(), newInstanceAdminPrincipal())
+	is.NoErr(err)
+	is.Equal(len(quarantine), 1)
+}
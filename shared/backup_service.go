@@ -0,0 +1,329 @@
+package shared
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BackupService backs up all organizations of the instance into an archive and restores them from the archive,
+// so that small self-hosted instances need no tooling of a database administrator. The archive is a tar.gz
+// with the rows of each table as CSV and a manifest with the checksums to verify the integrity before a restore.
+type BackupService struct {
+	config           *Config
+	storage          Storage
+	backupRepository BackupRepository
+}
+
+// NewBackupService creates a new service for backups of the instance
+func NewBackupService(config *Config, storage Storage, backupRepository BackupRepository) *BackupService {
+	return &BackupService{
+		config:           config,
+		storage:          storage,
+		backupRepository: backupRepository,
+	}
+}
+
+// WriteBackup writes the backup archive of all organizations, only operators of the instance back up the instance.
+// An archive broken off while writing lacks the manifest, which is written last, and fails the verification.
+func (s *BackupService) WriteBackup(ctx context.Context, principal *Principal, w io.Writer) (*BackupManifest, error) {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	writtenTables := make(map[string]*BackupTable)
+	manifest, err := s.backupRepository.ReadBackup(
+		withoutOrganization(ctx),
+		func(table string, copyTable func(w io.Writer) (int64, error)) error {
+			writtenTable, err := writeBackupTable(tarWriter, table, copyTable, now)
+			if err != nil {
+				return err
+			}
+			writtenTables[table] = writtenTable
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.FormatVersion = backupFormatVersion
+	manifest.CreatedAt = now
+	manifest.CreatedBy = principal.Username
+	for _, table := range manifest.Tables {
+		writtenTable := writtenTables[table.Name]
+		table.File = writtenTable.File
+		table.Size = writtenTable.Size
+		table.SHA256 = writtenTable.SHA256
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:    backupManifestFile,
+		Mode:    0600,
+		Size:    int64(len(manifestJSON)),
+		ModTime: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = tarWriter.Write(manifestJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+	err = gzipWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// StoreBackup writes the backup archive of all organizations to the storage, the archive is buffered
+// in a temporary file as the storage needs the size of the archive before the upload
+func (s *BackupService) StoreBackup(ctx context.Context, principal *Principal) (*StorageObject, error) {
+	tempFile, err := os.CreateTemp("", "baralga-backup-*.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		tempFile.Close()
+		os.Remove(tempFile.Name())
+	}()
+
+	manifest, err := s.WriteBackup(ctx, principal, tempFile)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := tempFile.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tempFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	storedBackup := &StorageObject{
+		Key:          fmt.Sprintf("%sbaralga-backup-%s.tar.gz", backupStoragePrefix, manifest.CreatedAt.UTC().Format("20060102-150405")),
+		Size:         size,
+		LastModified: manifest.CreatedAt,
+	}
+	err = s.storage.Put(ctx, storedBackup.Key, tempFile, size, "application/gzip")
+	if err != nil {
+		return nil, err
+	}
+
+	return storedBackup, nil
+}
+
+// ReadStoredBackups reads the backup archives in the storage
+func (s *BackupService) ReadStoredBackups(ctx context.Context, principal *Principal) ([]*StorageObject, error) {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storage.List(ctx, backupStoragePrefix)
+}
+
+// RestoreStoredBackup replaces all organizations of the instance by the backup archive in the storage
+func (s *BackupService) RestoreStoredBackup(ctx context.Context, key string) (*BackupManifest, error) {
+	if !strings.HasPrefix(key, backupStoragePrefix) {
+		return nil, errors.Wrapf(ErrBackupNotValid, "%s is no stored backup", key)
+	}
+
+	return s.RestoreBackup(ctx, func() (io.ReadCloser, error) {
+		return s.storage.Get(ctx, key)
+	})
+}
+
+// VerifyBackup verifies the integrity of the backup archive by the checksums of its manifest
+func (s *BackupService) VerifyBackup(r io.Reader) (*BackupManifest, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(ErrBackupNotValid, err.Error())
+	}
+	defer gzipReader.Close()
+
+	var manifest *BackupManifest
+	archivedTables := make(map[string]*BackupTable)
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(ErrBackupNotValid, err.Error())
+		}
+
+		switch {
+		case header.Name == backupManifestFile:
+			manifest = &BackupManifest{}
+			err = json.NewDecoder(tarReader).Decode(manifest)
+			if err != nil {
+				return nil, errors.Wrap(ErrBackupNotValid, err.Error())
+			}
+		case strings.HasPrefix(header.Name, backupTablesDir):
+			hash := sha256.New()
+			size, err := io.Copy(hash, tarReader)
+			if err != nil {
+				return nil, errors.Wrap(ErrBackupNotValid, err.Error())
+			}
+			archivedTables[header.Name] = &BackupTable{
+				File:   header.Name,
+				Size:   size,
+				SHA256: hex.EncodeToString(hash.Sum(nil)),
+			}
+		default:
+			return nil, errors.Wrapf(ErrBackupNotValid, "unknown file %s", header.Name)
+		}
+	}
+
+	if manifest == nil {
+		return nil, errors.Wrap(ErrBackupNotValid, "manifest missing")
+	}
+
+	err = manifest.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(archivedTables) != len(manifest.Tables) {
+		return nil, errors.Wrap(ErrBackupNotValid, "tables do not match the manifest")
+	}
+	for _, table := range manifest.Tables {
+		archivedTable, ok := archivedTables[table.File]
+		if !ok || archivedTable.Size != table.Size || archivedTable.SHA256 != table.SHA256 {
+			return nil, errors.Wrapf(ErrBackupNotValid, "checksum of table %s does not match", table.Name)
+		}
+	}
+
+	return manifest, nil
+}
+
+// RestoreBackup replaces all organizations of the instance by the backup archive. The archive is opened twice,
+// first to verify its integrity and then to restore the tables, so nothing is changed for a broken archive.
+func (s *BackupService) RestoreBackup(ctx context.Context, openArchive func() (io.ReadCloser, error)) (*BackupManifest, error) {
+	archive, err := openArchive()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := s.VerifyBackup(archive)
+	archive.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err = openArchive()
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		return nil, errors.Wrap(ErrBackupNotValid, err.Error())
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	err = s.backupRepository.RestoreBackup(
+		withoutOrganization(ctx),
+		manifest,
+		func(table string) (io.Reader, error) {
+			header, err := tarReader.Next()
+			if err != nil {
+				return nil, errors.Wrap(ErrBackupNotValid, err.Error())
+			}
+			if header.Name != backupFileOf(table) {
+				return nil, errors.Wrapf(ErrBackupNotValid, "expected table %s but found %s", table, header.Name)
+			}
+			return tarReader, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// writeBackupTable writes the rows of the table to the archive, the rows are buffered in a temporary file
+// as the size of a file in the archive is written before its content
+func writeBackupTable(tarWriter *tar.Writer, table string, copyTable func(w io.Writer) (int64, error), now time.Time) (*BackupTable, error) {
+	tempFile, err := os.CreateTemp("", "baralga-backup-*.csv")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		tempFile.Close()
+		os.Remove(tempFile.Name())
+	}()
+
+	hash := sha256.New()
+	_, err = copyTable(io.MultiWriter(tempFile, hash))
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := tempFile.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tempFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	backupTable := &BackupTable{
+		Name:   table,
+		File:   backupFileOf(table),
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:    backupTable.File,
+		Mode:    0600,
+		Size:    size,
+		ModTime: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.Copy(tarWriter, tempFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return backupTable, nil
+}
@@ -0,0 +1,156 @@
+package shared
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+func newTestBackupService() (*BackupService, *InMemBackupRepository) {
+	backupRepository := NewInMemBackupRepository()
+	return NewBackupService(&Config{InstanceAdmins: "ops@baralga.com"}, NewInMemStorage(), backupRepository), backupRepository
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	is := is.New(t)
+
+	backupService, backupRepository := newTestBackupService()
+	ctx := context.Background()
+
+	archive := &bytes.Buffer{}
+	manifest, err := backupService.WriteBackup(ctx, newInstanceAdminPrincipal(), archive)
+	is.NoErr(err)
+	is.Equal(len(manifest.Tables), 2)
+	is.Equal(manifest.Table("users").Rows, int64(1))
+	is.Equal(manifest.CreatedBy, "ops@baralga.com")
+
+	verifiedManifest, err := backupService.VerifyBackup(bytes.NewReader(archive.Bytes()))
+	is.NoErr(err)
+	is.Equal(verifiedManifest.Table("organizations").SHA256, manifest.Table("organizations").SHA256)
+
+	backupRepository.rowsByTable["users"] = "user_id,username,org_id\n"
+
+	_, err = backupService.RestoreBackup(ctx, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(archive.Bytes())), nil
+	})
+	is.NoErr(err)
+	is.Equal(backupRepository.tables, []string{"organizations", "users"})
+	is.Equal(backupRepository.rowsByTable["users"], "user_id,username,org_id\neeeeeb80-33f3-4d3f-befe-58694d2ac841,admin,"+OrganizationIDSample.String()+"\n")
+}
+
+func TestWriteBackupAsOrgAdmin(t *testing.T) {
+	is := is.New(t)
+
+	backupService, _ := newTestBackupService()
+
+	_, err := backupService.WriteBackup(context.Background(), &Principal{Username: "admin", Roles: []string{"ROLE_ADMIN"}}, &bytes.Buffer{})
+	is.Equal(err, ErrForbidden)
+}
+
+func TestRestoreTamperedBackup(t *testing.T) {
+	is := is.New(t)
+
+	backupService, backupRepository := newTestBackupService()
+	ctx := context.Background()
+
+	archive := &bytes.Buffer{}
+	_, err := backupService.WriteBackup(ctx, newInstanceAdminPrincipal(), archive)
+	is.NoErr(err)
+
+	// replace the rows of the users in the archive
+	tamperedArchive := &bytes.Buffer{}
+	gzipReader, err := gzip.NewReader(archive)
+	is.NoErr(err)
+	tarReader := tar.NewReader(gzipReader)
+	gzipWriter := gzip.NewWriter(tamperedArchive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		is.NoErr(err)
+
+		content, err := io.ReadAll(tarReader)
+		is.NoErr(err)
+		if header.Name == "tables/users.csv" {
+			content = bytes.ReplaceAll(content, []byte("admin"), []byte("admon"))
+		}
+
+		is.NoErr(tarWriter.WriteHeader(header))
+		_, err = tarWriter.Write(content)
+		is.NoErr(err)
+	}
+	is.NoErr(tarWriter.Close())
+	is.NoErr(gzipWriter.Close())
+
+	rowsBefore := backupRepository.rowsByTable["users"]
+	_, err = backupService.RestoreBackup(ctx, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(tamperedArchive.Bytes())), nil
+	})
+	is.True(DomainErrorOf(err) == ErrBackupNotValid)
+	is.Equal(backupRepository.rowsByTable["users"], rowsBefore)
+
+	// archives broken off miss the manifest
+	_, err = backupService.VerifyBackup(bytes.NewReader(tamperedArchive.Bytes()[:tamperedArchive.Len()/2]))
+	is.True(DomainErrorOf(err) == ErrBackupNotValid)
+}
+
+func TestRestoreBackupOfOtherSchemaVersion(t *testing.T) {
+	is := is.New(t)
+
+	backupService, backupRepository := newTestBackupService()
+	ctx := context.Background()
+
+	archive := &bytes.Buffer{}
+	_, err := backupService.WriteBackup(ctx, newInstanceAdminPrincipal(), archive)
+	is.NoErr(err)
+
+	backupRepository.schemaVersion = 2
+
+	_, err = backupService.RestoreBackup(ctx, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(archive.Bytes())), nil
+	})
+	is.Equal(err, ErrBackupSchemaMismatch)
+}
+
+func TestStoreAndRestoreStoredBackup(t *testing.T) {
+	is := is.New(t)
+
+	backupService, backupRepository := newTestBackupService()
+	ctx := context.Background()
+
+	storedBackup, err := backupService.StoreBackup(ctx, newInstanceAdminPrincipal())
+	is.NoErr(err)
+	is.True(strings.HasPrefix(storedBackup.Key, "backups/baralga-backup-"))
+	is.True(storedBackup.Size > 0)
+
+	storedBackups, err := backupService.ReadStoredBackups(ctx, newInstanceAdminPrincipal())
+	is.NoErr(err)
+	is.Equal(len(storedBackups), 1)
+	is.Equal(storedBackups[0].Key, storedBackup.Key)
+
+	backupRepository.rowsByTable["users"] = "user_id,username,org_id\n"
+
+	manifest, err := backupService.RestoreStoredBackup(ctx, storedBackup.Key)
+	is.NoErr(err)
+	is.Equal(len(manifest.Tables), 2)
+	is.Equal(backupRepository.rowsByTable["users"], "user_id,username,org_id\neeeeeb80-33f3-4d3f-befe-58694d2ac841,admin,"+OrganizationIDSample.String()+"\n")
+
+	t.Run("RestoreOtherFile", func(t *testing.T) {
+		_, err := backupService.RestoreStoredBackup(ctx, "exports/report")
+		is.True(errors.Is(err, ErrBackupNotValid))
+	})
+
+	t.Run("ReadStoredBackupsAsOrgAdmin", func(t *testing.T) {
+		_, err := backupService.ReadStoredBackups(ctx, &Principal{Username: "admin", Roles: []string{"ROLE_ADMIN"}})
+		is.Equal(err, ErrForbidden)
+	})
+}
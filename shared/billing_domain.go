@@ -0,0 +1,63 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SubscriptionStatusTrialing   = "trialing"
+	SubscriptionStatusActive     = "active"
+	SubscriptionStatusPastDue    = "past_due"
+	SubscriptionStatusUnpaid     = "unpaid"
+	SubscriptionStatusIncomplete = "incomplete"
+	SubscriptionStatusCanceled   = "canceled"
+)
+
+var (
+	ErrSubscriptionNotFound    = NewDomainError("billing:subscription-not-found", http.StatusNotFound, "subscription not found")
+	ErrBillingNotConfigured    = NewDomainError("billing:not-configured", http.StatusNotImplemented, "billing not configured")
+	ErrPlanNotPurchasable      = NewDomainError("billing:plan-not-purchasable", http.StatusBadRequest, "plan can not be purchased")
+	ErrWebhookSignatureInvalid = NewDomainError("billing:invalid-signature", http.StatusBadRequest, "invalid webhook signature")
+)
+
+// Subscription links an organization to its customer and subscription at Stripe
+type Subscription struct {
+	OrganizationID   uuid.UUID
+	CustomerID       string
+	SubscriptionID   string
+	Plan             string
+	Status           string
+	CurrentPeriodEnd *time.Time
+	LastEventAt      *time.Time
+	UpdatedAt        time.Time
+}
+
+type SubscriptionRepository interface {
+	FindSubscriptionByOrganizationID(ctx context.Context, organizationID uuid.UUID) (*Subscription, error)
+	FindSubscriptionByCustomerID(ctx context.Context, customerID string) (*Subscription, error)
+	UpsertSubscription(ctx context.Context, subscription *Subscription) (*Subscription, error)
+}
+
+// IsPaying checks whether the subscription grants its plan
+func (s *Subscription) IsPaying() bool {
+	switch s.Status {
+	case SubscriptionStatusActive, SubscriptionStatusTrialing, SubscriptionStatusPastDue:
+		return true
+	}
+	return false
+}
+
+// IsOutdated checks whether an event created at the time is older than the last event applied to the subscription
+func (s *Subscription) IsOutdated(eventCreatedAt time.Time) bool {
+	return s.LastEventAt != nil && eventCreatedAt.Before(*s.LastEventAt)
+}
+
+// BillingStatus is the plan of an organization with its subscription, no subscription if the organization never subscribed
+type BillingStatus struct {
+	Plan         *Plan
+	Subscription *Subscription
+}
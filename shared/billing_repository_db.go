@@ -0,0 +1,111 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbSubscriptionRepository is a SQL database repository for billing subscriptions
+type DbSubscriptionRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ SubscriptionRepository = (*DbSubscriptionRepository)(nil)
+
+// NewDbSubscriptionRepository creates a new SQL database repository for billing subscriptions
+func NewDbSubscriptionRepository(connPool *pgxpool.Pool) *DbSubscriptionRepository {
+	return &DbSubscriptionRepository{
+		connPool: connPool,
+	}
+}
+
+// FindSubscriptionByOrganizationID reads the subscription of the organization
+func (r *DbSubscriptionRepository) FindSubscriptionByOrganizationID(ctx context.Context, organizationID uuid.UUID) (*Subscription, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT org_id, customer_id, subscription_id, plan, status, current_period_end, last_event_at, updated_at 
+		 FROM billing_subscriptions 
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+	return scanSubscription(row)
+}
+
+// FindSubscriptionByCustomerID reads the subscription of the customer at Stripe
+func (r *DbSubscriptionRepository) FindSubscriptionByCustomerID(ctx context.Context, customerID string) (*Subscription, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT org_id, customer_id, subscription_id, plan, status, current_period_end, last_event_at, updated_at 
+		 FROM billing_subscriptions 
+		 WHERE customer_id = $1`,
+		customerID,
+	)
+	return scanSubscription(row)
+}
+
+// UpsertSubscription stores the subscription of the organization
+func (r *DbSubscriptionRepository) UpsertSubscription(ctx context.Context, subscription *Subscription) (*Subscription, error) {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO billing_subscriptions 
+		   (org_id, customer_id, subscription_id, plan, status, current_period_end, last_event_at, updated_at) 
+		 VALUES 
+		   ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (org_id) DO UPDATE 
+		 SET customer_id = EXCLUDED.customer_id, subscription_id = EXCLUDED.subscription_id, 
+		     plan = EXCLUDED.plan, status = EXCLUDED.status, current_period_end = EXCLUDED.current_period_end, 
+		     last_event_at = EXCLUDED.last_event_at, updated_at = EXCLUDED.updated_at`,
+		subscription.OrganizationID,
+		subscription.CustomerID,
+		sql.NullString{String: subscription.SubscriptionID, Valid: subscription.SubscriptionID != ""},
+		sql.NullString{String: subscription.Plan, Valid: subscription.Plan != ""},
+		sql.NullString{String: subscription.Status, Valid: subscription.Status != ""},
+		subscription.CurrentPeriodEnd,
+		subscription.LastEventAt,
+		subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func scanSubscription(row pgx.Row) (*Subscription, error) {
+	var (
+		subscriptionID pgtype.Varchar
+		plan           pgtype.Varchar
+		status         pgtype.Varchar
+	)
+
+	subscription := &Subscription{}
+	err := row.Scan(
+		&subscription.OrganizationID,
+		&subscription.CustomerID,
+		&subscriptionID,
+		&plan,
+		&status,
+		&subscription.CurrentPeriodEnd,
+		&subscription.LastEventAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+
+	subscription.SubscriptionID = subscriptionID.String
+	subscription.Plan = plan.String
+	subscription.Status = status.String
+	return subscription, nil
+}
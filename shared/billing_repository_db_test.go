@@ -0,0 +1,78 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestSubscriptionRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	subscriptionRepository := NewDbSubscriptionRepository(connPool)
+	planRepository := NewDbPlanRepository(connPool)
+	repositoryTxer := NewDbRepositoryTxer(connPool)
+
+	t.Run("UpsertSubscription", func(t *testing.T) {
+		_, err := subscriptionRepository.FindSubscriptionByOrganizationID(context.Background(), OrganizationIDSample)
+		is.Equal(err, ErrSubscriptionNotFound)
+
+		for _, status := range []string{"", SubscriptionStatusActive} {
+			err := repositoryTxer.InTx(
+				context.Background(),
+				func(ctx context.Context) error {
+					_, err := subscriptionRepository.UpsertSubscription(ctx, &Subscription{
+						OrganizationID: OrganizationIDSample,
+						CustomerID:     "cus_1",
+						Plan:           PlanTeam,
+						Status:         status,
+						UpdatedAt:      time.Now(),
+					})
+					return err
+				},
+			)
+			is.NoErr(err)
+
+			subscription, err := subscriptionRepository.FindSubscriptionByCustomerID(context.Background(), "cus_1")
+			is.NoErr(err)
+			is.Equal(subscription.OrganizationID, OrganizationIDSample)
+			is.Equal(subscription.Status, status)
+			is.Equal(subscription.SubscriptionID, "")
+		}
+	})
+
+	t.Run("UpdatePlanOfOrganization", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return planRepository.UpdatePlanOfOrganization(ctx, OrganizationIDSample, PlanBusiness)
+			},
+		)
+		is.NoErr(err)
+
+		plan, err := planRepository.FindPlanOfOrganization(context.Background(), OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(plan, PlanBusiness)
+	})
+}
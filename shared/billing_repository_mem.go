@@ -0,0 +1,55 @@
+package shared
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemSubscriptionRepository struct {
+	mu            sync.Mutex
+	subscriptions map[uuid.UUID]*Subscription
+}
+
+var _ SubscriptionRepository = (*InMemSubscriptionRepository)(nil)
+
+func NewInMemSubscriptionRepository() *InMemSubscriptionRepository {
+	return &InMemSubscriptionRepository{
+		subscriptions: make(map[uuid.UUID]*Subscription),
+	}
+}
+
+func (r *InMemSubscriptionRepository) FindSubscriptionByOrganizationID(ctx context.Context, organizationID uuid.UUID) (*Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscription, ok := r.subscriptions[organizationID]
+	if !ok {
+		return nil, ErrSubscriptionNotFound
+	}
+	found := *subscription
+	return &found, nil
+}
+
+func (r *InMemSubscriptionRepository) FindSubscriptionByCustomerID(ctx context.Context, customerID string) (*Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, subscription := range r.subscriptions {
+		if subscription.CustomerID == customerID {
+			found := *subscription
+			return &found, nil
+		}
+	}
+	return nil, ErrSubscriptionNotFound
+}
+
+func (r *InMemSubscriptionRepository) UpsertSubscription(ctx context.Context, subscription *Subscription) (*Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *subscription
+	r.subscriptions[subscription.OrganizationID] = &stored
+	return subscription, nil
+}
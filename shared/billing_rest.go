@@ -0,0 +1,150 @@
+package shared
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+const maxWebhookPayloadBytes = 64 * 1024
+
+type billingStatusModel struct {
+	Plan                 string     `json:"plan"`
+	MaxUsers             int        `json:"maxUsers"`
+	MaxProjects          int        `json:"maxProjects"`
+	APIRequestsPerMinute int        `json:"apiRequestsPerMinute"`
+	Subscribed           bool       `json:"subscribed"`
+	Status               string     `json:"status,omitempty"`
+	SubscribedPlan       string     `json:"subscribedPlan,omitempty"`
+	CurrentPeriodEnd     string     `json:"currentPeriodEnd,omitempty"`
+	Links                *hal.Links `json:"_links"`
+}
+
+type checkoutModel struct {
+	Plan string `json:"plan"`
+}
+
+type checkoutSessionModel struct {
+	URL string `json:"url"`
+}
+
+type BillingRestHandlers struct {
+	config         *Config
+	billingService *BillingService
+}
+
+func NewBillingRestHandlers(config *Config, billingService *BillingService) *BillingRestHandlers {
+	return &BillingRestHandlers{
+		config:         config,
+		billingService: billingService,
+	}
+}
+
+func (a *BillingRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/billing", a.HandleGetBillingStatus())
+	r.Post("/billing/checkout", a.HandleCreateCheckout())
+}
+
+func (a *BillingRestHandlers) RegisterOpen(r chi.Router) {
+	r.Post("/billing/webhook", a.HandleStripeWebhook())
+}
+
+// HandleGetBillingStatus reads the plan and subscription of the organization
+func (a *BillingRestHandlers) HandleGetBillingStatus() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	billingService := a.billingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			RenderProblemJSON(w, isProduction, ErrForbidden)
+			return
+		}
+
+		billingStatus, err := billingService.ReadBillingStatus(r.Context(), principal.OrganizationID)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		billingStatusModel := &billingStatusModel{
+			Plan:                 billingStatus.Plan.Name,
+			MaxUsers:             billingStatus.Plan.MaxUsers,
+			MaxProjects:          billingStatus.Plan.MaxProjects,
+			APIRequestsPerMinute: billingStatus.Plan.APIRequestsPerMinute,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		if subscription := billingStatus.Subscription; subscription != nil {
+			billingStatusModel.Subscribed = subscription.IsPaying()
+			billingStatusModel.Status = subscription.Status
+			billingStatusModel.SubscribedPlan = subscription.Plan
+			if subscription.CurrentPeriodEnd != nil {
+				billingStatusModel.CurrentPeriodEnd = subscription.CurrentPeriodEnd.Format(time.RFC3339)
+			}
+		}
+
+		RenderJSON(w, billingStatusModel)
+	}
+}
+
+// HandleCreateCheckout starts the checkout of a subscription to a plan at Stripe
+func (a *BillingRestHandlers) HandleCreateCheckout() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	billingService := a.billingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			RenderProblemJSON(w, isProduction, ErrForbidden)
+			return
+		}
+
+		var checkoutModel checkoutModel
+		err := json.NewDecoder(r.Body).Decode(&checkoutModel)
+		if err != nil {
+			RenderValidationProblemJSON(w, "checkout not valid", err)
+			return
+		}
+
+		if !IsValidPlan(checkoutModel.Plan) {
+			RenderValidationProblemJSON(w, "checkout not valid", NewInvalidParam("plan", "invalid", "plan is not known"))
+			return
+		}
+
+		checkoutURL, err := billingService.CreateCheckout(r.Context(), principal, checkoutModel.Plan)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		RenderJSON(w, &checkoutSessionModel{URL: checkoutURL})
+	}
+}
+
+// HandleStripeWebhook applies the payment events sent by Stripe
+func (a *BillingRestHandlers) HandleStripeWebhook() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	billingService := a.billingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayloadBytes))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		err = billingService.HandleWebhook(r.Context(), payload, r.Header.Get(StripeSignatureHeader))
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
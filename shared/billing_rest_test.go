@@ -0,0 +1,95 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestHandleGetBillingStatus(t *testing.T) {
+	is := is.New(t)
+
+	billingService, _, _, _ := newTestBillingService(&stripeClientStub{})
+	a := NewBillingRestHandlers(&Config{}, billingService)
+
+	getBillingStatus := func(roles ...string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+
+		r, _ := http.NewRequest("GET", "/api/billing", nil)
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+			OrganizationID: OrganizationIDSample,
+			Roles:          roles,
+		}))
+
+		a.HandleGetBillingStatus()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := getBillingStatus("ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	billingStatusModel := &billingStatusModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(billingStatusModel)
+	is.NoErr(err)
+	is.Equal(billingStatusModel.Plan, PlanFree)
+	is.Equal(billingStatusModel.MaxProjects, 3)
+	is.True(!billingStatusModel.Subscribed)
+
+	httpRec = getBillingStatus("ROLE_USER")
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
+
+func TestHandleCreateCheckout(t *testing.T) {
+	is := is.New(t)
+
+	billingService, _, _, _ := newTestBillingService(&stripeClientStub{})
+	a := NewBillingRestHandlers(&Config{}, billingService)
+
+	createCheckout := func(body string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+
+		r, _ := http.NewRequest("POST", "/api/billing/checkout", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+			OrganizationID: OrganizationIDSample,
+			Username:       "admin@baralga.com",
+			Roles:          []string{"ROLE_ADMIN"},
+		}))
+
+		a.HandleCreateCheckout()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := createCheckout(`{"plan":"team"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+	is.True(strings.Contains(httpRec.Body.String(), "https://checkout.stripe.com/"))
+
+	httpRec = createCheckout(`{"plan":"gold"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleStripeWebhook(t *testing.T) {
+	is := is.New(t)
+
+	billingService, _, _, _ := newTestBillingService(&stripeClientStub{})
+	a := NewBillingRestHandlers(&Config{}, billingService)
+
+	payload := `{"id":"evt_1","type":"invoice.paid","created":1,"data":{"object":{}}}`
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("POST", "/api/billing/webhook", strings.NewReader(payload))
+	r.Header.Set(StripeSignatureHeader, signStripePayload(payload, "whsec_test", time.Now()))
+	a.HandleStripeWebhook()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("POST", "/api/billing/webhook", strings.NewReader(payload))
+	r.Header.Set(StripeSignatureHeader, signStripePayload(payload, "whsec_other", time.Now()))
+	a.HandleStripeWebhook()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
@@ -0,0 +1,281 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// BillingService manages the subscriptions of organizations to the plans of the hosted offering
+type BillingService struct {
+	config                 *Config
+	repositoryTxer         RepositoryTxer
+	subscriptionRepository SubscriptionRepository
+	planService            *PlanService
+	lifecycleService       *LifecycleService
+	stripeClient           StripeClient
+}
+
+// NewBillingService creates a new service for subscriptions, billing is disabled without a Stripe client
+func NewBillingService(config *Config, repositoryTxer RepositoryTxer, subscriptionRepository SubscriptionRepository, planService *PlanService, lifecycleService *LifecycleService, stripeClient StripeClient) *BillingService {
+	return &BillingService{
+		config:                 config,
+		repositoryTxer:         repositoryTxer,
+		subscriptionRepository: subscriptionRepository,
+		planService:            planService,
+		lifecycleService:       lifecycleService,
+		stripeClient:           stripeClient,
+	}
+}
+
+// ReadBillingStatus reads the plan and subscription of the organization
+func (s *BillingService) ReadBillingStatus(ctx context.Context, organizationID uuid.UUID) (*BillingStatus, error) {
+	plan, err := s.planService.PlanOf(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.subscriptionRepository.FindSubscriptionByOrganizationID(ctx, organizationID)
+	if err != nil && !errors.Is(err, ErrSubscriptionNotFound) {
+		return nil, err
+	}
+
+	return &BillingStatus{
+		Plan:         plan,
+		Subscription: subscription,
+	}, nil
+}
+
+// CreateCheckout starts the checkout of a subscription to the plan for the organization of the principal,
+// returning the url of the checkout page at Stripe
+func (s *BillingService) CreateCheckout(ctx context.Context, principal *Principal, plan string) (string, error) {
+	if s.stripeClient == nil {
+		return "", ErrBillingNotConfigured
+	}
+
+	priceID, ok := s.config.StripePriceIDs()[plan]
+	if !ok {
+		return "", ErrPlanNotPurchasable
+	}
+
+	subscription, err := s.subscriptionRepository.FindSubscriptionByOrganizationID(ctx, principal.OrganizationID)
+	if err != nil && !errors.Is(err, ErrSubscriptionNotFound) {
+		return "", err
+	}
+
+	if subscription == nil {
+		customerID, err := s.stripeClient.CreateCustomer(ctx, principal.OrganizationID, principal.Username)
+		if err != nil {
+			return "", err
+		}
+
+		subscription = &Subscription{
+			OrganizationID: principal.OrganizationID,
+			CustomerID:     customerID,
+			UpdatedAt:      time.Now(),
+		}
+		err = s.repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				_, err := s.subscriptionRepository.UpsertSubscription(ctx, subscription)
+				return err
+			},
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return s.stripeClient.CreateCheckoutSession(
+		ctx,
+		principal.OrganizationID,
+		subscription.CustomerID,
+		priceID,
+		s.config.Webroot+"/?billing=success",
+		s.config.Webroot+"/?billing=canceled",
+	)
+}
+
+// HandleWebhook verifies and applies a payment event sent by Stripe
+func (s *BillingService) HandleWebhook(ctx context.Context, payload []byte, signatureHeader string) error {
+	if s.config.StripeWebhookSecret == "" {
+		return ErrBillingNotConfigured
+	}
+
+	err := verifyStripeSignature(payload, signatureHeader, s.config.StripeWebhookSecret, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var event stripeEvent
+	err = json.Unmarshal(payload, &event)
+	if err != nil {
+		return ErrWebhookSignatureInvalid
+	}
+
+	createdAt := time.Unix(event.Created, 0)
+	switch event.Type {
+	case stripeEventCheckoutComplete:
+		var session stripeCheckoutSession
+		if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+			return errors.Wrapf(err, "could not read stripe event %s", event.ID)
+		}
+		return s.linkSubscription(ctx, &session)
+	case stripeEventSubscriptionNew, stripeEventSubscriptionEdit, stripeEventSubscriptionEnd:
+		var stripeSubscription stripeSubscription
+		if err := json.Unmarshal(event.Data.Object, &stripeSubscription); err != nil {
+			return errors.Wrapf(err, "could not read stripe event %s", event.ID)
+		}
+		return s.updateSubscription(ctx, &stripeSubscription, createdAt)
+	case stripeEventPaymentFailed:
+		var invoice stripeInvoice
+		if err := json.Unmarshal(event.Data.Object, &invoice); err != nil {
+			return errors.Wrapf(err, "could not read stripe event %s", event.ID)
+		}
+		return s.markPastDue(ctx, &invoice, createdAt)
+	}
+
+	return nil
+}
+
+// linkSubscription links the customer and subscription of a completed checkout to the organization
+func (s *BillingService) linkSubscription(ctx context.Context, session *stripeCheckoutSession) error {
+	organizationID, err := uuid.Parse(session.ClientReferenceID)
+	if err != nil {
+		log.Printf("ignoring stripe checkout without organization %q", session.ClientReferenceID)
+		return nil
+	}
+
+	subscription, err := s.subscriptionRepository.FindSubscriptionByOrganizationID(ctx, organizationID)
+	if errors.Is(err, ErrSubscriptionNotFound) {
+		subscription = &Subscription{OrganizationID: organizationID}
+	} else if err != nil {
+		return err
+	}
+
+	subscription.CustomerID = session.Customer
+	subscription.SubscriptionID = session.Subscription
+	subscription.UpdatedAt = time.Now()
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			_, err := s.subscriptionRepository.UpsertSubscription(ctx, subscription)
+			return err
+		},
+	)
+}
+
+// updateSubscription applies the status and price of the subscription, changing the plan of the organization
+func (s *BillingService) updateSubscription(ctx context.Context, stripeSubscription *stripeSubscription, createdAt time.Time) error {
+	subscription, err := s.subscriptionRepository.FindSubscriptionByCustomerID(ctx, stripeSubscription.Customer)
+	if errors.Is(err, ErrSubscriptionNotFound) {
+		log.Printf("ignoring stripe subscription %s of unknown customer %s", stripeSubscription.ID, stripeSubscription.Customer)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if subscription.IsOutdated(createdAt) {
+		return nil
+	}
+
+	subscription.SubscriptionID = stripeSubscription.ID
+	subscription.Status = stripeSubscription.Status
+	if stripeSubscription.CurrentPeriodEnd > 0 {
+		currentPeriodEnd := time.Unix(stripeSubscription.CurrentPeriodEnd, 0)
+		subscription.CurrentPeriodEnd = &currentPeriodEnd
+	}
+	if len(stripeSubscription.Items.Data) > 0 {
+		if plan, ok := s.planOfPrice(stripeSubscription.Items.Data[0].Price.ID); ok {
+			subscription.Plan = plan
+		}
+	}
+	subscription.LastEventAt = &createdAt
+	subscription.UpdatedAt = time.Now()
+
+	return s.storeSubscription(ctx, subscription)
+}
+
+// markPastDue marks the subscription of a failed payment as past due, the organization keeps its plan until Stripe cancels the subscription
+func (s *BillingService) markPastDue(ctx context.Context, invoice *stripeInvoice, createdAt time.Time) error {
+	subscription, err := s.subscriptionRepository.FindSubscriptionByCustomerID(ctx, invoice.Customer)
+	if errors.Is(err, ErrSubscriptionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if subscription.IsOutdated(createdAt) || subscription.Status == SubscriptionStatusCanceled {
+		return nil
+	}
+
+	subscription.Status = SubscriptionStatusPastDue
+	subscription.LastEventAt = &createdAt
+	subscription.UpdatedAt = time.Now()
+
+	return s.storeSubscription(ctx, subscription)
+}
+
+// storeSubscription stores the subscription and changes the plan and status of the organization to the subscription,
+// organizations without a paying subscription are on the default plan
+func (s *BillingService) storeSubscription(ctx context.Context, subscription *Subscription) error {
+	plan := ""
+	if subscription.IsPaying() {
+		plan = subscription.Plan
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			_, err := s.subscriptionRepository.UpsertSubscription(ctx, subscription)
+			return err
+		},
+		func(ctx context.Context) error {
+			return s.planService.ChangePlan(ctx, subscription.OrganizationID, plan)
+		},
+		func(ctx context.Context) error {
+			status, ok := organizationStatusOf(subscription.Status)
+			if !ok {
+				return nil
+			}
+
+			err := s.lifecycleService.ChangeStatus(ctx, subscription.OrganizationID, status)
+			if errors.Is(err, ErrInvalidStatusTransition) {
+				log.Printf("ignoring subscription status %s of organization %s", subscription.Status, subscription.OrganizationID)
+				return nil
+			}
+			return err
+		},
+	)
+}
+
+// organizationStatusOf maps the status of a subscription to the status of the organization, false if the status is not changed
+func organizationStatusOf(subscriptionStatus string) (string, bool) {
+	switch subscriptionStatus {
+	case SubscriptionStatusActive, SubscriptionStatusTrialing:
+		return OrganizationStatusActive, true
+	case SubscriptionStatusPastDue:
+		return OrganizationStatusPastDue, true
+	case SubscriptionStatusUnpaid:
+		return OrganizationStatusSuspended, true
+	case SubscriptionStatusCanceled:
+		return OrganizationStatusCancelled, true
+	}
+	return "", false
+}
+
+func (s *BillingService) planOfPrice(priceID string) (string, bool) {
+	for plan, id := range s.config.StripePriceIDs() {
+		if id == priceID {
+			return plan, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,160 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+type stripeClientStub struct {
+	customers int
+}
+
+func (c *stripeClientStub) CreateCustomer(ctx context.Context, organizationID uuid.UUID, email string) (string, error) {
+	c.customers++
+	return fmt.Sprintf("cus_%v", c.customers), nil
+}
+
+func (c *stripeClientStub) CreateCheckoutSession(ctx context.Context, organizationID uuid.UUID, customerID, priceID, successURL, cancelURL string) (string, error) {
+	return "https://checkout.stripe.com/" + customerID + "/" + priceID, nil
+}
+
+func newTestBillingService(stripeClient StripeClient) (*BillingService, *InMemSubscriptionRepository, *PlanService, *LifecycleService) {
+	config := &Config{
+		DefaultPlan:         PlanFree,
+		StripeWebhookSecret: "whsec_test",
+		StripePrices:        "team:price_team,business:price_business",
+	}
+	subscriptionRepository := NewInMemSubscriptionRepository()
+	planService := NewPlanService(config, NewInMemPlanRepository())
+	lifecycleService := NewLifecycleService(config, NewInMemRepositoryTxer(), NewJobService(NewInMemRepositoryTxer(), NewInMemJobRepository()), NewInMemLifecycleRepository())
+	return NewBillingService(config, NewInMemRepositoryTxer(), subscriptionRepository, planService, lifecycleService, stripeClient), subscriptionRepository, planService, lifecycleService
+}
+
+func signStripePayload(payload, secret string, signedAt time.Time) string {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + stripeSignature([]byte(payload), timestamp, secret)
+}
+
+func TestVerifyStripeSignature(t *testing.T) {
+	is := is.New(t)
+
+	payload := []byte(`{"id":"evt_1"}`)
+	now := time.Now()
+
+	is.NoErr(verifyStripeSignature(payload, signStripePayload(string(payload), "whsec_test", now), "whsec_test", now))
+	is.Equal(verifyStripeSignature(payload, signStripePayload(string(payload), "whsec_other", now), "whsec_test", now), ErrWebhookSignatureInvalid)
+	is.Equal(verifyStripeSignature(payload, signStripePayload(string(payload), "whsec_test", now.Add(-10*time.Minute)), "whsec_test", now), ErrWebhookSignatureInvalid)
+	is.Equal(verifyStripeSignature([]byte(`{"id":"evt_2"}`), signStripePayload(string(payload), "whsec_test", now), "whsec_test", now), ErrWebhookSignatureInvalid)
+	is.Equal(verifyStripeSignature(payload, "", "whsec_test", now), ErrWebhookSignatureInvalid)
+}
+
+func TestCreateCheckout(t *testing.T) {
+	is := is.New(t)
+
+	stripeClient := &stripeClientStub{}
+	billingService, subscriptionRepository, _, _ := newTestBillingService(stripeClient)
+	principal := &Principal{OrganizationID: OrganizationIDSample, Username: "admin@baralga.com"}
+
+	checkoutURL, err := billingService.CreateCheckout(context.Background(), principal, PlanTeam)
+	is.NoErr(err)
+	is.Equal(checkoutURL, "https://checkout.stripe.com/cus_1/price_team")
+
+	subscription, err := subscriptionRepository.FindSubscriptionByOrganizationID(context.Background(), OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(subscription.CustomerID, "cus_1")
+
+	// customer is reused for further checkouts
+	_, err = billingService.CreateCheckout(context.Background(), principal, PlanBusiness)
+	is.NoErr(err)
+	is.Equal(stripeClient.customers, 1)
+
+	_, err = billingService.CreateCheckout(context.Background(), principal, PlanUnlimited)
+	is.Equal(err, ErrPlanNotPurchasable)
+
+	unconfiguredBillingService, _, _, _ := newTestBillingService(nil)
+	_, err = unconfiguredBillingService.CreateCheckout(context.Background(), principal, PlanTeam)
+	is.Equal(err, ErrBillingNotConfigured)
+}
+
+func TestHandleWebhook(t *testing.T) {
+	is := is.New(t)
+
+	billingService, subscriptionRepository, planService, lifecycleService := newTestBillingService(&stripeClientStub{})
+	now := time.Now()
+
+	sendEvent := func(eventType, object string, createdAt time.Time) error {
+		payload := fmt.Sprintf(`{"id":"evt_1","type":%q,"created":%v,"data":{"object":%s}}`, eventType, createdAt.Unix(), object)
+		return billingService.HandleWebhook(context.Background(), []byte(payload), signStripePayload(payload, "whsec_test", now))
+	}
+	statusOf := func() string {
+		lifecycle, err := lifecycleService.ReadLifecycle(context.Background(), OrganizationIDSample)
+		is.NoErr(err)
+		return lifecycle.Status
+	}
+	planOf := func() string {
+		plan, err := planService.PlanOf(context.Background(), OrganizationIDSample)
+		is.NoErr(err)
+		return plan.Name
+	}
+
+	t.Run("checkout links customer", func(t *testing.T) {
+		err := sendEvent(stripeEventCheckoutComplete, fmt.Sprintf(`{"client_reference_id":%q,"customer":"cus_1","subscription":"sub_1"}`, OrganizationIDSample), now)
+		is.NoErr(err)
+
+		subscription, err := subscriptionRepository.FindSubscriptionByCustomerID(context.Background(), "cus_1")
+		is.NoErr(err)
+		is.Equal(subscription.OrganizationID, OrganizationIDSample)
+		is.Equal(subscription.SubscriptionID, "sub_1")
+		is.Equal(planOf(), PlanFree)
+	})
+
+	t.Run("subscription upgrades plan", func(t *testing.T) {
+		err := sendEvent(stripeEventSubscriptionNew, `{"id":"sub_1","customer":"cus_1","status":"active","current_period_end":1893456000,"items":{"data":[{"price":{"id":"price_team"}}]}}`, now)
+		is.NoErr(err)
+		is.Equal(planOf(), PlanTeam)
+
+		err = sendEvent(stripeEventSubscriptionEdit, `{"id":"sub_1","customer":"cus_1","status":"active","items":{"data":[{"price":{"id":"price_business"}}]}}`, now.Add(time.Second))
+		is.NoErr(err)
+		is.Equal(planOf(), PlanBusiness)
+	})
+
+	t.Run("outdated event is ignored", func(t *testing.T) {
+		err := sendEvent(stripeEventSubscriptionEdit, `{"id":"sub_1","customer":"cus_1","status":"active","items":{"data":[{"price":{"id":"price_team"}}]}}`, now.Add(-time.Minute))
+		is.NoErr(err)
+		is.Equal(planOf(), PlanBusiness)
+	})
+
+	t.Run("failed payment keeps plan", func(t *testing.T) {
+		err := sendEvent(stripeEventPaymentFailed, `{"customer":"cus_1","subscription":"sub_1"}`, now.Add(2*time.Second))
+		is.NoErr(err)
+		is.Equal(planOf(), PlanBusiness)
+
+		subscription, err := subscriptionRepository.FindSubscriptionByCustomerID(context.Background(), "cus_1")
+		is.NoErr(err)
+		is.Equal(subscription.Status, SubscriptionStatusPastDue)
+		is.Equal(statusOf(), OrganizationStatusPastDue)
+	})
+
+	t.Run("canceled subscription downgrades to default plan", func(t *testing.T) {
+		err := sendEvent(stripeEventSubscriptionEnd, `{"id":"sub_1","customer":"cus_1","status":"canceled","items":{"data":[{"price":{"id":"price_business"}}]}}`, now.Add(3*time.Second))
+		is.NoErr(err)
+		is.Equal(planOf(), PlanFree)
+		is.Equal(statusOf(), OrganizationStatusCancelled)
+	})
+
+	t.Run("unknown customer is ignored", func(t *testing.T) {
+		err := sendEvent(stripeEventSubscriptionEdit, `{"id":"sub_2","customer":"cus_unknown","status":"active"}`, now)
+		is.NoErr(err)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		err := billingService.HandleWebhook(context.Background(), []byte(`{}`), "t=1,v1=abc")
+		is.Equal(err, ErrWebhookSignatureInvalid)
+	})
+}
@@ -0,0 +1,200 @@
+package shared
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	stripeAPIURL                = "https://api.stripe.com/v1"
+	stripeSignatureTolerance    = 5 * time.Minute
+	StripeSignatureHeader       = "Stripe-Signature"
+	stripeEventCheckoutComplete = "checkout.session.completed"
+	stripeEventSubscriptionNew  = "customer.subscription.created"
+	stripeEventSubscriptionEdit = "customer.subscription.updated"
+	stripeEventSubscriptionEnd  = "customer.subscription.deleted"
+	stripeEventPaymentFailed    = "invoice.payment_failed"
+)
+
+// StripeClient manages customers and checkouts of subscriptions at Stripe
+type StripeClient interface {
+	CreateCustomer(ctx context.Context, organizationID uuid.UUID, email string) (string, error)
+	CreateCheckoutSession(ctx context.Context, organizationID uuid.UUID, customerID, priceID, successURL, cancelURL string) (string, error)
+}
+
+// NewStripeClient creates a client for the Stripe api, nil if billing is not configured
+func NewStripeClient(config *Config) StripeClient {
+	if config.StripeSecretKey == "" {
+		return nil
+	}
+	return &httpStripeClient{
+		apiURL:     stripeAPIURL,
+		secretKey:  config.StripeSecretKey,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type httpStripeClient struct {
+	apiURL     string
+	secretKey  string
+	httpClient *http.Client
+}
+
+type stripeObject struct {
+	ID    string `json:"id"`
+	URL   string `json:"url"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// stripeEvent is an event sent to the webhook by Stripe
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type stripeCheckoutSession struct {
+	ClientReferenceID string `json:"client_reference_id"`
+	Customer          string `json:"customer"`
+	Subscription      string `json:"subscription"`
+}
+
+type stripeSubscription struct {
+	ID               string `json:"id"`
+	Customer         string `json:"customer"`
+	Status           string `json:"status"`
+	CurrentPeriodEnd int64  `json:"current_period_end"`
+	Items            struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+type stripeInvoice struct {
+	Customer     string `json:"customer"`
+	Subscription string `json:"subscription"`
+}
+
+func (c *httpStripeClient) CreateCustomer(ctx context.Context, organizationID uuid.UUID, email string) (string, error) {
+	form := url.Values{}
+	form.Set("email", email)
+	form.Set("metadata[org_id]", organizationID.String())
+
+	customer, err := c.post(ctx, "/customers", form)
+	if err != nil {
+		return "", errors.Wrap(err, "could not create stripe customer")
+	}
+	return customer.ID, nil
+}
+
+func (c *httpStripeClient) CreateCheckoutSession(ctx context.Context, organizationID uuid.UUID, customerID, priceID, successURL, cancelURL string) (string, error) {
+	form := url.Values{}
+	form.Set("mode", "subscription")
+	form.Set("customer", customerID)
+	form.Set("client_reference_id", organizationID.String())
+	form.Set("line_items[0][price]", priceID)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("subscription_data[metadata][org_id]", organizationID.String())
+	form.Set("success_url", successURL)
+	form.Set("cancel_url", cancelURL)
+
+	session, err := c.post(ctx, "/checkout/sessions", form)
+	if err != nil {
+		return "", errors.Wrap(err, "could not create stripe checkout session")
+	}
+	return session.URL, nil
+}
+
+func (c *httpStripeClient) post(ctx context.Context, path string, form url.Values) (*stripeObject, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.secretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var object stripeObject
+	err = json.NewDecoder(res.Body).Decode(&object)
+	if err != nil {
+		return nil, err
+	}
+
+	if object.Error != nil {
+		return nil, errors.Errorf("stripe responded with status %v: %s", res.StatusCode, object.Error.Message)
+	}
+	if res.StatusCode >= 300 {
+		return nil, errors.Errorf("stripe responded with status %v", res.StatusCode)
+	}
+	return &object, nil
+}
+
+// verifyStripeSignature checks the signature header of a webhook request like t=1492774577,v1=5257a869...
+func verifyStripeSignature(payload []byte, signatureHeader, secret string, now time.Time) error {
+	var (
+		timestamp  string
+		signatures []string
+	)
+	for _, item := range strings.Split(signatureHeader, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrWebhookSignatureInvalid
+	}
+
+	signedAt := time.Unix(seconds, 0)
+	if now.Sub(signedAt) > stripeSignatureTolerance || signedAt.Sub(now) > stripeSignatureTolerance {
+		return ErrWebhookSignatureInvalid
+	}
+
+	expected := stripeSignature(payload, timestamp, secret)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrWebhookSignatureInvalid
+}
+
+func stripeSignature(payload []byte, timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,91 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxBrandingLogoSize is the maximum size of uploaded logos
+	maxBrandingLogoSize = 512 << 10
+
+	// maxInvoiceFooterLength is the maximum length of the invoice footer text
+	maxInvoiceFooterLength = 500
+)
+
+// brandingLogoContentTypes are the image formats of logos, both can be embedded in PDFs
+var brandingLogoContentTypes = []string{"image/png", "image/jpeg"}
+
+var (
+	ErrBrandingNotFound         = NewDomainError("branding:not-found", http.StatusNotFound, "branding not found")
+	ErrBrandingNotValid         = NewDomainError("branding:not-valid", http.StatusBadRequest, "branding not valid")
+	ErrBrandingLogoNotFound     = NewDomainError("branding:logo-not-found", http.StatusNotFound, "logo not found")
+	ErrBrandingLogoNotSupported = NewDomainError("branding:logo-not-supported", http.StatusUnsupportedMediaType, "logo must be a png or jpeg image")
+)
+
+// Branding is how an organization presents itself in the web pages, generated PDFs and outgoing mails
+type Branding struct {
+	OrganizationID uuid.UUID
+
+	// PrimaryColor is a hex color like #5b3cc4, empty for the default color
+	PrimaryColor string
+
+	// InvoiceFooter is printed at the bottom of generated PDFs and appended to outgoing mails
+	InvoiceFooter string
+
+	LogoKey         string
+	LogoContentType string
+	LogoSize        int64
+	UpdatedAt       *time.Time
+}
+
+type BrandingRepository interface {
+	FindBranding(ctx context.Context, organizationID uuid.UUID) (*Branding, error)
+	UpsertBranding(ctx context.Context, branding *Branding) error
+}
+
+// HasLogo checks whether the organization uploaded a logo
+func (b *Branding) HasLogo() bool {
+	return b.LogoKey != ""
+}
+
+// LogoURL is the url of the logo at the path in the web pages, it changes with every update so browsers don't show a stale logo
+func (b *Branding) LogoURL(logoPath string) string {
+	if !b.HasLogo() {
+		return ""
+	}
+
+	version := int64(0)
+	if b.UpdatedAt != nil {
+		version = b.UpdatedAt.Unix()
+	}
+	return logoPath + "?v=" + strconv.FormatInt(version, 10)
+}
+
+// PrimaryColorRGB is the primary color as red, green and blue, black if there is no primary color
+func (b *Branding) PrimaryColorRGB() (uint8, uint8, uint8) {
+	if !accentColorPattern.MatchString(b.PrimaryColor) {
+		return 0, 0, 0
+	}
+
+	rgb, _ := strconv.ParseUint(b.PrimaryColor[1:], 16, 32)
+	return uint8(rgb >> 16), uint8(rgb >> 8), uint8(rgb)
+}
+
+// Validate checks the primary color and the length of the invoice footer
+func (b *Branding) Validate() error {
+	if !IsValidAccentColor(b.PrimaryColor) || len([]rune(b.InvoiceFooter)) > maxInvoiceFooterLength {
+		return ErrBrandingNotValid
+	}
+	return nil
+}
+
+// brandingLogoStorageKeyOf is the key of the logo of the organization in the storage
+func brandingLogoStorageKeyOf(organizationID uuid.UUID) string {
+	return fmt.Sprintf("branding/%s/logo", organizationID)
+}
@@ -0,0 +1,38 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestBrandingPrimaryColorRGB(t *testing.T) {
+	is := is.New(t)
+
+	r, g, b := (&Branding{PrimaryColor: "#5b3cc4"}).PrimaryColorRGB()
+	is.Equal([]uint8{r, g, b}, []uint8{0x5b, 0x3c, 0xc4})
+
+	r, g, b = (&Branding{}).PrimaryColorRGB()
+	is.Equal([]uint8{r, g, b}, []uint8{0, 0, 0})
+}
+
+func TestBrandingLogoURL(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal((&Branding{}).LogoURL("/branding/logo"), "")
+
+	updatedAt := time.Unix(1700000000, 0)
+	branding := &Branding{LogoKey: brandingLogoStorageKeyOf(OrganizationIDSample), UpdatedAt: &updatedAt}
+	is.Equal(branding.LogoURL("/branding/logo"), "/branding/logo?v=1700000000")
+}
+
+func TestBrandingValidate(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr((&Branding{PrimaryColor: "#5B3CC4", InvoiceFooter: "ACME Corp."}).Validate())
+	is.NoErr((&Branding{}).Validate())
+	is.Equal((&Branding{PrimaryColor: "red"}).Validate(), ErrBrandingNotValid)
+	is.Equal((&Branding{InvoiceFooter: strings.Repeat("a", maxInvoiceFooterLength+1)}).Validate(), ErrBrandingNotValid)
+}
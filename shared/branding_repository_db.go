@@ -0,0 +1,93 @@
+package shared
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbBrandingRepository is a SQL database repository for the branding of organizations
+type DbBrandingRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ BrandingRepository = (*DbBrandingRepository)(nil)
+
+// NewDbBrandingRepository creates a new SQL database repository for the branding of organizations
+func NewDbBrandingRepository(connPool *pgxpool.Pool) *DbBrandingRepository {
+	return &DbBrandingRepository{
+		connPool: connPool,
+	}
+}
+
+// FindBranding reads the branding of the organization
+func (r *DbBrandingRepository) FindBranding(ctx context.Context, organizationID uuid.UUID) (*Branding, error) {
+	row, err := SelectOne[brandingRow](
+		ctx,
+		r.connPool,
+		`SELECT `+Columns[brandingRow]()+`
+		 FROM organization_brandings
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBrandingNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toBranding(), nil
+}
+
+// UpsertBranding sets the branding of the organization
+func (r *DbBrandingRepository) UpsertBranding(ctx context.Context, branding *Branding) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO organization_brandings
+		   (org_id, primary_color, invoice_footer, logo_key, logo_content_type, logo_size, updated_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (org_id) DO UPDATE
+		 SET primary_color = EXCLUDED.primary_color, invoice_footer = EXCLUDED.invoice_footer,
+		     logo_key = EXCLUDED.logo_key, logo_content_type = EXCLUDED.logo_content_type,
+		     logo_size = EXCLUDED.logo_size, updated_at = EXCLUDED.updated_at`,
+		branding.OrganizationID,
+		branding.PrimaryColor,
+		branding.InvoiceFooter,
+		branding.LogoKey,
+		branding.LogoContentType,
+		branding.LogoSize,
+		branding.UpdatedAt,
+	)
+	return err
+}
+
+type brandingRow struct {
+	OrganizationID  uuid.UUID  `db:"org_id"`
+	PrimaryColor    string     `db:"primary_color"`
+	InvoiceFooter   string     `db:"invoice_footer"`
+	LogoKey         string     `db:"logo_key"`
+	LogoContentType string     `db:"logo_content_type"`
+	LogoSize        int64      `db:"logo_size"`
+	UpdatedAt       *time.Time `db:"updated_at"`
+}
+
+func (r *brandingRow) toBranding() *Branding {
+	return &Branding{
+		OrganizationID:  r.OrganizationID,
+		PrimaryColor:    r.PrimaryColor,
+		InvoiceFooter:   r.InvoiceFooter,
+		LogoKey:         r.LogoKey,
+		LogoContentType: r.LogoContentType,
+		LogoSize:        r.LogoSize,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
@@ -0,0 +1,68 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestBrandingRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	brandingRepository := NewDbBrandingRepository(connPool)
+	repositoryTxer := NewDbRepositoryTxer(connPool)
+
+	t.Run("FindBrandingWithoutBranding", func(t *testing.T) {
+		_, err := brandingRepository.FindBranding(context.Background(), OrganizationIDSample)
+		is.Equal(err, ErrBrandingNotFound)
+	})
+
+	t.Run("UpsertBranding", func(t *testing.T) {
+		for _, primaryColor := range []string{"#5b3cc4", "#ff0000"} {
+			updatedAt := time.Now().UTC().Truncate(time.Second)
+			err := repositoryTxer.InTx(
+				context.Background(),
+				func(ctx context.Context) error {
+					return brandingRepository.UpsertBranding(ctx, &Branding{
+						OrganizationID:  OrganizationIDSample,
+						PrimaryColor:    primaryColor,
+						InvoiceFooter:   "ACME Corp.",
+						LogoKey:         brandingLogoStorageKeyOf(OrganizationIDSample),
+						LogoContentType: "image/png",
+						LogoSize:        1024,
+						UpdatedAt:       &updatedAt,
+					})
+				},
+			)
+			is.NoErr(err)
+
+			branding, err := brandingRepository.FindBranding(context.Background(), OrganizationIDSample)
+			is.NoErr(err)
+			is.Equal(branding.PrimaryColor, primaryColor)
+			is.Equal(branding.InvoiceFooter, "ACME Corp.")
+			is.True(branding.HasLogo())
+			is.Equal(branding.LogoSize, int64(1024))
+		}
+	})
+}
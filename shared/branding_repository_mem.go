@@ -0,0 +1,48 @@
+package shared
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemBrandingRepository struct {
+	mu        sync.Mutex
+	brandings []*Branding
+}
+
+var _ BrandingRepository = (*InMemBrandingRepository)(nil)
+
+func NewInMemBrandingRepository() *InMemBrandingRepository {
+	return &InMemBrandingRepository{}
+}
+
+func (r *InMemBrandingRepository) FindBranding(ctx context.Context, organizationID uuid.UUID) (*Branding, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, b := range r.brandings {
+		if b.OrganizationID == organizationID {
+			branding := *b
+			return &branding, nil
+		}
+	}
+	return nil, ErrBrandingNotFound
+}
+
+func (r *InMemBrandingRepository) UpsertBranding(ctx context.Context, branding *Branding) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	upserted := *branding
+	for i, b := range r.brandings {
+		if b.OrganizationID == branding.OrganizationID {
+			r.brandings[i] = &upserted
+			return nil
+		}
+	}
+
+	r.brandings = append(r.brandings, &upserted)
+	return nil
+}
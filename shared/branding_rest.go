@@ -0,0 +1,192 @@
+package shared
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type brandingModel struct {
+	PrimaryColor  string     `json:"primaryColor" validate:"omitempty,hexcolor,len=7"`
+	InvoiceFooter string     `json:"invoiceFooter" validate:"max=500"`
+	HasLogo       bool       `json:"hasLogo"`
+	Links         *hal.Links `json:"_links,omitempty"`
+}
+
+type BrandingRestHandlers struct {
+	config          *Config
+	brandingService *BrandingService
+}
+
+func NewBrandingRestHandlers(config *Config, brandingService *BrandingService) *BrandingRestHandlers {
+	return &BrandingRestHandlers{
+		config:          config,
+		brandingService: brandingService,
+	}
+}
+
+func (a *BrandingRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/branding", a.HandleGetBranding())
+	r.Put("/branding", a.HandleUpdateBranding())
+	r.Get("/branding/logo", a.HandleGetLogo())
+	r.Put("/branding/logo", a.HandleUploadLogo())
+	r.Delete("/branding/logo", a.HandleDeleteLogo())
+}
+
+func (a *BrandingRestHandlers) RegisterOpen(r chi.Router) {
+	r.Get("/custom-domain/branding", a.HandleGetCustomDomainBranding())
+}
+
+// HandleGetBranding reads the branding of the principal's organization
+func (a *BrandingRestHandlers) HandleGetBranding() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	brandingService := a.brandingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		branding, err := brandingService.ReadBranding(r.Context(), principal.OrganizationID)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToBrandingModel(branding))
+	}
+}
+
+// HandleGetCustomDomainBranding reads the branding of the organization of the custom domain the request was
+// sent to, so the client portal is branded before login
+func (a *BrandingRestHandlers) HandleGetCustomDomainBranding() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	brandingService := a.brandingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		customDomain := CustomDomainOf(r.Context())
+		if customDomain == nil {
+			RenderProblemJSON(w, isProduction, ErrCustomDomainNotFound)
+			return
+		}
+
+		branding, err := brandingService.ReadBranding(r.Context(), customDomain.OrganizationID)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		brandingModel := mapToBrandingModel(branding)
+		links := []*hal.Links{hal.NewSelfLink("/api/custom-domain/branding")}
+		if branding.HasLogo() {
+			links = append(links, hal.NewLink("logo", branding.LogoURL(customDomainLogoPath)))
+		}
+		brandingModel.Links = hal.NewLinks(links...)
+		RenderJSON(w, brandingModel)
+	}
+}
+
+// HandleUpdateBranding sets the primary color and invoice footer of the principal's organization
+func (a *BrandingRestHandlers) HandleUpdateBranding() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := NewValidator()
+	brandingService := a.brandingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		var brandingModel brandingModel
+		err := json.NewDecoder(r.Body).Decode(&brandingModel)
+		if err != nil {
+			RenderValidationProblemJSON(w, "branding not valid", err)
+			return
+		}
+
+		err = validator.Struct(brandingModel)
+		if err != nil {
+			RenderValidationProblemJSON(w, "branding not valid", err)
+			return
+		}
+
+		branding, err := brandingService.UpdateBranding(r.Context(), principal, brandingModel.PrimaryColor, brandingModel.InvoiceFooter)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToBrandingModel(branding))
+	}
+}
+
+// HandleGetLogo streams the logo of the principal's organization
+func (a *BrandingRestHandlers) HandleGetLogo() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	brandingService := a.brandingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		branding, content, err := brandingService.OpenLogo(r.Context(), principal.OrganizationID)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+		defer content.Close()
+
+		renderLogo(w, branding, content)
+	}
+}
+
+// HandleUploadLogo uploads the logo of the principal's organization, the image is the raw request body
+func (a *BrandingRestHandlers) HandleUploadLogo() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	brandingService := a.brandingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		logo := http.MaxBytesReader(w, r.Body, maxBrandingLogoSize)
+		branding, err := brandingService.UploadLogo(r.Context(), principal, logo, r.Header.Get("Content-Type"))
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToBrandingModel(branding))
+	}
+}
+
+// HandleDeleteLogo removes the logo of the principal's organization
+func (a *BrandingRestHandlers) HandleDeleteLogo() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	brandingService := a.brandingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		err := brandingService.DeleteLogo(r.Context(), principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func renderLogo(w http.ResponseWriter, branding *Branding, content io.Reader) {
+	w.Header().Set("Content-Type", branding.LogoContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(branding.LogoSize, 10))
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	_, _ = io.Copy(w, content)
+}
+
+func mapToBrandingModel(branding *Branding) *brandingModel {
+	links := []*hal.Links{hal.NewSelfLink("/api/branding")}
+	if branding.HasLogo() {
+		links = append(links, hal.NewLink("logo", "/api/branding/logo"))
+	}
+
+	return &brandingModel{
+		PrimaryColor:  branding.PrimaryColor,
+		InvoiceFooter: branding.InvoiceFooter,
+		HasLogo:       branding.HasLogo(),
+		Links:         hal.NewLinks(links...),
+	}
+}
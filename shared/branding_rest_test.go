@@ -0,0 +1,92 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestBrandingRestHandlers(t *testing.T) {
+	is := is.New(t)
+
+	a := NewBrandingRestHandlers(&Config{}, newInMemBrandingService())
+	router := chi.NewRouter()
+	a.RegisterProtected(router)
+
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	request := func(method, path string, body *strings.Reader, contentType string, principal *Principal) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest(method, path, body)
+		r.Header.Set("Content-Type", contentType)
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, principal))
+		router.ServeHTTP(httpRec, r)
+		return httpRec
+	}
+
+	t.Run("update branding", func(t *testing.T) {
+		httpRec := request("PUT", "/branding", strings.NewReader(`{"primaryColor": "#5b3cc4", "invoiceFooter": "ACME Corp."}`), "application/json", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		httpRec = request("GET", "/branding", strings.NewReader(""), "", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		brandingModel := &brandingModel{}
+		err := json.NewDecoder(httpRec.Body).Decode(brandingModel)
+		is.NoErr(err)
+		is.Equal(brandingModel.PrimaryColor, "#5b3cc4")
+		is.Equal(brandingModel.InvoiceFooter, "ACME Corp.")
+		is.True(!brandingModel.HasLogo)
+	})
+
+	t.Run("update branding not valid", func(t *testing.T) {
+		httpRec := request("PUT", "/branding", strings.NewReader(`{"primaryColor": "red"}`), "application/json", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("user must not update branding", func(t *testing.T) {
+		httpRec := request("PUT", "/branding", strings.NewReader(`{"primaryColor": "#5b3cc4"}`), "application/json", &Principal{OrganizationID: OrganizationIDSample, Roles: []string{"ROLE_USER"}})
+		is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	})
+
+	t.Run("upload, get and delete logo", func(t *testing.T) {
+		logo := newBrandingLogoSample(t)
+
+		httpRec := request("PUT", "/branding/logo", strings.NewReader(logo.String()), "image/png", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		httpRec = request("GET", "/branding/logo", strings.NewReader(""), "", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Header().Get("Content-Type"), "image/png")
+		is.Equal(httpRec.Body.String(), logo.String())
+
+		httpRec = request("DELETE", "/branding/logo", strings.NewReader(""), "", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+
+		httpRec = request("GET", "/branding/logo", strings.NewReader(""), "", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+	})
+
+	t.Run("upload logo not supported", func(t *testing.T) {
+		httpRec := request("PUT", "/branding/logo", strings.NewReader("<svg/>"), "image/svg+xml", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusUnsupportedMediaType)
+	})
+}
+
+func TestBrandingWebHandlersLogoNotFound(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewBrandingWebHandlers(&Config{}, newInMemBrandingService())
+
+	r, _ := http.NewRequest("GET", "/branding/logo", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{OrganizationID: OrganizationIDSample}))
+
+	a.HandleLogo()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
@@ -0,0 +1,252 @@
+package shared
+
+import (
+	"context"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/baralga/shared/pdf"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// BrandingService reads and updates the branding of organizations, which is applied to
+// the web pages, generated PDFs and outgoing mails
+type BrandingService struct {
+	repositoryTxer     RepositoryTxer
+	storage            Storage
+	scanService        *ScanService
+	brandingRepository BrandingRepository
+}
+
+// NewBrandingService creates a new service for the branding of organizations
+func NewBrandingService(repositoryTxer RepositoryTxer, storage Storage, scanService *ScanService, brandingRepository BrandingRepository) *BrandingService {
+	return &BrandingService{
+		repositoryTxer:     repositoryTxer,
+		storage:            storage,
+		scanService:        scanService,
+		brandingRepository: brandingRepository,
+	}
+}
+
+// ReadBranding reads the branding of the organization, an empty branding if the organization has none
+func (s *BrandingService) ReadBranding(ctx context.Context, organizationID uuid.UUID) (*Branding, error) {
+	branding, err := s.brandingRepository.FindBranding(ctx, organizationID)
+	if errors.Is(err, ErrBrandingNotFound) {
+		return &Branding{OrganizationID: organizationID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return branding, nil
+}
+
+// UpdateBranding sets the primary color and invoice footer of the principal's organization, the logo is kept
+func (s *BrandingService) UpdateBranding(ctx context.Context, principal *Principal, primaryColor, invoiceFooter string) (*Branding, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrForbidden
+	}
+
+	branding, err := s.ReadBranding(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	branding.PrimaryColor = primaryColor
+	branding.InvoiceFooter = invoiceFooter
+	err = branding.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	branding.UpdatedAt = &now
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.brandingRepository.UpsertBranding(ctx, branding)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return branding, nil
+}
+
+// UploadLogo scans and stores the logo of the principal's organization, it replaces the previous logo
+func (s *BrandingService) UploadLogo(ctx context.Context, principal *Principal, logo io.Reader, contentType string) (*Branding, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrForbidden
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !slices.Contains(brandingLogoContentTypes, mediaType) {
+		return nil, ErrBrandingLogoNotSupported
+	}
+
+	branding, err := s.ReadBranding(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := BufferUpload(logo)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	err = s.scanService.ScanUpload(ctx, &Upload{
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		FileName:       "logo",
+		Content:        content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the logo is decoded for PDFs later on, so broken images are rejected right away
+	_, format, err := image.DecodeConfig(content)
+	if err != nil || "image/"+format != mediaType {
+		return nil, ErrBrandingLogoNotSupported
+	}
+	_, err = content.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := content.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	logoKey := brandingLogoStorageKeyOf(principal.OrganizationID)
+	err = s.storage.Put(ctx, logoKey, content, info.Size(), mediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	branding.LogoKey = logoKey
+	branding.LogoContentType = mediaType
+	branding.LogoSize = info.Size()
+	branding.UpdatedAt = &now
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.brandingRepository.UpsertBranding(ctx, branding)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return branding, nil
+}
+
+// DeleteLogo removes the logo of the principal's organization, so the default logo is shown again
+func (s *BrandingService) DeleteLogo(ctx context.Context, principal *Principal) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return ErrForbidden
+	}
+
+	branding, err := s.ReadBranding(ctx, principal.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if !branding.HasLogo() {
+		return ErrBrandingLogoNotFound
+	}
+
+	logoKey := branding.LogoKey
+	now := time.Now()
+	branding.LogoKey = ""
+	branding.LogoContentType = ""
+	branding.LogoSize = 0
+	branding.UpdatedAt = &now
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.brandingRepository.UpsertBranding(ctx, branding)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.Delete(ctx, logoKey)
+}
+
+// OpenLogo opens the logo of the organization
+func (s *BrandingService) OpenLogo(ctx context.Context, organizationID uuid.UUID) (*Branding, io.ReadCloser, error) {
+	branding, err := s.ReadBranding(ctx, organizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !branding.HasLogo() {
+		return nil, nil, ErrBrandingLogoNotFound
+	}
+
+	content, err := s.storage.Get(ctx, branding.LogoKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return branding, content, nil
+}
+
+// ReadLogoImage reads the logo of the organization as image for PDFs
+func (s *BrandingService) ReadLogoImage(ctx context.Context, organizationID uuid.UUID) (*pdf.Image, error) {
+	_, content, err := s.OpenLogo(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	return pdf.NewImage(content)
+}
+
+// BrandingMiddleware applies the branding of the principal's organization to the appearance in the
+// request context, so it must follow the appearance middleware. The accent color preferred by the
+// user wins over the primary color of the organization. Requests without principal to a custom
+// domain like the login get the branding of the organization of the domain.
+func (s *BrandingService) BrandingMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var organizationID uuid.UUID
+			logoPath := "/branding/logo"
+			if principal, ok := r.Context().Value(ContextKeyPrincipal).(*Principal); ok && principal != nil {
+				organizationID = principal.OrganizationID
+			} else if customDomain := CustomDomainOf(r.Context()); customDomain != nil {
+				organizationID = customDomain.OrganizationID
+				logoPath = customDomainLogoPath
+			} else {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			branding, err := s.ReadBranding(r.Context(), organizationID)
+			if err != nil {
+				log.Printf("could not read branding of organization %s: %s", organizationID, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			appearance := *AppearanceOf(r.Context())
+			if appearance.AccentColor == "" {
+				appearance.AccentColor = branding.PrimaryColor
+			}
+			appearance.LogoURL = branding.LogoURL(logoPath)
+
+			ctx := context.WithValue(r.Context(), ContextKeyAppearance, &appearance)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func newInMemBrandingService() *BrandingService {
+	storage := NewInMemStorage()
+	return NewBrandingService(
+		NewInMemRepositoryTxer(),
+		storage,
+		NewScanService(&Config{}, NewInMemRepositoryTxer(), NewInMemOutbox(NewInMemMailResource()), storage, nil),
+		NewInMemBrandingRepository(),
+	)
+}
+
+func newBrandingLogoSample(t *testing.T) *bytes.Buffer {
+	logo := &bytes.Buffer{}
+	err := png.Encode(logo, image.NewRGBA(image.Rect(0, 0, 4, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return logo
+}
+
+func TestReadBrandingWithoutBranding(t *testing.T) {
+	is := is.New(t)
+
+	brandingService := newInMemBrandingService()
+
+	branding, err := brandingService.ReadBranding(context.Background(), OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(branding.OrganizationID, OrganizationIDSample)
+	is.Equal(branding.PrimaryColor, "")
+	is.True(!branding.HasLogo())
+}
+
+func TestUpdateBranding(t *testing.T) {
+	is := is.New(t)
+
+	brandingService := newInMemBrandingService()
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	t.Run("user must not update branding", func(t *testing.T) {
+		_, err := brandingService.UpdateBranding(context.Background(), &Principal{OrganizationID: OrganizationIDSample, Roles: []string{"ROLE_USER"}}, "#5b3cc4", "")
+		is.Equal(err, ErrForbidden)
+	})
+
+	t.Run("admin updates branding", func(t *testing.T) {
+		_, err := brandingService.UpdateBranding(context.Background(), admin, "#5b3cc4", "ACME Corp.")
+		is.NoErr(err)
+
+		branding, err := brandingService.ReadBranding(context.Background(), OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(branding.PrimaryColor, "#5b3cc4")
+		is.Equal(branding.InvoiceFooter, "ACME Corp.")
+		is.True(branding.UpdatedAt != nil)
+	})
+
+	t.Run("primary color not valid", func(t *testing.T) {
+		_, err := brandingService.UpdateBranding(context.Background(), admin, "red", "")
+		is.Equal(err, ErrBrandingNotValid)
+	})
+}
+
+func TestUploadLogo(t *testing.T) {
+	is := is.New(t)
+
+	brandingService := newInMemBrandingService()
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	t.Run("user must not upload logo", func(t *testing.T) {
+		_, err := brandingService.UploadLogo(context.Background(), &Principal{OrganizationID: OrganizationIDSample, Roles: []string{"ROLE_USER"}}, newBrandingLogoSample(t), "image/png")
+		is.Equal(err, ErrForbidden)
+	})
+
+	t.Run("logo must be png or jpeg", func(t *testing.T) {
+		_, err := brandingService.UploadLogo(context.Background(), admin, strings.NewReader("<svg/>"), "image/svg+xml")
+		is.Equal(err, ErrBrandingLogoNotSupported)
+	})
+
+	t.Run("logo must be an image", func(t *testing.T) {
+		_, err := brandingService.UploadLogo(context.Background(), admin, strings.NewReader("no image"), "image/png")
+		is.Equal(err, ErrBrandingLogoNotSupported)
+	})
+
+	t.Run("admin uploads logo", func(t *testing.T) {
+		logo := newBrandingLogoSample(t)
+		size := int64(logo.Len())
+
+		_, err := brandingService.UpdateBranding(context.Background(), admin, "#5b3cc4", "")
+		is.NoErr(err)
+
+		branding, err := brandingService.UploadLogo(context.Background(), admin, logo, "image/png")
+		is.NoErr(err)
+		is.True(branding.HasLogo())
+		is.Equal(branding.LogoSize, size)
+		is.Equal(branding.PrimaryColor, "#5b3cc4")
+
+		_, content, err := brandingService.OpenLogo(context.Background(), OrganizationIDSample)
+		is.NoErr(err)
+		defer content.Close()
+		stored, _ := io.ReadAll(content)
+		is.Equal(int64(len(stored)), size)
+
+		logoImage, err := brandingService.ReadLogoImage(context.Background(), OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(logoImage.AspectRatio(), 2.0)
+	})
+
+	t.Run("admin deletes logo", func(t *testing.T) {
+		err := brandingService.DeleteLogo(context.Background(), admin)
+		is.NoErr(err)
+
+		_, _, err = brandingService.OpenLogo(context.Background(), OrganizationIDSample)
+		is.Equal(err, ErrBrandingLogoNotFound)
+
+		err = brandingService.DeleteLogo(context.Background(), admin)
+		is.Equal(err, ErrBrandingLogoNotFound)
+	})
+}
+
+func TestBrandingMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	brandingService := newInMemBrandingService()
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := brandingService.UpdateBranding(context.Background(), admin, "#5b3cc4", "")
+	is.NoErr(err)
+	_, err = brandingService.UploadLogo(context.Background(), admin, newBrandingLogoSample(t), "image/png")
+	is.NoErr(err)
+
+	var appearance *Appearance
+	handler := brandingService.BrandingMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		appearance = AppearanceOf(r.Context())
+	}))
+
+	t.Run("primary color of organization", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, admin))
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		is.Equal(appearance.Theme, ThemeDark)
+		is.Equal(appearance.AccentColor, "#5b3cc4")
+		is.True(strings.HasPrefix(appearance.LogoURL, "/branding/logo?v="))
+	})
+
+	t.Run("accent color of user wins", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/", nil)
+		ctx := context.WithValue(r.Context(), ContextKeyPrincipal, admin)
+		ctx = context.WithValue(ctx, ContextKeyAppearance, &Appearance{Theme: ThemeLight, AccentColor: "#ff0000"})
+		handler.ServeHTTP(httptest.NewRecorder(), r.WithContext(ctx))
+
+		is.Equal(appearance.Theme, ThemeLight)
+		is.Equal(appearance.AccentColor, "#ff0000")
+	})
+
+	t.Run("without principal", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/", nil)
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		is.Equal(appearance.LogoURL, "")
+	})
+}
+
+func TestBrandedOutbox(t *testing.T) {
+	is := is.New(t)
+
+	brandingRepository := NewInMemBrandingRepository()
+	mailResource := NewInMemMailResource()
+	outbox := NewBrandedOutbox(NewInMemOutbox(mailResource), brandingRepository)
+
+	err := outbox.SendMail(context.Background(), OrganizationIDSample, "user1@baralga.com", "Digest", "Hello")
+	is.NoErr(err)
+
+	err = brandingRepository.UpsertBranding(context.Background(), &Branding{OrganizationID: OrganizationIDSample, InvoiceFooter: "ACME Corp."})
+	is.NoErr(err)
+
+	err = outbox.SendMail(context.Background(), OrganizationIDSample, "user1@baralga.com", "Digest", "Hello")
+	is.NoErr(err)
+
+	is.Equal(len(mailResource.Mails), 2)
+	is.True(!strings.Contains(mailResource.Mails[0], "ACME Corp."))
+	is.True(strings.Contains(mailResource.Mails[1], "Hello\n\n--\nACME Corp."))
+}
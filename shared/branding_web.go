@@ -0,0 +1,73 @@
+package shared
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// customDomainLogoPath is the logo of the organization of the custom domain, shown before login
+const customDomainLogoPath = "/custom-domain/logo"
+
+type BrandingWebHandlers struct {
+	config          *Config
+	brandingService *BrandingService
+}
+
+func NewBrandingWebHandlers(config *Config, brandingService *BrandingService) *BrandingWebHandlers {
+	return &BrandingWebHandlers{
+		config:          config,
+		brandingService: brandingService,
+	}
+}
+
+func (a *BrandingWebHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/branding/logo", a.HandleLogo())
+}
+
+func (a *BrandingWebHandlers) RegisterOpen(r chi.Router) {
+	r.Get(customDomainLogoPath, a.HandleCustomDomainLogo())
+}
+
+// HandleLogo serves the logo of the principal's organization shown in the navbar
+func (a *BrandingWebHandlers) HandleLogo() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	brandingService := a.brandingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		renderLogoOf(w, r, isProduction, brandingService, principal.OrganizationID)
+	}
+}
+
+// HandleCustomDomainLogo serves the logo of the organization of the custom domain the request was sent to
+func (a *BrandingWebHandlers) HandleCustomDomainLogo() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	brandingService := a.brandingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		customDomain := CustomDomainOf(r.Context())
+		if customDomain == nil {
+			http.Error(w, ErrBrandingLogoNotFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		renderLogoOf(w, r, isProduction, brandingService, customDomain.OrganizationID)
+	}
+}
+
+func renderLogoOf(w http.ResponseWriter, r *http.Request, isProduction bool, brandingService *BrandingService, organizationID uuid.UUID) {
+	branding, content, err := brandingService.OpenLogo(r.Context(), organizationID)
+	if errors.Is(err, ErrBrandingLogoNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		RenderProblemHTML(w, isProduction, err)
+		return
+	}
+	defer content.Close()
+
+	renderLogo(w, branding, content)
+}
@@ -0,0 +1,263 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	g "github.com/maragudk/gomponents"
+	ghx "github.com/maragudk/gomponents-htmx"
+	. "github.com/maragudk/gomponents/html"
+	"github.com/pkg/errors"
+)
+
+const (
+	CaptchaProviderHCaptcha  = "hcaptcha"
+	CaptchaProviderTurnstile = "turnstile"
+)
+
+var (
+	ErrCaptchaRequired = NewDomainError("auth:captcha-required", http.StatusForbidden, "captcha required")
+	ErrCaptchaInvalid  = NewDomainError("auth:captcha-invalid", http.StatusForbidden, "captcha invalid")
+)
+
+// CaptchaVerifier verifies the response of a captcha solved by the user
+type CaptchaVerifier interface {
+	// Verify checks the response of the captcha with the provider
+	Verify(ctx context.Context, response, remoteIP string) error
+
+	// ResponseField is the name of the form field with the response of the captcha
+	ResponseField() string
+
+	// Widget renders the captcha
+	Widget() g.Node
+}
+
+// NewCaptchaVerifier creates the verifier of the configured captcha provider, which is nil if none is configured
+func NewCaptchaVerifier(config *Config) (CaptchaVerifier, error) {
+	switch config.CaptchaProvider {
+	case "":
+		return nil, nil
+	case CaptchaProviderHCaptcha:
+		return &siteVerifyCaptcha{
+			verifyURL:     "https://api.hcaptcha.com/siteverify",
+			scriptURL:     "https://js.hcaptcha.com/1/api.js",
+			widgetClass:   "h-captcha",
+			responseField: "h-captcha-response",
+			siteKey:       config.CaptchaSiteKey,
+			secret:        config.CaptchaSecret,
+			httpClient:    &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	case CaptchaProviderTurnstile:
+		return &siteVerifyCaptcha{
+			verifyURL:     "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+			scriptURL:     "https://challenges.cloudflare.com/turnstile/v0/api.js",
+			widgetClass:   "cf-turnstile",
+			responseField: "cf-turnstile-response",
+			siteKey:       config.CaptchaSiteKey,
+			secret:        config.CaptchaSecret,
+			httpClient:    &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	default:
+		return nil, errors.Errorf("captcha provider %s not supported", config.CaptchaProvider)
+	}
+}
+
+// siteVerifyCaptcha verifies captchas of providers with a siteverify api like hCaptcha and Turnstile
+type siteVerifyCaptcha struct {
+	verifyURL     string
+	scriptURL     string
+	widgetClass   string
+	responseField string
+	siteKey       string
+	secret        string
+	httpClient    *http.Client
+}
+
+func (c *siteVerifyCaptcha) Verify(ctx context.Context, response, remoteIP string) error {
+	if response == "" {
+		return ErrCaptchaRequired
+	}
+
+	form := url.Values{}
+	form.Set("secret", c.secret)
+	form.Set("sitekey", c.siteKey)
+	form.Set("response", response)
+	form.Set("remoteip", remoteIP)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not verify captcha")
+	}
+	defer res.Body.Close()
+
+	var verifyResponse struct {
+		Success bool `json:"success"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&verifyResponse)
+	if err != nil {
+		return errors.Wrap(err, "could not read captcha verification")
+	}
+
+	if !verifyResponse.Success {
+		return ErrCaptchaInvalid
+	}
+	return nil
+}
+
+func (c *siteVerifyCaptcha) ResponseField() string {
+	return c.responseField
+}
+
+func (c *siteVerifyCaptcha) Widget() g.Node {
+	return Div(
+		ID("captcha"),
+		Class("d-flex justify-content-center mb-3"),
+		ghx.Preserve("true"),
+		Div(
+			Class(c.widgetClass),
+			g.Attr("data-sitekey", c.siteKey),
+		),
+		Script(
+			Src(c.scriptURL),
+			g.Attr("async", "async"),
+			g.Attr("defer", "defer"),
+		),
+	)
+}
+
+// CaptchaGuard requires a captcha after repeated failed logins or signups from
+// the same client within the configured window, attempts are kept in memory
+type CaptchaGuard struct {
+	verifier  CaptchaVerifier
+	threshold int
+	window    time.Duration
+
+	mu       sync.Mutex
+	attempts map[string][]time.Time
+}
+
+// NewCaptchaGuard creates a guard requiring a captcha of the verifier after threshold attempts,
+// the guard never requires a captcha if the verifier is nil
+func NewCaptchaGuard(verifier CaptchaVerifier, threshold int, window time.Duration) *CaptchaGuard {
+	return &CaptchaGuard{
+		verifier:  verifier,
+		threshold: threshold,
+		window:    window,
+		attempts:  make(map[string][]time.Time),
+	}
+}
+
+// IsRequired checks whether a captcha is required for any of the keys like the username or ip address
+func (c *CaptchaGuard) IsRequired(keys ...string) bool {
+	if c.verifier == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if len(c.recentAttempts(key)) >= c.threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// Check verifies the captcha of the request if one is required for any of the keys
+func (c *CaptchaGuard) Check(ctx context.Context, captchaResponse, remoteIP string, keys ...string) error {
+	if !c.IsRequired(keys...) {
+		return nil
+	}
+
+	return c.verifier.Verify(ctx, captchaResponse, remoteIP)
+}
+
+// CheckRequest verifies the captcha of the form of the request if one is required for any of the keys
+func (c *CaptchaGuard) CheckRequest(r *http.Request, keys ...string) error {
+	if c.verifier == nil {
+		return nil
+	}
+
+	return c.Check(r.Context(), r.PostFormValue(c.verifier.ResponseField()), RemoteIP(r), keys...)
+}
+
+// RecordAttempt records a failed login or a signup for the keys
+func (c *CaptchaGuard) RecordAttempt(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for _, key := range keys {
+		c.attempts[key] = append(c.recentAttempts(key), now)
+	}
+}
+
+// Reset forgets the attempts of the keys like after a successful login
+func (c *CaptchaGuard) Reset(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.attempts, key)
+	}
+}
+
+// Widget renders the captcha if one is required for any of the keys
+func (c *CaptchaGuard) Widget(keys ...string) g.Node {
+	if !c.IsRequired(keys...) {
+		return nil
+	}
+
+	return c.verifier.Widget()
+}
+
+func (c *CaptchaGuard) recentAttempts(key string) []time.Time {
+	attempts := c.attempts[key]
+
+	windowStart := time.Now().Add(-c.window)
+	for len(attempts) > 0 && attempts[0].Before(windowStart) {
+		attempts = attempts[1:]
+	}
+
+	if len(attempts) == 0 {
+		delete(c.attempts, key)
+		return nil
+	}
+
+	c.attempts[key] = attempts
+	return attempts
+}
+
+// WithoutCaptchaResponse removes the response fields of the captcha providers from the values of a form
+func WithoutCaptchaResponse(values url.Values) url.Values {
+	formValues := make(url.Values, len(values))
+	for key, value := range values {
+		if key == "h-captcha-response" || key == "cf-turnstile-response" || key == "g-recaptcha-response" {
+			continue
+		}
+		formValues[key] = value
+	}
+	return formValues
+}
+
+// RemoteIP is the ip address of the client of the request
+func RemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
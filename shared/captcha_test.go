@@ -0,0 +1,105 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestCaptchaGuard(t *testing.T) {
+	is := is.New(t)
+
+	verifier, err := NewCaptchaVerifier(&Config{CaptchaProvider: CaptchaProviderTurnstile, CaptchaSiteKey: "site-key"})
+	is.NoErr(err)
+	guard := NewCaptchaGuard(verifier, 2, time.Minute)
+
+	guard.RecordAttempt("user1", "127.0.0.1")
+	is.True(!guard.IsRequired("user1", "127.0.0.1"))
+	is.True(guard.Widget("user1") == nil)
+
+	guard.RecordAttempt("user1", "127.0.0.1")
+	is.True(guard.IsRequired("user1"))
+	is.True(guard.IsRequired("user2", "127.0.0.1"))
+	is.True(!guard.IsRequired("user2", "127.0.0.2"))
+	is.True(guard.Widget("user1") != nil)
+
+	err = guard.Check(context.Background(), "", "127.0.0.1", "user1")
+	is.True(err == ErrCaptchaRequired)
+	is.NoErr(guard.Check(context.Background(), "", "127.0.0.2", "user2"))
+
+	guard.Reset("user1")
+	is.True(!guard.IsRequired("user1"))
+	is.True(guard.IsRequired("127.0.0.1"))
+}
+
+func TestCaptchaGuardWindow(t *testing.T) {
+	is := is.New(t)
+
+	verifier, err := NewCaptchaVerifier(&Config{CaptchaProvider: CaptchaProviderHCaptcha})
+	is.NoErr(err)
+	guard := NewCaptchaGuard(verifier, 1, time.Minute)
+
+	guard.attempts["user1"] = []time.Time{time.Now().Add(-2 * time.Minute)}
+	is.True(!guard.IsRequired("user1"))
+	is.Equal(len(guard.attempts), 0)
+}
+
+func TestCaptchaGuardWithoutVerifier(t *testing.T) {
+	is := is.New(t)
+
+	verifier, err := NewCaptchaVerifier(&Config{})
+	is.NoErr(err)
+	guard := NewCaptchaGuard(verifier, 0, time.Minute)
+
+	guard.RecordAttempt("user1")
+	is.True(!guard.IsRequired("user1"))
+	is.NoErr(guard.Check(context.Background(), "", "127.0.0.1", "user1"))
+}
+
+func TestNewCaptchaVerifierWithUnknownProvider(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewCaptchaVerifier(&Config{CaptchaProvider: "recaptcha"})
+	is.True(err != nil)
+}
+
+func TestSiteVerifyCaptcha(t *testing.T) {
+	is := is.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.PostFormValue("secret"), "secret")
+		if r.PostFormValue("response") == "solved" {
+			_, _ = w.Write([]byte(`{"success": true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+	}))
+	defer server.Close()
+
+	verifier := &siteVerifyCaptcha{
+		verifyURL:  server.URL,
+		secret:     "secret",
+		httpClient: server.Client(),
+	}
+
+	is.NoErr(verifier.Verify(context.Background(), "solved", "127.0.0.1"))
+	is.Equal(verifier.Verify(context.Background(), "guessed", "127.0.0.1"), ErrCaptchaInvalid)
+	is.Equal(verifier.Verify(context.Background(), "", "127.0.0.1"), ErrCaptchaRequired)
+}
+
+func TestWithoutCaptchaResponse(t *testing.T) {
+	is := is.New(t)
+
+	values := url.Values{
+		"EMail":                 []string{"user1"},
+		"h-captcha-response":    []string{"response"},
+		"cf-turnstile-response": []string{"response"},
+	}
+
+	is.Equal(WithoutCaptchaResponse(values), url.Values{"EMail": []string{"user1"}})
+}
@@ -0,0 +1,191 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	width       = 640
+	height      = 320
+	padding     = 40
+	legendWidth = 200
+)
+
+var palette = []string{
+	"#0d6efd", "#6610f2", "#d63384", "#fd7e14", "#198754",
+	"#20c997", "#0dcaf0", "#ffc107", "#dc3545", "#6c757d",
+}
+
+// Value is a labeled data point of a chart
+type Value struct {
+	Label string
+	Value float64
+}
+
+// ContentType is the content type of the rendered charts
+const ContentType = "image/svg+xml"
+
+// Bar renders the values as SVG bar chart
+func Bar(w io.Writer, title string, values []Value) error {
+	buf := &bytes.Buffer{}
+	header(buf, title)
+
+	maxValue := maxOf(values)
+	chartHeight := float64(height - 2*padding)
+	chartWidth := float64(width - 2*padding)
+
+	if len(values) > 0 {
+		slot := chartWidth / float64(len(values))
+		barWidth := slot * 0.7
+		for i, v := range values {
+			barHeight := 0.0
+			if maxValue > 0 {
+				barHeight = v.Value / maxValue * chartHeight
+			}
+			x := float64(padding) + float64(i)*slot + (slot-barWidth)/2
+			y := float64(height-padding) - barHeight
+			fmt.Fprintf(buf, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %s</title></rect>`,
+				x, y, barWidth, barHeight, color(0), escape(v.Label), formatValue(v.Value))
+			fmt.Fprintf(buf, `<text x="%.1f" y="%d" font-size="10" text-anchor="middle">%s</text>`,
+				x+barWidth/2, height-padding+14, escape(v.Label))
+		}
+	}
+
+	axes(buf)
+	footer(buf)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Line renders the values as SVG line chart
+func Line(w io.Writer, title string, values []Value) error {
+	buf := &bytes.Buffer{}
+	header(buf, title)
+
+	maxValue := maxOf(values)
+	chartHeight := float64(height - 2*padding)
+	chartWidth := float64(width - 2*padding)
+
+	if len(values) > 0 {
+		step := 0.0
+		if len(values) > 1 {
+			step = chartWidth / float64(len(values)-1)
+		}
+
+		points := &bytes.Buffer{}
+		for i, v := range values {
+			y := float64(height - padding)
+			if maxValue > 0 {
+				y -= v.Value / maxValue * chartHeight
+			}
+			x := float64(padding) + float64(i)*step
+			fmt.Fprintf(points, "%.1f,%.1f ", x, y)
+			fmt.Fprintf(buf, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s: %s</title></circle>`,
+				x, y, color(0), escape(v.Label), formatValue(v.Value))
+			fmt.Fprintf(buf, `<text x="%.1f" y="%d" font-size="10" text-anchor="middle">%s</text>`,
+				x, height-padding+14, escape(v.Label))
+		}
+		fmt.Fprintf(buf, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`, color(0), bytes.TrimSpace(points.Bytes()))
+	}
+
+	axes(buf)
+	footer(buf)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Pie renders the values as SVG pie chart with legend
+func Pie(w io.Writer, title string, values []Value) error {
+	buf := &bytes.Buffer{}
+	header(buf, title)
+
+	total := 0.0
+	for _, v := range values {
+		total += v.Value
+	}
+
+	cx := float64(width-legendWidth) / 2
+	cy := float64(height) / 2
+	radius := float64(height)/2 - padding
+
+	angle := -math.Pi / 2
+	for i, v := range values {
+		if total <= 0 || v.Value <= 0 {
+			continue
+		}
+
+		share := v.Value / total
+		if share >= 1 {
+			fmt.Fprintf(buf, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s"><title>%s: %s</title></circle>`,
+				cx, cy, radius, color(i), escape(v.Label), formatValue(v.Value))
+			break
+		}
+
+		endAngle := angle + share*2*math.Pi
+		largeArc := 0
+		if share > 0.5 {
+			largeArc = 1
+		}
+		fmt.Fprintf(buf, `<path d="M %.1f %.1f L %.1f %.1f A %.1f %.1f 0 %d 1 %.1f %.1f Z" fill="%s"><title>%s: %s</title></path>`,
+			cx, cy,
+			cx+radius*math.Cos(angle), cy+radius*math.Sin(angle),
+			radius, radius, largeArc,
+			cx+radius*math.Cos(endAngle), cy+radius*math.Sin(endAngle),
+			color(i), escape(v.Label), formatValue(v.Value))
+		angle = endAngle
+	}
+
+	for i, v := range values {
+		y := padding + i*18
+		fmt.Fprintf(buf, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`, width-legendWidth, y, color(i))
+		fmt.Fprintf(buf, `<text x="%d" y="%d" font-size="12">%s</text>`, width-legendWidth+18, y+10, escape(v.Label))
+	}
+
+	footer(buf)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func header(buf *bytes.Buffer, title string) {
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="sans-serif" fill="currentColor">`, width, height, width, height)
+	fmt.Fprintf(buf, `<title>%s</title>`, escape(title))
+	fmt.Fprintf(buf, `<text x="%d" y="20" font-size="14" font-weight="bold">%s</text>`, padding, escape(title))
+}
+
+func axes(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="currentColor"/>`, padding, height-padding, width-padding, height-padding)
+	fmt.Fprintf(buf, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="currentColor"/>`, padding, padding, padding, height-padding)
+}
+
+func footer(buf *bytes.Buffer) {
+	buf.WriteString(`</svg>`)
+}
+
+func maxOf(values []Value) float64 {
+	maxValue := 0.0
+	for _, v := range values {
+		maxValue = math.Max(maxValue, v.Value)
+	}
+	return maxValue
+}
+
+func color(i int) string {
+	return palette[i%len(palette)]
+}
+
+func formatValue(value float64) string {
+	return fmt.Sprintf("%.2f", value)
+}
+
+func escape(s string) string {
+	buf := &bytes.Buffer{}
+	_ = xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,75 @@
+package chart
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestBar(t *testing.T) {
+	is := is.New(t)
+	buf := &bytes.Buffer{}
+
+	err := Bar(buf, "Weekly Hours", []Value{
+		{Label: "1", Value: 10},
+		{Label: "2", Value: 20},
+	})
+
+	is.NoErr(err)
+	svg := buf.String()
+	is.True(strings.HasPrefix(svg, "<svg"))
+	is.True(strings.HasSuffix(svg, "</svg>"))
+	is.Equal(strings.Count(svg, "<rect"), 2)
+}
+
+func TestLine(t *testing.T) {
+	is := is.New(t)
+	buf := &bytes.Buffer{}
+
+	err := Line(buf, "Trend", []Value{
+		{Label: "1", Value: 10},
+		{Label: "2", Value: 20},
+		{Label: "3", Value: 0},
+	})
+
+	is.NoErr(err)
+	is.True(strings.Contains(buf.String(), "<polyline"))
+	is.Equal(strings.Count(buf.String(), "<circle"), 3)
+}
+
+func TestPie(t *testing.T) {
+	is := is.New(t)
+	buf := &bytes.Buffer{}
+
+	err := Pie(buf, "Projects", []Value{
+		{Label: "Project <A>", Value: 30},
+		{Label: "Project B", Value: 10},
+	})
+
+	is.NoErr(err)
+	svg := buf.String()
+	is.Equal(strings.Count(svg, "<path"), 2)
+	is.True(strings.Contains(svg, "Project &lt;A&gt;"))
+}
+
+func TestPieSingleValue(t *testing.T) {
+	is := is.New(t)
+	buf := &bytes.Buffer{}
+
+	err := Pie(buf, "Projects", []Value{
+		{Label: "Project A", Value: 30},
+	})
+
+	is.NoErr(err)
+	is.Equal(strings.Count(buf.String(), "<circle"), 1)
+}
+
+func TestEmptyCharts(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(Bar(&bytes.Buffer{}, "Empty", nil))
+	is.NoErr(Line(&bytes.Buffer{}, "Empty", nil))
+	is.NoErr(Pie(&bytes.Buffer{}, "Empty", nil))
+}
@@ -0,0 +1,41 @@
+package shared
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ClientPortalPath is the path all requests of client users are restricted to
+const ClientPortalPath = "/client-portal"
+
+// ClientPortalMiddleware restricts users with the client role to the client portal,
+// all other requests of client users are forbidden
+func ClientPortalMiddleware(config *Config) func(next http.Handler) http.Handler {
+	isProduction := config.IsProduction()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := r.Context().Value(ContextKeyPrincipal).(*Principal)
+			if !ok || !principal.HasRole("ROLE_CLIENT") || isClientPortalPath(routePathOf(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			RenderProblemJSON(w, isProduction, ErrForbidden)
+		})
+	}
+}
+
+func isClientPortalPath(path string) bool {
+	return path == ClientPortalPath || strings.HasPrefix(path, ClientPortalPath+"/")
+}
+
+// routePathOf is the path of the request within the router it's mounted to
+func routePathOf(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx != nil && rctx.RoutePath != "" {
+		return rctx.RoutePath
+	}
+	return r.URL.Path
+}
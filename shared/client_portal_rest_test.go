@@ -0,0 +1,44 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestClientPortalMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	router := chi.NewRouter()
+	router.Mount("/api", func() http.Handler {
+		r := chi.NewRouter()
+		r.Use(ClientPortalMiddleware(&Config{}))
+		r.Get("/activities", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		r.Get("/client-portal/report", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		return r
+	}())
+
+	request := func(path string, roles ...string) int {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", path, nil)
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+			OrganizationID: OrganizationIDSample,
+			Roles:          roles,
+		}))
+		router.ServeHTTP(httpRec, r)
+		return httpRec.Result().StatusCode
+	}
+
+	is.Equal(request("/api/activities", "ROLE_USER"), http.StatusNoContent)
+	is.Equal(request("/api/client-portal/report", "ROLE_USER"), http.StatusNoContent)
+	is.Equal(request("/api/client-portal/report", "ROLE_CLIENT"), http.StatusNoContent)
+	is.Equal(request("/api/activities", "ROLE_CLIENT"), http.StatusForbidden)
+}
@@ -0,0 +1,277 @@
+package shared
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	configPrefix       = "BARALGA_"
+	configFileKey      = configPrefix + "CONFIGFILE"
+	secretFileSuffix   = "_FILE"
+	redactedConfigItem = "******"
+)
+
+// LoadConfig loads the configuration from these sources, each overriding the previous one:
+// the defaults, a config file given by BARALGA_CONFIGFILE with lines like BARALGA_DB=postgres://...,
+// secret files given like BARALGA_JWTSECRET_FILE=/run/secrets/jwt and the environment variables
+func LoadConfig() (*Config, error) {
+	return loadConfig(os.LookupEnv)
+}
+
+func loadConfig(lookupEnv func(key string) (string, bool)) (*Config, error) {
+	fileValues := make(map[string]string)
+	if configFile, ok := lookupEnv(configFileKey); ok && configFile != "" {
+		values, err := readConfigFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		fileValues = values
+	}
+
+	config := &Config{}
+	configValue := reflect.ValueOf(config).Elem()
+	configType := configValue.Type()
+
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		key := ConfigKey(field.Name)
+
+		value := field.Tag.Get("default")
+		if fileValue, ok := fileValues[key]; ok {
+			value = fileValue
+		}
+		if secretFile, ok := lookupEnv(key + secretFileSuffix); ok && secretFile != "" {
+			secret, err := os.ReadFile(secretFile)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not read secret file of %s", key)
+			}
+			value = strings.TrimSpace(string(secret))
+		}
+		if envValue, ok := lookupEnv(key); ok {
+			value = envValue
+		}
+
+		err := setConfigField(configValue.Field(i), value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value of %s", key)
+		}
+	}
+
+	return config, nil
+}
+
+// ConfigKey is the key of the config field in the environment and config files
+func ConfigKey(fieldName string) string {
+	return configPrefix + strings.ToUpper(fieldName)
+}
+
+// Validate checks the configuration on startup, in production the default secrets must be replaced
+func (c *Config) Validate() error {
+	var errs []string
+
+	if _, err := strconv.Atoi(c.BindPort); err != nil {
+		errs = append(errs, fmt.Sprintf("%s must be a port number", ConfigKey("BindPort")))
+	}
+	if _, err := url.Parse(c.Db); err != nil || c.Db == "" {
+		errs = append(errs, fmt.Sprintf("%s must be a connection url", ConfigKey("Db")))
+	}
+	if c.DbMaxConns < 1 {
+		errs = append(errs, fmt.Sprintf("%s must be at least 1", ConfigKey("DbMaxConns")))
+	}
+	if c.DbMinConns < 0 || c.DbMinConns > c.DbMaxConns {
+		errs = append(errs, fmt.Sprintf("%s must be between 0 and %s", ConfigKey("DbMinConns"), ConfigKey("DbMaxConns")))
+	}
+
+	durations := map[string]string{
+		"DbQueryTimeout":          c.DbQueryTimeout,
+		"DbMaxConnLifetime":       c.DbMaxConnLifetime,
+		"DbMaxConnIdleTime":       c.DbMaxConnIdleTime,
+		"DbHealthCheckPeriod":     c.DbHealthCheckPeriod,
+		"JWTExpiry":               c.JWTExpiry,
+		"ReportCacheExpiry":       c.ReportCacheExpiry,
+		"CaptchaWindow":           c.CaptchaWindow,
+		"UndoWindow":              c.UndoWindow,
+		"LoginFailureAlertWindow": c.LoginFailureAlertWindow,
+	}
+	for _, name := range sortedKeys(durations) {
+		if _, err := time.ParseDuration(durations[name]); err != nil {
+			errs = append(errs, fmt.Sprintf("%s must be a duration like 30s or 1h", ConfigKey(name)))
+		}
+	}
+
+	if !IsValidPlan(c.DefaultPlan) {
+		errs = append(errs, fmt.Sprintf("%s must be %s, %s, %s or %s", ConfigKey("DefaultPlan"), PlanUnlimited, PlanFree, PlanTeam, PlanBusiness))
+	}
+
+	if c.TrialDays < 0 {
+		errs = append(errs, fmt.Sprintf("%s must not be negative", ConfigKey("TrialDays")))
+	}
+
+	for _, planPrice := range strings.Split(c.StripePrices, ",") {
+		plan, priceID, ok := strings.Cut(strings.TrimSpace(planPrice), ":")
+		if strings.TrimSpace(planPrice) != "" && (!ok || !IsValidPlan(strings.TrimSpace(plan)) || strings.TrimSpace(priceID) == "") {
+			errs = append(errs, fmt.Sprintf("%s must be comma separated plans and prices like %s:price_1", ConfigKey("StripePrices"), PlanTeam))
+			break
+		}
+	}
+	if c.StripeSecretKey != "" && c.StripeWebhookSecret == "" {
+		errs = append(errs, fmt.Sprintf("%s is required for billing", ConfigKey("StripeWebhookSecret")))
+	}
+	if c.EmailInDomain != "" && c.EmailInSigningKey == "" {
+		errs = append(errs, fmt.Sprintf("%s is required for email-in", ConfigKey("EmailInSigningKey")))
+	}
+
+	if c.HSTSSeconds < 0 {
+		errs = append(errs, fmt.Sprintf("%s must not be negative", ConfigKey("HSTSSeconds")))
+	}
+	if c.FrameOptions != "DENY" && c.FrameOptions != "SAMEORIGIN" {
+		errs = append(errs, fmt.Sprintf("%s must be DENY or SAMEORIGIN", ConfigKey("FrameOptions")))
+	}
+
+	if _, err := NewCaptchaVerifier(c); err != nil {
+		errs = append(errs, fmt.Sprintf("%s must be %s or %s", ConfigKey("CaptchaProvider"), CaptchaProviderHCaptcha, CaptchaProviderTurnstile))
+	} else if c.CaptchaProvider != "" && (c.CaptchaSiteKey == "" || c.CaptchaSecret == "") {
+		errs = append(errs, fmt.Sprintf("%s and %s are required for the captcha", ConfigKey("CaptchaSiteKey"), ConfigKey("CaptchaSecret")))
+	}
+	if c.CaptchaThreshold < 0 {
+		errs = append(errs, fmt.Sprintf("%s must not be negative", ConfigKey("CaptchaThreshold")))
+	}
+
+	if c.ReceiptRecognizer != "" && c.ReceiptRecognizer != "tesseract" && c.ReceiptRecognizer != "google-vision" {
+		errs = append(errs, fmt.Sprintf("%s must be tesseract or google-vision", ConfigKey("ReceiptRecognizer")))
+	}
+	if c.ReceiptRecognizer == "google-vision" && c.ReceiptGoogleVisionAPIKey == "" {
+		errs = append(errs, fmt.Sprintf("%s is required for google-vision", ConfigKey("ReceiptGoogleVisionAPIKey")))
+	}
+
+	if _, err := NewScanner(c); err != nil {
+		errs = append(errs, fmt.Sprintf("%s not valid: %v", ConfigKey("Scanner"), err))
+	}
+
+	if _, err := NewEventPublisher(c); err != nil {
+		errs = append(errs, fmt.Sprintf("%s not valid: %v", ConfigKey("EventBroker"), err))
+	}
+
+	if _, err := NewStorage(c); err != nil {
+		errs = append(errs, fmt.Sprintf("%s not valid: %v", ConfigKey("Storage"), err))
+	}
+
+	if _, err := NewEncrypter(c.EncryptionKeys); err != nil {
+		errs = append(errs, fmt.Sprintf("%s not valid: %v", ConfigKey("EncryptionKeys"), err))
+	}
+
+	if c.IsProduction() {
+		defaults := reflect.TypeOf(*c)
+		secrets := map[string]string{
+			"JWTSecret":      c.JWTSecret,
+			"CSRFSecret":     c.CSRFSecret,
+			"EncryptionKeys": c.EncryptionKeys,
+		}
+		for _, name := range sortedKeys(secrets) {
+			field, _ := defaults.FieldByName(name)
+			if secrets[name] == field.Tag.Get("default") {
+				errs = append(errs, fmt.Sprintf("%s must not be the default in production", ConfigKey(name)))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Errorf("configuration not valid: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Redacted lists the configuration by key with secrets and passwords of urls redacted
+func (c *Config) Redacted() map[string]string {
+	configType := reflect.TypeOf(*c)
+	configValue := reflect.ValueOf(*c)
+
+	redacted := make(map[string]string, configType.NumField())
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		value := fmt.Sprint(configValue.Field(i).Interface())
+
+		switch {
+		case field.Tag.Get("secret") == "true" && value != "":
+			value = redactedConfigItem
+		case field.Tag.Get("url") == "true":
+			if u, err := url.Parse(value); err == nil {
+				value = u.Redacted()
+			} else {
+				value = redactedConfigItem
+			}
+		}
+
+		redacted[ConfigKey(field.Name)] = value
+	}
+	return redacted
+}
+
+func setConfigField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	default:
+		return errors.Errorf("unsupported type %s", field.Kind())
+	}
+	return nil
+}
+
+// readConfigFile reads lines like BARALGA_DB=postgres://..., empty lines and lines starting with # are skipped
+func readConfigFile(configFile string) (map[string]string, error) {
+	file, err := os.Open(configFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read config file")
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, errors.Errorf("invalid line %d of config file, expected KEY=value", lineNumber)
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+
+	return values, scanner.Err()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
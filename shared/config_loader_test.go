@@ -0,0 +1,103 @@
+package shared
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestLoadConfigDefaults(t *testing.T) {
+	is := is.New(t)
+
+	config, err := loadConfig(lookupEnvOf(map[string]string{}))
+	is.NoErr(err)
+
+	is.Equal(config.BindPort, "8080")
+	is.Equal(config.DbMaxConns, int32(3))
+	is.Equal(config.DbRowLevelSecurity, true)
+	is.Equal(config.WorkingHoursPerWeek, 40)
+	is.NoErr(config.Validate())
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	is := is.New(t)
+	dir := t.TempDir()
+
+	configFile := filepath.Join(dir, "baralga.env")
+	err := os.WriteFile(configFile, []byte(`
+# config of baralga
+BARALGA_WEBROOT="https://baralga.example.com"
+BARALGA_DBMAXCONNS=10
+BARALGA_JWTSECRET=from-file
+BARALGA_SMTPUSER=from-file
+`), 0600)
+	is.NoErr(err)
+
+	secretFile := filepath.Join(dir, "jwt-secret")
+	err = os.WriteFile(secretFile, []byte("from-secret-file\n"), 0600)
+	is.NoErr(err)
+
+	config, err := loadConfig(lookupEnvOf(map[string]string{
+		"BARALGA_CONFIGFILE":     configFile,
+		"BARALGA_JWTSECRET_FILE": secretFile,
+		"BARALGA_SMTPUSER":       "from-env",
+	}))
+	is.NoErr(err)
+
+	is.Equal(config.Webroot, "https://baralga.example.com")
+	is.Equal(config.DbMaxConns, int32(10))
+	is.Equal(config.JWTSecret, "from-secret-file")
+	is.Equal(config.SMTPUser, "from-env")
+	is.Equal(config.BindPort, "8080")
+}
+
+func TestLoadConfigWithInvalidValue(t *testing.T) {
+	is := is.New(t)
+
+	_, err := loadConfig(lookupEnvOf(map[string]string{
+		"BARALGA_DBMAXCONNS": "many",
+	}))
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "BARALGA_DBMAXCONNS"))
+}
+
+func TestLoadConfigWithMissingSecretFile(t *testing.T) {
+	is := is.New(t)
+
+	_, err := loadConfig(lookupEnvOf(map[string]string{
+		"BARALGA_JWTSECRET_FILE": filepath.Join(t.TempDir(), "missing"),
+	}))
+	is.True(err != nil)
+}
+
+func TestValidateConfig(t *testing.T) {
+	is := is.New(t)
+
+	config, err := loadConfig(lookupEnvOf(map[string]string{
+		"BARALGA_ENV":            "production",
+		"BARALGA_DBMINCONNS":     "5",
+		"BARALGA_DBQUERYTIMEOUT": "soon",
+		"BARALGA_STRIPEPRICES":   "gold:price_1",
+		"BARALGA_EMAILINDOMAIN":  "in.baralga.com",
+	}))
+	is.NoErr(err)
+
+	err = config.Validate()
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "BARALGA_DBMINCONNS"))
+	is.True(strings.Contains(err.Error(), "BARALGA_DBQUERYTIMEOUT"))
+	is.True(strings.Contains(err.Error(), "BARALGA_STRIPEPRICES"))
+	is.True(strings.Contains(err.Error(), "BARALGA_EMAILINSIGNINGKEY is required"))
+	is.True(strings.Contains(err.Error(), "BARALGA_JWTSECRET must not be the default"))
+	is.True(strings.Contains(err.Error(), "BARALGA_ENCRYPTIONKEYS must not be the default"))
+}
+
+func lookupEnvOf(env map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"net/http"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type configModel struct {
+	Config map[string]string `json:"config"`
+	Links  *hal.Links        `json:"_links"`
+}
+
+type ConfigRestHandlers struct {
+	config *Config
+}
+
+func NewConfigRestHandlers(config *Config) *ConfigRestHandlers {
+	return &ConfigRestHandlers{
+		config: config,
+	}
+}
+
+func (a *ConfigRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/admin/config", a.HandleGetConfig())
+}
+
+func (a *ConfigRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetConfig reads the configuration with secrets redacted for operators
+func (a *ConfigRestHandlers) HandleGetConfig() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	config := a.config
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			RenderProblemJSON(w, isProduction, ErrForbidden)
+			return
+		}
+
+		RenderJSON(w, &configModel{
+			Config: config.Redacted(),
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
@@ -2,6 +2,7 @@ package shared
 
 import (
 	"testing"
+	"time"
 
 	"github.com/matryer/is"
 )
@@ -14,3 +15,98 @@ func TestIsProduction(t *testing.T) {
 	}
 	is.True(!config.IsProduction())
 }
+
+func TestReportCacheExpiryDuration(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{
+		ReportCacheExpiry: "30s",
+	}
+	is.Equal(config.ReportCacheExpiryDuration(), 30*time.Second)
+
+	config.ReportCacheExpiry = "invalid"
+	is.Equal(config.ReportCacheExpiryDuration(), time.Minute)
+}
+
+func TestDbQueryTimeoutDuration(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{
+		DbQueryTimeout: "10s",
+	}
+	is.Equal(config.DbQueryTimeoutDuration(), 10*time.Second)
+
+	config.DbQueryTimeout = "invalid"
+	is.Equal(config.DbQueryTimeoutDuration(), 30*time.Second)
+}
+
+func TestDbPoolConfig(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{
+		DbMaxConns:          10,
+		DbMinConns:          2,
+		DbMaxConnLifetime:   "2h",
+		DbMaxConnIdleTime:   "invalid",
+		DbHealthCheckPeriod: "30s",
+		DbQueryTimeout:      "10s",
+	}
+
+	poolConfig := config.DbPoolConfig()
+	is.Equal(poolConfig.MaxConns, int32(10))
+	is.Equal(poolConfig.MinConns, int32(2))
+	is.Equal(poolConfig.MaxConnLifetime, 2*time.Hour)
+	is.Equal(poolConfig.MaxConnIdleTime, 30*time.Minute)
+	is.Equal(poolConfig.HealthCheckPeriod, 30*time.Second)
+	is.Equal(poolConfig.QueryTimeout, 10*time.Second)
+}
+
+func TestStripePriceIDs(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{
+		StripePrices: "team:price_1, business: price_2,invalid",
+	}
+
+	priceIDs := config.StripePriceIDs()
+	is.Equal(len(priceIDs), 2)
+	is.Equal(priceIDs[PlanTeam], "price_1")
+	is.Equal(priceIDs[PlanBusiness], "price_2")
+}
+
+func TestCORSOrigins(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{}
+	is.Equal(len(config.CORSOrigins()), 0)
+
+	config.CORSAllowedOrigins = "chrome-extension://abc, https://example.com/"
+	is.Equal(config.CORSOrigins(), []string{"chrome-extension://abc", "https://example.com"})
+}
+
+func TestIsInstanceAdmin(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{
+		InstanceAdmins: "ops@baralga.com, support@baralga.com",
+	}
+	is.True(config.IsInstanceAdmin("ops@baralga.com"))
+	is.True(config.IsInstanceAdmin("support@baralga.com"))
+	is.True(!config.IsInstanceAdmin("admin@baralga.com"))
+	is.True(!config.IsInstanceAdmin(""))
+
+	config.InstanceAdmins = ""
+	is.True(!config.IsInstanceAdmin(""))
+}
+
+func TestUndoWindowDuration(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{
+		UndoWindow: "10m",
+	}
+	is.Equal(config.UndoWindowDuration(), 10*time.Minute)
+
+	config.UndoWindow = "invalid"
+	is.Equal(config.UndoWindowDuration(), 5*time.Minute)
+}
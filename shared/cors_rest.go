@@ -0,0 +1,42 @@
+package shared
+
+import (
+	"net/http"
+	"strings"
+
+	"slices"
+)
+
+// CORSMiddleware allows the configured origins like browser extensions to call the api cross origin.
+// Cross origin requests authenticate by bearer token, the session cookie is never sent along
+// as credentials are not allowed. Preflight requests of allowed origins are answered right away.
+func CORSMiddleware(config *Config) func(http.Handler) http.Handler {
+	origins := config.CORSOrigins()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" || !isAllowedOrigin(origins, origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", "Location, Deprecation, Link, Warning")
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", strings.Join([]string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}, ", "))
+				w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept, If-Match, If-None-Match")
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isAllowedOrigin(origins []string, origin string) bool {
+	return slices.Contains(origins, "*") || slices.Contains(origins, strings.TrimSuffix(origin, "/"))
+}
@@ -0,0 +1,52 @@
+package shared
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{CORSAllowedOrigins: "chrome-extension://abc, moz-extension://def/"}
+	handler := CORSMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("preflight of allowed origin", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("OPTIONS", "/api/extension/status", nil)
+		r.Header.Set("Origin", "chrome-extension://abc")
+		r.Header.Set("Access-Control-Request-Method", "GET")
+		handler.ServeHTTP(httpRec, r)
+
+		is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+		is.Equal(httpRec.Header().Get("Access-Control-Allow-Origin"), "chrome-extension://abc")
+		is.Equal(httpRec.Header().Get("Access-Control-Allow-Credentials"), "")
+		is.True(httpRec.Header().Get("Access-Control-Allow-Headers") != "")
+	})
+
+	t.Run("request of allowed origin", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/extension/status", nil)
+		r.Header.Set("Origin", "moz-extension://def")
+		handler.ServeHTTP(httpRec, r)
+
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Header().Get("Access-Control-Allow-Origin"), "moz-extension://def")
+	})
+
+	t.Run("request of other origin", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("OPTIONS", "/api/extension/status", nil)
+		r.Header.Set("Origin", "https://evil.example.com")
+		r.Header.Set("Access-Control-Request-Method", "GET")
+		handler.ServeHTTP(httpRec, r)
+
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Header().Get("Access-Control-Allow-Origin"), "")
+	})
+}
@@ -0,0 +1,83 @@
+package shared
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gorilla/csrf"
+	g "github.com/maragudk/gomponents"
+)
+
+const (
+	// SessionCookieName is the name of the cookie with the jwt of the web session
+	SessionCookieName = "jwt"
+
+	// CSRFFieldName is the name of the form field with the csrf token
+	CSRFFieldName = "CSRFToken"
+
+	// CSRFHeaderName is the name of the header with the csrf token for htmx requests
+	CSRFHeaderName = "X-CSRF-Token"
+)
+
+// CSRFMiddleware rejects state changing requests without a valid csrf token in
+// the form field CSRFToken or the header X-CSRF-Token
+func CSRFMiddleware(config *Config) func(http.Handler) http.Handler {
+	cookieName := "__Secure-csrf"
+	if !config.IsProduction() {
+		cookieName = "__Insecure-csrf"
+	}
+
+	return csrf.Protect(
+		[]byte(config.CSRFSecret),
+		csrf.CookieName(cookieName),
+		csrf.FieldName(CSRFFieldName),
+		csrf.RequestHeader(CSRFHeaderName),
+		csrf.Path("/"),
+		csrf.HttpOnly(true),
+		csrf.SameSite(csrf.SameSiteStrictMode),
+		csrf.Secure(config.IsProduction()),
+	)
+}
+
+// CSRFSessionMiddleware applies the csrf protection to requests authenticated by the session cookie
+// of the web ui like htmx requests to the api, requests with a bearer token are passed through
+func CSRFSessionMiddleware(config *Config) func(http.Handler) http.Handler {
+	protect := CSRFMiddleware(config)
+	return func(next http.Handler) http.Handler {
+		protected := protect(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := r.Cookie(SessionCookieName); err != nil || r.Header.Get("Authorization") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			protected.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CSRFHeaders adds the csrf token to the headers of all htmx requests of the element and its children
+func CSRFHeaders(csrfToken string) g.Node {
+	headers, _ := json.Marshal(map[string]string{CSRFHeaderName: csrfToken})
+	return g.Attr("hx-headers", string(headers))
+}
+
+// WithTestCSRFToken adds a valid csrf token and cookie to the request like a browser would,
+// so that handler tests pass through the csrf middleware
+func WithTestCSRFToken(config *Config, r *http.Request) *http.Request {
+	var csrfToken string
+	tokenHandler := CSRFMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		csrfToken = csrf.Token(r)
+	}))
+
+	httpRec := httptest.NewRecorder()
+	tokenRequest, _ := http.NewRequest("GET", "/", nil)
+	tokenHandler.ServeHTTP(httpRec, tokenRequest)
+
+	for _, cookie := range httpRec.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	r.Header.Set(CSRFHeaderName, csrfToken)
+	return r
+}
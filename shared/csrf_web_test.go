@@ -0,0 +1,93 @@
+package shared
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	g "github.com/maragudk/gomponents"
+	"github.com/matryer/is"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	is := is.New(t)
+	config := &Config{CSRFSecret: "CSRFsecret-of-32-bytes-for-tests"}
+	handler := CSRFMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	t.Run("post without token", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/projects/new", nil)
+
+		handler.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	})
+
+	t.Run("post with token", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/projects/new", nil)
+		r = WithTestCSRFToken(config, r)
+
+		handler.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+	})
+
+	t.Run("get without token", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/projects", nil)
+
+		handler.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+
+		cookie := httpRec.Result().Cookies()[0]
+		is.Equal(cookie.SameSite, http.SameSiteStrictMode)
+		is.True(cookie.HttpOnly)
+	})
+}
+
+func TestCSRFSessionMiddleware(t *testing.T) {
+	is := is.New(t)
+	config := &Config{CSRFSecret: "CSRFsecret-of-32-bytes-for-tests"}
+	handler := CSRFSessionMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	t.Run("delete with session cookie without token", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("DELETE", "/api/activities/1", nil)
+		r.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "token"})
+
+		handler.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	})
+
+	t.Run("delete with session cookie and token", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("DELETE", "/api/activities/1", nil)
+		r.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "token"})
+		r = WithTestCSRFToken(config, r)
+
+		handler.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+	})
+
+	t.Run("delete with bearer token", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("DELETE", "/api/activities/1", nil)
+		r.Header.Set("Authorization", "Bearer token")
+
+		handler.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+	})
+}
+
+func TestCSRFHeaders(t *testing.T) {
+	is := is.New(t)
+
+	var html strings.Builder
+	err := g.El("body", CSRFHeaders("my-token")).Render(&html)
+	is.NoErr(err)
+	is.Equal(html.String(), `<body hx-headers="{&#34;X-CSRF-Token&#34;:&#34;my-token&#34;}"></body>`)
+}
@@ -0,0 +1,166 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// customDomainVerificationRecord is the subdomain of the TXT record which proves the ownership of a custom domain
+	customDomainVerificationRecord = "_baralga-verification"
+
+	// maxHostnameLength is the maximum length of a hostname in the DNS
+	maxHostnameLength = 253
+)
+
+var (
+	ErrCustomDomainNotFound           = NewDomainError("custom-domain:not-found", http.StatusNotFound, "custom domain not found")
+	ErrCustomDomainNotValid           = NewDomainError("custom-domain:not-valid", http.StatusBadRequest, "custom domain must be a hostname like time.example.com")
+	ErrCustomDomainTaken              = NewDomainError("custom-domain:taken", http.StatusConflict, "custom domain is already taken")
+	ErrCustomDomainVerificationFailed = NewDomainError("custom-domain:verification-failed", http.StatusConflict, "verification record of custom domain not found")
+)
+
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// CustomDomain is a hostname of an organization which resolves to its branded login and client portal,
+// requests are routed by host to the organization once the ownership of the domain is verified via DNS
+type CustomDomain struct {
+	ID                uuid.UUID
+	OrganizationID    uuid.UUID
+	Hostname          string
+	VerificationToken string
+	VerifiedAt        *time.Time
+	CreatedAt         time.Time
+}
+
+type CustomDomainRepository interface {
+	FindCustomDomains(ctx context.Context, organizationID uuid.UUID) ([]*CustomDomain, error)
+	FindCustomDomainByID(ctx context.Context, organizationID, customDomainID uuid.UUID) (*CustomDomain, error)
+	FindVerifiedCustomDomainByHostname(ctx context.Context, hostname string) (*CustomDomain, error)
+	InsertCustomDomain(ctx context.Context, customDomain *CustomDomain) error
+	UpdateCustomDomainVerifiedAt(ctx context.Context, organizationID, customDomainID uuid.UUID, verifiedAt time.Time) error
+	DeleteCustomDomainByID(ctx context.Context, organizationID, customDomainID uuid.UUID) error
+}
+
+// TXTResolver looks up the TXT records of a domain, it's implemented by net.Resolver
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// CertificateHook is notified when custom domains are verified or removed, so a certificate
+// for the domain can be issued or revoked by the infrastructure in front of the instance
+type CertificateHook interface {
+	DomainVerified(ctx context.Context, customDomain *CustomDomain) error
+	DomainRemoved(ctx context.Context, customDomain *CustomDomain) error
+}
+
+// NewCustomDomain creates a new unverified custom domain of the organization with a random verification token
+func NewCustomDomain(organizationID uuid.UUID, hostname string, now time.Time) *CustomDomain {
+	return &CustomDomain{
+		ID:                uuid.New(),
+		OrganizationID:    organizationID,
+		Hostname:          hostname,
+		VerificationToken: strings.ReplaceAll(uuid.NewString(), "-", ""),
+		CreatedAt:         now,
+	}
+}
+
+// IsVerified checks whether the ownership of the domain is verified
+func (d *CustomDomain) IsVerified() bool {
+	return d.VerifiedAt != nil
+}
+
+// VerificationRecordName is the name of the TXT record the organization creates to verify the domain
+func (d *CustomDomain) VerificationRecordName() string {
+	return customDomainVerificationRecord + "." + d.Hostname
+}
+
+// VerificationRecordValue is the value of the TXT record the organization creates to verify the domain
+func (d *CustomDomain) VerificationRecordValue() string {
+	return "baralga-verification=" + d.VerificationToken
+}
+
+// NormalizeHostname lowercases the host of a request and strips the port and trailing dot
+func NormalizeHostname(host string) string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// IsValidHostname checks whether the hostname is a fully qualified domain name
+func IsValidHostname(hostname string) bool {
+	return len(hostname) <= maxHostnameLength && hostnamePattern.MatchString(hostname)
+}
+
+// CustomDomainOf reads the verified custom domain the request was sent to, nil for the host of the instance
+func CustomDomainOf(ctx context.Context) *CustomDomain {
+	customDomain, ok := ctx.Value(ContextKeyCustomDomain).(*CustomDomain)
+	if !ok {
+		return nil
+	}
+	return customDomain
+}
+
+// NewCertificateHook creates the hook of the configured url, which is nil if none is configured
+func NewCertificateHook(config *Config) CertificateHook {
+	if config.CustomDomainCertificateHook == "" {
+		return nil
+	}
+
+	return &webhookCertificateHook{
+		url:        config.CustomDomainCertificateHook,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// webhookCertificateHook posts the verified and removed custom domains to a webhook of the infrastructure
+type webhookCertificateHook struct {
+	url        string
+	httpClient *http.Client
+}
+
+func (h *webhookCertificateHook) DomainVerified(ctx context.Context, customDomain *CustomDomain) error {
+	return h.post(ctx, "domain.verified", customDomain)
+}
+
+func (h *webhookCertificateHook) DomainRemoved(ctx context.Context, customDomain *CustomDomain) error {
+	return h.post(ctx, "domain.removed", customDomain)
+}
+
+func (h *webhookCertificateHook) post(ctx context.Context, event string, customDomain *CustomDomain) error {
+	body, err := json.Marshal(map[string]string{
+		"event":          event,
+		"hostname":       customDomain.Hostname,
+		"organizationId": customDomain.OrganizationID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := h.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not call certificate hook")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return errors.Errorf("certificate hook responded with status %v", res.StatusCode)
+	}
+	return nil
+}
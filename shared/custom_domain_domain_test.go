@@ -0,0 +1,70 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestNormalizeHostname(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(NormalizeHostname("Time.Example.com:8443"), "time.example.com")
+	is.Equal(NormalizeHostname("time.example.com."), "time.example.com")
+	is.Equal(NormalizeHostname("localhost"), "localhost")
+}
+
+func TestIsValidHostname(t *testing.T) {
+	is := is.New(t)
+
+	is.True(IsValidHostname("time.example.com"))
+	is.True(IsValidHostname("example.io"))
+	is.True(!IsValidHostname("localhost"))
+	is.True(!IsValidHostname("-time.example.com"))
+	is.True(!IsValidHostname("time example.com"))
+	is.True(!IsValidHostname(strings.Repeat("a.", 127) + "com"))
+}
+
+func TestCustomDomainVerificationRecord(t *testing.T) {
+	is := is.New(t)
+
+	customDomain := NewCustomDomain(OrganizationIDSample, "time.example.com", time.Now())
+
+	is.True(!customDomain.IsVerified())
+	is.Equal(customDomain.VerificationRecordName(), "_baralga-verification.time.example.com")
+	is.Equal(customDomain.VerificationRecordValue(), "baralga-verification="+customDomain.VerificationToken)
+	is.Equal(len(customDomain.VerificationToken), 32)
+}
+
+func TestWebhookCertificateHook(t *testing.T) {
+	is := is.New(t)
+
+	var events []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	is.Equal(NewCertificateHook(&Config{}), nil)
+
+	certificateHook := NewCertificateHook(&Config{CustomDomainCertificateHook: server.URL})
+	customDomain := NewCustomDomain(OrganizationIDSample, "time.example.com", time.Now())
+
+	err := certificateHook.DomainVerified(context.Background(), customDomain)
+	is.NoErr(err)
+	err = certificateHook.DomainRemoved(context.Background(), customDomain)
+	is.NoErr(err)
+
+	is.Equal(len(events), 2)
+	is.Equal(events[0]["event"], "domain.verified")
+	is.Equal(events[0]["hostname"], "time.example.com")
+	is.Equal(events[1]["event"], "domain.removed")
+}
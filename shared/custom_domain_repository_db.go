@@ -0,0 +1,178 @@
+package shared
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbCustomDomainRepository is a SQL database repository for the custom domains of organizations
+type DbCustomDomainRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ CustomDomainRepository = (*DbCustomDomainRepository)(nil)
+
+// NewDbCustomDomainRepository creates a new SQL database repository for the custom domains of organizations
+func NewDbCustomDomainRepository(connPool *pgxpool.Pool) *DbCustomDomainRepository {
+	return &DbCustomDomainRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbCustomDomainRepository) FindCustomDomains(ctx context.Context, organizationID uuid.UUID) ([]*CustomDomain, error) {
+	rows, err := SelectAll[customDomainRow](
+		ctx,
+		r.connPool,
+		`SELECT `+Columns[customDomainRow]()+`
+		 FROM custom_domains
+		 WHERE org_id = $1
+		 ORDER BY hostname`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	customDomains := make([]*CustomDomain, len(rows))
+	for i, row := range rows {
+		customDomains[i] = row.toCustomDomain()
+	}
+	return customDomains, nil
+}
+
+func (r *DbCustomDomainRepository) FindCustomDomainByID(ctx context.Context, organizationID, customDomainID uuid.UUID) (*CustomDomain, error) {
+	row, err := SelectOne[customDomainRow](
+		ctx,
+		r.connPool,
+		`SELECT `+Columns[customDomainRow]()+`
+		 FROM custom_domains
+		 WHERE custom_domain_id = $1 AND org_id = $2`,
+		customDomainID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCustomDomainNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toCustomDomain(), nil
+}
+
+// FindVerifiedCustomDomainByHostname reads the verified custom domain of any organization by its hostname, it's used to route requests by host
+func (r *DbCustomDomainRepository) FindVerifiedCustomDomainByHostname(ctx context.Context, hostname string) (*CustomDomain, error) {
+	row, err := SelectOne[customDomainRow](
+		ctx,
+		r.connPool,
+		`SELECT `+Columns[customDomainRow]()+`
+		 FROM custom_domains
+		 WHERE hostname = $1 AND verified_at IS NOT NULL`,
+		hostname,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCustomDomainNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toCustomDomain(), nil
+}
+
+func (r *DbCustomDomainRepository) InsertCustomDomain(ctx context.Context, customDomain *CustomDomain) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO custom_domains
+		   (custom_domain_id, org_id, hostname, verification_token, verified_at, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6)`,
+		customDomain.ID,
+		customDomain.OrganizationID,
+		customDomain.Hostname,
+		customDomain.VerificationToken,
+		customDomain.VerifiedAt,
+		customDomain.CreatedAt,
+	)
+	if isUniqueViolation(err) {
+		return ErrCustomDomainTaken
+	}
+	return err
+}
+
+func (r *DbCustomDomainRepository) UpdateCustomDomainVerifiedAt(ctx context.Context, organizationID, customDomainID uuid.UUID, verifiedAt time.Time) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE custom_domains
+		 SET verified_at = $3
+		 WHERE custom_domain_id = $1 AND org_id = $2`,
+		customDomainID, organizationID, verifiedAt,
+	)
+	if isUniqueViolation(err) {
+		return ErrCustomDomainTaken
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCustomDomainNotFound
+	}
+	return nil
+}
+
+func (r *DbCustomDomainRepository) DeleteCustomDomainByID(ctx context.Context, organizationID, customDomainID uuid.UUID) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM custom_domains
+		 WHERE custom_domain_id = $1 AND org_id = $2`,
+		customDomainID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCustomDomainNotFound
+	}
+	return nil
+}
+
+type customDomainRow struct {
+	ID                uuid.UUID  `db:"custom_domain_id"`
+	OrganizationID    uuid.UUID  `db:"org_id"`
+	Hostname          string     `db:"hostname"`
+	VerificationToken string     `db:"verification_token"`
+	VerifiedAt        *time.Time `db:"verified_at"`
+	CreatedAt         time.Time  `db:"created_at"`
+}
+
+func (r *customDomainRow) toCustomDomain() *CustomDomain {
+	return &CustomDomain{
+		ID:                r.ID,
+		OrganizationID:    r.OrganizationID,
+		Hostname:          r.Hostname,
+		VerificationToken: r.VerificationToken,
+		VerifiedAt:        r.VerifiedAt,
+		CreatedAt:         r.CreatedAt,
+	}
+}
+
+// isUniqueViolation checks whether the error is a violation of a unique index
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,78 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestCustomDomainRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	customDomainRepository := NewDbCustomDomainRepository(connPool)
+	repositoryTxer := NewDbRepositoryTxer(connPool)
+	customDomain := NewCustomDomain(OrganizationIDSample, "time.example.com", time.Now().UTC().Truncate(time.Second))
+
+	t.Run("InsertCustomDomain", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return customDomainRepository.InsertCustomDomain(ctx, customDomain)
+			},
+		)
+		is.NoErr(err)
+
+		customDomains, err := customDomainRepository.FindCustomDomains(context.Background(), OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(customDomains), 1)
+		is.Equal(customDomains[0].VerificationToken, customDomain.VerificationToken)
+	})
+
+	t.Run("UpdateCustomDomainVerifiedAt", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return customDomainRepository.UpdateCustomDomainVerifiedAt(ctx, OrganizationIDSample, customDomain.ID, time.Now())
+			},
+		)
+		is.NoErr(err)
+
+		found, err := customDomainRepository.FindVerifiedCustomDomainByHostname(context.Background(), "time.example.com")
+		is.NoErr(err)
+		is.True(found.IsVerified())
+	})
+
+	t.Run("DeleteCustomDomainByID", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return customDomainRepository.DeleteCustomDomainByID(ctx, OrganizationIDSample, customDomain.ID)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = customDomainRepository.FindCustomDomainByID(context.Background(), OrganizationIDSample, customDomain.ID)
+		is.Equal(err, ErrCustomDomainNotFound)
+	})
+}
@@ -0,0 +1,111 @@
+package shared
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemCustomDomainRepository struct {
+	mu            sync.Mutex
+	customDomains []*CustomDomain
+}
+
+var _ CustomDomainRepository = (*InMemCustomDomainRepository)(nil)
+
+func NewInMemCustomDomainRepository() *InMemCustomDomainRepository {
+	return &InMemCustomDomainRepository{}
+}
+
+func (r *InMemCustomDomainRepository) FindCustomDomains(ctx context.Context, organizationID uuid.UUID) ([]*CustomDomain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var customDomains []*CustomDomain
+	for _, d := range r.customDomains {
+		if d.OrganizationID == organizationID {
+			customDomain := *d
+			customDomains = append(customDomains, &customDomain)
+		}
+	}
+
+	sort.Slice(customDomains, func(i, j int) bool {
+		return customDomains[i].Hostname < customDomains[j].Hostname
+	})
+	return customDomains, nil
+}
+
+func (r *InMemCustomDomainRepository) FindCustomDomainByID(ctx context.Context, organizationID, customDomainID uuid.UUID) (*CustomDomain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.customDomains {
+		if d.ID == customDomainID && d.OrganizationID == organizationID {
+			customDomain := *d
+			return &customDomain, nil
+		}
+	}
+	return nil, ErrCustomDomainNotFound
+}
+
+func (r *InMemCustomDomainRepository) FindVerifiedCustomDomainByHostname(ctx context.Context, hostname string) (*CustomDomain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.customDomains {
+		if d.Hostname == hostname && d.IsVerified() {
+			customDomain := *d
+			return &customDomain, nil
+		}
+	}
+	return nil, ErrCustomDomainNotFound
+}
+
+func (r *InMemCustomDomainRepository) InsertCustomDomain(ctx context.Context, customDomain *CustomDomain) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.customDomains {
+		if d.Hostname == customDomain.Hostname && (d.OrganizationID == customDomain.OrganizationID || d.IsVerified() && customDomain.IsVerified()) {
+			return ErrCustomDomainTaken
+		}
+	}
+
+	inserted := *customDomain
+	r.customDomains = append(r.customDomains, &inserted)
+	return nil
+}
+
+func (r *InMemCustomDomainRepository) UpdateCustomDomainVerifiedAt(ctx context.Context, organizationID, customDomainID uuid.UUID, verifiedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.customDomains {
+		if d.ID == customDomainID && d.OrganizationID == organizationID {
+			for _, other := range r.customDomains {
+				if other.ID != d.ID && other.Hostname == d.Hostname && other.IsVerified() {
+					return ErrCustomDomainTaken
+				}
+			}
+			d.VerifiedAt = &verifiedAt
+			return nil
+		}
+	}
+	return ErrCustomDomainNotFound
+}
+
+func (r *InMemCustomDomainRepository) DeleteCustomDomainByID(ctx context.Context, organizationID, customDomainID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, d := range r.customDomains {
+		if d.ID == customDomainID && d.OrganizationID == organizationID {
+			r.customDomains = append(r.customDomains[:i], r.customDomains[i+1:]...)
+			return nil
+		}
+	}
+	return ErrCustomDomainNotFound
+}
@@ -0,0 +1,202 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type customDomainModel struct {
+	ID                 string                   `json:"id"`
+	Hostname           string                   `json:"hostname" validate:"required,max=253"`
+	VerifiedAt         string                   `json:"verifiedAt,omitempty"`
+	VerificationRecord *verificationRecordModel `json:"verificationRecord,omitempty"`
+	Links              *hal.Links               `json:"_links"`
+}
+
+type verificationRecordModel struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type embeddedCustomDomains struct {
+	CustomDomainModels []*customDomainModel `json:"customDomains"`
+}
+
+type customDomainsModel struct {
+	Embedded *embeddedCustomDomains `json:"_embedded"`
+	Links    *hal.Links             `json:"_links"`
+}
+
+type CustomDomainRestHandlers struct {
+	config              *Config
+	customDomainService *CustomDomainService
+}
+
+func NewCustomDomainRestHandlers(config *Config, customDomainService *CustomDomainService) *CustomDomainRestHandlers {
+	return &CustomDomainRestHandlers{
+		config:              config,
+		customDomainService: customDomainService,
+	}
+}
+
+func (a *CustomDomainRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/custom-domains", a.HandleGetCustomDomains())
+	r.Post("/custom-domains", a.HandleCreateCustomDomain())
+	r.Post("/custom-domains/{custom-domain-id}/verification", a.HandleVerifyCustomDomain())
+	r.Delete("/custom-domains/{custom-domain-id}", a.HandleDeleteCustomDomain())
+}
+
+func (a *CustomDomainRestHandlers) RegisterOpen(r chi.Router) {
+	r.Get("/custom-domains/certificate-check", a.HandleCertificateCheck())
+}
+
+// HandleGetCustomDomains reads the custom domains of the principal's organization
+func (a *CustomDomainRestHandlers) HandleGetCustomDomains() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	customDomainService := a.customDomainService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		customDomains, err := customDomainService.ReadCustomDomains(r.Context(), principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		customDomainModels := make([]*customDomainModel, len(customDomains))
+		for i, customDomain := range customDomains {
+			customDomainModels[i] = mapToCustomDomainModel(customDomain)
+		}
+
+		RenderJSON(w, &customDomainsModel{
+			Embedded: &embeddedCustomDomains{
+				CustomDomainModels: customDomainModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleCreateCustomDomain adds a custom domain, the response contains the TXT record to verify the domain
+func (a *CustomDomainRestHandlers) HandleCreateCustomDomain() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := NewValidator()
+	customDomainService := a.customDomainService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		var customDomainModel customDomainModel
+		err := json.NewDecoder(r.Body).Decode(&customDomainModel)
+		if err != nil {
+			RenderValidationProblemJSON(w, "custom domain not valid", err)
+			return
+		}
+
+		err = validator.Struct(customDomainModel)
+		if err != nil {
+			RenderValidationProblemJSON(w, "custom domain not valid", err)
+			return
+		}
+
+		customDomain, err := customDomainService.AddCustomDomain(r.Context(), principal, customDomainModel.Hostname, time.Now())
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		RenderJSON(w, mapToCustomDomainModel(customDomain))
+	}
+}
+
+// HandleVerifyCustomDomain verifies a custom domain by its TXT record
+func (a *CustomDomainRestHandlers) HandleVerifyCustomDomain() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	customDomainService := a.customDomainService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		customDomainID, err := uuid.Parse(chi.URLParam(r, "custom-domain-id"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		customDomain, err := customDomainService.VerifyCustomDomain(r.Context(), principal, customDomainID, time.Now())
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToCustomDomainModel(customDomain))
+	}
+}
+
+// HandleDeleteCustomDomain removes a custom domain
+func (a *CustomDomainRestHandlers) HandleDeleteCustomDomain() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	customDomainService := a.customDomainService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		customDomainID, err := uuid.Parse(chi.URLParam(r, "custom-domain-id"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err = customDomainService.DeleteCustomDomain(r.Context(), principal, customDomainID)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleCertificateCheck answers whether a certificate may be issued for the domain, reverse proxies
+// issuing certificates on demand ask it before requesting a certificate
+func (a *CustomDomainRestHandlers) HandleCertificateCheck() http.HandlerFunc {
+	customDomainService := a.customDomainService
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := customDomainService.HostPolicy(r.Context(), r.URL.Query().Get("domain"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func mapToCustomDomainModel(customDomain *CustomDomain) *customDomainModel {
+	links := []*hal.Links{hal.NewSelfLink(fmt.Sprintf("/api/custom-domains/%s", customDomain.ID))}
+	customDomainModel := &customDomainModel{
+		ID:       customDomain.ID.String(),
+		Hostname: customDomain.Hostname,
+	}
+
+	if customDomain.IsVerified() {
+		customDomainModel.VerifiedAt = customDomain.VerifiedAt.UTC().Format(time.RFC3339)
+	} else {
+		customDomainModel.VerificationRecord = &verificationRecordModel{
+			Type:  "TXT",
+			Name:  customDomain.VerificationRecordName(),
+			Value: customDomain.VerificationRecordValue(),
+		}
+		links = append(links, hal.NewLink("verify", fmt.Sprintf("/api/custom-domains/%s/verification", customDomain.ID)))
+	}
+
+	customDomainModel.Links = hal.NewLinks(links...)
+	return customDomainModel
+}
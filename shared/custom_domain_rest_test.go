@@ -0,0 +1,83 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestCustomDomainRestHandlers(t *testing.T) {
+	is := is.New(t)
+
+	customDomainService, resolver, _ := newInMemCustomDomainService()
+	a := NewCustomDomainRestHandlers(&Config{}, customDomainService)
+	router := chi.NewRouter()
+	a.RegisterOpen(router)
+	a.RegisterProtected(router)
+
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	request := func(method, path, body string, principal *Principal) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest(method, path, strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, principal))
+		router.ServeHTTP(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := request("POST", "/custom-domains", `{"hostname": "time.example.com"}`, admin)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	customDomainModel := &customDomainModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(customDomainModel)
+	is.NoErr(err)
+	is.Equal(customDomainModel.VerificationRecord.Type, "TXT")
+	is.Equal(customDomainModel.VerificationRecord.Name, "_baralga-verification.time.example.com")
+
+	t.Run("create custom domain not valid", func(t *testing.T) {
+		httpRec := request("POST", "/custom-domains", `{"hostname": "localhost"}`, admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("verification fails without record", func(t *testing.T) {
+		httpRec := request("POST", fmt.Sprintf("/custom-domains/%s/verification", customDomainModel.ID), "", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusConflict)
+
+		httpRec = request("GET", "/custom-domains/certificate-check?domain=time.example.com", "", nil)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+	})
+
+	t.Run("verify custom domain", func(t *testing.T) {
+		resolver.records[customDomainModel.VerificationRecord.Name] = []string{customDomainModel.VerificationRecord.Value}
+
+		httpRec := request("POST", fmt.Sprintf("/custom-domains/%s/verification", customDomainModel.ID), "", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.True(strings.Contains(httpRec.Body.String(), `"verifiedAt"`))
+
+		httpRec = request("GET", "/custom-domains/certificate-check?domain=time.example.com", "", nil)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	})
+
+	t.Run("read custom domains", func(t *testing.T) {
+		httpRec := request("GET", "/custom-domains", "", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.True(strings.Contains(httpRec.Body.String(), `"hostname":"time.example.com"`))
+
+		httpRec = request("GET", "/custom-domains", "", &Principal{OrganizationID: OrganizationIDSample, Roles: []string{"ROLE_USER"}})
+		is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	})
+
+	t.Run("delete custom domain", func(t *testing.T) {
+		httpRec := request("DELETE", fmt.Sprintf("/custom-domains/%s", customDomainModel.ID), "", admin)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+
+		httpRec = request("GET", "/custom-domains/certificate-check?domain=time.example.com", "", nil)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,196 @@
+package shared
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// CustomDomainService manages the custom domains of organizations and routes requests by host,
+// the certificate hook is nil if certificates are not handled by the instance
+type CustomDomainService struct {
+	config                 *Config
+	repositoryTxer         RepositoryTxer
+	customDomainRepository CustomDomainRepository
+	resolver               TXTResolver
+	certificateHook        CertificateHook
+}
+
+// NewCustomDomainService creates a new service for custom domains
+func NewCustomDomainService(config *Config, repositoryTxer RepositoryTxer, customDomainRepository CustomDomainRepository, resolver TXTResolver, certificateHook CertificateHook) *CustomDomainService {
+	return &CustomDomainService{
+		config:                 config,
+		repositoryTxer:         repositoryTxer,
+		customDomainRepository: customDomainRepository,
+		resolver:               resolver,
+		certificateHook:        certificateHook,
+	}
+}
+
+// ReadCustomDomains reads the custom domains of the principal's organization
+func (s *CustomDomainService) ReadCustomDomains(ctx context.Context, principal *Principal) ([]*CustomDomain, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrForbidden
+	}
+
+	return s.customDomainRepository.FindCustomDomains(ctx, principal.OrganizationID)
+}
+
+// ReadCustomDomain reads a custom domain of the principal's organization
+func (s *CustomDomainService) ReadCustomDomain(ctx context.Context, principal *Principal, customDomainID uuid.UUID) (*CustomDomain, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrForbidden
+	}
+
+	return s.customDomainRepository.FindCustomDomainByID(ctx, principal.OrganizationID, customDomainID)
+}
+
+// AddCustomDomain adds an unverified custom domain to the principal's organization, the organization
+// verifies the domain by creating the verification TXT record. Several organizations may claim a hostname
+// until one of them verifies it, so only a verified domain is taken.
+func (s *CustomDomainService) AddCustomDomain(ctx context.Context, principal *Principal, hostname string, now time.Time) (*CustomDomain, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrForbidden
+	}
+
+	hostname = NormalizeHostname(hostname)
+	if !IsValidHostname(hostname) || hostname == s.instanceHostname() {
+		return nil, ErrCustomDomainNotValid
+	}
+
+	_, err := s.customDomainRepository.FindVerifiedCustomDomainByHostname(withoutOrganization(ctx), hostname)
+	if err == nil {
+		return nil, ErrCustomDomainTaken
+	}
+	if !errors.Is(err, ErrCustomDomainNotFound) {
+		return nil, err
+	}
+
+	customDomain := NewCustomDomain(principal.OrganizationID, hostname, now)
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.customDomainRepository.InsertCustomDomain(ctx, customDomain)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return customDomain, nil
+}
+
+// VerifyCustomDomain looks up the verification TXT record of the custom domain and marks the domain as verified
+// if the record is found. The certificate hook is notified of the verified domain afterwards.
+func (s *CustomDomainService) VerifyCustomDomain(ctx context.Context, principal *Principal, customDomainID uuid.UUID, now time.Time) (*CustomDomain, error) {
+	customDomain, err := s.ReadCustomDomain(ctx, principal, customDomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customDomain.IsVerified() {
+		return customDomain, nil
+	}
+
+	records, err := s.resolver.LookupTXT(ctx, customDomain.VerificationRecordName())
+	if err != nil || !slices.Contains(records, customDomain.VerificationRecordValue()) {
+		return nil, ErrCustomDomainVerificationFailed
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.customDomainRepository.UpdateCustomDomainVerifiedAt(ctx, principal.OrganizationID, customDomainID, now)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	customDomain.VerifiedAt = &now
+
+	if s.certificateHook != nil {
+		err = s.certificateHook.DomainVerified(ctx, customDomain)
+		if err != nil {
+			log.Printf("could not notify certificate hook of verified domain %s: %s", customDomain.Hostname, err)
+		}
+	}
+
+	return customDomain, nil
+}
+
+// DeleteCustomDomain removes a custom domain of the principal's organization, the certificate hook
+// is notified if the domain was verified
+func (s *CustomDomainService) DeleteCustomDomain(ctx context.Context, principal *Principal, customDomainID uuid.UUID) error {
+	customDomain, err := s.ReadCustomDomain(ctx, principal, customDomainID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.customDomainRepository.DeleteCustomDomainByID(ctx, principal.OrganizationID, customDomainID)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if s.certificateHook != nil && customDomain.IsVerified() {
+		err = s.certificateHook.DomainRemoved(ctx, customDomain)
+		if err != nil {
+			log.Printf("could not notify certificate hook of removed domain %s: %s", customDomain.Hostname, err)
+		}
+	}
+
+	return nil
+}
+
+// HostPolicy allows certificates only for verified custom domains, it fits the host policy
+// of automatic certificate managers like autocert
+func (s *CustomDomainService) HostPolicy(ctx context.Context, host string) error {
+	_, err := s.customDomainRepository.FindVerifiedCustomDomainByHostname(ctx, NormalizeHostname(host))
+	return err
+}
+
+// CustomDomainMiddleware routes requests by host, requests to a verified custom domain carry the
+// custom domain in the request context. Requests to the host of the instance are passed through.
+func (s *CustomDomainService) CustomDomainMiddleware() func(http.Handler) http.Handler {
+	instanceHostname := s.instanceHostname()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hostname := NormalizeHostname(r.Host)
+			if hostname == "" || hostname == instanceHostname {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			customDomain, err := s.customDomainRepository.FindVerifiedCustomDomainByHostname(r.Context(), hostname)
+			if err != nil {
+				if !errors.Is(err, ErrCustomDomainNotFound) {
+					log.Printf("could not read custom domain %s: %s", hostname, err)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ContextKeyCustomDomain, customDomain)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// instanceHostname is the hostname of the web root of the instance
+func (s *CustomDomainService) instanceHostname() string {
+	webroot, err := url.Parse(s.config.Webroot)
+	if err != nil {
+		return ""
+	}
+	return NormalizeHostname(webroot.Host)
+}
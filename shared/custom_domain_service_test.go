@@ -0,0 +1,208 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+type inMemTXTResolver struct {
+	records map[string][]string
+}
+
+func (r *inMemTXTResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return r.records[name], nil
+}
+
+type inMemCertificateHook struct {
+	verified []string
+	removed  []string
+}
+
+func (h *inMemCertificateHook) DomainVerified(ctx context.Context, customDomain *CustomDomain) error {
+	h.verified = append(h.verified, customDomain.Hostname)
+	return nil
+}
+
+func (h *inMemCertificateHook) DomainRemoved(ctx context.Context, customDomain *CustomDomain) error {
+	h.removed = append(h.removed, customDomain.Hostname)
+	return nil
+}
+
+func newInMemCustomDomainService() (*CustomDomainService, *inMemTXTResolver, *inMemCertificateHook) {
+	resolver := &inMemTXTResolver{records: make(map[string][]string)}
+	certificateHook := &inMemCertificateHook{}
+	customDomainService := NewCustomDomainService(
+		&Config{Webroot: "https://app.baralga.com"},
+		NewInMemRepositoryTxer(),
+		NewInMemCustomDomainRepository(),
+		resolver,
+		certificateHook,
+	)
+	return customDomainService, resolver, certificateHook
+}
+
+func TestAddCustomDomain(t *testing.T) {
+	is := is.New(t)
+
+	customDomainService, _, _ := newInMemCustomDomainService()
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	t.Run("user must not add custom domain", func(t *testing.T) {
+		_, err := customDomainService.AddCustomDomain(context.Background(), &Principal{OrganizationID: OrganizationIDSample, Roles: []string{"ROLE_USER"}}, "time.example.com", time.Now())
+		is.Equal(err, ErrForbidden)
+	})
+
+	t.Run("admin adds custom domain", func(t *testing.T) {
+		customDomain, err := customDomainService.AddCustomDomain(context.Background(), admin, "Time.Example.com", time.Now())
+		is.NoErr(err)
+		is.Equal(customDomain.Hostname, "time.example.com")
+		is.True(!customDomain.IsVerified())
+
+		customDomains, err := customDomainService.ReadCustomDomains(context.Background(), admin)
+		is.NoErr(err)
+		is.Equal(len(customDomains), 1)
+	})
+
+	t.Run("custom domain is taken", func(t *testing.T) {
+		_, err := customDomainService.AddCustomDomain(context.Background(), admin, "time.example.com", time.Now())
+		is.Equal(err, ErrCustomDomainTaken)
+	})
+
+	t.Run("custom domain not valid", func(t *testing.T) {
+		_, err := customDomainService.AddCustomDomain(context.Background(), admin, "localhost", time.Now())
+		is.Equal(err, ErrCustomDomainNotValid)
+
+		_, err = customDomainService.AddCustomDomain(context.Background(), admin, "app.baralga.com", time.Now())
+		is.Equal(err, ErrCustomDomainNotValid)
+	})
+}
+
+func TestClaimCustomDomainOfOtherOrganization(t *testing.T) {
+	is := is.New(t)
+
+	customDomainService, resolver, _ := newInMemCustomDomainService()
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	otherAdmin := &Principal{OrganizationID: uuid.New(), Username: "other", Roles: []string{"ROLE_ADMIN"}}
+
+	customDomain, err := customDomainService.AddCustomDomain(context.Background(), admin, "time.example.com", time.Now())
+	is.NoErr(err)
+
+	otherCustomDomain, err := customDomainService.AddCustomDomain(context.Background(), otherAdmin, "time.example.com", time.Now())
+	is.NoErr(err)
+
+	resolver.records[otherCustomDomain.VerificationRecordName()] = []string{otherCustomDomain.VerificationRecordValue()}
+	_, err = customDomainService.VerifyCustomDomain(context.Background(), otherAdmin, otherCustomDomain.ID, time.Now())
+	is.NoErr(err)
+
+	t.Run("verified custom domain is taken", func(t *testing.T) {
+		_, err := customDomainService.AddCustomDomain(context.Background(), &Principal{OrganizationID: uuid.New(), Roles: []string{"ROLE_ADMIN"}}, "time.example.com", time.Now())
+		is.Equal(err, ErrCustomDomainTaken)
+	})
+
+	t.Run("unverified claim can't be verified", func(t *testing.T) {
+		resolver.records[customDomain.VerificationRecordName()] = append(resolver.records[customDomain.VerificationRecordName()], customDomain.VerificationRecordValue())
+
+		_, err := customDomainService.VerifyCustomDomain(context.Background(), admin, customDomain.ID, time.Now())
+		is.Equal(err, ErrCustomDomainTaken)
+	})
+}
+
+func TestVerifyAndDeleteCustomDomain(t *testing.T) {
+	is := is.New(t)
+
+	customDomainService, resolver, certificateHook := newInMemCustomDomainService()
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	customDomain, err := customDomainService.AddCustomDomain(context.Background(), admin, "time.example.com", time.Now())
+	is.NoErr(err)
+
+	t.Run("verification record missing", func(t *testing.T) {
+		_, err := customDomainService.VerifyCustomDomain(context.Background(), admin, customDomain.ID, time.Now())
+		is.Equal(err, ErrCustomDomainVerificationFailed)
+
+		err = customDomainService.HostPolicy(context.Background(), "time.example.com")
+		is.Equal(err, ErrCustomDomainNotFound)
+	})
+
+	t.Run("verification record found", func(t *testing.T) {
+		resolver.records[customDomain.VerificationRecordName()] = []string{"v=spf1 -all", customDomain.VerificationRecordValue()}
+
+		verifiedDomain, err := customDomainService.VerifyCustomDomain(context.Background(), admin, customDomain.ID, time.Now())
+		is.NoErr(err)
+		is.True(verifiedDomain.IsVerified())
+		is.Equal(certificateHook.verified, []string{"time.example.com"})
+
+		err = customDomainService.HostPolicy(context.Background(), "time.example.com")
+		is.NoErr(err)
+	})
+
+	t.Run("delete custom domain", func(t *testing.T) {
+		err := customDomainService.DeleteCustomDomain(context.Background(), admin, customDomain.ID)
+		is.NoErr(err)
+		is.Equal(certificateHook.removed, []string{"time.example.com"})
+
+		err = customDomainService.DeleteCustomDomain(context.Background(), admin, customDomain.ID)
+		is.Equal(err, ErrCustomDomainNotFound)
+	})
+}
+
+func TestCustomDomainMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	customDomainService, resolver, _ := newInMemCustomDomainService()
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	customDomain, err := customDomainService.AddCustomDomain(context.Background(), admin, "time.example.com", time.Now())
+	is.NoErr(err)
+
+	var routedDomain *CustomDomain
+	handler := customDomainService.CustomDomainMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		routedDomain = CustomDomainOf(r.Context())
+	}))
+	request := func(host string) *CustomDomain {
+		r, _ := http.NewRequest("GET", "/login", nil)
+		r.Host = host
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		return routedDomain
+	}
+
+	is.Equal(request("time.example.com"), nil)
+
+	resolver.records[customDomain.VerificationRecordName()] = []string{customDomain.VerificationRecordValue()}
+	_, err = customDomainService.VerifyCustomDomain(context.Background(), admin, customDomain.ID, time.Now())
+	is.NoErr(err)
+
+	is.Equal(request("Time.Example.com:443").OrganizationID, OrganizationIDSample)
+	is.Equal(request("app.baralga.com"), nil)
+	is.Equal(request("other.example.com"), nil)
+}
+
+func TestBrandingMiddlewareOnCustomDomain(t *testing.T) {
+	is := is.New(t)
+
+	brandingService := newInMemBrandingService()
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := brandingService.UpdateBranding(context.Background(), admin, "#5b3cc4", "")
+	is.NoErr(err)
+	_, err = brandingService.UploadLogo(context.Background(), admin, newBrandingLogoSample(t), "image/png")
+	is.NoErr(err)
+
+	var appearance *Appearance
+	handler := brandingService.BrandingMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		appearance = AppearanceOf(r.Context())
+	}))
+
+	r, _ := http.NewRequest("GET", "/login", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyCustomDomain, &CustomDomain{OrganizationID: OrganizationIDSample}))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	is.Equal(appearance.AccentColor, "#5b3cc4")
+	is.Equal(appearance.LogoURL[:len(customDomainLogoPath)], customDomainLogoPath)
+}
@@ -0,0 +1,162 @@
+package shared
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const encryptedValuePrefix = "enc:v1:"
+
+var ErrDecryptionFailed = errors.New("decryption failed")
+
+// HashToken is the hash of a token as stored to look the token up, the tokens are random so no salt is needed
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// Encrypter encrypts sensitive values at rest with AES-GCM. Values are encrypted with the current key
+// and decrypted with the key they were encrypted with, so keys can be rotated.
+type Encrypter struct {
+	currentKeyID string
+	keys         map[string]cipher.AEAD
+}
+
+// NewEncrypter creates an encrypter of comma separated keys like 2:base64key,1:base64key
+// with the first key being the current key. Keys have 32 bytes for AES-256.
+func NewEncrypter(encryptionKeys string) (*Encrypter, error) {
+	e := &Encrypter{
+		keys: make(map[string]cipher.AEAD),
+	}
+
+	for _, encryptionKey := range strings.Split(encryptionKeys, ",") {
+		encryptionKey = strings.TrimSpace(encryptionKey)
+		if encryptionKey == "" {
+			continue
+		}
+
+		keyID, encodedKey, ok := strings.Cut(encryptionKey, ":")
+		if !ok || keyID == "" {
+			return nil, errors.New("encryption key must be given as id:base64key")
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode encryption key %s", keyID)
+		}
+		if len(key) != 32 {
+			return nil, errors.Errorf("encryption key %s must have 32 bytes", keyID)
+		}
+		if _, ok := e.keys[keyID]; ok {
+			return nil, errors.Errorf("duplicate encryption key %s", keyID)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		if e.currentKeyID == "" {
+			e.currentKeyID = keyID
+		}
+		e.keys[keyID] = aead
+	}
+
+	if e.currentKeyID == "" {
+		return nil, errors.New("no encryption key given")
+	}
+
+	return e, nil
+}
+
+// Encrypt encrypts the value with the current key
+func (e *Encrypter) Encrypt(plaintext string) (string, error) {
+	aead := e.keys[e.currentKeyID]
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext := aead.Seal(nonce, nonce, []byte(plaintext), []byte(e.currentKeyID))
+	return fmt.Sprintf("%s%s:%s", encryptedValuePrefix, e.currentKeyID, base64.StdEncoding.EncodeToString(ciphertext)), nil
+}
+
+// Decrypt decrypts the value with the key it was encrypted with
+func (e *Encrypter) Decrypt(value string) (string, error) {
+	keyID, ciphertext, err := e.parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	aead := e.keys[keyID]
+	nonceSize := aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return "", ErrDecryptionFailed
+	}
+
+	plaintext, err := aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], []byte(keyID))
+	if err != nil {
+		return "", ErrDecryptionFailed
+	}
+
+	return string(plaintext), nil
+}
+
+// IsCurrent checks whether the value is encrypted with the current key
+func (e *Encrypter) IsCurrent(value string) bool {
+	keyID, _, err := e.parse(value)
+	return err == nil && keyID == e.currentKeyID
+}
+
+// Rotate encrypts the value with the current key if it was encrypted with an older key
+// or if it was stored before its column was encrypted
+func (e *Encrypter) Rotate(value string) (string, error) {
+	if e.IsCurrent(value) {
+		return value, nil
+	}
+	if !strings.HasPrefix(value, encryptedValuePrefix) {
+		return e.Encrypt(value)
+	}
+
+	plaintext, err := e.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+
+	return e.Encrypt(plaintext)
+}
+
+func (e *Encrypter) parse(value string) (string, []byte, error) {
+	if !strings.HasPrefix(value, encryptedValuePrefix) {
+		return "", nil, ErrDecryptionFailed
+	}
+
+	keyID, encodedCiphertext, ok := strings.Cut(strings.TrimPrefix(value, encryptedValuePrefix), ":")
+	if !ok {
+		return "", nil, ErrDecryptionFailed
+	}
+	if _, ok := e.keys[keyID]; !ok {
+		return "", nil, errors.Wrapf(ErrDecryptionFailed, "unknown encryption key %s", keyID)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encodedCiphertext)
+	if err != nil {
+		return "", nil, ErrDecryptionFailed
+	}
+
+	return keyID, ciphertext, nil
+}
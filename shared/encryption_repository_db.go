@@ -0,0 +1,97 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EncryptedColumn is a column with values encrypted at rest, which are re-encrypted when keys are rotated
+type EncryptedColumn struct {
+	Table    string
+	IDColumn string
+	Column   string
+}
+
+var (
+	encryptedColumnsMu sync.Mutex
+	encryptedColumns   []*EncryptedColumn
+)
+
+// RegisterEncryptedColumn adds the column to the columns re-encrypted on key rotation
+func RegisterEncryptedColumn(table, idColumn, column string) {
+	encryptedColumnsMu.Lock()
+	defer encryptedColumnsMu.Unlock()
+
+	encryptedColumns = append(encryptedColumns, &EncryptedColumn{
+		Table:    table,
+		IDColumn: idColumn,
+		Column:   column,
+	})
+}
+
+// RotateEncryptionKeys re-encrypts all values of the encrypted columns which are not
+// encrypted with the current key and returns the number of values re-encrypted,
+// plaintext values stored before their column was encrypted are encrypted as well
+func RotateEncryptionKeys(ctx context.Context, connPool *pgxpool.Pool, encrypter *Encrypter) (int, error) {
+	encryptedColumnsMu.Lock()
+	columns := append([]*EncryptedColumn(nil), encryptedColumns...)
+	encryptedColumnsMu.Unlock()
+
+	rotated := 0
+	for _, column := range columns {
+		err := pgx.BeginTxFunc(ctx, connPool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+			rows, err := tx.Query(
+				ctx,
+				fmt.Sprintf("SELECT %s::text, %s FROM %s WHERE %s IS NOT NULL FOR UPDATE", column.IDColumn, column.Column, column.Table, column.Column),
+			)
+			if err != nil {
+				return err
+			}
+
+			values := make(map[string]string)
+			for rows.Next() {
+				var id, value string
+				err = rows.Scan(&id, &value)
+				if err != nil {
+					rows.Close()
+					return err
+				}
+				if !encrypter.IsCurrent(value) {
+					values[id] = value
+				}
+			}
+			rows.Close()
+			if rows.Err() != nil {
+				return rows.Err()
+			}
+
+			for id, value := range values {
+				rotatedValue, err := encrypter.Rotate(value)
+				if err != nil {
+					return err
+				}
+
+				_, err = tx.Exec(
+					ctx,
+					fmt.Sprintf("UPDATE %s SET %s = $2 WHERE %s::text = $1", column.Table, column.Column, column.IDColumn),
+					id, rotatedValue,
+				)
+				if err != nil {
+					return err
+				}
+				rotated++
+			}
+
+			return nil
+		})
+		if err != nil {
+			return rotated, err
+		}
+	}
+
+	return rotated, nil
+}
@@ -0,0 +1,100 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+const (
+	encryptionKey1 = "1:MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="
+	encryptionKey2 = "2:YmFyYWxnYS1kZXZlbG9wbWVudC1rZXktMzItYnl0ZXM="
+)
+
+func TestHashToken(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(HashToken("my-token"), HashToken("my-token"))
+	is.True(HashToken("my-token") != HashToken("other-token"))
+	is.Equal(len(HashToken("my-token")), 64)
+}
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	is := is.New(t)
+
+	encrypter, err := NewEncrypter(encryptionKey1)
+	is.NoErr(err)
+
+	encrypted, err := encrypter.Encrypt("my-secret")
+	is.NoErr(err)
+	is.True(encrypted != "my-secret")
+
+	decrypted, err := encrypter.Decrypt(encrypted)
+	is.NoErr(err)
+	is.Equal(decrypted, "my-secret")
+}
+
+func TestDecryptTampered(t *testing.T) {
+	is := is.New(t)
+
+	encrypter, err := NewEncrypter(encryptionKey1)
+	is.NoErr(err)
+
+	_, err = encrypter.Decrypt("enc:v1:1:bm90LWEtY2lwaGVydGV4dC1hdC1hbGw=")
+	is.True(err != nil)
+
+	_, err = encrypter.Decrypt("my-secret")
+	is.True(err != nil)
+}
+
+func TestRotateEncryptionKey(t *testing.T) {
+	is := is.New(t)
+
+	oldEncrypter, err := NewEncrypter(encryptionKey1)
+	is.NoErr(err)
+	encrypted, err := oldEncrypter.Encrypt("my-secret")
+	is.NoErr(err)
+
+	encrypter, err := NewEncrypter(encryptionKey2 + "," + encryptionKey1)
+	is.NoErr(err)
+	is.True(!encrypter.IsCurrent(encrypted))
+
+	decrypted, err := encrypter.Decrypt(encrypted)
+	is.NoErr(err)
+	is.Equal(decrypted, "my-secret")
+
+	rotated, err := encrypter.Rotate(encrypted)
+	is.NoErr(err)
+	is.True(encrypter.IsCurrent(rotated))
+
+	_, err = oldEncrypter.Decrypt(rotated)
+	is.True(err != nil)
+}
+
+func TestRotatePlaintext(t *testing.T) {
+	is := is.New(t)
+
+	encrypter, err := NewEncrypter(encryptionKey1)
+	is.NoErr(err)
+
+	rotated, err := encrypter.Rotate("my-secret")
+	is.NoErr(err)
+	is.True(encrypter.IsCurrent(rotated))
+
+	decrypted, err := encrypter.Decrypt(rotated)
+	is.NoErr(err)
+	is.Equal(decrypted, "my-secret")
+}
+
+func TestNewEncrypterWithInvalidKeys(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewEncrypter("")
+	is.True(err != nil)
+
+	_, err = NewEncrypter("1:c2hvcnQ=")
+	is.True(err != nil)
+
+	_, err = NewEncrypter(encryptionKey1 + "," + encryptionKey1)
+	is.True(err != nil)
+}
@@ -0,0 +1,83 @@
+package shared
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const problemTypePrefix = "urn:baralga:problem:"
+
+// DomainError is an error of the error catalog with a machine-readable code
+// like project:not-found which is mapped to a http status
+type DomainError struct {
+	Code   string
+	Status int
+	Title  string
+}
+
+var (
+	errorCatalogMu sync.Mutex
+	errorCatalog   = make(map[string]*DomainError)
+)
+
+var (
+	ErrInternal      = NewDomainError("internal:error", http.StatusInternalServerError, "internal server error")
+	ErrInvalidParams = NewDomainError("request:invalid-params", http.StatusBadRequest, "request not valid")
+	ErrUnauthorized  = NewDomainError("auth:unauthorized", http.StatusUnauthorized, "unauthorized")
+	ErrForbidden     = NewDomainError("auth:forbidden", http.StatusForbidden, "forbidden")
+	ErrLoginFailed   = NewDomainError("auth:login-failed", http.StatusForbidden, "login failed")
+)
+
+// NewDomainError creates a new error and adds it to the error catalog
+func NewDomainError(code string, status int, title string) *DomainError {
+	errorCatalogMu.Lock()
+	defer errorCatalogMu.Unlock()
+
+	if _, ok := errorCatalog[code]; ok {
+		panic("duplicate error code " + code)
+	}
+
+	domainError := &DomainError{
+		Code:   code,
+		Status: status,
+		Title:  title,
+	}
+	errorCatalog[code] = domainError
+	return domainError
+}
+
+func (e *DomainError) Error() string {
+	return e.Title
+}
+
+// Type is the problem type uri of the error
+func (e *DomainError) Type() string {
+	return problemTypePrefix + e.Code
+}
+
+// DomainErrorOf maps an error to its error of the catalog, unknown errors are internal errors
+func DomainErrorOf(err error) *DomainError {
+	var domainError *DomainError
+	if errors.As(err, &domainError) {
+		return domainError
+	}
+	return ErrInternal
+}
+
+// ErrorCatalog lists all errors of the catalog sorted by code
+func ErrorCatalog() []*DomainError {
+	errorCatalogMu.Lock()
+	defer errorCatalogMu.Unlock()
+
+	domainErrors := make([]*DomainError, 0, len(errorCatalog))
+	for _, domainError := range errorCatalog {
+		domainErrors = append(domainErrors, domainError)
+	}
+	sort.Slice(domainErrors, func(i, j int) bool {
+		return domainErrors[i].Code < domainErrors[j].Code
+	})
+	return domainErrors
+}
@@ -0,0 +1,40 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestDomainErrorOf(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(DomainErrorOf(fmt.Errorf("wrapped: %w", ErrJobNotFound)), ErrJobNotFound)
+	is.Equal(DomainErrorOf(errors.New("unknown")), ErrInternal)
+	is.Equal(ErrJobNotFound.Status, http.StatusNotFound)
+	is.Equal(ErrJobNotFound.Type(), "urn:baralga:problem:job:not-found")
+}
+
+func TestNewDomainErrorWithDuplicateCode(t *testing.T) {
+	is := is.New(t)
+
+	defer func() {
+		is.True(recover() != nil)
+	}()
+
+	NewDomainError(ErrForbidden.Code, http.StatusForbidden, "forbidden")
+}
+
+func TestErrorCatalog(t *testing.T) {
+	is := is.New(t)
+
+	catalog := ErrorCatalog()
+
+	is.True(len(catalog) >= 5)
+	for i := 1; i < len(catalog); i++ {
+		is.True(catalog[i-1].Code < catalog[i].Code)
+	}
+}
@@ -0,0 +1,117 @@
+package shared
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	EventBrokerNATS     = "nats"
+	EventBrokerKafka    = "kafka"
+	EventBrokerRabbitMQ = "rabbitmq"
+
+	eventPublishTimeout = 10 * time.Second
+)
+
+// CloudEvent is a domain event in the structured JSON format of the CloudEvents specification 1.0
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	OrganizationID  string          `json:"organizationid"`
+	Actor           string          `json:"actor,omitempty"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// EventPublisher publishes domain events to a message broker like NATS, Kafka or RabbitMQ
+type EventPublisher interface {
+	Publish(ctx context.Context, event *CloudEvent) error
+}
+
+// NewCloudEvent creates the event of the type like activity.created about the subject, with the data as JSON
+func NewCloudEvent(organizationID uuid.UUID, eventType, subject string, data any, actor *Principal, now time.Time) (*CloudEvent, error) {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              uuid.NewString(),
+		Source:          fmt.Sprintf("/organizations/%s", organizationID),
+		Type:            eventType,
+		Subject:         subject,
+		Time:            now.UTC().Format(time.RFC3339),
+		DataContentType: "application/json",
+		OrganizationID:  organizationID.String(),
+		Data:            dataJSON,
+	}
+	if actor != nil {
+		event.Actor = actor.Username
+	}
+	return event, nil
+}
+
+// NewEventPublisher creates the publisher of the configured event broker, nil if no events are published
+func NewEventPublisher(config *Config) (EventPublisher, error) {
+	if config.EventBroker == "" {
+		return nil, nil
+	}
+
+	brokerURL, err := url.Parse(config.EventBrokerURL)
+	if err != nil || brokerURL.Host == "" {
+		return nil, errors.Errorf("url is required for event broker %s", config.EventBroker)
+	}
+	if config.EventBrokerTopic == "" {
+		return nil, errors.Errorf("topic is required for event broker %s", config.EventBroker)
+	}
+
+	tlsConfig, err := eventBrokerTLSConfig(config.EventBrokerCAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.EventBroker {
+	case EventBrokerNATS:
+		return NewNATSEventPublisher(brokerURL, config.EventBrokerTopic, tlsConfig), nil
+	case EventBrokerKafka:
+		return NewKafkaEventPublisher(brokerURL, config.EventBrokerTopic), nil
+	case EventBrokerRabbitMQ:
+		return NewRabbitMQEventPublisher(brokerURL, config.EventBrokerTopic, tlsConfig), nil
+	default:
+		return nil, errors.Errorf("event broker must be %s, %s or %s", EventBrokerNATS, EventBrokerKafka, EventBrokerRabbitMQ)
+	}
+}
+
+// eventBrokerTLSConfig is the TLS configuration of encrypted connections to the event broker,
+// which is verified with the CA certificates of the file or the system certificates
+func eventBrokerTLSConfig(caFile string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile == "" {
+		return tlsConfig, nil
+	}
+
+	caCertificates, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read ca file of event broker")
+	}
+
+	tlsConfig.RootCAs = x509.NewCertPool()
+	if !tlsConfig.RootCAs.AppendCertsFromPEM(caCertificates) {
+		return nil, errors.Errorf("no certificates in ca file %s of event broker", caFile)
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,76 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// KafkaEventPublisher publishes events to a Kafka topic via the Confluent REST Proxy,
+// keyed by the organization so the events of an organization keep their order
+type KafkaEventPublisher struct {
+	topicURL   string
+	user       *url.Userinfo
+	httpClient *http.Client
+}
+
+var _ EventPublisher = (*KafkaEventPublisher)(nil)
+
+// NewKafkaEventPublisher creates a publisher for the REST Proxy at the url like http://localhost:8082
+func NewKafkaEventPublisher(brokerURL *url.URL, topic string) *KafkaEventPublisher {
+	proxyURL := *brokerURL
+	proxyURL.User = nil
+
+	return &KafkaEventPublisher{
+		topicURL:   proxyURL.JoinPath("topics", topic).String(),
+		user:       brokerURL.User,
+		httpClient: &http.Client{Timeout: eventPublishTimeout},
+	}
+}
+
+type kafkaRecord struct {
+	Key   string      `json:"key"`
+	Value *CloudEvent `json:"value"`
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+func (p *KafkaEventPublisher) Publish(ctx context.Context, event *CloudEvent) error {
+	body, err := json.Marshal(&kafkaRecords{
+		Records: []kafkaRecord{
+			{Key: event.OrganizationID, Value: event},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.topicURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	if p.user != nil {
+		password, _ := p.user.Password()
+		req.SetBasicAuth(p.user.Username(), password)
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not publish to kafka")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		reply, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.Errorf("kafka rest proxy replied %d %s", res.StatusCode, reply)
+	}
+	return nil
+}
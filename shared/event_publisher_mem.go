@@ -0,0 +1,27 @@
+package shared
+
+import (
+	"context"
+	"sync"
+)
+
+// InMemEventPublisher keeps the published events
+type InMemEventPublisher struct {
+	mu     sync.Mutex
+	Events []*CloudEvent
+}
+
+var _ EventPublisher = (*InMemEventPublisher)(nil)
+
+func NewInMemEventPublisher() *InMemEventPublisher {
+	return &InMemEventPublisher{}
+}
+
+func (p *InMemEventPublisher) Publish(ctx context.Context, event *CloudEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	published := *event
+	p.Events = append(p.Events, &published)
+	return nil
+}
@@ -0,0 +1,64 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FeatureInvoicing    = "invoicing"
+	FeatureApprovals    = "approvals"
+	FeatureIntegrations = "integrations"
+	FeatureAttendance   = "attendance"
+
+	// FeatureSupportAccess is the consent of an organization that instance admins may act as its users for support
+	FeatureSupportAccess = "support-access"
+)
+
+var (
+	ErrFeatureNotFound = NewDomainError("feature:not-found", http.StatusNotFound, "feature not found")
+	ErrFeatureDisabled = NewDomainError("feature:disabled", http.StatusForbidden, "feature not enabled for organization")
+)
+
+// featureDefaults are the known features with whether they are enabled for organizations without a flag
+var featureDefaults = map[string]bool{
+	FeatureInvoicing:     false,
+	FeatureApprovals:     false,
+	FeatureIntegrations:  false,
+	FeatureAttendance:    false,
+	FeatureSupportAccess: false,
+}
+
+// FeatureFlag enables or disables a feature for an organization
+type FeatureFlag struct {
+	Feature        string
+	OrganizationID uuid.UUID
+	Enabled        bool
+	Default        bool
+	UpdatedAt      *time.Time
+}
+
+type FeatureFlagRepository interface {
+	FindFeatureFlags(ctx context.Context, organizationID uuid.UUID) ([]*FeatureFlag, error)
+	UpsertFeatureFlag(ctx context.Context, featureFlag *FeatureFlag) (*FeatureFlag, error)
+}
+
+// IsKnownFeature checks whether the feature is known
+func IsKnownFeature(feature string) bool {
+	_, ok := featureDefaults[feature]
+	return ok
+}
+
+// Features lists the known features sorted by name
+func Features() []string {
+	features := make([]string, 0, len(featureDefaults))
+	for feature := range featureDefaults {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	return features
+}
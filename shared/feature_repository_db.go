@@ -0,0 +1,75 @@
+package shared
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DbFeatureFlagRepository is a SQL database repository for feature flags
+type DbFeatureFlagRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ FeatureFlagRepository = (*DbFeatureFlagRepository)(nil)
+
+// NewDbFeatureFlagRepository creates a new SQL database repository for feature flags
+func NewDbFeatureFlagRepository(connPool *pgxpool.Pool) *DbFeatureFlagRepository {
+	return &DbFeatureFlagRepository{
+		connPool: connPool,
+	}
+}
+
+// FindFeatureFlags reads the flags set for the organization
+func (r *DbFeatureFlagRepository) FindFeatureFlags(ctx context.Context, organizationID uuid.UUID) ([]*FeatureFlag, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT feature, org_id, enabled, updated_at 
+		 FROM feature_flags 
+		 WHERE org_id = $1 
+		 ORDER BY feature`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var featureFlags []*FeatureFlag
+	for rows.Next() {
+		featureFlag := &FeatureFlag{}
+		err := rows.Scan(&featureFlag.Feature, &featureFlag.OrganizationID, &featureFlag.Enabled, &featureFlag.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		featureFlags = append(featureFlags, featureFlag)
+	}
+
+	return featureFlags, rows.Err()
+}
+
+// UpsertFeatureFlag sets the flag of the organization
+func (r *DbFeatureFlagRepository) UpsertFeatureFlag(ctx context.Context, featureFlag *FeatureFlag) (*FeatureFlag, error) {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO feature_flags 
+		   (org_id, feature, enabled, updated_at) 
+		 VALUES 
+		   ($1, $2, $3, $4)
+		 ON CONFLICT (org_id, feature) DO UPDATE 
+		 SET enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at`,
+		featureFlag.OrganizationID,
+		featureFlag.Feature,
+		featureFlag.Enabled,
+		featureFlag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return featureFlag, nil
+}
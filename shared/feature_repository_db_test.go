@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestFeatureFlagRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	featureFlagRepository := NewDbFeatureFlagRepository(connPool)
+	repositoryTxer := NewDbRepositoryTxer(connPool)
+
+	t.Run("UpsertFeatureFlag", func(t *testing.T) {
+		for _, enabled := range []bool{true, false} {
+			now := time.Now()
+			err := repositoryTxer.InTx(
+				context.Background(),
+				func(ctx context.Context) error {
+					_, err := featureFlagRepository.UpsertFeatureFlag(ctx, &FeatureFlag{
+						Feature:        FeatureInvoicing,
+						OrganizationID: OrganizationIDSample,
+						Enabled:        enabled,
+						UpdatedAt:      &now,
+					})
+					return err
+				},
+			)
+			is.NoErr(err)
+
+			featureFlags, err := featureFlagRepository.FindFeatureFlags(context.Background(), OrganizationIDSample)
+			is.NoErr(err)
+			is.Equal(len(featureFlags), 1)
+			is.Equal(featureFlags[0].Enabled, enabled)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package shared
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemFeatureFlagRepository struct {
+	mu           sync.Mutex
+	featureFlags []*FeatureFlag
+}
+
+var _ FeatureFlagRepository = (*InMemFeatureFlagRepository)(nil)
+
+func NewInMemFeatureFlagRepository() *InMemFeatureFlagRepository {
+	return &InMemFeatureFlagRepository{}
+}
+
+func (r *InMemFeatureFlagRepository) FindFeatureFlags(ctx context.Context, organizationID uuid.UUID) ([]*FeatureFlag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var featureFlags []*FeatureFlag
+	for _, f := range r.featureFlags {
+		if f.OrganizationID == organizationID {
+			featureFlag := *f
+			featureFlags = append(featureFlags, &featureFlag)
+		}
+	}
+
+	sort.Slice(featureFlags, func(i, j int) bool {
+		return featureFlags[i].Feature < featureFlags[j].Feature
+	})
+
+	return featureFlags, nil
+}
+
+func (r *InMemFeatureFlagRepository) UpsertFeatureFlag(ctx context.Context, featureFlag *FeatureFlag) (*FeatureFlag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *featureFlag
+	for i, f := range r.featureFlags {
+		if f.OrganizationID == featureFlag.OrganizationID && f.Feature == featureFlag.Feature {
+			r.featureFlags[i] = &stored
+			return featureFlag, nil
+		}
+	}
+
+	r.featureFlags = append(r.featureFlags, &stored)
+	return featureFlag, nil
+}
@@ -0,0 +1,136 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type featureFlagModel struct {
+	Feature   string     `json:"feature"`
+	Enabled   bool       `json:"enabled"`
+	Default   bool       `json:"default"`
+	UpdatedAt string     `json:"updatedAt,omitempty"`
+	Links     *hal.Links `json:"_links"`
+}
+
+type featureFlagUpdateModel struct {
+	Enabled *bool `json:"enabled"`
+}
+
+type EmbeddedFeatureFlags struct {
+	FeatureFlagModels []*featureFlagModel `json:"features"`
+}
+
+type featureFlagsModel struct {
+	*EmbeddedFeatureFlags `json:"_embedded"`
+	Links                 *hal.Links `json:"_links"`
+}
+
+type FeatureRestHandlers struct {
+	config         *Config
+	featureService *FeatureService
+}
+
+func NewFeatureRestHandlers(config *Config, featureService *FeatureService) *FeatureRestHandlers {
+	return &FeatureRestHandlers{
+		config:         config,
+		featureService: featureService,
+	}
+}
+
+func (a *FeatureRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/admin/features", a.HandleGetFeatureFlags())
+	r.Put("/admin/features/{feature}", a.HandleUpdateFeatureFlag())
+}
+
+func (a *FeatureRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetFeatureFlags reads the flags of all features of the organization
+func (a *FeatureRestHandlers) HandleGetFeatureFlags() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	featureService := a.featureService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			RenderProblemJSON(w, isProduction, ErrForbidden)
+			return
+		}
+
+		featureFlags, err := featureService.ReadFeatureFlags(r.Context(), principal.OrganizationID)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		featureFlagModels := make([]*featureFlagModel, len(featureFlags))
+		for i, featureFlag := range featureFlags {
+			featureFlagModels[i] = mapToFeatureFlagModel(featureFlag)
+		}
+
+		RenderJSON(w, &featureFlagsModel{
+			EmbeddedFeatureFlags: &EmbeddedFeatureFlags{
+				FeatureFlagModels: featureFlagModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleUpdateFeatureFlag enables or disables a feature for the organization
+func (a *FeatureRestHandlers) HandleUpdateFeatureFlag() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	featureService := a.featureService
+	return func(w http.ResponseWriter, r *http.Request) {
+		feature := chi.URLParam(r, "feature")
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			RenderProblemJSON(w, isProduction, ErrForbidden)
+			return
+		}
+
+		var updateModel featureFlagUpdateModel
+		err := json.NewDecoder(r.Body).Decode(&updateModel)
+		if err != nil {
+			RenderValidationProblemJSON(w, "feature flag not valid", err)
+			return
+		}
+
+		if updateModel.Enabled == nil {
+			RenderValidationProblemJSON(w, "feature flag not valid", NewInvalidParam("enabled", "required", "enabled is required"))
+			return
+		}
+
+		featureFlag, err := featureService.UpdateFeatureFlag(r.Context(), principal, feature, *updateModel.Enabled)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToFeatureFlagModel(featureFlag))
+	}
+}
+
+func mapToFeatureFlagModel(featureFlag *FeatureFlag) *featureFlagModel {
+	featureFlagModel := &featureFlagModel{
+		Feature: featureFlag.Feature,
+		Enabled: featureFlag.Enabled,
+		Default: featureFlag.Default,
+		Links: hal.NewLinks(
+			hal.NewSelfLink(fmt.Sprintf("/api/admin/features/%s", featureFlag.Feature)),
+		),
+	}
+	if featureFlag.UpdatedAt != nil {
+		featureFlagModel.UpdatedAt = featureFlag.UpdatedAt.Format(time.RFC3339)
+	}
+	return featureFlagModel
+}
@@ -0,0 +1,91 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleGetFeatureFlags(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewFeatureRestHandlers(
+		&Config{},
+		NewFeatureService(&Config{}, NewInMemRepositoryTxer(), NewInMemFeatureFlagRepository()),
+	)
+
+	r, _ := http.NewRequest("GET", "/api/admin/features", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleGetFeatureFlags()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	featureFlagsModel := &featureFlagsModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(featureFlagsModel)
+	is.NoErr(err)
+	is.Equal(len(featureFlagsModel.FeatureFlagModels), 5)
+}
+
+func TestHandleUpdateFeatureFlag(t *testing.T) {
+	is := is.New(t)
+
+	featureService := NewFeatureService(&Config{}, NewInMemRepositoryTxer(), NewInMemFeatureFlagRepository())
+	a := NewFeatureRestHandlers(&Config{}, featureService)
+
+	updateFeatureFlag := func(feature, body string, roles ...string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+
+		r, _ := http.NewRequest("PUT", "/api/admin/features/"+feature, strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+			OrganizationID: OrganizationIDSample,
+			Roles:          roles,
+		}))
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("feature", feature)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		a.HandleUpdateFeatureFlag()(httpRec, r)
+		return httpRec
+	}
+
+	t.Run("as admin", func(t *testing.T) {
+		httpRec := updateFeatureFlag(FeatureApprovals, `{"enabled": true}`, "ROLE_ADMIN")
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		featureFlagModel := &featureFlagModel{}
+		err := json.NewDecoder(httpRec.Body).Decode(featureFlagModel)
+		is.NoErr(err)
+		is.Equal(featureFlagModel.Enabled, true)
+		is.Equal(featureFlagModel.Default, false)
+
+		enabled, err := featureService.IsEnabled(context.Background(), OrganizationIDSample, FeatureApprovals)
+		is.NoErr(err)
+		is.True(enabled)
+	})
+
+	t.Run("as user", func(t *testing.T) {
+		httpRec := updateFeatureFlag(FeatureApprovals, `{"enabled": false}`, "ROLE_USER")
+		is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	})
+
+	t.Run("unknown feature", func(t *testing.T) {
+		httpRec := updateFeatureFlag("time-travel", `{"enabled": true}`, "ROLE_ADMIN")
+		is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+	})
+
+	t.Run("without enabled", func(t *testing.T) {
+		httpRec := updateFeatureFlag(FeatureApprovals, `{}`, "ROLE_ADMIN")
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,127 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeatureService reads and toggles the features of organizations
+type FeatureService struct {
+	config                *Config
+	repositoryTxer        RepositoryTxer
+	featureFlagRepository FeatureFlagRepository
+}
+
+// NewFeatureService creates a new service for the features of organizations
+func NewFeatureService(config *Config, repositoryTxer RepositoryTxer, featureFlagRepository FeatureFlagRepository) *FeatureService {
+	return &FeatureService{
+		config:                config,
+		repositoryTxer:        repositoryTxer,
+		featureFlagRepository: featureFlagRepository,
+	}
+}
+
+// ReadFeatureFlags reads the flags of all known features of the organization, sorted by feature
+func (s *FeatureService) ReadFeatureFlags(ctx context.Context, organizationID uuid.UUID) ([]*FeatureFlag, error) {
+	storedFlags, err := s.featureFlagRepository.FindFeatureFlags(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	storedFlagsByFeature := make(map[string]*FeatureFlag, len(storedFlags))
+	for _, featureFlag := range storedFlags {
+		storedFlagsByFeature[featureFlag.Feature] = featureFlag
+	}
+
+	features := Features()
+	featureFlags := make([]*FeatureFlag, len(features))
+	for i, feature := range features {
+		featureFlag := &FeatureFlag{
+			Feature:        feature,
+			OrganizationID: organizationID,
+			Enabled:        featureDefaults[feature],
+			Default:        featureDefaults[feature],
+		}
+		if storedFlag, ok := storedFlagsByFeature[feature]; ok {
+			featureFlag.Enabled = storedFlag.Enabled
+			featureFlag.UpdatedAt = storedFlag.UpdatedAt
+		}
+		featureFlags[i] = featureFlag
+	}
+
+	return featureFlags, nil
+}
+
+// IsEnabled checks whether the feature is enabled for the organization
+func (s *FeatureService) IsEnabled(ctx context.Context, organizationID uuid.UUID, feature string) (bool, error) {
+	if !IsKnownFeature(feature) {
+		return false, ErrFeatureNotFound
+	}
+
+	featureFlags, err := s.ReadFeatureFlags(ctx, organizationID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, featureFlag := range featureFlags {
+		if featureFlag.Feature == feature {
+			return featureFlag.Enabled, nil
+		}
+	}
+	return false, nil
+}
+
+// UpdateFeatureFlag enables or disables the feature for the organization of the principal
+func (s *FeatureService) UpdateFeatureFlag(ctx context.Context, principal *Principal, feature string, enabled bool) (*FeatureFlag, error) {
+	if !IsKnownFeature(feature) {
+		return nil, ErrFeatureNotFound
+	}
+
+	now := time.Now()
+	featureFlag := &FeatureFlag{
+		Feature:        feature,
+		OrganizationID: principal.OrganizationID,
+		Enabled:        enabled,
+		Default:        featureDefaults[feature],
+		UpdatedAt:      &now,
+	}
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			_, err := s.featureFlagRepository.UpsertFeatureFlag(ctx, featureFlag)
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return featureFlag, nil
+}
+
+// RequireFeature rejects requests of organizations without the feature enabled
+func (s *FeatureService) RequireFeature(feature string) func(http.Handler) http.Handler {
+	isProduction := s.config.IsProduction()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+			enabled, err := s.IsEnabled(r.Context(), principal.OrganizationID, feature)
+			if err != nil {
+				RenderProblemJSON(w, isProduction, err)
+				return
+			}
+
+			if !enabled {
+				RenderProblemJSON(w, isProduction, ErrFeatureDisabled)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
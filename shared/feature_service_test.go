@@ -0,0 +1,74 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestReadFeatureFlags(t *testing.T) {
+	is := is.New(t)
+
+	featureService := NewFeatureService(&Config{}, NewInMemRepositoryTxer(), NewInMemFeatureFlagRepository())
+
+	featureFlags, err := featureService.ReadFeatureFlags(context.Background(), OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(len(featureFlags), 5)
+	is.Equal(featureFlags[0].Feature, FeatureApprovals)
+	is.Equal(featureFlags[0].Enabled, false)
+	is.True(featureFlags[0].UpdatedAt == nil)
+}
+
+func TestUpdateFeatureFlag(t *testing.T) {
+	is := is.New(t)
+
+	featureService := NewFeatureService(&Config{}, NewInMemRepositoryTxer(), NewInMemFeatureFlagRepository())
+	principal := &Principal{OrganizationID: OrganizationIDSample}
+
+	featureFlag, err := featureService.UpdateFeatureFlag(context.Background(), principal, FeatureInvoicing, true)
+	is.NoErr(err)
+	is.Equal(featureFlag.Enabled, true)
+
+	enabled, err := featureService.IsEnabled(context.Background(), OrganizationIDSample, FeatureInvoicing)
+	is.NoErr(err)
+	is.True(enabled)
+
+	enabled, err = featureService.IsEnabled(context.Background(), OrganizationIDSample, FeatureApprovals)
+	is.NoErr(err)
+	is.True(!enabled)
+
+	_, err = featureService.UpdateFeatureFlag(context.Background(), principal, "time-travel", true)
+	is.Equal(err, ErrFeatureNotFound)
+
+	_, err = featureService.IsEnabled(context.Background(), OrganizationIDSample, "time-travel")
+	is.Equal(err, ErrFeatureNotFound)
+}
+
+func TestRequireFeature(t *testing.T) {
+	is := is.New(t)
+
+	featureService := NewFeatureService(&Config{}, NewInMemRepositoryTxer(), NewInMemFeatureFlagRepository())
+	principal := &Principal{OrganizationID: OrganizationIDSample}
+	handler := featureService.RequireFeature(FeatureInvoicing)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	newRequest := func() *http.Request {
+		r, _ := http.NewRequest("GET", "/api/invoices", nil)
+		return r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, principal))
+	}
+
+	httpRec := httptest.NewRecorder()
+	handler.ServeHTTP(httpRec, newRequest())
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	_, err := featureService.UpdateFeatureFlag(context.Background(), principal, FeatureInvoicing, true)
+	is.NoErr(err)
+
+	httpRec = httptest.NewRecorder()
+	handler.ServeHTTP(httpRec, newRequest())
+	is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+}
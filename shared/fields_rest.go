@@ -0,0 +1,87 @@
+package shared
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"schneider.vip/problem"
+)
+
+// alwaysSelectedFields are kept in sparse responses so the items can still be identified and navigated
+var alwaysSelectedFields = []string{"id", "_links"}
+
+// FieldsOf reads the fields selected with the fields query param like ?fields=start,end
+func FieldsOf(r *http.Request) []string {
+	fieldsParam := r.URL.Query().Get("fields")
+	if fieldsParam == "" {
+		return nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(fieldsParam, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// RenderSparseJSON renders the json model with only the selected fields of the items of the embedded collection
+func RenderSparseJSON(w http.ResponseWriter, jsonModel interface{}, collection string, fields []string) {
+	if len(fields) == 0 {
+		RenderJSON(w, jsonModel)
+		return
+	}
+
+	sparseModel, err := selectFields(jsonModel, collection, fields)
+	if err != nil {
+		http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusInternalServerError)
+		return
+	}
+
+	RenderJSON(w, sparseModel)
+}
+
+func selectFields(jsonModel interface{}, collection string, fields []string) (map[string]any, error) {
+	document, err := json.Marshal(jsonModel)
+	if err != nil {
+		return nil, err
+	}
+
+	var model map[string]any
+	err = json.Unmarshal(document, &model)
+	if err != nil {
+		return nil, err
+	}
+
+	embedded, ok := model["_embedded"].(map[string]any)
+	if !ok {
+		return model, nil
+	}
+
+	items, ok := embedded[collection].([]any)
+	if !ok {
+		return model, nil
+	}
+
+	selected := make(map[string]bool, len(fields)+len(alwaysSelectedFields))
+	for _, field := range append(fields, alwaysSelectedFields...) {
+		selected[field] = true
+	}
+
+	for _, item := range items {
+		itemObject, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		for field := range itemObject {
+			if !selected[field] {
+				delete(itemObject, field)
+			}
+		}
+	}
+
+	return model, nil
+}
@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+type fieldsTestItemModel struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type fieldsTestModel struct {
+	Embedded struct {
+		Items []*fieldsTestItemModel `json:"items"`
+	} `json:"_embedded"`
+}
+
+func TestFieldsOf(t *testing.T) {
+	is := is.New(t)
+
+	r, _ := http.NewRequest("GET", "/api/activities?fields=start,+end,,description", nil)
+
+	is.Equal(FieldsOf(r), []string{"start", "end", "description"})
+}
+
+func TestFieldsOfWithoutParam(t *testing.T) {
+	is := is.New(t)
+
+	r, _ := http.NewRequest("GET", "/api/activities", nil)
+
+	is.Equal(len(FieldsOf(r)), 0)
+}
+
+func TestRenderSparseJSON(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	model := &fieldsTestModel{}
+	model.Embedded.Items = []*fieldsTestItemModel{
+		{ID: "1", Title: "My Title", Description: "My Description"},
+	}
+
+	RenderSparseJSON(httpRec, model, "items", []string{"title"})
+
+	is.Equal(httpRec.Body.String(), `{"_embedded":{"items":[{"id":"1","title":"My Title"}]}}`+"\n")
+}
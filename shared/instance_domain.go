@@ -0,0 +1,67 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+)
+
+// instanceUsageDays is the number of days the api requests of organizations are summed up for the operators
+const instanceUsageDays = 30
+
+var (
+	ErrInstanceOrganizationNotFound = NewDomainError("instance:organization-not-found", http.StatusNotFound, "organization not found")
+	ErrDisableOwnOrganization       = NewDomainError("instance:disable-own-organization", http.StatusConflict, "own organization can not be disabled")
+)
+
+// InstanceOrganization is an organization of the instance with its usage as seen by the operators of the instance
+type InstanceOrganization struct {
+	ID             uuid.UUID
+	Title          string
+	Status         string
+	Plan           string
+	Users          int
+	Projects       int
+	Activities     int
+	APIRequests    int
+	LastActivityAt *time.Time
+}
+
+type InstanceOrganizationsPaged struct {
+	Organizations []*InstanceOrganization
+	Page          *paged.Page
+}
+
+// DatabaseHealth is the health of the database and its connection pool
+type DatabaseHealth struct {
+	Up            bool
+	Error         string
+	MaxConns      int
+	TotalConns    int
+	IdleConns     int
+	AcquiredConns int
+}
+
+// InstanceHealth is the health of the instance for its operators
+type InstanceHealth struct {
+	Database   *DatabaseHealth
+	Jobs       map[string]int
+	Goroutines int
+	Uptime     time.Duration
+	GoVersion  string
+}
+
+type InstanceRepository interface {
+	FindOrganizations(ctx context.Context, usageSince time.Time, pageParams *paged.PageParams) (*InstanceOrganizationsPaged, error)
+	FindOrganization(ctx context.Context, organizationID uuid.UUID, usageSince time.Time) (*InstanceOrganization, error)
+	FindDatabaseHealth(ctx context.Context) *DatabaseHealth
+	FindJobCountsByStatus(ctx context.Context) (map[string]int, error)
+}
+
+// IsUp checks whether the instance is healthy
+func (h *InstanceHealth) IsUp() bool {
+	return h.Database != nil && h.Database.Up
+}
@@ -0,0 +1,171 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbInstanceRepository is a SQL database repository for the administration of the instance across all organizations
+type DbInstanceRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ InstanceRepository = (*DbInstanceRepository)(nil)
+
+type instanceOrganizationRow struct {
+	ID             uuid.UUID      `db:"org_id"`
+	Title          sql.NullString `db:"title"`
+	Status         sql.NullString `db:"status"`
+	Plan           sql.NullString `db:"plan"`
+	Users          int            `db:"users"`
+	Projects       int            `db:"projects"`
+	Activities     int            `db:"activities"`
+	APIRequests    int            `db:"api_requests"`
+	LastActivityAt *time.Time     `db:"last_activity_at"`
+}
+
+const instanceOrganizationColumns = `o.org_id, o.title, o.status, o.plan,
+	(SELECT count(*) FROM users u WHERE u.org_id = o.org_id) as users,
+	(SELECT count(*) FROM projects p WHERE p.org_id = o.org_id) as projects,
+	(SELECT count(*) FROM activities a WHERE a.org_id = o.org_id) as activities,
+	(SELECT COALESCE(sum(au.requests), 0) FROM api_usage au WHERE au.org_id = o.org_id AND au.day >= $1) as api_requests,
+	(SELECT max(a.start_time) FROM activities a WHERE a.org_id = o.org_id) as last_activity_at`
+
+// NewDbInstanceRepository creates a new SQL database repository for the administration of the instance
+func NewDbInstanceRepository(connPool *pgxpool.Pool) *DbInstanceRepository {
+	return &DbInstanceRepository{
+		connPool: connPool,
+	}
+}
+
+// FindOrganizations reads all organizations of the instance with their usage, the context must not be bound to an organization
+func (r *DbInstanceRepository) FindOrganizations(ctx context.Context, usageSince time.Time, pageParams *paged.PageParams) (*InstanceOrganizationsPaged, error) {
+	var rows []*instanceOrganizationRow
+	var total int
+	err := InSnapshot(ctx, r.connPool, func(tx pgx.Tx) error {
+		var err error
+		rows, err = SelectAll[instanceOrganizationRow](
+			ctx,
+			tx,
+			`SELECT `+instanceOrganizationColumns+`
+			 FROM organizations o
+			 ORDER BY o.title, o.org_id
+			 LIMIT $2 OFFSET $3`,
+			usageSince, pageParams.Size, pageParams.Offset(),
+		)
+		if err != nil {
+			return err
+		}
+
+		row := tx.QueryRow(
+			ctx,
+			`SELECT count(*) as total
+			 FROM organizations`,
+		)
+		return row.Scan(&total)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	organizations := make([]*InstanceOrganization, len(rows))
+	for i, row := range rows {
+		organizations[i] = row.toInstanceOrganization()
+	}
+
+	return &InstanceOrganizationsPaged{
+		Organizations: organizations,
+		Page:          pageParams.PageOfTotal(total),
+	}, nil
+}
+
+// FindOrganization reads the organization with its usage, the context must not be bound to an organization
+func (r *DbInstanceRepository) FindOrganization(ctx context.Context, organizationID uuid.UUID, usageSince time.Time) (*InstanceOrganization, error) {
+	row, err := SelectOne[instanceOrganizationRow](
+		ctx,
+		r.connPool,
+		`SELECT `+instanceOrganizationColumns+`
+		 FROM organizations o
+		 WHERE o.org_id = $2`,
+		usageSince, organizationID,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInstanceOrganizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toInstanceOrganization(), nil
+}
+
+// FindDatabaseHealth pings the database and reads the statistics of the connection pool
+func (r *DbInstanceRepository) FindDatabaseHealth(ctx context.Context) *DatabaseHealth {
+	stat := r.connPool.Stat()
+	databaseHealth := &DatabaseHealth{
+		Up:            true,
+		MaxConns:      int(stat.MaxConns()),
+		TotalConns:    int(stat.TotalConns()),
+		IdleConns:     int(stat.IdleConns()),
+		AcquiredConns: int(stat.AcquiredConns()),
+	}
+
+	err := r.connPool.Ping(ctx)
+	if err != nil {
+		databaseHealth.Up = false
+		databaseHealth.Error = err.Error()
+	}
+	return databaseHealth
+}
+
+// FindJobCountsByStatus counts the background jobs of all organizations by their status
+func (r *DbInstanceRepository) FindJobCountsByStatus(ctx context.Context) (map[string]int, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT status, count(*)
+		 FROM jobs
+		 GROUP BY status`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobCounts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		err = rows.Scan(&status, &count)
+		if err != nil {
+			return nil, err
+		}
+		jobCounts[status] = count
+	}
+	return jobCounts, rows.Err()
+}
+
+func (r *instanceOrganizationRow) toInstanceOrganization() *InstanceOrganization {
+	status := r.Status.String
+	if status == "" {
+		status = OrganizationStatusActive
+	}
+
+	return &InstanceOrganization{
+		ID:             r.ID,
+		Title:          r.Title.String,
+		Status:         status,
+		Plan:           r.Plan.String,
+		Users:          r.Users,
+		Projects:       r.Projects,
+		Activities:     r.Activities,
+		APIRequests:    r.APIRequests,
+		LastActivityAt: r.LastActivityAt,
+	}
+}
@@ -0,0 +1,68 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestInstanceRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	instanceRepository := NewDbInstanceRepository(connPool)
+	usageSince := time.Now().AddDate(0, 0, -instanceUsageDays)
+
+	t.Run("FindOrganizations", func(t *testing.T) {
+		organizationsPaged, err := instanceRepository.FindOrganizations(ctx, usageSince, &paged.PageParams{Size: 10})
+		is.NoErr(err)
+		is.True(len(organizationsPaged.Organizations) > 0)
+		is.Equal(organizationsPaged.Page.TotalElements, len(organizationsPaged.Organizations))
+	})
+
+	t.Run("FindOrganization", func(t *testing.T) {
+		organization, err := instanceRepository.FindOrganization(ctx, OrganizationIDSample, usageSince)
+		is.NoErr(err)
+		is.Equal(organization.ID, OrganizationIDSample)
+		is.Equal(organization.Status, OrganizationStatusActive)
+		is.True(organization.Users > 0)
+		is.True(organization.Activities > 0)
+	})
+
+	t.Run("FindOrganizationNotFound", func(t *testing.T) {
+		_, err := instanceRepository.FindOrganization(ctx, uuid.New(), usageSince)
+		is.Equal(err, ErrInstanceOrganizationNotFound)
+	})
+
+	t.Run("FindDatabaseHealth", func(t *testing.T) {
+		databaseHealth := instanceRepository.FindDatabaseHealth(ctx)
+		is.True(databaseHealth.Up)
+	})
+
+	t.Run("FindJobCountsByStatus", func(t *testing.T) {
+		_, err := instanceRepository.FindJobCountsByStatus(ctx)
+		is.NoErr(err)
+	})
+}
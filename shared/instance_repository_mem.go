@@ -0,0 +1,84 @@
+package shared
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+)
+
+type InMemInstanceRepository struct {
+	mu            sync.Mutex
+	organizations []*InstanceOrganization
+	jobCounts     map[string]int
+}
+
+var _ InstanceRepository = (*InMemInstanceRepository)(nil)
+
+func NewInMemInstanceRepository() *InMemInstanceRepository {
+	return &InMemInstanceRepository{
+		organizations: []*InstanceOrganization{
+			{
+				ID:     OrganizationIDSample,
+				Title:  "Test Organization",
+				Status: OrganizationStatusActive,
+				Users:  2,
+			},
+		},
+		jobCounts: map[string]int{
+			JobStatusQueued: 1,
+		},
+	}
+}
+
+func (r *InMemInstanceRepository) FindOrganizations(ctx context.Context, usageSince time.Time, pageParams *paged.PageParams) (*InstanceOrganizationsPaged, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	organizations := make([]*InstanceOrganization, len(r.organizations))
+	for i, o := range r.organizations {
+		organization := *o
+		organizations[i] = &organization
+	}
+	sort.SliceStable(organizations, func(i, j int) bool {
+		return organizations[i].Title < organizations[j].Title
+	})
+
+	return &InstanceOrganizationsPaged{
+		Organizations: organizations,
+		Page:          pageParams.PageOfTotal(len(organizations)),
+	}, nil
+}
+
+func (r *InMemInstanceRepository) FindOrganization(ctx context.Context, organizationID uuid.UUID, usageSince time.Time) (*InstanceOrganization, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, o := range r.organizations {
+		if o.ID == organizationID {
+			organization := *o
+			return &organization, nil
+		}
+	}
+	return nil, ErrInstanceOrganizationNotFound
+}
+
+func (r *InMemInstanceRepository) FindDatabaseHealth(ctx context.Context) *DatabaseHealth {
+	return &DatabaseHealth{
+		Up: true,
+	}
+}
+
+func (r *InMemInstanceRepository) FindJobCountsByStatus(ctx context.Context) (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobCounts := make(map[string]int, len(r.jobCounts))
+	for status, count := range r.jobCounts {
+		jobCounts[status] = count
+	}
+	return jobCounts, nil
+}
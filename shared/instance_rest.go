@@ -0,0 +1,235 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/baralga/shared/paged"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type instanceOrganizationModel struct {
+	ID             string     `json:"id"`
+	Title          string     `json:"title"`
+	Status         string     `json:"status"`
+	Enabled        bool       `json:"enabled"`
+	Plan           string     `json:"plan,omitempty"`
+	Users          int        `json:"users"`
+	Projects       int        `json:"projects"`
+	Activities     int        `json:"activities"`
+	APIRequests    int        `json:"apiRequests"`
+	LastActivityAt string     `json:"lastActivityAt,omitempty"`
+	Links          *hal.Links `json:"_links"`
+}
+
+type EmbeddedInstanceOrganizations struct {
+	InstanceOrganizationModels []*instanceOrganizationModel `json:"organizations"`
+}
+
+type instanceOrganizationsModel struct {
+	*EmbeddedInstanceOrganizations `json:"_embedded"`
+	*paged.Page                    `json:"page"`
+	Links                          *hal.Links `json:"_links"`
+}
+
+type instanceOrganizationStatusModel struct {
+	Enabled bool `json:"enabled"`
+}
+
+type databaseHealthModel struct {
+	Up            bool   `json:"up"`
+	Error         string `json:"error,omitempty"`
+	MaxConns      int    `json:"maxConns"`
+	TotalConns    int    `json:"totalConns"`
+	IdleConns     int    `json:"idleConns"`
+	AcquiredConns int    `json:"acquiredConns"`
+}
+
+type instanceHealthModel struct {
+	Status        string               `json:"status"`
+	Database      *databaseHealthModel `json:"database"`
+	Jobs          map[string]int       `json:"jobs"`
+	Goroutines    int                  `json:"goroutines"`
+	UptimeSeconds int64                `json:"uptimeSeconds"`
+	GoVersion     string               `json:"goVersion"`
+	Links         *hal.Links           `json:"_links"`
+}
+
+type InstanceRestHandlers struct {
+	config          *Config
+	instanceService *InstanceService
+}
+
+func NewInstanceRestHandlers(config *Config, instanceService *InstanceService) *InstanceRestHandlers {
+	return &InstanceRestHandlers{
+		config:          config,
+		instanceService: instanceService,
+	}
+}
+
+func (a *InstanceRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/instance/organizations", a.HandleGetOrganizations())
+	r.Get("/instance/organizations/{organization-id}", a.HandleGetOrganization())
+	r.Put("/instance/organizations/{organization-id}/status", a.HandleUpdateOrganizationStatus())
+	r.Get("/instance/health", a.HandleGetHealth())
+}
+
+func (a *InstanceRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetOrganizations reads all organizations of the instance with their usage
+func (a *InstanceRestHandlers) HandleGetOrganizations() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	instanceService := a.instanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+		pageParams := paged.PageParamsOf(r)
+
+		organizationsPaged, err := instanceService.ReadOrganizations(r.Context(), principal, pageParams)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		organizationModels := make([]*instanceOrganizationModel, len(organizationsPaged.Organizations))
+		for i, organization := range organizationsPaged.Organizations {
+			organizationModels[i] = mapToInstanceOrganizationModel(organization)
+		}
+
+		RenderJSON(w, &instanceOrganizationsModel{
+			EmbeddedInstanceOrganizations: &EmbeddedInstanceOrganizations{
+				InstanceOrganizationModels: organizationModels,
+			},
+			Page: organizationsPaged.Page,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleGetOrganization reads an organization of the instance with its usage
+func (a *InstanceRestHandlers) HandleGetOrganization() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	instanceService := a.instanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		organizationID, err := uuid.Parse(chi.URLParam(r, "organization-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		organization, err := instanceService.ReadOrganization(r.Context(), principal, organizationID)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToInstanceOrganizationModel(organization))
+	}
+}
+
+// HandleUpdateOrganizationStatus disables or enables an organization of the instance
+func (a *InstanceRestHandlers) HandleUpdateOrganizationStatus() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	instanceService := a.instanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		organizationID, err := uuid.Parse(chi.URLParam(r, "organization-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var statusModel instanceOrganizationStatusModel
+		err = json.NewDecoder(r.Body).Decode(&statusModel)
+		if err != nil {
+			RenderValidationProblemJSON(w, "status not valid", err)
+			return
+		}
+
+		organization, err := instanceService.ChangeOrganizationEnabled(r.Context(), principal, organizationID, statusModel.Enabled)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToInstanceOrganizationModel(organization))
+	}
+}
+
+// HandleGetHealth reads the health of the instance, an unhealthy instance responds with service unavailable
+func (a *InstanceRestHandlers) HandleGetHealth() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	instanceService := a.instanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		health, err := instanceService.ReadHealth(r.Context(), principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		healthModel := &instanceHealthModel{
+			Status: "up",
+			Database: &databaseHealthModel{
+				Up:            health.Database.Up,
+				Error:         health.Database.Error,
+				MaxConns:      health.Database.MaxConns,
+				TotalConns:    health.Database.TotalConns,
+				IdleConns:     health.Database.IdleConns,
+				AcquiredConns: health.Database.AcquiredConns,
+			},
+			Jobs:          health.Jobs,
+			Goroutines:    health.Goroutines,
+			UptimeSeconds: int64(health.Uptime.Seconds()),
+			GoVersion:     health.GoVersion,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+
+		if !health.IsUp() {
+			healthModel.Status = "down"
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		RenderJSON(w, healthModel)
+	}
+}
+
+func mapToInstanceOrganizationModel(organization *InstanceOrganization) *instanceOrganizationModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/instance/organizations/%s", organization.ID))
+	organizationModel := &instanceOrganizationModel{
+		ID:          organization.ID.String(),
+		Title:       organization.Title,
+		Status:      organization.Status,
+		Enabled:     organization.Status != OrganizationStatusDisabled,
+		Plan:        organization.Plan,
+		Users:       organization.Users,
+		Projects:    organization.Projects,
+		Activities:  organization.Activities,
+		APIRequests: organization.APIRequests,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("status", fmt.Sprintf("%s/status", selfLink.Href())),
+			hal.NewLink("exports", fmt.Sprintf("%s/exports", selfLink.Href())),
+		),
+	}
+
+	if organization.LastActivityAt != nil {
+		organizationModel.LastActivityAt = organization.LastActivityAt.Format(time.RFC3339)
+	}
+
+	return organizationModel
+}
@@ -0,0 +1,95 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleGetInstanceOrganizations(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	instanceService, _ := newTestInstanceService()
+	a := NewInstanceRestHandlers(&Config{}, instanceService)
+
+	r := httptest.NewRequest("GET", "/api/instance/organizations", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+	a.HandleGetOrganizations()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	organizationsModel := &instanceOrganizationsModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(organizationsModel)
+	is.NoErr(err)
+	is.Equal(len(organizationsModel.InstanceOrganizationModels), 2)
+	is.True(organizationsModel.InstanceOrganizationModels[0].Enabled)
+}
+
+func TestHandleGetInstanceOrganizationsAsOrgAdmin(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	instanceService, _ := newTestInstanceService()
+	a := NewInstanceRestHandlers(&Config{}, instanceService)
+
+	r := httptest.NewRequest("GET", "/api/instance/organizations", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		Username:       "admin",
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleGetOrganizations()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
+
+func TestHandleUpdateInstanceOrganizationStatus(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	instanceService, _ := newTestInstanceService()
+	a := NewInstanceRestHandlers(&Config{}, instanceService)
+
+	r := httptest.NewRequest("PUT", "/api/instance/organizations/"+organizationIDOther.String()+"/status", strings.NewReader(`{"enabled": false}`))
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("organization-id", organizationIDOther.String())
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	a.HandleUpdateOrganizationStatus()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	organizationModel := &instanceOrganizationModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(organizationModel)
+	is.NoErr(err)
+	is.Equal(organizationModel.Status, OrganizationStatusDisabled)
+	is.True(!organizationModel.Enabled)
+}
+
+func TestHandleGetInstanceHealth(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	instanceService, _ := newTestInstanceService()
+	a := NewInstanceRestHandlers(&Config{}, instanceService)
+
+	r := httptest.NewRequest("GET", "/api/instance/health", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+	a.HandleGetHealth()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	healthModel := &instanceHealthModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(healthModel)
+	is.NoErr(err)
+	is.Equal(healthModel.Status, "up")
+	is.True(healthModel.Database.Up)
+}
@@ -0,0 +1,156 @@
+package shared
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+)
+
+// startedAt is the start of the instance for its uptime
+var startedAt = time.Now()
+
+// InstanceService is the administration of the instance across all organizations for its operators,
+// the operators are configured as instance admins and are distinct from the admins of an organization
+type InstanceService struct {
+	config             *Config
+	repositoryTxer     RepositoryTxer
+	instanceRepository InstanceRepository
+	lifecycleService   *LifecycleService
+}
+
+// NewInstanceService creates a new service for the administration of the instance
+func NewInstanceService(config *Config, repositoryTxer RepositoryTxer, instanceRepository InstanceRepository, lifecycleService *LifecycleService) *InstanceService {
+	return &InstanceService{
+		config:             config,
+		repositoryTxer:     repositoryTxer,
+		instanceRepository: instanceRepository,
+		lifecycleService:   lifecycleService,
+	}
+}
+
+// AuthorizeInstanceAdmin checks whether the principal is an operator of the instance,
+// impersonated principals never act as operators
+func AuthorizeInstanceAdmin(config *Config, principal *Principal) error {
+	if !config.IsInstanceAdmin(principal.Username) || principal.IsImpersonated() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// AsInstanceAdmin is the context and principal for an operator of the instance acting as admin of the organization
+func AsInstanceAdmin(ctx context.Context, config *Config, principal *Principal, organizationID uuid.UUID) (context.Context, *Principal, error) {
+	err := AuthorizeInstanceAdmin(config, principal)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	organizationPrincipal := &Principal{
+		Name:           principal.Name,
+		Username:       principal.Username,
+		OrganizationID: organizationID,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+	return context.WithValue(ctx, ContextKeyPrincipal, organizationPrincipal), organizationPrincipal, nil
+}
+
+// ReadOrganizations reads all organizations of the instance with their usage
+func (s *InstanceService) ReadOrganizations(ctx context.Context, principal *Principal, pageParams *paged.PageParams) (*InstanceOrganizationsPaged, error) {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.instanceRepository.FindOrganizations(withoutOrganization(ctx), usageSince(), pageParams)
+}
+
+// ReadOrganization reads an organization of the instance with its usage
+func (s *InstanceService) ReadOrganization(ctx context.Context, principal *Principal, organizationID uuid.UUID) (*InstanceOrganization, error) {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	organization, err := s.instanceRepository.FindOrganization(withoutOrganization(ctx), organizationID, usageSince())
+	if err != nil {
+		return nil, err
+	}
+
+	lifecycle, err := s.lifecycleService.ReadLifecycle(WithOrganizationID(withoutOrganization(ctx), organizationID), organizationID)
+	if err != nil {
+		return nil, err
+	}
+	organization.Status = lifecycle.Status
+
+	return organization, nil
+}
+
+// ChangeOrganizationEnabled disables or enables the organization, users of disabled organizations are locked out.
+// Operators can not disable their own organization.
+func (s *InstanceService) ChangeOrganizationEnabled(ctx context.Context, principal *Principal, organizationID uuid.UUID, enabled bool) (*InstanceOrganization, error) {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled && organizationID == principal.OrganizationID {
+		return nil, ErrDisableOwnOrganization
+	}
+
+	_, err = s.instanceRepository.FindOrganization(withoutOrganization(ctx), organizationID, usageSince())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repositoryTxer.InTx(
+		WithOrganizationID(withoutOrganization(ctx), organizationID),
+		func(ctx context.Context) error {
+			if enabled {
+				return s.lifecycleService.EnableOrganization(ctx, organizationID)
+			}
+			return s.lifecycleService.DisableOrganization(ctx, organizationID)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ReadOrganization(ctx, principal, organizationID)
+}
+
+// ReadHealth reads the health of the instance
+func (s *InstanceService) ReadHealth(ctx context.Context, principal *Principal) (*InstanceHealth, error) {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withoutOrganization(ctx)
+	health := &InstanceHealth{
+		Database:   s.instanceRepository.FindDatabaseHealth(ctx),
+		Goroutines: runtime.NumGoroutine(),
+		Uptime:     time.Since(startedAt),
+		GoVersion:  runtime.Version(),
+	}
+
+	if health.Database.Up {
+		health.Jobs, err = s.instanceRepository.FindJobCountsByStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return health, nil
+}
+
+// withoutOrganization removes the organization of the principal from the context,
+// so that the row level security of the database shows the rows of all organizations
+func withoutOrganization(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ContextKeyPrincipal, (*Principal)(nil))
+}
+
+func usageSince() time.Time {
+	return time.Now().AddDate(0, 0, -instanceUsageDays)
+}
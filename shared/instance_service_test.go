@@ -0,0 +1,122 @@
+package shared
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+var organizationIDOther = uuid.MustParse("b1d3e9b4-1c1e-4d4f-9bd5-0f1d7f9c1a11")
+
+func newInstanceAdminPrincipal() *Principal {
+	return &Principal{
+		Username:       "ops@baralga.com",
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+}
+
+func newTestInstanceService() (*InstanceService, *LifecycleService) {
+	instanceRepository := NewInMemInstanceRepository()
+	instanceRepository.organizations = append(instanceRepository.organizations, &InstanceOrganization{
+		ID:     organizationIDOther,
+		Title:  "Other Organization",
+		Status: OrganizationStatusActive,
+	})
+
+	config := &Config{InstanceAdmins: "ops@baralga.com"}
+	lifecycleService, _ := newTestLifecycleService(config)
+	return NewInstanceService(config, NewInMemRepositoryTxer(), instanceRepository, lifecycleService), lifecycleService
+}
+
+func TestAuthorizeInstanceAdmin(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{InstanceAdmins: "ops@baralga.com"}
+
+	is.NoErr(AuthorizeInstanceAdmin(config, newInstanceAdminPrincipal()))
+
+	// org admins are no instance admins
+	is.Equal(AuthorizeInstanceAdmin(config, &Principal{Username: "admin", Roles: []string{"ROLE_ADMIN"}}), ErrForbidden)
+
+	// impersonated principals never act as instance admins
+	is.Equal(AuthorizeInstanceAdmin(config, &Principal{Username: "ops@baralga.com", ImpersonatedBy: "ops@baralga.com"}), ErrForbidden)
+}
+
+func TestAsInstanceAdmin(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{InstanceAdmins: "ops@baralga.com"}
+
+	ctx, principal, err := AsInstanceAdmin(context.Background(), config, newInstanceAdminPrincipal(), organizationIDOther)
+	is.NoErr(err)
+	is.Equal(principal.OrganizationID, organizationIDOther)
+	is.True(principal.HasRole("ROLE_ADMIN"))
+	is.Equal(organizationIDOf(ctx), organizationIDOther)
+
+	_, _, err = AsInstanceAdmin(context.Background(), config, &Principal{Username: "admin"}, organizationIDOther)
+	is.Equal(err, ErrForbidden)
+}
+
+func TestReadInstanceOrganizations(t *testing.T) {
+	is := is.New(t)
+
+	instanceService, _ := newTestInstanceService()
+	ctx := context.Background()
+
+	organizationsPaged, err := instanceService.ReadOrganizations(ctx, newInstanceAdminPrincipal(), &paged.PageParams{Size: 10})
+	is.NoErr(err)
+	is.Equal(len(organizationsPaged.Organizations), 2)
+	is.Equal(organizationsPaged.Page.TotalElements, 2)
+
+	_, err = instanceService.ReadOrganizations(ctx, &Principal{Username: "admin", Roles: []string{"ROLE_ADMIN"}}, &paged.PageParams{Size: 10})
+	is.Equal(err, ErrForbidden)
+
+	_, err = instanceService.ReadOrganization(ctx, newInstanceAdminPrincipal(), uuid.New())
+	is.Equal(err, ErrInstanceOrganizationNotFound)
+}
+
+func TestChangeInstanceOrganizationEnabled(t *testing.T) {
+	is := is.New(t)
+
+	instanceService, lifecycleService := newTestInstanceService()
+	ctx := context.Background()
+
+	organization, err := instanceService.ChangeOrganizationEnabled(ctx, newInstanceAdminPrincipal(), organizationIDOther, false)
+	is.NoErr(err)
+	is.Equal(organization.Status, OrganizationStatusDisabled)
+
+	lifecycle, err := lifecycleService.ReadLifecycle(ctx, organizationIDOther)
+	is.NoErr(err)
+	is.True(lifecycle.IsDisabled())
+
+	organization, err = instanceService.ChangeOrganizationEnabled(ctx, newInstanceAdminPrincipal(), organizationIDOther, true)
+	is.NoErr(err)
+	is.Equal(organization.Status, OrganizationStatusActive)
+
+	// the own organization can not be disabled
+	_, err = instanceService.ChangeOrganizationEnabled(ctx, newInstanceAdminPrincipal(), OrganizationIDSample, false)
+	is.Equal(err, ErrDisableOwnOrganization)
+
+	_, err = instanceService.ChangeOrganizationEnabled(ctx, newInstanceAdminPrincipal(), uuid.New(), false)
+	is.Equal(err, ErrInstanceOrganizationNotFound)
+}
+
+func TestReadInstanceHealth(t *testing.T) {
+	is := is.New(t)
+
+	instanceService, _ := newTestInstanceService()
+
+	health, err := instanceService.ReadHealth(context.Background(), newInstanceAdminPrincipal())
+	is.NoErr(err)
+	is.True(health.IsUp())
+	is.Equal(health.Jobs[JobStatusQueued], 1)
+	is.True(health.Goroutines > 0)
+	is.True(health.GoVersion != "")
+
+	_, err = instanceService.ReadHealth(context.Background(), &Principal{Username: "admin", Roles: []string{"ROLE_ADMIN"}})
+	is.Equal(err, ErrForbidden)
+}
@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	IntegrityProblemActivityMissingProject       = "activity-missing-project"
+	IntegrityProblemActivityNegativeDuration     = "activity-negative-duration"
+	IntegrityProblemActivityOrganizationMismatch = "activity-organization-mismatch"
+
+	// integrityMaxProblems is the maximum number of problems reported per check
+	integrityMaxProblems = 1000
+)
+
+// IntegrityProblem is a row of the database violating an invariant of the domain, like after
+// a manual intervention in the database. Only problems with an unambiguous fix are repairable.
+type IntegrityProblem struct {
+	Type           string
+	OrganizationID uuid.UUID
+	EntityType     string
+	EntityID       uuid.UUID
+	Detail         string
+	Repairable     bool
+	Repaired       bool
+}
+
+// IntegrityReport are the problems found by a check of the data integrity across all organizations
+type IntegrityReport struct {
+	CheckedAt time.Time
+	Problems  []*IntegrityProblem
+}
+
+type IntegrityRepository interface {
+	FindIntegrityProblems(ctx context.Context, limit int) ([]*IntegrityProblem, error)
+	RepairIntegrityProblem(ctx context.Context, problem *IntegrityProblem) error
+}
+
+// Repaired is the number of repaired problems
+func (r *IntegrityReport) Repaired() int {
+	repaired := 0
+	for _, problem := range r.Problems {
+		if problem.Repaired {
+			repaired++
+		}
+	}
+	return repaired
+}
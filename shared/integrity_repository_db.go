@@ -0,0 +1,144 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DbIntegrityRepository is a SQL database repository checking the data integrity across all organizations
+type DbIntegrityRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ IntegrityRepository = (*DbIntegrityRepository)(nil)
+
+type integrityActivityRow struct {
+	ID                    uuid.UUID  `db:"activity_id"`
+	OrganizationID        uuid.UUID  `db:"org_id"`
+	ProjectID             uuid.UUID  `db:"project_id"`
+	ProjectOrganizationID *uuid.UUID `db:"project_org_id"`
+	Start                 time.Time  `db:"start_time"`
+	End                   time.Time  `db:"end_time"`
+}
+
+const integrityActivityColumns = `a.activity_id, a.org_id, a.project_id, p.org_id as project_org_id, a.start_time, a.end_time`
+
+// NewDbIntegrityRepository creates a new SQL database repository checking the data integrity
+func NewDbIntegrityRepository(connPool *pgxpool.Pool) *DbIntegrityRepository {
+	return &DbIntegrityRepository{
+		connPool: connPool,
+	}
+}
+
+// FindIntegrityProblems reads up to limit problems of each check, the context must not be bound to an organization
+func (r *DbIntegrityRepository) FindIntegrityProblems(ctx context.Context, limit int) ([]*IntegrityProblem, error) {
+	var problems []*IntegrityProblem
+	err := InSnapshot(ctx, r.connPool, func(tx pgx.Tx) error {
+		missingProjectRows, err := SelectAll[integrityActivityRow](
+			ctx,
+			tx,
+			`SELECT `+integrityActivityColumns+`
+			 FROM activities a
+			 LEFT JOIN projects p ON p.project_id = a.project_id
+			 WHERE p.project_id IS NULL
+			 ORDER BY a.org_id, a.activity_id
+			 LIMIT $1`,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+		for _, row := range missingProjectRows {
+			problems = append(problems, &IntegrityProblem{
+				Type:           IntegrityProblemActivityMissingProject,
+				OrganizationID: row.OrganizationID,
+				EntityType:     "activity",
+				EntityID:       row.ID,
+				Detail:         fmt.Sprintf("project %v does not exist", row.ProjectID),
+			})
+		}
+
+		negativeDurationRows, err := SelectAll[integrityActivityRow](
+			ctx,
+			tx,
+			`SELECT `+integrityActivityColumns+`
+			 FROM activities a
+			 LEFT JOIN projects p ON p.project_id = a.project_id
+			 WHERE a.end_time < a.start_time
+			 ORDER BY a.org_id, a.activity_id
+			 LIMIT $1`,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+		for _, row := range negativeDurationRows {
+			problems = append(problems, &IntegrityProblem{
+				Type:           IntegrityProblemActivityNegativeDuration,
+				OrganizationID: row.OrganizationID,
+				EntityType:     "activity",
+				EntityID:       row.ID,
+				Detail:         fmt.Sprintf("ends at %v before it starts at %v", row.End.Format(time.RFC3339), row.Start.Format(time.RFC3339)),
+				Repairable:     true,
+			})
+		}
+
+		organizationMismatchRows, err := SelectAll[integrityActivityRow](
+			ctx,
+			tx,
+			`SELECT `+integrityActivityColumns+`
+			 FROM activities a
+			 JOIN projects p ON p.project_id = a.project_id
+			 WHERE p.org_id <> a.org_id
+			 ORDER BY a.org_id, a.activity_id
+			 LIMIT $1`,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+		for _, row := range organizationMismatchRows {
+			problems = append(problems, &IntegrityProblem{
+				Type:           IntegrityProblemActivityOrganizationMismatch,
+				OrganizationID: row.OrganizationID,
+				EntityType:     "activity",
+				EntityID:       row.ID,
+				Detail:         fmt.Sprintf("project %v belongs to organization %v", row.ProjectID, *row.ProjectOrganizationID),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(problems) > limit {
+		problems = problems[:limit]
+	}
+	return problems, nil
+}
+
+// RepairIntegrityProblem fixes a repairable problem, problems fixed in the meantime are left untouched
+func (r *DbIntegrityRepository) RepairIntegrityProblem(ctx context.Context, problem *IntegrityProblem) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	switch problem.Type {
+	case IntegrityProblemActivityNegativeDuration:
+		_, err := tx.Exec(
+			ctx,
+			`UPDATE activities
+			 SET start_time = end_time, end_time = start_time
+			 WHERE activity_id = $1 AND end_time < start_time`,
+			problem.EntityID,
+		)
+		return err
+	default:
+		return fmt.Errorf("integrity problem %v is not repairable", problem.Type)
+	}
+}
@@ -0,0 +1,70 @@
+package shared
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestIntegrityRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	integrityRepository := NewDbIntegrityRepository(connPool)
+	repositoryTxer := NewDbRepositoryTxer(connPool)
+	activityID := uuid.MustParse("2a52852c-3f36-11ec-9bbc-0242ac130002")
+
+	t.Run("FindIntegrityProblemsWithoutProblems", func(t *testing.T) {
+		problems, err := integrityRepository.FindIntegrityProblems(ctx, integrityMaxProblems)
+		is.NoErr(err)
+		is.Equal(len(problems), 0)
+	})
+
+	t.Run("RepairNegativeDuration", func(t *testing.T) {
+		_, err := connPool.Exec(
+			ctx,
+			`UPDATE activities SET start_time = end_time, end_time = start_time WHERE activity_id = $1`,
+			activityID,
+		)
+		is.NoErr(err)
+
+		problems, err := integrityRepository.FindIntegrityProblems(ctx, integrityMaxProblems)
+		is.NoErr(err)
+		is.Equal(len(problems), 1)
+		is.Equal(problems[0].Type, IntegrityProblemActivityNegativeDuration)
+		is.Equal(problems[0].EntityID, activityID)
+		is.True(problems[0].Repairable)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return integrityRepository.RepairIntegrityProblem(ctx, problems[0])
+			},
+		)
+		is.NoErr(err)
+
+		problems, err = integrityRepository.FindIntegrityProblems(ctx, integrityMaxProblems)
+		is.NoErr(err)
+		is.Equal(len(problems), 0)
+	})
+}
@@ -0,0 +1,68 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemIntegrityRepository struct {
+	mu       sync.Mutex
+	problems []*IntegrityProblem
+}
+
+var _ IntegrityRepository = (*InMemIntegrityRepository)(nil)
+
+func NewInMemIntegrityRepository() *InMemIntegrityRepository {
+	return &InMemIntegrityRepository{
+		problems: []*IntegrityProblem{
+			{
+				Type:           IntegrityProblemActivityNegativeDuration,
+				OrganizationID: OrganizationIDSample,
+				EntityType:     "activity",
+				EntityID:       uuid.MustParse("00000000-0000-0000-3333-000000000001"),
+				Repairable:     true,
+			},
+			{
+				Type:           IntegrityProblemActivityMissingProject,
+				OrganizationID: OrganizationIDSample,
+				EntityType:     "activity",
+				EntityID:       uuid.MustParse("00000000-0000-0000-3333-000000000002"),
+			},
+		},
+	}
+}
+
+func (r *InMemIntegrityRepository) FindIntegrityProblems(ctx context.Context, limit int) ([]*IntegrityProblem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var problems []*IntegrityProblem
+	for _, p := range r.problems {
+		if len(problems) == limit {
+			break
+		}
+		problem := *p
+		problems = append(problems, &problem)
+	}
+	return problems, nil
+}
+
+func (r *InMemIntegrityRepository) RepairIntegrityProblem(ctx context.Context, problem *IntegrityProblem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !problem.Repairable {
+		return fmt.Errorf("integrity problem %v is not repairable", problem.Type)
+	}
+
+	for i, p := range r.problems {
+		if p.Type == problem.Type && p.EntityID == problem.EntityID {
+			r.problems = append(r.problems[:i], r.problems[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package shared
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type integrityProblemModel struct {
+	Type           string `json:"type"`
+	OrganizationID string `json:"organizationId"`
+	EntityType     string `json:"entityType"`
+	EntityID       string `json:"entityId"`
+	Detail         string `json:"detail,omitempty"`
+	Repairable     bool   `json:"repairable"`
+	Repaired       bool   `json:"repaired"`
+}
+
+type integrityReportModel struct {
+	CheckedAt string                   `json:"checkedAt"`
+	Repaired  int                      `json:"repaired"`
+	Problems  []*integrityProblemModel `json:"problems"`
+	Links     *hal.Links               `json:"_links"`
+}
+
+type IntegrityRestHandlers struct {
+	config           *Config
+	integrityService *IntegrityService
+}
+
+func NewIntegrityRestHandlers(config *Config, integrityService *IntegrityService) *IntegrityRestHandlers {
+	return &IntegrityRestHandlers{
+		config:           config,
+		integrityService: integrityService,
+	}
+}
+
+func (a *IntegrityRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/instance/integrity", a.HandleGetIntegrity())
+	r.Post("/instance/integrity/repair", a.HandleRepairIntegrity())
+}
+
+func (a *IntegrityRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetIntegrity checks the data integrity across all organizations
+func (a *IntegrityRestHandlers) HandleGetIntegrity() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	integrityService := a.integrityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		report, err := integrityService.CheckIntegrity(r.Context(), principal, false)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToIntegrityReportModel(report))
+	}
+}
+
+// HandleRepairIntegrity checks the data integrity across all organizations and repairs the repairable problems
+func (a *IntegrityRestHandlers) HandleRepairIntegrity() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	integrityService := a.integrityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		report, err := integrityService.CheckIntegrity(r.Context(), principal, true)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		log.Printf("%v integrity problems repaired by %s", report.Repaired(), principal.Username)
+
+		RenderJSON(w, mapToIntegrityReportModel(report))
+	}
+}
+
+func mapToIntegrityReportModel(report *IntegrityReport) *integrityReportModel {
+	problemModels := make([]*integrityProblemModel, len(report.Problems))
+	for i, problem := range report.Problems {
+		problemModels[i] = &integrityProblemModel{
+			Type:           problem.Type,
+			OrganizationID: problem.OrganizationID.String(),
+			EntityType:     problem.EntityType,
+			EntityID:       problem.EntityID.String(),
+			Detail:         problem.Detail,
+			Repairable:     problem.Repairable,
+			Repaired:       problem.Repaired,
+		}
+	}
+
+	return &integrityReportModel{
+		CheckedAt: report.CheckedAt.Format(time.RFC3339),
+		Repaired:  report.Repaired(),
+		Problems:  problemModels,
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/instance/integrity"),
+			hal.NewLink("repair", "/api/instance/integrity/repair"),
+		),
+	}
+}
@@ -0,0 +1,32 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestHandleRepairIntegrity(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	config := &Config{InstanceAdmins: "ops@baralga.com"}
+	a := NewIntegrityRestHandlers(config, NewIntegrityService(config, NewInMemRepositoryTxer(), NewInMemIntegrityRepository()))
+
+	r := httptest.NewRequest("POST", "/api/instance/integrity/repair", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+	a.HandleRepairIntegrity()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	reportModel := &integrityReportModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(reportModel)
+	is.NoErr(err)
+	is.Equal(len(reportModel.Problems), 2)
+	is.Equal(reportModel.Repaired, 1)
+	is.Equal(reportModel.Problems[0].Type, IntegrityProblemActivityNegativeDuration)
+}
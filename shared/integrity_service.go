@@ -0,0 +1,72 @@
+package shared
+
+import (
+	"context"
+	"time"
+)
+
+// IntegrityService checks the data integrity across all organizations and repairs the problems with an unambiguous fix
+type IntegrityService struct {
+	config              *Config
+	repositoryTxer      RepositoryTxer
+	integrityRepository IntegrityRepository
+}
+
+// NewIntegrityService creates a new service for checking the data integrity
+func NewIntegrityService(config *Config, repositoryTxer RepositoryTxer, integrityRepository IntegrityRepository) *IntegrityService {
+	return &IntegrityService{
+		config:              config,
+		repositoryTxer:      repositoryTxer,
+		integrityRepository: integrityRepository,
+	}
+}
+
+// CheckIntegrity checks the data integrity for an operator of the instance, repairing the repairable problems if requested
+func (s *IntegrityService) CheckIntegrity(ctx context.Context, principal *Principal, repair bool) (*IntegrityReport, error) {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RunIntegrityCheck(withoutOrganization(ctx), repair)
+}
+
+// RunIntegrityCheck checks the data integrity, repairing the repairable problems if requested.
+// The context must not be bound to an organization.
+func (s *IntegrityService) RunIntegrityCheck(ctx context.Context, repair bool) (*IntegrityReport, error) {
+	problems, err := s.integrityRepository.FindIntegrityProblems(ctx, integrityMaxProblems)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &IntegrityReport{
+		CheckedAt: time.Now(),
+		Problems:  problems,
+	}
+	if !repair {
+		return report, nil
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			for _, problem := range problems {
+				if !problem.Repairable {
+					continue
+				}
+
+				err := s.integrityRepository.RepairIntegrityProblem(ctx, problem)
+				if err != nil {
+					return err
+				}
+				problem.Repaired = true
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
@@ -0,0 +1,45 @@
+package shared
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	is := is.New(t)
+
+	integrityRepository := NewInMemIntegrityRepository()
+	integrityService := NewIntegrityService(&Config{InstanceAdmins: "ops@baralga.com"}, NewInMemRepositoryTxer(), integrityRepository)
+
+	report, err := integrityService.CheckIntegrity(context.Background(), newInstanceAdminPrincipal(), false)
+	is.NoErr(err)
+	is.Equal(len(report.Problems), 2)
+	is.Equal(report.Repaired(), 0)
+
+	report, err = integrityService.CheckIntegrity(context.Background(), newInstanceAdminPrincipal(), true)
+	is.NoErr(err)
+	is.Equal(len(report.Problems), 2)
+	is.Equal(report.Repaired(), 1)
+	is.True(report.Problems[0].Repaired)
+	is.True(!report.Problems[1].Repaired)
+
+	report, err = integrityService.CheckIntegrity(context.Background(), newInstanceAdminPrincipal(), false)
+	is.NoErr(err)
+	is.Equal(len(report.Problems), 1)
+	is.Equal(report.Problems[0].Type, IntegrityProblemActivityMissingProject)
+}
+
+func TestCheckIntegrityAsOrgAdmin(t *testing.T) {
+	is := is.New(t)
+
+	integrityService := NewIntegrityService(&Config{InstanceAdmins: "ops@baralga.com"}, NewInMemRepositoryTxer(), NewInMemIntegrityRepository())
+
+	_, err := integrityService.CheckIntegrity(context.Background(), &Principal{
+		Username:       "admin",
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}, true)
+	is.Equal(err, ErrForbidden)
+}
@@ -0,0 +1,78 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+)
+
+const (
+	JobStatusQueued  string = "queued"
+	JobStatusRunning string = "running"
+	JobStatusDone    string = "done"
+	JobStatusFailed  string = "failed"
+)
+
+var ErrJobNotFound = NewDomainError("job:not-found", http.StatusNotFound, "job not found")
+
+// Job is a unit of work processed in the background
+type Job struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Type           string
+	Payload        string
+	Status         string
+	Attempts       int
+	MaxAttempts    int
+	RunAt          time.Time
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type JobsPaged struct {
+	Jobs []*Job
+	Page *paged.Page
+}
+
+// JobHandler processes a job of a type, a returned error retries the job
+type JobHandler func(ctx context.Context, job *Job) error
+
+type JobRepository interface {
+	FindJobs(ctx context.Context, organizationID uuid.UUID, status string, pageParams *paged.PageParams) (*JobsPaged, error)
+	FindJobByID(ctx context.Context, organizationID, jobID uuid.UUID) (*Job, error)
+	InsertJob(ctx context.Context, job *Job) (*Job, error)
+	ClaimNextJob(ctx context.Context, jobTypes []string, now time.Time) (*Job, error)
+	UpdateJob(ctx context.Context, job *Job) (*Job, error)
+	RequeueStaleJobs(ctx context.Context, staleBefore time.Time) error
+	DeleteFinishedJobs(ctx context.Context, finishedBefore time.Time) error
+}
+
+// NewJob creates a new job of the type which is due immediately
+func NewJob(organizationID uuid.UUID, jobType, payload string) *Job {
+	now := time.Now()
+	return &Job{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		Type:           jobType,
+		Payload:        payload,
+		Status:         JobStatusQueued,
+		MaxAttempts:    5,
+		RunAt:          now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// RetryDelay is the delay before the next attempt, growing with every attempt
+func (j *Job) RetryDelay() time.Duration {
+	return time.Duration(j.Attempts*j.Attempts) * 30 * time.Second
+}
+
+// CanRetry checks whether the job has attempts left
+func (j *Job) CanRetry() bool {
+	return j.Attempts < j.MaxAttempts
+}
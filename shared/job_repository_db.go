@@ -0,0 +1,247 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbJobRepository is a SQL database repository for background jobs
+type DbJobRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ JobRepository = (*DbJobRepository)(nil)
+
+// NewDbJobRepository creates a new SQL database repository for background jobs
+func NewDbJobRepository(connPool *pgxpool.Pool) *DbJobRepository {
+	return &DbJobRepository{
+		connPool: connPool,
+	}
+}
+
+// FindJobs reads the jobs of the organization and the jobs of the system
+func (r *DbJobRepository) FindJobs(ctx context.Context, organizationID uuid.UUID, status string, pageParams *paged.PageParams) (*JobsPaged, error) {
+	var jobs []*Job
+	var total int
+	err := InSnapshot(ctx, r.connPool, func(tx pgx.Tx) error {
+		rows, err := tx.Query(
+			ctx,
+			`SELECT job_id, org_id, job_type, payload, status, attempts, max_attempts, run_at, last_error, created_at, updated_at 
+			 FROM jobs 
+			 WHERE (org_id = $1 OR org_id IS NULL) AND ($2 = '' OR status = $2)
+			 ORDER BY created_at DESC 
+			 LIMIT $3 OFFSET $4`,
+			organizationID, status, pageParams.Size, pageParams.Offset(),
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			job, err := scanJob(rows)
+			if err != nil {
+				return err
+			}
+			jobs = append(jobs, job)
+		}
+
+		row := tx.QueryRow(
+			ctx,
+			`SELECT count(*) as total 
+			 FROM jobs 
+			 WHERE (org_id = $1 OR org_id IS NULL) AND ($2 = '' OR status = $2)`,
+			organizationID, status,
+		)
+		return row.Scan(&total)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &JobsPaged{
+		Jobs: jobs,
+		Page: pageParams.PageOfTotal(total),
+	}, nil
+}
+
+func (r *DbJobRepository) FindJobByID(ctx context.Context, organizationID, jobID uuid.UUID) (*Job, error) {
+	row := r.connPool.QueryRow(ctx,
+		`SELECT job_id, org_id, job_type, payload, status, attempts, max_attempts, run_at, last_error, created_at, updated_at 
+         FROM jobs 
+	     WHERE job_id = $1 AND (org_id = $2 OR org_id IS NULL)`,
+		jobID, organizationID)
+
+	job, err := scanJob(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (r *DbJobRepository) InsertJob(ctx context.Context, job *Job) (*Job, error) {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO jobs 
+		   (job_id, org_id, job_type, payload, status, attempts, max_attempts, run_at, last_error, created_at, updated_at) 
+		 VALUES 
+		   ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		job.ID,
+		uuid.NullUUID{UUID: job.OrganizationID, Valid: job.OrganizationID != uuid.Nil},
+		job.Type,
+		job.Payload,
+		job.Status,
+		job.Attempts,
+		job.MaxAttempts,
+		job.RunAt,
+		job.LastError,
+		job.CreatedAt,
+		job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ClaimNextJob marks the next due job of the types as running, concurrent workers skip locked jobs
+func (r *DbJobRepository) ClaimNextJob(ctx context.Context, jobTypes []string, now time.Time) (*Job, error) {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	row := tx.QueryRow(
+		ctx,
+		`UPDATE jobs 
+		 SET status = $1, attempts = attempts + 1, updated_at = $2
+		 WHERE job_id = (
+		   SELECT job_id FROM jobs 
+		   WHERE status = $3 AND run_at <= $2 AND job_type = ANY($4)
+		   ORDER BY run_at
+		   LIMIT 1
+		   FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING job_id, org_id, job_type, payload, status, attempts, max_attempts, run_at, last_error, created_at, updated_at`,
+		JobStatusRunning, now, JobStatusQueued, jobTypes,
+	)
+
+	job, err := scanJob(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (r *DbJobRepository) UpdateJob(ctx context.Context, job *Job) (*Job, error) {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	row := tx.QueryRow(
+		ctx,
+		`UPDATE jobs 
+		 SET status = $2, attempts = $3, run_at = $4, last_error = $5, updated_at = $6
+		 WHERE job_id = $1
+		 RETURNING job_id`,
+		job.ID,
+		job.Status,
+		job.Attempts,
+		job.RunAt,
+		job.LastError,
+		job.UpdatedAt,
+	)
+
+	var id string
+	err := row.Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// RequeueStaleJobs queues running jobs again which were interrupted e.g. by a restart
+func (r *DbJobRepository) RequeueStaleJobs(ctx context.Context, staleBefore time.Time) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`UPDATE jobs 
+		 SET status = $1
+		 WHERE status = $2 AND updated_at < $3`,
+		JobStatusQueued, JobStatusRunning, staleBefore,
+	)
+	return err
+}
+
+func (r *DbJobRepository) DeleteFinishedJobs(ctx context.Context, finishedBefore time.Time) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`DELETE FROM jobs 
+		 WHERE status IN ($1, $2) AND updated_at < $3`,
+		JobStatusDone, JobStatusFailed, finishedBefore,
+	)
+	return err
+}
+
+func scanJob(row pgx.Row) (*Job, error) {
+	var (
+		id             string
+		organizationID sql.NullString
+		jobType        string
+		payload        sql.NullString
+		status         string
+		attempts       int
+		maxAttempts    int
+		runAt          time.Time
+		lastError      sql.NullString
+		createdAt      time.Time
+		updatedAt      time.Time
+	)
+
+	err := row.Scan(&id, &organizationID, &jobType, &payload, &status, &attempts, &maxAttempts, &runAt, &lastError, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &Job{
+		ID:          uuid.MustParse(id),
+		Type:        jobType,
+		Payload:     payload.String,
+		Status:      status,
+		Attempts:    attempts,
+		MaxAttempts: maxAttempts,
+		RunAt:       runAt,
+		LastError:   lastError.String,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+
+	if organizationID.Valid {
+		job.OrganizationID = uuid.MustParse(organizationID.String)
+	}
+
+	return job, nil
+}
@@ -0,0 +1,110 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/matryer/is"
+)
+
+func TestJobRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	jobRepository := NewDbJobRepository(connPool)
+	repositoryTxer := NewDbRepositoryTxer(connPool)
+
+	job := NewJob(OrganizationIDSample, "sample", "my payload")
+
+	t.Run("InsertJob", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := jobRepository.InsertJob(ctx, job)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		jobsPaged, err := jobRepository.FindJobs(context.Background(), OrganizationIDSample, JobStatusQueued, &paged.PageParams{Page: 0, Size: 50})
+		is.NoErr(err)
+		is.Equal(len(jobsPaged.Jobs), 1)
+		is.Equal(jobsPaged.Page.TotalElements, 1)
+	})
+
+	t.Run("ClaimNextJob", func(t *testing.T) {
+		var claimedJob *Job
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				j, err := jobRepository.ClaimNextJob(ctx, []string{"sample"}, time.Now())
+				claimedJob = j
+				return err
+			},
+		)
+		is.NoErr(err)
+		is.Equal(claimedJob.ID, job.ID)
+		is.Equal(claimedJob.Status, JobStatusRunning)
+		is.Equal(claimedJob.Attempts, 1)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := jobRepository.ClaimNextJob(ctx, []string{"sample"}, time.Now())
+				return err
+			},
+		)
+		is.Equal(err, ErrJobNotFound)
+	})
+
+	t.Run("UpdateJob", func(t *testing.T) {
+		job.Status = JobStatusDone
+		job.Attempts = 1
+		job.UpdatedAt = time.Now()
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := jobRepository.UpdateJob(ctx, job)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		jobRead, err := jobRepository.FindJobByID(context.Background(), OrganizationIDSample, job.ID)
+		is.NoErr(err)
+		is.Equal(jobRead.Status, JobStatusDone)
+	})
+
+	t.Run("DeleteFinishedJobs", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return jobRepository.DeleteFinishedJobs(ctx, time.Now().Add(time.Hour))
+			},
+		)
+		is.NoErr(err)
+
+		_, err = jobRepository.FindJobByID(context.Background(), OrganizationIDSample, job.ID)
+		is.Equal(err, ErrJobNotFound)
+	})
+}
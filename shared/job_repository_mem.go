@@ -0,0 +1,139 @@
+package shared
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+)
+
+type InMemJobRepository struct {
+	mu   sync.Mutex
+	jobs []*Job
+}
+
+var _ JobRepository = (*InMemJobRepository)(nil)
+
+func NewInMemJobRepository() *InMemJobRepository {
+	return &InMemJobRepository{}
+}
+
+func (r *InMemJobRepository) FindJobs(ctx context.Context, organizationID uuid.UUID, status string, pageParams *paged.PageParams) (*JobsPaged, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var jobs []*Job
+	for _, j := range r.jobs {
+		if (j.OrganizationID == organizationID || j.OrganizationID == uuid.Nil) && (status == "" || j.Status == status) {
+			job := *j
+			jobs = append(jobs, &job)
+		}
+	}
+
+	return &JobsPaged{
+		Jobs: jobs,
+		Page: pageParams.PageOfTotal(len(jobs)),
+	}, nil
+}
+
+func (r *InMemJobRepository) FindJobByID(ctx context.Context, organizationID, jobID uuid.UUID) (*Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, j := range r.jobs {
+		if j.ID == jobID && (j.OrganizationID == organizationID || j.OrganizationID == uuid.Nil) {
+			job := *j
+			return &job, nil
+		}
+	}
+
+	return nil, ErrJobNotFound
+}
+
+func (r *InMemJobRepository) InsertJob(ctx context.Context, job *Job) (*Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j := *job
+	r.jobs = append(r.jobs, &j)
+	return job, nil
+}
+
+func (r *InMemJobRepository) ClaimNextJob(ctx context.Context, jobTypes []string, now time.Time) (*Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var next *Job
+	for _, j := range r.jobs {
+		if j.Status != JobStatusQueued || j.RunAt.After(now) || !containsJobType(jobTypes, j.Type) {
+			continue
+		}
+		if next == nil || j.RunAt.Before(next.RunAt) {
+			next = j
+		}
+	}
+
+	if next == nil {
+		return nil, ErrJobNotFound
+	}
+
+	next.Status = JobStatusRunning
+	next.Attempts++
+	next.UpdatedAt = now
+
+	job := *next
+	return &job, nil
+}
+
+func (r *InMemJobRepository) UpdateJob(ctx context.Context, job *Job) (*Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, j := range r.jobs {
+		if j.ID == job.ID {
+			updated := *job
+			r.jobs[i] = &updated
+			return job, nil
+		}
+	}
+
+	return nil, ErrJobNotFound
+}
+
+func (r *InMemJobRepository) RequeueStaleJobs(ctx context.Context, staleBefore time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, j := range r.jobs {
+		if j.Status == JobStatusRunning && j.UpdatedAt.Before(staleBefore) {
+			j.Status = JobStatusQueued
+		}
+	}
+	return nil
+}
+
+func (r *InMemJobRepository) DeleteFinishedJobs(ctx context.Context, finishedBefore time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var jobs []*Job
+	for _, j := range r.jobs {
+		finished := j.Status == JobStatusDone || j.Status == JobStatusFailed
+		if !finished || !j.UpdatedAt.Before(finishedBefore) {
+			jobs = append(jobs, j)
+		}
+	}
+	r.jobs = jobs
+	return nil
+}
+
+func containsJobType(jobTypes []string, jobType string) bool {
+	for _, t := range jobTypes {
+		if t == jobType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package shared
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/baralga/shared/paged"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type jobModel struct {
+	ID          string     `json:"id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"maxAttempts"`
+	RunAt       string     `json:"runAt"`
+	LastError   string     `json:"lastError,omitempty"`
+	CreatedAt   string     `json:"createdAt"`
+	UpdatedAt   string     `json:"updatedAt"`
+	Links       *hal.Links `json:"_links"`
+}
+
+type EmbeddedJobs struct {
+	JobModels []*jobModel `json:"jobs"`
+}
+
+type jobsModel struct {
+	*EmbeddedJobs `json:"_embedded"`
+	*paged.Page   `json:"page"`
+	Links         *hal.Links `json:"_links"`
+}
+
+type JobRestHandlers struct {
+	config        *Config
+	jobRepository JobRepository
+}
+
+func NewJobRestHandlers(config *Config, jobRepository JobRepository) *JobRestHandlers {
+	return &JobRestHandlers{
+		config:        config,
+		jobRepository: jobRepository,
+	}
+}
+
+func (a *JobRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/admin/jobs", a.HandleGetJobs())
+	r.Get("/admin/jobs/{job-id}", a.HandleGetJob())
+}
+
+func (a *JobRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetJobs reads the background jobs, optionally filtered by status
+func (a *JobRestHandlers) HandleGetJobs() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	jobRepository := a.jobRepository
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+		pageParams := paged.PageParamsOf(r)
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			RenderProblemJSON(w, isProduction, ErrForbidden)
+			return
+		}
+
+		jobsPaged, err := jobRepository.FindJobs(r.Context(), principal.OrganizationID, r.URL.Query().Get("status"), pageParams)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		jobModels := make([]*jobModel, len(jobsPaged.Jobs))
+		for i, job := range jobsPaged.Jobs {
+			jobModels[i] = mapToJobModel(job)
+		}
+
+		jobsModel := &jobsModel{
+			EmbeddedJobs: &EmbeddedJobs{
+				JobModels: jobModels,
+			},
+			Page: jobsPaged.Page,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+
+		RenderJSON(w, jobsModel)
+	}
+}
+
+// HandleGetJob reads a background job
+func (a *JobRestHandlers) HandleGetJob() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	jobRepository := a.jobRepository
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobIDParam := chi.URLParam(r, "job-id")
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			RenderProblemJSON(w, isProduction, ErrForbidden)
+			return
+		}
+
+		jobID, err := uuid.Parse(jobIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		job, err := jobRepository.FindJobByID(r.Context(), principal.OrganizationID, jobID)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToJobModel(job))
+	}
+}
+
+func mapToJobModel(job *Job) *jobModel {
+	return &jobModel{
+		ID:          job.ID.String(),
+		Type:        job.Type,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt.Format(time.RFC3339),
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   job.UpdatedAt.Format(time.RFC3339),
+		Links: hal.NewLinks(
+			hal.NewSelfLink(fmt.Sprintf("/api/admin/jobs/%s", job.ID)),
+		),
+	}
+}
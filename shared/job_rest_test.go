@@ -0,0 +1,83 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestHandleGetJobs(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	jobRepository := NewInMemJobRepository()
+	_, err := jobRepository.InsertJob(context.Background(), NewJob(OrganizationIDSample, "sample", ""))
+	is.NoErr(err)
+
+	a := &JobRestHandlers{
+		config:        &Config{},
+		jobRepository: jobRepository,
+	}
+
+	r, _ := http.NewRequest("GET", "/api/admin/jobs?status=queued", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleGetJobs()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	jobsModel := &jobsModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(jobsModel)
+	is.NoErr(err)
+	is.Equal(len(jobsModel.JobModels), 1)
+	is.Equal(jobsModel.JobModels[0].Type, "sample")
+}
+
+func TestHandleGetJobsAsUser(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &JobRestHandlers{
+		config:        &Config{},
+		jobRepository: NewInMemJobRepository(),
+	}
+
+	r, _ := http.NewRequest("GET", "/api/admin/jobs", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		Roles: []string{"ROLE_USER"},
+	}))
+
+	a.HandleGetJobs()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
+
+func TestHandleGetJobNotFound(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &JobRestHandlers{
+		config:        &Config{},
+		jobRepository: NewInMemJobRepository(),
+	}
+
+	jobID := uuid.New().String()
+	r, _ := http.NewRequest("GET", "/api/admin/jobs/"+jobID, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("job-id", jobID)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleGetJob()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
@@ -0,0 +1,217 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// jobPollInterval is the interval in which the worker looks for due jobs
+	jobPollInterval = 5 * time.Second
+	// jobStaleDuration is the duration after which a running job is considered interrupted
+	jobStaleDuration = 30 * time.Minute
+	// jobRetentionDuration is the duration finished jobs are kept for inspection
+	jobRetentionDuration = 7 * 24 * time.Hour
+)
+
+type jobSchedule struct {
+	jobType  string
+	interval time.Duration
+	next     time.Time
+}
+
+// JobService queues jobs persistently and processes them in the background with retries
+type JobService struct {
+	repositoryTxer RepositoryTxer
+	jobRepository  JobRepository
+
+	mu        sync.Mutex
+	handlers  map[string]JobHandler
+	schedules []*jobSchedule
+	wakeup    chan struct{}
+}
+
+// NewJobService creates a new service for background jobs
+func NewJobService(repositoryTxer RepositoryTxer, jobRepository JobRepository) *JobService {
+	return &JobService{
+		repositoryTxer: repositoryTxer,
+		jobRepository:  jobRepository,
+		handlers:       make(map[string]JobHandler),
+		wakeup:         make(chan struct{}, 1),
+	}
+}
+
+// RegisterHandler registers the handler processing the jobs of the type
+func (s *JobService) RegisterHandler(jobType string, handler JobHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[jobType] = handler
+}
+
+// Schedule queues a job of the type repeatedly in the interval
+func (s *JobService) Schedule(jobType string, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedules = append(s.schedules, &jobSchedule{
+		jobType:  jobType,
+		interval: interval,
+		next:     time.Now().Add(interval),
+	})
+}
+
+// Enqueue queues the job, the context is expected to carry the transaction of the triggering write
+func (s *JobService) Enqueue(ctxWithTx context.Context, job *Job) error {
+	_, err := s.jobRepository.InsertJob(ctxWithTx, job)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Run processes due jobs until the context is done
+func (s *JobService) Run(ctx context.Context) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.maintain(ctx, time.Now())
+
+		for {
+			processed, err := s.ProcessNextJob(ctx)
+			if err != nil {
+				log.Printf("could not process job: %v", err)
+			}
+			if !processed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wakeup:
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessNextJob processes the next due job, false if no job was due
+func (s *JobService) ProcessNextJob(ctx context.Context) (bool, error) {
+	s.mu.Lock()
+	jobTypes := make([]string, 0, len(s.handlers))
+	for jobType := range s.handlers {
+		jobTypes = append(jobTypes, jobType)
+	}
+	s.mu.Unlock()
+
+	var job *Job
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			j, err := s.jobRepository.ClaimNextJob(ctx, jobTypes, time.Now())
+			job = j
+			return err
+		},
+	)
+	if errors.Is(err, ErrJobNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	handler := s.handlers[job.Type]
+	s.mu.Unlock()
+
+	jobErr := runJobHandler(WithOrganizationID(ctx, job.OrganizationID), handler, job)
+
+	now := time.Now()
+	job.UpdatedAt = now
+	switch {
+	case jobErr == nil:
+		job.Status = JobStatusDone
+		job.LastError = ""
+	case job.CanRetry():
+		job.Status = JobStatusQueued
+		job.RunAt = now.Add(job.RetryDelay())
+		job.LastError = jobErr.Error()
+	default:
+		job.Status = JobStatusFailed
+		job.LastError = jobErr.Error()
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			_, err := s.jobRepository.UpdateJob(ctx, job)
+			return err
+		},
+	)
+	if err != nil {
+		return true, err
+	}
+
+	if jobErr != nil {
+		log.Printf("job %v of type %v failed in attempt %v: %v", job.ID, job.Type, job.Attempts, jobErr)
+	}
+
+	return true, nil
+}
+
+// maintain queues scheduled jobs, requeues interrupted jobs and removes old finished jobs
+func (s *JobService) maintain(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	var dueJobTypes []string
+	for _, schedule := range s.schedules {
+		if !now.Before(schedule.next) {
+			dueJobTypes = append(dueJobTypes, schedule.jobType)
+			schedule.next = now.Add(schedule.interval)
+		}
+	}
+	s.mu.Unlock()
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			for _, jobType := range dueJobTypes {
+				_, err := s.jobRepository.InsertJob(ctx, NewJob(uuid.Nil, jobType, ""))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			return s.jobRepository.RequeueStaleJobs(ctx, now.Add(-jobStaleDuration))
+		},
+		func(ctx context.Context) error {
+			return s.jobRepository.DeleteFinishedJobs(ctx, now.Add(-jobRetentionDuration))
+		},
+	)
+	if err != nil {
+		log.Printf("could not maintain jobs: %v", err)
+	}
+}
+
+func runJobHandler(ctx context.Context, handler JobHandler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
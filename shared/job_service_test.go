@@ -0,0 +1,132 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestJobService(t *testing.T) {
+	is := is.New(t)
+
+	jobRepository := NewInMemJobRepository()
+	jobService := NewJobService(NewInMemRepositoryTxer(), jobRepository)
+
+	var payloads []string
+	jobService.RegisterHandler("sample", func(ctx context.Context, job *Job) error {
+		payloads = append(payloads, job.Payload)
+		return nil
+	})
+
+	job := NewJob(OrganizationIDSample, "sample", "my payload")
+	err := jobService.Enqueue(context.Background(), job)
+	is.NoErr(err)
+
+	processed, err := jobService.ProcessNextJob(context.Background())
+	is.NoErr(err)
+	is.True(processed)
+	is.Equal(payloads, []string{"my payload"})
+
+	jobProcessed, err := jobRepository.FindJobByID(context.Background(), OrganizationIDSample, job.ID)
+	is.NoErr(err)
+	is.Equal(jobProcessed.Status, JobStatusDone)
+	is.Equal(jobProcessed.Attempts, 1)
+
+	processed, err = jobService.ProcessNextJob(context.Background())
+	is.NoErr(err)
+	is.True(!processed)
+}
+
+func TestJobServiceRetry(t *testing.T) {
+	is := is.New(t)
+
+	jobRepository := NewInMemJobRepository()
+	jobService := NewJobService(NewInMemRepositoryTxer(), jobRepository)
+
+	jobService.RegisterHandler("failing", func(ctx context.Context, job *Job) error {
+		return errors.New("failed")
+	})
+
+	job := NewJob(OrganizationIDSample, "failing", "")
+	job.MaxAttempts = 2
+	err := jobService.Enqueue(context.Background(), job)
+	is.NoErr(err)
+
+	processed, err := jobService.ProcessNextJob(context.Background())
+	is.NoErr(err)
+	is.True(processed)
+
+	jobRetried, err := jobRepository.FindJobByID(context.Background(), OrganizationIDSample, job.ID)
+	is.NoErr(err)
+	is.Equal(jobRetried.Status, JobStatusQueued)
+	is.Equal(jobRetried.LastError, "failed")
+	is.True(jobRetried.RunAt.After(time.Now()))
+
+	// retry is not due yet
+	processed, err = jobService.ProcessNextJob(context.Background())
+	is.NoErr(err)
+	is.True(!processed)
+
+	jobRetried.RunAt = time.Now()
+	_, err = jobRepository.UpdateJob(context.Background(), jobRetried)
+	is.NoErr(err)
+
+	processed, err = jobService.ProcessNextJob(context.Background())
+	is.NoErr(err)
+	is.True(processed)
+
+	jobFailed, err := jobRepository.FindJobByID(context.Background(), OrganizationIDSample, job.ID)
+	is.NoErr(err)
+	is.Equal(jobFailed.Status, JobStatusFailed)
+	is.Equal(jobFailed.Attempts, 2)
+}
+
+func TestJobServicePanic(t *testing.T) {
+	is := is.New(t)
+
+	jobRepository := NewInMemJobRepository()
+	jobService := NewJobService(NewInMemRepositoryTxer(), jobRepository)
+
+	jobService.RegisterHandler("panicking", func(ctx context.Context, job *Job) error {
+		panic("oops")
+	})
+
+	job := NewJob(uuid.Nil, "panicking", "")
+	err := jobService.Enqueue(context.Background(), job)
+	is.NoErr(err)
+
+	processed, err := jobService.ProcessNextJob(context.Background())
+	is.NoErr(err)
+	is.True(processed)
+
+	jobRetried, err := jobRepository.FindJobByID(context.Background(), OrganizationIDSample, job.ID)
+	is.NoErr(err)
+	is.Equal(jobRetried.LastError, "job panicked: oops")
+}
+
+func TestJobServiceSchedule(t *testing.T) {
+	is := is.New(t)
+
+	jobRepository := NewInMemJobRepository()
+	jobService := NewJobService(NewInMemRepositoryTxer(), jobRepository)
+	jobService.RegisterHandler("scheduled", func(ctx context.Context, job *Job) error {
+		return nil
+	})
+	jobService.Schedule("scheduled", time.Hour)
+
+	jobService.maintain(context.Background(), time.Now())
+	jobsPaged, err := jobRepository.FindJobs(context.Background(), OrganizationIDSample, "", &paged.PageParams{Page: 0, Size: 50})
+	is.NoErr(err)
+	is.Equal(len(jobsPaged.Jobs), 0)
+
+	jobService.maintain(context.Background(), time.Now().Add(2*time.Hour))
+	jobsPaged, err = jobRepository.FindJobs(context.Background(), OrganizationIDSample, JobStatusQueued, &paged.PageParams{Page: 0, Size: 50})
+	is.NoErr(err)
+	is.Equal(len(jobsPaged.Jobs), 1)
+	is.Equal(jobsPaged.Jobs[0].Type, "scheduled")
+}
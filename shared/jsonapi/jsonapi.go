@@ -0,0 +1,75 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// MediaType is the media type of JSON:API documents
+const MediaType = "application/vnd.api+json"
+
+type (
+	// Document is a JSON:API document with the primary data and the included related resources
+	Document struct {
+		Data     any               `json:"data"`
+		Included []*Resource       `json:"included,omitempty"`
+		Links    map[string]string `json:"links,omitempty"`
+		Meta     map[string]any    `json:"meta,omitempty"`
+	}
+
+	// Resource is a JSON:API resource object
+	Resource struct {
+		Type          string                   `json:"type"`
+		ID            string                   `json:"id"`
+		Attributes    any                      `json:"attributes,omitempty"`
+		Relationships map[string]*Relationship `json:"relationships,omitempty"`
+		Links         map[string]string        `json:"links,omitempty"`
+	}
+
+	// Relationship links a resource to a related resource
+	Relationship struct {
+		Data  *ResourceIdentifier `json:"data,omitempty"`
+		Links map[string]string   `json:"links,omitempty"`
+	}
+
+	// ResourceIdentifier identifies a resource by type and id
+	ResourceIdentifier struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	}
+)
+
+// NewRelationship creates a relationship to the resource with the given type and id
+func NewRelationship(resourceType, id, relatedHref string) *Relationship {
+	return &Relationship{
+		Data: &ResourceIdentifier{
+			Type: resourceType,
+			ID:   id,
+		},
+		Links: map[string]string{
+			"related": relatedHref,
+		},
+	}
+}
+
+// Accepts checks whether the client accepts JSON:API documents
+func Accepts(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			if strings.TrimSpace(strings.SplitN(mediaRange, ";", 2)[0]) == MediaType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Render writes the document as JSON:API response
+func Render(w http.ResponseWriter, document *Document) {
+	w.Header().Set("Content-Type", MediaType)
+	err := json.NewEncoder(w).Encode(document)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
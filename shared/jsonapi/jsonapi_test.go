@@ -0,0 +1,38 @@
+package jsonapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestAccepts(t *testing.T) {
+	is := is.New(t)
+
+	r, _ := http.NewRequest("GET", "/api/activities", nil)
+	is.True(!Accepts(r))
+
+	r.Header.Set("Accept", "application/json, application/vnd.api+json; q=0.9")
+	is.True(Accepts(r))
+}
+
+func TestRender(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	Render(httpRec, &Document{
+		Data: []*Resource{
+			{
+				Type:          "activities",
+				ID:            "1",
+				Relationships: map[string]*Relationship{"project": NewRelationship("projects", "2", "/api/projects/2")},
+			},
+		},
+	})
+
+	is.Equal(httpRec.Header().Get("Content-Type"), MediaType)
+	is.True(strings.Contains(httpRec.Body.String(), `"project":{"data":{"type":"projects","id":"2"}`))
+}
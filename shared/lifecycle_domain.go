@@ -0,0 +1,119 @@
+package shared
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OrganizationStatusTrial     = "trial"
+	OrganizationStatusActive    = "active"
+	OrganizationStatusPastDue   = "past_due"
+	OrganizationStatusSuspended = "suspended"
+	OrganizationStatusCancelled = "cancelled"
+
+	// OrganizationStatusDisabled is set by the operators of the instance, only they enable the organization again
+	OrganizationStatusDisabled = "disabled"
+)
+
+var (
+	ErrOrganizationReadOnly    = NewDomainError("organization:read-only", http.StatusForbidden, "organization is read-only")
+	ErrInvalidStatusTransition = NewDomainError("organization:invalid-status-transition", http.StatusConflict, "invalid status transition")
+	ErrOrganizationDisabled    = NewDomainError("organization:disabled", http.StatusForbidden, "organization is disabled")
+)
+
+// statusTransitions are the statuses an organization may change to from a status
+var statusTransitions = map[string][]string{
+	OrganizationStatusTrial:     {OrganizationStatusActive, OrganizationStatusPastDue, OrganizationStatusSuspended, OrganizationStatusCancelled},
+	OrganizationStatusActive:    {OrganizationStatusPastDue, OrganizationStatusSuspended, OrganizationStatusCancelled},
+	OrganizationStatusPastDue:   {OrganizationStatusActive, OrganizationStatusSuspended, OrganizationStatusCancelled},
+	OrganizationStatusSuspended: {OrganizationStatusActive, OrganizationStatusCancelled},
+	OrganizationStatusCancelled: {OrganizationStatusActive},
+	OrganizationStatusDisabled:  {},
+}
+
+// Lifecycle is the status of an organization in the hosted offering
+type Lifecycle struct {
+	OrganizationID  uuid.UUID
+	Status          string
+	TrialEndsAt     *time.Time
+	StatusChangedAt *time.Time
+}
+
+// Banner is a notice about the status of the organization shown to its users
+type Banner struct {
+	Level   string
+	Message string
+}
+
+type LifecycleRepository interface {
+	FindLifecycle(ctx context.Context, organizationID uuid.UUID) (*Lifecycle, error)
+	UpdateLifecycle(ctx context.Context, lifecycle *Lifecycle) error
+	FindOrganizationsWithExpiredTrial(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+}
+
+// CanChangeTo checks whether the organization may change to the status
+func (l *Lifecycle) CanChangeTo(status string) bool {
+	for _, s := range statusTransitions[l.Status] {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReadOnly checks whether the organization may only read its data
+func (l *Lifecycle) IsReadOnly() bool {
+	return l.Status == OrganizationStatusSuspended || l.Status == OrganizationStatusCancelled || l.IsDisabled()
+}
+
+// IsDisabled checks whether the operators of the instance disabled the organization
+func (l *Lifecycle) IsDisabled() bool {
+	return l.Status == OrganizationStatusDisabled
+}
+
+// TrialDaysLeft is the number of started days until the trial ends, 0 if not in trial
+func (l *Lifecycle) TrialDaysLeft(now time.Time) int {
+	if l.Status != OrganizationStatusTrial || l.TrialEndsAt == nil || !now.Before(*l.TrialEndsAt) {
+		return 0
+	}
+	return int(math.Ceil(l.TrialEndsAt.Sub(now).Hours() / 24))
+}
+
+// BannerOf is the notice about the status of the organization, nil if there is nothing to notice
+func (l *Lifecycle) BannerOf(now time.Time) *Banner {
+	switch l.Status {
+	case OrganizationStatusTrial:
+		return &Banner{Level: "info", Message: trialBannerMessage(l.TrialDaysLeft(now))}
+	case OrganizationStatusPastDue:
+		return &Banner{Level: "warning", Message: "Your last payment failed. Please update your payment method to keep your account."}
+	case OrganizationStatusSuspended:
+		return &Banner{Level: "danger", Message: "Your account is suspended and read-only. Please subscribe to a plan to continue tracking."}
+	case OrganizationStatusCancelled:
+		return &Banner{Level: "danger", Message: "Your account is cancelled and read-only. Please subscribe to a plan to continue tracking."}
+	case OrganizationStatusDisabled:
+		return &Banner{Level: "danger", Message: "Your account is disabled. Please contact the operator of the instance."}
+	}
+	return nil
+}
+
+func trialBannerMessage(daysLeft int) string {
+	switch daysLeft {
+	case 0:
+		return "Your trial ends today."
+	case 1:
+		return "Your trial ends in 1 day."
+	}
+	return "Your trial ends in " + strconv.Itoa(daysLeft) + " days."
+}
+
+// IsValidOrganizationStatus checks whether the status is known
+func IsValidOrganizationStatus(status string) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
@@ -0,0 +1,95 @@
+package shared
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbLifecycleRepository is a SQL database repository for the lifecycle of organizations
+type DbLifecycleRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ LifecycleRepository = (*DbLifecycleRepository)(nil)
+
+// NewDbLifecycleRepository creates a new SQL database repository for the lifecycle of organizations
+func NewDbLifecycleRepository(connPool *pgxpool.Pool) *DbLifecycleRepository {
+	return &DbLifecycleRepository{
+		connPool: connPool,
+	}
+}
+
+// FindLifecycle reads the lifecycle of the organization, organizations without a status are active
+func (r *DbLifecycleRepository) FindLifecycle(ctx context.Context, organizationID uuid.UUID) (*Lifecycle, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT status, trial_ends_at, status_changed_at 
+		 FROM organizations 
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+
+	var status pgtype.Varchar
+	lifecycle := &Lifecycle{OrganizationID: organizationID}
+	err := row.Scan(&status, &lifecycle.TrialEndsAt, &lifecycle.StatusChangedAt)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	lifecycle.Status = status.String
+	if lifecycle.Status == "" {
+		lifecycle.Status = OrganizationStatusActive
+	}
+	return lifecycle, nil
+}
+
+// UpdateLifecycle sets the status and trial of the organization
+func (r *DbLifecycleRepository) UpdateLifecycle(ctx context.Context, lifecycle *Lifecycle) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`UPDATE organizations 
+		 SET status = $2, trial_ends_at = $3, status_changed_at = $4 
+		 WHERE org_id = $1`,
+		lifecycle.OrganizationID,
+		lifecycle.Status,
+		lifecycle.TrialEndsAt,
+		lifecycle.StatusChangedAt,
+	)
+	return err
+}
+
+// FindOrganizationsWithExpiredTrial reads the organizations in trial with the trial ended before now
+func (r *DbLifecycleRepository) FindOrganizationsWithExpiredTrial(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT org_id 
+		 FROM organizations 
+		 WHERE status = $1 AND trial_ends_at < $2`,
+		OrganizationStatusTrial,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var organizationIDs []uuid.UUID
+	for rows.Next() {
+		var organizationID uuid.UUID
+		err := rows.Scan(&organizationID)
+		if err != nil {
+			return nil, err
+		}
+		organizationIDs = append(organizationIDs, organizationID)
+	}
+
+	return organizationIDs, rows.Err()
+}
@@ -0,0 +1,61 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestLifecycleRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	lifecycleRepository := NewDbLifecycleRepository(connPool)
+	repositoryTxer := NewDbRepositoryTxer(connPool)
+
+	t.Run("FindLifecycleWithoutStatus", func(t *testing.T) {
+		lifecycle, err := lifecycleRepository.FindLifecycle(context.Background(), OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(lifecycle.Status, OrganizationStatusActive)
+	})
+
+	t.Run("FindOrganizationsWithExpiredTrial", func(t *testing.T) {
+		trialEndsAt := time.Now().Add(-time.Hour)
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return lifecycleRepository.UpdateLifecycle(ctx, &Lifecycle{
+					OrganizationID: OrganizationIDSample,
+					Status:         OrganizationStatusTrial,
+					TrialEndsAt:    &trialEndsAt,
+				})
+			},
+		)
+		is.NoErr(err)
+
+		organizationIDs, err := lifecycleRepository.FindOrganizationsWithExpiredTrial(context.Background(), time.Now())
+		is.NoErr(err)
+		is.Equal(len(organizationIDs), 1)
+		is.Equal(organizationIDs[0], OrganizationIDSample)
+	})
+}
@@ -0,0 +1,56 @@
+package shared
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemLifecycleRepository struct {
+	mu         sync.Mutex
+	lifecycles map[uuid.UUID]*Lifecycle
+}
+
+var _ LifecycleRepository = (*InMemLifecycleRepository)(nil)
+
+func NewInMemLifecycleRepository() *InMemLifecycleRepository {
+	return &InMemLifecycleRepository{
+		lifecycles: make(map[uuid.UUID]*Lifecycle),
+	}
+}
+
+func (r *InMemLifecycleRepository) FindLifecycle(ctx context.Context, organizationID uuid.UUID) (*Lifecycle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lifecycle, ok := r.lifecycles[organizationID]
+	if !ok {
+		return &Lifecycle{OrganizationID: organizationID, Status: OrganizationStatusActive}, nil
+	}
+	found := *lifecycle
+	return &found, nil
+}
+
+func (r *InMemLifecycleRepository) UpdateLifecycle(ctx context.Context, lifecycle *Lifecycle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *lifecycle
+	r.lifecycles[lifecycle.OrganizationID] = &stored
+	return nil
+}
+
+func (r *InMemLifecycleRepository) FindOrganizationsWithExpiredTrial(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var organizationIDs []uuid.UUID
+	for _, lifecycle := range r.lifecycles {
+		if lifecycle.Status == OrganizationStatusTrial && lifecycle.TrialEndsAt != nil && lifecycle.TrialEndsAt.Before(now) {
+			organizationIDs = append(organizationIDs, lifecycle.OrganizationID)
+		}
+	}
+	return organizationIDs, nil
+}
@@ -0,0 +1,82 @@
+package shared
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type bannerModel struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+type organizationStatusModel struct {
+	Status          string       `json:"status"`
+	ReadOnly        bool         `json:"readOnly"`
+	TrialEndsAt     string       `json:"trialEndsAt,omitempty"`
+	TrialDaysLeft   int          `json:"trialDaysLeft,omitempty"`
+	StatusChangedAt string       `json:"statusChangedAt,omitempty"`
+	Banner          *bannerModel `json:"banner,omitempty"`
+	Links           *hal.Links   `json:"_links"`
+}
+
+type LifecycleRestHandlers struct {
+	config           *Config
+	lifecycleService *LifecycleService
+}
+
+func NewLifecycleRestHandlers(config *Config, lifecycleService *LifecycleService) *LifecycleRestHandlers {
+	return &LifecycleRestHandlers{
+		config:           config,
+		lifecycleService: lifecycleService,
+	}
+}
+
+func (a *LifecycleRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/organization/status", a.HandleGetOrganizationStatus())
+}
+
+func (a *LifecycleRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetOrganizationStatus reads the status of the organization with the banner to show its users
+func (a *LifecycleRestHandlers) HandleGetOrganizationStatus() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	lifecycleService := a.lifecycleService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		lifecycle, err := lifecycleService.ReadLifecycle(r.Context(), principal.OrganizationID)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		now := time.Now()
+		organizationStatusModel := &organizationStatusModel{
+			Status:        lifecycle.Status,
+			ReadOnly:      lifecycle.IsReadOnly(),
+			TrialDaysLeft: lifecycle.TrialDaysLeft(now),
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		if lifecycle.Status == OrganizationStatusTrial && lifecycle.TrialEndsAt != nil {
+			organizationStatusModel.TrialEndsAt = lifecycle.TrialEndsAt.Format(time.RFC3339)
+		}
+		if lifecycle.StatusChangedAt != nil {
+			organizationStatusModel.StatusChangedAt = lifecycle.StatusChangedAt.Format(time.RFC3339)
+		}
+		if banner := lifecycle.BannerOf(now); banner != nil {
+			organizationStatusModel.Banner = &bannerModel{
+				Level:   banner.Level,
+				Message: banner.Message,
+			}
+		}
+
+		RenderJSON(w, organizationStatusModel)
+	}
+}
@@ -0,0 +1,38 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestHandleGetOrganizationStatus(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	lifecycleService, _ := newTestLifecycleService(&Config{TrialDays: 30})
+	is.NoErr(lifecycleService.OrganizationInitializer()(context.Background(), OrganizationIDSample))
+
+	a := NewLifecycleRestHandlers(&Config{}, lifecycleService)
+
+	r, _ := http.NewRequest("GET", "/api/organization/status", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_USER"},
+	}))
+
+	a.HandleGetOrganizationStatus()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	organizationStatusModel := &organizationStatusModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(organizationStatusModel)
+	is.NoErr(err)
+	is.Equal(organizationStatusModel.Status, OrganizationStatusTrial)
+	is.Equal(organizationStatusModel.TrialDaysLeft, 30)
+	is.True(!organizationStatusModel.ReadOnly)
+	is.Equal(organizationStatusModel.Banner.Level, "info")
+}
@@ -0,0 +1,237 @@
+package shared
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	trialExpiryJobType = "trial-expiry"
+
+	// disabledCacheExpiry is the duration whether an organization is disabled is cached
+	disabledCacheExpiry = time.Minute
+)
+
+type cachedDisabled struct {
+	disabled  bool
+	expiresAt time.Time
+}
+
+// LifecycleService manages the status of organizations from trial to cancellation
+type LifecycleService struct {
+	config              *Config
+	repositoryTxer      RepositoryTxer
+	lifecycleRepository LifecycleRepository
+
+	mu            sync.Mutex
+	disabledByOrg map[uuid.UUID]*cachedDisabled
+}
+
+// NewLifecycleService creates a new service for the lifecycle of organizations, expiring trials in the background
+func NewLifecycleService(config *Config, repositoryTxer RepositoryTxer, jobService *JobService, lifecycleRepository LifecycleRepository) *LifecycleService {
+	s := &LifecycleService{
+		config:              config,
+		repositoryTxer:      repositoryTxer,
+		lifecycleRepository: lifecycleRepository,
+		disabledByOrg:       make(map[uuid.UUID]*cachedDisabled),
+	}
+
+	jobService.RegisterHandler(trialExpiryJobType, s.handleTrialExpiryJob)
+	jobService.Schedule(trialExpiryJobType, time.Hour)
+
+	return s
+}
+
+// ReadLifecycle reads the lifecycle of the organization
+func (s *LifecycleService) ReadLifecycle(ctx context.Context, organizationID uuid.UUID) (*Lifecycle, error) {
+	return s.lifecycleRepository.FindLifecycle(ctx, organizationID)
+}
+
+// ChangeStatus changes the status of the organization within the transaction of the context
+func (s *LifecycleService) ChangeStatus(ctxWithTx context.Context, organizationID uuid.UUID, status string) error {
+	lifecycle, err := s.lifecycleRepository.FindLifecycle(ctxWithTx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	if lifecycle.Status == status {
+		return nil
+	}
+	if !lifecycle.CanChangeTo(status) {
+		return ErrInvalidStatusTransition
+	}
+
+	now := time.Now()
+	lifecycle.Status = status
+	lifecycle.StatusChangedAt = &now
+	return s.lifecycleRepository.UpdateLifecycle(ctxWithTx, lifecycle)
+}
+
+// DisableOrganization disables the organization within the transaction of the context, its users are locked out
+func (s *LifecycleService) DisableOrganization(ctxWithTx context.Context, organizationID uuid.UUID) error {
+	return s.changeDisabled(ctxWithTx, organizationID, OrganizationStatusDisabled)
+}
+
+// EnableOrganization enables the disabled organization again within the transaction of the context
+func (s *LifecycleService) EnableOrganization(ctxWithTx context.Context, organizationID uuid.UUID) error {
+	lifecycle, err := s.lifecycleRepository.FindLifecycle(ctxWithTx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	if !lifecycle.IsDisabled() {
+		return nil
+	}
+	return s.changeDisabled(ctxWithTx, organizationID, OrganizationStatusActive)
+}
+
+func (s *LifecycleService) changeDisabled(ctxWithTx context.Context, organizationID uuid.UUID, status string) error {
+	lifecycle, err := s.lifecycleRepository.FindLifecycle(ctxWithTx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	if lifecycle.Status == status {
+		return nil
+	}
+
+	now := time.Now()
+	lifecycle.Status = status
+	lifecycle.StatusChangedAt = &now
+	err = s.lifecycleRepository.UpdateLifecycle(ctxWithTx, lifecycle)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.disabledByOrg, organizationID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// OrganizationInitializer starts the trial of new organizations, organizations are active if no trial is configured
+func (s *LifecycleService) OrganizationInitializer() func(ctx context.Context, organizationID uuid.UUID) error {
+	return func(ctx context.Context, organizationID uuid.UUID) error {
+		if s.config.TrialDays <= 0 {
+			return nil
+		}
+
+		now := time.Now()
+		trialEndsAt := now.AddDate(0, 0, s.config.TrialDays)
+		return s.lifecycleRepository.UpdateLifecycle(ctx, &Lifecycle{
+			OrganizationID:  organizationID,
+			Status:          OrganizationStatusTrial,
+			TrialEndsAt:     &trialEndsAt,
+			StatusChangedAt: &now,
+		})
+	}
+}
+
+// ReadOnlyMiddleware rejects changes of organizations that are read-only, except for requests to the writable paths
+func (s *LifecycleService) ReadOnlyMiddleware(writablePathSuffixes ...string) func(http.Handler) http.Handler {
+	isProduction := s.config.IsProduction()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+			for _, suffix := range writablePathSuffixes {
+				if strings.HasSuffix(r.URL.Path, suffix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+			lifecycle, err := s.lifecycleRepository.FindLifecycle(r.Context(), principal.OrganizationID)
+			if err != nil {
+				RenderProblemJSON(w, isProduction, err)
+				return
+			}
+
+			if lifecycle.IsReadOnly() {
+				RenderProblemJSON(w, isProduction, ErrOrganizationReadOnly)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DisabledMiddleware rejects all requests of organizations disabled by the operators of the instance
+func (s *LifecycleService) DisabledMiddleware() func(http.Handler) http.Handler {
+	isProduction := s.config.IsProduction()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+			disabled, err := s.isDisabled(r.Context(), principal.OrganizationID)
+			if err != nil {
+				RenderProblemJSON(w, isProduction, err)
+				return
+			}
+
+			if disabled {
+				RenderProblemJSON(w, isProduction, ErrOrganizationDisabled)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isDisabled checks whether the organization is disabled, cached for a minute
+func (s *LifecycleService) isDisabled(ctx context.Context, organizationID uuid.UUID) (bool, error) {
+	s.mu.Lock()
+	cached, ok := s.disabledByOrg[organizationID]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.disabled, nil
+	}
+
+	lifecycle, err := s.lifecycleRepository.FindLifecycle(ctx, organizationID)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	s.disabledByOrg[organizationID] = &cachedDisabled{disabled: lifecycle.IsDisabled(), expiresAt: time.Now().Add(disabledCacheExpiry)}
+	s.mu.Unlock()
+
+	return lifecycle.IsDisabled(), nil
+}
+
+// handleTrialExpiryJob suspends the organizations whose trial ended
+func (s *LifecycleService) handleTrialExpiryJob(ctx context.Context, job *Job) error {
+	organizationIDs, err := s.lifecycleRepository.FindOrganizationsWithExpiredTrial(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, organizationID := range organizationIDs {
+		organizationID := organizationID
+		err := s.repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return s.ChangeStatus(ctx, organizationID, OrganizationStatusSuspended)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		log.Printf("suspended organization %s after end of trial", organizationID)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func newTestLifecycleService(config *Config) (*LifecycleService, *InMemLifecycleRepository) {
+	lifecycleRepository := NewInMemLifecycleRepository()
+	jobService := NewJobService(NewInMemRepositoryTxer(), NewInMemJobRepository())
+	return NewLifecycleService(config, NewInMemRepositoryTxer(), jobService, lifecycleRepository), lifecycleRepository
+}
+
+func TestChangeStatus(t *testing.T) {
+	is := is.New(t)
+
+	lifecycleService, _ := newTestLifecycleService(&Config{})
+	ctx := context.Background()
+
+	is.NoErr(lifecycleService.ChangeStatus(ctx, OrganizationIDSample, OrganizationStatusPastDue))
+	is.NoErr(lifecycleService.ChangeStatus(ctx, OrganizationIDSample, OrganizationStatusSuspended))
+	is.Equal(lifecycleService.ChangeStatus(ctx, OrganizationIDSample, OrganizationStatusTrial), ErrInvalidStatusTransition)
+	is.Equal(lifecycleService.ChangeStatus(ctx, OrganizationIDSample, OrganizationStatusPastDue), ErrInvalidStatusTransition)
+
+	lifecycle, err := lifecycleService.ReadLifecycle(ctx, OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(lifecycle.Status, OrganizationStatusSuspended)
+	is.True(lifecycle.IsReadOnly())
+	is.True(lifecycle.StatusChangedAt != nil)
+}
+
+func TestTrialExpiry(t *testing.T) {
+	is := is.New(t)
+
+	lifecycleService, lifecycleRepository := newTestLifecycleService(&Config{TrialDays: 14})
+	ctx := context.Background()
+
+	err := lifecycleService.OrganizationInitializer()(ctx, OrganizationIDSample)
+	is.NoErr(err)
+
+	lifecycle, err := lifecycleService.ReadLifecycle(ctx, OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(lifecycle.Status, OrganizationStatusTrial)
+	is.Equal(lifecycle.TrialDaysLeft(time.Now()), 14)
+
+	// trial not yet ended
+	is.NoErr(lifecycleService.handleTrialExpiryJob(ctx, NewJob(OrganizationIDSample, trialExpiryJobType, "")))
+	lifecycle, _ = lifecycleService.ReadLifecycle(ctx, OrganizationIDSample)
+	is.Equal(lifecycle.Status, OrganizationStatusTrial)
+
+	// trial ended
+	trialEndsAt := time.Now().Add(-time.Hour)
+	lifecycle.TrialEndsAt = &trialEndsAt
+	is.NoErr(lifecycleRepository.UpdateLifecycle(ctx, lifecycle))
+
+	is.NoErr(lifecycleService.handleTrialExpiryJob(ctx, NewJob(OrganizationIDSample, trialExpiryJobType, "")))
+	lifecycle, _ = lifecycleService.ReadLifecycle(ctx, OrganizationIDSample)
+	is.Equal(lifecycle.Status, OrganizationStatusSuspended)
+}
+
+func TestOrganizationInitializerWithoutTrial(t *testing.T) {
+	is := is.New(t)
+
+	lifecycleService, _ := newTestLifecycleService(&Config{})
+	ctx := context.Background()
+
+	is.NoErr(lifecycleService.OrganizationInitializer()(ctx, OrganizationIDSample))
+
+	lifecycle, err := lifecycleService.ReadLifecycle(ctx, OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(lifecycle.Status, OrganizationStatusActive)
+}
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	lifecycleService, _ := newTestLifecycleService(&Config{})
+	is.NoErr(lifecycleService.ChangeStatus(context.Background(), OrganizationIDSample, OrganizationStatusCancelled))
+
+	handler := lifecycleService.ReadOnlyMiddleware("/billing/checkout")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func(method, path string) int {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest(method, path, nil)
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+			OrganizationID: OrganizationIDSample,
+		}))
+		handler.ServeHTTP(httpRec, r)
+		return httpRec.Result().StatusCode
+	}
+
+	is.Equal(request("GET", "/api/projects"), http.StatusNoContent)
+	is.Equal(request("POST", "/api/projects"), http.StatusForbidden)
+	is.Equal(request("DELETE", "/api/activities/1"), http.StatusForbidden)
+	is.Equal(request("POST", "/api/billing/checkout"), http.StatusNoContent)
+}
+
+func TestDisableAndEnableOrganization(t *testing.T) {
+	is := is.New(t)
+
+	lifecycleService, _ := newTestLifecycleService(&Config{})
+	ctx := context.Background()
+
+	is.NoErr(lifecycleService.ChangeStatus(ctx, OrganizationIDSample, OrganizationStatusPastDue))
+	is.NoErr(lifecycleService.DisableOrganization(ctx, OrganizationIDSample))
+
+	lifecycle, err := lifecycleService.ReadLifecycle(ctx, OrganizationIDSample)
+	is.NoErr(err)
+	is.True(lifecycle.IsDisabled())
+	is.True(lifecycle.IsReadOnly())
+
+	// billing can not enable a disabled organization
+	is.Equal(lifecycleService.ChangeStatus(ctx, OrganizationIDSample, OrganizationStatusActive), ErrInvalidStatusTransition)
+
+	is.NoErr(lifecycleService.EnableOrganization(ctx, OrganizationIDSample))
+	lifecycle, _ = lifecycleService.ReadLifecycle(ctx, OrganizationIDSample)
+	is.Equal(lifecycle.Status, OrganizationStatusActive)
+
+	// enabling an organization not disabled keeps its status
+	is.NoErr(lifecycleService.ChangeStatus(ctx, OrganizationIDSample, OrganizationStatusCancelled))
+	is.NoErr(lifecycleService.EnableOrganization(ctx, OrganizationIDSample))
+	lifecycle, _ = lifecycleService.ReadLifecycle(ctx, OrganizationIDSample)
+	is.Equal(lifecycle.Status, OrganizationStatusCancelled)
+}
+
+func TestDisabledMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	lifecycleService, _ := newTestLifecycleService(&Config{})
+
+	handler := lifecycleService.DisabledMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func() int {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/projects", nil)
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+			OrganizationID: OrganizationIDSample,
+		}))
+		handler.ServeHTTP(httpRec, r)
+		return httpRec.Result().StatusCode
+	}
+
+	is.Equal(request(), http.StatusNoContent)
+
+	is.NoErr(lifecycleService.DisableOrganization(context.Background(), OrganizationIDSample))
+	is.Equal(request(), http.StatusForbidden)
+
+	is.NoErr(lifecycleService.EnableOrganization(context.Background(), OrganizationIDSample))
+	is.Equal(request(), http.StatusNoContent)
+}
+
+func TestLifecycleBanner(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Now()
+	trialEndsAt := now.Add(36 * time.Hour)
+
+	banner := (&Lifecycle{Status: OrganizationStatusTrial, TrialEndsAt: &trialEndsAt}).BannerOf(now)
+	is.Equal(banner.Message, "Your trial ends in 2 days.")
+
+	banner = (&Lifecycle{Status: OrganizationStatusSuspended}).BannerOf(now)
+	is.Equal(banner.Level, "danger")
+
+	is.Equal((&Lifecycle{Status: OrganizationStatusActive}).BannerOf(now), nil)
+}
@@ -0,0 +1,188 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+)
+
+const (
+	// maxLinkPreviewPageSize is the maximum size of the page read for the preview of a link
+	maxLinkPreviewPageSize = 512 * 1024
+	// maxLinkPreviewTitleLength is the maximum length of the title of a link preview
+	maxLinkPreviewTitleLength = 255
+	// maxLinkPreviewRedirects is the maximum number of redirects followed for the preview of a link
+	maxLinkPreviewRedirects = 3
+)
+
+// ErrLinkPreviewAddressNotAllowed is returned for links to loopback, private and other internal addresses
+var ErrLinkPreviewAddressNotAllowed = errors.New("address of link not allowed")
+
+// LinkPreview is the title and the favicon of the page of a link
+type LinkPreview struct {
+	Title      string
+	FaviconURL string
+}
+
+// LinkUnfurler fetches the previews of links
+type LinkUnfurler interface {
+	Unfurl(ctx context.Context, link string) (*LinkPreview, error)
+}
+
+// NewLinkUnfurler creates the unfurler of links, which is nil if unfurling links is disabled
+func NewLinkUnfurler(config *Config) LinkUnfurler {
+	if !config.LinkUnfurling {
+		return nil
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: denyInternalAddress,
+	}
+	return &httpLinkUnfurler{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxLinkPreviewRedirects {
+					return errors.New("too many redirects")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// httpLinkUnfurler reads the title and the favicon from the HTML of the page of a link
+type httpLinkUnfurler struct {
+	httpClient *http.Client
+}
+
+var _ LinkUnfurler = (*httpLinkUnfurler)(nil)
+
+func (u *httpLinkUnfurler) Unfurl(ctx context.Context, link string) (*LinkPreview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("User-Agent", "Baralga-Link-Preview/1.0")
+
+	res, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("fetching link failed with status %v", res.StatusCode)
+	}
+
+	preview := &LinkPreview{}
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if mediaType == "text/html" {
+		preview = parseLinkPreview(io.LimitReader(res.Body, maxLinkPreviewPageSize), res.Request.URL)
+	}
+	if preview.FaviconURL == "" {
+		preview.FaviconURL = res.Request.URL.ResolveReference(&url.URL{Path: "/favicon.ico"}).String()
+	}
+	return preview, nil
+}
+
+// parseLinkPreview reads the title and favicon from the head of the page, the title of Open Graph is preferred
+func parseLinkPreview(page io.Reader, pageURL *url.URL) *LinkPreview {
+	preview := &LinkPreview{}
+	var title, ogTitle string
+
+	tokenizer := html.NewTokenizer(page)
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+
+		token := tokenizer.Token()
+		if tokenType == html.EndTagToken && token.Data == "head" {
+			break
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+
+		switch token.Data {
+		case "title":
+			if tokenizer.Next() == html.TextToken {
+				title = string(tokenizer.Text())
+			}
+		case "meta":
+			if linkPreviewAttr(token, "property") == "og:title" {
+				ogTitle = linkPreviewAttr(token, "content")
+			}
+		case "link":
+			rel := strings.Fields(strings.ToLower(linkPreviewAttr(token, "rel")))
+			if preview.FaviconURL != "" || !(slices.Contains(rel, "icon") || slices.Contains(rel, "apple-touch-icon")) {
+				continue
+			}
+			href, err := url.Parse(strings.TrimSpace(linkPreviewAttr(token, "href")))
+			if err != nil {
+				continue
+			}
+			favicon := pageURL.ResolveReference(href)
+			if favicon.Scheme == "http" || favicon.Scheme == "https" {
+				preview.FaviconURL = favicon.String()
+			}
+		}
+	}
+
+	if ogTitle != "" {
+		title = ogTitle
+	}
+	preview.Title = truncateLinkPreviewTitle(strings.Join(strings.Fields(title), " "))
+	return preview
+}
+
+func linkPreviewAttr(token html.Token, name string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func truncateLinkPreviewTitle(title string) string {
+	if utf8.RuneCountInString(title) <= maxLinkPreviewTitleLength {
+		return title
+	}
+	return string([]rune(title)[:maxLinkPreviewTitleLength-1]) + "…"
+}
+
+// denyInternalAddress prevents links from reaching loopback, private and link local addresses
+// like the metadata services of clouds, the address is checked after resolving the host
+func denyInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return ErrLinkPreviewAddressNotAllowed
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestNewLinkUnfurler(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(NewLinkUnfurler(&Config{}), nil)
+	is.True(NewLinkUnfurler(&Config{LinkUnfurling: true}) != nil)
+}
+
+func TestParseLinkPreview(t *testing.T) {
+	is := is.New(t)
+
+	pageURL, _ := url.Parse("https://example.com/tickets/1")
+
+	preview := parseLinkPreview(strings.NewReader(`<html><head>
+		<title>
+		  Ticket 1 &amp; more
+		</title>
+		<link rel="shortcut icon" href="/static/icon.png">
+		</head><body><title>Not this</title></body></html>`), pageURL)
+	is.Equal(preview.Title, "Ticket 1 & more")
+	is.Equal(preview.FaviconURL, "https://example.com/static/icon.png")
+
+	preview = parseLinkPreview(strings.NewReader(`<head><title>Page</title>
+		<meta property="og:title" content="Fix login">
+		<link rel="icon" href="javascript:alert(1)">
+		</head>`), pageURL)
+	is.Equal(preview.Title, "Fix login")
+	is.Equal(preview.FaviconURL, "")
+
+	preview = parseLinkPreview(strings.NewReader(`<title>`+strings.Repeat("a", 300)+`</title>`), pageURL)
+	is.Equal(len([]rune(preview.Title)), maxLinkPreviewTitleLength)
+}
+
+func TestHttpLinkUnfurler(t *testing.T) {
+	is := is.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tickets/1":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(`<html><head><title>Ticket 1</title></head></html>`))
+		case "/document.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte(`%PDF-1.4`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	u := &httpLinkUnfurler{httpClient: server.Client()}
+
+	preview, err := u.Unfurl(context.Background(), server.URL+"/tickets/1")
+	is.NoErr(err)
+	is.Equal(preview.Title, "Ticket 1")
+	is.Equal(preview.FaviconURL, server.URL+"/favicon.ico")
+
+	preview, err = u.Unfurl(context.Background(), server.URL+"/document.pdf")
+	is.NoErr(err)
+	is.Equal(preview.Title, "")
+
+	_, err = u.Unfurl(context.Background(), server.URL+"/missing")
+	is.True(err != nil)
+}
+
+func TestLinkUnfurlerDeniesInternalAddresses(t *testing.T) {
+	is := is.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<title>Internal</title>`))
+	}))
+	defer server.Close()
+
+	_, err := NewLinkUnfurler(&Config{LinkUnfurling: true}).Unfurl(context.Background(), server.URL)
+	is.True(errors.Is(err, ErrLinkPreviewAddressNotAllowed))
+
+	is.True(errors.Is(denyInternalAddress("tcp", "169.254.169.254:80", nil), ErrLinkPreviewAddressNotAllowed))
+	is.True(errors.Is(denyInternalAddress("tcp", "10.0.0.1:443", nil), ErrLinkPreviewAddressNotAllowed))
+	is.True(errors.Is(denyInternalAddress("tcp", "[::1]:443", nil), ErrLinkPreviewAddressNotAllowed))
+	is.NoErr(denyInternalAddress("tcp", "93.184.216.34:443", nil))
+}
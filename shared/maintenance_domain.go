@@ -0,0 +1,50 @@
+package shared
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	MaintenanceSourceAPI  = "api"
+	MaintenanceSourceFile = "file"
+
+	// defaultMaintenanceMessage is the message of a maintenance started without a message
+	defaultMaintenanceMessage = "Baralga is in maintenance. Changes are not possible at the moment, please try again later."
+
+	// maxMaintenanceMessageLength is the maximum length of the message of a maintenance
+	maxMaintenanceMessageLength = 500
+)
+
+var ErrMaintenance = NewDomainError("instance:maintenance", http.StatusServiceUnavailable, "instance is in maintenance")
+
+// Maintenance is the read-only mode of the instance switched on by its operators,
+// either at runtime by the api or by a flag file
+type Maintenance struct {
+	Enabled   bool
+	Message   string
+	Source    string
+	StartedAt *time.Time
+	StartedBy string
+}
+
+// MaintenanceError is the error of a change rejected during maintenance with the message for the users
+type MaintenanceError struct {
+	Message string
+}
+
+// BannerOf is the banner shown to all users during maintenance, nil if the instance is not in maintenance
+func (m *Maintenance) BannerOf() *Banner {
+	if !m.Enabled {
+		return nil
+	}
+	return &Banner{Level: "warning", Message: m.Message}
+}
+
+func (e *MaintenanceError) Error() string {
+	return e.Message
+}
+
+func (e *MaintenanceError) Unwrap() error {
+	return ErrMaintenance
+}
@@ -0,0 +1,146 @@
+package shared
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type maintenanceModel struct {
+	Enabled   bool         `json:"enabled"`
+	Message   string       `json:"message,omitempty"`
+	Source    string       `json:"source,omitempty"`
+	StartedAt string       `json:"startedAt,omitempty"`
+	StartedBy string       `json:"startedBy,omitempty"`
+	Banner    *bannerModel `json:"banner,omitempty"`
+	Links     *hal.Links   `json:"_links"`
+}
+
+type MaintenanceRestHandlers struct {
+	config             *Config
+	maintenanceService *MaintenanceService
+}
+
+func NewMaintenanceRestHandlers(config *Config, maintenanceService *MaintenanceService) *MaintenanceRestHandlers {
+	return &MaintenanceRestHandlers{
+		config:             config,
+		maintenanceService: maintenanceService,
+	}
+}
+
+func (a *MaintenanceRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/instance/maintenance", a.HandleGetInstanceMaintenance())
+	r.Put("/instance/maintenance", a.HandleStartMaintenance())
+	r.Delete("/instance/maintenance", a.HandleStopMaintenance())
+}
+
+func (a *MaintenanceRestHandlers) RegisterOpen(r chi.Router) {
+	r.Get("/maintenance", a.HandleGetMaintenance())
+}
+
+// HandleGetMaintenance reads whether the instance is in maintenance with the banner to show to all users
+func (a *MaintenanceRestHandlers) HandleGetMaintenance() http.HandlerFunc {
+	maintenanceService := a.maintenanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		maintenance := maintenanceService.ReadMaintenance()
+
+		maintenanceModel := &maintenanceModel{
+			Enabled: maintenance.Enabled,
+			Message: maintenance.Message,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		if banner := maintenance.BannerOf(); banner != nil {
+			maintenanceModel.Banner = &bannerModel{
+				Level:   banner.Level,
+				Message: banner.Message,
+			}
+		}
+
+		RenderJSON(w, maintenanceModel)
+	}
+}
+
+// HandleGetInstanceMaintenance reads the maintenance of the instance with its details for the operators
+func (a *MaintenanceRestHandlers) HandleGetInstanceMaintenance() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	config := a.config
+	maintenanceService := a.maintenanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		err := AuthorizeInstanceAdmin(config, principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToMaintenanceModel(maintenanceService.ReadMaintenance()))
+	}
+}
+
+// HandleStartMaintenance switches the instance into maintenance with the message for the users
+func (a *MaintenanceRestHandlers) HandleStartMaintenance() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	maintenanceService := a.maintenanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		var model maintenanceModel
+		err := json.NewDecoder(r.Body).Decode(&model)
+		if err != nil {
+			RenderValidationProblemJSON(w, "maintenance not valid", err)
+			return
+		}
+
+		if len(model.Message) > maxMaintenanceMessageLength {
+			RenderValidationProblemJSON(w, "maintenance not valid", NewInvalidParam("message", "too-long", "message is too long"))
+			return
+		}
+
+		maintenance, err := maintenanceService.StartMaintenance(principal, model.Message)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToMaintenanceModel(maintenance))
+	}
+}
+
+// HandleStopMaintenance ends the maintenance started by the api
+func (a *MaintenanceRestHandlers) HandleStopMaintenance() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	maintenanceService := a.maintenanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		maintenance, err := maintenanceService.StopMaintenance(principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToMaintenanceModel(maintenance))
+	}
+}
+
+func mapToMaintenanceModel(maintenance *Maintenance) *maintenanceModel {
+	maintenanceModel := &maintenanceModel{
+		Enabled:   maintenance.Enabled,
+		Message:   maintenance.Message,
+		Source:    maintenance.Source,
+		StartedBy: maintenance.StartedBy,
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/instance/maintenance"),
+		),
+	}
+	if maintenance.StartedAt != nil {
+		maintenanceModel.StartedAt = maintenance.StartedAt.Format(time.RFC3339)
+	}
+	return maintenanceModel
+}
@@ -0,0 +1,67 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestHandleStartMaintenance(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	config := &Config{InstanceAdmins: "ops@baralga.com"}
+	maintenanceService := NewMaintenanceService(config)
+	a := NewMaintenanceRestHandlers(config, maintenanceService)
+
+	r := httptest.NewRequest("PUT", "/api/instance/maintenance", strings.NewReader(`{"message": "Back at 10:00."}`))
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+	a.HandleStartMaintenance()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	model := &maintenanceModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(model)
+	is.NoErr(err)
+	is.True(model.Enabled)
+	is.Equal(model.Message, "Back at 10:00.")
+	is.Equal(model.StartedBy, "ops@baralga.com")
+
+	t.Run("HandleGetMaintenance", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/maintenance", nil)
+
+		a.HandleGetMaintenance()(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		model := &maintenanceModel{}
+		err := json.NewDecoder(httpRec.Body).Decode(model)
+		is.NoErr(err)
+		is.True(model.Enabled)
+		is.Equal(model.Banner.Message, "Back at 10:00.")
+		is.Equal(model.StartedBy, "")
+	})
+}
+
+func TestHandleStartMaintenanceAsOrgAdmin(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	config := &Config{InstanceAdmins: "ops@baralga.com"}
+	a := NewMaintenanceRestHandlers(config, NewMaintenanceService(config))
+
+	r := httptest.NewRequest("PUT", "/api/instance/maintenance", strings.NewReader(`{}`))
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		Username:       "admin",
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleStartMaintenance()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
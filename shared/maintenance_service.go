@@ -0,0 +1,140 @@
+package shared
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaintenanceService switches the instance into a read-only maintenance mode, so that operators can run
+// risky migrations without shutting the instance down. The maintenance is started at runtime by an instance admin
+// or by creating the configured flag file, which also covers all replicas of the instance.
+type MaintenanceService struct {
+	config *Config
+
+	mu          sync.Mutex
+	maintenance *Maintenance
+}
+
+// NewMaintenanceService creates a new service for the maintenance mode
+func NewMaintenanceService(config *Config) *MaintenanceService {
+	return &MaintenanceService{
+		config: config,
+	}
+}
+
+// ReadMaintenance reads the maintenance of the instance, the flag file takes precedence over the runtime switch
+func (s *MaintenanceService) ReadMaintenance() *Maintenance {
+	if maintenance := s.maintenanceOfFile(); maintenance != nil {
+		return maintenance
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maintenance == nil {
+		return &Maintenance{}
+	}
+	maintenance := *s.maintenance
+	return &maintenance
+}
+
+// StartMaintenance switches the instance into maintenance with the message for the users
+func (s *MaintenanceService) StartMaintenance(principal *Principal, message string) (*Maintenance, error) {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.maintenance = &Maintenance{
+		Enabled:   true,
+		Message:   message,
+		Source:    MaintenanceSourceAPI,
+		StartedAt: &now,
+		StartedBy: principal.Username,
+	}
+	s.mu.Unlock()
+
+	return s.ReadMaintenance(), nil
+}
+
+// StopMaintenance ends the maintenance started at runtime, a maintenance of the flag file lasts until the file is removed
+func (s *MaintenanceService) StopMaintenance(principal *Principal) (*Maintenance, error) {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.maintenance = nil
+	s.mu.Unlock()
+
+	return s.ReadMaintenance(), nil
+}
+
+// ReadOnlyMiddleware rejects changes during maintenance, except for requests to the writable paths
+func (s *MaintenanceService) ReadOnlyMiddleware(writablePathSuffixes ...string) func(http.Handler) http.Handler {
+	isProduction := s.config.IsProduction()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+			for _, suffix := range writablePathSuffixes {
+				if strings.HasSuffix(r.URL.Path, suffix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			maintenance := s.ReadMaintenance()
+			if maintenance.Enabled {
+				w.Header().Set("Retry-After", "300")
+				RenderProblemJSON(w, isProduction, &MaintenanceError{Message: maintenance.Message})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// maintenanceOfFile is the maintenance of the flag file, nil if no flag file is configured or it does not exist.
+// The content of the file is the message for the users.
+func (s *MaintenanceService) maintenanceOfFile() *Maintenance {
+	if s.config.MaintenanceFile == "" {
+		return nil
+	}
+
+	fileInfo, err := os.Stat(s.config.MaintenanceFile)
+	if err != nil {
+		return nil
+	}
+
+	message := defaultMaintenanceMessage
+	content, err := os.ReadFile(s.config.MaintenanceFile)
+	if err == nil && strings.TrimSpace(string(content)) != "" {
+		message = strings.TrimSpace(string(content))
+	}
+	if len(message) > maxMaintenanceMessageLength {
+		message = message[:maxMaintenanceMessageLength]
+	}
+
+	startedAt := fileInfo.ModTime()
+	return &Maintenance{
+		Enabled:   true,
+		Message:   message,
+		Source:    MaintenanceSourceFile,
+		StartedAt: &startedAt,
+	}
+}
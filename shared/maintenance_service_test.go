@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestStartAndStopMaintenance(t *testing.T) {
+	is := is.New(t)
+
+	maintenanceService := NewMaintenanceService(&Config{InstanceAdmins: "ops@baralga.com"})
+	principal := newInstanceAdminPrincipal()
+
+	is.True(!maintenanceService.ReadMaintenance().Enabled)
+
+	maintenance, err := maintenanceService.StartMaintenance(principal, "")
+	is.NoErr(err)
+	is.True(maintenance.Enabled)
+	is.Equal(maintenance.Message, defaultMaintenanceMessage)
+	is.Equal(maintenance.Source, MaintenanceSourceAPI)
+	is.Equal(maintenance.StartedBy, "ops@baralga.com")
+	is.Equal(maintenance.BannerOf().Level, "warning")
+
+	maintenance, err = maintenanceService.StopMaintenance(principal)
+	is.NoErr(err)
+	is.True(!maintenance.Enabled)
+	is.Equal(maintenance.BannerOf(), nil)
+
+	// org admins can not start a maintenance
+	_, err = maintenanceService.StartMaintenance(&Principal{Username: "admin", Roles: []string{"ROLE_ADMIN"}}, "")
+	is.Equal(err, ErrForbidden)
+}
+
+func TestMaintenanceOfFile(t *testing.T) {
+	is := is.New(t)
+
+	maintenanceFile := filepath.Join(t.TempDir(), "maintenance")
+	maintenanceService := NewMaintenanceService(&Config{InstanceAdmins: "ops@baralga.com", MaintenanceFile: maintenanceFile})
+
+	is.True(!maintenanceService.ReadMaintenance().Enabled)
+
+	is.NoErr(os.WriteFile(maintenanceFile, []byte("Upgrade of the database until 10:00.\n"), 0600))
+
+	maintenance := maintenanceService.ReadMaintenance()
+	is.True(maintenance.Enabled)
+	is.Equal(maintenance.Message, "Upgrade of the database until 10:00.")
+	is.Equal(maintenance.Source, MaintenanceSourceFile)
+
+	// the flag file lasts until it's removed
+	maintenance, err := maintenanceService.StopMaintenance(newInstanceAdminPrincipal())
+	is.NoErr(err)
+	is.True(maintenance.Enabled)
+
+	is.NoErr(os.Remove(maintenanceFile))
+	is.True(!maintenanceService.ReadMaintenance().Enabled)
+}
+
+func TestMaintenanceReadOnlyMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	maintenanceService := NewMaintenanceService(&Config{InstanceAdmins: "ops@baralga.com"})
+	_, err := maintenanceService.StartMaintenance(newInstanceAdminPrincipal(), "Back at 10:00.")
+	is.NoErr(err)
+
+	handler := maintenanceService.ReadOnlyMiddleware("/instance/maintenance")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest(method, path, nil)
+		r = r.WithContext(context.Background())
+		handler.ServeHTTP(httpRec, r)
+		return httpRec
+	}
+
+	is.Equal(request("GET", "/api/projects").Code, http.StatusNoContent)
+	is.Equal(request("DELETE", "/api/instance/maintenance").Code, http.StatusNoContent)
+
+	httpRec := request("POST", "/api/projects")
+	is.Equal(httpRec.Code, http.StatusServiceUnavailable)
+	is.Equal(httpRec.Header().Get("Retry-After"), "300")
+	is.True(httpRec.Body.Len() > 0)
+
+	_, err = maintenanceService.StopMaintenance(newInstanceAdminPrincipal())
+	is.NoErr(err)
+	is.Equal(request("POST", "/api/projects").Code, http.StatusNoContent)
+}
@@ -0,0 +1,215 @@
+package shared
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	g "github.com/maragudk/gomponents"
+)
+
+// markdownListItemPattern matches the items of unordered lists like "- item" and ordered lists like "1. item"
+var markdownListItemPattern = regexp.MustCompile(`^\s*(?:([-*+])|(\d{1,9})\.)\s+(.*)$`)
+
+// markdownLinkSchemes are the schemes of links which are rendered, links like javascript: are rendered as text
+var markdownLinkSchemes = []string{"http", "https", "mailto"}
+
+// RenderMarkdown renders the basic Markdown of texts like activity descriptions as HTML: paragraphs, line breaks,
+// lists, **bold**, *italic*, ~~strikethrough~~, `code` and [links](https://baralga.com). The text is escaped
+// and just these elements are generated, so the HTML is safe to embed without further sanitization.
+func RenderMarkdown(text string) string {
+	sb := &strings.Builder{}
+
+	var paragraph []string
+	listTag := ""
+	closeBlock := func() {
+		if len(paragraph) > 0 {
+			sb.WriteString("<p>")
+			for i, line := range paragraph {
+				if i > 0 {
+					sb.WriteString("<br>")
+				}
+				sb.WriteString(markdownInline(line, true))
+			}
+			sb.WriteString("</p>")
+			paragraph = nil
+		}
+		if listTag != "" {
+			sb.WriteString("</" + listTag + ">")
+			listTag = ""
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			closeBlock()
+			continue
+		}
+
+		item := markdownListItemPattern.FindStringSubmatch(line)
+		if item == nil {
+			if listTag != "" {
+				closeBlock()
+			}
+			paragraph = append(paragraph, strings.TrimSpace(line))
+			continue
+		}
+
+		tag := "ul"
+		if item[2] != "" {
+			tag = "ol"
+		}
+		if len(paragraph) > 0 || listTag != tag {
+			closeBlock()
+			listTag = tag
+			sb.WriteString("<" + tag + ">")
+		}
+		sb.WriteString("<li>" + markdownInline(item[3], true) + "</li>")
+	}
+	closeBlock()
+
+	return sb.String()
+}
+
+// MarkdownToPlainText removes the Markdown of the text for exports like CSV, links are written as text and url
+func MarkdownToPlainText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		item := markdownListItemPattern.FindStringSubmatch(line)
+		switch {
+		case item == nil:
+			lines[i] = markdownInline(strings.TrimSpace(line), false)
+		case item[2] != "":
+			lines[i] = item[2] + ". " + markdownInline(item[3], false)
+		default:
+			lines[i] = "- " + markdownInline(item[3], false)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Markdown renders the Markdown of the text as node
+func Markdown(text string) g.Node {
+	return g.Raw(RenderMarkdown(text))
+}
+
+// markdownInline renders the inline elements of the text as HTML or removes them for plain text
+func markdownInline(text string, asHTML bool) string {
+	sb := &strings.Builder{}
+	writeText := func(s string) {
+		if asHTML {
+			s = html.EscapeString(s)
+		}
+		sb.WriteString(s)
+	}
+	writeElement := func(tag, content string) {
+		if asHTML {
+			sb.WriteString("<" + tag + ">" + content + "</" + tag + ">")
+			return
+		}
+		sb.WriteString(content)
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_~[]()#+-.!", rune(rest[1])):
+			writeText(rest[1:2])
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end > 0 {
+				code := rest[1 : end+1]
+				if asHTML {
+					code = html.EscapeString(code)
+				}
+				writeElement("code", code)
+				i += end + 2
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__") || strings.HasPrefix(rest, "~~"):
+			delimiter := rest[:2]
+			if end := strings.Index(rest[2:], delimiter); end > 0 && (delimiter != "__" || isMarkdownWordStart(text, i)) {
+				tag := "strong"
+				if delimiter == "~~" {
+					tag = "del"
+				}
+				writeElement(tag, markdownInline(rest[2:end+2], asHTML))
+				i += end + 4
+				continue
+			}
+
+		case rest[0] == '*' || rest[0] == '_':
+			delimiter := rest[:1]
+			if end := strings.Index(rest[1:], delimiter); end > 0 && rest[1] != ' ' && (delimiter != "_" || isMarkdownWordStart(text, i)) {
+				writeElement("em", markdownInline(rest[1:end+1], asHTML))
+				i += end + 2
+				continue
+			}
+
+		case rest[0] == '[':
+			if label, href, n, ok := markdownLink(rest); ok {
+				switch {
+				case !isMarkdownLinkSafe(href):
+					sb.WriteString(markdownInline(label, asHTML))
+				case asHTML:
+					sb.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer" target="_blank">`)
+					sb.WriteString(markdownInline(label, asHTML))
+					sb.WriteString("</a>")
+				case label == href:
+					sb.WriteString(href)
+				default:
+					sb.WriteString(markdownInline(label, asHTML) + " (" + href + ")")
+				}
+				i += n
+				continue
+			}
+		}
+
+		writeText(rest[:1])
+		i++
+	}
+	return sb.String()
+}
+
+// markdownLink parses a link like [label](href) at the start of the text
+func markdownLink(text string) (string, string, int, bool) {
+	labelEnd := strings.Index(text, "](")
+	if labelEnd < 1 {
+		return "", "", 0, false
+	}
+	hrefEnd := strings.IndexByte(text[labelEnd+2:], ')')
+	if hrefEnd < 1 {
+		return "", "", 0, false
+	}
+
+	href := strings.TrimSpace(text[labelEnd+2 : labelEnd+2+hrefEnd])
+	return text[1:labelEnd], href, labelEnd + 3 + hrefEnd, !strings.ContainsAny(href, " \t")
+}
+
+// isMarkdownLinkSafe checks whether the link has an absolute url with a scheme rendered as link
+func isMarkdownLinkSafe(href string) bool {
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	for _, scheme := range markdownLinkSchemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMarkdownWordStart checks whether the position is at the start of a word, so underscores
+// within words like snake_case are not taken as emphasis
+func isMarkdownWordStart(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	previous := text[i-1]
+	return !(previous >= 'a' && previous <= 'z' || previous >= 'A' && previous <= 'Z' || previous >= '0' && previous <= '9')
+}
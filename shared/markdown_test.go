@@ -0,0 +1,39 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(RenderMarkdown("Workshop prep"), "<p>Workshop prep</p>")
+	is.Equal(RenderMarkdown("**Release** of *v2* with `make build`"), "<p><strong>Release</strong> of <em>v2</em> with <code>make build</code></p>")
+	is.Equal(RenderMarkdown("First line\nsecond line\n\nNext paragraph"), "<p>First line<br>second line</p><p>Next paragraph</p>")
+	is.Equal(RenderMarkdown("Tasks:\n- review\n- ~~deploy~~\n1. later"), "<p>Tasks:</p><ul><li>review</li><li><del>deploy</del></li></ul><ol><li>later</li></ol>")
+	is.Equal(RenderMarkdown("See [ticket](https://baralga.com/t/1)"), `<p>See <a href="https://baralga.com/t/1" rel="nofollow noopener noreferrer" target="_blank">ticket</a></p>`)
+	is.Equal(RenderMarkdown("fix snake_case_name and 2 * 3 * 4"), "<p>fix snake_case_name and 2 * 3 * 4</p>")
+	is.Equal(RenderMarkdown(`\*not italic\*`), "<p>*not italic*</p>")
+}
+
+func TestRenderMarkdownSanitizes(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(RenderMarkdown(`<script>alert("x")</script>`), "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</p>")
+	is.Equal(RenderMarkdown("[click](javascript:alert(1))"), "<p>click)</p>")
+	is.Equal(RenderMarkdown(`[x](https://baralga.com/"onmouseover="alert(1))`), `<p><a href="https://baralga.com/&#34;onmouseover=&#34;alert(1" rel="nofollow noopener noreferrer" target="_blank">x</a>)</p>`)
+	is.Equal(RenderMarkdown("`<b>code</b>`"), "<p><code>&lt;b&gt;code&lt;/b&gt;</code></p>")
+	is.Equal(RenderMarkdown("**<img src=x onerror=alert(1)>**"), "<p><strong>&lt;img src=x onerror=alert(1)&gt;</strong></p>")
+}
+
+func TestMarkdownToPlainText(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(MarkdownToPlainText("Workshop prep"), "Workshop prep")
+	is.Equal(MarkdownToPlainText("**Release** of *v2* with `make build`"), "Release of v2 with make build")
+	is.Equal(MarkdownToPlainText("Tasks:\n* review\n2. deploy"), "Tasks:\n- review\n2. deploy")
+	is.Equal(MarkdownToPlainText("See [ticket](https://baralga.com/t/1) or https://baralga.com"), "See ticket (https://baralga.com/t/1) or https://baralga.com")
+	is.Equal(MarkdownToPlainText("<b>as is</b>"), "<b>as is</b>")
+}
@@ -0,0 +1,50 @@
+package shared
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// poolMetric is a metric of the database connection pool in the Prometheus text format
+type poolMetric struct {
+	name       string
+	metricType string
+	help       string
+	value      float64
+}
+
+// HandlePoolMetrics renders the statistics of the database connection pool in the Prometheus text format
+func HandlePoolMetrics(connPool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writePoolMetrics(w, poolMetricsOf(connPool.Stat()))
+	}
+}
+
+func poolMetricsOf(stat *pgxpool.Stat) []*poolMetric {
+	return []*poolMetric{
+		{"baralga_db_pool_max_conns", "gauge", "Maximum size of the pool.", float64(stat.MaxConns())},
+		{"baralga_db_pool_total_conns", "gauge", "Total number of connections in the pool.", float64(stat.TotalConns())},
+		{"baralga_db_pool_idle_conns", "gauge", "Number of idle connections in the pool.", float64(stat.IdleConns())},
+		{"baralga_db_pool_acquired_conns", "gauge", "Number of connections currently acquired.", float64(stat.AcquiredConns())},
+		{"baralga_db_pool_constructing_conns", "gauge", "Number of connections being constructed.", float64(stat.ConstructingConns())},
+		{"baralga_db_pool_acquires_total", "counter", "Number of successful acquires from the pool.", float64(stat.AcquireCount())},
+		{"baralga_db_pool_acquire_duration_seconds_total", "counter", "Total duration of all successful acquires from the pool.", stat.AcquireDuration().Seconds()},
+		{"baralga_db_pool_empty_acquires_total", "counter", "Number of acquires that waited for a connection because the pool was empty.", float64(stat.EmptyAcquireCount())},
+		{"baralga_db_pool_canceled_acquires_total", "counter", "Number of acquires canceled by a context.", float64(stat.CanceledAcquireCount())},
+		{"baralga_db_pool_new_conns_total", "counter", "Number of new connections opened.", float64(stat.NewConnsCount())},
+		{"baralga_db_pool_max_lifetime_destroys_total", "counter", "Number of connections closed because of the max lifetime.", float64(stat.MaxLifetimeDestroyCount())},
+		{"baralga_db_pool_max_idle_destroys_total", "counter", "Number of connections closed because of the max idle time.", float64(stat.MaxIdleDestroyCount())},
+	}
+}
+
+func writePoolMetrics(w io.Writer, metrics []*poolMetric) {
+	for _, metric := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n", metric.name, metric.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", metric.name, metric.metricType)
+		fmt.Fprintf(w, "%s %g\n", metric.name, metric.value)
+	}
+}
@@ -0,0 +1,26 @@
+package shared
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestWritePoolMetrics(t *testing.T) {
+	is := is.New(t)
+	buf := &bytes.Buffer{}
+
+	writePoolMetrics(buf, []*poolMetric{
+		{"baralga_db_pool_max_conns", "gauge", "Maximum size of the pool.", 10},
+		{"baralga_db_pool_acquire_duration_seconds_total", "counter", "Total duration of all successful acquires from the pool.", 1.5},
+	})
+
+	is.Equal(buf.String(), `# HELP baralga_db_pool_max_conns Maximum size of the pool.
+# TYPE baralga_db_pool_max_conns gauge
+baralga_db_pool_max_conns 10
+# HELP baralga_db_pool_acquire_duration_seconds_total Total duration of all successful acquires from the pool.
+# TYPE baralga_db_pool_acquire_duration_seconds_total counter
+baralga_db_pool_acquire_duration_seconds_total 1.5
+`)
+}
@@ -0,0 +1,2 @@
+-- Project billable flag
+ALTER TABLE projects ADD billable boolean not null DEFAULT false;
@@ -0,0 +1,2 @@
+-- Project budget
+ALTER TABLE projects ADD budget_minutes integer not null DEFAULT 0;
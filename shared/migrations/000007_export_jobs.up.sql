@@ -0,0 +1,27 @@
+-- Table export_jobs
+CREATE TABLE export_jobs (
+     export_job_id    uuid not null,
+     org_id           uuid not null,
+     username         varchar(255) not null,
+     content_type     varchar(100) not null,
+     status           varchar(20) not null,
+     progress         integer not null DEFAULT 0,
+     total            integer not null DEFAULT 0,
+     filter_start     timestamp not null,
+     filter_end       timestamp not null,
+     filter_username  varchar(255),
+     filter_name      varchar(100),
+     content          bytea,
+     created_at       timestamp not null,
+     expires_at       timestamp not null
+);
+
+ALTER TABLE export_jobs
+ADD CONSTRAINT pk_export_jobs PRIMARY KEY (export_job_id);
+
+ALTER TABLE export_jobs
+ADD CONSTRAINT fk_export_jobs_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE INDEX export_jobs_idx_status
+ON export_jobs (status);
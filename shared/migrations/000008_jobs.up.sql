@@ -0,0 +1,20 @@
+-- Table jobs
+CREATE TABLE jobs (
+     job_id        uuid not null,
+     org_id        uuid,
+     job_type      varchar(100) not null,
+     payload       varchar(4000),
+     status        varchar(20) not null,
+     attempts      integer not null DEFAULT 0,
+     max_attempts  integer not null DEFAULT 5,
+     run_at        timestamp not null,
+     last_error    varchar(4000),
+     created_at    timestamp not null,
+     updated_at    timestamp not null
+);
+
+ALTER TABLE jobs
+ADD CONSTRAINT pk_jobs PRIMARY KEY (job_id);
+
+CREATE INDEX jobs_idx_status_run_at
+ON jobs (status, run_at);
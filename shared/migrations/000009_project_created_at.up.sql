@@ -0,0 +1,2 @@
+-- Project creation time for sorting
+ALTER TABLE projects ADD created_at timestamp not null DEFAULT now();
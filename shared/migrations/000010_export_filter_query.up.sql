@@ -0,0 +1,2 @@
+-- Filter query of activity exports
+ALTER TABLE export_jobs ADD filter_query varchar(1000);
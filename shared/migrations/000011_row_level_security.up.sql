@@ -0,0 +1,19 @@
+-- Row level security on the organization set on the connection as defense in depth,
+-- without an organization on the connection all rows are visible for system tasks
+ALTER TABLE projects ENABLE ROW LEVEL SECURITY;
+ALTER TABLE projects FORCE ROW LEVEL SECURITY;
+CREATE POLICY projects_org_isolation ON projects
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+ALTER TABLE activities ENABLE ROW LEVEL SECURITY;
+ALTER TABLE activities FORCE ROW LEVEL SECURITY;
+CREATE POLICY activities_org_isolation ON activities
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+ALTER TABLE export_jobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE export_jobs FORCE ROW LEVEL SECURITY;
+CREATE POLICY export_jobs_org_isolation ON export_jobs
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,16 @@
+-- Table feature_flags, features without a flag of the organization use their default
+CREATE TABLE feature_flags (
+     org_id        uuid not null,
+     feature       varchar(100) not null,
+     enabled       boolean not null,
+     updated_at    timestamp not null
+);
+
+ALTER TABLE feature_flags
+ADD CONSTRAINT pk_feature_flags PRIMARY KEY (org_id, feature);
+
+ALTER TABLE feature_flags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE feature_flags FORCE ROW LEVEL SECURITY;
+CREATE POLICY feature_flags_org_isolation ON feature_flags
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
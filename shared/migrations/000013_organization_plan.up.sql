@@ -0,0 +1,3 @@
+-- Plan of the organization, organizations without a plan are on the configured default plan
+ALTER TABLE organizations
+ADD COLUMN plan varchar(50);
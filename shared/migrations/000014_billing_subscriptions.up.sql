@@ -0,0 +1,26 @@
+-- Table billing_subscriptions, links an organization to its Stripe customer and subscription
+CREATE TABLE billing_subscriptions (
+     org_id              uuid not null,
+     customer_id         varchar(255) not null,
+     subscription_id     varchar(255),
+     plan                varchar(50),
+     status              varchar(50),
+     current_period_end  timestamp,
+     last_event_at       timestamp,
+     updated_at          timestamp not null
+);
+
+ALTER TABLE billing_subscriptions
+ADD CONSTRAINT pk_billing_subscriptions PRIMARY KEY (org_id);
+
+ALTER TABLE billing_subscriptions
+ADD CONSTRAINT fk_billing_subscriptions_organizations
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE UNIQUE INDEX idx_billing_subscriptions_customer ON billing_subscriptions (customer_id);
+
+ALTER TABLE billing_subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE billing_subscriptions FORCE ROW LEVEL SECURITY;
+CREATE POLICY billing_subscriptions_org_isolation ON billing_subscriptions
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,5 @@
+-- Lifecycle of the organization, organizations without a status are active
+ALTER TABLE organizations
+ADD COLUMN status varchar(20),
+ADD COLUMN trial_ends_at timestamp,
+ADD COLUMN status_changed_at timestamp;
@@ -0,0 +1,16 @@
+-- Table digest_settings, users without settings receive the weekly digest
+CREATE TABLE digest_settings (
+     org_id          uuid not null,
+     username        varchar(50) not null,
+     opted_out       boolean not null default false,
+     last_sent_week  date
+);
+
+ALTER TABLE digest_settings
+ADD CONSTRAINT pk_digest_settings PRIMARY KEY (org_id, username);
+
+ALTER TABLE digest_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE digest_settings FORCE ROW LEVEL SECURITY;
+CREATE POLICY digest_settings_org_isolation ON digest_settings
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,16 @@
+-- Table manager_digest_settings, organizations without settings send the manager digest to their admins
+CREATE TABLE manager_digest_settings (
+     org_id          uuid not null,
+     enabled         boolean not null default true,
+     recipients      varchar(1000),
+     last_sent_week  date
+);
+
+ALTER TABLE manager_digest_settings
+ADD CONSTRAINT pk_manager_digest_settings PRIMARY KEY (org_id);
+
+ALTER TABLE manager_digest_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE manager_digest_settings FORCE ROW LEVEL SECURITY;
+CREATE POLICY manager_digest_settings_org_isolation ON manager_digest_settings
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
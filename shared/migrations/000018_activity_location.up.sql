@@ -0,0 +1,40 @@
+-- Location of an activity, either a kind of work location or the coordinates captured by a mobile client
+ALTER TABLE activities ADD COLUMN location varchar(20);
+ALTER TABLE activities ADD COLUMN latitude double precision;
+ALTER TABLE activities ADD COLUMN longitude double precision;
+
+CREATE OR REPLACE VIEW activities_agg as
+SELECT
+  activities.activity_id,
+  activities.project_id,
+  activities.org_id,
+  activities.username,
+  activities.start_time,
+  activities.end_time,
+  EXTRACT(day from start_time) as day, 
+  EXTRACT(week from start_time) as week, 
+  EXTRACT(month from start_time) as month, 
+  EXTRACT(quarter from start_time) as quarter, 
+  EXTRACT(year from start_time) as year, 
+  EXTRACT(minute from end_time - start_time) as duration_minutes, 
+  EXTRACT(hour from end_time - start_time) as duration_hours,
+  EXTRACT(hour from end_time - start_time) * 60 + EXTRACT(minute from end_time - start_time) as duration_minutes_total,
+  activities.location
+FROM 
+  activities;
+
+-- Table location_policies, organizations without a policy capture locations optionally
+CREATE TABLE location_policies (
+     org_id             uuid not null,
+     mode               varchar(20) not null,
+     allow_coordinates  boolean not null default false
+);
+
+ALTER TABLE location_policies
+ADD CONSTRAINT pk_location_policies PRIMARY KEY (org_id);
+
+ALTER TABLE location_policies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE location_policies FORCE ROW LEVEL SECURITY;
+CREATE POLICY location_policies_org_isolation ON location_policies
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,35 @@
+-- Table clients, the customers projects are tracked and billed for
+CREATE TABLE clients (
+     client_id          uuid not null,
+     org_id             uuid not null,
+     name               varchar(100) not null,
+     hourly_rate_cents  integer not null default 0,
+     currency           varchar(3) not null default 'EUR',
+     created_at         timestamp not null default now()
+);
+
+ALTER TABLE clients
+ADD CONSTRAINT pk_clients PRIMARY KEY (client_id);
+
+ALTER TABLE clients
+ADD CONSTRAINT fk_clients_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE INDEX clients_idx_org_id
+ON clients (org_id);
+
+ALTER TABLE clients ENABLE ROW LEVEL SECURITY;
+ALTER TABLE clients FORCE ROW LEVEL SECURITY;
+CREATE POLICY clients_org_isolation ON clients
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Projects are optionally assigned to a client
+ALTER TABLE projects ADD COLUMN client_id uuid;
+
+ALTER TABLE projects
+ADD CONSTRAINT fk_projects_clients
+FOREIGN KEY (client_id) REFERENCES clients (client_id) ON DELETE SET NULL;
+
+CREATE INDEX projects_idx_client_id
+ON projects (client_id);
@@ -0,0 +1,39 @@
+-- Status of a project replaces the active flag, archived projects are done
+ALTER TABLE projects ADD COLUMN status varchar(20) not null DEFAULT 'active';
+ALTER TABLE projects ADD COLUMN started_at timestamp;
+ALTER TABLE projects ADD COLUMN completed_at timestamp;
+
+UPDATE projects SET status = 'done' WHERE active = false;
+UPDATE projects SET started_at = created_at;
+
+ALTER TABLE projects DROP COLUMN active;
+
+CREATE INDEX projects_idx_org_id_status
+ON projects (org_id, status);
+
+-- Table project_status_changes, the audit trail of the project lifecycle
+CREATE TABLE project_status_changes (
+     status_change_id  uuid not null,
+     org_id            uuid not null,
+     project_id        uuid not null,
+     from_status       varchar(20),
+     to_status         varchar(20) not null,
+     changed_by        varchar(36) not null,
+     changed_at        timestamp not null default now()
+);
+
+ALTER TABLE project_status_changes
+ADD CONSTRAINT pk_project_status_changes PRIMARY KEY (status_change_id);
+
+ALTER TABLE project_status_changes
+ADD CONSTRAINT fk_project_status_changes_projects
+FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE;
+
+CREATE INDEX project_status_changes_idx_project_id_org_id
+ON project_status_changes (project_id, org_id);
+
+ALTER TABLE project_status_changes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE project_status_changes FORCE ROW LEVEL SECURITY;
+CREATE POLICY project_status_changes_org_isolation ON project_status_changes
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
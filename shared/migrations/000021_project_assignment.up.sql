@@ -0,0 +1,44 @@
+-- Table default_projects, the project of a user for activities arriving without project
+CREATE TABLE default_projects (
+     org_id      uuid not null,
+     username    varchar(36) not null,
+     project_id  uuid not null
+);
+
+ALTER TABLE default_projects
+ADD CONSTRAINT pk_default_projects PRIMARY KEY (org_id, username);
+
+ALTER TABLE default_projects
+ADD CONSTRAINT fk_default_projects_projects
+FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE;
+
+ALTER TABLE default_projects ENABLE ROW LEVEL SECURITY;
+ALTER TABLE default_projects FORCE ROW LEVEL SECURITY;
+CREATE POLICY default_projects_org_isolation ON default_projects
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table project_assignment_rules, assigns activities without project by a keyword of their description
+CREATE TABLE project_assignment_rules (
+     rule_id     uuid not null,
+     org_id      uuid not null,
+     keyword     varchar(100) not null,
+     project_id  uuid not null,
+     created_at  timestamp not null default now()
+);
+
+ALTER TABLE project_assignment_rules
+ADD CONSTRAINT pk_project_assignment_rules PRIMARY KEY (rule_id);
+
+ALTER TABLE project_assignment_rules
+ADD CONSTRAINT fk_project_assignment_rules_projects
+FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE;
+
+CREATE INDEX project_assignment_rules_idx_org_id
+ON project_assignment_rules (org_id);
+
+ALTER TABLE project_assignment_rules ENABLE ROW LEVEL SECURITY;
+ALTER TABLE project_assignment_rules FORCE ROW LEVEL SECURITY;
+CREATE POLICY project_assignment_rules_org_isolation ON project_assignment_rules
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
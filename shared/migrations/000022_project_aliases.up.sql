@@ -0,0 +1,27 @@
+-- Table project_aliases, short codes to use instead of project ids
+CREATE TABLE project_aliases (
+     org_id       uuid not null,
+     alias        varchar(10) not null,
+     project_id   uuid not null,
+     created_at   timestamp not null default now()
+);
+
+ALTER TABLE project_aliases
+ADD CONSTRAINT pk_project_aliases PRIMARY KEY (org_id, alias);
+
+ALTER TABLE project_aliases
+ADD CONSTRAINT fk_project_aliases_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE project_aliases
+ADD CONSTRAINT fk_project_aliases_projects
+FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE;
+
+CREATE INDEX project_aliases_idx_project_id
+ON project_aliases (project_id);
+
+ALTER TABLE project_aliases ENABLE ROW LEVEL SECURITY;
+ALTER TABLE project_aliases FORCE ROW LEVEL SECURITY;
+CREATE POLICY project_aliases_org_isolation ON project_aliases
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,27 @@
+-- Table project_badges, tokens for the public badge of the tracked time of a project
+CREATE TABLE project_badges (
+     project_id   uuid not null,
+     org_id       uuid not null,
+     token        varchar(64) not null,
+     created_at   timestamp not null default now()
+);
+
+ALTER TABLE project_badges
+ADD CONSTRAINT pk_project_badges PRIMARY KEY (project_id);
+
+ALTER TABLE project_badges
+ADD CONSTRAINT fk_project_badges_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE project_badges
+ADD CONSTRAINT fk_project_badges_projects
+FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX project_badges_idx_token
+ON project_badges (token);
+
+ALTER TABLE project_badges ENABLE ROW LEVEL SECURITY;
+ALTER TABLE project_badges FORCE ROW LEVEL SECURITY;
+CREATE POLICY project_badges_org_isolation ON project_badges
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
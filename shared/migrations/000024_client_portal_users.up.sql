@@ -0,0 +1,31 @@
+-- Table client_portal_users, users of a client with read-only access to the client portal
+CREATE TABLE client_portal_users (
+     user_id      uuid not null,
+     org_id       uuid not null,
+     client_id    uuid not null,
+     created_at   timestamp not null default now()
+);
+
+ALTER TABLE client_portal_users
+ADD CONSTRAINT pk_client_portal_users PRIMARY KEY (user_id);
+
+ALTER TABLE client_portal_users
+ADD CONSTRAINT fk_client_portal_users_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE client_portal_users
+ADD CONSTRAINT fk_client_portal_users_users
+FOREIGN KEY (user_id) REFERENCES users (user_id);
+
+ALTER TABLE client_portal_users
+ADD CONSTRAINT fk_client_portal_users_clients
+FOREIGN KEY (client_id) REFERENCES clients (client_id) ON DELETE CASCADE;
+
+CREATE INDEX client_portal_users_idx_client_id
+ON client_portal_users (client_id);
+
+ALTER TABLE client_portal_users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE client_portal_users FORCE ROW LEVEL SECURITY;
+CREATE POLICY client_portal_users_org_isolation ON client_portal_users
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,15 @@
+-- Table compliance_policies, organizations without a policy evaluate the time of single users
+CREATE TABLE compliance_policies (
+     org_id              uuid not null,
+     works_council_mode  boolean not null default false,
+     min_group_size      integer not null default 5
+);
+
+ALTER TABLE compliance_policies
+ADD CONSTRAINT pk_compliance_policies PRIMARY KEY (org_id);
+
+ALTER TABLE compliance_policies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE compliance_policies FORCE ROW LEVEL SECURITY;
+CREATE POLICY compliance_policies_org_isolation ON compliance_policies
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,14 @@
+-- Table working_time_checks, the last day checked for violations of the working time directive per organization
+CREATE TABLE working_time_checks (
+     org_id            uuid not null,
+     last_checked_day  date not null
+);
+
+ALTER TABLE working_time_checks
+ADD CONSTRAINT pk_working_time_checks PRIMARY KEY (org_id);
+
+ALTER TABLE working_time_checks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE working_time_checks FORCE ROW LEVEL SECURITY;
+CREATE POLICY working_time_checks_org_isolation ON working_time_checks
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,15 @@
+-- Table break_policies, organizations without a policy do not deduct breaks
+CREATE TABLE break_policies (
+     org_id          uuid not null,
+     auto_deduction  boolean not null default false,
+     rules           varchar(200) not null default ''
+);
+
+ALTER TABLE break_policies
+ADD CONSTRAINT pk_break_policies PRIMARY KEY (org_id);
+
+ALTER TABLE break_policies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE break_policies FORCE ROW LEVEL SECURITY;
+CREATE POLICY break_policies_org_isolation ON break_policies
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,56 @@
+-- Table attendance_stamps, the clock-in and clock-out of users separate from the time tracked for projects
+CREATE TABLE attendance_stamps (
+     stamp_id    uuid not null,
+     org_id      uuid not null,
+     username    varchar(255) not null,
+     clock_in    timestamp not null,
+     clock_out   timestamp,
+     created_at  timestamp not null default now()
+);
+
+ALTER TABLE attendance_stamps
+ADD CONSTRAINT pk_attendance_stamps PRIMARY KEY (stamp_id);
+
+ALTER TABLE attendance_stamps
+ADD CONSTRAINT fk_attendance_stamps_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE INDEX attendance_stamps_idx_org_id_username_clock_in
+ON attendance_stamps (org_id, username, clock_in);
+
+ALTER TABLE attendance_stamps ENABLE ROW LEVEL SECURITY;
+ALTER TABLE attendance_stamps FORCE ROW LEVEL SECURITY;
+CREATE POLICY attendance_stamps_org_isolation ON attendance_stamps
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table attendance_corrections, corrections of stamps requested by users and decided by admins
+CREATE TABLE attendance_corrections (
+     correction_id  uuid not null,
+     org_id         uuid not null,
+     stamp_id       uuid,
+     username       varchar(255) not null,
+     clock_in       timestamp not null,
+     clock_out      timestamp not null,
+     reason         varchar(500) not null,
+     status         varchar(20) not null default 'pending',
+     requested_at   timestamp not null default now(),
+     decided_by     varchar(255),
+     decided_at     timestamp
+);
+
+ALTER TABLE attendance_corrections
+ADD CONSTRAINT pk_attendance_corrections PRIMARY KEY (correction_id);
+
+ALTER TABLE attendance_corrections
+ADD CONSTRAINT fk_attendance_corrections_stamps
+FOREIGN KEY (stamp_id) REFERENCES attendance_stamps (stamp_id) ON DELETE CASCADE;
+
+CREATE INDEX attendance_corrections_idx_org_id_status
+ON attendance_corrections (org_id, status);
+
+ALTER TABLE attendance_corrections ENABLE ROW LEVEL SECURITY;
+ALTER TABLE attendance_corrections FORCE ROW LEVEL SECURITY;
+CREATE POLICY attendance_corrections_org_isolation ON attendance_corrections
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,49 @@
+-- Table kiosk_devices, shared devices like tablets clocking users in and out by their device token
+CREATE TABLE kiosk_devices (
+     device_id     uuid not null,
+     org_id        uuid not null,
+     name          varchar(100) not null,
+     token_hash    varchar(64) not null,
+     created_at    timestamp not null default now(),
+     last_used_at  timestamp
+);
+
+ALTER TABLE kiosk_devices
+ADD CONSTRAINT pk_kiosk_devices PRIMARY KEY (device_id);
+
+ALTER TABLE kiosk_devices
+ADD CONSTRAINT fk_kiosk_devices_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE UNIQUE INDEX kiosk_devices_idx_token_hash
+ON kiosk_devices (token_hash);
+
+ALTER TABLE kiosk_devices ENABLE ROW LEVEL SECURITY;
+ALTER TABLE kiosk_devices FORCE ROW LEVEL SECURITY;
+CREATE POLICY kiosk_devices_org_isolation ON kiosk_devices
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table kiosk_credentials, the PIN and badge code of users to identify at kiosk devices
+CREATE TABLE kiosk_credentials (
+     org_id      uuid not null,
+     username    varchar(255) not null,
+     pin_hash    varchar(100),
+     badge_code  varchar(100)
+);
+
+ALTER TABLE kiosk_credentials
+ADD CONSTRAINT pk_kiosk_credentials PRIMARY KEY (org_id, username);
+
+ALTER TABLE kiosk_credentials
+ADD CONSTRAINT fk_kiosk_credentials_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE UNIQUE INDEX kiosk_credentials_idx_org_id_badge_code
+ON kiosk_credentials (org_id, badge_code);
+
+ALTER TABLE kiosk_credentials ENABLE ROW LEVEL SECURITY;
+ALTER TABLE kiosk_credentials FORCE ROW LEVEL SECURITY;
+CREATE POLICY kiosk_credentials_org_isolation ON kiosk_credentials
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,55 @@
+-- Table scan_tags, QR codes and NFC tags of a project to start and stop tracking by scanning them
+CREATE TABLE scan_tags (
+     tag_id      uuid not null,
+     org_id      uuid not null,
+     project_id  uuid not null,
+     name        varchar(100) not null,
+     code        varchar(64) not null,
+     location    varchar(20),
+     created_at  timestamp not null default now()
+);
+
+ALTER TABLE scan_tags
+ADD CONSTRAINT pk_scan_tags PRIMARY KEY (tag_id);
+
+ALTER TABLE scan_tags
+ADD CONSTRAINT fk_scan_tags_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE scan_tags
+ADD CONSTRAINT fk_scan_tags_projects
+FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX scan_tags_idx_org_id_code
+ON scan_tags (org_id, code);
+
+ALTER TABLE scan_tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE scan_tags FORCE ROW LEVEL SECURITY;
+CREATE POLICY scan_tags_org_isolation ON scan_tags
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table scan_sessions, the tracking of a user started by scanning a tag until the next scan
+CREATE TABLE scan_sessions (
+     org_id      uuid not null,
+     username    varchar(255) not null,
+     tag_id      uuid not null,
+     started_at  timestamp not null
+);
+
+ALTER TABLE scan_sessions
+ADD CONSTRAINT pk_scan_sessions PRIMARY KEY (org_id, username);
+
+ALTER TABLE scan_sessions
+ADD CONSTRAINT fk_scan_sessions_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE scan_sessions
+ADD CONSTRAINT fk_scan_sessions_tags
+FOREIGN KEY (tag_id) REFERENCES scan_tags (tag_id) ON DELETE CASCADE;
+
+ALTER TABLE scan_sessions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE scan_sessions FORCE ROW LEVEL SECURITY;
+CREATE POLICY scan_sessions_org_isolation ON scan_sessions
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
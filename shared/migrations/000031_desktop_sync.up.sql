@@ -0,0 +1,44 @@
+-- Table sync_timers, the running timer of a user shared by the desktop clients of the user
+CREATE TABLE sync_timers (
+     org_id       uuid not null,
+     username     varchar(255) not null,
+     project_id   uuid,
+     description  varchar(500),
+     started_at   timestamp,
+     version      integer not null default 0
+);
+
+ALTER TABLE sync_timers
+ADD CONSTRAINT pk_sync_timers PRIMARY KEY (org_id, username);
+
+ALTER TABLE sync_timers
+ADD CONSTRAINT fk_sync_timers_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE sync_timers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sync_timers FORCE ROW LEVEL SECURITY;
+CREATE POLICY sync_timers_org_isolation ON sync_timers
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table sync_entries, the entries tracked offline by desktop clients already synced as activities
+CREATE TABLE sync_entries (
+     org_id       uuid not null,
+     username     varchar(255) not null,
+     entry_id     uuid not null,
+     activity_id  uuid not null,
+     synced_at    timestamp not null default now()
+);
+
+ALTER TABLE sync_entries
+ADD CONSTRAINT pk_sync_entries PRIMARY KEY (org_id, username, entry_id);
+
+ALTER TABLE sync_entries
+ADD CONSTRAINT fk_sync_entries_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE sync_entries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sync_entries FORCE ROW LEVEL SECURITY;
+CREATE POLICY sync_entries_org_isolation ON sync_entries
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
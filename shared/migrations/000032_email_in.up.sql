@@ -0,0 +1,23 @@
+-- Table email_in_addresses, the personal addresses of users to send time entries to by email
+CREATE TABLE email_in_addresses (
+     org_id      uuid not null,
+     username    varchar(255) not null,
+     token       varchar(64) not null,
+     created_at  timestamp not null default now()
+);
+
+ALTER TABLE email_in_addresses
+ADD CONSTRAINT pk_email_in_addresses PRIMARY KEY (org_id, username);
+
+ALTER TABLE email_in_addresses
+ADD CONSTRAINT fk_email_in_addresses_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE UNIQUE INDEX email_in_addresses_idx_token
+ON email_in_addresses (token);
+
+ALTER TABLE email_in_addresses ENABLE ROW LEVEL SECURITY;
+ALTER TABLE email_in_addresses FORCE ROW LEVEL SECURITY;
+CREATE POLICY email_in_addresses_org_isolation ON email_in_addresses
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
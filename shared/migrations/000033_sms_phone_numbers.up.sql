@@ -0,0 +1,23 @@
+-- Table sms_phone_numbers, the phone numbers of users logging time by SMS or WhatsApp
+CREATE TABLE sms_phone_numbers (
+     org_id        uuid not null,
+     username      varchar(255) not null,
+     phone_number  varchar(20) not null,
+     created_at    timestamp not null default now()
+);
+
+ALTER TABLE sms_phone_numbers
+ADD CONSTRAINT pk_sms_phone_numbers PRIMARY KEY (org_id, username);
+
+ALTER TABLE sms_phone_numbers
+ADD CONSTRAINT fk_sms_phone_numbers_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE UNIQUE INDEX sms_phone_numbers_idx_phone_number
+ON sms_phone_numbers (phone_number);
+
+ALTER TABLE sms_phone_numbers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sms_phone_numbers FORCE ROW LEVEL SECURITY;
+CREATE POLICY sms_phone_numbers_org_isolation ON sms_phone_numbers
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,29 @@
+-- Table api_usage, the api requests of an organization per day, user, token and endpoint
+CREATE TABLE api_usage (
+     org_id           uuid not null,
+     day              date not null,
+     username         varchar(255) not null,
+     token_id         varchar(16) not null,
+     method           varchar(10) not null,
+     endpoint         varchar(255) not null,
+     client           varchar(255) not null default '',
+     requests         integer not null default 0,
+     errors           integer not null default 0,
+     last_request_at  timestamp not null
+);
+
+ALTER TABLE api_usage
+ADD CONSTRAINT pk_api_usage PRIMARY KEY (org_id, day, username, token_id, method, endpoint);
+
+ALTER TABLE api_usage
+ADD CONSTRAINT fk_api_usage_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE INDEX api_usage_idx_day
+ON api_usage (day);
+
+ALTER TABLE api_usage ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_usage FORCE ROW LEVEL SECURITY;
+CREATE POLICY api_usage_org_isolation ON api_usage
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
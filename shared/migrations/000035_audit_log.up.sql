@@ -0,0 +1,28 @@
+-- Table audit_log, the actions of instance admins acting as users of an organization for support
+CREATE TABLE audit_log (
+     audit_id         uuid not null,
+     org_id           uuid not null,
+     username         varchar(255) not null,
+     impersonated_by  varchar(255),
+     action           varchar(50) not null,
+     method           varchar(10) not null default '',
+     path             varchar(1000) not null default '',
+     status           integer not null default 0,
+     created_at       timestamp not null default now()
+);
+
+ALTER TABLE audit_log
+ADD CONSTRAINT pk_audit_log PRIMARY KEY (audit_id);
+
+ALTER TABLE audit_log
+ADD CONSTRAINT fk_audit_log_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE INDEX audit_log_idx_org_created_at
+ON audit_log (org_id, created_at);
+
+ALTER TABLE audit_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_log FORCE ROW LEVEL SECURITY;
+CREATE POLICY audit_log_org_isolation ON audit_log
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,71 @@
+-- Table screenshot_policies, organizations without a policy take no screenshots
+CREATE TABLE screenshot_policies (
+     org_id            uuid not null,
+     enabled           boolean not null default false,
+     interval_minutes  integer not null,
+     min_blur          varchar(20) not null,
+     retention_days    integer not null
+);
+
+ALTER TABLE screenshot_policies
+ADD CONSTRAINT pk_screenshot_policies PRIMARY KEY (org_id);
+
+ALTER TABLE screenshot_policies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE screenshot_policies FORCE ROW LEVEL SECURITY;
+CREATE POLICY screenshot_policies_org_isolation ON screenshot_policies
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table screenshot_consents, the users who agreed to screenshots while tracking
+CREATE TABLE screenshot_consents (
+     org_id        uuid not null,
+     username      varchar(255) not null,
+     consented_at  timestamp not null default now()
+);
+
+ALTER TABLE screenshot_consents
+ADD CONSTRAINT pk_screenshot_consents PRIMARY KEY (org_id, username);
+
+ALTER TABLE screenshot_consents
+ADD CONSTRAINT fk_screenshot_consents_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE screenshot_consents ENABLE ROW LEVEL SECURITY;
+ALTER TABLE screenshot_consents FORCE ROW LEVEL SECURITY;
+CREATE POLICY screenshot_consents_org_isolation ON screenshot_consents
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table activity_screenshots, the screenshots of running activities, the images are kept in the storage
+-- and outlive deleted activities until the retention of the organization expires
+CREATE TABLE activity_screenshots (
+     screenshot_id  uuid not null,
+     org_id         uuid not null,
+     activity_id    uuid not null,
+     username       varchar(255) not null,
+     blur           varchar(20) not null,
+     content_type   varchar(100) not null,
+     storage_key    varchar(500) not null,
+     size           bigint not null,
+     taken_at       timestamp not null,
+     created_at     timestamp not null default now()
+);
+
+ALTER TABLE activity_screenshots
+ADD CONSTRAINT pk_activity_screenshots PRIMARY KEY (screenshot_id);
+
+ALTER TABLE activity_screenshots
+ADD CONSTRAINT fk_activity_screenshots_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE INDEX activity_screenshots_idx_activity
+ON activity_screenshots (org_id, activity_id, taken_at);
+
+CREATE INDEX activity_screenshots_idx_created_at
+ON activity_screenshots (created_at);
+
+ALTER TABLE activity_screenshots ENABLE ROW LEVEL SECURITY;
+ALTER TABLE activity_screenshots FORCE ROW LEVEL SECURITY;
+CREATE POLICY activity_screenshots_org_isolation ON activity_screenshots
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
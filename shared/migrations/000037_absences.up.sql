@@ -0,0 +1,73 @@
+-- Table absences, the days users of an organization are out like vacations or sick leave
+CREATE TABLE absences (
+     absence_id   uuid not null,
+     org_id       uuid not null,
+     username     varchar(255) not null,
+     start_day    date not null,
+     end_day      date not null,
+     kind         varchar(20) not null,
+     created_at   timestamp not null default now()
+);
+
+ALTER TABLE absences
+ADD CONSTRAINT pk_absences PRIMARY KEY (absence_id);
+
+ALTER TABLE absences
+ADD CONSTRAINT fk_absences_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE INDEX absences_idx_days
+ON absences (org_id, end_day, start_day);
+
+ALTER TABLE absences ENABLE ROW LEVEL SECURITY;
+ALTER TABLE absences FORCE ROW LEVEL SECURITY;
+CREATE POLICY absences_org_isolation ON absences
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table holidays, the public holidays of an organization
+CREATE TABLE holidays (
+     holiday_id   uuid not null,
+     org_id       uuid not null,
+     day          date not null,
+     name         varchar(100) not null
+);
+
+ALTER TABLE holidays
+ADD CONSTRAINT pk_holidays PRIMARY KEY (holiday_id);
+
+ALTER TABLE holidays
+ADD CONSTRAINT fk_holidays_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE UNIQUE INDEX holidays_idx_day
+ON holidays (org_id, day);
+
+ALTER TABLE holidays ENABLE ROW LEVEL SECURITY;
+ALTER TABLE holidays FORCE ROW LEVEL SECURITY;
+CREATE POLICY holidays_org_isolation ON holidays
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table absence_calendars, tokens for the calendar feed of the absences and holidays of an organization
+CREATE TABLE absence_calendars (
+     org_id       uuid not null,
+     token        varchar(64) not null,
+     created_at   timestamp not null default now()
+);
+
+ALTER TABLE absence_calendars
+ADD CONSTRAINT pk_absence_calendars PRIMARY KEY (org_id);
+
+ALTER TABLE absence_calendars
+ADD CONSTRAINT fk_absence_calendars_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE UNIQUE INDEX absence_calendars_idx_token
+ON absence_calendars (token);
+
+ALTER TABLE absence_calendars ENABLE ROW LEVEL SECURITY;
+ALTER TABLE absence_calendars FORCE ROW LEVEL SECURITY;
+CREATE POLICY absence_calendars_org_isolation ON absence_calendars
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,31 @@
+-- Table allocations, the planned time of users on projects per week
+CREATE TABLE allocations (
+     allocation_id     uuid not null,
+     org_id            uuid not null,
+     username          varchar(255) not null,
+     project_id        uuid not null,
+     start_day         date not null,
+     end_day           date not null,
+     minutes_per_week  integer not null,
+     created_at        timestamp not null default now()
+);
+
+ALTER TABLE allocations
+ADD CONSTRAINT pk_allocations PRIMARY KEY (allocation_id);
+
+ALTER TABLE allocations
+ADD CONSTRAINT fk_allocations_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE allocations
+ADD CONSTRAINT fk_allocations_projects
+FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE;
+
+CREATE INDEX allocations_idx_days
+ON allocations (org_id, end_day, start_day);
+
+ALTER TABLE allocations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE allocations FORCE ROW LEVEL SECURITY;
+CREATE POLICY allocations_org_isolation ON allocations
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
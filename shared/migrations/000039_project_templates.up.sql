@@ -0,0 +1,27 @@
+-- Table project_templates, the blueprints for new projects of an organization
+CREATE TABLE project_templates (
+     template_id       uuid not null,
+     org_id            uuid not null,
+     name              varchar(100) not null,
+     description       varchar(500) not null default '',
+     billable          boolean not null default false,
+     budget_minutes    integer not null default 0,
+     tasks             varchar(100)[] not null default '{}',
+     created_at        timestamp not null default now()
+);
+
+ALTER TABLE project_templates
+ADD CONSTRAINT pk_project_templates PRIMARY KEY (template_id);
+
+ALTER TABLE project_templates
+ADD CONSTRAINT fk_project_templates_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE INDEX project_templates_idx_org
+ON project_templates (org_id, name);
+
+ALTER TABLE project_templates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE project_templates FORCE ROW LEVEL SECURITY;
+CREATE POLICY project_templates_org_isolation ON project_templates
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
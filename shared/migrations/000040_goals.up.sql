@@ -0,0 +1,49 @@
+-- Table goals, the goals or OKRs of an organization activities contribute to
+CREATE TABLE goals (
+     goal_id           uuid not null,
+     org_id            uuid not null,
+     title             varchar(100) not null,
+     description       varchar(500) not null default '',
+     archived          boolean not null default false,
+     created_at        timestamp not null default now()
+);
+
+ALTER TABLE goals
+ADD CONSTRAINT pk_goals PRIMARY KEY (goal_id);
+
+ALTER TABLE goals
+ADD CONSTRAINT fk_goals_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE goals ENABLE ROW LEVEL SECURITY;
+ALTER TABLE goals FORCE ROW LEVEL SECURITY;
+CREATE POLICY goals_org_isolation ON goals
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table activity_goals, the goals an activity is linked to
+CREATE TABLE activity_goals (
+     activity_id       uuid not null,
+     goal_id           uuid not null,
+     org_id            uuid not null
+);
+
+ALTER TABLE activity_goals
+ADD CONSTRAINT pk_activity_goals PRIMARY KEY (activity_id, goal_id);
+
+ALTER TABLE activity_goals
+ADD CONSTRAINT fk_activity_goals_activities
+FOREIGN KEY (activity_id) REFERENCES activities (activity_id) ON DELETE CASCADE;
+
+ALTER TABLE activity_goals
+ADD CONSTRAINT fk_activity_goals_goals
+FOREIGN KEY (goal_id) REFERENCES goals (goal_id) ON DELETE CASCADE;
+
+CREATE INDEX activity_goals_idx_goal
+ON activity_goals (goal_id);
+
+ALTER TABLE activity_goals ENABLE ROW LEVEL SECURITY;
+ALTER TABLE activity_goals FORCE ROW LEVEL SECURITY;
+CREATE POLICY activity_goals_org_isolation ON activity_goals
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
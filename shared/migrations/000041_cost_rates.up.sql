@@ -0,0 +1,19 @@
+-- Table cost_rates, the internal hourly cost of users, in the currency of the rates of the clients
+CREATE TABLE cost_rates (
+     org_id             uuid not null,
+     username           varchar(255) not null,
+     hourly_cost_cents  integer not null default 0
+);
+
+ALTER TABLE cost_rates
+ADD CONSTRAINT pk_cost_rates PRIMARY KEY (org_id, username);
+
+ALTER TABLE cost_rates
+ADD CONSTRAINT fk_cost_rates_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE cost_rates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE cost_rates FORCE ROW LEVEL SECURITY;
+CREATE POLICY cost_rates_org_isolation ON cost_rates
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,46 @@
+-- Table fixed_prices, projects with a fixed price whose revenue is recognized by milestones or budget
+CREATE TABLE fixed_prices (
+     project_id         uuid not null,
+     org_id             uuid not null,
+     price_cents        integer not null
+);
+
+ALTER TABLE fixed_prices
+ADD CONSTRAINT pk_fixed_prices PRIMARY KEY (project_id);
+
+ALTER TABLE fixed_prices
+ADD CONSTRAINT fk_fixed_prices_projects
+FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE;
+
+ALTER TABLE fixed_prices ENABLE ROW LEVEL SECURITY;
+ALTER TABLE fixed_prices FORCE ROW LEVEL SECURITY;
+CREATE POLICY fixed_prices_org_isolation ON fixed_prices
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table milestones, the milestones of fixed-price projects
+CREATE TABLE milestones (
+     milestone_id       uuid not null,
+     org_id             uuid not null,
+     project_id         uuid not null,
+     title              varchar(100) not null,
+     due_day            date not null,
+     amount_cents       integer not null,
+     completed_at       timestamp
+);
+
+ALTER TABLE milestones
+ADD CONSTRAINT pk_milestones PRIMARY KEY (milestone_id);
+
+ALTER TABLE milestones
+ADD CONSTRAINT fk_milestones_fixed_prices
+FOREIGN KEY (project_id) REFERENCES fixed_prices (project_id) ON DELETE CASCADE;
+
+CREATE INDEX milestones_idx_project
+ON milestones (org_id, project_id, due_day);
+
+ALTER TABLE milestones ENABLE ROW LEVEL SECURITY;
+ALTER TABLE milestones FORCE ROW LEVEL SECURITY;
+CREATE POLICY milestones_org_isolation ON milestones
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,46 @@
+-- Table retainers, the hours per month a client buys in advance, optionally rolling over unused hours
+CREATE TABLE retainers (
+     retainer_id        uuid not null,
+     org_id             uuid not null,
+     client_id          uuid not null,
+     minutes_per_month  integer not null,
+     rollover           boolean not null default false,
+     start_month        date not null,
+     end_month          date,
+     alert_percent      integer not null default 80
+);
+
+ALTER TABLE retainers
+ADD CONSTRAINT pk_retainers PRIMARY KEY (retainer_id);
+
+ALTER TABLE retainers
+ADD CONSTRAINT fk_retainers_clients
+FOREIGN KEY (client_id) REFERENCES clients (client_id) ON DELETE CASCADE;
+
+CREATE INDEX idx_retainers_org_id ON retainers (org_id);
+
+ALTER TABLE retainers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE retainers FORCE ROW LEVEL SECURITY;
+CREATE POLICY retainers_org_isolation ON retainers
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table retainer_alerts, the months admins were alerted about a nearly exhausted retainer
+CREATE TABLE retainer_alerts (
+     retainer_id        uuid not null,
+     org_id             uuid not null,
+     alert_month        date not null
+);
+
+ALTER TABLE retainer_alerts
+ADD CONSTRAINT pk_retainer_alerts PRIMARY KEY (retainer_id, alert_month);
+
+ALTER TABLE retainer_alerts
+ADD CONSTRAINT fk_retainer_alerts_retainers
+FOREIGN KEY (retainer_id) REFERENCES retainers (retainer_id) ON DELETE CASCADE;
+
+ALTER TABLE retainer_alerts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE retainer_alerts FORCE ROW LEVEL SECURITY;
+CREATE POLICY retainer_alerts_org_isolation ON retainer_alerts
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,42 @@
+-- Table accounting_settings, the accounts the billable time of an organization is exported to accounting systems with
+CREATE TABLE accounting_settings (
+     org_id               uuid not null,
+     revenue_account      varchar(50) not null,
+     receivable_account   varchar(50) not null,
+     tax_code             varchar(20) not null default '',
+     datev_consultant     integer not null default 0,
+     datev_client         integer not null default 0
+);
+
+ALTER TABLE accounting_settings
+ADD CONSTRAINT pk_accounting_settings PRIMARY KEY (org_id);
+
+ALTER TABLE accounting_settings
+ADD CONSTRAINT fk_accounting_settings_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE accounting_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE accounting_settings FORCE ROW LEVEL SECURITY;
+CREATE POLICY accounting_settings_org_isolation ON accounting_settings
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table client_accounts, the customer accounts of clients in the accounting system
+CREATE TABLE client_accounts (
+     client_id          uuid not null,
+     org_id             uuid not null,
+     account            varchar(50) not null
+);
+
+ALTER TABLE client_accounts
+ADD CONSTRAINT pk_client_accounts PRIMARY KEY (client_id);
+
+ALTER TABLE client_accounts
+ADD CONSTRAINT fk_client_accounts_clients
+FOREIGN KEY (client_id) REFERENCES clients (client_id) ON DELETE CASCADE;
+
+ALTER TABLE client_accounts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE client_accounts FORCE ROW LEVEL SECURITY;
+CREATE POLICY client_accounts_org_isolation ON client_accounts
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,52 @@
+-- Table e_invoice_settings, the seller details of an organization on electronic invoices
+CREATE TABLE e_invoice_settings (
+     org_id             uuid not null,
+     seller_name        varchar(100) not null,
+     street             varchar(100) not null,
+     postcode           varchar(20) not null,
+     city               varchar(100) not null,
+     country_code       varchar(2) not null,
+     vat_id             varchar(30) not null,
+     contact_name       varchar(100) not null,
+     contact_phone      varchar(50) not null,
+     contact_email      varchar(255) not null,
+     iban               varchar(34) not null,
+     vat_percent        integer not null default 19
+);
+
+ALTER TABLE e_invoice_settings
+ADD CONSTRAINT pk_e_invoice_settings PRIMARY KEY (org_id);
+
+ALTER TABLE e_invoice_settings
+ADD CONSTRAINT fk_e_invoice_settings_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE e_invoice_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE e_invoice_settings FORCE ROW LEVEL SECURITY;
+CREATE POLICY e_invoice_settings_org_isolation ON e_invoice_settings
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table client_e_invoice_details, the buyer details of clients on electronic invoices
+CREATE TABLE client_e_invoice_details (
+     client_id          uuid not null,
+     org_id             uuid not null,
+     buyer_reference    varchar(100) not null,
+     street             varchar(100) not null,
+     postcode           varchar(20) not null,
+     city               varchar(100) not null,
+     country_code       varchar(2) not null
+);
+
+ALTER TABLE client_e_invoice_details
+ADD CONSTRAINT pk_client_e_invoice_details PRIMARY KEY (client_id);
+
+ALTER TABLE client_e_invoice_details
+ADD CONSTRAINT fk_client_e_invoice_details_clients
+FOREIGN KEY (client_id) REFERENCES clients (client_id) ON DELETE CASCADE;
+
+ALTER TABLE client_e_invoice_details ENABLE ROW LEVEL SECURITY;
+ALTER TABLE client_e_invoice_details FORCE ROW LEVEL SECURITY;
+CREATE POLICY client_e_invoice_details_org_isolation ON client_e_invoice_details
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
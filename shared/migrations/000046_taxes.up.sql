@@ -0,0 +1,50 @@
+-- Table tax_settings, the default tax rate of an organization in basis points (1900 is 19 %)
+CREATE TABLE tax_settings (
+     org_id              uuid not null,
+     rate_basis_points   integer not null default 0
+);
+
+ALTER TABLE tax_settings
+ADD CONSTRAINT pk_tax_settings PRIMARY KEY (org_id);
+
+ALTER TABLE tax_settings
+ADD CONSTRAINT fk_tax_settings_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE tax_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tax_settings FORCE ROW LEVEL SECURITY;
+CREATE POLICY tax_settings_org_isolation ON tax_settings
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table client_tax_rates, the tax rate of clients deviating from the default rate of the organization
+CREATE TABLE client_tax_rates (
+     client_id           uuid not null,
+     org_id              uuid not null,
+     rate_basis_points   integer not null,
+     reverse_charge      boolean not null default false,
+     vat_id              varchar(30) not null default ''
+);
+
+ALTER TABLE client_tax_rates
+ADD CONSTRAINT pk_client_tax_rates PRIMARY KEY (client_id);
+
+ALTER TABLE client_tax_rates
+ADD CONSTRAINT fk_client_tax_rates_clients
+FOREIGN KEY (client_id) REFERENCES clients (client_id) ON DELETE CASCADE;
+
+ALTER TABLE client_tax_rates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE client_tax_rates FORCE ROW LEVEL SECURITY;
+CREATE POLICY client_tax_rates_org_isolation ON client_tax_rates
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- The VAT rate of electronic invoices becomes the default tax rate of the organization
+INSERT INTO tax_settings (org_id, rate_basis_points)
+SELECT org_id, vat_percent * 100 FROM e_invoice_settings;
+
+ALTER TABLE e_invoice_settings DROP COLUMN vat_percent;
+
+-- Accounts and codes for booking taxes
+ALTER TABLE accounting_settings ADD COLUMN tax_account varchar(50) not null default '1776';
+ALTER TABLE accounting_settings ADD COLUMN reverse_charge_tax_code varchar(20) not null default '';
@@ -0,0 +1,60 @@
+-- Table invoices, the monthly statements of clients issued as invoices with their payment status
+CREATE TABLE invoices (
+     invoice_id          uuid not null,
+     org_id              uuid not null,
+     client_id           uuid not null,
+     client_name         varchar(100) not null,
+     invoice_number      varchar(50) not null,
+     invoice_day         date not null,
+     due_day             date not null,
+     currency            varchar(3) not null,
+     net_amount_cents    integer not null,
+     tax_amount_cents    integer not null,
+     recipient_email     varchar(255) not null,
+     status              varchar(20) not null default 'draft',
+     sent_at             timestamp,
+     last_reminder_at    timestamp,
+     created_at          timestamp not null default now()
+);
+
+ALTER TABLE invoices
+ADD CONSTRAINT pk_invoices PRIMARY KEY (invoice_id);
+
+ALTER TABLE invoices
+ADD CONSTRAINT fk_invoices_clients
+FOREIGN KEY (client_id) REFERENCES clients (client_id);
+
+ALTER TABLE invoices
+ADD CONSTRAINT uq_invoices_number UNIQUE (org_id, invoice_number);
+
+CREATE INDEX idx_invoices_status ON invoices (status, due_day);
+
+ALTER TABLE invoices ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invoices FORCE ROW LEVEL SECURITY;
+CREATE POLICY invoices_org_isolation ON invoices
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table invoice_payments, the full or partial payments received for an invoice
+CREATE TABLE invoice_payments (
+     payment_id          uuid not null,
+     invoice_id          uuid not null,
+     org_id              uuid not null,
+     amount_cents        integer not null,
+     paid_on             date not null,
+     note                varchar(255) not null default '',
+     created_at          timestamp not null default now()
+);
+
+ALTER TABLE invoice_payments
+ADD CONSTRAINT pk_invoice_payments PRIMARY KEY (payment_id);
+
+ALTER TABLE invoice_payments
+ADD CONSTRAINT fk_invoice_payments_invoices
+FOREIGN KEY (invoice_id) REFERENCES invoices (invoice_id) ON DELETE CASCADE;
+
+ALTER TABLE invoice_payments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invoice_payments FORCE ROW LEVEL SECURITY;
+CREATE POLICY invoice_payments_org_isolation ON invoice_payments
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
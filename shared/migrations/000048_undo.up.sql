@@ -0,0 +1,32 @@
+-- Table deleted_activities, the trash of deleted activities which can be restored with the undo token until it expires
+CREATE TABLE deleted_activities (
+     undo_token     uuid not null,
+     org_id         uuid not null,
+     activity_id    uuid not null,
+     description    varchar(4000),
+     username       varchar(36) not null,
+     start_time     timestamp,
+     end_time       timestamp,
+     project_id     uuid not null,
+     location       varchar(20),
+     latitude       double precision,
+     longitude      double precision,
+     goal_ids       varchar(4000),
+     deleted_by     varchar(36) not null,
+     expires_at     timestamp not null
+);
+
+ALTER TABLE deleted_activities
+ADD CONSTRAINT pk_deleted_activities PRIMARY KEY (undo_token);
+
+ALTER TABLE deleted_activities
+ADD CONSTRAINT fk_deleted_activities_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE INDEX idx_deleted_activities_expires_at ON deleted_activities (expires_at);
+
+ALTER TABLE deleted_activities ENABLE ROW LEVEL SECURITY;
+ALTER TABLE deleted_activities FORCE ROW LEVEL SECURITY;
+CREATE POLICY deleted_activities_org_isolation ON deleted_activities
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,29 @@
+-- Entries of the audit log about a change of an entity like an activity or project
+ALTER TABLE audit_log ADD COLUMN entity_type varchar(50);
+ALTER TABLE audit_log ADD COLUMN entity_id uuid;
+
+CREATE INDEX audit_log_idx_org_entity
+ON audit_log (org_id, entity_type, entity_id, created_at);
+
+-- Table audit_changes, the changed fields of an entity with their old and new values
+CREATE TABLE audit_changes (
+     audit_id      uuid not null,
+     org_id        uuid not null,
+     position      integer not null,
+     field         varchar(50) not null,
+     old_value     varchar(4000) not null default '',
+     new_value     varchar(4000) not null default ''
+);
+
+ALTER TABLE audit_changes
+ADD CONSTRAINT pk_audit_changes PRIMARY KEY (audit_id, position);
+
+ALTER TABLE audit_changes
+ADD CONSTRAINT fk_audit_changes_audit_log
+FOREIGN KEY (audit_id) REFERENCES audit_log (audit_id) ON DELETE CASCADE;
+
+ALTER TABLE audit_changes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_changes FORCE ROW LEVEL SECURITY;
+CREATE POLICY audit_changes_org_isolation ON audit_changes
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,2 @@
+-- Password hashes of Argon2id with larger parameters are longer than those of bcrypt
+ALTER TABLE users ALTER COLUMN password TYPE varchar(255);
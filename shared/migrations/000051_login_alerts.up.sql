@@ -0,0 +1,64 @@
+-- Table login_devices, the devices users logged in from to notify them about logins from new devices
+CREATE TABLE login_devices (
+     org_id          uuid not null,
+     username        varchar(50) not null,
+     fingerprint     varchar(64) not null,
+     description     varchar(100) not null,
+     network         varchar(50) not null,
+     first_seen_at   timestamp not null,
+     last_seen_at    timestamp not null
+);
+
+ALTER TABLE login_devices
+ADD CONSTRAINT pk_login_devices PRIMARY KEY (org_id, username, fingerprint);
+
+ALTER TABLE login_devices ENABLE ROW LEVEL SECURITY;
+ALTER TABLE login_devices FORCE ROW LEVEL SECURITY;
+CREATE POLICY login_devices_org_isolation ON login_devices
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table login_alert_settings, users without settings are notified about logins from new devices
+CREATE TABLE login_alert_settings (
+     org_id          uuid not null,
+     username        varchar(50) not null,
+     opted_out       boolean not null default false
+);
+
+ALTER TABLE login_alert_settings
+ADD CONSTRAINT pk_login_alert_settings PRIMARY KEY (org_id, username);
+
+ALTER TABLE login_alert_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE login_alert_settings FORCE ROW LEVEL SECURITY;
+CREATE POLICY login_alert_settings_org_isolation ON login_alert_settings
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table failed_logins, the recent failed logins of the users of an organization
+CREATE TABLE failed_logins (
+     org_id          uuid not null,
+     username        varchar(50) not null,
+     failed_at       timestamp not null
+);
+
+CREATE INDEX idx_failed_logins_org_failed_at ON failed_logins (org_id, failed_at);
+
+ALTER TABLE failed_logins ENABLE ROW LEVEL SECURITY;
+ALTER TABLE failed_logins FORCE ROW LEVEL SECURITY;
+CREATE POLICY failed_logins_org_isolation ON failed_logins
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- Table failed_login_alerts, the alerts of the admins about bursts of failed logins of the organization
+CREATE TABLE failed_login_alerts (
+     org_id          uuid not null,
+     alerted_at      timestamp not null
+);
+
+CREATE INDEX idx_failed_login_alerts_org_alerted_at ON failed_login_alerts (org_id, alerted_at);
+
+ALTER TABLE failed_login_alerts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE failed_login_alerts FORCE ROW LEVEL SECURITY;
+CREATE POLICY failed_login_alerts_org_isolation ON failed_login_alerts
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
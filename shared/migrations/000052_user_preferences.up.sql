@@ -0,0 +1,20 @@
+-- Table user_preferences, the preferences of users which follow them across devices
+CREATE TABLE user_preferences (
+     org_id          uuid not null,
+     username        varchar(50) not null,
+     theme           varchar(10) not null default 'dark',
+     accent_color    varchar(7) not null default ''
+);
+
+ALTER TABLE user_preferences
+ADD CONSTRAINT pk_user_preferences PRIMARY KEY (org_id, username);
+
+ALTER TABLE user_preferences
+ADD CONSTRAINT fk_user_preferences_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE user_preferences ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_preferences FORCE ROW LEVEL SECURITY;
+CREATE POLICY user_preferences_org_isolation ON user_preferences
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,20 @@
+-- Table dashboard_layouts, the widgets on the dashboard of a user in the order they appear
+CREATE TABLE dashboard_layouts (
+     org_id          uuid not null,
+     username        varchar(50) not null,
+     widgets         varchar(500) not null,
+     updated_at      timestamp
+);
+
+ALTER TABLE dashboard_layouts
+ADD CONSTRAINT pk_dashboard_layouts PRIMARY KEY (org_id, username);
+
+ALTER TABLE dashboard_layouts
+ADD CONSTRAINT fk_dashboard_layouts_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE dashboard_layouts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE dashboard_layouts FORCE ROW LEVEL SECURITY;
+CREATE POLICY dashboard_layouts_org_isolation ON dashboard_layouts
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,23 @@
+-- Table organization_brandings, the logo, primary color and invoice footer of organizations
+CREATE TABLE organization_brandings (
+     org_id             uuid not null,
+     primary_color      varchar(7) not null default '',
+     invoice_footer     varchar(500) not null default '',
+     logo_key           varchar(255) not null default '',
+     logo_content_type  varchar(50) not null default '',
+     logo_size          bigint not null default 0,
+     updated_at         timestamp
+);
+
+ALTER TABLE organization_brandings
+ADD CONSTRAINT pk_organization_brandings PRIMARY KEY (org_id);
+
+ALTER TABLE organization_brandings
+ADD CONSTRAINT fk_organization_brandings_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE organization_brandings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE organization_brandings FORCE ROW LEVEL SECURITY;
+CREATE POLICY organization_brandings_org_isolation ON organization_brandings
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
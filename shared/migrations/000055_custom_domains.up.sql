@@ -0,0 +1,24 @@
+-- Table custom_domains, the hostnames of organizations which resolve to their branded login and client portal
+CREATE TABLE custom_domains (
+     custom_domain_id    uuid not null,
+     org_id              uuid not null,
+     hostname            varchar(253) not null,
+     verification_token  varchar(50) not null,
+     verified_at         timestamp,
+     created_at          timestamp not null
+);
+
+ALTER TABLE custom_domains
+ADD CONSTRAINT pk_custom_domains PRIMARY KEY (custom_domain_id);
+
+ALTER TABLE custom_domains
+ADD CONSTRAINT fk_custom_domains_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE UNIQUE INDEX idx_custom_domains_hostname ON custom_domains (hostname);
+
+ALTER TABLE custom_domains ENABLE ROW LEVEL SECURITY;
+ALTER TABLE custom_domains FORCE ROW LEVEL SECURITY;
+CREATE POLICY custom_domains_org_isolation ON custom_domains
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,23 @@
+-- Table sso_policies, the email domains of organizations whose users must log in with single sign-on
+CREATE TABLE sso_policies (
+     org_id          uuid not null,
+     email_domain    varchar(253) not null,
+     provider        varchar(20) not null,
+     exceptions      varchar(4000) not null default '',
+     updated_at      timestamp not null
+);
+
+ALTER TABLE sso_policies
+ADD CONSTRAINT pk_sso_policies PRIMARY KEY (org_id, email_domain);
+
+ALTER TABLE sso_policies
+ADD CONSTRAINT fk_sso_policies_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE INDEX idx_sso_policies_email_domain ON sso_policies (email_domain);
+
+ALTER TABLE sso_policies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sso_policies FORCE ROW LEVEL SECURITY;
+CREATE POLICY sso_policies_org_isolation ON sso_policies
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,31 @@
+-- Table service_accounts, the non-human accounts of integrations authenticated by their api token
+CREATE TABLE service_accounts (
+     service_account_id  uuid not null,
+     org_id              uuid not null,
+     name                varchar(100) not null,
+     role                varchar(20) not null,
+     scopes              varchar(4000) not null,
+     token_hash          varchar(64) not null,
+     created_by          varchar(255) not null,
+     created_at          timestamp not null default now(),
+     last_used_at        timestamp
+);
+
+ALTER TABLE service_accounts
+ADD CONSTRAINT pk_service_accounts PRIMARY KEY (service_account_id);
+
+ALTER TABLE service_accounts
+ADD CONSTRAINT fk_service_accounts_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE UNIQUE INDEX service_accounts_idx_token_hash
+ON service_accounts (token_hash);
+
+ALTER TABLE service_accounts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE service_accounts FORCE ROW LEVEL SECURITY;
+CREATE POLICY service_accounts_org_isolation ON service_accounts
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
+
+-- the service account of entries recorded for requests and changes of integrations
+ALTER TABLE audit_log ADD COLUMN service_account varchar(100);
@@ -0,0 +1,28 @@
+-- Table incoming_webhooks, the secret urls tools post JSON payloads to for creating activities or projects
+CREATE TABLE incoming_webhooks (
+     incoming_webhook_id  uuid not null,
+     org_id               uuid not null,
+     name                 varchar(100) not null,
+     target               varchar(20) not null,
+     username             varchar(255) not null,
+     mapping              varchar(4000) not null,
+     token_hash           varchar(64) not null,
+     created_by           varchar(255) not null,
+     created_at           timestamp not null default now()
+);
+
+ALTER TABLE incoming_webhooks
+ADD CONSTRAINT pk_incoming_webhooks PRIMARY KEY (incoming_webhook_id);
+
+ALTER TABLE incoming_webhooks
+ADD CONSTRAINT fk_incoming_webhooks_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+CREATE UNIQUE INDEX incoming_webhooks_idx_token_hash
+ON incoming_webhooks (token_hash);
+
+ALTER TABLE incoming_webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE incoming_webhooks FORCE ROW LEVEL SECURITY;
+CREATE POLICY incoming_webhooks_org_isolation ON incoming_webhooks
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
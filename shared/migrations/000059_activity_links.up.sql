@@ -0,0 +1,31 @@
+-- Table activity_links, the links like ticket urls or documents attached to activities with the preview of their page
+CREATE TABLE activity_links (
+     link_id        uuid not null,
+     org_id         uuid not null,
+     activity_id    uuid not null,
+     url            varchar(2000) not null,
+     title          varchar(255),
+     favicon_url    varchar(2000),
+     created_by     varchar(255) not null,
+     created_at     timestamp not null default now()
+);
+
+ALTER TABLE activity_links
+ADD CONSTRAINT pk_activity_links PRIMARY KEY (link_id);
+
+ALTER TABLE activity_links
+ADD CONSTRAINT fk_activity_links_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE activity_links
+ADD CONSTRAINT fk_activity_links_activities
+FOREIGN KEY (activity_id) REFERENCES activities (activity_id) ON DELETE CASCADE;
+
+CREATE INDEX activity_links_idx_activity
+ON activity_links (org_id, activity_id, created_at);
+
+ALTER TABLE activity_links ENABLE ROW LEVEL SECURITY;
+ALTER TABLE activity_links FORCE ROW LEVEL SECURITY;
+CREATE POLICY activity_links_org_isolation ON activity_links
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,32 @@
+-- Table categorization_rules, sets the project of activities from imports and integrations by their description and source
+CREATE TABLE categorization_rules (
+     rule_id     uuid not null,
+     org_id      uuid not null,
+     name        varchar(100) not null,
+     position    integer not null,
+     keywords    varchar(2000),
+     pattern     varchar(200),
+     source      varchar(20),
+     project_id  uuid not null,
+     created_at  timestamp not null default now()
+);
+
+ALTER TABLE categorization_rules
+ADD CONSTRAINT pk_categorization_rules PRIMARY KEY (rule_id);
+
+ALTER TABLE categorization_rules
+ADD CONSTRAINT fk_categorization_rules_orgs
+FOREIGN KEY (org_id) REFERENCES organizations (org_id);
+
+ALTER TABLE categorization_rules
+ADD CONSTRAINT fk_categorization_rules_projects
+FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE;
+
+CREATE INDEX categorization_rules_idx_org_position
+ON categorization_rules (org_id, position);
+
+ALTER TABLE categorization_rules ENABLE ROW LEVEL SECURITY;
+ALTER TABLE categorization_rules FORCE ROW LEVEL SECURITY;
+CREATE POLICY categorization_rules_org_isolation ON categorization_rules
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,14 @@
+-- Tokens of project badges are looked up by their hash and stored encrypted,
+-- the existing tokens are encrypted on startup
+ALTER TABLE project_badges ADD COLUMN token_hash varchar(64);
+
+UPDATE project_badges SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex');
+
+ALTER TABLE project_badges ALTER COLUMN token_hash SET NOT NULL;
+
+DROP INDEX project_badges_idx_token;
+
+CREATE UNIQUE INDEX project_badges_idx_token_hash
+ON project_badges (token_hash);
+
+ALTER TABLE project_badges ALTER COLUMN token TYPE varchar(255);
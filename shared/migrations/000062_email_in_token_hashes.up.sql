@@ -0,0 +1,14 @@
+-- Tokens of email-in addresses are looked up by their hash and stored encrypted,
+-- the existing tokens are encrypted on startup
+ALTER TABLE email_in_addresses ADD COLUMN token_hash varchar(64);
+
+UPDATE email_in_addresses SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex');
+
+ALTER TABLE email_in_addresses ALTER COLUMN token_hash SET NOT NULL;
+
+DROP INDEX email_in_addresses_idx_token;
+
+CREATE UNIQUE INDEX email_in_addresses_idx_token_hash
+ON email_in_addresses (token_hash);
+
+ALTER TABLE email_in_addresses ALTER COLUMN token TYPE varchar(255);
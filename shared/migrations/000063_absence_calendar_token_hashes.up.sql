@@ -0,0 +1,14 @@
+-- Tokens of absence calendars are looked up by their hash and stored encrypted,
+-- the existing tokens are encrypted on startup
+ALTER TABLE absence_calendars ADD COLUMN token_hash varchar(64);
+
+UPDATE absence_calendars SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex');
+
+ALTER TABLE absence_calendars ALTER COLUMN token_hash SET NOT NULL;
+
+DROP INDEX absence_calendars_idx_token;
+
+CREATE UNIQUE INDEX absence_calendars_idx_token_hash
+ON absence_calendars (token_hash);
+
+ALTER TABLE absence_calendars ALTER COLUMN token TYPE varchar(255);
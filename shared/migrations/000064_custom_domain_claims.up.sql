@@ -0,0 +1,9 @@
+-- A hostname may be claimed by several organizations until one of them verifies it,
+-- so unverified claims can't block the owner of the domain
+DROP INDEX idx_custom_domains_hostname;
+
+CREATE UNIQUE INDEX idx_custom_domains_hostname
+ON custom_domains (hostname) WHERE verified_at IS NOT NULL;
+
+CREATE UNIQUE INDEX idx_custom_domains_org_id_hostname
+ON custom_domains (org_id, hostname);
@@ -0,0 +1,24 @@
+-- Table deleted_activity_links, the links of deleted activities in the trash which are restored with the activity
+CREATE TABLE deleted_activity_links (
+     undo_token     uuid not null,
+     link_id        uuid not null,
+     org_id         uuid not null,
+     url            varchar(2000) not null,
+     title          varchar(255),
+     favicon_url    varchar(2000),
+     created_by     varchar(255) not null,
+     created_at     timestamp not null
+);
+
+ALTER TABLE deleted_activity_links
+ADD CONSTRAINT pk_deleted_activity_links PRIMARY KEY (undo_token, link_id);
+
+ALTER TABLE deleted_activity_links
+ADD CONSTRAINT fk_deleted_activity_links_deleted_activities
+FOREIGN KEY (undo_token) REFERENCES deleted_activities (undo_token) ON DELETE CASCADE;
+
+ALTER TABLE deleted_activity_links ENABLE ROW LEVEL SECURITY;
+ALTER TABLE deleted_activity_links FORCE ROW LEVEL SECURITY;
+CREATE POLICY deleted_activity_links_org_isolation ON deleted_activity_links
+  USING (CASE WHEN COALESCE(current_setting('baralga.org_id', true), '') = '' THEN true
+              ELSE org_id = current_setting('baralga.org_id', true)::uuid END);
@@ -0,0 +1,12 @@
+-- Badge codes of kiosk credentials are looked up by their hash, the plain badge codes are not stored
+ALTER TABLE kiosk_credentials ADD COLUMN badge_code_hash varchar(64);
+
+UPDATE kiosk_credentials SET badge_code_hash = encode(sha256(convert_to(badge_code, 'UTF8')), 'hex')
+WHERE badge_code IS NOT NULL;
+
+DROP INDEX kiosk_credentials_idx_org_id_badge_code;
+
+ALTER TABLE kiosk_credentials DROP COLUMN badge_code;
+
+CREATE UNIQUE INDEX kiosk_credentials_idx_org_id_badge_code_hash
+ON kiosk_credentials (org_id, badge_code_hash);
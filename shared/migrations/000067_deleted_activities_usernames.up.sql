@@ -0,0 +1,3 @@
+-- Usernames in the trash of deleted activities are as long as the usernames of users
+ALTER TABLE deleted_activities ALTER COLUMN deleted_by TYPE varchar(50);
+ALTER TABLE deleted_activities ALTER COLUMN username TYPE varchar(50);
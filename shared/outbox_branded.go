@@ -0,0 +1,41 @@
+package shared
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// BrandedOutbox appends the invoice footer of the organization to the mails sent via the outbox
+type BrandedOutbox struct {
+	outbox             Outbox
+	brandingRepository BrandingRepository
+}
+
+var _ Outbox = (*BrandedOutbox)(nil)
+
+// NewBrandedOutbox creates a new outbox which brands the mails before passing them to the outbox
+func NewBrandedOutbox(outbox Outbox, brandingRepository BrandingRepository) *BrandedOutbox {
+	return &BrandedOutbox{
+		outbox:             outbox,
+		brandingRepository: brandingRepository,
+	}
+}
+
+func (o *BrandedOutbox) SendMail(ctxWithTx context.Context, organizationID uuid.UUID, to, subject, body string) error {
+	branding, err := o.brandingRepository.FindBranding(ctxWithTx, organizationID)
+	if err != nil && !errors.Is(err, ErrBrandingNotFound) {
+		return err
+	}
+
+	if branding != nil && branding.InvoiceFooter != "" {
+		body = body + "\n\n--\n" + branding.InvoiceFooter
+	}
+
+	return o.outbox.SendMail(ctxWithTx, organizationID, to, subject, body)
+}
+
+func (o *BrandedOutbox) PublishEvent(ctxWithTx context.Context, event *CloudEvent) error {
+	return o.outbox.PublishEvent(ctxWithTx, event)
+}
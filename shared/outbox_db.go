@@ -0,0 +1,99 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+const (
+	outboxMailJobType  = "outbox-mail"
+	outboxEventJobType = "outbox-event"
+)
+
+type outboxMail struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// DbOutbox stores side effects as jobs within the transaction of the triggering write,
+// so they are delivered reliably by the job service once the transaction is committed
+type DbOutbox struct {
+	jobService     *JobService
+	mailResource   MailResource
+	eventPublisher EventPublisher
+}
+
+var _ Outbox = (*DbOutbox)(nil)
+
+// NewDbOutbox creates a new outbox delivering mails with the mail resource and events with the
+// event publisher, events are dropped if the publisher is nil
+func NewDbOutbox(jobService *JobService, mailResource MailResource, eventPublisher EventPublisher) *DbOutbox {
+	o := &DbOutbox{
+		jobService:     jobService,
+		mailResource:   mailResource,
+		eventPublisher: eventPublisher,
+	}
+
+	jobService.RegisterHandler(outboxMailJobType, o.handleMailJob)
+	jobService.RegisterHandler(outboxEventJobType, o.handleEventJob)
+
+	return o
+}
+
+func (o *DbOutbox) SendMail(ctxWithTx context.Context, organizationID uuid.UUID, to, subject, body string) error {
+	payload, err := json.Marshal(&outboxMail{
+		To:      to,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+
+	return o.jobService.Enqueue(ctxWithTx, NewJob(organizationID, outboxMailJobType, string(payload)))
+}
+
+func (o *DbOutbox) handleMailJob(ctx context.Context, job *Job) error {
+	mail := &outboxMail{}
+	err := json.Unmarshal([]byte(job.Payload), mail)
+	if err != nil {
+		return err
+	}
+
+	return o.mailResource.SendMail(mail.To, mail.Subject, mail.Body)
+}
+
+func (o *DbOutbox) PublishEvent(ctxWithTx context.Context, event *CloudEvent) error {
+	if o.eventPublisher == nil {
+		return nil
+	}
+
+	organizationID, err := uuid.Parse(event.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return o.jobService.Enqueue(ctxWithTx, NewJob(organizationID, outboxEventJobType, string(payload)))
+}
+
+func (o *DbOutbox) handleEventJob(ctx context.Context, job *Job) error {
+	if o.eventPublisher == nil {
+		return nil
+	}
+
+	event := &CloudEvent{}
+	err := json.Unmarshal([]byte(job.Payload), event)
+	if err != nil {
+		return err
+	}
+
+	return o.eventPublisher.Publish(ctx, event)
+}
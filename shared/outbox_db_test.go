@@ -0,0 +1,74 @@
+package shared
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestDbOutboxSendMail(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := NewInMemMailResource()
+	jobService := NewJobService(NewInMemRepositoryTxer(), NewInMemJobRepository())
+	outbox := NewDbOutbox(jobService, mailResource, nil)
+
+	err := outbox.SendMail(context.Background(), OrganizationIDSample, "user1@baralga.com", "Subject", "Body")
+	is.NoErr(err)
+
+	// mail is delivered by the job service only
+	is.Equal(len(mailResource.Mails), 0)
+
+	processed, err := jobService.ProcessNextJob(context.Background())
+	is.NoErr(err)
+	is.True(processed)
+
+	is.Equal(len(mailResource.Mails), 1)
+	is.True(strings.Contains(mailResource.Mails[0], "user1@baralga.com"))
+}
+
+func TestDbOutboxPublishEvent(t *testing.T) {
+	is := is.New(t)
+
+	eventPublisher := NewInMemEventPublisher()
+	jobService := NewJobService(NewInMemRepositoryTxer(), NewInMemJobRepository())
+	outbox := NewDbOutbox(jobService, NewInMemMailResource(), eventPublisher)
+
+	event, err := NewCloudEvent(OrganizationIDSample, "project.archived", "projects/1", map[string]string{"title": "Baralga"}, nil, time.Now())
+	is.NoErr(err)
+
+	err = outbox.PublishEvent(context.Background(), event)
+	is.NoErr(err)
+
+	// event is published by the job service only
+	is.Equal(len(eventPublisher.Events), 0)
+
+	processed, err := jobService.ProcessNextJob(context.Background())
+	is.NoErr(err)
+	is.True(processed)
+
+	is.Equal(len(eventPublisher.Events), 1)
+	is.Equal(eventPublisher.Events[0].ID, event.ID)
+	is.Equal(eventPublisher.Events[0].Type, "project.archived")
+	is.Equal(string(eventPublisher.Events[0].Data), `{"title":"Baralga"}`)
+}
+
+func TestDbOutboxPublishEventWithoutPublisher(t *testing.T) {
+	is := is.New(t)
+
+	jobService := NewJobService(NewInMemRepositoryTxer(), NewInMemJobRepository())
+	outbox := NewDbOutbox(jobService, NewInMemMailResource(), nil)
+
+	event, err := NewCloudEvent(OrganizationIDSample, "project.archived", "projects/1", nil, nil, time.Now())
+	is.NoErr(err)
+
+	err = outbox.PublishEvent(context.Background(), event)
+	is.NoErr(err)
+
+	processed, err := jobService.ProcessNextJob(context.Background())
+	is.NoErr(err)
+	is.True(!processed)
+}
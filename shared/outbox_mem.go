@@ -0,0 +1,36 @@
+package shared
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemOutbox struct {
+	mailResource MailResource
+
+	mu     sync.Mutex
+	Events []*CloudEvent
+}
+
+var _ Outbox = (*InMemOutbox)(nil)
+
+func NewInMemOutbox(mailResource MailResource) *InMemOutbox {
+	return &InMemOutbox{
+		mailResource: mailResource,
+	}
+}
+
+func (o *InMemOutbox) SendMail(ctxWithTx context.Context, organizationID uuid.UUID, to, subject, body string) error {
+	return o.mailResource.SendMail(to, subject, body)
+}
+
+func (o *InMemOutbox) PublishEvent(ctxWithTx context.Context, event *CloudEvent) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	published := *event
+	o.Events = append(o.Events, &published)
+	return nil
+}
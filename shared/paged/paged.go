@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 type Page struct {
@@ -15,8 +16,10 @@ type Page struct {
 }
 
 type PageParams struct {
-	Page int
-	Size int
+	Page      int
+	Size      int
+	SortBy    string
+	SortOrder string
 }
 
 // PageParamsFromQuery read the paging parameters from the url query params
@@ -50,6 +53,35 @@ func (p *PageParams) PageOfTotal(total int) *Page {
 	}
 }
 
+// PageLinks are the hrefs of the first, previous, next and last page of the url by relation
+func (p *Page) PageLinks(u *url.URL) map[string]string {
+	lastPage := p.TotalPages - 1
+	if lastPage < 0 {
+		lastPage = 0
+	}
+
+	links := map[string]string{
+		"first": pageHref(u, 0),
+		"last":  pageHref(u, lastPage),
+	}
+	if p.Number > 0 {
+		links["prev"] = pageHref(u, p.Number-1)
+	}
+	if p.Number < lastPage {
+		links["next"] = pageHref(u, p.Number+1)
+	}
+	return links
+}
+
+func pageHref(u *url.URL, page int) string {
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+
+	pageURL := *u
+	pageURL.RawQuery = query.Encode()
+	return pageURL.RequestURI()
+}
+
 func PageParamsOf(r *http.Request) *PageParams {
 	pageParams := &PageParams{
 		Page: 0,
@@ -72,5 +104,15 @@ func PageParamsOf(r *http.Request) *PageParams {
 		}
 	}
 
+	// sort like ?sort=title:desc, the repositories only accept whitelisted fields
+	sortQueryParam := r.URL.Query().Get("sort")
+	if sortQueryParam != "" {
+		sortParam := strings.SplitN(sortQueryParam, ":", 2)
+		pageParams.SortBy = strings.ToLower(sortParam[0])
+		if len(sortParam) == 2 {
+			pageParams.SortOrder = strings.ToLower(sortParam[1])
+		}
+	}
+
 	return pageParams
 }
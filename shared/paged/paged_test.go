@@ -107,3 +107,47 @@ func TestOffset(t *testing.T) {
 	// Assert
 	is.Equal(offset, 30)
 }
+
+func TestPageLinks(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+	u, _ := url.Parse("/api/activities?t=year&v=2021&page=1&size=10")
+	page := &Page{Size: 10, Number: 1, TotalElements: 35, TotalPages: 4}
+
+	// Act
+	links := page.PageLinks(u)
+
+	// Assert
+	is.Equal(links["first"], "/api/activities?page=0&size=10&t=year&v=2021")
+	is.Equal(links["prev"], "/api/activities?page=0&size=10&t=year&v=2021")
+	is.Equal(links["next"], "/api/activities?page=2&size=10&t=year&v=2021")
+	is.Equal(links["last"], "/api/activities?page=3&size=10&t=year&v=2021")
+}
+
+func TestPageLinksOfSinglePage(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+	u, _ := url.Parse("/api/activities")
+	page := &Page{Size: 50, Number: 0, TotalElements: 3, TotalPages: 1}
+
+	// Act
+	links := page.PageLinks(u)
+
+	// Assert
+	is.Equal(len(links), 2)
+	is.Equal(links["first"], "/api/activities?page=0")
+	is.Equal(links["last"], "/api/activities?page=0")
+}
+
+func TestPageParamsOfWithSort(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+	r, _ := http.NewRequest("GET", "/api/projects?sort=Last-Used:DESC", nil)
+
+	// Act
+	pageParams := PageParamsOf(r)
+
+	// Assert
+	is.Equal(pageParams.SortBy, "last-used")
+	is.Equal(pageParams.SortOrder, "desc")
+}
@@ -0,0 +1,131 @@
+package shared
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+)
+
+// Argon2idPasswordHasher hashes passwords with Argon2id to hashes encoded
+// like $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+type Argon2idPasswordHasher struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+var _ PasswordHasher = (*Argon2idPasswordHasher)(nil)
+
+// argon2idHash is a decoded Argon2id hash
+type argon2idHash struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+// NewArgon2idPasswordHasher creates a new Argon2id hasher with the iterations, the memory in KiB and the threads
+func NewArgon2idPasswordHasher(time, memory, threads int) (*Argon2idPasswordHasher, error) {
+	if time < 1 {
+		return nil, errors.Errorf("%s must be at least 1", ConfigKey("PasswordArgon2Time"))
+	}
+	if threads < 1 || threads > 255 {
+		return nil, errors.Errorf("%s must be between 1 and 255", ConfigKey("PasswordArgon2Threads"))
+	}
+	if memory < 8*threads || memory > 4*1024*1024 {
+		return nil, errors.Errorf("%s must be between 8 KiB per thread and 4 GiB", ConfigKey("PasswordArgon2Memory"))
+	}
+
+	return &Argon2idPasswordHasher{
+		time:    uint32(time),
+		memory:  uint32(memory),
+		threads: uint8(threads),
+	}, nil
+}
+
+func (h *Argon2idPasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.time, h.memory, h.threads, argon2idKeyLength)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.memory,
+		h.time,
+		h.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks the password with the parameters of the hash, so hashes with other parameters are still verified
+func (h *Argon2idPasswordHasher) Verify(hash, password string) bool {
+	decoded, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return false
+	}
+
+	key := argon2.IDKey([]byte(password), decoded.salt, decoded.time, decoded.memory, decoded.threads, uint32(len(decoded.key)))
+	return subtle.ConstantTimeCompare(key, decoded.key) == 1
+}
+
+func (h *Argon2idPasswordHasher) NeedsRehash(hash string) bool {
+	decoded, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return true
+	}
+	return decoded.time != h.time || decoded.memory != h.memory || decoded.threads != h.threads
+}
+
+func decodeArgon2idHash(hash string) (*argon2idHash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return nil, errors.New("no argon2id hash")
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil {
+		return nil, err
+	}
+	if version != argon2.Version {
+		return nil, errors.Errorf("argon2id version %v not supported", version)
+	}
+
+	decoded := &argon2idHash{}
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &decoded.memory, &decoded.time, &decoded.threads)
+	if err != nil {
+		return nil, err
+	}
+	if decoded.time < 1 || decoded.threads < 1 {
+		return nil, errors.New("argon2id parameters invalid")
+	}
+
+	decoded.salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, err
+	}
+	decoded.key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded.key) == 0 {
+		return nil, errors.New("argon2id key missing")
+	}
+	return decoded, nil
+}
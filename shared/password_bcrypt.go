@@ -0,0 +1,41 @@
+package shared
+
+import (
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptPasswordHasher hashes passwords with bcrypt
+type BcryptPasswordHasher struct {
+	cost int
+}
+
+var _ PasswordHasher = (*BcryptPasswordHasher)(nil)
+
+// NewBcryptPasswordHasher creates a new bcrypt hasher with the cost
+func NewBcryptPasswordHasher(cost int) *BcryptPasswordHasher {
+	return &BcryptPasswordHasher{
+		cost: cost,
+	}
+}
+
+func (h *BcryptPasswordHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptPasswordHasher) Verify(hash, password string) bool {
+	if !strings.HasPrefix(hash, "$2") {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func (h *BcryptPasswordHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
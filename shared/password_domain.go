@@ -0,0 +1,81 @@
+package shared
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	PasswordHashingArgon2id = "argon2id"
+	PasswordHashingBcrypt   = "bcrypt"
+)
+
+// PasswordHasher hashes passwords of users and verifies passwords against their hashes
+type PasswordHasher interface {
+	// Hash hashes the password to the encoded hash stored for the user
+	Hash(password string) (string, error)
+
+	// Verify checks the password against the hash, false if the hash is not of the hasher
+	Verify(hash, password string) bool
+
+	// NeedsRehash is true if the hash is not of the hasher or was hashed with other parameters
+	NeedsRehash(hash string) bool
+}
+
+// MigratingPasswordHasher hashes passwords with the current hasher and verifies the hashes of all hashers,
+// so the passwords are rehashed with the current hasher on their next successful login
+type MigratingPasswordHasher struct {
+	current PasswordHasher
+	legacy  []PasswordHasher
+}
+
+var _ PasswordHasher = (*MigratingPasswordHasher)(nil)
+
+// NewMigratingPasswordHasher creates a hasher which hashes with the current hasher and verifies the legacy hashes too
+func NewMigratingPasswordHasher(current PasswordHasher, legacy ...PasswordHasher) *MigratingPasswordHasher {
+	return &MigratingPasswordHasher{
+		current: current,
+		legacy:  legacy,
+	}
+}
+
+// NewPasswordHasher creates the password hasher of the config, the hashes of the other algorithm are still verified
+func NewPasswordHasher(config *Config) (*MigratingPasswordHasher, error) {
+	if config.PasswordBcryptCost < 4 || config.PasswordBcryptCost > 31 {
+		return nil, errors.Errorf("%s must be between 4 and 31", ConfigKey("PasswordBcryptCost"))
+	}
+	bcryptHasher := NewBcryptPasswordHasher(config.PasswordBcryptCost)
+
+	argon2idHasher, err := NewArgon2idPasswordHasher(config.PasswordArgon2Time, config.PasswordArgon2Memory, config.PasswordArgon2Threads)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.PasswordHashing {
+	case PasswordHashingArgon2id:
+		return NewMigratingPasswordHasher(argon2idHasher, bcryptHasher), nil
+	case PasswordHashingBcrypt:
+		return NewMigratingPasswordHasher(bcryptHasher, argon2idHasher), nil
+	default:
+		return nil, errors.Errorf("%s must be %s or %s", ConfigKey("PasswordHashing"), PasswordHashingArgon2id, PasswordHashingBcrypt)
+	}
+}
+
+func (h *MigratingPasswordHasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *MigratingPasswordHasher) Verify(hash, password string) bool {
+	if h.current.Verify(hash, password) {
+		return true
+	}
+	for _, hasher := range h.legacy {
+		if hasher.Verify(hash, password) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *MigratingPasswordHasher) NeedsRehash(hash string) bool {
+	return h.current.NeedsRehash(hash)
+}
@@ -0,0 +1,114 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestArgon2idPasswordHasher(t *testing.T) {
+	is := is.New(t)
+
+	hasher, err := NewArgon2idPasswordHasher(1, 64, 1)
+	is.NoErr(err)
+
+	hash, err := hasher.Hash("adm1n")
+	is.NoErr(err)
+	is.True(strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	is.True(hasher.Verify(hash, "adm1n"))
+	is.True(!hasher.Verify(hash, "-invalid-"))
+	is.True(!hasher.Verify("$2a$10$NuzYobDOSTCx/EKBClGwGe0A9c8/yC7D4IP75hwz1jn.RCBfdEtb2", "adm1n"))
+	is.True(!hasher.Verify("$argon2id$v=19$m=64,t=1,p=1$bm8tc2FsdA$", "adm1n"))
+
+	anotherHash, err := hasher.Hash("adm1n")
+	is.NoErr(err)
+	is.True(anotherHash != hash)
+
+	is.True(!hasher.NeedsRehash(hash))
+	is.True(hasher.NeedsRehash("$2a$10$NuzYobDOSTCx/EKBClGwGe0A9c8/yC7D4IP75hwz1jn.RCBfdEtb2"))
+
+	strongerHasher, err := NewArgon2idPasswordHasher(2, 128, 1)
+	is.NoErr(err)
+	is.True(strongerHasher.NeedsRehash(hash))
+	is.True(strongerHasher.Verify(hash, "adm1n"))
+}
+
+func TestNewArgon2idPasswordHasherWithInvalidParameters(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewArgon2idPasswordHasher(0, 64, 1)
+	is.True(err != nil)
+
+	_, err = NewArgon2idPasswordHasher(1, 64, 0)
+	is.True(err != nil)
+
+	_, err = NewArgon2idPasswordHasher(1, 8, 2)
+	is.True(err != nil)
+}
+
+func TestBcryptPasswordHasher(t *testing.T) {
+	is := is.New(t)
+
+	hasher := NewBcryptPasswordHasher(10)
+	hash := "$2a$10$NuzYobDOSTCx/EKBClGwGe0A9c8/yC7D4IP75hwz1jn.RCBfdEtb2"
+
+	is.True(hasher.Verify(hash, "adm1n"))
+	is.True(!hasher.Verify(hash, "-invalid-"))
+	is.True(!hasher.NeedsRehash(hash))
+	is.True(NewBcryptPasswordHasher(12).NeedsRehash(hash))
+	is.True(hasher.NeedsRehash("$argon2id$v=19$m=64,t=1,p=1$bm8tc2FsdA$a2V5"))
+}
+
+func TestMigratingPasswordHasher(t *testing.T) {
+	is := is.New(t)
+
+	argon2idHasher, err := NewArgon2idPasswordHasher(1, 64, 1)
+	is.NoErr(err)
+	hasher := NewMigratingPasswordHasher(argon2idHasher, NewBcryptPasswordHasher(4))
+
+	bcryptHash := "$2a$10$NuzYobDOSTCx/EKBClGwGe0A9c8/yC7D4IP75hwz1jn.RCBfdEtb2"
+	is.True(hasher.Verify(bcryptHash, "adm1n"))
+	is.True(!hasher.Verify(bcryptHash, "-invalid-"))
+	is.True(hasher.NeedsRehash(bcryptHash))
+
+	hash, err := hasher.Hash("adm1n")
+	is.NoErr(err)
+	is.True(strings.HasPrefix(hash, "$argon2id$"))
+	is.True(hasher.Verify(hash, "adm1n"))
+	is.True(!hasher.NeedsRehash(hash))
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	is := is.New(t)
+
+	config := &Config{
+		PasswordHashing:       PasswordHashingArgon2id,
+		PasswordArgon2Time:    1,
+		PasswordArgon2Memory:  64,
+		PasswordArgon2Threads: 1,
+		PasswordBcryptCost:    4,
+	}
+
+	hasher, err := NewPasswordHasher(config)
+	is.NoErr(err)
+	hash, err := hasher.Hash("adm1n")
+	is.NoErr(err)
+	is.True(strings.HasPrefix(hash, "$argon2id$"))
+
+	config.PasswordHashing = PasswordHashingBcrypt
+	hasher, err = NewPasswordHasher(config)
+	is.NoErr(err)
+	is.True(hasher.NeedsRehash(hash))
+	is.True(hasher.Verify(hash, "adm1n"))
+
+	config.PasswordHashing = "md5"
+	_, err = NewPasswordHasher(config)
+	is.True(err != nil)
+
+	config.PasswordHashing = PasswordHashingBcrypt
+	config.PasswordBcryptCost = 3
+	_, err = NewPasswordHasher(config)
+	is.True(err != nil)
+}
@@ -0,0 +1,68 @@
+package shared
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+)
+
+// MergePatchContentType is the content type of a JSON Merge Patch (RFC 7386)
+const MergePatchContentType = "application/merge-patch+json"
+
+// IsMergePatch checks whether the request body is a JSON Merge Patch
+func IsMergePatch(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return mediaType == MergePatchContentType
+}
+
+// MergePatch applies the JSON Merge Patch to the json document
+func MergePatch(document, patch []byte) ([]byte, error) {
+	var documentValue any
+	err := json.Unmarshal(document, &documentValue)
+	if err != nil {
+		return nil, err
+	}
+
+	var patchValue any
+	err = json.Unmarshal(patch, &patchValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergePatch(documentValue, patchValue))
+}
+
+// MergePatchModel applies the JSON Merge Patch to the json of the model
+func MergePatchModel(model any, patch []byte) ([]byte, error) {
+	document, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return MergePatch(document, patch)
+}
+
+func mergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+
+	return targetObject
+}
@@ -0,0 +1,36 @@
+package shared
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestMergePatch(t *testing.T) {
+	is := is.New(t)
+
+	document := `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`
+	patch := `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`
+
+	merged, err := MergePatch([]byte(document), []byte(patch))
+	is.NoErr(err)
+	is.Equal(string(merged), `{"author":{"givenName":"John"},"content":"This will be unchanged","phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}`)
+}
+
+func TestMergePatchWithInvalidPatch(t *testing.T) {
+	is := is.New(t)
+
+	_, err := MergePatch([]byte(`{"title":"Goodbye!"}`), []byte(`{INVALID`))
+	is.True(err != nil)
+}
+
+func TestIsMergePatch(t *testing.T) {
+	is := is.New(t)
+
+	r, _ := http.NewRequest("PATCH", "/api/activities/1", nil)
+	is.True(!IsMergePatch(r))
+
+	r.Header.Set("Content-Type", "application/merge-patch+json; charset=utf-8")
+	is.True(IsMergePatch(r))
+}
@@ -0,0 +1,325 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Attachment is a file embedded in an archival document, like the XML of an electronic invoice
+// as alternative representation of the document
+type Attachment struct {
+	Name         string
+	Description  string
+	ContentType  string
+	Relationship string
+	Content      []byte
+}
+
+// embeddedFont is a TrueType font embedded in archival documents, with the metrics in thousandths of the font size
+type embeddedFont struct {
+	name      string
+	program   []byte
+	length    int
+	width     int
+	ascent    int
+	descent   int
+	capHeight int
+	bbox      [4]int
+}
+
+var (
+	embeddedFontsOnce sync.Once
+	embeddedFonts     [2]*embeddedFont
+	embeddedFontsErr  error
+)
+
+// NewArchival creates a new PDF/A-3b document with a first empty page, the fonts are embedded
+// so that the document can be archived and files can be attached
+func NewArchival(title string, created time.Time) *Document {
+	d := New(title)
+	d.archival = true
+	d.created = created.UTC()
+	return d
+}
+
+// Attach embeds the file in the document, attachments are only written for archival documents
+func (d *Document) Attach(attachment *Attachment) {
+	d.attachments = append(d.attachments, attachment)
+}
+
+// AddMetadata adds the rdf:Description to the XMP metadata of archival documents,
+// like the extension schema describing the attachments
+func (d *Document) AddMetadata(description string) {
+	d.metadata = append(d.metadata, description)
+}
+
+// loadEmbeddedFonts reads the metrics of the regular and bold Go Mono fonts and compresses them once
+func loadEmbeddedFonts() ([2]*embeddedFont, error) {
+	embeddedFontsOnce.Do(func() {
+		for i, ttf := range [][]byte{gomono.TTF, gomonobold.TTF} {
+			embeddedFonts[i], embeddedFontsErr = newEmbeddedFont(ttf)
+			if embeddedFontsErr != nil {
+				return
+			}
+		}
+	})
+	return embeddedFonts, embeddedFontsErr
+}
+
+func newEmbeddedFont(ttf []byte) (*embeddedFont, error) {
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &sfnt.Buffer{}
+	name, err := f.Name(buf, sfnt.NameIDPostScript)
+	if err != nil {
+		return nil, err
+	}
+
+	unitsPerEm := f.UnitsPerEm()
+	ppem := fixed.Int26_6(unitsPerEm) << 6
+	scale := func(v fixed.Int26_6) int {
+		return int(math.Round(float64(v) / 64 * 1000 / float64(unitsPerEm)))
+	}
+
+	metrics, err := f.Metrics(buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, err
+	}
+	bounds, err := f.Bounds(buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, err
+	}
+	glyph, err := f.GlyphIndex(buf, 'M')
+	if err != nil {
+		return nil, err
+	}
+	advance, err := f.GlyphAdvance(buf, glyph, ppem, font.HintingNone)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := compress(ttf)
+	if err != nil {
+		return nil, err
+	}
+
+	// the y axis of the font points down, the y axis of the PDF points up
+	return &embeddedFont{
+		name:      name,
+		program:   program,
+		length:    len(ttf),
+		width:     scale(advance),
+		ascent:    scale(metrics.Ascent),
+		descent:   -scale(metrics.Descent),
+		capHeight: scale(metrics.CapHeight),
+		bbox:      [4]int{scale(bounds.Min.X), -scale(bounds.Max.Y), scale(bounds.Max.X), -scale(bounds.Min.Y)},
+	}, nil
+}
+
+// fontObject is the simple TrueType font in WinAnsiEncoding, all characters of the monospaced font have the same width
+func (f *embeddedFont) fontObject(descriptor int) string {
+	widths := strings.TrimSpace(strings.Repeat(fmt.Sprintf("%d ", f.width), 255-32+1))
+	return fmt.Sprintf(
+		"<< /Type /Font /Subtype /TrueType /BaseFont /%s /FirstChar 32 /LastChar 255 /Widths [%s] /Encoding /WinAnsiEncoding /FontDescriptor %d 0 R >>",
+		f.name, widths, descriptor,
+	)
+}
+
+// descriptorObject describes the fixed pitch, nonsymbolic font with its program in the given object
+func (f *embeddedFont) descriptorObject(program int) string {
+	return fmt.Sprintf(
+		"<< /Type /FontDescriptor /FontName /%s /Flags 33 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /MissingWidth %d /FontFile2 %d 0 R >>",
+		f.name, f.bbox[0], f.bbox[1], f.bbox[2], f.bbox[3], f.ascent, f.descent, f.capHeight, f.width, program,
+	)
+}
+
+func (f *embeddedFont) programObject() string {
+	return fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(f.program), f.length, f.program)
+}
+
+// xmpMetadata is the XMP metadata of the archival document, it matches the document information
+func (d *Document) xmpMetadata() string {
+	title := &strings.Builder{}
+	_ = xml.EscapeText(title, []byte(d.title))
+	created := d.created.Format("2006-01-02T15:04:05+00:00")
+
+	return fmt.Sprintf(`<?xpacket begin="%s" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/">
+<pdfaid:part>3</pdfaid:part>
+<pdfaid:conformance>B</pdfaid:conformance>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:title><rdf:Alt><rdf:li xml:lang="x-default">%s</rdf:li></rdf:Alt></dc:title>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:pdf="http://ns.adobe.com/pdf/1.3/">
+<pdf:Producer>Baralga</pdf:Producer>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:xmp="http://ns.adobe.com/xap/1.0/">
+<xmp:CreateDate>%s</xmp:CreateDate>
+<xmp:ModifyDate>%s</xmp:ModifyDate>
+</rdf:Description>
+%s
+</rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`, "\ufeff", title, created, created, strings.Join(d.metadata, "\n"))
+}
+
+// attachmentObjects are the embedded file stream and the file specification of the attachment
+func (d *Document) attachmentObjects(attachment *Attachment, file int) (string, string, error) {
+	content, err := compress(attachment.Content)
+	if err != nil {
+		return "", "", err
+	}
+
+	stream := fmt.Sprintf(
+		"<< /Type /EmbeddedFile /Subtype /%s /Params << /ModDate (%s) /Size %d >> /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+		strings.ReplaceAll(attachment.ContentType, "/", "#2F"), pdfDate(d.created), len(attachment.Content), len(content), content,
+	)
+	spec := fmt.Sprintf(
+		"<< /Type /Filespec /F (%s) /UF (%s) /Desc (%s) /AFRelationship /%s /EF << /F %d 0 R /UF %d 0 R >> >>",
+		escape(attachment.Name), escape(attachment.Name), escape(attachment.Description), attachment.Relationship, file, file,
+	)
+	return stream, spec, nil
+}
+
+// pdfDate formats the time as date of the document information
+func pdfDate(t time.Time) string {
+	return t.Format("D:20060102150405+00'00'")
+}
+
+func compress(content []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := zlib.NewWriter(buf)
+	_, err := zw.Write(content)
+	if err != nil {
+		return nil, err
+	}
+	err = zw.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var (
+	srgbProfileOnce sync.Once
+	srgbProfileData []byte
+)
+
+// srgbProfile is an ICC color profile of sRGB as output intent of archival documents,
+// with the primaries adapted to D50 and the transfer curve as table
+func srgbProfile() []byte {
+	srgbProfileOnce.Do(func() {
+		srgbProfileData = newSRGBProfile()
+	})
+	return srgbProfileData
+}
+
+func newSRGBProfile() []byte {
+	s15Fixed16 := func(b *bytes.Buffer, values ...float64) {
+		for _, v := range values {
+			_ = binary.Write(b, binary.BigEndian, int32(math.Round(v*65536)))
+		}
+	}
+	xyz := func(x, y, z float64) []byte {
+		b := &bytes.Buffer{}
+		b.WriteString("XYZ \x00\x00\x00\x00")
+		s15Fixed16(b, x, y, z)
+		return b.Bytes()
+	}
+	text := func(s string) []byte {
+		return []byte("text\x00\x00\x00\x00" + s + "\x00")
+	}
+
+	description := "sRGB IEC61966-2.1"
+	desc := &bytes.Buffer{}
+	desc.WriteString("desc\x00\x00\x00\x00")
+	_ = binary.Write(desc, binary.BigEndian, uint32(len(description)+1))
+	desc.WriteString(description + "\x00")
+	// empty unicode and script code descriptions
+	desc.Write(make([]byte, 4+4+2+1+67))
+
+	const trcEntries = 1024
+	trc := &bytes.Buffer{}
+	trc.WriteString("curv\x00\x00\x00\x00")
+	_ = binary.Write(trc, binary.BigEndian, uint32(trcEntries))
+	for i := 0; i < trcEntries; i++ {
+		v := float64(i) / (trcEntries - 1)
+		if v <= 0.04045 {
+			v = v / 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		_ = binary.Write(trc, binary.BigEndian, uint16(math.Round(v*65535)))
+	}
+
+	tags := []struct {
+		signature string
+		data      []byte
+	}{
+		{"desc", desc.Bytes()},
+		{"cprt", text("No copyright, use freely")},
+		{"wtpt", xyz(0.9642, 1.0, 0.8249)},
+		{"rXYZ", xyz(0.4360747, 0.2225045, 0.0139322)},
+		{"gXYZ", xyz(0.3850649, 0.7168786, 0.0971045)},
+		{"bXYZ", xyz(0.1430804, 0.0606169, 0.7141733)},
+		{"rTRC", trc.Bytes()},
+		{"gTRC", trc.Bytes()},
+		{"bTRC", trc.Bytes()},
+	}
+
+	const headerSize = 128
+	table := &bytes.Buffer{}
+	data := &bytes.Buffer{}
+	_ = binary.Write(table, binary.BigEndian, uint32(len(tags)))
+	dataStart := headerSize + 4 + 12*len(tags)
+	offsets := map[*byte]int{}
+	for _, tag := range tags {
+		// the curves of the colors share their data
+		offset, ok := offsets[&tag.data[0]]
+		if !ok {
+			offset = dataStart + data.Len()
+			offsets[&tag.data[0]] = offset
+			data.Write(tag.data)
+			for data.Len()%4 != 0 {
+				data.WriteByte(0)
+			}
+		}
+		table.WriteString(tag.signature)
+		_ = binary.Write(table, binary.BigEndian, uint32(offset))
+		_ = binary.Write(table, binary.BigEndian, uint32(len(tag.data)))
+	}
+
+	header := &bytes.Buffer{}
+	_ = binary.Write(header, binary.BigEndian, uint32(headerSize+table.Len()+data.Len()))
+	header.Write(make([]byte, 4))
+	_ = binary.Write(header, binary.BigEndian, uint32(0x02100000))
+	header.WriteString("mntrRGB XYZ ")
+	for _, v := range []uint16{2024, 1, 1, 0, 0, 0} {
+		_ = binary.Write(header, binary.BigEndian, v)
+	}
+	header.WriteString("acsp")
+	header.Write(make([]byte, 4+4+4+4+8+4))
+	s15Fixed16(header, 0.9642, 1.0, 0.8249)
+	header.Write(make([]byte, headerSize-header.Len()))
+
+	return append(append(header.Bytes(), table.Bytes()...), data.Bytes()...)
+}
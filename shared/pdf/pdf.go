@@ -0,0 +1,333 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/md5"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"strings"
+	"time"
+)
+
+// ContentType is the content type of the rendered documents
+const ContentType = "application/pdf"
+
+// Size of an A4 page in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// charWidth is the width of a character of the monospaced font relative to the font size
+const charWidth = 0.6
+
+// Document is a PDF document of text, lines and images in a monospaced font,
+// the coordinates are in points from the top left corner of the page
+type Document struct {
+	title       string
+	pages       []*bytes.Buffer
+	images      []*Image
+	footer      []string
+	footerSize  float64
+	archival    bool
+	created     time.Time
+	attachments []*Attachment
+	metadata    []string
+}
+
+// Image is a raster image which can be drawn on the pages of a document,
+// kept as compressed RGB samples
+type Image struct {
+	width  int
+	height int
+	data   []byte
+}
+
+// NewImage decodes a PNG or JPEG image, transparent parts are drawn on white
+func NewImage(content io.Reader) (*Image, error) {
+	img, _, err := image.Decode(content)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	buf := &bytes.Buffer{}
+	zw := zlib.NewWriter(buf)
+	row := make([]byte, 0, bounds.Dx()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row = row[:0]
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			white := 0xffff - a
+			row = append(row, byte((r+white)>>8), byte((g+white)>>8), byte((b+white)>>8))
+		}
+		_, err := zw.Write(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = zw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Image{
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		data:   buf.Bytes(),
+	}, nil
+}
+
+// AspectRatio is the width of the image relative to its height
+func (i *Image) AspectRatio() float64 {
+	return float64(i.width) / float64(i.height)
+}
+
+// New creates a new document with a first empty page
+func New(title string) *Document {
+	d := &Document{
+		title: title,
+	}
+	d.AddPage()
+	return d
+}
+
+// AddPage starts a new page, all following content is added to the new page
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// Text adds the text with its left edge at x and its baseline at y
+func (d *Document) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y, escape(text))
+}
+
+// TextRight adds the text with its right edge at x and its baseline at y
+func (d *Document) TextRight(x, y, size float64, bold bool, text string) {
+	d.Text(x-TextWidth(text, size), y, size, bold, text)
+}
+
+// Line adds a thin line from x1, y1 to x2, y2
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// SetColor sets the color of the following text and lines on the page, every page starts in black
+func (d *Document) SetColor(r, g, b uint8) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f rg %.3f %.3f %.3f RG\n", float64(r)/255, float64(g)/255, float64(b)/255, float64(r)/255, float64(g)/255, float64(b)/255)
+}
+
+// Image draws the image with its top left corner at x, y scaled to width and height
+func (d *Document) Image(img *Image, x, y, width, height float64) {
+	index := -1
+	for i, added := range d.images {
+		if added == img {
+			index = i
+		}
+	}
+	if index < 0 {
+		d.images = append(d.images, img)
+		index = len(d.images) - 1
+	}
+
+	fmt.Fprintf(d.page(), "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", width, height, x, PageHeight-y-height, index+1)
+}
+
+// Footer sets the text centered at the bottom of every page, lines are separated by newlines
+func (d *Document) Footer(size float64, text string) {
+	d.footer = nil
+	d.footerSize = size
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		d.footer = append(d.footer, strings.TrimSpace(line))
+	}
+}
+
+// FooterHeight is the space the footer takes at the bottom of every page, content must stay above it
+func (d *Document) FooterHeight() float64 {
+	if len(d.footer) == 0 {
+		return 0
+	}
+	return 25 + float64(len(d.footer))*d.footerSize*1.4
+}
+
+// TextWidth is the width of the text in the given font size
+func TextWidth(text string, size float64) float64 {
+	return float64(len([]rune(text))) * size * charWidth
+}
+
+// Write renders the document as PDF, archival documents as PDF/A-3b with their attachments
+func (d *Document) Write(w io.Writer) error {
+	buf := &bytes.Buffer{}
+	var offsets []int
+
+	object := func(content string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", len(offsets), content)
+	}
+
+	if d.archival {
+		buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	} else {
+		buf.WriteString("%PDF-1.4\n")
+	}
+
+	// objects 1 to 5 are the catalog, the page tree, the fonts and the info,
+	// followed by a page and its content for every page and the images
+	firstPage := 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	firstImage := firstPage + 2*len(d.pages)
+	xObjects := ""
+	if len(d.images) > 0 {
+		refs := make([]string, len(d.images))
+		for i := range d.images {
+			refs[i] = fmt.Sprintf("/Im%d %d 0 R", i+1, firstImage+i)
+		}
+		xObjects = fmt.Sprintf(" /XObject << %s >>", strings.Join(refs, " "))
+	}
+
+	// archival documents go on with the metadata, the color profile, the descriptor and program of both fonts
+	// and the embedded file and specification of every attachment
+	metadata := firstImage + len(d.images)
+	profile := metadata + 1
+	firstFont := profile + 1
+	firstAttachment := firstFont + 4
+
+	var fonts [2]*embeddedFont
+	if d.archival {
+		var err error
+		fonts, err = loadEmbeddedFonts()
+		if err != nil {
+			return err
+		}
+
+		specs := make([]string, len(d.attachments))
+		names := make([]string, len(d.attachments))
+		for i, attachment := range d.attachments {
+			specs[i] = fmt.Sprintf("%d 0 R", firstAttachment+2*i+1)
+			names[i] = fmt.Sprintf("(%s) %s", escape(attachment.Name), specs[i])
+		}
+		attachments := ""
+		if len(d.attachments) > 0 {
+			attachments = fmt.Sprintf(" /Names << /EmbeddedFiles << /Names [%s] >> >> /AF [%s]", strings.Join(names, " "), strings.Join(specs, " "))
+		}
+
+		object(fmt.Sprintf(
+			"<< /Type /Catalog /Pages 2 0 R /Metadata %d 0 R /OutputIntents [<< /Type /OutputIntent /S /GTS_PDFA1 /OutputConditionIdentifier (sRGB IEC61966-2.1) /DestOutputProfile %d 0 R >>]%s >>",
+			metadata, profile, attachments,
+		))
+		object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+		object(fonts[0].fontObject(firstFont))
+		object(fonts[1].fontObject(firstFont + 2))
+		object(fmt.Sprintf("<< /Title (%s) /Producer (Baralga) /CreationDate (%s) /ModDate (%s) >>", escape(d.title), pdfDate(d.created), pdfDate(d.created)))
+	} else {
+		object("<< /Type /Catalog /Pages 2 0 R >>")
+		object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+		object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+		object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+		object(fmt.Sprintf("<< /Title (%s) /Producer (Baralga) >>", escape(d.title)))
+	}
+	for i, page := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >>%s >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, xObjects, firstPage+2*i+1,
+		))
+		content := page.String() + d.footerContent()
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+	for _, img := range d.images {
+		object(fmt.Sprintf(
+			"<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+			img.width, img.height, len(img.data), img.data,
+		))
+	}
+
+	if d.archival {
+		xmp := d.xmpMetadata()
+		object(fmt.Sprintf("<< /Type /Metadata /Subtype /XML /Length %d >>\nstream\n%s\nendstream", len(xmp), xmp))
+		icc := srgbProfile()
+		object(fmt.Sprintf("<< /N 3 /Length %d >>\nstream\n%s\nendstream", len(icc), icc))
+		for i, font := range fonts {
+			object(font.descriptorObject(firstFont + 2*i + 1))
+			object(font.programObject())
+		}
+		for i, attachment := range d.attachments {
+			stream, spec, err := d.attachmentObjects(attachment, firstAttachment+2*i)
+			if err != nil {
+				return err
+			}
+			object(stream)
+			object(spec)
+		}
+	}
+
+	// archival documents are identified by the hash of their content
+	id := ""
+	if d.archival {
+		id = fmt.Sprintf(" /ID [<%x> <%x>]", md5.Sum(buf.Bytes()), md5.Sum(buf.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R%s >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, id, xref)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// footerContent renders the footer lines in black centered above the bottom of the page
+func (d *Document) footerContent() string {
+	if len(d.footer) == 0 {
+		return ""
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("0 0 0 rg 0 0 0 RG\n")
+	lineHeight := d.footerSize * 1.4
+	y := PageHeight - 25 - float64(len(d.footer)-1)*lineHeight
+	for _, line := range d.footer {
+		x := (PageWidth - TextWidth(line, d.footerSize)) / 2
+		fmt.Fprintf(buf, "BT /F1 %.2f Tf %.2f %.2f Td (%s) Tj ET\n", d.footerSize, x, PageHeight-y, escape(line))
+		y += lineHeight
+	}
+	return buf.String()
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// escape encodes the text in WinAnsiEncoding as string literal, characters not in the encoding are replaced
+func escape(text string) string {
+	b := &strings.Builder{}
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '€':
+			b.WriteString("\\200")
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestWrite(t *testing.T) {
+	is := is.New(t)
+	buf := &bytes.Buffer{}
+
+	d := New("Statement")
+	d.Text(40, 60, 16, true, "Statement (March)")
+	d.Line(40, 70, 550, 70)
+	d.AddPage()
+	d.TextRight(550, 60, 10, false, "1.200,00 €")
+
+	err := d.Write(buf)
+
+	is.NoErr(err)
+	doc := buf.String()
+	is.True(strings.HasPrefix(doc, "%PDF-1.4"))
+	is.True(strings.HasSuffix(doc, "%%EOF\n"))
+	is.True(strings.Contains(doc, "/Count 2"))
+	is.True(strings.Contains(doc, `(Statement \(March\)) Tj`))
+	is.True(strings.Contains(doc, `(1.200,00 \200) Tj`))
+
+	// all entries of the cross reference table point to their objects
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(doc, -1)
+	is.Equal(len(entries), 9)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		is.True(strings.HasPrefix(doc[offset:], fmt.Sprintf("%d 0 obj", i+1)))
+	}
+}
+
+func TestWriteWithImageAndFooter(t *testing.T) {
+	is := is.New(t)
+	buf := &bytes.Buffer{}
+
+	logo := &bytes.Buffer{}
+	rgba := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	rgba.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	err := png.Encode(logo, rgba)
+	is.NoErr(err)
+
+	img, err := NewImage(logo)
+	is.NoErr(err)
+	is.Equal(img.AspectRatio(), 2.0)
+
+	d := New("Statement")
+	d.Footer(8, "ACME Corp.\nIBAN DE00 1234")
+	d.Image(img, 400, 40, 100, 50)
+	d.SetColor(255, 0, 0)
+	d.Text(40, 60, 16, true, "Statement")
+	d.AddPage()
+	d.Image(img, 400, 40, 100, 50)
+
+	err = d.Write(buf)
+
+	is.NoErr(err)
+	doc := buf.String()
+	is.True(strings.Contains(doc, "1.000 0.000 0.000 rg 1.000 0.000 0.000 RG"))
+	is.True(strings.Contains(doc, "/Im1 Do"))
+	is.Equal(strings.Count(doc, "/Subtype /Image"), 1)
+	is.Equal(strings.Count(doc, "/XObject << /Im1 10 0 R >>"), 2)
+	is.Equal(strings.Count(doc, "(IBAN DE00 1234) Tj"), 2)
+	is.Equal(d.FooterHeight(), 25+2*8*1.4)
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(doc, -1)
+	is.Equal(len(entries), 10)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		is.True(strings.HasPrefix(doc[offset:], fmt.Sprintf("%d 0 obj", i+1)))
+	}
+}
+
+func TestWriteArchival(t *testing.T) {
+	is := is.New(t)
+	buf := &bytes.Buffer{}
+
+	d := NewArchival("Invoice", time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC))
+	d.Text(40, 60, 16, true, "Invoice")
+	d.Attach(&Attachment{
+		Name:         "invoice.xml",
+		Description:  "Invoice",
+		ContentType:  "text/xml",
+		Relationship: "Alternative",
+		Content:      []byte("<invoice/>"),
+	})
+	d.AddMetadata(`<rdf:Description rdf:about=""/>`)
+
+	err := d.Write(buf)
+
+	is.NoErr(err)
+	doc := buf.String()
+	is.True(strings.HasPrefix(doc, "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"))
+	is.True(strings.Contains(doc, "<pdfaid:part>3</pdfaid:part>"))
+	is.True(strings.Contains(doc, "<xmp:CreateDate>2024-04-01T09:30:00+00:00</xmp:CreateDate>"))
+	is.True(strings.Contains(doc, "/CreationDate (D:20240401093000+00'00')"))
+	is.True(strings.Contains(doc, `<rdf:Description rdf:about=""/>`))
+	is.True(strings.Contains(doc, "/Subtype /TrueType /BaseFont /GoMono "))
+	is.True(strings.Contains(doc, "/BaseFont /GoMono-Bold "))
+	is.True(strings.Contains(doc, "/MissingWidth 600 "))
+	is.True(!strings.Contains(doc, "/Courier"))
+	is.True(strings.Contains(doc, "/Type /EmbeddedFile /Subtype /text#2Fxml /Params << /ModDate (D:20240401093000+00'00') /Size 10 >>"))
+	is.True(strings.Contains(doc, "/AFRelationship /Alternative"))
+	is.True(strings.Contains(doc, "/Names [(invoice.xml) 15 0 R] >> >> /AF [15 0 R]"))
+	is.True(regexp.MustCompile(`/ID \[<[0-9a-f]{32}> <[0-9a-f]{32}>\]`).MatchString(doc))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(doc, -1)
+	is.Equal(len(entries), 15)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		is.True(strings.HasPrefix(doc[offset:], fmt.Sprintf("%d 0 obj", i+1)))
+	}
+}
+
+func TestSRGBProfile(t *testing.T) {
+	is := is.New(t)
+
+	profile := srgbProfile()
+
+	is.Equal(int(binary.BigEndian.Uint32(profile)), len(profile))
+	is.Equal(string(profile[12:24]), "mntrRGB XYZ ")
+	is.Equal(string(profile[36:40]), "acsp")
+	is.Equal(binary.BigEndian.Uint32(profile[128:]), uint32(9))
+}
+
+func TestNewImageNotValid(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewImage(strings.NewReader("no image"))
+	is.True(err != nil)
+}
+
+func TestEscape(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(escape(`a\b`), `a\\b`)
+	is.Equal(escape("Müller"), `M\374ller`)
+	is.Equal(escape("日本"), "??")
+}
+
+func TestTextWidth(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(TextWidth("abc", 10), 18.0)
+	is.Equal(TextWidth("€", 10), 6.0)
+}
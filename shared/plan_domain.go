@@ -0,0 +1,87 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PlanUnlimited = "unlimited"
+	PlanFree      = "free"
+	PlanTeam      = "team"
+	PlanBusiness  = "business"
+
+	PlanLimitUsers    = "users"
+	PlanLimitProjects = "projects"
+)
+
+var (
+	ErrPlanLimitExceeded = NewDomainError("plan:limit-exceeded", http.StatusForbidden, "plan limit exceeded")
+	ErrRateLimitExceeded = NewDomainError("plan:rate-limit-exceeded", http.StatusTooManyRequests, "rate limit exceeded")
+)
+
+// Plan limits the usage of an organization, a limit of 0 is unlimited
+type Plan struct {
+	Name                 string
+	MaxUsers             int
+	MaxProjects          int
+	APIRequestsPerMinute int
+}
+
+// plans are the pricing tiers of the hosted offering
+var plans = map[string]*Plan{
+	PlanUnlimited: {Name: PlanUnlimited},
+	PlanFree:      {Name: PlanFree, MaxUsers: 2, MaxProjects: 3, APIRequestsPerMinute: 60},
+	PlanTeam:      {Name: PlanTeam, MaxUsers: 25, MaxProjects: 50, APIRequestsPerMinute: 300},
+	PlanBusiness:  {Name: PlanBusiness, APIRequestsPerMinute: 1200},
+}
+
+// PlanLimitError is the error of a limit of a plan being exceeded
+type PlanLimitError struct {
+	Plan  string
+	Limit string
+	Max   int
+}
+
+type PlanRepository interface {
+	FindPlanOfOrganization(ctx context.Context, organizationID uuid.UUID) (string, error)
+	UpdatePlanOfOrganization(ctx context.Context, organizationID uuid.UUID, plan string) error
+}
+
+// PlanByName is the plan of the name, unknown plans are unlimited
+func PlanByName(name string) *Plan {
+	plan, ok := plans[name]
+	if !ok {
+		return plans[PlanUnlimited]
+	}
+	return plan
+}
+
+// IsValidPlan checks whether the plan is known
+func IsValidPlan(name string) bool {
+	_, ok := plans[name]
+	return ok
+}
+
+// MaxOf is the maximum of the limit of the plan, 0 if unlimited
+func (p *Plan) MaxOf(limit string) int {
+	switch limit {
+	case PlanLimitUsers:
+		return p.MaxUsers
+	case PlanLimitProjects:
+		return p.MaxProjects
+	default:
+		return 0
+	}
+}
+
+func (e *PlanLimitError) Error() string {
+	return fmt.Sprintf("the %s plan is limited to %d %s", e.Plan, e.Max, e.Limit)
+}
+
+func (e *PlanLimitError) Unwrap() error {
+	return ErrPlanLimitExceeded
+}
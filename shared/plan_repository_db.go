@@ -0,0 +1,63 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbPlanRepository is a SQL database repository for plans of organizations
+type DbPlanRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ PlanRepository = (*DbPlanRepository)(nil)
+
+// NewDbPlanRepository creates a new SQL database repository for plans of organizations
+func NewDbPlanRepository(connPool *pgxpool.Pool) *DbPlanRepository {
+	return &DbPlanRepository{
+		connPool: connPool,
+	}
+}
+
+// FindPlanOfOrganization reads the plan of the organization, empty if the organization has no plan
+func (r *DbPlanRepository) FindPlanOfOrganization(ctx context.Context, organizationID uuid.UUID) (string, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT plan 
+		 FROM organizations 
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+
+	var plan pgtype.Varchar
+	err := row.Scan(&plan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return plan.String, nil
+}
+
+// UpdatePlanOfOrganization sets the plan of the organization, an empty plan is the default plan
+func (r *DbPlanRepository) UpdatePlanOfOrganization(ctx context.Context, organizationID uuid.UUID, plan string) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`UPDATE organizations 
+		 SET plan = $2 
+		 WHERE org_id = $1`,
+		organizationID,
+		sql.NullString{String: plan, Valid: plan != ""},
+	)
+	return err
+}
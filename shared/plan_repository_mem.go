@@ -0,0 +1,41 @@
+package shared
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemPlanRepository struct {
+	mu    sync.Mutex
+	plans map[uuid.UUID]string
+}
+
+var _ PlanRepository = (*InMemPlanRepository)(nil)
+
+func NewInMemPlanRepository() *InMemPlanRepository {
+	return &InMemPlanRepository{
+		plans: make(map[uuid.UUID]string),
+	}
+}
+
+func (r *InMemPlanRepository) FindPlanOfOrganization(ctx context.Context, organizationID uuid.UUID) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.plans[organizationID], nil
+}
+
+func (r *InMemPlanRepository) UpdatePlanOfOrganization(ctx context.Context, organizationID uuid.UUID, plan string) error {
+	r.SetPlanOfOrganization(organizationID, plan)
+	return nil
+}
+
+// SetPlanOfOrganization sets the plan of the organization
+func (r *InMemPlanRepository) SetPlanOfOrganization(organizationID uuid.UUID, plan string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.plans[organizationID] = plan
+}
@@ -0,0 +1,148 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const planCacheExpiry = time.Minute
+
+type cachedPlan struct {
+	plan      *Plan
+	expiresAt time.Time
+}
+
+type rateLimitWindow struct {
+	start    time.Time
+	requests int
+}
+
+// PlanService reads the plans of organizations and enforces their limits
+type PlanService struct {
+	config         *Config
+	planRepository PlanRepository
+
+	mu               sync.Mutex
+	plansByOrg       map[uuid.UUID]*cachedPlan
+	rateLimitWindows map[uuid.UUID]*rateLimitWindow
+}
+
+// NewPlanService creates a new service for the plans of organizations
+func NewPlanService(config *Config, planRepository PlanRepository) *PlanService {
+	return &PlanService{
+		config:           config,
+		planRepository:   planRepository,
+		plansByOrg:       make(map[uuid.UUID]*cachedPlan),
+		rateLimitWindows: make(map[uuid.UUID]*rateLimitWindow),
+	}
+}
+
+// PlanOf reads the plan of the organization, organizations without a plan are on the default plan
+func (s *PlanService) PlanOf(ctx context.Context, organizationID uuid.UUID) (*Plan, error) {
+	s.mu.Lock()
+	cached, ok := s.plansByOrg[organizationID]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.plan, nil
+	}
+
+	planName, err := s.planRepository.FindPlanOfOrganization(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if planName == "" {
+		planName = s.config.DefaultPlan
+	}
+	plan := PlanByName(planName)
+
+	s.mu.Lock()
+	s.plansByOrg[organizationID] = &cachedPlan{plan: plan, expiresAt: time.Now().Add(planCacheExpiry)}
+	s.mu.Unlock()
+
+	return plan, nil
+}
+
+// ChangePlan sets the plan of the organization within the transaction of the context, an empty plan is the default plan
+func (s *PlanService) ChangePlan(ctxWithTx context.Context, organizationID uuid.UUID, plan string) error {
+	err := s.planRepository.UpdatePlanOfOrganization(ctxWithTx, organizationID, plan)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.plansByOrg, organizationID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// CheckLimit checks whether the organization may add one more to the current count of the limit
+func (s *PlanService) CheckLimit(ctx context.Context, organizationID uuid.UUID, limit string, current int) error {
+	plan, err := s.PlanOf(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	max := plan.MaxOf(limit)
+	if max > 0 && current >= max {
+		return &PlanLimitError{
+			Plan:  plan.Name,
+			Limit: limit,
+			Max:   max,
+		}
+	}
+	return nil
+}
+
+// RateLimitMiddleware limits the api requests of an organization per minute to the rate of its plan
+func (s *PlanService) RateLimitMiddleware() func(http.Handler) http.Handler {
+	isProduction := s.config.IsProduction()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+			plan, err := s.PlanOf(r.Context(), principal.OrganizationID)
+			if err != nil {
+				RenderProblemJSON(w, isProduction, err)
+				return
+			}
+
+			retryAfter, allowed := s.allowRequest(principal.OrganizationID, plan, time.Now())
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+				RenderProblemJSON(w, isProduction, ErrRateLimitExceeded)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allowRequest counts the request in the window of the current minute, returning the time until the next window if the rate is exceeded
+func (s *PlanService) allowRequest(organizationID uuid.UUID, plan *Plan, now time.Time) (time.Duration, bool) {
+	if plan.APIRequestsPerMinute == 0 {
+		return 0, true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	window, ok := s.rateLimitWindows[organizationID]
+	if !ok || now.Sub(window.start) >= time.Minute {
+		window = &rateLimitWindow{start: now}
+		s.rateLimitWindows[organizationID] = window
+	}
+
+	if window.requests >= plan.APIRequestsPerMinute {
+		return window.start.Add(time.Minute).Sub(now), false
+	}
+
+	window.requests++
+	return 0, true
+}
@@ -0,0 +1,104 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestPlanOf(t *testing.T) {
+	is := is.New(t)
+
+	planRepository := NewInMemPlanRepository()
+	planService := NewPlanService(&Config{DefaultPlan: PlanFree}, planRepository)
+
+	plan, err := planService.PlanOf(context.Background(), OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(plan.Name, PlanFree)
+
+	planRepository.SetPlanOfOrganization(OrganizationIDSample, PlanTeam)
+	planService = NewPlanService(&Config{DefaultPlan: PlanFree}, planRepository)
+
+	plan, err = planService.PlanOf(context.Background(), OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(plan.Name, PlanTeam)
+}
+
+func TestCheckLimit(t *testing.T) {
+	is := is.New(t)
+
+	planService := NewPlanService(&Config{DefaultPlan: PlanFree}, NewInMemPlanRepository())
+
+	is.NoErr(planService.CheckLimit(context.Background(), OrganizationIDSample, PlanLimitProjects, 2))
+
+	err := planService.CheckLimit(context.Background(), OrganizationIDSample, PlanLimitProjects, 3)
+	is.Equal(err.Error(), "the free plan is limited to 3 projects")
+	is.Equal(DomainErrorOf(err), ErrPlanLimitExceeded)
+
+	unlimitedPlanService := NewPlanService(&Config{DefaultPlan: PlanUnlimited}, NewInMemPlanRepository())
+	is.NoErr(unlimitedPlanService.CheckLimit(context.Background(), OrganizationIDSample, PlanLimitProjects, 1000))
+}
+
+func TestRenderPlanLimitProblem(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	RenderProblemJSON(httpRec, true, &PlanLimitError{Plan: PlanFree, Limit: PlanLimitProjects, Max: 3})
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	var problem map[string]any
+	err := json.NewDecoder(httpRec.Body).Decode(&problem)
+	is.NoErr(err)
+	is.Equal(problem["code"], "plan:limit-exceeded")
+	is.Equal(problem["detail"], "the free plan is limited to 3 projects")
+	is.Equal(problem["limit"], PlanLimitProjects)
+	is.Equal(problem["max"], 3.0)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	planService := NewPlanService(&Config{DefaultPlan: PlanFree}, NewInMemPlanRepository())
+	handler := planService.RateLimitMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func() *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/projects", nil)
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{OrganizationID: OrganizationIDSample}))
+		handler.ServeHTTP(httpRec, r)
+		return httpRec
+	}
+
+	for i := 0; i < PlanByName(PlanFree).APIRequestsPerMinute; i++ {
+		is.Equal(request().Result().StatusCode, http.StatusNoContent)
+	}
+
+	httpRec := request()
+	is.Equal(httpRec.Result().StatusCode, http.StatusTooManyRequests)
+	is.True(httpRec.Header().Get("Retry-After") != "")
+}
+
+func TestAllowRequestInNextWindow(t *testing.T) {
+	is := is.New(t)
+
+	planService := NewPlanService(&Config{}, NewInMemPlanRepository())
+	plan := &Plan{APIRequestsPerMinute: 1}
+	now := time.Now()
+
+	_, allowed := planService.allowRequest(OrganizationIDSample, plan, now)
+	is.True(allowed)
+
+	retryAfter, allowed := planService.allowRequest(OrganizationIDSample, plan, now.Add(10*time.Second))
+	is.True(!allowed)
+	is.Equal(retryAfter, 50*time.Second)
+
+	_, allowed = planService.allowRequest(OrganizationIDSample, plan, now.Add(time.Minute))
+	is.True(allowed)
+}
@@ -0,0 +1,64 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+	ThemeSystem = "system"
+)
+
+var (
+	ErrAppearanceNotFound = NewDomainError("appearance:not-found", http.StatusNotFound, "appearance not found")
+	ErrAppearanceNotValid = NewDomainError("appearance:not-valid", http.StatusBadRequest, "appearance not valid")
+)
+
+var accentColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Appearance is the preference of a user how the web pages look, it follows the user across devices
+type Appearance struct {
+	Theme string
+
+	// AccentColor is a hex color like #5b3cc4, empty for the default color
+	AccentColor string
+
+	// LogoURL is the logo of the organization, empty for the default logo
+	LogoURL string
+}
+
+type PreferenceRepository interface {
+	FindAppearance(ctx context.Context, organizationID uuid.UUID, username string) (*Appearance, error)
+	UpsertAppearance(ctx context.Context, organizationID uuid.UUID, username string, appearance *Appearance) error
+}
+
+// DefaultAppearance is the appearance of users without a preference
+func DefaultAppearance() *Appearance {
+	return &Appearance{
+		Theme: ThemeDark,
+	}
+}
+
+// IsValidTheme checks whether the theme is known
+func IsValidTheme(theme string) bool {
+	return theme == ThemeLight || theme == ThemeDark || theme == ThemeSystem
+}
+
+// IsValidAccentColor checks whether the accent color is empty or a hex color
+func IsValidAccentColor(accentColor string) bool {
+	return accentColor == "" || accentColorPattern.MatchString(accentColor)
+}
+
+// AppearanceOf reads the appearance of the request context, the default appearance if there is none
+func AppearanceOf(ctx context.Context) *Appearance {
+	appearance, ok := ctx.Value(ContextKeyAppearance).(*Appearance)
+	if !ok || appearance == nil {
+		return DefaultAppearance()
+	}
+	return appearance
+}
@@ -0,0 +1,77 @@
+package shared
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbPreferenceRepository is a SQL database repository for the preferences of users
+type DbPreferenceRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ PreferenceRepository = (*DbPreferenceRepository)(nil)
+
+// NewDbPreferenceRepository creates a new SQL database repository for the preferences of users
+func NewDbPreferenceRepository(connPool *pgxpool.Pool) *DbPreferenceRepository {
+	return &DbPreferenceRepository{
+		connPool: connPool,
+	}
+}
+
+// FindAppearance reads the appearance preferred by the user
+func (r *DbPreferenceRepository) FindAppearance(ctx context.Context, organizationID uuid.UUID, username string) (*Appearance, error) {
+	row, err := SelectOne[appearanceRow](
+		ctx,
+		r.connPool,
+		`SELECT `+Columns[appearanceRow]()+`
+		 FROM user_preferences
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAppearanceNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toAppearance(), nil
+}
+
+// UpsertAppearance sets the appearance preferred by the user
+func (r *DbPreferenceRepository) UpsertAppearance(ctx context.Context, organizationID uuid.UUID, username string, appearance *Appearance) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO user_preferences
+		   (org_id, username, theme, accent_color)
+		 VALUES
+		   ($1, $2, $3, $4)
+		 ON CONFLICT (org_id, username) DO UPDATE
+		 SET theme = EXCLUDED.theme, accent_color = EXCLUDED.accent_color`,
+		organizationID,
+		username,
+		appearance.Theme,
+		appearance.AccentColor,
+	)
+	return err
+}
+
+type appearanceRow struct {
+	Theme       string `db:"theme"`
+	AccentColor string `db:"accent_color"`
+}
+
+func (r *appearanceRow) toAppearance() *Appearance {
+	return &Appearance{
+		Theme:       r.Theme,
+		AccentColor: r.AccentColor,
+	}
+}
@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestPreferenceRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	preferenceRepository := NewDbPreferenceRepository(connPool)
+	repositoryTxer := NewDbRepositoryTxer(connPool)
+
+	t.Run("FindAppearanceWithoutPreference", func(t *testing.T) {
+		_, err := preferenceRepository.FindAppearance(context.Background(), OrganizationIDSample, "user1")
+		is.Equal(err, ErrAppearanceNotFound)
+	})
+
+	t.Run("UpsertAppearance", func(t *testing.T) {
+		for _, theme := range []string{ThemeLight, ThemeSystem} {
+			err := repositoryTxer.InTx(
+				context.Background(),
+				func(ctx context.Context) error {
+					return preferenceRepository.UpsertAppearance(ctx, OrganizationIDSample, "user1", &Appearance{
+						Theme:       theme,
+						AccentColor: "#5b3cc4",
+					})
+				},
+			)
+			is.NoErr(err)
+
+			appearance, err := preferenceRepository.FindAppearance(context.Background(), OrganizationIDSample, "user1")
+			is.NoErr(err)
+			is.Equal(appearance.Theme, theme)
+			is.Equal(appearance.AccentColor, "#5b3cc4")
+		}
+	})
+}
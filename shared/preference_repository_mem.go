@@ -0,0 +1,57 @@
+package shared
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type inMemAppearance struct {
+	organizationID uuid.UUID
+	username       string
+	appearance     Appearance
+}
+
+type InMemPreferenceRepository struct {
+	mu          sync.Mutex
+	appearances []*inMemAppearance
+}
+
+var _ PreferenceRepository = (*InMemPreferenceRepository)(nil)
+
+func NewInMemPreferenceRepository() *InMemPreferenceRepository {
+	return &InMemPreferenceRepository{}
+}
+
+func (r *InMemPreferenceRepository) FindAppearance(ctx context.Context, organizationID uuid.UUID, username string) (*Appearance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, a := range r.appearances {
+		if a.organizationID == organizationID && a.username == username {
+			appearance := a.appearance
+			return &appearance, nil
+		}
+	}
+	return nil, ErrAppearanceNotFound
+}
+
+func (r *InMemPreferenceRepository) UpsertAppearance(ctx context.Context, organizationID uuid.UUID, username string, appearance *Appearance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, a := range r.appearances {
+		if a.organizationID == organizationID && a.username == username {
+			a.appearance = *appearance
+			return nil
+		}
+	}
+
+	r.appearances = append(r.appearances, &inMemAppearance{
+		organizationID: organizationID,
+		username:       username,
+		appearance:     *appearance,
+	})
+	return nil
+}
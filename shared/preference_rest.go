@@ -0,0 +1,96 @@
+package shared
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type appearanceModel struct {
+	Theme       string     `json:"theme" validate:"required,oneof=light dark system"`
+	AccentColor string     `json:"accentColor" validate:"omitempty,hexcolor,len=7"`
+	Links       *hal.Links `json:"_links,omitempty"`
+}
+
+type PreferenceRestHandlers struct {
+	config            *Config
+	preferenceService *PreferenceService
+}
+
+func NewPreferenceRestHandlers(config *Config, preferenceService *PreferenceService) *PreferenceRestHandlers {
+	return &PreferenceRestHandlers{
+		config:            config,
+		preferenceService: preferenceService,
+	}
+}
+
+func (a *PreferenceRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/preferences/appearance", a.HandleGetAppearance())
+	r.Put("/preferences/appearance", a.HandleUpdateAppearance())
+}
+
+func (a *PreferenceRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetAppearance reads the appearance preferred by the principal
+func (a *PreferenceRestHandlers) HandleGetAppearance() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	preferenceService := a.preferenceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		appearance, err := preferenceService.ReadAppearance(r.Context(), principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToAppearanceModel(appearance))
+	}
+}
+
+// HandleUpdateAppearance sets the appearance preferred by the principal, it's used by all devices of the principal
+func (a *PreferenceRestHandlers) HandleUpdateAppearance() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := NewValidator()
+	preferenceService := a.preferenceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		var appearanceModel appearanceModel
+		err := json.NewDecoder(r.Body).Decode(&appearanceModel)
+		if err != nil {
+			RenderValidationProblemJSON(w, "appearance not valid", err)
+			return
+		}
+
+		err = validator.Struct(appearanceModel)
+		if err != nil {
+			RenderValidationProblemJSON(w, "appearance not valid", err)
+			return
+		}
+
+		appearance, err := preferenceService.UpdateAppearance(r.Context(), principal, &Appearance{
+			Theme:       appearanceModel.Theme,
+			AccentColor: appearanceModel.AccentColor,
+		})
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		RenderJSON(w, mapToAppearanceModel(appearance))
+	}
+}
+
+func mapToAppearanceModel(appearance *Appearance) *appearanceModel {
+	return &appearanceModel{
+		Theme:       appearance.Theme,
+		AccentColor: appearance.AccentColor,
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/preferences/appearance"),
+		),
+	}
+}
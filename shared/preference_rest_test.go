@@ -0,0 +1,86 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestHandleGetAppearance(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewPreferenceRestHandlers(
+		&Config{},
+		NewPreferenceService(NewInMemRepositoryTxer(), NewInMemPreferenceRepository()),
+	)
+
+	r, _ := http.NewRequest("GET", "/api/preferences/appearance", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		OrganizationID: OrganizationIDSample,
+		Username:       "user1",
+	}))
+
+	a.HandleGetAppearance()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	appearanceModel := &appearanceModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(appearanceModel)
+	is.NoErr(err)
+	is.Equal(appearanceModel.Theme, ThemeDark)
+}
+
+func TestHandleUpdateAppearance(t *testing.T) {
+	is := is.New(t)
+
+	preferenceService := NewPreferenceService(NewInMemRepositoryTxer(), NewInMemPreferenceRepository())
+	a := NewPreferenceRestHandlers(&Config{}, preferenceService)
+	principal := &Principal{
+		OrganizationID: OrganizationIDSample,
+		Username:       "user1",
+	}
+
+	updateAppearance := func(body string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+
+		r, _ := http.NewRequest("PUT", "/api/preferences/appearance", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, principal))
+
+		a.HandleUpdateAppearance()(httpRec, r)
+		return httpRec
+	}
+
+	t.Run("valid appearance", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := updateAppearance(`{"theme": "light", "accentColor": "#5b3cc4"}`)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		appearance, err := preferenceService.ReadAppearance(context.Background(), principal)
+		is.NoErr(err)
+		is.Equal(appearance.Theme, ThemeLight)
+		is.Equal(appearance.AccentColor, "#5b3cc4")
+	})
+
+	t.Run("unknown theme", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := updateAppearance(`{"theme": "blue"}`)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+		is.True(strings.Contains(httpRec.Body.String(), "theme"))
+	})
+
+	t.Run("invalid accent color", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := updateAppearance(`{"theme": "dark", "accentColor": "#fff"}`)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+		is.True(strings.Contains(httpRec.Body.String(), "accentColor"))
+	})
+
+}
@@ -0,0 +1,78 @@
+package shared
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// PreferenceService reads and updates the preferences of users
+type PreferenceService struct {
+	repositoryTxer       RepositoryTxer
+	preferenceRepository PreferenceRepository
+}
+
+// NewPreferenceService creates a new service for the preferences of users
+func NewPreferenceService(repositoryTxer RepositoryTxer, preferenceRepository PreferenceRepository) *PreferenceService {
+	return &PreferenceService{
+		repositoryTxer:       repositoryTxer,
+		preferenceRepository: preferenceRepository,
+	}
+}
+
+// ReadAppearance reads the appearance preferred by the principal, the default appearance if there is no preference
+func (s *PreferenceService) ReadAppearance(ctx context.Context, principal *Principal) (*Appearance, error) {
+	appearance, err := s.preferenceRepository.FindAppearance(ctx, principal.OrganizationID, principal.Username)
+	if errors.Is(err, ErrAppearanceNotFound) {
+		return DefaultAppearance(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return appearance, nil
+}
+
+// UpdateAppearance sets the appearance preferred by the principal
+func (s *PreferenceService) UpdateAppearance(ctx context.Context, principal *Principal, appearance *Appearance) (*Appearance, error) {
+	if !IsValidTheme(appearance.Theme) || !IsValidAccentColor(appearance.AccentColor) {
+		return nil, ErrAppearanceNotValid
+	}
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.preferenceRepository.UpsertAppearance(ctx, principal.OrganizationID, principal.Username, appearance)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return appearance, nil
+}
+
+// AppearanceMiddleware puts the appearance preferred by the principal into the request context,
+// so server rendered pages follow it. Pages fall back to the default appearance if it can't be read.
+func (s *PreferenceService) AppearanceMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := r.Context().Value(ContextKeyPrincipal).(*Principal)
+			if !ok || principal == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			appearance, err := s.ReadAppearance(r.Context(), principal)
+			if err != nil {
+				log.Printf("could not read appearance of %s: %s", principal.Username, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ContextKeyAppearance, appearance)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
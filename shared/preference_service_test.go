@@ -0,0 +1,78 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestReadAppearanceWithoutPreference(t *testing.T) {
+	is := is.New(t)
+
+	preferenceService := NewPreferenceService(NewInMemRepositoryTxer(), NewInMemPreferenceRepository())
+	principal := &Principal{OrganizationID: OrganizationIDSample, Username: "user1"}
+
+	appearance, err := preferenceService.ReadAppearance(context.Background(), principal)
+	is.NoErr(err)
+	is.Equal(appearance, DefaultAppearance())
+}
+
+func TestUpdateAppearance(t *testing.T) {
+	is := is.New(t)
+
+	preferenceService := NewPreferenceService(NewInMemRepositoryTxer(), NewInMemPreferenceRepository())
+	principal := &Principal{OrganizationID: OrganizationIDSample, Username: "user1"}
+
+	_, err := preferenceService.UpdateAppearance(context.Background(), principal, &Appearance{Theme: ThemeLight, AccentColor: "#5b3cc4"})
+	is.NoErr(err)
+
+	appearance, err := preferenceService.ReadAppearance(context.Background(), principal)
+	is.NoErr(err)
+	is.Equal(appearance.Theme, ThemeLight)
+	is.Equal(appearance.AccentColor, "#5b3cc4")
+
+	otherPrincipal := &Principal{OrganizationID: OrganizationIDSample, Username: "user2"}
+	appearance, err = preferenceService.ReadAppearance(context.Background(), otherPrincipal)
+	is.NoErr(err)
+	is.Equal(appearance.Theme, ThemeDark)
+}
+
+func TestUpdateAppearanceNotValid(t *testing.T) {
+	is := is.New(t)
+
+	preferenceService := NewPreferenceService(NewInMemRepositoryTxer(), NewInMemPreferenceRepository())
+	principal := &Principal{OrganizationID: OrganizationIDSample, Username: "user1"}
+
+	_, err := preferenceService.UpdateAppearance(context.Background(), principal, &Appearance{Theme: "blue"})
+	is.Equal(err, ErrAppearanceNotValid)
+
+	_, err = preferenceService.UpdateAppearance(context.Background(), principal, &Appearance{Theme: ThemeDark, AccentColor: "red"})
+	is.Equal(err, ErrAppearanceNotValid)
+}
+
+func TestAppearanceMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	preferenceService := NewPreferenceService(NewInMemRepositoryTxer(), NewInMemPreferenceRepository())
+	principal := &Principal{OrganizationID: OrganizationIDSample, Username: "user1"}
+
+	_, err := preferenceService.UpdateAppearance(context.Background(), principal, &Appearance{Theme: ThemeSystem})
+	is.NoErr(err)
+
+	var appearance *Appearance
+	handler := preferenceService.AppearanceMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		appearance = AppearanceOf(r.Context())
+	}))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, principal))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	is.Equal(appearance.Theme, ThemeSystem)
+
+	r, _ = http.NewRequest("GET", "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	is.Equal(appearance.Theme, ThemeDark)
+}
@@ -0,0 +1,52 @@
+package shared
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Querier runs queries on the connection pool or on a transaction
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Columns lists the columns of the row struct given by the db tags of its fields,
+// so the selected columns always match the fields the rows are mapped to
+func Columns[T any]() string {
+	rowType := reflect.TypeOf((*T)(nil)).Elem()
+
+	columns := make([]string, 0, rowType.NumField())
+	for i := 0; i < rowType.NumField(); i++ {
+		column := rowType.Field(i).Tag.Get("db")
+		if column == "" || column == "-" {
+			continue
+		}
+		columns = append(columns, column)
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+// SelectAll runs the query and maps the columns of all rows by name to the row struct
+func SelectAll[T any](ctx context.Context, q Querier, sql string, args ...any) ([]*T, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[T])
+}
+
+// SelectOne runs the query and maps the columns of the first row by name to the row struct,
+// without rows the error is pgx.ErrNoRows
+func SelectOne[T any](ctx context.Context, q Querier, sql string, args ...any) (*T, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[T])
+}
@@ -0,0 +1,20 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestColumns(t *testing.T) {
+	is := is.New(t)
+
+	type sampleRow struct {
+		ID      string `db:"sample_id"`
+		Title   string `db:"title"`
+		Ignored string `db:"-"`
+		Active  bool   `db:"active"`
+	}
+
+	is.Equal(Columns[sampleRow](), "sample_id, title, active")
+}
@@ -0,0 +1,78 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	ScannerClamAV = "clamav"
+	ScannerICAP   = "icap"
+
+	scanTimeout          = 30 * time.Second
+	quarantineStorageDir = "quarantine/"
+)
+
+var (
+	ErrUploadInfected = NewDomainError("upload:infected", http.StatusUnprocessableEntity, "upload contains malware")
+	ErrScanFailed     = NewDomainError("upload:scan-failed", http.StatusServiceUnavailable, "upload could not be scanned")
+)
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// ScanResult is the verdict of the scanner about an upload
+type ScanResult struct {
+	Infected bool
+	Threat   string
+}
+
+// Scanner scans the content of uploads for malware like a ClamAV daemon or an ICAP server
+type Scanner interface {
+	Scan(ctx context.Context, content io.Reader) (*ScanResult, error)
+}
+
+// Upload is a file uploaded by a user or received from an external service
+type Upload struct {
+	OrganizationID uuid.UUID
+	Username       string
+	FileName       string
+	Content        io.ReadSeeker
+}
+
+// NewScanner creates the scanner of the config, nil if uploads are not scanned
+func NewScanner(config *Config) (Scanner, error) {
+	switch config.Scanner {
+	case "":
+		return nil, nil
+	case ScannerClamAV:
+		if config.ScannerAddress == "" {
+			return nil, errors.Errorf("address is required for scanner %s", ScannerClamAV)
+		}
+		return NewClamAVScanner(config.ScannerAddress), nil
+	case ScannerICAP:
+		if !strings.HasPrefix(config.ScannerAddress, "icap://") {
+			return nil, errors.Errorf("address like icap://localhost:1344/avscan is required for scanner %s", ScannerICAP)
+		}
+		return NewICAPScanner(config.ScannerAddress)
+	default:
+		return nil, errors.Errorf("scanner must be %s or %s", ScannerClamAV, ScannerICAP)
+	}
+}
+
+// quarantineKeyOf is the key of the infected upload in the storage
+func quarantineKeyOf(upload *Upload) string {
+	fileName := path.Base(strings.ReplaceAll(upload.FileName, "\\", "/"))
+	fileName = unsafeFileNameChars.ReplaceAllString(fileName, "_")
+	if fileName == "" || strings.Trim(fileName, ".") == "" {
+		fileName = "upload"
+	}
+	return quarantineStorageDir + uuid.NewString() + "/" + fileName
+}
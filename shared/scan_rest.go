@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type quarantinedUploadModel struct {
+	Key        string `json:"key"`
+	Size       int64  `json:"size"`
+	DetectedAt string `json:"detectedAt"`
+}
+
+type quarantineModel struct {
+	EmbeddedQuarantinedUploads *embeddedQuarantinedUploadsModel `json:"_embedded"`
+	Links                      *hal.Links                       `json:"_links"`
+}
+
+type embeddedQuarantinedUploadsModel struct {
+	QuarantinedUploads []*quarantinedUploadModel `json:"uploads"`
+}
+
+type ScanRestHandlers struct {
+	config      *Config
+	scanService *ScanService
+}
+
+func NewScanRestHandlers(config *Config, scanService *ScanService) *ScanRestHandlers {
+	return &ScanRestHandlers{
+		config:      config,
+		scanService: scanService,
+	}
+}
+
+func (a *ScanRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/instance/quarantine", a.HandleGetQuarantine())
+	r.Delete("/instance/quarantine", a.HandleDeleteQuarantinedUpload())
+}
+
+func (a *ScanRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetQuarantine reads the infected uploads in the quarantine
+func (a *ScanRestHandlers) HandleGetQuarantine() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	scanService := a.scanService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		quarantinedUploads, err := scanService.ReadQuarantine(r.Context(), principal)
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		quarantinedUploadModels := make([]*quarantinedUploadModel, len(quarantinedUploads))
+		for i, quarantinedUpload := range quarantinedUploads {
+			quarantinedUploadModels[i] = &quarantinedUploadModel{
+				Key:        quarantinedUpload.Key,
+				Size:       quarantinedUpload.Size,
+				DetectedAt: quarantinedUpload.LastModified.Format(time.RFC3339),
+			}
+		}
+
+		RenderJSON(w, &quarantineModel{
+			EmbeddedQuarantinedUploads: &embeddedQuarantinedUploadsModel{
+				QuarantinedUploads: quarantinedUploadModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleDeleteQuarantinedUpload deletes the infected upload of the query parameter key from the quarantine
+func (a *ScanRestHandlers) HandleDeleteQuarantinedUpload() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	scanService := a.scanService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		err := scanService.DeleteQuarantinedUpload(r.Context(), principal, r.URL.Query().Get("key"))
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,66 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestHandleGetAndDeleteQuarantine(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	scanService := newTestScanService(nil)
+	a := NewScanRestHandlers(&Config{InstanceAdmins: "ops@baralga.com"}, scanService)
+
+	_ = scanService.ScanUpload(context.Background(), &Upload{
+		OrganizationID: OrganizationIDSample,
+		Username:       "user1@baralga.com",
+		FileName:       "eicar.com",
+		Content:        strings.NewReader(eicarSignature),
+	})
+
+	r := httptest.NewRequest("GET", "/api/instance/quarantine", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+	a.HandleGetQuarantine()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	var quarantine quarantineModel
+	err := json.NewDecoder(httpRec.Body).Decode(&quarantine)
+	is.NoErr(err)
+	is.Equal(len(quarantine.EmbeddedQuarantinedUploads.QuarantinedUploads), 1)
+
+	t.Run("HandleDeleteQuarantinedUpload", func(t *testing.T) {
+		deleteRec := httptest.NewRecorder()
+		key := quarantine.EmbeddedQuarantinedUploads.QuarantinedUploads[0].Key
+		r := httptest.NewRequest("DELETE", "/api/instance/quarantine?key="+url.QueryEscape(key), nil)
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, newInstanceAdminPrincipal()))
+
+		a.HandleDeleteQuarantinedUpload()(deleteRec, r)
+		is.Equal(deleteRec.Result().StatusCode, http.StatusNoContent)
+	})
+}
+
+func TestHandleGetQuarantineAsOrgAdmin(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewScanRestHandlers(&Config{InstanceAdmins: "ops@baralga.com"}, newTestScanService(nil))
+
+	r := httptest.NewRequest("GET", "/api/instance/quarantine", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+		Username:       "admin",
+		OrganizationID: OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleGetQuarantine()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
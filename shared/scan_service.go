@@ -0,0 +1,172 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ScanService scans uploads for malware before they are processed. Infected uploads are moved
+// to the quarantine in the storage and the operators of the instance are notified.
+type ScanService struct {
+	config         *Config
+	repositoryTxer RepositoryTxer
+	outbox         Outbox
+	storage        Storage
+	scanner        Scanner
+}
+
+// NewScanService creates a new service to scan uploads, uploads are not scanned if the scanner is nil
+func NewScanService(config *Config, repositoryTxer RepositoryTxer, outbox Outbox, storage Storage, scanner Scanner) *ScanService {
+	return &ScanService{
+		config:         config,
+		repositoryTxer: repositoryTxer,
+		outbox:         outbox,
+		storage:        storage,
+		scanner:        scanner,
+	}
+}
+
+// ScanUpload scans the upload and fails with ErrUploadInfected if malware is found. The content
+// of the upload is rewound afterwards, so the caller processes it from the start.
+func (s *ScanService) ScanUpload(ctx context.Context, upload *Upload) error {
+	if s.scanner == nil {
+		return nil
+	}
+
+	result, err := s.scanner.Scan(ctx, upload.Content)
+	if err != nil {
+		return err
+	}
+
+	_, err = upload.Content.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	if !result.Infected {
+		return nil
+	}
+
+	quarantineKey, err := s.quarantine(ctx, upload)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("upload %s of %s infected with %s moved to quarantine %s", upload.FileName, upload.Username, result.Threat, quarantineKey)
+
+	err = s.notify(ctx, upload, result, quarantineKey)
+	if err != nil {
+		log.Printf("could not notify about infected upload %s: %v", quarantineKey, err)
+	}
+
+	return errors.Wrap(ErrUploadInfected, result.Threat)
+}
+
+// ReadQuarantine reads the infected uploads in the quarantine
+func (s *ScanService) ReadQuarantine(ctx context.Context, principal *Principal) ([]*StorageObject, error) {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storage.List(ctx, quarantineStorageDir)
+}
+
+// DeleteQuarantinedUpload deletes an infected upload from the quarantine
+func (s *ScanService) DeleteQuarantinedUpload(ctx context.Context, principal *Principal, key string) error {
+	err := AuthorizeInstanceAdmin(s.config, principal)
+	if err != nil {
+		return err
+	}
+
+	if err := validateStorageKey(key); err != nil || !strings.HasPrefix(key, quarantineStorageDir) {
+		return ErrStorageObjectNotFound
+	}
+
+	return s.storage.Delete(ctx, key)
+}
+
+func (s *ScanService) quarantine(ctx context.Context, upload *Upload) (string, error) {
+	size, err := upload.Content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", err
+	}
+	_, err = upload.Content.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	quarantineKey := quarantineKeyOf(upload)
+	err = s.storage.Put(ctx, quarantineKey, upload.Content, size, "application/octet-stream")
+	if err != nil {
+		return "", err
+	}
+
+	return quarantineKey, nil
+}
+
+func (s *ScanService) notify(ctx context.Context, upload *Upload, result *ScanResult, quarantineKey string) error {
+	subject := "Infected upload moved to quarantine"
+	body := fmt.Sprintf(
+		`The upload %v of %v in organization %v contains %v. It was rejected and moved to the quarantine as %v.`,
+		upload.FileName,
+		upload.Username,
+		upload.OrganizationID,
+		result.Threat,
+		quarantineKey,
+	)
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			for _, instanceAdmin := range s.config.InstanceAdminUsernames() {
+				err := s.outbox.SendMail(ctx, upload.OrganizationID, instanceAdmin, subject, body)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
+
+// BufferedUpload is the content of an upload buffered in a temporary file, which is removed on close
+type BufferedUpload struct {
+	*os.File
+}
+
+// BufferUpload buffers the streamed content of an upload in a temporary file, so that it is read
+// once for the scan and once more for processing
+func BufferUpload(content io.Reader) (*BufferedUpload, error) {
+	tempFile, err := os.CreateTemp("", "baralga-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	bufferedUpload := &BufferedUpload{File: tempFile}
+
+	_, err = io.Copy(tempFile, content)
+	if err != nil {
+		bufferedUpload.Close()
+		return nil, err
+	}
+
+	_, err = tempFile.Seek(0, io.SeekStart)
+	if err != nil {
+		bufferedUpload.Close()
+		return nil, err
+	}
+
+	return bufferedUpload, nil
+}
+
+func (u *BufferedUpload) Close() error {
+	err := u.File.Close()
+	os.Remove(u.File.Name())
+	return err
+}
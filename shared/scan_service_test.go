@@ -0,0 +1,132 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+func newTestScanService(mailResource *InMemMailResource) *ScanService {
+	if mailResource == nil {
+		mailResource = NewInMemMailResource()
+	}
+	return NewScanService(
+		&Config{InstanceAdmins: "ops@baralga.com, support@baralga.com"},
+		NewInMemRepositoryTxer(),
+		NewInMemOutbox(mailResource),
+		NewInMemStorage(),
+		NewInMemScanner(),
+	)
+}
+
+func TestScanUpload(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := NewInMemMailResource()
+	scanService := newTestScanService(mailResource)
+	ctx := context.Background()
+
+	t.Run("Clean", func(t *testing.T) {
+		content := strings.NewReader("Date;Start")
+		err := scanService.ScanUpload(ctx, &Upload{
+			OrganizationID: OrganizationIDSample,
+			Username:       "user1@baralga.com",
+			FileName:       "activities.csv",
+			Content:        content,
+		})
+		is.NoErr(err)
+
+		// content is rewound for processing
+		buf, err := io.ReadAll(content)
+		is.NoErr(err)
+		is.Equal(string(buf), "Date;Start")
+	})
+
+	t.Run("Infected", func(t *testing.T) {
+		err := scanService.ScanUpload(ctx, &Upload{
+			OrganizationID: OrganizationIDSample,
+			Username:       "user1@baralga.com",
+			FileName:       "../invoice.pdf",
+			Content:        strings.NewReader(eicarSignature),
+		})
+		is.True(errors.Is(err, ErrUploadInfected))
+
+		quarantine, err := scanService.ReadQuarantine(ctx, newInstanceAdminPrincipal())
+		is.NoErr(err)
+		is.Equal(len(quarantine), 1)
+		is.True(strings.HasPrefix(quarantine[0].Key, "quarantine/"))
+		is.True(strings.HasSuffix(quarantine[0].Key, "/invoice.pdf"))
+
+		is.Equal(len(mailResource.Mails), 2)
+		is.True(strings.Contains(mailResource.Mails[0], "Eicar-Signature"))
+
+		err = scanService.DeleteQuarantinedUpload(ctx, newInstanceAdminPrincipal(), quarantine[0].Key)
+		is.NoErr(err)
+
+		quarantine, err = scanService.ReadQuarantine(ctx, newInstanceAdminPrincipal())
+		is.NoErr(err)
+		is.Equal(len(quarantine), 0)
+	})
+
+	t.Run("DeleteOutsideOfQuarantine", func(t *testing.T) {
+		err := scanService.DeleteQuarantinedUpload(ctx, newInstanceAdminPrincipal(), "backups/baralga-backup.tar.gz")
+		is.Equal(err, ErrStorageObjectNotFound)
+	})
+
+	t.Run("ReadQuarantineAsOrgAdmin", func(t *testing.T) {
+		_, err := scanService.ReadQuarantine(ctx, &Principal{Username: "admin", Roles: []string{"ROLE_ADMIN"}})
+		is.Equal(err, ErrForbidden)
+	})
+}
+
+func TestScanUploadWithoutScanner(t *testing.T) {
+	is := is.New(t)
+
+	scanService := NewScanService(&Config{}, NewInMemRepositoryTxer(), NewInMemOutbox(NewInMemMailResource()), NewInMemStorage(), nil)
+
+	err := scanService.ScanUpload(context.Background(), &Upload{
+		FileName: "eicar.txt",
+		Content:  strings.NewReader(eicarSignature),
+	})
+	is.NoErr(err)
+}
+
+func TestBufferUpload(t *testing.T) {
+	is := is.New(t)
+
+	bufferedUpload, err := BufferUpload(strings.NewReader("Date;Start"))
+	is.NoErr(err)
+
+	buf, err := io.ReadAll(bufferedUpload)
+	is.NoErr(err)
+	is.Equal(string(buf), "Date;Start")
+
+	is.NoErr(bufferedUpload.Close())
+}
+
+func TestNewScanner(t *testing.T) {
+	is := is.New(t)
+
+	scanner, err := NewScanner(&Config{})
+	is.NoErr(err)
+	is.True(scanner == nil)
+
+	scanner, err = NewScanner(&Config{Scanner: ScannerClamAV, ScannerAddress: "localhost:3310"})
+	is.NoErr(err)
+	_, ok := scanner.(*ClamAVScanner)
+	is.True(ok)
+
+	scanner, err = NewScanner(&Config{Scanner: ScannerICAP, ScannerAddress: "icap://localhost/avscan"})
+	is.NoErr(err)
+	is.Equal(scanner.(*ICAPScanner).serviceURL.Host, "localhost:1344")
+
+	_, err = NewScanner(&Config{Scanner: ScannerICAP, ScannerAddress: "localhost:1344"})
+	is.True(err != nil)
+
+	_, err = NewScanner(&Config{Scanner: "virustotal"})
+	is.True(err != nil)
+}
@@ -0,0 +1,101 @@
+package shared
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const clamAVChunkSize = 64 << 10
+
+// ClamAVScanner scans uploads with a ClamAV daemon using the INSTREAM command of clamd
+type ClamAVScanner struct {
+	network string
+	address string
+}
+
+var _ Scanner = (*ClamAVScanner)(nil)
+
+// NewClamAVScanner creates a scanner for the clamd at the address like localhost:3310 or unix:/run/clamav/clamd.sock
+func NewClamAVScanner(address string) *ClamAVScanner {
+	if socket, ok := strings.CutPrefix(address, "unix:"); ok {
+		return &ClamAVScanner{network: "unix", address: socket}
+	}
+	return &ClamAVScanner{network: "tcp", address: address}
+}
+
+// Scan streams the content in chunks prefixed by their length to clamd, which replies with OK or the found threat
+func (s *ClamAVScanner) Scan(ctx context.Context, content io.Reader) (*ScanResult, error) {
+	dialer := &net.Dialer{Timeout: scanTimeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, errors.Wrap(ErrScanFailed, err.Error())
+	}
+	defer conn.Close()
+
+	err = conn.SetDeadline(time.Now().Add(scanTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = conn.Write([]byte("zINSTREAM\x00"))
+	if err != nil {
+		return nil, errors.Wrap(ErrScanFailed, err.Error())
+	}
+
+	chunk := make([]byte, clamAVChunkSize)
+	length := make([]byte, 4)
+	for {
+		n, err := content.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(length, uint32(n))
+			_, writeErr := conn.Write(append(length, chunk[:n]...))
+			if writeErr != nil {
+				return nil, errors.Wrap(ErrScanFailed, writeErr.Error())
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// a chunk of length zero ends the stream
+	_, err = conn.Write([]byte{0, 0, 0, 0})
+	if err != nil {
+		return nil, errors.Wrap(ErrScanFailed, err.Error())
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(ErrScanFailed, err.Error())
+	}
+
+	return parseClamAVReply(reply)
+}
+
+// parseClamAVReply parses replies like "stream: OK" or "stream: Eicar-Signature FOUND"
+func parseClamAVReply(reply string) (*ScanResult, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	verdict := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+
+	switch {
+	case verdict == "OK":
+		return &ScanResult{}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return &ScanResult{
+			Infected: true,
+			Threat:   strings.TrimSuffix(verdict, " FOUND"),
+		}, nil
+	default:
+		return nil, errors.Wrapf(ErrScanFailed, "clamd replied %s", reply)
+	}
+}
@@ -0,0 +1,120 @@
+package shared
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const icapDefaultPort = "1344"
+
+// ICAPScanner scans uploads with an ICAP server like c-icap with the antivirus service of ClamAV
+type ICAPScanner struct {
+	serviceURL *url.URL
+}
+
+var _ Scanner = (*ICAPScanner)(nil)
+
+// NewICAPScanner creates a scanner for the service of the ICAP server like icap://localhost:1344/avscan
+func NewICAPScanner(serviceURL string) (*ICAPScanner, error) {
+	parsedURL, err := url.Parse(serviceURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsedURL.Port() == "" {
+		parsedURL.Host = net.JoinHostPort(parsedURL.Hostname(), icapDefaultPort)
+	}
+	return &ICAPScanner{serviceURL: parsedURL}, nil
+}
+
+// Scan sends the content as body of a response modification, the server answers 204 if the content is clean
+func (s *ICAPScanner) Scan(ctx context.Context, content io.Reader) (*ScanResult, error) {
+	dialer := &net.Dialer{Timeout: scanTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.serviceURL.Host)
+	if err != nil {
+		return nil, errors.Wrap(ErrScanFailed, err.Error())
+	}
+	defer conn.Close()
+
+	err = conn.SetDeadline(time.Now().Add(scanTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	httpHeader := "HTTP/1.1 200 OK\r\nContent-Type: application/octet-stream\r\n\r\n"
+	writer := bufio.NewWriter(conn)
+	fmt.Fprintf(writer, "RESPMOD %s ICAP/1.0\r\n", s.serviceURL.String())
+	fmt.Fprintf(writer, "Host: %s\r\n", s.serviceURL.Hostname())
+	fmt.Fprintf(writer, "Allow: 204\r\n")
+	fmt.Fprintf(writer, "Encapsulated: res-hdr=0, res-body=%d\r\n\r\n", len(httpHeader))
+	writer.WriteString(httpHeader)
+
+	chunk := make([]byte, 64<<10)
+	for {
+		n, err := content.Read(chunk)
+		if n > 0 {
+			fmt.Fprintf(writer, "%x\r\n", n)
+			writer.Write(chunk[:n])
+			writer.WriteString("\r\n")
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	writer.WriteString("0\r\n\r\n")
+
+	err = writer.Flush()
+	if err != nil {
+		return nil, errors.Wrap(ErrScanFailed, err.Error())
+	}
+
+	reader := textproto.NewReader(bufio.NewReader(conn))
+	statusLine, err := reader.ReadLine()
+	if err != nil {
+		return nil, errors.Wrap(ErrScanFailed, err.Error())
+	}
+	header, err := reader.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(ErrScanFailed, err.Error())
+	}
+
+	return parseICAPResponse(statusLine, header)
+}
+
+// parseICAPResponse interprets the status and the headers of the ICAP response, servers name the threat
+// in the header X-Infection-Found like "Type=0; Resolution=2; Threat=Eicar-Signature;"
+func parseICAPResponse(statusLine string, header textproto.MIMEHeader) (*ScanResult, error) {
+	fields := strings.Fields(statusLine)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "ICAP/") {
+		return nil, errors.Wrapf(ErrScanFailed, "icap server replied %s", statusLine)
+	}
+
+	switch fields[1] {
+	case "204":
+		return &ScanResult{}, nil
+	case "200":
+		threat := "unknown"
+		for _, part := range strings.Split(header.Get("X-Infection-Found"), ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(part), "Threat="); ok {
+				threat = value
+			}
+		}
+		return &ScanResult{
+			Infected: true,
+			Threat:   threat,
+		}, nil
+	default:
+		return nil, errors.Wrapf(ErrScanFailed, "icap server replied %s", statusLine)
+	}
+}
@@ -0,0 +1,34 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// eicarSignature is the start of the EICAR test file, which all scanners detect as malware
+const eicarSignature = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!`
+
+// InMemScanner detects the EICAR test file like a real scanner
+type InMemScanner struct{}
+
+var _ Scanner = (*InMemScanner)(nil)
+
+func NewInMemScanner() *InMemScanner {
+	return &InMemScanner{}
+}
+
+func (s *InMemScanner) Scan(ctx context.Context, content io.Reader) (*ScanResult, error) {
+	buf, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(string(buf), eicarSignature) {
+		return &ScanResult{
+			Infected: true,
+			Threat:   "Eicar-Signature",
+		}, nil
+	}
+	return &ScanResult{}, nil
+}
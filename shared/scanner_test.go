@@ -0,0 +1,154 @@
+package shared
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+func TestClamAVScanner(t *testing.T) {
+	is := is.New(t)
+
+	// fake clamd which reads the stream and replies like clamd
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	is.NoErr(err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			reader := bufio.NewReader(conn)
+			command, _ := reader.ReadString(0)
+			content := &strings.Builder{}
+			for command == "zINSTREAM\x00" {
+				length := make([]byte, 4)
+				_, err := io.ReadFull(reader, length)
+				if err != nil || binary.BigEndian.Uint32(length) == 0 {
+					break
+				}
+				_, _ = io.CopyN(content, reader, int64(binary.BigEndian.Uint32(length)))
+			}
+
+			if strings.Contains(content.String(), eicarSignature) {
+				_, _ = conn.Write([]byte("stream: Eicar-Signature FOUND\x00"))
+			} else {
+				_, _ = conn.Write([]byte("stream: OK\x00"))
+			}
+			conn.Close()
+		}
+	}()
+
+	scanner := NewClamAVScanner(listener.Addr().String())
+
+	result, err := scanner.Scan(context.Background(), strings.NewReader("Date;Start"))
+	is.NoErr(err)
+	is.True(!result.Infected)
+
+	result, err = scanner.Scan(context.Background(), strings.NewReader(eicarSignature))
+	is.NoErr(err)
+	is.True(result.Infected)
+	is.Equal(result.Threat, "Eicar-Signature")
+}
+
+func TestClamAVScannerNotReachable(t *testing.T) {
+	is := is.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	is.NoErr(err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	_, err = NewClamAVScanner(address).Scan(context.Background(), strings.NewReader("Date;Start"))
+	is.True(errors.Is(err, ErrScanFailed))
+}
+
+func TestParseClamAVReply(t *testing.T) {
+	is := is.New(t)
+
+	result, err := parseClamAVReply("stream: OK\x00")
+	is.NoErr(err)
+	is.True(!result.Infected)
+
+	result, err = parseClamAVReply("stream: Win.Test.EICAR_HDB-1 FOUND\x00")
+	is.NoErr(err)
+	is.Equal(result.Threat, "Win.Test.EICAR_HDB-1")
+
+	_, err = parseClamAVReply("INSTREAM size limit exceeded. ERROR\x00")
+	is.True(errors.Is(err, ErrScanFailed))
+}
+
+func TestICAPScanner(t *testing.T) {
+	is := is.New(t)
+
+	// fake ICAP server which reads the request and replies like c-icap
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	is.NoErr(err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			reader := textproto.NewReader(bufio.NewReader(conn))
+			requestLine, _ := reader.ReadLine()
+			_, _ = reader.ReadMIMEHeader()
+			_, _ = reader.ReadMIMEHeader()
+			body := &strings.Builder{}
+			for strings.HasPrefix(requestLine, "RESPMOD icap://") {
+				line, err := reader.ReadLine()
+				if err != nil || line == "0" {
+					break
+				}
+				chunk, _ := reader.ReadLine()
+				body.WriteString(chunk)
+			}
+
+			if strings.Contains(body.String(), eicarSignature) {
+				_, _ = conn.Write([]byte("ICAP/1.0 200 OK\r\nX-Infection-Found: Type=0; Resolution=2; Threat=Eicar-Signature;\r\n\r\n"))
+			} else {
+				_, _ = conn.Write([]byte("ICAP/1.0 204 No Content\r\n\r\n"))
+			}
+			conn.Close()
+		}
+	}()
+
+	scanner, err := NewICAPScanner("icap://" + listener.Addr().String() + "/avscan")
+	is.NoErr(err)
+
+	result, err := scanner.Scan(context.Background(), strings.NewReader("Date;Start"))
+	is.NoErr(err)
+	is.True(!result.Infected)
+
+	result, err = scanner.Scan(context.Background(), strings.NewReader(eicarSignature))
+	is.NoErr(err)
+	is.True(result.Infected)
+	is.Equal(result.Threat, "Eicar-Signature")
+}
+
+func TestParseICAPResponse(t *testing.T) {
+	is := is.New(t)
+
+	result, err := parseICAPResponse("ICAP/1.0 200 OK", textproto.MIMEHeader{})
+	is.NoErr(err)
+	is.True(result.Infected)
+	is.Equal(result.Threat, "unknown")
+
+	_, err = parseICAPResponse("ICAP/1.0 500 Server Error", textproto.MIMEHeader{})
+	is.True(errors.Is(err, ErrScanFailed))
+
+	_, err = parseICAPResponse("HTTP/1.1 200 OK", textproto.MIMEHeader{})
+	is.True(errors.Is(err, ErrScanFailed))
+}
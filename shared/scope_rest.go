@@ -0,0 +1,28 @@
+package shared
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ServiceAccountScopeMiddleware restricts service accounts to the api resources of their scopes,
+// the resource of a request is the first segment of its path like activities of /activities/{id}
+func ServiceAccountScopeMiddleware(config *Config) func(next http.Handler) http.Handler {
+	isProduction := config.IsProduction()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := r.Context().Value(ContextKeyPrincipal).(*Principal)
+			if !ok || principal.HasScope(resourceOf(routePathOf(r)), isChangingRequest(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			RenderProblemJSON(w, isProduction, ErrForbidden)
+		})
+	}
+}
+
+func resourceOf(path string) string {
+	resource, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return resource
+}
@@ -0,0 +1,54 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestServiceAccountScopeMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	router := chi.NewRouter()
+	router.Mount("/api", func() http.Handler {
+		r := chi.NewRouter()
+		r.Use(ServiceAccountScopeMiddleware(&Config{}))
+		r.Get("/activities", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		r.Post("/activities", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		r.Get("/projects/{project-id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		return r
+	}())
+
+	request := func(method, path string, serviceAccountID uuid.UUID, scopes ...string) int {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest(method, path, nil)
+		r = r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+			OrganizationID:   OrganizationIDSample,
+			Roles:            []string{"ROLE_USER"},
+			ServiceAccountID: serviceAccountID,
+			Scopes:           scopes,
+		}))
+		router.ServeHTTP(httpRec, r)
+		return httpRec.Result().StatusCode
+	}
+
+	serviceAccountID := uuid.New()
+	is.Equal(request("POST", "/api/activities", uuid.Nil), http.StatusNoContent)
+	is.Equal(request("GET", "/api/activities", serviceAccountID, "activities:read"), http.StatusNoContent)
+	is.Equal(request("POST", "/api/activities", serviceAccountID, "activities:read"), http.StatusForbidden)
+	is.Equal(request("POST", "/api/activities", serviceAccountID, "activities:write"), http.StatusNoContent)
+	is.Equal(request("GET", "/api/activities", serviceAccountID, "activities:write"), http.StatusNoContent)
+	is.Equal(request("GET", "/api/projects/"+uuid.NewString(), serviceAccountID, "activities:write"), http.StatusForbidden)
+	is.Equal(request("GET", "/api/projects/"+uuid.NewString(), serviceAccountID, "projects:read"), http.StatusNoContent)
+}
@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"net/http"
+
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+	"github.com/unrolled/secure"
+)
+
+// SecurityHeadersMiddleware sets the Content-Security-Policy, Strict-Transport-Security,
+// X-Frame-Options and Referrer-Policy headers as configured. A $NONCE in the policy is
+// replaced by a nonce per request which inline scripts need to carry, see InlineScript.
+// Strict-Transport-Security is only sent in production.
+func SecurityHeadersMiddleware(config *Config) func(http.Handler) http.Handler {
+	options := secure.Options{
+		HostsProxyHeaders:       []string{"X-Forwarded-Host"},
+		SSLProxyHeaders:         map[string]string{"X-Forwarded-Proto": "https"},
+		ForceSTSHeader:          true,
+		IsDevelopment:           !config.IsProduction(),
+		STSSeconds:              config.HSTSSeconds,
+		STSIncludeSubdomains:    true,
+		STSPreload:              true,
+		CustomFrameOptionsValue: config.FrameOptions,
+		ContentTypeNosniff:      true,
+		BrowserXssFilter:        true,
+		ReferrerPolicy:          config.ReferrerPolicy,
+		PermissionsPolicy:       "fullscreen=*",
+	}
+
+	if config.ContentSecurityPolicyReportOnly {
+		options.ContentSecurityPolicyReportOnly = config.ContentSecurityPolicy
+	} else {
+		options.ContentSecurityPolicy = config.ContentSecurityPolicy
+	}
+
+	return secure.New(options).Handler
+}
+
+// CSPNonce is the nonce of the Content-Security-Policy of the request
+func CSPNonce(r *http.Request) string {
+	return secure.CSPNonce(r.Context())
+}
+
+// InlineScript renders an inline script allowed by the nonce of the Content-Security-Policy
+func InlineScript(r *http.Request, script string) g.Node {
+	return Script(
+		g.If(CSPNonce(r) != "", g.Attr("nonce", CSPNonce(r))),
+		g.Raw(script),
+	)
+}
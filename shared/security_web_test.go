@@ -0,0 +1,62 @@
+package shared
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	var script strings.Builder
+	handler := func(config *Config) http.Handler {
+		return SecurityHeadersMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = InlineScript(r, "console.log('baralga')").Render(&script)
+		}))
+	}
+
+	t.Run("production", func(t *testing.T) {
+		script.Reset()
+		config, err := loadConfig(lookupEnvOf(map[string]string{"BARALGA_ENV": "production"}))
+		is.NoErr(err)
+
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "https://baralga.example.com/", nil)
+		handler(config).ServeHTTP(httpRec, r)
+
+		headers := httpRec.Result().Header
+		is.Equal(headers.Get("Strict-Transport-Security"), "max-age=31536000; includeSubDomains; preload")
+		is.Equal(headers.Get("X-Frame-Options"), "DENY")
+		is.Equal(headers.Get("Referrer-Policy"), "same-origin")
+
+		csp := headers.Get("Content-Security-Policy")
+		is.True(strings.Contains(csp, "script-src 'self' 'nonce-"))
+		is.True(!strings.Contains(csp, "$NONCE"))
+
+		nonce := strings.Split(strings.Split(csp, "'nonce-")[1], "'")[0]
+		is.Equal(script.String(), `<script nonce="`+nonce+`">console.log('baralga')</script>`)
+	})
+
+	t.Run("development with report only policy", func(t *testing.T) {
+		script.Reset()
+		config, err := loadConfig(lookupEnvOf(map[string]string{
+			"BARALGA_CONTENTSECURITYPOLICYREPORTONLY": "true",
+			"BARALGA_FRAMEOPTIONS":                    "SAMEORIGIN",
+		}))
+		is.NoErr(err)
+
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "http://localhost:8080/", nil)
+		handler(config).ServeHTTP(httpRec, r)
+
+		headers := httpRec.Result().Header
+		is.Equal(headers.Get("Strict-Transport-Security"), "")
+		is.Equal(headers.Get("X-Frame-Options"), "SAMEORIGIN")
+		is.Equal(headers.Get("Content-Security-Policy"), "")
+		is.True(strings.Contains(headers.Get("Content-Security-Policy-Report-Only"), "'nonce-"))
+	})
+}
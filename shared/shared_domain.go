@@ -2,6 +2,7 @@ package shared
 
 import (
 	"context"
+	"sync"
 
 	"github.com/google/uuid"
 )
@@ -9,8 +10,13 @@ import (
 type contextKey int
 
 const (
-	ContextKeyPrincipal contextKey = 0
-	ContextKeyTx        contextKey = 1
+	ContextKeyPrincipal    contextKey = 0
+	ContextKeyTx           contextKey = 1
+	ContextKeyAPIVersion   contextKey = 2
+	ContextKeyOrganization contextKey = 3
+	ContextKeyAppearance   contextKey = 4
+	ContextKeyCustomDomain contextKey = 5
+	ContextKeyAfterCommit  contextKey = 6
 )
 
 type Principal struct {
@@ -18,6 +24,14 @@ type Principal struct {
 	Username       string
 	OrganizationID uuid.UUID
 	Roles          []string
+
+	// ImpersonatedBy is the instance admin acting as the user in a support session
+	ImpersonatedBy string
+
+	// ServiceAccountID is the service account of an integration authenticated by its api token,
+	// it's restricted to the api resources of its scopes
+	ServiceAccountID uuid.UUID
+	Scopes           []string
 }
 
 func (p *Principal) HasRole(role string) bool {
@@ -29,10 +43,85 @@ func (p *Principal) HasRole(role string) bool {
 	return false
 }
 
+// IsImpersonated checks whether an instance admin acts as the user in a support session
+func (p *Principal) IsImpersonated() bool {
+	return p.ImpersonatedBy != ""
+}
+
+// IsServiceAccount checks whether the principal is a service account of an integration instead of a user
+func (p *Principal) IsServiceAccount() bool {
+	return p.ServiceAccountID != uuid.Nil
+}
+
+// HasScope checks whether the principal may access the api resource, users may access all resources
+// and service accounts the resources of their scopes. The write scope of a resource includes reading it.
+func (p *Principal) HasScope(resource string, write bool) bool {
+	if !p.IsServiceAccount() {
+		return true
+	}
+
+	for _, scope := range p.Scopes {
+		if scope == resource+":write" || (!write && scope == resource+":read") {
+			return true
+		}
+	}
+	return false
+}
+
 type RepositoryTxer interface {
 	InTx(ctx context.Context, txFuncs ...func(ctxWithTx context.Context) error) error
 }
 
+// afterCommitHooks are run once the transaction committed, hooks with the same key run only once
+type afterCommitHooks struct {
+	mu    sync.Mutex
+	keys  map[string]bool
+	hooks []func()
+}
+
+// AfterCommit runs the hook after the transaction of the context committed, it's not run on rollback.
+// Hooks with the same key are run once per transaction. Outside of a transaction the hook runs at once.
+func AfterCommit(ctx context.Context, key string, hook func()) {
+	hooks, ok := ctx.Value(ContextKeyAfterCommit).(*afterCommitHooks)
+	if !ok {
+		hook()
+		return
+	}
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+
+	if hooks.keys[key] {
+		return
+	}
+	hooks.keys[key] = true
+	hooks.hooks = append(hooks.hooks, hook)
+}
+
+func withAfterCommitHooks(ctx context.Context) (context.Context, *afterCommitHooks) {
+	hooks := &afterCommitHooks{keys: make(map[string]bool)}
+	return context.WithValue(ctx, ContextKeyAfterCommit, hooks), hooks
+}
+
+func (h *afterCommitHooks) run() {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
 type MailResource interface {
 	SendMail(to, subject, body string) error
 }
+
+// Outbox records side effects in the transaction of the triggering write and delivers them after commit
+type Outbox interface {
+	SendMail(ctxWithTx context.Context, organizationID uuid.UUID, to, subject, body string) error
+
+	// PublishEvent publishes the domain event to the configured event broker
+	PublishEvent(ctxWithTx context.Context, event *CloudEvent) error
+}
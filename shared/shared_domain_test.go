@@ -1,6 +1,8 @@
 package shared
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/matryer/is"
@@ -23,3 +25,37 @@ func TestHasRole(t *testing.T) {
 		is.True(!hasClaim)
 	})
 }
+
+func TestAfterCommit(t *testing.T) {
+	is := is.New(t)
+
+	txer := NewInMemRepositoryTxer()
+
+	t.Run("run once after commit", func(t *testing.T) {
+		runs := 0
+		err := txer.InTx(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, "hook", func() { runs++ })
+			AfterCommit(ctx, "hook", func() { runs++ })
+			is.Equal(runs, 0)
+			return nil
+		})
+		is.NoErr(err)
+		is.Equal(runs, 1)
+	})
+
+	t.Run("not run on rollback", func(t *testing.T) {
+		runs := 0
+		err := txer.InTx(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, "hook", func() { runs++ })
+			return errors.New("rollback")
+		})
+		is.True(err != nil)
+		is.Equal(runs, 0)
+	})
+
+	t.Run("run at once without transaction", func(t *testing.T) {
+		runs := 0
+		AfterCommit(context.Background(), "hook", func() { runs++ })
+		is.Equal(runs, 1)
+	})
+}
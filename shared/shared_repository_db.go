@@ -5,12 +5,16 @@ import (
 	"embed"
 	"fmt"
 	"log"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/pgx"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
@@ -20,6 +24,9 @@ import (
 //go:embed migrations
 var migrations embed.FS
 
+// EncryptionKeySample is the encryption key of values encrypted at rest in tests
+const EncryptionKeySample = "sample:YmFyYWxnYS1zYW1wbGUta2V5LW9mLTMyLWJ5dGVzISE="
+
 var (
 	OrganizationIDSample uuid.UUID
 	ProjectIDSample      uuid.UUID
@@ -54,7 +61,8 @@ func (txer *DbRepositoryTxer) InTx(ctx context.Context, txFuncs ...func(ctxWithT
 		return err
 	}
 
-	ctxWithTx := context.WithValue(ctx, ContextKeyTx, tx)
+	ctxWithHooks, afterCommitHooks := withAfterCommitHooks(ctx)
+	ctxWithTx := context.WithValue(ctxWithHooks, ContextKeyTx, tx)
 
 	for _, txFunc := range txFuncs {
 		err = txFunc(ctxWithTx)
@@ -72,9 +80,23 @@ func (txer *DbRepositoryTxer) InTx(ctx context.Context, txFuncs ...func(ctxWithT
 		return err
 	}
 
+	afterCommitHooks.run()
 	return nil
 }
 
+// InSnapshot runs the reads in a read only transaction, so that all reads like the page and
+// the count of a paged query see the same snapshot of the data despite concurrent writes
+func InSnapshot(ctx context.Context, connPool *pgxpool.Pool, read func(tx pgx.Tx) error) error {
+	return pgx.BeginTxFunc(ctx, connPool, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, read)
+}
+
+// WithoutStatementTimeout lifts the statement timeout of the pool for the rest of the transaction,
+// for long running statements like streamed exports or the copies of a backup
+func WithoutStatementTimeout(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, "SET LOCAL statement_timeout = 0")
+	return err
+}
+
 func insertSampleContent(ctx context.Context, connPool *pgxpool.Pool) error {
 	_, err := connPool.Exec(
 		ctx,
@@ -86,6 +108,52 @@ func insertSampleContent(ctx context.Context, connPool *pgxpool.Pool) error {
 	return err
 }
 
+// SetupTestDatabaseOnServer creates a new database with the sample content on the Postgres server of the url,
+// like a service container in CI. The cleanup drops the database.
+func SetupTestDatabaseOnServer(ctx context.Context, serverURL string) (func() error, *pgxpool.Pool, error) {
+	conn, err := pgx.Connect(ctx, serverURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close(ctx)
+
+	dbName := "baralga_test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	_, err = conn.Exec(ctx, "CREATE DATABASE "+dbName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dropDatabase := func() error {
+		conn, err := pgx.Connect(context.Background(), serverURL)
+		if err != nil {
+			return err
+		}
+		defer conn.Close(context.Background())
+
+		_, err = conn.Exec(context.Background(), "DROP DATABASE IF EXISTS "+dbName+" WITH (FORCE)")
+		return err
+	}
+
+	dbURL, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	dbURL.Path = "/" + dbName
+
+	connPool, err := Connect(dbURL.String(), &DbPoolConfig{MaxConns: 4})
+	if err != nil {
+		_ = dropDatabase()
+		return nil, nil, err
+	}
+
+	err = insertSampleContent(ctx, connPool)
+
+	return func() error {
+		connPool.Close()
+		return dropDatabase()
+	}, connPool, err
+}
+
 func SetupTestDatabase(ctx context.Context) (func() error, *pgxpool.Pool, error) {
 	pool, err := dockertest.NewPool("")
 	if err != nil {
@@ -117,7 +185,7 @@ func SetupTestDatabase(ctx context.Context) (func() error, *pgxpool.Pool, error)
 	err = pool.Retry(func() error {
 		var err error
 
-		_, err = Connect(dbURI, 1)
+		_, err = Connect(dbURI, &DbPoolConfig{MaxConns: 1})
 		if err != nil {
 			return err
 		}
@@ -127,7 +195,7 @@ func SetupTestDatabase(ctx context.Context) (func() error, *pgxpool.Pool, error)
 		return nil, nil, err
 	}
 
-	connPool, err := Connect(dbURI, 1)
+	connPool, err := Connect(dbURI, &DbPoolConfig{MaxConns: 1})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -139,7 +207,24 @@ func SetupTestDatabase(ctx context.Context) (func() error, *pgxpool.Pool, error)
 	}, connPool, err
 }
 
-func Connect(dbURL string, maxConns int32) (*pgxpool.Pool, error) {
+// DbPoolConfig configures the sizing and lifetimes of the database connection pool
+type DbPoolConfig struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+
+	// QueryTimeout cancels statements running longer in the database, zero disables the timeout
+	QueryTimeout time.Duration
+
+	// RowLevelSecurity sets the organization of the context on each connection for the row level security policies
+	RowLevelSecurity bool
+}
+
+// Connect migrates the database and connects to it with a pool of the given configuration,
+// durations of zero keep the defaults of the pool
+func Connect(dbURL string, poolConfig *DbPoolConfig) (*pgxpool.Pool, error) {
 	err := migrateDb(dbURL)
 	if err != nil {
 		return nil, err
@@ -150,7 +235,23 @@ func Connect(dbURL string, maxConns int32) (*pgxpool.Pool, error) {
 		return nil, err
 	}
 
-	pgxConfig.MaxConns = maxConns
+	pgxConfig.MaxConns = poolConfig.MaxConns
+	pgxConfig.MinConns = poolConfig.MinConns
+	if poolConfig.MaxConnLifetime > 0 {
+		pgxConfig.MaxConnLifetime = poolConfig.MaxConnLifetime
+	}
+	if poolConfig.MaxConnIdleTime > 0 {
+		pgxConfig.MaxConnIdleTime = poolConfig.MaxConnIdleTime
+	}
+	if poolConfig.HealthCheckPeriod > 0 {
+		pgxConfig.HealthCheckPeriod = poolConfig.HealthCheckPeriod
+	}
+	if poolConfig.QueryTimeout > 0 {
+		pgxConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(poolConfig.QueryTimeout.Milliseconds(), 10)
+	}
+	if poolConfig.RowLevelSecurity {
+		pgxConfig.BeforeAcquire = setOrganizationOfConnection
+	}
 
 	conn, err := pgxpool.NewWithConfig(context.Background(), pgxConfig)
 	if err != nil {
@@ -160,6 +261,39 @@ func Connect(dbURL string, maxConns int32) (*pgxpool.Pool, error) {
 	return conn, nil
 }
 
+// WithOrganizationID sets the organization of the context for the row level security
+// of the database where there is no principal like in background jobs
+func WithOrganizationID(ctx context.Context, organizationID uuid.UUID) context.Context {
+	return context.WithValue(ctx, ContextKeyOrganization, organizationID)
+}
+
+// organizationIDOf is the organization of the principal or the organization set on the context
+func organizationIDOf(ctx context.Context) uuid.UUID {
+	if principal, ok := ctx.Value(ContextKeyPrincipal).(*Principal); ok && principal != nil {
+		return principal.OrganizationID
+	}
+	if organizationID, ok := ctx.Value(ContextKeyOrganization).(uuid.UUID); ok {
+		return organizationID
+	}
+	return uuid.Nil
+}
+
+// setOrganizationOfConnection sets the organization of the context on the connection before it's used,
+// without organization the connection is reset so that all rows are visible for system tasks
+func setOrganizationOfConnection(ctx context.Context, conn *pgx.Conn) bool {
+	organization := ""
+	if organizationID := organizationIDOf(ctx); organizationID != uuid.Nil {
+		organization = organizationID.String()
+	}
+
+	_, err := conn.Exec(ctx, "SELECT set_config('baralga.org_id', $1, false)", organization)
+	if err != nil {
+		log.Printf("could not set organization of connection: %v", err)
+		return false
+	}
+	return true
+}
+
 func migrateDb(dbURL string) error {
 	source, err := iofs.New(migrations, "migrations")
 	if err != nil {
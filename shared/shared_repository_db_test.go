@@ -0,0 +1,22 @@
+package shared
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestOrganizationIDOf(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(organizationIDOf(context.Background()), uuid.Nil)
+
+	ctx := WithOrganizationID(context.Background(), OrganizationIDSample)
+	is.Equal(organizationIDOf(ctx), OrganizationIDSample)
+
+	otherOrganizationID := uuid.New()
+	ctx = context.WithValue(ctx, ContextKeyPrincipal, &Principal{OrganizationID: otherOrganizationID})
+	is.Equal(organizationIDOf(ctx), otherOrganizationID)
+}
@@ -16,12 +16,14 @@ func NewInMemRepositoryTxer() *InMemRepositoryTxer {
 }
 
 func (txer *InMemRepositoryTxer) InTx(ctx context.Context, txFuncs ...func(ctxWithTx context.Context) error) error {
+	ctxWithHooks, afterCommitHooks := withAfterCommitHooks(ctx)
 	for _, txFunc := range txFuncs {
-		err := txFunc(ctx)
+		err := txFunc(ctxWithHooks)
 		if err != nil {
 			return err
 		}
 	}
 
+	afterCommitHooks.run()
 	return nil
 }
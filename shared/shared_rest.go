@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
 	"schneider.vip/problem"
 )
 
@@ -22,13 +23,39 @@ func RenderJSON(w http.ResponseWriter, jsonModel interface{}) {
 	}
 }
 
+// RenderProblemJSON renders the error as problem with the code and status of the error catalog
 func RenderProblemJSON(w http.ResponseWriter, isProduction bool, err error) {
-	log.Printf("internal server error: %s", err)
+	domainError := DomainErrorOf(err)
 
-	if !isProduction {
-		http.Error(w, problem.New(problem.Title("internal server error"), problem.Wrap(err)).JSONString(), http.StatusInternalServerError)
-		return
+	options := []problem.Option{
+		problem.Type(domainError.Type()),
+		problem.Title(domainError.Title),
+		problem.Status(domainError.Status),
+		problem.Custom("code", domainError.Code),
 	}
 
-	http.Error(w, problem.New(problem.Title("internal server error")).JSONString(), http.StatusInternalServerError)
+	var planLimitError *PlanLimitError
+	if errors.As(err, &planLimitError) {
+		options = append(options,
+			problem.Detail(planLimitError.Error()),
+			problem.Custom("plan", planLimitError.Plan),
+			problem.Custom("limit", planLimitError.Limit),
+			problem.Custom("max", planLimitError.Max),
+		)
+	}
+
+	var maintenanceError *MaintenanceError
+	if errors.As(err, &maintenanceError) {
+		options = append(options, problem.Detail(maintenanceError.Message))
+	}
+
+	if domainError == ErrInternal {
+		log.Printf("internal server error: %s", err)
+
+		if !isProduction {
+			options = append(options, problem.Wrap(err))
+		}
+	}
+
+	_, _ = problem.New(options...).WriteTo(w)
 }
@@ -2,6 +2,8 @@ package shared
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -50,3 +52,15 @@ func TestRenderProblemJSON(t *testing.T) {
 
 	is.True(strings.Contains(w.Body.String(), "my error"))
 }
+
+func TestRenderProblemJSONWithDomainError(t *testing.T) {
+	is := is.New(t)
+
+	w := httptest.NewRecorder()
+
+	RenderProblemJSON(w, false, fmt.Errorf("delete failed: %w", ErrForbidden))
+
+	is.Equal(w.Code, http.StatusForbidden)
+	is.True(strings.Contains(w.Body.String(), `"code":"auth:forbidden"`))
+	is.True(strings.Contains(w.Body.String(), `"type":"urn:baralga:problem:auth:forbidden"`))
+}
@@ -19,6 +19,8 @@ type PageContext struct {
 	Title        string
 	CurrentPath  string
 	CurrentQuery url.Values
+	CSRFToken    string
+	Appearance   *Appearance
 }
 
 func HandleWebManifest() http.HandlerFunc {
@@ -76,8 +78,12 @@ func ModalView() g.Node {
 	})
 }
 
-func Page(title, currentPath string, body []g.Node) g.Node {
-	return HTML5Page(c.HTML5Props{
+// Page renders a page in the appearance preferred by the user, the default appearance if appearance is nil
+func Page(title, currentPath string, appearance *Appearance, body []g.Node) g.Node {
+	if appearance == nil {
+		appearance = DefaultAppearance()
+	}
+	return HTML5Page(appearance, c.HTML5Props{
 		Title:    fmt.Sprintf("%s # Baralga", title),
 		Language: "en",
 		Head: []g.Node{
@@ -97,8 +103,6 @@ func Page(title, currentPath string, body []g.Node) g.Node {
 			Link(
 				Rel("stylesheet"),
 				Href("/assets/bootstrap-icons-1.10.5/bootstrap-icons.min.css"),
-				g.Attr("media", "print"),
-				g.Attr("onload", "this.media='all'"),
 				g.Attr("crossorigin", "anonymous"),
 			),
 			Link(
@@ -124,16 +128,46 @@ func Page(title, currentPath string, body []g.Node) g.Node {
 				g.Attr("crossorigin", "anonymous"),
 				g.Attr("defer", "defer"),
 			),
+			g.If(appearance.Theme == ThemeSystem,
+				Script(
+					Src("/assets/theme.js"),
+				),
+			),
+			g.If(appearance.AccentColor != "",
+				StyleEl(
+					g.Raw(accentColorStyle(appearance.AccentColor)),
+				),
+			),
 		},
 		Body: body,
 	})
 }
 
+// accentColorStyle overrides the primary color of bootstrap with the accent color
+func accentColorStyle(accentColor string) string {
+	if !IsValidAccentColor(accentColor) {
+		return ""
+	}
+	return fmt.Sprintf(
+		`:root, [data-bs-theme] { --bs-primary: %[1]s; --bs-link-color: %[1]s; --bs-link-hover-color: %[1]s; }
+.btn-primary { --bs-btn-bg: %[1]s; --bs-btn-border-color: %[1]s; --bs-btn-hover-bg: %[1]s; --bs-btn-hover-border-color: %[1]s; --bs-btn-active-bg: %[1]s; --bs-btn-active-border-color: %[1]s; --bs-btn-disabled-bg: %[1]s; --bs-btn-disabled-border-color: %[1]s; }`,
+		accentColor,
+	)
+}
+
+// htmlTheme is the bootstrap theme of the appearance, the system theme starts dark until theme.js applied the theme of the system
+func htmlTheme(appearance *Appearance) string {
+	if appearance.Theme == ThemeLight {
+		return ThemeLight
+	}
+	return ThemeDark
+}
+
 // HTML5 document template.
-func HTML5Page(p c.HTML5Props) g.Node {
+func HTML5Page(appearance *Appearance, p c.HTML5Props) g.Node {
 	return Doctype(
 		HTML(g.If(p.Language != "", Lang(p.Language)),
-			g.Attr("data-bs-theme", "dark"),
+			g.Attr("data-bs-theme", htmlTheme(appearance)),
 			Head(
 				Meta(Charset("utf-8")),
 				Meta(Name("viewport"), Content("width=device-width, initial-scale=1")),
@@ -147,13 +181,19 @@ func HTML5Page(p c.HTML5Props) g.Node {
 }
 
 func Navbar(pageContext *PageContext) g.Node {
+	logoURL := "assets/baralga_48.png"
+	if pageContext.Appearance != nil && pageContext.Appearance.LogoURL != "" {
+		logoURL = pageContext.Appearance.LogoURL
+	}
+
 	return Nav(
 		Class("container-xxl navbar navbar-expand-lg bg-body-tertiary"),
 		ghx.Boost(""),
 		A(
 			Class("navbar-brand ms-2"), Href("/"),
 			Img(
-				Src("assets/baralga_48.png"),
+				Src(logoURL),
+				StyleAttr("max-height: 48px"),
 			),
 		),
 		Button(
@@ -169,6 +209,7 @@ func Navbar(pageContext *PageContext) g.Node {
 				Class("navbar-nav flex-row flex-wrap bd-navbar-nav pt-2 py-md-0"),
 				NavbarLi("/", "Track", pageContext.CurrentPath),
 				NavbarLi("/reports", "Report", pageContext.CurrentPath),
+				NavbarLi("/dashboard", "Dashboard", pageContext.CurrentPath),
 			),
 			Hr(
 				Class("d-md-none text-white-50"),
@@ -65,3 +65,44 @@ func TestRenderProblemHTMLInDevelopment(t *testing.T) {
 	// Assert
 	is.True(strings.Contains(w.Body.String(), "internal server error: BAM"))
 }
+
+func TestPageWithAppearance(t *testing.T) {
+	t.Run("default appearance", func(t *testing.T) {
+		is := is.New(t)
+		w := httptest.NewRecorder()
+
+		RenderHTML(w, Page("Test", "/", nil, []g.Node{}))
+
+		is.True(strings.Contains(w.Body.String(), `data-bs-theme="dark"`))
+		is.True(!strings.Contains(w.Body.String(), "/assets/theme.js"))
+		is.True(!strings.Contains(w.Body.String(), "--bs-primary"))
+	})
+
+	t.Run("light theme with accent color", func(t *testing.T) {
+		is := is.New(t)
+		w := httptest.NewRecorder()
+
+		RenderHTML(w, Page("Test", "/", &Appearance{Theme: ThemeLight, AccentColor: "#5b3cc4"}, []g.Node{}))
+
+		is.True(strings.Contains(w.Body.String(), `data-bs-theme="light"`))
+		is.True(strings.Contains(w.Body.String(), "--bs-primary: #5b3cc4"))
+	})
+
+	t.Run("system theme", func(t *testing.T) {
+		is := is.New(t)
+		w := httptest.NewRecorder()
+
+		RenderHTML(w, Page("Test", "/", &Appearance{Theme: ThemeSystem}, []g.Node{}))
+
+		is.True(strings.Contains(w.Body.String(), "/assets/theme.js"))
+	})
+
+	t.Run("invalid accent color is ignored", func(t *testing.T) {
+		is := is.New(t)
+		w := httptest.NewRecorder()
+
+		RenderHTML(w, Page("Test", "/", &Appearance{Theme: ThemeDark, AccentColor: "red;}</style>"}, []g.Node{}))
+
+		is.True(!strings.Contains(w.Body.String(), "--bs-primary"))
+	})
+}
@@ -0,0 +1,64 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+var ErrStorageObjectNotFound = NewDomainError("storage:object-not-found", http.StatusNotFound, "object not found")
+
+// StorageObject is a file in the storage
+type StorageObject struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Storage stores files like exports and backups on the local disk or in an S3 compatible object storage,
+// the keys of the files are paths like exports/4ed0c11d/report.csv
+type Storage interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]*StorageObject, error)
+}
+
+// NewStorage creates the storage of the config
+func NewStorage(config *Config) (Storage, error) {
+	switch config.Storage {
+	case StorageLocal:
+		return NewLocalStorage(config.StorageDir), nil
+	case StorageS3:
+		if config.StorageS3Endpoint == "" || config.StorageS3Bucket == "" {
+			return nil, errors.Errorf("endpoint and bucket are required for storage %s", StorageS3)
+		}
+		return NewS3Storage(
+			config.StorageS3Endpoint,
+			config.StorageS3Region,
+			config.StorageS3Bucket,
+			config.StorageS3AccessKey,
+			config.StorageS3SecretKey,
+		), nil
+	default:
+		return nil, errors.Errorf("storage must be %s or %s", StorageLocal, StorageS3)
+	}
+}
+
+// validateStorageKey checks that the key is a relative path which does not leave the storage
+func validateStorageKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return errors.Errorf("storage key %s not valid", key)
+	}
+	return nil
+}
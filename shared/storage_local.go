@@ -0,0 +1,137 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LocalStorage stores files in a directory of the local disk
+type LocalStorage struct {
+	dir string
+}
+
+var _ Storage = (*LocalStorage)(nil)
+
+// NewLocalStorage creates a new storage in the directory, which is created on the first file
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{
+		dir: dir,
+	}
+}
+
+// Put writes the file to a temporary file first, so that readers never see a partially written file
+func (s *LocalStorage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	err := validateStorageKey(key)
+	if err != nil {
+		return err
+	}
+
+	filePath := s.pathOf(key)
+	err = os.MkdirAll(filepath.Dir(filePath), 0750)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = io.Copy(tempFile, content)
+	if err != nil {
+		tempFile.Close()
+		return err
+	}
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), filePath)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	err := validateStorageKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(s.pathOf(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrStorageObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Delete removes the file, a file not found is already deleted
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	err := validateStorageKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(s.pathOf(key))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List reads the files with keys starting with the prefix sorted by key
+func (s *LocalStorage) List(ctx context.Context, prefix string) ([]*StorageObject, error) {
+	objects := []*StorageObject{}
+	err := filepath.WalkDir(s.dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(s.dir, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(relativePath)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, &StorageObject{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	return objects, nil
+}
+
+func (s *LocalStorage) pathOf(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
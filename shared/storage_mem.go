@@ -0,0 +1,111 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type inMemStorageObject struct {
+	content      []byte
+	lastModified time.Time
+}
+
+type InMemStorage struct {
+	mu      sync.Mutex
+	objects map[string]*inMemStorageObject
+}
+
+var _ Storage = (*InMemStorage)(nil)
+
+func NewInMemStorage() *InMemStorage {
+	return &InMemStorage{
+		objects: make(map[string]*inMemStorageObject),
+	}
+}
+
+func (s *InMemStorage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	err := validateStorageKey(key)
+	if err != nil {
+		return err
+	}
+
+	buf, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[key] = &inMemStorageObject{
+		content:      buf,
+		lastModified: time.Now(),
+	}
+	return nil
+}
+
+func (s *InMemStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	err := validateStorageKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, ErrStorageObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(object.content)), nil
+}
+
+func (s *InMemStorage) Delete(ctx context.Context, key string) error {
+	err := validateStorageKey(key)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *InMemStorage) List(ctx context.Context, prefix string) ([]*StorageObject, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objects := []*StorageObject{}
+	for key, object := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		objects = append(objects, &StorageObject{
+			Key:          key,
+			Size:         int64(len(object.content)),
+			LastModified: object.lastModified,
+		})
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	return objects, nil
+}
+
+// Touch changes the time the object was last modified to test expiry
+func (s *InMemStorage) Touch(key string, lastModified time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if object, ok := s.objects[key]; ok {
+		object.lastModified = lastModified
+	}
+}
@@ -0,0 +1,109 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestLocalStorage(t *testing.T) {
+	testStorage(t, NewLocalStorage(t.TempDir()))
+}
+
+func TestInMemStorage(t *testing.T) {
+	testStorage(t, NewInMemStorage())
+}
+
+// testStorage verifies the behaviour all implementations of the storage share
+func testStorage(t *testing.T, storage Storage) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	err := storage.Put(ctx, "exports/org1/export1", strings.NewReader("Date;Start"), 10, "text/csv")
+	is.NoErr(err)
+	err = storage.Put(ctx, "exports/org2/export2", strings.NewReader("Date;Start;End"), 14, "text/csv")
+	is.NoErr(err)
+	err = storage.Put(ctx, "backups/baralga-backup.tar.gz", strings.NewReader("backup"), 6, "application/gzip")
+	is.NoErr(err)
+
+	t.Run("Get", func(t *testing.T) {
+		content, err := storage.Get(ctx, "exports/org1/export1")
+		is.NoErr(err)
+		defer content.Close()
+
+		buf, err := io.ReadAll(content)
+		is.NoErr(err)
+		is.Equal(string(buf), "Date;Start")
+	})
+
+	t.Run("GetNotFound", func(t *testing.T) {
+		_, err := storage.Get(ctx, "exports/org1/unknown")
+		is.Equal(err, ErrStorageObjectNotFound)
+	})
+
+	t.Run("PutReplaces", func(t *testing.T) {
+		err := storage.Put(ctx, "exports/org1/export1", strings.NewReader("Date"), 4, "text/csv")
+		is.NoErr(err)
+
+		content, err := storage.Get(ctx, "exports/org1/export1")
+		is.NoErr(err)
+		defer content.Close()
+
+		buf, err := io.ReadAll(content)
+		is.NoErr(err)
+		is.Equal(string(buf), "Date")
+	})
+
+	t.Run("List", func(t *testing.T) {
+		objects, err := storage.List(ctx, "exports/")
+		is.NoErr(err)
+		is.Equal(len(objects), 2)
+		is.Equal(objects[0].Key, "exports/org1/export1")
+		is.Equal(objects[0].Size, int64(4))
+		is.Equal(objects[1].Key, "exports/org2/export2")
+		is.True(!objects[1].LastModified.IsZero())
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		err := storage.Delete(ctx, "exports/org2/export2")
+		is.NoErr(err)
+
+		_, err = storage.Get(ctx, "exports/org2/export2")
+		is.Equal(err, ErrStorageObjectNotFound)
+
+		// deleting again succeeds
+		err = storage.Delete(ctx, "exports/org2/export2")
+		is.NoErr(err)
+	})
+
+	t.Run("KeyNotValid", func(t *testing.T) {
+		err := storage.Put(ctx, "../outside", strings.NewReader("x"), 1, "text/plain")
+		is.True(err != nil)
+
+		_, err = storage.Get(ctx, "/etc/passwd")
+		is.True(err != nil)
+	})
+}
+
+func TestNewStorage(t *testing.T) {
+	is := is.New(t)
+
+	storage, err := NewStorage(&Config{Storage: StorageLocal, StorageDir: t.TempDir()})
+	is.NoErr(err)
+	_, ok := storage.(*LocalStorage)
+	is.True(ok)
+
+	storage, err = NewStorage(&Config{Storage: StorageS3, StorageS3Endpoint: "http://localhost:9000", StorageS3Bucket: "baralga"})
+	is.NoErr(err)
+	_, ok = storage.(*S3Storage)
+	is.True(ok)
+
+	_, err = NewStorage(&Config{Storage: StorageS3})
+	is.True(err != nil)
+
+	_, err = NewStorage(&Config{Storage: "ftp"})
+	is.True(err != nil)
+}
@@ -0,0 +1,231 @@
+package testkit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// passwordSample is the encrypted password of the sample users
+const passwordSample = "$2a$10$NuzYobDOSTCx/EKBClGwGe0A9c8/yC7D4IP75hwz1jn.RCBfdEtb2"
+
+// Organization is an organization inserted as fixture
+type Organization struct {
+	ID    uuid.UUID
+	Title string
+}
+
+// User is an enabled user inserted as fixture
+type User struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Username       string
+	Name           string
+	EMail          string
+	Password       string
+	Roles          []string
+}
+
+// Project is a project inserted as fixture
+type Project struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Title          string
+	Description    string
+	Status         string
+	Billable       bool
+	BudgetMinutes  int
+}
+
+// Activity is an activity inserted as fixture
+type Activity struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ProjectID      uuid.UUID
+	Username       string
+	Description    string
+	Start          time.Time
+	End            time.Time
+}
+
+type OrganizationBuilder struct {
+	db           *Database
+	organization *Organization
+}
+
+type UserBuilder struct {
+	db   *Database
+	user *User
+}
+
+type ProjectBuilder struct {
+	db      *Database
+	project *Project
+}
+
+type ActivityBuilder struct {
+	db       *Database
+	activity *Activity
+}
+
+// Organization builds a new organization
+func (d *Database) Organization() *OrganizationBuilder {
+	id := uuid.New()
+	return &OrganizationBuilder{
+		db: d,
+		organization: &Organization{
+			ID:    id,
+			Title: fmt.Sprintf("Organization %v", id.String()[:8]),
+		},
+	}
+}
+
+func (b *OrganizationBuilder) Title(title string) *OrganizationBuilder {
+	b.organization.Title = title
+	return b
+}
+
+// Insert inserts the organization
+func (b *OrganizationBuilder) Insert(t testing.TB) *Organization {
+	t.Helper()
+
+	o := b.organization
+	b.db.exec(t, `INSERT INTO organizations (org_id, title) VALUES ($1, $2)`, o.ID, o.Title)
+	return o
+}
+
+// User builds a new user of the organization with the role ROLE_USER and the password of the sample users
+func (d *Database) User(organizationID uuid.UUID) *UserBuilder {
+	id := uuid.New()
+	username := "user-" + id.String()[:8]
+	return &UserBuilder{
+		db: d,
+		user: &User{
+			ID:             id,
+			OrganizationID: organizationID,
+			Username:       username,
+			Name:           username,
+			EMail:          username + "@baralga.com",
+			Password:       passwordSample,
+			Roles:          []string{"ROLE_USER"},
+		},
+	}
+}
+
+func (b *UserBuilder) Username(username string) *UserBuilder {
+	b.user.Username = username
+	return b
+}
+
+func (b *UserBuilder) Name(name string) *UserBuilder {
+	b.user.Name = name
+	return b
+}
+
+func (b *UserBuilder) Roles(roles ...string) *UserBuilder {
+	b.user.Roles = roles
+	return b
+}
+
+// Insert inserts the enabled user with its roles
+func (b *UserBuilder) Insert(t testing.TB) *User {
+	t.Helper()
+
+	u := b.user
+	b.db.exec(
+		t,
+		`INSERT INTO users (user_id, username, email, name, password, enabled, org_id, origin) VALUES ($1, $2, $3, $4, $5, 1, $6, 'baralga')`,
+		u.ID, u.Username, u.EMail, u.Name, u.Password, u.OrganizationID,
+	)
+	for _, role := range u.Roles {
+		b.db.exec(t, `INSERT INTO roles (user_id, role, org_id) VALUES ($1, $2, $3)`, u.ID, role, u.OrganizationID)
+	}
+	return u
+}
+
+// Project builds a new active project of the organization
+func (d *Database) Project(organizationID uuid.UUID) *ProjectBuilder {
+	id := uuid.New()
+	return &ProjectBuilder{
+		db: d,
+		project: &Project{
+			ID:             id,
+			OrganizationID: organizationID,
+			Title:          fmt.Sprintf("Project %v", id.String()[:8]),
+			Status:         "active",
+		},
+	}
+}
+
+func (b *ProjectBuilder) Title(title string) *ProjectBuilder {
+	b.project.Title = title
+	return b
+}
+
+func (b *ProjectBuilder) Status(status string) *ProjectBuilder {
+	b.project.Status = status
+	return b
+}
+
+func (b *ProjectBuilder) Billable(budgetMinutes int) *ProjectBuilder {
+	b.project.Billable = true
+	b.project.BudgetMinutes = budgetMinutes
+	return b
+}
+
+// Insert inserts the project
+func (b *ProjectBuilder) Insert(t testing.TB) *Project {
+	t.Helper()
+
+	p := b.project
+	b.db.exec(
+		t,
+		`INSERT INTO projects (project_id, org_id, title, description, active, status, billable, budget_minutes) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		p.ID, p.OrganizationID, p.Title, p.Description, p.Status == "active", p.Status, p.Billable, p.BudgetMinutes,
+	)
+	return p
+}
+
+// Activity builds a new activity of an hour of the user on the project,
+// starting at the start of the sample activity
+func (d *Database) Activity(organizationID, projectID uuid.UUID, username string) *ActivityBuilder {
+	start := time.Date(2021, 10, 14, 14, 0, 0, 0, time.UTC)
+	return &ActivityBuilder{
+		db: d,
+		activity: &Activity{
+			ID:             uuid.New(),
+			OrganizationID: organizationID,
+			ProjectID:      projectID,
+			Username:       username,
+			Description:    "My Activity",
+			Start:          start,
+			End:            start.Add(time.Hour),
+		},
+	}
+}
+
+func (b *ActivityBuilder) Description(description string) *ActivityBuilder {
+	b.activity.Description = description
+	return b
+}
+
+func (b *ActivityBuilder) Between(start, end time.Time) *ActivityBuilder {
+	b.activity.Start = start
+	b.activity.End = end
+	return b
+}
+
+// Insert inserts the activity
+func (b *ActivityBuilder) Insert(t testing.TB) *Activity {
+	t.Helper()
+
+	a := b.activity
+	b.db.exec(
+		t,
+		`INSERT INTO activities (activity_id, start_time, end_time, description, project_id, org_id, username) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		a.ID, a.Start, a.End, a.Description, a.ProjectID, a.OrganizationID, a.Username,
+	)
+	return a
+}
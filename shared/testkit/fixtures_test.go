@@ -0,0 +1,28 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestFixtures(t *testing.T) {
+	is := is.New(t)
+	db := NewDatabase(t)
+
+	organization := db.Organization().Title("Acme").Insert(t)
+	user := db.User(organization.ID).Roles("ROLE_ADMIN", "ROLE_USER").Insert(t)
+	project := db.Project(organization.ID).Billable(600).Insert(t)
+	db.Activity(organization.ID, project.ID, user.Username).Insert(t)
+	db.Activity(organization.ID, project.ID, user.Username).Description("Other").Insert(t)
+
+	var roles, activities int
+	err := db.ConnPool.QueryRow(context.Background(), `SELECT count(*) FROM roles WHERE user_id = $1`, user.ID).Scan(&roles)
+	is.NoErr(err)
+	is.Equal(roles, 2)
+
+	err = db.ConnPool.QueryRow(context.Background(), `SELECT count(*) FROM activities WHERE org_id = $1`, organization.ID).Scan(&activities)
+	is.NoErr(err)
+	is.Equal(activities, 2)
+}
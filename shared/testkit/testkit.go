@@ -0,0 +1,64 @@
+// Package testkit runs integration tests of repositories against a real Postgres database,
+// with builders for the fixtures of organizations, users, projects and activities.
+package testkit
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Database is a migrated Postgres database with the sample content for one test
+type Database struct {
+	ConnPool       *pgxpool.Pool
+	RepositoryTxer shared.RepositoryTxer
+}
+
+// NewDatabase provides a database for the test which is removed after the test. The database is created on the
+// Postgres server of the environment variable BARALGA_TESTDB if set, else a Postgres container is started.
+// Integration tests are skipped in short mode.
+func NewDatabase(t testing.TB) *Database {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("integration test skipped in short mode")
+	}
+
+	ctx := context.Background()
+	var cleanupFunc func() error
+	var connPool *pgxpool.Pool
+	var err error
+	if serverURL := os.Getenv("BARALGA_TESTDB"); serverURL != "" {
+		cleanupFunc, connPool, err = shared.SetupTestDatabaseOnServer(ctx, serverURL)
+	} else {
+		cleanupFunc, connPool, err = shared.SetupTestDatabase(ctx)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	})
+
+	return &Database{
+		ConnPool:       connPool,
+		RepositoryTxer: shared.NewDbRepositoryTxer(connPool),
+	}
+}
+
+// exec runs the statement of a fixture and fails the test on errors
+func (d *Database) exec(t testing.TB, sql string, args ...any) {
+	t.Helper()
+
+	_, err := d.ConnPool.Exec(context.Background(), sql, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
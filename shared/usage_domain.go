@@ -0,0 +1,171 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	APIUsageGroupByToken    = "token"
+	APIUsageGroupByUser     = "user"
+	APIUsageGroupByEndpoint = "endpoint"
+	APIUsageGroupByClient   = "client"
+
+	// apiUsageFlushInterval is the interval in which the counted api requests are written to the database
+	apiUsageFlushInterval = time.Minute
+
+	// apiUsageRetentionDays is the number of days the api usage is kept
+	apiUsageRetentionDays = 90
+
+	// apiUsageTokenIDLength is the length of the fingerprint of a token, the token itself is never stored
+	apiUsageTokenIDLength = 12
+
+	// apiUsageMaxClientLength is the maximum length of the user agent of a client
+	apiUsageMaxClientLength = 255
+
+	// apiUsageUnmatchedEndpoint is the endpoint of requests matching no route
+	apiUsageUnmatchedEndpoint = "unmatched"
+)
+
+// APIUsage counts the api requests of a token of a user to an endpoint on a day
+type APIUsage struct {
+	OrganizationID uuid.UUID
+	Day            time.Time
+	Username       string
+	TokenID        string
+	Method         string
+	Endpoint       string
+	Client         string
+	Requests       int
+	Errors         int
+	LastRequestAt  time.Time
+}
+
+// APIUsageFilter filters the api usage by the days from and to, both inclusive
+type APIUsageFilter struct {
+	OrganizationID uuid.UUID
+	From           time.Time
+	To             time.Time
+	Username       string
+}
+
+// APIUsageGroup sums up the api usage of a token, user, endpoint or client
+type APIUsageGroup struct {
+	Key           string
+	Usernames     []string
+	Requests      int
+	Errors        int
+	LastRequestAt time.Time
+}
+
+// apiUsageKey identifies the counter of the api usage
+type apiUsageKey struct {
+	organizationID uuid.UUID
+	day            string
+	username       string
+	tokenID        string
+	method         string
+	endpoint       string
+}
+
+type APIUsageRepository interface {
+	FindAPIUsage(ctx context.Context, filter *APIUsageFilter) ([]*APIUsage, error)
+	AddAPIUsage(ctx context.Context, usage *APIUsage) error
+	DeleteAPIUsageBefore(ctx context.Context, day time.Time) error
+}
+
+// TokenIDOf is the fingerprint of the token, it tells tokens apart without revealing them
+func TokenIDOf(token string) string {
+	if token == "" {
+		return ""
+	}
+
+	return HashToken(token)[:apiUsageTokenIDLength]
+}
+
+// tokenOfRequest is the bearer token of the request or the token of the session cookie
+func tokenOfRequest(r *http.Request) string {
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+		return authorization[7:]
+	}
+
+	cookie, err := r.Cookie(SessionCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// clientOfRequest is the user agent of the request, shortened to fit the database
+func clientOfRequest(r *http.Request) string {
+	client := strings.TrimSpace(r.UserAgent())
+	if len(client) > apiUsageMaxClientLength {
+		client = client[:apiUsageMaxClientLength]
+	}
+	return client
+}
+
+// IsValidAPIUsageGroupBy checks whether the api usage can be grouped by the key
+func IsValidAPIUsageGroupBy(groupBy string) bool {
+	switch groupBy {
+	case APIUsageGroupByToken, APIUsageGroupByUser, APIUsageGroupByEndpoint, APIUsageGroupByClient:
+		return true
+	}
+	return false
+}
+
+// GroupAPIUsage sums up the api usage by token, user, endpoint or client, the groups with most requests first
+func GroupAPIUsage(usages []*APIUsage, groupBy string) []*APIUsageGroup {
+	groupsByKey := make(map[string]*APIUsageGroup)
+	var groups []*APIUsageGroup
+	for _, usage := range usages {
+		key := apiUsageGroupKeyOf(usage, groupBy)
+
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &APIUsageGroup{Key: key}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+
+		group.Requests += usage.Requests
+		group.Errors += usage.Errors
+		if usage.LastRequestAt.After(group.LastRequestAt) {
+			group.LastRequestAt = usage.LastRequestAt
+		}
+		if !slices.Contains(group.Usernames, usage.Username) {
+			group.Usernames = append(group.Usernames, usage.Username)
+		}
+	}
+
+	for _, group := range groups {
+		sort.Strings(group.Usernames)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Requests != groups[j].Requests {
+			return groups[i].Requests > groups[j].Requests
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+func apiUsageGroupKeyOf(usage *APIUsage, groupBy string) string {
+	switch groupBy {
+	case APIUsageGroupByUser:
+		return usage.Username
+	case APIUsageGroupByEndpoint:
+		return usage.Method + " " + usage.Endpoint
+	case APIUsageGroupByClient:
+		return usage.Client
+	default:
+		return usage.TokenID
+	}
+}
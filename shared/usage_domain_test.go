@@ -0,0 +1,65 @@
+package shared
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestTokenIDOf(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(TokenIDOf(""), "")
+	is.Equal(len(TokenIDOf("my-token")), apiUsageTokenIDLength)
+	is.Equal(TokenIDOf("my-token"), TokenIDOf("my-token"))
+	is.True(TokenIDOf("my-token") != TokenIDOf("other-token"))
+}
+
+func TestTokenOfRequest(t *testing.T) {
+	is := is.New(t)
+
+	r, _ := http.NewRequest("GET", "/api/activities", nil)
+	is.Equal(tokenOfRequest(r), "")
+
+	r.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "cookie-token"})
+	is.Equal(tokenOfRequest(r), "cookie-token")
+
+	r.Header.Set("Authorization", "Bearer header-token")
+	is.Equal(tokenOfRequest(r), "header-token")
+}
+
+func TestGroupAPIUsage(t *testing.T) {
+	is := is.New(t)
+
+	lastRequestAt := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	usages := []*APIUsage{
+		{Username: "user1", TokenID: "a", Method: "GET", Endpoint: "/api/activities", Client: "curl", Requests: 2, LastRequestAt: lastRequestAt},
+		{Username: "user1", TokenID: "b", Method: "GET", Endpoint: "/api/projects", Client: "browser", Requests: 1, LastRequestAt: lastRequestAt.Add(-time.Hour)},
+		{Username: "admin", TokenID: "c", Method: "GET", Endpoint: "/api/activities", Client: "curl", Requests: 5, Errors: 3, LastRequestAt: lastRequestAt.Add(time.Hour)},
+	}
+
+	groups := GroupAPIUsage(usages, APIUsageGroupByEndpoint)
+	is.Equal(len(groups), 2)
+	is.Equal(groups[0].Key, "GET /api/activities")
+	is.Equal(groups[0].Requests, 7)
+	is.Equal(groups[0].Errors, 3)
+	is.Equal(groups[0].Usernames, []string{"admin", "user1"})
+	is.Equal(groups[0].LastRequestAt, lastRequestAt.Add(time.Hour))
+
+	groups = GroupAPIUsage(usages, APIUsageGroupByUser)
+	is.Equal(len(groups), 2)
+	is.Equal(groups[0].Key, "admin")
+	is.Equal(groups[1].Key, "user1")
+	is.Equal(groups[1].Requests, 3)
+
+	groups = GroupAPIUsage(usages, APIUsageGroupByToken)
+	is.Equal(len(groups), 3)
+	is.Equal(groups[0].Key, "c")
+
+	groups = GroupAPIUsage(usages, APIUsageGroupByClient)
+	is.Equal(len(groups), 2)
+	is.Equal(groups[0].Key, "curl")
+	is.Equal(groups[0].Requests, 7)
+}
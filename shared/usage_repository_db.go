@@ -0,0 +1,118 @@
+package shared
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DbAPIUsageRepository is a SQL database repository for the api usage of organizations
+type DbAPIUsageRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ APIUsageRepository = (*DbAPIUsageRepository)(nil)
+
+type apiUsageRow struct {
+	OrganizationID uuid.UUID `db:"org_id"`
+	Day            time.Time `db:"day"`
+	Username       string    `db:"username"`
+	TokenID        string    `db:"token_id"`
+	Method         string    `db:"method"`
+	Endpoint       string    `db:"endpoint"`
+	Client         string    `db:"client"`
+	Requests       int       `db:"requests"`
+	Errors         int       `db:"errors"`
+	LastRequestAt  time.Time `db:"last_request_at"`
+}
+
+// NewDbAPIUsageRepository creates a new SQL database repository for the api usage of organizations
+func NewDbAPIUsageRepository(connPool *pgxpool.Pool) *DbAPIUsageRepository {
+	return &DbAPIUsageRepository{
+		connPool: connPool,
+	}
+}
+
+// FindAPIUsage reads the api usage of the organization in the days of the filter
+func (r *DbAPIUsageRepository) FindAPIUsage(ctx context.Context, filter *APIUsageFilter) ([]*APIUsage, error) {
+	rows, err := SelectAll[apiUsageRow](
+		ctx,
+		r.connPool,
+		`SELECT `+Columns[apiUsageRow]()+` 
+		 FROM api_usage 
+		 WHERE org_id = $1 AND day >= $2 AND day <= $3 AND ($4 = '' OR username = $4) 
+		 ORDER BY day, username, token_id, method, endpoint`,
+		filter.OrganizationID,
+		filter.From,
+		filter.To,
+		filter.Username,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]*APIUsage, len(rows))
+	for i, row := range rows {
+		usages[i] = row.toAPIUsage()
+	}
+	return usages, nil
+}
+
+// AddAPIUsage adds the counted requests to the api usage of the day
+func (r *DbAPIUsageRepository) AddAPIUsage(ctx context.Context, usage *APIUsage) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO api_usage 
+		   (org_id, day, username, token_id, method, endpoint, client, requests, errors, last_request_at) 
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
+		 ON CONFLICT (org_id, day, username, token_id, method, endpoint) 
+		 DO UPDATE SET client = EXCLUDED.client, 
+		   requests = api_usage.requests + EXCLUDED.requests, 
+		   errors = api_usage.errors + EXCLUDED.errors, 
+		   last_request_at = GREATEST(api_usage.last_request_at, EXCLUDED.last_request_at)`,
+		usage.OrganizationID,
+		usage.Day,
+		usage.Username,
+		usage.TokenID,
+		usage.Method,
+		usage.Endpoint,
+		usage.Client,
+		usage.Requests,
+		usage.Errors,
+		usage.LastRequestAt,
+	)
+	return err
+}
+
+// DeleteAPIUsageBefore deletes the api usage of all organizations before the day
+func (r *DbAPIUsageRepository) DeleteAPIUsageBefore(ctx context.Context, day time.Time) error {
+	tx := ctx.Value(ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`DELETE FROM api_usage 
+		 WHERE day < $1`,
+		day,
+	)
+	return err
+}
+
+func (r *apiUsageRow) toAPIUsage() *APIUsage {
+	return &APIUsage{
+		OrganizationID: r.OrganizationID,
+		Day:            r.Day,
+		Username:       r.Username,
+		TokenID:        r.TokenID,
+		Method:         r.Method,
+		Endpoint:       r.Endpoint,
+		Client:         r.Client,
+		Requests:       r.Requests,
+		Errors:         r.Errors,
+		LastRequestAt:  r.LastRequestAt,
+	}
+}
@@ -0,0 +1,87 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestAPIUsageRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	apiUsageRepository := NewDbAPIUsageRepository(connPool)
+	repositoryTxer := NewDbRepositoryTxer(connPool)
+
+	t.Run("AddAPIUsage", func(t *testing.T) {
+		day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+		for _, lastRequestAt := range []time.Time{day.Add(2 * time.Hour), day.Add(time.Hour)} {
+			usage := &APIUsage{
+				OrganizationID: OrganizationIDSample,
+				Day:            day,
+				Username:       "user1",
+				TokenID:        "a",
+				Method:         "GET",
+				Endpoint:       "/api/activities",
+				Client:         "curl",
+				Requests:       2,
+				Errors:         1,
+				LastRequestAt:  lastRequestAt,
+			}
+			err := repositoryTxer.InTx(
+				context.Background(),
+				func(ctx context.Context) error {
+					return apiUsageRepository.AddAPIUsage(ctx, usage)
+				},
+			)
+			is.NoErr(err)
+		}
+
+		usages, err := apiUsageRepository.FindAPIUsage(context.Background(), &APIUsageFilter{
+			OrganizationID: OrganizationIDSample,
+			From:           day,
+			To:             day,
+		})
+		is.NoErr(err)
+		is.Equal(len(usages), 1)
+		is.Equal(usages[0].Requests, 4)
+		is.Equal(usages[0].Errors, 2)
+		is.Equal(usages[0].LastRequestAt.UTC(), day.Add(2*time.Hour))
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return apiUsageRepository.DeleteAPIUsageBefore(ctx, day.AddDate(0, 0, 1))
+			},
+		)
+		is.NoErr(err)
+
+		usages, err = apiUsageRepository.FindAPIUsage(context.Background(), &APIUsageFilter{
+			OrganizationID: OrganizationIDSample,
+			From:           day,
+			To:             day,
+		})
+		is.NoErr(err)
+		is.Equal(len(usages), 0)
+	})
+}
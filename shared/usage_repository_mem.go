@@ -0,0 +1,76 @@
+package shared
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type InMemAPIUsageRepository struct {
+	mu     sync.Mutex
+	usages []*APIUsage
+}
+
+var _ APIUsageRepository = (*InMemAPIUsageRepository)(nil)
+
+func NewInMemAPIUsageRepository() *InMemAPIUsageRepository {
+	return &InMemAPIUsageRepository{}
+}
+
+func (r *InMemAPIUsageRepository) FindAPIUsage(ctx context.Context, filter *APIUsageFilter) ([]*APIUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var usages []*APIUsage
+	for _, usage := range r.usages {
+		if usage.OrganizationID != filter.OrganizationID ||
+			usage.Day.Before(filter.From) ||
+			usage.Day.After(filter.To) ||
+			(filter.Username != "" && usage.Username != filter.Username) {
+			continue
+		}
+		found := *usage
+		usages = append(usages, &found)
+	}
+	return usages, nil
+}
+
+func (r *InMemAPIUsageRepository) AddAPIUsage(ctx context.Context, usage *APIUsage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.usages {
+		if stored.OrganizationID == usage.OrganizationID &&
+			stored.Day.Equal(usage.Day) &&
+			stored.Username == usage.Username &&
+			stored.TokenID == usage.TokenID &&
+			stored.Method == usage.Method &&
+			stored.Endpoint == usage.Endpoint {
+			stored.Client = usage.Client
+			stored.Requests += usage.Requests
+			stored.Errors += usage.Errors
+			if usage.LastRequestAt.After(stored.LastRequestAt) {
+				stored.LastRequestAt = usage.LastRequestAt
+			}
+			return nil
+		}
+	}
+
+	stored := *usage
+	r.usages = append(r.usages, &stored)
+	return nil
+}
+
+func (r *InMemAPIUsageRepository) DeleteAPIUsageBefore(ctx context.Context, day time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var usages []*APIUsage
+	for _, usage := range r.usages {
+		if !usage.Day.Before(day) {
+			usages = append(usages, usage)
+		}
+	}
+	r.usages = usages
+	return nil
+}
@@ -0,0 +1,163 @@
+package shared
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// apiUsageDefaultDays is the number of days of the api usage if no days are requested
+	apiUsageDefaultDays = 30
+)
+
+type apiUsageGroupModel struct {
+	Key           string   `json:"key"`
+	Usernames     []string `json:"usernames"`
+	Requests      int      `json:"requests"`
+	Errors        int      `json:"errors"`
+	LastRequestAt string   `json:"lastRequestAt"`
+}
+
+type apiUsageModel struct {
+	From     string                 `json:"from"`
+	To       string                 `json:"to"`
+	GroupBy  string                 `json:"groupBy"`
+	Requests int                    `json:"requests"`
+	Errors   int                    `json:"errors"`
+	Embedded *embeddedAPIUsageGroup `json:"_embedded"`
+	Links    *hal.Links             `json:"_links"`
+}
+
+type embeddedAPIUsageGroup struct {
+	APIUsageGroupModels []*apiUsageGroupModel `json:"groups"`
+}
+
+type APIUsageRestHandlers struct {
+	config          *Config
+	apiUsageService *APIUsageService
+}
+
+func NewAPIUsageRestHandlers(config *Config, apiUsageService *APIUsageService) *APIUsageRestHandlers {
+	return &APIUsageRestHandlers{
+		config:          config,
+		apiUsageService: apiUsageService,
+	}
+}
+
+func (a *APIUsageRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/api-usage", a.HandleGetAPIUsage())
+}
+
+func (a *APIUsageRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetAPIUsage reads the api usage of the organization grouped by token, user, endpoint or client,
+// or all counts per day as CSV. The usage of the last minute may not be written yet.
+func (a *APIUsageRestHandlers) HandleGetAPIUsage() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	apiUsageService := a.apiUsageService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+		now := time.Now().UTC()
+		to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		from := to.AddDate(0, 0, -apiUsageDefaultDays+1)
+
+		if r.URL.Query().Get("from") != "" {
+			day, err := time.Parse("2006-01-02", r.URL.Query().Get("from"))
+			if err != nil {
+				RenderValidationProblemJSON(w, "api usage query not valid", NewInvalidParam("from", "date", "must be a date like 2024-03-01"))
+				return
+			}
+			from = day
+		}
+		if r.URL.Query().Get("to") != "" {
+			day, err := time.Parse("2006-01-02", r.URL.Query().Get("to"))
+			if err != nil {
+				RenderValidationProblemJSON(w, "api usage query not valid", NewInvalidParam("to", "date", "must be a date like 2024-03-31"))
+				return
+			}
+			to = day
+		}
+		if to.Before(from) {
+			RenderValidationProblemJSON(w, "api usage query not valid", NewInvalidParam("to", "after", "must not be before from"))
+			return
+		}
+
+		groupBy := r.URL.Query().Get("groupBy")
+		if groupBy == "" {
+			groupBy = APIUsageGroupByToken
+		}
+		if !IsValidAPIUsageGroupBy(groupBy) {
+			RenderValidationProblemJSON(w, "api usage query not valid", NewInvalidParam("groupBy", "oneof", "must be one of token, user, endpoint or client"))
+			return
+		}
+
+		usages, err := apiUsageService.ReadAPIUsage(r.Context(), principal, &APIUsageFilter{
+			From:     from,
+			To:       to,
+			Username: r.URL.Query().Get("username"),
+		})
+		if err != nil {
+			RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		contentType := r.URL.Query().Get("contentType")
+		if contentType == "" {
+			contentType = r.Header.Get("Content-Type")
+		}
+
+		if contentType == "text/csv" {
+			buf := &bytes.Buffer{}
+			err := apiUsageService.WriteAPIUsageAsCSV(usages, buf)
+			if err != nil {
+				RenderProblemJSON(w, isProduction, err)
+				return
+			}
+
+			fileName := fmt.Sprintf("API_Usage_%s_%s.csv", from.Format("2006-01-02"), to.Format("2006-01-02"))
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+			_, _ = buf.WriteTo(w)
+			return
+		}
+
+		apiUsageModel := &apiUsageModel{
+			From:    from.Format("2006-01-02"),
+			To:      to.Format("2006-01-02"),
+			GroupBy: groupBy,
+			Embedded: &embeddedAPIUsageGroup{
+				APIUsageGroupModels: []*apiUsageGroupModel{},
+			},
+		}
+		for _, group := range GroupAPIUsage(usages, groupBy) {
+			apiUsageModel.Requests += group.Requests
+			apiUsageModel.Errors += group.Errors
+			apiUsageModel.Embedded.APIUsageGroupModels = append(apiUsageModel.Embedded.APIUsageGroupModels, mapToAPIUsageGroupModel(group))
+		}
+
+		csvQuery := r.URL.Query()
+		csvQuery.Set("contentType", "text/csv")
+		apiUsageModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+			hal.NewLink("csv", fmt.Sprintf("%s?%s", r.URL.Path, csvQuery.Encode())),
+		)
+		RenderJSON(w, apiUsageModel)
+	}
+}
+
+func mapToAPIUsageGroupModel(group *APIUsageGroup) *apiUsageGroupModel {
+	return &apiUsageGroupModel{
+		Key:           group.Key,
+		Usernames:     group.Usernames,
+		Requests:      group.Requests,
+		Errors:        group.Errors,
+		LastRequestAt: group.LastRequestAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,63 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestHandleGetAPIUsage(t *testing.T) {
+	is := is.New(t)
+
+	apiUsageService, apiUsageRepository := newTestAPIUsageService()
+	a := NewAPIUsageRestHandlers(&Config{}, apiUsageService)
+
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	is.NoErr(apiUsageRepository.AddAPIUsage(context.Background(), &APIUsage{OrganizationID: OrganizationIDSample, Day: day, Username: "user1", TokenID: "a", Method: "GET", Endpoint: "/api/activities", Requests: 2, LastRequestAt: day}))
+	is.NoErr(apiUsageRepository.AddAPIUsage(context.Background(), &APIUsage{OrganizationID: OrganizationIDSample, Day: day, Username: "admin", TokenID: "b", Method: "POST", Endpoint: "/api/activities", Requests: 7, Errors: 7, LastRequestAt: day}))
+
+	request := func(url string, roles ...string) *http.Request {
+		r, _ := http.NewRequest("GET", url, nil)
+		return r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, &Principal{
+			OrganizationID: OrganizationIDSample,
+			Username:       "admin",
+			Roles:          roles,
+		}))
+	}
+
+	httpRec := httptest.NewRecorder()
+	a.HandleGetAPIUsage()(httpRec, request("/api/api-usage?from=2024-03-01&to=2024-03-31&groupBy=user", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	apiUsageModel := &apiUsageModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(apiUsageModel)
+	is.NoErr(err)
+	is.Equal(apiUsageModel.Requests, 9)
+	is.Equal(apiUsageModel.Errors, 7)
+	is.Equal(len(apiUsageModel.Embedded.APIUsageGroupModels), 2)
+	is.Equal(apiUsageModel.Embedded.APIUsageGroupModels[0].Key, "admin")
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetAPIUsage()(httpRec, request("/api/api-usage?from=2024-03-01&to=2024-03-31&contentType=text/csv", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(httpRec.Header().Get("Content-Type"), "text/csv")
+	is.Equal(len(strings.Split(strings.TrimSpace(httpRec.Body.String()), "\n")), 3)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetAPIUsage()(httpRec, request("/api/api-usage?groupBy=ip", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetAPIUsage()(httpRec, request("/api/api-usage?from=2024-03-31&to=2024-03-01", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetAPIUsage()(httpRec, request("/api/api-usage", "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
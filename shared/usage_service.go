@@ -0,0 +1,233 @@
+package shared
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const apiUsageCleanupJobType = "api-usage-cleanup"
+
+var apiUsageCSVHeaders = []string{"Day", "Username", "Token", "Method", "Endpoint", "Client", "Requests", "Errors", "Last request"}
+
+// APIUsageService counts the api requests of organizations per token, user and endpoint, so admins
+// see which integrations are active. Requests are counted in memory and written to the database periodically.
+type APIUsageService struct {
+	repositoryTxer     RepositoryTxer
+	apiUsageRepository APIUsageRepository
+
+	mu       sync.Mutex
+	counters map[apiUsageKey]*APIUsage
+}
+
+// NewAPIUsageService creates a new service for the api usage of organizations, deleting old usage in the background
+func NewAPIUsageService(repositoryTxer RepositoryTxer, jobService *JobService, apiUsageRepository APIUsageRepository) *APIUsageService {
+	s := &APIUsageService{
+		repositoryTxer:     repositoryTxer,
+		apiUsageRepository: apiUsageRepository,
+		counters:           make(map[apiUsageKey]*APIUsage),
+	}
+
+	jobService.RegisterHandler(apiUsageCleanupJobType, s.handleAPIUsageCleanupJob)
+	jobService.Schedule(apiUsageCleanupJobType, time.Hour)
+
+	return s
+}
+
+// UsageMiddleware counts the api requests of the principal by the route pattern of the endpoint
+func (s *APIUsageService) UsageMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			principal := r.Context().Value(ContextKeyPrincipal).(*Principal)
+
+			endpoint := apiUsageUnmatchedEndpoint
+			if routeContext := chi.RouteContext(r.Context()); routeContext != nil && routeContext.RoutePattern() != "" {
+				endpoint = routeContext.RoutePattern()
+			}
+
+			s.countRequest(&APIUsage{
+				OrganizationID: principal.OrganizationID,
+				Username:       principal.Username,
+				TokenID:        TokenIDOf(tokenOfRequest(r)),
+				Method:         r.Method,
+				Endpoint:       endpoint,
+				Client:         clientOfRequest(r),
+			}, ww.Status(), time.Now())
+		})
+	}
+}
+
+// countRequest counts the request in the usage of its day, a status of 400 and above is an error
+func (s *APIUsageService) countRequest(request *APIUsage, status int, now time.Time) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	key := apiUsageKey{
+		organizationID: request.OrganizationID,
+		day:            day.Format("2006-01-02"),
+		username:       request.Username,
+		tokenID:        request.TokenID,
+		method:         request.Method,
+		endpoint:       request.Endpoint,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usage, ok := s.counters[key]
+	if !ok {
+		usage = &APIUsage{
+			OrganizationID: request.OrganizationID,
+			Day:            day,
+			Username:       request.Username,
+			TokenID:        request.TokenID,
+			Method:         request.Method,
+			Endpoint:       request.Endpoint,
+		}
+		s.counters[key] = usage
+	}
+
+	usage.Client = request.Client
+	usage.Requests++
+	if status >= http.StatusBadRequest {
+		usage.Errors++
+	}
+	usage.LastRequestAt = now
+}
+
+// Run writes the counted requests to the database in the flush interval until the context is done
+func (s *APIUsageService) Run(ctx context.Context) {
+	ticker := time.NewTicker(apiUsageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			err := s.Flush(context.Background())
+			if err != nil {
+				log.Printf("could not write api usage: %s", err)
+			}
+			return
+		case <-ticker.C:
+			err := s.Flush(ctx)
+			if err != nil {
+				log.Printf("could not write api usage: %s", err)
+			}
+		}
+	}
+}
+
+// Flush writes the counted requests to the database, the counts are kept for the next flush if writing fails
+func (s *APIUsageService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	counters := s.counters
+	s.counters = make(map[apiUsageKey]*APIUsage)
+	s.mu.Unlock()
+
+	if len(counters) == 0 {
+		return nil
+	}
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			for _, usage := range counters {
+				err := s.apiUsageRepository.AddAPIUsage(ctx, usage)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		s.restoreCounters(counters)
+		return err
+	}
+
+	return nil
+}
+
+// restoreCounters adds the counters that could not be written to the counters of the next flush
+func (s *APIUsageService) restoreCounters(counters map[apiUsageKey]*APIUsage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, usage := range counters {
+		current, ok := s.counters[key]
+		if !ok {
+			s.counters[key] = usage
+			continue
+		}
+
+		current.Requests += usage.Requests
+		current.Errors += usage.Errors
+		if usage.LastRequestAt.After(current.LastRequestAt) {
+			current.LastRequestAt = usage.LastRequestAt
+		}
+	}
+}
+
+// ReadAPIUsage reads the api usage of the organization, only admins see the usage of the organization
+func (s *APIUsageService) ReadAPIUsage(ctx context.Context, principal *Principal, filter *APIUsageFilter) ([]*APIUsage, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrForbidden
+	}
+
+	filter.OrganizationID = principal.OrganizationID
+	return s.apiUsageRepository.FindAPIUsage(ctx, filter)
+}
+
+// WriteAPIUsageAsCSV writes the api usage as CSV
+func (s *APIUsageService) WriteAPIUsageAsCSV(usages []*APIUsage, w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Comma = ';'
+
+	err := csvWriter.Write(apiUsageCSVHeaders)
+	if err != nil {
+		return err
+	}
+
+	for _, usage := range usages {
+		err := csvWriter.Write([]string{
+			usage.Day.Format("2006-01-02"),
+			usage.Username,
+			usage.TokenID,
+			usage.Method,
+			usage.Endpoint,
+			usage.Client,
+			strconv.Itoa(usage.Requests),
+			strconv.Itoa(usage.Errors),
+			usage.LastRequestAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// handleAPIUsageCleanupJob deletes the api usage older than the retention
+func (s *APIUsageService) handleAPIUsageCleanupJob(ctx context.Context, job *Job) error {
+	now := time.Now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -apiUsageRetentionDays)
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.apiUsageRepository.DeleteAPIUsageBefore(ctx, day)
+		},
+	)
+}
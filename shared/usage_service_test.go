@@ -0,0 +1,124 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func newTestAPIUsageService() (*APIUsageService, *InMemAPIUsageRepository) {
+	apiUsageRepository := NewInMemAPIUsageRepository()
+	jobService := NewJobService(NewInMemRepositoryTxer(), NewInMemJobRepository())
+	return NewAPIUsageService(NewInMemRepositoryTxer(), jobService, apiUsageRepository), apiUsageRepository
+}
+
+func TestUsageMiddleware(t *testing.T) {
+	is := is.New(t)
+
+	apiUsageService, _ := newTestAPIUsageService()
+	admin := &Principal{OrganizationID: OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ContextKeyPrincipal, admin)))
+		})
+	})
+	router.Use(apiUsageService.UsageMiddleware())
+	router.Get("/api/activities/{activity-id}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "activity-id") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	for _, activityID := range []string{"1", "2", "missing"} {
+		r, _ := http.NewRequest("GET", "/api/activities/"+activityID, nil)
+		r.Header.Set("Authorization", "Bearer my-token")
+		r.Header.Set("User-Agent", "my-script/1.0")
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	err := apiUsageService.Flush(context.Background())
+	is.NoErr(err)
+
+	now := time.Now().UTC()
+	usages, err := apiUsageService.ReadAPIUsage(context.Background(), admin, &APIUsageFilter{
+		From: now.AddDate(0, 0, -1),
+		To:   now,
+	})
+	is.NoErr(err)
+	is.Equal(len(usages), 1)
+	is.Equal(usages[0].Endpoint, "/api/activities/{activity-id}")
+	is.Equal(usages[0].Method, "GET")
+	is.Equal(usages[0].TokenID, TokenIDOf("my-token"))
+	is.Equal(usages[0].Client, "my-script/1.0")
+	is.Equal(usages[0].Requests, 3)
+	is.Equal(usages[0].Errors, 1)
+}
+
+func TestFlushAPIUsage(t *testing.T) {
+	is := is.New(t)
+
+	apiUsageService, apiUsageRepository := newTestAPIUsageService()
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	request := &APIUsage{OrganizationID: OrganizationIDSample, Username: "user1", TokenID: "a", Method: "GET", Endpoint: "/api/projects"}
+
+	apiUsageService.countRequest(request, http.StatusOK, now)
+	is.NoErr(apiUsageService.Flush(context.Background()))
+	apiUsageService.countRequest(request, http.StatusTooManyRequests, now.Add(time.Minute))
+	is.NoErr(apiUsageService.Flush(context.Background()))
+	is.NoErr(apiUsageService.Flush(context.Background()))
+
+	usages, err := apiUsageRepository.FindAPIUsage(context.Background(), &APIUsageFilter{
+		OrganizationID: OrganizationIDSample,
+		From:           now.AddDate(0, 0, -1),
+		To:             now,
+	})
+	is.NoErr(err)
+	is.Equal(len(usages), 1)
+	is.Equal(usages[0].Requests, 2)
+	is.Equal(usages[0].Errors, 1)
+	is.Equal(usages[0].LastRequestAt, now.Add(time.Minute))
+
+	apiUsageService.repositoryTxer = &failingRepositoryTxer{}
+	apiUsageService.countRequest(request, http.StatusOK, now)
+	is.True(apiUsageService.Flush(context.Background()) != nil)
+	is.Equal(len(apiUsageService.counters), 1)
+}
+
+func TestReadAPIUsageForbidden(t *testing.T) {
+	is := is.New(t)
+
+	apiUsageService, _ := newTestAPIUsageService()
+	_, err := apiUsageService.ReadAPIUsage(context.Background(), &Principal{OrganizationID: OrganizationIDSample, Roles: []string{"ROLE_USER"}}, &APIUsageFilter{})
+	is.True(errors.Is(err, ErrForbidden))
+}
+
+func TestWriteAPIUsageAsCSV(t *testing.T) {
+	is := is.New(t)
+
+	apiUsageService, _ := newTestAPIUsageService()
+	buf := &bytes.Buffer{}
+	err := apiUsageService.WriteAPIUsageAsCSV([]*APIUsage{
+		{Day: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Username: "user1", TokenID: "a", Method: "GET", Endpoint: "/api/projects", Client: "curl", Requests: 2, Errors: 1, LastRequestAt: time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)},
+	}, buf)
+	is.NoErr(err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	is.Equal(len(lines), 2)
+	is.Equal(lines[1], "2024-03-04;user1;a;GET;/api/projects;curl;2;1;2024-03-04T12:00:00Z")
+}
+
+type failingRepositoryTxer struct{}
+
+func (txer *failingRepositoryTxer) InTx(ctx context.Context, txFuncs ...func(ctxWithTx context.Context) error) error {
+	return errors.New("database not available")
+}
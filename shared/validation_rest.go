@@ -0,0 +1,108 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"schneider.vip/problem"
+)
+
+// InvalidParam is a field of a request which is not valid
+type InvalidParam struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewInvalidParam creates a new invalid field of a request
+func NewInvalidParam(field, code, message string) *InvalidParam {
+	return &InvalidParam{
+		Field:   field,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (p *InvalidParam) Error() string {
+	return fmt.Sprintf("%s %s", p.Field, p.Message)
+}
+
+// NewValidator creates a validator which reports fields by their json names
+func NewValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// InvalidParamsOf maps validation, decoding and parameter errors to invalid fields
+func InvalidParamsOf(err error) []*InvalidParam {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		invalidParams := make([]*InvalidParam, len(validationErrors))
+		for i, fieldError := range validationErrors {
+			invalidParams[i] = NewInvalidParam(fieldError.Field(), fieldError.Tag(), validationMessage(fieldError))
+		}
+		return invalidParams
+	}
+
+	var invalidParam *InvalidParam
+	if errors.As(err, &invalidParam) {
+		return []*InvalidParam{invalidParam}
+	}
+
+	var unmarshalTypeError *json.UnmarshalTypeError
+	if errors.As(err, &unmarshalTypeError) {
+		return []*InvalidParam{
+			NewInvalidParam(unmarshalTypeError.Field, "type", fmt.Sprintf("must be of type %s", unmarshalTypeError.Type)),
+		}
+	}
+
+	return nil
+}
+
+// RenderValidationProblemJSON renders a bad request problem with the invalid fields of the error
+func RenderValidationProblemJSON(w http.ResponseWriter, title string, err error) {
+	options := []problem.Option{
+		problem.Type(ErrInvalidParams.Type()),
+		problem.Title(title),
+		problem.Status(ErrInvalidParams.Status),
+		problem.Custom("code", ErrInvalidParams.Code),
+	}
+
+	invalidParams := InvalidParamsOf(err)
+	if len(invalidParams) > 0 {
+		options = append(options, problem.Custom("invalid-params", invalidParams))
+	} else if err != nil {
+		options = append(options, problem.Detail(err.Error()))
+	}
+
+	_, _ = problem.New(options...).WriteTo(w)
+}
+
+func validationMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s", fieldError.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", fieldError.Param())
+	case "email":
+		return "must be a valid email address"
+	case "uuid":
+		return "must be a valid id"
+	default:
+		return "is not valid"
+	}
+}
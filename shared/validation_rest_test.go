@@ -0,0 +1,58 @@
+package shared
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+type validationTestModel struct {
+	Title string `json:"title" validate:"required,min=3"`
+}
+
+func TestInvalidParamsOfValidationErrors(t *testing.T) {
+	is := is.New(t)
+
+	err := NewValidator().Struct(&validationTestModel{Title: "ab"})
+
+	invalidParams := InvalidParamsOf(err)
+	is.Equal(len(invalidParams), 1)
+	is.Equal(invalidParams[0].Field, "title")
+	is.Equal(invalidParams[0].Code, "min")
+	is.Equal(invalidParams[0].Message, "must be at least 3")
+}
+
+func TestInvalidParamsOfUnmarshalTypeError(t *testing.T) {
+	is := is.New(t)
+
+	var model validationTestModel
+	err := json.NewDecoder(strings.NewReader(`{"title": 1}`)).Decode(&model)
+
+	invalidParams := InvalidParamsOf(err)
+	is.Equal(len(invalidParams), 1)
+	is.Equal(invalidParams[0].Field, "title")
+	is.Equal(invalidParams[0].Code, "type")
+}
+
+func TestRenderValidationProblemJSON(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	RenderValidationProblemJSON(httpRec, "invalid query params", NewInvalidParam("v", "format", "must be a month like 2021-12"))
+
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	var problemModel struct {
+		Title         string          `json:"title"`
+		InvalidParams []*InvalidParam `json:"invalid-params"`
+	}
+	err := json.NewDecoder(httpRec.Body).Decode(&problemModel)
+	is.NoErr(err)
+	is.Equal(problemModel.Title, "invalid query params")
+	is.Equal(len(problemModel.InvalidParams), 1)
+	is.Equal(problemModel.InvalidParams[0].Field, "v")
+}
@@ -0,0 +1,45 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	APIVersion1 = "v1"
+	APIVersion2 = "v2"
+)
+
+// APIVersionMiddleware sets up the version of the api for the request
+func APIVersionMiddleware(version string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("API-Version", version)
+
+			ctx := context.WithValue(r.Context(), ContextKeyAPIVersion, version)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// DeprecationMiddleware marks the responses as deprecated in favour of the successor api
+func DeprecationMiddleware(successor string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// APIVersionOf reads the version of the api of the request, which defaults to the first version
+func APIVersionOf(r *http.Request) string {
+	version, ok := r.Context().Value(ContextKeyAPIVersion).(string)
+	if !ok {
+		return APIVersion1
+	}
+	return version
+}
@@ -0,0 +1,46 @@
+package shared
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestAPIVersionMiddleware(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	version := ""
+	handler := APIVersionMiddleware(APIVersion2)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version = APIVersionOf(r)
+	}))
+
+	r, _ := http.NewRequest("GET", "/api/v2/activities", nil)
+	handler.ServeHTTP(httpRec, r)
+
+	is.Equal(version, APIVersion2)
+	is.Equal(httpRec.Header().Get("API-Version"), APIVersion2)
+}
+
+func TestAPIVersionOfDefault(t *testing.T) {
+	is := is.New(t)
+
+	r, _ := http.NewRequest("GET", "/api/activities", nil)
+
+	is.Equal(APIVersionOf(r), APIVersion1)
+}
+
+func TestDeprecationMiddleware(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	handler := DeprecationMiddleware("/api/v1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	r, _ := http.NewRequest("GET", "/api/activities", nil)
+	handler.ServeHTTP(httpRec, r)
+
+	is.Equal(httpRec.Header().Get("Deprecation"), "true")
+	is.Equal(httpRec.Header().Get("Link"), `</api/v1>; rel="successor-version"`)
+}
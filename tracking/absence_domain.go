@@ -0,0 +1,174 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	AbsenceKindVacation string = "vacation"
+	AbsenceKindSick     string = "sick"
+	AbsenceKindOther    string = "other"
+)
+
+const (
+	// absenceCalendarPast is how far back the calendar feed reaches, calendars keep past events they already synced
+	absenceCalendarPast = 90 * 24 * time.Hour
+
+	// absenceCalendarFuture is how far ahead the calendar feed reaches
+	absenceCalendarFuture = 365 * 24 * time.Hour
+
+	// icsMaxLineOctets is the maximum length of a content line of the iCalendar format
+	icsMaxLineOctets = 75
+)
+
+var absenceKinds = []string{AbsenceKindVacation, AbsenceKindSick, AbsenceKindOther}
+
+var (
+	ErrAbsenceNotFound         = shared.NewDomainError("absence:not-found", http.StatusNotFound, "absence not found")
+	ErrHolidayNotFound         = shared.NewDomainError("holiday:not-found", http.StatusNotFound, "holiday not found")
+	ErrHolidayExists           = shared.NewDomainError("holiday:exists", http.StatusConflict, "holiday on the day already exists")
+	ErrAbsenceCalendarNotFound = shared.NewDomainError("absence-calendar:not-found", http.StatusNotFound, "absence calendar not found")
+)
+
+// Absence is a period of whole days a user is out, the end day is included
+type Absence struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Username       string
+	Start          time.Time
+	End            time.Time
+	Kind           string
+	CreatedAt      time.Time
+}
+
+// Holiday is a public holiday of an organization
+type Holiday struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Day            time.Time
+	Name           string
+}
+
+// AbsenceCalendar grants access to the calendar feed of the absences and holidays of an organization by a secret token,
+// so planners subscribe to it in their calendar apps. The token is looked up by its hash and stored encrypted.
+type AbsenceCalendar struct {
+	OrganizationID uuid.UUID
+	TokenHash      string
+	Token          string
+}
+
+// AbsenceCalendarFeed are the absences and holidays of the team of an organization as published in the calendar feed
+type AbsenceCalendarFeed struct {
+	Absences []*Absence
+	Holidays []*Holiday
+	Members  []*TeamMember
+}
+
+type AbsenceRepository interface {
+	FindAbsences(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*Absence, error)
+	FindAbsenceByID(ctx context.Context, organizationID, absenceID uuid.UUID) (*Absence, error)
+	InsertAbsence(ctx context.Context, absence *Absence) error
+	DeleteAbsence(ctx context.Context, organizationID, absenceID uuid.UUID) error
+	FindHolidays(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*Holiday, error)
+	InsertHoliday(ctx context.Context, holiday *Holiday) error
+	DeleteHoliday(ctx context.Context, organizationID, holidayID uuid.UUID) error
+	FindAbsenceCalendar(ctx context.Context, organizationID uuid.UUID) (*AbsenceCalendar, error)
+	FindAbsenceCalendarByTokenHash(ctx context.Context, tokenHash string) (*AbsenceCalendar, error)
+	UpdateAbsenceCalendar(ctx context.Context, absenceCalendar *AbsenceCalendar) error
+	DeleteAbsenceCalendar(ctx context.Context, organizationID uuid.UUID) error
+	FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error)
+}
+
+// IsValidAbsenceKind checks if the kind is a known kind of absence
+func IsValidAbsenceKind(kind string) bool {
+	return slices.Contains(absenceKinds, kind)
+}
+
+// ICS renders the feed in the iCalendar format with an all-day event per absence and holiday,
+// the reason of sick leaves is not published as the feed is shared beyond the team
+func (f *AbsenceCalendarFeed) ICS(now time.Time) string {
+	namesByUsername := make(map[string]string)
+	for _, member := range f.Members {
+		if member.Name != "" {
+			namesByUsername[member.Username] = member.Name
+		}
+	}
+
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//Baralga//Absences//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:Absences")
+
+	dtstamp := now.UTC().Format("20060102T150405Z")
+	for _, absence := range f.Absences {
+		name, ok := namesByUsername[absence.Username]
+		if !ok {
+			name = absence.Username
+		}
+
+		summary := fmt.Sprintf("%s out", name)
+		if absence.Kind == AbsenceKindVacation {
+			summary = fmt.Sprintf("%s on vacation", name)
+		}
+
+		writeICSEvent(&b, fmt.Sprintf("absence-%s@baralga", absence.ID), dtstamp, absence.Start, absence.End, summary)
+	}
+	for _, holiday := range f.Holidays {
+		writeICSEvent(&b, fmt.Sprintf("holiday-%s@baralga", holiday.ID), dtstamp, holiday.Day, holiday.Day, holiday.Name)
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+func writeICSEvent(b *strings.Builder, uid, dtstamp string, start, end time.Time, summary string) {
+	writeICSLine(b, "BEGIN:VEVENT")
+	writeICSLine(b, "UID:"+uid)
+	writeICSLine(b, "DTSTAMP:"+dtstamp)
+	writeICSLine(b, "DTSTART;VALUE=DATE:"+start.Format("20060102"))
+	// the end of all-day events is exclusive
+	writeICSLine(b, "DTEND;VALUE=DATE:"+end.AddDate(0, 0, 1).Format("20060102"))
+	writeICSLine(b, "SUMMARY:"+escapeICSText(summary))
+	writeICSLine(b, "TRANSP:TRANSPARENT")
+	writeICSLine(b, "END:VEVENT")
+}
+
+// writeICSLine writes the content line folded after 75 octets without splitting characters
+func writeICSLine(b *strings.Builder, line string) {
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// the space of a continuation line counts to its length
+		limit = icsMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func escapeICSText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
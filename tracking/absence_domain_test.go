@@ -0,0 +1,69 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestAbsenceCalendarFeedICS(t *testing.T) {
+	is := is.New(t)
+
+	feed := &AbsenceCalendarFeed{
+		Absences: []*Absence{
+			{
+				ID:       uuid.MustParse("00000000-0000-0000-4444-000000000001"),
+				Username: "user1",
+				Start:    time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+				End:      time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC),
+				Kind:     AbsenceKindVacation,
+			},
+			{
+				ID:       uuid.MustParse("00000000-0000-0000-4444-000000000002"),
+				Username: "user2",
+				Start:    time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+				End:      time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+				Kind:     AbsenceKindSick,
+			},
+		},
+		Holidays: []*Holiday{
+			{
+				ID:   uuid.MustParse("00000000-0000-0000-5555-000000000001"),
+				Day:  time.Date(2024, 3, 29, 0, 0, 0, 0, time.UTC),
+				Name: "Good Friday, Easter",
+			},
+		},
+		Members: []*TeamMember{
+			{Username: "user1", Name: "Ulani User"},
+		},
+	}
+
+	ics := feed.ICS(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	is.True(strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	is.True(strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	is.True(strings.Contains(ics, "UID:absence-00000000-0000-0000-4444-000000000001@baralga\r\n"))
+	is.True(strings.Contains(ics, "DTSTAMP:20240301T120000Z\r\n"))
+	is.True(strings.Contains(ics, "DTSTART;VALUE=DATE:20240304\r\nDTEND;VALUE=DATE:20240309\r\n"))
+	is.True(strings.Contains(ics, "SUMMARY:Ulani User on vacation\r\n"))
+	is.True(strings.Contains(ics, "SUMMARY:user2 out\r\n"))
+	is.True(!strings.Contains(ics, "sick"))
+	is.True(strings.Contains(ics, "SUMMARY:Good Friday\\, Easter\r\n"))
+}
+
+func TestWriteICSLineFolded(t *testing.T) {
+	is := is.New(t)
+
+	var b strings.Builder
+	writeICSLine(&b, "SUMMARY:"+strings.Repeat("ä", 50))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	is.Equal(len(lines), 2)
+	for _, line := range lines {
+		is.True(len(line) <= icsMaxLineOctets)
+	}
+	is.Equal(lines[0]+strings.TrimPrefix(lines[1], " "), "SUMMARY:"+strings.Repeat("ä", 50))
+}
@@ -0,0 +1,309 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbAbsenceRepository is a SQL database repository for absences, holidays and their calendar feeds
+type DbAbsenceRepository struct {
+	connPool  *pgxpool.Pool
+	encrypter *shared.Encrypter
+}
+
+var _ AbsenceRepository = (*DbAbsenceRepository)(nil)
+
+func init() {
+	shared.RegisterEncryptedColumn("absence_calendars", "org_id", "token")
+}
+
+// NewDbAbsenceRepository creates a new SQL database repository for absences,
+// the tokens of the calendar feeds are stored encrypted by the encrypter
+func NewDbAbsenceRepository(connPool *pgxpool.Pool, encrypter *shared.Encrypter) *DbAbsenceRepository {
+	return &DbAbsenceRepository{
+		connPool:  connPool,
+		encrypter: encrypter,
+	}
+}
+
+// FindAbsences reads the absences overlapping the days from start to end
+func (r *DbAbsenceRepository) FindAbsences(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*Absence, error) {
+	rows, err := shared.SelectAll[absenceRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[absenceRow]()+`
+		 FROM absences
+		 WHERE org_id = $1 AND end_day >= $2 AND start_day <= $3
+		 ORDER BY start_day ASC, username ASC`,
+		organizationID, start, end,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	absences := make([]*Absence, len(rows))
+	for i, row := range rows {
+		absences[i] = row.toAbsence()
+	}
+	return absences, nil
+}
+
+func (r *DbAbsenceRepository) FindAbsenceByID(ctx context.Context, organizationID, absenceID uuid.UUID) (*Absence, error) {
+	row, err := shared.SelectOne[absenceRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[absenceRow]()+`
+		 FROM absences
+		 WHERE absence_id = $1 AND org_id = $2`,
+		absenceID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAbsenceNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toAbsence(), nil
+}
+
+func (r *DbAbsenceRepository) InsertAbsence(ctx context.Context, absence *Absence) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO absences
+		   (absence_id, org_id, username, start_day, end_day, kind, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7)`,
+		absence.ID,
+		absence.OrganizationID,
+		absence.Username,
+		absence.Start,
+		absence.End,
+		absence.Kind,
+		absence.CreatedAt,
+	)
+	return err
+}
+
+func (r *DbAbsenceRepository) DeleteAbsence(ctx context.Context, organizationID, absenceID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM absences
+		 WHERE absence_id = $1 AND org_id = $2`,
+		absenceID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrAbsenceNotFound
+	}
+	return nil
+}
+
+// FindHolidays reads the holidays on the days from start to end
+func (r *DbAbsenceRepository) FindHolidays(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*Holiday, error) {
+	rows, err := shared.SelectAll[holidayRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[holidayRow]()+`
+		 FROM holidays
+		 WHERE org_id = $1 AND day >= $2 AND day <= $3
+		 ORDER BY day ASC`,
+		organizationID, start, end,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays := make([]*Holiday, len(rows))
+	for i, row := range rows {
+		holidays[i] = row.toHoliday()
+	}
+	return holidays, nil
+}
+
+func (r *DbAbsenceRepository) InsertHoliday(ctx context.Context, holiday *Holiday) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO holidays
+		   (holiday_id, org_id, day, name)
+		 VALUES
+		   ($1, $2, $3, $4)`,
+		holiday.ID,
+		holiday.OrganizationID,
+		holiday.Day,
+		holiday.Name,
+	)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrHolidayExists
+	}
+	return err
+}
+
+func (r *DbAbsenceRepository) DeleteHoliday(ctx context.Context, organizationID, holidayID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM holidays
+		 WHERE holiday_id = $1 AND org_id = $2`,
+		holidayID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrHolidayNotFound
+	}
+	return nil
+}
+
+// FindAbsenceCalendar reads the calendar feed of the organization
+func (r *DbAbsenceRepository) FindAbsenceCalendar(ctx context.Context, organizationID uuid.UUID) (*AbsenceCalendar, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT token_hash, token
+		 FROM absence_calendars
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+
+	var token string
+	absenceCalendar := &AbsenceCalendar{OrganizationID: organizationID}
+	err := row.Scan(&absenceCalendar.TokenHash, &token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAbsenceCalendarNotFound
+		}
+
+		return nil, err
+	}
+
+	absenceCalendar.Token, err = r.encrypter.Decrypt(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return absenceCalendar, nil
+}
+
+// FindAbsenceCalendarByTokenHash reads the calendar feed of the token across all organizations, the token is not decrypted
+func (r *DbAbsenceRepository) FindAbsenceCalendarByTokenHash(ctx context.Context, tokenHash string) (*AbsenceCalendar, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT org_id
+		 FROM absence_calendars
+		 WHERE token_hash = $1`,
+		tokenHash,
+	)
+
+	absenceCalendar := &AbsenceCalendar{TokenHash: tokenHash}
+	err := row.Scan(&absenceCalendar.OrganizationID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAbsenceCalendarNotFound
+		}
+
+		return nil, err
+	}
+
+	return absenceCalendar, nil
+}
+
+// UpdateAbsenceCalendar sets the calendar feed of the organization, an existing token is replaced
+func (r *DbAbsenceRepository) UpdateAbsenceCalendar(ctx context.Context, absenceCalendar *AbsenceCalendar) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	token, err := r.encrypter.Encrypt(absenceCalendar.Token)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO absence_calendars
+		   (org_id, token_hash, token)
+		 VALUES
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id) DO UPDATE
+		 SET token_hash = EXCLUDED.token_hash, token = EXCLUDED.token, created_at = now()`,
+		absenceCalendar.OrganizationID,
+		absenceCalendar.TokenHash,
+		token,
+	)
+	return err
+}
+
+func (r *DbAbsenceRepository) DeleteAbsenceCalendar(ctx context.Context, organizationID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`DELETE FROM absence_calendars
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+	return err
+}
+
+// FindTeamMembers reads the enabled users of the organization
+func (r *DbAbsenceRepository) FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error) {
+	return findTeamMembers(ctx, r.connPool, organizationID)
+}
+
+type absenceRow struct {
+	ID             uuid.UUID `db:"absence_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	Username       string    `db:"username"`
+	Start          time.Time `db:"start_day"`
+	End            time.Time `db:"end_day"`
+	Kind           string    `db:"kind"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (r *absenceRow) toAbsence() *Absence {
+	return &Absence{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		Start:          r.Start,
+		End:            r.End,
+		Kind:           r.Kind,
+		CreatedAt:      r.CreatedAt,
+	}
+}
+
+type holidayRow struct {
+	ID             uuid.UUID `db:"holiday_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	Day            time.Time `db:"day"`
+	Name           string    `db:"name"`
+}
+
+func (r *holidayRow) toHoliday() *Holiday {
+	return &Holiday{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Day:            r.Day,
+		Name:           r.Name,
+	}
+}
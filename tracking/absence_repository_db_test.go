@@ -0,0 +1,123 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestAbsenceRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	encrypter, err := shared.NewEncrypter(shared.EncryptionKeySample)
+	is.NoErr(err)
+
+	absenceRepository := NewDbAbsenceRepository(connPool, encrypter)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("InsertAndFindAbsences", func(t *testing.T) {
+		absence := &Absence{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Start:          start,
+			End:            start.AddDate(0, 0, 4),
+			Kind:           AbsenceKindVacation,
+			CreatedAt:      time.Now(),
+		}
+
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return absenceRepository.InsertAbsence(ctx, absence)
+			},
+		)
+		is.NoErr(err)
+
+		absences, err := absenceRepository.FindAbsences(context.Background(), shared.OrganizationIDSample, start.AddDate(0, 0, 4), start.AddDate(0, 0, 10))
+		is.NoErr(err)
+		is.Equal(len(absences), 1)
+		is.Equal(absences[0].Kind, AbsenceKindVacation)
+		is.True(absences[0].End.Equal(start.AddDate(0, 0, 4)))
+
+		absences, err = absenceRepository.FindAbsences(context.Background(), shared.OrganizationIDSample, start.AddDate(0, 0, 5), start.AddDate(0, 0, 10))
+		is.NoErr(err)
+		is.Equal(len(absences), 0)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return absenceRepository.DeleteAbsence(ctx, shared.OrganizationIDSample, absence.ID)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = absenceRepository.FindAbsenceByID(context.Background(), shared.OrganizationIDSample, absence.ID)
+		is.Equal(err, ErrAbsenceNotFound)
+	})
+
+	t.Run("InsertHolidayTwice", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return absenceRepository.InsertHoliday(ctx, &Holiday{ID: uuid.New(), OrganizationID: shared.OrganizationIDSample, Day: start, Name: "Holiday"})
+			},
+		)
+		is.NoErr(err)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return absenceRepository.InsertHoliday(ctx, &Holiday{ID: uuid.New(), OrganizationID: shared.OrganizationIDSample, Day: start, Name: "Holiday"})
+			},
+		)
+		is.Equal(err, ErrHolidayExists)
+
+		holidays, err := absenceRepository.FindHolidays(context.Background(), shared.OrganizationIDSample, start, start)
+		is.NoErr(err)
+		is.Equal(len(holidays), 1)
+	})
+
+	t.Run("UpdateAbsenceCalendar", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return absenceRepository.UpdateAbsenceCalendar(ctx, &AbsenceCalendar{OrganizationID: shared.OrganizationIDSample, TokenHash: shared.HashToken("my-token"), Token: "my-token"})
+			},
+		)
+		is.NoErr(err)
+
+		absenceCalendar, err := absenceRepository.FindAbsenceCalendarByTokenHash(context.Background(), shared.HashToken("my-token"))
+		is.NoErr(err)
+		is.Equal(absenceCalendar.OrganizationID, shared.OrganizationIDSample)
+
+		absenceCalendar, err = absenceRepository.FindAbsenceCalendar(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(absenceCalendar.Token, "my-token")
+	})
+}
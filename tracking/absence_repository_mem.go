@@ -0,0 +1,185 @@
+package tracking
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+type InMemAbsenceRepository struct {
+	mu               sync.Mutex
+	teamMembers      []*TeamMember
+	absences         []*Absence
+	holidays         []*Holiday
+	absenceCalendars map[uuid.UUID]*AbsenceCalendar
+}
+
+var _ AbsenceRepository = (*InMemAbsenceRepository)(nil)
+
+func NewInMemAbsenceRepository() *InMemAbsenceRepository {
+	return &InMemAbsenceRepository{
+		teamMembers: []*TeamMember{
+			{Username: "admin", Name: "Ed Admin", EMail: "admin@baralga.com", Admin: true},
+			{Username: "user1", Name: "Ulani User", EMail: "user1@baralga.com"},
+		},
+		absenceCalendars: make(map[uuid.UUID]*AbsenceCalendar),
+	}
+}
+
+func (r *InMemAbsenceRepository) FindAbsences(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*Absence, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var absences []*Absence
+	for _, absence := range r.absences {
+		if absence.OrganizationID == organizationID && !absence.End.Before(start) && !absence.Start.After(end) {
+			found := *absence
+			absences = append(absences, &found)
+		}
+	}
+	sort.Slice(absences, func(i, j int) bool {
+		return absences[i].Start.Before(absences[j].Start)
+	})
+	return absences, nil
+}
+
+func (r *InMemAbsenceRepository) FindAbsenceByID(ctx context.Context, organizationID, absenceID uuid.UUID) (*Absence, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, absence := range r.absences {
+		if absence.OrganizationID == organizationID && absence.ID == absenceID {
+			found := *absence
+			return &found, nil
+		}
+	}
+	return nil, ErrAbsenceNotFound
+}
+
+func (r *InMemAbsenceRepository) InsertAbsence(ctx context.Context, absence *Absence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *absence
+	r.absences = append(r.absences, &inserted)
+	return nil
+}
+
+func (r *InMemAbsenceRepository) DeleteAbsence(ctx context.Context, organizationID, absenceID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, absence := range r.absences {
+		if absence.OrganizationID == organizationID && absence.ID == absenceID {
+			r.absences = append(r.absences[:i], r.absences[i+1:]...)
+			return nil
+		}
+	}
+	return ErrAbsenceNotFound
+}
+
+func (r *InMemAbsenceRepository) FindHolidays(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*Holiday, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var holidays []*Holiday
+	for _, holiday := range r.holidays {
+		if holiday.OrganizationID == organizationID && !holiday.Day.Before(start) && !holiday.Day.After(end) {
+			found := *holiday
+			holidays = append(holidays, &found)
+		}
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Day.Before(holidays[j].Day)
+	})
+	return holidays, nil
+}
+
+func (r *InMemAbsenceRepository) InsertHoliday(ctx context.Context, holiday *Holiday) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, h := range r.holidays {
+		if h.OrganizationID == holiday.OrganizationID && h.Day.Equal(holiday.Day) {
+			return ErrHolidayExists
+		}
+	}
+	inserted := *holiday
+	r.holidays = append(r.holidays, &inserted)
+	return nil
+}
+
+func (r *InMemAbsenceRepository) DeleteHoliday(ctx context.Context, organizationID, holidayID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, holiday := range r.holidays {
+		if holiday.OrganizationID == organizationID && holiday.ID == holidayID {
+			r.holidays = append(r.holidays[:i], r.holidays[i+1:]...)
+			return nil
+		}
+	}
+	return ErrHolidayNotFound
+}
+
+func (r *InMemAbsenceRepository) FindAbsenceCalendar(ctx context.Context, organizationID uuid.UUID) (*AbsenceCalendar, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	absenceCalendar, ok := r.absenceCalendars[organizationID]
+	if !ok {
+		return nil, ErrAbsenceCalendarNotFound
+	}
+	found := *absenceCalendar
+	return &found, nil
+}
+
+func (r *InMemAbsenceRepository) FindAbsenceCalendarByTokenHash(ctx context.Context, tokenHash string) (*AbsenceCalendar, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, absenceCalendar := range r.absenceCalendars {
+		if absenceCalendar.TokenHash == tokenHash {
+			found := *absenceCalendar
+			return &found, nil
+		}
+	}
+	return nil, ErrAbsenceCalendarNotFound
+}
+
+func (r *InMemAbsenceRepository) UpdateAbsenceCalendar(ctx context.Context, absenceCalendar *AbsenceCalendar) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := *absenceCalendar
+	r.absenceCalendars[absenceCalendar.OrganizationID] = &updated
+	return nil
+}
+
+func (r *InMemAbsenceRepository) DeleteAbsenceCalendar(ctx context.Context, organizationID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.absenceCalendars, organizationID)
+	return nil
+}
+
+func (r *InMemAbsenceRepository) FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if organizationID != shared.OrganizationIDSample {
+		return nil, nil
+	}
+
+	teamMembers := make([]*TeamMember, len(r.teamMembers))
+	for i, teamMember := range r.teamMembers {
+		found := *teamMember
+		teamMembers[i] = &found
+	}
+	return teamMembers, nil
+}
@@ -0,0 +1,418 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+// absenceCalendarMaxAge is the time calendar apps may cache the calendar feed
+const absenceCalendarMaxAge = 15 * time.Minute
+
+type absenceModel struct {
+	ID       string     `json:"id,omitempty"`
+	Username string     `json:"username,omitempty"`
+	Start    string     `json:"start"`
+	End      string     `json:"end"`
+	Kind     string     `json:"kind"`
+	Links    *hal.Links `json:"_links,omitempty"`
+}
+
+type absencesModel struct {
+	Embedded struct {
+		AbsenceModels []*absenceModel `json:"absences"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type holidayModel struct {
+	ID    string     `json:"id,omitempty"`
+	Day   string     `json:"day"`
+	Name  string     `json:"name"`
+	Links *hal.Links `json:"_links,omitempty"`
+}
+
+type holidaysModel struct {
+	Embedded struct {
+		HolidayModels []*holidayModel `json:"holidays"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type absenceCalendarModel struct {
+	Token string     `json:"token"`
+	Links *hal.Links `json:"_links"`
+}
+
+type AbsenceRestHandlers struct {
+	config         *shared.Config
+	absenceService *AbsenceService
+}
+
+func NewAbsenceRestHandlers(config *shared.Config, absenceService *AbsenceService) *AbsenceRestHandlers {
+	return &AbsenceRestHandlers{
+		config:         config,
+		absenceService: absenceService,
+	}
+}
+
+func (a *AbsenceRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/absences", a.HandleGetAbsences())
+	r.Post("/absences", a.HandleCreateAbsence())
+	r.Delete("/absences/{absence-id}", a.HandleDeleteAbsence())
+	r.Get("/holidays", a.HandleGetHolidays())
+	r.Post("/holidays", a.HandleCreateHoliday())
+	r.Delete("/holidays/{holiday-id}", a.HandleDeleteHoliday())
+	r.Get("/absence-calendar", a.HandleGetAbsenceCalendar())
+	r.Post("/absence-calendar", a.HandleCreateAbsenceCalendar())
+	r.Delete("/absence-calendar", a.HandleDeleteAbsenceCalendar())
+}
+
+func (a *AbsenceRestHandlers) RegisterOpen(r chi.Router) {
+	r.Get("/calendars/{token}/absences.ics", a.HandleGetAbsenceCalendarFeed())
+}
+
+// HandleGetAbsences reads the absences of the team overlapping the days from start to end,
+// without days the absences of the next 30 days are read
+func (a *AbsenceRestHandlers) HandleGetAbsences() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	absenceService := a.absenceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		start, end, ok := parseDayRange(w, r)
+		if !ok {
+			return
+		}
+
+		absences, err := absenceService.ReadAbsences(r.Context(), principal, start, end)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		absenceModels := make([]*absenceModel, len(absences))
+		for i, absence := range absences {
+			absenceModels[i] = mapToAbsenceModel(absence)
+		}
+
+		absencesModel := &absencesModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		absencesModel.Embedded.AbsenceModels = absenceModels
+
+		shared.RenderJSON(w, absencesModel)
+	}
+}
+
+// HandleCreateAbsence adds an absence of the user, admins also add absences of other users
+func (a *AbsenceRestHandlers) HandleCreateAbsence() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	absenceService := a.absenceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var absenceModel absenceModel
+		err := json.NewDecoder(r.Body).Decode(&absenceModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "absence not valid", err)
+			return
+		}
+
+		start, err := time.Parse("2006-01-02", absenceModel.Start)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "absence not valid", shared.NewInvalidParam("start", "date", "start must be a date like 2024-03-04"))
+			return
+		}
+		end, err := time.Parse("2006-01-02", absenceModel.End)
+		if err != nil || end.Before(start) {
+			shared.RenderValidationProblemJSON(w, "absence not valid", shared.NewInvalidParam("end", "date", "end must be a date like 2024-03-04 not before start"))
+			return
+		}
+		if !IsValidAbsenceKind(absenceModel.Kind) {
+			shared.RenderValidationProblemJSON(w, "absence not valid", shared.NewInvalidParam("kind", "oneof", "kind must be vacation, sick or other"))
+			return
+		}
+
+		absence, err := absenceService.CreateAbsence(r.Context(), principal, &Absence{
+			Username: absenceModel.Username,
+			Start:    start,
+			End:      end,
+			Kind:     absenceModel.Kind,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToAbsenceModel(absence))
+	}
+}
+
+// HandleDeleteAbsence deletes an absence
+func (a *AbsenceRestHandlers) HandleDeleteAbsence() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	absenceService := a.absenceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		absenceID, err := uuid.Parse(chi.URLParam(r, "absence-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = absenceService.DeleteAbsence(r.Context(), principal, absenceID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleGetHolidays reads the holidays of the organization on the days from start to end,
+// without days the holidays of the next 30 days are read
+func (a *AbsenceRestHandlers) HandleGetHolidays() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	absenceService := a.absenceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		start, end, ok := parseDayRange(w, r)
+		if !ok {
+			return
+		}
+
+		holidays, err := absenceService.ReadHolidays(r.Context(), principal, start, end)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		holidayModels := make([]*holidayModel, len(holidays))
+		for i, holiday := range holidays {
+			holidayModels[i] = mapToHolidayModel(holiday)
+		}
+
+		holidaysModel := &holidaysModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		holidaysModel.Embedded.HolidayModels = holidayModels
+
+		shared.RenderJSON(w, holidaysModel)
+	}
+}
+
+// HandleCreateHoliday adds a holiday to the organization
+func (a *AbsenceRestHandlers) HandleCreateHoliday() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	absenceService := a.absenceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var holidayModel holidayModel
+		err := json.NewDecoder(r.Body).Decode(&holidayModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "holiday not valid", err)
+			return
+		}
+
+		day, err := time.Parse("2006-01-02", holidayModel.Day)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "holiday not valid", shared.NewInvalidParam("day", "date", "day must be a date like 2024-03-04"))
+			return
+		}
+		name := strings.TrimSpace(holidayModel.Name)
+		if name == "" || len(name) > 100 {
+			shared.RenderValidationProblemJSON(w, "holiday not valid", shared.NewInvalidParam("name", "max", "name must have 1 to 100 characters"))
+			return
+		}
+
+		holiday, err := absenceService.CreateHoliday(r.Context(), principal, &Holiday{
+			Day:  day,
+			Name: name,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToHolidayModel(holiday))
+	}
+}
+
+// HandleDeleteHoliday removes a holiday of the organization
+func (a *AbsenceRestHandlers) HandleDeleteHoliday() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	absenceService := a.absenceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		holidayID, err := uuid.Parse(chi.URLParam(r, "holiday-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = absenceService.DeleteHoliday(r.Context(), principal, holidayID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleGetAbsenceCalendar reads the calendar feed of the organization
+func (a *AbsenceRestHandlers) HandleGetAbsenceCalendar() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	absenceService := a.absenceService
+	webroot := a.config.Webroot
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		absenceCalendar, err := absenceService.ReadAbsenceCalendar(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToAbsenceCalendarModel(absenceCalendar, webroot))
+	}
+}
+
+// HandleCreateAbsenceCalendar creates the calendar feed of the organization with a new token
+func (a *AbsenceRestHandlers) HandleCreateAbsenceCalendar() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	absenceService := a.absenceService
+	webroot := a.config.Webroot
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		absenceCalendar, err := absenceService.CreateAbsenceCalendar(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToAbsenceCalendarModel(absenceCalendar, webroot))
+	}
+}
+
+// HandleDeleteAbsenceCalendar removes the calendar feed of the organization
+func (a *AbsenceRestHandlers) HandleDeleteAbsenceCalendar() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	absenceService := a.absenceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		err := absenceService.DeleteAbsenceCalendar(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleGetAbsenceCalendarFeed renders the absences and holidays of the organization of the token
+// as iCalendar feed to subscribe to in calendar apps
+func (a *AbsenceRestHandlers) HandleGetAbsenceCalendarFeed() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	absenceService := a.absenceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		absenceCalendarFeed, err := absenceService.ReadAbsenceCalendarFeed(r.Context(), chi.URLParam(r, "token"), now)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(absenceCalendarMaxAge.Seconds())))
+		_, _ = w.Write([]byte(absenceCalendarFeed.ICS(now)))
+	}
+}
+
+// parseDayRange parses the days of the query parameters start and end, which default to the next 30 days
+func parseDayRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 30)
+
+	var err error
+	if startParam := r.URL.Query().Get("start"); startParam != "" {
+		start, err = time.Parse("2006-01-02", startParam)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "days not valid", shared.NewInvalidParam("start", "date", "start must be a date like 2024-03-04"))
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	if endParam := r.URL.Query().Get("end"); endParam != "" {
+		end, err = time.Parse("2006-01-02", endParam)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "days not valid", shared.NewInvalidParam("end", "date", "end must be a date like 2024-03-04"))
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	return start, end, true
+}
+
+func mapToAbsenceModel(absence *Absence) *absenceModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/absences/%s", absence.ID))
+	return &absenceModel{
+		ID:       absence.ID.String(),
+		Username: absence.Username,
+		Start:    absence.Start.Format("2006-01-02"),
+		End:      absence.End.Format("2006-01-02"),
+		Kind:     absence.Kind,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+}
+
+func mapToHolidayModel(holiday *Holiday) *holidayModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/holidays/%s", holiday.ID))
+	return &holidayModel{
+		ID:   holiday.ID.String(),
+		Day:  holiday.Day.Format("2006-01-02"),
+		Name: holiday.Name,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+}
+
+func mapToAbsenceCalendarModel(absenceCalendar *AbsenceCalendar, webroot string) *absenceCalendarModel {
+	feedURL := fmt.Sprintf("%s/api/calendars/%s/absences.ics", webroot, absenceCalendar.Token)
+	return &absenceCalendarModel{
+		Token: absenceCalendar.Token,
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/absence-calendar"),
+			hal.NewLink("ics", feedURL),
+			hal.NewLink("webcal", "webcal"+strings.TrimPrefix(strings.TrimPrefix(feedURL, "https"), "http")),
+		),
+	}
+}
@@ -0,0 +1,113 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleCreateAbsence(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewAbsenceRestHandlers(&shared.Config{}, NewAbsenceService(shared.NewInMemRepositoryTxer(), NewInMemAbsenceRepository()))
+
+	r, _ := http.NewRequest("POST", "/api/absences", strings.NewReader(`{"start": "2024-03-04", "end": "2024-03-08", "kind": "vacation"}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}))
+
+	a.HandleCreateAbsence()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	var absenceModel absenceModel
+	err := json.NewDecoder(httpRec.Body).Decode(&absenceModel)
+	is.NoErr(err)
+	is.Equal(absenceModel.Username, "user1")
+	is.Equal(absenceModel.Start, "2024-03-04")
+	is.Equal(absenceModel.End, "2024-03-08")
+}
+
+func TestHandleCreateAbsenceNotValid(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewAbsenceRestHandlers(&shared.Config{}, NewAbsenceService(shared.NewInMemRepositoryTxer(), NewInMemAbsenceRepository()))
+
+	r, _ := http.NewRequest("POST", "/api/absences", strings.NewReader(`{"start": "2024-03-08", "end": "2024-03-04", "kind": "vacation"}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}))
+
+	a.HandleCreateAbsence()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleGetAbsenceCalendarFeed(t *testing.T) {
+	is := is.New(t)
+
+	absenceService := NewAbsenceService(shared.NewInMemRepositoryTxer(), NewInMemAbsenceRepository())
+	absenceCalendar, err := absenceService.CreateAbsenceCalendar(context.Background(), &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	})
+	is.NoErr(err)
+
+	a := NewAbsenceRestHandlers(&shared.Config{}, absenceService)
+	router := chi.NewRouter()
+	a.RegisterOpen(router)
+
+	t.Run("ValidToken", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/calendars/"+absenceCalendar.Token+"/absences.ics", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Header().Get("Content-Type"), "text/calendar; charset=utf-8")
+		is.True(strings.HasPrefix(httpRec.Body.String(), "BEGIN:VCALENDAR"))
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/calendars/invalid/absences.ics", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+	})
+}
+
+func TestHandleGetAbsenceCalendar(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+	absenceService := NewAbsenceService(shared.NewInMemRepositoryTxer(), NewInMemAbsenceRepository())
+	absenceCalendar, err := absenceService.CreateAbsenceCalendar(context.Background(), principal)
+	is.NoErr(err)
+
+	a := NewAbsenceRestHandlers(&shared.Config{Webroot: "https://baralga.com"}, absenceService)
+
+	r, _ := http.NewRequest("GET", "/api/absence-calendar", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+
+	a.HandleGetAbsenceCalendar()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	var absenceCalendarModel absenceCalendarModel
+	err = json.NewDecoder(httpRec.Body).Decode(&absenceCalendarModel)
+	is.NoErr(err)
+	is.Equal(absenceCalendarModel.Links.HrefOf("ics"), "https://baralga.com/api/calendars/"+absenceCalendar.Token+"/absences.ics")
+	is.Equal(absenceCalendarModel.Links.HrefOf("webcal"), "webcal://baralga.com/api/calendars/"+absenceCalendar.Token+"/absences.ics")
+}
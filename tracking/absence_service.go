@@ -0,0 +1,227 @@
+package tracking
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// absenceCalendarTokenBytes is the length of the random calendar tokens
+const absenceCalendarTokenBytes = 24
+
+// AbsenceService manages the absences and holidays of the team of an organization
+// and publishes them as calendar feed for planners
+type AbsenceService struct {
+	repositoryTxer    shared.RepositoryTxer
+	absenceRepository AbsenceRepository
+}
+
+// NewAbsenceService creates a new service for absences and holidays
+func NewAbsenceService(repositoryTxer shared.RepositoryTxer, absenceRepository AbsenceRepository) *AbsenceService {
+	return &AbsenceService{
+		repositoryTxer:    repositoryTxer,
+		absenceRepository: absenceRepository,
+	}
+}
+
+// ReadAbsences reads the absences of the team from start to end, so everybody sees who is out,
+// only admins and the absent user see the reason of a sick leave
+func (s *AbsenceService) ReadAbsences(ctx context.Context, principal *shared.Principal, start, end time.Time) ([]*Absence, error) {
+	absences, err := s.absenceRepository.FindAbsences(ctx, principal.OrganizationID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	if principal.HasRole("ROLE_ADMIN") {
+		return absences, nil
+	}
+
+	for _, absence := range absences {
+		if absence.Kind == AbsenceKindSick && absence.Username != principal.Username {
+			absence.Kind = AbsenceKindOther
+		}
+	}
+	return absences, nil
+}
+
+// CreateAbsence adds an absence of the principal, admins also add absences of other users
+func (s *AbsenceService) CreateAbsence(ctx context.Context, principal *shared.Principal, absence *Absence) (*Absence, error) {
+	if absence.Username == "" {
+		absence.Username = principal.Username
+	}
+	if absence.Username != principal.Username && !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	absence.ID = uuid.New()
+	absence.OrganizationID = principal.OrganizationID
+	absence.CreatedAt = time.Now()
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.absenceRepository.InsertAbsence(ctx, absence)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return absence, nil
+}
+
+// DeleteAbsence deletes an absence of the principal, admins also delete absences of other users
+func (s *AbsenceService) DeleteAbsence(ctx context.Context, principal *shared.Principal, absenceID uuid.UUID) error {
+	absence, err := s.absenceRepository.FindAbsenceByID(ctx, principal.OrganizationID, absenceID)
+	if err != nil {
+		return err
+	}
+	if absence.Username != principal.Username && !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.absenceRepository.DeleteAbsence(ctx, principal.OrganizationID, absenceID)
+		},
+	)
+}
+
+// ReadHolidays reads the holidays of the organization from start to end
+func (s *AbsenceService) ReadHolidays(ctx context.Context, principal *shared.Principal, start, end time.Time) ([]*Holiday, error) {
+	return s.absenceRepository.FindHolidays(ctx, principal.OrganizationID, start, end)
+}
+
+// CreateHoliday adds a holiday to the organization
+func (s *AbsenceService) CreateHoliday(ctx context.Context, principal *shared.Principal, holiday *Holiday) (*Holiday, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	holiday.ID = uuid.New()
+	holiday.OrganizationID = principal.OrganizationID
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.absenceRepository.InsertHoliday(ctx, holiday)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+// DeleteHoliday removes a holiday of the organization
+func (s *AbsenceService) DeleteHoliday(ctx context.Context, principal *shared.Principal, holidayID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.absenceRepository.DeleteHoliday(ctx, principal.OrganizationID, holidayID)
+		},
+	)
+}
+
+// ReadAbsenceCalendar reads the calendar feed of the organization, only admins see the token
+func (s *AbsenceService) ReadAbsenceCalendar(ctx context.Context, principal *shared.Principal) (*AbsenceCalendar, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.absenceRepository.FindAbsenceCalendar(ctx, principal.OrganizationID)
+}
+
+// CreateAbsenceCalendar creates the calendar feed with a new token, a previous token is no longer valid
+func (s *AbsenceService) CreateAbsenceCalendar(ctx context.Context, principal *shared.Principal) (*AbsenceCalendar, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	token, err := newAbsenceCalendarToken()
+	if err != nil {
+		return nil, err
+	}
+
+	absenceCalendar := &AbsenceCalendar{
+		OrganizationID: principal.OrganizationID,
+		TokenHash:      shared.HashToken(token),
+		Token:          token,
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.absenceRepository.UpdateAbsenceCalendar(ctx, absenceCalendar)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return absenceCalendar, nil
+}
+
+// DeleteAbsenceCalendar removes the calendar feed of the organization
+func (s *AbsenceService) DeleteAbsenceCalendar(ctx context.Context, principal *shared.Principal) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.absenceRepository.DeleteAbsenceCalendar(ctx, principal.OrganizationID)
+		},
+	)
+}
+
+// ReadAbsenceCalendarFeed reads the absences and holidays around now of the organization of the calendar token
+func (s *AbsenceService) ReadAbsenceCalendarFeed(ctx context.Context, token string, now time.Time) (*AbsenceCalendarFeed, error) {
+	absenceCalendar, err := s.absenceRepository.FindAbsenceCalendarByTokenHash(ctx, shared.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = shared.WithOrganizationID(ctx, absenceCalendar.OrganizationID)
+
+	start := now.Add(-absenceCalendarPast)
+	end := now.Add(absenceCalendarFuture)
+
+	absences, err := s.absenceRepository.FindAbsences(ctx, absenceCalendar.OrganizationID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.absenceRepository.FindHolidays(ctx, absenceCalendar.OrganizationID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.absenceRepository.FindTeamMembers(ctx, absenceCalendar.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AbsenceCalendarFeed{
+		Absences: absences,
+		Holidays: holidays,
+		Members:  members,
+	}, nil
+}
+
+func newAbsenceCalendarToken() (string, error) {
+	token := make([]byte, absenceCalendarTokenBytes)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
@@ -0,0 +1,130 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestAbsences(t *testing.T) {
+	is := is.New(t)
+
+	s := NewAbsenceService(shared.NewInMemRepositoryTxer(), NewInMemAbsenceRepository())
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	other := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user2"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	absence, err := s.CreateAbsence(context.Background(), user, &Absence{
+		Start: start,
+		End:   start.AddDate(0, 0, 2),
+		Kind:  AbsenceKindSick,
+	})
+	is.NoErr(err)
+	is.Equal(absence.Username, "user1")
+
+	t.Run("CreateAbsenceOfOtherUser", func(t *testing.T) {
+		_, err := s.CreateAbsence(context.Background(), other, &Absence{Username: "user1", Start: start, End: start, Kind: AbsenceKindOther})
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	t.Run("ReadAbsencesHidesSickLeave", func(t *testing.T) {
+		absences, err := s.ReadAbsences(context.Background(), other, start, start.AddDate(0, 0, 7))
+		is.NoErr(err)
+		is.Equal(len(absences), 1)
+		is.Equal(absences[0].Kind, AbsenceKindOther)
+
+		absences, err = s.ReadAbsences(context.Background(), admin, start, start.AddDate(0, 0, 7))
+		is.NoErr(err)
+		is.Equal(absences[0].Kind, AbsenceKindSick)
+	})
+
+	t.Run("ReadAbsencesOverlapping", func(t *testing.T) {
+		absences, err := s.ReadAbsences(context.Background(), user, start.AddDate(0, 0, 2), start.AddDate(0, 0, 7))
+		is.NoErr(err)
+		is.Equal(len(absences), 1)
+
+		absences, err = s.ReadAbsences(context.Background(), user, start.AddDate(0, 0, 3), start.AddDate(0, 0, 7))
+		is.NoErr(err)
+		is.Equal(len(absences), 0)
+	})
+
+	t.Run("DeleteAbsenceOfOtherUser", func(t *testing.T) {
+		err := s.DeleteAbsence(context.Background(), other, absence.ID)
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	err = s.DeleteAbsence(context.Background(), admin, absence.ID)
+	is.NoErr(err)
+}
+
+func TestHolidays(t *testing.T) {
+	is := is.New(t)
+
+	s := NewAbsenceService(shared.NewInMemRepositoryTxer(), NewInMemAbsenceRepository())
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	day := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.CreateHoliday(context.Background(), user, &Holiday{Day: day, Name: "Christmas"})
+	is.Equal(err, shared.ErrForbidden)
+
+	holiday, err := s.CreateHoliday(context.Background(), admin, &Holiday{Day: day, Name: "Christmas"})
+	is.NoErr(err)
+
+	_, err = s.CreateHoliday(context.Background(), admin, &Holiday{Day: day, Name: "Christmas Day"})
+	is.Equal(err, ErrHolidayExists)
+
+	holidays, err := s.ReadHolidays(context.Background(), user, day, day)
+	is.NoErr(err)
+	is.Equal(len(holidays), 1)
+
+	err = s.DeleteHoliday(context.Background(), admin, holiday.ID)
+	is.NoErr(err)
+}
+
+func TestAbsenceCalendarFeed(t *testing.T) {
+	is := is.New(t)
+
+	s := NewAbsenceService(shared.NewInMemRepositoryTxer(), NewInMemAbsenceRepository())
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	_, err := s.CreateAbsence(context.Background(), user, &Absence{Start: now.AddDate(0, 0, 3), End: now.AddDate(0, 0, 5), Kind: AbsenceKindVacation})
+	is.NoErr(err)
+	_, err = s.CreateAbsence(context.Background(), user, &Absence{Start: now.AddDate(-1, 0, 0), End: now.AddDate(-1, 0, 0), Kind: AbsenceKindVacation})
+	is.NoErr(err)
+
+	_, err = s.CreateAbsenceCalendar(context.Background(), user)
+	is.Equal(err, shared.ErrForbidden)
+
+	absenceCalendar, err := s.CreateAbsenceCalendar(context.Background(), admin)
+	is.NoErr(err)
+
+	absenceCalendarFeed, err := s.ReadAbsenceCalendarFeed(context.Background(), absenceCalendar.Token, now)
+	is.NoErr(err)
+	is.Equal(len(absenceCalendarFeed.Absences), 1)
+	is.Equal(len(absenceCalendarFeed.Members), 2)
+
+	t.Run("RenewedToken", func(t *testing.T) {
+		renewedCalendar, err := s.CreateAbsenceCalendar(context.Background(), admin)
+		is.NoErr(err)
+		is.True(renewedCalendar.Token != absenceCalendar.Token)
+
+		_, err = s.ReadAbsenceCalendarFeed(context.Background(), absenceCalendar.Token, now)
+		is.Equal(err, ErrAbsenceCalendarNotFound)
+	})
+
+	t.Run("DeletedCalendar", func(t *testing.T) {
+		err := s.DeleteAbsenceCalendar(context.Background(), admin)
+		is.NoErr(err)
+
+		_, err = s.ReadAbsenceCalendar(context.Background(), admin)
+		is.Equal(err, ErrAbsenceCalendarNotFound)
+	})
+}
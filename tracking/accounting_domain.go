@@ -0,0 +1,150 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AccountingFormatDATEV      = "datev"
+	AccountingFormatQuickBooks = "quickbooks"
+	AccountingFormatXero       = "xero"
+)
+
+const (
+	// defaultRevenueAccount and defaultReceivableAccount are the accounts for revenue and receivables of the SKR03
+	defaultRevenueAccount    = "8400"
+	defaultReceivableAccount = "1400"
+
+	// defaultTaxAccount is the account for the value added tax of the SKR03
+	defaultTaxAccount = "1776"
+
+	// accountingPaymentTermDays are the days after the end of the month the exported statements are due
+	accountingPaymentTermDays = 14
+)
+
+// AccountMapping maps the billable time of an organization to the accounts of its accounting system,
+// clients without an account of their own are booked on the receivable account
+type AccountMapping struct {
+	OrganizationID       uuid.UUID
+	RevenueAccount       string
+	ReceivableAccount    string
+	TaxAccount           string
+	TaxCode              string
+	ReverseChargeTaxCode string
+	DATEVConsultant      int
+	DATEVClient          int
+	ClientAccounts       map[uuid.UUID]string
+}
+
+// AccountingInvoice is the monthly statement of a client as invoice for the accounting system
+type AccountingInvoice struct {
+	Number    string
+	Day       time.Time
+	DueDay    time.Time
+	Statement *ClientStatement
+	Tax       *TaxRate
+}
+
+type AccountingRepository interface {
+	FindAccountMapping(ctx context.Context, organizationID uuid.UUID) (*AccountMapping, error)
+	UpdateAccountMapping(ctx context.Context, accountMapping *AccountMapping) error
+}
+
+// IsValidAccountingFormat checks whether the billable time can be exported in the format
+func IsValidAccountingFormat(format string) bool {
+	return format == AccountingFormatDATEV || format == AccountingFormatQuickBooks || format == AccountingFormatXero
+}
+
+// NewAccountMapping creates the account mapping of an organization with the default accounts
+func NewAccountMapping(organizationID uuid.UUID) *AccountMapping {
+	return &AccountMapping{
+		OrganizationID:    organizationID,
+		RevenueAccount:    defaultRevenueAccount,
+		ReceivableAccount: defaultReceivableAccount,
+		TaxAccount:        defaultTaxAccount,
+		ClientAccounts:    make(map[uuid.UUID]string),
+	}
+}
+
+// ClientAccount is the account of the client or the receivable account if the client has none
+func (m *AccountMapping) ClientAccount(clientID uuid.UUID) string {
+	if account, ok := m.ClientAccounts[clientID]; ok && account != "" {
+		return account
+	}
+	return m.ReceivableAccount
+}
+
+// TaxCodeOf is the tax code for bookings of the invoice
+func (m *AccountMapping) TaxCodeOf(invoice *AccountingInvoice) string {
+	if invoice.Tax.ReverseCharge {
+		return m.ReverseChargeTaxCode
+	}
+	return m.TaxCode
+}
+
+// NewAccountingInvoices creates an invoice dated the last day of the month for each statement with billable amount,
+// taxed at the rate of the client
+func NewAccountingInvoices(month time.Time, statements []*ClientStatement, taxSettings *TaxSettings) []*AccountingInvoice {
+	day := month.AddDate(0, 1, -1)
+
+	var invoices []*AccountingInvoice
+	for _, statement := range statements {
+		if statement.TotalAmountCents() <= 0 {
+			continue
+		}
+		invoices = append(invoices, &AccountingInvoice{
+			Number:    fmt.Sprintf("%s-%02d", month.Format("2006-01"), len(invoices)+1),
+			Day:       day,
+			DueDay:    day.AddDate(0, 0, accountingPaymentTermDays),
+			Statement: statement,
+			Tax:       taxSettings.TaxRateOf(statement.Client.ID),
+		})
+	}
+	return invoices
+}
+
+// BillableItems are the billable items of the statement of the invoice
+func (i *AccountingInvoice) BillableItems() []*ClientReportItem {
+	var items []*ClientReportItem
+	for _, item := range i.Statement.Items {
+		if item.Billable && item.DurationInMinutesTotal > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// NetAmountCents is the billable amount of the invoice without taxes
+func (i *AccountingInvoice) NetAmountCents() int {
+	return i.Statement.TotalAmountCents()
+}
+
+// TaxAmountCents is the tax on the net amount of the invoice
+func (i *AccountingInvoice) TaxAmountCents() int {
+	return i.Tax.TaxCents(i.NetAmountCents())
+}
+
+// GrossAmountCents is the billable amount of the invoice including taxes
+func (i *AccountingInvoice) GrossAmountCents() int {
+	return i.NetAmountCents() + i.TaxAmountCents()
+}
+
+// LineTaxCents is the tax of each billable item, the tax is rounded on the net amount of the invoice
+// so the rounding difference is added to the last item to match the tax of the invoice
+func (i *AccountingInvoice) LineTaxCents() []int {
+	items := i.BillableItems()
+	lineTaxCents := make([]int, len(items))
+	total := 0
+	for j, item := range items {
+		lineTaxCents[j] = i.Tax.TaxCents(i.Statement.AmountCents(item))
+		total += lineTaxCents[j]
+	}
+	if len(items) > 0 {
+		lineTaxCents[len(items)-1] += i.TaxAmountCents() - total
+	}
+	return lineTaxCents
+}
@@ -0,0 +1,49 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestNewAccountingInvoices(t *testing.T) {
+	is := is.New(t)
+
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	statements := []*ClientStatement{
+		{
+			Client: &Client{ID: uuid.New(), Name: "ACME", HourlyRateCents: 10000},
+			Items: []*ClientReportItem{
+				{ProjectTitle: "Web", Billable: true, DurationInMinutesTotal: 90},
+				{ProjectTitle: "Internal", DurationInMinutesTotal: 30},
+			},
+		},
+		{
+			Client: &Client{ID: uuid.New(), Name: "Initech", HourlyRateCents: 10000},
+			Items: []*ClientReportItem{
+				{ProjectTitle: "Internal", DurationInMinutesTotal: 30},
+			},
+		},
+	}
+
+	invoices := NewAccountingInvoices(month, statements, NewTaxSettings(uuid.New()))
+	is.Equal(len(invoices), 1)
+	is.Equal(invoices[0].Number, "2024-03-01")
+	is.Equal(invoices[0].Day, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+	is.Equal(invoices[0].DueDay, time.Date(2024, 4, 14, 0, 0, 0, 0, time.UTC))
+	is.Equal(len(invoices[0].BillableItems()), 1)
+	is.Equal(invoices[0].TaxAmountCents(), 0)
+}
+
+func TestAccountMappingClientAccount(t *testing.T) {
+	is := is.New(t)
+
+	clientID := uuid.New()
+	accountMapping := NewAccountMapping(uuid.New())
+	accountMapping.ClientAccounts[clientID] = "10001"
+
+	is.Equal(accountMapping.ClientAccount(clientID), "10001")
+	is.Equal(accountMapping.ClientAccount(uuid.New()), defaultReceivableAccount)
+}
@@ -0,0 +1,140 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbAccountingRepository is a SQL database repository for the account mappings of the accounting export
+type DbAccountingRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ AccountingRepository = (*DbAccountingRepository)(nil)
+
+// NewDbAccountingRepository creates a new SQL database repository for account mappings
+func NewDbAccountingRepository(connPool *pgxpool.Pool) *DbAccountingRepository {
+	return &DbAccountingRepository{
+		connPool: connPool,
+	}
+}
+
+// FindAccountMapping reads the account mapping of the organization, the default accounts if none is set
+func (r *DbAccountingRepository) FindAccountMapping(ctx context.Context, organizationID uuid.UUID) (*AccountMapping, error) {
+	accountMapping := NewAccountMapping(organizationID)
+
+	row, err := shared.SelectOne[accountingSettingsRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[accountingSettingsRow]()+`
+		 FROM accounting_settings
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		accountMapping.RevenueAccount = row.RevenueAccount
+		accountMapping.ReceivableAccount = row.ReceivableAccount
+		accountMapping.TaxAccount = row.TaxAccount
+		accountMapping.TaxCode = row.TaxCode
+		accountMapping.ReverseChargeTaxCode = row.ReverseChargeTaxCode
+		accountMapping.DATEVConsultant = row.DATEVConsultant
+		accountMapping.DATEVClient = row.DATEVClient
+	}
+
+	clientAccountRows, err := shared.SelectAll[clientAccountRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[clientAccountRow]()+`
+		 FROM client_accounts
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, clientAccountRow := range clientAccountRows {
+		accountMapping.ClientAccounts[clientAccountRow.ClientID] = clientAccountRow.Account
+	}
+
+	return accountMapping, nil
+}
+
+// UpdateAccountMapping sets the accounts of the organization and replaces the accounts of its clients
+func (r *DbAccountingRepository) UpdateAccountMapping(ctx context.Context, accountMapping *AccountMapping) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO accounting_settings
+		   (org_id, revenue_account, receivable_account, tax_account, tax_code, reverse_charge_tax_code, datev_consultant, datev_client)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (org_id) DO UPDATE
+		 SET revenue_account = EXCLUDED.revenue_account, receivable_account = EXCLUDED.receivable_account,
+		     tax_account = EXCLUDED.tax_account, tax_code = EXCLUDED.tax_code, reverse_charge_tax_code = EXCLUDED.reverse_charge_tax_code,
+		     datev_consultant = EXCLUDED.datev_consultant, datev_client = EXCLUDED.datev_client`,
+		accountMapping.OrganizationID,
+		accountMapping.RevenueAccount,
+		accountMapping.ReceivableAccount,
+		accountMapping.TaxAccount,
+		accountMapping.TaxCode,
+		accountMapping.ReverseChargeTaxCode,
+		accountMapping.DATEVConsultant,
+		accountMapping.DATEVClient,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`DELETE FROM client_accounts
+		 WHERE org_id = $1`,
+		accountMapping.OrganizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	for clientID, account := range accountMapping.ClientAccounts {
+		_, err := tx.Exec(
+			ctx,
+			`INSERT INTO client_accounts
+			   (client_id, org_id, account)
+			 VALUES
+			   ($1, $2, $3)`,
+			clientID,
+			accountMapping.OrganizationID,
+			account,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type accountingSettingsRow struct {
+	OrganizationID       uuid.UUID `db:"org_id"`
+	RevenueAccount       string    `db:"revenue_account"`
+	ReceivableAccount    string    `db:"receivable_account"`
+	TaxCode              string    `db:"tax_code"`
+	DATEVConsultant      int       `db:"datev_consultant"`
+	DATEVClient          int       `db:"datev_client"`
+	TaxAccount           string    `db:"tax_account"`
+	ReverseChargeTaxCode string    `db:"reverse_charge_tax_code"`
+}
+
+type clientAccountRow struct {
+	ClientID       uuid.UUID `db:"client_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	Account        string    `db:"account"`
+}
@@ -0,0 +1,79 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestAccountingRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	clientRepository := NewDbClientRepository(connPool)
+	accountingRepository := NewDbAccountingRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("FindAccountMappingWithDefaults", func(t *testing.T) {
+		accountMapping, err := accountingRepository.FindAccountMapping(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(accountMapping.RevenueAccount, defaultRevenueAccount)
+		is.Equal(len(accountMapping.ClientAccounts), 0)
+	})
+
+	t.Run("UpdateAccountMapping", func(t *testing.T) {
+		client := &Client{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			Name:           "ACME Corp.",
+			Currency:       "EUR",
+		}
+
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := clientRepository.InsertClient(ctx, client)
+				if err != nil {
+					return err
+				}
+				return accountingRepository.UpdateAccountMapping(ctx, &AccountMapping{
+					OrganizationID:    shared.OrganizationIDSample,
+					RevenueAccount:    "8400",
+					ReceivableAccount: "1400",
+					TaxCode:           "3",
+					DATEVConsultant:   1001,
+					DATEVClient:       1,
+					ClientAccounts:    map[uuid.UUID]string{client.ID: "10001"},
+				})
+			},
+		)
+		is.NoErr(err)
+
+		accountMapping, err := accountingRepository.FindAccountMapping(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(accountMapping.TaxCode, "3")
+		is.Equal(accountMapping.DATEVConsultant, 1001)
+		is.Equal(accountMapping.ClientAccount(client.ID), "10001")
+	})
+}
@@ -0,0 +1,49 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemAccountingRepository struct {
+	mu              sync.Mutex
+	accountMappings map[uuid.UUID]*AccountMapping
+}
+
+var _ AccountingRepository = (*InMemAccountingRepository)(nil)
+
+func NewInMemAccountingRepository() *InMemAccountingRepository {
+	return &InMemAccountingRepository{
+		accountMappings: make(map[uuid.UUID]*AccountMapping),
+	}
+}
+
+func (r *InMemAccountingRepository) FindAccountMapping(ctx context.Context, organizationID uuid.UUID) (*AccountMapping, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	accountMapping, ok := r.accountMappings[organizationID]
+	if !ok {
+		return NewAccountMapping(organizationID), nil
+	}
+	return copyAccountMapping(accountMapping), nil
+}
+
+func (r *InMemAccountingRepository) UpdateAccountMapping(ctx context.Context, accountMapping *AccountMapping) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.accountMappings[accountMapping.OrganizationID] = copyAccountMapping(accountMapping)
+	return nil
+}
+
+func copyAccountMapping(accountMapping *AccountMapping) *AccountMapping {
+	copied := *accountMapping
+	copied.ClientAccounts = make(map[uuid.UUID]string, len(accountMapping.ClientAccounts))
+	for clientID, account := range accountMapping.ClientAccounts {
+		copied.ClientAccounts[clientID] = account
+	}
+	return &copied
+}
@@ -0,0 +1,582 @@
+package tracking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/baralga/shared/pdf"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+// accountingFileTypes are the content type and file extension of each accounting format
+var accountingFileTypes = map[string][2]string{
+	AccountingFormatDATEV:      {"text/csv", "csv"},
+	AccountingFormatQuickBooks: {"application/x-iif", "iif"},
+	AccountingFormatXero:       {"text/csv", "csv"},
+}
+
+type clientAccountModel struct {
+	ClientID string `json:"clientId" validate:"required,uuid"`
+	Account  string `json:"account" validate:"required,max=50"`
+}
+
+type accountMappingModel struct {
+	RevenueAccount       string                `json:"revenueAccount" validate:"required,max=50"`
+	ReceivableAccount    string                `json:"receivableAccount" validate:"required,max=50"`
+	TaxAccount           string                `json:"taxAccount" validate:"max=50"`
+	TaxCode              string                `json:"taxCode" validate:"max=20"`
+	ReverseChargeTaxCode string                `json:"reverseChargeTaxCode" validate:"max=20"`
+	DATEVConsultant      int                   `json:"datevConsultant" validate:"min=0,max=9999999"`
+	DATEVClient          int                   `json:"datevClient" validate:"min=0,max=99999"`
+	ClientAccounts       []*clientAccountModel `json:"clientAccounts" validate:"dive"`
+	Links                *hal.Links            `json:"_links,omitempty"`
+}
+
+type clientTaxRateModel struct {
+	ClientID        string `json:"clientId" validate:"required,uuid"`
+	RateBasisPoints int    `json:"rateBasisPoints" validate:"min=0,max=10000"`
+	ReverseCharge   bool   `json:"reverseCharge"`
+	VATID           string `json:"vatId" validate:"required_if=ReverseCharge true,max=30"`
+}
+
+type taxSettingsModel struct {
+	RateBasisPoints int                   `json:"rateBasisPoints" validate:"min=0,max=10000"`
+	ClientTaxRates  []*clientTaxRateModel `json:"clientTaxRates" validate:"dive"`
+	Links           *hal.Links            `json:"_links,omitempty"`
+}
+
+type vatSummaryItemModel struct {
+	RateBasisPoints int    `json:"rateBasisPoints"`
+	ReverseCharge   bool   `json:"reverseCharge"`
+	Currency        string `json:"currency"`
+	Invoices        int    `json:"invoices"`
+	NetAmount       string `json:"netAmount"`
+	TaxAmount       string `json:"taxAmount"`
+	GrossAmount     string `json:"grossAmount"`
+}
+
+type vatSummaryModel struct {
+	Month string                 `json:"month"`
+	Items []*vatSummaryItemModel `json:"items"`
+	Links *hal.Links             `json:"_links,omitempty"`
+}
+
+type eInvoiceSettingsModel struct {
+	SellerName   string     `json:"sellerName" validate:"required,max=100"`
+	Street       string     `json:"street" validate:"required,max=100"`
+	Postcode     string     `json:"postcode" validate:"required,max=20"`
+	City         string     `json:"city" validate:"required,max=100"`
+	CountryCode  string     `json:"countryCode" validate:"required,iso3166_1_alpha2"`
+	VATID        string     `json:"vatId" validate:"required,max=30"`
+	ContactName  string     `json:"contactName" validate:"required,max=100"`
+	ContactPhone string     `json:"contactPhone" validate:"required,max=50"`
+	ContactEMail string     `json:"contactEmail" validate:"required,email,max=255"`
+	IBAN         string     `json:"iban" validate:"required,max=34"`
+	Links        *hal.Links `json:"_links,omitempty"`
+}
+
+type clientEInvoiceDetailsModel struct {
+	BuyerReference string     `json:"buyerReference" validate:"required,max=100"`
+	Street         string     `json:"street" validate:"required,max=100"`
+	Postcode       string     `json:"postcode" validate:"required,max=20"`
+	City           string     `json:"city" validate:"required,max=100"`
+	CountryCode    string     `json:"countryCode" validate:"required,iso3166_1_alpha2"`
+	Links          *hal.Links `json:"_links,omitempty"`
+}
+
+type AccountingRestHandlers struct {
+	config            *shared.Config
+	accountingService *AccountingService
+}
+
+func NewAccountingRestHandlers(config *shared.Config, accountingService *AccountingService) *AccountingRestHandlers {
+	return &AccountingRestHandlers{
+		config:            config,
+		accountingService: accountingService,
+	}
+}
+
+func (a *AccountingRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/accounting/mapping", a.HandleGetAccountMapping())
+	r.Put("/accounting/mapping", a.HandleUpdateAccountMapping())
+	r.Get("/accounting/exports/{month}", a.HandleAccountingExport())
+	r.Get("/accounting/tax-settings", a.HandleGetTaxSettings())
+	r.Put("/accounting/tax-settings", a.HandleUpdateTaxSettings())
+	r.Get("/accounting/vat-summary/{month}", a.HandleVATSummary())
+	r.Get("/accounting/e-invoice-settings", a.HandleGetEInvoiceSettings())
+	r.Put("/accounting/e-invoice-settings", a.HandleUpdateEInvoiceSettings())
+	r.Get("/accounting/e-invoices/{month}/{client-id}", a.HandleEInvoice())
+	r.Get("/clients/{client-id}/e-invoice-details", a.HandleGetClientEInvoiceDetails())
+	r.Put("/clients/{client-id}/e-invoice-details", a.HandleUpdateClientEInvoiceDetails())
+}
+
+func (a *AccountingRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetAccountMapping reads the accounts the billable time is exported with
+func (a *AccountingRestHandlers) HandleGetAccountMapping() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		accountMapping, err := accountingService.ReadAccountMapping(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToAccountMappingModel(accountMapping))
+	}
+}
+
+// HandleUpdateAccountMapping sets the accounts of the organization and of its clients
+func (a *AccountingRestHandlers) HandleUpdateAccountMapping() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var accountMappingModel accountMappingModel
+		err := json.NewDecoder(r.Body).Decode(&accountMappingModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "account mapping not valid", err)
+			return
+		}
+
+		err = validator.Struct(accountMappingModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "account mapping not valid", err)
+			return
+		}
+
+		accountMappingToUpdate := &AccountMapping{
+			RevenueAccount:       accountMappingModel.RevenueAccount,
+			ReceivableAccount:    accountMappingModel.ReceivableAccount,
+			TaxAccount:           accountMappingModel.TaxAccount,
+			TaxCode:              accountMappingModel.TaxCode,
+			ReverseChargeTaxCode: accountMappingModel.ReverseChargeTaxCode,
+			DATEVConsultant:      accountMappingModel.DATEVConsultant,
+			DATEVClient:          accountMappingModel.DATEVClient,
+			ClientAccounts:       make(map[uuid.UUID]string, len(accountMappingModel.ClientAccounts)),
+		}
+		if accountMappingToUpdate.TaxAccount == "" {
+			accountMappingToUpdate.TaxAccount = defaultTaxAccount
+		}
+		for _, clientAccountModel := range accountMappingModel.ClientAccounts {
+			accountMappingToUpdate.ClientAccounts[uuid.MustParse(clientAccountModel.ClientID)] = clientAccountModel.Account
+		}
+
+		accountMapping, err := accountingService.UpdateAccountMapping(r.Context(), principal, accountMappingToUpdate)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToAccountMappingModel(accountMapping))
+	}
+}
+
+// HandleAccountingExport downloads the billable time of the month as invoices for the accounting system
+// of the query param format, which is datev, quickbooks or xero
+func (a *AccountingRestHandlers) HandleAccountingExport() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		month, err := time.Parse("2006-01", chi.URLParam(r, "month"))
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid month", shared.NewInvalidParam("month", "month", "must be a month like 2024-03"))
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if !IsValidAccountingFormat(format) {
+			shared.RenderValidationProblemJSON(w, "invalid query params", shared.NewInvalidParam("format", "oneof", "format must be datev, quickbooks or xero"))
+			return
+		}
+
+		buf := &bytes.Buffer{}
+		err = accountingService.WriteAccountingExport(r.Context(), principal, format, month, buf)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		fileType := accountingFileTypes[format]
+		w.Header().Set("Content-Type", fileType[0])
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("Accounting_%s_%s.%s", format, month.Format("2006-01"), fileType[1])))
+		_, _ = buf.WriteTo(w)
+	}
+}
+
+// HandleGetTaxSettings reads the default tax rate of the organization and the tax rates of its clients
+func (a *AccountingRestHandlers) HandleGetTaxSettings() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		taxSettings, err := accountingService.ReadTaxSettings(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToTaxSettingsModel(taxSettings))
+	}
+}
+
+// HandleUpdateTaxSettings sets the default tax rate of the organization and the tax rates of its clients
+func (a *AccountingRestHandlers) HandleUpdateTaxSettings() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var taxSettingsModel taxSettingsModel
+		err := json.NewDecoder(r.Body).Decode(&taxSettingsModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "tax settings not valid", err)
+			return
+		}
+
+		err = validator.Struct(taxSettingsModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "tax settings not valid", err)
+			return
+		}
+
+		taxSettingsToUpdate := &TaxSettings{
+			RateBasisPoints: taxSettingsModel.RateBasisPoints,
+			ClientTaxRates:  make(map[uuid.UUID]*ClientTaxRate, len(taxSettingsModel.ClientTaxRates)),
+		}
+		for _, clientTaxRateModel := range taxSettingsModel.ClientTaxRates {
+			taxSettingsToUpdate.ClientTaxRates[uuid.MustParse(clientTaxRateModel.ClientID)] = &ClientTaxRate{
+				RateBasisPoints: clientTaxRateModel.RateBasisPoints,
+				ReverseCharge:   clientTaxRateModel.ReverseCharge,
+				VATID:           clientTaxRateModel.VATID,
+			}
+		}
+
+		taxSettings, err := accountingService.UpdateTaxSettings(r.Context(), principal, taxSettingsToUpdate)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToTaxSettingsModel(taxSettings))
+	}
+}
+
+// HandleVATSummary reads the net amounts and taxes of the invoices of the month per currency and tax rate
+func (a *AccountingRestHandlers) HandleVATSummary() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		month, err := time.Parse("2006-01", chi.URLParam(r, "month"))
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid month", shared.NewInvalidParam("month", "month", "must be a month like 2024-03"))
+			return
+		}
+
+		vatSummaryItems, err := accountingService.VATSummary(r.Context(), principal, month)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		vatSummaryModel := &vatSummaryModel{
+			Month: month.Format("2006-01"),
+			Items: make([]*vatSummaryItemModel, len(vatSummaryItems)),
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		for i, item := range vatSummaryItems {
+			vatSummaryModel.Items[i] = &vatSummaryItemModel{
+				RateBasisPoints: item.RateBasisPoints,
+				ReverseCharge:   item.ReverseCharge,
+				Currency:        item.Currency,
+				Invoices:        item.Invoices,
+				NetAmount:       formatCents(item.NetAmountCents),
+				TaxAmount:       formatCents(item.TaxAmountCents),
+				GrossAmount:     formatCents(item.NetAmountCents + item.TaxAmountCents),
+			}
+		}
+
+		shared.RenderJSON(w, vatSummaryModel)
+	}
+}
+
+// HandleGetEInvoiceSettings reads the details of the organization as seller on electronic invoices
+func (a *AccountingRestHandlers) HandleGetEInvoiceSettings() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		settings, err := accountingService.ReadEInvoiceSettings(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToEInvoiceSettingsModel(settings))
+	}
+}
+
+// HandleUpdateEInvoiceSettings sets the details of the organization as seller on electronic invoices
+func (a *AccountingRestHandlers) HandleUpdateEInvoiceSettings() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var settingsModel eInvoiceSettingsModel
+		err := json.NewDecoder(r.Body).Decode(&settingsModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "e-invoice settings not valid", err)
+			return
+		}
+
+		err = validator.Struct(settingsModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "e-invoice settings not valid", err)
+			return
+		}
+
+		settings, err := accountingService.UpdateEInvoiceSettings(r.Context(), principal, &EInvoiceSettings{
+			SellerName:   settingsModel.SellerName,
+			Street:       settingsModel.Street,
+			Postcode:     settingsModel.Postcode,
+			City:         settingsModel.City,
+			CountryCode:  strings.ToUpper(settingsModel.CountryCode),
+			VATID:        settingsModel.VATID,
+			ContactName:  settingsModel.ContactName,
+			ContactPhone: settingsModel.ContactPhone,
+			ContactEMail: settingsModel.ContactEMail,
+			IBAN:         strings.ReplaceAll(settingsModel.IBAN, " ", ""),
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToEInvoiceSettingsModel(settings))
+	}
+}
+
+// HandleGetClientEInvoiceDetails reads the details of the client as buyer on electronic invoices
+func (a *AccountingRestHandlers) HandleGetClientEInvoiceDetails() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		details, err := accountingService.ReadClientEInvoiceDetails(r.Context(), principal, clientID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToClientEInvoiceDetailsModel(details))
+	}
+}
+
+// HandleUpdateClientEInvoiceDetails sets the details of the client as buyer on electronic invoices
+func (a *AccountingRestHandlers) HandleUpdateClientEInvoiceDetails() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var detailsModel clientEInvoiceDetailsModel
+		err = json.NewDecoder(r.Body).Decode(&detailsModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "e-invoice details not valid", err)
+			return
+		}
+
+		err = validator.Struct(detailsModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "e-invoice details not valid", err)
+			return
+		}
+
+		details, err := accountingService.UpdateClientEInvoiceDetails(r.Context(), principal, &ClientEInvoiceDetails{
+			ClientID:       clientID,
+			BuyerReference: detailsModel.BuyerReference,
+			Street:         detailsModel.Street,
+			Postcode:       detailsModel.Postcode,
+			City:           detailsModel.City,
+			CountryCode:    strings.ToUpper(detailsModel.CountryCode),
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToClientEInvoiceDetailsModel(details))
+	}
+}
+
+// HandleEInvoice downloads the statement of the client in the month as electronic invoice in the XRechnung format,
+// or as hybrid PDF in the ZUGFeRD format if the PDF content type is requested
+func (a *AccountingRestHandlers) HandleEInvoice() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	accountingService := a.accountingService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		month, err := time.Parse("2006-01", chi.URLParam(r, "month"))
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid month", shared.NewInvalidParam("month", "month", "must be a month like 2024-03"))
+			return
+		}
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		contentType := r.URL.Query().Get("contentType")
+		if contentType == "" {
+			contentType = r.Header.Get("Content-Type")
+		}
+
+		buf := &bytes.Buffer{}
+		var invoice *AccountingInvoice
+		var fileName string
+		if contentType == pdf.ContentType {
+			invoice, err = accountingService.WriteZUGFeRD(r.Context(), principal, clientID, month, buf)
+			fileName = "ZUGFeRD_%s.pdf"
+		} else {
+			contentType = XRechnungContentType
+			invoice, err = accountingService.WriteXRechnung(r.Context(), principal, clientID, month, buf)
+			fileName = "XRechnung_%s.xml"
+		}
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf(fileName, invoice.Number)))
+		_, _ = buf.WriteTo(w)
+	}
+}
+
+func mapToTaxSettingsModel(taxSettings *TaxSettings) *taxSettingsModel {
+	taxSettingsModel := &taxSettingsModel{
+		RateBasisPoints: taxSettings.RateBasisPoints,
+		ClientTaxRates:  make([]*clientTaxRateModel, 0, len(taxSettings.ClientTaxRates)),
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/accounting/tax-settings"),
+			hal.NewLink("edit", "/api/accounting/tax-settings"),
+		),
+	}
+	for clientID, clientTaxRate := range taxSettings.ClientTaxRates {
+		taxSettingsModel.ClientTaxRates = append(taxSettingsModel.ClientTaxRates, &clientTaxRateModel{
+			ClientID:        clientID.String(),
+			RateBasisPoints: clientTaxRate.RateBasisPoints,
+			ReverseCharge:   clientTaxRate.ReverseCharge,
+			VATID:           clientTaxRate.VATID,
+		})
+	}
+	sort.Slice(taxSettingsModel.ClientTaxRates, func(i, j int) bool {
+		return taxSettingsModel.ClientTaxRates[i].ClientID < taxSettingsModel.ClientTaxRates[j].ClientID
+	})
+	return taxSettingsModel
+}
+
+func mapToEInvoiceSettingsModel(settings *EInvoiceSettings) *eInvoiceSettingsModel {
+	return &eInvoiceSettingsModel{
+		SellerName:   settings.SellerName,
+		Street:       settings.Street,
+		Postcode:     settings.Postcode,
+		City:         settings.City,
+		CountryCode:  settings.CountryCode,
+		VATID:        settings.VATID,
+		ContactName:  settings.ContactName,
+		ContactPhone: settings.ContactPhone,
+		ContactEMail: settings.ContactEMail,
+		IBAN:         settings.IBAN,
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/accounting/e-invoice-settings"),
+			hal.NewLink("edit", "/api/accounting/e-invoice-settings"),
+		),
+	}
+}
+
+func mapToClientEInvoiceDetailsModel(details *ClientEInvoiceDetails) *clientEInvoiceDetailsModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/clients/%s/e-invoice-details", details.ClientID))
+	return &clientEInvoiceDetailsModel{
+		BuyerReference: details.BuyerReference,
+		Street:         details.Street,
+		Postcode:       details.Postcode,
+		City:           details.City,
+		CountryCode:    details.CountryCode,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("edit", selfLink.Href()),
+			hal.NewLink("client", fmt.Sprintf("/api/clients/%s", details.ClientID)),
+		),
+	}
+}
+
+func mapToAccountMappingModel(accountMapping *AccountMapping) *accountMappingModel {
+	accountMappingModel := &accountMappingModel{
+		RevenueAccount:       accountMapping.RevenueAccount,
+		ReceivableAccount:    accountMapping.ReceivableAccount,
+		TaxAccount:           accountMapping.TaxAccount,
+		TaxCode:              accountMapping.TaxCode,
+		ReverseChargeTaxCode: accountMapping.ReverseChargeTaxCode,
+		DATEVConsultant:      accountMapping.DATEVConsultant,
+		DATEVClient:          accountMapping.DATEVClient,
+		ClientAccounts:       make([]*clientAccountModel, 0, len(accountMapping.ClientAccounts)),
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/accounting/mapping"),
+			hal.NewLink("edit", "/api/accounting/mapping"),
+		),
+	}
+	for clientID, account := range accountMapping.ClientAccounts {
+		accountMappingModel.ClientAccounts = append(accountMappingModel.ClientAccounts, &clientAccountModel{
+			ClientID: clientID.String(),
+			Account:  account,
+		})
+	}
+	sort.Slice(accountMappingModel.ClientAccounts, func(i, j int) bool {
+		return accountMappingModel.ClientAccounts[i].ClientID < accountMappingModel.ClientAccounts[j].ClientID
+	})
+	return accountMappingModel
+}
@@ -0,0 +1,149 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/pdf"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleAccounting(t *testing.T) {
+	is := is.New(t)
+
+	a := NewAccountingRestHandlers(&shared.Config{}, NewAccountingService(shared.NewInMemRepositoryTxer(), NewInMemAccountingRepository(), NewInMemEInvoiceRepository(), NewInMemTaxRepository(), NewInMemClientRepository()))
+	admin := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, admin)))
+		})
+	})
+	a.RegisterProtected(router)
+
+	t.Run("UpdateAccountMapping", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/accounting/mapping", strings.NewReader(`{"revenueAccount": "Services", "receivableAccount": "Accounts Receivable", "clientAccounts": [{"clientId": "`+clientIDSample.String()+`", "account": "ACME"}]}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		var accountMappingModel accountMappingModel
+		err := json.NewDecoder(httpRec.Body).Decode(&accountMappingModel)
+		is.NoErr(err)
+		is.Equal(accountMappingModel.RevenueAccount, "Services")
+		is.Equal(len(accountMappingModel.ClientAccounts), 1)
+	})
+
+	t.Run("UpdateAccountMappingNotValid", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/accounting/mapping", strings.NewReader(`{"revenueAccount": "", "receivableAccount": "1400", "clientAccounts": [{"clientId": "no-uuid", "account": "ACME"}]}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("AccountingExport", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/accounting/exports/2024-03?format=quickbooks", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Result().Header.Get("Content-Type"), "application/x-iif")
+		is.True(strings.Contains(httpRec.Result().Header.Get("Content-Disposition"), "Accounting_quickbooks_2024-03.iif"))
+		is.True(strings.Contains(httpRec.Body.String(), "TRNS\tINVOICE\t03/31/2024\tACME\t"))
+	})
+
+	t.Run("AccountingExportFormatNotValid", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/accounting/exports/2024-03?format=lexware", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("UpdateEInvoiceSettingsNotValid", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/accounting/e-invoice-settings", strings.NewReader(`{"sellerName": "Baralga GmbH", "countryCode": "Germany"}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("XRechnung", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/accounting/e-invoice-settings", strings.NewReader(`{"sellerName": "Baralga GmbH", "street": "Hauptstr. 1", "postcode": "10115", "city": "Berlin", "countryCode": "DE", "vatId": "DE123456789", "contactName": "Jane Doe", "contactPhone": "+49 30 123456", "contactEmail": "billing@baralga.com", "iban": "DE02 1203 0000 0000 2020 51"}`))
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		httpRec = httptest.NewRecorder()
+		r, _ = http.NewRequest("PUT", "/clients/"+clientIDSample.String()+"/e-invoice-details", strings.NewReader(`{"buyerReference": "991-01234-56", "street": "Marktplatz 2", "postcode": "80331", "city": "München", "countryCode": "DE"}`))
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		httpRec = httptest.NewRecorder()
+		r, _ = http.NewRequest("GET", "/accounting/e-invoices/2024-03/"+clientIDSample.String(), nil)
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Result().Header.Get("Content-Type"), "application/xml")
+		is.True(strings.Contains(httpRec.Result().Header.Get("Content-Disposition"), "XRechnung_2024-03-01.xml"))
+		is.True(strings.Contains(httpRec.Body.String(), "<ram:IBANID>DE02120300000000202051</ram:IBANID>"))
+
+		httpRec = httptest.NewRecorder()
+		r, _ = http.NewRequest("GET", "/accounting/e-invoices/2024-03/"+clientIDSample.String()+"?contentType="+pdf.ContentType, nil)
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Result().Header.Get("Content-Type"), pdf.ContentType)
+		is.True(strings.Contains(httpRec.Result().Header.Get("Content-Disposition"), "ZUGFeRD_2024-03-01.pdf"))
+		is.True(strings.HasPrefix(httpRec.Body.String(), "%PDF-1.7"))
+	})
+
+	t.Run("UpdateTaxSettings", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/accounting/tax-settings", strings.NewReader(`{"rateBasisPoints": 1900, "clientTaxRates": [{"clientId": "`+clientIDSample.String()+`", "rateBasisPoints": 700}]}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		var taxSettingsModel taxSettingsModel
+		err := json.NewDecoder(httpRec.Body).Decode(&taxSettingsModel)
+		is.NoErr(err)
+		is.Equal(taxSettingsModel.RateBasisPoints, 1900)
+		is.Equal(len(taxSettingsModel.ClientTaxRates), 1)
+	})
+
+	t.Run("UpdateTaxSettingsReverseChargeWithoutVATID", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/accounting/tax-settings", strings.NewReader(`{"rateBasisPoints": 1900, "clientTaxRates": [{"clientId": "`+clientIDSample.String()+`", "reverseCharge": true}]}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("VATSummary", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/accounting/vat-summary/2024-03", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		var vatSummaryModel vatSummaryModel
+		err := json.NewDecoder(httpRec.Body).Decode(&vatSummaryModel)
+		is.NoErr(err)
+		is.Equal(len(vatSummaryModel.Items), 1)
+		is.Equal(vatSummaryModel.Items[0].RateBasisPoints, 700)
+		is.Equal(vatSummaryModel.Items[0].TaxAmount, "9.45")
+		is.Equal(vatSummaryModel.Items[0].GrossAmount, "144.45")
+	})
+}
@@ -0,0 +1,479 @@
+package tracking
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+var (
+	datevCSVHeaders = []string{"Umsatz (ohne Soll/Haben-Kz)", "Soll/Haben-Kennzeichen", "WKZ Umsatz", "Kurs", "Basis-Umsatz", "WKZ Basis-Umsatz",
+		"Konto", "Gegenkonto (ohne BU-Schlüssel)", "BU-Schlüssel", "Belegdatum", "Belegfeld 1", "Belegfeld 2", "Skonto", "Buchungstext"}
+	xeroCSVHeaders = []string{"*ContactName", "*InvoiceNumber", "*InvoiceDate", "*DueDate", "*Description", "*Quantity", "*UnitAmount",
+		"*AccountCode", "*TaxType", "TaxAmount", "Currency"}
+)
+
+// maxDATEVTextLength is the maximum length of the booking text of DATEV
+const maxDATEVTextLength = 60
+
+// AccountingService exports the billable time of the monthly client statements to accounting systems
+// and as electronic invoices
+type AccountingService struct {
+	repositoryTxer       shared.RepositoryTxer
+	accountingRepository AccountingRepository
+	eInvoiceRepository   EInvoiceRepository
+	taxRepository        TaxRepository
+	clientRepository     ClientRepository
+}
+
+// NewAccountingService creates a new service for the accounting export
+func NewAccountingService(repositoryTxer shared.RepositoryTxer, accountingRepository AccountingRepository, eInvoiceRepository EInvoiceRepository, taxRepository TaxRepository, clientRepository ClientRepository) *AccountingService {
+	return &AccountingService{
+		repositoryTxer:       repositoryTxer,
+		accountingRepository: accountingRepository,
+		eInvoiceRepository:   eInvoiceRepository,
+		taxRepository:        taxRepository,
+		clientRepository:     clientRepository,
+	}
+}
+
+// ReadAccountMapping reads the accounts the billable time of the organization is exported with
+func (s *AccountingService) ReadAccountMapping(ctx context.Context, principal *shared.Principal) (*AccountMapping, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.accountingRepository.FindAccountMapping(ctx, principal.OrganizationID)
+}
+
+// UpdateAccountMapping sets the accounts of the organization and of its clients
+func (s *AccountingService) UpdateAccountMapping(ctx context.Context, principal *shared.Principal, accountMapping *AccountMapping) (*AccountMapping, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	for clientID := range accountMapping.ClientAccounts {
+		_, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, clientID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	accountMapping.OrganizationID = principal.OrganizationID
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.accountingRepository.UpdateAccountMapping(ctx, accountMapping)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return accountMapping, nil
+}
+
+// WriteAccountingExport writes the statements of all clients of the month as invoices in the format
+// of the accounting system, only billable time is exported
+func (s *AccountingService) WriteAccountingExport(ctx context.Context, principal *shared.Principal, format string, month time.Time, w io.Writer) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	accountMapping, err := s.accountingRepository.FindAccountMapping(ctx, principal.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	invoices, err := s.findAccountingInvoices(ctx, principal.OrganizationID, month)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case AccountingFormatDATEV:
+		return writeDATEV(w, accountMapping, month, invoices, time.Now())
+	case AccountingFormatQuickBooks:
+		return writeQuickBooksIIF(w, accountMapping, invoices)
+	default:
+		return writeXeroCSV(w, accountMapping, invoices)
+	}
+}
+
+// ReadTaxSettings reads the default tax rate of the organization and the tax rates of its clients
+func (s *AccountingService) ReadTaxSettings(ctx context.Context, principal *shared.Principal) (*TaxSettings, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.taxRepository.FindTaxSettings(ctx, principal.OrganizationID)
+}
+
+// UpdateTaxSettings sets the default tax rate of the organization and the tax rates of its clients
+func (s *AccountingService) UpdateTaxSettings(ctx context.Context, principal *shared.Principal, taxSettings *TaxSettings) (*TaxSettings, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	for clientID := range taxSettings.ClientTaxRates {
+		_, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, clientID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	taxSettings.OrganizationID = principal.OrganizationID
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.taxRepository.UpdateTaxSettings(ctx, taxSettings)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return taxSettings, nil
+}
+
+// VATSummary sums up the net amounts and taxes of the invoices of the month per currency and tax rate
+func (s *AccountingService) VATSummary(ctx context.Context, principal *shared.Principal, month time.Time) ([]*VATSummaryItem, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	invoices, err := s.findAccountingInvoices(ctx, principal.OrganizationID, month)
+	if err != nil {
+		return nil, err
+	}
+	return NewVATSummary(invoices), nil
+}
+
+// ReadEInvoiceSettings reads the details of the organization as seller on electronic invoices
+func (s *AccountingService) ReadEInvoiceSettings(ctx context.Context, principal *shared.Principal) (*EInvoiceSettings, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.eInvoiceRepository.FindEInvoiceSettings(ctx, principal.OrganizationID)
+}
+
+// UpdateEInvoiceSettings sets the details of the organization as seller on electronic invoices
+func (s *AccountingService) UpdateEInvoiceSettings(ctx context.Context, principal *shared.Principal, settings *EInvoiceSettings) (*EInvoiceSettings, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	settings.OrganizationID = principal.OrganizationID
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.eInvoiceRepository.UpdateEInvoiceSettings(ctx, settings)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// ReadClientEInvoiceDetails reads the details of the client as buyer on electronic invoices
+func (s *AccountingService) ReadClientEInvoiceDetails(ctx context.Context, principal *shared.Principal, clientID uuid.UUID) (*ClientEInvoiceDetails, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.eInvoiceRepository.FindClientEInvoiceDetails(ctx, principal.OrganizationID, clientID)
+}
+
+// UpdateClientEInvoiceDetails sets the details of the client as buyer on electronic invoices
+func (s *AccountingService) UpdateClientEInvoiceDetails(ctx context.Context, principal *shared.Principal, details *ClientEInvoiceDetails) (*ClientEInvoiceDetails, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, details.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	details.OrganizationID = principal.OrganizationID
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.eInvoiceRepository.UpdateClientEInvoiceDetails(ctx, details)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// WriteXRechnung writes the statement of the client in the month as electronic invoice in the XRechnung format,
+// the invoice has the same number as in the accounting export
+func (s *AccountingService) WriteXRechnung(ctx context.Context, principal *shared.Principal, clientID uuid.UUID, month time.Time, w io.Writer) (*AccountingInvoice, error) {
+	invoice, settings, details, err := s.readEInvoice(ctx, principal, clientID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeXRechnung(w, invoice, settings, details)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// WriteZUGFeRD writes the statement of the client in the month as hybrid PDF in the ZUGFeRD format,
+// which carries the same invoice in the XRechnung format
+func (s *AccountingService) WriteZUGFeRD(ctx context.Context, principal *shared.Principal, clientID uuid.UUID, month time.Time, w io.Writer) (*AccountingInvoice, error) {
+	invoice, settings, details, err := s.readEInvoice(ctx, principal, clientID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeZUGFeRD(w, invoice, settings, details, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// readEInvoice reads the invoice of the client in the month with the seller and buyer details of electronic invoices
+func (s *AccountingService) readEInvoice(ctx context.Context, principal *shared.Principal, clientID uuid.UUID, month time.Time) (*AccountingInvoice, *EInvoiceSettings, *ClientEInvoiceDetails, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, nil, nil, shared.ErrForbidden
+	}
+
+	_, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, clientID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	settings, err := s.eInvoiceRepository.FindEInvoiceSettings(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	details, err := s.eInvoiceRepository.FindClientEInvoiceDetails(ctx, principal.OrganizationID, clientID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	invoice, err := s.findAccountingInvoiceOfClient(ctx, principal.OrganizationID, clientID, month)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return invoice, settings, details, nil
+}
+
+// findAccountingInvoiceOfClient builds the statement of the client in the month as taxed invoice,
+// numbered like in the accounting export
+func (s *AccountingService) findAccountingInvoiceOfClient(ctx context.Context, organizationID, clientID uuid.UUID, month time.Time) (*AccountingInvoice, error) {
+	invoices, err := s.findAccountingInvoices(ctx, organizationID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, invoice := range invoices {
+		if invoice.Statement.Client.ID == clientID {
+			return invoice, nil
+		}
+	}
+	return nil, ErrInvoiceWithoutBillableTime
+}
+
+// findAccountingInvoices builds the statements of all clients in the month as taxed invoices
+func (s *AccountingService) findAccountingInvoices(ctx context.Context, organizationID uuid.UUID, month time.Time) ([]*AccountingInvoice, error) {
+	taxSettings, err := s.taxRepository.FindTaxSettings(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := s.clientRepository.FindClients(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make([]*ClientStatement, len(clients))
+	for i, client := range clients {
+		reportItems, err := s.clientRepository.ClientReport(ctx, organizationID, client.ID, month, month.AddDate(0, 1, 0))
+		if err != nil {
+			return nil, err
+		}
+		statements[i] = &ClientStatement{
+			Client: client,
+			Start:  month,
+			End:    month.AddDate(0, 1, 0),
+			Items:  reportItems,
+		}
+	}
+
+	return NewAccountingInvoices(month, statements, taxSettings), nil
+}
+
+// writeDATEV writes a booking per invoice in the DATEV format of booking batches, the client account
+// is debited with the gross amount and the revenue account credited, DATEV books the tax by the tax code
+func writeDATEV(w io.Writer, accountMapping *AccountMapping, month time.Time, invoices []*AccountingInvoice, created time.Time) error {
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Comma = ';'
+
+	err := csvWriter.Write([]string{
+		"EXTF", "700", "21", "Buchungsstapel", "13",
+		created.Format("20060102150405") + "000",
+		"", "", "", "",
+		strconv.Itoa(accountMapping.DATEVConsultant),
+		strconv.Itoa(accountMapping.DATEVClient),
+		time.Date(month.Year(), 1, 1, 0, 0, 0, 0, time.UTC).Format("20060102"),
+		strconv.Itoa(len(accountMapping.RevenueAccount)),
+		month.Format("20060102"),
+		month.AddDate(0, 1, -1).Format("20060102"),
+		fmt.Sprintf("Baralga %s", month.Format("2006-01")),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = csvWriter.Write(datevCSVHeaders)
+	if err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		statement := invoice.Statement
+		text := []rune(fmt.Sprintf("Statement %s", statement.Client.Name))
+		if len(text) > maxDATEVTextLength {
+			text = text[:maxDATEVTextLength]
+		}
+
+		err := csvWriter.Write([]string{
+			strings.Replace(formatCents(invoice.GrossAmountCents()), ".", ",", 1),
+			"S",
+			statement.Client.Currency,
+			"", "", "",
+			accountMapping.ClientAccount(statement.Client.ID),
+			accountMapping.RevenueAccount,
+			accountMapping.TaxCodeOf(invoice),
+			invoice.Day.Format("0201"),
+			invoice.Number,
+			invoice.DueDay.Format("020106"),
+			"",
+			string(text),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// writeQuickBooksIIF writes an invoice transaction per statement with a split line per billable project
+// and one for the tax in the Intuit Interchange Format of QuickBooks Desktop
+func writeQuickBooksIIF(w io.Writer, accountMapping *AccountMapping, invoices []*AccountingInvoice) error {
+	bufWriter := bufio.NewWriter(w)
+	writeLine := func(fields ...string) {
+		for i, field := range fields {
+			fields[i] = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(field)
+		}
+		_, _ = bufWriter.WriteString(strings.Join(fields, "\t") + "\r\n")
+	}
+
+	writeLine("!TRNS", "TRNSTYPE", "DATE", "ACCNT", "NAME", "AMOUNT", "DOCNUM", "MEMO", "DUEDATE")
+	writeLine("!SPL", "TRNSTYPE", "DATE", "ACCNT", "NAME", "AMOUNT", "DOCNUM", "MEMO", "QNTY", "PRICE")
+	writeLine("!ENDTRNS")
+
+	for _, invoice := range invoices {
+		statement := invoice.Statement
+		day := invoice.Day.Format("01/02/2006")
+
+		writeLine(
+			"TRNS", "INVOICE", day,
+			accountMapping.ClientAccount(statement.Client.ID),
+			statement.Client.Name,
+			formatCents(invoice.GrossAmountCents()),
+			invoice.Number,
+			fmt.Sprintf("Statement %s", statement.Start.Format("January 2006")),
+			invoice.DueDay.Format("01/02/2006"),
+		)
+		for _, item := range invoice.BillableItems() {
+			writeLine(
+				"SPL", "INVOICE", day,
+				accountMapping.RevenueAccount,
+				statement.Client.Name,
+				formatCents(-statement.AmountCents(item)),
+				invoice.Number,
+				item.ProjectTitle,
+				"-"+formatHours(item.DurationInMinutesTotal),
+				formatCents(statement.Client.HourlyRateCents),
+			)
+		}
+		if invoice.TaxAmountCents() != 0 {
+			writeLine(
+				"SPL", "INVOICE", day,
+				accountMapping.TaxAccount,
+				statement.Client.Name,
+				formatCents(-invoice.TaxAmountCents()),
+				invoice.Number,
+				fmt.Sprintf("VAT %s %%", invoice.Tax.Percent()),
+				"",
+				invoice.Tax.Percent()+"%",
+			)
+		}
+		writeLine("ENDTRNS")
+	}
+
+	return bufWriter.Flush()
+}
+
+// writeXeroCSV writes a line per billable project of each invoice in the sales invoice import format of Xero
+func writeXeroCSV(w io.Writer, accountMapping *AccountMapping, invoices []*AccountingInvoice) error {
+	csvWriter := csv.NewWriter(w)
+
+	err := csvWriter.Write(xeroCSVHeaders)
+	if err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		statement := invoice.Statement
+		lineTaxCents := invoice.LineTaxCents()
+		for i, item := range invoice.BillableItems() {
+			err := csvWriter.Write([]string{
+				statement.Client.Name,
+				invoice.Number,
+				invoice.Day.Format("02/01/2006"),
+				invoice.DueDay.Format("02/01/2006"),
+				item.ProjectTitle,
+				formatHours(item.DurationInMinutesTotal),
+				formatCents(statement.Client.HourlyRateCents),
+				accountMapping.RevenueAccount,
+				accountMapping.TaxCodeOf(invoice),
+				formatCents(lineTaxCents[i]),
+				statement.Client.Currency,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func formatCents(cents int) string {
+	return fmt.Sprintf("%.2f", float64(cents)/100)
+}
@@ -0,0 +1,221 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestAccountingExport(t *testing.T) {
+	is := is.New(t)
+
+	s := NewAccountingService(shared.NewInMemRepositoryTxer(), NewInMemAccountingRepository(), NewInMemEInvoiceRepository(), NewInMemTaxRepository(), NewInMemClientRepository())
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.UpdateAccountMapping(context.Background(), admin, &AccountMapping{
+		RevenueAccount:    "8400",
+		ReceivableAccount: "1400",
+		TaxCode:           "3",
+		ClientAccounts:    map[uuid.UUID]string{clientIDSample: "10001"},
+	})
+	is.NoErr(err)
+
+	t.Run("DATEV", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := s.WriteAccountingExport(context.Background(), admin, AccountingFormatDATEV, month, buf)
+		is.NoErr(err)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		is.Equal(len(lines), 3)
+		is.True(strings.HasPrefix(lines[0], "EXTF;700;21;Buchungsstapel"))
+		is.Equal(lines[2], "135,00;S;EUR;;;;10001;8400;3;3103;2024-03-01;140424;;Statement ACME Corp.")
+	})
+
+	t.Run("QuickBooks", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := s.WriteAccountingExport(context.Background(), admin, AccountingFormatQuickBooks, month, buf)
+		is.NoErr(err)
+
+		is.True(strings.Contains(buf.String(), "TRNS\tINVOICE\t03/31/2024\t10001\tACME Corp.\t135.00\t2024-03-01"))
+		is.True(strings.Contains(buf.String(), "SPL\tINVOICE\t03/31/2024\t8400\tACME Corp.\t-135.00\t2024-03-01\tMy Project\t-1.50\t90.00"))
+	})
+
+	t.Run("Xero", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := s.WriteAccountingExport(context.Background(), admin, AccountingFormatXero, month, buf)
+		is.NoErr(err)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		is.Equal(len(lines), 2)
+		is.Equal(lines[1], "ACME Corp.,2024-03-01,31/03/2024,14/04/2024,My Project,1.50,90.00,8400,3,0.00,EUR")
+	})
+
+	t.Run("ClientNotFound", func(t *testing.T) {
+		_, err := s.UpdateAccountMapping(context.Background(), admin, &AccountMapping{
+			RevenueAccount:    "8400",
+			ReceivableAccount: "1400",
+			ClientAccounts:    map[uuid.UUID]string{uuid.New(): "10002"},
+		})
+		is.Equal(err, ErrClientNotFound)
+	})
+
+	t.Run("AsUser", func(t *testing.T) {
+		err := s.WriteAccountingExport(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}, AccountingFormatXero, month, &bytes.Buffer{})
+		is.Equal(err, shared.ErrForbidden)
+	})
+}
+
+func TestAccountingExportWithTaxes(t *testing.T) {
+	is := is.New(t)
+
+	s := NewAccountingService(shared.NewInMemRepositoryTxer(), NewInMemAccountingRepository(), NewInMemEInvoiceRepository(), NewInMemTaxRepository(), NewInMemClientRepository())
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.UpdateAccountMapping(context.Background(), admin, &AccountMapping{
+		RevenueAccount:       "8400",
+		ReceivableAccount:    "1400",
+		TaxAccount:           "1776",
+		TaxCode:              "3",
+		ReverseChargeTaxCode: "94",
+	})
+	is.NoErr(err)
+
+	_, err = s.UpdateTaxSettings(context.Background(), admin, &TaxSettings{RateBasisPoints: 1900})
+	is.NoErr(err)
+
+	t.Run("DATEV", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := s.WriteAccountingExport(context.Background(), admin, AccountingFormatDATEV, month, buf)
+		is.NoErr(err)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		is.Equal(lines[2], "160,65;S;EUR;;;;1400;8400;3;3103;2024-03-01;140424;;Statement ACME Corp.")
+	})
+
+	t.Run("QuickBooks", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := s.WriteAccountingExport(context.Background(), admin, AccountingFormatQuickBooks, month, buf)
+		is.NoErr(err)
+
+		is.True(strings.Contains(buf.String(), "TRNS\tINVOICE\t03/31/2024\t1400\tACME Corp.\t160.65\t2024-03-01"))
+		is.True(strings.Contains(buf.String(), "SPL\tINVOICE\t03/31/2024\t1776\tACME Corp.\t-25.65\t2024-03-01\tVAT 19.00 %\t\t19.00%"))
+	})
+
+	t.Run("Xero", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := s.WriteAccountingExport(context.Background(), admin, AccountingFormatXero, month, buf)
+		is.NoErr(err)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		is.Equal(lines[1], "ACME Corp.,2024-03-01,31/03/2024,14/04/2024,My Project,1.50,90.00,8400,3,25.65,EUR")
+	})
+
+	t.Run("VATSummary", func(t *testing.T) {
+		vatSummaryItems, err := s.VATSummary(context.Background(), admin, month)
+		is.NoErr(err)
+		is.Equal(len(vatSummaryItems), 1)
+		is.Equal(vatSummaryItems[0].RateBasisPoints, 1900)
+		is.Equal(vatSummaryItems[0].NetAmountCents, 13500)
+		is.Equal(vatSummaryItems[0].TaxAmountCents, 2565)
+	})
+
+	t.Run("ReverseCharge", func(t *testing.T) {
+		_, err := s.UpdateTaxSettings(context.Background(), admin, &TaxSettings{
+			RateBasisPoints: 1900,
+			ClientTaxRates: map[uuid.UUID]*ClientTaxRate{
+				clientIDSample: {ReverseCharge: true, VATID: "ATU12345678"},
+			},
+		})
+		is.NoErr(err)
+
+		buf := &bytes.Buffer{}
+		err = s.WriteAccountingExport(context.Background(), admin, AccountingFormatDATEV, month, buf)
+		is.NoErr(err)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		is.Equal(lines[2], "135,00;S;EUR;;;;1400;8400;94;3103;2024-03-01;140424;;Statement ACME Corp.")
+
+		vatSummaryItems, err := s.VATSummary(context.Background(), admin, month)
+		is.NoErr(err)
+		is.True(vatSummaryItems[0].ReverseCharge)
+		is.Equal(vatSummaryItems[0].TaxAmountCents, 0)
+	})
+
+	t.Run("ClientNotFound", func(t *testing.T) {
+		_, err := s.UpdateTaxSettings(context.Background(), admin, &TaxSettings{
+			ClientTaxRates: map[uuid.UUID]*ClientTaxRate{uuid.New(): {RateBasisPoints: 700}},
+		})
+		is.Equal(err, ErrClientNotFound)
+	})
+}
+
+func TestXRechnung(t *testing.T) {
+	is := is.New(t)
+
+	s := NewAccountingService(shared.NewInMemRepositoryTxer(), NewInMemAccountingRepository(), NewInMemEInvoiceRepository(), NewInMemTaxRepository(), NewInMemClientRepository())
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("WithoutSettings", func(t *testing.T) {
+		_, err := s.WriteXRechnung(context.Background(), admin, clientIDSample, month, &bytes.Buffer{})
+		is.Equal(err, ErrEInvoiceSettingsNotFound)
+	})
+
+	_, err := s.UpdateEInvoiceSettings(context.Background(), admin, &EInvoiceSettings{
+		SellerName:  "Baralga GmbH",
+		CountryCode: "DE",
+		VATID:       "DE123456789",
+		IBAN:        "DE02120300000000202051",
+	})
+	is.NoErr(err)
+
+	_, err = s.UpdateTaxSettings(context.Background(), admin, &TaxSettings{RateBasisPoints: 1900})
+	is.NoErr(err)
+
+	t.Run("WithoutClientDetails", func(t *testing.T) {
+		_, err := s.WriteXRechnung(context.Background(), admin, clientIDSample, month, &bytes.Buffer{})
+		is.Equal(err, ErrClientEInvoiceDetailsNotFound)
+	})
+
+	_, err = s.UpdateClientEInvoiceDetails(context.Background(), admin, &ClientEInvoiceDetails{
+		ClientID:       clientIDSample,
+		BuyerReference: "991-01234-56",
+		CountryCode:    "DE",
+	})
+	is.NoErr(err)
+
+	t.Run("WriteXRechnung", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		invoice, err := s.WriteXRechnung(context.Background(), admin, clientIDSample, month, buf)
+		is.NoErr(err)
+		is.Equal(invoice.Number, "2024-03-01")
+		is.True(strings.Contains(buf.String(), "<ram:GrandTotalAmount>160.65</ram:GrandTotalAmount>"))
+	})
+
+	t.Run("WriteZUGFeRD", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		invoice, err := s.WriteZUGFeRD(context.Background(), admin, clientIDSample, month, buf)
+		is.NoErr(err)
+		is.Equal(invoice.Number, "2024-03-01")
+		is.True(strings.HasPrefix(buf.String(), "%PDF-1.7"))
+		is.True(strings.Contains(buf.String(), "(Invoice 2024-03-01) Tj"))
+	})
+
+	t.Run("ClientNotFound", func(t *testing.T) {
+		_, err := s.UpdateClientEInvoiceDetails(context.Background(), admin, &ClientEInvoiceDetails{ClientID: uuid.New()})
+		is.Equal(err, ErrClientNotFound)
+	})
+
+	t.Run("AsUser", func(t *testing.T) {
+		_, err := s.ReadEInvoiceSettings(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"})
+		is.Equal(err, shared.ErrForbidden)
+	})
+}
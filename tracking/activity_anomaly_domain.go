@@ -0,0 +1,99 @@
+package tracking
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ActivityAnomalyZeroDuration   string = "zero-duration"
+	ActivityAnomalyOverlong       string = "overlong"
+	ActivityAnomalyDuplicate      string = "duplicate"
+	ActivityAnomalyUnusualWeekend string = "unusual-weekend"
+)
+
+// maxUsualActivityDuration is the duration above which an activity is reported as overlong
+const maxUsualActivityDuration = 12 * time.Hour
+
+// weekendLookbackDays is the number of days before a report that decide whether a user usually works on weekends
+const weekendLookbackDays = 84
+
+// ActivityAnomaly is an unusual activity which is likely a mistake
+type ActivityAnomaly struct {
+	Type        string
+	Activity    *Activity
+	DuplicateOf *uuid.UUID
+}
+
+// DetectActivityAnomalies finds the anomalies of the activities starting between start and end,
+// the activities before start are used as history to decide whether a user usually works on weekends
+func DetectActivityAnomalies(activities []*Activity, start, end time.Time) []*ActivityAnomaly {
+	sorted := make([]*Activity, len(activities))
+	copy(sorted, activities)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	worksOnWeekends := make(map[string]bool)
+	for _, activity := range sorted {
+		if activity.Start.Before(start) && isWeekend(activity.Start) {
+			worksOnWeekends[activity.Username] = true
+		}
+	}
+
+	var anomalies []*ActivityAnomaly
+	firstOccurrences := make(map[activityDuplicateKey]uuid.UUID)
+	for _, activity := range sorted {
+		if activity.Start.Before(start) || !activity.Start.Before(end) {
+			continue
+		}
+
+		duration := activity.End.Sub(activity.Start)
+		if duration < time.Minute {
+			anomalies = append(anomalies, &ActivityAnomaly{Type: ActivityAnomalyZeroDuration, Activity: activity})
+		}
+		if duration > maxUsualActivityDuration {
+			anomalies = append(anomalies, &ActivityAnomaly{Type: ActivityAnomalyOverlong, Activity: activity})
+		}
+
+		key := duplicateKeyOf(activity)
+		if firstID, ok := firstOccurrences[key]; ok {
+			duplicateOf := firstID
+			anomalies = append(anomalies, &ActivityAnomaly{Type: ActivityAnomalyDuplicate, Activity: activity, DuplicateOf: &duplicateOf})
+		} else {
+			firstOccurrences[key] = activity.ID
+		}
+
+		if isWeekend(activity.Start) && !worksOnWeekends[activity.Username] {
+			anomalies = append(anomalies, &ActivityAnomaly{Type: ActivityAnomalyUnusualWeekend, Activity: activity})
+		}
+	}
+
+	return anomalies
+}
+
+// activityDuplicateKey identifies activities of the same user and project with the same time and description
+type activityDuplicateKey struct {
+	username    string
+	projectID   uuid.UUID
+	start       time.Time
+	end         time.Time
+	description string
+}
+
+func duplicateKeyOf(activity *Activity) activityDuplicateKey {
+	return activityDuplicateKey{
+		username:    activity.Username,
+		projectID:   activity.ProjectID,
+		start:       activity.Start.UTC(),
+		end:         activity.End.UTC(),
+		description: strings.ToLower(strings.TrimSpace(activity.Description)),
+	}
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
@@ -0,0 +1,49 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestDetectActivityAnomalies(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	start := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	projectID := uuid.New()
+
+	// 2021-10-04 is a monday, 2021-10-09 a saturday
+	monday := time.Date(2021, 10, 4, 9, 0, 0, 0, time.UTC)
+	saturday := time.Date(2021, 10, 9, 9, 0, 0, 0, time.UTC)
+
+	zero := &Activity{ID: uuid.New(), Username: "user1", ProjectID: projectID, Start: monday, End: monday}
+	overlong := &Activity{ID: uuid.New(), Username: "user1", ProjectID: projectID, Start: monday.Add(time.Hour), End: monday.Add(14 * time.Hour)}
+	original := &Activity{ID: uuid.New(), Username: "user1", ProjectID: projectID, Start: monday.AddDate(0, 0, 1), End: monday.AddDate(0, 0, 1).Add(time.Hour), Description: "Meeting"}
+	duplicate := &Activity{ID: uuid.New(), Username: "user1", ProjectID: projectID, Start: original.Start, End: original.End, Description: " meeting "}
+	otherUser := &Activity{ID: uuid.New(), Username: "user2", ProjectID: projectID, Start: original.Start, End: original.End, Description: "Meeting"}
+	weekend := &Activity{ID: uuid.New(), Username: "user1", ProjectID: projectID, Start: saturday, End: saturday.Add(time.Hour)}
+	usualWeekend := &Activity{ID: uuid.New(), Username: "user2", ProjectID: projectID, Start: saturday, End: saturday.Add(time.Hour)}
+	pastWeekend := &Activity{ID: uuid.New(), Username: "user2", ProjectID: projectID, Start: saturday.AddDate(0, 0, -14), End: saturday.AddDate(0, 0, -14).Add(time.Hour)}
+	outside := &Activity{ID: uuid.New(), Username: "user1", ProjectID: projectID, Start: end, End: end}
+
+	activities := []*Activity{weekend, zero, overlong, original, duplicate, otherUser, usualWeekend, pastWeekend, outside}
+
+	// Act
+	anomalies := DetectActivityAnomalies(activities, start, end)
+
+	// Assert
+	is.Equal(len(anomalies), 4)
+	is.Equal(anomalies[0].Type, ActivityAnomalyZeroDuration)
+	is.Equal(anomalies[0].Activity, zero)
+	is.Equal(anomalies[1].Type, ActivityAnomalyOverlong)
+	is.Equal(anomalies[1].Activity, overlong)
+	is.Equal(anomalies[2].Type, ActivityAnomalyDuplicate)
+	is.Equal(anomalies[2].Activity, duplicate)
+	is.Equal(*anomalies[2].DuplicateOf, original.ID)
+	is.Equal(anomalies[3].Type, ActivityAnomalyUnusualWeekend)
+	is.Equal(anomalies[3].Activity, weekend)
+}
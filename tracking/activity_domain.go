@@ -2,14 +2,19 @@ package tracking
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"net/http"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/baralga/shared"
 	"github.com/baralga/shared/paged"
 	time_utils "github.com/baralga/tracking/time"
 	"github.com/google/uuid"
-	"github.com/pkg/errors"
 )
 
 const (
@@ -17,7 +22,12 @@ const (
 	SortOrderDesc string = "desc"
 )
 
-var ErrActivityNotFound = errors.New("activity not found")
+const (
+	ActivityEmbedProject string = "project"
+	ActivityEmbedUser    string = "user"
+)
+
+var ErrActivityNotFound = shared.NewDomainError("activity:not-found", http.StatusNotFound, "activity not found")
 
 // Activity represents a tracked time for a project
 type Activity struct {
@@ -28,6 +38,9 @@ type Activity struct {
 	ProjectID      uuid.UUID
 	OrganizationID uuid.UUID
 	Username       string
+	Location       string
+	Latitude       *float64
+	Longitude      *float64
 }
 
 // ActivityFilter reprensents a filter for activities
@@ -35,6 +48,9 @@ type ActivityFilter struct {
 	Timespan  string
 	sortBy    string
 	sortOrder string
+	query     *ActivityQuery
+	embed     []string
+	anonymize bool
 	start     time.Time
 	end       time.Time
 }
@@ -50,15 +66,25 @@ type ActivityTimeReportItem struct {
 
 type ActivitiesPaged struct {
 	Activities []*Activity
+	Users      []*ActivityUser
 	Page       *paged.Page
 }
 
+// ActivityUser is the user who tracked activities
+type ActivityUser struct {
+	Username string
+	Name     string
+}
+
 type ActivitiesFilter struct {
 	Start          time.Time
 	End            time.Time
 	SortBy         string
 	SortOrder      string
 	Username       string
+	Query          *ActivityQuery
+	EmbedUsers     bool
+	AnonymizeUsers bool
 	OrganizationID uuid.UUID
 }
 
@@ -68,6 +94,21 @@ func IsValidActivitySortField(f string) bool {
 		return true
 	case "start":
 		return true
+	case "end":
+		return true
+	case "duration":
+		return true
+	default:
+		return false
+	}
+}
+
+func IsValidActivityEmbed(e string) bool {
+	switch strings.ToLower(e) {
+	case ActivityEmbedProject:
+		return true
+	case ActivityEmbedUser:
+		return true
 	default:
 		return false
 	}
@@ -90,13 +131,19 @@ type ActivityRepository interface {
 	TimeReportByMonth(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityTimeReportItem, error)
 	TimeReportByQuarter(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityTimeReportItem, error)
 	ProjectReport(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityProjectReportItem, error)
+	UtilizationReportByUser(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityUtilizationReportItem, error)
+	UtilizationReportByWeek(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityUtilizationReportItem, error)
+	ProjectBurndownReport(ctx context.Context, organizationID, projectID uuid.UUID, aggregateBy string) ([]*ProjectBurndownItem, error)
 	FindActivities(ctx context.Context, filter *ActivitiesFilter, pageParams *paged.PageParams) (*ActivitiesPaged, []*Project, error)
+	StreamActivities(ctx context.Context, filter *ActivitiesFilter, consume func(activity *Activity, project *Project) error) error
 	InsertActivity(ctx context.Context, activity *Activity) (*Activity, error)
+	InsertActivities(ctx context.Context, activities []*Activity) (int, error)
 	FindActivityByID(ctx context.Context, activityID uuid.UUID, organizationID uuid.UUID) (*Activity, error)
 	DeleteActivityByID(ctx context.Context, organizationID, activityID uuid.UUID) error
 	DeleteActivityByIDAndUsername(ctx context.Context, organizationID, activityID uuid.UUID, username string) error
 	UpdateActivity(ctx context.Context, organizationID uuid.UUID, activity *Activity) (*Activity, error)
 	UpdateActivityByUsername(ctx context.Context, organizationID uuid.UUID, activity *Activity, username string) (*Activity, error)
+	LocationReport(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityLocationReportItem, error)
 }
 
 // DurationFormatted is the activity duration as formatted string (e.g. 1:15 h)
@@ -107,6 +154,7 @@ func (i *ActivityTimeReportItem) DurationFormatted() string {
 type ActivityProjectReportItem struct {
 	ProjectID              uuid.UUID
 	ProjectTitle           string
+	ProjectStatus          string
 	DurationInMinutesTotal int
 }
 
@@ -115,6 +163,73 @@ func (i *ActivityProjectReportItem) DurationFormatted() string {
 	return time_utils.FormatMinutesAsDuration(float64(i.DurationInMinutesTotal))
 }
 
+// ActivityUtilizationReportItem is the billable and total time of a user or a week
+type ActivityUtilizationReportItem struct {
+	Username                       string
+	Year                           int
+	Week                           int
+	DurationInMinutesTotal         int
+	BillableDurationInMinutesTotal int
+}
+
+// NonBillableDurationInMinutesTotal is the tracked time not billable in minutes
+func (i *ActivityUtilizationReportItem) NonBillableDurationInMinutesTotal() int {
+	return i.DurationInMinutesTotal - i.BillableDurationInMinutesTotal
+}
+
+// BillablePercentage is the share of billable time of the tracked time (e.g. 75.0)
+func (i *ActivityUtilizationReportItem) BillablePercentage() float64 {
+	if i.DurationInMinutesTotal == 0 {
+		return 0
+	}
+	return math.Round(float64(i.BillableDurationInMinutesTotal)/float64(i.DurationInMinutesTotal)*1000) / 10
+}
+
+// UtilizationReport contains the utilization of the users, the whole team and the weekly trend
+type UtilizationReport struct {
+	TargetMinutesPerUser int
+	Team                 *ActivityUtilizationReportItem
+	Users                []*ActivityUtilizationReportItem
+	Trend                []*ActivityUtilizationReportItem
+}
+
+// TargetPercentage is the share of billable time of the working-time target (e.g. 80.0)
+func (r *UtilizationReport) TargetPercentage(item *ActivityUtilizationReportItem, users int) float64 {
+	targetMinutes := r.TargetMinutesPerUser * users
+	if targetMinutes == 0 {
+		return 0
+	}
+	return math.Round(float64(item.BillableDurationInMinutesTotal)/float64(targetMinutes)*1000) / 10
+}
+
+// AnonymousUsername is the name of the user at the 1-based position of an anonymized report,
+// like User A, User B, ... User Z, User AA
+func AnonymousUsername(position int) string {
+	letters := ""
+	for position > 0 {
+		position--
+		letters = string(rune('A'+position%26)) + letters
+		position /= 26
+	}
+	return "User " + letters
+}
+
+// anonymizeUtilizationReportItems replaces the usernames by their position in the order of the md5 hash of the username,
+// the same order as in the anonymized reports of the repository
+func anonymizeUtilizationReportItems(reportItems []*ActivityUtilizationReportItem) {
+	hashOf := func(username string) string {
+		hash := md5.Sum([]byte(username))
+		return hex.EncodeToString(hash[:])
+	}
+
+	sort.SliceStable(reportItems, func(i, j int) bool {
+		return hashOf(reportItems[i].Username) < hashOf(reportItems[j].Username)
+	})
+	for i, reportItem := range reportItems {
+		reportItem.Username = AnonymousUsername(i + 1)
+	}
+}
+
 // AsTime returns the report item as time.Time
 func (i *ActivityTimeReportItem) AsTime() time.Time {
 	t, _ := time.Parse("2006-1-2", fmt.Sprintf("%v-%v-%v", i.Year, i.Month, i.Day))
@@ -156,6 +271,21 @@ func (f *ActivityFilter) End() time.Time {
 	}
 }
 
+// Embeds checks whether the related entity is embedded with the activities
+func (f *ActivityFilter) Embeds(relation string) bool {
+	for _, e := range f.embed {
+		if strings.EqualFold(e, relation) {
+			return true
+		}
+	}
+	return false
+}
+
+// Anonymizes checks whether the users are anonymized in reports
+func (f *ActivityFilter) Anonymizes() bool {
+	return f.anonymize
+}
+
 func (f *ActivityFilter) Home() *ActivityFilter {
 	return &ActivityFilter{
 		Timespan: f.Timespan,
@@ -323,6 +453,11 @@ func (a *Activity) DurationFormatted() string {
 	return time_utils.FormatMinutesAsDuration(float64(a.DurationMinutesTotal()))
 }
 
+// HasLocation checks if the activity has a kind of location or coordinates
+func (a *Activity) HasLocation() bool {
+	return a.Location != "" || (a.Latitude != nil && a.Longitude != nil)
+}
+
 func (a *Activity) duration() time.Duration {
 	return a.End.Sub(a.Start)
 }
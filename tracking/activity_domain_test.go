@@ -273,3 +273,26 @@ func TestIsValidActivitySortField(t *testing.T) {
 	})
 
 }
+
+func TestUtilizationReportItemBillablePercentage(t *testing.T) {
+	is := is.New(t)
+
+	reportItem := &ActivityUtilizationReportItem{
+		DurationInMinutesTotal:         120,
+		BillableDurationInMinutesTotal: 90,
+	}
+
+	is.Equal(reportItem.BillablePercentage(), 75.0)
+	is.Equal(reportItem.NonBillableDurationInMinutesTotal(), 30)
+	is.Equal((&ActivityUtilizationReportItem{}).BillablePercentage(), 0.0)
+}
+
+func TestAnonymousUsername(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(AnonymousUsername(1), "User A")
+	is.Equal(AnonymousUsername(2), "User B")
+	is.Equal(AnonymousUsername(26), "User Z")
+	is.Equal(AnonymousUsername(27), "User AA")
+	is.Equal(AnonymousUsername(53), "User BA")
+}
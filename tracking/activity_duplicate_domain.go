@@ -0,0 +1,106 @@
+package tracking
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+)
+
+var ErrActivityNearDuplicate = shared.NewDomainError("activity:near-duplicate", http.StatusConflict, "activity is a near duplicate of an existing activity")
+
+// nearDuplicateWindow is the time around new activities in which existing activities are checked for near duplicates
+const nearDuplicateWindow = 24 * time.Hour
+
+// minDescriptionSimilarity is the share of common words from which descriptions are similar
+const minDescriptionSimilarity = 0.5
+
+// ActivityNearDuplicateError is the error of an activity being a near duplicate of other activities
+type ActivityNearDuplicateError struct {
+	Activity   *Activity
+	Duplicates []*Activity
+}
+
+func (e *ActivityNearDuplicateError) Error() string {
+	return fmt.Sprintf("activity starting %s is a near duplicate of %d activities", e.Activity.Start.Format(time.RFC3339), len(e.Duplicates))
+}
+
+func (e *ActivityNearDuplicateError) Unwrap() error {
+	return ErrActivityNearDuplicate
+}
+
+// IsNearDuplicateOf checks whether the activity is of the same user and project as the other one,
+// overlaps it in time and has a similar description
+func (a *Activity) IsNearDuplicateOf(other *Activity) bool {
+	if a.ID == other.ID || a.Username != other.Username || a.ProjectID != other.ProjectID {
+		return false
+	}
+
+	overlaps := a.Start.Equal(other.Start) || (a.Start.Before(other.End) && other.Start.Before(a.End))
+	return overlaps && isSimilarDescription(a.Description, other.Description)
+}
+
+// CheckNearDuplicates checks the activities against the existing ones and each other,
+// the first activity being a near duplicate is returned as error
+func CheckNearDuplicates(activities []*Activity, existingActivities []*Activity) error {
+	candidates := make([]*Activity, len(existingActivities), len(existingActivities)+len(activities))
+	copy(candidates, existingActivities)
+
+	for _, activity := range activities {
+		var duplicates []*Activity
+		for _, candidate := range candidates {
+			if activity.IsNearDuplicateOf(candidate) {
+				duplicates = append(duplicates, candidate)
+			}
+		}
+
+		if len(duplicates) > 0 {
+			return &ActivityNearDuplicateError{
+				Activity:   activity,
+				Duplicates: duplicates,
+			}
+		}
+
+		candidates = append(candidates, activity)
+	}
+
+	return nil
+}
+
+// isSimilarDescription checks whether the descriptions share most of their words, ignoring case
+func isSimilarDescription(description, otherDescription string) bool {
+	words := descriptionWords(description)
+	otherWords := descriptionWords(otherDescription)
+	if len(words) == 0 || len(otherWords) == 0 {
+		return len(words) == len(otherWords)
+	}
+
+	common := 0
+	for word := range words {
+		if otherWords[word] {
+			common++
+		}
+	}
+
+	all := len(words) + len(otherWords) - common
+	return float64(common)/float64(all) >= minDescriptionSimilarity
+}
+
+func descriptionWords(description string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(description), isDescriptionSeparator) {
+		words[word] = true
+	}
+	return words
+}
+
+func isDescriptionSeparator(r rune) bool {
+	switch r {
+	case ' ', '\t', '\n', ',', '.', ';', ':', '-', '/':
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,85 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestIsNearDuplicateOf(t *testing.T) {
+	is := is.New(t)
+
+	start, _ := time.Parse(time.RFC3339, "2021-10-04T10:00:00.000Z")
+	activity := &Activity{
+		ID:          uuid.New(),
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Description: "Sprint planning",
+		ProjectID:   shared.ProjectIDSample,
+		Username:    "user1",
+	}
+
+	t.Run("overlapping with similar description", func(t *testing.T) {
+		other := *activity
+		other.ID = uuid.New()
+		other.Start = start.Add(30 * time.Minute)
+		other.End = start.Add(90 * time.Minute)
+		other.Description = "sprint planning, backlog"
+		is.True(activity.IsNearDuplicateOf(&other))
+	})
+
+	t.Run("adjacent", func(t *testing.T) {
+		other := *activity
+		other.ID = uuid.New()
+		other.Start = activity.End
+		other.End = activity.End.Add(time.Hour)
+		is.True(!activity.IsNearDuplicateOf(&other))
+	})
+
+	t.Run("different description", func(t *testing.T) {
+		other := *activity
+		other.ID = uuid.New()
+		other.Description = "Code review"
+		is.True(!activity.IsNearDuplicateOf(&other))
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		other := *activity
+		other.ID = uuid.New()
+		other.Username = "user2"
+		is.True(!activity.IsNearDuplicateOf(&other))
+	})
+
+	t.Run("other project", func(t *testing.T) {
+		other := *activity
+		other.ID = uuid.New()
+		other.ProjectID = uuid.New()
+		is.True(!activity.IsNearDuplicateOf(&other))
+	})
+
+	t.Run("same activity", func(t *testing.T) {
+		is.True(!activity.IsNearDuplicateOf(activity))
+	})
+}
+
+func TestCheckNearDuplicates(t *testing.T) {
+	is := is.New(t)
+
+	start, _ := time.Parse(time.RFC3339, "2021-10-04T10:00:00.000Z")
+	existing := &Activity{ID: uuid.New(), Start: start, End: start.Add(time.Hour), Username: "user1"}
+	first := &Activity{ID: uuid.New(), Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour), Username: "user1"}
+	second := &Activity{ID: uuid.New(), Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour), Username: "user1"}
+
+	is.NoErr(CheckNearDuplicates([]*Activity{first}, []*Activity{existing}))
+
+	err := CheckNearDuplicates([]*Activity{first, second}, []*Activity{existing})
+	nearDuplicateError, ok := err.(*ActivityNearDuplicateError)
+	is.True(ok)
+	is.Equal(nearDuplicateError.Activity, second)
+	is.Equal(len(nearDuplicateError.Duplicates), 1)
+	is.Equal(nearDuplicateError.Duplicates[0], first)
+	is.Equal(shared.DomainErrorOf(err), ErrActivityNearDuplicate)
+}
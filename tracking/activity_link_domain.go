@@ -0,0 +1,89 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	EventTypeActivityLinkAdded   = "activity.link.added"
+	EventTypeActivityLinkRemoved = "activity.link.removed"
+
+	maxActivityLinkURLLength   = 2000
+	maxActivityLinkTitleLength = 255
+	// maxActivityLinks is the maximum number of links of an activity
+	maxActivityLinks = 20
+
+	activityLinkUnfurlJobType = "activity-link-unfurl"
+)
+
+var (
+	ErrActivityLinkNotFound     = shared.NewDomainError("activity-link:not-found", http.StatusNotFound, "link of activity not found")
+	ErrActivityLinkLimitReached = shared.NewDomainError("activity-link:limit-reached", http.StatusConflict, "activity has the maximum number of links")
+)
+
+// ActivityLink is a link like the url of a ticket or a document attached to an activity,
+// title and favicon are given by the user or fetched from the page of the link
+type ActivityLink struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ActivityID     uuid.UUID
+	URL            string
+	Title          string
+	FaviconURL     string
+	CreatedBy      string
+	CreatedAt      time.Time
+}
+
+type ActivityLinkRepository interface {
+	FindActivityLinks(ctx context.Context, organizationID, activityID uuid.UUID) ([]*ActivityLink, error)
+	FindActivityLinksOfActivities(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityLink, error)
+	FindActivityLinkByID(ctx context.Context, organizationID, linkID uuid.UUID) (*ActivityLink, error)
+	InsertActivityLink(ctx context.Context, link *ActivityLink) error
+	UpdateActivityLinkPreview(ctx context.Context, link *ActivityLink) error
+	DeleteActivityLink(ctx context.Context, organizationID, linkID uuid.UUID) error
+}
+
+// Validate checks that the url of the link is an absolute http or https url and the title is not too long
+func (l *ActivityLink) Validate() error {
+	if l.URL == "" {
+		return shared.NewInvalidParam("url", "required", "url is required")
+	}
+	if len(l.URL) > maxActivityLinkURLLength {
+		return shared.NewInvalidParam("url", "max", "url must not be longer than 2000 characters")
+	}
+
+	u, err := url.Parse(l.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return shared.NewInvalidParam("url", "url", "url must be a http or https url like https://example.com/tickets/1")
+	}
+
+	if utf8.RuneCountInString(l.Title) > maxActivityLinkTitleLength {
+		return shared.NewInvalidParam("title", "max", "title must not be longer than 255 characters")
+	}
+	return nil
+}
+
+// DisplayTitle is the title of the link or the url if the link has no title
+func (l *ActivityLink) DisplayTitle() string {
+	if l.Title != "" {
+		return l.Title
+	}
+	return l.URL
+}
+
+// activityLinkURLs are the urls of the links separated by the separator for exports
+func activityLinkURLs(links []*ActivityLink, separator string) string {
+	urls := make([]string, len(links))
+	for i, link := range links {
+		urls[i] = link.URL
+	}
+	return strings.Join(urls, separator)
+}
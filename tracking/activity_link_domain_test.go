@@ -0,0 +1,29 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestActivityLinkValidate(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr((&ActivityLink{URL: "https://example.com/tickets/1"}).Validate())
+	is.NoErr((&ActivityLink{URL: "http://example.com", Title: "Example"}).Validate())
+
+	is.True((&ActivityLink{}).Validate() != nil)
+	is.True((&ActivityLink{URL: "example.com/tickets/1"}).Validate() != nil)
+	is.True((&ActivityLink{URL: "javascript:alert(1)"}).Validate() != nil)
+	is.True((&ActivityLink{URL: "ftp://example.com/file"}).Validate() != nil)
+	is.True((&ActivityLink{URL: "https://example.com/" + strings.Repeat("a", 2000)}).Validate() != nil)
+	is.True((&ActivityLink{URL: "https://example.com", Title: strings.Repeat("a", 256)}).Validate() != nil)
+}
+
+func TestActivityLinkDisplayTitle(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal((&ActivityLink{URL: "https://example.com", Title: "Example"}).DisplayTitle(), "Example")
+	is.Equal((&ActivityLink{URL: "https://example.com"}).DisplayTitle(), "https://example.com")
+}
@@ -0,0 +1,193 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbActivityLinkRepository is a SQL database repository for the links of activities
+type DbActivityLinkRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ ActivityLinkRepository = (*DbActivityLinkRepository)(nil)
+
+// NewDbActivityLinkRepository creates a new SQL database repository for the links of activities
+func NewDbActivityLinkRepository(connPool *pgxpool.Pool) *DbActivityLinkRepository {
+	return &DbActivityLinkRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbActivityLinkRepository) FindActivityLinks(ctx context.Context, organizationID, activityID uuid.UUID) ([]*ActivityLink, error) {
+	rows, err := shared.SelectAll[activityLinkRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[activityLinkRow]()+`
+		 FROM activity_links
+		 WHERE org_id = $1 AND activity_id = $2
+		 ORDER BY created_at ASC`,
+		organizationID, activityID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toActivityLinks(rows), nil
+}
+
+// FindActivityLinksOfActivities reads the links of the activities in the timespan of the filter,
+// only of the activities of the user if the filter has a user
+func (r *DbActivityLinkRepository) FindActivityLinksOfActivities(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityLink, error) {
+	params := []any{filter.OrganizationID, filter.Start, filter.End}
+	filterSql := ""
+	if filter.Username != "" {
+		params = append(params, filter.Username)
+		filterSql = " AND username = $4"
+	}
+
+	rows, err := shared.SelectAll[activityLinkRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[activityLinkRow]()+`
+		 FROM activity_links
+		 WHERE org_id = $1 AND activity_id IN (
+		   SELECT activity_id FROM activities
+		   WHERE org_id = $1 AND $2 <= start_time AND start_time < $3`+filterSql+`
+		 )
+		 ORDER BY created_at ASC`,
+		params...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toActivityLinks(rows), nil
+}
+
+func (r *DbActivityLinkRepository) FindActivityLinkByID(ctx context.Context, organizationID, linkID uuid.UUID) (*ActivityLink, error) {
+	row, err := shared.SelectOne[activityLinkRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[activityLinkRow]()+`
+		 FROM activity_links
+		 WHERE link_id = $1 AND org_id = $2`,
+		linkID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrActivityLinkNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toActivityLink(), nil
+}
+
+func (r *DbActivityLinkRepository) InsertActivityLink(ctx context.Context, link *ActivityLink) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO activity_links
+		   (link_id, org_id, activity_id, url, title, favicon_url, created_by, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		link.ID,
+		link.OrganizationID,
+		link.ActivityID,
+		link.URL,
+		nullableString(link.Title),
+		nullableString(link.FaviconURL),
+		link.CreatedBy,
+		link.CreatedAt,
+	)
+	return err
+}
+
+// UpdateActivityLinkPreview sets the title and favicon fetched from the page of the link
+func (r *DbActivityLinkRepository) UpdateActivityLinkPreview(ctx context.Context, link *ActivityLink) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE activity_links
+		 SET title = $3, favicon_url = $4
+		 WHERE link_id = $1 AND org_id = $2`,
+		link.ID,
+		link.OrganizationID,
+		nullableString(link.Title),
+		nullableString(link.FaviconURL),
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrActivityLinkNotFound
+	}
+	return nil
+}
+
+func (r *DbActivityLinkRepository) DeleteActivityLink(ctx context.Context, organizationID, linkID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM activity_links
+		 WHERE link_id = $1 AND org_id = $2`,
+		linkID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrActivityLinkNotFound
+	}
+	return nil
+}
+
+type activityLinkRow struct {
+	ID             uuid.UUID      `db:"link_id"`
+	OrganizationID uuid.UUID      `db:"org_id"`
+	ActivityID     uuid.UUID      `db:"activity_id"`
+	URL            string         `db:"url"`
+	Title          sql.NullString `db:"title"`
+	FaviconURL     sql.NullString `db:"favicon_url"`
+	CreatedBy      string         `db:"created_by"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+func (r *activityLinkRow) toActivityLink() *ActivityLink {
+	return &ActivityLink{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		ActivityID:     r.ActivityID,
+		URL:            r.URL,
+		Title:          r.Title.String,
+		FaviconURL:     r.FaviconURL.String,
+		CreatedBy:      r.CreatedBy,
+		CreatedAt:      r.CreatedAt,
+	}
+}
+
+func toActivityLinks(rows []*activityLinkRow) []*ActivityLink {
+	links := make([]*ActivityLink, len(rows))
+	for i, row := range rows {
+		links[i] = row.toActivityLink()
+	}
+	return links
+}
+
+func nullableString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,101 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestActivityLinkRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	activityLinkRepository := NewDbActivityLinkRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	// sample activity of 10 minutes on 2021-10-14
+	activityID := uuid.MustParse("2a52852c-3f36-11ec-9bbc-0242ac130002")
+
+	link := &ActivityLink{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		ActivityID:     activityID,
+		URL:            "https://example.com/tickets/1",
+		CreatedBy:      "user1",
+		CreatedAt:      time.Now(),
+	}
+
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return activityLinkRepository.InsertActivityLink(ctx, link)
+		},
+	)
+	is.NoErr(err)
+
+	link.Title = "Ticket 1"
+	link.FaviconURL = "https://example.com/favicon.ico"
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return activityLinkRepository.UpdateActivityLinkPreview(ctx, link)
+		},
+	)
+	is.NoErr(err)
+
+	links, err := activityLinkRepository.FindActivityLinks(context.Background(), shared.OrganizationIDSample, activityID)
+	is.NoErr(err)
+	is.Equal(len(links), 1)
+	is.Equal(links[0].Title, "Ticket 1")
+	is.Equal(links[0].FaviconURL, "https://example.com/favicon.ico")
+
+	links, err = activityLinkRepository.FindActivityLinksOfActivities(context.Background(), &ActivitiesFilter{
+		OrganizationID: shared.OrganizationIDSample,
+		Start:          time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:            time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC),
+	})
+	is.NoErr(err)
+	is.Equal(len(links), 1)
+
+	links, err = activityLinkRepository.FindActivityLinksOfActivities(context.Background(), &ActivitiesFilter{
+		OrganizationID: shared.OrganizationIDSample,
+		Start:          time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC),
+		End:            time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC),
+	})
+	is.NoErr(err)
+	is.Equal(len(links), 0)
+
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return activityLinkRepository.DeleteActivityLink(ctx, shared.OrganizationIDSample, link.ID)
+		},
+	)
+	is.NoErr(err)
+
+	_, err = activityLinkRepository.FindActivityLinkByID(context.Background(), shared.OrganizationIDSample, link.ID)
+	is.True(errors.Is(err, ErrActivityLinkNotFound))
+}
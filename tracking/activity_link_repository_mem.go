@@ -0,0 +1,97 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemActivityLinkRepository struct {
+	mu    sync.Mutex
+	links []*ActivityLink
+}
+
+var _ ActivityLinkRepository = (*InMemActivityLinkRepository)(nil)
+
+func NewInMemActivityLinkRepository() *InMemActivityLinkRepository {
+	return &InMemActivityLinkRepository{}
+}
+
+func (r *InMemActivityLinkRepository) FindActivityLinks(ctx context.Context, organizationID, activityID uuid.UUID) ([]*ActivityLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var links []*ActivityLink
+	for _, link := range r.links {
+		if link.OrganizationID == organizationID && link.ActivityID == activityID {
+			found := *link
+			links = append(links, &found)
+		}
+	}
+	return links, nil
+}
+
+// FindActivityLinksOfActivities reads all links of the organization, as the activities are not known here
+func (r *InMemActivityLinkRepository) FindActivityLinksOfActivities(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var links []*ActivityLink
+	for _, link := range r.links {
+		if link.OrganizationID == filter.OrganizationID {
+			found := *link
+			links = append(links, &found)
+		}
+	}
+	return links, nil
+}
+
+func (r *InMemActivityLinkRepository) FindActivityLinkByID(ctx context.Context, organizationID, linkID uuid.UUID) (*ActivityLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, link := range r.links {
+		if link.OrganizationID == organizationID && link.ID == linkID {
+			found := *link
+			return &found, nil
+		}
+	}
+	return nil, ErrActivityLinkNotFound
+}
+
+func (r *InMemActivityLinkRepository) InsertActivityLink(ctx context.Context, link *ActivityLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *link
+	r.links = append(r.links, &inserted)
+	return nil
+}
+
+func (r *InMemActivityLinkRepository) UpdateActivityLinkPreview(ctx context.Context, link *ActivityLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, l := range r.links {
+		if l.OrganizationID == link.OrganizationID && l.ID == link.ID {
+			l.Title = link.Title
+			l.FaviconURL = link.FaviconURL
+			return nil
+		}
+	}
+	return ErrActivityLinkNotFound
+}
+
+func (r *InMemActivityLinkRepository) DeleteActivityLink(ctx context.Context, organizationID, linkID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, link := range r.links {
+		if link.OrganizationID == organizationID && link.ID == linkID {
+			r.links = append(r.links[:i], r.links[i+1:]...)
+			return nil
+		}
+	}
+	return ErrActivityLinkNotFound
+}
@@ -0,0 +1,177 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type activityLinkModel struct {
+	ID         string     `json:"id"`
+	ActivityID string     `json:"activityId"`
+	URL        string     `json:"url"`
+	Title      string     `json:"title,omitempty"`
+	FaviconURL string     `json:"faviconUrl,omitempty"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  string     `json:"createdAt"`
+	Links      *hal.Links `json:"_links"`
+}
+
+type activityLinksModel struct {
+	Embedded *embeddedActivityLinks `json:"_embedded"`
+	Links    *hal.Links             `json:"_links"`
+}
+
+type embeddedActivityLinks struct {
+	ActivityLinkModels []*activityLinkModel `json:"links"`
+}
+
+type ActivityLinkRestHandlers struct {
+	config              *shared.Config
+	activityLinkService *ActivityLinkService
+}
+
+func NewActivityLinkRestHandlers(config *shared.Config, activityLinkService *ActivityLinkService) *ActivityLinkRestHandlers {
+	return &ActivityLinkRestHandlers{
+		config:              config,
+		activityLinkService: activityLinkService,
+	}
+}
+
+func (a *ActivityLinkRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/activities/{activity-id}/links", a.HandleGetActivityLinks())
+	r.Post("/activities/{activity-id}/links", a.HandleAddActivityLink())
+	r.Delete("/activities/{activity-id}/links/{link-id}", a.HandleDeleteActivityLink())
+}
+
+func (a *ActivityLinkRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetActivityLinks reads the links of an activity
+func (a *ActivityLinkRestHandlers) HandleGetActivityLinks() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityLinkService := a.activityLinkService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, err := uuid.Parse(chi.URLParam(r, "activity-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		links, err := activityLinkService.ReadActivityLinks(r.Context(), principal, activityID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		linkModels := make([]*activityLinkModel, len(links))
+		for i, link := range links {
+			linkModels[i] = mapToActivityLinkModel(link)
+		}
+
+		shared.RenderJSON(w, &activityLinksModel{
+			Embedded: &embeddedActivityLinks{
+				ActivityLinkModels: linkModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleAddActivityLink attaches a link to an activity
+func (a *ActivityLinkRestHandlers) HandleAddActivityLink() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityLinkService := a.activityLinkService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, err := uuid.Parse(chi.URLParam(r, "activity-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var linkModel activityLinkModel
+		err = json.NewDecoder(r.Body).Decode(&linkModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "link not valid", err)
+			return
+		}
+
+		link := &ActivityLink{
+			URL:   linkModel.URL,
+			Title: linkModel.Title,
+		}
+		err = link.Validate()
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "link not valid", err)
+			return
+		}
+
+		link, err = activityLinkService.AddActivityLink(r.Context(), principal, activityID, link)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToActivityLinkModel(link))
+	}
+}
+
+// HandleDeleteActivityLink removes a link from an activity
+func (a *ActivityLinkRestHandlers) HandleDeleteActivityLink() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityLinkService := a.activityLinkService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, err := uuid.Parse(chi.URLParam(r, "activity-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		linkID, err := uuid.Parse(chi.URLParam(r, "link-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = activityLinkService.DeleteActivityLink(r.Context(), principal, activityID, linkID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func mapToActivityLinkModel(link *ActivityLink) *activityLinkModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/activities/%s/links/%s", link.ActivityID, link.ID))
+	return &activityLinkModel{
+		ID:         link.ID.String(),
+		ActivityID: link.ActivityID.String(),
+		URL:        link.URL,
+		Title:      link.Title,
+		FaviconURL: link.FaviconURL,
+		CreatedBy:  link.CreatedBy,
+		CreatedAt:  link.CreatedAt.UTC().Format(time.RFC3339),
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+}
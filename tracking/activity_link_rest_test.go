@@ -0,0 +1,70 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleActivityLinks(t *testing.T) {
+	is := is.New(t)
+
+	a := NewActivityLinkRestHandlers(&shared.Config{}, newActivityLinkServiceForTest(shared.NewInMemOutbox(shared.NewInMemMailResource()), nil))
+
+	request := func(method, url, body string) *http.Request {
+		r, _ := http.NewRequest(method, url, strings.NewReader(body))
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Roles:          []string{"ROLE_USER"},
+		}))
+	}
+
+	router := chi.NewRouter()
+	a.RegisterProtected(router)
+
+	linksPath := fmt.Sprintf("/activities/%s/links", sampleActivityID)
+
+	httpRec := httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("POST", linksPath, `{"url": "file:///etc/passwd"}`))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("POST", "/activities/not-a-uuid/links", `{"url": "https://example.com/tickets/1"}`))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("POST", linksPath, `{"url": "https://example.com/tickets/1", "title": "Ticket 1"}`))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	var created activityLinkModel
+	err := json.NewDecoder(httpRec.Body).Decode(&created)
+	is.NoErr(err)
+	is.Equal(created.URL, "https://example.com/tickets/1")
+	is.Equal(created.Title, "Ticket 1")
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("GET", linksPath, ""))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	var links activityLinksModel
+	err = json.NewDecoder(httpRec.Body).Decode(&links)
+	is.NoErr(err)
+	is.Equal(len(links.Embedded.ActivityLinkModels), 1)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("DELETE", linksPath+"/"+created.ID, ""))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("DELETE", linksPath+"/"+created.ID, ""))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
@@ -0,0 +1,215 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// activityLinkEventData is the data of the events of links of activities
+type activityLinkEventData struct {
+	ID         string `json:"id"`
+	ActivityID string `json:"activityId"`
+	URL        string `json:"url"`
+	Title      string `json:"title,omitempty"`
+}
+
+// ActivityLinkService manages the links attached to activities, the previews of links are fetched
+// in the background if unfurling links is enabled
+type ActivityLinkService struct {
+	repositoryTxer         shared.RepositoryTxer
+	jobService             *shared.JobService
+	outbox                 shared.Outbox
+	linkUnfurler           shared.LinkUnfurler
+	activityLinkRepository ActivityLinkRepository
+	activityRepository     ActivityRepository
+}
+
+// NewActivityLinkService creates a new service for the links of activities, the link unfurler is nil if disabled
+func NewActivityLinkService(
+	repositoryTxer shared.RepositoryTxer,
+	jobService *shared.JobService,
+	outbox shared.Outbox,
+	linkUnfurler shared.LinkUnfurler,
+	activityLinkRepository ActivityLinkRepository,
+	activityRepository ActivityRepository,
+) *ActivityLinkService {
+	s := &ActivityLinkService{
+		repositoryTxer:         repositoryTxer,
+		jobService:             jobService,
+		outbox:                 outbox,
+		linkUnfurler:           linkUnfurler,
+		activityLinkRepository: activityLinkRepository,
+		activityRepository:     activityRepository,
+	}
+
+	jobService.RegisterHandler(activityLinkUnfurlJobType, s.handleActivityLinkUnfurlJob)
+
+	return s
+}
+
+// ReadActivityLinks reads the links of an activity, which only the user of the activity and admins may see
+func (s *ActivityLinkService) ReadActivityLinks(ctx context.Context, principal *shared.Principal, activityID uuid.UUID) ([]*ActivityLink, error) {
+	activity, err := s.readActivity(ctx, principal, activityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.activityLinkRepository.FindActivityLinks(ctx, principal.OrganizationID, activity.ID)
+}
+
+// AddActivityLink attaches a link to an activity of the principal, the preview of links without a title
+// is fetched in the background if unfurling links is enabled
+func (s *ActivityLinkService) AddActivityLink(ctx context.Context, principal *shared.Principal, activityID uuid.UUID, link *ActivityLink) (*ActivityLink, error) {
+	err := link.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := s.readActivity(ctx, principal, activityID)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.activityLinkRepository.FindActivityLinks(ctx, principal.OrganizationID, activity.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(links) >= maxActivityLinks {
+		return nil, ErrActivityLinkLimitReached
+	}
+
+	link.ID = uuid.New()
+	link.OrganizationID = principal.OrganizationID
+	link.ActivityID = activity.ID
+	link.FaviconURL = ""
+	link.CreatedBy = principal.Username
+	link.CreatedAt = time.Now()
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			err := s.activityLinkRepository.InsertActivityLink(ctx, link)
+			if err != nil {
+				return err
+			}
+
+			if s.linkUnfurler != nil {
+				err = s.jobService.Enqueue(ctx, shared.NewJob(link.OrganizationID, activityLinkUnfurlJobType, link.ID.String()))
+				if err != nil {
+					return err
+				}
+			}
+
+			return s.publishEvent(ctx, EventTypeActivityLinkAdded, link)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// DeleteActivityLink removes a link from an activity of the principal
+func (s *ActivityLinkService) DeleteActivityLink(ctx context.Context, principal *shared.Principal, activityID, linkID uuid.UUID) error {
+	activity, err := s.readActivity(ctx, principal, activityID)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.activityLinkRepository.FindActivityLinkByID(ctx, principal.OrganizationID, linkID)
+	if err != nil {
+		return err
+	}
+	if link.ActivityID != activity.ID {
+		return ErrActivityLinkNotFound
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			err := s.activityLinkRepository.DeleteActivityLink(ctx, principal.OrganizationID, link.ID)
+			if err != nil {
+				return err
+			}
+
+			return s.publishEvent(ctx, EventTypeActivityLinkRemoved, link)
+		},
+	)
+}
+
+func (s *ActivityLinkService) readActivity(ctx context.Context, principal *shared.Principal, activityID uuid.UUID) (*Activity, error) {
+	activity, err := s.activityRepository.FindActivityByID(ctx, activityID, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if activity.Username != principal.Username && !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrActivityNotFound
+	}
+	return activity, nil
+}
+
+func (s *ActivityLinkService) publishEvent(ctx context.Context, eventType string, link *ActivityLink) error {
+	data := &activityLinkEventData{
+		ID:         link.ID.String(),
+		ActivityID: link.ActivityID.String(),
+		URL:        link.URL,
+		Title:      link.Title,
+	}
+
+	event, err := shared.NewCloudEvent(
+		link.OrganizationID,
+		eventType,
+		fmt.Sprintf("activities/%s/links/%s", link.ActivityID, link.ID),
+		data,
+		auditActorOf(ctx, link.OrganizationID),
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.outbox.PublishEvent(ctx, event)
+}
+
+// handleActivityLinkUnfurlJob fetches the preview of the link, a title given by the user is kept
+func (s *ActivityLinkService) handleActivityLinkUnfurlJob(ctx context.Context, job *shared.Job) error {
+	if s.linkUnfurler == nil {
+		return nil
+	}
+
+	linkID, err := uuid.Parse(job.Payload)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.activityLinkRepository.FindActivityLinkByID(ctx, job.OrganizationID, linkID)
+	if err != nil {
+		if errors.Is(err, ErrActivityLinkNotFound) {
+			// link was removed in the meantime
+			return nil
+		}
+		return err
+	}
+
+	preview, err := s.linkUnfurler.Unfurl(ctx, link.URL)
+	if err != nil {
+		return err
+	}
+
+	if link.Title == "" {
+		link.Title = preview.Title
+	}
+	link.FaviconURL = preview.FaviconURL
+
+	return s.repositoryTxer.InTx(
+		shared.WithOrganizationID(ctx, link.OrganizationID),
+		func(ctx context.Context) error {
+			return s.activityLinkRepository.UpdateActivityLinkPreview(ctx, link)
+		},
+	)
+}
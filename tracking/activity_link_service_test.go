@@ -0,0 +1,124 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+// sampleActivityID is the activity of user1 in the in memory activity repository
+var sampleActivityID = uuid.MustParse("00000000-0000-0000-2222-000000000001")
+
+type fakeLinkUnfurler struct {
+	preview *shared.LinkPreview
+	err     error
+}
+
+func (u *fakeLinkUnfurler) Unfurl(ctx context.Context, link string) (*shared.LinkPreview, error) {
+	return u.preview, u.err
+}
+
+func newActivityLinkServiceForTest(outbox shared.Outbox, linkUnfurler shared.LinkUnfurler) *ActivityLinkService {
+	repositoryTxer := shared.NewInMemRepositoryTxer()
+	return NewActivityLinkService(
+		repositoryTxer,
+		shared.NewJobService(repositoryTxer, shared.NewInMemJobRepository()),
+		outbox,
+		linkUnfurler,
+		NewInMemActivityLinkRepository(),
+		NewInMemActivityRepository(),
+	)
+}
+
+func TestActivityLinks(t *testing.T) {
+	is := is.New(t)
+
+	outbox := shared.NewInMemOutbox(shared.NewInMemMailResource())
+	s := newActivityLinkServiceForTest(outbox, nil)
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+	otherUser := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user2", Roles: []string{"ROLE_USER"}}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	link, err := s.AddActivityLink(context.Background(), user, sampleActivityID, &ActivityLink{URL: "https://example.com/tickets/1", Title: "Ticket 1"})
+	is.NoErr(err)
+	is.Equal(link.ActivityID, sampleActivityID)
+	is.Equal(link.CreatedBy, "user1")
+
+	_, err = s.AddActivityLink(context.Background(), otherUser, sampleActivityID, &ActivityLink{URL: "https://example.com/tickets/2"})
+	is.True(errors.Is(err, ErrActivityNotFound))
+
+	_, err = s.AddActivityLink(context.Background(), user, sampleActivityID, &ActivityLink{URL: "javascript:alert(1)"})
+	is.True(err != nil)
+
+	_, err = s.ReadActivityLinks(context.Background(), otherUser, sampleActivityID)
+	is.True(errors.Is(err, ErrActivityNotFound))
+
+	links, err := s.ReadActivityLinks(context.Background(), admin, sampleActivityID)
+	is.NoErr(err)
+	is.Equal(len(links), 1)
+	is.Equal(links[0].Title, "Ticket 1")
+
+	err = s.DeleteActivityLink(context.Background(), user, sampleActivityID, link.ID)
+	is.NoErr(err)
+
+	err = s.DeleteActivityLink(context.Background(), user, sampleActivityID, link.ID)
+	is.True(errors.Is(err, ErrActivityLinkNotFound))
+
+	is.Equal(len(outbox.Events), 2)
+	is.Equal(outbox.Events[0].Type, EventTypeActivityLinkAdded)
+	is.Equal(outbox.Events[0].Subject, fmt.Sprintf("activities/%s/links/%s", sampleActivityID, link.ID))
+	is.Equal(outbox.Events[1].Type, EventTypeActivityLinkRemoved)
+}
+
+func TestActivityLinksLimit(t *testing.T) {
+	is := is.New(t)
+
+	s := newActivityLinkServiceForTest(shared.NewInMemOutbox(shared.NewInMemMailResource()), nil)
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+
+	for i := 0; i < maxActivityLinks; i++ {
+		_, err := s.AddActivityLink(context.Background(), user, sampleActivityID, &ActivityLink{URL: fmt.Sprintf("https://example.com/tickets/%v", i)})
+		is.NoErr(err)
+	}
+
+	_, err := s.AddActivityLink(context.Background(), user, sampleActivityID, &ActivityLink{URL: "https://example.com/tickets/more"})
+	is.True(errors.Is(err, ErrActivityLinkLimitReached))
+}
+
+func TestActivityLinkUnfurling(t *testing.T) {
+	is := is.New(t)
+
+	linkUnfurler := &fakeLinkUnfurler{
+		preview: &shared.LinkPreview{Title: "Fix login", FaviconURL: "https://example.com/favicon.ico"},
+	}
+	s := newActivityLinkServiceForTest(shared.NewInMemOutbox(shared.NewInMemMailResource()), linkUnfurler)
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+
+	link, err := s.AddActivityLink(context.Background(), user, sampleActivityID, &ActivityLink{URL: "https://example.com/tickets/1"})
+	is.NoErr(err)
+	titled, err := s.AddActivityLink(context.Background(), user, sampleActivityID, &ActivityLink{URL: "https://example.com/tickets/2", Title: "My Ticket"})
+	is.NoErr(err)
+
+	for {
+		processed, err := s.jobService.ProcessNextJob(context.Background())
+		is.NoErr(err)
+		if !processed {
+			break
+		}
+	}
+
+	found, err := s.activityLinkRepository.FindActivityLinkByID(context.Background(), shared.OrganizationIDSample, link.ID)
+	is.NoErr(err)
+	is.Equal(found.Title, "Fix login")
+	is.Equal(found.FaviconURL, "https://example.com/favicon.ico")
+
+	found, err = s.activityLinkRepository.FindActivityLinkByID(context.Background(), shared.OrganizationIDSample, titled.ID)
+	is.NoErr(err)
+	is.Equal(found.Title, "My Ticket")
+	is.Equal(found.FaviconURL, "https://example.com/favicon.ico")
+}
@@ -0,0 +1,284 @@
+package tracking
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+)
+
+const (
+	ActivityQueryFieldProject     = "project"
+	ActivityQueryFieldUser        = "user"
+	ActivityQueryFieldDuration    = "duration"
+	ActivityQueryFieldDescription = "description"
+	ActivityQueryFieldDate        = "date"
+)
+
+// activityQueryOperators are the operators allowed for each field of an activity query
+var activityQueryOperators = map[string][]string{
+	ActivityQueryFieldProject:     {"=", "!=", "in"},
+	ActivityQueryFieldUser:        {"=", "!=", "in"},
+	ActivityQueryFieldDuration:    {"=", "!=", ">", ">=", "<", "<="},
+	ActivityQueryFieldDescription: {"=", "~"},
+	ActivityQueryFieldDate:        {"=", ">", ">=", "<", "<="},
+}
+
+// ActivityQuery is a combination of conditions activities must match like
+// project in (...) and duration>2h and description~"standup"
+type ActivityQuery struct {
+	raw        string
+	Conditions []*ActivityCondition
+}
+
+// ActivityCondition is a condition on a field of an activity
+type ActivityCondition struct {
+	Field      string
+	Operator   string
+	Values     []string
+	ProjectIDs []uuid.UUID
+	Duration   time.Duration
+	Date       time.Time
+}
+
+type activityQueryToken struct {
+	value    string
+	quoted   bool
+	position int
+}
+
+// ParseActivityQuery parses the activity query, all conditions are combined with and
+func ParseActivityQuery(query string) (*ActivityQuery, error) {
+	tokens, err := tokenizeActivityQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	activityQuery := &ActivityQuery{raw: query}
+	for i := 0; i < len(tokens); {
+		if len(activityQuery.Conditions) > 0 {
+			if strings.ToLower(tokens[i].value) != "and" || tokens[i].quoted {
+				return nil, invalidActivityQuery(tokens[i].position, "expected and")
+			}
+			i++
+		}
+
+		condition, next, err := parseActivityCondition(tokens, i, len(query))
+		if err != nil {
+			return nil, err
+		}
+		activityQuery.Conditions = append(activityQuery.Conditions, condition)
+		i = next
+	}
+
+	return activityQuery, nil
+}
+
+// String is the query as given by the user
+func (q *ActivityQuery) String() string {
+	return q.raw
+}
+
+// Matches checks whether the activity matches all conditions of the query
+func (q *ActivityQuery) Matches(activity *Activity) bool {
+	for _, condition := range q.Conditions {
+		if !condition.matches(activity) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *ActivityCondition) matches(activity *Activity) bool {
+	switch c.Field {
+	case ActivityQueryFieldProject:
+		found := false
+		for _, projectID := range c.ProjectIDs {
+			if activity.ProjectID == projectID {
+				found = true
+				break
+			}
+		}
+		return found == (c.Operator != "!=")
+	case ActivityQueryFieldUser:
+		found := false
+		for _, username := range c.Values {
+			if activity.Username == username {
+				found = true
+				break
+			}
+		}
+		return found == (c.Operator != "!=")
+	case ActivityQueryFieldDuration:
+		return compareActivityQueryValues(c.Operator, activity.End.Sub(activity.Start), c.Duration)
+	case ActivityQueryFieldDescription:
+		if c.Operator == "~" {
+			return strings.Contains(strings.ToLower(activity.Description), strings.ToLower(c.Values[0]))
+		}
+		return activity.Description == c.Values[0]
+	case ActivityQueryFieldDate:
+		day := time.Date(activity.Start.Year(), activity.Start.Month(), activity.Start.Day(), 0, 0, 0, 0, time.UTC)
+		return compareActivityQueryValues(c.Operator, day.Sub(c.Date), 0)
+	default:
+		return false
+	}
+}
+
+func compareActivityQueryValues(operator string, value, other time.Duration) bool {
+	switch operator {
+	case "=":
+		return value == other
+	case "!=":
+		return value != other
+	case ">":
+		return value > other
+	case ">=":
+		return value >= other
+	case "<":
+		return value < other
+	case "<=":
+		return value <= other
+	default:
+		return false
+	}
+}
+
+func parseActivityCondition(tokens []*activityQueryToken, i, end int) (*ActivityCondition, int, error) {
+	if i+1 >= len(tokens) {
+		return nil, i, invalidActivityQuery(end, "expected condition like duration>2h")
+	}
+
+	field := strings.ToLower(tokens[i].value)
+	operators, ok := activityQueryOperators[field]
+	if !ok || tokens[i].quoted {
+		return nil, i, invalidActivityQuery(tokens[i].position, fmt.Sprintf("unknown field %s", tokens[i].value))
+	}
+
+	operator := strings.ToLower(tokens[i+1].value)
+	if !slices.Contains(operators, operator) || tokens[i+1].quoted {
+		return nil, i, invalidActivityQuery(tokens[i+1].position, fmt.Sprintf("operator %s not supported for %s", tokens[i+1].value, field))
+	}
+
+	condition := &ActivityCondition{
+		Field:    field,
+		Operator: operator,
+	}
+
+	next := i + 2
+	if operator == "in" {
+		if next >= len(tokens) || tokens[next].value != "(" || tokens[next].quoted {
+			return nil, i, invalidActivityQuery(end, "expected (")
+		}
+		next++
+		for {
+			if next >= len(tokens) {
+				return nil, i, invalidActivityQuery(end, "expected )")
+			}
+			condition.Values = append(condition.Values, tokens[next].value)
+			next++
+			if next < len(tokens) && tokens[next].value == ")" && !tokens[next].quoted {
+				next++
+				break
+			}
+			if next >= len(tokens) || tokens[next].value != "," || tokens[next].quoted {
+				return nil, i, invalidActivityQuery(end, "expected , or )")
+			}
+			next++
+		}
+	} else {
+		if next >= len(tokens) {
+			return nil, i, invalidActivityQuery(end, fmt.Sprintf("expected value for %s", field))
+		}
+		condition.Values = []string{tokens[next].value}
+		next++
+	}
+
+	err := condition.parseValues(tokens[i].position)
+	if err != nil {
+		return nil, i, err
+	}
+
+	return condition, next, nil
+}
+
+func (c *ActivityCondition) parseValues(position int) error {
+	switch c.Field {
+	case ActivityQueryFieldProject:
+		for _, value := range c.Values {
+			projectID, err := uuid.Parse(value)
+			if err != nil {
+				return invalidActivityQuery(position, fmt.Sprintf("project %s is not a valid id", value))
+			}
+			c.ProjectIDs = append(c.ProjectIDs, projectID)
+		}
+	case ActivityQueryFieldDuration:
+		duration, err := time.ParseDuration(c.Values[0])
+		if err != nil {
+			return invalidActivityQuery(position, fmt.Sprintf("duration %s is not valid like 2h or 1h30m", c.Values[0]))
+		}
+		c.Duration = duration
+	case ActivityQueryFieldDate:
+		date, err := time_utils.ParseDate(c.Values[0])
+		if err != nil {
+			return invalidActivityQuery(position, fmt.Sprintf("date %s is not valid like 2021-12-31", c.Values[0]))
+		}
+		c.Date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return nil
+}
+
+func tokenizeActivityQuery(query string) ([]*activityQueryToken, error) {
+	var tokens []*activityQueryToken
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, &activityQueryToken{value: string(r), position: i})
+			i++
+		case r == '"':
+			start := i
+			i++
+			var value strings.Builder
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, invalidActivityQuery(start, "unterminated string")
+			}
+			i++
+			tokens = append(tokens, &activityQueryToken{value: value.String(), quoted: true, position: start})
+		case strings.ContainsRune("=!<>~", r):
+			start := i
+			i++
+			if i < len(runes) && runes[i] == '=' && r != '=' && r != '~' {
+				i++
+			}
+			tokens = append(tokens, &activityQueryToken{value: string(runes[start:i]), position: start})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("(),\"=!<>~", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, &activityQueryToken{value: string(runes[start:i]), position: start})
+		}
+	}
+
+	return tokens, nil
+}
+
+func invalidActivityQuery(position int, message string) *shared.InvalidParam {
+	return shared.NewInvalidParam("filter", "syntax", fmt.Sprintf("%s at position %d", message, position))
+}
@@ -0,0 +1,107 @@
+package tracking
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestParseActivityQuery(t *testing.T) {
+	is := is.New(t)
+
+	query, err := ParseActivityQuery(`project in (00000000-0000-0000-1111-000000000001, 00000000-0000-0000-1111-000000000002) and duration>2h AND description~"daily standup"`)
+	is.NoErr(err)
+	is.Equal(len(query.Conditions), 3)
+
+	is.Equal(query.Conditions[0].Field, ActivityQueryFieldProject)
+	is.Equal(query.Conditions[0].Operator, "in")
+	is.Equal(len(query.Conditions[0].ProjectIDs), 2)
+
+	is.Equal(query.Conditions[1].Field, ActivityQueryFieldDuration)
+	is.Equal(query.Conditions[1].Operator, ">")
+	is.Equal(query.Conditions[1].Duration, 2*time.Hour)
+
+	is.Equal(query.Conditions[2].Field, ActivityQueryFieldDescription)
+	is.Equal(query.Conditions[2].Operator, "~")
+	is.Equal(query.Conditions[2].Values[0], "daily standup")
+}
+
+func TestParseActivityQueryWithDate(t *testing.T) {
+	is := is.New(t)
+
+	query, err := ParseActivityQuery(`date>=2022-03-01 and user!=user1`)
+	is.NoErr(err)
+	is.Equal(len(query.Conditions), 2)
+	is.Equal(query.Conditions[0].Date, time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC))
+	is.Equal(query.Conditions[1].Values, []string{"user1"})
+}
+
+func TestParseActivityQueryNotValid(t *testing.T) {
+	var tests = []struct {
+		query   string
+		message string
+	}{
+		{`tag="billing"`, "unknown field tag at position 0"},
+		{`duration>two`, "duration two is not valid like 2h or 1h30m at position 0"},
+		{`description~"standup`, "unterminated string at position 12"},
+		{`description>"standup"`, "operator > not supported for description at position 11"},
+		{`duration>2h duration<4h`, "expected and at position 12"},
+		{`project in (00000000-0000-0000-1111-000000000001`, "expected , or ) at position 48"},
+		{`user=`, "expected value for user at position 5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			is := is.New(t)
+
+			_, err := ParseActivityQuery(tt.query)
+			is.True(err != nil)
+
+			var invalidParam *shared.InvalidParam
+			is.True(errors.As(err, &invalidParam))
+			is.Equal(invalidParam.Field, "filter")
+			is.Equal(invalidParam.Message, tt.message)
+		})
+	}
+}
+
+func TestActivityQueryMatches(t *testing.T) {
+	activity := &Activity{
+		ID:          uuid.New(),
+		ProjectID:   shared.ProjectIDSample,
+		Username:    "user1",
+		Description: "Daily Standup",
+		Start:       time.Date(2022, 3, 1, 9, 0, 0, 0, time.UTC),
+		End:         time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	var tests = []struct {
+		query   string
+		matches bool
+	}{
+		{`project=` + shared.ProjectIDSample.String(), true},
+		{`project!=` + shared.ProjectIDSample.String(), false},
+		{`user in (user1, user2)`, true},
+		{`duration>2h`, true},
+		{`duration>2h and duration<3h`, false},
+		{`description~"standup"`, true},
+		{`description="standup"`, false},
+		{`date=2022-03-01`, true},
+		{`date>2022-03-01`, false},
+		{`date<=2022-03-01`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			is := is.New(t)
+
+			query, err := ParseActivityQuery(tt.query)
+			is.NoErr(err)
+			is.Equal(query.Matches(activity), tt.matches)
+		})
+	}
+}
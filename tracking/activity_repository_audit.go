@@ -0,0 +1,183 @@
+package tracking
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	AuditEntityActivity = "activity"
+
+	AuditActionActivityCreated = "activity:created"
+	AuditActionActivityUpdated = "activity:updated"
+	AuditActionActivityDeleted = "activity:deleted"
+
+	// auditActorSystem is the actor of changes made outside of a request
+	auditActorSystem = "system"
+)
+
+// AuditedActivityRepository records every change of an activity with the changed fields
+// in the audit log, within the transaction of the change
+type AuditedActivityRepository struct {
+	ActivityRepository
+	auditRepository shared.AuditRepository
+}
+
+var _ ActivityRepository = (*AuditedActivityRepository)(nil)
+
+// NewAuditedActivityRepository creates a new activity repository which records changes in the audit log
+func NewAuditedActivityRepository(activityRepository ActivityRepository, auditRepository shared.AuditRepository) *AuditedActivityRepository {
+	return &AuditedActivityRepository{
+		ActivityRepository: activityRepository,
+		auditRepository:    auditRepository,
+	}
+}
+
+func (r *AuditedActivityRepository) InsertActivity(ctx context.Context, activity *Activity) (*Activity, error) {
+	inserted, err := r.ActivityRepository.InsertActivity(ctx, activity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.recordChange(ctx, AuditActionActivityCreated, inserted.OrganizationID, inserted.ID, nil, inserted)
+	if err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+func (r *AuditedActivityRepository) InsertActivities(ctx context.Context, activities []*Activity) (int, error) {
+	count, err := r.ActivityRepository.InsertActivities(ctx, activities)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, activity := range activities {
+		err := r.recordChange(ctx, AuditActionActivityCreated, activity.OrganizationID, activity.ID, nil, activity)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+func (r *AuditedActivityRepository) UpdateActivity(ctx context.Context, organizationID uuid.UUID, activity *Activity) (*Activity, error) {
+	before, err := r.ActivityRepository.FindActivityByID(ctx, activity.ID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.ActivityRepository.UpdateActivity(ctx, organizationID, activity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.recordChange(ctx, AuditActionActivityUpdated, organizationID, updated.ID, before, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *AuditedActivityRepository) UpdateActivityByUsername(ctx context.Context, organizationID uuid.UUID, activity *Activity, username string) (*Activity, error) {
+	before, err := r.ActivityRepository.FindActivityByID(ctx, activity.ID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.ActivityRepository.UpdateActivityByUsername(ctx, organizationID, activity, username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.recordChange(ctx, AuditActionActivityUpdated, organizationID, updated.ID, before, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *AuditedActivityRepository) DeleteActivityByID(ctx context.Context, organizationID, activityID uuid.UUID) error {
+	before, err := r.ActivityRepository.FindActivityByID(ctx, activityID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	err = r.ActivityRepository.DeleteActivityByID(ctx, organizationID, activityID)
+	if err != nil {
+		return err
+	}
+
+	return r.recordChange(ctx, AuditActionActivityDeleted, organizationID, activityID, before, nil)
+}
+
+func (r *AuditedActivityRepository) DeleteActivityByIDAndUsername(ctx context.Context, organizationID, activityID uuid.UUID, username string) error {
+	before, err := r.ActivityRepository.FindActivityByID(ctx, activityID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	err = r.ActivityRepository.DeleteActivityByIDAndUsername(ctx, organizationID, activityID, username)
+	if err != nil {
+		return err
+	}
+
+	return r.recordChange(ctx, AuditActionActivityDeleted, organizationID, activityID, before, nil)
+}
+
+// recordChange adds the changed fields of the activity to the audit log, updates without changes are not recorded
+func (r *AuditedActivityRepository) recordChange(ctx context.Context, action string, organizationID, activityID uuid.UUID, before, after *Activity) error {
+	auditEntry := shared.NewEntityAuditEntry(
+		auditActorOf(ctx, organizationID),
+		action,
+		AuditEntityActivity,
+		activityID,
+		auditFieldsOfActivity(before),
+		auditFieldsOfActivity(after),
+		time.Now(),
+	)
+	if action == AuditActionActivityUpdated && len(auditEntry.Changes) == 0 {
+		return nil
+	}
+
+	return r.auditRepository.InsertAuditEntry(ctx, auditEntry)
+}
+
+func auditFieldsOfActivity(activity *Activity) map[string]string {
+	if activity == nil {
+		return nil
+	}
+
+	fields := map[string]string{
+		"start":       activity.Start.UTC().Format(time.RFC3339),
+		"end":         activity.End.UTC().Format(time.RFC3339),
+		"description": activity.Description,
+		"projectId":   activity.ProjectID.String(),
+		"username":    activity.Username,
+		"location":    activity.Location,
+	}
+	if activity.Latitude != nil {
+		fields["latitude"] = strconv.FormatFloat(*activity.Latitude, 'f', -1, 64)
+	}
+	if activity.Longitude != nil {
+		fields["longitude"] = strconv.FormatFloat(*activity.Longitude, 'f', -1, 64)
+	}
+	return fields
+}
+
+// auditActorOf is the principal changing an entity, changes outside of a request are made by the system
+func auditActorOf(ctx context.Context, organizationID uuid.UUID) *shared.Principal {
+	principal, ok := ctx.Value(shared.ContextKeyPrincipal).(*shared.Principal)
+	if ok && principal != nil {
+		return principal
+	}
+
+	return &shared.Principal{
+		OrganizationID: organizationID,
+		Username:       auditActorSystem,
+	}
+}
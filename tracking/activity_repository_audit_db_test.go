@@ -0,0 +1,64 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/testkit"
+	"github.com/matryer/is"
+)
+
+func TestAuditedActivityRepositoryOnDatabase(t *testing.T) {
+	is := is.New(t)
+	db := testkit.NewDatabase(t)
+
+	organization := db.Organization().Insert(t)
+	user := db.User(organization.ID).Insert(t)
+	project := db.Project(organization.ID).Insert(t)
+	fixture := db.Activity(organization.ID, project.ID, user.Username).Insert(t)
+
+	auditRepository := shared.NewDbAuditRepository(db.ConnPool)
+	activityRepository := NewAuditedActivityRepository(NewDbActivityRepository(db.ConnPool), auditRepository)
+	ctx := context.WithValue(context.Background(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: organization.ID,
+		Username:       user.Username,
+	})
+
+	t.Run("UpdateActivity", func(t *testing.T) {
+		activity, err := activityRepository.FindActivityByID(ctx, fixture.ID, organization.ID)
+		is.NoErr(err)
+
+		activity.Description = "Edited"
+		err = db.RepositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				_, err := activityRepository.UpdateActivity(ctx, organization.ID, activity)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		auditEntries, err := auditRepository.FindAuditEntriesOfEntity(ctx, organization.ID, AuditEntityActivity, fixture.ID)
+		is.NoErr(err)
+		is.Equal(len(auditEntries), 1)
+		is.Equal(auditEntries[0].Username, user.Username)
+		is.Equal(len(auditEntries[0].Changes), 1)
+		is.Equal(*auditEntries[0].Changes[0], shared.AuditChange{Field: "description", OldValue: fixture.Description, NewValue: "Edited"})
+	})
+
+	t.Run("DeleteActivityByID", func(t *testing.T) {
+		err := db.RepositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return activityRepository.DeleteActivityByID(ctx, organization.ID, fixture.ID)
+			},
+		)
+		is.NoErr(err)
+
+		auditEntries, err := auditRepository.FindAuditEntriesOfEntity(ctx, organization.ID, AuditEntityActivity, fixture.ID)
+		is.NoErr(err)
+		is.Equal(len(auditEntries), 2)
+		is.Equal(auditEntries[1].Action, AuditActionActivityDeleted)
+	})
+}
@@ -0,0 +1,84 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestAuditedActivityRepository(t *testing.T) {
+	is := is.New(t)
+
+	auditRepository := shared.NewInMemAuditRepository()
+	activityRepository := NewAuditedActivityRepository(NewInMemActivityRepository(), auditRepository)
+
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", ImpersonatedBy: "ops@baralga.com"}
+	ctx := context.WithValue(context.Background(), shared.ContextKeyPrincipal, principal)
+
+	activityID := uuid.New()
+	start := time.Date(2021, 11, 5, 10, 0, 0, 0, time.UTC)
+	activity := &Activity{
+		ID:             activityID,
+		Start:          start,
+		End:            start.Add(time.Hour),
+		Description:    "Meeting",
+		ProjectID:      shared.ProjectIDSample,
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}
+	_, err := activityRepository.InsertActivity(ctx, activity)
+	is.NoErr(err)
+
+	updated := *activity
+	updated.End = start.Add(2 * time.Hour)
+	_, err = activityRepository.UpdateActivity(ctx, shared.OrganizationIDSample, &updated)
+	is.NoErr(err)
+
+	unchanged := updated
+	_, err = activityRepository.UpdateActivityByUsername(ctx, shared.OrganizationIDSample, &unchanged, "user1")
+	is.NoErr(err)
+
+	err = activityRepository.DeleteActivityByID(context.Background(), shared.OrganizationIDSample, activityID)
+	is.NoErr(err)
+
+	auditEntries, err := auditRepository.FindAuditEntriesOfEntity(context.Background(), shared.OrganizationIDSample, AuditEntityActivity, activityID)
+	is.NoErr(err)
+	is.Equal(len(auditEntries), 3)
+
+	is.Equal(auditEntries[0].Action, AuditActionActivityCreated)
+	is.Equal(auditEntries[0].Username, "admin")
+	is.Equal(auditEntries[0].ImpersonatedBy, "ops@baralga.com")
+
+	is.Equal(auditEntries[1].Action, AuditActionActivityUpdated)
+	is.Equal(len(auditEntries[1].Changes), 1)
+	is.Equal(*auditEntries[1].Changes[0], shared.AuditChange{Field: "end", OldValue: "2021-11-05T11:00:00Z", NewValue: "2021-11-05T12:00:00Z"})
+
+	is.Equal(auditEntries[2].Action, AuditActionActivityDeleted)
+	is.Equal(auditEntries[2].Username, "system")
+	is.Equal(auditEntries[2].Changes[0].NewValue, "")
+}
+
+func TestAuditedActivityRepositoryInsertActivities(t *testing.T) {
+	is := is.New(t)
+
+	auditRepository := shared.NewInMemAuditRepository()
+	activityRepository := NewAuditedActivityRepository(NewInMemActivityRepository(), auditRepository)
+
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	ctx := context.WithValue(context.Background(), shared.ContextKeyPrincipal, principal)
+
+	start := time.Date(2021, 11, 5, 10, 0, 0, 0, time.UTC)
+	activity := &Activity{ID: uuid.New(), Start: start, End: start.Add(time.Hour), ProjectID: shared.ProjectIDSample, OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	_, err := activityRepository.InsertActivities(ctx, []*Activity{activity})
+	is.NoErr(err)
+
+	auditEntries, err := auditRepository.FindAuditEntriesOfEntity(context.Background(), shared.OrganizationIDSample, AuditEntityActivity, activity.ID)
+	is.NoErr(err)
+	is.Equal(len(auditEntries), 1)
+	is.Equal(auditEntries[0].Action, AuditActionActivityCreated)
+	is.Equal(auditEntries[0].Username, "user1")
+}
@@ -0,0 +1,246 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+)
+
+// CachedActivityRepository caches the report aggregations of an activity repository
+// per organization. The cache of an organization is invalidated on every activity write, on changes of projects
+// and on repairs of integrity problems, the cached reports expire after the configured duration.
+type CachedActivityRepository struct {
+	activityRepository ActivityRepository
+	expiry             time.Duration
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]map[string]*reportCacheEntry
+
+	// generations count the invalidations per organization, so reports loaded
+	// before an invalidation are not cached after it
+	generations map[uuid.UUID]uint64
+}
+
+type reportCacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+var _ ActivityRepository = (*CachedActivityRepository)(nil)
+
+// NewCachedActivityRepository creates a new report cache for the activity repository
+func NewCachedActivityRepository(activityRepository ActivityRepository, expiry time.Duration) *CachedActivityRepository {
+	return &CachedActivityRepository{
+		activityRepository: activityRepository,
+		expiry:             expiry,
+		entries:            make(map[uuid.UUID]map[string]*reportCacheEntry),
+		generations:        make(map[uuid.UUID]uint64),
+	}
+}
+
+func (r *CachedActivityRepository) TimeReportByDay(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityTimeReportItem, error) {
+	return cachedReport(r, filter.OrganizationID, reportCacheKey("day", filter), func() ([]*ActivityTimeReportItem, error) {
+		return r.activityRepository.TimeReportByDay(ctx, filter)
+	})
+}
+
+func (r *CachedActivityRepository) TimeReportByWeek(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityTimeReportItem, error) {
+	return cachedReport(r, filter.OrganizationID, reportCacheKey("week", filter), func() ([]*ActivityTimeReportItem, error) {
+		return r.activityRepository.TimeReportByWeek(ctx, filter)
+	})
+}
+
+func (r *CachedActivityRepository) TimeReportByMonth(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityTimeReportItem, error) {
+	return cachedReport(r, filter.OrganizationID, reportCacheKey("month", filter), func() ([]*ActivityTimeReportItem, error) {
+		return r.activityRepository.TimeReportByMonth(ctx, filter)
+	})
+}
+
+func (r *CachedActivityRepository) TimeReportByQuarter(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityTimeReportItem, error) {
+	return cachedReport(r, filter.OrganizationID, reportCacheKey("quarter", filter), func() ([]*ActivityTimeReportItem, error) {
+		return r.activityRepository.TimeReportByQuarter(ctx, filter)
+	})
+}
+
+func (r *CachedActivityRepository) ProjectReport(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityProjectReportItem, error) {
+	return cachedReport(r, filter.OrganizationID, reportCacheKey("project", filter), func() ([]*ActivityProjectReportItem, error) {
+		return r.activityRepository.ProjectReport(ctx, filter)
+	})
+}
+
+func (r *CachedActivityRepository) UtilizationReportByUser(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityUtilizationReportItem, error) {
+	return cachedReport(r, filter.OrganizationID, reportCacheKey("utilization-user", filter), func() ([]*ActivityUtilizationReportItem, error) {
+		return r.activityRepository.UtilizationReportByUser(ctx, filter)
+	})
+}
+
+func (r *CachedActivityRepository) UtilizationReportByWeek(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityUtilizationReportItem, error) {
+	return cachedReport(r, filter.OrganizationID, reportCacheKey("utilization-week", filter), func() ([]*ActivityUtilizationReportItem, error) {
+		return r.activityRepository.UtilizationReportByWeek(ctx, filter)
+	})
+}
+
+func (r *CachedActivityRepository) LocationReport(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityLocationReportItem, error) {
+	return cachedReport(r, filter.OrganizationID, reportCacheKey("location", filter), func() ([]*ActivityLocationReportItem, error) {
+		return r.activityRepository.LocationReport(ctx, filter)
+	})
+}
+
+func (r *CachedActivityRepository) ProjectBurndownReport(ctx context.Context, organizationID, projectID uuid.UUID, aggregateBy string) ([]*ProjectBurndownItem, error) {
+	key := fmt.Sprintf("burndown|%s|%s", projectID, aggregateBy)
+	return cachedReport(r, organizationID, key, func() ([]*ProjectBurndownItem, error) {
+		return r.activityRepository.ProjectBurndownReport(ctx, organizationID, projectID, aggregateBy)
+	})
+}
+
+func (r *CachedActivityRepository) FindActivities(ctx context.Context, filter *ActivitiesFilter, pageParams *paged.PageParams) (*ActivitiesPaged, []*Project, error) {
+	return r.activityRepository.FindActivities(ctx, filter, pageParams)
+}
+
+func (r *CachedActivityRepository) StreamActivities(ctx context.Context, filter *ActivitiesFilter, consume func(activity *Activity, project *Project) error) error {
+	return r.activityRepository.StreamActivities(ctx, filter, consume)
+}
+
+func (r *CachedActivityRepository) FindActivityByID(ctx context.Context, activityID uuid.UUID, organizationID uuid.UUID) (*Activity, error) {
+	return r.activityRepository.FindActivityByID(ctx, activityID, organizationID)
+}
+
+func (r *CachedActivityRepository) InsertActivity(ctx context.Context, activity *Activity) (*Activity, error) {
+	r.invalidateAfterCommit(ctx, activity.OrganizationID)
+	return r.activityRepository.InsertActivity(ctx, activity)
+}
+
+func (r *CachedActivityRepository) InsertActivities(ctx context.Context, activities []*Activity) (int, error) {
+	for _, activity := range activities {
+		r.invalidateAfterCommit(ctx, activity.OrganizationID)
+	}
+	return r.activityRepository.InsertActivities(ctx, activities)
+}
+
+func (r *CachedActivityRepository) DeleteActivityByID(ctx context.Context, organizationID, activityID uuid.UUID) error {
+	r.invalidateAfterCommit(ctx, organizationID)
+	return r.activityRepository.DeleteActivityByID(ctx, organizationID, activityID)
+}
+
+func (r *CachedActivityRepository) DeleteActivityByIDAndUsername(ctx context.Context, organizationID, activityID uuid.UUID, username string) error {
+	r.invalidateAfterCommit(ctx, organizationID)
+	return r.activityRepository.DeleteActivityByIDAndUsername(ctx, organizationID, activityID, username)
+}
+
+func (r *CachedActivityRepository) UpdateActivity(ctx context.Context, organizationID uuid.UUID, activity *Activity) (*Activity, error) {
+	r.invalidateAfterCommit(ctx, organizationID)
+	return r.activityRepository.UpdateActivity(ctx, organizationID, activity)
+}
+
+func (r *CachedActivityRepository) UpdateActivityByUsername(ctx context.Context, organizationID uuid.UUID, activity *Activity, username string) (*Activity, error) {
+	r.invalidateAfterCommit(ctx, organizationID)
+	return r.activityRepository.UpdateActivityByUsername(ctx, organizationID, activity, username)
+}
+
+// invalidateAfterCommit invalidates the cached reports of the organization once the transaction of the write
+// committed, so a concurrent read can't cache the data from before the write. It's invalidated once per transaction.
+func (r *CachedActivityRepository) invalidateAfterCommit(ctx context.Context, organizationID uuid.UUID) {
+	shared.AfterCommit(ctx, "report-cache/"+organizationID.String(), func() {
+		r.Invalidate(organizationID)
+	})
+}
+
+// Invalidate removes all cached reports of the organization
+func (r *CachedActivityRepository) Invalidate(organizationID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, organizationID)
+	r.generations[organizationID]++
+}
+
+func (r *CachedActivityRepository) generation(organizationID uuid.UUID) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.generations[organizationID]
+}
+
+func (r *CachedActivityRepository) get(organizationID uuid.UUID, key string, now time.Time) (any, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[organizationID][key]
+	if !ok || now.After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// put caches the report unless the cache of the organization was invalidated since the report was loaded
+func (r *CachedActivityRepository) put(organizationID uuid.UUID, key string, value any, generation uint64, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.generations[organizationID] != generation {
+		return
+	}
+
+	organizationEntries, ok := r.entries[organizationID]
+	if !ok {
+		organizationEntries = make(map[string]*reportCacheEntry)
+		r.entries[organizationID] = organizationEntries
+	}
+
+	// prune expired entries to keep the cache small
+	for k, entry := range organizationEntries {
+		if now.After(entry.expiresAt) {
+			delete(organizationEntries, k)
+		}
+	}
+
+	organizationEntries[key] = &reportCacheEntry{
+		value:     value,
+		expiresAt: now.Add(r.expiry),
+	}
+}
+
+func cachedReport[T any](r *CachedActivityRepository, organizationID uuid.UUID, key string, load func() ([]T, error)) ([]T, error) {
+	if r.expiry <= 0 {
+		return load()
+	}
+
+	now := time.Now()
+	if value, ok := r.get(organizationID, key, now); ok {
+		return value.([]T), nil
+	}
+
+	generation := r.generation(organizationID)
+	reportItems, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	r.put(organizationID, key, reportItems, generation, now)
+	return reportItems, nil
+}
+
+// reportCacheKey contains every field of the filter which changes the report,
+// so anonymized reports never share an entry with reports of real usernames
+func reportCacheKey(report string, filter *ActivitiesFilter) string {
+	query := ""
+	if filter.Query != nil {
+		query = filter.Query.String()
+	}
+
+	return fmt.Sprintf(
+		"%s|%d|%d|%s|%s|%s|%t|%t|%q",
+		report,
+		filter.Start.UnixNano(),
+		filter.End.UnixNano(),
+		filter.Username,
+		filter.SortBy,
+		filter.SortOrder,
+		filter.AnonymizeUsers,
+		filter.EmbedUsers,
+		query,
+	)
+}
@@ -0,0 +1,182 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestCachedActivityRepository(t *testing.T) {
+	is := is.New(t)
+
+	inMemActivityRepository := NewInMemActivityRepository()
+	activityRepository := NewCachedActivityRepository(inMemActivityRepository, time.Minute)
+
+	filter := &ActivitiesFilter{
+		OrganizationID: shared.OrganizationIDSample,
+	}
+
+	t.Run("ReportIsCached", func(t *testing.T) {
+		reportItems, err := activityRepository.ProjectReport(context.Background(), filter)
+		is.NoErr(err)
+		is.Equal(len(reportItems), 1)
+
+		inMemActivityRepository.activities = append(inMemActivityRepository.activities, &Activity{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+		})
+
+		reportItems, err = activityRepository.ProjectReport(context.Background(), filter)
+		is.NoErr(err)
+		is.Equal(len(reportItems), 1)
+	})
+
+	t.Run("ReportIsInvalidatedOnWrite", func(t *testing.T) {
+		_, err := activityRepository.InsertActivity(context.Background(), &Activity{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+		})
+		is.NoErr(err)
+
+		reportItems, err := activityRepository.ProjectReport(context.Background(), filter)
+		is.NoErr(err)
+		is.Equal(len(reportItems), 3)
+	})
+}
+
+func TestCachedActivityRepositoryInvalidatedAfterCommit(t *testing.T) {
+	is := is.New(t)
+
+	inMemActivityRepository := NewInMemActivityRepository()
+	activityRepository := NewCachedActivityRepository(inMemActivityRepository, time.Minute)
+	repositoryTxer := shared.NewInMemRepositoryTxer()
+
+	filter := &ActivitiesFilter{
+		OrganizationID: shared.OrganizationIDSample,
+	}
+
+	err := repositoryTxer.InTx(context.Background(), func(ctx context.Context) error {
+		_, err := activityRepository.InsertActivities(ctx, []*Activity{
+			{ID: uuid.New(), OrganizationID: shared.OrganizationIDSample},
+			{ID: uuid.New(), OrganizationID: shared.OrganizationIDSample},
+		})
+		is.NoErr(err)
+
+		// a concurrent read before the commit caches the report
+		reportItems, err := activityRepository.ProjectReport(ctx, filter)
+		is.NoErr(err)
+		is.Equal(len(reportItems), 3)
+
+		inMemActivityRepository.activities = append(inMemActivityRepository.activities, &Activity{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+		})
+		return nil
+	})
+	is.NoErr(err)
+
+	reportItems, err := activityRepository.ProjectReport(context.Background(), filter)
+	is.NoErr(err)
+	is.Equal(len(reportItems), 4)
+}
+
+func TestCachedActivityRepositoryInvalidatedWhileLoading(t *testing.T) {
+	is := is.New(t)
+
+	activityRepository := NewCachedActivityRepository(NewInMemActivityRepository(), time.Minute)
+
+	loads := 0
+	load := func() ([]int, error) {
+		loads++
+		if loads == 1 {
+			// a write commits while the report is read from before the write
+			activityRepository.Invalidate(shared.OrganizationIDSample)
+		}
+		return []int{loads}, nil
+	}
+
+	reportItems, err := cachedReport(activityRepository, shared.OrganizationIDSample, "report", load)
+	is.NoErr(err)
+	is.Equal(reportItems[0], 1)
+
+	reportItems, err = cachedReport(activityRepository, shared.OrganizationIDSample, "report", load)
+	is.NoErr(err)
+	is.Equal(reportItems[0], 2)
+
+	reportItems, err = cachedReport(activityRepository, shared.OrganizationIDSample, "report", load)
+	is.NoErr(err)
+	is.Equal(reportItems[0], 2)
+}
+
+func TestCachedActivityRepositoryAnonymizedReport(t *testing.T) {
+	is := is.New(t)
+
+	activityRepository := NewCachedActivityRepository(NewInMemActivityRepository(), time.Minute)
+
+	reportItems, err := activityRepository.UtilizationReportByUser(context.Background(), &ActivitiesFilter{
+		OrganizationID: shared.OrganizationIDSample,
+	})
+	is.NoErr(err)
+	is.Equal(len(reportItems), 1)
+	is.Equal(reportItems[0].Username, "user1")
+
+	reportItems, err = activityRepository.UtilizationReportByUser(context.Background(), &ActivitiesFilter{
+		OrganizationID: shared.OrganizationIDSample,
+		AnonymizeUsers: true,
+	})
+	is.NoErr(err)
+	is.Equal(len(reportItems), 1)
+	is.Equal(reportItems[0].Username, AnonymousUsername(1))
+}
+
+func TestCachedActivityRepositoryDisabled(t *testing.T) {
+	is := is.New(t)
+
+	inMemActivityRepository := NewInMemActivityRepository()
+	activityRepository := NewCachedActivityRepository(inMemActivityRepository, 0)
+
+	filter := &ActivitiesFilter{
+		OrganizationID: shared.OrganizationIDSample,
+	}
+
+	reportItems, err := activityRepository.TimeReportByDay(context.Background(), filter)
+	is.NoErr(err)
+	is.Equal(len(reportItems), 1)
+
+	inMemActivityRepository.activities = append(inMemActivityRepository.activities, &Activity{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+	})
+
+	reportItems, err = activityRepository.TimeReportByDay(context.Background(), filter)
+	is.NoErr(err)
+	is.Equal(len(reportItems), 2)
+}
+
+func TestReportInvalidatingProjectRepository(t *testing.T) {
+	is := is.New(t)
+
+	inMemActivityRepository := NewInMemActivityRepository()
+	reportCache := NewCachedActivityRepository(inMemActivityRepository, time.Minute)
+	projectRepository := NewReportInvalidatingProjectRepository(NewInMemProjectRepository(), reportCache)
+
+	filter := &ActivitiesFilter{
+		OrganizationID: shared.OrganizationIDSample,
+	}
+
+	reportItems, err := reportCache.ProjectReport(context.Background(), filter)
+	is.NoErr(err)
+	is.Equal(len(reportItems), 1)
+
+	inMemActivityRepository.activities = nil
+	err = projectRepository.DeleteProjectByID(context.Background(), shared.OrganizationIDSample, shared.ProjectIDSample)
+	is.NoErr(err)
+
+	reportItems, err = reportCache.ProjectReport(context.Background(), filter)
+	is.NoErr(err)
+	is.Equal(len(reportItems), 0)
+}
@@ -0,0 +1,202 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/testkit"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+// activityRepositoryContractOrganization is an organization with a user and a project to track activities on
+type activityRepositoryContractOrganization struct {
+	OrganizationID uuid.UUID
+	ProjectID      uuid.UUID
+	Username       string
+}
+
+// activityRepositoryContractSetup provides the activity repository under test with a function
+// creating a new organization without activities
+type activityRepositoryContractSetup func(t *testing.T) (ActivityRepository, shared.RepositoryTxer, func() *activityRepositoryContractOrganization)
+
+func TestInMemActivityRepositoryContract(t *testing.T) {
+	testActivityRepositoryContract(t, func(t *testing.T) (ActivityRepository, shared.RepositoryTxer, func() *activityRepositoryContractOrganization) {
+		return NewInMemActivityRepository(), shared.NewInMemRepositoryTxer(), func() *activityRepositoryContractOrganization {
+			return &activityRepositoryContractOrganization{
+				OrganizationID: uuid.New(),
+				ProjectID:      uuid.New(),
+				Username:       "user1",
+			}
+		}
+	})
+}
+
+func TestDbActivityRepositoryContract(t *testing.T) {
+	testActivityRepositoryContract(t, func(t *testing.T) (ActivityRepository, shared.RepositoryTxer, func() *activityRepositoryContractOrganization) {
+		db := testkit.NewDatabase(t)
+		return NewDbActivityRepository(db.ConnPool), db.RepositoryTxer, func() *activityRepositoryContractOrganization {
+			organization := db.Organization().Insert(t)
+			user := db.User(organization.ID).Insert(t)
+			project := db.Project(organization.ID).Insert(t)
+			return &activityRepositoryContractOrganization{
+				OrganizationID: organization.ID,
+				ProjectID:      project.ID,
+				Username:       user.Username,
+			}
+		}
+	})
+}
+
+// testActivityRepositoryContract verifies the behavior all implementations of the activity repository share
+func testActivityRepositoryContract(t *testing.T, setup activityRepositoryContractSetup) {
+	is := is.New(t)
+	activityRepository, repositoryTxer, newOrganization := setup(t)
+	ctx := context.Background()
+
+	start := time.Date(2021, 10, 14, 14, 0, 0, 0, time.UTC)
+
+	insertActivity := func(organization *activityRepositoryContractOrganization, description string) *Activity {
+		activity := &Activity{
+			ID:             uuid.New(),
+			Start:          start,
+			End:            start.Add(time.Hour),
+			Description:    description,
+			ProjectID:      organization.ProjectID,
+			OrganizationID: organization.OrganizationID,
+			Username:       organization.Username,
+		}
+		err := repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				_, err := activityRepository.InsertActivity(ctx, activity)
+				return err
+			},
+		)
+		is.NoErr(err)
+		return activity
+	}
+
+	t.Run("FindActivityByID", func(t *testing.T) {
+		organization := newOrganization()
+		activity := insertActivity(organization, "My Activity")
+
+		found, err := activityRepository.FindActivityByID(ctx, activity.ID, organization.OrganizationID)
+		is.NoErr(err)
+		is.Equal(found.ID, activity.ID)
+		is.Equal(found.Description, "My Activity")
+		is.Equal(found.Username, organization.Username)
+		is.Equal(found.ProjectID, organization.ProjectID)
+		is.True(found.Start.Equal(activity.Start))
+		is.True(found.End.Equal(activity.End))
+
+		_, err = activityRepository.FindActivityByID(ctx, uuid.New(), organization.OrganizationID)
+		is.Equal(err, ErrActivityNotFound)
+	})
+
+	t.Run("ActivitiesOfOtherOrganization", func(t *testing.T) {
+		organization := newOrganization()
+		other := insertActivity(newOrganization(), "Other")
+
+		_, err := activityRepository.FindActivityByID(ctx, other.ID, organization.OrganizationID)
+		is.Equal(err, ErrActivityNotFound)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				changed := *other
+				changed.Description = "Changed"
+				_, err := activityRepository.UpdateActivity(ctx, organization.OrganizationID, &changed)
+				return err
+			},
+		)
+		is.Equal(err, ErrActivityNotFound)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return activityRepository.DeleteActivityByID(ctx, organization.OrganizationID, other.ID)
+			},
+		)
+		is.Equal(err, ErrActivityNotFound)
+
+		found, err := activityRepository.FindActivityByID(ctx, other.ID, other.OrganizationID)
+		is.NoErr(err)
+		is.Equal(found.Description, "Other")
+	})
+
+	t.Run("ActivitiesOfOtherUser", func(t *testing.T) {
+		organization := newOrganization()
+		activity := insertActivity(organization, "My Activity")
+
+		err := repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				changed := *activity
+				changed.Description = "Changed"
+				_, err := activityRepository.UpdateActivityByUsername(ctx, organization.OrganizationID, &changed, "other-user")
+				return err
+			},
+		)
+		is.Equal(err, ErrActivityNotFound)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return activityRepository.DeleteActivityByIDAndUsername(ctx, organization.OrganizationID, activity.ID, "other-user")
+			},
+		)
+		is.Equal(err, ErrActivityNotFound)
+
+		found, err := activityRepository.FindActivityByID(ctx, activity.ID, organization.OrganizationID)
+		is.NoErr(err)
+		is.Equal(found.Description, "My Activity")
+	})
+
+	t.Run("UpdateActivity", func(t *testing.T) {
+		organization := newOrganization()
+		activity := insertActivity(organization, "My Activity")
+
+		err := repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				changed := *activity
+				changed.Description = "Changed"
+				_, err := activityRepository.UpdateActivityByUsername(ctx, organization.OrganizationID, &changed, organization.Username)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		found, err := activityRepository.FindActivityByID(ctx, activity.ID, organization.OrganizationID)
+		is.NoErr(err)
+		is.Equal(found.Description, "Changed")
+		is.Equal(found.OrganizationID, organization.OrganizationID)
+	})
+
+	t.Run("DeleteActivityByID", func(t *testing.T) {
+		organization := newOrganization()
+		activity := insertActivity(organization, "My Activity")
+
+		err := repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return activityRepository.DeleteActivityByIDAndUsername(ctx, organization.OrganizationID, activity.ID, organization.Username)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = activityRepository.FindActivityByID(ctx, activity.ID, organization.OrganizationID)
+		is.Equal(err, ErrActivityNotFound)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return activityRepository.DeleteActivityByID(ctx, organization.OrganizationID, activity.ID)
+			},
+		)
+		is.Equal(err, ErrActivityNotFound)
+	})
+}
@@ -2,7 +2,9 @@ package tracking
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -240,7 +242,7 @@ func (r *DbActivityRepository) ProjectReport(ctx context.Context, filter *Activi
 	}
 
 	sql := fmt.Sprintf(
-		`SELECT ag.project_id, projects.title as title, ag.duration_minutes_total FROM 
+		`SELECT ag.project_id, projects.title as title, projects.status as status, ag.duration_minutes_total FROM 
 		  (SELECT project_id, sum(duration_minutes_total) as duration_minutes_total  
 		   FROM activities_agg
 	       WHERE org_id = $1 AND $2 <= start_time AND start_time < $3 %s
@@ -263,10 +265,11 @@ func (r *DbActivityRepository) ProjectReport(ctx context.Context, filter *Activi
 		var (
 			projectID         uuid.UUID
 			projectTitle      string
+			projectStatus     string
 			durationInMinutes int
 		)
 
-		err = rows.Scan(&projectID, &projectTitle, &durationInMinutes)
+		err = rows.Scan(&projectID, &projectTitle, &projectStatus, &durationInMinutes)
 		if err != nil {
 			return nil, err
 		}
@@ -274,6 +277,7 @@ func (r *DbActivityRepository) ProjectReport(ctx context.Context, filter *Activi
 		activity := &ActivityProjectReportItem{
 			ProjectID:              projectID,
 			ProjectTitle:           projectTitle,
+			ProjectStatus:          projectStatus,
 			DurationInMinutesTotal: durationInMinutes,
 		}
 		activities = append(activities, activity)
@@ -282,90 +286,210 @@ func (r *DbActivityRepository) ProjectReport(ctx context.Context, filter *Activi
 	return activities, nil
 }
 
-func (r *DbActivityRepository) FindActivities(ctx context.Context, filter *ActivitiesFilter, pageParams *paged.PageParams) (*ActivitiesPaged, []*Project, error) {
-	params := []interface{}{filter.OrganizationID, filter.Start, filter.End, pageParams.Size, pageParams.Offset()}
+func (r *DbActivityRepository) UtilizationReportByUser(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityUtilizationReportItem, error) {
+	params := []interface{}{filter.OrganizationID, filter.Start, filter.End}
 	filterSql := ""
 
 	if filter.Username != "" {
 		params = append(params, filter.Username)
-		filterSql = " AND username = $6"
+		filterSql = " AND ag.username = $4"
 	}
 
-	sortBy := "start"
-	if filter.SortBy != "" {
-		sortBy = strings.ToLower(filter.SortBy)
+	// anonymized reports only contain the position of the user in an order which does not reveal the username
+	usernameSql := "ag.username"
+	orderSql := "ag.username"
+	if filter.AnonymizeUsers {
+		usernameSql = "(dense_rank() OVER (ORDER BY md5(ag.username)))::text"
+		orderSql = "md5(ag.username)"
 	}
 
-	sortOrder := "DESC"
-	if filter.SortOrder != "" {
-		sortOrder = strings.ToUpper(filter.SortOrder)
+	sql := fmt.Sprintf(
+		`SELECT %s as username, 
+		        sum(ag.duration_minutes_total) as duration_minutes_total,
+		        sum(CASE WHEN projects.billable THEN ag.duration_minutes_total ELSE 0 END) as billable_minutes_total
+		 FROM activities_agg ag
+		 INNER JOIN projects
+		 ON projects.project_id = ag.project_id
+	     WHERE ag.org_id = $1 AND $2 <= ag.start_time AND ag.start_time < $3 %s
+		 GROUP BY ag.username
+         ORDER BY %s asc`,
+		usernameSql,
+		filterSql,
+		orderSql,
+	)
+
+	rows, err := r.connPool.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reportItems []*ActivityUtilizationReportItem
+	for rows.Next() {
+		var (
+			username                  string
+			durationInMinutes         int
+			billableDurationInMinutes int
+		)
+
+		err = rows.Scan(&username, &durationInMinutes, &billableDurationInMinutes)
+		if err != nil {
+			return nil, err
+		}
+
+		if filter.AnonymizeUsers {
+			position, err := strconv.Atoi(username)
+			if err != nil {
+				return nil, err
+			}
+			username = AnonymousUsername(position)
+		}
+
+		reportItem := &ActivityUtilizationReportItem{
+			Username:                       username,
+			DurationInMinutesTotal:         durationInMinutes,
+			BillableDurationInMinutesTotal: billableDurationInMinutes,
+		}
+		reportItems = append(reportItems, reportItem)
+	}
+
+	return reportItems, nil
+}
+
+func (r *DbActivityRepository) UtilizationReportByWeek(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityUtilizationReportItem, error) {
+	params := []interface{}{filter.OrganizationID, filter.Start, filter.End}
+	filterSql := ""
+
+	if filter.Username != "" {
+		params = append(params, filter.Username)
+		filterSql = " AND ag.username = $4"
 	}
 
 	sql := fmt.Sprintf(
-		`SELECT a.*, projects.title as project FROM
-		   (SELECT activity_id as id, description, start_time as start, end_time as end, username, org_id, project_id
-			FROM activities 
-			WHERE org_id = $1 %s AND $2 <= start_time AND start_time < $3
-		   ) a
-         INNER JOIN projects
-	     ON projects.project_id = a.project_id
-		 ORDER by %s %s 
-	     LIMIT $4 OFFSET $5`,
+		`SELECT ag.year, ag.week, 
+		        sum(ag.duration_minutes_total) as duration_minutes_total,
+		        sum(CASE WHEN projects.billable THEN ag.duration_minutes_total ELSE 0 END) as billable_minutes_total
+		 FROM activities_agg ag
+		 INNER JOIN projects
+		 ON projects.project_id = ag.project_id
+	     WHERE ag.org_id = $1 AND $2 <= ag.start_time AND ag.start_time < $3 %s
+		 GROUP BY ag.year, ag.week
+         ORDER BY (ag.year, ag.week) asc`,
 		filterSql,
-		sortBy,
-		sortOrder,
 	)
 
 	rows, err := r.connPool.Query(ctx, sql, params...)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	var activities []*Activity
-	projectsById := make(map[uuid.UUID]*Project)
+	var reportItems []*ActivityUtilizationReportItem
 	for rows.Next() {
 		var (
-			id             string
-			description    pgtype.Varchar
-			startTime      time.Time
-			endTime        time.Time
-			username       string
-			organizationID string
-			projectID      string
-			projectTitle   string
+			year                      int
+			week                      int
+			durationInMinutes         int
+			billableDurationInMinutes int
 		)
 
-		err = rows.Scan(&id, &description, &startTime, &endTime, &username, &organizationID, &projectID, &projectTitle)
+		err = rows.Scan(&year, &week, &durationInMinutes, &billableDurationInMinutes)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
+		}
+
+		reportItem := &ActivityUtilizationReportItem{
+			Year:                           year,
+			Week:                           week,
+			DurationInMinutesTotal:         durationInMinutes,
+			BillableDurationInMinutesTotal: billableDurationInMinutes,
 		}
+		reportItems = append(reportItems, reportItem)
+	}
+
+	return reportItems, nil
+}
+
+func (r *DbActivityRepository) ProjectBurndownReport(ctx context.Context, organizationID, projectID uuid.UUID, aggregateBy string) ([]*ProjectBurndownItem, error) {
+	truncateBy := "day"
+	if aggregateBy == "week" {
+		truncateBy = "week"
+	}
+
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT date_trunc($3, start_time) as bucket, sum(duration_minutes_total) as duration_minutes_total
+		 FROM activities_agg
+		 WHERE org_id = $1 AND project_id = $2
+		 GROUP BY bucket
+		 ORDER BY bucket asc`,
+		organizationID, projectID, truncateBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		projectUUID := uuid.MustParse(projectID)
+	var reportItems []*ProjectBurndownItem
+	for rows.Next() {
+		var (
+			bucket            time.Time
+			durationInMinutes int
+		)
 
-		activity := &Activity{
-			ID:             uuid.MustParse(id),
-			Description:    description.String,
-			Start:          startTime,
-			End:            endTime,
-			Username:       username,
-			OrganizationID: uuid.MustParse(organizationID),
-			ProjectID:      projectUUID,
+		err = rows.Scan(&bucket, &durationInMinutes)
+		if err != nil {
+			return nil, err
 		}
-		activities = append(activities, activity)
 
-		if _, ok := projectsById[projectUUID]; !ok {
-			project := &Project{
-				ID:             projectUUID,
-				OrganizationID: uuid.MustParse(organizationID),
-				Title:          projectTitle,
-			}
-			projectsById[projectUUID] = project
+		reportItem := &ProjectBurndownItem{
+			Date:                   bucket,
+			DurationInMinutesTotal: durationInMinutes,
 		}
+		reportItems = append(reportItems, reportItem)
+	}
+
+	return reportItems, nil
+}
+
+func (r *DbActivityRepository) FindActivities(ctx context.Context, filter *ActivitiesFilter, pageParams *paged.PageParams) (*ActivitiesPaged, []*Project, error) {
+	params := []interface{}{filter.OrganizationID, filter.Start, filter.End, pageParams.Size, pageParams.Offset()}
+	filterSql := ""
 
+	if filter.Username != "" {
+		params = append(params, filter.Username)
+		filterSql = " AND username = $6"
 	}
 
-	projects := maps.Values(projectsById)
+	querySql, params := activityQuerySql(filter.Query, params)
+	filterSql += querySql
+
+	// users are joined only if embedded as the projects are needed anyways
+	userSelect := "NULL"
+	userJoin := ""
+	if filter.EmbedUsers {
+		userSelect = "users.name"
+		userJoin = "LEFT JOIN users ON users.username = a.username AND users.org_id = a.org_id"
+	}
+
+	sql := fmt.Sprintf(
+		`SELECT a.*, projects.title as project, projects.description as project_description, 
+		        projects.status, projects.billable, projects.budget_minutes, %s as user_name FROM
+		   (SELECT activity_id as id, description, start_time as start, end_time as end, username, org_id, project_id,
+		           location, latitude, longitude
+			FROM activities 
+			WHERE org_id = $1 %s AND $2 <= start_time AND start_time < $3
+		   ) a
+         INNER JOIN projects
+	     ON projects.project_id = a.project_id
+		 %s
+		 ORDER by %s 
+	     LIMIT $4 OFFSET $5`,
+		userSelect,
+		filterSql,
+		userJoin,
+		activitiesOrderBy(filter),
+	)
 
 	countParams := []interface{}{filter.OrganizationID, filter.Start, filter.End}
 	countFilter := ""
@@ -375,29 +499,203 @@ func (r *DbActivityRepository) FindActivities(ctx context.Context, filter *Activ
 		countFilter = " AND username = $4"
 	}
 
+	countQuerySql, countParams := activityQuerySql(filter.Query, countParams)
+	countFilter += countQuerySql
+
 	countSql := fmt.Sprintf(`
      	SELECT count(*) as total 
 	    FROM activities
 	    WHERE org_id = $1 %s AND $2 <= start_time AND start_time < $3`,
 		countFilter)
-	row := r.connPool.QueryRow(ctx, countSql, countParams...)
+
+	var activities []*Activity
 	var total int
-	err = row.Scan(&total)
+	projectsById := make(map[uuid.UUID]*Project)
+	usersByUsername := make(map[string]*ActivityUser)
+	err := shared.InSnapshot(ctx, r.connPool, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, sql, params...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				id                 string
+				description        pgtype.Varchar
+				startTime          time.Time
+				endTime            time.Time
+				username           string
+				organizationID     string
+				projectID          string
+				location           pgtype.Varchar
+				latitude           *float64
+				longitude          *float64
+				projectTitle       string
+				projectDescription pgtype.Varchar
+				projectStatus      string
+				projectBillable    bool
+				projectBudget      int
+				userName           pgtype.Varchar
+			)
+
+			err = rows.Scan(&id, &description, &startTime, &endTime, &username, &organizationID, &projectID,
+				&location, &latitude, &longitude, &projectTitle, &projectDescription, &projectStatus, &projectBillable, &projectBudget, &userName)
+			if err != nil {
+				return err
+			}
+
+			projectUUID := uuid.MustParse(projectID)
+
+			activity := &Activity{
+				ID:             uuid.MustParse(id),
+				Description:    description.String,
+				Start:          startTime,
+				End:            endTime,
+				Username:       username,
+				OrganizationID: uuid.MustParse(organizationID),
+				ProjectID:      projectUUID,
+				Location:       location.String,
+				Latitude:       latitude,
+				Longitude:      longitude,
+			}
+			activities = append(activities, activity)
+
+			if _, ok := projectsById[projectUUID]; !ok {
+				project := &Project{
+					ID:             projectUUID,
+					OrganizationID: uuid.MustParse(organizationID),
+					Title:          projectTitle,
+					Description:    projectDescription.String,
+					Status:         projectStatus,
+					Billable:       projectBillable,
+					BudgetMinutes:  projectBudget,
+				}
+				projectsById[projectUUID] = project
+			}
+
+			if _, ok := usersByUsername[username]; filter.EmbedUsers && !ok {
+				user := &ActivityUser{
+					Username: username,
+					Name:     userName.String,
+				}
+				usersByUsername[username] = user
+			}
+		}
+
+		row := tx.QueryRow(ctx, countSql, countParams...)
+		return row.Scan(&total)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
 
+	projects := maps.Values(projectsById)
+
 	actvtivitiesPaged := &ActivitiesPaged{
 		Activities: activities,
 		Page:       pageParams.PageOfTotal(total),
 	}
+	if filter.EmbedUsers {
+		actvtivitiesPaged.Users = maps.Values(usersByUsername)
+	}
 
 	return actvtivitiesPaged, projects, nil
 }
 
+// StreamActivities reads all activities matching the filter row by row without buffering them
+func (r *DbActivityRepository) StreamActivities(ctx context.Context, filter *ActivitiesFilter, consume func(activity *Activity, project *Project) error) error {
+	params := []interface{}{filter.OrganizationID, filter.Start, filter.End}
+	filterSql := ""
+
+	if filter.Username != "" {
+		params = append(params, filter.Username)
+		filterSql = " AND username = $4"
+	}
+
+	querySql, params := activityQuerySql(filter.Query, params)
+	filterSql += querySql
+
+	sql := fmt.Sprintf(
+		`SELECT a.*, projects.title as project FROM
+		   (SELECT activity_id as id, description, start_time as start, end_time as end, username, org_id, project_id
+			FROM activities 
+			WHERE org_id = $1 %s AND $2 <= start_time AND start_time < $3
+		   ) a
+         INNER JOIN projects
+	     ON projects.project_id = a.project_id
+		 ORDER by %s`,
+		filterSql,
+		activitiesOrderBy(filter),
+	)
+
+	// the export streams all activities with one statement, which may run longer than the statement timeout
+	return pgx.BeginTxFunc(ctx, r.connPool, pgx.TxOptions{AccessMode: pgx.ReadOnly}, func(tx pgx.Tx) error {
+		err := shared.WithoutStatementTimeout(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, sql, params...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		projectsById := make(map[uuid.UUID]*Project)
+		for rows.Next() {
+			var (
+				id             string
+				description    pgtype.Varchar
+				startTime      time.Time
+				endTime        time.Time
+				username       string
+				organizationID string
+				projectID      string
+				projectTitle   string
+			)
+
+			err = rows.Scan(&id, &description, &startTime, &endTime, &username, &organizationID, &projectID, &projectTitle)
+			if err != nil {
+				return err
+			}
+
+			projectUUID := uuid.MustParse(projectID)
+
+			activity := &Activity{
+				ID:             uuid.MustParse(id),
+				Description:    description.String,
+				Start:          startTime,
+				End:            endTime,
+				Username:       username,
+				OrganizationID: uuid.MustParse(organizationID),
+				ProjectID:      projectUUID,
+			}
+
+			project, ok := projectsById[projectUUID]
+			if !ok {
+				project = &Project{
+					ID:             projectUUID,
+					OrganizationID: uuid.MustParse(organizationID),
+					Title:          projectTitle,
+				}
+				projectsById[projectUUID] = project
+			}
+
+			err = consume(activity, project)
+			if err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+}
+
 func (r *DbActivityRepository) FindActivityByID(ctx context.Context, activityID, organizationID uuid.UUID) (*Activity, error) {
 	row := r.connPool.QueryRow(ctx,
-		`SELECT activity_id as id, description, start_time, end_time, username, org_id, project_id 
+		`SELECT activity_id as id, description, start_time, end_time, username, org_id, project_id, 
+		        location, latitude, longitude 
          FROM activities 
 	     WHERE activity_id = $1 AND org_id = $2`,
 		activityID, organizationID)
@@ -410,9 +708,12 @@ func (r *DbActivityRepository) FindActivityByID(ctx context.Context, activityID,
 		username    string
 		orgID       string
 		projectID   string
+		location    pgtype.Varchar
+		latitude    *float64
+		longitude   *float64
 	)
 
-	err := row.Scan(&id, &description, &startTime, &endTime, &username, &orgID, &projectID)
+	err := row.Scan(&id, &description, &startTime, &endTime, &username, &orgID, &projectID, &location, &latitude, &longitude)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrActivityNotFound
@@ -429,13 +730,18 @@ func (r *DbActivityRepository) FindActivityByID(ctx context.Context, activityID,
 		Username:       username,
 		OrganizationID: uuid.MustParse(orgID),
 		ProjectID:      uuid.MustParse(projectID),
+		Location:       location.String,
+		Latitude:       latitude,
+		Longitude:      longitude,
 	}
 
 	return activity, nil
 }
 
 func (r *DbActivityRepository) DeleteActivityByID(ctx context.Context, organizationID, activityID uuid.UUID) error {
-	row := r.connPool.QueryRow(ctx,
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	row := tx.QueryRow(ctx,
 		`DELETE 
          FROM activities 
 	     WHERE activity_id = $1 AND org_id = $2
@@ -491,11 +797,13 @@ func (r *DbActivityRepository) UpdateActivity(ctx context.Context, organizationI
 
 	row := tx.QueryRow(ctx,
 		`UPDATE activities 
-		 SET start_time = $3, end_time = $4, description = $5, project_id = $6 
+		 SET start_time = $3, end_time = $4, description = $5, project_id = $6, 
+		     location = $7, latitude = $8, longitude = $9 
 		 WHERE activity_id = $1 AND org_id = $2
 		 RETURNING activity_id`,
 		activity.ID, organizationID,
 		activity.Start, activity.End, activity.Description, activity.ProjectID,
+		nullableLocation(activity), activity.Latitude, activity.Longitude,
 	)
 
 	var id string
@@ -516,11 +824,13 @@ func (r *DbActivityRepository) UpdateActivityByUsername(ctx context.Context, org
 
 	row := tx.QueryRow(ctx,
 		`UPDATE activities 
-		 SET start_time = $4, end_time = $5, description = $6, project_id = $7 
+		 SET start_time = $4, end_time = $5, description = $6, project_id = $7, 
+		     location = $8, latitude = $9, longitude = $10 
 		 WHERE activity_id = $1 AND org_id = $2 AND username = $3
 		 RETURNING activity_id`,
 		activity.ID, organizationID, username,
 		activity.Start, activity.End, activity.Description, activity.ProjectID,
+		nullableLocation(activity), activity.Latitude, activity.Longitude,
 	)
 
 	var id string
@@ -542,9 +852,9 @@ func (r *DbActivityRepository) InsertActivity(ctx context.Context, activity *Act
 	_, err := tx.Exec(
 		ctx,
 		`INSERT INTO activities 
-		   (activity_id, start_time, end_time, description, project_id, org_id, username) 
+		   (activity_id, start_time, end_time, description, project_id, org_id, username, location, latitude, longitude) 
 		 VALUES 
-		   ($1, $2, $3, $4, $5, $6, $7)`,
+		   ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
 		activity.ID,
 		activity.Start,
 		activity.End,
@@ -552,6 +862,9 @@ func (r *DbActivityRepository) InsertActivity(ctx context.Context, activity *Act
 		activity.ProjectID,
 		activity.OrganizationID,
 		activity.Username,
+		nullableLocation(activity),
+		activity.Latitude,
+		activity.Longitude,
 	)
 	if err != nil {
 		return nil, err
@@ -559,3 +872,180 @@ func (r *DbActivityRepository) InsertActivity(ctx context.Context, activity *Act
 
 	return activity, nil
 }
+
+// InsertActivities inserts many activities at once using the copy protocol, so mass imports don't insert row by row
+func (r *DbActivityRepository) InsertActivities(ctx context.Context, activities []*Activity) (int, error) {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	count, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"activities"},
+		[]string{"activity_id", "start_time", "end_time", "description", "project_id", "org_id", "username", "location", "latitude", "longitude"},
+		pgx.CopyFromSlice(len(activities), func(i int) ([]any, error) {
+			activity := activities[i]
+			return []any{
+				activity.ID,
+				activity.Start,
+				activity.End,
+				activity.Description,
+				activity.ProjectID,
+				activity.OrganizationID,
+				activity.Username,
+				nullableLocation(activity),
+				activity.Latitude,
+				activity.Longitude,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// activitiesOrderBy maps the whitelisted sort field of the filter to the order by clause,
+// ties are ordered by start and id so that paging is stable
+func activitiesOrderBy(filter *ActivitiesFilter) string {
+	sortOrder := "DESC"
+	if strings.ToLower(filter.SortOrder) == SortOrderAsc {
+		sortOrder = "ASC"
+	}
+
+	sortColumn := ""
+	if IsValidActivitySortField(filter.SortBy) {
+		switch strings.ToLower(filter.SortBy) {
+		case "project":
+			sortColumn = "project"
+		case "end":
+			sortColumn = `a."end"`
+		case "duration":
+			sortColumn = `(a."end" - a.start)`
+		}
+	}
+
+	if sortColumn == "" {
+		return fmt.Sprintf("a.start %s, a.id ASC", sortOrder)
+	}
+	return fmt.Sprintf("%s %s, a.start DESC, a.id ASC", sortColumn, sortOrder)
+}
+
+// activityQuerySql maps the conditions of the query to sql, the values are added to the params
+func activityQuerySql(query *ActivityQuery, params []interface{}) (string, []interface{}) {
+	if query == nil {
+		return "", params
+	}
+
+	param := func(value interface{}) string {
+		params = append(params, value)
+		return fmt.Sprintf("$%d", len(params))
+	}
+
+	var querySql strings.Builder
+	for _, condition := range query.Conditions {
+		switch condition.Field {
+		case ActivityQueryFieldProject:
+			projectIDs := make([]string, len(condition.ProjectIDs))
+			for i, projectID := range condition.ProjectIDs {
+				projectIDs[i] = projectID.String()
+			}
+			fmt.Fprintf(&querySql, " AND %sproject_id = ANY(%s::uuid[])", sqlNegation(condition.Operator), param(projectIDs))
+		case ActivityQueryFieldUser:
+			fmt.Fprintf(&querySql, " AND %susername = ANY(%s::text[])", sqlNegation(condition.Operator), param(condition.Values))
+		case ActivityQueryFieldDuration:
+			fmt.Fprintf(&querySql, " AND EXTRACT(EPOCH FROM (end_time - start_time)) %s %s", sqlOperator(condition.Operator), param(condition.Duration.Seconds()))
+		case ActivityQueryFieldDescription:
+			if condition.Operator == "~" {
+				fmt.Fprintf(&querySql, " AND description ILIKE %s", param("%"+escapeLike(condition.Values[0])+"%"))
+			} else {
+				fmt.Fprintf(&querySql, " AND description = %s", param(condition.Values[0]))
+			}
+		case ActivityQueryFieldDate:
+			nextDay := condition.Date.AddDate(0, 0, 1)
+			switch condition.Operator {
+			case "=":
+				fmt.Fprintf(&querySql, " AND %s <= start_time AND start_time < %s", param(condition.Date), param(nextDay))
+			case ">":
+				fmt.Fprintf(&querySql, " AND %s <= start_time", param(nextDay))
+			case ">=":
+				fmt.Fprintf(&querySql, " AND %s <= start_time", param(condition.Date))
+			case "<":
+				fmt.Fprintf(&querySql, " AND start_time < %s", param(condition.Date))
+			case "<=":
+				fmt.Fprintf(&querySql, " AND start_time < %s", param(nextDay))
+			}
+		}
+	}
+
+	return querySql.String(), params
+}
+
+// sqlOperator maps the operator of the query to sql, operators are already checked by the parser
+func sqlOperator(operator string) string {
+	if operator == "!=" {
+		return "<>"
+	}
+	return operator
+}
+
+func sqlNegation(operator string) string {
+	if operator == "!=" {
+		return "NOT "
+	}
+	return ""
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// LocationReport reports the tracked time per location, activities without a location are reported as unspecified
+func (r *DbActivityRepository) LocationReport(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityLocationReportItem, error) {
+	params := []interface{}{filter.OrganizationID, filter.Start, filter.End, LocationUnspecified}
+	filterSql := ""
+
+	if filter.Username != "" {
+		params = append(params, filter.Username)
+		filterSql = " AND username = $5"
+	}
+
+	sql := fmt.Sprintf(
+		`SELECT COALESCE(location, $4) as location, sum(duration_minutes_total) as duration_minutes_total 
+		 FROM activities_agg
+	     WHERE org_id = $1 AND $2 <= start_time AND start_time < $3 %s
+		 GROUP BY COALESCE(location, $4)
+		 ORDER BY duration_minutes_total desc, location asc`,
+		filterSql,
+	)
+
+	rows, err := r.connPool.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reportItems []*ActivityLocationReportItem
+	for rows.Next() {
+		var (
+			location          string
+			durationInMinutes int
+		)
+
+		err = rows.Scan(&location, &durationInMinutes)
+		if err != nil {
+			return nil, err
+		}
+
+		reportItem := &ActivityLocationReportItem{
+			Location:               location,
+			DurationInMinutesTotal: durationInMinutes,
+		}
+		reportItems = append(reportItems, reportItem)
+	}
+
+	return reportItems, rows.Err()
+}
+
+func nullableLocation(activity *Activity) sql.NullString {
+	return sql.NullString{String: activity.Location, Valid: activity.Location != ""}
+}
@@ -110,6 +110,29 @@ func TestActivityRepository(t *testing.T) {
 		is.True(activityiesPage != nil)
 	})
 
+	t.Run("FindActivitiesByOrganizationId with embedded users", func(t *testing.T) {
+		filter := &ActivitiesFilter{
+			Start:          start,
+			End:            time.Now(),
+			EmbedUsers:     true,
+			OrganizationID: shared.OrganizationIDSample,
+		}
+		activityiesPage, projects, err := activityRepository.FindActivities(
+			context.Background(),
+			filter,
+			&paged.PageParams{
+				Page: 0,
+				Size: 50,
+			},
+		)
+
+		is.NoErr(err)
+		is.Equal(len(activityiesPage.Activities), 1)
+		is.Equal(len(projects), 1)
+		is.Equal(len(activityiesPage.Users), 1)
+		is.Equal(activityiesPage.Users[0].Name, "Ed Admin")
+	})
+
 	t.Run("InsertAndFindAndDeleteActivity", func(t *testing.T) {
 		start, _ := time.Parse(time.RFC3339, "2021-11-12T11:00:00.000Z")
 		end, _ := time.Parse(time.RFC3339, "2021-11-12T11:30:00.000Z")
@@ -139,7 +162,99 @@ func TestActivityRepository(t *testing.T) {
 		is.Equal(activtiy.ID, activityFound.ID)
 		is.Equal(activtiy.Description, activityFound.Description)
 
-		err = activityRepository.DeleteActivityByID(context.Background(), activtiy.OrganizationID, activtiy.ID)
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return activityRepository.DeleteActivityByID(ctx, activtiy.OrganizationID, activtiy.ID)
+			},
+		)
+		is.NoErr(err)
+	})
+
+	t.Run("InsertAndFindActivityWithLocation", func(t *testing.T) {
+		start, _ := time.Parse(time.RFC3339, "2021-11-12T12:00:00.000Z")
+		end, _ := time.Parse(time.RFC3339, "2021-11-12T12:30:00.000Z")
+		latitude, longitude := 52.52, 13.405
+
+		activtiy := &Activity{
+			ID:             uuid.New(),
+			ProjectID:      shared.ProjectIDSample,
+			OrganizationID: shared.OrganizationIDSample,
+			Start:          start,
+			End:            end,
+			Location:       LocationClientSite,
+			Latitude:       &latitude,
+			Longitude:      &longitude,
+		}
+
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := activityRepository.InsertActivity(ctx, activtiy)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		activityFound, err := activityRepository.FindActivityByID(context.Background(), activtiy.ID, shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(activityFound.Location, LocationClientSite)
+		is.Equal(*activityFound.Latitude, latitude)
+		is.Equal(*activityFound.Longitude, longitude)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return activityRepository.DeleteActivityByID(ctx, activtiy.OrganizationID, activtiy.ID)
+			},
+		)
+		is.NoErr(err)
+	})
+
+	t.Run("InsertActivitiesAndFind", func(t *testing.T) {
+		start, _ := time.Parse(time.RFC3339, "2021-11-13T11:00:00.000Z")
+		end, _ := time.Parse(time.RFC3339, "2021-11-13T11:30:00.000Z")
+
+		activities := make([]*Activity, 100)
+		for i := range activities {
+			activities[i] = &Activity{
+				ID:             uuid.New(),
+				ProjectID:      shared.ProjectIDSample,
+				OrganizationID: shared.OrganizationIDSample,
+				Start:          start,
+				End:            end,
+				Username:       "user1",
+			}
+		}
+
+		var count int
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				c, err := activityRepository.InsertActivities(ctx, activities)
+				count = c
+				return err
+			},
+		)
+		is.NoErr(err)
+		is.Equal(count, 100)
+
+		activityFound, err := activityRepository.FindActivityByID(context.Background(), activities[99].ID, shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(activities[99].ID, activityFound.ID)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				for _, activity := range activities {
+					err := activityRepository.DeleteActivityByID(ctx, activity.OrganizationID, activity.ID)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		)
 		is.NoErr(err)
 	})
 
@@ -188,10 +303,15 @@ func TestActivityRepository(t *testing.T) {
 	})
 
 	t.Run("DeleteNonExistingActivityByID", func(t *testing.T) {
-		err := activityRepository.DeleteActivityByID(
+		err := repositoryTxer.InTx(
 			context.Background(),
-			shared.OrganizationIDSample,
-			uuid.MustParse("f8d8a2ac-3f3e-11ec-9bbc-0242ac130002"),
+			func(ctx context.Context) error {
+				return activityRepository.DeleteActivityByID(
+					ctx,
+					shared.OrganizationIDSample,
+					uuid.MustParse("f8d8a2ac-3f3e-11ec-9bbc-0242ac130002"),
+				)
+			},
 		)
 
 		is.True(errors.Is(err, ErrActivityNotFound))
@@ -452,6 +572,88 @@ func TestActivityRepositoryReports(t *testing.T) {
 		is.Equal(len(reportItems), 1)
 		is.Equal(300, reportItems[0].DurationInMinutesTotal)
 	})
+
+	t.Run("LocationReport", func(t *testing.T) {
+		// Arrange
+
+		// Act
+		reportItems, err := activityRepository.LocationReport(
+			context.Background(),
+			filter,
+		)
+
+		// Assert
+		is.NoErr(err)
+		is.Equal(len(reportItems), 1)
+		is.Equal(LocationUnspecified, reportItems[0].Location)
+		is.Equal(300, reportItems[0].DurationInMinutesTotal)
+	})
+
+	t.Run("UtilizationReportByUser", func(t *testing.T) {
+		// Arrange
+
+		// Act
+		reportItems, err := activityRepository.UtilizationReportByUser(
+			context.Background(),
+			filter,
+		)
+
+		// Assert
+		is.NoErr(err)
+		is.Equal(len(reportItems), 1)
+		is.Equal(300, reportItems[0].DurationInMinutesTotal)
+		is.Equal(0, reportItems[0].BillableDurationInMinutesTotal)
+	})
+
+	t.Run("UtilizationReportByUser anonymized", func(t *testing.T) {
+		// Arrange
+		anonymizedFilter := *filter
+		anonymizedFilter.AnonymizeUsers = true
+
+		// Act
+		reportItems, err := activityRepository.UtilizationReportByUser(
+			context.Background(),
+			&anonymizedFilter,
+		)
+
+		// Assert
+		is.NoErr(err)
+		is.Equal(len(reportItems), 1)
+		is.Equal("User A", reportItems[0].Username)
+		is.Equal(300, reportItems[0].DurationInMinutesTotal)
+	})
+
+	t.Run("UtilizationReportByWeek", func(t *testing.T) {
+		// Arrange
+
+		// Act
+		reportItems, err := activityRepository.UtilizationReportByWeek(
+			context.Background(),
+			filter,
+		)
+
+		// Assert
+		is.NoErr(err)
+		is.Equal(len(reportItems), 4)
+		is.Equal(120, reportItems[0].DurationInMinutesTotal)
+	})
+
+	t.Run("ProjectBurndownReport", func(t *testing.T) {
+		// Arrange
+
+		// Act
+		reportItems, err := activityRepository.ProjectBurndownReport(
+			context.Background(),
+			shared.OrganizationIDSample,
+			shared.ProjectIDSample,
+			"day",
+		)
+
+		// Assert
+		is.NoErr(err)
+		is.Equal(len(reportItems), 4)
+		is.Equal(120, reportItems[0].DurationInMinutesTotal)
+	})
 }
 
 func insertSampleActivitiesForReports(ctx context.Context, connPool *pgxpool.Pool) error {
@@ -536,3 +738,25 @@ func insertSampleActivitiesForReports(ctx context.Context, connPool *pgxpool.Poo
 	}
 	return nil
 }
+
+func TestActivitiesOrderBy(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(activitiesOrderBy(&ActivitiesFilter{}), "a.start DESC, a.id ASC")
+	is.Equal(activitiesOrderBy(&ActivitiesFilter{SortBy: "duration", SortOrder: "asc"}), `(a."end" - a.start) ASC, a.start DESC, a.id ASC`)
+	is.Equal(activitiesOrderBy(&ActivitiesFilter{SortBy: "project", SortOrder: "desc"}), "project DESC, a.start DESC, a.id ASC")
+	is.Equal(activitiesOrderBy(&ActivitiesFilter{SortBy: "start; DROP TABLE activities"}), "a.start DESC, a.id ASC")
+}
+
+func TestActivityQuerySql(t *testing.T) {
+	is := is.New(t)
+
+	query, err := ParseActivityQuery(`user!=user1 and duration>=1h30m and description~"100%"`)
+	is.NoErr(err)
+
+	querySql, params := activityQuerySql(query, []interface{}{"org"})
+	is.Equal(querySql, " AND NOT username = ANY($2::text[]) AND EXTRACT(EPOCH FROM (end_time - start_time)) >= $3 AND description ILIKE $4")
+	is.Equal(len(params), 4)
+	is.Equal(params[2], float64(5400))
+	is.Equal(params[3], `%100\%%`)
+}
@@ -0,0 +1,156 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	EventTypeActivityCreated = "activity.created"
+	EventTypeActivityUpdated = "activity.updated"
+	EventTypeActivityDeleted = "activity.deleted"
+)
+
+// activityEventData is the data of the events of an activity
+type activityEventData struct {
+	ID          string   `json:"id"`
+	Start       string   `json:"start"`
+	End         string   `json:"end"`
+	Description string   `json:"description"`
+	ProjectID   string   `json:"projectId"`
+	Username    string   `json:"username"`
+	Location    string   `json:"location,omitempty"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+}
+
+// EventPublishingActivityRepository publishes every change of an activity as event via the outbox,
+// within the transaction of the change
+type EventPublishingActivityRepository struct {
+	ActivityRepository
+	outbox shared.Outbox
+}
+
+var _ ActivityRepository = (*EventPublishingActivityRepository)(nil)
+
+// NewEventPublishingActivityRepository creates a new activity repository which publishes changes as events
+func NewEventPublishingActivityRepository(activityRepository ActivityRepository, outbox shared.Outbox) *EventPublishingActivityRepository {
+	return &EventPublishingActivityRepository{
+		ActivityRepository: activityRepository,
+		outbox:             outbox,
+	}
+}
+
+func (r *EventPublishingActivityRepository) InsertActivity(ctx context.Context, activity *Activity) (*Activity, error) {
+	inserted, err := r.ActivityRepository.InsertActivity(ctx, activity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.publishEvent(ctx, EventTypeActivityCreated, inserted)
+	if err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+func (r *EventPublishingActivityRepository) InsertActivities(ctx context.Context, activities []*Activity) (int, error) {
+	count, err := r.ActivityRepository.InsertActivities(ctx, activities)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, activity := range activities {
+		err := r.publishEvent(ctx, EventTypeActivityCreated, activity)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+func (r *EventPublishingActivityRepository) UpdateActivity(ctx context.Context, organizationID uuid.UUID, activity *Activity) (*Activity, error) {
+	updated, err := r.ActivityRepository.UpdateActivity(ctx, organizationID, activity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.publishEvent(ctx, EventTypeActivityUpdated, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *EventPublishingActivityRepository) UpdateActivityByUsername(ctx context.Context, organizationID uuid.UUID, activity *Activity, username string) (*Activity, error) {
+	updated, err := r.ActivityRepository.UpdateActivityByUsername(ctx, organizationID, activity, username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.publishEvent(ctx, EventTypeActivityUpdated, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *EventPublishingActivityRepository) DeleteActivityByID(ctx context.Context, organizationID, activityID uuid.UUID) error {
+	deleted, err := r.ActivityRepository.FindActivityByID(ctx, activityID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	err = r.ActivityRepository.DeleteActivityByID(ctx, organizationID, activityID)
+	if err != nil {
+		return err
+	}
+
+	return r.publishEvent(ctx, EventTypeActivityDeleted, deleted)
+}
+
+func (r *EventPublishingActivityRepository) DeleteActivityByIDAndUsername(ctx context.Context, organizationID, activityID uuid.UUID, username string) error {
+	deleted, err := r.ActivityRepository.FindActivityByID(ctx, activityID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	err = r.ActivityRepository.DeleteActivityByIDAndUsername(ctx, organizationID, activityID, username)
+	if err != nil {
+		return err
+	}
+
+	return r.publishEvent(ctx, EventTypeActivityDeleted, deleted)
+}
+
+func (r *EventPublishingActivityRepository) publishEvent(ctx context.Context, eventType string, activity *Activity) error {
+	data := &activityEventData{
+		ID:          activity.ID.String(),
+		Start:       activity.Start.UTC().Format(time.RFC3339),
+		End:         activity.End.UTC().Format(time.RFC3339),
+		Description: activity.Description,
+		ProjectID:   activity.ProjectID.String(),
+		Username:    activity.Username,
+		Location:    activity.Location,
+		Latitude:    activity.Latitude,
+		Longitude:   activity.Longitude,
+	}
+
+	event, err := shared.NewCloudEvent(
+		activity.OrganizationID,
+		eventType,
+		fmt.Sprintf("activities/%s", activity.ID),
+		data,
+		auditActorOf(ctx, activity.OrganizationID),
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.outbox.PublishEvent(ctx, event)
+}
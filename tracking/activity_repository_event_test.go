@@ -0,0 +1,81 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestEventPublishingActivityRepository(t *testing.T) {
+	is := is.New(t)
+
+	outbox := shared.NewInMemOutbox(shared.NewInMemMailResource())
+	activityRepository := NewEventPublishingActivityRepository(NewInMemActivityRepository(), outbox)
+
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin"}
+	ctx := context.WithValue(context.Background(), shared.ContextKeyPrincipal, principal)
+
+	activityID := uuid.New()
+	start := time.Date(2021, 11, 5, 10, 0, 0, 0, time.UTC)
+	activity := &Activity{
+		ID:             activityID,
+		Start:          start,
+		End:            start.Add(time.Hour),
+		Description:    "Meeting",
+		ProjectID:      shared.ProjectIDSample,
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}
+	_, err := activityRepository.InsertActivity(ctx, activity)
+	is.NoErr(err)
+
+	updated := *activity
+	updated.End = start.Add(2 * time.Hour)
+	_, err = activityRepository.UpdateActivity(ctx, shared.OrganizationIDSample, &updated)
+	is.NoErr(err)
+
+	err = activityRepository.DeleteActivityByID(context.Background(), shared.OrganizationIDSample, activityID)
+	is.NoErr(err)
+
+	is.Equal(len(outbox.Events), 3)
+
+	is.Equal(outbox.Events[0].Type, EventTypeActivityCreated)
+	is.Equal(outbox.Events[0].Subject, "activities/"+activityID.String())
+	is.Equal(outbox.Events[0].OrganizationID, shared.OrganizationIDSample.String())
+	is.Equal(outbox.Events[0].Actor, "admin")
+
+	data := &activityEventData{}
+	err = json.Unmarshal(outbox.Events[1].Data, data)
+	is.NoErr(err)
+	is.Equal(outbox.Events[1].Type, EventTypeActivityUpdated)
+	is.Equal(data.End, "2021-11-05T12:00:00Z")
+	is.Equal(data.Username, "user1")
+
+	is.Equal(outbox.Events[2].Type, EventTypeActivityDeleted)
+	is.Equal(outbox.Events[2].Actor, auditActorSystem)
+}
+
+func TestEventPublishingActivityRepositoryInsertActivities(t *testing.T) {
+	is := is.New(t)
+
+	outbox := shared.NewInMemOutbox(shared.NewInMemMailResource())
+	activityRepository := NewEventPublishingActivityRepository(NewInMemActivityRepository(), outbox)
+
+	start := time.Date(2021, 11, 5, 10, 0, 0, 0, time.UTC)
+	activities := []*Activity{
+		{ID: uuid.New(), Start: start, End: start.Add(time.Hour), ProjectID: shared.ProjectIDSample, OrganizationID: shared.OrganizationIDSample, Username: "user1"},
+		{ID: uuid.New(), Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), ProjectID: shared.ProjectIDSample, OrganizationID: shared.OrganizationIDSample, Username: "user1"},
+	}
+	count, err := activityRepository.InsertActivities(context.Background(), activities)
+	is.NoErr(err)
+	is.Equal(count, 2)
+
+	is.Equal(len(outbox.Events), 2)
+	is.Equal(outbox.Events[1].Type, EventTypeActivityCreated)
+	is.Equal(outbox.Events[1].Subject, "activities/"+activities[1].ID.String())
+}
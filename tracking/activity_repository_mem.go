@@ -7,6 +7,7 @@ import (
 	"github.com/baralga/shared/paged"
 	time_utils "github.com/baralga/tracking/time"
 	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
 )
 
 type InMemActivityRepository struct {
@@ -91,6 +92,7 @@ func (r *InMemActivityRepository) ProjectReport(ctx context.Context, filter *Act
 		reportItem := &ActivityProjectReportItem{
 			ProjectID:              a.ProjectID,
 			ProjectTitle:           "My Project",
+			ProjectStatus:          ProjectStatusActive,
 			DurationInMinutesTotal: 60,
 		}
 		reportItems = append(reportItems, reportItem)
@@ -98,16 +100,91 @@ func (r *InMemActivityRepository) ProjectReport(ctx context.Context, filter *Act
 	return reportItems, nil
 }
 
+func (r *InMemActivityRepository) UtilizationReportByUser(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityUtilizationReportItem, error) {
+	var reportItems []*ActivityUtilizationReportItem
+	reportItemsByUsername := make(map[string]*ActivityUtilizationReportItem)
+	for _, a := range r.activities {
+		reportItem, ok := reportItemsByUsername[a.Username]
+		if !ok {
+			reportItem = &ActivityUtilizationReportItem{
+				Username: a.Username,
+			}
+			reportItemsByUsername[a.Username] = reportItem
+			reportItems = append(reportItems, reportItem)
+		}
+		reportItem.DurationInMinutesTotal += a.DurationMinutesTotal()
+		reportItem.BillableDurationInMinutesTotal += a.DurationMinutesTotal()
+	}
+
+	if filter.AnonymizeUsers {
+		for i, reportItem := range reportItems {
+			reportItem.Username = AnonymousUsername(i + 1)
+		}
+	}
+	return reportItems, nil
+}
+
+func (r *InMemActivityRepository) UtilizationReportByWeek(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityUtilizationReportItem, error) {
+	var reportItems []*ActivityUtilizationReportItem
+	for _, a := range r.activities {
+		y, w := a.Start.ISOWeek()
+		reportItem := &ActivityUtilizationReportItem{
+			Year:                           y,
+			Week:                           w,
+			DurationInMinutesTotal:         a.DurationMinutesTotal(),
+			BillableDurationInMinutesTotal: a.DurationMinutesTotal(),
+		}
+		reportItems = append(reportItems, reportItem)
+	}
+	return reportItems, nil
+}
+
+func (r *InMemActivityRepository) ProjectBurndownReport(ctx context.Context, organizationID, projectID uuid.UUID, aggregateBy string) ([]*ProjectBurndownItem, error) {
+	var reportItems []*ProjectBurndownItem
+	for _, a := range r.activities {
+		if a.ProjectID != projectID {
+			continue
+		}
+		reportItem := &ProjectBurndownItem{
+			Date:                   truncateToBucket(a.Start, aggregateBy),
+			DurationInMinutesTotal: a.DurationMinutesTotal(),
+		}
+		reportItems = append(reportItems, reportItem)
+	}
+	return reportItems, nil
+}
+
 func (r *InMemActivityRepository) FindActivities(ctx context.Context, filter *ActivitiesFilter, pageParams *paged.PageParams) (*ActivitiesPaged, []*Project, error) {
+	activities := r.activities
+	if filter.Query != nil {
+		activities = nil
+		for _, a := range r.activities {
+			if filter.Query.Matches(a) {
+				activities = append(activities, a)
+			}
+		}
+	}
+
 	activitiesPage := &ActivitiesPaged{
-		Activities: r.activities,
+		Activities: activities,
 		Page: &paged.Page{
-			Size:          len(r.activities),
+			Size:          len(activities),
 			Number:        0,
-			TotalElements: len(r.activities),
+			TotalElements: len(activities),
 			TotalPages:    1,
 		},
 	}
+	if filter.EmbedUsers {
+		usersByUsername := make(map[string]*ActivityUser)
+		for _, a := range activities {
+			usersByUsername[a.Username] = &ActivityUser{
+				Username: a.Username,
+				Name:     a.Username,
+			}
+		}
+		activitiesPage.Users = maps.Values(usersByUsername)
+	}
+
 	projects := []*Project{
 		{
 			ID:             shared.ProjectIDSample,
@@ -119,9 +196,28 @@ func (r *InMemActivityRepository) FindActivities(ctx context.Context, filter *Ac
 	return activitiesPage, projects, nil
 }
 
+func (r *InMemActivityRepository) StreamActivities(ctx context.Context, filter *ActivitiesFilter, consume func(activity *Activity, project *Project) error) error {
+	project := &Project{
+		ID:             shared.ProjectIDSample,
+		Title:          "My Project",
+		OrganizationID: shared.OrganizationIDSample,
+	}
+
+	for _, a := range r.activities {
+		if filter.Query != nil && !filter.Query.Matches(a) {
+			continue
+		}
+		err := consume(a, project)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *InMemActivityRepository) FindActivityByID(ctx context.Context, activityID, organizationID uuid.UUID) (*Activity, error) {
 	for _, a := range r.activities {
-		if a.ID == activityID {
+		if a.ID == activityID && a.OrganizationID == organizationID {
 			return a, nil
 		}
 	}
@@ -133,9 +229,14 @@ func (r *InMemActivityRepository) InsertActivity(ctx context.Context, activity *
 	return activity, nil
 }
 
+func (r *InMemActivityRepository) InsertActivities(ctx context.Context, activities []*Activity) (int, error) {
+	r.activities = append(r.activities, activities...)
+	return len(activities), nil
+}
+
 func (r *InMemActivityRepository) DeleteActivityByID(ctx context.Context, organizationID, activityID uuid.UUID) error {
 	for i, a := range r.activities {
-		if a.ID == activityID {
+		if a.ID == activityID && a.OrganizationID == organizationID {
 			r.activities = append(r.activities[:i], r.activities[i+1:]...)
 			return nil
 		}
@@ -145,7 +246,7 @@ func (r *InMemActivityRepository) DeleteActivityByID(ctx context.Context, organi
 
 func (r *InMemActivityRepository) DeleteActivityByIDAndUsername(ctx context.Context, organizationID, activityID uuid.UUID, username string) error {
 	for i, a := range r.activities {
-		if a.ID == activityID && a.Username == username {
+		if a.ID == activityID && a.OrganizationID == organizationID && a.Username == username {
 			r.activities = append(r.activities[:i], r.activities[i+1:]...)
 			return nil
 		}
@@ -155,8 +256,10 @@ func (r *InMemActivityRepository) DeleteActivityByIDAndUsername(ctx context.Cont
 
 func (r *InMemActivityRepository) UpdateActivity(ctx context.Context, organizationID uuid.UUID, activity *Activity) (*Activity, error) {
 	for i, a := range r.activities {
-		if a.ID == activity.ID {
-			r.activities[i] = activity
+		if a.ID == activity.ID && a.OrganizationID == organizationID {
+			updated := *activity
+			updated.OrganizationID = a.OrganizationID
+			r.activities[i] = &updated
 			return activity, nil
 		}
 	}
@@ -165,10 +268,33 @@ func (r *InMemActivityRepository) UpdateActivity(ctx context.Context, organizati
 
 func (r *InMemActivityRepository) UpdateActivityByUsername(ctx context.Context, organizationID uuid.UUID, activity *Activity, username string) (*Activity, error) {
 	for i, a := range r.activities {
-		if a.ID == activity.ID && a.Username == username {
-			r.activities[i] = activity
+		if a.ID == activity.ID && a.OrganizationID == organizationID && a.Username == username {
+			updated := *activity
+			updated.OrganizationID = a.OrganizationID
+			r.activities[i] = &updated
 			return activity, nil
 		}
 	}
 	return nil, ErrActivityNotFound
 }
+
+func (r *InMemActivityRepository) LocationReport(ctx context.Context, filter *ActivitiesFilter) ([]*ActivityLocationReportItem, error) {
+	var reportItems []*ActivityLocationReportItem
+	reportItemsByLocation := make(map[string]*ActivityLocationReportItem)
+	for _, a := range r.activities {
+		location := a.Location
+		if location == "" {
+			location = LocationUnspecified
+		}
+		reportItem, ok := reportItemsByLocation[location]
+		if !ok {
+			reportItem = &ActivityLocationReportItem{
+				Location: location,
+			}
+			reportItemsByLocation[location] = reportItem
+			reportItems = append(reportItems, reportItem)
+		}
+		reportItem.DurationInMinutesTotal += a.DurationMinutesTotal()
+	}
+	return reportItems, nil
+}
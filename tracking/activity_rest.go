@@ -1,8 +1,9 @@
 package tracking
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -11,10 +12,10 @@ import (
 
 	"github.com/baralga/shared"
 	"github.com/baralga/shared/hal"
+	"github.com/baralga/shared/jsonapi"
 	"github.com/baralga/shared/paged"
 	time_utils "github.com/baralga/tracking/time"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/snabb/isoweek"
@@ -23,13 +24,21 @@ import (
 
 type activitiesModel struct {
 	*EmbeddedActivities `json:"_embedded"`
-	Links               *hal.Links `json:"_links"`
+	Page                *paged.Page `json:"page"`
+	Links               *hal.Links  `json:"_links"`
 }
 
 // EmbeddedActivities contains embedded activities and projects
 type EmbeddedActivities struct {
-	ActivityModels []*activityModel `json:"activities"`
-	ProjectModels  []*projectModel  `json:"projects"`
+	ActivityModels []*activityModel     `json:"activities"`
+	ProjectModels  []*projectModel      `json:"projects"`
+	UserModels     []*activityUserModel `json:"users,omitempty"`
+}
+
+// activityUserModel is a user embedded with the activities
+type activityUserModel struct {
+	Username string `json:"username"`
+	Name     string `json:"name"`
 }
 
 type activityModel struct {
@@ -37,6 +46,9 @@ type activityModel struct {
 	Start       string         `json:"start" validate:"required"`
 	End         string         `json:"end" validate:"required"`
 	Description string         `json:"description" validate:"max=500"`
+	Location    string         `json:"location,omitempty" validate:"omitempty,oneof=office home client-site"`
+	Latitude    *float64       `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	Longitude   *float64       `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
 	Duration    *durationModel `json:"duration"`
 	Links       *hal.Links     `json:"_links"`
 }
@@ -51,14 +63,18 @@ type durationModel struct {
 type ActivityRestHandlers struct {
 	config             *shared.Config
 	actitivityService  *ActitivityService
+	undoService        *UndoService
 	activityRepository ActivityRepository
+	projectRepository  ProjectRepository
 }
 
-func NewActivityRestHandlers(config *shared.Config, actitivityService *ActitivityService, activityRepository ActivityRepository) *ActivityRestHandlers {
+func NewActivityRestHandlers(config *shared.Config, actitivityService *ActitivityService, undoService *UndoService, activityRepository ActivityRepository, projectRepository ProjectRepository) *ActivityRestHandlers {
 	return &ActivityRestHandlers{
 		config:             config,
 		actitivityService:  actitivityService,
+		undoService:        undoService,
 		activityRepository: activityRepository,
+		projectRepository:  projectRepository,
 	}
 }
 
@@ -85,7 +101,18 @@ func (a *ActivityRestHandlers) HandleGetActivities() http.HandlerFunc {
 
 		filter, err := filterFromQueryParams(r.URL.Query())
 		if err != nil {
-			shared.RenderProblemJSON(w, isProduction, errors.New("invalid query params"))
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		if r.URL.Query().Get("contentType") == "text/csv" || r.Header.Get("Content-Type") == "text/csv" {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"Activities_%v.csv\"", filter.String()))
+			err := actitivityService.StreamAsCSV(r.Context(), principal, filter, w)
+			if err != nil {
+				// response is already streaming, so the export can only be aborted
+				log.Printf("could not stream activities as csv: %v", err)
+			}
 			return
 		}
 
@@ -95,19 +122,16 @@ func (a *ActivityRestHandlers) HandleGetActivities() http.HandlerFunc {
 			return
 		}
 
-		if r.URL.Query().Get("contentType") == "text/csv" || r.Header.Get("Content-Type") == "text/csv" {
-			w.Header().Set("Content-Type", "text/csv")
-			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"Activities_%v.csv\"", filter.String()))
-			err := actitivityService.WriteAsCSV(activitiesPage.Activities, projects, w)
+		if r.URL.Query().Get("contentType") == "application/vnd.ms-excel" || r.Header.Get("Content-Type") == "application/vnd.ms-excel" {
+			links, err := actitivityService.ReadLinksOfActivities(r.Context(), principal, filter)
 			if err != nil {
 				shared.RenderProblemJSON(w, isProduction, err)
 				return
 			}
-			return
-		} else if r.URL.Query().Get("contentType") == "application/vnd.ms-excel" || r.Header.Get("Content-Type") == "application/vnd.ms-excel" {
+
 			w.Header().Set("Content-Type", "!!")
 			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"Activities_%v.xlsx\"", filter.String()))
-			err := actitivityService.WriteAsExcel(activitiesPage.Activities, projects, w)
+			err = actitivityService.WriteAsExcel(activitiesPage.Activities, projects, links, w)
 			if err != nil {
 				shared.RenderProblemJSON(w, isProduction, err)
 				return
@@ -115,61 +139,114 @@ func (a *ActivityRestHandlers) HandleGetActivities() http.HandlerFunc {
 			return
 		}
 
+		if !filter.Embeds(ActivityEmbedProject) {
+			projects = nil
+		}
+
+		if jsonapi.Accepts(r) {
+			renderActivitiesDocument(w, r, activitiesPage, projects)
+			return
+		}
+
+		var projectModels []*projectModel
+		if projects != nil {
+			projectModels = mapToProjectModels(principal, projects)
+		}
+		userModels := mapToActivityUserModels(activitiesPage.Users)
+
+		if shared.APIVersionOf(r) == shared.APIVersion2 {
+			activitiesModelV2 := &activitiesModelV2{
+				EmbeddedActivitiesV2: &EmbeddedActivitiesV2{
+					ProjectModels:  projectModels,
+					UserModels:     userModels,
+					ActivityModels: mapToActivityModelsV2(activitiesPage.Activities),
+				},
+				Page: activitiesPage.Page,
+				Links: hal.NewLinks(
+					append(
+						pageLinks(r, activitiesPage.Page),
+						hal.NewSelfLink(r.RequestURI),
+						hal.NewLink("create", "/api/v2/activities"),
+					)...,
+				),
+			}
+
+			shared.RenderSparseJSON(w, activitiesModelV2, "activities", shared.FieldsOf(r))
+			return
+		}
+
 		activityModels := mapToActivityModels(activitiesPage.Activities)
-		projectModels := mapToProjectModels(principal, projects)
 
 		activitiesModel := &activitiesModel{
 			EmbeddedActivities: &EmbeddedActivities{
 				ProjectModels:  projectModels,
+				UserModels:     userModels,
 				ActivityModels: activityModels,
 			},
+			Page: activitiesPage.Page,
 			Links: hal.NewLinks(
-				hal.NewSelfLink(r.RequestURI),
-				hal.NewLink("create", "/api/activities"),
+				append(
+					pageLinks(r, activitiesPage.Page),
+					hal.NewSelfLink(r.RequestURI),
+					hal.NewLink("create", "/api/activities"),
+				)...,
 			),
 		}
 
-		shared.RenderJSON(w, activitiesModel)
+		shared.RenderSparseJSON(w, activitiesModel, "activities", shared.FieldsOf(r))
 	}
 }
 
 // HandleGetActivities creates an activity
 func (a *ActivityRestHandlers) HandleCreateActivity() http.HandlerFunc {
 	isProduction := a.config.IsProduction()
-	validator := validator.New()
+	validator := shared.NewValidator()
 	actitivityService := a.actitivityService
+	projectRepository := a.projectRepository
 	return func(w http.ResponseWriter, r *http.Request) {
-		var activityModel activityModel
-		err := json.NewDecoder(r.Body).Decode(&activityModel)
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityModel, err := decodeActivityModel(r, validator, nil)
 		if err != nil {
-			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			shared.RenderValidationProblemJSON(w, "activity not valid", err)
 			return
 		}
 
-		err = validator.Struct(activityModel)
+		err = resolveProjectAliasOf(r.Context(), projectRepository, principal.OrganizationID, activityModel)
 		if err != nil {
-			http.Error(w, problem.New(problem.Title("activity not valid")).JSONString(), http.StatusBadRequest)
+			shared.RenderProblemJSON(w, isProduction, err)
 			return
 		}
 
-		activityToCreate, err := mapToActivity(&activityModel)
+		activityToCreate, err := mapToActivity(activityModel)
 		if err != nil {
-			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			shared.RenderValidationProblemJSON(w, "activity not valid", err)
 			return
 		}
 
-		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+		// near duplicates are created with a warning, clients opt in to reject them instead
+		rejectDuplicate := r.URL.Query().Get("rejectDuplicate") == "true"
 
-		activity, err := actitivityService.CreateActivity(r.Context(), principal, activityToCreate)
+		activity, err := actitivityService.CreateActivity(r.Context(), principal, activityToCreate, !rejectDuplicate)
+		var nearDuplicateError *ActivityNearDuplicateError
+		if errors.As(err, &nearDuplicateError) {
+			renderNearDuplicateProblemJSON(w, nearDuplicateError)
+			return
+		}
 		if err != nil {
 			shared.RenderProblemJSON(w, isProduction, err)
 			return
 		}
 
-		activityModelCreated := mapToActivityModel(activity)
+		duplicates, err := actitivityService.ReadNearDuplicates(r.Context(), principal, activity)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+		setNearDuplicateWarning(w, duplicates)
 
 		w.WriteHeader(http.StatusCreated)
-		shared.RenderJSON(w, activityModelCreated)
+		renderActivityModel(w, r, activity)
 	}
 }
 
@@ -188,24 +265,19 @@ func (a *ActivityRestHandlers) HandleGetActivity() http.HandlerFunc {
 		}
 
 		activity, err := activityRepository.FindActivityByID(r.Context(), activityID, principal.OrganizationID)
-		if errors.Is(err, ErrActivityNotFound) {
-			http.Error(w, problem.New(problem.Title("activity not found")).JSONString(), http.StatusNotFound)
-			return
-		}
 		if err != nil {
 			shared.RenderProblemJSON(w, isProduction, err)
 			return
 		}
 
-		activityModel := mapToActivityModel(activity)
-		shared.RenderJSON(w, activityModel)
+		renderActivityModel(w, r, activity)
 	}
 }
 
-// HandleDeleteActivity deletes an activity
+// HandleDeleteActivity deletes an activity and renders the token to undo the deletion
 func (a *ActivityRestHandlers) HandleDeleteActivity() http.HandlerFunc {
 	isProduction := a.config.IsProduction()
-	actitivityService := a.actitivityService
+	undoService := a.undoService
 	return func(w http.ResponseWriter, r *http.Request) {
 		activityIDParam := chi.URLParam(r, "activity-id")
 		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
@@ -216,95 +288,163 @@ func (a *ActivityRestHandlers) HandleDeleteActivity() http.HandlerFunc {
 			return
 		}
 
-		err = actitivityService.DeleteActivityByID(r.Context(), principal, activityID)
-		if errors.Is(err, ErrActivityNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
+		deletedActivity, err := undoService.DeleteActivityByID(r.Context(), principal, activityID, time.Now())
 		if err != nil {
 			shared.RenderProblemJSON(w, isProduction, err)
 			return
 		}
 
 		w.Header().Set("HX-Trigger", "baralga__activities-changed")
+		shared.RenderJSON(w, mapToUndoModel(deletedActivity))
 	}
 }
 
-// HandleUpdateActivity updates an activity
+// HandleUpdateActivity updates an activity, either in full or with a JSON Merge Patch
 func (a *ActivityRestHandlers) HandleUpdateActivity() http.HandlerFunc {
 	isProduction := a.config.IsProduction()
-	validator := validator.New()
+	validator := shared.NewValidator()
 	actitivityService := a.actitivityService
+	activityRepository := a.activityRepository
+	projectRepository := a.projectRepository
 	return func(w http.ResponseWriter, r *http.Request) {
 		activityIDParam := chi.URLParam(r, "activity-id")
 		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
 
-		var activityModel activityModel
-		err := json.NewDecoder(r.Body).Decode(&activityModel)
+		activityID, err := uuid.Parse(activityIDParam)
 		if err != nil {
 			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
 			return
 		}
 
-		err = validator.Struct(activityModel)
+		var currentActivity *Activity
+		if shared.IsMergePatch(r) {
+			currentActivity, err = activityRepository.FindActivityByID(r.Context(), activityID, principal.OrganizationID)
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+		}
+
+		activityModel, err := decodeActivityModel(r, validator, currentActivity)
 		if err != nil {
-			http.Error(w, problem.New(problem.Title("activity not valid")).JSONString(), http.StatusBadRequest)
+			shared.RenderValidationProblemJSON(w, "activity not valid", err)
 			return
 		}
 
-		activity, err := mapToActivity(&activityModel)
+		err = resolveProjectAliasOf(r.Context(), projectRepository, principal.OrganizationID, activityModel)
 		if err != nil {
-			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			shared.RenderProblemJSON(w, isProduction, err)
 			return
 		}
 
-		activityID, err := uuid.Parse(activityIDParam)
+		activity, err := mapToActivity(activityModel)
+		if err == nil && activity.ProjectID == uuid.Nil {
+			err = shared.NewInvalidParam("_links.project", "uuid", "must link a project")
+		}
 		if err != nil {
-			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			shared.RenderValidationProblemJSON(w, "activity not valid", err)
 			return
 		}
 		activity.ID = activityID
 
 		activityUpdate, err := actitivityService.UpdateActivity(r.Context(), principal, activity)
-		if errors.Is(err, ErrActivityNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
 		if err != nil {
 			shared.RenderProblemJSON(w, isProduction, err)
 			return
 		}
 
-		activityModelUpdate := mapToActivityModel(activityUpdate)
-		shared.RenderJSON(w, activityModelUpdate)
+		renderActivityModel(w, r, activityUpdate)
 	}
 }
 
+// resolveProjectAliasOf replaces a project alias in the project link of the activity like /api/projects/WEB
+// by the id of the project
+func resolveProjectAliasOf(ctx context.Context, projectRepository ProjectRepository, organizationID uuid.UUID, activityModel *activityModel) error {
+	if activityModel.Links == nil {
+		return nil
+	}
+
+	projectHref := activityModel.Links.HrefOf("project")
+	alias := projectHref[strings.LastIndex(projectHref, "/")+1:]
+	if !IsValidProjectAlias(alias) {
+		return nil
+	}
+
+	project, err := projectRepository.FindProjectByAlias(ctx, organizationID, alias)
+	if err != nil {
+		return err
+	}
+
+	(*activityModel.Links)["project"] = &hal.Link{Href: fmt.Sprintf("/api/projects/%s", project.ID)}
+	return nil
+}
+
+// setNearDuplicateWarning warns that the created activity is a near duplicate, the duplicates are linked with relation duplicate
+func setNearDuplicateWarning(w http.ResponseWriter, duplicates []*Activity) {
+	if len(duplicates) == 0 {
+		return
+	}
+
+	w.Header().Set("Warning", fmt.Sprintf("299 - %q", ErrActivityNearDuplicate.Title))
+	for _, duplicate := range duplicates {
+		w.Header().Add("Link", fmt.Sprintf(`</api/activities/%s>; rel="duplicate"`, duplicate.ID))
+	}
+}
+
+// renderNearDuplicateProblemJSON renders the near duplicate as problem listing the links to the duplicates
+func renderNearDuplicateProblemJSON(w http.ResponseWriter, nearDuplicateError *ActivityNearDuplicateError) {
+	duplicateHrefs := make([]string, len(nearDuplicateError.Duplicates))
+	for i, duplicate := range nearDuplicateError.Duplicates {
+		duplicateHrefs[i] = fmt.Sprintf("/api/activities/%s", duplicate.ID)
+	}
+
+	_, _ = problem.New(
+		problem.Type(ErrActivityNearDuplicate.Type()),
+		problem.Title(ErrActivityNearDuplicate.Title),
+		problem.Status(ErrActivityNearDuplicate.Status),
+		problem.Custom("code", ErrActivityNearDuplicate.Code),
+		problem.Detail("create the activity anyway without query param rejectDuplicate=true"),
+		problem.Custom("duplicates", duplicateHrefs),
+	).WriteTo(w)
+}
+
 func mapToActivity(activityModel *activityModel) (*Activity, error) {
 	var activityID uuid.UUID
 
 	if activityModel.ID != "" {
 		aID, err := uuid.Parse(activityModel.ID)
 		if err != nil {
-			return nil, err
+			return nil, shared.NewInvalidParam("id", "uuid", "must be a valid id")
 		}
 		activityID = aID
 	}
 
 	start, err := time_utils.ParseDateTime(activityModel.Start)
 	if err != nil {
-		return nil, err
+		return nil, shared.NewInvalidParam("start", "datetime", "must be a date time like 2021-12-31T10:00:00")
 	}
 
 	end, err := time_utils.ParseDateTime(activityModel.End)
 	if err != nil {
-		return nil, err
+		return nil, shared.NewInvalidParam("end", "datetime", "must be a date time like 2021-12-31T10:00:00")
 	}
 
-	projectHref := activityModel.Links.HrefOf("project")
-	projectID, err := uuid.Parse(projectHref[strings.LastIndex(projectHref, "/")+1:])
-	if err != nil {
-		return nil, err
+	// an activity without project is assigned one by the project assignment rules
+	var projectID uuid.UUID
+	var projectHref string
+	if activityModel.Links != nil {
+		projectHref = activityModel.Links.HrefOf("project")
+	}
+	if projectHref != "" {
+		pID, err := uuid.Parse(projectHref[strings.LastIndex(projectHref, "/")+1:])
+		if err != nil {
+			return nil, shared.NewInvalidParam("_links.project", "uuid", "must link a project")
+		}
+		projectID = pID
+	}
+
+	if (activityModel.Latitude == nil) != (activityModel.Longitude == nil) {
+		return nil, shared.NewInvalidParam("latitude", "coordinates", "latitude and longitude must be given together")
 	}
 
 	activity := &Activity{
@@ -313,6 +453,9 @@ func mapToActivity(activityModel *activityModel) (*Activity, error) {
 		End:         *end,
 		ProjectID:   projectID,
 		Description: activityModel.Description,
+		Location:    activityModel.Location,
+		Latitude:    activityModel.Latitude,
+		Longitude:   activityModel.Longitude,
 	}
 
 	return activity, nil
@@ -324,6 +467,9 @@ func mapToActivityModel(activity *Activity) *activityModel {
 		Description: activity.Description,
 		Start:       time_utils.FormatDateTime(activity.Start),
 		End:         time_utils.FormatDateTime(activity.End),
+		Location:    activity.Location,
+		Latitude:    activity.Latitude,
+		Longitude:   activity.Longitude,
 		Links: hal.NewLinks(
 			hal.NewSelfLink(fmt.Sprintf("/api/activities/%s", activity.ID)),
 			hal.NewLink("delete", fmt.Sprintf("/api/activities/%s", activity.ID)),
@@ -350,6 +496,18 @@ func mapToActivityModels(activities []*Activity) []*activityModel {
 	return activityModels
 }
 
+func pageLinks(r *http.Request, page *paged.Page) []*hal.Links {
+	if page == nil {
+		return nil
+	}
+
+	var links []*hal.Links
+	for relation, href := range page.PageLinks(r.URL) {
+		links = append(links, hal.NewLink(relation, href))
+	}
+	return links
+}
+
 func mapToProjectModels(principal *shared.Principal, projects []*Project) []*projectModel {
 	activityModels := make([]*projectModel, len(projects))
 
@@ -361,6 +519,19 @@ func mapToProjectModels(principal *shared.Principal, projects []*Project) []*pro
 	return activityModels
 }
 
+func mapToActivityUserModels(users []*ActivityUser) []*activityUserModel {
+	userModels := make([]*activityUserModel, len(users))
+
+	for i, user := range users {
+		userModels[i] = &activityUserModel{
+			Username: user.Username,
+			Name:     user.Name,
+		}
+	}
+
+	return userModels
+}
+
 func filterFromQueryParams(params url.Values) (*ActivityFilter, error) {
 	if len(params["t"]) == 0 {
 		params["t"] = []string{"week"}
@@ -384,14 +555,51 @@ func filterFromQueryParams(params url.Values) (*ActivityFilter, error) {
 		}
 	}
 
+	var query *ActivityQuery
+	if len(params["filter"]) != 0 && strings.TrimSpace(params["filter"][0]) != "" {
+		q, err := ParseActivityQuery(params["filter"][0])
+		if err != nil {
+			return nil, err
+		}
+		query = q
+	}
+
+	// projects are embedded by default for compatibility
+	embed := []string{ActivityEmbedProject}
+	if len(params["embed"]) != 0 {
+		embed = nil
+		for _, e := range strings.Split(params["embed"][0], ",") {
+			e = strings.TrimSpace(e)
+			if e == "" {
+				continue
+			}
+			if !IsValidActivityEmbed(e) {
+				return nil, shared.NewInvalidParam("embed", "oneof", fmt.Sprintf("%s can not be embedded, must be one of project or user", e))
+			}
+			embed = append(embed, e)
+		}
+	}
+
+	anonymize := false
+	if len(params["anonymize"]) != 0 {
+		a, err := strconv.ParseBool(params["anonymize"][0])
+		if err != nil {
+			return nil, shared.NewInvalidParam("anonymize", "boolean", "must be true or false")
+		}
+		anonymize = a
+	}
+
 	filter := &ActivityFilter{
 		Timespan:  timespan,
 		sortBy:    sortBy,
 		sortOrder: sortOrder,
+		query:     query,
+		embed:     embed,
+		anonymize: anonymize,
 	}
 
 	if timespan == TimespanCustom && len(params["start"]) == 0 && len(params["end"]) == 0 {
-		return nil, errors.New("missing timespan value")
+		return nil, shared.NewInvalidParam("start", "required", "is required for a custom timespan")
 	}
 
 	if len(params["v"]) != 0 {
@@ -404,17 +612,17 @@ func filterFromQueryParams(params url.Values) (*ActivityFilter, error) {
 	case TimespanYear:
 		start, err := time.Parse("2006", value)
 		if err != nil {
-			return nil, err
+			return nil, invalidTimespanValue(timespan)
 		}
 		filter.start = start
 	case TimespanQuarter:
 		if !strings.Contains(value, "-") {
-			return nil, errors.New("invalid quarter")
+			return nil, invalidTimespanValue(timespan)
 		}
 		valueParts := strings.Split(value, "-")
 		start, err := time.Parse("2006", valueParts[0])
 		if err != nil {
-			return nil, err
+			return nil, invalidTimespanValue(timespan)
 		}
 
 		d := 24 * time.Hour
@@ -422,35 +630,35 @@ func filterFromQueryParams(params url.Values) (*ActivityFilter, error) {
 
 		startQuarterOfYear, err := strconv.Atoi(valueParts[1])
 		if err != nil {
-			return nil, errors.New("invalid quarter")
+			return nil, invalidTimespanValue(timespan)
 		}
 		filter.start = start.AddDate(0, 3*(startQuarterOfYear-1), 0)
 	case TimespanMonth:
 		start, err := time.Parse("2006-01", value)
 		if err != nil {
-			return nil, err
+			return nil, invalidTimespanValue(timespan)
 		}
 		filter.start = start
 	case TimespanWeek:
 		if !strings.Contains(value, "-") {
-			return nil, errors.New("invalid week")
+			return nil, invalidTimespanValue(timespan)
 		}
 		valueParts := strings.Split(value, "-")
 
 		startYear, err := strconv.Atoi(valueParts[0])
 		if err != nil {
-			return nil, err
+			return nil, invalidTimespanValue(timespan)
 		}
 		startWeekOfYear, err := strconv.Atoi(valueParts[1])
 		if err != nil {
-			return nil, err
+			return nil, invalidTimespanValue(timespan)
 		}
 
 		filter.start = isoweek.StartTime(startYear, startWeekOfYear, time.UTC)
 	case TimespanDay:
 		start, err := time.Parse("2006-01-02", value)
 		if err != nil {
-			return nil, err
+			return nil, invalidTimespanValue(timespan)
 		}
 		filter.start = start
 	case TimespanCustom:
@@ -458,7 +666,7 @@ func filterFromQueryParams(params url.Values) (*ActivityFilter, error) {
 		if startParamValue != "" {
 			startParam, err := time_utils.ParseDate(startParamValue)
 			if err != nil {
-				return nil, err
+				return nil, shared.NewInvalidParam("start", "date", "must be a date like 2021-12-31")
 			}
 			filter.start = *startParam
 		}
@@ -467,13 +675,24 @@ func filterFromQueryParams(params url.Values) (*ActivityFilter, error) {
 		if endParamValue != "" {
 			endParam, err := time_utils.ParseDate(endParamValue)
 			if err != nil {
-				return nil, err
+				return nil, shared.NewInvalidParam("end", "date", "must be a date like 2021-12-31")
 			}
 			filter.end = *endParam
 		}
 	default:
-		return nil, errors.New("invalid activity filter")
+		return nil, shared.NewInvalidParam("t", "timespan", "must be one of year, quarter, month, week, day or custom")
 	}
 
 	return filter, nil
 }
+
+func invalidTimespanValue(timespan string) *shared.InvalidParam {
+	formats := map[string]string{
+		TimespanYear:    "2021",
+		TimespanQuarter: "2021-4",
+		TimespanMonth:   "2021-12",
+		TimespanWeek:    "2021-52",
+		TimespanDay:     "2021-12-31",
+	}
+	return shared.NewInvalidParam("v", "format", fmt.Sprintf("must be a %s like %s", timespan, formats[timespan]))
+}
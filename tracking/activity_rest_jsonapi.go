@@ -0,0 +1,124 @@
+package tracking
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/baralga/shared/jsonapi"
+	time_utils "github.com/baralga/tracking/time"
+)
+
+type activityAttributes struct {
+	Start           string   `json:"start"`
+	End             string   `json:"end"`
+	Description     string   `json:"description"`
+	Location        string   `json:"location,omitempty"`
+	Latitude        *float64 `json:"latitude,omitempty"`
+	Longitude       *float64 `json:"longitude,omitempty"`
+	DurationMinutes int      `json:"durationMinutes"`
+}
+
+type userAttributes struct {
+	Name string `json:"name"`
+}
+
+type projectAttributes struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Status      string  `json:"status"`
+	Billable    bool    `json:"billable"`
+	BudgetHours float64 `json:"budgetHours"`
+}
+
+// renderActivitiesDocument renders the page of activities as JSON:API document
+// with the embedded projects and users included
+func renderActivitiesDocument(w http.ResponseWriter, r *http.Request, activitiesPage *ActivitiesPaged, projects []*Project) {
+	links := map[string]string{
+		"self": r.URL.RequestURI(),
+	}
+	meta := map[string]any{}
+	if activitiesPage.Page != nil {
+		for relation, href := range activitiesPage.Page.PageLinks(r.URL) {
+			links[relation] = href
+		}
+		meta["page"] = activitiesPage.Page
+	}
+
+	jsonapi.Render(w, &jsonapi.Document{
+		Data:     mapToActivityResources(activitiesPage.Activities),
+		Included: append(mapToProjectResources(projects), mapToUserResources(activitiesPage.Users)...),
+		Links:    links,
+		Meta:     meta,
+	})
+}
+
+func mapToActivityResources(activities []*Activity) []*jsonapi.Resource {
+	resources := make([]*jsonapi.Resource, len(activities))
+
+	for i, activity := range activities {
+		resources[i] = &jsonapi.Resource{
+			Type: "activities",
+			ID:   activity.ID.String(),
+			Attributes: &activityAttributes{
+				Start:           time_utils.FormatDateTime(activity.Start),
+				End:             time_utils.FormatDateTime(activity.End),
+				Description:     activity.Description,
+				Location:        activity.Location,
+				Latitude:        activity.Latitude,
+				Longitude:       activity.Longitude,
+				DurationMinutes: activity.DurationMinutesTotal(),
+			},
+			Relationships: map[string]*jsonapi.Relationship{
+				"project": jsonapi.NewRelationship(
+					"projects",
+					activity.ProjectID.String(),
+					fmt.Sprintf("/api/projects/%s", activity.ProjectID),
+				),
+			},
+			Links: map[string]string{
+				"self": fmt.Sprintf("/api/activities/%s", activity.ID),
+			},
+		}
+	}
+
+	return resources
+}
+
+func mapToProjectResources(projects []*Project) []*jsonapi.Resource {
+	resources := make([]*jsonapi.Resource, len(projects))
+
+	for i, project := range projects {
+		resources[i] = &jsonapi.Resource{
+			Type: "projects",
+			ID:   project.ID.String(),
+			Attributes: &projectAttributes{
+				Title:       project.Title,
+				Description: project.Description,
+				Status:      project.Status,
+				Billable:    project.Billable,
+				BudgetHours: float64(project.BudgetMinutes) / 60.0,
+			},
+			Links: map[string]string{
+				"self": fmt.Sprintf("/api/projects/%s", project.ID),
+			},
+		}
+	}
+
+	return resources
+}
+
+func mapToUserResources(users []*ActivityUser) []*jsonapi.Resource {
+	resources := make([]*jsonapi.Resource, len(users))
+
+	for i, user := range users {
+		resources[i] = &jsonapi.Resource{
+			Type: "users",
+			ID:   user.Username,
+			Attributes: &userAttributes{
+				Name: user.Name,
+			},
+		}
+	}
+
+	return resources
+}
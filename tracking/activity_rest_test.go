@@ -12,6 +12,7 @@ import (
 
 	"github.com/baralga/shared"
 	"github.com/baralga/shared/hal"
+	"github.com/baralga/shared/jsonapi"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/matryer/is"
@@ -86,7 +87,9 @@ func TestHandleGetActivity(t *testing.T) {
 	}
 
 	r, _ := http.NewRequest("GET", "/api/activities/00000000-0000-0000-2222-000000000001", nil)
-	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
 
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("activity-id", "00000000-0000-0000-2222-000000000001")
@@ -145,7 +148,8 @@ func TestHandleGetActivitiesWithUrlParams(t *testing.T) {
 		config:             &shared.Config{},
 		activityRepository: activityRepository,
 		actitivityService: &ActitivityService{
-			activityRepository: activityRepository,
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -161,6 +165,173 @@ func TestHandleGetActivitiesWithUrlParams(t *testing.T) {
 	is.Equal(1, len(activitiesModel.ActivityModels))
 }
 
+func TestHandleGetActivitiesWithFields(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: activityRepository,
+		actitivityService: &ActitivityService{
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	r, _ := http.NewRequest("GET", "/api/activities?start=2021-10-01&end=2022-10-01&fields=start,end", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleGetActivities()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	activitiesModel := &activitiesModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(activitiesModel)
+	is.NoErr(err)
+	is.Equal(1, len(activitiesModel.ActivityModels))
+	is.True(activitiesModel.ActivityModels[0].ID != "")
+	is.True(activitiesModel.ActivityModels[0].Start != "")
+	is.Equal(activitiesModel.ActivityModels[0].Duration, nil)
+	is.True(len(activitiesModel.ProjectModels) > 0)
+}
+
+func TestHandleGetActivitiesAsJSONAPI(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: activityRepository,
+		actitivityService: &ActitivityService{
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	r, _ := http.NewRequest("GET", "/api/activities?start=2021-10-01&end=2022-10-01", nil)
+	r.Header.Set("Accept", jsonapi.MediaType)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleGetActivities()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(httpRec.Header().Get("Content-Type"), jsonapi.MediaType)
+
+	var document struct {
+		Data     []*jsonapi.Resource `json:"data"`
+		Included []*jsonapi.Resource `json:"included"`
+		Links    map[string]string   `json:"links"`
+	}
+	err := json.NewDecoder(httpRec.Body).Decode(&document)
+	is.NoErr(err)
+	is.Equal(1, len(document.Data))
+	is.Equal(document.Data[0].Type, "activities")
+	is.Equal(document.Data[0].Relationships["project"].Data.Type, "projects")
+	is.True(len(document.Included) > 0)
+	is.True(document.Links["first"] != "")
+}
+
+func TestHandleGetActivitiesWithFilterQuery(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: activityRepository,
+		actitivityService: &ActitivityService{
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	filter := url.QueryEscape(`user in (user2, user3)`)
+	r, _ := http.NewRequest("GET", "/api/activities?start=2021-10-01&end=2022-10-01&filter="+filter, nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleGetActivities()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	activitiesModel := &activitiesModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(activitiesModel)
+	is.NoErr(err)
+	is.Equal(0, len(activitiesModel.ActivityModels))
+}
+
+func TestHandleGetActivitiesWithInvalidFilterQuery(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: activityRepository,
+		actitivityService: &ActitivityService{
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	filter := url.QueryEscape(`tag="billing"`)
+	r, _ := http.NewRequest("GET", "/api/activities?start=2021-10-01&end=2022-10-01&filter="+filter, nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleGetActivities()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	is.True(strings.Contains(httpRec.Body.String(), `"invalid-params":[{"field":"filter","code":"syntax","message":"unknown field tag at position 0"}]`))
+}
+
+func TestHandleGetActivitiesWithEmbeddedUsers(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: activityRepository,
+		actitivityService: &ActitivityService{
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	r, _ := http.NewRequest("GET", "/api/activities?start=2021-10-01&end=2022-10-01&embed=user", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleGetActivities()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	activitiesModel := &activitiesModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(activitiesModel)
+	is.NoErr(err)
+	is.Equal(1, len(activitiesModel.ActivityModels))
+	is.Equal(0, len(activitiesModel.ProjectModels))
+	is.Equal(1, len(activitiesModel.UserModels))
+	is.Equal(activitiesModel.UserModels[0].Username, "user1")
+}
+
+func TestHandleGetActivitiesWithInvalidEmbed(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: activityRepository,
+		actitivityService: &ActitivityService{
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	r, _ := http.NewRequest("GET", "/api/activities?start=2021-10-01&end=2022-10-01&embed=project,organization", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleGetActivities()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	is.True(strings.Contains(httpRec.Body.String(), `"field":"embed"`))
+}
+
 func TestHandleGetActivitiesWithTimespanUrlParams(t *testing.T) {
 	is := is.New(t)
 	httpRec := httptest.NewRecorder()
@@ -170,7 +341,8 @@ func TestHandleGetActivitiesWithTimespanUrlParams(t *testing.T) {
 		config:             &shared.Config{},
 		activityRepository: activityRepository,
 		actitivityService: &ActitivityService{
-			activityRepository: activityRepository,
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -195,7 +367,9 @@ func TestHandleGetActivitiesWithTimespanUrlParamsAsCSV(t *testing.T) {
 		config:             &shared.Config{},
 		activityRepository: activityRepository,
 		actitivityService: &ActitivityService{
-			activityRepository: activityRepository,
+			activityLinkRepository:   NewInMemActivityLinkRepository(),
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -220,7 +394,9 @@ func TestHandleGetActivitiesWithTimespanUrlParamsAsExcel(t *testing.T) {
 		config:             &shared.Config{},
 		activityRepository: repo,
 		actitivityService: &ActitivityService{
-			activityRepository: repo,
+			activityLinkRepository:   NewInMemActivityLinkRepository(),
+			activityRepository:       repo,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -243,8 +419,9 @@ func TestHandleCreateActivity(t *testing.T) {
 		config:             config,
 		activityRepository: repo,
 		actitivityService: &ActitivityService{
-			repositoryTxer:     shared.NewInMemRepositoryTxer(),
-			activityRepository: repo,
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       repo,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -271,6 +448,181 @@ func TestHandleCreateActivity(t *testing.T) {
 	is.Equal(countBefore+1, len(repo.activities))
 }
 
+func TestHandleCreateActivityWithLocation(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	repo := NewInMemActivityRepository()
+	locationPolicyRepository := NewInMemLocationPolicyRepository()
+	locationPolicyRepository.policies[shared.OrganizationIDSample] = &LocationPolicy{
+		OrganizationID:   shared.OrganizationIDSample,
+		Mode:             LocationPolicyOptional,
+		AllowCoordinates: true,
+	}
+
+	c := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: repo,
+		actitivityService: &ActitivityService{
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       repo,
+			locationPolicyRepository: locationPolicyRepository,
+		},
+	}
+
+	body := `
+	{
+		"start":"2021-11-06T21:37:00",
+		"end":"2021-11-06T22:37:00",
+		"location":"client-site",
+		"latitude":52.52,
+		"longitude":13.405,
+		"_links":{
+		   "project":{
+			  "href":"http://localhost:8080/api/projects/f4b1087c-8fbb-4c8d-bbb7-ab4d46da16ea"
+		   }
+		}
+	 }
+	`
+
+	r, _ := http.NewRequest("POST", "/api/activities", strings.NewReader(body))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{OrganizationID: shared.OrganizationIDSample}))
+
+	c.HandleCreateActivity()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+	is.True(strings.Contains(httpRec.Body.String(), `"location":"client-site"`))
+	is.True(strings.Contains(httpRec.Body.String(), `"latitude":52.52`))
+
+	activity := repo.activities[len(repo.activities)-1]
+	is.Equal(activity.Location, LocationClientSite)
+	is.Equal(*activity.Longitude, 13.405)
+}
+
+func TestHandleCreateActivityWithInvalidLocation(t *testing.T) {
+	is := is.New(t)
+
+	repo := NewInMemActivityRepository()
+	c := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: repo,
+		actitivityService: &ActitivityService{
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       repo,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	bodies := []string{
+		`{"start":"2021-11-06T21:37:00","end":"2021-11-06T22:37:00","location":"beach",` +
+			`"_links":{"project":{"href":"/api/projects/f4b1087c-8fbb-4c8d-bbb7-ab4d46da16ea"}}}`,
+		`{"start":"2021-11-06T21:37:00","end":"2021-11-06T22:37:00","latitude":52.52,` +
+			`"_links":{"project":{"href":"/api/projects/f4b1087c-8fbb-4c8d-bbb7-ab4d46da16ea"}}}`,
+		`{"start":"2021-11-06T21:37:00","end":"2021-11-06T22:37:00","latitude":91,"longitude":13.405,` +
+			`"_links":{"project":{"href":"/api/projects/f4b1087c-8fbb-4c8d-bbb7-ab4d46da16ea"}}}`,
+	}
+
+	for _, body := range bodies {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/api/activities", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+		c.HandleCreateActivity()(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestHandleCreateActivityV2(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	repo := NewInMemActivityRepository()
+
+	c := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: repo,
+		actitivityService: &ActitivityService{
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       repo,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	countBefore := len(repo.activities)
+	body := `
+	{
+		"start":"2021-11-06T20:07:00",
+		"end":"2021-11-06T21:37:00",
+		"description":"",
+		"projectId":"f4b1087c-8fbb-4c8d-bbb7-ab4d46da16ea"
+	 }
+	`
+
+	r, _ := http.NewRequest("POST", "/api/v2/activities", strings.NewReader(body))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyAPIVersion, shared.APIVersion2))
+
+	c.HandleCreateActivity()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+	is.Equal(countBefore+1, len(repo.activities))
+
+	activityModel := &activityModelV2{}
+	err := json.NewDecoder(httpRec.Body).Decode(activityModel)
+	is.NoErr(err)
+	is.Equal(activityModel.ProjectID, "f4b1087c-8fbb-4c8d-bbb7-ab4d46da16ea")
+	is.Equal(activityModel.DurationMinutes, 90)
+}
+
+func TestHandleCreateActivityV2WithoutProject(t *testing.T) {
+	is := is.New(t)
+
+	projectAssignmentRepository := NewInMemProjectAssignmentRepository()
+	a := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: NewInMemActivityRepository(),
+		actitivityService: &ActitivityService{
+			repositoryTxer:              shared.NewInMemRepositoryTxer(),
+			activityRepository:          NewInMemActivityRepository(),
+			locationPolicyRepository:    NewInMemLocationPolicyRepository(),
+			projectAssignmentRepository: projectAssignmentRepository,
+		},
+	}
+
+	body := `
+	{
+		"start":"2021-11-06T20:07:00",
+		"end":"2021-11-06T21:37:00"
+	 }
+	`
+
+	createActivity := func() *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/api/v2/activities", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+		}))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyAPIVersion, shared.APIVersion2))
+		a.HandleCreateActivity()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := createActivity()
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	is.True(strings.Contains(httpRec.Body.String(), "activity:project-required"))
+
+	projectID := shared.ProjectIDSample
+	err := projectAssignmentRepository.UpdateDefaultProject(context.Background(), shared.OrganizationIDSample, "user1", &projectID)
+	is.NoErr(err)
+
+	httpRec = createActivity()
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	activityModel := &activityModelV2{}
+	err = json.NewDecoder(httpRec.Body).Decode(activityModel)
+	is.NoErr(err)
+	is.Equal(activityModel.ProjectID, shared.ProjectIDSample.String())
+}
+
 func TestHandleCreateInvalidActivity(t *testing.T) {
 	is := is.New(t)
 	httpRec := httptest.NewRecorder()
@@ -300,6 +652,37 @@ func TestHandleCreateInvalidActivity(t *testing.T) {
 
 	a.HandleCreateActivity()(httpRec, r)
 	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	is.True(strings.Contains(httpRec.Body.String(), `"field":"description"`))
+}
+
+func TestHandleCreateActivityWithInvalidStart(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: NewInMemActivityRepository(),
+	}
+
+	body := `
+	{
+		"start":"2021-11-06 21:37",
+		"end":"2021-11-06T21:37:00",
+		"_links":{
+		   "project":{
+			  "href":"http://localhost:8080/api/projects/f4b1087c-8fbb-4c8d-bbb7-ab4d46da16ea"
+		   }
+		}
+	 }
+	`
+
+	r, _ := http.NewRequest("POST", "/api/activities", strings.NewReader(body))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleCreateActivity()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	is.Equal(httpRec.Header().Get("Content-Type"), "application/problem+json")
+	is.True(strings.Contains(httpRec.Body.String(), `"invalid-params":[{"field":"start","code":"datetime"`))
 }
 
 func TestHandleDeleteActivityAsAdmin(t *testing.T) {
@@ -312,16 +695,21 @@ func TestHandleDeleteActivityAsAdmin(t *testing.T) {
 	c := &ActivityRestHandlers{
 		config:             config,
 		activityRepository: repo,
-		actitivityService: &ActitivityService{
-			repositoryTxer:     shared.NewInMemRepositoryTxer(),
-			activityRepository: repo,
+		undoService: &UndoService{
+			config:                 config,
+			repositoryTxer:         shared.NewInMemRepositoryTxer(),
+			undoRepository:         NewInMemUndoRepository(),
+			activityRepository:     repo,
+			goalRepository:         NewInMemGoalRepository(),
+			activityLinkRepository: NewInMemActivityLinkRepository(),
 		},
 	}
 
 	r, _ := http.NewRequest("DELETE", "/api/activities/00000000-0000-0000-2222-000000000001", nil)
 	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
-		Username: "admin",
-		Roles:    []string{"ROLE_ADMIN"},
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
 	}))
 
 	rctx := chi.NewRouteContext()
@@ -331,6 +719,7 @@ func TestHandleDeleteActivityAsAdmin(t *testing.T) {
 	c.HandleDeleteActivity()(httpRec, r)
 	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
 	is.Equal(0, len(repo.activities))
+	is.True(strings.Contains(httpRec.Body.String(), `"undo":{"href":"/api/undo/`))
 }
 
 func TestHandleDeleteActivityAsMatchingUser(t *testing.T) {
@@ -343,15 +732,20 @@ func TestHandleDeleteActivityAsMatchingUser(t *testing.T) {
 	c := &ActivityRestHandlers{
 		config:             config,
 		activityRepository: repo,
-		actitivityService: &ActitivityService{
-			repositoryTxer:     shared.NewInMemRepositoryTxer(),
-			activityRepository: repo,
+		undoService: &UndoService{
+			config:                 config,
+			repositoryTxer:         shared.NewInMemRepositoryTxer(),
+			undoRepository:         NewInMemUndoRepository(),
+			activityRepository:     repo,
+			goalRepository:         NewInMemGoalRepository(),
+			activityLinkRepository: NewInMemActivityLinkRepository(),
 		},
 	}
 
 	r, _ := http.NewRequest("DELETE", "/api/activities/00000000-0000-0000-2222-000000000001", nil)
 	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
-		Username: "user1",
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
 	}))
 
 	rctx := chi.NewRouteContext()
@@ -410,13 +804,18 @@ func TestHandleDeleteActivityAsNonMatchingUser(t *testing.T) {
 	httpRec := httptest.NewRecorder()
 
 	repo := NewInMemActivityRepository()
+	config := &shared.Config{}
 
 	c := &ActivityRestHandlers{
-		config:             &shared.Config{},
+		config:             config,
 		activityRepository: repo,
-		actitivityService: &ActitivityService{
-			repositoryTxer:     shared.NewInMemRepositoryTxer(),
-			activityRepository: repo,
+		undoService: &UndoService{
+			config:                 config,
+			repositoryTxer:         shared.NewInMemRepositoryTxer(),
+			undoRepository:         NewInMemUndoRepository(),
+			activityRepository:     repo,
+			goalRepository:         NewInMemGoalRepository(),
+			activityLinkRepository: NewInMemActivityLinkRepository(),
 		},
 	}
 
@@ -444,8 +843,9 @@ func TestHandleUpdateActivity(t *testing.T) {
 		config:             &shared.Config{},
 		activityRepository: repo,
 		actitivityService: &ActitivityService{
-			repositoryTxer:     shared.NewInMemRepositoryTxer(),
-			activityRepository: repo,
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       repo,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -465,7 +865,8 @@ func TestHandleUpdateActivity(t *testing.T) {
 
 	r, _ := http.NewRequest("POST", "/api/activities", strings.NewReader(body))
 	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
-		Roles: []string{"ROLE_ADMIN"},
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
 	}))
 
 	rctx := chi.NewRouteContext()
@@ -480,6 +881,50 @@ func TestHandleUpdateActivity(t *testing.T) {
 	is.Equal("My updated Description", activityUpdate.Description)
 }
 
+func TestHandleUpdateActivityWithMergePatch(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	repo := NewInMemActivityRepository()
+
+	c := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: repo,
+		actitivityService: &ActitivityService{
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       repo,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	activityID := uuid.MustParse("00000000-0000-0000-2222-000000000001")
+	activityBefore, err := repo.FindActivityByID(context.Background(), activityID, shared.OrganizationIDSample)
+	is.NoErr(err)
+	startBefore := activityBefore.Start
+
+	body := `{"description": "My patched Description"}`
+
+	r, _ := http.NewRequest("PATCH", "/api/activities/00000000-0000-0000-2222-000000000001", strings.NewReader(body))
+	r.Header.Set("Content-Type", shared.MergePatchContentType)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("activity-id", activityID.String())
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	c.HandleUpdateActivity()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	activityUpdate, err := repo.FindActivityByID(context.Background(), activityID, shared.OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal("My patched Description", activityUpdate.Description)
+	is.Equal(startBefore.Format(time.RFC3339), activityUpdate.Start.Format(time.RFC3339))
+	is.Equal(shared.ProjectIDSample, activityUpdate.ProjectID)
+}
+
 func TestHandleUpdateInvalidActivity(t *testing.T) {
 	is := is.New(t)
 	httpRec := httptest.NewRecorder()
@@ -527,8 +972,9 @@ func TestHandleUpdateActivityAsUser(t *testing.T) {
 		config:             &shared.Config{},
 		activityRepository: repo,
 		actitivityService: &ActitivityService{
-			repositoryTxer:     shared.NewInMemRepositoryTxer(),
-			activityRepository: repo,
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       repo,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -548,7 +994,8 @@ func TestHandleUpdateActivityAsUser(t *testing.T) {
 
 	r, _ := http.NewRequest("POST", "/api/activities", strings.NewReader(body))
 	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
-		Username: "user1",
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
 	}))
 
 	rctx := chi.NewRouteContext()
@@ -573,8 +1020,9 @@ func TestHandleUpdateActivityWithNonMatchingUser(t *testing.T) {
 		config:             &shared.Config{},
 		activityRepository: repo,
 		actitivityService: &ActitivityService{
-			repositoryTxer:     shared.NewInMemRepositoryTxer(),
-			activityRepository: repo,
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       repo,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -827,3 +1275,98 @@ func TestFilterFromQueryParams(t *testing.T) {
 	})
 
 }
+
+func TestHandleCreateActivityWithProjectAlias(t *testing.T) {
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	err := projectRepository.InsertProjectAlias(context.Background(), &ProjectAlias{
+		OrganizationID: shared.OrganizationIDSample,
+		ProjectID:      shared.ProjectIDSample,
+		Alias:          "WEB",
+	})
+	is.NoErr(err)
+
+	c := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: NewInMemActivityRepository(),
+		projectRepository:  projectRepository,
+		actitivityService: &ActitivityService{
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       NewInMemActivityRepository(),
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	createActivity := func(body string, apiVersion string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/api/activities", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+		}))
+		if apiVersion != "" {
+			r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyAPIVersion, apiVersion))
+		}
+		c.HandleCreateActivity()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := createActivity(`{"start":"2021-11-06T20:07:00","end":"2021-11-06T21:37:00",`+
+		`"_links":{"project":{"href":"/api/projects/web"}}}`, "")
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+	is.True(strings.Contains(httpRec.Body.String(), shared.ProjectIDSample.String()))
+
+	httpRec = createActivity(`{"start":"2021-11-06T20:07:00","end":"2021-11-06T21:37:00","projectId":"WEB"}`, shared.APIVersion2)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	activityModel := &activityModelV2{}
+	err = json.NewDecoder(httpRec.Body).Decode(activityModel)
+	is.NoErr(err)
+	is.Equal(activityModel.ProjectID, shared.ProjectIDSample.String())
+
+	httpRec = createActivity(`{"start":"2021-11-06T20:07:00","end":"2021-11-06T21:37:00","projectId":"APP"}`, shared.APIVersion2)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
+
+func TestHandleCreateActivityNearDuplicate(t *testing.T) {
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActivityRestHandlers{
+		config:             &shared.Config{},
+		activityRepository: activityRepository,
+		actitivityService: &ActitivityService{
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	createActivity := func(url string) *httptest.ResponseRecorder {
+		body := `{"start":"2021-11-06T20:07:00","end":"2021-11-06T21:37:00","description":"Meeting",` +
+			`"_links":{"project":{"href":"/api/projects/` + shared.ProjectIDSample.String() + `"}}}`
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", url, strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+		}))
+		a.HandleCreateActivity()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := createActivity("/api/activities")
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+	is.Equal(httpRec.Header().Get("Warning"), "")
+
+	httpRec = createActivity("/api/activities")
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+	is.True(strings.Contains(httpRec.Header().Get("Warning"), "near duplicate"))
+	is.Equal(len(httpRec.Header().Values("Link")), 1)
+	is.True(strings.Contains(httpRec.Header().Get("Link"), `rel="duplicate"`))
+
+	httpRec = createActivity("/api/activities?rejectDuplicate=true")
+	is.Equal(httpRec.Result().StatusCode, http.StatusConflict)
+	is.True(strings.Contains(httpRec.Body.String(), `"code":"activity:near-duplicate"`))
+	is.True(strings.Contains(httpRec.Body.String(), `"duplicates":["/api/activities/`))
+}
@@ -0,0 +1,152 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/baralga/shared/paged"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-playground/validator/v10"
+)
+
+// activitiesModelV2 contains the activities of the second version of the api
+type activitiesModelV2 struct {
+	*EmbeddedActivitiesV2 `json:"_embedded"`
+	Page                  *paged.Page `json:"page"`
+	Links                 *hal.Links  `json:"_links"`
+}
+
+// EmbeddedActivitiesV2 contains embedded activities and projects of the second version of the api
+type EmbeddedActivitiesV2 struct {
+	ActivityModels []*activityModelV2   `json:"activities"`
+	ProjectModels  []*projectModel      `json:"projects"`
+	UserModels     []*activityUserModel `json:"users,omitempty"`
+}
+
+// activityModelV2 references the project by id instead of a link
+// and has the duration in minutes
+type activityModelV2 struct {
+	ID              string     `json:"id"`
+	Start           string     `json:"start" validate:"required"`
+	End             string     `json:"end" validate:"required"`
+	Description     string     `json:"description" validate:"max=500"`
+	ProjectID       string     `json:"projectId" validate:"omitempty,uuid|alphanum"`
+	Location        string     `json:"location,omitempty" validate:"omitempty,oneof=office home client-site"`
+	Latitude        *float64   `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	Longitude       *float64   `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
+	DurationMinutes int        `json:"durationMinutes"`
+	Links           *hal.Links `json:"_links"`
+}
+
+// decodeActivityModel reads the activity of the request in the model of the api version
+// and maps it to the model of the first version. A JSON Merge Patch is applied to the current activity.
+func decodeActivityModel(r *http.Request, validator *validator.Validate, current *Activity) (*activityModel, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	isV2 := shared.APIVersionOf(r) == shared.APIVersion2
+
+	if current != nil && shared.IsMergePatch(r) {
+		var currentModel any = mapToActivityModel(current)
+		if isV2 {
+			currentModel = mapToActivityModelV2(current)
+		}
+
+		body, err = shared.MergePatchModel(currentModel, body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isV2 {
+		var activityModelV2 activityModelV2
+		err := json.Unmarshal(body, &activityModelV2)
+		if err != nil {
+			return nil, err
+		}
+
+		err = validator.Struct(activityModelV2)
+		if err != nil {
+			return nil, err
+		}
+
+		return activityModelV2.toActivityModel(), nil
+	}
+
+	var activityModel activityModel
+	err = json.Unmarshal(body, &activityModel)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validator.Struct(activityModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &activityModel, nil
+}
+
+// renderActivityModel renders the activity in the model of the api version
+func renderActivityModel(w http.ResponseWriter, r *http.Request, activity *Activity) {
+	if shared.APIVersionOf(r) == shared.APIVersion2 {
+		shared.RenderJSON(w, mapToActivityModelV2(activity))
+		return
+	}
+
+	shared.RenderJSON(w, mapToActivityModel(activity))
+}
+
+func (m *activityModelV2) toActivityModel() *activityModel {
+	model := &activityModel{
+		ID:          m.ID,
+		Start:       m.Start,
+		End:         m.End,
+		Description: m.Description,
+		Location:    m.Location,
+		Latitude:    m.Latitude,
+		Longitude:   m.Longitude,
+	}
+	if m.ProjectID != "" {
+		model.Links = hal.NewLinks(
+			hal.NewLink("project", fmt.Sprintf("/api/v2/projects/%s", m.ProjectID)),
+		)
+	}
+	return model
+}
+
+func mapToActivityModelV2(activity *Activity) *activityModelV2 {
+	return &activityModelV2{
+		ID:              activity.ID.String(),
+		Start:           time_utils.FormatDateTime(activity.Start),
+		End:             time_utils.FormatDateTime(activity.End),
+		Description:     activity.Description,
+		ProjectID:       activity.ProjectID.String(),
+		Location:        activity.Location,
+		Latitude:        activity.Latitude,
+		Longitude:       activity.Longitude,
+		DurationMinutes: activity.DurationMinutesTotal(),
+		Links: hal.NewLinks(
+			hal.NewSelfLink(fmt.Sprintf("/api/v2/activities/%s", activity.ID)),
+			hal.NewLink("delete", fmt.Sprintf("/api/v2/activities/%s", activity.ID)),
+			hal.NewLink("edit", fmt.Sprintf("/api/v2/activities/%s", activity.ID)),
+			hal.NewLink("project", fmt.Sprintf("/api/v2/projects/%s", activity.ProjectID)),
+		),
+	}
+}
+
+func mapToActivityModelsV2(activities []*Activity) []*activityModelV2 {
+	activityModels := make([]*activityModelV2, len(activities))
+
+	for i, activity := range activities {
+		activityModels[i] = mapToActivityModelV2(activity)
+	}
+
+	return activityModels
+}
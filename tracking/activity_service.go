@@ -5,23 +5,47 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"math"
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/baralga/shared"
+	"github.com/baralga/shared/chart"
 	"github.com/baralga/shared/paged"
 	"github.com/google/uuid"
 	"github.com/xuri/excelize/v2"
 )
 
+// csvFlushInterval is the number of rows after which a streamed CSV export is flushed
+const csvFlushInterval = 1000
+
+var activitiesCSVHeaders = []string{"Date", "Start", "End", "Duration", "Project", "Description", "Links"}
+
+// maxForecastPeriods limits the periods of a burndown forecast
+const maxForecastPeriods = 104
+
 type ActitivityService struct {
-	repositoryTxer     shared.RepositoryTxer
-	activityRepository ActivityRepository
+	repositoryTxer               shared.RepositoryTxer
+	activityRepository           ActivityRepository
+	locationPolicyRepository     LocationPolicyRepository
+	projectAssignmentRepository  ProjectAssignmentRepository
+	compliancePolicyRepository   CompliancePolicyRepository
+	breakPolicyRepository        BreakPolicyRepository
+	activityLinkRepository       ActivityLinkRepository
+	categorizationRuleRepository CategorizationRuleRepository
 }
 
-func NewActitivityService(repositoryTxer shared.RepositoryTxer, activityRepository ActivityRepository) *ActitivityService {
+func NewActitivityService(repositoryTxer shared.RepositoryTxer, activityRepository ActivityRepository, locationPolicyRepository LocationPolicyRepository, projectAssignmentRepository ProjectAssignmentRepository, compliancePolicyRepository CompliancePolicyRepository, breakPolicyRepository BreakPolicyRepository, activityLinkRepository ActivityLinkRepository, categorizationRuleRepository CategorizationRuleRepository) *ActitivityService {
 	return &ActitivityService{
-		repositoryTxer:     repositoryTxer,
-		activityRepository: activityRepository,
+		repositoryTxer:               repositoryTxer,
+		activityRepository:           activityRepository,
+		locationPolicyRepository:     locationPolicyRepository,
+		projectAssignmentRepository:  projectAssignmentRepository,
+		compliancePolicyRepository:   compliancePolicyRepository,
+		breakPolicyRepository:        breakPolicyRepository,
+		activityLinkRepository:       activityLinkRepository,
+		categorizationRuleRepository: categorizationRuleRepository,
 	}
 }
 
@@ -37,21 +61,34 @@ func (a *ActitivityService) ReadActivitiesWithProjects(ctx context.Context, prin
 	return activitiesPage, projects, err
 }
 
+// TimeReports reports the tracked time by day, week, month or quarter, with auto deduction of breaks
+// the breaks not taken are deducted from the tracked time
 func (a *ActitivityService) TimeReports(ctx context.Context, principal *shared.Principal, filter *ActivityFilter, aggregateBy string) ([]*ActivityTimeReportItem, error) {
 	activitiesFilter := toFilter(principal, filter)
 
+	var reportItems []*ActivityTimeReportItem
+	var err error
 	switch {
 	case aggregateBy == "week":
-		return a.activityRepository.TimeReportByWeek(ctx, activitiesFilter)
+		reportItems, err = a.activityRepository.TimeReportByWeek(ctx, activitiesFilter)
 	case aggregateBy == "month":
-		return a.activityRepository.TimeReportByMonth(ctx, activitiesFilter)
+		reportItems, err = a.activityRepository.TimeReportByMonth(ctx, activitiesFilter)
 	case aggregateBy == "quarter":
-		return a.activityRepository.TimeReportByQuarter(ctx, activitiesFilter)
-	case aggregateBy == "day":
-		return a.activityRepository.TimeReportByDay(ctx, activitiesFilter)
+		reportItems, err = a.activityRepository.TimeReportByQuarter(ctx, activitiesFilter)
 	default:
-		return a.activityRepository.TimeReportByDay(ctx, activitiesFilter)
+		reportItems, err = a.activityRepository.TimeReportByDay(ctx, activitiesFilter)
+	}
+	if err != nil {
+		return nil, err
 	}
+
+	deductions, err := a.breakDeductions(ctx, activitiesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	DeductBreaksFromTimeReport(reportItems, deductions, aggregateBy)
+	return reportItems, nil
 }
 
 func (a *ActitivityService) ProjectReports(ctx context.Context, principal *shared.Principal, filter *ActivityFilter) ([]*ActivityProjectReportItem, error) {
@@ -59,14 +96,190 @@ func (a *ActitivityService) ProjectReports(ctx context.Context, principal *share
 	return a.activityRepository.ProjectReport(ctx, activitiesFilter)
 }
 
-// CreateActivity creates a new activity
-func (a *ActitivityService) CreateActivity(ctx context.Context, principal *shared.Principal, activity *Activity) (*Activity, error) {
+// UtilizationReport reports the billable and non-billable time per user, for the whole team and as weekly trend,
+// in the works council mode only admins evaluate the utilization
+func (a *ActitivityService) UtilizationReport(ctx context.Context, principal *shared.Principal, filter *ActivityFilter, workingHoursPerWeek int) (*UtilizationReport, error) {
+	compliancePolicy, err := a.compliancePolicyRepository.FindCompliancePolicy(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !compliancePolicy.AllowsPerUserEvaluation(principal) {
+		return nil, ErrPerUserEvaluationDisabled
+	}
+
+	activitiesFilter := toFilter(principal, filter)
+
+	deductions, err := a.breakDeductions(ctx, activitiesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	// breaks are deducted by username, so the users are anonymized after the deduction
+	usersFilter := *activitiesFilter
+	usersFilter.AnonymizeUsers = activitiesFilter.AnonymizeUsers && len(deductions) == 0
+
+	users, err := a.activityRepository.UtilizationReportByUser(ctx, &usersFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	trend, err := a.activityRepository.UtilizationReportByWeek(ctx, activitiesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	DeductBreaksFromUtilization(users, trend, deductions)
+	if activitiesFilter.AnonymizeUsers && !usersFilter.AnonymizeUsers {
+		anonymizeUtilizationReportItems(users)
+	}
+
+	team := &ActivityUtilizationReportItem{}
+	for _, user := range users {
+		team.DurationInMinutesTotal += user.DurationInMinutesTotal
+		team.BillableDurationInMinutesTotal += user.BillableDurationInMinutesTotal
+	}
+
+	days := activitiesFilter.End.Sub(activitiesFilter.Start).Hours() / 24
+	targetMinutesPerUser := 0
+	if days > 0 {
+		targetMinutesPerUser = int(math.Round(float64(workingHoursPerWeek*60) * days / 7))
+	}
+
+	return &UtilizationReport{
+		TargetMinutesPerUser: targetMinutesPerUser,
+		Team:                 team,
+		Users:                users,
+		Trend:                trend,
+	}, nil
+}
+
+// ProjectBurndown reports the cumulative tracked time of a project against its budget by day or week,
+// optionally extended by a forecast based on the average consumption of the recent periods
+func (a *ActitivityService) ProjectBurndown(ctx context.Context, principal *shared.Principal, project *Project, aggregateBy string, forecast bool, now time.Time) ([]*ProjectBurndownItem, error) {
+	reportItems, err := a.activityRepository.ProjectBurndownReport(ctx, principal.OrganizationID, project.ID, aggregateBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reportItems) == 0 {
+		return reportItems, nil
+	}
+
+	step := func(t time.Time) time.Time {
+		if aggregateBy == "week" {
+			return t.AddDate(0, 0, 7)
+		}
+		return t.AddDate(0, 0, 1)
+	}
+
+	// fill gaps with empty periods up to the current period
+	minutesByBucket := make(map[time.Time]int)
+	for _, reportItem := range reportItems {
+		bucket := truncateToBucket(reportItem.Date, aggregateBy)
+		minutesByBucket[bucket] += reportItem.DurationInMinutesTotal
+	}
+
+	first := truncateToBucket(reportItems[0].Date, aggregateBy)
+	last := truncateToBucket(now.In(first.Location()), aggregateBy)
+	if last.Before(first) {
+		last = truncateToBucket(reportItems[len(reportItems)-1].Date, aggregateBy)
+	}
+
+	var burndown []*ProjectBurndownItem
+	cumulativeMinutes := 0
+	for bucket := first; !bucket.After(last); bucket = step(bucket) {
+		cumulativeMinutes += minutesByBucket[bucket]
+		burndown = append(burndown, &ProjectBurndownItem{
+			Date:                   bucket,
+			DurationInMinutesTotal: minutesByBucket[bucket],
+			CumulativeMinutes:      cumulativeMinutes,
+			RemainingMinutes:       project.BudgetMinutes - cumulativeMinutes,
+		})
+	}
+
+	if !forecast || !project.HasBudget() {
+		return burndown, nil
+	}
+
+	// forecast with the average of the recent periods until the budget is consumed
+	recentPeriods := 4
+	if len(burndown) < recentPeriods {
+		recentPeriods = len(burndown)
+	}
+	recentMinutes := 0
+	for _, reportItem := range burndown[len(burndown)-recentPeriods:] {
+		recentMinutes += reportItem.DurationInMinutesTotal
+	}
+	averageMinutes := recentMinutes / recentPeriods
+	if averageMinutes <= 0 {
+		return burndown, nil
+	}
+
+	bucket := last
+	for i := 0; i < maxForecastPeriods && cumulativeMinutes < project.BudgetMinutes; i++ {
+		bucket = step(bucket)
+		cumulativeMinutes += averageMinutes
+		burndown = append(burndown, &ProjectBurndownItem{
+			Date:                   bucket,
+			DurationInMinutesTotal: averageMinutes,
+			CumulativeMinutes:      cumulativeMinutes,
+			RemainingMinutes:       project.BudgetMinutes - cumulativeMinutes,
+			Forecast:               true,
+		})
+	}
+
+	return burndown, nil
+}
+
+// CreateActivity creates a new activity, activities without project are assigned by the project assignment rules.
+// Unless duplicates are allowed an activity which is a near duplicate of an existing one is rejected.
+func (a *ActitivityService) CreateActivity(ctx context.Context, principal *shared.Principal, activity *Activity, allowDuplicates bool) (*Activity, error) {
+	return a.CreateActivityFromSource(ctx, principal, activity, "", allowDuplicates)
+}
+
+// CreateActivityFromSource creates a new activity coming from an integration like a webhook, the categorization rules
+// of the organization set its project before activities without project are assigned by the project assignment rules.
+func (a *ActitivityService) CreateActivityFromSource(ctx context.Context, principal *shared.Principal, activity *Activity, source ActivitySource, allowDuplicates bool) (*Activity, error) {
 	activity.ID = uuid.New()
 	activity.OrganizationID = principal.OrganizationID
 	activity.Username = principal.Username
 
+	err := a.applyLocationPolicy(ctx, principal, activity)
+	if err != nil {
+		return nil, err
+	}
+
+	if source != "" {
+		categorization, err := a.categorizationOf(ctx, principal)
+		if err != nil {
+			return nil, err
+		}
+
+		categorization.Categorize(activity, source)
+	}
+
+	if activity.ProjectID == uuid.Nil {
+		projectAssignment, err := a.projectAssignmentOf(ctx, principal)
+		if err != nil {
+			return nil, err
+		}
+
+		err = projectAssignment.Assign(activity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !allowDuplicates {
+		err = a.checkNearDuplicates(ctx, principal, []*Activity{activity})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var newActivity *Activity
-	err := a.repositoryTxer.InTx(
+	err = a.repositoryTxer.InTx(
 		ctx,
 		func(ctx context.Context) error {
 			a, err := a.activityRepository.InsertActivity(ctx, activity)
@@ -83,26 +296,148 @@ func (a *ActitivityService) CreateActivity(ctx context.Context, principal *share
 	return newActivity, nil
 }
 
-// DeleteActivityByID deletes an activity
-func (a *ActitivityService) DeleteActivityByID(ctx context.Context, principal *shared.Principal, activityID uuid.UUID) error {
-	if principal.HasRole("ROLE_ADMIN") {
-		return a.repositoryTxer.InTx(
-			ctx,
-			func(ctx context.Context) error {
-				return a.activityRepository.DeleteActivityByID(ctx, principal.OrganizationID, activityID)
-			},
-		)
+// CreateActivities creates many activities of the source at once with a bulk insert like for a sync of entries tracked offline,
+// each activity is categorized and assigned to a project like a single one. Activities rejected by the location policy or the
+// project assignment rules are not created, their errors are returned by the index of the activity. Unless duplicates are allowed
+// no activity is created if one is a near duplicate.
+func (a *ActitivityService) CreateActivities(ctx context.Context, principal *shared.Principal, activities []*Activity, source ActivitySource, allowDuplicates bool) (map[int]error, error) {
+	locationPolicy, err := a.locationPolicyRepository.FindLocationPolicy(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	categorization, err := a.categorizationOf(ctx, principal)
+	if err != nil {
+		return nil, err
 	}
-	return a.repositoryTxer.InTx(
+
+	var projectAssignment *ProjectAssignment
+	rejected := make(map[int]error)
+	accepted := make([]*Activity, 0, len(activities))
+	for i, activity := range activities {
+		activity.ID = uuid.New()
+		activity.OrganizationID = principal.OrganizationID
+		activity.Username = principal.Username
+
+		err := locationPolicy.Apply(activity)
+		if err != nil {
+			rejected[i] = err
+			continue
+		}
+
+		categorization.Categorize(activity, source)
+
+		if activity.ProjectID == uuid.Nil {
+			if projectAssignment == nil {
+				projectAssignment, err = a.projectAssignmentOf(ctx, principal)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			err = projectAssignment.Assign(activity)
+			if err != nil {
+				rejected[i] = err
+				continue
+			}
+		}
+
+		accepted = append(accepted, activity)
+	}
+
+	if len(accepted) == 0 {
+		return rejected, nil
+	}
+
+	if !allowDuplicates {
+		err = a.checkNearDuplicates(ctx, principal, accepted)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = a.repositoryTxer.InTx(
 		ctx,
 		func(ctx context.Context) error {
-			return a.activityRepository.DeleteActivityByIDAndUsername(ctx, principal.OrganizationID, activityID, principal.Username)
+			_, err := a.activityRepository.InsertActivities(ctx, accepted)
+			return err
 		},
 	)
+	if err != nil {
+		return nil, err
+	}
+	return rejected, nil
+}
+
+// checkNearDuplicates checks the new activities against the principal's existing activities around them
+func (a *ActitivityService) checkNearDuplicates(ctx context.Context, principal *shared.Principal, activities []*Activity) error {
+	if len(activities) == 0 {
+		return nil
+	}
+
+	existingActivities, err := a.activitiesAround(ctx, principal, activities)
+	if err != nil {
+		return err
+	}
+
+	return CheckNearDuplicates(activities, existingActivities)
+}
+
+// ReadNearDuplicates reads the principal's existing activities the activity is a near duplicate of,
+// like to warn about an activity created anyway
+func (a *ActitivityService) ReadNearDuplicates(ctx context.Context, principal *shared.Principal, activity *Activity) ([]*Activity, error) {
+	existingActivities, err := a.activitiesAround(ctx, principal, []*Activity{activity})
+	if err != nil {
+		return nil, err
+	}
+
+	var duplicates []*Activity
+	for _, existingActivity := range existingActivities {
+		if activity.IsNearDuplicateOf(existingActivity) {
+			duplicates = append(duplicates, existingActivity)
+		}
+	}
+	return duplicates, nil
+}
+
+// activitiesAround reads the principal's existing activities in the near duplicate window around the activities
+func (a *ActitivityService) activitiesAround(ctx context.Context, principal *shared.Principal, activities []*Activity) ([]*Activity, error) {
+	start, end := activities[0].Start, activities[0].End
+	for _, activity := range activities {
+		if activity.Start.Before(start) {
+			start = activity.Start
+		}
+		if activity.End.After(end) {
+			end = activity.End
+		}
+	}
+
+	streamFilter := &ActivitiesFilter{
+		Start:          start.Add(-nearDuplicateWindow),
+		End:            end.Add(nearDuplicateWindow),
+		SortOrder:      SortOrderAsc,
+		Username:       principal.Username,
+		OrganizationID: principal.OrganizationID,
+	}
+
+	var existingActivities []*Activity
+	err := a.activityRepository.StreamActivities(ctx, streamFilter, func(activity *Activity, project *Project) error {
+		existingActivities = append(existingActivities, activity)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existingActivities, nil
 }
 
 // UpdateActivity updates an activity
 func (a *ActitivityService) UpdateActivity(ctx context.Context, principal *shared.Principal, activity *Activity) (*Activity, error) {
+	err := a.applyLocationPolicy(ctx, principal, activity)
+	if err != nil {
+		return nil, err
+	}
+
 	var activityUpdate *Activity
 	if principal.HasRole("ROLE_ADMIN") {
 		err := a.repositoryTxer.InTx(
@@ -121,7 +456,7 @@ func (a *ActitivityService) UpdateActivity(ctx context.Context, principal *share
 		}
 		return activityUpdate, nil
 	}
-	err := a.repositoryTxer.InTx(
+	err = a.repositoryTxer.InTx(
 		ctx,
 		func(ctx context.Context) error {
 			a, err := a.activityRepository.UpdateActivityByUsername(ctx, principal.OrganizationID, activity, principal.Username)
@@ -138,15 +473,219 @@ func (a *ActitivityService) UpdateActivity(ctx context.Context, principal *share
 	return activityUpdate, nil
 }
 
-func (a *ActitivityService) WriteAsCSV(activities []*Activity, projects []*Project, w io.Writer) error {
-	csvWriter := csv.NewWriter(w)
-	csvWriter.Comma = ';'
+// ReadLocationPolicy reads the location policy of the principal's organization
+func (a *ActitivityService) ReadLocationPolicy(ctx context.Context, principal *shared.Principal) (*LocationPolicy, error) {
+	return a.locationPolicyRepository.FindLocationPolicy(ctx, principal.OrganizationID)
+}
 
-	defer csvWriter.Flush()
+// UpdateLocationPolicy sets the location policy of the principal's organization
+func (a *ActitivityService) UpdateLocationPolicy(ctx context.Context, principal *shared.Principal, policy *LocationPolicy) (*LocationPolicy, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	policy.OrganizationID = principal.OrganizationID
+	err := a.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return a.locationPolicyRepository.UpdateLocationPolicy(ctx, policy)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// ReadCompliancePolicy reads the compliance policy of the principal's organization
+func (a *ActitivityService) ReadCompliancePolicy(ctx context.Context, principal *shared.Principal) (*CompliancePolicy, error) {
+	return a.compliancePolicyRepository.FindCompliancePolicy(ctx, principal.OrganizationID)
+}
+
+// SuggestActivities suggests the projects and descriptions the principal is likely to track,
+// based on the activities of the principal in the recent past
+func (a *ActitivityService) SuggestActivities(ctx context.Context, principal *shared.Principal, query string, timeOfDay *TimeOfDay, now time.Time) ([]*ActivitySuggestion, error) {
+	activitiesFilter := &ActivitiesFilter{
+		Start:          now.Add(-activitySuggestionsLookback),
+		End:            now,
+		SortBy:         "start",
+		SortOrder:      SortOrderDesc,
+		Username:       principal.Username,
+		OrganizationID: principal.OrganizationID,
+	}
+	activitiesPaged, projects, err := a.activityRepository.FindActivities(ctx, activitiesFilter, &paged.PageParams{Page: 0, Size: activitySuggestionsHistorySize})
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestActivitiesOf(activitiesPaged.Activities, projects, query, timeOfDay, now), nil
+}
+
+// ReadBreakPolicy reads the break policy of the principal's organization
+func (a *ActitivityService) ReadBreakPolicy(ctx context.Context, principal *shared.Principal) (*BreakPolicy, error) {
+	return a.breakPolicyRepository.FindBreakPolicy(ctx, principal.OrganizationID)
+}
+
+// UpdateBreakPolicy sets the break policy of the principal's organization
+func (a *ActitivityService) UpdateBreakPolicy(ctx context.Context, principal *shared.Principal, policy *BreakPolicy) (*BreakPolicy, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	policy.OrganizationID = principal.OrganizationID
+	sortBreakDeductionRules(policy.Rules)
+	err := a.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return a.breakPolicyRepository.UpdateBreakPolicy(ctx, policy)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	headers := []string{"Date", "Start", "End", "Duration", "Project", "Description"}
+	return policy, nil
+}
 
-	err := csvWriter.Write(headers)
+// breakDeductions are the breaks deducted from the days of the activities of the filter, none without auto deduction
+func (a *ActitivityService) breakDeductions(ctx context.Context, activitiesFilter *ActivitiesFilter) ([]*BreakDeduction, error) {
+	breakPolicy, err := a.breakPolicyRepository.FindBreakPolicy(ctx, activitiesFilter.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !breakPolicy.AutoDeduction {
+		return nil, nil
+	}
+
+	// breaks depend on all activities of a day, not only the ones matching the query
+	streamFilter := *activitiesFilter
+	streamFilter.Query = nil
+
+	var activities []*Activity
+	err = a.activityRepository.StreamActivities(ctx, &streamFilter, func(activity *Activity, project *Project) error {
+		activities = append(activities, activity)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return breakPolicy.Deductions(activities), nil
+}
+
+// UpdateCompliancePolicy sets the compliance policy of the principal's organization
+func (a *ActitivityService) UpdateCompliancePolicy(ctx context.Context, principal *shared.Principal, policy *CompliancePolicy) (*CompliancePolicy, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	policy.OrganizationID = principal.OrganizationID
+	err := a.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return a.compliancePolicyRepository.UpdateCompliancePolicy(ctx, policy)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// LocationReport reports the tracked time per location
+func (a *ActitivityService) LocationReport(ctx context.Context, principal *shared.Principal, filter *ActivityFilter) ([]*ActivityLocationReportItem, error) {
+	activitiesFilter := toFilter(principal, filter)
+	return a.activityRepository.LocationReport(ctx, activitiesFilter)
+}
+
+// AnomalyReport finds the unusual activities of the organization in the filter's timespan
+func (a *ActitivityService) AnomalyReport(ctx context.Context, principal *shared.Principal, filter *ActivityFilter) ([]*ActivityAnomaly, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	// the weeks before the timespan decide whether a user usually works on weekends
+	streamFilter := toFilter(principal, filter)
+	streamFilter.Start = filter.Start().AddDate(0, 0, -weekendLookbackDays)
+	streamFilter.SortBy = ""
+	streamFilter.SortOrder = SortOrderAsc
+	streamFilter.Query = nil
+
+	var activities []*Activity
+	err := a.activityRepository.StreamActivities(ctx, streamFilter, func(activity *Activity, project *Project) error {
+		activities = append(activities, activity)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return DetectActivityAnomalies(activities, filter.Start(), filter.End()), nil
+}
+
+// projectAssignmentOf reads the project assignment rules of the organization and the default project of the principal
+func (a *ActitivityService) projectAssignmentOf(ctx context.Context, principal *shared.Principal) (*ProjectAssignment, error) {
+	rules, err := a.projectAssignmentRepository.FindProjectAssignmentRules(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultProjectID, err := a.projectAssignmentRepository.FindDefaultProject(ctx, principal.OrganizationID, principal.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectAssignment{
+		Rules:            rules,
+		DefaultProjectID: defaultProjectID,
+	}, nil
+}
+
+// categorizationOf reads the categorization rules of the organization in the order they are applied
+func (a *ActitivityService) categorizationOf(ctx context.Context, principal *shared.Principal) (*Categorization, error) {
+	rules, err := a.categorizationRuleRepository.FindCategorizationRules(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Categorization{
+		Rules: rules,
+	}, nil
+}
+
+func (a *ActitivityService) applyLocationPolicy(ctx context.Context, principal *shared.Principal, activity *Activity) error {
+	locationPolicy, err := a.locationPolicyRepository.FindLocationPolicy(ctx, principal.OrganizationID)
+	if err != nil {
+		return err
+	}
+	return locationPolicy.Apply(activity)
+}
+
+// ReadLinksOfActivities reads the links of the activities matching the filter for exports, grouped by activity
+func (a *ActitivityService) ReadLinksOfActivities(ctx context.Context, principal *shared.Principal, filter *ActivityFilter) (map[uuid.UUID][]*ActivityLink, error) {
+	return a.linksOfActivities(ctx, toFilter(principal, filter))
+}
+
+func (a *ActitivityService) linksOfActivities(ctx context.Context, filter *ActivitiesFilter) (map[uuid.UUID][]*ActivityLink, error) {
+	links, err := a.activityLinkRepository.FindActivityLinksOfActivities(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	linksByActivityID := make(map[uuid.UUID][]*ActivityLink)
+	for _, link := range links {
+		linksByActivityID[link.ActivityID] = append(linksByActivityID[link.ActivityID], link)
+	}
+	return linksByActivityID, nil
+}
+
+func (a *ActitivityService) WriteAsCSV(activities []*Activity, projects []*Project, links map[uuid.UUID][]*ActivityLink, w io.Writer) error {
+	csvWriter := newActivitiesCSVWriter(w)
+
+	defer csvWriter.Flush()
+
+	err := csvWriter.Write(activitiesCSVHeaders)
 	if err != nil {
 		return err
 	}
@@ -159,15 +698,7 @@ func (a *ActitivityService) WriteAsCSV(activities []*Activity, projects []*Proje
 
 	// write records for activities
 	for _, activity := range activities {
-		record := []string{
-			activity.Start.Format("2006-01-02"),
-			activity.Start.Format("15:04"),
-			activity.End.Format("15:04"),
-			activity.DurationFormatted(),
-			projectsById[activity.ProjectID].Title,
-			activity.Description,
-		}
-		err := csvWriter.Write(record)
+		err := csvWriter.Write(activityCSVRecord(activity, projectsById[activity.ProjectID], links[activity.ID]))
 		if err != nil {
 			return err
 		}
@@ -176,7 +707,69 @@ func (a *ActitivityService) WriteAsCSV(activities []*Activity, projects []*Proje
 	return nil
 }
 
-func (a *ActitivityService) WriteAsExcel(activities []*Activity, projects []*Project, w io.Writer) error {
+// StreamAsCSV writes all activities matching the filter as CSV while reading them,
+// flushing the writer periodically so large exports are not buffered in memory
+func (a *ActitivityService) StreamAsCSV(ctx context.Context, principal *shared.Principal, filter *ActivityFilter, w io.Writer) error {
+	activitiesFilter := toFilter(principal, filter)
+
+	links, err := a.linksOfActivities(ctx, activitiesFilter)
+	if err != nil {
+		return err
+	}
+
+	csvWriter := newActivitiesCSVWriter(w)
+	flush := func() error {
+		csvWriter.Flush()
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return csvWriter.Error()
+	}
+
+	err = csvWriter.Write(activitiesCSVHeaders)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = a.activityRepository.StreamActivities(ctx, activitiesFilter, func(activity *Activity, project *Project) error {
+		err := csvWriter.Write(activityCSVRecord(activity, project, links[activity.ID]))
+		if err != nil {
+			return err
+		}
+
+		count++
+		if count%csvFlushInterval == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+func newActivitiesCSVWriter(w io.Writer) *csv.Writer {
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Comma = ';'
+	return csvWriter
+}
+
+func activityCSVRecord(activity *Activity, project *Project, links []*ActivityLink) []string {
+	return []string{
+		activity.Start.Format("2006-01-02"),
+		activity.Start.Format("15:04"),
+		activity.End.Format("15:04"),
+		activity.DurationFormatted(),
+		project.Title,
+		shared.MarkdownToPlainText(activity.Description),
+		activityLinkURLs(links, " "),
+	}
+}
+
+func (a *ActitivityService) WriteAsExcel(activities []*Activity, projects []*Project, links map[uuid.UUID][]*ActivityLink, w io.Writer) error {
 	// prepare projects
 	projectsById := make(map[uuid.UUID]*Project)
 	for _, project := range projects {
@@ -196,6 +789,7 @@ func (a *ActitivityService) WriteAsExcel(activities []*Activity, projects []*Pro
 	_ = f.SetCellValue("Activities", "D1", "End")
 	_ = f.SetCellValue("Activities", "E1", "Hours")
 	_ = f.SetCellValue("Activities", "F1", "Description")
+	_ = f.SetCellValue("Activities", "G1", "Links")
 
 	style, _ := f.NewStyle(&excelize.Style{
 		Font: &excelize.Font{
@@ -210,7 +804,7 @@ func (a *ActitivityService) WriteAsExcel(activities []*Activity, projects []*Pro
 	styleDuration, _ := f.NewStyle(&excelize.Style{
 		NumFmt: 4,
 	})
-	_ = f.SetCellStyle("Activities", "A1", "F1", style)
+	_ = f.SetCellStyle("Activities", "A1", "G1", style)
 
 	descriptionStyle, _ := f.NewStyle(&excelize.Style{
 		Alignment: &excelize.Alignment{
@@ -231,19 +825,62 @@ func (a *ActitivityService) WriteAsExcel(activities []*Activity, projects []*Pro
 		_ = f.SetCellValue("Activities", fmt.Sprintf("E%v", idx), duration)
 		_ = f.SetCellStyle("Activities", fmt.Sprintf("E%v", idx), fmt.Sprintf("E%v", idx), styleDuration)
 
-		_ = f.SetCellValue("Activities", fmt.Sprintf("F%v", idx), activity.Description)
+		_ = f.SetCellValue("Activities", fmt.Sprintf("F%v", idx), shared.MarkdownToPlainText(activity.Description))
 		_ = f.SetCellStyle("Activities", fmt.Sprintf("F%v", idx), fmt.Sprintf("F%v", idx), descriptionStyle)
+
+		_ = f.SetCellValue("Activities", fmt.Sprintf("G%v", idx), activityLinkURLs(links[activity.ID], "\n"))
+		_ = f.SetCellStyle("Activities", fmt.Sprintf("G%v", idx), fmt.Sprintf("G%v", idx), descriptionStyle)
 	}
 
 	return f.Write(w)
 }
 
+// WriteProjectChart writes the distribution of the tracked time over the projects as pie chart
+func (a *ActitivityService) WriteProjectChart(reportItems []*ActivityProjectReportItem, w io.Writer) error {
+	values := make([]chart.Value, len(reportItems))
+	for i, reportItem := range reportItems {
+		values[i] = chart.Value{
+			Label: reportItem.ProjectTitle,
+			Value: float64(reportItem.DurationInMinutesTotal) / 60.0,
+		}
+	}
+	return chart.Pie(w, "Hours by Project", values)
+}
+
+// WriteWeeklyChart writes the tracked time per week as bar chart
+func (a *ActitivityService) WriteWeeklyChart(reportItems []*ActivityTimeReportItem, w io.Writer) error {
+	values := make([]chart.Value, len(reportItems))
+	for i, reportItem := range reportItems {
+		// report items are sorted descending, the chart shows them in ascending order
+		values[len(reportItems)-1-i] = chart.Value{
+			Label: fmt.Sprintf("%v/%v", reportItem.Week, reportItem.Year),
+			Value: float64(reportItem.DurationInMinutesTotal) / 60.0,
+		}
+	}
+	return chart.Bar(w, "Hours by Week", values)
+}
+
+// WriteUtilizationChart writes the weekly trend of the billable percentage as line chart
+func (a *ActitivityService) WriteUtilizationChart(utilizationReport *UtilizationReport, w io.Writer) error {
+	values := make([]chart.Value, len(utilizationReport.Trend))
+	for i, reportItem := range utilizationReport.Trend {
+		values[i] = chart.Value{
+			Label: fmt.Sprintf("%v/%v", reportItem.Week, reportItem.Year),
+			Value: reportItem.BillablePercentage(),
+		}
+	}
+	return chart.Line(w, "Billable Percentage by Week", values)
+}
+
 func toFilter(principal *shared.Principal, filter *ActivityFilter) *ActivitiesFilter {
 	activitiesFilter := &ActivitiesFilter{
 		Start:          filter.Start(),
 		End:            filter.End(),
 		SortBy:         filter.sortBy,
 		SortOrder:      filter.sortOrder,
+		Query:          filter.query,
+		EmbedUsers:     filter.Embeds(ActivityEmbedUser),
+		AnonymizeUsers: filter.Anonymizes(),
 		OrganizationID: principal.OrganizationID,
 	}
 
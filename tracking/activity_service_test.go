@@ -3,6 +3,7 @@ package tracking
 import (
 	"bytes"
 	"context"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/baralga/shared"
 	"github.com/google/uuid"
 	"github.com/matryer/is"
+	"github.com/pkg/errors"
 )
 
 func TestTimeReportsByDay(t *testing.T) {
@@ -18,7 +20,9 @@ func TestTimeReportsByDay(t *testing.T) {
 
 	activityRepository := NewInMemActivityRepository()
 	a := &ActitivityService{
-		activityRepository: activityRepository,
+		activityRepository:       activityRepository,
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		breakPolicyRepository:    NewInMemBreakPolicyRepository(),
 	}
 
 	start1, _ := time.Parse(time.RFC3339, "2021-01-01T10:00:00.000Z")
@@ -65,7 +69,9 @@ func TestTimeReportsByWeek(t *testing.T) {
 
 	activityRepository := NewInMemActivityRepository()
 	a := &ActitivityService{
-		activityRepository: activityRepository,
+		activityRepository:       activityRepository,
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		breakPolicyRepository:    NewInMemBreakPolicyRepository(),
 	}
 
 	start1, _ := time.Parse(time.RFC3339, "2021-01-01T10:00:00.000Z")
@@ -112,7 +118,9 @@ func TestTimeReportsByMonth(t *testing.T) {
 
 	activityRepository := NewInMemActivityRepository()
 	a := &ActitivityService{
-		activityRepository: activityRepository,
+		activityRepository:       activityRepository,
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		breakPolicyRepository:    NewInMemBreakPolicyRepository(),
 	}
 
 	start1, _ := time.Parse(time.RFC3339, "2021-01-01T10:00:00.000Z")
@@ -159,7 +167,9 @@ func TestTimeReportsByQuarter(t *testing.T) {
 
 	activityRepository := NewInMemActivityRepository()
 	a := &ActitivityService{
-		activityRepository: activityRepository,
+		activityRepository:       activityRepository,
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		breakPolicyRepository:    NewInMemBreakPolicyRepository(),
 	}
 
 	start1, _ := time.Parse(time.RFC3339, "2021-01-01T10:00:00.000Z")
@@ -206,7 +216,9 @@ func TestProjectReports(t *testing.T) {
 
 	activityRepository := NewInMemActivityRepository()
 	a := &ActitivityService{
-		activityRepository: activityRepository,
+		activityRepository:       activityRepository,
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		activityLinkRepository:   NewInMemActivityLinkRepository(),
 	}
 
 	projectId1 := uuid.New()
@@ -260,9 +272,11 @@ func TestWriteAsCSV(t *testing.T) {
 	end, _ := time.Parse(time.RFC3339, "2021-11-12T11:30:00.000Z")
 
 	activity := &Activity{
-		Start:     start,
-		End:       end,
-		ProjectID: uuid.New(),
+		ID:          uuid.New(),
+		Start:       start,
+		End:         end,
+		Description: "**Release** of [v2](https://baralga.com/v2)",
+		ProjectID:   uuid.New(),
 	}
 	activities := []*Activity{activity}
 
@@ -274,7 +288,14 @@ func TestWriteAsCSV(t *testing.T) {
 
 	var buffer bytes.Buffer
 
-	err := a.WriteAsCSV(activities, projects, &buffer)
+	links := map[uuid.UUID][]*ActivityLink{
+		activity.ID: {
+			{URL: "https://example.com/tickets/1"},
+			{URL: "https://example.com/tickets/2"},
+		},
+	}
+
+	err := a.WriteAsCSV(activities, projects, links, &buffer)
 
 	is.NoErr(err)
 	csv := buffer.String()
@@ -283,6 +304,42 @@ func TestWriteAsCSV(t *testing.T) {
 	is.True(strings.Contains(csv, "My Project"))
 	is.True(strings.Contains(csv, "11:00"))
 	is.True(strings.Contains(csv, "11:30"))
+	is.True(strings.Contains(csv, "Release of v2 (https://baralga.com/v2)"))
+	is.True(strings.Contains(csv, ";Links\n"))
+	is.True(strings.Contains(csv, "https://example.com/tickets/1 https://example.com/tickets/2"))
+}
+
+func TestStreamAsCSV(t *testing.T) {
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActitivityService{
+		activityLinkRepository:   NewInMemActivityLinkRepository(),
+		activityRepository:       activityRepository,
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+	}
+
+	start, _ := time.Parse(time.RFC3339, "2021-11-12T11:00:00.000Z")
+	end, _ := time.Parse(time.RFC3339, "2021-11-12T11:30:00.000Z")
+
+	activityRepository.activities = nil
+	for i := 0; i < csvFlushInterval+1; i++ {
+		activityRepository.activities = append(activityRepository.activities, &Activity{
+			Start:     start,
+			End:       end,
+			ProjectID: shared.ProjectIDSample,
+		})
+	}
+
+	httpRec := httptest.NewRecorder()
+
+	err := a.StreamAsCSV(context.Background(), &shared.Principal{}, &ActivityFilter{Timespan: TimespanMonth, start: start}, httpRec)
+
+	is.NoErr(err)
+	is.True(httpRec.Flushed)
+	csv := httpRec.Body.String()
+	is.Equal(strings.Count(csv, "\n"), csvFlushInterval+2)
+	is.True(strings.Contains(csv, "My Project"))
 }
 
 func TestWriteAsExcel(t *testing.T) {
@@ -308,7 +365,377 @@ func TestWriteAsExcel(t *testing.T) {
 
 	var buffer bytes.Buffer
 
-	err := a.WriteAsExcel(activities, projects, &buffer)
+	err := a.WriteAsExcel(activities, projects, map[uuid.UUID][]*ActivityLink{}, &buffer)
 
 	is.NoErr(err)
 }
+
+func TestUtilizationReport(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActitivityService{
+		activityRepository:         activityRepository,
+		locationPolicyRepository:   NewInMemLocationPolicyRepository(),
+		compliancePolicyRepository: NewInMemCompliancePolicyRepository(),
+		breakPolicyRepository:      NewInMemBreakPolicyRepository(),
+	}
+
+	start1, _ := time.Parse(time.RFC3339, "2021-01-04T10:00:00.000Z")
+	end1, _ := time.Parse(time.RFC3339, "2021-01-04T11:00:00.000Z")
+
+	start2, _ := time.Parse(time.RFC3339, "2021-01-05T10:00:00.000Z")
+	end2, _ := time.Parse(time.RFC3339, "2021-01-05T12:00:00.000Z")
+
+	activityRepository.activities = []*Activity{
+		{
+			Start:    start1,
+			End:      end1,
+			Username: "user1",
+		},
+		{
+			Start:    start2,
+			End:      end2,
+			Username: "user2",
+		},
+	}
+
+	principal := &shared.Principal{}
+	filter := &ActivityFilter{
+		Timespan: TimespanWeek,
+		start:    start1,
+	}
+
+	// Act
+	utilizationReport, err := a.UtilizationReport(context.Background(), principal, filter, 40)
+
+	// Assert
+	is.NoErr(err)
+	is.Equal(len(utilizationReport.Users), 2)
+	is.Equal(utilizationReport.Team.DurationInMinutesTotal, 180)
+	is.Equal(utilizationReport.TargetMinutesPerUser, 40*60)
+	is.Equal(len(utilizationReport.Trend), 2)
+}
+
+func TestUtilizationReportWithBreakDeduction(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	breakPolicyRepository := NewInMemBreakPolicyRepository()
+	a := &ActitivityService{
+		repositoryTxer:             shared.NewInMemRepositoryTxer(),
+		activityRepository:         activityRepository,
+		compliancePolicyRepository: NewInMemCompliancePolicyRepository(),
+		breakPolicyRepository:      breakPolicyRepository,
+	}
+
+	start1, _ := time.Parse(time.RFC3339, "2021-01-04T08:00:00.000Z")
+	end1, _ := time.Parse(time.RFC3339, "2021-01-04T15:00:00.000Z")
+
+	activityRepository.activities = []*Activity{
+		{
+			Start:    start1,
+			End:      end1,
+			Username: "user1",
+		},
+	}
+
+	admin := &shared.Principal{Roles: []string{"ROLE_ADMIN"}}
+	_, err := a.UpdateBreakPolicy(context.Background(), admin, &BreakPolicy{
+		AutoDeduction: true,
+		Rules:         []*BreakDeductionRule{{AfterMinutes: 360, BreakMinutes: 30}},
+	})
+	is.NoErr(err)
+
+	filter := &ActivityFilter{
+		Timespan:  TimespanWeek,
+		start:     start1,
+		anonymize: true,
+	}
+
+	// Act
+	utilizationReport, err := a.UtilizationReport(context.Background(), admin, filter, 40)
+
+	// Assert
+	is.NoErr(err)
+	is.Equal(utilizationReport.Users[0].Username, "User A")
+	is.Equal(utilizationReport.Users[0].DurationInMinutesTotal, 390)
+	is.Equal(utilizationReport.Team.DurationInMinutesTotal, 390)
+	is.Equal(utilizationReport.Trend[0].DurationInMinutesTotal, 390)
+
+	timeReports, err := a.TimeReports(context.Background(), admin, filter, "day")
+	is.NoErr(err)
+	// in memory repository reports an hour per activity
+	is.Equal(timeReports[0].DurationInMinutesTotal, 30)
+}
+
+func TestProjectBurndown(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActitivityService{
+		activityRepository:       activityRepository,
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+	}
+
+	start1, _ := time.Parse(time.RFC3339, "2021-01-04T10:00:00.000Z")
+	end1, _ := time.Parse(time.RFC3339, "2021-01-04T12:00:00.000Z")
+
+	start2, _ := time.Parse(time.RFC3339, "2021-01-06T10:00:00.000Z")
+	end2, _ := time.Parse(time.RFC3339, "2021-01-06T12:00:00.000Z")
+
+	activityRepository.activities = []*Activity{
+		{
+			Start:     start1,
+			End:       end1,
+			ProjectID: shared.ProjectIDSample,
+		},
+		{
+			Start:     start2,
+			End:       end2,
+			ProjectID: shared.ProjectIDSample,
+		},
+	}
+
+	project := &Project{
+		ID:            shared.ProjectIDSample,
+		BudgetMinutes: 600,
+	}
+	now, _ := time.Parse(time.RFC3339, "2021-01-06T18:00:00.000Z")
+
+	// Act
+	burndown, err := a.ProjectBurndown(context.Background(), &shared.Principal{}, project, "day", false, now)
+
+	// Assert
+	is.NoErr(err)
+	is.Equal(len(burndown), 3)
+	is.Equal(burndown[1].DurationInMinutesTotal, 0)
+	is.Equal(burndown[2].CumulativeMinutes, 240)
+	is.Equal(burndown[2].RemainingMinutes, 360)
+}
+
+func TestProjectBurndownWithForecast(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActitivityService{
+		activityRepository:       activityRepository,
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+	}
+
+	start, _ := time.Parse(time.RFC3339, "2021-01-06T10:00:00.000Z")
+	end, _ := time.Parse(time.RFC3339, "2021-01-06T12:00:00.000Z")
+
+	activityRepository.activities = []*Activity{
+		{
+			Start:     start,
+			End:       end,
+			ProjectID: shared.ProjectIDSample,
+		},
+	}
+
+	project := &Project{
+		ID:            shared.ProjectIDSample,
+		BudgetMinutes: 600,
+	}
+
+	// Act
+	burndown, err := a.ProjectBurndown(context.Background(), &shared.Principal{}, project, "week", true, start)
+
+	// Assert
+	is.NoErr(err)
+	is.Equal(len(burndown), 5)
+	is.Equal(burndown[0].Date.Weekday(), time.Monday)
+	is.True(!burndown[0].Forecast)
+	is.True(burndown[4].Forecast)
+	is.Equal(burndown[4].RemainingMinutes, 0)
+}
+
+func TestCreateActivities(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActitivityService{
+		repositoryTxer:               shared.NewInMemRepositoryTxer(),
+		activityRepository:           activityRepository,
+		locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+		categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+	}
+
+	principal := &shared.Principal{
+		Username:       "user1",
+		OrganizationID: shared.OrganizationIDSample,
+	}
+	activities := []*Activity{
+		{ProjectID: shared.ProjectIDSample},
+		{ProjectID: shared.ProjectIDSample},
+	}
+
+	// Act
+	rejected, err := a.CreateActivities(context.Background(), principal, activities, ActivitySourceSync, true)
+
+	// Assert
+	is.NoErr(err)
+	is.Equal(len(rejected), 0)
+	is.Equal(len(activityRepository.activities), 3)
+	is.True(activities[0].ID != uuid.Nil)
+	is.Equal(activities[1].Username, "user1")
+}
+
+func TestCreateActivityWithRequiredLocation(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	locationPolicyRepository := NewInMemLocationPolicyRepository()
+	locationPolicyRepository.policies[shared.OrganizationIDSample] = &LocationPolicy{
+		OrganizationID: shared.OrganizationIDSample,
+		Mode:           LocationPolicyRequired,
+	}
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActitivityService{
+		repositoryTxer:               shared.NewInMemRepositoryTxer(),
+		activityRepository:           activityRepository,
+		locationPolicyRepository:     locationPolicyRepository,
+		categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+	}
+
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	countBefore := len(activityRepository.activities)
+
+	// Act
+	_, errWithout := a.CreateActivity(context.Background(), principal, &Activity{ProjectID: shared.ProjectIDSample}, false)
+	_, errWith := a.CreateActivity(context.Background(), principal, &Activity{ProjectID: shared.ProjectIDSample, Location: LocationOffice}, true)
+	rejected, errBulk := a.CreateActivities(context.Background(), principal, []*Activity{{ProjectID: shared.ProjectIDSample}, {ProjectID: shared.ProjectIDSample, Location: LocationOffice}}, ActivitySourceSync, true)
+
+	// Assert
+	is.Equal(errWithout, ErrLocationRequired)
+	is.NoErr(errWith)
+	is.NoErr(errBulk)
+	is.Equal(rejected[0], ErrLocationRequired)
+	is.Equal(len(rejected), 1)
+	is.Equal(len(activityRepository.activities), countBefore+2)
+}
+
+func TestUpdateLocationPolicy(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	a := &ActitivityService{
+		repositoryTxer:           shared.NewInMemRepositoryTxer(),
+		activityRepository:       NewInMemActivityRepository(),
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+	}
+
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}}
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_USER"}}
+
+	// Act
+	_, errUser := a.UpdateLocationPolicy(context.Background(), user, &LocationPolicy{Mode: LocationPolicyOff})
+	_, errAdmin := a.UpdateLocationPolicy(context.Background(), admin, &LocationPolicy{Mode: LocationPolicyOff})
+	policy, errRead := a.ReadLocationPolicy(context.Background(), user)
+
+	// Assert
+	is.Equal(errUser, shared.ErrForbidden)
+	is.NoErr(errAdmin)
+	is.NoErr(errRead)
+	is.Equal(policy.Mode, LocationPolicyOff)
+}
+
+func TestLocationReport(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActitivityService{
+		activityRepository:       activityRepository,
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+	}
+
+	start, _ := time.Parse(time.RFC3339, "2021-01-01T10:00:00.000Z")
+	activityRepository.activities = []*Activity{
+		{Start: start, End: start.Add(time.Hour), Location: LocationOffice},
+		{Start: start, End: start.Add(30 * time.Minute), Location: LocationOffice},
+		{Start: start, End: start.Add(time.Hour)},
+	}
+
+	// Act
+	reportItems, err := a.LocationReport(context.Background(), &shared.Principal{}, &ActivityFilter{})
+
+	// Assert
+	is.NoErr(err)
+	is.Equal(len(reportItems), 2)
+	is.Equal(reportItems[0].Location, LocationOffice)
+	is.Equal(reportItems[0].DurationInMinutesTotal, 90)
+	is.Equal(reportItems[1].Location, LocationUnspecified)
+}
+
+func TestAnomalyReport(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActitivityService{
+		activityRepository: activityRepository,
+	}
+
+	start, _ := time.Parse(time.RFC3339, "2021-10-04T10:00:00.000Z")
+	activityRepository.activities = []*Activity{
+		{ID: uuid.New(), Start: start, End: start, Username: "user1"},
+		{ID: uuid.New(), Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Username: "user1"},
+	}
+
+	filter := &ActivityFilter{
+		Timespan: TimespanMonth,
+		start:    time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}}
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_USER"}}
+
+	// Act
+	anomalies, err := a.AnomalyReport(context.Background(), admin, filter)
+	_, errUser := a.AnomalyReport(context.Background(), user, filter)
+
+	// Assert
+	is.NoErr(err)
+	is.Equal(len(anomalies), 1)
+	is.Equal(anomalies[0].Type, ActivityAnomalyZeroDuration)
+	is.Equal(errUser, shared.ErrForbidden)
+}
+
+func TestCreateActivityNearDuplicate(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ActitivityService{
+		repositoryTxer:               shared.NewInMemRepositoryTxer(),
+		activityRepository:           activityRepository,
+		locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+		categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+	}
+
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	start, _ := time.Parse(time.RFC3339, "2021-10-04T10:00:00.000Z")
+	newActivity := func() *Activity {
+		return &Activity{ProjectID: shared.ProjectIDSample, Start: start, End: start.Add(time.Hour), Description: "Meeting"}
+	}
+
+	// Act
+	_, errFirst := a.CreateActivity(context.Background(), principal, newActivity(), false)
+	_, errDuplicate := a.CreateActivity(context.Background(), principal, newActivity(), false)
+	_, errAllowed := a.CreateActivity(context.Background(), principal, newActivity(), true)
+	_, errBulk := a.CreateActivities(context.Background(), principal, []*Activity{newActivity()}, ActivitySourceSync, false)
+
+	// Assert
+	is.NoErr(errFirst)
+	is.True(errors.Is(errDuplicate, ErrActivityNearDuplicate))
+	is.NoErr(errAllowed)
+	is.True(errors.Is(errBulk, ErrActivityNearDuplicate))
+	is.Equal(len(activityRepository.activities), 3)
+}
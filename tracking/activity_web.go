@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/baralga/shared"
@@ -30,6 +31,9 @@ type activityFormModel struct {
 	StartTime   string `validate:"required,min=5,max=5"`
 	EndTime     string `validate:"required,min=5,max=5"`
 	Description string `validate:"min=0,max=500"`
+	Location    string `validate:"omitempty,oneof=office home client-site"`
+	Latitude    string
+	Longitude   string
 }
 
 type activityTrackFormModel struct {
@@ -67,6 +71,9 @@ func (a *ActivityWebHandlers) RegisterProtected(r chi.Router) {
 	r.Post("/activities/validate-start-time", a.HandleStartTimeValidation())
 	r.Post("/activities/validate-end-time", a.HandleEndTimeValidation())
 	r.Get("/activities/{activity-id}/edit", a.HandleActivityEditPage())
+	r.Get("/activities/{activity-id}/row", a.HandleActivityRow())
+	r.Get("/activities/{activity-id}/inline-edit", a.HandleActivityInlineEdit())
+	r.Post("/activities/{activity-id}/inline-edit", a.HandleActivityInlineForm())
 	r.Post("/activities/new", a.HandleActivityForm())
 	r.Post("/activities/{activity-id}", a.HandleActivityForm())
 	r.Post("/activities/track", a.HandleActivityTrackForm())
@@ -126,6 +133,8 @@ func (a *ActivityWebHandlers) HandleTrackingPage() http.HandlerFunc {
 		pageContext := &shared.PageContext{
 			Principal:   principal,
 			CurrentPath: r.URL.Path,
+			CSRFToken:   csrf.Token(r),
+			Appearance:  shared.AppearanceOf(r.Context()),
 		}
 
 		formModel := activityTrackFormModel{Action: "start"}
@@ -155,6 +164,8 @@ func (a *ActivityWebHandlers) HandleActivityAddPage() http.HandlerFunc {
 		pageContext := &shared.PageContext{
 			Principal:   principal,
 			CurrentPath: r.URL.Path,
+			CSRFToken:   csrf.Token(r),
+			Appearance:  shared.AppearanceOf(r.Context()),
 			Title:       "Add Activity",
 		}
 		activityFormModel := newActivityFormModel()
@@ -207,6 +218,8 @@ func (a *ActivityWebHandlers) HandleActivityEditPage() http.HandlerFunc {
 		pageContext := &shared.PageContext{
 			Principal:   principal,
 			CurrentPath: r.URL.Path,
+			CSRFToken:   csrf.Token(r),
+			Appearance:  shared.AppearanceOf(r.Context()),
 			Title:       "Edit Activity",
 		}
 		formModel := mapActivityToForm(*activity)
@@ -305,7 +318,7 @@ func (a *ActivityWebHandlers) HandleActivityTrackForm() http.HandlerFunc {
 				return
 			}
 
-			_, err := activityService.CreateActivity(r.Context(), principal, activityToCreate)
+			_, err := activityService.CreateActivity(r.Context(), principal, activityToCreate, true)
 			if err != nil {
 				shared.RenderProblemHTML(w, isProduction, err)
 				return
@@ -385,7 +398,7 @@ func (a *ActivityWebHandlers) HandleActivityForm() http.HandlerFunc {
 		}
 
 		if uuid.Nil == activityNew.ID {
-			_, err = activityService.CreateActivity(r.Context(), principal, activityNew)
+			_, err = activityService.CreateActivity(r.Context(), principal, activityNew, true)
 		} else {
 			_, err = activityService.UpdateActivity(r.Context(), principal, activityNew)
 		}
@@ -398,6 +411,116 @@ func (a *ActivityWebHandlers) HandleActivityForm() http.HandlerFunc {
 	}
 }
 
+// HandleActivityRow renders the row of an activity, used to swap back from the inline edit form
+func (a *ActivityWebHandlers) HandleActivityRow() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityRepository := a.activityRepository
+	return func(w http.ResponseWriter, r *http.Request) {
+		activityIDParam := chi.URLParam(r, "activity-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, err := uuid.Parse(activityIDParam)
+		if err != nil {
+			shared.RenderProblemHTML(w, isProduction, err)
+			return
+		}
+
+		activity, err := activityRepository.FindActivityByID(r.Context(), activityID, principal.OrganizationID)
+		if errors.Is(err, ErrActivityNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			shared.RenderProblemHTML(w, isProduction, err)
+			return
+		}
+
+		a.renderActivityRow(w, r, principal, isProduction, activity)
+	}
+}
+
+// HandleActivityInlineEdit renders the inline edit form which is swapped in place of the activity row
+func (a *ActivityWebHandlers) HandleActivityInlineEdit() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityRepository := a.activityRepository
+	return func(w http.ResponseWriter, r *http.Request) {
+		activityIDParam := chi.URLParam(r, "activity-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, err := uuid.Parse(activityIDParam)
+		if err != nil {
+			shared.RenderProblemHTML(w, isProduction, err)
+			return
+		}
+
+		activity, err := activityRepository.FindActivityByID(r.Context(), activityID, principal.OrganizationID)
+		if errors.Is(err, ErrActivityNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			shared.RenderProblemHTML(w, isProduction, err)
+			return
+		}
+
+		a.renderActivityInlineForm(w, r, principal, isProduction, mapActivityToForm(*activity), "", nil)
+	}
+}
+
+// HandleActivityInlineForm updates the activity of the inline edit form. The updated row is rendered on success,
+// otherwise the form is rendered again with the validation errors.
+func (a *ActivityWebHandlers) HandleActivityInlineForm() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := validator.New()
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		activityIDParam := chi.URLParam(r, "activity-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, err := uuid.Parse(activityIDParam)
+		if err != nil {
+			shared.RenderProblemHTML(w, isProduction, err)
+			return
+		}
+
+		var formModel activityFormModel
+		err = r.ParseForm()
+		if err == nil {
+			err = schema.NewDecoder().Decode(&formModel, r.PostForm)
+		}
+		formModel.ID = activityID.String()
+		if err != nil {
+			a.renderActivityInlineForm(w, r, principal, isProduction, formModel, "Activity could not be read.", nil)
+			return
+		}
+
+		err = validator.Struct(formModel)
+		if err != nil {
+			a.renderActivityInlineForm(w, r, principal, isProduction, formModel, "", activityFormFieldErrors(err))
+			return
+		}
+
+		activityUpdate, err := mapFormToActivity(formModel)
+		if err != nil {
+			a.renderActivityInlineForm(w, r, principal, isProduction, formModel, "Invalid date or time.", nil)
+			return
+		}
+
+		activity, err := activityService.UpdateActivity(r.Context(), principal, activityUpdate)
+		if errors.Is(err, ErrActivityNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			shared.RenderProblemHTML(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("HX-Trigger", "baralga__activities-changed")
+		a.renderActivityRow(w, r, principal, isProduction, activity)
+	}
+}
+
 func (a *ActivityWebHandlers) HandleStartTimeValidation() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		err := r.ParseForm()
@@ -440,7 +563,9 @@ func TrackingPage(pageContext *shared.PageContext, formModel activityTrackFormMo
 	return shared.Page(
 		"Track Activities",
 		pageContext.CurrentPath,
+		pageContext.Appearance,
 		[]g.Node{
+			shared.CSRFHeaders(pageContext.CSRFToken),
 			shared.Navbar(pageContext),
 			Div(
 				Class("container"),
@@ -629,56 +754,230 @@ func ActivitiesSumByDayView(activitiesPage *ActivitiesPaged, projects []*Project
 					),
 				),
 				g.Group(g.Map(activities, func(activity *Activity) g.Node {
-					return Div(
-						Class("d-flex justify-content-between mb-2"),
-						ghx.Target(fmt.Sprintf("#%v", activityCardID)),
-						TitleAttr(activity.Description),
-						Span(
-							Class("flex-fill"),
-							g.Text(time_utils.FormatTime(activity.Start)+" - "+time_utils.FormatTime(activity.End)),
-						),
-						Span(
-							Class("flex-fill"),
-							g.Text(projectsById[activity.ProjectID].Title),
-						),
-						Span(
-							Class("flex-fill text-end pe-3"),
-							g.Text(activity.DurationFormatted()),
-						),
-						Div(
-							A(
-								ghx.Get(fmt.Sprintf("/activities/%v/edit", activity.ID)),
-								ghx.Target("#baralga__main_content_modal_content"),
-								ghx.Swap("outerHTML"),
-
-								Class("btn btn-outline-secondary btn-sm"),
-								I(Class("bi-pen")),
-							),
-							A(
-								ghx.Confirm(
-									fmt.Sprintf(
-										"Do you really want to delete the activity from %v on %v?",
-										time_utils.FormatTime(activity.Start),
-										activity.Start.Format("Monday"),
-									),
-								),
-								ghx.Delete(fmt.Sprintf("/api/activities/%v", activity.ID)),
-								Class("btn btn-outline-secondary btn-sm ms-1"),
-								I(Class("bi-trash2")),
-							),
-						),
-					)
+					return ActivityRowView(activity, projectsById[activity.ProjectID])
 				})),
 			),
 		)
 	}))
 }
 
+// ActivityRowView renders a single activity of a day, the row is swapped with the inline edit form and back
+func ActivityRowView(activity *Activity, project *Project) g.Node {
+	return Div(
+		ID(activityRowID(activity.ID)),
+		Class("d-flex justify-content-between mb-2"),
+		TitleAttr(shared.MarkdownToPlainText(activity.Description)),
+		Span(
+			Class("flex-fill"),
+			g.Text(time_utils.FormatTime(activity.Start)+" - "+time_utils.FormatTime(activity.End)),
+		),
+		Span(
+			Class("flex-fill"),
+			g.Text(project.Title),
+		),
+		Span(
+			Class("flex-fill text-end pe-3"),
+			g.Text(activity.DurationFormatted()),
+		),
+		Div(
+			A(
+				ghx.Get(fmt.Sprintf("/activities/%v/inline-edit", activity.ID)),
+				ghx.Target(fmt.Sprintf("#%v", activityRowID(activity.ID))),
+				ghx.Swap("outerHTML"),
+
+				Class("btn btn-outline-secondary btn-sm"),
+				I(Class("bi-pen")),
+			),
+			A(
+				ghx.Confirm(
+					fmt.Sprintf(
+						"Do you really want to delete the activity from %v on %v?",
+						time_utils.FormatTime(activity.Start),
+						activity.Start.Format("Monday"),
+					),
+				),
+				ghx.Delete(fmt.Sprintf("/api/activities/%v", activity.ID)),
+				ghx.Swap("none"),
+				Class("btn btn-outline-secondary btn-sm ms-1"),
+				I(Class("bi-trash2")),
+			),
+		),
+	)
+}
+
+// ActivityInlineForm renders the form to edit an activity in place of its row, field errors are shown with their inputs
+func ActivityInlineForm(formModel activityFormModel, projects *ProjectsPaged, errorMessage string, fieldErrors map[string]string) g.Node {
+	rowID := activityRowID(uuid.MustParse(formModel.ID))
+	return FormEl(
+		ID(rowID),
+		Class("mb-2"),
+
+		ghx.Post(fmt.Sprintf("/activities/%v/inline-edit", formModel.ID)),
+		ghx.Target("this"),
+		ghx.Swap("outerHTML"),
+
+		g.If(
+			errorMessage != "",
+			Div(
+				Class("alert alert-danger p-1 mb-1"),
+				Role("alert"),
+				Span(g.Text(errorMessage)),
+			),
+		),
+		Input(
+			Type("hidden"),
+			Name("CSRFToken"),
+			Value(formModel.CSRFToken),
+		),
+		Input(
+			Type("hidden"),
+			Name("Location"),
+			Value(formModel.Location),
+		),
+		g.If(formModel.Latitude != "" && formModel.Longitude != "",
+			g.Group([]g.Node{
+				Input(
+					Type("hidden"),
+					Name("Latitude"),
+					Value(formModel.Latitude),
+				),
+				Input(
+					Type("hidden"),
+					Name("Longitude"),
+					Value(formModel.Longitude),
+				),
+			}),
+		),
+		Div(
+			Class("d-flex gap-1"),
+			Div(
+				Select(
+					Class(inlineInputClass("form-select form-select-sm", fieldErrors["ProjectID"])),
+					Name("ProjectID"),
+					g.Group(
+						g.Map(projects.Projects, func(project *Project) g.Node {
+							return Option(
+								Value(project.ID.String()),
+								g.Text(project.Title),
+								g.If(formModel.ProjectID == project.ID.String(), Selected()),
+							)
+						}),
+					),
+				),
+				inlineFieldError(fieldErrors["ProjectID"]),
+			),
+			Div(
+				Input(
+					Type("text"),
+					Name("Date"),
+					Value(formModel.Date),
+					Pattern("[0-3][0-9]\\.[0-1][0-9]\\.20[0-9]{2}"),
+					MinLength("10"),
+					MaxLength("10"),
+					g.Attr("required", "required"),
+					g.Attr("size", "10"),
+					Class(inlineInputClass("form-control form-control-sm", fieldErrors["Date"])),
+					g.Attr("placeholder", "16.11.2021"),
+				),
+				inlineFieldError(fieldErrors["Date"]),
+			),
+			Div(
+				Input(
+					Type("text"),
+					Name("StartTime"),
+					Value(formModel.StartTime),
+					Pattern("[0-9]{2}:[0-5][0-9]"),
+					MinLength("5"),
+					MaxLength("5"),
+					g.Attr("required", "required"),
+					g.Attr("size", "5"),
+					Class(inlineInputClass("form-control form-control-sm", fieldErrors["StartTime"])),
+					g.Attr("placeholder", "10:00"),
+				),
+				inlineFieldError(fieldErrors["StartTime"]),
+			),
+			Div(
+				Input(
+					Type("text"),
+					Name("EndTime"),
+					Value(formModel.EndTime),
+					Pattern("[0-9]{2}:[0-5][0-9]"),
+					MinLength("5"),
+					MaxLength("5"),
+					g.Attr("required", "required"),
+					g.Attr("size", "5"),
+					Class(inlineInputClass("form-control form-control-sm", fieldErrors["EndTime"])),
+					g.Attr("placeholder", "11:00"),
+				),
+				inlineFieldError(fieldErrors["EndTime"]),
+			),
+			Div(
+				Class("flex-fill"),
+				Input(
+					Type("text"),
+					Name("Description"),
+					Value(formModel.Description),
+					MaxLength("500"),
+					Class(inlineInputClass("form-control form-control-sm", fieldErrors["Description"])),
+					g.Attr("placeholder", "Describe what you do ..."),
+				),
+				inlineFieldError(fieldErrors["Description"]),
+			),
+			Div(
+				Class("text-nowrap"),
+				Button(
+					Type("submit"),
+					Class("btn btn-primary btn-sm"),
+					TitleAttr("Update"),
+					I(Class("bi-save")),
+				),
+				A(
+					ghx.Get(fmt.Sprintf("/activities/%v/row", formModel.ID)),
+					Class("btn btn-outline-secondary btn-sm ms-1"),
+					TitleAttr("Cancel"),
+					I(Class("bi-x")),
+				),
+				A(
+					ghx.Get(fmt.Sprintf("/activities/%v/edit", formModel.ID)),
+					ghx.Target("#baralga__main_content_modal_content"),
+					ghx.Swap("outerHTML"),
+					Class("btn btn-outline-secondary btn-sm ms-1"),
+					TitleAttr("More"),
+					I(Class("bi-three-dots")),
+				),
+			),
+		),
+	)
+}
+
+func inlineInputClass(class, fieldError string) string {
+	if fieldError != "" {
+		return class + " is-invalid"
+	}
+	return class
+}
+
+func inlineFieldError(fieldError string) g.Node {
+	return g.If(
+		fieldError != "",
+		Div(
+			Class("invalid-feedback d-block"),
+			g.Text(fieldError),
+		),
+	)
+}
+
+func activityRowID(activityID uuid.UUID) string {
+	return fmt.Sprintf("baralga__activity_%v", activityID)
+}
+
 func ActivityAddPage(pageContext *shared.PageContext, activityFormModel activityFormModel, projects *ProjectsPaged) g.Node {
 	return shared.Page(
 		pageContext.Title,
 		pageContext.CurrentPath,
+		pageContext.Appearance,
 		[]g.Node{
+			shared.CSRFHeaders(pageContext.CSRFToken),
 			shared.Navbar(pageContext),
 			Section(
 				Class("full-center"),
@@ -851,8 +1150,45 @@ func ActivityForm(formModel activityFormModel, projects *ProjectsPaged, errorMes
 					Name("Description"),
 					Class("form-control"),
 					g.Attr("placeholder", "Describe what you do ..."),
+					g.Attr("aria-describedby", "DescriptionHelp"),
 					g.Text(formModel.Description),
 				),
+				Div(
+					ID("DescriptionHelp"),
+					Class("form-text"),
+					g.Text("Supports Markdown like **bold**, *italic*, lists and [links](https://baralga.com)."),
+				),
+			),
+			Div(
+				Class("mb-3"),
+				Label(
+					Class("form-label"),
+					g.Attr("for", "Location"),
+					g.Text("Location"),
+				),
+				Select(
+					Class("form-select"),
+					ID("Location"),
+					Name("Location"),
+					Option(Value(""), g.Text("Not specified"), g.If(formModel.Location == "", Selected())),
+					Option(Value(LocationOffice), g.Text("Office"), g.If(formModel.Location == LocationOffice, Selected())),
+					Option(Value(LocationHome), g.Text("Home"), g.If(formModel.Location == LocationHome, Selected())),
+					Option(Value(LocationClientSite), g.Text("Client Site"), g.If(formModel.Location == LocationClientSite, Selected())),
+				),
+			),
+			g.If(formModel.Latitude != "" && formModel.Longitude != "",
+				g.Group([]g.Node{
+					Input(
+						Type("hidden"),
+						Name("Latitude"),
+						Value(formModel.Latitude),
+					),
+					Input(
+						Type("hidden"),
+						Name("Longitude"),
+						Value(formModel.Longitude),
+					),
+				}),
 			),
 		),
 		Div(
@@ -1046,6 +1382,8 @@ func (a *ActivityWebHandlers) renderActivityAddView(w http.ResponseWriter, r *ht
 	pageContext := &shared.PageContext{
 		Principal:   principal,
 		CurrentPath: r.URL.Path,
+		CSRFToken:   csrf.Token(r),
+		Appearance:  shared.AppearanceOf(r.Context()),
 		Title:       "Add Activity",
 	}
 
@@ -1055,6 +1393,60 @@ func (a *ActivityWebHandlers) renderActivityAddView(w http.ResponseWriter, r *ht
 	shared.RenderHTML(w, ActivityAddPage(pageContext, activityFormModel, projects))
 }
 
+func (a *ActivityWebHandlers) renderActivityRow(w http.ResponseWriter, r *http.Request, principal *shared.Principal, isProduction bool, activity *Activity) {
+	project, err := a.projectRepository.FindProjectByID(r.Context(), principal.OrganizationID, activity.ProjectID)
+	if err != nil {
+		shared.RenderProblemHTML(w, isProduction, err)
+		return
+	}
+
+	shared.RenderHTML(w, ActivityRowView(activity, project))
+}
+
+func (a *ActivityWebHandlers) renderActivityInlineForm(w http.ResponseWriter, r *http.Request, principal *shared.Principal, isProduction bool, formModel activityFormModel, errorMessage string, fieldErrors map[string]string) {
+	pageParams := &paged.PageParams{
+		Page: 0,
+		Size: 50,
+	}
+
+	projects, err := a.projectRepository.FindProjects(r.Context(), principal.OrganizationID, pageParams)
+	if err != nil {
+		shared.RenderProblemHTML(w, isProduction, err)
+		return
+	}
+
+	formModel.CSRFToken = csrf.Token(r)
+	shared.RenderHTML(w, ActivityInlineForm(formModel, projects, errorMessage, fieldErrors))
+}
+
+// activityFormFieldErrors maps the validation errors of the activity form to messages by field
+func activityFormFieldErrors(err error) map[string]string {
+	fieldErrors := make(map[string]string)
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return fieldErrors
+	}
+
+	for _, fieldError := range validationErrors {
+		switch fieldError.Field() {
+		case "ProjectID":
+			fieldErrors["ProjectID"] = "Project is required."
+		case "Date":
+			fieldErrors["Date"] = "Date is required."
+		case "StartTime":
+			fieldErrors["StartTime"] = "Invalid start time."
+		case "EndTime":
+			fieldErrors["EndTime"] = "Invalid end time."
+		case "Description":
+			fieldErrors["Description"] = "Description is too long."
+		default:
+			fieldErrors[fieldError.Field()] = "Invalid value."
+		}
+	}
+	return fieldErrors
+}
+
 func mapFormToActivity(formModel activityFormModel) (*Activity, error) {
 	var activityID uuid.UUID
 
@@ -1087,18 +1479,39 @@ func mapFormToActivity(formModel activityFormModel) (*Activity, error) {
 		End:         *end,
 		ProjectID:   projectID,
 		Description: formModel.Description,
+		Location:    formModel.Location,
+	}
+
+	// coordinates are captured by mobile clients only and kept as they are
+	if formModel.Latitude != "" && formModel.Longitude != "" {
+		latitude, err := strconv.ParseFloat(formModel.Latitude, 64)
+		if err != nil {
+			return nil, err
+		}
+		longitude, err := strconv.ParseFloat(formModel.Longitude, 64)
+		if err != nil {
+			return nil, err
+		}
+		activity.Latitude = &latitude
+		activity.Longitude = &longitude
 	}
 
 	return activity, nil
 }
 
 func mapActivityToForm(activity Activity) activityFormModel {
-	return activityFormModel{
+	formModel := activityFormModel{
 		ID:          activity.ID.String(),
 		Date:        time_utils.FormatDateDE(activity.Start),
 		StartTime:   time_utils.FormatTime(activity.Start),
 		EndTime:     time_utils.FormatTime(activity.End),
 		ProjectID:   activity.ProjectID.String(),
 		Description: activity.Description,
+		Location:    activity.Location,
+	}
+	if activity.Latitude != nil && activity.Longitude != nil {
+		formModel.Latitude = strconv.FormatFloat(*activity.Latitude, 'f', -1, 64)
+		formModel.Longitude = strconv.FormatFloat(*activity.Longitude, 'f', -1, 64)
 	}
+	return formModel
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/baralga/shared"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/matryer/is"
 )
 
@@ -23,7 +24,8 @@ func TestHandleTrackingPage(t *testing.T) {
 		activityRepository: activityRepository,
 		projectRepository:  NewInMemProjectRepository(),
 		activityService: &ActitivityService{
-			activityRepository: activityRepository,
+			activityRepository:       activityRepository,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -67,7 +69,9 @@ func TestHandleActivityEditPage(t *testing.T) {
 	}
 
 	r, _ := http.NewRequest("GET", "/activities/00000000-0000-0000-2222-000000000001/edit", nil)
-	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
 
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("activity-id", "00000000-0000-0000-2222-000000000001")
@@ -80,6 +84,169 @@ func TestHandleActivityEditPage(t *testing.T) {
 	is.True(strings.Contains(htmlBody, "<form"))
 }
 
+func TestHandleActivityRow(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ActivityWebHandlers{
+		config:             &shared.Config{},
+		activityRepository: NewInMemActivityRepository(),
+		projectRepository:  NewInMemProjectRepository(),
+	}
+
+	r, _ := http.NewRequest("GET", "/activities/00000000-0000-0000-2222-000000000001/row", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("activity-id", "00000000-0000-0000-2222-000000000001")
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	a.HandleActivityRow()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	htmlBody := httpRec.Body.String()
+	is.True(strings.Contains(htmlBody, "baralga__activity_00000000-0000-0000-2222-000000000001"))
+	is.True(strings.Contains(htmlBody, "/activities/00000000-0000-0000-2222-000000000001/inline-edit"))
+}
+
+func TestHandleActivityRowNotFound(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ActivityWebHandlers{
+		config:             &shared.Config{},
+		activityRepository: NewInMemActivityRepository(),
+		projectRepository:  NewInMemProjectRepository(),
+	}
+
+	r, _ := http.NewRequest("GET", "/activities/00000000-0000-0000-2222-000000000099/row", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("activity-id", "00000000-0000-0000-2222-000000000099")
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	a.HandleActivityRow()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
+
+func TestHandleActivityInlineEdit(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ActivityWebHandlers{
+		config:             &shared.Config{},
+		activityRepository: NewInMemActivityRepository(),
+		projectRepository:  NewInMemProjectRepository(),
+	}
+
+	r, _ := http.NewRequest("GET", "/activities/00000000-0000-0000-2222-000000000001/inline-edit", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("activity-id", "00000000-0000-0000-2222-000000000001")
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	a.HandleActivityInlineEdit()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	htmlBody := httpRec.Body.String()
+	is.True(strings.Contains(htmlBody, "<form"))
+	is.True(strings.Contains(htmlBody, `id="baralga__activity_00000000-0000-0000-2222-000000000001"`))
+	is.True(!strings.Contains(htmlBody, "modal-content"))
+}
+
+func TestHandleActivityInlineFormWithValidActivity(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	repo := NewInMemActivityRepository()
+	a := &ActivityWebHandlers{
+		config:             &shared.Config{},
+		activityRepository: repo,
+		projectRepository:  NewInMemProjectRepository(),
+		activityService: &ActitivityService{
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       repo,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	data := url.Values{}
+	data["ProjectID"] = []string{shared.ProjectIDSample.String()}
+	data["Date"] = []string{"21.12.2021"}
+	data["StartTime"] = []string{"10:00"}
+	data["EndTime"] = []string{"12:30"}
+	data["Description"] = []string{"Inline description"}
+
+	r, _ := http.NewRequest("POST", "/activities/00000000-0000-0000-2222-000000000001/inline-edit", strings.NewReader(data.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("activity-id", "00000000-0000-0000-2222-000000000001")
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	a.HandleActivityInlineForm()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(httpRec.Result().Header.Get("HX-Trigger"), "baralga__activities-changed")
+
+	htmlBody := httpRec.Body.String()
+	is.True(!strings.Contains(htmlBody, "<form"))
+	is.True(strings.Contains(htmlBody, "10:00 - 12:30"))
+
+	activity, err := repo.FindActivityByID(context.Background(), uuid.MustParse("00000000-0000-0000-2222-000000000001"), shared.OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(activity.Description, "Inline description")
+}
+
+func TestHandleActivityInlineFormWithInvalidActivity(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	repo := NewInMemActivityRepository()
+	a := &ActivityWebHandlers{
+		config:             &shared.Config{},
+		activityRepository: repo,
+		projectRepository:  NewInMemProjectRepository(),
+	}
+
+	data := url.Values{}
+	data["ProjectID"] = []string{shared.ProjectIDSample.String()}
+	data["Date"] = []string{"21.12.2021"}
+	data["StartTime"] = []string{"1"}
+	data["EndTime"] = []string{"12:30"}
+
+	r, _ := http.NewRequest("POST", "/activities/00000000-0000-0000-2222-000000000001/inline-edit", strings.NewReader(data.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("activity-id", "00000000-0000-0000-2222-000000000001")
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	a.HandleActivityInlineForm()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(httpRec.Result().Header.Get("HX-Trigger"), "")
+
+	htmlBody := httpRec.Body.String()
+	is.True(strings.Contains(htmlBody, "<form"))
+	is.True(strings.Contains(htmlBody, "is-invalid"))
+	is.True(strings.Contains(htmlBody, "Invalid start time."))
+}
+
 func TestHandleCreateActivtiyWithValidActivtiy(t *testing.T) {
 	is := is.New(t)
 	httpRec := httptest.NewRecorder()
@@ -92,8 +259,9 @@ func TestHandleCreateActivtiyWithValidActivtiy(t *testing.T) {
 		activityRepository: repo,
 		projectRepository:  NewInMemProjectRepository(),
 		activityService: &ActitivityService{
-			repositoryTxer:     shared.NewInMemRepositoryTxer(),
-			activityRepository: repo,
+			repositoryTxer:           shared.NewInMemRepositoryTxer(),
+			activityRepository:       repo,
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -194,3 +362,25 @@ func TestHandleEndTimeValidation(t *testing.T) {
 	htmlBody := httpRec.Body.String()
 	is.True(strings.Contains(htmlBody, "10:00"))
 }
+
+func TestMapFormToActivityKeepsLocation(t *testing.T) {
+	is := is.New(t)
+
+	latitude, longitude := 52.52, 13.405
+	activity := Activity{
+		ProjectID: shared.ProjectIDSample,
+		Location:  LocationHome,
+		Latitude:  &latitude,
+		Longitude: &longitude,
+	}
+
+	formModel := mapActivityToForm(activity)
+	is.Equal(formModel.Location, LocationHome)
+	is.Equal(formModel.Latitude, "52.52")
+
+	mapped, err := mapFormToActivity(formModel)
+	is.NoErr(err)
+	is.Equal(mapped.Location, LocationHome)
+	is.Equal(*mapped.Latitude, latitude)
+	is.Equal(*mapped.Longitude, longitude)
+}
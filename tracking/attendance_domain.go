@@ -0,0 +1,121 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+)
+
+// Status of attendance corrections
+const (
+	AttendanceCorrectionPending  = "pending"
+	AttendanceCorrectionApproved = "approved"
+	AttendanceCorrectionRejected = "rejected"
+)
+
+const maxAttendanceCorrectionReasonLength = 500
+
+var (
+	ErrAttendanceStampNotFound      = shared.NewDomainError("attendance:stamp-not-found", http.StatusNotFound, "attendance stamp not found")
+	ErrAlreadyClockedIn             = shared.NewDomainError("attendance:already-clocked-in", http.StatusConflict, "already clocked in")
+	ErrNotClockedIn                 = shared.NewDomainError("attendance:not-clocked-in", http.StatusConflict, "not clocked in")
+	ErrAttendanceCorrectionNotFound = shared.NewDomainError("attendance:correction-not-found", http.StatusNotFound, "attendance correction not found")
+	ErrAttendanceCorrectionDecided  = shared.NewDomainError("attendance:correction-decided", http.StatusConflict, "attendance correction already decided")
+)
+
+// AttendanceStamp is the time a user was present from clock-in to clock-out, it's open until the clock-out
+type AttendanceStamp struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Username       string
+	ClockIn        time.Time
+	ClockOut       *time.Time
+}
+
+// AttendanceCorrection is the correction of a stamp requested by its user, without stamp a missing stamp is added,
+// the correction is applied when an admin approves it
+type AttendanceCorrection struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	StampID        *uuid.UUID
+	Username       string
+	ClockIn        time.Time
+	ClockOut       time.Time
+	Reason         string
+	Status         string
+	RequestedAt    time.Time
+	DecidedBy      string
+	DecidedAt      *time.Time
+}
+
+// AttendanceSheet are the stamps of a user in a month
+type AttendanceSheet struct {
+	Username string
+	Month    time.Time
+	Stamps   []*AttendanceStamp
+}
+
+type AttendanceRepository interface {
+	FindOpenAttendanceStamp(ctx context.Context, organizationID uuid.UUID, username string) (*AttendanceStamp, error)
+	FindAttendanceStampByID(ctx context.Context, organizationID, stampID uuid.UUID) (*AttendanceStamp, error)
+	FindAttendanceStamps(ctx context.Context, organizationID uuid.UUID, username string, start, end time.Time) ([]*AttendanceStamp, error)
+	InsertAttendanceStamp(ctx context.Context, stamp *AttendanceStamp) error
+	UpdateAttendanceStamp(ctx context.Context, stamp *AttendanceStamp) error
+	FindAttendanceCorrections(ctx context.Context, organizationID uuid.UUID, username, status string) ([]*AttendanceCorrection, error)
+	FindAttendanceCorrectionByID(ctx context.Context, organizationID, correctionID uuid.UUID) (*AttendanceCorrection, error)
+	InsertAttendanceCorrection(ctx context.Context, correction *AttendanceCorrection) error
+	UpdateAttendanceCorrection(ctx context.Context, correction *AttendanceCorrection) error
+}
+
+// Validate checks that the clock-out is at most a day after the clock-in and the reason is given
+func (c *AttendanceCorrection) Validate() error {
+	if !c.ClockOut.After(c.ClockIn) {
+		return shared.NewInvalidParam("clockOut", "after", "clock-out must be after clock-in")
+	}
+	if c.ClockOut.Sub(c.ClockIn) > 24*time.Hour {
+		return shared.NewInvalidParam("clockOut", "max", "clock-out must be within 24 hours after clock-in")
+	}
+	if c.Reason == "" {
+		return shared.NewInvalidParam("reason", "required", "reason is required")
+	}
+	if len([]rune(c.Reason)) > maxAttendanceCorrectionReasonLength {
+		return shared.NewInvalidParam("reason", "max", "reason must not be longer than 500 characters")
+	}
+	return nil
+}
+
+// IsPending checks whether the correction is not yet decided
+func (c *AttendanceCorrection) IsPending() bool {
+	return c.Status == AttendanceCorrectionPending
+}
+
+// DurationInMinutes is the time from clock-in to clock-out, open stamps have no duration
+func (s *AttendanceStamp) DurationInMinutes() int {
+	if s.ClockOut == nil {
+		return 0
+	}
+	return int(s.ClockOut.Sub(s.ClockIn).Minutes())
+}
+
+// DurationFormatted is the time from clock-in to clock-out as formatted string (e.g. 8:15 h)
+func (s *AttendanceStamp) DurationFormatted() string {
+	return time_utils.FormatMinutesAsDuration(float64(s.DurationInMinutes()))
+}
+
+// DurationInMinutesTotal is the time of all stamps of the sheet
+func (s *AttendanceSheet) DurationInMinutesTotal() int {
+	total := 0
+	for _, stamp := range s.Stamps {
+		total += stamp.DurationInMinutes()
+	}
+	return total
+}
+
+// DurationFormatted is the time of all stamps of the sheet as formatted string (e.g. 160:30 h)
+func (s *AttendanceSheet) DurationFormatted() string {
+	return time_utils.FormatMinutesAsDuration(float64(s.DurationInMinutesTotal()))
+}
@@ -0,0 +1,47 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestValidateAttendanceCorrection(t *testing.T) {
+	is := is.New(t)
+
+	clockIn := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	correction := &AttendanceCorrection{ClockIn: clockIn, ClockOut: clockIn.Add(8 * time.Hour), Reason: "forgot to clock out"}
+	is.NoErr(correction.Validate())
+
+	correction = &AttendanceCorrection{ClockIn: clockIn, ClockOut: clockIn, Reason: "forgot to clock out"}
+	is.True(correction.Validate() != nil)
+
+	correction = &AttendanceCorrection{ClockIn: clockIn, ClockOut: clockIn.Add(25 * time.Hour), Reason: "forgot to clock out"}
+	is.True(correction.Validate() != nil)
+
+	correction = &AttendanceCorrection{ClockIn: clockIn, ClockOut: clockIn.Add(8 * time.Hour)}
+	is.True(correction.Validate() != nil)
+
+	correction = &AttendanceCorrection{ClockIn: clockIn, ClockOut: clockIn.Add(8 * time.Hour), Reason: strings.Repeat("x", 501)}
+	is.True(correction.Validate() != nil)
+}
+
+func TestAttendanceSheetDuration(t *testing.T) {
+	is := is.New(t)
+
+	clockIn := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	clockOut := clockIn.Add(7*time.Hour + 45*time.Minute)
+
+	sheet := &AttendanceSheet{
+		Stamps: []*AttendanceStamp{
+			{ClockIn: clockIn, ClockOut: &clockOut},
+			{ClockIn: clockIn.AddDate(0, 0, 1)},
+		},
+	}
+
+	is.Equal(sheet.Stamps[1].DurationInMinutes(), 0)
+	is.Equal(sheet.DurationInMinutesTotal(), 7*60+45)
+}
@@ -0,0 +1,269 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbAttendanceRepository is a SQL database repository for attendance stamps and their corrections
+type DbAttendanceRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ AttendanceRepository = (*DbAttendanceRepository)(nil)
+
+// NewDbAttendanceRepository creates a new SQL database repository for attendance stamps
+func NewDbAttendanceRepository(connPool *pgxpool.Pool) *DbAttendanceRepository {
+	return &DbAttendanceRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbAttendanceRepository) FindOpenAttendanceStamp(ctx context.Context, organizationID uuid.UUID, username string) (*AttendanceStamp, error) {
+	row, err := shared.SelectOne[attendanceStampRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[attendanceStampRow]()+` 
+		 FROM attendance_stamps 
+		 WHERE org_id = $1 AND username = $2 AND clock_out IS NULL 
+		 ORDER BY clock_in DESC 
+		 LIMIT 1`,
+		organizationID, username,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAttendanceStampNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toAttendanceStamp(), nil
+}
+
+func (r *DbAttendanceRepository) FindAttendanceStampByID(ctx context.Context, organizationID, stampID uuid.UUID) (*AttendanceStamp, error) {
+	row, err := shared.SelectOne[attendanceStampRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[attendanceStampRow]()+` 
+		 FROM attendance_stamps 
+		 WHERE stamp_id = $1 AND org_id = $2`,
+		stampID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAttendanceStampNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toAttendanceStamp(), nil
+}
+
+func (r *DbAttendanceRepository) FindAttendanceStamps(ctx context.Context, organizationID uuid.UUID, username string, start, end time.Time) ([]*AttendanceStamp, error) {
+	rows, err := shared.SelectAll[attendanceStampRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[attendanceStampRow]()+` 
+		 FROM attendance_stamps 
+		 WHERE org_id = $1 AND username = $2 AND $3 <= clock_in AND clock_in < $4 
+		 ORDER BY clock_in ASC`,
+		organizationID, username, start, end,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stamps := make([]*AttendanceStamp, len(rows))
+	for i, row := range rows {
+		stamps[i] = row.toAttendanceStamp()
+	}
+	return stamps, nil
+}
+
+func (r *DbAttendanceRepository) InsertAttendanceStamp(ctx context.Context, stamp *AttendanceStamp) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO attendance_stamps 
+		   (stamp_id, org_id, username, clock_in, clock_out) 
+		 VALUES 
+		   ($1, $2, $3, $4, $5)`,
+		stamp.ID,
+		stamp.OrganizationID,
+		stamp.Username,
+		stamp.ClockIn,
+		stamp.ClockOut,
+	)
+	return err
+}
+
+func (r *DbAttendanceRepository) UpdateAttendanceStamp(ctx context.Context, stamp *AttendanceStamp) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE attendance_stamps 
+		 SET clock_in = $3, clock_out = $4 
+		 WHERE stamp_id = $1 AND org_id = $2`,
+		stamp.ID,
+		stamp.OrganizationID,
+		stamp.ClockIn,
+		stamp.ClockOut,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrAttendanceStampNotFound
+	}
+	return nil
+}
+
+func (r *DbAttendanceRepository) FindAttendanceCorrections(ctx context.Context, organizationID uuid.UUID, username, status string) ([]*AttendanceCorrection, error) {
+	rows, err := shared.SelectAll[attendanceCorrectionRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[attendanceCorrectionRow]()+` 
+		 FROM attendance_corrections 
+		 WHERE org_id = $1 AND ($2 = '' OR username = $2) AND ($3 = '' OR status = $3) 
+		 ORDER BY requested_at ASC`,
+		organizationID, username, status,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	corrections := make([]*AttendanceCorrection, len(rows))
+	for i, row := range rows {
+		corrections[i] = row.toAttendanceCorrection()
+	}
+	return corrections, nil
+}
+
+func (r *DbAttendanceRepository) FindAttendanceCorrectionByID(ctx context.Context, organizationID, correctionID uuid.UUID) (*AttendanceCorrection, error) {
+	row, err := shared.SelectOne[attendanceCorrectionRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[attendanceCorrectionRow]()+` 
+		 FROM attendance_corrections 
+		 WHERE correction_id = $1 AND org_id = $2`,
+		correctionID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAttendanceCorrectionNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toAttendanceCorrection(), nil
+}
+
+func (r *DbAttendanceRepository) InsertAttendanceCorrection(ctx context.Context, correction *AttendanceCorrection) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO attendance_corrections 
+		   (correction_id, org_id, stamp_id, username, clock_in, clock_out, reason, status, requested_at) 
+		 VALUES 
+		   ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		correction.ID,
+		correction.OrganizationID,
+		correction.StampID,
+		correction.Username,
+		correction.ClockIn,
+		correction.ClockOut,
+		correction.Reason,
+		correction.Status,
+		correction.RequestedAt,
+	)
+	return err
+}
+
+func (r *DbAttendanceRepository) UpdateAttendanceCorrection(ctx context.Context, correction *AttendanceCorrection) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE attendance_corrections 
+		 SET stamp_id = $3, status = $4, decided_by = $5, decided_at = $6 
+		 WHERE correction_id = $1 AND org_id = $2`,
+		correction.ID,
+		correction.OrganizationID,
+		correction.StampID,
+		correction.Status,
+		correction.DecidedBy,
+		correction.DecidedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrAttendanceCorrectionNotFound
+	}
+	return nil
+}
+
+type attendanceStampRow struct {
+	ID             uuid.UUID  `db:"stamp_id"`
+	OrganizationID uuid.UUID  `db:"org_id"`
+	Username       string     `db:"username"`
+	ClockIn        time.Time  `db:"clock_in"`
+	ClockOut       *time.Time `db:"clock_out"`
+}
+
+func (r *attendanceStampRow) toAttendanceStamp() *AttendanceStamp {
+	return &AttendanceStamp{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		ClockIn:        r.ClockIn,
+		ClockOut:       r.ClockOut,
+	}
+}
+
+type attendanceCorrectionRow struct {
+	ID             uuid.UUID  `db:"correction_id"`
+	OrganizationID uuid.UUID  `db:"org_id"`
+	StampID        *uuid.UUID `db:"stamp_id"`
+	Username       string     `db:"username"`
+	ClockIn        time.Time  `db:"clock_in"`
+	ClockOut       time.Time  `db:"clock_out"`
+	Reason         string     `db:"reason"`
+	Status         string     `db:"status"`
+	RequestedAt    time.Time  `db:"requested_at"`
+	DecidedBy      *string    `db:"decided_by"`
+	DecidedAt      *time.Time `db:"decided_at"`
+}
+
+func (r *attendanceCorrectionRow) toAttendanceCorrection() *AttendanceCorrection {
+	correction := &AttendanceCorrection{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		StampID:        r.StampID,
+		Username:       r.Username,
+		ClockIn:        r.ClockIn,
+		ClockOut:       r.ClockOut,
+		Reason:         r.Reason,
+		Status:         r.Status,
+		RequestedAt:    r.RequestedAt,
+		DecidedAt:      r.DecidedAt,
+	}
+	if r.DecidedBy != nil {
+		correction.DecidedBy = *r.DecidedBy
+	}
+	return correction
+}
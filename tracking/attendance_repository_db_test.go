@@ -0,0 +1,118 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestAttendanceRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	attendanceRepository := NewDbAttendanceRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	stamp := &AttendanceStamp{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		ClockIn:        time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("ClockInAndClockOut", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return attendanceRepository.InsertAttendanceStamp(ctx, stamp)
+			},
+		)
+		is.NoErr(err)
+
+		openStamp, err := attendanceRepository.FindOpenAttendanceStamp(context.Background(), shared.OrganizationIDSample, "user1")
+		is.NoErr(err)
+		is.Equal(openStamp.ID, stamp.ID)
+
+		clockOut := stamp.ClockIn.Add(8 * time.Hour)
+		openStamp.ClockOut = &clockOut
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return attendanceRepository.UpdateAttendanceStamp(ctx, openStamp)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = attendanceRepository.FindOpenAttendanceStamp(context.Background(), shared.OrganizationIDSample, "user1")
+		is.Equal(err, ErrAttendanceStampNotFound)
+
+		stamps, err := attendanceRepository.FindAttendanceStamps(context.Background(), shared.OrganizationIDSample, "user1", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+		is.NoErr(err)
+		is.Equal(len(stamps), 1)
+		is.Equal(stamps[0].DurationInMinutes(), 8*60)
+	})
+
+	t.Run("RequestAndDecideCorrection", func(t *testing.T) {
+		correction := &AttendanceCorrection{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			StampID:        &stamp.ID,
+			Username:       "user1",
+			ClockIn:        stamp.ClockIn,
+			ClockOut:       stamp.ClockIn.Add(9 * time.Hour),
+			Reason:         "forgot to clock out",
+			Status:         AttendanceCorrectionPending,
+			RequestedAt:    time.Now(),
+		}
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return attendanceRepository.InsertAttendanceCorrection(ctx, correction)
+			},
+		)
+		is.NoErr(err)
+
+		corrections, err := attendanceRepository.FindAttendanceCorrections(context.Background(), shared.OrganizationIDSample, "", AttendanceCorrectionPending)
+		is.NoErr(err)
+		is.Equal(len(corrections), 1)
+
+		decidedAt := time.Now()
+		correction.Status = AttendanceCorrectionRejected
+		correction.DecidedBy = "admin"
+		correction.DecidedAt = &decidedAt
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return attendanceRepository.UpdateAttendanceCorrection(ctx, correction)
+			},
+		)
+		is.NoErr(err)
+
+		found, err := attendanceRepository.FindAttendanceCorrectionByID(context.Background(), shared.OrganizationIDSample, correction.ID)
+		is.NoErr(err)
+		is.Equal(found.Status, AttendanceCorrectionRejected)
+		is.Equal(found.DecidedBy, "admin")
+	})
+}
@@ -0,0 +1,147 @@
+package tracking
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemAttendanceRepository struct {
+	mu          sync.Mutex
+	stamps      []*AttendanceStamp
+	corrections []*AttendanceCorrection
+}
+
+var _ AttendanceRepository = (*InMemAttendanceRepository)(nil)
+
+func NewInMemAttendanceRepository() *InMemAttendanceRepository {
+	return &InMemAttendanceRepository{}
+}
+
+func (r *InMemAttendanceRepository) FindOpenAttendanceStamp(ctx context.Context, organizationID uuid.UUID, username string) (*AttendanceStamp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stamp := range r.stamps {
+		if stamp.OrganizationID == organizationID && stamp.Username == username && stamp.ClockOut == nil {
+			found := *stamp
+			return &found, nil
+		}
+	}
+	return nil, ErrAttendanceStampNotFound
+}
+
+func (r *InMemAttendanceRepository) FindAttendanceStampByID(ctx context.Context, organizationID, stampID uuid.UUID) (*AttendanceStamp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stamp := range r.stamps {
+		if stamp.OrganizationID == organizationID && stamp.ID == stampID {
+			found := *stamp
+			return &found, nil
+		}
+	}
+	return nil, ErrAttendanceStampNotFound
+}
+
+func (r *InMemAttendanceRepository) FindAttendanceStamps(ctx context.Context, organizationID uuid.UUID, username string, start, end time.Time) ([]*AttendanceStamp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var stamps []*AttendanceStamp
+	for _, stamp := range r.stamps {
+		if stamp.OrganizationID != organizationID || stamp.Username != username {
+			continue
+		}
+		if stamp.ClockIn.Before(start) || !stamp.ClockIn.Before(end) {
+			continue
+		}
+		found := *stamp
+		stamps = append(stamps, &found)
+	}
+
+	sort.SliceStable(stamps, func(i, j int) bool {
+		return stamps[i].ClockIn.Before(stamps[j].ClockIn)
+	})
+	return stamps, nil
+}
+
+func (r *InMemAttendanceRepository) InsertAttendanceStamp(ctx context.Context, stamp *AttendanceStamp) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *stamp
+	r.stamps = append(r.stamps, &inserted)
+	return nil
+}
+
+func (r *InMemAttendanceRepository) UpdateAttendanceStamp(ctx context.Context, stamp *AttendanceStamp) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, s := range r.stamps {
+		if s.OrganizationID == stamp.OrganizationID && s.ID == stamp.ID {
+			updated := *stamp
+			r.stamps[i] = &updated
+			return nil
+		}
+	}
+	return ErrAttendanceStampNotFound
+}
+
+func (r *InMemAttendanceRepository) FindAttendanceCorrections(ctx context.Context, organizationID uuid.UUID, username, status string) ([]*AttendanceCorrection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var corrections []*AttendanceCorrection
+	for _, correction := range r.corrections {
+		if correction.OrganizationID != organizationID {
+			continue
+		}
+		if (username != "" && correction.Username != username) || (status != "" && correction.Status != status) {
+			continue
+		}
+		found := *correction
+		corrections = append(corrections, &found)
+	}
+	return corrections, nil
+}
+
+func (r *InMemAttendanceRepository) FindAttendanceCorrectionByID(ctx context.Context, organizationID, correctionID uuid.UUID) (*AttendanceCorrection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, correction := range r.corrections {
+		if correction.OrganizationID == organizationID && correction.ID == correctionID {
+			found := *correction
+			return &found, nil
+		}
+	}
+	return nil, ErrAttendanceCorrectionNotFound
+}
+
+func (r *InMemAttendanceRepository) InsertAttendanceCorrection(ctx context.Context, correction *AttendanceCorrection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *correction
+	r.corrections = append(r.corrections, &inserted)
+	return nil
+}
+
+func (r *InMemAttendanceRepository) UpdateAttendanceCorrection(ctx context.Context, correction *AttendanceCorrection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, c := range r.corrections {
+		if c.OrganizationID == correction.OrganizationID && c.ID == correction.ID {
+			updated := *correction
+			r.corrections[i] = &updated
+			return nil
+		}
+	}
+	return ErrAttendanceCorrectionNotFound
+}
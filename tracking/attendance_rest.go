@@ -0,0 +1,319 @@
+package tracking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type attendanceStampModel struct {
+	ID                string     `json:"id"`
+	Username          string     `json:"username"`
+	ClockIn           string     `json:"clockIn"`
+	ClockOut          string     `json:"clockOut,omitempty"`
+	DurationFormatted string     `json:"durationFormatted"`
+	Links             *hal.Links `json:"_links,omitempty"`
+}
+
+type attendanceSheetModel struct {
+	Username          string                  `json:"username"`
+	Month             string                  `json:"month"`
+	MinutesTotal      int                     `json:"minutesTotal"`
+	DurationFormatted string                  `json:"durationFormatted"`
+	Stamps            []*attendanceStampModel `json:"stamps"`
+	Links             *hal.Links              `json:"_links"`
+}
+
+type attendanceCorrectionModel struct {
+	ID        string     `json:"id"`
+	StampID   string     `json:"stampId,omitempty"`
+	Username  string     `json:"username"`
+	ClockIn   string     `json:"clockIn"`
+	ClockOut  string     `json:"clockOut"`
+	Reason    string     `json:"reason"`
+	Status    string     `json:"status"`
+	DecidedBy string     `json:"decidedBy,omitempty"`
+	Links     *hal.Links `json:"_links,omitempty"`
+}
+
+type attendanceCorrectionsModel struct {
+	Embedded *embeddedAttendanceCorrections `json:"_embedded"`
+	Links    *hal.Links                     `json:"_links"`
+}
+
+type embeddedAttendanceCorrections struct {
+	AttendanceCorrectionModels []*attendanceCorrectionModel `json:"corrections"`
+}
+
+type AttendanceRestHandlers struct {
+	config            *shared.Config
+	attendanceService *AttendanceService
+}
+
+func NewAttendanceRestHandlers(config *shared.Config, attendanceService *AttendanceService) *AttendanceRestHandlers {
+	return &AttendanceRestHandlers{
+		config:            config,
+		attendanceService: attendanceService,
+	}
+}
+
+func (a *AttendanceRestHandlers) RegisterProtected(r chi.Router) {
+	r.Post("/attendance/clock-in", a.HandleClockIn())
+	r.Post("/attendance/clock-out", a.HandleClockOut())
+	r.Get("/attendance/sheets/{month}", a.HandleAttendanceSheet())
+	r.Get("/attendance/corrections", a.HandleGetAttendanceCorrections())
+	r.Post("/attendance/corrections", a.HandleRequestAttendanceCorrection())
+	r.Post("/attendance/corrections/{correction-id}/approve", a.HandleDecideAttendanceCorrection(true))
+	r.Post("/attendance/corrections/{correction-id}/reject", a.HandleDecideAttendanceCorrection(false))
+}
+
+func (a *AttendanceRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleClockIn clocks in the user
+func (a *AttendanceRestHandlers) HandleClockIn() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	attendanceService := a.attendanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		stamp, err := attendanceService.ClockIn(r.Context(), principal, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToAttendanceStampModel(stamp))
+	}
+}
+
+// HandleClockOut clocks out the user
+func (a *AttendanceRestHandlers) HandleClockOut() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	attendanceService := a.attendanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		stamp, err := attendanceService.ClockOut(r.Context(), principal, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToAttendanceStampModel(stamp))
+	}
+}
+
+// HandleAttendanceSheet reads the monthly attendance sheet of a user as JSON or CSV,
+// admins read the sheet of other users with the query param username
+func (a *AttendanceRestHandlers) HandleAttendanceSheet() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	attendanceService := a.attendanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		month, err := time.Parse("2006-01", chi.URLParam(r, "month"))
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid month", shared.NewInvalidParam("month", "month", "must be a month like 2024-03"))
+			return
+		}
+
+		sheet, err := attendanceService.ReadAttendanceSheet(r.Context(), principal, r.URL.Query().Get("username"), month)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		contentType := r.URL.Query().Get("contentType")
+		if contentType == "" {
+			contentType = r.Header.Get("Content-Type")
+		}
+
+		if contentType == "text/csv" {
+			buf := &bytes.Buffer{}
+			err := attendanceService.WriteAttendanceSheetAsCSV(sheet, buf)
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+
+			fileName := fmt.Sprintf("Attendance_%s_%s.csv", sheet.Username, month.Format("2006-01"))
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+			_, _ = buf.WriteTo(w)
+			return
+		}
+
+		sheetModel := mapToAttendanceSheetModel(sheet)
+		sheetModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+			hal.NewLink("csv", fmt.Sprintf("%s?contentType=text/csv&username=%s", r.URL.Path, sheet.Username)),
+		)
+		shared.RenderJSON(w, sheetModel)
+	}
+}
+
+// HandleGetAttendanceCorrections reads the pending corrections for admins and the own corrections for other users
+func (a *AttendanceRestHandlers) HandleGetAttendanceCorrections() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	attendanceService := a.attendanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		corrections, err := attendanceService.ReadAttendanceCorrections(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		correctionModels := make([]*attendanceCorrectionModel, len(corrections))
+		for i, correction := range corrections {
+			correctionModels[i] = mapToAttendanceCorrectionModel(correction)
+		}
+
+		shared.RenderJSON(w, &attendanceCorrectionsModel{
+			Embedded: &embeddedAttendanceCorrections{
+				AttendanceCorrectionModels: correctionModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleRequestAttendanceCorrection requests the correction of a stamp or to add a missing stamp
+func (a *AttendanceRestHandlers) HandleRequestAttendanceCorrection() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	attendanceService := a.attendanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var correctionModel attendanceCorrectionModel
+		err := json.NewDecoder(r.Body).Decode(&correctionModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "attendance correction not valid", err)
+			return
+		}
+
+		correction, err := mapToAttendanceCorrection(&correctionModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "attendance correction not valid", err)
+			return
+		}
+
+		correction, err = attendanceService.RequestAttendanceCorrection(r.Context(), principal, correction)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToAttendanceCorrectionModel(correction))
+	}
+}
+
+// HandleDecideAttendanceCorrection approves or rejects a pending correction
+func (a *AttendanceRestHandlers) HandleDecideAttendanceCorrection(approve bool) http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	attendanceService := a.attendanceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		correctionID, err := uuid.Parse(chi.URLParam(r, "correction-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		correction, err := attendanceService.DecideAttendanceCorrection(r.Context(), principal, correctionID, approve)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToAttendanceCorrectionModel(correction))
+	}
+}
+
+func mapToAttendanceStampModel(stamp *AttendanceStamp) *attendanceStampModel {
+	stampModel := &attendanceStampModel{
+		ID:                stamp.ID.String(),
+		Username:          stamp.Username,
+		ClockIn:           time_utils.FormatDateTime(stamp.ClockIn),
+		DurationFormatted: stamp.DurationFormatted(),
+	}
+	if stamp.ClockOut != nil {
+		stampModel.ClockOut = time_utils.FormatDateTime(*stamp.ClockOut)
+	}
+	return stampModel
+}
+
+func mapToAttendanceSheetModel(sheet *AttendanceSheet) *attendanceSheetModel {
+	stampModels := make([]*attendanceStampModel, len(sheet.Stamps))
+	for i, stamp := range sheet.Stamps {
+		stampModels[i] = mapToAttendanceStampModel(stamp)
+	}
+
+	return &attendanceSheetModel{
+		Username:          sheet.Username,
+		Month:             sheet.Month.Format("2006-01"),
+		MinutesTotal:      sheet.DurationInMinutesTotal(),
+		DurationFormatted: sheet.DurationFormatted(),
+		Stamps:            stampModels,
+	}
+}
+
+func mapToAttendanceCorrectionModel(correction *AttendanceCorrection) *attendanceCorrectionModel {
+	correctionModel := &attendanceCorrectionModel{
+		ID:        correction.ID.String(),
+		Username:  correction.Username,
+		ClockIn:   time_utils.FormatDateTime(correction.ClockIn),
+		ClockOut:  time_utils.FormatDateTime(correction.ClockOut),
+		Reason:    correction.Reason,
+		Status:    correction.Status,
+		DecidedBy: correction.DecidedBy,
+	}
+	if correction.StampID != nil {
+		correctionModel.StampID = correction.StampID.String()
+	}
+	return correctionModel
+}
+
+func mapToAttendanceCorrection(correctionModel *attendanceCorrectionModel) (*AttendanceCorrection, error) {
+	clockIn, err := time_utils.ParseDateTime(correctionModel.ClockIn)
+	if err != nil {
+		return nil, shared.NewInvalidParam("clockIn", "datetime", err.Error())
+	}
+
+	clockOut, err := time_utils.ParseDateTime(correctionModel.ClockOut)
+	if err != nil {
+		return nil, shared.NewInvalidParam("clockOut", "datetime", err.Error())
+	}
+
+	correction := &AttendanceCorrection{
+		ClockIn:  *clockIn,
+		ClockOut: *clockOut,
+		Reason:   correctionModel.Reason,
+	}
+
+	if correctionModel.StampID != "" {
+		stampID, err := uuid.Parse(correctionModel.StampID)
+		if err != nil {
+			return nil, shared.NewInvalidParam("stampId", "uuid", "stamp id must be a uuid")
+		}
+		correction.StampID = &stampID
+	}
+
+	return correction, correction.Validate()
+}
@@ -0,0 +1,94 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleAttendance(t *testing.T) {
+	is := is.New(t)
+
+	a := NewAttendanceRestHandlers(&shared.Config{}, newAttendanceServiceForTest(t, true))
+
+	withPrincipal := func(r *http.Request) *http.Request {
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Roles:          []string{"ROLE_USER"},
+		}))
+	}
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("POST", "/api/attendance/clock-in", nil)
+	a.HandleClockIn()(httpRec, withPrincipal(r))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("POST", "/api/attendance/clock-in", nil)
+	a.HandleClockIn()(httpRec, withPrincipal(r))
+	is.Equal(httpRec.Result().StatusCode, http.StatusConflict)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("POST", "/api/attendance/clock-out", nil)
+	a.HandleClockOut()(httpRec, withPrincipal(r))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	stampModel := &attendanceStampModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(stampModel)
+	is.NoErr(err)
+	is.True(stampModel.ClockOut != "")
+}
+
+func TestHandleRequestAttendanceCorrection(t *testing.T) {
+	is := is.New(t)
+
+	a := NewAttendanceRestHandlers(&shared.Config{}, newAttendanceServiceForTest(t, true))
+
+	requestCorrection := func(body string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/api/attendance/corrections", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Roles:          []string{"ROLE_USER"},
+		}))
+		a.HandleRequestAttendanceCorrection()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := requestCorrection(`{"clockIn": "2024-03-05T09:00:00", "clockOut": "2024-03-05T17:00:00", "reason": "forgot to clock in"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	httpRec = requestCorrection(`{"clockIn": "2024-03-05T09:00:00", "clockOut": "2024-03-05T08:00:00", "reason": "forgot to clock in"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleAttendanceSheetAsCSV(t *testing.T) {
+	is := is.New(t)
+
+	a := NewAttendanceRestHandlers(&shared.Config{}, newAttendanceServiceForTest(t, true))
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/api/attendance/sheets/2024-03?contentType=text/csv", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}))
+
+	router := chi.NewRouter()
+	router.Get("/api/attendance/sheets/{month}", a.HandleAttendanceSheet())
+	router.ServeHTTP(httpRec, r)
+
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(httpRec.Result().Header.Get("Content-Disposition"), `attachment; filename="Attendance_user1_2024-03.csv"`)
+	is.True(strings.HasPrefix(httpRec.Body.String(), "Date;Clock in;Clock out;Duration"))
+}
@@ -0,0 +1,291 @@
+package tracking
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+var attendanceSheetCSVHeaders = []string{"Date", "Clock in", "Clock out", "Duration"}
+
+// AttendanceService records the attendance of users by clock-in and clock-out, for organizations with the attendance feature
+type AttendanceService struct {
+	repositoryTxer       shared.RepositoryTxer
+	featureService       *shared.FeatureService
+	attendanceRepository AttendanceRepository
+}
+
+func NewAttendanceService(repositoryTxer shared.RepositoryTxer, featureService *shared.FeatureService, attendanceRepository AttendanceRepository) *AttendanceService {
+	return &AttendanceService{
+		repositoryTxer:       repositoryTxer,
+		featureService:       featureService,
+		attendanceRepository: attendanceRepository,
+	}
+}
+
+// ClockIn opens a stamp of the principal, a user can only be clocked in once
+func (s *AttendanceService) ClockIn(ctx context.Context, principal *shared.Principal, now time.Time) (*AttendanceStamp, error) {
+	err := s.checkAttendanceEnabled(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	stamp := &AttendanceStamp{
+		ID:             uuid.New(),
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		ClockIn:        now.Truncate(time.Minute),
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			_, err := s.attendanceRepository.FindOpenAttendanceStamp(ctx, principal.OrganizationID, principal.Username)
+			if err == nil {
+				return ErrAlreadyClockedIn
+			}
+			if !errors.Is(err, ErrAttendanceStampNotFound) {
+				return err
+			}
+
+			return s.attendanceRepository.InsertAttendanceStamp(ctx, stamp)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return stamp, nil
+}
+
+// ClockOut closes the open stamp of the principal
+func (s *AttendanceService) ClockOut(ctx context.Context, principal *shared.Principal, now time.Time) (*AttendanceStamp, error) {
+	err := s.checkAttendanceEnabled(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	var stamp *AttendanceStamp
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			openStamp, err := s.attendanceRepository.FindOpenAttendanceStamp(ctx, principal.OrganizationID, principal.Username)
+			if errors.Is(err, ErrAttendanceStampNotFound) {
+				return ErrNotClockedIn
+			}
+			if err != nil {
+				return err
+			}
+
+			clockOut := now.Truncate(time.Minute)
+			openStamp.ClockOut = &clockOut
+			stamp = openStamp
+			return s.attendanceRepository.UpdateAttendanceStamp(ctx, openStamp)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return stamp, nil
+}
+
+// ReadAttendanceSheet reads the stamps of the user in the month, users who are no admins only read their own sheet
+func (s *AttendanceService) ReadAttendanceSheet(ctx context.Context, principal *shared.Principal, username string, month time.Time) (*AttendanceSheet, error) {
+	err := s.checkAttendanceEnabled(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	if username == "" {
+		username = principal.Username
+	}
+	if username != principal.Username && !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	stamps, err := s.attendanceRepository.FindAttendanceStamps(ctx, principal.OrganizationID, username, month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttendanceSheet{
+		Username: username,
+		Month:    month,
+		Stamps:   stamps,
+	}, nil
+}
+
+// RequestAttendanceCorrection requests the correction of a stamp of the principal or to add a missing stamp
+func (s *AttendanceService) RequestAttendanceCorrection(ctx context.Context, principal *shared.Principal, correction *AttendanceCorrection) (*AttendanceCorrection, error) {
+	err := s.checkAttendanceEnabled(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	if correction.StampID != nil {
+		stamp, err := s.attendanceRepository.FindAttendanceStampByID(ctx, principal.OrganizationID, *correction.StampID)
+		if err != nil {
+			return nil, err
+		}
+		if stamp.Username != principal.Username {
+			return nil, ErrAttendanceStampNotFound
+		}
+	}
+
+	correction.ID = uuid.New()
+	correction.OrganizationID = principal.OrganizationID
+	correction.Username = principal.Username
+	correction.Status = AttendanceCorrectionPending
+	correction.RequestedAt = time.Now()
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.attendanceRepository.InsertAttendanceCorrection(ctx, correction)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return correction, nil
+}
+
+// ReadAttendanceCorrections reads the pending corrections of all users for admins and the own corrections for other users
+func (s *AttendanceService) ReadAttendanceCorrections(ctx context.Context, principal *shared.Principal) ([]*AttendanceCorrection, error) {
+	err := s.checkAttendanceEnabled(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	if principal.HasRole("ROLE_ADMIN") {
+		return s.attendanceRepository.FindAttendanceCorrections(ctx, principal.OrganizationID, "", AttendanceCorrectionPending)
+	}
+	return s.attendanceRepository.FindAttendanceCorrections(ctx, principal.OrganizationID, principal.Username, "")
+}
+
+// DecideAttendanceCorrection approves or rejects a pending correction, an approved correction
+// updates the corrected stamp or adds the missing stamp
+func (s *AttendanceService) DecideAttendanceCorrection(ctx context.Context, principal *shared.Principal, correctionID uuid.UUID, approve bool) (*AttendanceCorrection, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	err := s.checkAttendanceEnabled(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	var correction *AttendanceCorrection
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			c, err := s.attendanceRepository.FindAttendanceCorrectionByID(ctx, principal.OrganizationID, correctionID)
+			if err != nil {
+				return err
+			}
+			if !c.IsPending() {
+				return ErrAttendanceCorrectionDecided
+			}
+
+			now := time.Now()
+			c.DecidedBy = principal.Username
+			c.DecidedAt = &now
+			c.Status = AttendanceCorrectionRejected
+			if approve {
+				c.Status = AttendanceCorrectionApproved
+				err := s.applyAttendanceCorrection(ctx, c)
+				if err != nil {
+					return err
+				}
+			}
+
+			correction = c
+			return s.attendanceRepository.UpdateAttendanceCorrection(ctx, c)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return correction, nil
+}
+
+// WriteAttendanceSheetAsCSV writes a line per stamp of the sheet and the total
+func (s *AttendanceService) WriteAttendanceSheetAsCSV(sheet *AttendanceSheet, w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Comma = ';'
+
+	err := csvWriter.Write(attendanceSheetCSVHeaders)
+	if err != nil {
+		return err
+	}
+
+	for _, stamp := range sheet.Stamps {
+		clockOut := ""
+		if stamp.ClockOut != nil {
+			clockOut = time_utils.FormatTime(*stamp.ClockOut)
+		}
+
+		err := csvWriter.Write([]string{
+			time_utils.FormatDate(stamp.ClockIn),
+			time_utils.FormatTime(stamp.ClockIn),
+			clockOut,
+			stamp.DurationFormatted(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = csvWriter.Write([]string{
+		"Total",
+		"",
+		"",
+		sheet.DurationFormatted(),
+	})
+	if err != nil {
+		return err
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func (s *AttendanceService) applyAttendanceCorrection(ctx context.Context, correction *AttendanceCorrection) error {
+	clockOut := correction.ClockOut
+	if correction.StampID == nil {
+		stamp := &AttendanceStamp{
+			ID:             uuid.New(),
+			OrganizationID: correction.OrganizationID,
+			Username:       correction.Username,
+			ClockIn:        correction.ClockIn,
+			ClockOut:       &clockOut,
+		}
+		correction.StampID = &stamp.ID
+		return s.attendanceRepository.InsertAttendanceStamp(ctx, stamp)
+	}
+
+	stamp, err := s.attendanceRepository.FindAttendanceStampByID(ctx, correction.OrganizationID, *correction.StampID)
+	if err != nil {
+		return err
+	}
+
+	stamp.ClockIn = correction.ClockIn
+	stamp.ClockOut = &clockOut
+	return s.attendanceRepository.UpdateAttendanceStamp(ctx, stamp)
+}
+
+func (s *AttendanceService) checkAttendanceEnabled(ctx context.Context, principal *shared.Principal) error {
+	enabled, err := s.featureService.IsEnabled(ctx, principal.OrganizationID, shared.FeatureAttendance)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return shared.ErrFeatureDisabled
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newAttendanceServiceForTest(t *testing.T, enabled bool) *AttendanceService {
+	featureService := shared.NewFeatureService(&shared.Config{}, shared.NewInMemRepositoryTxer(), shared.NewInMemFeatureFlagRepository())
+	if enabled {
+		_, err := featureService.UpdateFeatureFlag(context.Background(), &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          []string{"ROLE_ADMIN"},
+		}, shared.FeatureAttendance, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return NewAttendanceService(shared.NewInMemRepositoryTxer(), featureService, NewInMemAttendanceRepository())
+}
+
+func TestAttendanceDisabled(t *testing.T) {
+	is := is.New(t)
+
+	a := newAttendanceServiceForTest(t, false)
+
+	_, err := a.ClockIn(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}, time.Now())
+	is.Equal(err, shared.ErrFeatureDisabled)
+}
+
+func TestClockInAndClockOut(t *testing.T) {
+	is := is.New(t)
+
+	a := newAttendanceServiceForTest(t, true)
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+	clockIn := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	_, err := a.ClockOut(context.Background(), principal, clockIn)
+	is.Equal(err, ErrNotClockedIn)
+
+	stamp, err := a.ClockIn(context.Background(), principal, clockIn)
+	is.NoErr(err)
+	is.True(stamp.ClockOut == nil)
+
+	_, err = a.ClockIn(context.Background(), principal, clockIn.Add(time.Hour))
+	is.Equal(err, ErrAlreadyClockedIn)
+
+	stamp, err = a.ClockOut(context.Background(), principal, clockIn.Add(8*time.Hour+15*time.Minute))
+	is.NoErr(err)
+	is.Equal(stamp.DurationInMinutes(), 8*60+15)
+
+	sheet, err := a.ReadAttendanceSheet(context.Background(), principal, "", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	is.NoErr(err)
+	is.Equal(len(sheet.Stamps), 1)
+	is.Equal(sheet.DurationFormatted(), "8:15 h")
+
+	_, err = a.ReadAttendanceSheet(context.Background(), principal, "admin", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	is.Equal(err, shared.ErrForbidden)
+}
+
+func TestDecideAttendanceCorrection(t *testing.T) {
+	is := is.New(t)
+
+	a := newAttendanceServiceForTest(t, true)
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	correction, err := a.RequestAttendanceCorrection(context.Background(), user, &AttendanceCorrection{
+		ClockIn:  time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+		ClockOut: time.Date(2024, 3, 5, 17, 0, 0, 0, time.UTC),
+		Reason:   "forgot to clock in",
+	})
+	is.NoErr(err)
+	is.True(correction.IsPending())
+
+	_, err = a.DecideAttendanceCorrection(context.Background(), user, correction.ID, true)
+	is.Equal(err, shared.ErrForbidden)
+
+	corrections, err := a.ReadAttendanceCorrections(context.Background(), admin)
+	is.NoErr(err)
+	is.Equal(len(corrections), 1)
+
+	correction, err = a.DecideAttendanceCorrection(context.Background(), admin, correction.ID, true)
+	is.NoErr(err)
+	is.Equal(correction.Status, AttendanceCorrectionApproved)
+	is.Equal(correction.DecidedBy, "admin")
+
+	_, err = a.DecideAttendanceCorrection(context.Background(), admin, correction.ID, false)
+	is.Equal(err, ErrAttendanceCorrectionDecided)
+
+	sheet, err := a.ReadAttendanceSheet(context.Background(), user, "", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	is.NoErr(err)
+	is.Equal(len(sheet.Stamps), 1)
+	is.Equal(sheet.DurationInMinutesTotal(), 8*60)
+}
+
+func TestWriteAttendanceSheetAsCSV(t *testing.T) {
+	is := is.New(t)
+
+	a := newAttendanceServiceForTest(t, true)
+	clockOut := time.Date(2024, 3, 4, 16, 30, 0, 0, time.UTC)
+
+	buf := &bytes.Buffer{}
+	err := a.WriteAttendanceSheetAsCSV(&AttendanceSheet{
+		Username: "user1",
+		Month:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Stamps: []*AttendanceStamp{
+			{Username: "user1", ClockIn: time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC), ClockOut: &clockOut},
+			{Username: "user1", ClockIn: time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)},
+		},
+	}, buf)
+	is.NoErr(err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	is.Equal(len(lines), 4)
+	is.Equal(lines[0], "Date;Clock in;Clock out;Duration")
+	is.True(strings.HasPrefix(lines[3], "Total;;;8:30 h"))
+}
@@ -0,0 +1,129 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	// workingDaysPerWeek are the days from monday to friday the weekly target is spread over
+	workingDaysPerWeek = 5
+
+	// maxAvailabilityDays is the longest range of days the availability is calculated for
+	maxAvailabilityDays = 366
+)
+
+var ErrAllocationNotFound = shared.NewDomainError("allocation:not-found", http.StatusNotFound, "allocation not found")
+
+// Allocation is the time a user is planned on a project per week from the start to the end day
+type Allocation struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Username       string
+	ProjectID      uuid.UUID
+	Start          time.Time
+	End            time.Time
+	MinutesPerWeek int
+	CreatedAt      time.Time
+}
+
+// AvailabilityDay is the capacity of a user on a day and how much of it is planned
+type AvailabilityDay struct {
+	Day              time.Time
+	CapacityMinutes  int
+	AllocatedMinutes int
+	Absent           bool
+	Holiday          string
+}
+
+// Availability is the capacity of a user from the start to the end day and how much of it is planned
+type Availability struct {
+	Username string
+	Name     string
+	Days     []*AvailabilityDay
+}
+
+type AllocationRepository interface {
+	FindAllocations(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*Allocation, error)
+	FindAllocationByID(ctx context.Context, organizationID, allocationID uuid.UUID) (*Allocation, error)
+	InsertAllocation(ctx context.Context, allocation *Allocation) error
+	DeleteAllocation(ctx context.Context, organizationID, allocationID uuid.UUID) error
+}
+
+// Covers checks whether the allocation plans the user on the day
+func (a *Allocation) Covers(day time.Time) bool {
+	return !day.Before(a.Start) && !day.After(a.End)
+}
+
+// AvailableMinutes is the capacity not yet planned, negative if the user is overbooked
+func (d *AvailabilityDay) AvailableMinutes() int {
+	return d.CapacityMinutes - d.AllocatedMinutes
+}
+
+// CapacityMinutes is the capacity of all days
+func (a *Availability) CapacityMinutes() int {
+	total := 0
+	for _, day := range a.Days {
+		total += day.CapacityMinutes
+	}
+	return total
+}
+
+// AllocatedMinutes is the planned time of all days
+func (a *Availability) AllocatedMinutes() int {
+	total := 0
+	for _, day := range a.Days {
+		total += day.AllocatedMinutes
+	}
+	return total
+}
+
+// AvailableMinutes is the capacity of all days not yet planned, negative if the user is overbooked
+func (a *Availability) AvailableMinutes() int {
+	return a.CapacityMinutes() - a.AllocatedMinutes()
+}
+
+// CalculateAvailability spreads the weekly target and the allocations of the member over the working days
+// from start to end, there is no capacity on weekends, holidays and days the member is absent
+func CalculateAvailability(member *TeamMember, start, end time.Time, weeklyTargetMinutes int, absences []*Absence, holidays []*Holiday, allocations []*Allocation) *Availability {
+	availability := &Availability{
+		Username: member.Username,
+		Name:     member.Name,
+	}
+
+	holidaysByDay := make(map[string]string)
+	for _, holiday := range holidays {
+		holidaysByDay[holiday.Day.Format("2006-01-02")] = holiday.Name
+	}
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		availabilityDay := &AvailabilityDay{
+			Day:     day,
+			Holiday: holidaysByDay[day.Format("2006-01-02")],
+		}
+		availability.Days = append(availability.Days, availabilityDay)
+
+		for _, absence := range absences {
+			if absence.Username == member.Username && !day.Before(absence.Start) && !day.After(absence.End) {
+				availabilityDay.Absent = true
+			}
+		}
+
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday || availabilityDay.Holiday != "" || availabilityDay.Absent {
+			continue
+		}
+
+		availabilityDay.CapacityMinutes = weeklyTargetMinutes / workingDaysPerWeek
+		for _, allocation := range allocations {
+			if allocation.Username == member.Username && allocation.Covers(day) {
+				availabilityDay.AllocatedMinutes += allocation.MinutesPerWeek / workingDaysPerWeek
+			}
+		}
+	}
+
+	return availability
+}
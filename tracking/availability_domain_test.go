@@ -0,0 +1,56 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestCalculateAvailability(t *testing.T) {
+	is := is.New(t)
+
+	// monday to sunday
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 6)
+	member := &TeamMember{Username: "user1", Name: "Ulani User"}
+
+	t.Run("WeeklyTargetOnWorkingDays", func(t *testing.T) {
+		availability := CalculateAvailability(member, start, end, 40*60, nil, nil, nil)
+		is.Equal(len(availability.Days), 7)
+		is.Equal(availability.Days[0].CapacityMinutes, 8*60)
+		is.Equal(availability.Days[5].CapacityMinutes, 0)
+		is.Equal(availability.CapacityMinutes(), 40*60)
+		is.Equal(availability.AvailableMinutes(), 40*60)
+	})
+
+	t.Run("AbsencesAndHolidays", func(t *testing.T) {
+		absences := []*Absence{
+			{Username: "user1", Start: start, End: start.AddDate(0, 0, 1), Kind: AbsenceKindVacation},
+			{Username: "user2", Start: start, End: end, Kind: AbsenceKindVacation},
+		}
+		holidays := []*Holiday{
+			{Day: start.AddDate(0, 0, 4), Name: "Holiday"},
+		}
+
+		availability := CalculateAvailability(member, start, end, 40*60, absences, holidays, nil)
+		is.True(availability.Days[0].Absent)
+		is.Equal(availability.Days[4].Holiday, "Holiday")
+		is.Equal(availability.CapacityMinutes(), 2*8*60)
+	})
+
+	t.Run("Allocations", func(t *testing.T) {
+		allocations := []*Allocation{
+			{Username: "user1", Start: start, End: end, MinutesPerWeek: 20 * 60},
+			{Username: "user1", Start: start.AddDate(0, 0, 4), End: end, MinutesPerWeek: 30 * 60},
+			{Username: "user2", Start: start, End: end, MinutesPerWeek: 40 * 60},
+		}
+
+		availability := CalculateAvailability(member, start, end, 40*60, nil, nil, allocations)
+		is.Equal(availability.Days[0].AllocatedMinutes, 4*60)
+		is.Equal(availability.Days[4].AllocatedMinutes, 10*60)
+		is.Equal(availability.Days[4].AvailableMinutes(), -2*60)
+		is.Equal(availability.AllocatedMinutes(), 26*60)
+		is.Equal(availability.AvailableMinutes(), 14*60)
+	})
+}
@@ -0,0 +1,132 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbAllocationRepository is a SQL database repository for the planned allocations of users on projects
+type DbAllocationRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ AllocationRepository = (*DbAllocationRepository)(nil)
+
+// NewDbAllocationRepository creates a new SQL database repository for allocations
+func NewDbAllocationRepository(connPool *pgxpool.Pool) *DbAllocationRepository {
+	return &DbAllocationRepository{
+		connPool: connPool,
+	}
+}
+
+// FindAllocations reads the allocations overlapping the days from start to end
+func (r *DbAllocationRepository) FindAllocations(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*Allocation, error) {
+	rows, err := shared.SelectAll[allocationRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[allocationRow]()+`
+		 FROM allocations
+		 WHERE org_id = $1 AND end_day >= $2 AND start_day <= $3
+		 ORDER BY start_day ASC, username ASC`,
+		organizationID, start, end,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations := make([]*Allocation, len(rows))
+	for i, row := range rows {
+		allocations[i] = row.toAllocation()
+	}
+	return allocations, nil
+}
+
+func (r *DbAllocationRepository) FindAllocationByID(ctx context.Context, organizationID, allocationID uuid.UUID) (*Allocation, error) {
+	row, err := shared.SelectOne[allocationRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[allocationRow]()+`
+		 FROM allocations
+		 WHERE allocation_id = $1 AND org_id = $2`,
+		allocationID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAllocationNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toAllocation(), nil
+}
+
+func (r *DbAllocationRepository) InsertAllocation(ctx context.Context, allocation *Allocation) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO allocations
+		   (allocation_id, org_id, username, project_id, start_day, end_day, minutes_per_week, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		allocation.ID,
+		allocation.OrganizationID,
+		allocation.Username,
+		allocation.ProjectID,
+		allocation.Start,
+		allocation.End,
+		allocation.MinutesPerWeek,
+		allocation.CreatedAt,
+	)
+	return err
+}
+
+func (r *DbAllocationRepository) DeleteAllocation(ctx context.Context, organizationID, allocationID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM allocations
+		 WHERE allocation_id = $1 AND org_id = $2`,
+		allocationID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrAllocationNotFound
+	}
+	return nil
+}
+
+type allocationRow struct {
+	ID             uuid.UUID `db:"allocation_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	Username       string    `db:"username"`
+	ProjectID      uuid.UUID `db:"project_id"`
+	Start          time.Time `db:"start_day"`
+	End            time.Time `db:"end_day"`
+	MinutesPerWeek int       `db:"minutes_per_week"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (r *allocationRow) toAllocation() *Allocation {
+	return &Allocation{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		ProjectID:      r.ProjectID,
+		Start:          r.Start,
+		End:            r.End,
+		MinutesPerWeek: r.MinutesPerWeek,
+		CreatedAt:      r.CreatedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestAllocationRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	allocationRepository := NewDbAllocationRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	allocation := &Allocation{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		ProjectID:      shared.ProjectIDSample,
+		Start:          start,
+		End:            start.AddDate(0, 0, 4),
+		MinutesPerWeek: 20 * 60,
+		CreatedAt:      time.Now(),
+	}
+
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return allocationRepository.InsertAllocation(ctx, allocation)
+		},
+	)
+	is.NoErr(err)
+
+	allocations, err := allocationRepository.FindAllocations(context.Background(), shared.OrganizationIDSample, start.AddDate(0, 0, 4), start.AddDate(0, 0, 10))
+	is.NoErr(err)
+	is.Equal(len(allocations), 1)
+	is.Equal(allocations[0].MinutesPerWeek, 20*60)
+
+	allocations, err = allocationRepository.FindAllocations(context.Background(), shared.OrganizationIDSample, start.AddDate(0, 0, 5), start.AddDate(0, 0, 10))
+	is.NoErr(err)
+	is.Equal(len(allocations), 0)
+
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return allocationRepository.DeleteAllocation(ctx, shared.OrganizationIDSample, allocation.ID)
+		},
+	)
+	is.NoErr(err)
+
+	_, err = allocationRepository.FindAllocationByID(context.Background(), shared.OrganizationIDSample, allocation.ID)
+	is.Equal(err, ErrAllocationNotFound)
+}
@@ -0,0 +1,73 @@
+package tracking
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemAllocationRepository struct {
+	mu          sync.Mutex
+	allocations []*Allocation
+}
+
+var _ AllocationRepository = (*InMemAllocationRepository)(nil)
+
+func NewInMemAllocationRepository() *InMemAllocationRepository {
+	return &InMemAllocationRepository{}
+}
+
+func (r *InMemAllocationRepository) FindAllocations(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*Allocation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var allocations []*Allocation
+	for _, allocation := range r.allocations {
+		if allocation.OrganizationID == organizationID && !allocation.End.Before(start) && !allocation.Start.After(end) {
+			found := *allocation
+			allocations = append(allocations, &found)
+		}
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].Start.Before(allocations[j].Start)
+	})
+	return allocations, nil
+}
+
+func (r *InMemAllocationRepository) FindAllocationByID(ctx context.Context, organizationID, allocationID uuid.UUID) (*Allocation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, allocation := range r.allocations {
+		if allocation.OrganizationID == organizationID && allocation.ID == allocationID {
+			found := *allocation
+			return &found, nil
+		}
+	}
+	return nil, ErrAllocationNotFound
+}
+
+func (r *InMemAllocationRepository) InsertAllocation(ctx context.Context, allocation *Allocation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *allocation
+	r.allocations = append(r.allocations, &inserted)
+	return nil
+}
+
+func (r *InMemAllocationRepository) DeleteAllocation(ctx context.Context, organizationID, allocationID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, allocation := range r.allocations {
+		if allocation.OrganizationID == organizationID && allocation.ID == allocationID {
+			r.allocations = append(r.allocations[:i], r.allocations[i+1:]...)
+			return nil
+		}
+	}
+	return ErrAllocationNotFound
+}
@@ -0,0 +1,273 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type allocationModel struct {
+	ID             string     `json:"id,omitempty"`
+	Username       string     `json:"username"`
+	ProjectID      string     `json:"projectId"`
+	Start          string     `json:"start"`
+	End            string     `json:"end"`
+	MinutesPerWeek int        `json:"minutesPerWeek"`
+	Links          *hal.Links `json:"_links,omitempty"`
+}
+
+type allocationsModel struct {
+	Embedded struct {
+		AllocationModels []*allocationModel `json:"allocations"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type availabilityDayModel struct {
+	Day              string `json:"day"`
+	CapacityMinutes  int    `json:"capacityMinutes"`
+	AllocatedMinutes int    `json:"allocatedMinutes"`
+	AvailableMinutes int    `json:"availableMinutes"`
+	Absent           bool   `json:"absent"`
+	Holiday          string `json:"holiday,omitempty"`
+}
+
+type availabilityModel struct {
+	Username         string                  `json:"username"`
+	Name             string                  `json:"name"`
+	CapacityMinutes  int                     `json:"capacityMinutes"`
+	AllocatedMinutes int                     `json:"allocatedMinutes"`
+	AvailableMinutes int                     `json:"availableMinutes"`
+	Days             []*availabilityDayModel `json:"days"`
+}
+
+type availabilitiesModel struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Embedded struct {
+		AvailabilityModels []*availabilityModel `json:"availabilities"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type AvailabilityRestHandlers struct {
+	config              *shared.Config
+	availabilityService *AvailabilityService
+}
+
+func NewAvailabilityRestHandlers(config *shared.Config, availabilityService *AvailabilityService) *AvailabilityRestHandlers {
+	return &AvailabilityRestHandlers{
+		config:              config,
+		availabilityService: availabilityService,
+	}
+}
+
+func (a *AvailabilityRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/allocations", a.HandleGetAllocations())
+	r.Post("/allocations", a.HandleCreateAllocation())
+	r.Delete("/allocations/{allocation-id}", a.HandleDeleteAllocation())
+	r.Get("/availability", a.HandleGetAvailability())
+}
+
+func (a *AvailabilityRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetAllocations reads the allocations overlapping the days from start to end,
+// without days the allocations of the next 30 days are read
+func (a *AvailabilityRestHandlers) HandleGetAllocations() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	availabilityService := a.availabilityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		start, end, ok := parseDayRange(w, r)
+		if !ok {
+			return
+		}
+
+		allocations, err := availabilityService.ReadAllocations(r.Context(), principal, start, end)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		allocationModels := make([]*allocationModel, len(allocations))
+		for i, allocation := range allocations {
+			allocationModels[i] = mapToAllocationModel(allocation)
+		}
+
+		allocationsModel := &allocationsModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		allocationsModel.Embedded.AllocationModels = allocationModels
+
+		shared.RenderJSON(w, allocationsModel)
+	}
+}
+
+// HandleCreateAllocation plans a user on a project
+func (a *AvailabilityRestHandlers) HandleCreateAllocation() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	availabilityService := a.availabilityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var allocationModel allocationModel
+		err := json.NewDecoder(r.Body).Decode(&allocationModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "allocation not valid", err)
+			return
+		}
+
+		if allocationModel.Username == "" {
+			shared.RenderValidationProblemJSON(w, "allocation not valid", shared.NewInvalidParam("username", "required", "username is required"))
+			return
+		}
+		projectID, err := uuid.Parse(allocationModel.ProjectID)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "allocation not valid", shared.NewInvalidParam("projectId", "uuid", "projectId must be a project id"))
+			return
+		}
+		start, err := time.Parse("2006-01-02", allocationModel.Start)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "allocation not valid", shared.NewInvalidParam("start", "date", "start must be a date like 2024-03-04"))
+			return
+		}
+		end, err := time.Parse("2006-01-02", allocationModel.End)
+		if err != nil || end.Before(start) {
+			shared.RenderValidationProblemJSON(w, "allocation not valid", shared.NewInvalidParam("end", "date", "end must be a date like 2024-03-04 not before start"))
+			return
+		}
+		if allocationModel.MinutesPerWeek <= 0 || allocationModel.MinutesPerWeek > 7*24*60 {
+			shared.RenderValidationProblemJSON(w, "allocation not valid", shared.NewInvalidParam("minutesPerWeek", "range", "minutesPerWeek must be between 1 and 10080"))
+			return
+		}
+
+		allocation, err := availabilityService.CreateAllocation(r.Context(), principal, &Allocation{
+			Username:       allocationModel.Username,
+			ProjectID:      projectID,
+			Start:          start,
+			End:            end,
+			MinutesPerWeek: allocationModel.MinutesPerWeek,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToAllocationModel(allocation))
+	}
+}
+
+// HandleDeleteAllocation deletes an allocation
+func (a *AvailabilityRestHandlers) HandleDeleteAllocation() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	availabilityService := a.availabilityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		allocationID, err := uuid.Parse(chi.URLParam(r, "allocation-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = availabilityService.DeleteAllocation(r.Context(), principal, allocationID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleGetAvailability reads the availability per user on the days from start to end,
+// without days the availability of the next 30 days is read
+func (a *AvailabilityRestHandlers) HandleGetAvailability() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	availabilityService := a.availabilityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		start, end, ok := parseDayRange(w, r)
+		if !ok {
+			return
+		}
+		if end.Before(start) || end.Sub(start) >= maxAvailabilityDays*24*time.Hour {
+			shared.RenderValidationProblemJSON(w, "days not valid", shared.NewInvalidParam("end", "range", fmt.Sprintf("end must not be before start and at most %v days after it", maxAvailabilityDays-1)))
+			return
+		}
+
+		availabilities, err := availabilityService.ReadAvailability(r.Context(), principal, start, end)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		availabilityModels := make([]*availabilityModel, len(availabilities))
+		for i, availability := range availabilities {
+			availabilityModels[i] = mapToAvailabilityModel(availability)
+		}
+
+		availabilitiesModel := &availabilitiesModel{
+			Start: start.Format("2006-01-02"),
+			End:   end.Format("2006-01-02"),
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		availabilitiesModel.Embedded.AvailabilityModels = availabilityModels
+
+		shared.RenderJSON(w, availabilitiesModel)
+	}
+}
+
+func mapToAllocationModel(allocation *Allocation) *allocationModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/allocations/%s", allocation.ID))
+	return &allocationModel{
+		ID:             allocation.ID.String(),
+		Username:       allocation.Username,
+		ProjectID:      allocation.ProjectID.String(),
+		Start:          allocation.Start.Format("2006-01-02"),
+		End:            allocation.End.Format("2006-01-02"),
+		MinutesPerWeek: allocation.MinutesPerWeek,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+}
+
+func mapToAvailabilityModel(availability *Availability) *availabilityModel {
+	dayModels := make([]*availabilityDayModel, len(availability.Days))
+	for i, day := range availability.Days {
+		dayModels[i] = &availabilityDayModel{
+			Day:              day.Day.Format("2006-01-02"),
+			CapacityMinutes:  day.CapacityMinutes,
+			AllocatedMinutes: day.AllocatedMinutes,
+			AvailableMinutes: day.AvailableMinutes(),
+			Absent:           day.Absent,
+			Holiday:          day.Holiday,
+		}
+	}
+
+	return &availabilityModel{
+		Username:         availability.Username,
+		Name:             availability.Name,
+		CapacityMinutes:  availability.CapacityMinutes(),
+		AllocatedMinutes: availability.AllocatedMinutes(),
+		AvailableMinutes: availability.AvailableMinutes(),
+		Days:             dayModels,
+	}
+}
@@ -0,0 +1,88 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleCreateAllocation(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewAvailabilityRestHandlers(&shared.Config{}, newAvailabilityServiceForTest())
+
+	r, _ := http.NewRequest("POST", "/api/allocations", strings.NewReader(`{"username": "user1", "projectId": "`+shared.ProjectIDSample.String()+`", "start": "2024-03-04", "end": "2024-03-29", "minutesPerWeek": 1200}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleCreateAllocation()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	var allocationModel allocationModel
+	err := json.NewDecoder(httpRec.Body).Decode(&allocationModel)
+	is.NoErr(err)
+	is.Equal(allocationModel.Username, "user1")
+	is.Equal(allocationModel.MinutesPerWeek, 1200)
+}
+
+func TestHandleCreateAllocationNotValid(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewAvailabilityRestHandlers(&shared.Config{}, newAvailabilityServiceForTest())
+
+	r, _ := http.NewRequest("POST", "/api/allocations", strings.NewReader(`{"username": "user1", "projectId": "`+shared.ProjectIDSample.String()+`", "start": "2024-03-04", "end": "2024-03-29", "minutesPerWeek": 0}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleCreateAllocation()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleGetAvailability(t *testing.T) {
+	is := is.New(t)
+
+	a := NewAvailabilityRestHandlers(&shared.Config{WorkingHoursPerWeek: 40}, newAvailabilityServiceForTest())
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}
+
+	t.Run("Week", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/availability?start=2024-03-04&end=2024-03-10", nil)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+
+		a.HandleGetAvailability()(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		var availabilitiesModel availabilitiesModel
+		err := json.NewDecoder(httpRec.Body).Decode(&availabilitiesModel)
+		is.NoErr(err)
+		is.Equal(len(availabilitiesModel.Embedded.AvailabilityModels), 1)
+		is.Equal(availabilitiesModel.Embedded.AvailabilityModels[0].CapacityMinutes, 40*60)
+		is.Equal(len(availabilitiesModel.Embedded.AvailabilityModels[0].Days), 7)
+	})
+
+	t.Run("RangeTooLong", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/availability?start=2024-01-01&end=2025-01-01", nil)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+
+		a.HandleGetAvailability()(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+}
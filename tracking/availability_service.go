@@ -0,0 +1,126 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// AvailabilityService plans the allocations of users on projects and calculates the availability of the team
+// from the weekly target, the absences, the holidays and the allocations
+type AvailabilityService struct {
+	config               *shared.Config
+	repositoryTxer       shared.RepositoryTxer
+	allocationRepository AllocationRepository
+	absenceRepository    AbsenceRepository
+	projectRepository    ProjectRepository
+}
+
+// NewAvailabilityService creates a new service for allocations and availability
+func NewAvailabilityService(config *shared.Config, repositoryTxer shared.RepositoryTxer, allocationRepository AllocationRepository, absenceRepository AbsenceRepository, projectRepository ProjectRepository) *AvailabilityService {
+	return &AvailabilityService{
+		config:               config,
+		repositoryTxer:       repositoryTxer,
+		allocationRepository: allocationRepository,
+		absenceRepository:    absenceRepository,
+		projectRepository:    projectRepository,
+	}
+}
+
+// ReadAllocations reads the allocations from start to end, admins see the allocations of all users
+func (s *AvailabilityService) ReadAllocations(ctx context.Context, principal *shared.Principal, start, end time.Time) ([]*Allocation, error) {
+	allocations, err := s.allocationRepository.FindAllocations(ctx, principal.OrganizationID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	if principal.HasRole("ROLE_ADMIN") {
+		return allocations, nil
+	}
+
+	var ownAllocations []*Allocation
+	for _, allocation := range allocations {
+		if allocation.Username == principal.Username {
+			ownAllocations = append(ownAllocations, allocation)
+		}
+	}
+	return ownAllocations, nil
+}
+
+// CreateAllocation plans a user on a project
+func (s *AvailabilityService) CreateAllocation(ctx context.Context, principal *shared.Principal, allocation *Allocation) (*Allocation, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.projectRepository.FindProjectByID(ctx, principal.OrganizationID, allocation.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	allocation.ID = uuid.New()
+	allocation.OrganizationID = principal.OrganizationID
+	allocation.CreatedAt = time.Now()
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.allocationRepository.InsertAllocation(ctx, allocation)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return allocation, nil
+}
+
+// DeleteAllocation removes an allocation
+func (s *AvailabilityService) DeleteAllocation(ctx context.Context, principal *shared.Principal, allocationID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.allocationRepository.DeleteAllocation(ctx, principal.OrganizationID, allocationID)
+		},
+	)
+}
+
+// ReadAvailability calculates the availability of the team from start to end, admins see all users
+// and everybody else only themselves
+func (s *AvailabilityService) ReadAvailability(ctx context.Context, principal *shared.Principal, start, end time.Time) ([]*Availability, error) {
+	members, err := s.absenceRepository.FindTeamMembers(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	absences, err := s.absenceRepository.FindAbsences(ctx, principal.OrganizationID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.absenceRepository.FindHolidays(ctx, principal.OrganizationID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, err := s.allocationRepository.FindAllocations(ctx, principal.OrganizationID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	weeklyTargetMinutes := s.config.WorkingHoursPerWeek * 60
+
+	var availabilities []*Availability
+	for _, member := range members {
+		if member.Username != principal.Username && !principal.HasRole("ROLE_ADMIN") {
+			continue
+		}
+		availabilities = append(availabilities, CalculateAvailability(member, start, end, weeklyTargetMinutes, absences, holidays, allocations))
+	}
+	return availabilities, nil
+}
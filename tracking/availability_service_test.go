@@ -0,0 +1,91 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func newAvailabilityServiceForTest() *AvailabilityService {
+	return NewAvailabilityService(
+		&shared.Config{WorkingHoursPerWeek: 40},
+		shared.NewInMemRepositoryTxer(),
+		NewInMemAllocationRepository(),
+		NewInMemAbsenceRepository(),
+		NewInMemProjectRepository(),
+	)
+}
+
+func TestAllocations(t *testing.T) {
+	is := is.New(t)
+
+	s := newAvailabilityServiceForTest()
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("CreateAllocationAsUser", func(t *testing.T) {
+		_, err := s.CreateAllocation(context.Background(), user, &Allocation{Username: "user1", ProjectID: shared.ProjectIDSample, Start: start, End: start, MinutesPerWeek: 60})
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	t.Run("CreateAllocationOfUnknownProject", func(t *testing.T) {
+		_, err := s.CreateAllocation(context.Background(), admin, &Allocation{Username: "user1", ProjectID: uuid.New(), Start: start, End: start, MinutesPerWeek: 60})
+		is.True(err != nil)
+	})
+
+	allocation, err := s.CreateAllocation(context.Background(), admin, &Allocation{Username: "admin", ProjectID: shared.ProjectIDSample, Start: start, End: start.AddDate(0, 0, 4), MinutesPerWeek: 10 * 60})
+	is.NoErr(err)
+
+	t.Run("ReadAllocationsOfOtherUser", func(t *testing.T) {
+		allocations, err := s.ReadAllocations(context.Background(), user, start, start.AddDate(0, 0, 7))
+		is.NoErr(err)
+		is.Equal(len(allocations), 0)
+
+		allocations, err = s.ReadAllocations(context.Background(), admin, start, start.AddDate(0, 0, 7))
+		is.NoErr(err)
+		is.Equal(len(allocations), 1)
+	})
+
+	err = s.DeleteAllocation(context.Background(), user, allocation.ID)
+	is.Equal(err, shared.ErrForbidden)
+
+	err = s.DeleteAllocation(context.Background(), admin, allocation.ID)
+	is.NoErr(err)
+
+	err = s.DeleteAllocation(context.Background(), admin, allocation.ID)
+	is.Equal(err, ErrAllocationNotFound)
+}
+
+func TestReadAvailability(t *testing.T) {
+	is := is.New(t)
+
+	s := newAvailabilityServiceForTest()
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 4)
+
+	_, err := s.CreateAllocation(context.Background(), admin, &Allocation{Username: "user1", ProjectID: shared.ProjectIDSample, Start: start, End: end, MinutesPerWeek: 30 * 60})
+	is.NoErr(err)
+
+	t.Run("AsUser", func(t *testing.T) {
+		availabilities, err := s.ReadAvailability(context.Background(), user, start, end)
+		is.NoErr(err)
+		is.Equal(len(availabilities), 1)
+		is.Equal(availabilities[0].Username, "user1")
+		is.Equal(availabilities[0].AvailableMinutes(), 10*60)
+	})
+
+	t.Run("AsAdmin", func(t *testing.T) {
+		availabilities, err := s.ReadAvailability(context.Background(), admin, start, end)
+		is.NoErr(err)
+		is.Equal(len(availabilities), 2)
+	})
+}
@@ -0,0 +1,70 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+)
+
+var ErrProjectBadgeNotFound = shared.NewDomainError("project-badge:not-found", http.StatusNotFound, "project badge not found")
+
+// ProjectBadge grants public access to the tracked time of a project by a secret token,
+// the token is looked up by its hash and stored encrypted
+type ProjectBadge struct {
+	OrganizationID uuid.UUID
+	ProjectID      uuid.UUID
+	TokenHash      string
+	Token          string
+}
+
+// ProjectBadgeStatus is the tracked time of a project in a month as shown on its badge
+type ProjectBadgeStatus struct {
+	Month                  time.Time
+	DurationInMinutesTotal int
+}
+
+type ProjectBadgeRepository interface {
+	FindProjectBadge(ctx context.Context, organizationID, projectID uuid.UUID) (*ProjectBadge, error)
+	FindProjectBadgeByTokenHash(ctx context.Context, tokenHash string) (*ProjectBadge, error)
+	UpdateProjectBadge(ctx context.Context, projectBadge *ProjectBadge) error
+	DeleteProjectBadge(ctx context.Context, organizationID, projectID uuid.UUID) error
+}
+
+// Label is the label of the badge like "tracked Dec 2021"
+func (s *ProjectBadgeStatus) Label() string {
+	return fmt.Sprintf("tracked %s", s.Month.Format("Jan 2006"))
+}
+
+// Message is the tracked time of the badge like "12:30 h"
+func (s *ProjectBadgeStatus) Message() string {
+	return time_utils.FormatMinutesAsDuration(float64(s.DurationInMinutesTotal))
+}
+
+// SVG renders the badge in the style of shields.io
+func (s *ProjectBadgeStatus) SVG() string {
+	label := html.EscapeString(s.Label())
+	message := html.EscapeString(s.Message())
+
+	// text width is estimated as there are no font metrics
+	labelWidth := utf8.RuneCountInString(s.Label())*7 + 10
+	messageWidth := utf8.RuneCountInString(s.Message())*7 + 10
+	width := labelWidth + messageWidth
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[2]s: %[3]s">`+
+		`<title>%[2]s: %[3]s</title>`+
+		`<rect width="%[4]d" height="20" fill="#555"/>`+
+		`<rect x="%[4]d" width="%[5]d" height="20" fill="#007ec6"/>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[6]d" y="14">%[2]s</text>`+
+		`<text x="%[7]d" y="14">%[3]s</text>`+
+		`</g></svg>`,
+		width, label, message, labelWidth, messageWidth, labelWidth/2, labelWidth+messageWidth/2,
+	)
+}
@@ -0,0 +1,25 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestProjectBadgeStatus(t *testing.T) {
+	is := is.New(t)
+
+	projectBadgeStatus := &ProjectBadgeStatus{
+		Month:                  time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC),
+		DurationInMinutesTotal: 750,
+	}
+
+	is.Equal(projectBadgeStatus.Label(), "tracked Dec 2021")
+	is.Equal(projectBadgeStatus.Message(), "12:30 h")
+
+	svg := projectBadgeStatus.SVG()
+	is.True(strings.HasPrefix(svg, "<svg"))
+	is.True(strings.Contains(svg, "<title>tracked Dec 2021: 12:30 h</title>"))
+}
@@ -0,0 +1,131 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbProjectBadgeRepository is a SQL database repository for project badges
+type DbProjectBadgeRepository struct {
+	connPool  *pgxpool.Pool
+	encrypter *shared.Encrypter
+}
+
+var _ ProjectBadgeRepository = (*DbProjectBadgeRepository)(nil)
+
+func init() {
+	shared.RegisterEncryptedColumn("project_badges", "project_id", "token")
+}
+
+// NewDbProjectBadgeRepository creates a new SQL database repository for project badges,
+// the tokens are stored encrypted by the encrypter
+func NewDbProjectBadgeRepository(connPool *pgxpool.Pool, encrypter *shared.Encrypter) *DbProjectBadgeRepository {
+	return &DbProjectBadgeRepository{
+		connPool:  connPool,
+		encrypter: encrypter,
+	}
+}
+
+// FindProjectBadge reads the badge of the project
+func (r *DbProjectBadgeRepository) FindProjectBadge(ctx context.Context, organizationID, projectID uuid.UUID) (*ProjectBadge, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT token_hash, token 
+		 FROM project_badges 
+		 WHERE project_id = $1 AND org_id = $2`,
+		projectID, organizationID,
+	)
+
+	var token string
+	projectBadge := &ProjectBadge{OrganizationID: organizationID, ProjectID: projectID}
+	err := row.Scan(&projectBadge.TokenHash, &token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProjectBadgeNotFound
+		}
+
+		return nil, err
+	}
+
+	projectBadge.Token, err = r.encrypter.Decrypt(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return projectBadge, nil
+}
+
+// FindProjectBadgeByTokenHash reads the badge of the token across all organizations, the token is not decrypted
+func (r *DbProjectBadgeRepository) FindProjectBadgeByTokenHash(ctx context.Context, tokenHash string) (*ProjectBadge, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT org_id, project_id 
+		 FROM project_badges 
+		 WHERE token_hash = $1`,
+		tokenHash,
+	)
+
+	projectBadge := &ProjectBadge{TokenHash: tokenHash}
+	err := row.Scan(&projectBadge.OrganizationID, &projectBadge.ProjectID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProjectBadgeNotFound
+		}
+
+		return nil, err
+	}
+
+	return projectBadge, nil
+}
+
+// UpdateProjectBadge sets the badge of the project, an existing token is replaced
+func (r *DbProjectBadgeRepository) UpdateProjectBadge(ctx context.Context, projectBadge *ProjectBadge) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	token, err := r.encrypter.Encrypt(projectBadge.Token)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO project_badges 
+		   (project_id, org_id, token_hash, token) 
+		 VALUES 
+		   ($1, $2, $3, $4)
+		 ON CONFLICT (project_id) DO UPDATE 
+		 SET token_hash = EXCLUDED.token_hash, token = EXCLUDED.token, created_at = now()`,
+		projectBadge.ProjectID,
+		projectBadge.OrganizationID,
+		projectBadge.TokenHash,
+		token,
+	)
+	return err
+}
+
+// DeleteProjectBadge removes the badge of the project, so its token is no longer valid
+func (r *DbProjectBadgeRepository) DeleteProjectBadge(ctx context.Context, organizationID, projectID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE 
+		 FROM project_badges 
+		 WHERE project_id = $1 AND org_id = $2`,
+		projectID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrProjectBadgeNotFound
+	}
+
+	return nil
+}
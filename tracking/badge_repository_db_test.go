@@ -0,0 +1,90 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestProjectBadgeRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	encrypter, err := shared.NewEncrypter(shared.EncryptionKeySample)
+	is.NoErr(err)
+
+	projectBadgeRepository := NewDbProjectBadgeRepository(connPool, encrypter)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	updateProjectBadge := func(token string) error {
+		return repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return projectBadgeRepository.UpdateProjectBadge(ctx, &ProjectBadge{
+					OrganizationID: shared.OrganizationIDSample,
+					ProjectID:      shared.ProjectIDSample,
+					TokenHash:      shared.HashToken(token),
+					Token:          token,
+				})
+			},
+		)
+	}
+
+	t.Run("UpdateProjectBadge", func(t *testing.T) {
+		err := updateProjectBadge("token-1")
+		is.NoErr(err)
+
+		err = updateProjectBadge("token-2")
+		is.NoErr(err)
+
+		_, err = projectBadgeRepository.FindProjectBadgeByTokenHash(context.Background(), shared.HashToken("token-1"))
+		is.Equal(err, ErrProjectBadgeNotFound)
+
+		projectBadge, err := projectBadgeRepository.FindProjectBadgeByTokenHash(context.Background(), shared.HashToken("token-2"))
+		is.NoErr(err)
+		is.Equal(projectBadge.ProjectID, shared.ProjectIDSample)
+		is.Equal(projectBadge.OrganizationID, shared.OrganizationIDSample)
+
+		projectBadge, err = projectBadgeRepository.FindProjectBadge(context.Background(), shared.OrganizationIDSample, shared.ProjectIDSample)
+		is.NoErr(err)
+		is.Equal(projectBadge.Token, "token-2")
+
+		var storedToken string
+		err = connPool.QueryRow(context.Background(), "SELECT token FROM project_badges WHERE project_id = $1", shared.ProjectIDSample).Scan(&storedToken)
+		is.NoErr(err)
+		is.True(storedToken != "token-2")
+	})
+
+	t.Run("DeleteProjectBadge", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return projectBadgeRepository.DeleteProjectBadge(ctx, shared.OrganizationIDSample, shared.ProjectIDSample)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = projectBadgeRepository.FindProjectBadge(context.Background(), shared.OrganizationIDSample, shared.ProjectIDSample)
+		is.Equal(err, ErrProjectBadgeNotFound)
+	})
+}
@@ -0,0 +1,70 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemProjectBadgeRepository struct {
+	mu            sync.Mutex
+	projectBadges []*ProjectBadge
+}
+
+var _ ProjectBadgeRepository = (*InMemProjectBadgeRepository)(nil)
+
+func NewInMemProjectBadgeRepository() *InMemProjectBadgeRepository {
+	return &InMemProjectBadgeRepository{}
+}
+
+func (r *InMemProjectBadgeRepository) FindProjectBadge(ctx context.Context, organizationID, projectID uuid.UUID) (*ProjectBadge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, b := range r.projectBadges {
+		if b.OrganizationID == organizationID && b.ProjectID == projectID {
+			return b, nil
+		}
+	}
+	return nil, ErrProjectBadgeNotFound
+}
+
+func (r *InMemProjectBadgeRepository) FindProjectBadgeByTokenHash(ctx context.Context, tokenHash string) (*ProjectBadge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, b := range r.projectBadges {
+		if b.TokenHash == tokenHash {
+			return b, nil
+		}
+	}
+	return nil, ErrProjectBadgeNotFound
+}
+
+func (r *InMemProjectBadgeRepository) UpdateProjectBadge(ctx context.Context, projectBadge *ProjectBadge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, b := range r.projectBadges {
+		if b.OrganizationID == projectBadge.OrganizationID && b.ProjectID == projectBadge.ProjectID {
+			r.projectBadges[i] = projectBadge
+			return nil
+		}
+	}
+	r.projectBadges = append(r.projectBadges, projectBadge)
+	return nil
+}
+
+func (r *InMemProjectBadgeRepository) DeleteProjectBadge(ctx context.Context, organizationID, projectID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, b := range r.projectBadges {
+		if b.OrganizationID == organizationID && b.ProjectID == projectID {
+			r.projectBadges = append(r.projectBadges[:i], r.projectBadges[i+1:]...)
+			return nil
+		}
+	}
+	return ErrProjectBadgeNotFound
+}
@@ -0,0 +1,176 @@
+package tracking
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+// projectBadgeMaxAge is the time badges may be cached by READMEs and dashboards
+const projectBadgeMaxAge = 5 * time.Minute
+
+type projectBadgeModel struct {
+	Token string     `json:"token"`
+	Links *hal.Links `json:"_links"`
+}
+
+// projectBadgeStatusModel follows the endpoint schema of shields.io,
+// so badges can also be rendered by shields.io
+type projectBadgeStatusModel struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+type ProjectBadgeRestHandlers struct {
+	config              *shared.Config
+	projectBadgeService *ProjectBadgeService
+}
+
+func NewProjectBadgeRestHandlers(config *shared.Config, projectBadgeService *ProjectBadgeService) *ProjectBadgeRestHandlers {
+	return &ProjectBadgeRestHandlers{
+		config:              config,
+		projectBadgeService: projectBadgeService,
+	}
+}
+
+func (a *ProjectBadgeRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/projects/{project-id}/badge", a.HandleGetProjectBadge())
+	r.Post("/projects/{project-id}/badge", a.HandleCreateProjectBadge())
+	r.Delete("/projects/{project-id}/badge", a.HandleDeleteProjectBadge())
+}
+
+func (a *ProjectBadgeRestHandlers) RegisterOpen(r chi.Router) {
+	r.Get("/badges/{token}", a.HandleGetProjectBadgeStatus())
+	r.Get("/badges/{token}/svg", a.HandleGetProjectBadgeSVG())
+}
+
+// HandleGetProjectBadge reads the badge of a project
+func (a *ProjectBadgeRestHandlers) HandleGetProjectBadge() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectBadgeService := a.projectBadgeService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
+			return
+		}
+
+		projectBadge, err := projectBadgeService.ReadProjectBadge(r.Context(), principal, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToProjectBadgeModel(projectBadge))
+	}
+}
+
+// HandleCreateProjectBadge creates a badge with a new token for a project
+func (a *ProjectBadgeRestHandlers) HandleCreateProjectBadge() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectBadgeService := a.projectBadgeService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
+			return
+		}
+
+		projectBadge, err := projectBadgeService.CreateProjectBadge(r.Context(), principal, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToProjectBadgeModel(projectBadge))
+	}
+}
+
+// HandleDeleteProjectBadge removes the badge of a project
+func (a *ProjectBadgeRestHandlers) HandleDeleteProjectBadge() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectBadgeService := a.projectBadgeService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
+			return
+		}
+
+		err = projectBadgeService.DeleteProjectBadge(r.Context(), principal, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+// HandleGetProjectBadgeStatus reads the tracked time of the project of the badge this month as JSON
+func (a *ProjectBadgeRestHandlers) HandleGetProjectBadgeStatus() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectBadgeService := a.projectBadgeService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectBadgeStatus, err := projectBadgeService.ReadProjectBadgeStatus(r.Context(), chi.URLParam(r, "token"), time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(projectBadgeMaxAge.Seconds())))
+		shared.RenderJSON(w, &projectBadgeStatusModel{
+			SchemaVersion: 1,
+			Label:         projectBadgeStatus.Label(),
+			Message:       projectBadgeStatus.Message(),
+			Color:         "blue",
+		})
+	}
+}
+
+// HandleGetProjectBadgeSVG renders the badge with the tracked time of the project this month as SVG image
+func (a *ProjectBadgeRestHandlers) HandleGetProjectBadgeSVG() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectBadgeService := a.projectBadgeService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectBadgeStatus, err := projectBadgeService.ReadProjectBadgeStatus(r.Context(), chi.URLParam(r, "token"), time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(projectBadgeMaxAge.Seconds())))
+		_, _ = w.Write([]byte(projectBadgeStatus.SVG()))
+	}
+}
+
+func mapToProjectBadgeModel(projectBadge *ProjectBadge) *projectBadgeModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/projects/%s/badge", projectBadge.ProjectID))
+	return &projectBadgeModel{
+		Token: projectBadge.Token,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("project", fmt.Sprintf("/api/projects/%s", projectBadge.ProjectID)),
+			hal.NewLink("badge", fmt.Sprintf("/api/badges/%s", projectBadge.Token)),
+			hal.NewLink("svg", fmt.Sprintf("/api/badges/%s/svg", projectBadge.Token)),
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+}
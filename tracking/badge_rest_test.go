@@ -0,0 +1,87 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleProjectBadge(t *testing.T) {
+	is := is.New(t)
+
+	a := NewProjectBadgeRestHandlers(&shared.Config{}, &ProjectBadgeService{
+		repositoryTxer:         shared.NewInMemRepositoryTxer(),
+		projectBadgeRepository: NewInMemProjectBadgeRepository(),
+		projectRepository:      NewInMemProjectRepository(),
+		activityRepository:     NewInMemActivityRepository(),
+	})
+
+	projectBadgeRequest := func(method string, roles ...string) *http.Request {
+		r, _ := http.NewRequest(method, fmt.Sprintf("/api/projects/%s/badge", shared.ProjectIDSample), nil)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          roles,
+		}))
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	badgeRequest := func(url, token string) *http.Request {
+		r, _ := http.NewRequest("GET", url, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("token", token)
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	httpRec := httptest.NewRecorder()
+	a.HandleCreateProjectBadge()(httpRec, projectBadgeRequest("POST", "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreateProjectBadge()(httpRec, projectBadgeRequest("POST", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	projectBadgeModel := &projectBadgeModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(projectBadgeModel)
+	is.NoErr(err)
+	is.Equal(projectBadgeModel.Links.HrefOf("svg"), fmt.Sprintf("/api/badges/%s/svg", projectBadgeModel.Token))
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetProjectBadgeStatus()(httpRec, badgeRequest("/api/badges/"+projectBadgeModel.Token, projectBadgeModel.Token))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(httpRec.Result().Header.Get("Cache-Control"), "public, max-age=300")
+
+	projectBadgeStatusModel := &projectBadgeStatusModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(projectBadgeStatusModel)
+	is.NoErr(err)
+	is.Equal(projectBadgeStatusModel.SchemaVersion, 1)
+	is.Equal(projectBadgeStatusModel.Message, "1:00 h")
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetProjectBadgeSVG()(httpRec, badgeRequest("/api/badges/"+projectBadgeModel.Token+"/svg", projectBadgeModel.Token))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(httpRec.Result().Header.Get("Content-Type"), "image/svg+xml")
+	is.True(strings.Contains(httpRec.Body.String(), "1:00 h"))
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetProjectBadgeSVG()(httpRec, badgeRequest("/api/badges/unknown/svg", "unknown"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleDeleteProjectBadge()(httpRec, projectBadgeRequest("DELETE", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetProjectBadgeStatus()(httpRec, badgeRequest("/api/badges/"+projectBadgeModel.Token, projectBadgeModel.Token))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
@@ -0,0 +1,130 @@
+package tracking
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// projectBadgeTokenBytes is the length of the random badge tokens
+const projectBadgeTokenBytes = 24
+
+// ProjectBadgeService manages the public badges of projects showing their tracked time
+type ProjectBadgeService struct {
+	repositoryTxer         shared.RepositoryTxer
+	projectBadgeRepository ProjectBadgeRepository
+	projectRepository      ProjectRepository
+	activityRepository     ActivityRepository
+}
+
+// NewProjectBadgeService creates a new service for project badges
+func NewProjectBadgeService(repositoryTxer shared.RepositoryTxer, projectBadgeRepository ProjectBadgeRepository, projectRepository ProjectRepository, activityRepository ActivityRepository) *ProjectBadgeService {
+	return &ProjectBadgeService{
+		repositoryTxer:         repositoryTxer,
+		projectBadgeRepository: projectBadgeRepository,
+		projectRepository:      projectRepository,
+		activityRepository:     activityRepository,
+	}
+}
+
+// ReadProjectBadge reads the badge of a project, only admins see the token
+func (s *ProjectBadgeService) ReadProjectBadge(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) (*ProjectBadge, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.projectBadgeRepository.FindProjectBadge(ctx, principal.OrganizationID, projectID)
+}
+
+// CreateProjectBadge creates a badge with a new token for the project, a previous token is no longer valid
+func (s *ProjectBadgeService) CreateProjectBadge(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) (*ProjectBadge, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.projectRepository.FindProjectByID(ctx, principal.OrganizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newProjectBadgeToken()
+	if err != nil {
+		return nil, err
+	}
+
+	projectBadge := &ProjectBadge{
+		OrganizationID: principal.OrganizationID,
+		ProjectID:      projectID,
+		TokenHash:      shared.HashToken(token),
+		Token:          token,
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.projectBadgeRepository.UpdateProjectBadge(ctx, projectBadge)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return projectBadge, nil
+}
+
+// DeleteProjectBadge removes the badge of the project
+func (s *ProjectBadgeService) DeleteProjectBadge(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.projectBadgeRepository.DeleteProjectBadge(ctx, principal.OrganizationID, projectID)
+		},
+	)
+}
+
+// ReadProjectBadgeStatus reads the tracked time of the project of the badge token in the month of now,
+// it's public so nothing but the total time is revealed
+func (s *ProjectBadgeService) ReadProjectBadgeStatus(ctx context.Context, token string, now time.Time) (*ProjectBadgeStatus, error) {
+	projectBadge, err := s.projectBadgeRepository.FindProjectBadgeByTokenHash(ctx, shared.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = shared.WithOrganizationID(ctx, projectBadge.OrganizationID)
+
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	reportItems, err := s.activityRepository.ProjectReport(ctx, &ActivitiesFilter{
+		Start:          month,
+		End:            month.AddDate(0, 1, 0),
+		OrganizationID: projectBadge.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	projectBadgeStatus := &ProjectBadgeStatus{
+		Month: month,
+	}
+	for _, reportItem := range reportItems {
+		if reportItem.ProjectID == projectBadge.ProjectID {
+			projectBadgeStatus.DurationInMinutesTotal += reportItem.DurationInMinutesTotal
+		}
+	}
+	return projectBadgeStatus, nil
+}
+
+func newProjectBadgeToken() (string, error) {
+	token := make([]byte, projectBadgeTokenBytes)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
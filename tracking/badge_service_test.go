@@ -0,0 +1,67 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestProjectBadgeService(t *testing.T) {
+	is := is.New(t)
+
+	projectBadgeService := &ProjectBadgeService{
+		repositoryTxer:         shared.NewInMemRepositoryTxer(),
+		projectBadgeRepository: NewInMemProjectBadgeRepository(),
+		projectRepository:      NewInMemProjectRepository(),
+		activityRepository:     NewInMemActivityRepository(),
+	}
+
+	adminPrincipal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+	userPrincipal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_USER"},
+	}
+
+	t.Run("user must not create badge", func(t *testing.T) {
+		_, err := projectBadgeService.CreateProjectBadge(context.Background(), userPrincipal, shared.ProjectIDSample)
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	t.Run("badge of unknown project", func(t *testing.T) {
+		_, err := projectBadgeService.CreateProjectBadge(context.Background(), adminPrincipal, uuid.New())
+		is.Equal(err, ErrProjectNotFound)
+	})
+
+	t.Run("new token replaces previous token", func(t *testing.T) {
+		projectBadge, err := projectBadgeService.CreateProjectBadge(context.Background(), adminPrincipal, shared.ProjectIDSample)
+		is.NoErr(err)
+		is.Equal(len(projectBadge.Token), 32)
+
+		newProjectBadge, err := projectBadgeService.CreateProjectBadge(context.Background(), adminPrincipal, shared.ProjectIDSample)
+		is.NoErr(err)
+		is.True(newProjectBadge.Token != projectBadge.Token)
+
+		_, err = projectBadgeService.ReadProjectBadgeStatus(context.Background(), projectBadge.Token, time.Now())
+		is.Equal(err, ErrProjectBadgeNotFound)
+
+		projectBadgeStatus, err := projectBadgeService.ReadProjectBadgeStatus(context.Background(), newProjectBadge.Token, time.Date(2021, 12, 15, 10, 0, 0, 0, time.UTC))
+		is.NoErr(err)
+		is.Equal(projectBadgeStatus.Month, time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC))
+		is.Equal(projectBadgeStatus.DurationInMinutesTotal, 60)
+	})
+
+	t.Run("delete badge", func(t *testing.T) {
+		err := projectBadgeService.DeleteProjectBadge(context.Background(), adminPrincipal, shared.ProjectIDSample)
+		is.NoErr(err)
+
+		_, err = projectBadgeService.ReadProjectBadge(context.Background(), adminPrincipal, shared.ProjectIDSample)
+		is.Equal(err, ErrProjectBadgeNotFound)
+	})
+}
@@ -0,0 +1,196 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+)
+
+const (
+	maxBreakDeductionRules = 5
+	maxBreakMinutes        = 4 * 60
+)
+
+// BreakDeductionRule requires a break on days with more than the working time
+type BreakDeductionRule struct {
+	AfterMinutes int
+	BreakMinutes int
+}
+
+// BreakPolicy controls the automatic deduction of breaks in an organization, with auto deduction
+// the part of the required break not taken between the activities of a day is deducted from the tracked time
+type BreakPolicy struct {
+	OrganizationID uuid.UUID
+	AutoDeduction  bool
+	Rules          []*BreakDeductionRule
+}
+
+// BreakDeduction is the time deducted as break from a day of a user
+type BreakDeduction struct {
+	Username string
+	Date     time.Time
+	Minutes  int
+}
+
+type BreakPolicyRepository interface {
+	FindBreakPolicy(ctx context.Context, organizationID uuid.UUID) (*BreakPolicy, error)
+	UpdateBreakPolicy(ctx context.Context, policy *BreakPolicy) error
+}
+
+// NewDefaultBreakPolicy is the policy of organizations without a policy, the auto deduction is off
+// with rules as in the working time directive
+func NewDefaultBreakPolicy(organizationID uuid.UUID) *BreakPolicy {
+	return &BreakPolicy{
+		OrganizationID: organizationID,
+		Rules: []*BreakDeductionRule{
+			{AfterMinutes: 6 * 60, BreakMinutes: 30},
+			{AfterMinutes: 9 * 60, BreakMinutes: 45},
+		},
+	}
+}
+
+// ValidateBreakDeductionRules checks that the rules have a working time of at most a day and a break of at most 4 hours
+func ValidateBreakDeductionRules(rules []*BreakDeductionRule) error {
+	if len(rules) > maxBreakDeductionRules {
+		return shared.NewInvalidParam("rules", "max", fmt.Sprintf("at most %v rules are allowed", maxBreakDeductionRules))
+	}
+
+	for _, rule := range rules {
+		if rule.AfterMinutes <= 0 || rule.AfterMinutes > 24*60 {
+			return shared.NewInvalidParam("rules", "range", fmt.Sprintf("working time of %v minutes must be between 1 and %v minutes", rule.AfterMinutes, 24*60))
+		}
+		if rule.BreakMinutes <= 0 || rule.BreakMinutes > maxBreakMinutes {
+			return shared.NewInvalidParam("rules", "range", fmt.Sprintf("break of %v minutes must be between 1 and %v minutes", rule.BreakMinutes, maxBreakMinutes))
+		}
+	}
+	return nil
+}
+
+// RequiredBreakMinutes is the break of the rule with the longest working time exceeded
+func (p *BreakPolicy) RequiredBreakMinutes(workingMinutes int) int {
+	requiredBreakMinutes := 0
+	afterMinutes := 0
+	for _, rule := range p.Rules {
+		if workingMinutes > rule.AfterMinutes && rule.AfterMinutes >= afterMinutes {
+			requiredBreakMinutes = rule.BreakMinutes
+			afterMinutes = rule.AfterMinutes
+		}
+	}
+	return requiredBreakMinutes
+}
+
+// DeductionMinutes is the part of the required break not taken, nothing is deducted without auto deduction
+func (p *BreakPolicy) DeductionMinutes(workingMinutes, breakMinutes int) int {
+	if p == nil || !p.AutoDeduction {
+		return 0
+	}
+
+	deductionMinutes := p.RequiredBreakMinutes(workingMinutes) - breakMinutes
+	if deductionMinutes <= 0 {
+		return 0
+	}
+	if deductionMinutes > workingMinutes {
+		return workingMinutes
+	}
+	return deductionMinutes
+}
+
+// Deductions are the breaks deducted from the days of the users who tracked the activities
+func (p *BreakPolicy) Deductions(activities []*Activity) []*BreakDeduction {
+	var deductions []*BreakDeduction
+	for _, day := range workingDaysOf(activities) {
+		deductionMinutes := p.DeductionMinutes(day.WorkingMinutes, day.BreakMinutes)
+		if deductionMinutes == 0 {
+			continue
+		}
+		deductions = append(deductions, &BreakDeduction{
+			Username: day.Username,
+			Date:     day.Date,
+			Minutes:  deductionMinutes,
+		})
+	}
+	return deductions
+}
+
+// sortBreakDeductionRules sorts the rules by working time
+func sortBreakDeductionRules(rules []*BreakDeductionRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].AfterMinutes < rules[j].AfterMinutes
+	})
+}
+
+// DeductBreaksFromTimeReport deducts the breaks from the tracked time of the report items of the days of the breaks
+func DeductBreaksFromTimeReport(reportItems []*ActivityTimeReportItem, deductions []*BreakDeduction, aggregateBy string) {
+	deductionMinutesByPeriod := make(map[[3]int]int)
+	for _, deduction := range deductions {
+		deductionMinutesByPeriod[timeReportPeriodOf(deduction.Date, aggregateBy)] += deduction.Minutes
+	}
+
+	for _, reportItem := range reportItems {
+		reportItem.DurationInMinutesTotal -= deductionMinutesByPeriod[reportItem.period(aggregateBy)]
+		if reportItem.DurationInMinutesTotal < 0 {
+			reportItem.DurationInMinutesTotal = 0
+		}
+	}
+}
+
+// DeductBreaksFromUtilization deducts the breaks from the tracked time of the users and weeks,
+// breaks are deducted from the non-billable time first
+func DeductBreaksFromUtilization(users, trend []*ActivityUtilizationReportItem, deductions []*BreakDeduction) {
+	deductionMinutesByUsername := make(map[string]int)
+	deductionMinutesByWeek := make(map[[3]int]int)
+	for _, deduction := range deductions {
+		deductionMinutesByUsername[deduction.Username] += deduction.Minutes
+		deductionMinutesByWeek[timeReportPeriodOf(deduction.Date, "week")] += deduction.Minutes
+	}
+
+	for _, user := range users {
+		user.deductMinutes(deductionMinutesByUsername[user.Username])
+	}
+	for _, week := range trend {
+		week.deductMinutes(deductionMinutesByWeek[[3]int{week.Year, week.Week, 0}])
+	}
+}
+
+func (i *ActivityUtilizationReportItem) deductMinutes(minutes int) {
+	i.DurationInMinutesTotal -= minutes
+	if i.DurationInMinutesTotal < 0 {
+		i.DurationInMinutesTotal = 0
+	}
+	if i.BillableDurationInMinutesTotal > i.DurationInMinutesTotal {
+		i.BillableDurationInMinutesTotal = i.DurationInMinutesTotal
+	}
+}
+
+func (i *ActivityTimeReportItem) period(aggregateBy string) [3]int {
+	switch aggregateBy {
+	case "week":
+		return [3]int{i.Year, i.Week, 0}
+	case "month":
+		return [3]int{i.Year, i.Month, 0}
+	case "quarter":
+		return [3]int{i.Year, i.Quarter, 0}
+	default:
+		return [3]int{i.Year, i.Month, i.Day}
+	}
+}
+
+// timeReportPeriodOf is the period of a report item the date belongs to, weeks belong to the calendar year like in the reports
+func timeReportPeriodOf(date time.Time, aggregateBy string) [3]int {
+	switch aggregateBy {
+	case "week":
+		_, week := date.ISOWeek()
+		return [3]int{date.Year(), week, 0}
+	case "month":
+		return [3]int{date.Year(), int(date.Month()), 0}
+	case "quarter":
+		return [3]int{date.Year(), time_utils.Quarter(date), 0}
+	default:
+		return [3]int{date.Year(), int(date.Month()), date.Day()}
+	}
+}
@@ -0,0 +1,82 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestBreakPolicyDeductionMinutes(t *testing.T) {
+	is := is.New(t)
+
+	policy := NewDefaultBreakPolicy(shared.OrganizationIDSample)
+	is.Equal(policy.DeductionMinutes(7*60, 0), 0)
+
+	policy.AutoDeduction = true
+	is.Equal(policy.DeductionMinutes(6*60, 0), 0)
+	is.Equal(policy.DeductionMinutes(7*60, 0), 30)
+	is.Equal(policy.DeductionMinutes(7*60, 20), 10)
+	is.Equal(policy.DeductionMinutes(7*60, 30), 0)
+	is.Equal(policy.DeductionMinutes(10*60, 30), 15)
+}
+
+func TestBreakPolicyDeductions(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	policy := NewDefaultBreakPolicy(shared.OrganizationIDSample)
+	policy.AutoDeduction = true
+
+	deductions := policy.Deductions([]*Activity{
+		workingTimeActivity("user1", day.Add(8*time.Hour), day.Add(12*time.Hour)),
+		workingTimeActivity("user1", day.Add(12*time.Hour+20*time.Minute), day.Add(15*time.Hour)),
+		workingTimeActivity("user2", day.Add(8*time.Hour), day.Add(12*time.Hour)),
+	})
+	is.Equal(len(deductions), 1)
+	is.Equal(deductions[0].Username, "user1")
+	is.Equal(deductions[0].Date, day)
+	is.Equal(deductions[0].Minutes, 10)
+}
+
+func TestValidateBreakDeductionRules(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(ValidateBreakDeductionRules([]*BreakDeductionRule{{AfterMinutes: 360, BreakMinutes: 30}}))
+	is.True(ValidateBreakDeductionRules([]*BreakDeductionRule{{AfterMinutes: 0, BreakMinutes: 30}}) != nil)
+	is.True(ValidateBreakDeductionRules([]*BreakDeductionRule{{AfterMinutes: 360, BreakMinutes: 300}}) != nil)
+}
+
+func TestDeductBreaksFromTimeReport(t *testing.T) {
+	is := is.New(t)
+
+	reportItems := []*ActivityTimeReportItem{
+		{Year: 2024, Week: 10, DurationInMinutesTotal: 600},
+		{Year: 2024, Week: 11, DurationInMinutesTotal: 20},
+	}
+	DeductBreaksFromTimeReport(reportItems, []*BreakDeduction{
+		{Username: "user1", Date: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Minutes: 30},
+		{Username: "user2", Date: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), Minutes: 15},
+		{Username: "user1", Date: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), Minutes: 30},
+	}, "week")
+
+	is.Equal(reportItems[0].DurationInMinutesTotal, 555)
+	is.Equal(reportItems[1].DurationInMinutesTotal, 0)
+}
+
+func TestParseBreakDeductionRules(t *testing.T) {
+	is := is.New(t)
+
+	rules, err := parseBreakDeductionRules(formatBreakDeductionRules(NewDefaultBreakPolicy(shared.OrganizationIDSample).Rules))
+	is.NoErr(err)
+	is.Equal(len(rules), 2)
+	is.Equal(*rules[1], BreakDeductionRule{AfterMinutes: 540, BreakMinutes: 45})
+
+	rules, err = parseBreakDeductionRules("")
+	is.NoErr(err)
+	is.Equal(len(rules), 0)
+
+	_, err = parseBreakDeductionRules("360")
+	is.True(err != nil)
+}
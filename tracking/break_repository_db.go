@@ -0,0 +1,99 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbBreakPolicyRepository is a SQL database repository for break policies
+type DbBreakPolicyRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ BreakPolicyRepository = (*DbBreakPolicyRepository)(nil)
+
+// NewDbBreakPolicyRepository creates a new SQL database repository for break policies
+func NewDbBreakPolicyRepository(connPool *pgxpool.Pool) *DbBreakPolicyRepository {
+	return &DbBreakPolicyRepository{
+		connPool: connPool,
+	}
+}
+
+// FindBreakPolicy reads the break policy of the organization, organizations without a policy get the default policy
+func (r *DbBreakPolicyRepository) FindBreakPolicy(ctx context.Context, organizationID uuid.UUID) (*BreakPolicy, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT auto_deduction, rules 
+		 FROM break_policies 
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+
+	var rules string
+	policy := &BreakPolicy{OrganizationID: organizationID}
+	err := row.Scan(&policy.AutoDeduction, &rules)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewDefaultBreakPolicy(organizationID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	policy.Rules, err = parseBreakDeductionRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdateBreakPolicy sets the break policy of the organization
+func (r *DbBreakPolicyRepository) UpdateBreakPolicy(ctx context.Context, policy *BreakPolicy) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO break_policies 
+		   (org_id, auto_deduction, rules) 
+		 VALUES 
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id) DO UPDATE 
+		 SET auto_deduction = EXCLUDED.auto_deduction, rules = EXCLUDED.rules`,
+		policy.OrganizationID,
+		policy.AutoDeduction,
+		formatBreakDeductionRules(policy.Rules),
+	)
+	return err
+}
+
+// formatBreakDeductionRules formats the rules as comma separated list of working and break minutes like 360:30,540:45
+func formatBreakDeductionRules(rules []*BreakDeductionRule) string {
+	formattedRules := make([]string, len(rules))
+	for i, rule := range rules {
+		formattedRules[i] = fmt.Sprintf("%d:%d", rule.AfterMinutes, rule.BreakMinutes)
+	}
+	return strings.Join(formattedRules, ",")
+}
+
+func parseBreakDeductionRules(formattedRules string) ([]*BreakDeductionRule, error) {
+	var rules []*BreakDeductionRule
+	for _, formattedRule := range strings.Split(formattedRules, ",") {
+		if formattedRule == "" {
+			continue
+		}
+
+		rule := &BreakDeductionRule{}
+		_, err := fmt.Sscanf(formattedRule, "%d:%d", &rule.AfterMinutes, &rule.BreakMinutes)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,62 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestBreakPolicyRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	breakPolicyRepository := NewDbBreakPolicyRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("FindDefaultBreakPolicy", func(t *testing.T) {
+		policy, err := breakPolicyRepository.FindBreakPolicy(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(!policy.AutoDeduction)
+		is.Equal(len(policy.Rules), 2)
+	})
+
+	t.Run("UpdateBreakPolicy", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return breakPolicyRepository.UpdateBreakPolicy(ctx, &BreakPolicy{
+					OrganizationID: shared.OrganizationIDSample,
+					AutoDeduction:  true,
+					Rules:          []*BreakDeductionRule{{AfterMinutes: 480, BreakMinutes: 60}},
+				})
+			},
+		)
+		is.NoErr(err)
+
+		policy, err := breakPolicyRepository.FindBreakPolicy(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(policy.AutoDeduction)
+		is.Equal(len(policy.Rules), 1)
+		is.Equal(policy.Rules[0].BreakMinutes, 60)
+	})
+}
@@ -0,0 +1,42 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemBreakPolicyRepository struct {
+	mu       sync.Mutex
+	policies map[uuid.UUID]*BreakPolicy
+}
+
+var _ BreakPolicyRepository = (*InMemBreakPolicyRepository)(nil)
+
+func NewInMemBreakPolicyRepository() *InMemBreakPolicyRepository {
+	return &InMemBreakPolicyRepository{
+		policies: make(map[uuid.UUID]*BreakPolicy),
+	}
+}
+
+func (r *InMemBreakPolicyRepository) FindBreakPolicy(ctx context.Context, organizationID uuid.UUID) (*BreakPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	policy, ok := r.policies[organizationID]
+	if !ok {
+		return NewDefaultBreakPolicy(organizationID), nil
+	}
+	found := *policy
+	return &found, nil
+}
+
+func (r *InMemBreakPolicyRepository) UpdateBreakPolicy(ctx context.Context, policy *BreakPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := *policy
+	r.policies[policy.OrganizationID] = &updated
+	return nil
+}
@@ -0,0 +1,117 @@
+package tracking
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type breakPolicyModel struct {
+	AutoDeduction bool                       `json:"autoDeduction"`
+	Rules         []*breakDeductionRuleModel `json:"rules"`
+	Links         *hal.Links                 `json:"_links,omitempty"`
+}
+
+type breakDeductionRuleModel struct {
+	AfterMinutes int `json:"afterMinutes"`
+	BreakMinutes int `json:"breakMinutes"`
+}
+
+type BreakRestHandlers struct {
+	config          *shared.Config
+	activityService *ActitivityService
+}
+
+func NewBreakRestHandlers(config *shared.Config, activityService *ActitivityService) *BreakRestHandlers {
+	return &BreakRestHandlers{
+		config:          config,
+		activityService: activityService,
+	}
+}
+
+func (a *BreakRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/break-policy", a.HandleGetBreakPolicy())
+	r.Put("/break-policy", a.HandleUpdateBreakPolicy())
+}
+
+func (a *BreakRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetBreakPolicy reads the break policy of the organization, so clients know whether breaks are deducted
+func (a *BreakRestHandlers) HandleGetBreakPolicy() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		policy, err := activityService.ReadBreakPolicy(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToBreakPolicyModel(policy, r.RequestURI))
+	}
+}
+
+// HandleUpdateBreakPolicy sets the break policy of the organization
+func (a *BreakRestHandlers) HandleUpdateBreakPolicy() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var policyModel breakPolicyModel
+		err := json.NewDecoder(r.Body).Decode(&policyModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "break policy not valid", err)
+			return
+		}
+
+		rules := make([]*BreakDeductionRule, len(policyModel.Rules))
+		for i, ruleModel := range policyModel.Rules {
+			rules[i] = &BreakDeductionRule{
+				AfterMinutes: ruleModel.AfterMinutes,
+				BreakMinutes: ruleModel.BreakMinutes,
+			}
+		}
+
+		err = ValidateBreakDeductionRules(rules)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "break policy not valid", err)
+			return
+		}
+
+		policy, err := activityService.UpdateBreakPolicy(r.Context(), principal, &BreakPolicy{
+			AutoDeduction: policyModel.AutoDeduction,
+			Rules:         rules,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToBreakPolicyModel(policy, r.RequestURI))
+	}
+}
+
+func mapToBreakPolicyModel(policy *BreakPolicy, selfLink string) *breakPolicyModel {
+	ruleModels := make([]*breakDeductionRuleModel, len(policy.Rules))
+	for i, rule := range policy.Rules {
+		ruleModels[i] = &breakDeductionRuleModel{
+			AfterMinutes: rule.AfterMinutes,
+			BreakMinutes: rule.BreakMinutes,
+		}
+	}
+
+	return &breakPolicyModel{
+		AutoDeduction: policy.AutoDeduction,
+		Rules:         ruleModels,
+		Links: hal.NewLinks(
+			hal.NewSelfLink(selfLink),
+		),
+	}
+}
@@ -0,0 +1,59 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleBreakPolicy(t *testing.T) {
+	is := is.New(t)
+
+	a := NewBreakRestHandlers(&shared.Config{}, &ActitivityService{
+		repositoryTxer:        shared.NewInMemRepositoryTxer(),
+		activityRepository:    NewInMemActivityRepository(),
+		breakPolicyRepository: NewInMemBreakPolicyRepository(),
+	})
+
+	updatePolicy := func(body string, roles ...string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/api/break-policy", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          roles,
+		}))
+		a.HandleUpdateBreakPolicy()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := updatePolicy(`{"autoDeduction": true, "rules": [{"afterMinutes": 540, "breakMinutes": 45}, {"afterMinutes": 360, "breakMinutes": 30}]}`, "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = updatePolicy(`{"autoDeduction": true, "rules": [{"afterMinutes": 360, "breakMinutes": 0}]}`, "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = updatePolicy(`{"autoDeduction": false}`, "ROLE_USER")
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/api/break-policy", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_USER"},
+	}))
+	a.HandleGetBreakPolicy()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	policyModel := &breakPolicyModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(policyModel)
+	is.NoErr(err)
+	is.True(policyModel.AutoDeduction)
+	is.Equal(len(policyModel.Rules), 2)
+	is.Equal(policyModel.Rules[0].AfterMinutes, 360)
+}
@@ -0,0 +1,201 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// ActivitySource is where an activity which was not tracked by hand comes from
+type ActivitySource string
+
+const (
+	ActivitySourceImport    ActivitySource = "import"
+	ActivitySourceWebhook   ActivitySource = "webhook"
+	ActivitySourceExtension ActivitySource = "extension"
+	ActivitySourceSync      ActivitySource = "sync"
+	ActivitySourceScanTag   ActivitySource = "scan-tag"
+	ActivitySourceQuickAdd  ActivitySource = "quick-add"
+
+	// maxCategorizationRules is the maximum number of categorization rules of an organization
+	maxCategorizationRules         = 50
+	maxCategorizationRuleKeywords  = 20
+	maxCategorizationPatternLength = 200
+)
+
+var activitySources = []ActivitySource{
+	ActivitySourceImport,
+	ActivitySourceWebhook,
+	ActivitySourceExtension,
+	ActivitySourceSync,
+	ActivitySourceScanTag,
+	ActivitySourceQuickAdd,
+}
+
+var (
+	ErrCategorizationRuleNotFound     = shared.NewDomainError("categorization-rule:not-found", http.StatusNotFound, "categorization rule not found")
+	ErrCategorizationRuleLimitReached = shared.NewDomainError("categorization-rule:limit-reached", http.StatusConflict, "organization has the maximum number of categorization rules")
+	ErrCategorizationRuleOrderInvalid = shared.NewDomainError("categorization-rule:order-invalid", http.StatusBadRequest, "order must contain each categorization rule exactly once")
+)
+
+// CategorizationRule sets the project of activities from imports and integrations if all of its conditions match,
+// the description contains any of the keywords or matches the pattern and the activity comes from the source.
+// Rules are applied by their position, the first matching rule wins.
+type CategorizationRule struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Name           string
+	Position       int
+	Keywords       []string
+	Pattern        string
+	Source         ActivitySource
+	ProjectID      uuid.UUID
+
+	compiledPattern *regexp.Regexp
+}
+
+type CategorizationRuleRepository interface {
+	FindCategorizationRules(ctx context.Context, organizationID uuid.UUID) ([]*CategorizationRule, error)
+	FindCategorizationRuleByID(ctx context.Context, organizationID, ruleID uuid.UUID) (*CategorizationRule, error)
+	InsertCategorizationRule(ctx context.Context, rule *CategorizationRule) error
+	UpdateCategorizationRule(ctx context.Context, rule *CategorizationRule) error
+	UpdateCategorizationRulePositions(ctx context.Context, organizationID uuid.UUID, ruleIDs []uuid.UUID) error
+	DeleteCategorizationRuleByID(ctx context.Context, organizationID, ruleID uuid.UUID) error
+}
+
+// IsValidActivitySource checks whether the source is known, the empty source means any source
+func IsValidActivitySource(source ActivitySource) bool {
+	return source == "" || slices.Contains(activitySources, source)
+}
+
+// Validate checks that the rule has a name and at least one condition, keywords are
+// stored comma separated so they must not contain commas
+func (r *CategorizationRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return shared.NewInvalidParam("name", "required", "name is required")
+	}
+	if utf8.RuneCountInString(r.Name) > 100 {
+		return shared.NewInvalidParam("name", "max", "name must not be longer than 100 characters")
+	}
+
+	if len(r.Keywords) == 0 && r.Pattern == "" && r.Source == "" {
+		return shared.NewInvalidParam("keywords", "required", "at least one of keywords, pattern or source is required")
+	}
+
+	if len(r.Keywords) > maxCategorizationRuleKeywords {
+		return shared.NewInvalidParam("keywords", "max", "must not have more than 20 keywords")
+	}
+	for _, keyword := range r.Keywords {
+		if utf8.RuneCountInString(strings.TrimSpace(keyword)) < 2 || utf8.RuneCountInString(keyword) > 100 {
+			return shared.NewInvalidParam("keywords", "len", "keywords must have between 2 and 100 characters")
+		}
+		if strings.Contains(keyword, ",") {
+			return shared.NewInvalidParam("keywords", "excludes", "keywords must not contain commas")
+		}
+	}
+
+	if len(r.Pattern) > maxCategorizationPatternLength {
+		return shared.NewInvalidParam("pattern", "max", "pattern must not be longer than 200 characters")
+	}
+	if r.Pattern != "" {
+		_, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return shared.NewInvalidParam("pattern", "regexp", "pattern must be a valid regular expression")
+		}
+	}
+
+	if !IsValidActivitySource(r.Source) {
+		return shared.NewInvalidParam("source", "oneof", "source must be one of import, webhook, extension, sync, scan-tag or quick-add")
+	}
+	return nil
+}
+
+// Matches checks whether all conditions of the rule match the description and source of an activity,
+// keywords are matched ignoring case
+func (r *CategorizationRule) Matches(description string, source ActivitySource) bool {
+	if r.Source != "" && r.Source != source {
+		return false
+	}
+
+	if len(r.Keywords) > 0 {
+		lowerDescription := strings.ToLower(description)
+		matchesKeyword := slices.ContainsFunc(r.Keywords, func(keyword string) bool {
+			return strings.Contains(lowerDescription, strings.ToLower(strings.TrimSpace(keyword)))
+		})
+		if !matchesKeyword {
+			return false
+		}
+	}
+
+	if r.Pattern != "" {
+		if r.compiledPattern == nil {
+			pattern, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return false
+			}
+			r.compiledPattern = pattern
+		}
+		if !r.compiledPattern.MatchString(description) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Categorization categorizes activities from imports and integrations by the rules of the organization
+type Categorization struct {
+	Rules []*CategorizationRule
+}
+
+// RuleFor finds the first rule by position matching the description and source, nil if none matches
+func (c *Categorization) RuleFor(description string, source ActivitySource) *CategorizationRule {
+	for _, rule := range c.Rules {
+		if rule.Matches(description, source) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Categorize sets the project of the first matching rule, it takes precedence over the project
+// given by the import or integration. Activities tracked by hand have no source and are left as they are.
+func (c *Categorization) Categorize(activity *Activity, source ActivitySource) {
+	if source == "" {
+		return
+	}
+
+	rule := c.RuleFor(activity.Description, source)
+	if rule == nil {
+		return
+	}
+	activity.ProjectID = rule.ProjectID
+}
+
+// checkCategorizationRuleOrder checks that the order contains each rule exactly once
+func checkCategorizationRuleOrder(rules []*CategorizationRule, ruleIDs []uuid.UUID) error {
+	if len(rules) != len(ruleIDs) {
+		return ErrCategorizationRuleOrderInvalid
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, ruleID := range ruleIDs {
+		if seen[ruleID] {
+			return ErrCategorizationRuleOrderInvalid
+		}
+		seen[ruleID] = true
+	}
+
+	for _, rule := range rules {
+		if !seen[rule.ID] {
+			return ErrCategorizationRuleOrderInvalid
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestCategorizationRuleValidate(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr((&CategorizationRule{Name: "Support", Keywords: []string{"ticket"}}).Validate())
+	is.NoErr((&CategorizationRule{Name: "Support", Pattern: `^SUP-\d+`}).Validate())
+	is.NoErr((&CategorizationRule{Name: "Webhooks", Source: ActivitySourceWebhook}).Validate())
+
+	is.True((&CategorizationRule{Keywords: []string{"ticket"}}).Validate() != nil)
+	is.True((&CategorizationRule{Name: "No Condition"}).Validate() != nil)
+	is.True((&CategorizationRule{Name: "Short", Keywords: []string{"t"}}).Validate() != nil)
+	is.True((&CategorizationRule{Name: "Comma", Keywords: []string{"ticket,bug"}}).Validate() != nil)
+	is.True((&CategorizationRule{Name: "Pattern", Pattern: "(unclosed"}).Validate() != nil)
+	is.True((&CategorizationRule{Name: "Source", Source: "mail"}).Validate() != nil)
+}
+
+func TestCategorizationRuleMatches(t *testing.T) {
+	is := is.New(t)
+
+	t.Run("keywords ignoring case", func(t *testing.T) {
+		rule := &CategorizationRule{Keywords: []string{"ticket", "bug"}}
+
+		is.True(rule.Matches("Fixed BUG in login", ActivitySourceImport))
+		is.True(rule.Matches("Support Ticket", ""))
+		is.True(!rule.Matches("Planning", ActivitySourceImport))
+	})
+
+	t.Run("pattern", func(t *testing.T) {
+		rule := &CategorizationRule{Pattern: `^SUP-\d+`}
+
+		is.True(rule.Matches("SUP-42 Printer broken", ActivitySourceWebhook))
+		is.True(!rule.Matches("Printer broken SUP-42", ActivitySourceWebhook))
+	})
+
+	t.Run("all conditions", func(t *testing.T) {
+		rule := &CategorizationRule{Keywords: []string{"review"}, Source: ActivitySourceWebhook}
+
+		is.True(rule.Matches("Code review", ActivitySourceWebhook))
+		is.True(!rule.Matches("Code review", ActivitySourceImport))
+		is.True(!rule.Matches("Deployment", ActivitySourceWebhook))
+	})
+}
+
+func TestCategorizationCategorize(t *testing.T) {
+	is := is.New(t)
+
+	supportProjectID := uuid.New()
+	webhookProjectID := uuid.New()
+	givenProjectID := uuid.New()
+
+	categorization := &Categorization{
+		Rules: []*CategorizationRule{
+			{Keywords: []string{"support"}, ProjectID: supportProjectID},
+			{Source: ActivitySourceWebhook, ProjectID: webhookProjectID},
+		},
+	}
+
+	t.Run("first matching rule", func(t *testing.T) {
+		activity := &Activity{Description: "Support call", ProjectID: givenProjectID}
+		categorization.Categorize(activity, ActivitySourceWebhook)
+		is.Equal(activity.ProjectID, supportProjectID)
+	})
+
+	t.Run("rule of source", func(t *testing.T) {
+		activity := &Activity{Description: "Deployment"}
+		categorization.Categorize(activity, ActivitySourceWebhook)
+		is.Equal(activity.ProjectID, webhookProjectID)
+	})
+
+	t.Run("no matching rule", func(t *testing.T) {
+		activity := &Activity{Description: "Deployment", ProjectID: givenProjectID}
+		categorization.Categorize(activity, ActivitySourceImport)
+		is.Equal(activity.ProjectID, givenProjectID)
+	})
+
+	t.Run("tracked by hand", func(t *testing.T) {
+		activity := &Activity{Description: "Support call", ProjectID: givenProjectID}
+		categorization.Categorize(activity, "")
+		is.Equal(activity.ProjectID, givenProjectID)
+	})
+}
+
+func TestCheckCategorizationRuleOrder(t *testing.T) {
+	is := is.New(t)
+
+	rules := []*CategorizationRule{{ID: uuid.New()}, {ID: uuid.New()}}
+
+	is.NoErr(checkCategorizationRuleOrder(rules, []uuid.UUID{rules[1].ID, rules[0].ID}))
+	is.Equal(checkCategorizationRuleOrder(rules, []uuid.UUID{rules[1].ID}), ErrCategorizationRuleOrderInvalid)
+	is.Equal(checkCategorizationRuleOrder(rules, []uuid.UUID{rules[1].ID, rules[1].ID}), ErrCategorizationRuleOrderInvalid)
+	is.Equal(checkCategorizationRuleOrder(rules, []uuid.UUID{rules[1].ID, uuid.New()}), ErrCategorizationRuleOrderInvalid)
+}
@@ -0,0 +1,189 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbCategorizationRuleRepository is a SQL database repository for categorization rules
+type DbCategorizationRuleRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ CategorizationRuleRepository = (*DbCategorizationRuleRepository)(nil)
+
+// NewDbCategorizationRuleRepository creates a new SQL database repository for categorization rules
+func NewDbCategorizationRuleRepository(connPool *pgxpool.Pool) *DbCategorizationRuleRepository {
+	return &DbCategorizationRuleRepository{
+		connPool: connPool,
+	}
+}
+
+// FindCategorizationRules reads the rules of the organization in the order they are applied
+func (r *DbCategorizationRuleRepository) FindCategorizationRules(ctx context.Context, organizationID uuid.UUID) ([]*CategorizationRule, error) {
+	rows, err := shared.SelectAll[categorizationRuleRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[categorizationRuleRow]()+`
+		 FROM categorization_rules
+		 WHERE org_id = $1
+		 ORDER BY position ASC, created_at ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*CategorizationRule, len(rows))
+	for i, row := range rows {
+		rules[i] = row.toCategorizationRule()
+	}
+	return rules, nil
+}
+
+func (r *DbCategorizationRuleRepository) FindCategorizationRuleByID(ctx context.Context, organizationID, ruleID uuid.UUID) (*CategorizationRule, error) {
+	row, err := shared.SelectOne[categorizationRuleRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[categorizationRuleRow]()+`
+		 FROM categorization_rules
+		 WHERE rule_id = $1 AND org_id = $2`,
+		ruleID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCategorizationRuleNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toCategorizationRule(), nil
+}
+
+func (r *DbCategorizationRuleRepository) InsertCategorizationRule(ctx context.Context, rule *CategorizationRule) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO categorization_rules
+		   (rule_id, org_id, name, position, keywords, pattern, source, project_id)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		rule.ID,
+		rule.OrganizationID,
+		rule.Name,
+		rule.Position,
+		nullableString(strings.Join(rule.Keywords, ",")),
+		nullableString(rule.Pattern),
+		nullableString(string(rule.Source)),
+		rule.ProjectID,
+	)
+	return err
+}
+
+// UpdateCategorizationRule updates the name, conditions and project of the rule, its position is kept
+func (r *DbCategorizationRuleRepository) UpdateCategorizationRule(ctx context.Context, rule *CategorizationRule) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE categorization_rules
+		 SET name = $3, keywords = $4, pattern = $5, source = $6, project_id = $7
+		 WHERE rule_id = $1 AND org_id = $2`,
+		rule.ID,
+		rule.OrganizationID,
+		rule.Name,
+		nullableString(strings.Join(rule.Keywords, ",")),
+		nullableString(rule.Pattern),
+		nullableString(string(rule.Source)),
+		rule.ProjectID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCategorizationRuleNotFound
+	}
+	return nil
+}
+
+// UpdateCategorizationRulePositions sets the position of each rule to its index in the rule ids
+func (r *DbCategorizationRuleRepository) UpdateCategorizationRulePositions(ctx context.Context, organizationID uuid.UUID, ruleIDs []uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	for position, ruleID := range ruleIDs {
+		result, err := tx.Exec(
+			ctx,
+			`UPDATE categorization_rules
+			 SET position = $3
+			 WHERE rule_id = $1 AND org_id = $2`,
+			ruleID, organizationID, position,
+		)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() == 0 {
+			return ErrCategorizationRuleNotFound
+		}
+	}
+	return nil
+}
+
+func (r *DbCategorizationRuleRepository) DeleteCategorizationRuleByID(ctx context.Context, organizationID, ruleID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM categorization_rules
+		 WHERE rule_id = $1 AND org_id = $2`,
+		ruleID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCategorizationRuleNotFound
+	}
+	return nil
+}
+
+// categorizationRuleRow is a row of the categorization_rules table
+type categorizationRuleRow struct {
+	ID             uuid.UUID      `db:"rule_id"`
+	OrganizationID uuid.UUID      `db:"org_id"`
+	Name           string         `db:"name"`
+	Position       int            `db:"position"`
+	Keywords       sql.NullString `db:"keywords"`
+	Pattern        sql.NullString `db:"pattern"`
+	Source         sql.NullString `db:"source"`
+	ProjectID      uuid.UUID      `db:"project_id"`
+}
+
+func (r *categorizationRuleRow) toCategorizationRule() *CategorizationRule {
+	var keywords []string
+	if r.Keywords.String != "" {
+		keywords = strings.Split(r.Keywords.String, ",")
+	}
+
+	return &CategorizationRule{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Name:           r.Name,
+		Position:       r.Position,
+		Keywords:       keywords,
+		Pattern:        r.Pattern.String,
+		Source:         ActivitySource(r.Source.String),
+		ProjectID:      r.ProjectID,
+	}
+}
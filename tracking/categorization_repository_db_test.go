@@ -0,0 +1,110 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestCategorizationRuleRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	categorizationRuleRepository := NewDbCategorizationRuleRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	supportRule := &CategorizationRule{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Name:           "Support",
+		Position:       0,
+		Keywords:       []string{"support", "ticket"},
+		ProjectID:      shared.ProjectIDSample,
+	}
+	webhookRule := &CategorizationRule{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Name:           "Webhooks",
+		Position:       1,
+		Pattern:        `^SUP-\d+`,
+		Source:         ActivitySourceWebhook,
+		ProjectID:      shared.ProjectIDSample,
+	}
+
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			err := categorizationRuleRepository.InsertCategorizationRule(ctx, supportRule)
+			if err != nil {
+				return err
+			}
+			return categorizationRuleRepository.InsertCategorizationRule(ctx, webhookRule)
+		},
+	)
+	is.NoErr(err)
+
+	rules, err := categorizationRuleRepository.FindCategorizationRules(context.Background(), shared.OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(len(rules), 2)
+	is.Equal(rules[0].Keywords, []string{"support", "ticket"})
+	is.Equal(rules[1].Pattern, `^SUP-\d+`)
+	is.Equal(rules[1].Source, ActivitySourceWebhook)
+
+	supportRule.Name = "Support Tickets"
+	supportRule.Keywords = nil
+	supportRule.Source = ActivitySourceImport
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			err := categorizationRuleRepository.UpdateCategorizationRule(ctx, supportRule)
+			if err != nil {
+				return err
+			}
+			return categorizationRuleRepository.UpdateCategorizationRulePositions(ctx, shared.OrganizationIDSample, []uuid.UUID{webhookRule.ID, supportRule.ID})
+		},
+	)
+	is.NoErr(err)
+
+	rule, err := categorizationRuleRepository.FindCategorizationRuleByID(context.Background(), shared.OrganizationIDSample, supportRule.ID)
+	is.NoErr(err)
+	is.Equal(rule.Name, "Support Tickets")
+	is.Equal(len(rule.Keywords), 0)
+	is.Equal(rule.Source, ActivitySourceImport)
+	is.Equal(rule.Position, 1)
+
+	rules, err = categorizationRuleRepository.FindCategorizationRules(context.Background(), shared.OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(rules[0].ID, webhookRule.ID)
+
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return categorizationRuleRepository.DeleteCategorizationRuleByID(ctx, shared.OrganizationIDSample, supportRule.ID)
+		},
+	)
+	is.NoErr(err)
+
+	_, err = categorizationRuleRepository.FindCategorizationRuleByID(context.Background(), shared.OrganizationIDSample, supportRule.ID)
+	is.Equal(err, ErrCategorizationRuleNotFound)
+}
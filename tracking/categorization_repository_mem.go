@@ -0,0 +1,108 @@
+package tracking
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemCategorizationRuleRepository struct {
+	mu    sync.Mutex
+	rules []*CategorizationRule
+}
+
+var _ CategorizationRuleRepository = (*InMemCategorizationRuleRepository)(nil)
+
+func NewInMemCategorizationRuleRepository() *InMemCategorizationRuleRepository {
+	return &InMemCategorizationRuleRepository{}
+}
+
+func (r *InMemCategorizationRuleRepository) FindCategorizationRules(ctx context.Context, organizationID uuid.UUID) ([]*CategorizationRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var rules []*CategorizationRule
+	for _, rule := range r.rules {
+		if rule.OrganizationID == organizationID {
+			rules = append(rules, copyCategorizationRule(rule))
+		}
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Position < rules[j].Position
+	})
+	return rules, nil
+}
+
+func (r *InMemCategorizationRuleRepository) FindCategorizationRuleByID(ctx context.Context, organizationID, ruleID uuid.UUID) (*CategorizationRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rule := range r.rules {
+		if rule.OrganizationID == organizationID && rule.ID == ruleID {
+			return copyCategorizationRule(rule), nil
+		}
+	}
+	return nil, ErrCategorizationRuleNotFound
+}
+
+func (r *InMemCategorizationRuleRepository) InsertCategorizationRule(ctx context.Context, rule *CategorizationRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules = append(r.rules, copyCategorizationRule(rule))
+	return nil
+}
+
+func (r *InMemCategorizationRuleRepository) UpdateCategorizationRule(ctx context.Context, rule *CategorizationRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.rules {
+		if existing.OrganizationID == rule.OrganizationID && existing.ID == rule.ID {
+			updated := copyCategorizationRule(rule)
+			updated.Position = existing.Position
+			r.rules[i] = updated
+			return nil
+		}
+	}
+	return ErrCategorizationRuleNotFound
+}
+
+func (r *InMemCategorizationRuleRepository) UpdateCategorizationRulePositions(ctx context.Context, organizationID uuid.UUID, ruleIDs []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rule := range r.rules {
+		if rule.OrganizationID != organizationID {
+			continue
+		}
+		if position := slices.Index(ruleIDs, rule.ID); position >= 0 {
+			rule.Position = position
+		}
+	}
+	return nil
+}
+
+func (r *InMemCategorizationRuleRepository) DeleteCategorizationRuleByID(ctx context.Context, organizationID, ruleID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, rule := range r.rules {
+		if rule.OrganizationID == organizationID && rule.ID == ruleID {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			return nil
+		}
+	}
+	return ErrCategorizationRuleNotFound
+}
+
+func copyCategorizationRule(rule *CategorizationRule) *CategorizationRule {
+	found := *rule
+	found.Keywords = slices.Clone(rule.Keywords)
+	found.compiledPattern = nil
+	return &found
+}
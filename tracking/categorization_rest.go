@@ -0,0 +1,372 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type categorizationRuleModel struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name" validate:"required,max=100"`
+	Position  int        `json:"position"`
+	Keywords  []string   `json:"keywords"`
+	Pattern   string     `json:"pattern,omitempty" validate:"max=200"`
+	Source    string     `json:"source,omitempty"`
+	ProjectID string     `json:"projectId" validate:"required,uuid"`
+	Links     *hal.Links `json:"_links"`
+}
+
+type categorizationRulesModel struct {
+	Embedded *embeddedCategorizationRules `json:"_embedded"`
+	Links    *hal.Links                   `json:"_links"`
+}
+
+type embeddedCategorizationRules struct {
+	RuleModels []*categorizationRuleModel `json:"rules"`
+}
+
+type categorizationRuleOrderModel struct {
+	RuleIDs []string `json:"ruleIds" validate:"dive,uuid"`
+}
+
+type categorizationTestModel struct {
+	Description string                   `json:"description" validate:"max=4000"`
+	Source      string                   `json:"source"`
+	Rule        *categorizationRuleModel `json:"rule,omitempty"`
+}
+
+type categorizationTestResultModel struct {
+	Matched   bool                     `json:"matched"`
+	Rule      *categorizationRuleModel `json:"rule,omitempty"`
+	ProjectID string                   `json:"projectId,omitempty"`
+	Links     *hal.Links               `json:"_links"`
+}
+
+type CategorizationRestHandlers struct {
+	config                    *shared.Config
+	categorizationRuleService *CategorizationRuleService
+}
+
+func NewCategorizationRestHandlers(config *shared.Config, categorizationRuleService *CategorizationRuleService) *CategorizationRestHandlers {
+	return &CategorizationRestHandlers{
+		config:                    config,
+		categorizationRuleService: categorizationRuleService,
+	}
+}
+
+func (a *CategorizationRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/categorization-rules", a.HandleGetCategorizationRules())
+	r.Post("/categorization-rules", a.HandleCreateCategorizationRule())
+	r.Put("/categorization-rules/order", a.HandleReorderCategorizationRules())
+	r.Post("/categorization-rules/test", a.HandleTestCategorizationRules())
+	r.Put("/categorization-rules/{rule-id}", a.HandleUpdateCategorizationRule())
+	r.Delete("/categorization-rules/{rule-id}", a.HandleDeleteCategorizationRule())
+}
+
+func (a *CategorizationRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetCategorizationRules reads the categorization rules of the organization in the order they are applied
+func (a *CategorizationRestHandlers) HandleGetCategorizationRules() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	categorizationRuleService := a.categorizationRuleService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		rules, err := categorizationRuleService.ReadCategorizationRules(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToCategorizationRulesModel(principal, rules, r.RequestURI))
+	}
+}
+
+// HandleCreateCategorizationRule creates a categorization rule which is applied after the existing rules
+func (a *CategorizationRestHandlers) HandleCreateCategorizationRule() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	categorizationRuleService := a.categorizationRuleService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var ruleModel categorizationRuleModel
+		err := json.NewDecoder(r.Body).Decode(&ruleModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "categorization rule not valid", err)
+			return
+		}
+
+		err = validator.Struct(ruleModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "categorization rule not valid", err)
+			return
+		}
+
+		rule := mapToCategorizationRule(&ruleModel)
+		err = rule.Validate()
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "categorization rule not valid", err)
+			return
+		}
+
+		rule, err = categorizationRuleService.CreateCategorizationRule(r.Context(), principal, rule)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToCategorizationRuleModel(principal, rule))
+	}
+}
+
+// HandleUpdateCategorizationRule updates the name, conditions and project of a categorization rule
+func (a *CategorizationRestHandlers) HandleUpdateCategorizationRule() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	categorizationRuleService := a.categorizationRuleService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		ruleID, err := uuid.Parse(chi.URLParam(r, "rule-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var ruleModel categorizationRuleModel
+		err = json.NewDecoder(r.Body).Decode(&ruleModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "categorization rule not valid", err)
+			return
+		}
+
+		err = validator.Struct(ruleModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "categorization rule not valid", err)
+			return
+		}
+
+		rule := mapToCategorizationRule(&ruleModel)
+		err = rule.Validate()
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "categorization rule not valid", err)
+			return
+		}
+		rule.ID = ruleID
+
+		rule, err = categorizationRuleService.UpdateCategorizationRule(r.Context(), principal, rule)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToCategorizationRuleModel(principal, rule))
+	}
+}
+
+// HandleReorderCategorizationRules changes the order in which the categorization rules are applied
+func (a *CategorizationRestHandlers) HandleReorderCategorizationRules() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	categorizationRuleService := a.categorizationRuleService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var orderModel categorizationRuleOrderModel
+		err := json.NewDecoder(r.Body).Decode(&orderModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "order of categorization rules not valid", err)
+			return
+		}
+
+		err = validator.Struct(orderModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "order of categorization rules not valid", err)
+			return
+		}
+
+		ruleIDs := make([]uuid.UUID, len(orderModel.RuleIDs))
+		for i, ruleID := range orderModel.RuleIDs {
+			ruleIDs[i] = uuid.MustParse(ruleID)
+		}
+
+		rules, err := categorizationRuleService.ReorderCategorizationRules(r.Context(), principal, ruleIDs)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToCategorizationRulesModel(principal, rules, "/api/categorization-rules"))
+	}
+}
+
+// HandleTestCategorizationRules is a dry run of the categorization rules for a description and source,
+// with a rule in the body only that rule is tested
+func (a *CategorizationRestHandlers) HandleTestCategorizationRules() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	categorizationRuleService := a.categorizationRuleService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var testModel categorizationTestModel
+		err := json.NewDecoder(r.Body).Decode(&testModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "categorization test not valid", err)
+			return
+		}
+
+		err = validator.Struct(testModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "categorization test not valid", err)
+			return
+		}
+
+		source := ActivitySource(testModel.Source)
+		if !IsValidActivitySource(source) {
+			shared.RenderValidationProblemJSON(w, "categorization test not valid", shared.NewInvalidParam("source", "oneof", "source must be one of import, webhook, extension, sync, scan-tag or quick-add"))
+			return
+		}
+
+		var rule *CategorizationRule
+		if testModel.Rule != nil {
+			rule = mapToCategorizationRule(testModel.Rule)
+			err = rule.Validate()
+			if err != nil {
+				shared.RenderValidationProblemJSON(w, "categorization test not valid", err)
+				return
+			}
+		}
+
+		matchingRule, err := categorizationRuleService.TestCategorizationRules(r.Context(), principal, testModel.Description, source, rule)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		resultModel := &categorizationTestResultModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		if matchingRule != nil {
+			resultModel.Matched = true
+			resultModel.ProjectID = matchingRule.ProjectID.String()
+			if rule == nil {
+				resultModel.Rule = mapToCategorizationRuleModel(principal, matchingRule)
+			}
+		}
+
+		shared.RenderJSON(w, resultModel)
+	}
+}
+
+// HandleDeleteCategorizationRule deletes a categorization rule
+func (a *CategorizationRestHandlers) HandleDeleteCategorizationRule() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	categorizationRuleService := a.categorizationRuleService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		ruleID, err := uuid.Parse(chi.URLParam(r, "rule-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = categorizationRuleService.DeleteCategorizationRuleByID(r.Context(), principal, ruleID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func mapToCategorizationRule(ruleModel *categorizationRuleModel) *CategorizationRule {
+	rule := &CategorizationRule{
+		Name:     ruleModel.Name,
+		Keywords: ruleModel.Keywords,
+		Pattern:  ruleModel.Pattern,
+		Source:   ActivitySource(ruleModel.Source),
+	}
+
+	projectID, err := uuid.Parse(ruleModel.ProjectID)
+	if err == nil {
+		rule.ProjectID = projectID
+	}
+	return rule
+}
+
+func mapToCategorizationRulesModel(principal *shared.Principal, rules []*CategorizationRule, selfHref string) *categorizationRulesModel {
+	ruleModels := make([]*categorizationRuleModel, len(rules))
+	for i, rule := range rules {
+		ruleModels[i] = mapToCategorizationRuleModel(principal, rule)
+	}
+
+	rulesModel := &categorizationRulesModel{
+		Embedded: &embeddedCategorizationRules{
+			RuleModels: ruleModels,
+		},
+	}
+
+	selfLink := hal.NewSelfLink(selfHref)
+	if principal.HasRole("ROLE_ADMIN") {
+		rulesModel.Links = hal.NewLinks(
+			selfLink,
+			hal.NewLink("create", "/api/categorization-rules"),
+			hal.NewLink("order", "/api/categorization-rules/order"),
+			hal.NewLink("test", "/api/categorization-rules/test"),
+		)
+	} else {
+		rulesModel.Links = hal.NewLinks(
+			selfLink,
+		)
+	}
+	return rulesModel
+}
+
+func mapToCategorizationRuleModel(principal *shared.Principal, rule *CategorizationRule) *categorizationRuleModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/categorization-rules/%s", rule.ID))
+	projectLink := hal.NewLink("project", fmt.Sprintf("/api/projects/%s", rule.ProjectID))
+
+	keywords := rule.Keywords
+	if keywords == nil {
+		keywords = []string{}
+	}
+
+	ruleModel := &categorizationRuleModel{
+		ID:        rule.ID.String(),
+		Name:      rule.Name,
+		Position:  rule.Position,
+		Keywords:  keywords,
+		Pattern:   rule.Pattern,
+		Source:    string(rule.Source),
+		ProjectID: rule.ProjectID.String(),
+	}
+	if principal.HasRole("ROLE_ADMIN") {
+		ruleModel.Links = hal.NewLinks(
+			selfLink,
+			projectLink,
+			hal.NewLink("edit", selfLink.Href()),
+			hal.NewLink("delete", selfLink.Href()),
+		)
+	} else {
+		ruleModel.Links = hal.NewLinks(
+			selfLink,
+			projectLink,
+		)
+	}
+	return ruleModel
+}
@@ -0,0 +1,135 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleCategorizationRules(t *testing.T) {
+	is := is.New(t)
+
+	a := NewCategorizationRestHandlers(&shared.Config{}, &CategorizationRuleService{
+		repositoryTxer:               shared.NewInMemRepositoryTxer(),
+		categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+		projectRepository:            NewInMemProjectRepository(),
+	})
+
+	requestWithRoles := func(method, url, body string, roles ...string) *http.Request {
+		r, _ := http.NewRequest(method, url, strings.NewReader(body))
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          roles,
+		}))
+	}
+
+	withRuleID := func(r *http.Request, ruleID string) *http.Request {
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("rule-id", ruleID)
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, chiCtx))
+	}
+
+	body := fmt.Sprintf(`{"name": "Support", "keywords": ["support", "ticket"], "projectId": "%s"}`, shared.ProjectIDSample)
+
+	httpRec := httptest.NewRecorder()
+	a.HandleCreateCategorizationRule()(httpRec, requestWithRoles("POST", "/api/categorization-rules", body, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreateCategorizationRule()(httpRec, requestWithRoles("POST", "/api/categorization-rules", fmt.Sprintf(`{"name": "Support", "projectId": "%s"}`, shared.ProjectIDSample), "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreateCategorizationRule()(httpRec, requestWithRoles("POST", "/api/categorization-rules", fmt.Sprintf(`{"name": "Support", "pattern": "(", "projectId": "%s"}`, shared.ProjectIDSample), "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreateCategorizationRule()(httpRec, requestWithRoles("POST", "/api/categorization-rules", body, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	supportRuleModel := &categorizationRuleModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(supportRuleModel)
+	is.NoErr(err)
+	is.Equal(supportRuleModel.Name, "Support")
+	is.Equal(supportRuleModel.Keywords, []string{"support", "ticket"})
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreateCategorizationRule()(httpRec, requestWithRoles("POST", "/api/categorization-rules", fmt.Sprintf(`{"name": "Webhooks", "source": "webhook", "projectId": "%s"}`, shared.ProjectIDSample), "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	webhookRuleModel := &categorizationRuleModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(webhookRuleModel)
+	is.NoErr(err)
+	is.Equal(webhookRuleModel.Position, 1)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleUpdateCategorizationRule()(httpRec, withRuleID(requestWithRoles("PUT", "/api/categorization-rules/"+webhookRuleModel.ID, fmt.Sprintf(`{"name": "Webhook Reviews", "keywords": ["review"], "source": "webhook", "projectId": "%s"}`, shared.ProjectIDSample), "ROLE_ADMIN"), webhookRuleModel.ID))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleReorderCategorizationRules()(httpRec, requestWithRoles("PUT", "/api/categorization-rules/order", fmt.Sprintf(`{"ruleIds": ["%s"]}`, webhookRuleModel.ID), "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleReorderCategorizationRules()(httpRec, requestWithRoles("PUT", "/api/categorization-rules/order", fmt.Sprintf(`{"ruleIds": ["%s", "%s"]}`, webhookRuleModel.ID, supportRuleModel.ID), "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetCategorizationRules()(httpRec, requestWithRoles("GET", "/api/categorization-rules", "", "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	rulesModel := &categorizationRulesModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(rulesModel)
+	is.NoErr(err)
+	is.Equal(len(rulesModel.Embedded.RuleModels), 2)
+	is.Equal(rulesModel.Embedded.RuleModels[0].Name, "Webhook Reviews")
+	is.Equal(rulesModel.Links.HrefOf("create"), "")
+
+	testRules := func(body string) (*httptest.ResponseRecorder, *categorizationTestResultModel) {
+		httpRec := httptest.NewRecorder()
+		a.HandleTestCategorizationRules()(httpRec, requestWithRoles("POST", "/api/categorization-rules/test", body, "ROLE_ADMIN"))
+
+		resultModel := &categorizationTestResultModel{}
+		_ = json.NewDecoder(httpRec.Body).Decode(resultModel)
+		return httpRec, resultModel
+	}
+
+	httpRec, resultModel := testRules(`{"description": "Support ticket", "source": "import"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.True(resultModel.Matched)
+	is.Equal(resultModel.Rule.ID, supportRuleModel.ID)
+	is.Equal(resultModel.ProjectID, shared.ProjectIDSample.String())
+
+	_, resultModel = testRules(`{"description": "Planning", "source": "import"}`)
+	is.True(!resultModel.Matched)
+
+	_, resultModel = testRules(fmt.Sprintf(`{"description": "Planning", "rule": {"name": "Planning", "keywords": ["planning"], "projectId": "%s"}}`, shared.ProjectIDSample))
+	is.True(resultModel.Matched)
+	is.True(resultModel.Rule == nil)
+
+	httpRec, _ = testRules(`{"description": "Planning", "source": "mail"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	deleteRule := func(ruleID string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		a.HandleDeleteCategorizationRule()(httpRec, withRuleID(requestWithRoles("DELETE", "/api/categorization-rules/"+ruleID, "", "ROLE_ADMIN"), ruleID))
+		return httpRec
+	}
+
+	httpRec = deleteRule("no-uuid")
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = deleteRule(supportRuleModel.ID)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+
+	httpRec = deleteRule(supportRuleModel.ID)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
@@ -0,0 +1,193 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// CategorizationRuleService manages the categorization rules of an organization
+type CategorizationRuleService struct {
+	repositoryTxer               shared.RepositoryTxer
+	categorizationRuleRepository CategorizationRuleRepository
+	projectRepository            ProjectRepository
+}
+
+// NewCategorizationRuleService creates a new service for categorization rules
+func NewCategorizationRuleService(repositoryTxer shared.RepositoryTxer, categorizationRuleRepository CategorizationRuleRepository, projectRepository ProjectRepository) *CategorizationRuleService {
+	return &CategorizationRuleService{
+		repositoryTxer:               repositoryTxer,
+		categorizationRuleRepository: categorizationRuleRepository,
+		projectRepository:            projectRepository,
+	}
+}
+
+// ReadCategorizationRules reads the rules of the organization in the order they are applied
+func (s *CategorizationRuleService) ReadCategorizationRules(ctx context.Context, principal *shared.Principal) ([]*CategorizationRule, error) {
+	return s.categorizationRuleRepository.FindCategorizationRules(ctx, principal.OrganizationID)
+}
+
+// CreateCategorizationRule adds a rule, it's applied after the existing rules
+func (s *CategorizationRuleService) CreateCategorizationRule(ctx context.Context, principal *shared.Principal, rule *CategorizationRule) (*CategorizationRule, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	err := rule.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkOpenProject(ctx, principal, rule.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.categorizationRuleRepository.FindCategorizationRules(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) >= maxCategorizationRules {
+		return nil, ErrCategorizationRuleLimitReached
+	}
+
+	rule.ID = uuid.New()
+	rule.OrganizationID = principal.OrganizationID
+	rule.Position = 0
+	if len(rules) > 0 {
+		rule.Position = rules[len(rules)-1].Position + 1
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.categorizationRuleRepository.InsertCategorizationRule(ctx, rule)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateCategorizationRule updates the name, conditions and project of a rule, its position is kept
+func (s *CategorizationRuleService) UpdateCategorizationRule(ctx context.Context, principal *shared.Principal, rule *CategorizationRule) (*CategorizationRule, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	err := rule.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	existingRule, err := s.categorizationRuleRepository.FindCategorizationRuleByID(ctx, principal.OrganizationID, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkOpenProject(ctx, principal, rule.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.OrganizationID = principal.OrganizationID
+	rule.Position = existingRule.Position
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.categorizationRuleRepository.UpdateCategorizationRule(ctx, rule)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// ReorderCategorizationRules changes the order in which the rules are applied, the order must contain each rule exactly once
+func (s *CategorizationRuleService) ReorderCategorizationRules(ctx context.Context, principal *shared.Principal, ruleIDs []uuid.UUID) ([]*CategorizationRule, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	rules, err := s.categorizationRuleRepository.FindCategorizationRules(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkCategorizationRuleOrder(rules, ruleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.categorizationRuleRepository.UpdateCategorizationRulePositions(ctx, principal.OrganizationID, ruleIDs)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.categorizationRuleRepository.FindCategorizationRules(ctx, principal.OrganizationID)
+}
+
+// DeleteCategorizationRuleByID deletes a rule
+func (s *CategorizationRuleService) DeleteCategorizationRuleByID(ctx context.Context, principal *shared.Principal, ruleID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.categorizationRuleRepository.DeleteCategorizationRuleByID(ctx, principal.OrganizationID, ruleID)
+		},
+	)
+}
+
+// TestCategorizationRules is a dry run finding the rule which would categorize an activity with the description
+// from the source without creating it. With a rule only that rule is tested, so rules can be tried before saving them.
+// The matching rule is nil if no rule matches.
+func (s *CategorizationRuleService) TestCategorizationRules(ctx context.Context, principal *shared.Principal, description string, source ActivitySource, rule *CategorizationRule) (*CategorizationRule, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	if rule != nil {
+		err := rule.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		if !rule.Matches(description, source) {
+			return nil, nil
+		}
+		return rule, nil
+	}
+
+	rules, err := s.categorizationRuleRepository.FindCategorizationRules(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	categorization := &Categorization{Rules: rules}
+	return categorization.RuleFor(description, source), nil
+}
+
+// checkOpenProject checks that activities can be categorized into the project, done projects are not
+func (s *CategorizationRuleService) checkOpenProject(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) error {
+	project, err := s.projectRepository.FindProjectByID(ctx, principal.OrganizationID, projectID)
+	if err != nil {
+		return err
+	}
+
+	if !project.IsOpen() {
+		return ErrProjectNotFound
+	}
+	return nil
+}
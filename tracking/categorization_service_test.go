@@ -0,0 +1,212 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestCategorizationRuleService(t *testing.T) {
+	is := is.New(t)
+
+	categorizationRuleService := &CategorizationRuleService{
+		repositoryTxer:               shared.NewInMemRepositoryTxer(),
+		categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+		projectRepository:            NewInMemProjectRepository(),
+	}
+
+	admin := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+
+	user := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}
+
+	var supportRule, webhookRule *CategorizationRule
+
+	t.Run("create rules", func(t *testing.T) {
+		var err error
+		supportRule, err = categorizationRuleService.CreateCategorizationRule(context.Background(), admin, &CategorizationRule{
+			Name:      "Support",
+			Keywords:  []string{"support"},
+			ProjectID: shared.ProjectIDSample,
+		})
+		is.NoErr(err)
+		is.Equal(supportRule.Position, 0)
+
+		webhookRule, err = categorizationRuleService.CreateCategorizationRule(context.Background(), admin, &CategorizationRule{
+			Name:      "Webhooks",
+			Source:    ActivitySourceWebhook,
+			ProjectID: shared.ProjectIDSample,
+		})
+		is.NoErr(err)
+		is.Equal(webhookRule.Position, 1)
+	})
+
+	t.Run("create rule as user", func(t *testing.T) {
+		_, err := categorizationRuleService.CreateCategorizationRule(context.Background(), user, &CategorizationRule{
+			Name:      "Support",
+			Keywords:  []string{"support"},
+			ProjectID: shared.ProjectIDSample,
+		})
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	t.Run("create rule with unknown project", func(t *testing.T) {
+		_, err := categorizationRuleService.CreateCategorizationRule(context.Background(), admin, &CategorizationRule{
+			Name:      "Support",
+			Keywords:  []string{"support"},
+			ProjectID: uuid.New(),
+		})
+		is.Equal(err, ErrProjectNotFound)
+	})
+
+	t.Run("update rule", func(t *testing.T) {
+		rule, err := categorizationRuleService.UpdateCategorizationRule(context.Background(), admin, &CategorizationRule{
+			ID:        webhookRule.ID,
+			Name:      "Webhook Reviews",
+			Keywords:  []string{"review"},
+			Source:    ActivitySourceWebhook,
+			ProjectID: shared.ProjectIDSample,
+		})
+		is.NoErr(err)
+		is.Equal(rule.Position, 1)
+
+		_, err = categorizationRuleService.UpdateCategorizationRule(context.Background(), admin, &CategorizationRule{
+			ID:        uuid.New(),
+			Name:      "Unknown",
+			Keywords:  []string{"unknown"},
+			ProjectID: shared.ProjectIDSample,
+		})
+		is.Equal(err, ErrCategorizationRuleNotFound)
+	})
+
+	t.Run("reorder rules", func(t *testing.T) {
+		rules, err := categorizationRuleService.ReorderCategorizationRules(context.Background(), admin, []uuid.UUID{webhookRule.ID, supportRule.ID})
+		is.NoErr(err)
+		is.Equal(len(rules), 2)
+		is.Equal(rules[0].ID, webhookRule.ID)
+		is.Equal(rules[1].ID, supportRule.ID)
+
+		_, err = categorizationRuleService.ReorderCategorizationRules(context.Background(), admin, []uuid.UUID{webhookRule.ID})
+		is.Equal(err, ErrCategorizationRuleOrderInvalid)
+
+		_, err = categorizationRuleService.ReorderCategorizationRules(context.Background(), user, []uuid.UUID{supportRule.ID, webhookRule.ID})
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	t.Run("test rules", func(t *testing.T) {
+		rule, err := categorizationRuleService.TestCategorizationRules(context.Background(), admin, "Support review", ActivitySourceWebhook, nil)
+		is.NoErr(err)
+		is.Equal(rule.ID, webhookRule.ID)
+
+		rule, err = categorizationRuleService.TestCategorizationRules(context.Background(), admin, "Support review", ActivitySourceImport, nil)
+		is.NoErr(err)
+		is.Equal(rule.ID, supportRule.ID)
+
+		rule, err = categorizationRuleService.TestCategorizationRules(context.Background(), admin, "Planning", ActivitySourceImport, nil)
+		is.NoErr(err)
+		is.True(rule == nil)
+	})
+
+	t.Run("test unsaved rule", func(t *testing.T) {
+		unsavedRule := &CategorizationRule{
+			Name:      "Planning",
+			Pattern:   "(?i)^planning",
+			ProjectID: shared.ProjectIDSample,
+		}
+
+		rule, err := categorizationRuleService.TestCategorizationRules(context.Background(), admin, "Planning Q4", ActivitySourceImport, unsavedRule)
+		is.NoErr(err)
+		is.Equal(rule, unsavedRule)
+
+		rules, err := categorizationRuleService.ReadCategorizationRules(context.Background(), user)
+		is.NoErr(err)
+		is.Equal(len(rules), 2)
+	})
+
+	t.Run("delete rule", func(t *testing.T) {
+		err := categorizationRuleService.DeleteCategorizationRuleByID(context.Background(), user, supportRule.ID)
+		is.Equal(err, shared.ErrForbidden)
+
+		err = categorizationRuleService.DeleteCategorizationRuleByID(context.Background(), admin, supportRule.ID)
+		is.NoErr(err)
+
+		err = categorizationRuleService.DeleteCategorizationRuleByID(context.Background(), admin, supportRule.ID)
+		is.Equal(err, ErrCategorizationRuleNotFound)
+	})
+}
+
+func TestCreateActivitiesWithCategorization(t *testing.T) {
+	is := is.New(t)
+
+	supportProjectID := uuid.New()
+	categorizationRuleRepository := NewInMemCategorizationRuleRepository()
+	err := categorizationRuleRepository.InsertCategorizationRule(context.Background(), &CategorizationRule{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Name:           "Support",
+		Keywords:       []string{"support"},
+		ProjectID:      supportProjectID,
+	})
+	is.NoErr(err)
+
+	actitivityService := &ActitivityService{
+		repositoryTxer:               shared.NewInMemRepositoryTxer(),
+		activityRepository:           NewInMemActivityRepository(),
+		locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+		projectAssignmentRepository:  NewInMemProjectAssignmentRepository(),
+		categorizationRuleRepository: categorizationRuleRepository,
+	}
+
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}
+
+	start := time.Date(2021, 11, 6, 9, 0, 0, 0, time.UTC)
+
+	t.Run("import", func(t *testing.T) {
+		activities := []*Activity{
+			{Start: start, End: start.Add(15 * time.Minute), Description: "Support call", ProjectID: shared.ProjectIDSample},
+			{Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Description: "Planning", ProjectID: shared.ProjectIDSample},
+		}
+
+		rejected, err := actitivityService.CreateActivities(context.Background(), principal, activities, ActivitySourceImport, true)
+		is.NoErr(err)
+		is.Equal(len(rejected), 0)
+		is.Equal(activities[0].ProjectID, supportProjectID)
+		is.Equal(activities[1].ProjectID, shared.ProjectIDSample)
+	})
+
+	t.Run("integration", func(t *testing.T) {
+		activity, err := actitivityService.CreateActivityFromSource(context.Background(), principal, &Activity{
+			Start:       start.Add(3 * time.Hour),
+			End:         start.Add(4 * time.Hour),
+			Description: "Support ticket",
+			ProjectID:   shared.ProjectIDSample,
+		}, ActivitySourceWebhook, true)
+		is.NoErr(err)
+		is.Equal(activity.ProjectID, supportProjectID)
+	})
+
+	t.Run("tracked by hand", func(t *testing.T) {
+		activity, err := actitivityService.CreateActivity(context.Background(), principal, &Activity{
+			Start:       start.Add(5 * time.Hour),
+			End:         start.Add(6 * time.Hour),
+			Description: "Support ticket",
+			ProjectID:   shared.ProjectIDSample,
+		}, true)
+		is.NoErr(err)
+		is.Equal(activity.ProjectID, shared.ProjectIDSample)
+	})
+}
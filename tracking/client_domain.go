@@ -0,0 +1,87 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+)
+
+var ErrClientNotFound = shared.NewDomainError("client:not-found", http.StatusNotFound, "client not found")
+
+// Client is a customer of an organization whose projects are reported and billed together
+type Client struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	Name            string
+	HourlyRateCents int
+	Currency        string
+}
+
+// ClientReportItem is the tracked time of a project of a client
+type ClientReportItem struct {
+	ProjectID              uuid.UUID
+	ProjectTitle           string
+	Billable               bool
+	DurationInMinutesTotal int
+}
+
+// ClientStatement is the tracked time and amount of all projects of a client in a timespan
+type ClientStatement struct {
+	Client *Client
+	Start  time.Time
+	End    time.Time
+	Items  []*ClientReportItem
+}
+
+type ClientRepository interface {
+	FindClients(ctx context.Context, organizationID uuid.UUID) ([]*Client, error)
+	FindClientByID(ctx context.Context, organizationID, clientID uuid.UUID) (*Client, error)
+	FindProjectIDsOfClient(ctx context.Context, organizationID, clientID uuid.UUID) ([]uuid.UUID, error)
+	InsertClient(ctx context.Context, client *Client) (*Client, error)
+	UpdateClient(ctx context.Context, client *Client) (*Client, error)
+	DeleteClientByID(ctx context.Context, organizationID, clientID uuid.UUID) error
+	AssignProjectToClient(ctx context.Context, organizationID, projectID uuid.UUID, clientID *uuid.UUID) error
+	ClientReport(ctx context.Context, organizationID, clientID uuid.UUID, start, end time.Time) ([]*ClientReportItem, error)
+}
+
+// AmountCents is the amount of the tracked time at the rate of the client, non-billable time has no amount
+func (s *ClientStatement) AmountCents(item *ClientReportItem) int {
+	if !item.Billable {
+		return 0
+	}
+	return int(math.Round(float64(item.DurationInMinutesTotal) * float64(s.Client.HourlyRateCents) / 60))
+}
+
+// DurationInMinutesTotal is the time tracked for all projects of the client
+func (s *ClientStatement) DurationInMinutesTotal() int {
+	total := 0
+	for _, item := range s.Items {
+		total += item.DurationInMinutesTotal
+	}
+	return total
+}
+
+// TotalAmountCents is the amount of the time tracked for all projects of the client
+func (s *ClientStatement) TotalAmountCents() int {
+	total := 0
+	for _, item := range s.Items {
+		total += s.AmountCents(item)
+	}
+	return total
+}
+
+// FormatAmount formats the amount in cents in the currency of the client (e.g. 1250.50 EUR)
+func (s *ClientStatement) FormatAmount(cents int) string {
+	return fmt.Sprintf("%.2f %s", float64(cents)/100, s.Client.Currency)
+}
+
+// DurationFormatted is the tracked time as formatted string (e.g. 1:15 h)
+func (i *ClientReportItem) DurationFormatted() string {
+	return time_utils.FormatMinutesAsDuration(float64(i.DurationInMinutesTotal))
+}
@@ -0,0 +1,31 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestClientStatementAmounts(t *testing.T) {
+	is := is.New(t)
+
+	statement := &ClientStatement{
+		Client: &Client{
+			Name:            "ACME Corp.",
+			HourlyRateCents: 9000,
+			Currency:        "EUR",
+		},
+		Items: []*ClientReportItem{
+			{ProjectTitle: "Billable", Billable: true, DurationInMinutesTotal: 90},
+			{ProjectTitle: "Internal", Billable: false, DurationInMinutesTotal: 30},
+			{ProjectTitle: "Short", Billable: true, DurationInMinutesTotal: 1},
+		},
+	}
+
+	is.Equal(statement.AmountCents(statement.Items[0]), 13500)
+	is.Equal(statement.AmountCents(statement.Items[1]), 0)
+	is.Equal(statement.AmountCents(statement.Items[2]), 150)
+	is.Equal(statement.DurationInMinutesTotal(), 121)
+	is.Equal(statement.TotalAmountCents(), 13650)
+	is.Equal(statement.FormatAmount(13650), "136.50 EUR")
+}
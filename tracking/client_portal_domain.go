@@ -0,0 +1,33 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrClientPortalUserNotFound  = shared.NewDomainError("client-portal:user-not-found", http.StatusNotFound, "client portal user not found")
+	ErrClientPortalUsernameTaken = shared.NewDomainError("client-portal:username-taken", http.StatusConflict, "username is already taken")
+)
+
+// ClientPortalUser is a user of a client with read-only access to the aggregated reports
+// and statements of the client's projects, it has the client role only
+type ClientPortalUser struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ClientID       uuid.UUID
+	Username       string
+	Name           string
+	EMail          string
+	Password       string
+}
+
+type ClientPortalRepository interface {
+	FindClientPortalUsers(ctx context.Context, organizationID, clientID uuid.UUID) ([]*ClientPortalUser, error)
+	FindClientIDOfPortalUser(ctx context.Context, organizationID uuid.UUID, username string) (uuid.UUID, error)
+	InsertClientPortalUser(ctx context.Context, clientPortalUser *ClientPortalUser) (*ClientPortalUser, error)
+	DeleteClientPortalUser(ctx context.Context, organizationID, clientID, userID uuid.UUID) error
+}
@@ -0,0 +1,185 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbClientPortalRepository is a SQL database repository for client portal users
+type DbClientPortalRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ ClientPortalRepository = (*DbClientPortalRepository)(nil)
+
+// NewDbClientPortalRepository creates a new SQL database repository for client portal users
+func NewDbClientPortalRepository(connPool *pgxpool.Pool) *DbClientPortalRepository {
+	return &DbClientPortalRepository{
+		connPool: connPool,
+	}
+}
+
+// FindClientPortalUsers reads the enabled portal users of the client
+func (r *DbClientPortalRepository) FindClientPortalUsers(ctx context.Context, organizationID, clientID uuid.UUID) ([]*ClientPortalUser, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT u.user_id, u.username, u.name, u.email 
+		 FROM client_portal_users cu 
+		 INNER JOIN users u 
+		 ON u.user_id = cu.user_id 
+		 WHERE cu.org_id = $1 AND cu.client_id = $2 AND u.enabled = 1 
+		 ORDER BY u.username ASC`,
+		organizationID, clientID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clientPortalUsers []*ClientPortalUser
+	for rows.Next() {
+		clientPortalUser := &ClientPortalUser{OrganizationID: organizationID, ClientID: clientID}
+		var name, email *string
+		err := rows.Scan(&clientPortalUser.ID, &clientPortalUser.Username, &name, &email)
+		if err != nil {
+			return nil, err
+		}
+		if name != nil {
+			clientPortalUser.Name = *name
+		}
+		if email != nil {
+			clientPortalUser.EMail = *email
+		}
+		clientPortalUsers = append(clientPortalUsers, clientPortalUser)
+	}
+
+	return clientPortalUsers, rows.Err()
+}
+
+// FindClientIDOfPortalUser reads the client the user has portal access to
+func (r *DbClientPortalRepository) FindClientIDOfPortalUser(ctx context.Context, organizationID uuid.UUID, username string) (uuid.UUID, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT cu.client_id 
+		 FROM client_portal_users cu 
+		 INNER JOIN users u 
+		 ON u.user_id = cu.user_id 
+		 WHERE cu.org_id = $1 AND u.username = $2 AND u.enabled = 1`,
+		organizationID, username,
+	)
+
+	var clientID uuid.UUID
+	err := row.Scan(&clientID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrClientPortalUserNotFound
+		}
+
+		return uuid.Nil, err
+	}
+
+	return clientID, nil
+}
+
+// InsertClientPortalUser creates a user with the client role only and grants it access to the portal of the client
+func (r *DbClientPortalRepository) InsertClientPortalUser(ctx context.Context, clientPortalUser *ClientPortalUser) (*ClientPortalUser, error) {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	var usernameCount int
+	err := tx.QueryRow(
+		ctx,
+		`SELECT count(*) 
+		 FROM users 
+		 WHERE username = $1`,
+		clientPortalUser.Username,
+	).Scan(&usernameCount)
+	if err != nil {
+		return nil, err
+	}
+	if usernameCount > 0 {
+		return nil, ErrClientPortalUsernameTaken
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO users 
+		   (user_id, username, email, name, password, enabled, org_id, origin) 
+		 VALUES 
+		   ($1, $2, $3, $4, $5, 1, $6, 'baralga')`,
+		clientPortalUser.ID,
+		clientPortalUser.Username,
+		clientPortalUser.EMail,
+		clientPortalUser.Name,
+		clientPortalUser.Password,
+		clientPortalUser.OrganizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO roles 
+		   (user_id, role, org_id) 
+		 VALUES 
+		   ($1, 'ROLE_CLIENT', $2)`,
+		clientPortalUser.ID,
+		clientPortalUser.OrganizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO client_portal_users 
+		   (user_id, org_id, client_id) 
+		 VALUES 
+		   ($1, $2, $3)`,
+		clientPortalUser.ID,
+		clientPortalUser.OrganizationID,
+		clientPortalUser.ClientID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientPortalUser, nil
+}
+
+// DeleteClientPortalUser revokes the portal access of the user and disables the user, so it can no longer log in
+func (r *DbClientPortalRepository) DeleteClientPortalUser(ctx context.Context, organizationID, clientID, userID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	row := tx.QueryRow(ctx,
+		`DELETE 
+		 FROM client_portal_users 
+		 WHERE user_id = $1 AND org_id = $2 AND client_id = $3 
+		 RETURNING user_id`,
+		userID, organizationID, clientID,
+	)
+
+	var id string
+	err := row.Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrClientPortalUserNotFound
+		}
+
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE users 
+		 SET enabled = 0 
+		 WHERE user_id = $1 AND org_id = $2`,
+		userID, organizationID,
+	)
+	return err
+}
@@ -0,0 +1,105 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestClientPortalRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	clientRepository := NewDbClientRepository(connPool)
+	clientPortalRepository := NewDbClientPortalRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	client := &Client{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Name:           "ACME Corp.",
+		Currency:       "EUR",
+	}
+	clientPortalUser := &ClientPortalUser{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		ClientID:       client.ID,
+		Username:       "client@acme.com",
+		Name:           "ACME Controlling",
+		Password:       "$2a$10$NuzYobDOSTCx/EKBClGwGe0A9c8/yC7D4IP75hwz1jn.RCBfdEtb2",
+	}
+
+	t.Run("InsertClientPortalUser", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := clientRepository.InsertClient(ctx, client)
+				if err != nil {
+					return err
+				}
+				_, err = clientPortalRepository.InsertClientPortalUser(ctx, clientPortalUser)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		clientPortalUsers, err := clientPortalRepository.FindClientPortalUsers(context.Background(), shared.OrganizationIDSample, client.ID)
+		is.NoErr(err)
+		is.Equal(len(clientPortalUsers), 1)
+		is.Equal(clientPortalUsers[0].Name, "ACME Controlling")
+
+		clientID, err := clientPortalRepository.FindClientIDOfPortalUser(context.Background(), shared.OrganizationIDSample, "client@acme.com")
+		is.NoErr(err)
+		is.Equal(clientID, client.ID)
+	})
+
+	t.Run("InsertClientPortalUser with taken username", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := clientPortalRepository.InsertClientPortalUser(ctx, &ClientPortalUser{
+					ID:             uuid.New(),
+					OrganizationID: shared.OrganizationIDSample,
+					ClientID:       client.ID,
+					Username:       "admin",
+				})
+				return err
+			},
+		)
+		is.Equal(err, ErrClientPortalUsernameTaken)
+	})
+
+	t.Run("DeleteClientPortalUser", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return clientPortalRepository.DeleteClientPortalUser(ctx, shared.OrganizationIDSample, client.ID, clientPortalUser.ID)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = clientPortalRepository.FindClientIDOfPortalUser(context.Background(), shared.OrganizationIDSample, "client@acme.com")
+		is.Equal(err, ErrClientPortalUserNotFound)
+	})
+}
@@ -0,0 +1,73 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemClientPortalRepository struct {
+	mu                sync.Mutex
+	clientPortalUsers []*ClientPortalUser
+	usernames         map[string]bool
+}
+
+var _ ClientPortalRepository = (*InMemClientPortalRepository)(nil)
+
+func NewInMemClientPortalRepository() *InMemClientPortalRepository {
+	return &InMemClientPortalRepository{
+		usernames: make(map[string]bool),
+	}
+}
+
+func (r *InMemClientPortalRepository) FindClientPortalUsers(ctx context.Context, organizationID, clientID uuid.UUID) ([]*ClientPortalUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var clientPortalUsers []*ClientPortalUser
+	for _, clientPortalUser := range r.clientPortalUsers {
+		if clientPortalUser.OrganizationID == organizationID && clientPortalUser.ClientID == clientID {
+			clientPortalUsers = append(clientPortalUsers, clientPortalUser)
+		}
+	}
+	return clientPortalUsers, nil
+}
+
+func (r *InMemClientPortalRepository) FindClientIDOfPortalUser(ctx context.Context, organizationID uuid.UUID, username string) (uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, clientPortalUser := range r.clientPortalUsers {
+		if clientPortalUser.OrganizationID == organizationID && clientPortalUser.Username == username {
+			return clientPortalUser.ClientID, nil
+		}
+	}
+	return uuid.Nil, ErrClientPortalUserNotFound
+}
+
+func (r *InMemClientPortalRepository) InsertClientPortalUser(ctx context.Context, clientPortalUser *ClientPortalUser) (*ClientPortalUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.usernames[clientPortalUser.Username] {
+		return nil, ErrClientPortalUsernameTaken
+	}
+
+	r.usernames[clientPortalUser.Username] = true
+	r.clientPortalUsers = append(r.clientPortalUsers, clientPortalUser)
+	return clientPortalUser, nil
+}
+
+func (r *InMemClientPortalRepository) DeleteClientPortalUser(ctx context.Context, organizationID, clientID, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, clientPortalUser := range r.clientPortalUsers {
+		if clientPortalUser.OrganizationID == organizationID && clientPortalUser.ClientID == clientID && clientPortalUser.ID == userID {
+			r.clientPortalUsers = append(r.clientPortalUsers[:i], r.clientPortalUsers[i+1:]...)
+			return nil
+		}
+	}
+	return ErrClientPortalUserNotFound
+}
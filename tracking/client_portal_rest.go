@@ -0,0 +1,265 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type clientPortalUserModel struct {
+	ID       string     `json:"id"`
+	Username string     `json:"username" validate:"required,min=3,max=100"`
+	Name     string     `json:"name,omitempty" validate:"max=100"`
+	EMail    string     `json:"email,omitempty" validate:"omitempty,email"`
+	Password string     `json:"password,omitempty" validate:"required,min=8,max=100"`
+	Links    *hal.Links `json:"_links"`
+}
+
+type clientPortalUsersModel struct {
+	Embedded *embeddedClientPortalUsers `json:"_embedded"`
+	Links    *hal.Links                 `json:"_links"`
+}
+
+type embeddedClientPortalUsers struct {
+	ClientPortalUserModels []*clientPortalUserModel `json:"users"`
+}
+
+type clientPortalReportModel struct {
+	Client       string                         `json:"client"`
+	Start        string                         `json:"start"`
+	End          string                         `json:"end"`
+	MinutesTotal int                            `json:"minutesTotal"`
+	Projects     []*clientPortalReportItemModel `json:"projects"`
+	Links        *hal.Links                     `json:"_links"`
+}
+
+type clientPortalReportItemModel struct {
+	ProjectID         string `json:"projectId"`
+	ProjectTitle      string `json:"projectTitle"`
+	MinutesTotal      int    `json:"minutesTotal"`
+	DurationFormatted string `json:"durationFormatted"`
+}
+
+type ClientPortalRestHandlers struct {
+	config              *shared.Config
+	clientPortalService *ClientPortalService
+	clientService       *ClientService
+}
+
+func NewClientPortalRestHandlers(config *shared.Config, clientPortalService *ClientPortalService, clientService *ClientService) *ClientPortalRestHandlers {
+	return &ClientPortalRestHandlers{
+		config:              config,
+		clientPortalService: clientPortalService,
+		clientService:       clientService,
+	}
+}
+
+func (a *ClientPortalRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/clients/{client-id}/portal-users", a.HandleGetClientPortalUsers())
+	r.Post("/clients/{client-id}/portal-users", a.HandleCreateClientPortalUser())
+	r.Delete("/clients/{client-id}/portal-users/{user-id}", a.HandleDeleteClientPortalUser())
+	r.Get("/client-portal/report", a.HandleClientPortalReport())
+	r.Get("/client-portal/statements/{month}", a.HandleClientPortalStatement())
+}
+
+func (a *ClientPortalRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetClientPortalUsers reads the users of the client with access to the client portal
+func (a *ClientPortalRestHandlers) HandleGetClientPortalUsers() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientPortalService := a.clientPortalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		clientPortalUsers, err := clientPortalService.ReadClientPortalUsers(r.Context(), principal, clientID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		clientPortalUserModels := make([]*clientPortalUserModel, len(clientPortalUsers))
+		for i, clientPortalUser := range clientPortalUsers {
+			clientPortalUserModels[i] = mapToClientPortalUserModel(clientPortalUser)
+		}
+
+		shared.RenderJSON(w, &clientPortalUsersModel{
+			Embedded: &embeddedClientPortalUsers{
+				ClientPortalUserModels: clientPortalUserModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleCreateClientPortalUser creates a user of the client, which can only log in to the client portal
+func (a *ClientPortalRestHandlers) HandleCreateClientPortalUser() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	clientPortalService := a.clientPortalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var clientPortalUserModel clientPortalUserModel
+		err = json.NewDecoder(r.Body).Decode(&clientPortalUserModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "client portal user not valid", err)
+			return
+		}
+
+		err = validator.Struct(clientPortalUserModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "client portal user not valid", err)
+			return
+		}
+
+		clientPortalUser := &ClientPortalUser{
+			ClientID: clientID,
+			Username: clientPortalUserModel.Username,
+			Name:     clientPortalUserModel.Name,
+			EMail:    clientPortalUserModel.EMail,
+		}
+
+		clientPortalUser, err = clientPortalService.CreateClientPortalUser(r.Context(), principal, clientPortalUser, clientPortalUserModel.Password)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToClientPortalUserModel(clientPortalUser))
+	}
+}
+
+// HandleDeleteClientPortalUser revokes the access of a user to the client portal
+func (a *ClientPortalRestHandlers) HandleDeleteClientPortalUser() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientPortalService := a.clientPortalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		userID, err := uuid.Parse(chi.URLParam(r, "user-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = clientPortalService.DeleteClientPortalUser(r.Context(), principal, clientID, userID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+// HandleClientPortalReport reads the tracked time per project of the client user's client in the timespan of the query params
+func (a *ClientPortalRestHandlers) HandleClientPortalReport() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientPortalService := a.clientPortalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		filter, err := filterFromQueryParams(r.URL.Query())
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		statement, err := clientPortalService.ReadClientPortalStatement(r.Context(), principal, filter.Start(), filter.End())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		reportModel := mapToClientPortalReportModel(statement)
+		reportModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+		)
+
+		shared.RenderJSON(w, reportModel)
+	}
+}
+
+// HandleClientPortalStatement reads the monthly statement of the client user's client as JSON, CSV or PDF
+func (a *ClientPortalRestHandlers) HandleClientPortalStatement() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientPortalService := a.clientPortalService
+	clientService := a.clientService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		month, err := time.Parse("2006-01", chi.URLParam(r, "month"))
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid month", shared.NewInvalidParam("month", "month", "must be a month like 2024-03"))
+			return
+		}
+
+		statement, err := clientPortalService.ReadClientPortalStatement(r.Context(), principal, month, month.AddDate(0, 1, 0))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		renderClientStatement(w, r, isProduction, clientService, statement, month)
+	}
+}
+
+func mapToClientPortalUserModel(clientPortalUser *ClientPortalUser) *clientPortalUserModel {
+	return &clientPortalUserModel{
+		ID:       clientPortalUser.ID.String(),
+		Username: clientPortalUser.Username,
+		Name:     clientPortalUser.Name,
+		EMail:    clientPortalUser.EMail,
+		Links: hal.NewLinks(
+			hal.NewLink("delete", fmt.Sprintf("/api/clients/%s/portal-users/%s", clientPortalUser.ClientID, clientPortalUser.ID)),
+		),
+	}
+}
+
+func mapToClientPortalReportModel(statement *ClientStatement) *clientPortalReportModel {
+	itemModels := make([]*clientPortalReportItemModel, len(statement.Items))
+	for i, item := range statement.Items {
+		itemModels[i] = &clientPortalReportItemModel{
+			ProjectID:         item.ProjectID.String(),
+			ProjectTitle:      item.ProjectTitle,
+			MinutesTotal:      item.DurationInMinutesTotal,
+			DurationFormatted: item.DurationFormatted(),
+		}
+	}
+
+	return &clientPortalReportModel{
+		Client:       statement.Client.Name,
+		Start:        time_utils.FormatDate(statement.Start),
+		End:          time_utils.FormatDate(statement.End),
+		MinutesTotal: statement.DurationInMinutesTotal(),
+		Projects:     itemModels,
+	}
+}
@@ -0,0 +1,86 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/pdf"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestClientPortalRestHandlers(t *testing.T) {
+	is := is.New(t)
+
+	clientRepository := NewInMemClientRepository()
+	a := NewClientPortalRestHandlers(
+		&shared.Config{},
+		NewClientPortalService(shared.NewInMemRepositoryTxer(), clientRepository, NewInMemClientPortalRepository(), shared.NewBcryptPasswordHasher(4)),
+		NewClientService(shared.NewInMemRepositoryTxer(), newInMemBrandingService(), clientRepository, NewInMemProjectRepository()),
+	)
+
+	router := chi.NewRouter()
+	a.RegisterProtected(router)
+
+	request := func(method, path, body string, principal *shared.Principal) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest(method, path, strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+		router.ServeHTTP(httpRec, r)
+		return httpRec
+	}
+
+	adminPrincipal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+	clientPrincipal := &shared.Principal{
+		Username:       "client@acme.com",
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_CLIENT"},
+	}
+
+	httpRec := request("POST", "/clients/"+clientIDSample.String()+"/portal-users", `{"username": "client@acme.com"}`, adminPrincipal)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = request("POST", "/clients/"+clientIDSample.String()+"/portal-users", `{"username": "client@acme.com", "password": "s3cret-passw0rd"}`, adminPrincipal)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	userModel := &clientPortalUserModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(userModel)
+	is.NoErr(err)
+	is.Equal(userModel.Username, "client@acme.com")
+	is.Equal(userModel.Password, "")
+
+	httpRec = request("GET", "/clients/"+clientIDSample.String()+"/portal-users", "", adminPrincipal)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.True(strings.Contains(httpRec.Body.String(), "client@acme.com"))
+
+	httpRec = request("GET", "/client-portal/report?t=month&v=2021-12", "", clientPrincipal)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	reportModel := &clientPortalReportModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(reportModel)
+	is.NoErr(err)
+	is.Equal(reportModel.Client, "ACME Corp.")
+	is.Equal(reportModel.MinutesTotal, 90)
+	is.True(!strings.Contains(httpRec.Body.String(), "username"))
+
+	httpRec = request("GET", "/client-portal/statements/2021-12?contentType="+pdf.ContentType, "", clientPrincipal)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(httpRec.Result().Header.Get("Content-Type"), pdf.ContentType)
+
+	httpRec = request("GET", "/client-portal/report", "", adminPrincipal)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = request("DELETE", "/clients/"+clientIDSample.String()+"/portal-users/"+userModel.ID, "", adminPrincipal)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = request("GET", "/client-portal/report", "", clientPrincipal)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
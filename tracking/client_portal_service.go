@@ -0,0 +1,140 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ClientPortalService manages the users of clients and the read-only access of client users
+// to the aggregated reports and statements of their client's projects
+type ClientPortalService struct {
+	repositoryTxer         shared.RepositoryTxer
+	clientRepository       ClientRepository
+	clientPortalRepository ClientPortalRepository
+	passwordHasher         shared.PasswordHasher
+}
+
+// NewClientPortalService creates a new service for the client portal
+func NewClientPortalService(repositoryTxer shared.RepositoryTxer, clientRepository ClientRepository, clientPortalRepository ClientPortalRepository, passwordHasher shared.PasswordHasher) *ClientPortalService {
+	return &ClientPortalService{
+		repositoryTxer:         repositoryTxer,
+		clientRepository:       clientRepository,
+		clientPortalRepository: clientPortalRepository,
+		passwordHasher:         passwordHasher,
+	}
+}
+
+// ReadClientPortalUsers reads the portal users of the client
+func (s *ClientPortalService) ReadClientPortalUsers(ctx context.Context, principal *shared.Principal, clientID uuid.UUID) ([]*ClientPortalUser, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.clientPortalRepository.FindClientPortalUsers(ctx, principal.OrganizationID, clientID)
+}
+
+// CreateClientPortalUser creates a user of the client which can only log in to the client portal
+func (s *ClientPortalService) CreateClientPortalUser(ctx context.Context, principal *shared.Principal, clientPortalUser *ClientPortalUser, password string) (*ClientPortalUser, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, clientPortalUser.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedPassword, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	clientPortalUser.ID = uuid.New()
+	clientPortalUser.OrganizationID = principal.OrganizationID
+	clientPortalUser.Password = encryptedPassword
+	if clientPortalUser.Name == "" {
+		clientPortalUser.Name = clientPortalUser.Username
+	}
+
+	var clientPortalUserCreated *ClientPortalUser
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			u, err := s.clientPortalRepository.InsertClientPortalUser(ctx, clientPortalUser)
+			if err != nil {
+				return err
+			}
+			clientPortalUserCreated = u
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return clientPortalUserCreated, nil
+}
+
+// DeleteClientPortalUser revokes the access of a user to the client portal
+func (s *ClientPortalService) DeleteClientPortalUser(ctx context.Context, principal *shared.Principal, clientID, userID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.clientPortalRepository.DeleteClientPortalUser(ctx, principal.OrganizationID, clientID, userID)
+		},
+	)
+}
+
+// ReadClientPortalStatement reads the tracked time and amount of the projects of the client user's client
+// in the timespan, the time is aggregated per project without any details of the users who tracked it
+func (s *ClientPortalService) ReadClientPortalStatement(ctx context.Context, principal *shared.Principal, start, end time.Time) (*ClientStatement, error) {
+	clientID, err := s.clientIDOf(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	reportItems, err := s.clientRepository.ClientReport(ctx, principal.OrganizationID, clientID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientStatement{
+		Client: client,
+		Start:  start,
+		End:    end,
+		Items:  reportItems,
+	}, nil
+}
+
+// clientIDOf is the client a client user has portal access to, other users have no access
+func (s *ClientPortalService) clientIDOf(ctx context.Context, principal *shared.Principal) (uuid.UUID, error) {
+	if !principal.HasRole("ROLE_CLIENT") {
+		return uuid.Nil, shared.ErrForbidden
+	}
+
+	clientID, err := s.clientPortalRepository.FindClientIDOfPortalUser(ctx, principal.OrganizationID, principal.Username)
+	if err != nil {
+		if errors.Is(err, ErrClientPortalUserNotFound) {
+			return uuid.Nil, shared.ErrForbidden
+		}
+		return uuid.Nil, err
+	}
+	return clientID, nil
+}
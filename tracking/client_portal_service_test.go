@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestClientPortalService(t *testing.T) {
+	is := is.New(t)
+
+	clientPortalService := &ClientPortalService{
+		repositoryTxer:         shared.NewInMemRepositoryTxer(),
+		clientRepository:       NewInMemClientRepository(),
+		clientPortalRepository: NewInMemClientPortalRepository(),
+		passwordHasher:         shared.NewBcryptPasswordHasher(4),
+	}
+
+	adminPrincipal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+	clientPrincipal := &shared.Principal{
+		Username:       "client@acme.com",
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_CLIENT"},
+	}
+	start := time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	t.Run("client user without portal access", func(t *testing.T) {
+		_, err := clientPortalService.ReadClientPortalStatement(context.Background(), clientPrincipal, start, end)
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	var clientPortalUser *ClientPortalUser
+	t.Run("admin creates client portal user", func(t *testing.T) {
+		u, err := clientPortalService.CreateClientPortalUser(context.Background(), adminPrincipal, &ClientPortalUser{
+			ClientID: clientIDSample,
+			Username: "client@acme.com",
+		}, "s3cret-passw0rd")
+		is.NoErr(err)
+		is.True(u.ID != uuid.Nil)
+		is.Equal(u.Name, "client@acme.com")
+		is.True(u.Password != "s3cret-passw0rd")
+		clientPortalUser = u
+
+		_, err = clientPortalService.CreateClientPortalUser(context.Background(), adminPrincipal, &ClientPortalUser{
+			ClientID: clientIDSample,
+			Username: "client@acme.com",
+		}, "s3cret-passw0rd")
+		is.Equal(err, ErrClientPortalUsernameTaken)
+
+		clientPortalUsers, err := clientPortalService.ReadClientPortalUsers(context.Background(), adminPrincipal, clientIDSample)
+		is.NoErr(err)
+		is.Equal(len(clientPortalUsers), 1)
+	})
+
+	t.Run("user must not create client portal user", func(t *testing.T) {
+		principal := &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          []string{"ROLE_USER"},
+		}
+
+		_, err := clientPortalService.CreateClientPortalUser(context.Background(), principal, &ClientPortalUser{
+			ClientID: clientIDSample,
+			Username: "other@acme.com",
+		}, "s3cret-passw0rd")
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	t.Run("client user reads statement of own client", func(t *testing.T) {
+		statement, err := clientPortalService.ReadClientPortalStatement(context.Background(), clientPrincipal, start, end)
+		is.NoErr(err)
+		is.Equal(statement.Client.ID, clientIDSample)
+		is.Equal(len(statement.Items), 1)
+	})
+
+	t.Run("admin is no client user", func(t *testing.T) {
+		_, err := clientPortalService.ReadClientPortalStatement(context.Background(), adminPrincipal, start, end)
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	t.Run("admin revokes portal access", func(t *testing.T) {
+		err := clientPortalService.DeleteClientPortalUser(context.Background(), adminPrincipal, clientIDSample, clientPortalUser.ID)
+		is.NoErr(err)
+
+		_, err = clientPortalService.ReadClientPortalStatement(context.Background(), clientPrincipal, start, end)
+		is.Equal(err, shared.ErrForbidden)
+	})
+}
@@ -0,0 +1,240 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbClientRepository is a SQL database repository for clients
+type DbClientRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ ClientRepository = (*DbClientRepository)(nil)
+
+// NewDbClientRepository creates a new SQL database repository for clients
+func NewDbClientRepository(connPool *pgxpool.Pool) *DbClientRepository {
+	return &DbClientRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbClientRepository) FindClients(ctx context.Context, organizationID uuid.UUID) ([]*Client, error) {
+	rows, err := shared.SelectAll[clientRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[clientRow]()+` 
+		 FROM clients 
+		 WHERE org_id = $1 
+		 ORDER BY name ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make([]*Client, len(rows))
+	for i, row := range rows {
+		clients[i] = row.toClient()
+	}
+	return clients, nil
+}
+
+func (r *DbClientRepository) FindClientByID(ctx context.Context, organizationID, clientID uuid.UUID) (*Client, error) {
+	row, err := shared.SelectOne[clientRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[clientRow]()+` 
+		 FROM clients 
+		 WHERE client_id = $1 AND org_id = $2`,
+		clientID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrClientNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toClient(), nil
+}
+
+func (r *DbClientRepository) FindProjectIDsOfClient(ctx context.Context, organizationID, clientID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT project_id 
+		 FROM projects 
+		 WHERE org_id = $1 AND client_id = $2 
+		 ORDER BY title ASC`,
+		organizationID, clientID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projectIDs []uuid.UUID
+	for rows.Next() {
+		var projectID uuid.UUID
+		err := rows.Scan(&projectID)
+		if err != nil {
+			return nil, err
+		}
+		projectIDs = append(projectIDs, projectID)
+	}
+
+	return projectIDs, rows.Err()
+}
+
+func (r *DbClientRepository) InsertClient(ctx context.Context, client *Client) (*Client, error) {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO clients 
+		   (client_id, org_id, name, hourly_rate_cents, currency) 
+		 VALUES 
+		   ($1, $2, $3, $4, $5)`,
+		client.ID,
+		client.OrganizationID,
+		client.Name,
+		client.HourlyRateCents,
+		client.Currency,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (r *DbClientRepository) UpdateClient(ctx context.Context, client *Client) (*Client, error) {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	row := tx.QueryRow(ctx,
+		`UPDATE clients 
+		 SET name = $3, hourly_rate_cents = $4, currency = $5 
+		 WHERE client_id = $1 AND org_id = $2
+		 RETURNING client_id`,
+		client.ID, client.OrganizationID,
+		client.Name, client.HourlyRateCents, client.Currency,
+	)
+
+	var id string
+	err := row.Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrClientNotFound
+		}
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (r *DbClientRepository) DeleteClientByID(ctx context.Context, organizationID, clientID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	row := tx.QueryRow(ctx,
+		`DELETE 
+         FROM clients 
+	     WHERE client_id = $1 AND org_id = $2
+		 RETURNING client_id`,
+		clientID, organizationID,
+	)
+
+	var id string
+	err := row.Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrClientNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// AssignProjectToClient assigns the project to the client, without client the project is unassigned
+func (r *DbClientRepository) AssignProjectToClient(ctx context.Context, organizationID, projectID uuid.UUID, clientID *uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	row := tx.QueryRow(ctx,
+		`UPDATE projects 
+		 SET client_id = $3 
+		 WHERE project_id = $1 AND org_id = $2
+		 RETURNING project_id`,
+		projectID, organizationID, clientID,
+	)
+
+	var id string
+	err := row.Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProjectNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// ClientReport reports the tracked time per project of the client, projects without tracked time are left out
+func (r *DbClientRepository) ClientReport(ctx context.Context, organizationID, clientID uuid.UUID, start, end time.Time) ([]*ClientReportItem, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT p.project_id, p.title, p.billable, sum(ag.duration_minutes_total) as duration_minutes_total 
+		 FROM projects p 
+		 INNER JOIN activities_agg ag 
+		 ON ag.project_id = p.project_id AND ag.org_id = p.org_id 
+		 WHERE p.org_id = $1 AND p.client_id = $2 AND $3 <= ag.start_time AND ag.start_time < $4 
+		 GROUP BY p.project_id, p.title, p.billable 
+		 ORDER BY p.title ASC`,
+		organizationID, clientID, start, end,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reportItems []*ClientReportItem
+	for rows.Next() {
+		reportItem := &ClientReportItem{}
+		err := rows.Scan(&reportItem.ProjectID, &reportItem.ProjectTitle, &reportItem.Billable, &reportItem.DurationInMinutesTotal)
+		if err != nil {
+			return nil, err
+		}
+		reportItems = append(reportItems, reportItem)
+	}
+
+	return reportItems, rows.Err()
+}
+
+// clientRow is a row of the clients table
+type clientRow struct {
+	ID              uuid.UUID `db:"client_id"`
+	OrganizationID  uuid.UUID `db:"org_id"`
+	Name            string    `db:"name"`
+	HourlyRateCents int       `db:"hourly_rate_cents"`
+	Currency        string    `db:"currency"`
+}
+
+func (r *clientRow) toClient() *Client {
+	return &Client{
+		ID:              r.ID,
+		OrganizationID:  r.OrganizationID,
+		Name:            r.Name,
+		HourlyRateCents: r.HourlyRateCents,
+		Currency:        r.Currency,
+	}
+}
@@ -0,0 +1,117 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestClientRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	clientRepository := NewDbClientRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	client := &Client{
+		ID:              uuid.New(),
+		OrganizationID:  shared.OrganizationIDSample,
+		Name:            "ACME Corp.",
+		HourlyRateCents: 9000,
+		Currency:        "EUR",
+	}
+
+	t.Run("InsertClient", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := clientRepository.InsertClient(ctx, client)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		clients, err := clientRepository.FindClients(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(clients), 1)
+		is.Equal(clients[0].Name, "ACME Corp.")
+	})
+
+	t.Run("UpdateClient", func(t *testing.T) {
+		client.HourlyRateCents = 10000
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := clientRepository.UpdateClient(ctx, client)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		clientUpdate, err := clientRepository.FindClientByID(context.Background(), shared.OrganizationIDSample, client.ID)
+		is.NoErr(err)
+		is.Equal(clientUpdate.HourlyRateCents, 10000)
+	})
+
+	t.Run("AssignProjectToClient", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return clientRepository.AssignProjectToClient(ctx, shared.OrganizationIDSample, shared.ProjectIDSample, &client.ID)
+			},
+		)
+		is.NoErr(err)
+
+		projectIDs, err := clientRepository.FindProjectIDsOfClient(context.Background(), shared.OrganizationIDSample, client.ID)
+		is.NoErr(err)
+		is.Equal(projectIDs, []uuid.UUID{shared.ProjectIDSample})
+
+		_, err = clientRepository.ClientReport(context.Background(), shared.OrganizationIDSample, client.ID, time.Now().AddDate(-1, 0, 0), time.Now())
+		is.NoErr(err)
+	})
+
+	t.Run("AssignNotExistingProjectToClient", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return clientRepository.AssignProjectToClient(ctx, shared.OrganizationIDSample, uuid.New(), &client.ID)
+			},
+		)
+		is.Equal(err, ErrProjectNotFound)
+	})
+
+	t.Run("DeleteClient", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return clientRepository.DeleteClientByID(ctx, shared.OrganizationIDSample, client.ID)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = clientRepository.FindClientByID(context.Background(), shared.OrganizationIDSample, client.ID)
+		is.Equal(err, ErrClientNotFound)
+	})
+}
@@ -0,0 +1,145 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// clientIDSample is the id of the sample client of the in memory repository
+var clientIDSample = uuid.MustParse("00000000-0000-0000-5555-000000000001")
+
+type InMemClientRepository struct {
+	mu               sync.Mutex
+	clients          []*Client
+	clientOfProjects map[uuid.UUID]uuid.UUID
+}
+
+var _ ClientRepository = (*InMemClientRepository)(nil)
+
+func NewInMemClientRepository() *InMemClientRepository {
+	return &InMemClientRepository{
+		clients: []*Client{
+			{
+				ID:              clientIDSample,
+				OrganizationID:  shared.OrganizationIDSample,
+				Name:            "ACME Corp.",
+				HourlyRateCents: 9000,
+				Currency:        "EUR",
+			},
+		},
+		clientOfProjects: map[uuid.UUID]uuid.UUID{
+			shared.ProjectIDSample: clientIDSample,
+		},
+	}
+}
+
+func (r *InMemClientRepository) FindClients(ctx context.Context, organizationID uuid.UUID) ([]*Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var clients []*Client
+	for _, c := range r.clients {
+		if c.OrganizationID == organizationID {
+			clients = append(clients, c)
+		}
+	}
+	return clients, nil
+}
+
+func (r *InMemClientRepository) FindClientByID(ctx context.Context, organizationID, clientID uuid.UUID) (*Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.clients {
+		if c.ID == clientID && c.OrganizationID == organizationID {
+			return c, nil
+		}
+	}
+	return nil, ErrClientNotFound
+}
+
+func (r *InMemClientRepository) FindProjectIDsOfClient(ctx context.Context, organizationID, clientID uuid.UUID) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var projectIDs []uuid.UUID
+	for projectID, cID := range r.clientOfProjects {
+		if cID == clientID {
+			projectIDs = append(projectIDs, projectID)
+		}
+	}
+	return projectIDs, nil
+}
+
+func (r *InMemClientRepository) InsertClient(ctx context.Context, client *Client) (*Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clients = append(r.clients, client)
+	return client, nil
+}
+
+func (r *InMemClientRepository) UpdateClient(ctx context.Context, client *Client) (*Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, c := range r.clients {
+		if c.ID == client.ID && c.OrganizationID == client.OrganizationID {
+			r.clients[i] = client
+			return client, nil
+		}
+	}
+	return nil, ErrClientNotFound
+}
+
+func (r *InMemClientRepository) DeleteClientByID(ctx context.Context, organizationID, clientID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, c := range r.clients {
+		if c.ID == clientID && c.OrganizationID == organizationID {
+			r.clients = append(r.clients[:i], r.clients[i+1:]...)
+			for projectID, cID := range r.clientOfProjects {
+				if cID == clientID {
+					delete(r.clientOfProjects, projectID)
+				}
+			}
+			return nil
+		}
+	}
+	return ErrClientNotFound
+}
+
+func (r *InMemClientRepository) AssignProjectToClient(ctx context.Context, organizationID, projectID uuid.UUID, clientID *uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if clientID == nil {
+		delete(r.clientOfProjects, projectID)
+		return nil
+	}
+	r.clientOfProjects[projectID] = *clientID
+	return nil
+}
+
+func (r *InMemClientRepository) ClientReport(ctx context.Context, organizationID, clientID uuid.UUID, start, end time.Time) ([]*ClientReportItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var reportItems []*ClientReportItem
+	for projectID, cID := range r.clientOfProjects {
+		if cID == clientID {
+			reportItems = append(reportItems, &ClientReportItem{
+				ProjectID:              projectID,
+				ProjectTitle:           "My Project",
+				Billable:               true,
+				DurationInMinutesTotal: 90,
+			})
+		}
+	}
+	return reportItems, nil
+}
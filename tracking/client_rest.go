@@ -0,0 +1,487 @@
+package tracking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/baralga/shared/pdf"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+const defaultClientCurrency = "EUR"
+
+type clientModel struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name" validate:"required,min=1,max=100"`
+	HourlyRate *float64   `json:"hourlyRate,omitempty" validate:"required,min=0"`
+	Currency   string     `json:"currency" validate:"omitempty,len=3,uppercase"`
+	ProjectIDs []string   `json:"projectIds,omitempty"`
+	Links      *hal.Links `json:"_links"`
+}
+
+type clientsModel struct {
+	Embedded *embeddedClients `json:"_embedded"`
+	Links    *hal.Links       `json:"_links"`
+}
+
+type embeddedClients struct {
+	ClientModels []*clientModel `json:"clients"`
+}
+
+type clientStatementModel struct {
+	ClientID        string                      `json:"clientId"`
+	Client          string                      `json:"client"`
+	Start           string                      `json:"start"`
+	End             string                      `json:"end"`
+	Currency        string                      `json:"currency"`
+	HourlyRate      float64                     `json:"hourlyRate"`
+	MinutesTotal    int                         `json:"minutesTotal"`
+	Amount          float64                     `json:"amount"`
+	AmountFormatted string                      `json:"amountFormatted"`
+	Projects        []*clientStatementItemModel `json:"projects"`
+	Links           *hal.Links                  `json:"_links"`
+}
+
+type clientStatementItemModel struct {
+	ProjectID         string  `json:"projectId"`
+	ProjectTitle      string  `json:"projectTitle"`
+	Billable          bool    `json:"billable"`
+	MinutesTotal      int     `json:"minutesTotal"`
+	DurationFormatted string  `json:"durationFormatted"`
+	Amount            float64 `json:"amount"`
+	AmountFormatted   string  `json:"amountFormatted"`
+}
+
+type ClientRestHandlers struct {
+	config        *shared.Config
+	clientService *ClientService
+}
+
+func NewClientRestHandlers(config *shared.Config, clientService *ClientService) *ClientRestHandlers {
+	return &ClientRestHandlers{
+		config:        config,
+		clientService: clientService,
+	}
+}
+
+func (a *ClientRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/clients", a.HandleGetClients())
+	r.Post("/clients", a.HandleCreateClient())
+	r.Get("/clients/{client-id}", a.HandleGetClient())
+	r.Patch("/clients/{client-id}", a.HandleUpdateClient())
+	r.Delete("/clients/{client-id}", a.HandleDeleteClient())
+	r.Put("/clients/{client-id}/projects/{project-id}", a.HandleAssignProject())
+	r.Delete("/clients/{client-id}/projects/{project-id}", a.HandleUnassignProject())
+	r.Get("/clients/{client-id}/report", a.HandleClientReport())
+	r.Get("/clients/{client-id}/statements/{month}", a.HandleClientStatement())
+}
+
+func (a *ClientRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetClients reads the clients of the organization
+func (a *ClientRestHandlers) HandleGetClients() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientService := a.clientService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clients, err := clientService.ReadClients(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		clientModels := make([]*clientModel, len(clients))
+		for i, client := range clients {
+			clientModels[i] = mapToClientModel(principal, client, nil)
+		}
+
+		shared.RenderJSON(w, &clientsModel{
+			Embedded: &embeddedClients{
+				ClientModels: clientModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleGetClient reads a client with its projects
+func (a *ClientRestHandlers) HandleGetClient() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientService := a.clientService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		client, projectIDs, err := clientService.ReadClient(r.Context(), principal, clientID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToClientModel(principal, client, projectIDs))
+	}
+}
+
+// HandleCreateClient creates a client
+func (a *ClientRestHandlers) HandleCreateClient() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	clientService := a.clientService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var clientModel clientModel
+		err := json.NewDecoder(r.Body).Decode(&clientModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "client not valid", err)
+			return
+		}
+
+		err = validator.Struct(clientModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "client not valid", err)
+			return
+		}
+
+		client, err := clientService.CreateClient(r.Context(), principal, mapToClient(&clientModel))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToClientModel(principal, client, nil))
+	}
+}
+
+// HandleUpdateClient updates a client, either in full or with a JSON Merge Patch
+func (a *ClientRestHandlers) HandleUpdateClient() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	clientService := a.clientService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "client not valid", err)
+			return
+		}
+
+		if shared.IsMergePatch(r) {
+			currentClient, _, err := clientService.ReadClient(r.Context(), principal, clientID)
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+
+			body, err = shared.MergePatchModel(mapToClientModel(principal, currentClient, nil), body)
+			if err != nil {
+				shared.RenderValidationProblemJSON(w, "client not valid", err)
+				return
+			}
+		}
+
+		var clientModel clientModel
+		err = json.Unmarshal(body, &clientModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "client not valid", err)
+			return
+		}
+
+		err = validator.Struct(clientModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "client not valid", err)
+			return
+		}
+
+		client := mapToClient(&clientModel)
+		client.ID = clientID
+
+		clientUpdate, err := clientService.UpdateClient(r.Context(), principal, client)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToClientModel(principal, clientUpdate, nil))
+	}
+}
+
+// HandleDeleteClient deletes a client
+func (a *ClientRestHandlers) HandleDeleteClient() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientService := a.clientService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = clientService.DeleteClientByID(r.Context(), principal, clientID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+// HandleAssignProject assigns a project to the client
+func (a *ClientRestHandlers) HandleAssignProject() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientService := a.clientService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, projectID, err := clientAndProjectIDOf(r)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = clientService.AssignProject(r.Context(), principal, clientID, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleUnassignProject removes a project from the client
+func (a *ClientRestHandlers) HandleUnassignProject() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientService := a.clientService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, projectID, err := clientAndProjectIDOf(r)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = clientService.UnassignProject(r.Context(), principal, clientID, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleClientReport reads the tracked time and amount of all projects of the client in the timespan of the query params
+func (a *ClientRestHandlers) HandleClientReport() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientService := a.clientService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		filter, err := filterFromQueryParams(r.URL.Query())
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		statement, err := clientService.ClientStatement(r.Context(), principal, clientID, filter.Start(), filter.End())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		statementModel := mapToClientStatementModel(statement)
+		statementModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+		)
+
+		shared.RenderJSON(w, statementModel)
+	}
+}
+
+// HandleClientStatement reads the monthly statement of the client as JSON, CSV or PDF
+func (a *ClientRestHandlers) HandleClientStatement() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	clientService := a.clientService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		month, err := time.Parse("2006-01", chi.URLParam(r, "month"))
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid month", shared.NewInvalidParam("month", "month", "must be a month like 2024-03"))
+			return
+		}
+
+		statement, err := clientService.ClientStatement(r.Context(), principal, clientID, month, month.AddDate(0, 1, 0))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		renderClientStatement(w, r, isProduction, clientService, statement, month)
+	}
+}
+
+// renderClientStatement renders the monthly statement as JSON, CSV or PDF depending on the requested content type
+func renderClientStatement(w http.ResponseWriter, r *http.Request, isProduction bool, clientService *ClientService, statement *ClientStatement, month time.Time) {
+	contentType := r.URL.Query().Get("contentType")
+	if contentType == "" {
+		contentType = r.Header.Get("Content-Type")
+	}
+	fileName := fmt.Sprintf("Statement_%s_%s", statement.Client.Name, month.Format("2006-01"))
+
+	switch contentType {
+	case "text/csv", pdf.ContentType:
+		buf := &bytes.Buffer{}
+		var err error
+		if contentType == pdf.ContentType {
+			err = clientService.WriteStatementAsPDF(r.Context(), statement, buf)
+			fileName += ".pdf"
+		} else {
+			err = clientService.WriteStatementAsCSV(statement, buf)
+			fileName += ".csv"
+		}
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+		_, _ = buf.WriteTo(w)
+	default:
+		statementModel := mapToClientStatementModel(statement)
+		statementModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+			hal.NewLink("csv", fmt.Sprintf("%s?contentType=text/csv", r.URL.Path)),
+			hal.NewLink("pdf", fmt.Sprintf("%s?contentType=%s", r.URL.Path, pdf.ContentType)),
+		)
+		shared.RenderJSON(w, statementModel)
+	}
+}
+
+func clientAndProjectIDOf(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	clientID, err := uuid.Parse(chi.URLParam(r, "client-id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	projectID, err := uuid.Parse(chi.URLParam(r, "project-id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return clientID, projectID, nil
+}
+
+func mapToClient(clientModel *clientModel) *Client {
+	currency := clientModel.Currency
+	if currency == "" {
+		currency = defaultClientCurrency
+	}
+	return &Client{
+		Name:            clientModel.Name,
+		HourlyRateCents: int(math.Round(*clientModel.HourlyRate * 100)),
+		Currency:        currency,
+	}
+}
+
+func mapToClientModel(principal *shared.Principal, client *Client, projectIDs []uuid.UUID) *clientModel {
+	clientModel := &clientModel{
+		ID:       client.ID.String(),
+		Name:     client.Name,
+		Currency: client.Currency,
+	}
+
+	for _, projectID := range projectIDs {
+		clientModel.ProjectIDs = append(clientModel.ProjectIDs, projectID.String())
+	}
+
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/clients/%s", client.ID))
+	if principal.HasRole("ROLE_ADMIN") {
+		hourlyRate := float64(client.HourlyRateCents) / 100
+		clientModel.HourlyRate = &hourlyRate
+		clientModel.Links = hal.NewLinks(
+			selfLink,
+			hal.NewLink("delete", selfLink.Href()),
+			hal.NewLink("edit", selfLink.Href()),
+			hal.NewLink("report", fmt.Sprintf("/api/clients/%s/report", client.ID)),
+		)
+	} else {
+		clientModel.Links = hal.NewLinks(
+			selfLink,
+		)
+	}
+	return clientModel
+}
+
+func mapToClientStatementModel(statement *ClientStatement) *clientStatementModel {
+	itemModels := make([]*clientStatementItemModel, len(statement.Items))
+	for i, item := range statement.Items {
+		amountCents := statement.AmountCents(item)
+		itemModels[i] = &clientStatementItemModel{
+			ProjectID:         item.ProjectID.String(),
+			ProjectTitle:      item.ProjectTitle,
+			Billable:          item.Billable,
+			MinutesTotal:      item.DurationInMinutesTotal,
+			DurationFormatted: item.DurationFormatted(),
+			Amount:            float64(amountCents) / 100,
+			AmountFormatted:   statement.FormatAmount(amountCents),
+		}
+	}
+
+	totalCents := statement.TotalAmountCents()
+	return &clientStatementModel{
+		ClientID:        statement.Client.ID.String(),
+		Client:          statement.Client.Name,
+		Start:           time_utils.FormatDate(statement.Start),
+		End:             time_utils.FormatDate(statement.End),
+		Currency:        statement.Client.Currency,
+		HourlyRate:      float64(statement.Client.HourlyRateCents) / 100,
+		MinutesTotal:    statement.DurationInMinutesTotal(),
+		Amount:          float64(totalCents) / 100,
+		AmountFormatted: statement.FormatAmount(totalCents),
+		Projects:        itemModels,
+	}
+}
@@ -0,0 +1,196 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func newClientTestRouter() chi.Router {
+	a := NewClientRestHandlers(&shared.Config{}, &ClientService{
+		repositoryTxer:    shared.NewInMemRepositoryTxer(),
+		brandingService:   newInMemBrandingService(),
+		clientRepository:  NewInMemClientRepository(),
+		projectRepository: NewInMemProjectRepository(),
+	})
+
+	r := chi.NewRouter()
+	a.RegisterProtected(r)
+	return r
+}
+
+func newClientTestRequest(method, url, body string, roles ...string) *http.Request {
+	r, _ := http.NewRequest(method, url, strings.NewReader(body))
+	return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          roles,
+	}))
+}
+
+func TestHandleGetClients(t *testing.T) {
+	is := is.New(t)
+	router := newClientTestRouter()
+
+	httpRec := httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("GET", "/clients", "", "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	clientsModel := &clientsModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(clientsModel)
+	is.NoErr(err)
+	is.Equal(len(clientsModel.Embedded.ClientModels), 1)
+	is.Equal(clientsModel.Embedded.ClientModels[0].Name, "ACME Corp.")
+	is.Equal(clientsModel.Embedded.ClientModels[0].HourlyRate, nil)
+}
+
+func TestHandleGetClient(t *testing.T) {
+	is := is.New(t)
+	router := newClientTestRouter()
+
+	httpRec := httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("GET", fmt.Sprintf("/clients/%s", clientIDSample), "", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	clientModel := &clientModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(clientModel)
+	is.NoErr(err)
+	is.Equal(*clientModel.HourlyRate, 90.0)
+	is.Equal(clientModel.ProjectIDs, []string{shared.ProjectIDSample.String()})
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("GET", "/clients/not-a-uuid", "", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleCreateClient(t *testing.T) {
+	is := is.New(t)
+	router := newClientTestRouter()
+
+	httpRec := httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("POST", "/clients", `{"name": "Globex", "hourlyRate": 120.5}`, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	clientModel := &clientModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(clientModel)
+	is.NoErr(err)
+	is.Equal(*clientModel.HourlyRate, 120.5)
+	is.Equal(clientModel.Currency, "EUR")
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("POST", "/clients", `{"name": "Globex", "hourlyRate": -1}`, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("POST", "/clients", `{"name": "Globex", "hourlyRate": 100}`, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
+
+func TestHandleUpdateClientWithMergePatch(t *testing.T) {
+	is := is.New(t)
+	router := newClientTestRouter()
+
+	httpRec := httptest.NewRecorder()
+	r := newClientTestRequest("PATCH", fmt.Sprintf("/clients/%s", clientIDSample), `{"hourlyRate": 100}`, "ROLE_ADMIN")
+	r.Header.Set("Content-Type", "application/merge-patch+json")
+	router.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	clientModel := &clientModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(clientModel)
+	is.NoErr(err)
+	is.Equal(clientModel.Name, "ACME Corp.")
+	is.Equal(*clientModel.HourlyRate, 100.0)
+}
+
+func TestHandleUnassignAndAssignProject(t *testing.T) {
+	is := is.New(t)
+	router := newClientTestRouter()
+
+	url := fmt.Sprintf("/clients/%s/projects/%s", clientIDSample, shared.ProjectIDSample)
+
+	httpRec := httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("DELETE", url, "", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("DELETE", url, "", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("PUT", url, "", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+}
+
+func TestHandleClientReport(t *testing.T) {
+	is := is.New(t)
+	router := newClientTestRouter()
+
+	httpRec := httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("GET", fmt.Sprintf("/clients/%s/report?t=year&v=2024", clientIDSample), "", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	statementModel := &clientStatementModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(statementModel)
+	is.NoErr(err)
+	is.Equal(statementModel.Start, "2024-01-01")
+	is.Equal(statementModel.MinutesTotal, 90)
+	is.Equal(statementModel.Amount, 135.0)
+	is.Equal(len(statementModel.Projects), 1)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, newClientTestRequest("GET", fmt.Sprintf("/clients/%s/report?t=year&v=2024", clientIDSample), "", "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
+
+func TestHandleClientStatement(t *testing.T) {
+	is := is.New(t)
+	router := newClientTestRouter()
+
+	url := fmt.Sprintf("/clients/%s/statements/2024-03", clientIDSample)
+
+	t.Run("as JSON", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		router.ServeHTTP(httpRec, newClientTestRequest("GET", url, "", "ROLE_ADMIN"))
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		statementModel := &clientStatementModel{}
+		err := json.NewDecoder(httpRec.Body).Decode(statementModel)
+		is.NoErr(err)
+		is.Equal(statementModel.Start, "2024-03-01")
+		is.Equal(statementModel.End, "2024-04-01")
+		is.Equal(statementModel.AmountFormatted, "135.00 EUR")
+	})
+
+	t.Run("as CSV", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		router.ServeHTTP(httpRec, newClientTestRequest("GET", url+"?contentType=text/csv", "", "ROLE_ADMIN"))
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Header().Get("Content-Type"), "text/csv")
+		is.True(strings.Contains(httpRec.Header().Get("Content-Disposition"), "Statement_ACME Corp._2024-03.csv"))
+		is.True(strings.HasPrefix(httpRec.Body.String(), "Project;"))
+	})
+
+	t.Run("as PDF", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r := newClientTestRequest("GET", url, "", "ROLE_ADMIN")
+		r.Header.Set("Content-Type", "application/pdf")
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Header().Get("Content-Type"), "application/pdf")
+		is.True(strings.HasPrefix(httpRec.Body.String(), "%PDF-"))
+	})
+
+	t.Run("invalid month", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		router.ServeHTTP(httpRec, newClientTestRequest("GET", fmt.Sprintf("/clients/%s/statements/2024-13", clientIDSample), "", "ROLE_ADMIN"))
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,337 @@
+package tracking
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/pdf"
+	"github.com/google/uuid"
+)
+
+var clientStatementCSVHeaders = []string{"Project", "Billable", "Duration", "Hours", "Rate", "Amount"}
+
+// maxStatementTitleLength limits the project titles on a statement, so they don't overlap the columns
+const maxStatementTitleLength = 40
+
+// ClientService manages the clients of an organization and reports their projects
+type ClientService struct {
+	repositoryTxer    shared.RepositoryTxer
+	brandingService   *shared.BrandingService
+	clientRepository  ClientRepository
+	projectRepository ProjectRepository
+}
+
+// NewClientService creates a new service for clients
+func NewClientService(repositoryTxer shared.RepositoryTxer, brandingService *shared.BrandingService, clientRepository ClientRepository, projectRepository ProjectRepository) *ClientService {
+	return &ClientService{
+		repositoryTxer:    repositoryTxer,
+		brandingService:   brandingService,
+		clientRepository:  clientRepository,
+		projectRepository: projectRepository,
+	}
+}
+
+// ReadClients reads the clients of the principal's organization
+func (s *ClientService) ReadClients(ctx context.Context, principal *shared.Principal) ([]*Client, error) {
+	return s.clientRepository.FindClients(ctx, principal.OrganizationID)
+}
+
+// ReadClient reads a client with the ids of its projects
+func (s *ClientService) ReadClient(ctx context.Context, principal *shared.Principal, clientID uuid.UUID) (*Client, []uuid.UUID, error) {
+	client, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, clientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	projectIDs, err := s.clientRepository.FindProjectIDsOfClient(ctx, principal.OrganizationID, clientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, projectIDs, nil
+}
+
+// CreateClient creates a new client
+func (s *ClientService) CreateClient(ctx context.Context, principal *shared.Principal, client *Client) (*Client, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	client.ID = uuid.New()
+	client.OrganizationID = principal.OrganizationID
+
+	var newClient *Client
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			c, err := s.clientRepository.InsertClient(ctx, client)
+			if err != nil {
+				return err
+			}
+			newClient = c
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return newClient, nil
+}
+
+// UpdateClient updates the name and rate of a client
+func (s *ClientService) UpdateClient(ctx context.Context, principal *shared.Principal, client *Client) (*Client, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	client.OrganizationID = principal.OrganizationID
+
+	var clientUpdate *Client
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			c, err := s.clientRepository.UpdateClient(ctx, client)
+			if err != nil {
+				return err
+			}
+			clientUpdate = c
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return clientUpdate, nil
+}
+
+// DeleteClientByID deletes a client, its projects are kept without client
+func (s *ClientService) DeleteClientByID(ctx context.Context, principal *shared.Principal, clientID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.clientRepository.DeleteClientByID(ctx, principal.OrganizationID, clientID)
+		},
+	)
+}
+
+// AssignProject assigns the project to the client, a project belongs to one client at most
+func (s *ClientService) AssignProject(ctx context.Context, principal *shared.Principal, clientID, projectID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	_, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, clientID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.projectRepository.FindProjectByID(ctx, principal.OrganizationID, projectID)
+	if err != nil {
+		return err
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.clientRepository.AssignProjectToClient(ctx, principal.OrganizationID, projectID, &clientID)
+		},
+	)
+}
+
+// UnassignProject removes the project from the client
+func (s *ClientService) UnassignProject(ctx context.Context, principal *shared.Principal, clientID, projectID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	projectIDs, err := s.clientRepository.FindProjectIDsOfClient(ctx, principal.OrganizationID, clientID)
+	if err != nil {
+		return err
+	}
+
+	assigned := false
+	for _, id := range projectIDs {
+		if id == projectID {
+			assigned = true
+			break
+		}
+	}
+	if !assigned {
+		return ErrProjectNotFound
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.clientRepository.AssignProjectToClient(ctx, principal.OrganizationID, projectID, nil)
+		},
+	)
+}
+
+// ClientStatement reports the tracked time and amount of all projects of the client in the timespan,
+// only admins see the statements as they contain the rates
+func (s *ClientService) ClientStatement(ctx context.Context, principal *shared.Principal, clientID uuid.UUID, start, end time.Time) (*ClientStatement, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	client, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	reportItems, err := s.clientRepository.ClientReport(ctx, principal.OrganizationID, clientID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientStatement{
+		Client: client,
+		Start:  start,
+		End:    end,
+		Items:  reportItems,
+	}, nil
+}
+
+// WriteStatementAsCSV writes a line per project of the statement and the total
+func (s *ClientService) WriteStatementAsCSV(statement *ClientStatement, w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Comma = ';'
+
+	err := csvWriter.Write(clientStatementCSVHeaders)
+	if err != nil {
+		return err
+	}
+
+	rate := statement.FormatAmount(statement.Client.HourlyRateCents)
+	for _, item := range statement.Items {
+		err := csvWriter.Write([]string{
+			item.ProjectTitle,
+			strconv.FormatBool(item.Billable),
+			item.DurationFormatted(),
+			formatHours(item.DurationInMinutesTotal),
+			rate,
+			statement.FormatAmount(statement.AmountCents(item)),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	total := &ClientReportItem{DurationInMinutesTotal: statement.DurationInMinutesTotal()}
+	err = csvWriter.Write([]string{
+		"Total",
+		"",
+		total.DurationFormatted(),
+		formatHours(total.DurationInMinutesTotal),
+		"",
+		statement.FormatAmount(statement.TotalAmountCents()),
+	})
+	if err != nil {
+		return err
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// WriteStatementAsPDF renders the statement as PDF document with the branding of the client's organization
+func (s *ClientService) WriteStatementAsPDF(ctx context.Context, statement *ClientStatement, w io.Writer) error {
+	const (
+		left        = 50.0
+		right       = pdf.PageWidth - 50
+		hoursColumn = right - 130
+		lineHeight  = 16.0
+		logoHeight  = 40.0
+		logoWidth   = 150.0
+	)
+
+	branding, err := s.brandingService.ReadBranding(ctx, statement.Client.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	var logo *pdf.Image
+	if branding.HasLogo() {
+		logo, err = s.brandingService.ReadLogoImage(ctx, statement.Client.OrganizationID)
+		if err != nil {
+			return err
+		}
+	}
+
+	doc := pdf.New(fmt.Sprintf("Statement %s", statement.Client.Name))
+	doc.Footer(8, branding.InvoiceFooter)
+	bottom := pdf.PageHeight - 60 - doc.FooterHeight()
+	primaryColor := func() {
+		doc.SetColor(branding.PrimaryColorRGB())
+	}
+	black := func() {
+		doc.SetColor(0, 0, 0)
+	}
+
+	if logo != nil {
+		width, height := logoHeight*logo.AspectRatio(), logoHeight
+		if width > logoWidth {
+			width, height = logoWidth, logoWidth/logo.AspectRatio()
+		}
+		doc.Image(logo, right-width, 40, width, height)
+	}
+
+	primaryColor()
+	doc.Text(left, 70, 18, true, "Statement")
+	black()
+	doc.Text(left, 100, 11, false, statement.Client.Name)
+	doc.Text(left, 116, 11, false, fmt.Sprintf("%s - %s", statement.Start.Format("2006-01-02"), statement.End.AddDate(0, 0, -1).Format("2006-01-02")))
+	doc.Text(left, 132, 11, false, fmt.Sprintf("Hourly rate: %s", statement.FormatAmount(statement.Client.HourlyRateCents)))
+
+	tableHeader := func(y float64) float64 {
+		doc.Text(left, y, 10, true, "Project")
+		doc.TextRight(hoursColumn, y, 10, true, "Hours")
+		doc.TextRight(right, y, 10, true, "Amount")
+		primaryColor()
+		doc.Line(left, y+5, right, y+5)
+		black()
+		return y + lineHeight + 4
+	}
+
+	y := tableHeader(170)
+	for _, item := range statement.Items {
+		if y > bottom {
+			doc.AddPage()
+			y = tableHeader(70)
+		}
+
+		title := []rune(item.ProjectTitle)
+		if len(title) > maxStatementTitleLength {
+			title = append(title[:maxStatementTitleLength-3], []rune("...")...)
+		}
+
+		doc.Text(left, y, 10, false, string(title))
+		doc.TextRight(hoursColumn, y, 10, false, formatHours(item.DurationInMinutesTotal))
+		doc.TextRight(right, y, 10, false, statement.FormatAmount(statement.AmountCents(item)))
+		y += lineHeight
+	}
+
+	primaryColor()
+	doc.Line(left, y-lineHeight+5, right, y-lineHeight+5)
+	black()
+	y += 4
+	doc.Text(left, y, 10, true, "Total")
+	doc.TextRight(hoursColumn, y, 10, true, formatHours(statement.DurationInMinutesTotal()))
+	doc.TextRight(right, y, 10, true, statement.FormatAmount(statement.TotalAmountCents()))
+
+	return doc.Write(w)
+}
+
+func formatHours(minutes int) string {
+	return fmt.Sprintf("%.2f", float64(minutes)/60)
+}
@@ -0,0 +1,198 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestClientServiceCreateClient(t *testing.T) {
+	is := is.New(t)
+
+	clientService := &ClientService{
+		repositoryTxer:    shared.NewInMemRepositoryTxer(),
+		brandingService:   newInMemBrandingService(),
+		clientRepository:  NewInMemClientRepository(),
+		projectRepository: NewInMemProjectRepository(),
+	}
+
+	t.Run("admin creates client", func(t *testing.T) {
+		principal := &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          []string{"ROLE_ADMIN"},
+		}
+
+		client, err := clientService.CreateClient(context.Background(), principal, &Client{Name: "Globex", HourlyRateCents: 12000, Currency: "USD"})
+		is.NoErr(err)
+		is.True(client.ID != uuid.Nil)
+		is.Equal(client.OrganizationID, shared.OrganizationIDSample)
+
+		clients, err := clientService.ReadClients(context.Background(), principal)
+		is.NoErr(err)
+		is.Equal(len(clients), 2)
+	})
+
+	t.Run("user must not create client", func(t *testing.T) {
+		principal := &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          []string{"ROLE_USER"},
+		}
+
+		_, err := clientService.CreateClient(context.Background(), principal, &Client{Name: "Globex"})
+		is.Equal(err, shared.ErrForbidden)
+	})
+}
+
+func TestClientServiceAssignProject(t *testing.T) {
+	is := is.New(t)
+
+	clientService := &ClientService{
+		repositoryTxer:    shared.NewInMemRepositoryTxer(),
+		brandingService:   newInMemBrandingService(),
+		clientRepository:  NewInMemClientRepository(),
+		projectRepository: NewInMemProjectRepository(),
+	}
+
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+
+	err := clientService.UnassignProject(context.Background(), principal, clientIDSample, shared.ProjectIDSample)
+	is.NoErr(err)
+
+	_, projectIDs, err := clientService.ReadClient(context.Background(), principal, clientIDSample)
+	is.NoErr(err)
+	is.Equal(len(projectIDs), 0)
+
+	err = clientService.UnassignProject(context.Background(), principal, clientIDSample, shared.ProjectIDSample)
+	is.Equal(err, ErrProjectNotFound)
+
+	err = clientService.AssignProject(context.Background(), principal, clientIDSample, shared.ProjectIDSample)
+	is.NoErr(err)
+
+	_, projectIDs, err = clientService.ReadClient(context.Background(), principal, clientIDSample)
+	is.NoErr(err)
+	is.Equal(projectIDs, []uuid.UUID{shared.ProjectIDSample})
+
+	err = clientService.AssignProject(context.Background(), principal, clientIDSample, uuid.New())
+	is.Equal(err, ErrProjectNotFound)
+
+	err = clientService.AssignProject(context.Background(), principal, uuid.New(), shared.ProjectIDSample)
+	is.Equal(err, ErrClientNotFound)
+}
+
+func TestClientServiceStatement(t *testing.T) {
+	is := is.New(t)
+
+	clientService := &ClientService{
+		repositoryTxer:    shared.NewInMemRepositoryTxer(),
+		brandingService:   newInMemBrandingService(),
+		clientRepository:  NewInMemClientRepository(),
+		projectRepository: NewInMemProjectRepository(),
+	}
+
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	t.Run("user must not read statement", func(t *testing.T) {
+		principal := &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          []string{"ROLE_USER"},
+		}
+
+		_, err := clientService.ClientStatement(context.Background(), principal, clientIDSample, start, end)
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+
+	statement, err := clientService.ClientStatement(context.Background(), principal, clientIDSample, start, end)
+	is.NoErr(err)
+	is.Equal(len(statement.Items), 1)
+	is.Equal(statement.TotalAmountCents(), 13500)
+
+	t.Run("statement as CSV", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := clientService.WriteStatementAsCSV(statement, buf)
+		is.NoErr(err)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		is.Equal(len(lines), 3)
+		is.Equal(lines[0], "Project;Billable;Duration;Hours;Rate;Amount")
+		is.True(strings.HasSuffix(lines[1], ";1.50;90.00 EUR;135.00 EUR"))
+		is.True(strings.HasPrefix(lines[2], "Total;"))
+	})
+
+	t.Run("statement as PDF", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := clientService.WriteStatementAsPDF(context.Background(), statement, buf)
+		is.NoErr(err)
+
+		out := buf.String()
+		is.True(strings.HasPrefix(out, "%PDF-"))
+		is.True(strings.Contains(out, "(ACME Corp.)"))
+		is.True(strings.Contains(out, "(135.00 EUR)"))
+		is.True(strings.HasSuffix(strings.TrimSpace(out), "%%EOF"))
+	})
+}
+
+func TestClientServiceStatementWithBranding(t *testing.T) {
+	is := is.New(t)
+
+	brandingService := newInMemBrandingService()
+	clientService := &ClientService{
+		repositoryTxer:    shared.NewInMemRepositoryTxer(),
+		brandingService:   brandingService,
+		clientRepository:  NewInMemClientRepository(),
+		projectRepository: NewInMemProjectRepository(),
+	}
+
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+
+	_, err := brandingService.UpdateBranding(context.Background(), principal, "#ff0000", "ACME Corp. - IBAN DE00 1234")
+	is.NoErr(err)
+
+	logo := &bytes.Buffer{}
+	err = png.Encode(logo, image.NewRGBA(image.Rect(0, 0, 4, 2)))
+	is.NoErr(err)
+	_, err = brandingService.UploadLogo(context.Background(), principal, logo, "image/png")
+	is.NoErr(err)
+
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	statement, err := clientService.ClientStatement(context.Background(), principal, clientIDSample, start, start.AddDate(0, 1, 0))
+	is.NoErr(err)
+
+	buf := &bytes.Buffer{}
+	err = clientService.WriteStatementAsPDF(context.Background(), statement, buf)
+	is.NoErr(err)
+
+	out := buf.String()
+	is.True(strings.Contains(out, "/Subtype /Image /Width 4 /Height 2"))
+	is.True(strings.Contains(out, "1.000 0.000 0.000 rg"))
+	is.True(strings.Contains(out, "(ACME Corp. - IBAN DE00 1234)"))
+}
+
+func newInMemBrandingService() *shared.BrandingService {
+	storage := shared.NewInMemStorage()
+	return shared.NewBrandingService(
+		shared.NewInMemRepositoryTxer(),
+		storage,
+		shared.NewScanService(&shared.Config{}, shared.NewInMemRepositoryTxer(), shared.NewInMemOutbox(shared.NewInMemMailResource()), storage, nil),
+		shared.NewInMemBrandingRepository(),
+	)
+}
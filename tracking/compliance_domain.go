@@ -0,0 +1,54 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultMinGroupSize is the minimum number of users of a team aggregate, as common in works council agreements
+	defaultMinGroupSize = 5
+	minMinGroupSize     = 2
+	maxMinGroupSize     = 100
+)
+
+var ErrPerUserEvaluationDisabled = shared.NewDomainError("compliance:per-user-evaluation-disabled", http.StatusForbidden, "evaluation of single users is disabled by the works council mode")
+
+// CompliancePolicy controls how the time of users is evaluated in an organization, in the works council mode
+// only admins evaluate the time of single users and managers only get aggregates of teams of a minimum size
+type CompliancePolicy struct {
+	OrganizationID   uuid.UUID
+	WorksCouncilMode bool
+	MinGroupSize     int
+}
+
+type CompliancePolicyRepository interface {
+	FindCompliancePolicy(ctx context.Context, organizationID uuid.UUID) (*CompliancePolicy, error)
+	UpdateCompliancePolicy(ctx context.Context, policy *CompliancePolicy) error
+}
+
+// NewDefaultCompliancePolicy is the policy of organizations without a policy, the works council mode is off
+func NewDefaultCompliancePolicy(organizationID uuid.UUID) *CompliancePolicy {
+	return &CompliancePolicy{
+		OrganizationID: organizationID,
+		MinGroupSize:   defaultMinGroupSize,
+	}
+}
+
+// IsValidMinGroupSize checks if the minimum group size is within the allowed range
+func IsValidMinGroupSize(minGroupSize int) bool {
+	return minMinGroupSize <= minGroupSize && minGroupSize <= maxMinGroupSize
+}
+
+// AllowsPerUserEvaluation checks whether the principal may evaluate the time per user
+func (p *CompliancePolicy) AllowsPerUserEvaluation(principal *shared.Principal) bool {
+	return !p.WorksCouncilMode || principal.HasRole("ROLE_ADMIN")
+}
+
+// AllowsTeamAggregate checks whether the time of a team of the size may be reported to managers
+func (p *CompliancePolicy) AllowsTeamAggregate(teamSize int) bool {
+	return !p.WorksCouncilMode || teamSize >= p.MinGroupSize
+}
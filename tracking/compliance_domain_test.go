@@ -0,0 +1,29 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestCompliancePolicy(t *testing.T) {
+	is := is.New(t)
+
+	admin := &shared.Principal{Roles: []string{"ROLE_ADMIN"}}
+	user := &shared.Principal{Roles: []string{"ROLE_USER"}}
+
+	policy := NewDefaultCompliancePolicy(shared.OrganizationIDSample)
+	is.True(policy.AllowsPerUserEvaluation(user))
+	is.True(policy.AllowsTeamAggregate(1))
+
+	policy.WorksCouncilMode = true
+	is.True(policy.AllowsPerUserEvaluation(admin))
+	is.True(!policy.AllowsPerUserEvaluation(user))
+	is.True(!policy.AllowsTeamAggregate(4))
+	is.True(policy.AllowsTeamAggregate(5))
+
+	is.True(!IsValidMinGroupSize(1))
+	is.True(IsValidMinGroupSize(2))
+	is.True(!IsValidMinGroupSize(101))
+}
@@ -0,0 +1,66 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbCompliancePolicyRepository is a SQL database repository for compliance policies
+type DbCompliancePolicyRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ CompliancePolicyRepository = (*DbCompliancePolicyRepository)(nil)
+
+// NewDbCompliancePolicyRepository creates a new SQL database repository for compliance policies
+func NewDbCompliancePolicyRepository(connPool *pgxpool.Pool) *DbCompliancePolicyRepository {
+	return &DbCompliancePolicyRepository{
+		connPool: connPool,
+	}
+}
+
+// FindCompliancePolicy reads the compliance policy of the organization, organizations without a policy get the default policy
+func (r *DbCompliancePolicyRepository) FindCompliancePolicy(ctx context.Context, organizationID uuid.UUID) (*CompliancePolicy, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT works_council_mode, min_group_size 
+		 FROM compliance_policies 
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+
+	policy := &CompliancePolicy{OrganizationID: organizationID}
+	err := row.Scan(&policy.WorksCouncilMode, &policy.MinGroupSize)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewDefaultCompliancePolicy(organizationID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// UpdateCompliancePolicy sets the compliance policy of the organization
+func (r *DbCompliancePolicyRepository) UpdateCompliancePolicy(ctx context.Context, policy *CompliancePolicy) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO compliance_policies 
+		   (org_id, works_council_mode, min_group_size) 
+		 VALUES 
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id) DO UPDATE 
+		 SET works_council_mode = EXCLUDED.works_council_mode, min_group_size = EXCLUDED.min_group_size`,
+		policy.OrganizationID,
+		policy.WorksCouncilMode,
+		policy.MinGroupSize,
+	)
+	return err
+}
@@ -0,0 +1,61 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestCompliancePolicyRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	compliancePolicyRepository := NewDbCompliancePolicyRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("FindDefaultCompliancePolicy", func(t *testing.T) {
+		policy, err := compliancePolicyRepository.FindCompliancePolicy(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(!policy.WorksCouncilMode)
+		is.Equal(policy.MinGroupSize, defaultMinGroupSize)
+	})
+
+	t.Run("UpdateCompliancePolicy", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return compliancePolicyRepository.UpdateCompliancePolicy(ctx, &CompliancePolicy{
+					OrganizationID:   shared.OrganizationIDSample,
+					WorksCouncilMode: true,
+					MinGroupSize:     3,
+				})
+			},
+		)
+		is.NoErr(err)
+
+		policy, err := compliancePolicyRepository.FindCompliancePolicy(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(policy.WorksCouncilMode)
+		is.Equal(policy.MinGroupSize, 3)
+	})
+}
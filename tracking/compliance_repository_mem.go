@@ -0,0 +1,42 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemCompliancePolicyRepository struct {
+	mu       sync.Mutex
+	policies map[uuid.UUID]*CompliancePolicy
+}
+
+var _ CompliancePolicyRepository = (*InMemCompliancePolicyRepository)(nil)
+
+func NewInMemCompliancePolicyRepository() *InMemCompliancePolicyRepository {
+	return &InMemCompliancePolicyRepository{
+		policies: make(map[uuid.UUID]*CompliancePolicy),
+	}
+}
+
+func (r *InMemCompliancePolicyRepository) FindCompliancePolicy(ctx context.Context, organizationID uuid.UUID) (*CompliancePolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	policy, ok := r.policies[organizationID]
+	if !ok {
+		return NewDefaultCompliancePolicy(organizationID), nil
+	}
+	found := *policy
+	return &found, nil
+}
+
+func (r *InMemCompliancePolicyRepository) UpdateCompliancePolicy(ctx context.Context, policy *CompliancePolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := *policy
+	r.policies[policy.OrganizationID] = &updated
+	return nil
+}
@@ -0,0 +1,96 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type compliancePolicyModel struct {
+	WorksCouncilMode bool       `json:"worksCouncilMode"`
+	MinGroupSize     int        `json:"minGroupSize"`
+	Links            *hal.Links `json:"_links,omitempty"`
+}
+
+type ComplianceRestHandlers struct {
+	config          *shared.Config
+	activityService *ActitivityService
+}
+
+func NewComplianceRestHandlers(config *shared.Config, activityService *ActitivityService) *ComplianceRestHandlers {
+	return &ComplianceRestHandlers{
+		config:          config,
+		activityService: activityService,
+	}
+}
+
+func (a *ComplianceRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/compliance-policy", a.HandleGetCompliancePolicy())
+	r.Put("/compliance-policy", a.HandleUpdateCompliancePolicy())
+}
+
+func (a *ComplianceRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetCompliancePolicy reads the compliance policy of the organization, so clients know whether per user reports are available
+func (a *ComplianceRestHandlers) HandleGetCompliancePolicy() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		policy, err := activityService.ReadCompliancePolicy(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToCompliancePolicyModel(policy, r.RequestURI))
+	}
+}
+
+// HandleUpdateCompliancePolicy sets the compliance policy of the organization
+func (a *ComplianceRestHandlers) HandleUpdateCompliancePolicy() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		policyModel := compliancePolicyModel{MinGroupSize: defaultMinGroupSize}
+		err := json.NewDecoder(r.Body).Decode(&policyModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "compliance policy not valid", err)
+			return
+		}
+
+		if !IsValidMinGroupSize(policyModel.MinGroupSize) {
+			shared.RenderValidationProblemJSON(w, "compliance policy not valid", shared.NewInvalidParam("minGroupSize", "range", fmt.Sprintf("minimum group size must be between %v and %v", minMinGroupSize, maxMinGroupSize)))
+			return
+		}
+
+		policy, err := activityService.UpdateCompliancePolicy(r.Context(), principal, &CompliancePolicy{
+			WorksCouncilMode: policyModel.WorksCouncilMode,
+			MinGroupSize:     policyModel.MinGroupSize,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToCompliancePolicyModel(policy, r.RequestURI))
+	}
+}
+
+func mapToCompliancePolicyModel(policy *CompliancePolicy, selfLink string) *compliancePolicyModel {
+	return &compliancePolicyModel{
+		WorksCouncilMode: policy.WorksCouncilMode,
+		MinGroupSize:     policy.MinGroupSize,
+		Links: hal.NewLinks(
+			hal.NewSelfLink(selfLink),
+		),
+	}
+}
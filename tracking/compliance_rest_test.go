@@ -0,0 +1,73 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleCompliancePolicy(t *testing.T) {
+	is := is.New(t)
+
+	activityService := &ActitivityService{
+		repositoryTxer:             shared.NewInMemRepositoryTxer(),
+		activityRepository:         NewInMemActivityRepository(),
+		locationPolicyRepository:   NewInMemLocationPolicyRepository(),
+		compliancePolicyRepository: NewInMemCompliancePolicyRepository(),
+		breakPolicyRepository:      NewInMemBreakPolicyRepository(),
+	}
+	a := NewComplianceRestHandlers(&shared.Config{}, activityService)
+	reports := NewReportRestHandlers(&shared.Config{WorkingHoursPerWeek: 40}, activityService, NewInMemProjectRepository())
+
+	requestAs := func(r *http.Request, roles ...string) *http.Request {
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          roles,
+		}))
+	}
+
+	updatePolicy := func(body string, roles ...string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/api/compliance-policy", strings.NewReader(body))
+		a.HandleUpdateCompliancePolicy()(httpRec, requestAs(r, roles...))
+		return httpRec
+	}
+
+	utilizationReport := func(roles ...string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/reports/utilization?t=month&v=2021-10", nil)
+		reports.HandleUtilizationReport()(httpRec, requestAs(r, roles...))
+		return httpRec
+	}
+
+	httpRec := updatePolicy(`{"worksCouncilMode": true, "minGroupSize": 1}`, "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = updatePolicy(`{"worksCouncilMode": true}`, "ROLE_USER")
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	is.Equal(utilizationReport("ROLE_USER").Result().StatusCode, http.StatusOK)
+
+	httpRec = updatePolicy(`{"worksCouncilMode": true}`, "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/api/compliance-policy", nil)
+	a.HandleGetCompliancePolicy()(httpRec, requestAs(r, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	policyModel := &compliancePolicyModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(policyModel)
+	is.NoErr(err)
+	is.True(policyModel.WorksCouncilMode)
+	is.Equal(policyModel.MinGroupSize, defaultMinGroupSize)
+
+	is.Equal(utilizationReport("ROLE_USER").Result().StatusCode, http.StatusForbidden)
+	is.Equal(utilizationReport("ROLE_ADMIN").Result().StatusCode, http.StatusOK)
+}
@@ -0,0 +1,144 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// Widgets are the cards users can place on their dashboard
+const (
+	WidgetRunningTimer = "running-timer"
+	WidgetWeeklyTarget = "weekly-target"
+	WidgetBudgetAlerts = "budget-alerts"
+	WidgetTeamActivity = "team-activity"
+)
+
+// budgetAlertPercentage is the share of the budget tracked from which a project is shown in the budget alerts
+const budgetAlertPercentage = 80
+
+var (
+	ErrDashboardLayoutNotFound = shared.NewDomainError("dashboard:layout-not-found", http.StatusNotFound, "dashboard layout not found")
+	ErrDashboardWidgetNotFound = shared.NewDomainError("dashboard:widget-not-found", http.StatusNotFound, "dashboard widget not found")
+	ErrDashboardWidgetDenied   = shared.NewDomainError("dashboard:widget-denied", http.StatusForbidden, "dashboard widget not available for user")
+	ErrDashboardLayoutNotValid = shared.NewDomainError("dashboard:layout-not-valid", http.StatusBadRequest, "dashboard layout not valid")
+)
+
+// widgetRoles are the roles needed for a widget, widgets without an entry are available for all users
+var widgetRoles = map[string]string{
+	WidgetTeamActivity: "ROLE_ADMIN",
+}
+
+// widgets are the known widgets in the order of the default layout
+var widgets = []string{
+	WidgetRunningTimer,
+	WidgetWeeklyTarget,
+	WidgetBudgetAlerts,
+	WidgetTeamActivity,
+}
+
+// DashboardLayout are the widgets on the dashboard of a user in the order they appear
+type DashboardLayout struct {
+	OrganizationID uuid.UUID
+	Username       string
+	Widgets        []string
+	UpdatedAt      *time.Time
+}
+
+// WeeklyTargetProgress is the time tracked in a week against the weekly target
+type WeeklyTargetProgress struct {
+	WeekStart      time.Time
+	TrackedMinutes int
+	TargetMinutes  int
+}
+
+// BudgetAlert is an open project which used up most of its budget
+type BudgetAlert struct {
+	Project        *Project
+	TrackedMinutes int
+}
+
+// TeamActivityItem is the time a member of the team tracked in a week
+type TeamActivityItem struct {
+	Username       string
+	TrackedMinutes int
+}
+
+type DashboardRepository interface {
+	FindDashboardLayout(ctx context.Context, organizationID uuid.UUID, username string) (*DashboardLayout, error)
+	UpsertDashboardLayout(ctx context.Context, layout *DashboardLayout) error
+}
+
+// IsKnownWidget checks whether the widget is known
+func IsKnownWidget(widget string) bool {
+	for _, w := range widgets {
+		if w == widget {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWidgetAvailable checks whether the principal may place the widget on the dashboard
+func IsWidgetAvailable(principal *shared.Principal, widget string) bool {
+	role, ok := widgetRoles[widget]
+	return !ok || principal.HasRole(role)
+}
+
+// DefaultDashboardLayout is the layout of users without their own layout, with all widgets available for the principal
+func DefaultDashboardLayout(principal *shared.Principal) *DashboardLayout {
+	var availableWidgets []string
+	for _, widget := range widgets {
+		if IsWidgetAvailable(principal, widget) {
+			availableWidgets = append(availableWidgets, widget)
+		}
+	}
+
+	return &DashboardLayout{
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		Widgets:        availableWidgets,
+	}
+}
+
+// ValidateDashboardWidgets checks that the widgets are known, available for the principal and placed once
+func ValidateDashboardWidgets(principal *shared.Principal, layoutWidgets []string) error {
+	placed := make(map[string]bool, len(layoutWidgets))
+	for _, widget := range layoutWidgets {
+		if !IsKnownWidget(widget) {
+			return ErrDashboardWidgetNotFound
+		}
+		if !IsWidgetAvailable(principal, widget) {
+			return ErrDashboardWidgetDenied
+		}
+		if placed[widget] {
+			return ErrDashboardLayoutNotValid
+		}
+		placed[widget] = true
+	}
+	return nil
+}
+
+// Percentage is the share of the weekly target tracked
+func (p *WeeklyTargetProgress) Percentage() int {
+	if p.TargetMinutes <= 0 {
+		return 0
+	}
+	return p.TrackedMinutes * 100 / p.TargetMinutes
+}
+
+// Percentage is the share of the budget of the project tracked
+func (a *BudgetAlert) Percentage() int {
+	if !a.Project.HasBudget() {
+		return 0
+	}
+	return a.TrackedMinutes * 100 / a.Project.BudgetMinutes
+}
+
+// IsExceeded checks whether more time was tracked than budgeted
+func (a *BudgetAlert) IsExceeded() bool {
+	return a.TrackedMinutes > a.Project.BudgetMinutes
+}
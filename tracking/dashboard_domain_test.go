@@ -0,0 +1,55 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestDefaultDashboardLayout(t *testing.T) {
+	is := is.New(t)
+
+	layout := DefaultDashboardLayout(&shared.Principal{Roles: []string{"ROLE_USER"}})
+	is.Equal(layout.Widgets, []string{WidgetRunningTimer, WidgetWeeklyTarget, WidgetBudgetAlerts})
+
+	layout = DefaultDashboardLayout(&shared.Principal{Roles: []string{"ROLE_ADMIN"}})
+	is.Equal(layout.Widgets, []string{WidgetRunningTimer, WidgetWeeklyTarget, WidgetBudgetAlerts, WidgetTeamActivity})
+}
+
+func TestValidateDashboardWidgets(t *testing.T) {
+	is := is.New(t)
+
+	user := &shared.Principal{Roles: []string{"ROLE_USER"}}
+	admin := &shared.Principal{Roles: []string{"ROLE_ADMIN"}}
+
+	is.NoErr(ValidateDashboardWidgets(user, []string{WidgetWeeklyTarget, WidgetRunningTimer}))
+	is.NoErr(ValidateDashboardWidgets(user, []string{}))
+	is.NoErr(ValidateDashboardWidgets(admin, []string{WidgetTeamActivity}))
+
+	is.Equal(ValidateDashboardWidgets(user, []string{"weather"}), ErrDashboardWidgetNotFound)
+	is.Equal(ValidateDashboardWidgets(user, []string{WidgetTeamActivity}), ErrDashboardWidgetDenied)
+	is.Equal(ValidateDashboardWidgets(user, []string{WidgetWeeklyTarget, WidgetWeeklyTarget}), ErrDashboardLayoutNotValid)
+}
+
+func TestBudgetAlertPercentage(t *testing.T) {
+	is := is.New(t)
+
+	budgetAlert := &BudgetAlert{
+		Project:        &Project{BudgetMinutes: 600},
+		TrackedMinutes: 540,
+	}
+	is.Equal(budgetAlert.Percentage(), 90)
+	is.True(!budgetAlert.IsExceeded())
+
+	budgetAlert.TrackedMinutes = 660
+	is.Equal(budgetAlert.Percentage(), 110)
+	is.True(budgetAlert.IsExceeded())
+}
+
+func TestWeeklyTargetProgressPercentage(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal((&WeeklyTargetProgress{TrackedMinutes: 1200, TargetMinutes: 2400}).Percentage(), 50)
+	is.Equal((&WeeklyTargetProgress{TrackedMinutes: 1200}).Percentage(), 0)
+}
@@ -0,0 +1,87 @@
+package tracking
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbDashboardRepository is a SQL database repository for the dashboard layouts of users
+type DbDashboardRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ DashboardRepository = (*DbDashboardRepository)(nil)
+
+// NewDbDashboardRepository creates a new SQL database repository for the dashboard layouts of users
+func NewDbDashboardRepository(connPool *pgxpool.Pool) *DbDashboardRepository {
+	return &DbDashboardRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbDashboardRepository) FindDashboardLayout(ctx context.Context, organizationID uuid.UUID, username string) (*DashboardLayout, error) {
+	row, err := shared.SelectOne[dashboardLayoutRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[dashboardLayoutRow]()+`
+		 FROM dashboard_layouts
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDashboardLayoutNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toDashboardLayout(), nil
+}
+
+func (r *DbDashboardRepository) UpsertDashboardLayout(ctx context.Context, layout *DashboardLayout) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO dashboard_layouts
+		   (org_id, username, widgets, updated_at)
+		 VALUES
+		   ($1, $2, $3, $4)
+		 ON CONFLICT (org_id, username) DO UPDATE
+		 SET widgets = EXCLUDED.widgets, updated_at = EXCLUDED.updated_at`,
+		layout.OrganizationID,
+		layout.Username,
+		strings.Join(layout.Widgets, ","),
+		layout.UpdatedAt,
+	)
+	return err
+}
+
+type dashboardLayoutRow struct {
+	OrganizationID uuid.UUID  `db:"org_id"`
+	Username       string     `db:"username"`
+	Widgets        string     `db:"widgets"`
+	UpdatedAt      *time.Time `db:"updated_at"`
+}
+
+func (r *dashboardLayoutRow) toDashboardLayout() *DashboardLayout {
+	var widgets []string
+	if r.Widgets != "" {
+		widgets = strings.Split(r.Widgets, ",")
+	}
+
+	return &DashboardLayout{
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		Widgets:        widgets,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
@@ -0,0 +1,63 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestDashboardRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	dashboardRepository := NewDbDashboardRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("FindDashboardLayoutWithoutLayout", func(t *testing.T) {
+		_, err := dashboardRepository.FindDashboardLayout(context.Background(), shared.OrganizationIDSample, "user1")
+		is.Equal(err, ErrDashboardLayoutNotFound)
+	})
+
+	t.Run("UpsertDashboardLayout", func(t *testing.T) {
+		for _, widgets := range [][]string{{WidgetWeeklyTarget, WidgetRunningTimer}, {}} {
+			now := time.Now()
+			err := repositoryTxer.InTx(
+				context.Background(),
+				func(ctx context.Context) error {
+					return dashboardRepository.UpsertDashboardLayout(ctx, &DashboardLayout{
+						OrganizationID: shared.OrganizationIDSample,
+						Username:       "user1",
+						Widgets:        widgets,
+						UpdatedAt:      &now,
+					})
+				},
+			)
+			is.NoErr(err)
+
+			layout, err := dashboardRepository.FindDashboardLayout(context.Background(), shared.OrganizationIDSample, "user1")
+			is.NoErr(err)
+			is.Equal(len(layout.Widgets), len(widgets))
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemDashboardRepository struct {
+	mu      sync.Mutex
+	layouts []*DashboardLayout
+}
+
+var _ DashboardRepository = (*InMemDashboardRepository)(nil)
+
+func NewInMemDashboardRepository() *InMemDashboardRepository {
+	return &InMemDashboardRepository{}
+}
+
+func (r *InMemDashboardRepository) FindDashboardLayout(ctx context.Context, organizationID uuid.UUID, username string) (*DashboardLayout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, layout := range r.layouts {
+		if layout.OrganizationID == organizationID && layout.Username == username {
+			found := *layout
+			found.Widgets = append([]string(nil), layout.Widgets...)
+			return &found, nil
+		}
+	}
+	return nil, ErrDashboardLayoutNotFound
+}
+
+func (r *InMemDashboardRepository) UpsertDashboardLayout(ctx context.Context, layout *DashboardLayout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *layout
+	stored.Widgets = append([]string(nil), layout.Widgets...)
+	for i, l := range r.layouts {
+		if l.OrganizationID == layout.OrganizationID && l.Username == layout.Username {
+			r.layouts[i] = &stored
+			return nil
+		}
+	}
+
+	r.layouts = append(r.layouts, &stored)
+	return nil
+}
@@ -0,0 +1,103 @@
+package tracking
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type dashboardLayoutModel struct {
+	Widgets          []string   `json:"widgets" validate:"required"`
+	AvailableWidgets []string   `json:"availableWidgets,omitempty"`
+	UpdatedAt        string     `json:"updatedAt,omitempty"`
+	Links            *hal.Links `json:"_links,omitempty"`
+}
+
+type DashboardRestHandlers struct {
+	config           *shared.Config
+	dashboardService *DashboardService
+}
+
+func NewDashboardRestHandlers(config *shared.Config, dashboardService *DashboardService) *DashboardRestHandlers {
+	return &DashboardRestHandlers{
+		config:           config,
+		dashboardService: dashboardService,
+	}
+}
+
+func (a *DashboardRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/dashboard/layout", a.HandleGetDashboardLayout())
+	r.Put("/dashboard/layout", a.HandleUpdateDashboardLayout())
+}
+
+func (a *DashboardRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetDashboardLayout reads the widgets on the dashboard of the principal
+func (a *DashboardRestHandlers) HandleGetDashboardLayout() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	dashboardService := a.dashboardService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		layout, err := dashboardService.ReadDashboardLayout(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToDashboardLayoutModel(principal, layout))
+	}
+}
+
+// HandleUpdateDashboardLayout places the widgets on the dashboard of the principal in the given order
+func (a *DashboardRestHandlers) HandleUpdateDashboardLayout() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	dashboardService := a.dashboardService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var layoutModel dashboardLayoutModel
+		err := json.NewDecoder(r.Body).Decode(&layoutModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "dashboard layout not valid", err)
+			return
+		}
+
+		err = validator.Struct(layoutModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "dashboard layout not valid", err)
+			return
+		}
+
+		layout, err := dashboardService.UpdateDashboardLayout(r.Context(), principal, layoutModel.Widgets)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToDashboardLayoutModel(principal, layout))
+	}
+}
+
+func mapToDashboardLayoutModel(principal *shared.Principal, layout *DashboardLayout) *dashboardLayoutModel {
+	layoutModel := &dashboardLayoutModel{
+		Widgets:          layout.Widgets,
+		AvailableWidgets: DefaultDashboardLayout(principal).Widgets,
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/dashboard/layout"),
+		),
+	}
+	if layoutModel.Widgets == nil {
+		layoutModel.Widgets = []string{}
+	}
+	if layout.UpdatedAt != nil {
+		layoutModel.UpdatedAt = layout.UpdatedAt.Format(time.RFC3339)
+	}
+	return layoutModel
+}
@@ -0,0 +1,91 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleGetDashboardLayout(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewDashboardRestHandlers(
+		&shared.Config{},
+		newInMemDashboardService(NewInMemProjectRepository(), NewInMemSyncRepository()),
+	)
+
+	r, _ := http.NewRequest("GET", "/api/dashboard/layout", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}))
+
+	a.HandleGetDashboardLayout()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	layoutModel := &dashboardLayoutModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(layoutModel)
+	is.NoErr(err)
+	is.Equal(layoutModel.Widgets, []string{WidgetRunningTimer, WidgetWeeklyTarget, WidgetBudgetAlerts})
+	is.Equal(layoutModel.AvailableWidgets, layoutModel.Widgets)
+}
+
+func TestHandleUpdateDashboardLayout(t *testing.T) {
+	dashboardService := newInMemDashboardService(NewInMemProjectRepository(), NewInMemSyncRepository())
+	a := NewDashboardRestHandlers(&shared.Config{}, dashboardService)
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}
+
+	updateDashboardLayout := func(body string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+
+		r, _ := http.NewRequest("PUT", "/api/dashboard/layout", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+
+		a.HandleUpdateDashboardLayout()(httpRec, r)
+		return httpRec
+	}
+
+	t.Run("valid layout", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := updateDashboardLayout(`{"widgets": ["weekly-target", "running-timer"]}`)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		layout, err := dashboardService.ReadDashboardLayout(context.Background(), principal)
+		is.NoErr(err)
+		is.Equal(layout.Widgets, []string{WidgetWeeklyTarget, WidgetRunningTimer})
+	})
+
+	t.Run("missing widgets", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := updateDashboardLayout(`{}`)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("unknown widget", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := updateDashboardLayout(`{"widgets": ["weather"]}`)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+	})
+
+	t.Run("widget of admins", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := updateDashboardLayout(`{"widgets": ["team-activity"]}`)
+		is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	})
+}
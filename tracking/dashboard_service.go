@@ -0,0 +1,215 @@
+package tracking
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// DashboardService reads the dashboard layouts of users and the data of their widgets
+type DashboardService struct {
+	config              *shared.Config
+	repositoryTxer      shared.RepositoryTxer
+	dashboardRepository DashboardRepository
+	activityRepository  ActivityRepository
+	projectRepository   ProjectRepository
+	syncRepository      SyncRepository
+}
+
+// NewDashboardService creates a new service for dashboards
+func NewDashboardService(
+	config *shared.Config,
+	repositoryTxer shared.RepositoryTxer,
+	dashboardRepository DashboardRepository,
+	activityRepository ActivityRepository,
+	projectRepository ProjectRepository,
+	syncRepository SyncRepository,
+) *DashboardService {
+	return &DashboardService{
+		config:              config,
+		repositoryTxer:      repositoryTxer,
+		dashboardRepository: dashboardRepository,
+		activityRepository:  activityRepository,
+		projectRepository:   projectRepository,
+		syncRepository:      syncRepository,
+	}
+}
+
+// ReadDashboardLayout reads the dashboard layout of the principal, the default layout if the principal has none.
+// Widgets no longer available for the principal are left out.
+func (s *DashboardService) ReadDashboardLayout(ctx context.Context, principal *shared.Principal) (*DashboardLayout, error) {
+	layout, err := s.dashboardRepository.FindDashboardLayout(ctx, principal.OrganizationID, principal.Username)
+	if errors.Is(err, ErrDashboardLayoutNotFound) {
+		return DefaultDashboardLayout(principal), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var availableWidgets []string
+	for _, widget := range layout.Widgets {
+		if IsKnownWidget(widget) && IsWidgetAvailable(principal, widget) {
+			availableWidgets = append(availableWidgets, widget)
+		}
+	}
+	layout.Widgets = availableWidgets
+
+	return layout, nil
+}
+
+// UpdateDashboardLayout places the widgets on the dashboard of the principal in the given order
+func (s *DashboardService) UpdateDashboardLayout(ctx context.Context, principal *shared.Principal, widgets []string) (*DashboardLayout, error) {
+	err := ValidateDashboardWidgets(principal, widgets)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	layout := &DashboardLayout{
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		Widgets:        widgets,
+		UpdatedAt:      &now,
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.dashboardRepository.UpsertDashboardLayout(ctx, layout)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return layout, nil
+}
+
+// RunningTimer reads the timer of the principal with its project, the project is nil if the timer is not running
+func (s *DashboardService) RunningTimer(ctx context.Context, principal *shared.Principal) (*TimerState, *Project, error) {
+	timerState, err := s.syncRepository.FindTimerState(ctx, principal.OrganizationID, principal.Username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !timerState.IsRunning() {
+		return timerState, nil, nil
+	}
+
+	project, err := s.projectRepository.FindProjectByID(ctx, principal.OrganizationID, timerState.ProjectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return timerState, project, nil
+}
+
+// WeeklyTarget reports the time the principal tracked in the current week against the weekly target
+func (s *DashboardService) WeeklyTarget(ctx context.Context, principal *shared.Principal, now time.Time) (*WeeklyTargetProgress, error) {
+	weekStart := truncateToBucket(now, "week")
+	projectReports, err := s.activityRepository.ProjectReport(ctx, &ActivitiesFilter{
+		Start:          weekStart,
+		End:            weekStart.AddDate(0, 0, 7),
+		Username:       principal.Username,
+		OrganizationID: principal.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trackedMinutes := 0
+	for _, projectReport := range projectReports {
+		trackedMinutes += projectReport.DurationInMinutesTotal
+	}
+
+	return &WeeklyTargetProgress{
+		WeekStart:      weekStart,
+		TrackedMinutes: trackedMinutes,
+		TargetMinutes:  s.config.WorkingHoursPerWeek * 60,
+	}, nil
+}
+
+// BudgetAlerts lists the open projects of the organization which used up most of their budget, the most used up first
+func (s *DashboardService) BudgetAlerts(ctx context.Context, principal *shared.Principal, now time.Time) ([]*BudgetAlert, error) {
+	projectReports, err := s.activityRepository.ProjectReport(ctx, &ActivitiesFilter{
+		End:            now,
+		OrganizationID: principal.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trackedMinutesByProject := make(map[uuid.UUID]int, len(projectReports))
+	projectIDs := make([]uuid.UUID, 0, len(projectReports))
+	for _, projectReport := range projectReports {
+		if _, ok := trackedMinutesByProject[projectReport.ProjectID]; !ok {
+			projectIDs = append(projectIDs, projectReport.ProjectID)
+		}
+		trackedMinutesByProject[projectReport.ProjectID] += projectReport.DurationInMinutesTotal
+	}
+
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	projects, err := s.projectRepository.FindProjectsByIDs(ctx, principal.OrganizationID, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var budgetAlerts []*BudgetAlert
+	for _, project := range projects {
+		if !project.IsOpen() || !project.HasBudget() {
+			continue
+		}
+
+		budgetAlert := &BudgetAlert{
+			Project:        project,
+			TrackedMinutes: trackedMinutesByProject[project.ID],
+		}
+		if budgetAlert.Percentage() >= budgetAlertPercentage {
+			budgetAlerts = append(budgetAlerts, budgetAlert)
+		}
+	}
+
+	sort.SliceStable(budgetAlerts, func(i, j int) bool {
+		return budgetAlerts[i].Percentage() > budgetAlerts[j].Percentage()
+	})
+
+	return budgetAlerts, nil
+}
+
+// TeamActivity reports the time each member of the organization tracked in the current week, for admins only
+func (s *DashboardService) TeamActivity(ctx context.Context, principal *shared.Principal, now time.Time) ([]*TeamActivityItem, error) {
+	if !IsWidgetAvailable(principal, WidgetTeamActivity) {
+		return nil, ErrDashboardWidgetDenied
+	}
+
+	weekStart := truncateToBucket(now, "week")
+	reportItems, err := s.activityRepository.UtilizationReportByUser(ctx, &ActivitiesFilter{
+		Start:          weekStart,
+		End:            weekStart.AddDate(0, 0, 7),
+		OrganizationID: principal.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	teamActivity := make([]*TeamActivityItem, len(reportItems))
+	for i, reportItem := range reportItems {
+		teamActivity[i] = &TeamActivityItem{
+			Username:       reportItem.Username,
+			TrackedMinutes: reportItem.DurationInMinutesTotal,
+		}
+	}
+
+	sort.SliceStable(teamActivity, func(i, j int) bool {
+		return teamActivity[i].TrackedMinutes > teamActivity[j].TrackedMinutes
+	})
+
+	return teamActivity, nil
+}
@@ -0,0 +1,162 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newInMemDashboardService(projectRepository *InMemProjectRepository, syncRepository *InMemSyncRepository) *DashboardService {
+	return NewDashboardService(
+		&shared.Config{WorkingHoursPerWeek: 40},
+		shared.NewInMemRepositoryTxer(),
+		NewInMemDashboardRepository(),
+		NewInMemActivityRepository(),
+		projectRepository,
+		syncRepository,
+	)
+}
+
+func TestReadDashboardLayout(t *testing.T) {
+	is := is.New(t)
+
+	dashboardService := newInMemDashboardService(NewInMemProjectRepository(), NewInMemSyncRepository())
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}
+
+	layout, err := dashboardService.ReadDashboardLayout(context.Background(), principal)
+	is.NoErr(err)
+	is.Equal(layout.Widgets, DefaultDashboardLayout(principal).Widgets)
+
+	_, err = dashboardService.UpdateDashboardLayout(context.Background(), principal, []string{WidgetBudgetAlerts, WidgetRunningTimer})
+	is.NoErr(err)
+
+	layout, err = dashboardService.ReadDashboardLayout(context.Background(), principal)
+	is.NoErr(err)
+	is.Equal(layout.Widgets, []string{WidgetBudgetAlerts, WidgetRunningTimer})
+}
+
+func TestReadDashboardLayoutLeavesOutWidgetsNoLongerAvailable(t *testing.T) {
+	is := is.New(t)
+
+	dashboardService := newInMemDashboardService(NewInMemProjectRepository(), NewInMemSyncRepository())
+	admin := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+
+	_, err := dashboardService.UpdateDashboardLayout(context.Background(), admin, []string{WidgetTeamActivity, WidgetWeeklyTarget})
+	is.NoErr(err)
+
+	user := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}
+	layout, err := dashboardService.ReadDashboardLayout(context.Background(), user)
+	is.NoErr(err)
+	is.Equal(layout.Widgets, []string{WidgetWeeklyTarget})
+}
+
+func TestRunningTimer(t *testing.T) {
+	is := is.New(t)
+
+	syncRepository := NewInMemSyncRepository()
+	dashboardService := newInMemDashboardService(NewInMemProjectRepository(), syncRepository)
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}
+
+	timerState, project, err := dashboardService.RunningTimer(context.Background(), principal)
+	is.NoErr(err)
+	is.True(!timerState.IsRunning())
+	is.True(project == nil)
+
+	start := time.Now().Add(-30 * time.Minute)
+	err = syncRepository.UpdateTimerState(context.Background(), &TimerState{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		ProjectID:      shared.ProjectIDSample,
+		Start:          &start,
+		Version:        1,
+	}, 0)
+	is.NoErr(err)
+
+	timerState, project, err = dashboardService.RunningTimer(context.Background(), principal)
+	is.NoErr(err)
+	is.True(timerState.IsRunning())
+	is.Equal(project.ID, shared.ProjectIDSample)
+}
+
+func TestWeeklyTarget(t *testing.T) {
+	is := is.New(t)
+
+	dashboardService := newInMemDashboardService(NewInMemProjectRepository(), NewInMemSyncRepository())
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}
+
+	progress, err := dashboardService.WeeklyTarget(context.Background(), principal, time.Date(2021, 11, 12, 12, 0, 0, 0, time.UTC))
+	is.NoErr(err)
+	is.Equal(progress.WeekStart, time.Date(2021, 11, 8, 0, 0, 0, 0, time.UTC))
+	is.Equal(progress.TargetMinutes, 40*60)
+	is.Equal(progress.TrackedMinutes, 60)
+}
+
+func TestBudgetAlerts(t *testing.T) {
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	dashboardService := newInMemDashboardService(projectRepository, NewInMemSyncRepository())
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}
+
+	budgetAlerts, err := dashboardService.BudgetAlerts(context.Background(), principal, time.Now())
+	is.NoErr(err)
+	is.Equal(len(budgetAlerts), 0)
+
+	project, err := projectRepository.FindProjectByID(context.Background(), shared.OrganizationIDSample, shared.ProjectIDSample)
+	is.NoErr(err)
+	project.BudgetMinutes = 70
+	_, err = projectRepository.UpdateProject(context.Background(), shared.OrganizationIDSample, project)
+	is.NoErr(err)
+
+	budgetAlerts, err = dashboardService.BudgetAlerts(context.Background(), principal, time.Now())
+	is.NoErr(err)
+	is.Equal(len(budgetAlerts), 1)
+	is.Equal(budgetAlerts[0].Project.ID, shared.ProjectIDSample)
+	is.Equal(budgetAlerts[0].Percentage(), 85)
+}
+
+func TestTeamActivity(t *testing.T) {
+	is := is.New(t)
+
+	dashboardService := newInMemDashboardService(NewInMemProjectRepository(), NewInMemSyncRepository())
+
+	_, err := dashboardService.TeamActivity(context.Background(), &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}, time.Now())
+	is.Equal(err, ErrDashboardWidgetDenied)
+
+	teamActivity, err := dashboardService.TeamActivity(context.Background(), &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}, time.Now())
+	is.NoErr(err)
+	is.Equal(len(teamActivity), 1)
+	is.Equal(teamActivity[0].Username, "user1")
+}
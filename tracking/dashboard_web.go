@@ -0,0 +1,326 @@
+package tracking
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/csrf"
+	g "github.com/maragudk/gomponents"
+	ghx "github.com/maragudk/gomponents-htmx"
+	. "github.com/maragudk/gomponents/html"
+)
+
+// widgetTitles are the titles of the widget cards
+var widgetTitles = map[string]string{
+	WidgetRunningTimer: "Running Timer",
+	WidgetWeeklyTarget: "Weekly Target",
+	WidgetBudgetAlerts: "Budget Alerts",
+	WidgetTeamActivity: "Team Activity",
+}
+
+type DashboardWebHandlers struct {
+	config           *shared.Config
+	dashboardService *DashboardService
+}
+
+func NewDashboardWebHandlers(config *shared.Config, dashboardService *DashboardService) *DashboardWebHandlers {
+	return &DashboardWebHandlers{
+		config:           config,
+		dashboardService: dashboardService,
+	}
+}
+
+func (a *DashboardWebHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/dashboard", a.HandleDashboardPage())
+	r.Get("/dashboard/widgets/{widget}", a.HandleDashboardWidget())
+}
+
+func (a *DashboardWebHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleDashboardPage renders the dashboard with the widgets of the principal, each widget loads itself as fragment
+func (a *DashboardWebHandlers) HandleDashboardPage() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	dashboardService := a.dashboardService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		layout, err := dashboardService.ReadDashboardLayout(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemHTML(w, isProduction, err)
+			return
+		}
+
+		pageContext := &shared.PageContext{
+			Principal:   principal,
+			CurrentPath: r.URL.Path,
+			CSRFToken:   csrf.Token(r),
+			Appearance:  shared.AppearanceOf(r.Context()),
+			Title:       "Dashboard",
+		}
+
+		shared.RenderHTML(w, DashboardPage(pageContext, layout))
+	}
+}
+
+// HandleDashboardWidget renders a widget of the dashboard as fragment
+func (a *DashboardWebHandlers) HandleDashboardWidget() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	dashboardService := a.dashboardService
+	return func(w http.ResponseWriter, r *http.Request) {
+		widget := chi.URLParam(r, "widget")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		if !IsKnownWidget(widget) {
+			http.Error(w, ErrDashboardWidgetNotFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		if !IsWidgetAvailable(principal, widget) {
+			http.Error(w, ErrDashboardWidgetDenied.Error(), http.StatusForbidden)
+			return
+		}
+
+		now := time.Now()
+		var content g.Node
+		switch widget {
+		case WidgetRunningTimer:
+			timerState, project, err := dashboardService.RunningTimer(r.Context(), principal)
+			if err != nil {
+				shared.RenderProblemHTML(w, isProduction, err)
+				return
+			}
+			content = RunningTimerWidgetView(timerState, project, now)
+		case WidgetWeeklyTarget:
+			progress, err := dashboardService.WeeklyTarget(r.Context(), principal, now)
+			if err != nil {
+				shared.RenderProblemHTML(w, isProduction, err)
+				return
+			}
+			content = WeeklyTargetWidgetView(progress)
+		case WidgetBudgetAlerts:
+			budgetAlerts, err := dashboardService.BudgetAlerts(r.Context(), principal, now)
+			if err != nil {
+				shared.RenderProblemHTML(w, isProduction, err)
+				return
+			}
+			content = BudgetAlertsWidgetView(budgetAlerts)
+		case WidgetTeamActivity:
+			teamActivity, err := dashboardService.TeamActivity(r.Context(), principal, now)
+			if err != nil {
+				shared.RenderProblemHTML(w, isProduction, err)
+				return
+			}
+			content = TeamActivityWidgetView(teamActivity)
+		}
+
+		shared.RenderHTML(w, DashboardWidgetView(widget, content))
+	}
+}
+
+func DashboardPage(pageContext *shared.PageContext, layout *DashboardLayout) g.Node {
+	return shared.Page(
+		pageContext.Title,
+		pageContext.CurrentPath,
+		pageContext.Appearance,
+		[]g.Node{
+			shared.CSRFHeaders(pageContext.CSRFToken),
+			shared.Navbar(pageContext),
+			Div(
+				Class("container mt-4"),
+				g.If(len(layout.Widgets) == 0,
+					Div(
+						Class("alert alert-secondary text-center"),
+						g.Text("No widgets on the dashboard."),
+					),
+				),
+				Div(
+					Class("row row-cols-1 row-cols-md-2 g-4"),
+					g.Group(g.Map(layout.Widgets, func(widget string) g.Node {
+						return Div(
+							Class("col"),
+							DashboardWidgetPlaceholder(widget),
+						)
+					})),
+				),
+			),
+			shared.ModalView(),
+		},
+	)
+}
+
+// DashboardWidgetPlaceholder renders the card of a widget which loads its content once the page is loaded
+func DashboardWidgetPlaceholder(widget string) g.Node {
+	return Div(
+		ID(dashboardWidgetID(widget)),
+		Class("card h-100"),
+		ghx.Get(dashboardWidgetPath(widget)),
+		ghx.Trigger("load"),
+		ghx.Swap("outerHTML"),
+		Div(
+			Class("card-body"),
+			H6(
+				Class("card-subtitle mb-2 text-muted"),
+				g.Text(widgetTitles[widget]),
+			),
+			Div(
+				Class("spinner-border spinner-border-sm text-secondary"),
+				Role("status"),
+			),
+		),
+	)
+}
+
+// DashboardWidgetView renders the card of a widget which reloads when activities change, the running timer also every minute
+func DashboardWidgetView(widget string, content g.Node) g.Node {
+	trigger := "baralga__activities-changed from:body"
+	if widget == WidgetRunningTimer {
+		trigger = "every 60s, " + trigger
+	}
+
+	return Div(
+		ID(dashboardWidgetID(widget)),
+		Class("card h-100"),
+		ghx.Get(dashboardWidgetPath(widget)),
+		ghx.Trigger(trigger),
+		ghx.Swap("outerHTML"),
+		Div(
+			Class("card-body"),
+			H6(
+				Class("card-subtitle mb-2 text-muted"),
+				g.Text(widgetTitles[widget]),
+			),
+			content,
+		),
+	)
+}
+
+func RunningTimerWidgetView(timerState *TimerState, project *Project, now time.Time) g.Node {
+	if !timerState.IsRunning() {
+		return P(
+			Class("card-text text-muted"),
+			g.Text("No timer running."),
+		)
+	}
+
+	return g.Group([]g.Node{
+		H5(
+			Class("card-title"),
+			g.Text(project.Title),
+		),
+		g.If(timerState.Description != "",
+			Div(
+				Class("card-text"),
+				shared.Markdown(timerState.Description),
+			),
+		),
+		P(
+			Class("card-text"),
+			I(Class("bi-stopwatch me-2")),
+			g.Text(fmt.Sprintf(
+				"since %v (%v)",
+				time_utils.FormatTime(*timerState.Start),
+				time_utils.FormatMinutesAsDuration(now.Sub(*timerState.Start).Minutes()),
+			)),
+		),
+	})
+}
+
+func WeeklyTargetWidgetView(progress *WeeklyTargetProgress) g.Node {
+	if progress.TargetMinutes <= 0 {
+		return P(
+			Class("card-text text-muted"),
+			g.Text(fmt.Sprintf("%v tracked this week.", time_utils.FormatMinutesAsDuration(float64(progress.TrackedMinutes)))),
+		)
+	}
+
+	return g.Group([]g.Node{
+		P(
+			Class("card-text"),
+			g.Text(fmt.Sprintf(
+				"%v of %v tracked this week",
+				time_utils.FormatMinutesAsDuration(float64(progress.TrackedMinutes)),
+				time_utils.FormatMinutesAsDuration(float64(progress.TargetMinutes)),
+			)),
+		),
+		Div(
+			Class("progress"),
+			Role("progressbar"),
+			g.Attr("aria-valuenow", fmt.Sprint(progress.Percentage())),
+			g.Attr("aria-valuemin", "0"),
+			g.Attr("aria-valuemax", "100"),
+			Div(
+				Class("progress-bar"),
+				StyleAttr(fmt.Sprintf("width: %v%%", min(progress.Percentage(), 100))),
+				g.Text(fmt.Sprintf("%v%%", progress.Percentage())),
+			),
+		),
+	})
+}
+
+func BudgetAlertsWidgetView(budgetAlerts []*BudgetAlert) g.Node {
+	if len(budgetAlerts) == 0 {
+		return P(
+			Class("card-text text-muted"),
+			g.Text("All projects are within budget."),
+		)
+	}
+
+	return Ul(
+		Class("list-group list-group-flush"),
+		g.Group(g.Map(budgetAlerts, func(budgetAlert *BudgetAlert) g.Node {
+			return Li(
+				Class("list-group-item d-flex justify-content-between px-0"),
+				Span(
+					g.Text(budgetAlert.Project.Title),
+				),
+				Span(
+					g.If(budgetAlert.IsExceeded(), Class("badge bg-danger")),
+					g.If(!budgetAlert.IsExceeded(), Class("badge bg-warning text-dark")),
+					TitleAttr(fmt.Sprintf(
+						"%v of %v",
+						time_utils.FormatMinutesAsDuration(float64(budgetAlert.TrackedMinutes)),
+						time_utils.FormatMinutesAsDuration(float64(budgetAlert.Project.BudgetMinutes)),
+					)),
+					g.Text(fmt.Sprintf("%v%%", budgetAlert.Percentage())),
+				),
+			)
+		})),
+	)
+}
+
+func TeamActivityWidgetView(teamActivity []*TeamActivityItem) g.Node {
+	if len(teamActivity) == 0 {
+		return P(
+			Class("card-text text-muted"),
+			g.Text("No time tracked this week."),
+		)
+	}
+
+	return Ul(
+		Class("list-group list-group-flush"),
+		g.Group(g.Map(teamActivity, func(item *TeamActivityItem) g.Node {
+			return Li(
+				Class("list-group-item d-flex justify-content-between px-0"),
+				Span(
+					g.Text(item.Username),
+				),
+				Span(
+					g.Text(time_utils.FormatMinutesAsDuration(float64(item.TrackedMinutes))),
+				),
+			)
+		})),
+	)
+}
+
+func dashboardWidgetID(widget string) string {
+	return fmt.Sprintf("baralga__dashboard_widget_%v", widget)
+}
+
+func dashboardWidgetPath(widget string) string {
+	return fmt.Sprintf("/dashboard/widgets/%v", widget)
+}
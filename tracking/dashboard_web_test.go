@@ -0,0 +1,111 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleDashboardPage(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewDashboardWebHandlers(
+		&shared.Config{},
+		newInMemDashboardService(NewInMemProjectRepository(), NewInMemSyncRepository()),
+	)
+
+	r, _ := http.NewRequest("GET", "/dashboard", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}))
+
+	a.HandleDashboardPage()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	htmlBody := httpRec.Body.String()
+	is.True(strings.Contains(htmlBody, "Dashboard # Baralga"))
+	is.True(strings.Contains(htmlBody, "/dashboard/widgets/running-timer"))
+	is.True(strings.Contains(htmlBody, "/dashboard/widgets/weekly-target"))
+	is.True(!strings.Contains(htmlBody, "/dashboard/widgets/team-activity"))
+}
+
+func TestHandleDashboardWidget(t *testing.T) {
+	a := NewDashboardWebHandlers(
+		&shared.Config{WorkingHoursPerWeek: 40},
+		newInMemDashboardService(NewInMemProjectRepository(), NewInMemSyncRepository()),
+	)
+
+	renderWidget := func(widget string, roles ...string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+
+		r, _ := http.NewRequest("GET", "/dashboard/widgets/"+widget, nil)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Roles:          roles,
+		}))
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("widget", widget)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		a.HandleDashboardWidget()(httpRec, r)
+		return httpRec
+	}
+
+	t.Run("running timer", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := renderWidget(WidgetRunningTimer, "ROLE_USER")
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.True(strings.Contains(httpRec.Body.String(), "No timer running."))
+		is.True(strings.Contains(httpRec.Body.String(), "every 60s"))
+	})
+
+	t.Run("weekly target", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := renderWidget(WidgetWeeklyTarget, "ROLE_USER")
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.True(strings.Contains(httpRec.Body.String(), "of 40:00 h tracked this week"))
+	})
+
+	t.Run("budget alerts", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := renderWidget(WidgetBudgetAlerts, "ROLE_USER")
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.True(strings.Contains(httpRec.Body.String(), "All projects are within budget."))
+	})
+
+	t.Run("team activity", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := renderWidget(WidgetTeamActivity, "ROLE_ADMIN")
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.True(strings.Contains(httpRec.Body.String(), "user1"))
+	})
+
+	t.Run("team activity for users", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := renderWidget(WidgetTeamActivity, "ROLE_USER")
+		is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	})
+
+	t.Run("unknown widget", func(t *testing.T) {
+		is := is.New(t)
+
+		httpRec := renderWidget("weather", "ROLE_USER")
+		is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+	})
+}
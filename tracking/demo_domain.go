@@ -0,0 +1,141 @@
+package tracking
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// demoDayOffChance is the chance a user takes a working day off in the demo data
+	demoDayOffChance = 0.05
+
+	// demoDayStartHour and demoDayEndHour frame the working day of the demo data
+	demoDayStartHour = 8
+	demoDayEndHour   = 19
+)
+
+type demoProject struct {
+	title         string
+	description   string
+	billable      bool
+	budgetMinutes int
+	weight        int
+	activities    []string
+}
+
+// demoProjects are the projects of the demo data with the descriptions of their activities,
+// projects with a higher weight get more activities
+var demoProjects = []*demoProject{
+	{
+		title:         "Website Relaunch",
+		description:   "Relaunch of the corporate website with the new design",
+		billable:      true,
+		budgetMinutes: 600 * 60,
+		weight:        5,
+		activities:    []string{"Implement landing page", "Review design drafts", "Fix layout on mobile", "Sprint planning", "Deploy to staging", "Content migration"},
+	},
+	{
+		title:         "Mobile App",
+		description:   "Native app for iOS and Android",
+		billable:      true,
+		budgetMinutes: 900 * 60,
+		weight:        4,
+		activities:    []string{"Implement login screen", "Push notifications", "Code review", "Bug fixing", "Release build", "Daily standup"},
+	},
+	{
+		title:       "Customer Support",
+		description: "Support for customers with a maintenance contract",
+		billable:    true,
+		weight:      3,
+		activities:  []string{"Answer support tickets", "Analyze error report", "Call with customer", "Update documentation"},
+	},
+	{
+		title:       "Internal",
+		description: "Meetings, trainings and administration",
+		weight:      2,
+		activities:  []string{"Team meeting", "Training", "Recruiting interview", "Expense report", "1:1 meeting"},
+	},
+}
+
+// DemoData are projects and activities of an organization generated for demos, screenshots and load tests
+type DemoData struct {
+	Projects   []*Project
+	Activities []*Activity
+}
+
+// GenerateDemoData generates projects and the activities of the users on the working days of the months before
+// the day until. The same seed, users and day generate the same data.
+func GenerateDemoData(organizationID uuid.UUID, usernames []string, seed int64, months int, until time.Time) *DemoData {
+	random := rand.New(rand.NewSource(seed))
+
+	until = time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, until.Location())
+	from := until.AddDate(0, -months, 0)
+
+	demoData := &DemoData{}
+	var weightedProjects []*Project
+	var projectActivities = make(map[uuid.UUID][]string)
+	for _, p := range demoProjects {
+		project := &Project{
+			ID:             newDemoID(random),
+			Title:          p.title,
+			Description:    p.description,
+			Status:         ProjectStatusActive,
+			Billable:       p.billable,
+			BudgetMinutes:  p.budgetMinutes,
+			OrganizationID: organizationID,
+		}
+		project.stampStatus(from)
+
+		demoData.Projects = append(demoData.Projects, project)
+		projectActivities[project.ID] = p.activities
+		for i := 0; i < p.weight; i++ {
+			weightedProjects = append(weightedProjects, project)
+		}
+	}
+
+	for day := from; day.Before(until); day = day.AddDate(0, 0, 1) {
+		if isWeekend(day) {
+			continue
+		}
+
+		for _, username := range usernames {
+			if random.Float64() < demoDayOffChance {
+				continue
+			}
+
+			dayEnd := day.Add(demoDayEndHour * time.Hour)
+			start := day.Add(demoDayStartHour*time.Hour + time.Duration(random.Intn(8))*15*time.Minute)
+			for blocks := 2 + random.Intn(3); blocks > 0; blocks-- {
+				end := start.Add(time.Duration(2+random.Intn(11)) * 15 * time.Minute)
+				if end.After(dayEnd) {
+					break
+				}
+
+				project := weightedProjects[random.Intn(len(weightedProjects))]
+				descriptions := projectActivities[project.ID]
+				demoData.Activities = append(demoData.Activities, &Activity{
+					ID:             newDemoID(random),
+					Start:          start,
+					End:            end,
+					Description:    descriptions[random.Intn(len(descriptions))],
+					ProjectID:      project.ID,
+					OrganizationID: organizationID,
+					Username:       username,
+				})
+
+				// breaks of up to an hour between the activities
+				start = end.Add(time.Duration(random.Intn(5)) * 15 * time.Minute)
+			}
+		}
+	}
+
+	return demoData
+}
+
+// newDemoID generates an id from the random source, so the ids of the demo data are deterministic as well
+func newDemoID(random *rand.Rand) uuid.UUID {
+	id, _ := uuid.NewRandomFromReader(random)
+	return id
+}
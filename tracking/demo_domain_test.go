@@ -0,0 +1,42 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestGenerateDemoData(t *testing.T) {
+	is := is.New(t)
+
+	until := time.Date(2021, 11, 5, 14, 30, 0, 0, time.UTC)
+	usernames := []string{"demo1", "demo2"}
+	demoData := GenerateDemoData(shared.OrganizationIDSample, usernames, 42, 2, until)
+
+	is.Equal(len(demoData.Projects), len(demoProjects))
+	is.True(len(demoData.Activities) > 2*2*30)
+
+	projectIDs := make(map[string]bool)
+	for _, project := range demoData.Projects {
+		projectIDs[project.ID.String()] = true
+	}
+
+	for _, activity := range demoData.Activities {
+		is.True(projectIDs[activity.ProjectID.String()])
+		is.True(!isWeekend(activity.Start))
+		is.True(activity.Start.Before(activity.End))
+		is.True(activity.Start.Hour() >= demoDayStartHour)
+		is.True(!activity.End.After(time.Date(activity.Start.Year(), activity.Start.Month(), activity.Start.Day(), demoDayEndHour, 0, 0, 0, time.UTC)))
+		is.True(activity.Start.After(until.AddDate(0, -2, -1)))
+		is.True(activity.End.Before(until))
+	}
+
+	other := GenerateDemoData(shared.OrganizationIDSample, usernames, 42, 2, until)
+	is.Equal(len(other.Activities), len(demoData.Activities))
+	is.Equal(other.Projects[0].ID, demoData.Projects[0].ID)
+	is.Equal(*other.Activities[len(other.Activities)-1], *demoData.Activities[len(demoData.Activities)-1])
+
+	is.True(GenerateDemoData(shared.OrganizationIDSample, usernames, 7, 2, until).Projects[0].ID != demoData.Projects[0].ID)
+}
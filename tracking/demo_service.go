@@ -0,0 +1,40 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DemoService seeds organizations with generated projects and activities for demos, screenshots and load tests
+type DemoService struct {
+	projectRepository  ProjectRepository
+	activityRepository ActivityRepository
+}
+
+// NewDemoService creates a new service to seed organizations with demo data
+func NewDemoService(projectRepository ProjectRepository, activityRepository ActivityRepository) *DemoService {
+	return &DemoService{
+		projectRepository:  projectRepository,
+		activityRepository: activityRepository,
+	}
+}
+
+// OrganizationSeeder seeds a new organization with the demo data of the users within the transaction of its set up,
+// the same seed generates the same data
+func (s *DemoService) OrganizationSeeder(usernames []string, seed int64, months int, until time.Time) func(ctxWithTx context.Context, organizationID uuid.UUID) error {
+	return func(ctxWithTx context.Context, organizationID uuid.UUID) error {
+		demoData := GenerateDemoData(organizationID, usernames, seed, months, until)
+
+		for _, project := range demoData.Projects {
+			_, err := s.projectRepository.InsertProject(ctxWithTx, project)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := s.activityRepository.InsertActivities(ctxWithTx, demoData.Activities)
+		return err
+	}
+}
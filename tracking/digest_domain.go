@@ -0,0 +1,64 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+)
+
+const digestTopProjects = 3
+
+var ErrDigestLinkInvalid = shared.NewDomainError("digest:link-invalid", http.StatusForbidden, "unsubscribe link invalid")
+
+// DigestRecipient is a user receiving the weekly digest
+type DigestRecipient struct {
+	OrganizationID uuid.UUID
+	Username       string
+	Name           string
+	EMail          string
+}
+
+// Digest summarizes the tracked time of a user in a week
+type Digest struct {
+	Recipient       *DigestRecipient
+	WeekStart       time.Time
+	TrackedMinutes  int
+	TargetMinutes   int
+	TopProjects     []*ActivityProjectReportItem
+	UnsubscribeLink string
+	ActivitiesLink  string
+}
+
+type DigestRepository interface {
+	FindDigestRecipients(ctx context.Context, weekStart time.Time) ([]*DigestRecipient, error)
+	FindDigestOptOut(ctx context.Context, organizationID uuid.UUID, username string) (bool, error)
+	UpdateDigestOptOut(ctx context.Context, organizationID uuid.UUID, username string, optOut bool) error
+	MarkDigestSent(ctx context.Context, organizationID uuid.UUID, username string, weekStart time.Time) error
+}
+
+// WeekEnd is the last day of the week of the digest
+func (d *Digest) WeekEnd() time.Time {
+	return d.WeekStart.AddDate(0, 0, 6)
+}
+
+// TrackedFormatted is the tracked time as formatted string (e.g. 38:15 h)
+func (d *Digest) TrackedFormatted() string {
+	return time_utils.FormatMinutesAsDuration(float64(d.TrackedMinutes))
+}
+
+// TargetFormatted is the target time as formatted string (e.g. 40:00 h)
+func (d *Digest) TargetFormatted() string {
+	return time_utils.FormatMinutesAsDuration(float64(d.TargetMinutes))
+}
+
+// TargetPercentage is the tracked time in percent of the target, 0 without a target
+func (d *Digest) TargetPercentage() int {
+	if d.TargetMinutes == 0 {
+		return 0
+	}
+	return d.TrackedMinutes * 100 / d.TargetMinutes
+}
@@ -0,0 +1,121 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbDigestRepository is a SQL database repository for weekly digests
+type DbDigestRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ DigestRepository = (*DbDigestRepository)(nil)
+
+// NewDbDigestRepository creates a new SQL database repository for weekly digests
+func NewDbDigestRepository(connPool *pgxpool.Pool) *DbDigestRepository {
+	return &DbDigestRepository{
+		connPool: connPool,
+	}
+}
+
+// FindDigestRecipients reads the enabled users with email that did not opt out and did not yet receive the digest of the week
+func (r *DbDigestRepository) FindDigestRecipients(ctx context.Context, weekStart time.Time) ([]*DigestRecipient, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT u.org_id, u.username, u.name, u.email 
+		 FROM users u 
+		 LEFT JOIN digest_settings d 
+		 ON d.org_id = u.org_id AND d.username = u.username 
+		 WHERE u.enabled = 1 AND u.email IS NOT NULL AND u.email <> '' 
+		   AND (d.username IS NULL OR (NOT d.opted_out AND (d.last_sent_week IS NULL OR d.last_sent_week < $1))) 
+		 ORDER BY u.org_id, u.username`,
+		weekStart,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []*DigestRecipient
+	for rows.Next() {
+		var name pgtype.Varchar
+		recipient := &DigestRecipient{}
+		err := rows.Scan(&recipient.OrganizationID, &recipient.Username, &name, &recipient.EMail)
+		if err != nil {
+			return nil, err
+		}
+		recipient.Name = name.String
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, rows.Err()
+}
+
+// FindDigestOptOut reads whether the user opted out of the weekly digest
+func (r *DbDigestRepository) FindDigestOptOut(ctx context.Context, organizationID uuid.UUID, username string) (bool, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT opted_out 
+		 FROM digest_settings 
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID,
+		username,
+	)
+
+	var optOut bool
+	err := row.Scan(&optOut)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return optOut, nil
+}
+
+// UpdateDigestOptOut sets whether the user opted out of the weekly digest
+func (r *DbDigestRepository) UpdateDigestOptOut(ctx context.Context, organizationID uuid.UUID, username string, optOut bool) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO digest_settings 
+		   (org_id, username, opted_out) 
+		 VALUES 
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id, username) DO UPDATE 
+		 SET opted_out = EXCLUDED.opted_out`,
+		organizationID,
+		username,
+		optOut,
+	)
+	return err
+}
+
+// MarkDigestSent marks the digest of the week as sent to the user
+func (r *DbDigestRepository) MarkDigestSent(ctx context.Context, organizationID uuid.UUID, username string, weekStart time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO digest_settings 
+		   (org_id, username, last_sent_week) 
+		 VALUES 
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id, username) DO UPDATE 
+		 SET last_sent_week = EXCLUDED.last_sent_week`,
+		organizationID,
+		username,
+		weekStart,
+	)
+	return err
+}
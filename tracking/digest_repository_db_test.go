@@ -0,0 +1,78 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestDigestRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	digestRepository := NewDbDigestRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+	weekStart := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	recipients, err := digestRepository.FindDigestRecipients(context.Background(), weekStart)
+	is.NoErr(err)
+	is.True(len(recipients) > 1)
+	recipientCount := len(recipients)
+
+	t.Run("MarkDigestSent", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return digestRepository.MarkDigestSent(ctx, recipients[0].OrganizationID, recipients[0].Username, weekStart)
+			},
+		)
+		is.NoErr(err)
+
+		recipientsOfWeek, err := digestRepository.FindDigestRecipients(context.Background(), weekStart)
+		is.NoErr(err)
+		is.Equal(len(recipientsOfWeek), recipientCount-1)
+
+		recipientsOfNextWeek, err := digestRepository.FindDigestRecipients(context.Background(), weekStart.AddDate(0, 0, 7))
+		is.NoErr(err)
+		is.Equal(len(recipientsOfNextWeek), recipientCount)
+	})
+
+	t.Run("UpdateDigestOptOut", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return digestRepository.UpdateDigestOptOut(ctx, recipients[1].OrganizationID, recipients[1].Username, true)
+			},
+		)
+		is.NoErr(err)
+
+		optOut, err := digestRepository.FindDigestOptOut(context.Background(), recipients[1].OrganizationID, recipients[1].Username)
+		is.NoErr(err)
+		is.True(optOut)
+
+		recipientsOfNextWeek, err := digestRepository.FindDigestRecipients(context.Background(), weekStart.AddDate(0, 0, 7))
+		is.NoErr(err)
+		is.Equal(len(recipientsOfNextWeek), recipientCount-1)
+	})
+}
@@ -0,0 +1,86 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+type inMemDigestSettings struct {
+	optOut       bool
+	lastSentWeek *time.Time
+}
+
+type InMemDigestRepository struct {
+	mu         sync.Mutex
+	recipients []*DigestRecipient
+	settings   map[string]*inMemDigestSettings
+}
+
+var _ DigestRepository = (*InMemDigestRepository)(nil)
+
+func NewInMemDigestRepository() *InMemDigestRepository {
+	return &InMemDigestRepository{
+		recipients: []*DigestRecipient{
+			{
+				OrganizationID: shared.OrganizationIDSample,
+				Username:       "user1",
+				Name:           "Ulani User",
+				EMail:          "user1@baralga.com",
+			},
+		},
+		settings: make(map[string]*inMemDigestSettings),
+	}
+}
+
+func (r *InMemDigestRepository) FindDigestRecipients(ctx context.Context, weekStart time.Time) ([]*DigestRecipient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var recipients []*DigestRecipient
+	for _, recipient := range r.recipients {
+		settings := r.settingsOf(recipient.OrganizationID, recipient.Username)
+		if settings.optOut || (settings.lastSentWeek != nil && !settings.lastSentWeek.Before(weekStart)) {
+			continue
+		}
+		found := *recipient
+		recipients = append(recipients, &found)
+	}
+	return recipients, nil
+}
+
+func (r *InMemDigestRepository) FindDigestOptOut(ctx context.Context, organizationID uuid.UUID, username string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.settingsOf(organizationID, username).optOut, nil
+}
+
+func (r *InMemDigestRepository) UpdateDigestOptOut(ctx context.Context, organizationID uuid.UUID, username string, optOut bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.settingsOf(organizationID, username).optOut = optOut
+	return nil
+}
+
+func (r *InMemDigestRepository) MarkDigestSent(ctx context.Context, organizationID uuid.UUID, username string, weekStart time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.settingsOf(organizationID, username).lastSentWeek = &weekStart
+	return nil
+}
+
+func (r *InMemDigestRepository) settingsOf(organizationID uuid.UUID, username string) *inMemDigestSettings {
+	key := organizationID.String() + "|" + username
+	settings, ok := r.settings[key]
+	if !ok {
+		settings = &inMemDigestSettings{}
+		r.settings[key] = settings
+	}
+	return settings
+}
@@ -0,0 +1,106 @@
+package tracking
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type digestSettingsModel struct {
+	OptOut *bool      `json:"optOut"`
+	Links  *hal.Links `json:"_links,omitempty"`
+}
+
+type DigestRestHandlers struct {
+	config        *shared.Config
+	digestService *DigestService
+}
+
+func NewDigestRestHandlers(config *shared.Config, digestService *DigestService) *DigestRestHandlers {
+	return &DigestRestHandlers{
+		config:        config,
+		digestService: digestService,
+	}
+}
+
+func (a *DigestRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/digest", a.HandleGetDigestSettings())
+	r.Put("/digest", a.HandleUpdateDigestSettings())
+}
+
+func (a *DigestRestHandlers) RegisterOpen(r chi.Router) {
+	r.Get("/digest/unsubscribe", a.HandleUnsubscribeDigest())
+}
+
+// HandleGetDigestSettings reads whether the principal receives the weekly digest
+func (a *DigestRestHandlers) HandleGetDigestSettings() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	digestService := a.digestService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		optOut, err := digestService.ReadDigestOptOut(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, &digestSettingsModel{
+			OptOut: &optOut,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleUpdateDigestSettings opts the principal in or out of the weekly digest
+func (a *DigestRestHandlers) HandleUpdateDigestSettings() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	digestService := a.digestService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var digestSettingsModel digestSettingsModel
+		err := json.NewDecoder(r.Body).Decode(&digestSettingsModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "digest settings not valid", err)
+			return
+		}
+
+		if digestSettingsModel.OptOut == nil {
+			shared.RenderValidationProblemJSON(w, "digest settings not valid", shared.NewInvalidParam("optOut", "required", "optOut is required"))
+			return
+		}
+
+		err = digestService.UpdateDigestOptOut(r.Context(), principal, *digestSettingsModel.OptOut)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		digestSettingsModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+		)
+		shared.RenderJSON(w, digestSettingsModel)
+	}
+}
+
+// HandleUnsubscribeDigest opts the user of a signed link from the digest out of the weekly digest
+func (a *DigestRestHandlers) HandleUnsubscribeDigest() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	digestService := a.digestService
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := digestService.UnsubscribeByLink(r.Context(), r.URL.Query())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("You unsubscribed from the weekly digest."))
+	}
+}
@@ -0,0 +1,54 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleUpdateDigestSettings(t *testing.T) {
+	is := is.New(t)
+
+	a := NewDigestRestHandlers(&shared.Config{}, newInMemDigestService(shared.NewInMemMailResource()))
+	principal := &shared.Principal{Username: "user1", OrganizationID: shared.OrganizationIDSample}
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("PUT", "/api/digest", strings.NewReader(`{"optOut": true}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+	a.HandleUpdateDigestSettings()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("GET", "/api/digest", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+	a.HandleGetDigestSettings()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	digestSettingsModel := &digestSettingsModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(digestSettingsModel)
+	is.NoErr(err)
+	is.True(*digestSettingsModel.OptOut)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("PUT", "/api/digest", strings.NewReader(`{}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+	a.HandleUpdateDigestSettings()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleUnsubscribeDigestWithInvalidLink(t *testing.T) {
+	is := is.New(t)
+
+	a := NewDigestRestHandlers(&shared.Config{}, newInMemDigestService(shared.NewInMemMailResource()))
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/api/digest/unsubscribe?org="+shared.OrganizationIDSample.String()+"&user=user1&signature=00", nil)
+	a.HandleUnsubscribeDigest()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
@@ -0,0 +1,205 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const weeklyDigestJobType = "weekly-digest"
+
+var weeklyDigestTemplate = template.Must(template.New("weekly-digest").Parse(
+	`Hello {{ .Recipient.Name }},
+
+here is your summary of the week from {{ .WeekStart.Format "2006-01-02" }} to {{ .WeekEnd.Format "2006-01-02" }}.
+
+Tracked: {{ .TrackedFormatted }}{{ if .TargetMinutes }} of {{ .TargetFormatted }} ({{ .TargetPercentage }}%){{ end }}
+{{ if .TopProjects }}
+Top projects:
+{{ range .TopProjects }}- {{ .ProjectTitle }}: {{ .DurationFormatted }}
+{{ end }}{{ else }}
+You did not track any activities.
+{{ end }}
+See your activities at {{ .ActivitiesLink }}
+
+You receive this digest every week. Unsubscribe at {{ .UnsubscribeLink }}
+`))
+
+// DigestService sends a weekly digest of the tracked time to every user
+type DigestService struct {
+	config             *shared.Config
+	repositoryTxer     shared.RepositoryTxer
+	outbox             shared.Outbox
+	digestRepository   DigestRepository
+	activityRepository ActivityRepository
+}
+
+// NewDigestService creates a new service for weekly digests, sending them in the background if enabled
+func NewDigestService(
+	config *shared.Config,
+	repositoryTxer shared.RepositoryTxer,
+	outbox shared.Outbox,
+	jobService *shared.JobService,
+	digestRepository DigestRepository,
+	activityRepository ActivityRepository,
+) *DigestService {
+	s := &DigestService{
+		config:             config,
+		repositoryTxer:     repositoryTxer,
+		outbox:             outbox,
+		digestRepository:   digestRepository,
+		activityRepository: activityRepository,
+	}
+
+	if config.WeeklyDigest {
+		jobService.RegisterHandler(weeklyDigestJobType, s.handleWeeklyDigestJob)
+		jobService.Schedule(weeklyDigestJobType, time.Hour)
+	}
+
+	return s
+}
+
+// ReadDigestOptOut reads whether the principal opted out of the weekly digest
+func (s *DigestService) ReadDigestOptOut(ctx context.Context, principal *shared.Principal) (bool, error) {
+	return s.digestRepository.FindDigestOptOut(ctx, principal.OrganizationID, principal.Username)
+}
+
+// UpdateDigestOptOut sets whether the principal opted out of the weekly digest
+func (s *DigestService) UpdateDigestOptOut(ctx context.Context, principal *shared.Principal, optOut bool) error {
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.digestRepository.UpdateDigestOptOut(ctx, principal.OrganizationID, principal.Username, optOut)
+		},
+	)
+}
+
+// UnsubscribeByLink opts the user of a signed unsubscribe link out of the weekly digest
+func (s *DigestService) UnsubscribeByLink(ctx context.Context, query url.Values) error {
+	organizationID, err := uuid.Parse(query.Get("org"))
+	if err != nil {
+		return ErrDigestLinkInvalid
+	}
+
+	username := query.Get("user")
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil || username == "" || !hmac.Equal(signature, s.sign(organizationID, username)) {
+		return ErrDigestLinkInvalid
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.digestRepository.UpdateDigestOptOut(ctx, organizationID, username, true)
+		},
+	)
+}
+
+// UnsubscribeLink is the signed link to opt out of the weekly digest without login
+func (s *DigestService) UnsubscribeLink(recipient *DigestRecipient) string {
+	query := url.Values{}
+	query.Set("org", recipient.OrganizationID.String())
+	query.Set("user", recipient.Username)
+	query.Set("signature", hex.EncodeToString(s.sign(recipient.OrganizationID, recipient.Username)))
+
+	return fmt.Sprintf("%s/api/digest/unsubscribe?%s", s.config.Webroot, query.Encode())
+}
+
+// BuildDigest summarizes the tracked time of the recipient in the week
+func (s *DigestService) BuildDigest(ctx context.Context, recipient *DigestRecipient, weekStart time.Time) (*Digest, error) {
+	projectReports, err := s.activityRepository.ProjectReport(ctx, &ActivitiesFilter{
+		Start:          weekStart,
+		End:            weekStart.AddDate(0, 0, 7),
+		Username:       recipient.Username,
+		OrganizationID: recipient.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trackedMinutes := 0
+	for _, projectReport := range projectReports {
+		trackedMinutes += projectReport.DurationInMinutesTotal
+	}
+
+	sort.SliceStable(projectReports, func(i, j int) bool {
+		return projectReports[i].DurationInMinutesTotal > projectReports[j].DurationInMinutesTotal
+	})
+	if len(projectReports) > digestTopProjects {
+		projectReports = projectReports[:digestTopProjects]
+	}
+
+	year, week := weekStart.ISOWeek()
+	return &Digest{
+		Recipient:       recipient,
+		WeekStart:       weekStart,
+		TrackedMinutes:  trackedMinutes,
+		TargetMinutes:   s.config.WorkingHoursPerWeek * 60,
+		TopProjects:     projectReports,
+		ActivitiesLink:  fmt.Sprintf("%s/?t=week&v=%d-%d", s.config.Webroot, year, week),
+		UnsubscribeLink: s.UnsubscribeLink(recipient),
+	}, nil
+}
+
+// SendWeeklyDigests sends the digest of the last complete week to all recipients that did not yet receive it
+func (s *DigestService) SendWeeklyDigests(ctx context.Context, now time.Time) error {
+	weekStart := truncateToBucket(now, "week").AddDate(0, 0, -7)
+
+	recipients, err := s.digestRepository.FindDigestRecipients(ctx, weekStart)
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range recipients {
+		digest, err := s.BuildDigest(ctx, recipient, weekStart)
+		if err != nil {
+			return err
+		}
+
+		body := &bytes.Buffer{}
+		err = weeklyDigestTemplate.Execute(body, digest)
+		if err != nil {
+			return err
+		}
+
+		subject := fmt.Sprintf("Your week from %s", weekStart.Format("2006-01-02"))
+		err = s.repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return s.outbox.SendMail(ctx, recipient.OrganizationID, recipient.EMail, subject, body.String())
+			},
+			func(ctx context.Context) error {
+				return s.digestRepository.MarkDigestSent(ctx, recipient.OrganizationID, recipient.Username, weekStart)
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(recipients) > 0 {
+		log.Printf("sent weekly digest to %v users", len(recipients))
+	}
+	return nil
+}
+
+func (s *DigestService) handleWeeklyDigestJob(ctx context.Context, job *shared.Job) error {
+	return s.SendWeeklyDigests(ctx, time.Now())
+}
+
+func (s *DigestService) sign(organizationID uuid.UUID, username string) []byte {
+	mac := hmac.New(sha256.New, []byte(s.config.JWTSecret))
+	fmt.Fprintf(mac, "digest|%s|%s", organizationID, username)
+	return mac.Sum(nil)
+}
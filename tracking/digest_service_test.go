@@ -0,0 +1,93 @@
+package tracking
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newInMemDigestService(mailResource shared.MailResource) *DigestService {
+	repositoryTxer := shared.NewInMemRepositoryTxer()
+	return NewDigestService(
+		&shared.Config{Webroot: "http://localhost:8080", JWTSecret: "secret", WorkingHoursPerWeek: 40, WeeklyDigest: true},
+		repositoryTxer,
+		shared.NewInMemOutbox(mailResource),
+		shared.NewJobService(repositoryTxer, shared.NewInMemJobRepository()),
+		NewInMemDigestRepository(),
+		NewInMemActivityRepository(),
+	)
+}
+
+func TestSendWeeklyDigests(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemDigestService(mailResource)
+	now := time.Date(2024, 3, 13, 10, 0, 0, 0, time.UTC)
+
+	err := s.SendWeeklyDigests(context.Background(), now)
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 1)
+
+	mail := mailResource.Mails[0]
+	is.True(strings.HasPrefix(mail, "user1@baralga.com"))
+	is.True(strings.Contains(mail, "from 2024-03-04 to 2024-03-10"))
+	is.True(strings.Contains(mail, "Tracked: 1:00 h of 40:00 h (2%)"))
+	is.True(strings.Contains(mail, "- My Project: 1:00 h"))
+	is.True(strings.Contains(mail, "/?t=week&v=2024-10"))
+	is.True(strings.Contains(mail, "/api/digest/unsubscribe?"))
+
+	// digest of the week is sent only once
+	err = s.SendWeeklyDigests(context.Background(), now.Add(time.Hour))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 1)
+
+	// digest of the next week
+	err = s.SendWeeklyDigests(context.Background(), now.AddDate(0, 0, 7))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 2)
+}
+
+func TestSendWeeklyDigestsWithOptOut(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemDigestService(mailResource)
+	principal := &shared.Principal{Username: "user1", OrganizationID: shared.OrganizationIDSample}
+
+	err := s.UpdateDigestOptOut(context.Background(), principal, true)
+	is.NoErr(err)
+
+	optOut, err := s.ReadDigestOptOut(context.Background(), principal)
+	is.NoErr(err)
+	is.True(optOut)
+
+	err = s.SendWeeklyDigests(context.Background(), time.Now())
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 0)
+}
+
+func TestUnsubscribeByLink(t *testing.T) {
+	is := is.New(t)
+
+	s := newInMemDigestService(shared.NewInMemMailResource())
+	recipient := &DigestRecipient{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+
+	link, err := url.Parse(s.UnsubscribeLink(recipient))
+	is.NoErr(err)
+
+	query := link.Query()
+	query.Set("user", "user2")
+	is.Equal(s.UnsubscribeByLink(context.Background(), query), ErrDigestLinkInvalid)
+
+	is.NoErr(s.UnsubscribeByLink(context.Background(), link.Query()))
+
+	optOut, err := s.ReadDigestOptOut(context.Background(), &shared.Principal{Username: "user1", OrganizationID: shared.OrganizationIDSample})
+	is.NoErr(err)
+	is.True(optOut)
+}
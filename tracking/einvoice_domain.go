@@ -0,0 +1,48 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEInvoiceSettingsNotFound      = shared.NewDomainError("e-invoice-settings:not-found", http.StatusNotFound, "e-invoice settings of the organization not found")
+	ErrClientEInvoiceDetailsNotFound = shared.NewDomainError("client-e-invoice-details:not-found", http.StatusNotFound, "e-invoice details of the client not found")
+)
+
+// EInvoiceSettings are the details of the organization as seller on electronic invoices
+type EInvoiceSettings struct {
+	OrganizationID uuid.UUID
+	SellerName     string
+	Street         string
+	Postcode       string
+	City           string
+	CountryCode    string
+	VATID          string
+	ContactName    string
+	ContactPhone   string
+	ContactEMail   string
+	IBAN           string
+}
+
+// ClientEInvoiceDetails are the details of a client as buyer on electronic invoices, the buyer reference
+// is the Leitweg-ID of public-sector clients in Germany
+type ClientEInvoiceDetails struct {
+	ClientID       uuid.UUID
+	OrganizationID uuid.UUID
+	BuyerReference string
+	Street         string
+	Postcode       string
+	City           string
+	CountryCode    string
+}
+
+type EInvoiceRepository interface {
+	FindEInvoiceSettings(ctx context.Context, organizationID uuid.UUID) (*EInvoiceSettings, error)
+	UpdateEInvoiceSettings(ctx context.Context, settings *EInvoiceSettings) error
+	FindClientEInvoiceDetails(ctx context.Context, organizationID, clientID uuid.UUID) (*ClientEInvoiceDetails, error)
+	UpdateClientEInvoiceDetails(ctx context.Context, details *ClientEInvoiceDetails) error
+}
@@ -0,0 +1,161 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbEInvoiceRepository is a SQL database repository for the seller and buyer details of electronic invoices
+type DbEInvoiceRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ EInvoiceRepository = (*DbEInvoiceRepository)(nil)
+
+// NewDbEInvoiceRepository creates a new SQL database repository for electronic invoices
+func NewDbEInvoiceRepository(connPool *pgxpool.Pool) *DbEInvoiceRepository {
+	return &DbEInvoiceRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbEInvoiceRepository) FindEInvoiceSettings(ctx context.Context, organizationID uuid.UUID) (*EInvoiceSettings, error) {
+	row, err := shared.SelectOne[eInvoiceSettingsRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[eInvoiceSettingsRow]()+`
+		 FROM e_invoice_settings
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEInvoiceSettingsNotFound
+		}
+
+		return nil, err
+	}
+
+	return &EInvoiceSettings{
+		OrganizationID: row.OrganizationID,
+		SellerName:     row.SellerName,
+		Street:         row.Street,
+		Postcode:       row.Postcode,
+		City:           row.City,
+		CountryCode:    row.CountryCode,
+		VATID:          row.VATID,
+		ContactName:    row.ContactName,
+		ContactPhone:   row.ContactPhone,
+		ContactEMail:   row.ContactEMail,
+		IBAN:           row.IBAN,
+	}, nil
+}
+
+func (r *DbEInvoiceRepository) UpdateEInvoiceSettings(ctx context.Context, settings *EInvoiceSettings) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO e_invoice_settings
+		   (org_id, seller_name, street, postcode, city, country_code, vat_id, contact_name, contact_phone, contact_email, iban)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		 ON CONFLICT (org_id) DO UPDATE
+		 SET seller_name = EXCLUDED.seller_name, street = EXCLUDED.street, postcode = EXCLUDED.postcode,
+		     city = EXCLUDED.city, country_code = EXCLUDED.country_code, vat_id = EXCLUDED.vat_id,
+		     contact_name = EXCLUDED.contact_name, contact_phone = EXCLUDED.contact_phone,
+		     contact_email = EXCLUDED.contact_email, iban = EXCLUDED.iban`,
+		settings.OrganizationID,
+		settings.SellerName,
+		settings.Street,
+		settings.Postcode,
+		settings.City,
+		settings.CountryCode,
+		settings.VATID,
+		settings.ContactName,
+		settings.ContactPhone,
+		settings.ContactEMail,
+		settings.IBAN,
+	)
+	return err
+}
+
+func (r *DbEInvoiceRepository) FindClientEInvoiceDetails(ctx context.Context, organizationID, clientID uuid.UUID) (*ClientEInvoiceDetails, error) {
+	row, err := shared.SelectOne[clientEInvoiceDetailsRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[clientEInvoiceDetailsRow]()+`
+		 FROM client_e_invoice_details
+		 WHERE client_id = $1 AND org_id = $2`,
+		clientID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrClientEInvoiceDetailsNotFound
+		}
+
+		return nil, err
+	}
+
+	return &ClientEInvoiceDetails{
+		ClientID:       row.ClientID,
+		OrganizationID: row.OrganizationID,
+		BuyerReference: row.BuyerReference,
+		Street:         row.Street,
+		Postcode:       row.Postcode,
+		City:           row.City,
+		CountryCode:    row.CountryCode,
+	}, nil
+}
+
+func (r *DbEInvoiceRepository) UpdateClientEInvoiceDetails(ctx context.Context, details *ClientEInvoiceDetails) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO client_e_invoice_details
+		   (client_id, org_id, buyer_reference, street, postcode, city, country_code)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (client_id) DO UPDATE
+		 SET buyer_reference = EXCLUDED.buyer_reference, street = EXCLUDED.street, postcode = EXCLUDED.postcode,
+		     city = EXCLUDED.city, country_code = EXCLUDED.country_code`,
+		details.ClientID,
+		details.OrganizationID,
+		details.BuyerReference,
+		details.Street,
+		details.Postcode,
+		details.City,
+		details.CountryCode,
+	)
+	return err
+}
+
+type eInvoiceSettingsRow struct {
+	OrganizationID uuid.UUID `db:"org_id"`
+	SellerName     string    `db:"seller_name"`
+	Street         string    `db:"street"`
+	Postcode       string    `db:"postcode"`
+	City           string    `db:"city"`
+	CountryCode    string    `db:"country_code"`
+	VATID          string    `db:"vat_id"`
+	ContactName    string    `db:"contact_name"`
+	ContactPhone   string    `db:"contact_phone"`
+	ContactEMail   string    `db:"contact_email"`
+	IBAN           string    `db:"iban"`
+}
+
+type clientEInvoiceDetailsRow struct {
+	ClientID       uuid.UUID `db:"client_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	BuyerReference string    `db:"buyer_reference"`
+	Street         string    `db:"street"`
+	Postcode       string    `db:"postcode"`
+	City           string    `db:"city"`
+	CountryCode    string    `db:"country_code"`
+}
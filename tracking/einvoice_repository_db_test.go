@@ -0,0 +1,100 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestEInvoiceRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	clientRepository := NewDbClientRepository(connPool)
+	eInvoiceRepository := NewDbEInvoiceRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	client := &Client{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Name:           "ACME Corp.",
+		Currency:       "EUR",
+	}
+
+	t.Run("FindEInvoiceSettingsNotFound", func(t *testing.T) {
+		_, err := eInvoiceRepository.FindEInvoiceSettings(context.Background(), shared.OrganizationIDSample)
+		is.Equal(err, ErrEInvoiceSettingsNotFound)
+	})
+
+	t.Run("UpdateEInvoiceSettings", func(t *testing.T) {
+		settings := &EInvoiceSettings{
+			OrganizationID: shared.OrganizationIDSample,
+			SellerName:     "Baralga GmbH",
+			CountryCode:    "DE",
+		}
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				err := eInvoiceRepository.UpdateEInvoiceSettings(ctx, settings)
+				if err != nil {
+					return err
+				}
+				settings.City = "Berlin"
+				return eInvoiceRepository.UpdateEInvoiceSettings(ctx, settings)
+			},
+		)
+		is.NoErr(err)
+
+		settingsRead, err := eInvoiceRepository.FindEInvoiceSettings(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(settingsRead.SellerName, "Baralga GmbH")
+		is.Equal(settingsRead.City, "Berlin")
+	})
+
+	t.Run("UpdateClientEInvoiceDetails", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := clientRepository.InsertClient(ctx, client)
+				if err != nil {
+					return err
+				}
+				return eInvoiceRepository.UpdateClientEInvoiceDetails(ctx, &ClientEInvoiceDetails{
+					ClientID:       client.ID,
+					OrganizationID: shared.OrganizationIDSample,
+					BuyerReference: "991-01234-56",
+					CountryCode:    "DE",
+				})
+			},
+		)
+		is.NoErr(err)
+
+		details, err := eInvoiceRepository.FindClientEInvoiceDetails(context.Background(), shared.OrganizationIDSample, client.ID)
+		is.NoErr(err)
+		is.Equal(details.BuyerReference, "991-01234-56")
+
+		_, err = eInvoiceRepository.FindClientEInvoiceDetails(context.Background(), shared.OrganizationIDSample, uuid.New())
+		is.Equal(err, ErrClientEInvoiceDetailsNotFound)
+	})
+}
@@ -0,0 +1,65 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemEInvoiceRepository struct {
+	mu                    sync.Mutex
+	settings              map[uuid.UUID]*EInvoiceSettings
+	clientEInvoiceDetails map[uuid.UUID]*ClientEInvoiceDetails
+}
+
+var _ EInvoiceRepository = (*InMemEInvoiceRepository)(nil)
+
+func NewInMemEInvoiceRepository() *InMemEInvoiceRepository {
+	return &InMemEInvoiceRepository{
+		settings:              make(map[uuid.UUID]*EInvoiceSettings),
+		clientEInvoiceDetails: make(map[uuid.UUID]*ClientEInvoiceDetails),
+	}
+}
+
+func (r *InMemEInvoiceRepository) FindEInvoiceSettings(ctx context.Context, organizationID uuid.UUID) (*EInvoiceSettings, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings, ok := r.settings[organizationID]
+	if !ok {
+		return nil, ErrEInvoiceSettingsNotFound
+	}
+	copied := *settings
+	return &copied, nil
+}
+
+func (r *InMemEInvoiceRepository) UpdateEInvoiceSettings(ctx context.Context, settings *EInvoiceSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *settings
+	r.settings[settings.OrganizationID] = &copied
+	return nil
+}
+
+func (r *InMemEInvoiceRepository) FindClientEInvoiceDetails(ctx context.Context, organizationID, clientID uuid.UUID) (*ClientEInvoiceDetails, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	details, ok := r.clientEInvoiceDetails[clientID]
+	if !ok || details.OrganizationID != organizationID {
+		return nil, ErrClientEInvoiceDetailsNotFound
+	}
+	copied := *details
+	return &copied, nil
+}
+
+func (r *InMemEInvoiceRepository) UpdateClientEInvoiceDetails(ctx context.Context, details *ClientEInvoiceDetails) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *details
+	r.clientEInvoiceDetails[details.ClientID] = &copied
+	return nil
+}
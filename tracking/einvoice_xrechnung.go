@@ -0,0 +1,299 @@
+package tracking
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	xrechnungGuideline       = "urn:cen.eu:en16931:2017#compliant#urn:xeinkauf.de:kosit:xrechnung_3.0"
+	xrechnungBusinessProcess = "urn:fdc:peppol.eu:2017:poacc:billing:01:1.0"
+
+	// xrechnungCommercialInvoice is the type code of commercial invoices
+	xrechnungCommercialInvoice = "380"
+
+	// xrechnungCreditTransfer is the payment means code of SEPA credit transfers
+	xrechnungCreditTransfer = "58"
+
+	// xrechnungHour is the unit code of hours
+	xrechnungHour = "HUR"
+
+	// xrechnungDateFormat is the date format 102 (CCYYMMDD) of the cross industry invoice
+	xrechnungDateFormat = "102"
+)
+
+// XRechnungContentType is the content type of electronic invoices in the XRechnung format
+const XRechnungContentType = "application/xml"
+
+type ciiInvoice struct {
+	XMLName     xml.Name            `xml:"rsm:CrossIndustryInvoice"`
+	XmlnsRsm    string              `xml:"xmlns:rsm,attr"`
+	XmlnsRam    string              `xml:"xmlns:ram,attr"`
+	XmlnsUdt    string              `xml:"xmlns:udt,attr"`
+	Context     ciiContext          `xml:"rsm:ExchangedDocumentContext"`
+	Document    ciiDocument         `xml:"rsm:ExchangedDocument"`
+	Transaction ciiTradeTransaction `xml:"rsm:SupplyChainTradeTransaction"`
+}
+
+type ciiID struct {
+	ID string `xml:"ram:ID"`
+}
+
+type ciiContext struct {
+	BusinessProcess ciiID `xml:"ram:BusinessProcessSpecifiedDocumentContextParameter"`
+	Guideline       ciiID `xml:"ram:GuidelineSpecifiedDocumentContextParameter"`
+}
+
+type ciiDate struct {
+	DateTimeString ciiDateString `xml:"udt:DateTimeString"`
+}
+
+type ciiDateString struct {
+	Format string `xml:"format,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ciiDocument struct {
+	ID            string  `xml:"ram:ID"`
+	TypeCode      string  `xml:"ram:TypeCode"`
+	IssueDateTime ciiDate `xml:"ram:IssueDateTime"`
+}
+
+type ciiTradeTransaction struct {
+	LineItems  []*ciiLineItem `xml:"ram:IncludedSupplyChainTradeLineItem"`
+	Agreement  ciiAgreement   `xml:"ram:ApplicableHeaderTradeAgreement"`
+	Delivery   struct{}       `xml:"ram:ApplicableHeaderTradeDelivery"`
+	Settlement ciiSettlement  `xml:"ram:ApplicableHeaderTradeSettlement"`
+}
+
+type ciiLineItem struct {
+	LineID     string            `xml:"ram:AssociatedDocumentLineDocument>ram:LineID"`
+	Name       string            `xml:"ram:SpecifiedTradeProduct>ram:Name"`
+	NetPrice   string            `xml:"ram:SpecifiedLineTradeAgreement>ram:NetPriceProductTradePrice>ram:ChargeAmount"`
+	Quantity   ciiQuantity       `xml:"ram:SpecifiedLineTradeDelivery>ram:BilledQuantity"`
+	Settlement ciiLineSettlement `xml:"ram:SpecifiedLineTradeSettlement"`
+}
+
+type ciiQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiLineSettlement struct {
+	Tax             ciiLineTax `xml:"ram:ApplicableTradeTax"`
+	LineTotalAmount string     `xml:"ram:SpecifiedTradeSettlementLineMonetarySummation>ram:LineTotalAmount"`
+}
+
+type ciiLineTax struct {
+	TypeCode     string `xml:"ram:TypeCode"`
+	CategoryCode string `xml:"ram:CategoryCode"`
+	RatePercent  string `xml:"ram:RateApplicablePercent,omitempty"`
+}
+
+type ciiAgreement struct {
+	BuyerReference string        `xml:"ram:BuyerReference"`
+	Seller         ciiTradeParty `xml:"ram:SellerTradeParty"`
+	Buyer          ciiTradeParty `xml:"ram:BuyerTradeParty"`
+}
+
+type ciiTradeParty struct {
+	Name            string       `xml:"ram:Name"`
+	Contact         *ciiContact  `xml:"ram:DefinedTradeContact,omitempty"`
+	Address         ciiAddress   `xml:"ram:PostalTradeAddress"`
+	EMail           *ciiSchemeID `xml:"ram:URIUniversalCommunication>ram:URIID,omitempty"`
+	TaxRegistration *ciiSchemeID `xml:"ram:SpecifiedTaxRegistration>ram:ID,omitempty"`
+}
+
+type ciiContact struct {
+	PersonName string `xml:"ram:PersonName"`
+	Phone      string `xml:"ram:TelephoneUniversalCommunication>ram:CompleteNumber"`
+	EMail      string `xml:"ram:EmailURIUniversalCommunication>ram:URIID"`
+}
+
+type ciiAddress struct {
+	Postcode    string `xml:"ram:PostcodeCode"`
+	LineOne     string `xml:"ram:LineOne"`
+	City        string `xml:"ram:CityName"`
+	CountryCode string `xml:"ram:CountryID"`
+}
+
+type ciiSchemeID struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiSettlement struct {
+	CurrencyCode string          `xml:"ram:InvoiceCurrencyCode"`
+	PaymentMeans ciiPaymentMeans `xml:"ram:SpecifiedTradeSettlementPaymentMeans"`
+	Tax          ciiHeaderTax    `xml:"ram:ApplicableTradeTax"`
+	Period       ciiPeriod       `xml:"ram:BillingSpecifiedPeriod"`
+	DueDate      ciiDate         `xml:"ram:SpecifiedTradePaymentTerms>ram:DueDateDateTime"`
+	Summation    ciiSummation    `xml:"ram:SpecifiedTradeSettlementHeaderMonetarySummation"`
+}
+
+type ciiPaymentMeans struct {
+	TypeCode string `xml:"ram:TypeCode"`
+	IBAN     string `xml:"ram:PayeePartyCreditorFinancialAccount>ram:IBANID"`
+}
+
+type ciiHeaderTax struct {
+	CalculatedAmount string `xml:"ram:CalculatedAmount"`
+	TypeCode         string `xml:"ram:TypeCode"`
+	ExemptionReason  string `xml:"ram:ExemptionReason,omitempty"`
+	BasisAmount      string `xml:"ram:BasisAmount"`
+	CategoryCode     string `xml:"ram:CategoryCode"`
+	RatePercent      string `xml:"ram:RateApplicablePercent,omitempty"`
+}
+
+type ciiPeriod struct {
+	Start ciiDate `xml:"ram:StartDateTime"`
+	End   ciiDate `xml:"ram:EndDateTime"`
+}
+
+type ciiSummation struct {
+	LineTotalAmount     string    `xml:"ram:LineTotalAmount"`
+	TaxBasisTotalAmount string    `xml:"ram:TaxBasisTotalAmount"`
+	TaxTotalAmount      ciiAmount `xml:"ram:TaxTotalAmount"`
+	GrandTotalAmount    string    `xml:"ram:GrandTotalAmount"`
+	DuePayableAmount    string    `xml:"ram:DuePayableAmount"`
+}
+
+type ciiAmount struct {
+	CurrencyID string `xml:"currencyID,attr"`
+	Value      string `xml:",chardata"`
+}
+
+// writeXRechnung writes the invoice as cross industry invoice in the XRechnung format
+// with a line per billable project of the statement
+func writeXRechnung(w io.Writer, invoice *AccountingInvoice, settings *EInvoiceSettings, details *ClientEInvoiceDetails) error {
+	statement := invoice.Statement
+	client := statement.Client
+
+	categoryCode, exemptionReason := xrechnungTaxCategory(invoice.Tax)
+	lineTax := ciiLineTax{TypeCode: "VAT", CategoryCode: categoryCode, RatePercent: invoice.Tax.Percent()}
+	headerTax := ciiHeaderTax{TypeCode: "VAT", CategoryCode: categoryCode, RatePercent: invoice.Tax.Percent(), ExemptionReason: exemptionReason}
+
+	cii := &ciiInvoice{
+		XmlnsRsm: "urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100",
+		XmlnsRam: "urn:un:unece:uncefact:data:standard:ReusableAggregateBusinessInformationEntity:100",
+		XmlnsUdt: "urn:un:unece:uncefact:data:standard:UnqualifiedDataType:100",
+		Context: ciiContext{
+			BusinessProcess: ciiID{ID: xrechnungBusinessProcess},
+			Guideline:       ciiID{ID: xrechnungGuideline},
+		},
+		Document: ciiDocument{
+			ID:            invoice.Number,
+			TypeCode:      xrechnungCommercialInvoice,
+			IssueDateTime: ciiDateOf(invoice.Day),
+		},
+	}
+
+	transaction := &cii.Transaction
+	for i, item := range invoice.BillableItems() {
+		amountCents := statement.AmountCents(item)
+
+		transaction.LineItems = append(transaction.LineItems, &ciiLineItem{
+			LineID:   fmt.Sprint(i + 1),
+			Name:     item.ProjectTitle,
+			NetPrice: formatCents(client.HourlyRateCents),
+			Quantity: ciiQuantity{
+				UnitCode: xrechnungHour,
+				Value:    fmt.Sprintf("%.4f", float64(item.DurationInMinutesTotal)/60),
+			},
+			Settlement: ciiLineSettlement{
+				Tax:             lineTax,
+				LineTotalAmount: formatCents(amountCents),
+			},
+		})
+	}
+
+	transaction.Agreement = ciiAgreement{
+		BuyerReference: details.BuyerReference,
+		Seller: ciiTradeParty{
+			Name: settings.SellerName,
+			Contact: &ciiContact{
+				PersonName: settings.ContactName,
+				Phone:      settings.ContactPhone,
+				EMail:      settings.ContactEMail,
+			},
+			Address: ciiAddress{
+				Postcode:    settings.Postcode,
+				LineOne:     settings.Street,
+				City:        settings.City,
+				CountryCode: settings.CountryCode,
+			},
+			EMail:           &ciiSchemeID{SchemeID: "EM", Value: settings.ContactEMail},
+			TaxRegistration: &ciiSchemeID{SchemeID: "VA", Value: settings.VATID},
+		},
+		Buyer: ciiTradeParty{
+			Name: client.Name,
+			Address: ciiAddress{
+				Postcode:    details.Postcode,
+				LineOne:     details.Street,
+				City:        details.City,
+				CountryCode: details.CountryCode,
+			},
+		},
+	}
+
+	buyer := &transaction.Agreement.Buyer
+	if invoice.Tax.BuyerVATID != "" {
+		buyer.TaxRegistration = &ciiSchemeID{SchemeID: "VA", Value: invoice.Tax.BuyerVATID}
+	}
+
+	headerTax.CalculatedAmount = formatCents(invoice.TaxAmountCents())
+	headerTax.BasisAmount = formatCents(invoice.NetAmountCents())
+
+	transaction.Settlement = ciiSettlement{
+		CurrencyCode: client.Currency,
+		PaymentMeans: ciiPaymentMeans{
+			TypeCode: xrechnungCreditTransfer,
+			IBAN:     settings.IBAN,
+		},
+		Tax: headerTax,
+		Period: ciiPeriod{
+			Start: ciiDateOf(statement.Start),
+			End:   ciiDateOf(statement.End.AddDate(0, 0, -1)),
+		},
+		DueDate: ciiDateOf(invoice.DueDay),
+		Summation: ciiSummation{
+			LineTotalAmount:     formatCents(invoice.NetAmountCents()),
+			TaxBasisTotalAmount: formatCents(invoice.NetAmountCents()),
+			TaxTotalAmount:      ciiAmount{CurrencyID: client.Currency, Value: formatCents(invoice.TaxAmountCents())},
+			GrandTotalAmount:    formatCents(invoice.GrossAmountCents()),
+			DuePayableAmount:    formatCents(invoice.GrossAmountCents()),
+		},
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(cii)
+}
+
+func ciiDateOf(day time.Time) ciiDate {
+	return ciiDate{
+		DateTimeString: ciiDateString{
+			Format: xrechnungDateFormat,
+			Value:  day.Format("20060102"),
+		},
+	}
+}
+
+// xrechnungTaxCategory is the VAT category of the tax rate, with the reason for invoices without tax
+func xrechnungTaxCategory(taxRate *TaxRate) (string, string) {
+	switch {
+	case taxRate.ReverseCharge:
+		return "AE", "Reverse charge"
+	case taxRate.RateBasisPoints == 0:
+		return "E", "Exempt from VAT"
+	default:
+		return "S", ""
+	}
+}
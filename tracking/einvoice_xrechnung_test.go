@@ -0,0 +1,117 @@
+package tracking
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestWriteXRechnung(t *testing.T) {
+	is := is.New(t)
+
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	statements := []*ClientStatement{
+		{
+			Client: &Client{ID: clientIDSample, Name: "ACME Corp.", HourlyRateCents: 9000, Currency: "EUR"},
+			Start:  month,
+			End:    month.AddDate(0, 1, 0),
+			Items: []*ClientReportItem{
+				{ProjectID: shared.ProjectIDSample, ProjectTitle: "My Project", Billable: true, DurationInMinutesTotal: 90},
+				{ProjectID: uuid.New(), ProjectTitle: "Internal", Billable: false, DurationInMinutesTotal: 30},
+			},
+		},
+	}
+	taxSettings := NewTaxSettings(shared.OrganizationIDSample)
+	taxSettings.RateBasisPoints = 1900
+	settings := &EInvoiceSettings{
+		SellerName:   "Baralga GmbH",
+		Street:       "Hauptstr. 1",
+		Postcode:     "10115",
+		City:         "Berlin",
+		CountryCode:  "DE",
+		VATID:        "DE123456789",
+		ContactName:  "Jane Doe",
+		ContactPhone: "+49 30 123456",
+		ContactEMail: "billing@baralga.com",
+		IBAN:         "DE02120300000000202051",
+	}
+	details := &ClientEInvoiceDetails{
+		ClientID:       clientIDSample,
+		BuyerReference: "991-01234-56",
+		Street:         "Marktplatz 2",
+		Postcode:       "80331",
+		City:           "München",
+		CountryCode:    "DE",
+	}
+
+	t.Run("WithVAT", func(t *testing.T) {
+		invoices := NewAccountingInvoices(month, statements, taxSettings)
+
+		buf := &bytes.Buffer{}
+		err := writeXRechnung(buf, invoices[0], settings, details)
+		is.NoErr(err)
+
+		is.NoErr(xml.Unmarshal(buf.Bytes(), new(struct{})))
+
+		x := buf.String()
+		is.True(strings.HasPrefix(x, xml.Header))
+		is.True(strings.Contains(x, "<ram:ID>"+xrechnungGuideline+"</ram:ID>"))
+		is.True(strings.Contains(x, "<ram:ID>2024-03-01</ram:ID>"))
+		is.True(strings.Contains(x, `<udt:DateTimeString format="102">20240331</udt:DateTimeString>`))
+		is.True(strings.Contains(x, "<ram:BuyerReference>991-01234-56</ram:BuyerReference>"))
+		is.True(strings.Contains(x, `<ram:BilledQuantity unitCode="HUR">1.5000</ram:BilledQuantity>`))
+		is.True(strings.Contains(x, "<ram:LineTotalAmount>135.00</ram:LineTotalAmount>"))
+		is.True(strings.Contains(x, "<ram:CalculatedAmount>25.65</ram:CalculatedAmount>"))
+		is.True(strings.Contains(x, `<ram:TaxTotalAmount currencyID="EUR">25.65</ram:TaxTotalAmount>`))
+		is.True(strings.Contains(x, "<ram:DuePayableAmount>160.65</ram:DuePayableAmount>"))
+		is.True(strings.Contains(x, "<ram:IBANID>DE02120300000000202051</ram:IBANID>"))
+		is.True(!strings.Contains(x, "Internal"))
+	})
+
+	t.Run("ExemptFromVAT", func(t *testing.T) {
+		invoices := NewAccountingInvoices(month, statements, NewTaxSettings(shared.OrganizationIDSample))
+
+		buf := &bytes.Buffer{}
+		err := writeXRechnung(buf, invoices[0], settings, details)
+		is.NoErr(err)
+
+		x := buf.String()
+		is.True(strings.Contains(x, "<ram:CategoryCode>E</ram:CategoryCode>"))
+		is.True(strings.Contains(x, "<ram:ExemptionReason>Exempt from VAT</ram:ExemptionReason>"))
+		is.True(strings.Contains(x, "<ram:DuePayableAmount>135.00</ram:DuePayableAmount>"))
+	})
+}
+
+func TestWriteXRechnungReverseCharge(t *testing.T) {
+	is := is.New(t)
+
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	taxSettings := NewTaxSettings(shared.OrganizationIDSample)
+	taxSettings.RateBasisPoints = 1900
+	taxSettings.ClientTaxRates[clientIDSample] = &ClientTaxRate{ReverseCharge: true, VATID: "ATU12345678"}
+
+	invoices := NewAccountingInvoices(month, []*ClientStatement{
+		{
+			Client: &Client{ID: clientIDSample, Name: "ACME GmbH", HourlyRateCents: 9000, Currency: "EUR"},
+			Start:  month,
+			End:    month.AddDate(0, 1, 0),
+			Items:  []*ClientReportItem{{ProjectTitle: "My Project", Billable: true, DurationInMinutesTotal: 90}},
+		},
+	}, taxSettings)
+
+	buf := &bytes.Buffer{}
+	err := writeXRechnung(buf, invoices[0], &EInvoiceSettings{SellerName: "Baralga GmbH"}, &ClientEInvoiceDetails{CountryCode: "AT"})
+	is.NoErr(err)
+
+	x := buf.String()
+	is.True(strings.Contains(x, "<ram:CategoryCode>AE</ram:CategoryCode>"))
+	is.True(strings.Contains(x, "<ram:ExemptionReason>Reverse charge</ram:ExemptionReason>"))
+	is.True(strings.Contains(x, `<ram:ID schemeID="VA">ATU12345678</ram:ID>`))
+	is.True(strings.Contains(x, "<ram:GrandTotalAmount>135.00</ram:GrandTotalAmount>"))
+}
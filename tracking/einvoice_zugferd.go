@@ -0,0 +1,149 @@
+package tracking
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/baralga/shared/pdf"
+)
+
+// zugferdFileName is the name of the embedded invoice XML of the XRechnung profile of ZUGFeRD
+const zugferdFileName = "xrechnung.xml"
+
+// zugferdMetadata describes the embedded invoice XML in the XMP metadata with the Factur-X extension schema
+var zugferdMetadata = fmt.Sprintf(`<rdf:Description rdf:about="" xmlns:pdfaExtension="http://www.aiim.org/pdfa/ns/extension/" xmlns:pdfaSchema="http://www.aiim.org/pdfa/ns/schema#" xmlns:pdfaProperty="http://www.aiim.org/pdfa/ns/property#">
+<pdfaExtension:schemas>
+<rdf:Bag>
+<rdf:li rdf:parseType="Resource">
+<pdfaSchema:schema>Factur-X PDFA Extension Schema</pdfaSchema:schema>
+<pdfaSchema:namespaceURI>urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#</pdfaSchema:namespaceURI>
+<pdfaSchema:prefix>fx</pdfaSchema:prefix>
+<pdfaSchema:property>
+<rdf:Seq>
+<rdf:li rdf:parseType="Resource"><pdfaProperty:name>DocumentFileName</pdfaProperty:name><pdfaProperty:valueType>Text</pdfaProperty:valueType><pdfaProperty:category>external</pdfaProperty:category><pdfaProperty:description>The name of the embedded XML document</pdfaProperty:description></rdf:li>
+<rdf:li rdf:parseType="Resource"><pdfaProperty:name>DocumentType</pdfaProperty:name><pdfaProperty:valueType>Text</pdfaProperty:valueType><pdfaProperty:category>external</pdfaProperty:category><pdfaProperty:description>The type of the hybrid document</pdfaProperty:description></rdf:li>
+<rdf:li rdf:parseType="Resource"><pdfaProperty:name>Version</pdfaProperty:name><pdfaProperty:valueType>Text</pdfaProperty:valueType><pdfaProperty:category>external</pdfaProperty:category><pdfaProperty:description>The version of the XML schema</pdfaProperty:description></rdf:li>
+<rdf:li rdf:parseType="Resource"><pdfaProperty:name>ConformanceLevel</pdfaProperty:name><pdfaProperty:valueType>Text</pdfaProperty:valueType><pdfaProperty:category>external</pdfaProperty:category><pdfaProperty:description>The conformance level of the embedded XML document</pdfaProperty:description></rdf:li>
+</rdf:Seq>
+</pdfaSchema:property>
+</rdf:li>
+</rdf:Bag>
+</pdfaExtension:schemas>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:fx="urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#">
+<fx:DocumentType>INVOICE</fx:DocumentType>
+<fx:DocumentFileName>%s</fx:DocumentFileName>
+<fx:Version>1.0</fx:Version>
+<fx:ConformanceLevel>XRECHNUNG</fx:ConformanceLevel>
+</rdf:Description>`, zugferdFileName)
+
+// writeZUGFeRD writes the invoice as hybrid PDF in the ZUGFeRD format, a PDF/A-3 document
+// of the invoice with the same invoice in the XRechnung format embedded
+func writeZUGFeRD(w io.Writer, invoice *AccountingInvoice, settings *EInvoiceSettings, details *ClientEInvoiceDetails, now time.Time) error {
+	const (
+		left         = 50.0
+		right        = pdf.PageWidth - 50
+		hoursColumn  = right - 230
+		rateColumn   = right - 130
+		lineHeight   = 16.0
+		dateFormat   = "2006-01-02"
+		headerTop    = 60.0
+		addressTop   = 110.0
+		invoiceTitle = 200.0
+	)
+
+	xrechnung := &bytes.Buffer{}
+	err := writeXRechnung(xrechnung, invoice, settings, details)
+	if err != nil {
+		return err
+	}
+
+	statement := invoice.Statement
+	client := statement.Client
+
+	doc := pdf.NewArchival(fmt.Sprintf("Invoice %s", invoice.Number), now)
+	doc.Footer(8, fmt.Sprintf(
+		"%s · VAT ID %s\n%s · %s · %s\nIBAN %s",
+		settings.SellerName, settings.VATID, settings.ContactName, settings.ContactPhone, settings.ContactEMail, settings.IBAN,
+	))
+	bottom := pdf.PageHeight - 60 - doc.FooterHeight()
+
+	doc.Text(left, headerTop, 8, false, fmt.Sprintf("%s · %s · %s %s", settings.SellerName, settings.Street, settings.Postcode, settings.City))
+
+	doc.Text(left, addressTop, 11, false, client.Name)
+	doc.Text(left, addressTop+lineHeight, 11, false, details.Street)
+	doc.Text(left, addressTop+2*lineHeight, 11, false, fmt.Sprintf("%s %s", details.Postcode, details.City))
+	doc.Text(left, addressTop+3*lineHeight, 11, false, details.CountryCode)
+
+	doc.TextRight(right, addressTop, 10, false, fmt.Sprintf("Invoice date: %s", invoice.Day.Format(dateFormat)))
+	doc.TextRight(right, addressTop+lineHeight, 10, false, fmt.Sprintf("Due date: %s", invoice.DueDay.Format(dateFormat)))
+	doc.TextRight(right, addressTop+2*lineHeight, 10, false, fmt.Sprintf("Period: %s - %s", statement.Start.Format(dateFormat), statement.End.AddDate(0, 0, -1).Format(dateFormat)))
+	doc.TextRight(right, addressTop+3*lineHeight, 10, false, fmt.Sprintf("Buyer reference: %s", details.BuyerReference))
+	if invoice.Tax.BuyerVATID != "" {
+		doc.TextRight(right, addressTop+4*lineHeight, 10, false, fmt.Sprintf("Buyer VAT ID: %s", invoice.Tax.BuyerVATID))
+	}
+
+	doc.Text(left, invoiceTitle, 18, true, fmt.Sprintf("Invoice %s", invoice.Number))
+
+	tableHeader := func(y float64) float64 {
+		doc.Text(left, y, 10, true, "Project")
+		doc.TextRight(hoursColumn, y, 10, true, "Hours")
+		doc.TextRight(rateColumn, y, 10, true, "Rate")
+		doc.TextRight(right, y, 10, true, "Amount")
+		doc.Line(left, y+5, right, y+5)
+		return y + lineHeight + 4
+	}
+
+	y := tableHeader(invoiceTitle + 40)
+	for _, item := range invoice.BillableItems() {
+		if y > bottom {
+			doc.AddPage()
+			y = tableHeader(70)
+		}
+
+		title := []rune(item.ProjectTitle)
+		if len(title) > maxStatementTitleLength {
+			title = append(title[:maxStatementTitleLength-3], []rune("...")...)
+		}
+
+		doc.Text(left, y, 10, false, string(title))
+		doc.TextRight(hoursColumn, y, 10, false, formatHours(item.DurationInMinutesTotal))
+		doc.TextRight(rateColumn, y, 10, false, statement.FormatAmount(client.HourlyRateCents))
+		doc.TextRight(right, y, 10, false, statement.FormatAmount(statement.AmountCents(item)))
+		y += lineHeight
+	}
+
+	if y+4*lineHeight > bottom {
+		doc.AddPage()
+		y = 70
+	}
+
+	taxLabel := fmt.Sprintf("VAT %s%%", invoice.Tax.Percent())
+	if _, exemptionReason := xrechnungTaxCategory(invoice.Tax); exemptionReason != "" {
+		taxLabel = exemptionReason
+	}
+
+	doc.Line(left, y-lineHeight+5, right, y-lineHeight+5)
+	y += 4
+	doc.Text(left, y, 10, false, "Net amount")
+	doc.TextRight(right, y, 10, false, statement.FormatAmount(invoice.NetAmountCents()))
+	y += lineHeight
+	doc.Text(left, y, 10, false, taxLabel)
+	doc.TextRight(right, y, 10, false, statement.FormatAmount(invoice.TaxAmountCents()))
+	y += lineHeight
+	doc.Text(left, y, 10, true, "Total")
+	doc.TextRight(right, y, 10, true, statement.FormatAmount(invoice.GrossAmountCents()))
+
+	doc.Attach(&pdf.Attachment{
+		Name:         zugferdFileName,
+		Description:  fmt.Sprintf("XRechnung %s", invoice.Number),
+		ContentType:  "text/xml",
+		Relationship: "Alternative",
+		Content:      xrechnung.Bytes(),
+	})
+	doc.AddMetadata(zugferdMetadata)
+
+	return doc.Write(w)
+}
@@ -0,0 +1,60 @@
+package tracking
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestWriteZUGFeRD(t *testing.T) {
+	is := is.New(t)
+
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	taxSettings := NewTaxSettings(shared.OrganizationIDSample)
+	taxSettings.RateBasisPoints = 1900
+	invoices := NewAccountingInvoices(month, []*ClientStatement{
+		{
+			Client: &Client{ID: clientIDSample, Name: "ACME Corp.", HourlyRateCents: 9000, Currency: "EUR"},
+			Start:  month,
+			End:    month.AddDate(0, 1, 0),
+			Items:  []*ClientReportItem{{ProjectTitle: "My Project", Billable: true, DurationInMinutesTotal: 90}},
+		},
+	}, taxSettings)
+	settings := &EInvoiceSettings{SellerName: "Baralga GmbH", CountryCode: "DE", VATID: "DE123456789", IBAN: "DE02120300000000202051"}
+	details := &ClientEInvoiceDetails{ClientID: clientIDSample, BuyerReference: "991-01234-56", City: "München", CountryCode: "DE"}
+
+	buf := &bytes.Buffer{}
+	err := writeZUGFeRD(buf, invoices[0], settings, details, time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC))
+	is.NoErr(err)
+
+	doc := buf.String()
+	is.True(strings.HasPrefix(doc, "%PDF-1.7"))
+	is.True(strings.Contains(doc, "(Invoice 2024-03-01) Tj"))
+	is.True(strings.Contains(doc, "(VAT 19.00%) Tj"))
+	is.True(strings.Contains(doc, "(160.65 EUR) Tj"))
+	is.True(strings.Contains(doc, "/AFRelationship /Alternative"))
+	is.True(strings.Contains(doc, "<fx:DocumentFileName>xrechnung.xml</fx:DocumentFileName>"))
+	is.True(strings.Contains(doc, "<fx:ConformanceLevel>XRECHNUNG</fx:ConformanceLevel>"))
+
+	// the embedded invoice is the invoice in the XRechnung format
+	embedded := regexp.MustCompile(`/Type /EmbeddedFile [^\n]*/Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindStringSubmatchIndex(doc)
+	is.True(embedded != nil)
+	length, _ := strconv.Atoi(doc[embedded[2]:embedded[3]])
+	zr, err := zlib.NewReader(strings.NewReader(doc[embedded[1] : embedded[1]+length]))
+	is.NoErr(err)
+	xrechnung, err := io.ReadAll(zr)
+	is.NoErr(err)
+
+	expected := &bytes.Buffer{}
+	err = writeXRechnung(expected, invoices[0], settings, details)
+	is.NoErr(err)
+	is.Equal(string(xrechnung), expected.String())
+}
@@ -0,0 +1,127 @@
+package tracking
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	// emailInTokenBytes is the length of the random tokens of the email-in addresses
+	emailInTokenBytes = 12
+
+	// maxEmailInEntries is the maximum number of time entries per mail
+	maxEmailInEntries = 20
+
+	// emailInSignatureMaxAge is the maximum age of the signature of the webhook to prevent replays
+	emailInSignatureMaxAge = 15 * time.Minute
+)
+
+var (
+	ErrEmailInDisabled          = shared.NewDomainError("email-in:disabled", http.StatusNotFound, "email-in is not enabled")
+	ErrEmailInAddressNotFound   = shared.NewDomainError("email-in:address-not-found", http.StatusNotAcceptable, "email-in address not found")
+	ErrEmailInSignatureNotValid = shared.NewDomainError("email-in:signature-not-valid", http.StatusNotAcceptable, "signature of the mail not valid")
+	ErrEmailInInfected          = shared.NewDomainError("email-in:infected", http.StatusNotAcceptable, "attachment of the mail contains malware")
+)
+
+// EmailInAddress is the personal address of a user to send time entries to by email,
+// the token is the secret part of the address which is looked up by its hash and stored encrypted
+type EmailInAddress struct {
+	OrganizationID uuid.UUID
+	Username       string
+	TokenHash      string
+	Token          string
+	EMail          string
+}
+
+// InboundMail is a mail received by the webhook of the mail provider
+type InboundMail struct {
+	Recipient   string
+	Sender      string
+	Subject     string
+	Text        string
+	Attachments []*InboundAttachment
+}
+
+// InboundAttachment is a file attached to a mail
+type InboundAttachment struct {
+	FileName string
+	Content  io.ReadSeeker
+}
+
+// EmailInResult is the outcome of a time entry of a mail
+type EmailInResult struct {
+	Line     string
+	Activity *Activity
+	Error    string
+}
+
+// EmailInReply is the confirmation sent back to the user for a mail
+type EmailInReply struct {
+	Subject string
+	Results []*EmailInResult
+}
+
+type EmailInRepository interface {
+	FindEmailInAddress(ctx context.Context, organizationID uuid.UUID, username string) (*EmailInAddress, error)
+	FindEmailInAddressByTokenHash(ctx context.Context, tokenHash string) (*EmailInAddress, error)
+	UpsertEmailInAddress(ctx context.Context, address *EmailInAddress) error
+}
+
+// Address is the full mail address on the domain of the email-in
+func (a *EmailInAddress) Address(domain string) string {
+	return a.Token + "@" + domain
+}
+
+// EmailInTokenOf is the token of the recipient address on the domain of the email-in
+func EmailInTokenOf(recipient, domain string) (string, bool) {
+	address, err := mail.ParseAddress(recipient)
+	if err != nil {
+		return "", false
+	}
+
+	localPart, recipientDomain, ok := strings.Cut(address.Address, "@")
+	if !ok || !strings.EqualFold(recipientDomain, domain) || localPart == "" {
+		return "", false
+	}
+	return strings.ToLower(localPart), true
+}
+
+// Lines are the time entries of the mail, one per line of the text up to the signature or a quoted reply,
+// the subject is the time entry if the text is empty
+func (m *InboundMail) Lines() []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(m.Text, "\r\n", "\n"), "\n") {
+		if line == "-- " || strings.TrimSpace(line) == "--" || strings.HasPrefix(line, ">") {
+			break
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	subject := strings.TrimSpace(m.Subject)
+	if len(lines) == 0 && subject != "" {
+		lines = append(lines, subject)
+	}
+	return lines
+}
+
+// Failed checks whether any time entry of the mail could not be tracked
+func (r *EmailInReply) Failed() bool {
+	for _, result := range r.Results {
+		if result.Error != "" {
+			return true
+		}
+	}
+	return false
+}
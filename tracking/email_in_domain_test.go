@@ -0,0 +1,37 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestEmailInTokenOf(t *testing.T) {
+	is := is.New(t)
+
+	token, ok := EmailInTokenOf("John <AbC123@in.baralga.com>", "in.baralga.com")
+	is.True(ok)
+	is.Equal(token, "abc123")
+
+	_, ok = EmailInTokenOf("abc123@other.com", "in.baralga.com")
+	is.True(!ok)
+
+	_, ok = EmailInTokenOf("not an address", "in.baralga.com")
+	is.True(!ok)
+}
+
+func TestInboundMailLines(t *testing.T) {
+	is := is.New(t)
+
+	mail := &InboundMail{
+		Subject: "Hours",
+		Text:    "3h Project X — workshop prep\r\n\r\n 1h #website fixes \r\n-- \r\nJohn Doe\r\n",
+	}
+	is.Equal(mail.Lines(), []string{"3h Project X — workshop prep", "1h #website fixes"})
+
+	mail = &InboundMail{
+		Subject: "2h Project X - review",
+		Text:    "> quoted reply",
+	}
+	is.Equal(mail.Lines(), []string{"2h Project X - review"})
+}
@@ -0,0 +1,126 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbEmailInRepository is a SQL database repository for the email-in addresses of users
+type DbEmailInRepository struct {
+	connPool  *pgxpool.Pool
+	encrypter *shared.Encrypter
+}
+
+var _ EmailInRepository = (*DbEmailInRepository)(nil)
+
+func init() {
+	shared.RegisterEncryptedColumn("email_in_addresses", "token_hash", "token")
+}
+
+// NewDbEmailInRepository creates a new SQL database repository for email-in addresses,
+// the tokens are stored encrypted by the encrypter
+func NewDbEmailInRepository(connPool *pgxpool.Pool, encrypter *shared.Encrypter) *DbEmailInRepository {
+	return &DbEmailInRepository{
+		connPool:  connPool,
+		encrypter: encrypter,
+	}
+}
+
+func (r *DbEmailInRepository) FindEmailInAddress(ctx context.Context, organizationID uuid.UUID, username string) (*EmailInAddress, error) {
+	row, err := shared.SelectOne[emailInAddressRow](
+		ctx,
+		r.connPool,
+		`SELECT e.org_id, e.username, e.token_hash, e.token, u.email
+		 FROM email_in_addresses e
+		 LEFT JOIN users u ON u.org_id = e.org_id AND u.username = e.username
+		 WHERE e.org_id = $1 AND e.username = $2`,
+		organizationID,
+		username,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEmailInAddressNotFound
+		}
+
+		return nil, err
+	}
+
+	return r.toEmailInAddress(row)
+}
+
+// FindEmailInAddressByTokenHash reads the address of the token across all organizations,
+// addresses of disabled users are not found
+func (r *DbEmailInRepository) FindEmailInAddressByTokenHash(ctx context.Context, tokenHash string) (*EmailInAddress, error) {
+	row, err := shared.SelectOne[emailInAddressRow](
+		ctx,
+		r.connPool,
+		`SELECT e.org_id, e.username, e.token_hash, e.token, u.email
+		 FROM email_in_addresses e
+		 JOIN users u ON u.org_id = e.org_id AND u.username = e.username
+		 WHERE e.token_hash = $1 AND u.enabled = 1`,
+		tokenHash,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEmailInAddressNotFound
+		}
+
+		return nil, err
+	}
+
+	return r.toEmailInAddress(row)
+}
+
+func (r *DbEmailInRepository) UpsertEmailInAddress(ctx context.Context, address *EmailInAddress) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	token, err := r.encrypter.Encrypt(address.Token)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO email_in_addresses
+		   (org_id, username, token_hash, token, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, now())
+		 ON CONFLICT (org_id, username) DO UPDATE
+		 SET token_hash = $3, token = $4, created_at = now()`,
+		address.OrganizationID,
+		address.Username,
+		address.TokenHash,
+		token,
+	)
+	return err
+}
+
+// toEmailInAddress maps the row to the address with the decrypted token
+func (r *DbEmailInRepository) toEmailInAddress(row *emailInAddressRow) (*EmailInAddress, error) {
+	token, err := r.encrypter.Decrypt(row.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailInAddress{
+		OrganizationID: row.OrganizationID,
+		Username:       row.Username,
+		TokenHash:      row.TokenHash,
+		Token:          token,
+		EMail:          row.EMail.String,
+	}, nil
+}
+
+type emailInAddressRow struct {
+	OrganizationID uuid.UUID      `db:"org_id"`
+	Username       string         `db:"username"`
+	TokenHash      string         `db:"token_hash"`
+	Token          string         `db:"token"`
+	EMail          sql.NullString `db:"email"`
+}
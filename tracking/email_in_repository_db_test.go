@@ -0,0 +1,72 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestEmailInRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	encrypter, err := shared.NewEncrypter(shared.EncryptionKeySample)
+	is.NoErr(err)
+
+	emailInRepository := NewDbEmailInRepository(connPool, encrypter)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("UpsertEmailInAddress", func(t *testing.T) {
+		_, err := emailInRepository.FindEmailInAddress(context.Background(), shared.OrganizationIDSample, "user1")
+		is.True(errors.Is(err, ErrEmailInAddressNotFound))
+
+		for _, token := range []string{"first", "second"} {
+			address := &EmailInAddress{
+				OrganizationID: shared.OrganizationIDSample,
+				Username:       "user1",
+				TokenHash:      shared.HashToken(token),
+				Token:          token,
+			}
+			err := repositoryTxer.InTx(
+				context.Background(),
+				func(ctx context.Context) error {
+					return emailInRepository.UpsertEmailInAddress(ctx, address)
+				},
+			)
+			is.NoErr(err)
+		}
+
+		address, err := emailInRepository.FindEmailInAddress(context.Background(), shared.OrganizationIDSample, "user1")
+		is.NoErr(err)
+		is.Equal(address.Token, "second")
+
+		address, err = emailInRepository.FindEmailInAddressByTokenHash(context.Background(), shared.HashToken("second"))
+		is.NoErr(err)
+		is.Equal(address.Username, "user1")
+		is.True(address.EMail != "")
+
+		_, err = emailInRepository.FindEmailInAddressByTokenHash(context.Background(), shared.HashToken("first"))
+		is.True(errors.Is(err, ErrEmailInAddressNotFound))
+	})
+}
@@ -0,0 +1,70 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemEmailInRepository struct {
+	mu        sync.Mutex
+	addresses []*EmailInAddress
+	emails    map[string]string
+}
+
+var _ EmailInRepository = (*InMemEmailInRepository)(nil)
+
+func NewInMemEmailInRepository() *InMemEmailInRepository {
+	return &InMemEmailInRepository{
+		emails: map[string]string{
+			"user1": "user1@baralga.com",
+		},
+	}
+}
+
+func (r *InMemEmailInRepository) FindEmailInAddress(ctx context.Context, organizationID uuid.UUID, username string) (*EmailInAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, address := range r.addresses {
+		if address.OrganizationID == organizationID && address.Username == username {
+			return r.withEMail(address), nil
+		}
+	}
+	return nil, ErrEmailInAddressNotFound
+}
+
+func (r *InMemEmailInRepository) FindEmailInAddressByTokenHash(ctx context.Context, tokenHash string) (*EmailInAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, address := range r.addresses {
+		if address.TokenHash == tokenHash {
+			return r.withEMail(address), nil
+		}
+	}
+	return nil, ErrEmailInAddressNotFound
+}
+
+func (r *InMemEmailInRepository) UpsertEmailInAddress(ctx context.Context, address *EmailInAddress) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	upserted := *address
+	upserted.EMail = ""
+	for i, a := range r.addresses {
+		if a.OrganizationID == address.OrganizationID && a.Username == address.Username {
+			r.addresses[i] = &upserted
+			return nil
+		}
+	}
+	r.addresses = append(r.addresses, &upserted)
+	return nil
+}
+
+func (r *InMemEmailInRepository) withEMail(address *EmailInAddress) *EmailInAddress {
+	found := *address
+	found.EMail = r.emails[address.Username]
+	return &found
+}
@@ -0,0 +1,171 @@
+package tracking
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxEmailInMailSize is the maximum size of mails posted to the webhook including attachments
+const maxEmailInMailSize = 10 << 20
+
+type emailInAddressModel struct {
+	Address string     `json:"address"`
+	Links   *hal.Links `json:"_links"`
+}
+
+type emailInResultModel struct {
+	Line       string `json:"line"`
+	ActivityID string `json:"activityId,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type emailInReplyModel struct {
+	Results []*emailInResultModel `json:"results"`
+}
+
+type EmailInRestHandlers struct {
+	config         *shared.Config
+	emailInService *EmailInService
+}
+
+func NewEmailInRestHandlers(config *shared.Config, emailInService *EmailInService) *EmailInRestHandlers {
+	return &EmailInRestHandlers{
+		config:         config,
+		emailInService: emailInService,
+	}
+}
+
+func (a *EmailInRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/email-in/address", a.HandleGetEmailInAddress())
+	r.Post("/email-in/address/renew", a.HandleRenewEmailInAddress())
+}
+
+func (a *EmailInRestHandlers) RegisterOpen(r chi.Router) {
+	r.Post("/email-in/webhook", a.HandleEmailInWebhook())
+}
+
+// HandleGetEmailInAddress reads the personal address the user sends time entries to
+func (a *EmailInRestHandlers) HandleGetEmailInAddress() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	emailInService := a.emailInService
+	emailInDomain := a.config.EmailInDomain
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		address, err := emailInService.ReadEmailInAddress(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToEmailInAddressModel(address, emailInDomain))
+	}
+}
+
+// HandleRenewEmailInAddress replaces the personal address of the user by a new one
+func (a *EmailInRestHandlers) HandleRenewEmailInAddress() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	emailInService := a.emailInService
+	emailInDomain := a.config.EmailInDomain
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		address, err := emailInService.RenewEmailInAddress(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToEmailInAddressModel(address, emailInDomain))
+	}
+}
+
+// HandleEmailInWebhook receives the mails posted by the routes of the mail provider in the format of Mailgun,
+// mails to unknown addresses or with an invalid signature are rejected with 406 so that they are not retried
+func (a *EmailInRestHandlers) HandleEmailInWebhook() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	emailInService := a.emailInService
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxEmailInMailSize)
+		err := r.ParseMultipartForm(maxEmailInMailSize)
+		if err != nil && err != http.ErrNotMultipart {
+			shared.RenderValidationProblemJSON(w, "mail not valid", err)
+			return
+		}
+		if err == http.ErrNotMultipart {
+			err = r.ParseForm()
+			if err != nil {
+				shared.RenderValidationProblemJSON(w, "mail not valid", err)
+				return
+			}
+		}
+
+		err = emailInService.VerifyEmailInSignature(r.PostFormValue("timestamp"), r.PostFormValue("token"), r.PostFormValue("signature"), time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		text := r.PostFormValue("stripped-text")
+		if text == "" {
+			text = r.PostFormValue("body-plain")
+		}
+
+		var attachments []*InboundAttachment
+		if r.MultipartForm != nil {
+			for _, fileHeaders := range r.MultipartForm.File {
+				for _, fileHeader := range fileHeaders {
+					file, err := fileHeader.Open()
+					if err != nil {
+						shared.RenderProblemJSON(w, isProduction, err)
+						return
+					}
+					defer file.Close()
+
+					attachments = append(attachments, &InboundAttachment{
+						FileName: fileHeader.Filename,
+						Content:  file,
+					})
+				}
+			}
+		}
+
+		reply, err := emailInService.ReceiveMail(r.Context(), &InboundMail{
+			Recipient:   r.PostFormValue("recipient"),
+			Sender:      r.PostFormValue("sender"),
+			Subject:     r.PostFormValue("subject"),
+			Text:        text,
+			Attachments: attachments,
+		}, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		resultModels := make([]*emailInResultModel, len(reply.Results))
+		for i, result := range reply.Results {
+			resultModels[i] = &emailInResultModel{
+				Line:  result.Line,
+				Error: result.Error,
+			}
+			if result.Activity != nil {
+				resultModels[i].ActivityID = result.Activity.ID.String()
+			}
+		}
+		shared.RenderJSON(w, &emailInReplyModel{Results: resultModels})
+	}
+}
+
+func mapToEmailInAddressModel(address *EmailInAddress, emailInDomain string) *emailInAddressModel {
+	return &emailInAddressModel{
+		Address: address.Address(emailInDomain),
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/email-in/address"),
+			hal.NewLink("renew", "/api/email-in/address/renew"),
+		),
+	}
+}
@@ -0,0 +1,80 @@
+package tracking
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleEmailIn(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{EmailInDomain: "in.baralga.com", EmailInSigningKey: "key"}
+	emailInService, mailResource := newEmailInServiceForTest(config)
+	a := NewEmailInRestHandlers(config, emailInService)
+
+	r, _ := http.NewRequest("GET", "/api/email-in/address", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}))
+
+	httpRec := httptest.NewRecorder()
+	a.HandleGetEmailInAddress()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	addressModel := &emailInAddressModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(addressModel)
+	is.NoErr(err)
+
+	webhook := func(recipient, signingKey string) *http.Request {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(signingKey))
+		mac.Write([]byte(timestamp + "token"))
+
+		form := url.Values{}
+		form.Set("timestamp", timestamp)
+		form.Set("token", "token")
+		form.Set("signature", hex.EncodeToString(mac.Sum(nil)))
+		form.Set("recipient", recipient)
+		form.Set("sender", "user1@baralga.com")
+		form.Set("subject", "Hours")
+		form.Set("body-plain", "3h My Project — workshop prep")
+
+		r, _ := http.NewRequest("POST", "/api/email-in/webhook", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return r
+	}
+
+	httpRec = httptest.NewRecorder()
+	a.HandleEmailInWebhook()(httpRec, webhook(addressModel.Address, "other"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotAcceptable)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleEmailInWebhook()(httpRec, webhook("unknown@in.baralga.com", "key"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotAcceptable)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleEmailInWebhook()(httpRec, webhook(addressModel.Address, "key"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	replyModel := &emailInReplyModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(replyModel)
+	is.NoErr(err)
+	is.Equal(len(replyModel.Results), 1)
+	is.True(replyModel.Results[0].ActivityID != "")
+	is.Equal(len(mailResource.Mails), 1)
+}
@@ -0,0 +1,250 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/pkg/errors"
+)
+
+var emailInReplyTemplate = template.Must(template.New("email-in-reply").Funcs(template.FuncMap{
+	"formatDateTime": time_utils.FormatDateTime,
+}).Parse(`{{ range .Results }}{{ if .Activity }}Tracked: {{ .Line }}
+  {{ formatDateTime .Activity.Start }} - {{ formatDateTime .Activity.End }}
+{{ else }}Not tracked: {{ .Line }}
+  {{ .Error }}
+{{ end }}{{ else }}The mail contains no time entries.
+{{ end }}{{ if .Failed }}
+Send one time entry per line like:
+  3h Project X - workshop prep
+  9:00-11:30 yesterday Project X - review
+  1h30m #project-x planning
+{{ end }}`))
+
+// EmailInService tracks the time entries users send to their personal email-in address
+type EmailInService struct {
+	config            *shared.Config
+	repositoryTxer    shared.RepositoryTxer
+	outbox            shared.Outbox
+	emailInRepository EmailInRepository
+	quickAddService   *QuickAddService
+	scanService       *shared.ScanService
+}
+
+// NewEmailInService creates a new service for time entries by email
+func NewEmailInService(
+	config *shared.Config,
+	repositoryTxer shared.RepositoryTxer,
+	outbox shared.Outbox,
+	emailInRepository EmailInRepository,
+	quickAddService *QuickAddService,
+	scanService *shared.ScanService,
+) *EmailInService {
+	return &EmailInService{
+		config:            config,
+		repositoryTxer:    repositoryTxer,
+		outbox:            outbox,
+		emailInRepository: emailInRepository,
+		quickAddService:   quickAddService,
+		scanService:       scanService,
+	}
+}
+
+// ReadEmailInAddress reads the email-in address of the principal, the address is created on first use
+func (s *EmailInService) ReadEmailInAddress(ctx context.Context, principal *shared.Principal) (*EmailInAddress, error) {
+	if s.config.EmailInDomain == "" {
+		return nil, ErrEmailInDisabled
+	}
+
+	address, err := s.emailInRepository.FindEmailInAddress(ctx, principal.OrganizationID, principal.Username)
+	if err == nil {
+		return address, nil
+	}
+	if !errors.Is(err, ErrEmailInAddressNotFound) {
+		return nil, err
+	}
+
+	return s.RenewEmailInAddress(ctx, principal)
+}
+
+// RenewEmailInAddress replaces the email-in address of the principal by a new one, e.g. if it leaked
+func (s *EmailInService) RenewEmailInAddress(ctx context.Context, principal *shared.Principal) (*EmailInAddress, error) {
+	if s.config.EmailInDomain == "" {
+		return nil, ErrEmailInDisabled
+	}
+
+	token, err := newEmailInToken()
+	if err != nil {
+		return nil, err
+	}
+
+	address := &EmailInAddress{
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		TokenHash:      shared.HashToken(token),
+		Token:          token,
+	}
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.emailInRepository.UpsertEmailInAddress(ctx, address)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// VerifyEmailInSignature verifies the signature of the mail provider over the timestamp and token of the webhook
+func (s *EmailInService) VerifyEmailInSignature(timestamp, token, signature string, now time.Time) error {
+	if s.config.EmailInDomain == "" {
+		return ErrEmailInDisabled
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrEmailInSignatureNotValid
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > emailInSignatureMaxAge || age < -emailInSignatureMaxAge {
+		return ErrEmailInSignatureNotValid
+	}
+
+	expectedSignature, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrEmailInSignatureNotValid
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.config.EmailInSigningKey))
+	mac.Write([]byte(timestamp + token))
+	if !hmac.Equal(mac.Sum(nil), expectedSignature) {
+		return ErrEmailInSignatureNotValid
+	}
+	return nil
+}
+
+// ReceiveMail tracks the time entries of the mail for the user of the recipient address and replies
+// with the tracked activities and the lines that could not be tracked. The reply is sent to the email
+// of the user, not to the sender, so that forged senders never receive replies.
+func (s *EmailInService) ReceiveMail(ctx context.Context, inboundMail *InboundMail, now time.Time) (*EmailInReply, error) {
+	if s.config.EmailInDomain == "" {
+		return nil, ErrEmailInDisabled
+	}
+
+	token, ok := EmailInTokenOf(inboundMail.Recipient, s.config.EmailInDomain)
+	if !ok {
+		return nil, ErrEmailInAddressNotFound
+	}
+
+	address, err := s.emailInRepository.FindEmailInAddressByTokenHash(ctx, shared.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = shared.WithOrganizationID(ctx, address.OrganizationID)
+
+	// mails with infected attachments are rejected as a whole, the attachments are not processed
+	for _, attachment := range inboundMail.Attachments {
+		err := s.scanService.ScanUpload(ctx, &shared.Upload{
+			OrganizationID: address.OrganizationID,
+			Username:       address.Username,
+			FileName:       attachment.FileName,
+			Content:        attachment.Content,
+		})
+		if errors.Is(err, shared.ErrUploadInfected) {
+			return nil, errors.Wrap(ErrEmailInInfected, err.Error())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	principal := &shared.Principal{
+		Username:       address.Username,
+		OrganizationID: address.OrganizationID,
+		Roles:          []string{"ROLE_USER"},
+	}
+
+	reply := &EmailInReply{
+		Subject: fmt.Sprintf("Re: %s", inboundMail.Subject),
+	}
+	if inboundMail.Subject == "" {
+		reply.Subject = "Your time entries"
+	}
+
+	lines := inboundMail.Lines()
+	if len(lines) > maxEmailInEntries {
+		lines = lines[:maxEmailInEntries]
+	}
+
+	for _, line := range lines {
+		result, err := s.trackLine(ctx, principal, line, now)
+		if err != nil {
+			return nil, err
+		}
+		reply.Results = append(reply.Results, result)
+	}
+
+	if address.EMail == "" {
+		log.Printf("could not reply to email-in of user %s without email", address.Username)
+		return reply, nil
+	}
+
+	body := &bytes.Buffer{}
+	err = emailInReplyTemplate.Execute(body, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.outbox.SendMail(ctx, address.OrganizationID, address.EMail, reply.Subject, body.String())
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// trackLine tracks the time entry of a line, entries not valid are reported in the result
+func (s *EmailInService) trackLine(ctx context.Context, principal *shared.Principal, line string, now time.Time) (*EmailInResult, error) {
+	result := &EmailInResult{
+		Line: line,
+	}
+
+	activity, err := s.quickAddService.TrackQuickAddMessage(ctx, principal, line, now)
+	if err != nil {
+		result.Error, err = quickAddErrorMessage(err)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	result.Activity = activity
+	return result, nil
+}
+
+func newEmailInToken() (string, error) {
+	token := make([]byte, emailInTokenBytes)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,139 @@
+package tracking
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newEmailInServiceForTest(config *shared.Config) (*EmailInService, *shared.InMemMailResource) {
+	activityService := &ActitivityService{
+		repositoryTxer:               shared.NewInMemRepositoryTxer(),
+		activityRepository:           NewInMemActivityRepository(),
+		locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+		projectAssignmentRepository:  NewInMemProjectAssignmentRepository(),
+		breakPolicyRepository:        NewInMemBreakPolicyRepository(),
+		categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+	}
+	mailResource := shared.NewInMemMailResource()
+
+	return NewEmailInService(
+		config,
+		shared.NewInMemRepositoryTxer(),
+		shared.NewInMemOutbox(mailResource),
+		NewInMemEmailInRepository(),
+		NewQuickAddService(activityService, NewInMemProjectRepository()),
+		shared.NewScanService(config, shared.NewInMemRepositoryTxer(), shared.NewInMemOutbox(mailResource), shared.NewInMemStorage(), nil),
+	), mailResource
+}
+
+func TestReceiveMail(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{EmailInDomain: "in.baralga.com", EmailInSigningKey: "key"}
+	s, mailResource := newEmailInServiceForTest(config)
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+	now := time.Date(2024, 3, 4, 17, 0, 0, 0, time.UTC)
+
+	address, err := s.ReadEmailInAddress(context.Background(), principal)
+	is.NoErr(err)
+	is.True(strings.HasSuffix(address.Address(config.EmailInDomain), "@in.baralga.com"))
+
+	// the address is kept
+	again, err := s.ReadEmailInAddress(context.Background(), principal)
+	is.NoErr(err)
+	is.Equal(again.Token, address.Token)
+
+	reply, err := s.ReceiveMail(context.Background(), &InboundMail{
+		Recipient: address.Address(config.EmailInDomain),
+		Sender:    "user1@baralga.com",
+		Subject:   "Hours",
+		Text:      "3h My Project — workshop prep\nsome text without duration\n2h Unknown Project - review",
+	}, now)
+	is.NoErr(err)
+	is.Equal(len(reply.Results), 3)
+	is.Equal(reply.Results[0].Activity.ProjectID, shared.ProjectIDSample)
+	is.Equal(reply.Results[0].Activity.Description, "workshop prep")
+	is.Equal(reply.Results[0].Activity.End.Sub(reply.Results[0].Activity.Start), 3*time.Hour)
+	is.True(reply.Results[1].Error != "")
+	is.Equal(reply.Results[2].Error, ErrQuickAddProjectNotFound.Title)
+	is.True(reply.Failed())
+
+	is.Equal(len(mailResource.Mails), 1)
+	is.True(strings.HasPrefix(mailResource.Mails[0], "user1@baralga.com"))
+	is.True(strings.Contains(mailResource.Mails[0], "Re: Hours"))
+	is.True(strings.Contains(mailResource.Mails[0], "Tracked: 3h My Project — workshop prep"))
+	is.True(strings.Contains(mailResource.Mails[0], "Not tracked: 2h Unknown Project - review"))
+
+	// renewed addresses replace the old address
+	_, err = s.RenewEmailInAddress(context.Background(), principal)
+	is.NoErr(err)
+
+	_, err = s.ReceiveMail(context.Background(), &InboundMail{Recipient: address.Address(config.EmailInDomain), Text: "1h My Project - review"}, now)
+	is.True(errors.Is(err, ErrEmailInAddressNotFound))
+}
+
+func TestReceiveMailWithInfectedAttachment(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{EmailInDomain: "in.baralga.com", EmailInSigningKey: "key", InstanceAdmins: "ops@baralga.com"}
+	s, mailResource := newEmailInServiceForTest(config)
+	s.scanService = shared.NewScanService(config, shared.NewInMemRepositoryTxer(), shared.NewInMemOutbox(mailResource), shared.NewInMemStorage(), shared.NewInMemScanner())
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+
+	address, err := s.ReadEmailInAddress(context.Background(), principal)
+	is.NoErr(err)
+
+	_, err = s.ReceiveMail(context.Background(), &InboundMail{
+		Recipient: address.Address(config.EmailInDomain),
+		Text:      "1h My Project - review",
+		Attachments: []*InboundAttachment{
+			{FileName: "timesheet.csv", Content: strings.NewReader("Date;Start")},
+			{FileName: "eicar.com", Content: strings.NewReader(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`)},
+		},
+	}, time.Now())
+	is.True(errors.Is(err, ErrEmailInInfected))
+
+	// operators are notified, the sender gets no reply
+	is.Equal(len(mailResource.Mails), 1)
+	is.True(strings.HasPrefix(mailResource.Mails[0], "ops@baralga.com"))
+}
+
+func TestReceiveMailDisabled(t *testing.T) {
+	is := is.New(t)
+
+	s, _ := newEmailInServiceForTest(&shared.Config{})
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+
+	_, err := s.ReadEmailInAddress(context.Background(), principal)
+	is.True(errors.Is(err, ErrEmailInDisabled))
+
+	_, err = s.ReceiveMail(context.Background(), &InboundMail{Recipient: "abc@in.baralga.com"}, time.Now())
+	is.True(errors.Is(err, ErrEmailInDisabled))
+}
+
+func TestVerifyEmailInSignature(t *testing.T) {
+	is := is.New(t)
+
+	s, _ := newEmailInServiceForTest(&shared.Config{EmailInDomain: "in.baralga.com", EmailInSigningKey: "key"})
+	now := time.Date(2024, 3, 4, 17, 0, 0, 0, time.UTC)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte(timestamp + "token"))
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	is.NoErr(s.VerifyEmailInSignature(timestamp, "token", signature, now))
+	is.True(errors.Is(s.VerifyEmailInSignature(timestamp, "other", signature, now), ErrEmailInSignatureNotValid))
+	is.True(errors.Is(s.VerifyEmailInSignature(timestamp, "token", signature, now.Add(time.Hour)), ErrEmailInSignatureNotValid))
+	is.True(errors.Is(s.VerifyEmailInSignature("soon", "token", signature, now), ErrEmailInSignatureNotValid))
+}
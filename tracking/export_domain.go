@@ -0,0 +1,75 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	ExportJobStatusQueued   string = "queued"
+	ExportJobStatusRunning  string = "running"
+	ExportJobStatusDone     string = "done"
+	ExportJobStatusFailed   string = "failed"
+	ExportContentTypeCSV    string = "text/csv"
+	ExportContentTypeExcel  string = "application/vnd.ms-excel"
+	exportJobExpiryDuration        = 24 * time.Hour
+)
+
+var ErrExportJobNotFound = shared.NewDomainError("export:not-found", http.StatusNotFound, "export job not found")
+
+// ExportJob is an export of activities processed in the background
+type ExportJob struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Username       string
+	ContentType    string
+	Status         string
+	Progress       int
+	Total          int
+	Filter         *ActivitiesFilter
+	FilterName     string
+	Content        []byte
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+type ExportJobRepository interface {
+	FindExportJobByID(ctx context.Context, organizationID, exportJobID uuid.UUID) (*ExportJob, error)
+	InsertExportJob(ctx context.Context, exportJob *ExportJob) (*ExportJob, error)
+	UpdateExportJobProgress(ctx context.Context, exportJobID uuid.UUID, status string, progress, total int) error
+	UpdateExportJobContent(ctx context.Context, exportJobID uuid.UUID, content []byte) error
+	DeleteExpiredExportJobs(ctx context.Context, now time.Time) error
+}
+
+// IsDone checks whether the export is ready for download
+func (j *ExportJob) IsDone() bool {
+	return j.Status == ExportJobStatusDone
+}
+
+// IsExpired checks whether the export is no longer available
+func (j *ExportJob) IsExpired(now time.Time) bool {
+	return now.After(j.ExpiresAt)
+}
+
+// ProgressPercentage is the share of the exported activities in percent
+func (j *ExportJob) ProgressPercentage() int {
+	if j.IsDone() {
+		return 100
+	}
+	if j.Total <= 0 {
+		return 0
+	}
+	return j.Progress * 100 / j.Total
+}
+
+// FileName is the name of the exported file
+func (j *ExportJob) FileName() string {
+	if j.ContentType == ExportContentTypeExcel {
+		return "Activities_" + j.FilterName + ".xlsx"
+	}
+	return "Activities_" + j.FilterName + ".csv"
+}
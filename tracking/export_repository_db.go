@@ -0,0 +1,184 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbExportJobRepository is a SQL database repository for export jobs
+type DbExportJobRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ ExportJobRepository = (*DbExportJobRepository)(nil)
+
+// NewDbExportJobRepository creates a new SQL database repository for export jobs
+func NewDbExportJobRepository(connPool *pgxpool.Pool) *DbExportJobRepository {
+	return &DbExportJobRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbExportJobRepository) FindExportJobByID(ctx context.Context, organizationID, exportJobID uuid.UUID) (*ExportJob, error) {
+	row := r.connPool.QueryRow(ctx,
+		`SELECT export_job_id, org_id, username, content_type, status, progress, total, 
+		        filter_start, filter_end, filter_username, filter_name, filter_query, content, created_at, expires_at
+         FROM export_jobs 
+	     WHERE export_job_id = $1 AND org_id = $2`,
+		exportJobID, organizationID)
+
+	exportJob, err := scanExportJob(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExportJobNotFound
+		}
+
+		return nil, err
+	}
+
+	return exportJob, nil
+}
+
+func (r *DbExportJobRepository) InsertExportJob(ctx context.Context, exportJob *ExportJob) (*ExportJob, error) {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	var filterQuery sql.NullString
+	if exportJob.Filter.Query != nil {
+		filterQuery = sql.NullString{String: exportJob.Filter.Query.String(), Valid: true}
+	}
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO export_jobs 
+		   (export_job_id, org_id, username, content_type, status, progress, total, 
+		    filter_start, filter_end, filter_username, filter_name, filter_query, created_at, expires_at) 
+		 VALUES 
+		   ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		exportJob.ID,
+		exportJob.OrganizationID,
+		exportJob.Username,
+		exportJob.ContentType,
+		exportJob.Status,
+		exportJob.Progress,
+		exportJob.Total,
+		exportJob.Filter.Start,
+		exportJob.Filter.End,
+		exportJob.Filter.Username,
+		exportJob.FilterName,
+		filterQuery,
+		exportJob.CreatedAt,
+		exportJob.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return exportJob, nil
+}
+
+func (r *DbExportJobRepository) UpdateExportJobProgress(ctx context.Context, exportJobID uuid.UUID, status string, progress, total int) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`UPDATE export_jobs 
+		 SET status = $2, progress = $3, total = $4
+		 WHERE export_job_id = $1`,
+		exportJobID,
+		status,
+		progress,
+		total,
+	)
+	return err
+}
+
+func (r *DbExportJobRepository) UpdateExportJobContent(ctx context.Context, exportJobID uuid.UUID, content []byte) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`UPDATE export_jobs 
+		 SET content = $2, status = $3, progress = total
+		 WHERE export_job_id = $1`,
+		exportJobID,
+		content,
+		ExportJobStatusDone,
+	)
+	return err
+}
+
+func (r *DbExportJobRepository) DeleteExpiredExportJobs(ctx context.Context, now time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`DELETE FROM export_jobs 
+		 WHERE expires_at < $1`,
+		now,
+	)
+	return err
+}
+
+func scanExportJob(row pgx.Row) (*ExportJob, error) {
+	var (
+		id             string
+		organizationID string
+		username       string
+		contentType    string
+		status         string
+		progress       int
+		total          int
+		filterStart    time.Time
+		filterEnd      time.Time
+		filterUsername sql.NullString
+		filterName     sql.NullString
+		filterQuery    sql.NullString
+		content        []byte
+		createdAt      time.Time
+		expiresAt      time.Time
+	)
+
+	err := row.Scan(&id, &organizationID, &username, &contentType, &status, &progress, &total,
+		&filterStart, &filterEnd, &filterUsername, &filterName, &filterQuery, &content, &createdAt, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var query *ActivityQuery
+	if filterQuery.Valid {
+		query, err = ParseActivityQuery(filterQuery.String)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	exportJob := &ExportJob{
+		ID:             uuid.MustParse(id),
+		OrganizationID: uuid.MustParse(organizationID),
+		Username:       username,
+		ContentType:    contentType,
+		Status:         status,
+		Progress:       progress,
+		Total:          total,
+		Filter: &ActivitiesFilter{
+			Start:          filterStart,
+			End:            filterEnd,
+			Username:       filterUsername.String,
+			Query:          query,
+			OrganizationID: uuid.MustParse(organizationID),
+		},
+		FilterName: filterName.String,
+		Content:    content,
+		CreatedAt:  createdAt,
+		ExpiresAt:  expiresAt,
+	}
+
+	return exportJob, nil
+}
@@ -0,0 +1,102 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestExportJobRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	exportJobRepository := NewDbExportJobRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	now := time.Now()
+	exportJob := &ExportJob{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1@baralga.com",
+		ContentType:    ExportContentTypeCSV,
+		Status:         ExportJobStatusQueued,
+		Filter: &ActivitiesFilter{
+			Start:          now.AddDate(0, -1, 0),
+			End:            now,
+			OrganizationID: shared.OrganizationIDSample,
+		},
+		FilterName: "2022-01",
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(exportJobExpiryDuration),
+	}
+
+	t.Run("InsertExportJob", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := exportJobRepository.InsertExportJob(ctx, exportJob)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		exportJobRead, err := exportJobRepository.FindExportJobByID(context.Background(), shared.OrganizationIDSample, exportJob.ID)
+		is.NoErr(err)
+		is.Equal(exportJobRead.Status, ExportJobStatusQueued)
+	})
+
+	t.Run("UpdateExportJob", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				err := exportJobRepository.UpdateExportJobProgress(ctx, exportJob.ID, ExportJobStatusRunning, 1, 2)
+				if err != nil {
+					return err
+				}
+				return exportJobRepository.UpdateExportJobContent(ctx, exportJob.ID, []byte("Date;Start"))
+			},
+		)
+		is.NoErr(err)
+
+		exportJobRead, err := exportJobRepository.FindExportJobByID(context.Background(), shared.OrganizationIDSample, exportJob.ID)
+		is.NoErr(err)
+		is.Equal(exportJobRead.Status, ExportJobStatusDone)
+		is.Equal(exportJobRead.Progress, 2)
+		is.Equal(string(exportJobRead.Content), "Date;Start")
+	})
+
+	t.Run("DeleteExpiredExportJobs", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return exportJobRepository.DeleteExpiredExportJobs(ctx, now.Add(2*exportJobExpiryDuration))
+			},
+		)
+		is.NoErr(err)
+
+		_, err = exportJobRepository.FindExportJobByID(context.Background(), shared.OrganizationIDSample, exportJob.ID)
+		is.Equal(err, ErrExportJobNotFound)
+	})
+}
@@ -0,0 +1,89 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemExportJobRepository struct {
+	mu         sync.Mutex
+	exportJobs []*ExportJob
+}
+
+var _ ExportJobRepository = (*InMemExportJobRepository)(nil)
+
+func NewInMemExportJobRepository() *InMemExportJobRepository {
+	return &InMemExportJobRepository{}
+}
+
+func (r *InMemExportJobRepository) FindExportJobByID(ctx context.Context, organizationID, exportJobID uuid.UUID) (*ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, j := range r.exportJobs {
+		if j.ID == exportJobID && j.OrganizationID == organizationID {
+			exportJob := *j
+			return &exportJob, nil
+		}
+	}
+
+	return nil, ErrExportJobNotFound
+}
+
+func (r *InMemExportJobRepository) InsertExportJob(ctx context.Context, exportJob *ExportJob) (*ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j := *exportJob
+	r.exportJobs = append(r.exportJobs, &j)
+	return exportJob, nil
+}
+
+func (r *InMemExportJobRepository) UpdateExportJobProgress(ctx context.Context, exportJobID uuid.UUID, status string, progress, total int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, j := range r.exportJobs {
+		if j.ID == exportJobID {
+			j.Status = status
+			j.Progress = progress
+			j.Total = total
+			return nil
+		}
+	}
+
+	return ErrExportJobNotFound
+}
+
+func (r *InMemExportJobRepository) UpdateExportJobContent(ctx context.Context, exportJobID uuid.UUID, content []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, j := range r.exportJobs {
+		if j.ID == exportJobID {
+			j.Content = content
+			j.Status = ExportJobStatusDone
+			j.Progress = j.Total
+			return nil
+		}
+	}
+
+	return ErrExportJobNotFound
+}
+
+func (r *InMemExportJobRepository) DeleteExpiredExportJobs(ctx context.Context, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var exportJobs []*ExportJob
+	for _, j := range r.exportJobs {
+		if !j.IsExpired(now) {
+			exportJobs = append(exportJobs, j)
+		}
+	}
+	r.exportJobs = exportJobs
+	return nil
+}
@@ -0,0 +1,255 @@
+package tracking
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type exportJobModel struct {
+	ID          string     `json:"id"`
+	ContentType string     `json:"contentType"`
+	Status      string     `json:"status"`
+	Progress    int        `json:"progress"`
+	Total       int        `json:"total"`
+	Percentage  int        `json:"percentage"`
+	ExpiresAt   string     `json:"expiresAt"`
+	Links       *hal.Links `json:"_links"`
+}
+
+type ExportRestHandlers struct {
+	config        *shared.Config
+	exportService *ExportService
+}
+
+func NewExportRestHandlers(config *shared.Config, exportService *ExportService) *ExportRestHandlers {
+	return &ExportRestHandlers{
+		config:        config,
+		exportService: exportService,
+	}
+}
+
+func (a *ExportRestHandlers) RegisterProtected(r chi.Router) {
+	r.Post("/exports", a.HandleCreateExportJob())
+	r.Get("/exports/{export-id}", a.HandleGetExportJob())
+	r.Post("/instance/organizations/{organization-id}/exports", a.HandleCreateInstanceExportJob())
+	r.Get("/instance/organizations/{organization-id}/exports/{export-id}", a.HandleGetInstanceExportJob())
+}
+
+func (a *ExportRestHandlers) RegisterOpen(r chi.Router) {
+	r.Get("/exports/{export-id}/download", a.HandleDownloadExport())
+}
+
+// HandleCreateExportJob queues an export of the filtered activities
+func (a *ExportRestHandlers) HandleCreateExportJob() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	exportService := a.exportService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		filter, err := filterFromQueryParams(r.URL.Query())
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		contentType := r.URL.Query().Get("contentType")
+		if contentType == "" {
+			contentType = ExportContentTypeCSV
+		}
+		if contentType != ExportContentTypeCSV && contentType != ExportContentTypeExcel {
+			http.Error(w, problem.New(problem.Title("content type not supported")).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		exportJob, err := exportService.CreateExportJob(r.Context(), principal, filter, contentType)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		exportJobModel := mapToExportJobModel(exportJob, "")
+
+		w.Header().Set("Location", fmt.Sprintf("/api/exports/%s", exportJob.ID))
+		w.WriteHeader(http.StatusAccepted)
+		shared.RenderJSON(w, exportJobModel)
+	}
+}
+
+// HandleGetExportJob reads the progress of an export
+func (a *ExportRestHandlers) HandleGetExportJob() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	exportService := a.exportService
+	return func(w http.ResponseWriter, r *http.Request) {
+		exportIDParam := chi.URLParam(r, "export-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		exportJobID, err := uuid.Parse(exportIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		exportJob, err := exportService.ReadExportJob(r.Context(), principal, exportJobID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		downloadLink := ""
+		if exportJob.IsDone() {
+			downloadLink = exportService.DownloadLink(exportJob)
+		}
+
+		shared.RenderJSON(w, mapToExportJobModel(exportJob, downloadLink))
+	}
+}
+
+// HandleCreateInstanceExportJob queues an export of the filtered activities of an organization for an operator of the instance
+func (a *ExportRestHandlers) HandleCreateInstanceExportJob() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	exportService := a.exportService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		organizationID, err := uuid.Parse(chi.URLParam(r, "organization-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		filter, err := filterFromQueryParams(r.URL.Query())
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		contentType := r.URL.Query().Get("contentType")
+		if contentType == "" {
+			contentType = ExportContentTypeCSV
+		}
+		if contentType != ExportContentTypeCSV && contentType != ExportContentTypeExcel {
+			http.Error(w, problem.New(problem.Title("content type not supported")).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		exportJob, err := exportService.CreateInstanceExportJob(r.Context(), principal, organizationID, filter, contentType)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		exportJobModel := mapToInstanceExportJobModel(exportJob, "")
+
+		w.Header().Set("Location", exportJobModel.Links.HrefOf("self"))
+		w.WriteHeader(http.StatusAccepted)
+		shared.RenderJSON(w, exportJobModel)
+	}
+}
+
+// HandleGetInstanceExportJob reads the progress of an export of an organization for an operator of the instance
+func (a *ExportRestHandlers) HandleGetInstanceExportJob() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	exportService := a.exportService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		organizationID, err := uuid.Parse(chi.URLParam(r, "organization-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		exportJobID, err := uuid.Parse(chi.URLParam(r, "export-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		exportJob, err := exportService.ReadInstanceExportJob(r.Context(), principal, organizationID, exportJobID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		downloadLink := ""
+		if exportJob.IsDone() {
+			downloadLink = exportService.DownloadLink(exportJob)
+		}
+
+		shared.RenderJSON(w, mapToInstanceExportJobModel(exportJob, downloadLink))
+	}
+}
+
+// HandleDownloadExport downloads a finished export with a signed link
+func (a *ExportRestHandlers) HandleDownloadExport() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	exportService := a.exportService
+	return func(w http.ResponseWriter, r *http.Request) {
+		exportIDParam := chi.URLParam(r, "export-id")
+
+		exportJobID, err := uuid.Parse(exportIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		exportJob, err := exportService.ReadExportJobByLink(r.Context(), exportJobID, r.URL.Query())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		content, err := exportService.OpenExportContent(r.Context(), exportJob)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+		defer content.Close()
+
+		w.Header().Set("Content-Type", exportJob.ContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%v\"", exportJob.FileName()))
+		_, _ = io.Copy(w, content)
+	}
+}
+
+func mapToExportJobModel(exportJob *ExportJob, downloadLink string) *exportJobModel {
+	links := []*hal.Links{
+		hal.NewSelfLink(fmt.Sprintf("/api/exports/%s", exportJob.ID)),
+	}
+	if downloadLink != "" {
+		links = append(links, hal.NewLink("download", downloadLink))
+	}
+
+	return &exportJobModel{
+		ID:          exportJob.ID.String(),
+		ContentType: exportJob.ContentType,
+		Status:      exportJob.Status,
+		Progress:    exportJob.Progress,
+		Total:       exportJob.Total,
+		Percentage:  exportJob.ProgressPercentage(),
+		ExpiresAt:   exportJob.ExpiresAt.Format(time.RFC3339),
+		Links:       hal.NewLinks(links...),
+	}
+}
+
+func mapToInstanceExportJobModel(exportJob *ExportJob, downloadLink string) *exportJobModel {
+	exportJobModel := mapToExportJobModel(exportJob, downloadLink)
+
+	links := []*hal.Links{
+		hal.NewSelfLink(fmt.Sprintf("/api/instance/organizations/%s/exports/%s", exportJob.OrganizationID, exportJob.ID)),
+	}
+	if downloadLink != "" {
+		links = append(links, hal.NewLink("download", downloadLink))
+	}
+	exportJobModel.Links = hal.NewLinks(links...)
+
+	return exportJobModel
+}
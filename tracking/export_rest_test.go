@@ -0,0 +1,125 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestHandleCreateExportJob(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ExportRestHandlers{
+		config:        &shared.Config{},
+		exportService: newInMemExportService(shared.NewInMemMailResource()),
+	}
+
+	r, _ := http.NewRequest("POST", "/api/exports?t=month&v=2021-10&contentType=text/csv", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		Username:       "user1@baralga.com",
+		OrganizationID: shared.OrganizationIDSample,
+	}))
+
+	a.HandleCreateExportJob()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusAccepted)
+
+	exportJobModel := &exportJobModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(exportJobModel)
+	is.NoErr(err)
+	is.Equal(exportJobModel.Status, ExportJobStatusQueued)
+	is.Equal(httpRec.Header().Get("Location"), "/api/exports/"+exportJobModel.ID)
+}
+
+func TestHandleCreateInstanceExportJob(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ExportRestHandlers{
+		config:        &shared.Config{},
+		exportService: newInMemExportService(shared.NewInMemMailResource()),
+	}
+
+	organizationID := uuid.New()
+	r, _ := http.NewRequest("POST", "/api/instance/organizations/"+organizationID.String()+"/exports?t=month&v=2021-10", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		Username:       "ops@baralga.com",
+		OrganizationID: shared.OrganizationIDSample,
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("organization-id", organizationID.String())
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	a.HandleCreateInstanceExportJob()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusAccepted)
+
+	exportJobModel := &exportJobModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(exportJobModel)
+	is.NoErr(err)
+	is.Equal(httpRec.Header().Get("Location"), "/api/instance/organizations/"+organizationID.String()+"/exports/"+exportJobModel.ID)
+}
+
+func TestHandleCreateExportJobWithInvalidContentType(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ExportRestHandlers{
+		config:        &shared.Config{},
+		exportService: newInMemExportService(shared.NewInMemMailResource()),
+	}
+
+	r, _ := http.NewRequest("POST", "/api/exports?t=month&v=2021-10&contentType=text/plain", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleCreateExportJob()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleGetExportJobNotFound(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ExportRestHandlers{
+		config:        &shared.Config{},
+		exportService: newInMemExportService(shared.NewInMemMailResource()),
+	}
+
+	exportJobID := uuid.New().String()
+	r, _ := http.NewRequest("GET", "/api/exports/"+exportJobID, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("export-id", exportJobID)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
+
+	a.HandleGetExportJob()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
+
+func TestHandleDownloadExportWithInvalidSignature(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ExportRestHandlers{
+		config:        &shared.Config{},
+		exportService: newInMemExportService(shared.NewInMemMailResource()),
+	}
+
+	exportJobID := uuid.New().String()
+	r, _ := http.NewRequest("GET", "/api/exports/"+exportJobID+"/download?org="+shared.OrganizationIDSample.String()+"&expires=9999999999&signature=abcd", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("export-id", exportJobID)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	a.HandleDownloadExport()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
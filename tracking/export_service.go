@@ -0,0 +1,372 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	exportJobType        = "export"
+	exportCleanupJobType = "export-cleanup"
+	exportStoragePrefix  = "exports/"
+)
+
+var ErrExportLinkInvalid = shared.NewDomainError("export:link-invalid", http.StatusForbidden, "download link invalid or expired")
+
+// ExportService processes exports of activities in the background
+type ExportService struct {
+	config              *shared.Config
+	repositoryTxer      shared.RepositoryTxer
+	outbox              shared.Outbox
+	jobService          *shared.JobService
+	storage             shared.Storage
+	exportJobRepository ExportJobRepository
+	activityRepository  ActivityRepository
+	activityService     *ActitivityService
+}
+
+// NewExportService creates a new service to export activities in the background
+func NewExportService(
+	config *shared.Config,
+	repositoryTxer shared.RepositoryTxer,
+	outbox shared.Outbox,
+	jobService *shared.JobService,
+	storage shared.Storage,
+	exportJobRepository ExportJobRepository,
+	activityRepository ActivityRepository,
+	activityService *ActitivityService,
+) *ExportService {
+	s := &ExportService{
+		config:              config,
+		repositoryTxer:      repositoryTxer,
+		outbox:              outbox,
+		jobService:          jobService,
+		storage:             storage,
+		exportJobRepository: exportJobRepository,
+		activityRepository:  activityRepository,
+		activityService:     activityService,
+	}
+
+	jobService.RegisterHandler(exportJobType, s.handleExportJob)
+	jobService.RegisterHandler(exportCleanupJobType, s.handleExportCleanupJob)
+	jobService.Schedule(exportCleanupJobType, time.Hour)
+
+	return s
+}
+
+// CreateExportJob queues an export of the filtered activities
+func (s *ExportService) CreateExportJob(ctx context.Context, principal *shared.Principal, filter *ActivityFilter, contentType string) (*ExportJob, error) {
+	if contentType != ExportContentTypeCSV && contentType != ExportContentTypeExcel {
+		return nil, errors.Errorf("content type %s not supported", contentType)
+	}
+
+	now := time.Now()
+	exportJob := &ExportJob{
+		ID:             uuid.New(),
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		ContentType:    contentType,
+		Status:         ExportJobStatusQueued,
+		Filter:         toFilter(principal, filter),
+		FilterName:     filter.String(),
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(exportJobExpiryDuration),
+	}
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			_, err := s.exportJobRepository.InsertExportJob(ctx, exportJob)
+			return err
+		},
+		func(ctx context.Context) error {
+			return s.jobService.Enqueue(ctx, shared.NewJob(exportJob.OrganizationID, exportJobType, exportJob.ID.String()))
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return exportJob, nil
+}
+
+// ReadExportJob reads an export job of the principal's organization
+func (s *ExportService) ReadExportJob(ctx context.Context, principal *shared.Principal, exportJobID uuid.UUID) (*ExportJob, error) {
+	exportJob, err := s.exportJobRepository.FindExportJobByID(ctx, principal.OrganizationID, exportJobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if exportJob.Username != principal.Username && !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrExportJobNotFound
+	}
+
+	return exportJob, nil
+}
+
+// CreateInstanceExportJob queues an export of the filtered activities of an organization for an operator of the instance
+func (s *ExportService) CreateInstanceExportJob(ctx context.Context, principal *shared.Principal, organizationID uuid.UUID, filter *ActivityFilter, contentType string) (*ExportJob, error) {
+	ctx, organizationPrincipal, err := shared.AsInstanceAdmin(ctx, s.config, principal, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.CreateExportJob(ctx, organizationPrincipal, filter, contentType)
+}
+
+// ReadInstanceExportJob reads an export job of an organization for an operator of the instance
+func (s *ExportService) ReadInstanceExportJob(ctx context.Context, principal *shared.Principal, organizationID, exportJobID uuid.UUID) (*ExportJob, error) {
+	ctx, organizationPrincipal, err := shared.AsInstanceAdmin(ctx, s.config, principal, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ReadExportJob(ctx, organizationPrincipal, exportJobID)
+}
+
+// ReadExportJobByLink reads a finished export job of a signed download link
+func (s *ExportService) ReadExportJobByLink(ctx context.Context, exportJobID uuid.UUID, query url.Values) (*ExportJob, error) {
+	organizationID, err := uuid.Parse(query.Get("org"))
+	if err != nil {
+		return nil, ErrExportLinkInvalid
+	}
+
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().After(time.Unix(expires, 0)) {
+		return nil, ErrExportLinkInvalid
+	}
+
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil || !hmac.Equal(signature, s.sign(exportJobID, organizationID, expires)) {
+		return nil, ErrExportLinkInvalid
+	}
+
+	exportJob, err := s.exportJobRepository.FindExportJobByID(ctx, organizationID, exportJobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exportJob.IsDone() {
+		return nil, ErrExportJobNotFound
+	}
+
+	return exportJob, nil
+}
+
+// OpenExportContent opens the content of a finished export in the storage, exports finished
+// before the storage was introduced still have their content in the database
+func (s *ExportService) OpenExportContent(ctx context.Context, exportJob *ExportJob) (io.ReadCloser, error) {
+	content, err := s.storage.Get(ctx, exportStorageKeyOf(exportJob))
+	if errors.Is(err, shared.ErrStorageObjectNotFound) && exportJob.Content != nil {
+		return io.NopCloser(bytes.NewReader(exportJob.Content)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// DownloadLink is the signed link to download the export until it expires
+func (s *ExportService) DownloadLink(exportJob *ExportJob) string {
+	expires := exportJob.ExpiresAt.Unix()
+
+	query := url.Values{}
+	query.Set("org", exportJob.OrganizationID.String())
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", hex.EncodeToString(s.sign(exportJob.ID, exportJob.OrganizationID, expires)))
+
+	return fmt.Sprintf("%s/api/exports/%s/download?%s", s.config.Webroot, exportJob.ID, query.Encode())
+}
+
+func (s *ExportService) handleExportJob(ctx context.Context, job *shared.Job) error {
+	exportJob, err := s.exportJobRepository.FindExportJobByID(ctx, job.OrganizationID, uuid.MustParse(job.Payload))
+	if errors.Is(err, ErrExportJobNotFound) {
+		// export expired in the meantime
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// export was already processed
+	if exportJob.Status == ExportJobStatusDone || exportJob.Status == ExportJobStatusFailed {
+		return nil
+	}
+
+	err = s.export(ctx, exportJob)
+	if err != nil {
+		if !job.CanRetry() {
+			updateErr := s.updateProgress(ctx, exportJob, ExportJobStatusFailed, exportJob.Progress)
+			if updateErr != nil {
+				log.Printf("could not update export job %v: %v", exportJob.ID, updateErr)
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *ExportService) handleExportCleanupJob(ctx context.Context, job *shared.Job) error {
+	now := time.Now()
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.exportJobRepository.DeleteExpiredExportJobs(ctx, now)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// the content of an export expires together with its export job
+	objects, err := s.storage.List(ctx, exportStoragePrefix)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		if object.LastModified.Add(exportJobExpiryDuration).After(now) {
+			continue
+		}
+		err = s.storage.Delete(ctx, object.Key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ExportService) export(ctx context.Context, exportJob *ExportJob) error {
+	activitiesPage, _, err := s.activityRepository.FindActivities(ctx, exportJob.Filter, &paged.PageParams{Page: 0, Size: 1})
+	if err != nil {
+		return err
+	}
+	exportJob.Total = activitiesPage.Page.TotalElements
+
+	err = s.updateProgress(ctx, exportJob, ExportJobStatusRunning, 0)
+	if err != nil {
+		return err
+	}
+
+	links, err := s.activityService.linksOfActivities(ctx, exportJob.Filter)
+	if err != nil {
+		return err
+	}
+
+	var activities []*Activity
+	projectsByID := make(map[uuid.UUID]*Project)
+
+	buf := &bytes.Buffer{}
+	csvWriter := newActivitiesCSVWriter(buf)
+	if exportJob.ContentType == ExportContentTypeCSV {
+		err = csvWriter.Write(activitiesCSVHeaders)
+		if err != nil {
+			return err
+		}
+	}
+
+	count := 0
+	err = s.activityRepository.StreamActivities(ctx, exportJob.Filter, func(activity *Activity, project *Project) error {
+		if exportJob.ContentType == ExportContentTypeCSV {
+			err := csvWriter.Write(activityCSVRecord(activity, project, links[activity.ID]))
+			if err != nil {
+				return err
+			}
+		} else {
+			activities = append(activities, activity)
+			projectsByID[project.ID] = project
+		}
+
+		count++
+		if count%csvFlushInterval == 0 {
+			return s.updateProgress(ctx, exportJob, ExportJobStatusRunning, count)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if exportJob.ContentType == ExportContentTypeCSV {
+		csvWriter.Flush()
+		err = csvWriter.Error()
+	} else {
+		projects := make([]*Project, 0, len(projectsByID))
+		for _, project := range projectsByID {
+			projects = append(projects, project)
+		}
+		err = s.activityService.WriteAsExcel(activities, projects, links, buf)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Put(ctx, exportStorageKeyOf(exportJob), bytes.NewReader(buf.Bytes()), int64(buf.Len()), exportJob.ContentType)
+	if err != nil {
+		return err
+	}
+
+	exportJob.Progress = count
+	exportJob.Status = ExportJobStatusDone
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.exportJobRepository.UpdateExportJobContent(ctx, exportJob.ID, nil)
+		},
+		func(ctx context.Context) error {
+			return s.notify(ctx, exportJob)
+		},
+	)
+}
+
+func (s *ExportService) updateProgress(ctx context.Context, exportJob *ExportJob, status string, progress int) error {
+	exportJob.Status = status
+	exportJob.Progress = progress
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.exportJobRepository.UpdateExportJobProgress(ctx, exportJob.ID, status, progress, exportJob.Total)
+		},
+	)
+}
+
+func (s *ExportService) notify(ctxWithTx context.Context, exportJob *ExportJob) error {
+	// usernames are email addresses except for some external logins
+	if _, err := mail.ParseAddress(exportJob.Username); err != nil {
+		return nil
+	}
+
+	subject := "Your export is ready"
+	body := fmt.Sprintf(
+		`Your export of activities is ready. Download it at %v until %v.`,
+		s.DownloadLink(exportJob),
+		exportJob.ExpiresAt.Format("2006-01-02 15:04"),
+	)
+	return s.outbox.SendMail(ctxWithTx, exportJob.OrganizationID, exportJob.Username, subject, body)
+}
+
+func exportStorageKeyOf(exportJob *ExportJob) string {
+	return fmt.Sprintf("%s%s/%s", exportStoragePrefix, exportJob.OrganizationID, exportJob.ID)
+}
+
+func (s *ExportService) sign(exportJobID, organizationID uuid.UUID, expires int64) []byte {
+	mac := hmac.New(sha256.New, []byte(s.config.JWTSecret))
+	fmt.Fprintf(mac, "%s|%s|%d", exportJobID, organizationID, expires)
+	return mac.Sum(nil)
+}
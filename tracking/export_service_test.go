@@ -0,0 +1,172 @@
+package tracking
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func newInMemExportService(mailResource shared.MailResource) *ExportService {
+	activityRepository := NewInMemActivityRepository()
+	repositoryTxer := shared.NewInMemRepositoryTxer()
+	return NewExportService(
+		&shared.Config{Webroot: "http://localhost:8080", JWTSecret: "secret", InstanceAdmins: "ops@baralga.com"},
+		repositoryTxer,
+		shared.NewInMemOutbox(mailResource),
+		shared.NewJobService(repositoryTxer, shared.NewInMemJobRepository()),
+		shared.NewInMemStorage(),
+		NewInMemExportJobRepository(),
+		activityRepository,
+		&ActitivityService{activityRepository: activityRepository, activityLinkRepository: NewInMemActivityLinkRepository()},
+	)
+}
+
+func TestExportJob(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemExportService(mailResource)
+
+	principal := &shared.Principal{
+		Username:       "user1@baralga.com",
+		OrganizationID: shared.OrganizationIDSample,
+	}
+	filter := &ActivityFilter{Timespan: TimespanMonth, start: time.Now()}
+
+	exportJobCreated, err := s.CreateExportJob(context.Background(), principal, filter, ExportContentTypeCSV)
+	is.NoErr(err)
+	is.Equal(exportJobCreated.Status, ExportJobStatusQueued)
+	is.Equal(exportJobCreated.Filter.Username, principal.Username)
+
+	t.Run("ProcessExportJob", func(t *testing.T) {
+		processed, err := s.jobService.ProcessNextJob(context.Background())
+		is.NoErr(err)
+		is.True(processed)
+
+		exportJob, err := s.ReadExportJob(context.Background(), principal, exportJobCreated.ID)
+		is.NoErr(err)
+		is.Equal(exportJob.Status, ExportJobStatusDone)
+		is.Equal(exportJob.ProgressPercentage(), 100)
+
+		content, err := s.OpenExportContent(context.Background(), exportJob)
+		is.NoErr(err)
+		defer content.Close()
+		csv, err := io.ReadAll(content)
+		is.NoErr(err)
+		is.True(strings.Contains(string(csv), "My Project"))
+
+		is.Equal(len(mailResource.Mails), 1)
+		is.True(strings.Contains(mailResource.Mails[0], "/api/exports/"+exportJob.ID.String()+"/download"))
+	})
+
+	t.Run("ReadExportJobByLink", func(t *testing.T) {
+		downloadLink, err := url.Parse(s.DownloadLink(exportJobCreated))
+		is.NoErr(err)
+
+		exportJob, err := s.ReadExportJobByLink(context.Background(), exportJobCreated.ID, downloadLink.Query())
+		is.NoErr(err)
+		is.Equal(exportJob.ID, exportJobCreated.ID)
+
+		query := downloadLink.Query()
+		query.Set("expires", "1")
+		_, err = s.ReadExportJobByLink(context.Background(), exportJobCreated.ID, query)
+		is.Equal(err, ErrExportLinkInvalid)
+	})
+
+	t.Run("ReadExportJobOfOtherUser", func(t *testing.T) {
+		_, err := s.ReadExportJob(context.Background(), &shared.Principal{
+			Username:       "user2@baralga.com",
+			OrganizationID: shared.OrganizationIDSample,
+		}, exportJobCreated.ID)
+		is.Equal(err, ErrExportJobNotFound)
+	})
+}
+
+func TestOpenExportContentFromDatabase(t *testing.T) {
+	is := is.New(t)
+
+	s := newInMemExportService(shared.NewInMemMailResource())
+
+	content, err := s.OpenExportContent(context.Background(), &ExportJob{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Content:        []byte("Date;Start"),
+	})
+	is.NoErr(err)
+	defer content.Close()
+
+	csv, err := io.ReadAll(content)
+	is.NoErr(err)
+	is.Equal(string(csv), "Date;Start")
+}
+
+func TestExportCleanupJobDeletesExpiredContent(t *testing.T) {
+	is := is.New(t)
+
+	s := newInMemExportService(shared.NewInMemMailResource())
+
+	err := s.storage.Put(context.Background(), "exports/expired", strings.NewReader("Date;Start"), 10, ExportContentTypeCSV)
+	is.NoErr(err)
+	s.storage.(*shared.InMemStorage).Touch("exports/expired", time.Now().Add(-exportJobExpiryDuration))
+
+	err = s.storage.Put(context.Background(), "exports/current", strings.NewReader("Date;Start"), 10, ExportContentTypeCSV)
+	is.NoErr(err)
+
+	err = s.handleExportCleanupJob(context.Background(), nil)
+	is.NoErr(err)
+
+	objects, err := s.storage.List(context.Background(), exportStoragePrefix)
+	is.NoErr(err)
+	is.Equal(len(objects), 1)
+	is.Equal(objects[0].Key, "exports/current")
+}
+
+func TestCreateExportJobWithInvalidContentType(t *testing.T) {
+	is := is.New(t)
+
+	s := newInMemExportService(shared.NewInMemMailResource())
+
+	_, err := s.CreateExportJob(context.Background(), &shared.Principal{}, &ActivityFilter{Timespan: TimespanMonth, start: time.Now()}, "text/plain")
+	is.True(err != nil)
+}
+
+func TestInstanceExportJob(t *testing.T) {
+	is := is.New(t)
+
+	s := newInMemExportService(shared.NewInMemMailResource())
+
+	organizationID := uuid.MustParse("b1d3e9b4-1c1e-4d4f-9bd5-0f1d7f9c1a11")
+	principal := &shared.Principal{
+		Username:       "ops@baralga.com",
+		OrganizationID: shared.OrganizationIDSample,
+	}
+	filter := &ActivityFilter{Timespan: TimespanMonth, start: time.Now()}
+
+	exportJobCreated, err := s.CreateInstanceExportJob(context.Background(), principal, organizationID, filter, ExportContentTypeCSV)
+	is.NoErr(err)
+	is.Equal(exportJobCreated.OrganizationID, organizationID)
+	is.Equal(exportJobCreated.Filter.Username, "")
+
+	exportJob, err := s.ReadInstanceExportJob(context.Background(), principal, organizationID, exportJobCreated.ID)
+	is.NoErr(err)
+	is.Equal(exportJob.ID, exportJobCreated.ID)
+
+	// org admins can not export other organizations
+	orgAdmin := &shared.Principal{
+		Username:       "admin@baralga.com",
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+	_, err = s.CreateInstanceExportJob(context.Background(), orgAdmin, organizationID, filter, ExportContentTypeCSV)
+	is.Equal(err, shared.ErrForbidden)
+
+	_, err = s.ReadInstanceExportJob(context.Background(), orgAdmin, organizationID, exportJobCreated.ID)
+	is.Equal(err, shared.ErrForbidden)
+}
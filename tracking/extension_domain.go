@@ -0,0 +1,101 @@
+package tracking
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	maxQuickProjects      = 5
+	maxPageTitleLength    = 200
+	quickProjectsLookback = 30 * 24 * time.Hour
+)
+
+// ExtensionStatus is the compact status shown by the browser extension,
+// the timer of the user and the projects to pick from quickly
+type ExtensionStatus struct {
+	Timer    *TimerState
+	Projects []*Project
+}
+
+// PageActivity is an activity tracked on a web page with the browser extension,
+// the title and url of the page are recorded as context of the activity
+type PageActivity struct {
+	ProjectID uuid.UUID
+	Start     time.Time
+	End       time.Time
+	Title     string
+	URL       string
+}
+
+// Validate checks that the page is a web page and the activity ends after it starts
+func (p *PageActivity) Validate() error {
+	if !p.End.After(p.Start) {
+		return shared.NewInvalidParam("end", "after", "end must be after start")
+	}
+	if utf8.RuneCountInString(p.Title) > maxPageTitleLength {
+		return shared.NewInvalidParam("title", "max", fmt.Sprintf("title must not be longer than %v characters", maxPageTitleLength))
+	}
+
+	pageURL, err := url.Parse(p.URL)
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return shared.NewInvalidParam("url", "url", "url must be a http or https url")
+	}
+	return nil
+}
+
+// ToActivity is the activity of the page, the description is the title and the url of the page
+// shortened to the maximum length of descriptions, the url is left out if it doesn't fit
+func (p *PageActivity) ToActivity() *Activity {
+	title := strings.TrimSpace(p.Title)
+	description := p.URL
+	if title != "" {
+		description = title + " - " + p.URL
+	}
+	if utf8.RuneCountInString(description) > maxSyncDescriptionLength {
+		description = title
+	}
+	if description == "" {
+		description = string([]rune(p.URL)[:maxSyncDescriptionLength])
+	}
+
+	return &Activity{
+		Start:       p.Start,
+		End:         p.End,
+		Description: description,
+		ProjectID:   p.ProjectID,
+	}
+}
+
+// quickProjectsOf are the projects of the activities, most recent first, followed by the open projects
+// until there are enough to pick from
+func quickProjectsOf(activities []*Activity, projects []*Project, openProjects []*Project) []*Project {
+	projectsByID := make(map[uuid.UUID]*Project)
+	for _, project := range projects {
+		projectsByID[project.ID] = project
+	}
+
+	var quickProjects []*Project
+	added := make(map[uuid.UUID]bool)
+	add := func(project *Project) {
+		if project == nil || added[project.ID] || len(quickProjects) >= maxQuickProjects {
+			return
+		}
+		added[project.ID] = true
+		quickProjects = append(quickProjects, project)
+	}
+
+	for _, activity := range activities {
+		add(projectsByID[activity.ProjectID])
+	}
+	for _, project := range openProjects {
+		add(project)
+	}
+	return quickProjects
+}
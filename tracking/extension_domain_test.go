@@ -0,0 +1,61 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestValidatePageActivity(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	is.NoErr((&PageActivity{Start: start, End: start.Add(time.Hour), Title: "Issue", URL: "https://github.com/baralga/baralga-app/issues/1"}).Validate())
+	is.True((&PageActivity{Start: start, End: start, URL: "https://github.com"}).Validate() != nil)
+	is.True((&PageActivity{Start: start, End: start.Add(time.Hour), URL: "javascript:alert(1)"}).Validate() != nil)
+	is.True((&PageActivity{Start: start, End: start.Add(time.Hour), URL: "https://github.com", Title: strings.Repeat("a", 201)}).Validate() != nil)
+}
+
+func TestPageActivityToActivity(t *testing.T) {
+	is := is.New(t)
+
+	page := &PageActivity{Title: " Issue 1 ", URL: "https://github.com/baralga/baralga-app/issues/1"}
+	is.Equal(page.ToActivity().Description, "Issue 1 - https://github.com/baralga/baralga-app/issues/1")
+
+	page = &PageActivity{URL: "https://github.com"}
+	is.Equal(page.ToActivity().Description, "https://github.com")
+
+	// the url is left out if it doesn't fit
+	page = &PageActivity{Title: "Issue 1", URL: "https://github.com/?q=" + strings.Repeat("a", 500)}
+	is.Equal(page.ToActivity().Description, "Issue 1")
+}
+
+func TestQuickProjectsOf(t *testing.T) {
+	is := is.New(t)
+
+	recent := &Project{ID: uuid.New(), Title: "Recent"}
+	older := &Project{ID: uuid.New(), Title: "Older"}
+	open := &Project{ID: shared.ProjectIDSample, Title: "Open"}
+
+	activities := []*Activity{
+		{ProjectID: recent.ID},
+		{ProjectID: older.ID},
+		{ProjectID: recent.ID},
+	}
+
+	quickProjects := quickProjectsOf(activities, []*Project{older, recent}, []*Project{recent, open})
+	is.Equal(len(quickProjects), 3)
+	is.Equal(quickProjects[0].Title, "Recent")
+	is.Equal(quickProjects[1].Title, "Older")
+	is.Equal(quickProjects[2].Title, "Open")
+
+	var manyProjects []*Project
+	for i := 0; i < 10; i++ {
+		manyProjects = append(manyProjects, &Project{ID: uuid.New()})
+	}
+	is.Equal(len(quickProjectsOf(nil, nil, manyProjects)), maxQuickProjects)
+}
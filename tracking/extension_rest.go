@@ -0,0 +1,145 @@
+package tracking
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type extensionStatusModel struct {
+	Timer    *timerStateModel         `json:"timer"`
+	Projects []*extensionProjectModel `json:"projects"`
+	Links    *hal.Links               `json:"_links"`
+}
+
+type extensionProjectModel struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type pageActivityModel struct {
+	ProjectID string `json:"projectId"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+}
+
+type ExtensionRestHandlers struct {
+	config           *shared.Config
+	extensionService *ExtensionService
+}
+
+func NewExtensionRestHandlers(config *shared.Config, extensionService *ExtensionService) *ExtensionRestHandlers {
+	return &ExtensionRestHandlers{
+		config:           config,
+		extensionService: extensionService,
+	}
+}
+
+func (a *ExtensionRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/extension/status", a.HandleGetExtensionStatus())
+	r.Post("/extension/activities", a.HandleCreatePageActivity())
+}
+
+func (a *ExtensionRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetExtensionStatus reads the timer and the quick projects in one compact payload for the browser extension
+func (a *ExtensionRestHandlers) HandleGetExtensionStatus() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	extensionService := a.extensionService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		status, err := extensionService.ReadExtensionStatus(r.Context(), principal, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		projectModels := make([]*extensionProjectModel, len(status.Projects))
+		for i, project := range status.Projects {
+			projectModels[i] = &extensionProjectModel{
+				ID:    project.ID.String(),
+				Title: project.Title,
+			}
+		}
+
+		shared.RenderJSON(w, &extensionStatusModel{
+			Timer:    mapToTimerStateModel(status.Timer),
+			Projects: projectModels,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+				hal.NewLink("commands", "/api/sync/commands"),
+				hal.NewLink("activities", "/api/extension/activities"),
+			),
+		})
+	}
+}
+
+// HandleCreatePageActivity creates an activity tracked on a web page with the title and url of the page as context
+func (a *ExtensionRestHandlers) HandleCreatePageActivity() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	extensionService := a.extensionService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var pageModel pageActivityModel
+		err := json.NewDecoder(r.Body).Decode(&pageModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "page activity not valid", err)
+			return
+		}
+
+		pageActivity, err := mapToPageActivity(&pageModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "page activity not valid", err)
+			return
+		}
+
+		activity, err := extensionService.CreatePageActivity(r.Context(), principal, pageActivity)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToActivityModel(activity))
+	}
+}
+
+func mapToPageActivity(pageModel *pageActivityModel) (*PageActivity, error) {
+	pageActivity := &PageActivity{
+		Title: pageModel.Title,
+		URL:   pageModel.URL,
+	}
+
+	if pageModel.ProjectID != "" {
+		projectID, err := uuid.Parse(pageModel.ProjectID)
+		if err != nil {
+			return nil, shared.NewInvalidParam("projectId", "uuid", "project id must be a uuid")
+		}
+		pageActivity.ProjectID = projectID
+	}
+
+	start, err := time_utils.ParseDateTime(pageModel.Start)
+	if err != nil {
+		return nil, shared.NewInvalidParam("start", "datetime", "must be a date time like 2021-12-31T10:00:00")
+	}
+	pageActivity.Start = *start
+
+	end, err := time_utils.ParseDateTime(pageModel.End)
+	if err != nil {
+		return nil, shared.NewInvalidParam("end", "datetime", "must be a date time like 2021-12-31T10:00:00")
+	}
+	pageActivity.End = *end
+
+	return pageActivity, pageActivity.Validate()
+}
@@ -0,0 +1,58 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newExtensionServiceForTest() *ExtensionService {
+	syncService := newSyncServiceForTest()
+	return NewExtensionService(syncService, syncService.activityService, NewInMemProjectRepository())
+}
+
+func TestHandleExtension(t *testing.T) {
+	is := is.New(t)
+
+	a := NewExtensionRestHandlers(&shared.Config{}, newExtensionServiceForTest())
+
+	request := func(method, url, body string) *http.Request {
+		r, _ := http.NewRequest(method, url, strings.NewReader(body))
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Roles:          []string{"ROLE_USER"},
+		}))
+	}
+
+	httpRec := httptest.NewRecorder()
+	a.HandleGetExtensionStatus()(httpRec, request("GET", "/api/extension/status", ""))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	statusModel := &extensionStatusModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(statusModel)
+	is.NoErr(err)
+	is.True(!statusModel.Timer.Running)
+	is.Equal(len(statusModel.Projects), 1)
+	is.Equal(statusModel.Projects[0].ID, shared.ProjectIDSample.String())
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreatePageActivity()(httpRec, request("POST", "/api/extension/activities", fmt.Sprintf(`{"projectId": "%s", "start": "2024-03-04T08:00:00", "end": "2024-03-04T09:00:00", "title": "Issue 1", "url": "ftp://example.com"}`, shared.ProjectIDSample)))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreatePageActivity()(httpRec, request("POST", "/api/extension/activities", fmt.Sprintf(`{"projectId": "%s", "start": "2024-03-04T08:00:00", "end": "2024-03-04T09:00:00", "title": "Issue 1", "url": "https://github.com/baralga/baralga-app/issues/1"}`, shared.ProjectIDSample)))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	activityModel := &activityModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(activityModel)
+	is.NoErr(err)
+	is.Equal(activityModel.Description, "Issue 1 - https://github.com/baralga/baralga-app/issues/1")
+}
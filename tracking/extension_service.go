@@ -0,0 +1,61 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/paged"
+)
+
+// ExtensionService serves the browser extension with what it shows and tracks
+type ExtensionService struct {
+	syncService       *SyncService
+	activityService   *ActitivityService
+	projectRepository ProjectRepository
+}
+
+// NewExtensionService creates a new service for the browser extension
+func NewExtensionService(syncService *SyncService, activityService *ActitivityService, projectRepository ProjectRepository) *ExtensionService {
+	return &ExtensionService{
+		syncService:       syncService,
+		activityService:   activityService,
+		projectRepository: projectRepository,
+	}
+}
+
+// ReadExtensionStatus reads the timer of the principal and the projects the principal tracked recently
+func (s *ExtensionService) ReadExtensionStatus(ctx context.Context, principal *shared.Principal, now time.Time) (*ExtensionStatus, error) {
+	timer, err := s.syncService.ReadTimerState(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	activitiesFilter := &ActivitiesFilter{
+		Start:          now.Add(-quickProjectsLookback),
+		End:            now,
+		SortBy:         "start",
+		SortOrder:      SortOrderDesc,
+		Username:       principal.Username,
+		OrganizationID: principal.OrganizationID,
+	}
+	activitiesPaged, projects, err := s.activityService.activityRepository.FindActivities(ctx, activitiesFilter, &paged.PageParams{Page: 0, Size: 50})
+	if err != nil {
+		return nil, err
+	}
+
+	openProjects, err := s.projectRepository.FindProjectsWithStatus(ctx, principal.OrganizationID, []string{ProjectStatusActive}, &paged.PageParams{Page: 0, Size: maxQuickProjects})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExtensionStatus{
+		Timer:    timer,
+		Projects: quickProjectsOf(activitiesPaged.Activities, projects, openProjects.Projects),
+	}, nil
+}
+
+// CreatePageActivity creates the activity tracked on a web page
+func (s *ExtensionService) CreatePageActivity(ctx context.Context, principal *shared.Principal, pageActivity *PageActivity) (*Activity, error) {
+	return s.activityService.CreateActivityFromSource(ctx, principal, pageActivity.ToActivity(), ActivitySourceExtension, true)
+}
@@ -0,0 +1,117 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// recognitionByBudgetTitle is the title of the revenue recognized by the tracked time against the budget
+const recognitionByBudgetTitle = "Percentage of completion"
+
+var (
+	ErrFixedPriceNotFound = shared.NewDomainError("fixed-price:not-found", http.StatusNotFound, "project has no fixed price")
+	ErrMilestoneNotFound  = shared.NewDomainError("milestone:not-found", http.StatusNotFound, "milestone not found")
+)
+
+// FixedPrice is the price of a fixed-price project in the currency of its client, the revenue is
+// recognized when milestones are completed or, without milestones, by the tracked time against the budget
+type FixedPrice struct {
+	OrganizationID uuid.UUID
+	ProjectID      uuid.UUID
+	PriceCents     int
+	ProjectTitle   string
+	BudgetMinutes  int
+	ClientID       *uuid.UUID
+	ClientName     string
+	Currency       string
+}
+
+// Milestone is a deliverable of a fixed-price project with the amount recognized on completion
+type Milestone struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ProjectID      uuid.UUID
+	Title          string
+	Due            time.Time
+	AmountCents    int
+	CompletedAt    *time.Time
+}
+
+// RevenueRecognition is revenue of a fixed-price project recognized on a day
+type RevenueRecognition struct {
+	ProjectID   uuid.UUID
+	Day         time.Time
+	Title       string
+	AmountCents int
+}
+
+type FixedPriceRepository interface {
+	FindFixedPrices(ctx context.Context, organizationID uuid.UUID) ([]*FixedPrice, error)
+	FindFixedPrice(ctx context.Context, organizationID, projectID uuid.UUID) (*FixedPrice, error)
+	UpdateFixedPrice(ctx context.Context, fixedPrice *FixedPrice) error
+	DeleteFixedPrice(ctx context.Context, organizationID, projectID uuid.UUID) error
+	FindMilestones(ctx context.Context, organizationID uuid.UUID) ([]*Milestone, error)
+	FindMilestonesOfProject(ctx context.Context, organizationID, projectID uuid.UUID) ([]*Milestone, error)
+	FindMilestoneByID(ctx context.Context, organizationID, milestoneID uuid.UUID) (*Milestone, error)
+	InsertMilestone(ctx context.Context, milestone *Milestone) error
+	UpdateMilestone(ctx context.Context, milestone *Milestone) error
+	DeleteMilestone(ctx context.Context, organizationID, milestoneID uuid.UUID) error
+	FindTrackedMinutesBefore(ctx context.Context, organizationID uuid.UUID, before time.Time) (map[uuid.UUID]int, error)
+}
+
+// IsCompleted checks whether the milestone is completed
+func (m *Milestone) IsCompleted() bool {
+	return m.CompletedAt != nil
+}
+
+// Recognize is the revenue recognized from start to end, which are the milestones completed in the timespan
+// or, without milestones, the share of the price for the time tracked in the timespan up to the budget
+func (f *FixedPrice) Recognize(start, end time.Time, milestones []*Milestone, minutesBefore, minutesInTimespan int) []*RevenueRecognition {
+	var recognitions []*RevenueRecognition
+
+	if len(milestones) > 0 {
+		for _, milestone := range milestones {
+			if !milestone.IsCompleted() || milestone.CompletedAt.Before(start) || !milestone.CompletedAt.Before(end) {
+				continue
+			}
+			completedAt := *milestone.CompletedAt
+			recognitions = append(recognitions, &RevenueRecognition{
+				ProjectID:   f.ProjectID,
+				Day:         time.Date(completedAt.Year(), completedAt.Month(), completedAt.Day(), 0, 0, 0, 0, completedAt.Location()),
+				Title:       milestone.Title,
+				AmountCents: milestone.AmountCents,
+			})
+		}
+		return recognitions
+	}
+
+	if f.BudgetMinutes <= 0 {
+		return nil
+	}
+
+	completedBefore := min(minutesBefore, f.BudgetMinutes)
+	completedAfter := min(minutesBefore+minutesInTimespan, f.BudgetMinutes)
+	amountCents := (f.PriceCents*completedAfter)/f.BudgetMinutes - (f.PriceCents*completedBefore)/f.BudgetMinutes
+	if amountCents <= 0 {
+		return nil
+	}
+
+	return append(recognitions, &RevenueRecognition{
+		ProjectID:   f.ProjectID,
+		Day:         end.AddDate(0, 0, -1),
+		Title:       recognitionByBudgetTitle,
+		AmountCents: amountCents,
+	})
+}
+
+// sortRevenueRecognitions sorts the recognitions by day
+func sortRevenueRecognitions(recognitions []*RevenueRecognition) {
+	sort.SliceStable(recognitions, func(i, j int) bool {
+		return recognitions[i].Day.Before(recognitions[j].Day)
+	})
+}
@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestFixedPriceRecognize(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("ByMilestones", func(t *testing.T) {
+		completedInMarch := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+		completedInFebruary := time.Date(2024, 2, 28, 10, 0, 0, 0, time.UTC)
+		fixedPrice := &FixedPrice{ProjectID: uuid.New(), PriceCents: 1000000, BudgetMinutes: 6000}
+		milestones := []*Milestone{
+			{Title: "Design", AmountCents: 300000, CompletedAt: &completedInFebruary},
+			{Title: "Build", AmountCents: 500000, CompletedAt: &completedInMarch},
+			{Title: "Launch", AmountCents: 200000},
+		}
+
+		recognitions := fixedPrice.Recognize(start, end, milestones, 3000, 600)
+		is.Equal(len(recognitions), 1)
+		is.Equal(recognitions[0].Title, "Build")
+		is.Equal(recognitions[0].AmountCents, 500000)
+		is.Equal(recognitions[0].Day, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	})
+
+	t.Run("ByBudget", func(t *testing.T) {
+		fixedPrice := &FixedPrice{ProjectID: uuid.New(), PriceCents: 1000000, BudgetMinutes: 6000}
+
+		recognitions := fixedPrice.Recognize(start, end, nil, 3000, 600)
+		is.Equal(len(recognitions), 1)
+		is.Equal(recognitions[0].Title, recognitionByBudgetTitle)
+		is.Equal(recognitions[0].AmountCents, 100000)
+		is.Equal(recognitions[0].Day, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+	})
+
+	t.Run("ByBudgetExceeded", func(t *testing.T) {
+		fixedPrice := &FixedPrice{ProjectID: uuid.New(), PriceCents: 1000000, BudgetMinutes: 6000}
+
+		recognitions := fixedPrice.Recognize(start, end, nil, 5700, 600)
+		is.Equal(len(recognitions), 1)
+		is.Equal(recognitions[0].AmountCents, 50000)
+
+		recognitions = fixedPrice.Recognize(start, end, nil, 6000, 600)
+		is.Equal(len(recognitions), 0)
+	})
+
+	t.Run("WithoutBudget", func(t *testing.T) {
+		fixedPrice := &FixedPrice{ProjectID: uuid.New(), PriceCents: 1000000}
+
+		recognitions := fixedPrice.Recognize(start, end, nil, 0, 600)
+		is.Equal(len(recognitions), 0)
+	})
+}
+
+func TestNewProfitabilityReportWithFixedPrice(t *testing.T) {
+	is := is.New(t)
+
+	clientID := uuid.New()
+	projectID := uuid.New()
+
+	fixedPrices := []*FixedPrice{
+		{ProjectID: projectID, PriceCents: 1000000, ProjectTitle: "Web", BudgetMinutes: 6000, ClientID: &clientID, ClientName: "ACME", Currency: "EUR"},
+	}
+	costRates := []*CostRate{
+		{Username: "user1", HourlyCostCents: 4000},
+	}
+	items := []*ProfitabilityReportItem{
+		{ProjectID: projectID, ProjectTitle: "Web", Billable: true, ClientID: &clientID, ClientName: "ACME", HourlyRateCents: 10000, Currency: "EUR", Username: "user1", DurationInMinutesTotal: 600},
+	}
+	recognitions := []*RevenueRecognition{
+		{ProjectID: projectID, Day: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), Title: recognitionByBudgetTitle, AmountCents: 100000},
+	}
+
+	report := NewProfitabilityReport(time.Time{}, time.Time{}, ProfitabilityByProject, costRates, items, fixedPrices, recognitions)
+	is.Equal(len(report.Lines), 1)
+	is.Equal(report.Lines[0].RevenueCents, 100000)
+	is.Equal(report.Lines[0].CostCents, 40000)
+	is.Equal(len(report.Recognitions), 1)
+
+	t.Run("RecognitionWithoutTime", func(t *testing.T) {
+		report := NewProfitabilityReport(time.Time{}, time.Time{}, ProfitabilityByClient, costRates, nil, fixedPrices, recognitions)
+		is.Equal(len(report.Lines), 1)
+		is.Equal(*report.Lines[0].ID, clientID)
+		is.Equal(report.Lines[0].RevenueCents, 100000)
+		is.Equal(report.Lines[0].CostCents, 0)
+	})
+}
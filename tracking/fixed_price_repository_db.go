@@ -0,0 +1,316 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbFixedPriceRepository is a SQL database repository for fixed-price projects and their milestones
+type DbFixedPriceRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ FixedPriceRepository = (*DbFixedPriceRepository)(nil)
+
+// NewDbFixedPriceRepository creates a new SQL database repository for fixed-price projects
+func NewDbFixedPriceRepository(connPool *pgxpool.Pool) *DbFixedPriceRepository {
+	return &DbFixedPriceRepository{
+		connPool: connPool,
+	}
+}
+
+const fixedPriceSelect = `SELECT f.project_id, f.org_id, f.price_cents, p.title, p.budget_minutes,
+		        c.client_id, COALESCE(c.name, '') as client_name, COALESCE(c.currency, '') as currency
+		 FROM fixed_prices f
+		 INNER JOIN projects p
+		 ON p.project_id = f.project_id
+		 LEFT JOIN clients c
+		 ON c.client_id = p.client_id`
+
+func (r *DbFixedPriceRepository) FindFixedPrices(ctx context.Context, organizationID uuid.UUID) ([]*FixedPrice, error) {
+	rows, err := shared.SelectAll[fixedPriceRow](
+		ctx,
+		r.connPool,
+		fixedPriceSelect+`
+		 WHERE f.org_id = $1
+		 ORDER BY p.title ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fixedPrices := make([]*FixedPrice, len(rows))
+	for i, row := range rows {
+		fixedPrices[i] = row.toFixedPrice()
+	}
+	return fixedPrices, nil
+}
+
+func (r *DbFixedPriceRepository) FindFixedPrice(ctx context.Context, organizationID, projectID uuid.UUID) (*FixedPrice, error) {
+	row, err := shared.SelectOne[fixedPriceRow](
+		ctx,
+		r.connPool,
+		fixedPriceSelect+`
+		 WHERE f.org_id = $1 AND f.project_id = $2`,
+		organizationID, projectID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFixedPriceNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toFixedPrice(), nil
+}
+
+// UpdateFixedPrice sets the fixed price of the project, which makes it a fixed-price project
+func (r *DbFixedPriceRepository) UpdateFixedPrice(ctx context.Context, fixedPrice *FixedPrice) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO fixed_prices
+		   (project_id, org_id, price_cents)
+		 VALUES
+		   ($1, $2, $3)
+		 ON CONFLICT (project_id) DO UPDATE
+		 SET price_cents = EXCLUDED.price_cents`,
+		fixedPrice.ProjectID,
+		fixedPrice.OrganizationID,
+		fixedPrice.PriceCents,
+	)
+	return err
+}
+
+func (r *DbFixedPriceRepository) DeleteFixedPrice(ctx context.Context, organizationID, projectID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM fixed_prices
+		 WHERE project_id = $1 AND org_id = $2`,
+		projectID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrFixedPriceNotFound
+	}
+	return nil
+}
+
+func (r *DbFixedPriceRepository) FindMilestones(ctx context.Context, organizationID uuid.UUID) ([]*Milestone, error) {
+	rows, err := shared.SelectAll[milestoneRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[milestoneRow]()+`
+		 FROM milestones
+		 WHERE org_id = $1
+		 ORDER BY due_day ASC, title ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toMilestones(rows), nil
+}
+
+func (r *DbFixedPriceRepository) FindMilestonesOfProject(ctx context.Context, organizationID, projectID uuid.UUID) ([]*Milestone, error) {
+	rows, err := shared.SelectAll[milestoneRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[milestoneRow]()+`
+		 FROM milestones
+		 WHERE org_id = $1 AND project_id = $2
+		 ORDER BY due_day ASC, title ASC`,
+		organizationID, projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toMilestones(rows), nil
+}
+
+func (r *DbFixedPriceRepository) FindMilestoneByID(ctx context.Context, organizationID, milestoneID uuid.UUID) (*Milestone, error) {
+	row, err := shared.SelectOne[milestoneRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[milestoneRow]()+`
+		 FROM milestones
+		 WHERE milestone_id = $1 AND org_id = $2`,
+		milestoneID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMilestoneNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toMilestone(), nil
+}
+
+func (r *DbFixedPriceRepository) InsertMilestone(ctx context.Context, milestone *Milestone) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO milestones
+		   (milestone_id, org_id, project_id, title, due_day, amount_cents, completed_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7)`,
+		milestone.ID,
+		milestone.OrganizationID,
+		milestone.ProjectID,
+		milestone.Title,
+		milestone.Due,
+		milestone.AmountCents,
+		milestone.CompletedAt,
+	)
+	return err
+}
+
+func (r *DbFixedPriceRepository) UpdateMilestone(ctx context.Context, milestone *Milestone) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE milestones
+		 SET title = $3, due_day = $4, amount_cents = $5, completed_at = $6
+		 WHERE milestone_id = $1 AND org_id = $2`,
+		milestone.ID,
+		milestone.OrganizationID,
+		milestone.Title,
+		milestone.Due,
+		milestone.AmountCents,
+		milestone.CompletedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrMilestoneNotFound
+	}
+	return nil
+}
+
+func (r *DbFixedPriceRepository) DeleteMilestone(ctx context.Context, organizationID, milestoneID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM milestones
+		 WHERE milestone_id = $1 AND org_id = $2`,
+		milestoneID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrMilestoneNotFound
+	}
+	return nil
+}
+
+// FindTrackedMinutesBefore sums up the time tracked for each fixed-price project before the time
+func (r *DbFixedPriceRepository) FindTrackedMinutesBefore(ctx context.Context, organizationID uuid.UUID, before time.Time) (map[uuid.UUID]int, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT f.project_id, sum(ag.duration_minutes_total) as duration_minutes_total
+		 FROM fixed_prices f
+		 INNER JOIN activities_agg ag
+		 ON ag.project_id = f.project_id AND ag.org_id = f.org_id
+		 WHERE f.org_id = $1 AND ag.start_time < $2
+		 GROUP BY f.project_id`,
+		organizationID, before,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trackedMinutes := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var (
+			projectID uuid.UUID
+			minutes   int
+		)
+		err := rows.Scan(&projectID, &minutes)
+		if err != nil {
+			return nil, err
+		}
+		trackedMinutes[projectID] = minutes
+	}
+
+	return trackedMinutes, rows.Err()
+}
+
+type fixedPriceRow struct {
+	ProjectID      uuid.UUID  `db:"project_id"`
+	OrganizationID uuid.UUID  `db:"org_id"`
+	PriceCents     int        `db:"price_cents"`
+	ProjectTitle   string     `db:"title"`
+	BudgetMinutes  int        `db:"budget_minutes"`
+	ClientID       *uuid.UUID `db:"client_id"`
+	ClientName     string     `db:"client_name"`
+	Currency       string     `db:"currency"`
+}
+
+func (r *fixedPriceRow) toFixedPrice() *FixedPrice {
+	return &FixedPrice{
+		OrganizationID: r.OrganizationID,
+		ProjectID:      r.ProjectID,
+		PriceCents:     r.PriceCents,
+		ProjectTitle:   r.ProjectTitle,
+		BudgetMinutes:  r.BudgetMinutes,
+		ClientID:       r.ClientID,
+		ClientName:     r.ClientName,
+		Currency:       r.Currency,
+	}
+}
+
+type milestoneRow struct {
+	ID             uuid.UUID  `db:"milestone_id"`
+	OrganizationID uuid.UUID  `db:"org_id"`
+	ProjectID      uuid.UUID  `db:"project_id"`
+	Title          string     `db:"title"`
+	Due            time.Time  `db:"due_day"`
+	AmountCents    int        `db:"amount_cents"`
+	CompletedAt    *time.Time `db:"completed_at"`
+}
+
+func (r *milestoneRow) toMilestone() *Milestone {
+	return &Milestone{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		ProjectID:      r.ProjectID,
+		Title:          r.Title,
+		Due:            r.Due,
+		AmountCents:    r.AmountCents,
+		CompletedAt:    r.CompletedAt,
+	}
+}
+
+func toMilestones(rows []*milestoneRow) []*Milestone {
+	milestones := make([]*Milestone, len(rows))
+	for i, row := range rows {
+		milestones[i] = row.toMilestone()
+	}
+	return milestones
+}
@@ -0,0 +1,98 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestFixedPriceRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	fixedPriceRepository := NewDbFixedPriceRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("UpdateFixedPrice", func(t *testing.T) {
+		for _, priceCents := range []int{1000000, 1200000} {
+			err := repositoryTxer.InTx(
+				context.Background(),
+				func(ctx context.Context) error {
+					return fixedPriceRepository.UpdateFixedPrice(ctx, &FixedPrice{OrganizationID: shared.OrganizationIDSample, ProjectID: shared.ProjectIDSample, PriceCents: priceCents})
+				},
+			)
+			is.NoErr(err)
+		}
+
+		fixedPrices, err := fixedPriceRepository.FindFixedPrices(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(fixedPrices), 1)
+		is.Equal(fixedPrices[0].PriceCents, 1200000)
+		is.True(fixedPrices[0].ClientID == nil)
+	})
+
+	t.Run("Milestones", func(t *testing.T) {
+		completedAt := time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)
+		milestone := &Milestone{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			ProjectID:      shared.ProjectIDSample,
+			Title:          "Design",
+			Due:            time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			AmountCents:    400000,
+			CompletedAt:    &completedAt,
+		}
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return fixedPriceRepository.InsertMilestone(ctx, milestone)
+			},
+		)
+		is.NoErr(err)
+
+		milestones, err := fixedPriceRepository.FindMilestonesOfProject(context.Background(), shared.OrganizationIDSample, shared.ProjectIDSample)
+		is.NoErr(err)
+		is.Equal(len(milestones), 1)
+		is.True(milestones[0].IsCompleted())
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return fixedPriceRepository.DeleteMilestone(ctx, shared.OrganizationIDSample, milestone.ID)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = fixedPriceRepository.FindMilestoneByID(context.Background(), shared.OrganizationIDSample, milestone.ID)
+		is.Equal(err, ErrMilestoneNotFound)
+	})
+
+	t.Run("FindTrackedMinutesBefore", func(t *testing.T) {
+		// sample activity of 10 minutes on 2021-10-14
+		trackedMinutes, err := fixedPriceRepository.FindTrackedMinutesBefore(context.Background(), shared.OrganizationIDSample, time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC))
+		is.NoErr(err)
+		is.Equal(trackedMinutes[shared.ProjectIDSample], 10)
+	})
+}
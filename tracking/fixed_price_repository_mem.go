@@ -0,0 +1,180 @@
+package tracking
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemFixedPriceRepository struct {
+	mu             sync.Mutex
+	fixedPrices    []*FixedPrice
+	milestones     []*Milestone
+	trackedMinutes map[uuid.UUID]int
+}
+
+var _ FixedPriceRepository = (*InMemFixedPriceRepository)(nil)
+
+func NewInMemFixedPriceRepository() *InMemFixedPriceRepository {
+	return &InMemFixedPriceRepository{
+		trackedMinutes: make(map[uuid.UUID]int),
+	}
+}
+
+func (r *InMemFixedPriceRepository) FindFixedPrices(ctx context.Context, organizationID uuid.UUID) ([]*FixedPrice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var fixedPrices []*FixedPrice
+	for _, fixedPrice := range r.fixedPrices {
+		if fixedPrice.OrganizationID == organizationID {
+			found := *fixedPrice
+			fixedPrices = append(fixedPrices, &found)
+		}
+	}
+	return fixedPrices, nil
+}
+
+func (r *InMemFixedPriceRepository) FindFixedPrice(ctx context.Context, organizationID, projectID uuid.UUID) (*FixedPrice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, fixedPrice := range r.fixedPrices {
+		if fixedPrice.OrganizationID == organizationID && fixedPrice.ProjectID == projectID {
+			found := *fixedPrice
+			return &found, nil
+		}
+	}
+	return nil, ErrFixedPriceNotFound
+}
+
+func (r *InMemFixedPriceRepository) UpdateFixedPrice(ctx context.Context, fixedPrice *FixedPrice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := *fixedPrice
+	for i, f := range r.fixedPrices {
+		if f.OrganizationID == fixedPrice.OrganizationID && f.ProjectID == fixedPrice.ProjectID {
+			r.fixedPrices[i] = &updated
+			return nil
+		}
+	}
+	r.fixedPrices = append(r.fixedPrices, &updated)
+	return nil
+}
+
+func (r *InMemFixedPriceRepository) DeleteFixedPrice(ctx context.Context, organizationID, projectID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, fixedPrice := range r.fixedPrices {
+		if fixedPrice.OrganizationID == organizationID && fixedPrice.ProjectID == projectID {
+			r.fixedPrices = append(r.fixedPrices[:i], r.fixedPrices[i+1:]...)
+
+			var milestones []*Milestone
+			for _, milestone := range r.milestones {
+				if milestone.ProjectID != projectID {
+					milestones = append(milestones, milestone)
+				}
+			}
+			r.milestones = milestones
+			return nil
+		}
+	}
+	return ErrFixedPriceNotFound
+}
+
+func (r *InMemFixedPriceRepository) FindMilestones(ctx context.Context, organizationID uuid.UUID) ([]*Milestone, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.findMilestones(func(milestone *Milestone) bool {
+		return milestone.OrganizationID == organizationID
+	}), nil
+}
+
+func (r *InMemFixedPriceRepository) FindMilestonesOfProject(ctx context.Context, organizationID, projectID uuid.UUID) ([]*Milestone, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.findMilestones(func(milestone *Milestone) bool {
+		return milestone.OrganizationID == organizationID && milestone.ProjectID == projectID
+	}), nil
+}
+
+func (r *InMemFixedPriceRepository) FindMilestoneByID(ctx context.Context, organizationID, milestoneID uuid.UUID) (*Milestone, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, milestone := range r.milestones {
+		if milestone.OrganizationID == organizationID && milestone.ID == milestoneID {
+			found := *milestone
+			return &found, nil
+		}
+	}
+	return nil, ErrMilestoneNotFound
+}
+
+func (r *InMemFixedPriceRepository) InsertMilestone(ctx context.Context, milestone *Milestone) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *milestone
+	r.milestones = append(r.milestones, &inserted)
+	return nil
+}
+
+func (r *InMemFixedPriceRepository) UpdateMilestone(ctx context.Context, milestone *Milestone) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, m := range r.milestones {
+		if m.OrganizationID == milestone.OrganizationID && m.ID == milestone.ID {
+			updated := *milestone
+			r.milestones[i] = &updated
+			return nil
+		}
+	}
+	return ErrMilestoneNotFound
+}
+
+func (r *InMemFixedPriceRepository) DeleteMilestone(ctx context.Context, organizationID, milestoneID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, milestone := range r.milestones {
+		if milestone.OrganizationID == organizationID && milestone.ID == milestoneID {
+			r.milestones = append(r.milestones[:i], r.milestones[i+1:]...)
+			return nil
+		}
+	}
+	return ErrMilestoneNotFound
+}
+
+func (r *InMemFixedPriceRepository) FindTrackedMinutesBefore(ctx context.Context, organizationID uuid.UUID, before time.Time) (map[uuid.UUID]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trackedMinutes := make(map[uuid.UUID]int, len(r.trackedMinutes))
+	for projectID, minutes := range r.trackedMinutes {
+		trackedMinutes[projectID] = minutes
+	}
+	return trackedMinutes, nil
+}
+
+func (r *InMemFixedPriceRepository) findMilestones(matches func(milestone *Milestone) bool) []*Milestone {
+	var milestones []*Milestone
+	for _, milestone := range r.milestones {
+		if matches(milestone) {
+			found := *milestone
+			milestones = append(milestones, &found)
+		}
+	}
+	sort.Slice(milestones, func(i, j int) bool {
+		return milestones[i].Due.Before(milestones[j].Due)
+	})
+	return milestones
+}
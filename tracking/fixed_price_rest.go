@@ -0,0 +1,357 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type fixedPriceModel struct {
+	ProjectID     string     `json:"projectId,omitempty"`
+	PriceCents    int        `json:"priceCents" validate:"min=0,max=10000000000"`
+	BudgetMinutes int        `json:"budgetMinutes"`
+	Currency      string     `json:"currency,omitempty"`
+	Links         *hal.Links `json:"_links,omitempty"`
+}
+
+type milestoneModel struct {
+	ID          string     `json:"id,omitempty"`
+	Title       string     `json:"title" validate:"required,min=3,max=100"`
+	Due         string     `json:"due"`
+	AmountCents int        `json:"amountCents" validate:"min=0,max=10000000000"`
+	CompletedOn string     `json:"completedOn,omitempty"`
+	Links       *hal.Links `json:"_links,omitempty"`
+}
+
+type milestonesModel struct {
+	Embedded struct {
+		MilestoneModels []*milestoneModel `json:"milestones"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type FixedPriceRestHandlers struct {
+	config            *shared.Config
+	fixedPriceService *FixedPriceService
+}
+
+func NewFixedPriceRestHandlers(config *shared.Config, fixedPriceService *FixedPriceService) *FixedPriceRestHandlers {
+	return &FixedPriceRestHandlers{
+		config:            config,
+		fixedPriceService: fixedPriceService,
+	}
+}
+
+func (a *FixedPriceRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/projects/{project-id}/fixed-price", a.HandleGetFixedPrice())
+	r.Put("/projects/{project-id}/fixed-price", a.HandleUpdateFixedPrice())
+	r.Delete("/projects/{project-id}/fixed-price", a.HandleDeleteFixedPrice())
+	r.Get("/projects/{project-id}/milestones", a.HandleGetMilestones())
+	r.Post("/projects/{project-id}/milestones", a.HandleCreateMilestone())
+	r.Put("/projects/{project-id}/milestones/{milestone-id}", a.HandleUpdateMilestone())
+	r.Delete("/projects/{project-id}/milestones/{milestone-id}", a.HandleDeleteMilestone())
+}
+
+func (a *FixedPriceRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetFixedPrice reads the fixed price of a project
+func (a *FixedPriceRestHandlers) HandleGetFixedPrice() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	fixedPriceService := a.fixedPriceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(chi.URLParam(r, "project-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		fixedPrice, err := fixedPriceService.ReadFixedPrice(r.Context(), principal, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToFixedPriceModel(fixedPrice))
+	}
+}
+
+// HandleUpdateFixedPrice sets the fixed price of a project
+func (a *FixedPriceRestHandlers) HandleUpdateFixedPrice() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	fixedPriceService := a.fixedPriceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(chi.URLParam(r, "project-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var fixedPriceModel fixedPriceModel
+		err = json.NewDecoder(r.Body).Decode(&fixedPriceModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "fixed price not valid", err)
+			return
+		}
+
+		err = validator.Struct(fixedPriceModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "fixed price not valid", err)
+			return
+		}
+
+		fixedPrice, err := fixedPriceService.UpdateFixedPrice(r.Context(), principal, &FixedPrice{
+			ProjectID:  projectID,
+			PriceCents: fixedPriceModel.PriceCents,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToFixedPriceModel(fixedPrice))
+	}
+}
+
+// HandleDeleteFixedPrice removes the fixed price and the milestones of a project
+func (a *FixedPriceRestHandlers) HandleDeleteFixedPrice() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	fixedPriceService := a.fixedPriceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(chi.URLParam(r, "project-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = fixedPriceService.DeleteFixedPrice(r.Context(), principal, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleGetMilestones reads the milestones of a fixed-price project
+func (a *FixedPriceRestHandlers) HandleGetMilestones() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	fixedPriceService := a.fixedPriceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(chi.URLParam(r, "project-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		milestones, err := fixedPriceService.ReadMilestones(r.Context(), principal, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		milestoneModels := make([]*milestoneModel, len(milestones))
+		for i, milestone := range milestones {
+			milestoneModels[i] = mapToMilestoneModel(milestone)
+		}
+
+		milestonesModel := &milestonesModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		milestonesModel.Embedded.MilestoneModels = milestoneModels
+
+		shared.RenderJSON(w, milestonesModel)
+	}
+}
+
+// HandleCreateMilestone adds a milestone to a fixed-price project
+func (a *FixedPriceRestHandlers) HandleCreateMilestone() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	fixedPriceService := a.fixedPriceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(chi.URLParam(r, "project-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		milestoneToCreate, ok := decodeMilestone(w, r, validator)
+		if !ok {
+			return
+		}
+		milestoneToCreate.ProjectID = projectID
+
+		milestone, err := fixedPriceService.CreateMilestone(r.Context(), principal, milestoneToCreate)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToMilestoneModel(milestone))
+	}
+}
+
+// HandleUpdateMilestone changes a milestone of a fixed-price project, a milestone with completedOn is completed
+func (a *FixedPriceRestHandlers) HandleUpdateMilestone() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	fixedPriceService := a.fixedPriceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, milestoneID, ok := parseProjectMilestoneIDs(w, r)
+		if !ok {
+			return
+		}
+
+		milestoneToUpdate, ok := decodeMilestone(w, r, validator)
+		if !ok {
+			return
+		}
+		milestoneToUpdate.ID = milestoneID
+		milestoneToUpdate.ProjectID = projectID
+
+		milestone, err := fixedPriceService.UpdateMilestone(r.Context(), principal, milestoneToUpdate)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToMilestoneModel(milestone))
+	}
+}
+
+// HandleDeleteMilestone removes a milestone of a fixed-price project
+func (a *FixedPriceRestHandlers) HandleDeleteMilestone() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	fixedPriceService := a.fixedPriceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, milestoneID, ok := parseProjectMilestoneIDs(w, r)
+		if !ok {
+			return
+		}
+
+		err := fixedPriceService.DeleteMilestone(r.Context(), principal, projectID, milestoneID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func decodeMilestone(w http.ResponseWriter, r *http.Request, validator *validator.Validate) (*Milestone, bool) {
+	var milestoneModel milestoneModel
+	err := json.NewDecoder(r.Body).Decode(&milestoneModel)
+	if err != nil {
+		shared.RenderValidationProblemJSON(w, "milestone not valid", err)
+		return nil, false
+	}
+
+	err = validator.Struct(milestoneModel)
+	if err != nil {
+		shared.RenderValidationProblemJSON(w, "milestone not valid", err)
+		return nil, false
+	}
+
+	due, err := time.Parse("2006-01-02", milestoneModel.Due)
+	if err != nil {
+		shared.RenderValidationProblemJSON(w, "milestone not valid", shared.NewInvalidParam("due", "date", "due must be a date like 2024-03-04"))
+		return nil, false
+	}
+
+	milestone := &Milestone{
+		Title:       milestoneModel.Title,
+		Due:         due,
+		AmountCents: milestoneModel.AmountCents,
+	}
+
+	if milestoneModel.CompletedOn != "" {
+		completedOn, err := time.Parse("2006-01-02", milestoneModel.CompletedOn)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "milestone not valid", shared.NewInvalidParam("completedOn", "date", "completedOn must be a date like 2024-03-04"))
+			return nil, false
+		}
+		milestone.CompletedAt = &completedOn
+	}
+	return milestone, true
+}
+
+func parseProjectMilestoneIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	projectID, err := uuid.Parse(chi.URLParam(r, "project-id"))
+	if err != nil {
+		http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	milestoneID, err := uuid.Parse(chi.URLParam(r, "milestone-id"))
+	if err != nil {
+		http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return projectID, milestoneID, true
+}
+
+func mapToFixedPriceModel(fixedPrice *FixedPrice) *fixedPriceModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/projects/%s/fixed-price", fixedPrice.ProjectID))
+	return &fixedPriceModel{
+		ProjectID:     fixedPrice.ProjectID.String(),
+		PriceCents:    fixedPrice.PriceCents,
+		BudgetMinutes: fixedPrice.BudgetMinutes,
+		Currency:      fixedPrice.Currency,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("milestones", fmt.Sprintf("/api/projects/%s/milestones", fixedPrice.ProjectID)),
+			hal.NewLink("edit", selfLink.Href()),
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+}
+
+func mapToMilestoneModel(milestone *Milestone) *milestoneModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/projects/%s/milestones/%s", milestone.ProjectID, milestone.ID))
+
+	milestoneModel := &milestoneModel{
+		ID:          milestone.ID.String(),
+		Title:       milestone.Title,
+		Due:         time_utils.FormatDate(milestone.Due),
+		AmountCents: milestone.AmountCents,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("edit", selfLink.Href()),
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+	if milestone.IsCompleted() {
+		milestoneModel.CompletedOn = time_utils.FormatDate(*milestone.CompletedAt)
+	}
+	return milestoneModel
+}
@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleFixedPrice(t *testing.T) {
+	is := is.New(t)
+
+	a := NewFixedPriceRestHandlers(&shared.Config{}, NewFixedPriceService(shared.NewInMemRepositoryTxer(), NewInMemFixedPriceRepository(), NewInMemProjectRepository()))
+	admin := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, admin)))
+		})
+	})
+	a.RegisterProtected(router)
+
+	t.Run("UpdateFixedPrice", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/projects/"+shared.ProjectIDSample.String()+"/fixed-price", strings.NewReader(`{"priceCents": 1000000}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		var fixedPriceModel fixedPriceModel
+		err := json.NewDecoder(httpRec.Body).Decode(&fixedPriceModel)
+		is.NoErr(err)
+		is.Equal(fixedPriceModel.PriceCents, 1000000)
+	})
+
+	t.Run("CreateMilestone", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/projects/"+shared.ProjectIDSample.String()+"/milestones", strings.NewReader(`{"title": "Design", "due": "2024-03-15", "amountCents": 400000, "completedOn": "2024-03-14"}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+		var milestoneModel milestoneModel
+		err := json.NewDecoder(httpRec.Body).Decode(&milestoneModel)
+		is.NoErr(err)
+		is.Equal(milestoneModel.Due, "2024-03-15")
+		is.Equal(milestoneModel.CompletedOn, "2024-03-14")
+	})
+
+	t.Run("CreateMilestoneDueNotValid", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/projects/"+shared.ProjectIDSample.String()+"/milestones", strings.NewReader(`{"title": "Design", "due": "15.03.2024"}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("GetMilestones", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/projects/"+shared.ProjectIDSample.String()+"/milestones", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		var milestonesModel milestonesModel
+		err := json.NewDecoder(httpRec.Body).Decode(&milestonesModel)
+		is.NoErr(err)
+		is.Equal(len(milestonesModel.Embedded.MilestoneModels), 1)
+	})
+
+	t.Run("DeleteFixedPrice", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("DELETE", "/projects/"+shared.ProjectIDSample.String()+"/fixed-price", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+
+		httpRec = httptest.NewRecorder()
+		r, _ = http.NewRequest("GET", "/projects/"+shared.ProjectIDSample.String()+"/fixed-price", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,169 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// FixedPriceService manages the fixed prices of projects and their milestones, which only admins may see
+type FixedPriceService struct {
+	repositoryTxer       shared.RepositoryTxer
+	fixedPriceRepository FixedPriceRepository
+	projectRepository    ProjectRepository
+}
+
+// NewFixedPriceService creates a new service for fixed-price projects
+func NewFixedPriceService(repositoryTxer shared.RepositoryTxer, fixedPriceRepository FixedPriceRepository, projectRepository ProjectRepository) *FixedPriceService {
+	return &FixedPriceService{
+		repositoryTxer:       repositoryTxer,
+		fixedPriceRepository: fixedPriceRepository,
+		projectRepository:    projectRepository,
+	}
+}
+
+// ReadFixedPrice reads the fixed price of a project
+func (s *FixedPriceService) ReadFixedPrice(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) (*FixedPrice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.fixedPriceRepository.FindFixedPrice(ctx, principal.OrganizationID, projectID)
+}
+
+// UpdateFixedPrice sets the fixed price of a project, which makes it a fixed-price project
+func (s *FixedPriceService) UpdateFixedPrice(ctx context.Context, principal *shared.Principal, fixedPrice *FixedPrice) (*FixedPrice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.projectRepository.FindProjectByID(ctx, principal.OrganizationID, fixedPrice.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	fixedPrice.OrganizationID = principal.OrganizationID
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.fixedPriceRepository.UpdateFixedPrice(ctx, fixedPrice)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fixedPriceRepository.FindFixedPrice(ctx, principal.OrganizationID, fixedPrice.ProjectID)
+}
+
+// DeleteFixedPrice removes the fixed price and the milestones of a project, which makes it an hourly project again
+func (s *FixedPriceService) DeleteFixedPrice(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.fixedPriceRepository.DeleteFixedPrice(ctx, principal.OrganizationID, projectID)
+		},
+	)
+}
+
+// ReadMilestones reads the milestones of a fixed-price project
+func (s *FixedPriceService) ReadMilestones(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) ([]*Milestone, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.fixedPriceRepository.FindFixedPrice(ctx, principal.OrganizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fixedPriceRepository.FindMilestonesOfProject(ctx, principal.OrganizationID, projectID)
+}
+
+// CreateMilestone adds a milestone to a fixed-price project
+func (s *FixedPriceService) CreateMilestone(ctx context.Context, principal *shared.Principal, milestone *Milestone) (*Milestone, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.fixedPriceRepository.FindFixedPrice(ctx, principal.OrganizationID, milestone.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	milestone.ID = uuid.New()
+	milestone.OrganizationID = principal.OrganizationID
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.fixedPriceRepository.InsertMilestone(ctx, milestone)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// UpdateMilestone changes a milestone of a fixed-price project or marks it as completed
+func (s *FixedPriceService) UpdateMilestone(ctx context.Context, principal *shared.Principal, milestone *Milestone) (*Milestone, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.findMilestoneOfProject(ctx, principal, milestone.ProjectID, milestone.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	milestone.OrganizationID = principal.OrganizationID
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.fixedPriceRepository.UpdateMilestone(ctx, milestone)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// DeleteMilestone removes a milestone of a fixed-price project
+func (s *FixedPriceService) DeleteMilestone(ctx context.Context, principal *shared.Principal, projectID, milestoneID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	_, err := s.findMilestoneOfProject(ctx, principal, projectID, milestoneID)
+	if err != nil {
+		return err
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.fixedPriceRepository.DeleteMilestone(ctx, principal.OrganizationID, milestoneID)
+		},
+	)
+}
+
+func (s *FixedPriceService) findMilestoneOfProject(ctx context.Context, principal *shared.Principal, projectID, milestoneID uuid.UUID) (*Milestone, error) {
+	milestone, err := s.fixedPriceRepository.FindMilestoneByID(ctx, principal.OrganizationID, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	if milestone.ProjectID != projectID {
+		return nil, ErrMilestoneNotFound
+	}
+	return milestone, nil
+}
@@ -0,0 +1,64 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestFixedPriceService(t *testing.T) {
+	is := is.New(t)
+
+	s := NewFixedPriceService(shared.NewInMemRepositoryTxer(), NewInMemFixedPriceRepository(), NewInMemProjectRepository())
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := s.UpdateFixedPrice(context.Background(), user, &FixedPrice{ProjectID: shared.ProjectIDSample, PriceCents: 1000000})
+	is.Equal(err, shared.ErrForbidden)
+
+	_, err = s.CreateMilestone(context.Background(), admin, &Milestone{ProjectID: shared.ProjectIDSample, Title: "Design", Due: time.Now()})
+	is.Equal(err, ErrFixedPriceNotFound)
+
+	_, err = s.UpdateFixedPrice(context.Background(), admin, &FixedPrice{ProjectID: shared.ProjectIDSample, PriceCents: 1000000})
+	is.NoErr(err)
+
+	fixedPrice, err := s.ReadFixedPrice(context.Background(), admin, shared.ProjectIDSample)
+	is.NoErr(err)
+	is.Equal(fixedPrice.PriceCents, 1000000)
+
+	t.Run("Milestones", func(t *testing.T) {
+		milestone, err := s.CreateMilestone(context.Background(), admin, &Milestone{ProjectID: shared.ProjectIDSample, Title: "Design", Due: time.Now(), AmountCents: 400000})
+		is.NoErr(err)
+
+		completedAt := time.Now()
+		milestone.CompletedAt = &completedAt
+		_, err = s.UpdateMilestone(context.Background(), admin, milestone)
+		is.NoErr(err)
+
+		milestones, err := s.ReadMilestones(context.Background(), admin, shared.ProjectIDSample)
+		is.NoErr(err)
+		is.Equal(len(milestones), 1)
+		is.True(milestones[0].IsCompleted())
+
+		err = s.DeleteMilestone(context.Background(), admin, uuid.New(), milestone.ID)
+		is.Equal(err, ErrMilestoneNotFound)
+
+		err = s.DeleteMilestone(context.Background(), admin, shared.ProjectIDSample, milestone.ID)
+		is.NoErr(err)
+	})
+
+	t.Run("ProjectNotFound", func(t *testing.T) {
+		_, err := s.UpdateFixedPrice(context.Background(), admin, &FixedPrice{ProjectID: uuid.New(), PriceCents: 1000000})
+		is.True(err != nil)
+	})
+
+	err = s.DeleteFixedPrice(context.Background(), admin, shared.ProjectIDSample)
+	is.NoErr(err)
+
+	_, err = s.ReadFixedPrice(context.Background(), admin, shared.ProjectIDSample)
+	is.Equal(err, ErrFixedPriceNotFound)
+}
@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrGoalNotFound = shared.NewDomainError("goal:not-found", http.StatusNotFound, "goal not found")
+	ErrGoalArchived = shared.NewDomainError("goal:archived", http.StatusConflict, "archived goals cannot be linked to activities")
+)
+
+// Goal is a goal or OKR of an organization, activities are linked to goals to see where effort goes
+type Goal struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Title          string
+	Description    string
+	Archived       bool
+	CreatedAt      time.Time
+}
+
+// GoalReportItem is the time of the activities linked to a goal in a quarter
+type GoalReportItem struct {
+	GoalID                 uuid.UUID
+	Year                   int
+	Quarter                int
+	DurationInMinutesTotal int
+}
+
+// GoalQuarters is the time spent on a goal in each quarter of a year
+type GoalQuarters struct {
+	Goal           *Goal
+	QuarterMinutes [4]int
+}
+
+// GoalReport is the time spent per goal in each quarter of a year
+type GoalReport struct {
+	Year  int
+	Goals []*GoalQuarters
+}
+
+type GoalRepository interface {
+	FindGoals(ctx context.Context, organizationID uuid.UUID) ([]*Goal, error)
+	FindGoalByID(ctx context.Context, organizationID, goalID uuid.UUID) (*Goal, error)
+	InsertGoal(ctx context.Context, goal *Goal) error
+	UpdateGoal(ctx context.Context, goal *Goal) error
+	DeleteGoal(ctx context.Context, organizationID, goalID uuid.UUID) error
+	FindGoalsOfActivity(ctx context.Context, organizationID, activityID uuid.UUID) ([]*Goal, error)
+	InsertActivityGoal(ctx context.Context, organizationID, activityID, goalID uuid.UUID) error
+	DeleteActivityGoal(ctx context.Context, organizationID, activityID, goalID uuid.UUID) error
+	GoalReport(ctx context.Context, organizationID uuid.UUID, year int) ([]*GoalReportItem, error)
+}
+
+// TotalMinutes is the time spent on the goal in the year
+func (g *GoalQuarters) TotalMinutes() int {
+	total := 0
+	for _, minutes := range g.QuarterMinutes {
+		total += minutes
+	}
+	return total
+}
+
+// NewGoalReport sums up the time per goal and quarter, archived goals without time in the year are left out
+func NewGoalReport(year int, goals []*Goal, items []*GoalReportItem) *GoalReport {
+	report := &GoalReport{
+		Year: year,
+	}
+
+	quartersByGoal := make(map[uuid.UUID]*GoalQuarters, len(goals))
+	for _, goal := range goals {
+		quartersByGoal[goal.ID] = &GoalQuarters{Goal: goal}
+	}
+
+	for _, item := range items {
+		goalQuarters, ok := quartersByGoal[item.GoalID]
+		if !ok || item.Year != year || item.Quarter < 1 || item.Quarter > 4 {
+			continue
+		}
+		goalQuarters.QuarterMinutes[item.Quarter-1] += item.DurationInMinutesTotal
+	}
+
+	for _, goal := range goals {
+		goalQuarters := quartersByGoal[goal.ID]
+		if goal.Archived && goalQuarters.TotalMinutes() == 0 {
+			continue
+		}
+		report.Goals = append(report.Goals, goalQuarters)
+	}
+	return report
+}
@@ -0,0 +1,31 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestNewGoalReport(t *testing.T) {
+	is := is.New(t)
+
+	goal := &Goal{ID: uuid.New(), Title: "Grow revenue"}
+	archivedGoal := &Goal{ID: uuid.New(), Title: "Old goal", Archived: true}
+	archivedGoalWithTime := &Goal{ID: uuid.New(), Title: "Done goal", Archived: true}
+
+	items := []*GoalReportItem{
+		{GoalID: goal.ID, Year: 2024, Quarter: 1, DurationInMinutesTotal: 60},
+		{GoalID: goal.ID, Year: 2024, Quarter: 3, DurationInMinutesTotal: 90},
+		{GoalID: goal.ID, Year: 2023, Quarter: 3, DurationInMinutesTotal: 30},
+		{GoalID: archivedGoalWithTime.ID, Year: 2024, Quarter: 2, DurationInMinutesTotal: 45},
+		{GoalID: uuid.New(), Year: 2024, Quarter: 2, DurationInMinutesTotal: 15},
+	}
+
+	report := NewGoalReport(2024, []*Goal{goal, archivedGoal, archivedGoalWithTime}, items)
+	is.Equal(report.Year, 2024)
+	is.Equal(len(report.Goals), 2)
+	is.Equal(report.Goals[0].QuarterMinutes, [4]int{60, 0, 90, 0})
+	is.Equal(report.Goals[0].TotalMinutes(), 150)
+	is.Equal(report.Goals[1].Goal, archivedGoalWithTime)
+}
@@ -0,0 +1,242 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbGoalRepository is a SQL database repository for goals and the activities linked to them
+type DbGoalRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ GoalRepository = (*DbGoalRepository)(nil)
+
+// NewDbGoalRepository creates a new SQL database repository for goals
+func NewDbGoalRepository(connPool *pgxpool.Pool) *DbGoalRepository {
+	return &DbGoalRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbGoalRepository) FindGoals(ctx context.Context, organizationID uuid.UUID) ([]*Goal, error) {
+	rows, err := shared.SelectAll[goalRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[goalRow]()+`
+		 FROM goals
+		 WHERE org_id = $1
+		 ORDER BY archived ASC, title ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGoals(rows), nil
+}
+
+func (r *DbGoalRepository) FindGoalByID(ctx context.Context, organizationID, goalID uuid.UUID) (*Goal, error) {
+	row, err := shared.SelectOne[goalRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[goalRow]()+`
+		 FROM goals
+		 WHERE goal_id = $1 AND org_id = $2`,
+		goalID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrGoalNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toGoal(), nil
+}
+
+func (r *DbGoalRepository) InsertGoal(ctx context.Context, goal *Goal) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO goals
+		   (goal_id, org_id, title, description, archived, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6)`,
+		goal.ID,
+		goal.OrganizationID,
+		goal.Title,
+		goal.Description,
+		goal.Archived,
+		goal.CreatedAt,
+	)
+	return err
+}
+
+func (r *DbGoalRepository) UpdateGoal(ctx context.Context, goal *Goal) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE goals
+		 SET title = $3, description = $4, archived = $5
+		 WHERE goal_id = $1 AND org_id = $2`,
+		goal.ID,
+		goal.OrganizationID,
+		goal.Title,
+		goal.Description,
+		goal.Archived,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+func (r *DbGoalRepository) DeleteGoal(ctx context.Context, organizationID, goalID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM goals
+		 WHERE goal_id = $1 AND org_id = $2`,
+		goalID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+func (r *DbGoalRepository) FindGoalsOfActivity(ctx context.Context, organizationID, activityID uuid.UUID) ([]*Goal, error) {
+	rows, err := shared.SelectAll[goalRow](
+		ctx,
+		r.connPool,
+		`SELECT g.goal_id, g.org_id, g.title, g.description, g.archived, g.created_at
+		 FROM goals g
+		 JOIN activity_goals ag ON ag.goal_id = g.goal_id
+		 WHERE ag.org_id = $1 AND ag.activity_id = $2
+		 ORDER BY g.title ASC`,
+		organizationID, activityID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGoals(rows), nil
+}
+
+// InsertActivityGoal links the activity to the goal, linking it again has no effect
+func (r *DbGoalRepository) InsertActivityGoal(ctx context.Context, organizationID, activityID, goalID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO activity_goals
+		   (activity_id, goal_id, org_id)
+		 VALUES
+		   ($1, $2, $3)
+		 ON CONFLICT (activity_id, goal_id) DO NOTHING`,
+		activityID, goalID, organizationID,
+	)
+	return err
+}
+
+func (r *DbGoalRepository) DeleteActivityGoal(ctx context.Context, organizationID, activityID, goalID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM activity_goals
+		 WHERE activity_id = $1 AND goal_id = $2 AND org_id = $3`,
+		activityID, goalID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+// GoalReport sums up the time of the activities linked to each goal per quarter of the year
+func (r *DbGoalRepository) GoalReport(ctx context.Context, organizationID uuid.UUID, year int) ([]*GoalReportItem, error) {
+	rows, err := shared.SelectAll[goalReportRow](
+		ctx,
+		r.connPool,
+		`SELECT ag.goal_id, a.year, a.quarter, sum(a.duration_minutes_total) as duration_minutes_total
+		 FROM activity_goals ag
+		 JOIN activities_agg a ON a.activity_id = ag.activity_id
+		 WHERE ag.org_id = $1 AND a.year = $2
+		 GROUP BY ag.goal_id, a.year, a.quarter
+		 ORDER BY a.quarter ASC`,
+		organizationID, year,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*GoalReportItem, len(rows))
+	for i, row := range rows {
+		items[i] = &GoalReportItem{
+			GoalID:                 row.GoalID,
+			Year:                   row.Year,
+			Quarter:                row.Quarter,
+			DurationInMinutesTotal: row.DurationInMinutesTotal,
+		}
+	}
+	return items, nil
+}
+
+type goalRow struct {
+	ID             uuid.UUID `db:"goal_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	Title          string    `db:"title"`
+	Description    string    `db:"description"`
+	Archived       bool      `db:"archived"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+type goalReportRow struct {
+	GoalID                 uuid.UUID `db:"goal_id"`
+	Year                   int       `db:"year"`
+	Quarter                int       `db:"quarter"`
+	DurationInMinutesTotal int       `db:"duration_minutes_total"`
+}
+
+func (r *goalRow) toGoal() *Goal {
+	return &Goal{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Title:          r.Title,
+		Description:    r.Description,
+		Archived:       r.Archived,
+		CreatedAt:      r.CreatedAt,
+	}
+}
+
+func toGoals(rows []*goalRow) []*Goal {
+	goals := make([]*Goal, len(rows))
+	for i, row := range rows {
+		goals[i] = row.toGoal()
+	}
+	return goals
+}
@@ -0,0 +1,83 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestGoalRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	goalRepository := NewDbGoalRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	// sample activity of 10 minutes on 2021-10-14
+	activityID := uuid.MustParse("2a52852c-3f36-11ec-9bbc-0242ac130002")
+
+	goal := &Goal{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Title:          "Grow revenue",
+		CreatedAt:      time.Now(),
+	}
+
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return goalRepository.InsertGoal(ctx, goal)
+		},
+		func(ctx context.Context) error {
+			return goalRepository.InsertActivityGoal(ctx, shared.OrganizationIDSample, activityID, goal.ID)
+		},
+		func(ctx context.Context) error {
+			return goalRepository.InsertActivityGoal(ctx, shared.OrganizationIDSample, activityID, goal.ID)
+		},
+	)
+	is.NoErr(err)
+
+	goals, err := goalRepository.FindGoalsOfActivity(context.Background(), shared.OrganizationIDSample, activityID)
+	is.NoErr(err)
+	is.Equal(len(goals), 1)
+
+	items, err := goalRepository.GoalReport(context.Background(), shared.OrganizationIDSample, 2021)
+	is.NoErr(err)
+	is.Equal(len(items), 1)
+	is.Equal(items[0].Quarter, 4)
+	is.Equal(items[0].DurationInMinutesTotal, 10)
+
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return goalRepository.DeleteGoal(ctx, shared.OrganizationIDSample, goal.ID)
+		},
+	)
+	is.NoErr(err)
+
+	goals, err = goalRepository.FindGoalsOfActivity(context.Background(), shared.OrganizationIDSample, activityID)
+	is.NoErr(err)
+	is.Equal(len(goals), 0)
+}
@@ -0,0 +1,170 @@
+package tracking
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemGoalRepository struct {
+	mu            sync.Mutex
+	goals         []*Goal
+	activityGoals []*activityGoal
+}
+
+type activityGoal struct {
+	organizationID uuid.UUID
+	activityID     uuid.UUID
+	goalID         uuid.UUID
+}
+
+var _ GoalRepository = (*InMemGoalRepository)(nil)
+
+func NewInMemGoalRepository() *InMemGoalRepository {
+	return &InMemGoalRepository{}
+}
+
+func (r *InMemGoalRepository) FindGoals(ctx context.Context, organizationID uuid.UUID) ([]*Goal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var goals []*Goal
+	for _, goal := range r.goals {
+		if goal.OrganizationID == organizationID {
+			found := *goal
+			goals = append(goals, &found)
+		}
+	}
+	sort.Slice(goals, func(i, j int) bool {
+		return goals[i].Title < goals[j].Title
+	})
+	return goals, nil
+}
+
+func (r *InMemGoalRepository) FindGoalByID(ctx context.Context, organizationID, goalID uuid.UUID) (*Goal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, goal := range r.goals {
+		if goal.OrganizationID == organizationID && goal.ID == goalID {
+			found := *goal
+			return &found, nil
+		}
+	}
+	return nil, ErrGoalNotFound
+}
+
+func (r *InMemGoalRepository) InsertGoal(ctx context.Context, goal *Goal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *goal
+	r.goals = append(r.goals, &inserted)
+	return nil
+}
+
+func (r *InMemGoalRepository) UpdateGoal(ctx context.Context, goal *Goal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, g := range r.goals {
+		if g.OrganizationID == goal.OrganizationID && g.ID == goal.ID {
+			updated := *goal
+			updated.CreatedAt = g.CreatedAt
+			r.goals[i] = &updated
+			return nil
+		}
+	}
+	return ErrGoalNotFound
+}
+
+func (r *InMemGoalRepository) DeleteGoal(ctx context.Context, organizationID, goalID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, goal := range r.goals {
+		if goal.OrganizationID == organizationID && goal.ID == goalID {
+			r.goals = append(r.goals[:i], r.goals[i+1:]...)
+
+			var activityGoals []*activityGoal
+			for _, ag := range r.activityGoals {
+				if ag.goalID != goalID {
+					activityGoals = append(activityGoals, ag)
+				}
+			}
+			r.activityGoals = activityGoals
+			return nil
+		}
+	}
+	return ErrGoalNotFound
+}
+
+func (r *InMemGoalRepository) FindGoalsOfActivity(ctx context.Context, organizationID, activityID uuid.UUID) ([]*Goal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var goals []*Goal
+	for _, ag := range r.activityGoals {
+		if ag.organizationID != organizationID || ag.activityID != activityID {
+			continue
+		}
+		for _, goal := range r.goals {
+			if goal.ID == ag.goalID {
+				found := *goal
+				goals = append(goals, &found)
+			}
+		}
+	}
+	return goals, nil
+}
+
+func (r *InMemGoalRepository) InsertActivityGoal(ctx context.Context, organizationID, activityID, goalID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ag := range r.activityGoals {
+		if ag.activityID == activityID && ag.goalID == goalID {
+			return nil
+		}
+	}
+
+	r.activityGoals = append(r.activityGoals, &activityGoal{
+		organizationID: organizationID,
+		activityID:     activityID,
+		goalID:         goalID,
+	})
+	return nil
+}
+
+func (r *InMemGoalRepository) DeleteActivityGoal(ctx context.Context, organizationID, activityID, goalID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, ag := range r.activityGoals {
+		if ag.organizationID == organizationID && ag.activityID == activityID && ag.goalID == goalID {
+			r.activityGoals = append(r.activityGoals[:i], r.activityGoals[i+1:]...)
+			return nil
+		}
+	}
+	return ErrGoalNotFound
+}
+
+func (r *InMemGoalRepository) GoalReport(ctx context.Context, organizationID uuid.UUID, year int) ([]*GoalReportItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var items []*GoalReportItem
+	for _, ag := range r.activityGoals {
+		if ag.organizationID == organizationID {
+			items = append(items, &GoalReportItem{
+				GoalID:                 ag.goalID,
+				Year:                   year,
+				Quarter:                1,
+				DurationInMinutesTotal: 60,
+			})
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,349 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type goalModel struct {
+	ID          string     `json:"id,omitempty"`
+	Title       string     `json:"title" validate:"required,min=3,max=100"`
+	Description string     `json:"description" validate:"max=500"`
+	Archived    bool       `json:"archived"`
+	Links       *hal.Links `json:"_links,omitempty"`
+}
+
+type goalsModel struct {
+	Embedded struct {
+		GoalModels []*goalModel `json:"goals"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type goalReportItemModel struct {
+	GoalID         string `json:"goalId"`
+	Title          string `json:"title"`
+	Archived       bool   `json:"archived"`
+	QuarterMinutes [4]int `json:"quarterMinutes"`
+	TotalMinutes   int    `json:"totalMinutes"`
+}
+
+type goalReportModel struct {
+	Year  int                    `json:"year"`
+	Goals []*goalReportItemModel `json:"goals"`
+	Links *hal.Links             `json:"_links"`
+}
+
+type GoalRestHandlers struct {
+	config      *shared.Config
+	goalService *GoalService
+}
+
+func NewGoalRestHandlers(config *shared.Config, goalService *GoalService) *GoalRestHandlers {
+	return &GoalRestHandlers{
+		config:      config,
+		goalService: goalService,
+	}
+}
+
+func (a *GoalRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/goals", a.HandleGetGoals())
+	r.Post("/goals", a.HandleCreateGoal())
+	r.Get("/goals/report", a.HandleGetGoalReport())
+	r.Put("/goals/{goal-id}", a.HandleUpdateGoal())
+	r.Delete("/goals/{goal-id}", a.HandleDeleteGoal())
+	r.Get("/activities/{activity-id}/goals", a.HandleGetGoalsOfActivity())
+	r.Put("/activities/{activity-id}/goals/{goal-id}", a.HandleLinkActivityToGoal())
+	r.Delete("/activities/{activity-id}/goals/{goal-id}", a.HandleUnlinkActivityFromGoal())
+}
+
+func (a *GoalRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetGoals reads the goals of the organization
+func (a *GoalRestHandlers) HandleGetGoals() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	goalService := a.goalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		goals, err := goalService.ReadGoals(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToGoalsModel(principal, goals, r.RequestURI))
+	}
+}
+
+// HandleCreateGoal adds a goal to the organization
+func (a *GoalRestHandlers) HandleCreateGoal() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	goalService := a.goalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var goalModel goalModel
+		err := json.NewDecoder(r.Body).Decode(&goalModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "goal not valid", err)
+			return
+		}
+
+		err = validator.Struct(goalModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "goal not valid", err)
+			return
+		}
+
+		goal, err := goalService.CreateGoal(r.Context(), principal, &Goal{
+			Title:       goalModel.Title,
+			Description: goalModel.Description,
+			Archived:    goalModel.Archived,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToGoalModel(principal, goal))
+	}
+}
+
+// HandleUpdateGoal changes a goal
+func (a *GoalRestHandlers) HandleUpdateGoal() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	goalService := a.goalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		goalID, err := uuid.Parse(chi.URLParam(r, "goal-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var goalModel goalModel
+		err = json.NewDecoder(r.Body).Decode(&goalModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "goal not valid", err)
+			return
+		}
+
+		err = validator.Struct(goalModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "goal not valid", err)
+			return
+		}
+
+		goal, err := goalService.UpdateGoal(r.Context(), principal, &Goal{
+			ID:          goalID,
+			Title:       goalModel.Title,
+			Description: goalModel.Description,
+			Archived:    goalModel.Archived,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToGoalModel(principal, goal))
+	}
+}
+
+// HandleDeleteGoal removes a goal
+func (a *GoalRestHandlers) HandleDeleteGoal() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	goalService := a.goalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		goalID, err := uuid.Parse(chi.URLParam(r, "goal-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = goalService.DeleteGoal(r.Context(), principal, goalID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleGetGoalReport reports the time spent per goal in each quarter of a year, by default the current year
+func (a *GoalRestHandlers) HandleGetGoalReport() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	goalService := a.goalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		year := time.Now().Year()
+		if yearParam := r.URL.Query().Get("year"); yearParam != "" {
+			y, err := strconv.Atoi(yearParam)
+			if err != nil || y < 1900 || y > 9999 {
+				shared.RenderValidationProblemJSON(w, "year not valid", shared.NewInvalidParam("year", "number", "year must be a year like 2024"))
+				return
+			}
+			year = y
+		}
+
+		report, err := goalService.GoalReport(r.Context(), principal, year)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		reportModel := &goalReportModel{
+			Year:  report.Year,
+			Goals: make([]*goalReportItemModel, len(report.Goals)),
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		for i, goalQuarters := range report.Goals {
+			reportModel.Goals[i] = &goalReportItemModel{
+				GoalID:         goalQuarters.Goal.ID.String(),
+				Title:          goalQuarters.Goal.Title,
+				Archived:       goalQuarters.Goal.Archived,
+				QuarterMinutes: goalQuarters.QuarterMinutes,
+				TotalMinutes:   goalQuarters.TotalMinutes(),
+			}
+		}
+
+		shared.RenderJSON(w, reportModel)
+	}
+}
+
+// HandleGetGoalsOfActivity reads the goals an activity is linked to
+func (a *GoalRestHandlers) HandleGetGoalsOfActivity() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	goalService := a.goalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, err := uuid.Parse(chi.URLParam(r, "activity-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		goals, err := goalService.ReadGoalsOfActivity(r.Context(), principal, activityID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToGoalsModel(principal, goals, r.RequestURI))
+	}
+}
+
+// HandleLinkActivityToGoal links an activity to a goal
+func (a *GoalRestHandlers) HandleLinkActivityToGoal() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	goalService := a.goalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, goalID, ok := parseActivityGoalIDs(w, r)
+		if !ok {
+			return
+		}
+
+		err := goalService.LinkActivityToGoal(r.Context(), principal, activityID, goalID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleUnlinkActivityFromGoal removes the link of an activity to a goal
+func (a *GoalRestHandlers) HandleUnlinkActivityFromGoal() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	goalService := a.goalService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, goalID, ok := parseActivityGoalIDs(w, r)
+		if !ok {
+			return
+		}
+
+		err := goalService.UnlinkActivityFromGoal(r.Context(), principal, activityID, goalID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func parseActivityGoalIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	activityID, err := uuid.Parse(chi.URLParam(r, "activity-id"))
+	if err != nil {
+		http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	goalID, err := uuid.Parse(chi.URLParam(r, "goal-id"))
+	if err != nil {
+		http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return activityID, goalID, true
+}
+
+func mapToGoalsModel(principal *shared.Principal, goals []*Goal, selfHref string) *goalsModel {
+	goalModels := make([]*goalModel, len(goals))
+	for i, goal := range goals {
+		goalModels[i] = mapToGoalModel(principal, goal)
+	}
+
+	goalsModel := &goalsModel{
+		Links: hal.NewLinks(
+			hal.NewSelfLink(selfHref),
+		),
+	}
+	goalsModel.Embedded.GoalModels = goalModels
+	return goalsModel
+}
+
+func mapToGoalModel(principal *shared.Principal, goal *Goal) *goalModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/goals/%s", goal.ID))
+	links := hal.NewLinks(selfLink)
+	if principal.HasRole("ROLE_ADMIN") {
+		links = hal.NewLinks(
+			selfLink,
+			hal.NewLink("edit", selfLink.Href()),
+			hal.NewLink("delete", selfLink.Href()),
+		)
+	}
+
+	return &goalModel{
+		ID:          goal.ID.String(),
+		Title:       goal.Title,
+		Description: goal.Description,
+		Archived:    goal.Archived,
+		Links:       links,
+	}
+}
@@ -0,0 +1,101 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleCreateGoal(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewGoalRestHandlers(&shared.Config{}, newGoalServiceForTest())
+
+	r, _ := http.NewRequest("POST", "/api/goals", strings.NewReader(`{"title": "Grow revenue"}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleCreateGoal()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	var goalModel goalModel
+	err := json.NewDecoder(httpRec.Body).Decode(&goalModel)
+	is.NoErr(err)
+	is.Equal(goalModel.Title, "Grow revenue")
+}
+
+func TestHandleCreateGoalNotValid(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewGoalRestHandlers(&shared.Config{}, newGoalServiceForTest())
+
+	r, _ := http.NewRequest("POST", "/api/goals", strings.NewReader(`{"title": ""}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleCreateGoal()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleGoalReport(t *testing.T) {
+	is := is.New(t)
+
+	goalService := newGoalServiceForTest()
+	admin := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+	goal, err := goalService.CreateGoal(context.Background(), admin, &Goal{Title: "Grow revenue"})
+	is.NoErr(err)
+
+	a := NewGoalRestHandlers(&shared.Config{}, goalService)
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, admin)))
+		})
+	})
+	a.RegisterProtected(router)
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("PUT", "/activities/"+activityIDGoalSample.String()+"/goals/"+goal.ID.String(), nil)
+	router.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNoContent)
+
+	t.Run("Year", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/goals/report?year=2024", nil)
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		var reportModel goalReportModel
+		err := json.NewDecoder(httpRec.Body).Decode(&reportModel)
+		is.NoErr(err)
+		is.Equal(reportModel.Year, 2024)
+		is.Equal(len(reportModel.Goals), 1)
+		is.Equal(reportModel.Goals[0].TotalMinutes, 60)
+	})
+
+	t.Run("YearNotValid", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/goals/report?year=last", nil)
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+}
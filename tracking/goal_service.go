@@ -0,0 +1,171 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// GoalService manages the goals of an organization, links activities to them and reports the time per goal
+type GoalService struct {
+	repositoryTxer     shared.RepositoryTxer
+	goalRepository     GoalRepository
+	activityRepository ActivityRepository
+}
+
+// NewGoalService creates a new service for goals
+func NewGoalService(repositoryTxer shared.RepositoryTxer, goalRepository GoalRepository, activityRepository ActivityRepository) *GoalService {
+	return &GoalService{
+		repositoryTxer:     repositoryTxer,
+		goalRepository:     goalRepository,
+		activityRepository: activityRepository,
+	}
+}
+
+// ReadGoals reads the goals of the organization
+func (s *GoalService) ReadGoals(ctx context.Context, principal *shared.Principal) ([]*Goal, error) {
+	return s.goalRepository.FindGoals(ctx, principal.OrganizationID)
+}
+
+// CreateGoal adds a goal to the organization
+func (s *GoalService) CreateGoal(ctx context.Context, principal *shared.Principal, goal *Goal) (*Goal, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	goal.ID = uuid.New()
+	goal.OrganizationID = principal.OrganizationID
+	goal.CreatedAt = time.Now()
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.goalRepository.InsertGoal(ctx, goal)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// UpdateGoal changes the title and description of a goal or archives it
+func (s *GoalService) UpdateGoal(ctx context.Context, principal *shared.Principal, goal *Goal) (*Goal, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	currentGoal, err := s.goalRepository.FindGoalByID(ctx, principal.OrganizationID, goal.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	goal.OrganizationID = principal.OrganizationID
+	goal.CreatedAt = currentGoal.CreatedAt
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.goalRepository.UpdateGoal(ctx, goal)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// DeleteGoal removes a goal and unlinks its activities
+func (s *GoalService) DeleteGoal(ctx context.Context, principal *shared.Principal, goalID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.goalRepository.DeleteGoal(ctx, principal.OrganizationID, goalID)
+		},
+	)
+}
+
+// ReadGoalsOfActivity reads the goals an activity is linked to
+func (s *GoalService) ReadGoalsOfActivity(ctx context.Context, principal *shared.Principal, activityID uuid.UUID) ([]*Goal, error) {
+	err := s.checkActivity(ctx, principal, activityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.goalRepository.FindGoalsOfActivity(ctx, principal.OrganizationID, activityID)
+}
+
+// LinkActivityToGoal links an activity of the user to a goal which is not archived, admins link all activities
+func (s *GoalService) LinkActivityToGoal(ctx context.Context, principal *shared.Principal, activityID, goalID uuid.UUID) error {
+	err := s.checkActivity(ctx, principal, activityID)
+	if err != nil {
+		return err
+	}
+
+	goal, err := s.goalRepository.FindGoalByID(ctx, principal.OrganizationID, goalID)
+	if err != nil {
+		return err
+	}
+	if goal.Archived {
+		return ErrGoalArchived
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.goalRepository.InsertActivityGoal(ctx, principal.OrganizationID, activityID, goalID)
+		},
+	)
+}
+
+// UnlinkActivityFromGoal removes the link of an activity to a goal
+func (s *GoalService) UnlinkActivityFromGoal(ctx context.Context, principal *shared.Principal, activityID, goalID uuid.UUID) error {
+	err := s.checkActivity(ctx, principal, activityID)
+	if err != nil {
+		return err
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.goalRepository.DeleteActivityGoal(ctx, principal.OrganizationID, activityID, goalID)
+		},
+	)
+}
+
+// GoalReport reports the time spent per goal in each quarter of the year, which only admins may see
+func (s *GoalService) GoalReport(ctx context.Context, principal *shared.Principal, year int) (*GoalReport, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	goals, err := s.goalRepository.FindGoals(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.goalRepository.GoalReport(ctx, principal.OrganizationID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGoalReport(year, goals, items), nil
+}
+
+// checkActivity checks whether the activity is one of the user or the user is an admin
+func (s *GoalService) checkActivity(ctx context.Context, principal *shared.Principal, activityID uuid.UUID) error {
+	activity, err := s.activityRepository.FindActivityByID(ctx, activityID, principal.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if activity.Username != principal.Username && !principal.HasRole("ROLE_ADMIN") {
+		return ErrActivityNotFound
+	}
+	return nil
+}
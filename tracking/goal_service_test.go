@@ -0,0 +1,77 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+var activityIDGoalSample = uuid.MustParse("00000000-0000-0000-2222-000000000001")
+
+func newGoalServiceForTest() *GoalService {
+	return NewGoalService(shared.NewInMemRepositoryTxer(), NewInMemGoalRepository(), NewInMemActivityRepository())
+}
+
+func TestGoals(t *testing.T) {
+	is := is.New(t)
+
+	s := newGoalServiceForTest()
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := s.CreateGoal(context.Background(), user, &Goal{Title: "Grow revenue"})
+	is.Equal(err, shared.ErrForbidden)
+
+	goal, err := s.CreateGoal(context.Background(), admin, &Goal{Title: "Grow revenue"})
+	is.NoErr(err)
+
+	t.Run("LinkActivityToGoal", func(t *testing.T) {
+		err := s.LinkActivityToGoal(context.Background(), user, activityIDGoalSample, goal.ID)
+		is.NoErr(err)
+
+		err = s.LinkActivityToGoal(context.Background(), user, activityIDGoalSample, goal.ID)
+		is.NoErr(err)
+
+		goals, err := s.ReadGoalsOfActivity(context.Background(), user, activityIDGoalSample)
+		is.NoErr(err)
+		is.Equal(len(goals), 1)
+	})
+
+	t.Run("LinkActivityOfOtherUser", func(t *testing.T) {
+		other := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user2"}
+
+		err := s.LinkActivityToGoal(context.Background(), other, activityIDGoalSample, goal.ID)
+		is.Equal(err, ErrActivityNotFound)
+	})
+
+	t.Run("GoalReport", func(t *testing.T) {
+		_, err := s.GoalReport(context.Background(), user, 2024)
+		is.Equal(err, shared.ErrForbidden)
+
+		report, err := s.GoalReport(context.Background(), admin, 2024)
+		is.NoErr(err)
+		is.Equal(len(report.Goals), 1)
+		is.Equal(report.Goals[0].TotalMinutes(), 60)
+	})
+
+	t.Run("LinkArchivedGoal", func(t *testing.T) {
+		archivedGoal, err := s.CreateGoal(context.Background(), admin, &Goal{Title: "Old goal"})
+		is.NoErr(err)
+
+		archivedGoal.Archived = true
+		_, err = s.UpdateGoal(context.Background(), admin, archivedGoal)
+		is.NoErr(err)
+
+		err = s.LinkActivityToGoal(context.Background(), user, activityIDGoalSample, archivedGoal.ID)
+		is.Equal(err, ErrGoalArchived)
+	})
+
+	err = s.UnlinkActivityFromGoal(context.Background(), user, activityIDGoalSample, goal.ID)
+	is.NoErr(err)
+
+	err = s.DeleteGoal(context.Background(), admin, goal.ID)
+	is.NoErr(err)
+}
@@ -0,0 +1,113 @@
+package tracking
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type historyEntryModel struct {
+	Actor          string                     `json:"actor"`
+	ImpersonatedBy string                     `json:"impersonatedBy,omitempty"`
+	Action         string                     `json:"action"`
+	CreatedAt      string                     `json:"createdAt"`
+	Changes        []*shared.AuditChangeModel `json:"changes"`
+}
+
+type historyModel struct {
+	Entries []*historyEntryModel `json:"entries"`
+	Links   *hal.Links           `json:"_links"`
+}
+
+type HistoryRestHandlers struct {
+	config         *shared.Config
+	historyService *HistoryService
+}
+
+func NewHistoryRestHandlers(config *shared.Config, historyService *HistoryService) *HistoryRestHandlers {
+	return &HistoryRestHandlers{
+		config:         config,
+		historyService: historyService,
+	}
+}
+
+func (a *HistoryRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/activities/{activity-id}/history", a.HandleGetActivityHistory())
+	r.Get("/projects/{project-id}/history", a.HandleGetProjectHistory())
+}
+
+func (a *HistoryRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetActivityHistory reads the change timeline of an activity with the changed fields and actors
+func (a *HistoryRestHandlers) HandleGetActivityHistory() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	historyService := a.historyService
+	return func(w http.ResponseWriter, r *http.Request) {
+		activityIDParam := chi.URLParam(r, "activity-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, err := uuid.Parse(activityIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		auditEntries, err := historyService.ReadActivityHistory(r.Context(), principal, activityID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToHistoryModel(r.RequestURI, auditEntries))
+	}
+}
+
+// HandleGetProjectHistory reads the change timeline of a project with the changed fields and actors
+func (a *HistoryRestHandlers) HandleGetProjectHistory() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	historyService := a.historyService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		auditEntries, err := historyService.ReadProjectHistory(r.Context(), principal, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToHistoryModel(r.RequestURI, auditEntries))
+	}
+}
+
+func mapToHistoryModel(self string, auditEntries []*shared.AuditEntry) *historyModel {
+	entryModels := make([]*historyEntryModel, len(auditEntries))
+	for i, auditEntry := range auditEntries {
+		entryModels[i] = &historyEntryModel{
+			Actor:          auditEntry.Username,
+			ImpersonatedBy: auditEntry.ImpersonatedBy,
+			Action:         auditEntry.Action,
+			CreatedAt:      auditEntry.CreatedAt.UTC().Format(time.RFC3339),
+			Changes:        shared.MapToAuditChangeModels(auditEntry.Changes),
+		}
+	}
+
+	return &historyModel{
+		Entries: entryModels,
+		Links: hal.NewLinks(
+			hal.NewSelfLink(self),
+		),
+	}
+}
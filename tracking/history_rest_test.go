@@ -0,0 +1,71 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestHandleGetActivityHistory(t *testing.T) {
+	is := is.New(t)
+
+	auditRepository := shared.NewInMemAuditRepository()
+	activityRepository := NewAuditedActivityRepository(NewInMemActivityRepository(), auditRepository)
+	a := &HistoryRestHandlers{
+		config:         &shared.Config{},
+		historyService: NewHistoryService(auditRepository, activityRepository),
+	}
+
+	activityID := uuid.MustParse("00000000-0000-0000-2222-000000000001")
+	activity, err := activityRepository.FindActivityByID(context.Background(), activityID, shared.OrganizationIDSample)
+	is.NoErr(err)
+	updated := *activity
+	updated.Description = "Edited"
+	_, err = activityRepository.UpdateActivity(context.Background(), shared.OrganizationIDSample, &updated)
+	is.NoErr(err)
+
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal)))
+		})
+	})
+	a.RegisterProtected(router)
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/activities/"+activityID.String()+"/history", nil)
+	router.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	historyModel := &historyModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(historyModel)
+	is.NoErr(err)
+	is.Equal(len(historyModel.Entries), 1)
+	is.Equal(historyModel.Entries[0].Action, AuditActionActivityUpdated)
+	is.Equal(historyModel.Entries[0].Changes[0].NewValue, "Edited")
+
+	principal.Username = "user2"
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("GET", "/activities/"+activityID.String()+"/history", nil)
+	router.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("GET", "/projects/"+shared.ProjectIDSample.String()+"/history", nil)
+	router.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	principal.Roles = []string{"ROLE_ADMIN"}
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("GET", "/projects/"+shared.ProjectIDSample.String()+"/history", nil)
+	router.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+}
@@ -0,0 +1,62 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// HistoryService reads the change history of activities and projects from the audit log,
+// so disputes about edited time entries can be resolved
+type HistoryService struct {
+	auditRepository    shared.AuditRepository
+	activityRepository ActivityRepository
+}
+
+// NewHistoryService creates a new service for the change history of activities and projects
+func NewHistoryService(auditRepository shared.AuditRepository, activityRepository ActivityRepository) *HistoryService {
+	return &HistoryService{
+		auditRepository:    auditRepository,
+		activityRepository: activityRepository,
+	}
+}
+
+// ReadActivityHistory reads the changes of the activity, oldest first. Users read the history of their own
+// activities, only admins read the history of deleted activities.
+func (s *HistoryService) ReadActivityHistory(ctx context.Context, principal *shared.Principal, activityID uuid.UUID) ([]*shared.AuditEntry, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		activity, err := s.activityRepository.FindActivityByID(ctx, activityID, principal.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+
+		if activity.Username != principal.Username {
+			return nil, ErrActivityNotFound
+		}
+	}
+
+	auditEntries, err := s.auditRepository.FindAuditEntriesOfEntity(ctx, principal.OrganizationID, AuditEntityActivity, activityID)
+	if err != nil {
+		return nil, err
+	}
+
+	// activities without history are either unknown or unchanged since before the history was recorded
+	if len(auditEntries) == 0 {
+		_, err := s.activityRepository.FindActivityByID(ctx, activityID, principal.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return auditEntries, nil
+}
+
+// ReadProjectHistory reads the changes of the project, oldest first, only admins read the history of projects
+func (s *HistoryService) ReadProjectHistory(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) ([]*shared.AuditEntry, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.auditRepository.FindAuditEntriesOfEntity(ctx, principal.OrganizationID, AuditEntityProject, projectID)
+}
@@ -0,0 +1,150 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	IncomingWebhookTargetActivity = "activity"
+	IncomingWebhookTargetProject  = "project"
+
+	maxIncomingWebhookNameLength = 100
+)
+
+var (
+	ErrIncomingWebhookNotFound      = shared.NewDomainError("incoming-webhook:not-found", http.StatusNotFound, "incoming webhook not found")
+	ErrIncomingWebhookUserNotMember = shared.NewDomainError("incoming-webhook:user-not-member", http.StatusBadRequest, "user of incoming webhook is not a member of the organization")
+)
+
+// incomingWebhookFields are the fields of the targets which are mapped from the payload
+var incomingWebhookFields = map[string][]string{
+	IncomingWebhookTargetActivity: {"start", "end", "duration", "description", "project"},
+	IncomingWebhookTargetProject:  {"title", "description", "billable"},
+}
+
+// IncomingWebhook is a secret url of an organization to which tools without a Baralga integration
+// post JSON payloads, which create an activity or a project of the target. The mapping tells for each field
+// of the target the path of its value in the payload like issue.fields.summary, unmapped fields are read from
+// the payload by their name.
+type IncomingWebhook struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Name           string
+	Target         string
+	Username       string
+	Mapping        map[string]string
+	TokenHash      string
+	CreatedBy      string
+	CreatedAt      time.Time
+
+	// Token is only known right after the webhook is created as just the hash is stored
+	Token string
+}
+
+type IncomingWebhookRepository interface {
+	FindIncomingWebhooks(ctx context.Context, organizationID uuid.UUID) ([]*IncomingWebhook, error)
+	FindIncomingWebhookByTokenHash(ctx context.Context, tokenHash string) (*IncomingWebhook, error)
+	InsertIncomingWebhook(ctx context.Context, incomingWebhook *IncomingWebhook) error
+	DeleteIncomingWebhook(ctx context.Context, organizationID, incomingWebhookID uuid.UUID) error
+	IsMember(ctx context.Context, organizationID uuid.UUID, username string) (bool, error)
+}
+
+// Validate checks the name, the target and the mapping of the webhook
+func (w *IncomingWebhook) Validate() error {
+	if w.Name == "" {
+		return shared.NewInvalidParam("name", "required", "name is required")
+	}
+	if utf8.RuneCountInString(w.Name) > maxIncomingWebhookNameLength {
+		return shared.NewInvalidParam("name", "max", "name must not be longer than 100 characters")
+	}
+
+	fields, ok := incomingWebhookFields[w.Target]
+	if !ok {
+		return shared.NewInvalidParam("target", "oneof", "target must be activity or project")
+	}
+	for field, path := range w.Mapping {
+		if !slices.Contains(fields, field) {
+			return shared.NewInvalidParam("mapping", "oneof", "mapping of "+w.Target+" must be of the fields "+strings.Join(fields, ", "))
+		}
+		if path == "" || len(path) > 200 || strings.Contains(path, "..") || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") {
+			return shared.NewInvalidParam("mapping", "path", "mapping of "+field+" must be a path like issue.fields.summary")
+		}
+	}
+	return nil
+}
+
+// Principal is the principal the webhook acts as, it's the user of the webhook with the user role only
+// as the admin check of projects is done when the webhook is set up
+func (w *IncomingWebhook) Principal() *shared.Principal {
+	return &shared.Principal{
+		Name:           w.Name,
+		Username:       w.Username,
+		OrganizationID: w.OrganizationID,
+		Roles:          []string{"ROLE_USER"},
+	}
+}
+
+// ValuesOf reads the values of the fields of the target from the JSON payload, missing values are left out
+func (w *IncomingWebhook) ValuesOf(payload []byte) (map[string]string, error) {
+	var document any
+	err := json.Unmarshal(payload, &document)
+	if err != nil {
+		return nil, shared.NewInvalidParam("payload", "json", "payload must be JSON")
+	}
+
+	values := make(map[string]string)
+	for _, field := range incomingWebhookFields[w.Target] {
+		path, ok := w.Mapping[field]
+		if !ok {
+			path = field
+		}
+
+		value, ok := incomingWebhookValueAt(document, path)
+		if ok {
+			values[field] = value
+		}
+	}
+	return values, nil
+}
+
+// incomingWebhookValueAt is the value at the dot separated path in the JSON document,
+// path segments of arrays are the index like items.0.name
+func incomingWebhookValueAt(document any, path string) (string, bool) {
+	value := document
+	for _, segment := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			value = node[segment]
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", false
+			}
+			value = node[index]
+		default:
+			return "", false
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		v = strings.TrimSpace(v)
+		return v, v != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,49 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestValidateIncomingWebhook(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr((&IncomingWebhook{Name: "Jira", Target: IncomingWebhookTargetActivity, Mapping: map[string]string{"description": "issue.fields.summary"}}).Validate())
+	is.NoErr((&IncomingWebhook{Name: "CRM", Target: IncomingWebhookTargetProject}).Validate())
+
+	is.True((&IncomingWebhook{Target: IncomingWebhookTargetActivity}).Validate() != nil)
+	is.True((&IncomingWebhook{Name: "Jira", Target: "invoice"}).Validate() != nil)
+	is.True((&IncomingWebhook{Name: "Jira", Target: IncomingWebhookTargetProject, Mapping: map[string]string{"start": "created"}}).Validate() != nil)
+	is.True((&IncomingWebhook{Name: "Jira", Target: IncomingWebhookTargetActivity, Mapping: map[string]string{"start": "issue..created"}}).Validate() != nil)
+}
+
+func TestIncomingWebhookValuesOf(t *testing.T) {
+	is := is.New(t)
+
+	incomingWebhook := &IncomingWebhook{
+		Target: IncomingWebhookTargetActivity,
+		Mapping: map[string]string{
+			"description": "issue.fields.summary",
+			"duration":    "worklogs.0.minutes",
+		},
+	}
+
+	values, err := incomingWebhook.ValuesOf([]byte(`{
+		"start": "2021-11-05T10:00:00Z",
+		"issue": {"fields": {"summary": " Fix login "}},
+		"worklogs": [{"minutes": 90}],
+		"project": {"key": "BAR"}
+	}`))
+	is.NoErr(err)
+	is.Equal(values["start"], "2021-11-05T10:00:00Z")
+	is.Equal(values["description"], "Fix login")
+	is.Equal(values["duration"], "90")
+
+	// objects are no values
+	_, ok := values["project"]
+	is.True(!ok)
+
+	_, err = incomingWebhook.ValuesOf([]byte(`not json`))
+	is.True(err != nil)
+}
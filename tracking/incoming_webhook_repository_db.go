@@ -0,0 +1,177 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbIncomingWebhookRepository is a SQL database repository for the incoming webhooks of organizations
+type DbIncomingWebhookRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ IncomingWebhookRepository = (*DbIncomingWebhookRepository)(nil)
+
+// NewDbIncomingWebhookRepository creates a new SQL database repository for incoming webhooks
+func NewDbIncomingWebhookRepository(connPool *pgxpool.Pool) *DbIncomingWebhookRepository {
+	return &DbIncomingWebhookRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbIncomingWebhookRepository) FindIncomingWebhooks(ctx context.Context, organizationID uuid.UUID) ([]*IncomingWebhook, error) {
+	rows, err := shared.SelectAll[incomingWebhookRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[incomingWebhookRow]()+`
+		 FROM incoming_webhooks
+		 WHERE org_id = $1
+		 ORDER BY name ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	incomingWebhooks := make([]*IncomingWebhook, len(rows))
+	for i, row := range rows {
+		incomingWebhook, err := row.toIncomingWebhook()
+		if err != nil {
+			return nil, err
+		}
+		incomingWebhooks[i] = incomingWebhook
+	}
+	return incomingWebhooks, nil
+}
+
+// FindIncomingWebhookByTokenHash reads the incoming webhook of the token across all organizations,
+// webhooks of disabled users or users which are no longer members are not found
+func (r *DbIncomingWebhookRepository) FindIncomingWebhookByTokenHash(ctx context.Context, tokenHash string) (*IncomingWebhook, error) {
+	row, err := shared.SelectOne[incomingWebhookRow](
+		ctx,
+		r.connPool,
+		`SELECT w.incoming_webhook_id, w.org_id, w.name, w.target, w.username, w.mapping,
+		        w.token_hash, w.created_by, w.created_at
+		 FROM incoming_webhooks w
+		 JOIN users u ON u.org_id = w.org_id AND u.username = w.username
+		 WHERE w.token_hash = $1 AND u.enabled = 1
+		   AND EXISTS (SELECT 1 FROM roles r WHERE r.user_id = u.user_id AND r.role IN ('ROLE_USER', 'ROLE_ADMIN'))`,
+		tokenHash,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIncomingWebhookNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toIncomingWebhook()
+}
+
+func (r *DbIncomingWebhookRepository) InsertIncomingWebhook(ctx context.Context, incomingWebhook *IncomingWebhook) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	mapping, err := json.Marshal(incomingWebhook.Mapping)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO incoming_webhooks
+		   (incoming_webhook_id, org_id, name, target, username, mapping, token_hash, created_by, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		incomingWebhook.ID,
+		incomingWebhook.OrganizationID,
+		incomingWebhook.Name,
+		incomingWebhook.Target,
+		incomingWebhook.Username,
+		string(mapping),
+		incomingWebhook.TokenHash,
+		incomingWebhook.CreatedBy,
+		incomingWebhook.CreatedAt,
+	)
+	return err
+}
+
+func (r *DbIncomingWebhookRepository) DeleteIncomingWebhook(ctx context.Context, organizationID, incomingWebhookID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM incoming_webhooks
+		 WHERE incoming_webhook_id = $1 AND org_id = $2`,
+		incomingWebhookID,
+		organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrIncomingWebhookNotFound
+	}
+	return nil
+}
+
+// IsMember checks whether the username is an enabled user of the organization tracking time,
+// users of the client portal are no members
+func (r *DbIncomingWebhookRepository) IsMember(ctx context.Context, organizationID uuid.UUID, username string) (bool, error) {
+	var isMember bool
+	err := r.connPool.QueryRow(
+		ctx,
+		`SELECT EXISTS (
+		   SELECT 1
+		   FROM users u
+		   JOIN roles r ON r.user_id = u.user_id
+		   WHERE u.org_id = $1 AND u.username = $2 AND u.enabled = 1 AND r.role IN ('ROLE_USER', 'ROLE_ADMIN')
+		 )`,
+		organizationID, username,
+	).Scan(&isMember)
+	if err != nil {
+		return false, err
+	}
+
+	return isMember, nil
+}
+
+type incomingWebhookRow struct {
+	ID             uuid.UUID `db:"incoming_webhook_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	Name           string    `db:"name"`
+	Target         string    `db:"target"`
+	Username       string    `db:"username"`
+	Mapping        string    `db:"mapping"`
+	TokenHash      string    `db:"token_hash"`
+	CreatedBy      string    `db:"created_by"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (r *incomingWebhookRow) toIncomingWebhook() (*IncomingWebhook, error) {
+	mapping := make(map[string]string)
+	err := json.Unmarshal([]byte(r.Mapping), &mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IncomingWebhook{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Name:           r.Name,
+		Target:         r.Target,
+		Username:       r.Username,
+		Mapping:        mapping,
+		TokenHash:      r.TokenHash,
+		CreatedBy:      r.CreatedBy,
+		CreatedAt:      r.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestIncomingWebhookRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	incomingWebhookRepository := NewDbIncomingWebhookRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("InsertAndDeleteIncomingWebhook", func(t *testing.T) {
+		incomingWebhook := &IncomingWebhook{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			Name:           "Jira",
+			Target:         IncomingWebhookTargetActivity,
+			Username:       "user1",
+			Mapping:        map[string]string{"description": "issue.summary"},
+			TokenHash:      shared.HashToken("token"),
+			CreatedBy:      "admin",
+			CreatedAt:      time.Now(),
+		}
+
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return incomingWebhookRepository.InsertIncomingWebhook(ctx, incomingWebhook)
+			},
+		)
+		is.NoErr(err)
+
+		found, err := incomingWebhookRepository.FindIncomingWebhookByTokenHash(context.Background(), shared.HashToken("token"))
+		is.NoErr(err)
+		is.Equal(found.ID, incomingWebhook.ID)
+		is.Equal(found.Mapping["description"], "issue.summary")
+
+		incomingWebhooks, err := incomingWebhookRepository.FindIncomingWebhooks(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(incomingWebhooks), 1)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return incomingWebhookRepository.DeleteIncomingWebhook(ctx, shared.OrganizationIDSample, incomingWebhook.ID)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = incomingWebhookRepository.FindIncomingWebhookByTokenHash(context.Background(), shared.HashToken("token"))
+		is.True(errors.Is(err, ErrIncomingWebhookNotFound))
+	})
+}
@@ -0,0 +1,88 @@
+package tracking
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+type InMemIncomingWebhookRepository struct {
+	mu               sync.Mutex
+	incomingWebhooks []*IncomingWebhook
+	members          map[uuid.UUID][]string
+}
+
+var _ IncomingWebhookRepository = (*InMemIncomingWebhookRepository)(nil)
+
+func NewInMemIncomingWebhookRepository() *InMemIncomingWebhookRepository {
+	return &InMemIncomingWebhookRepository{
+		members: map[uuid.UUID][]string{
+			shared.OrganizationIDSample: {"admin", "user1"},
+		},
+	}
+}
+
+func (r *InMemIncomingWebhookRepository) FindIncomingWebhooks(ctx context.Context, organizationID uuid.UUID) ([]*IncomingWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var incomingWebhooks []*IncomingWebhook
+	for _, incomingWebhook := range r.incomingWebhooks {
+		if incomingWebhook.OrganizationID == organizationID {
+			found := *incomingWebhook
+			incomingWebhooks = append(incomingWebhooks, &found)
+		}
+	}
+
+	sort.Slice(incomingWebhooks, func(i, j int) bool {
+		return incomingWebhooks[i].Name < incomingWebhooks[j].Name
+	})
+	return incomingWebhooks, nil
+}
+
+func (r *InMemIncomingWebhookRepository) FindIncomingWebhookByTokenHash(ctx context.Context, tokenHash string) (*IncomingWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, incomingWebhook := range r.incomingWebhooks {
+		if incomingWebhook.TokenHash == tokenHash {
+			found := *incomingWebhook
+			return &found, nil
+		}
+	}
+	return nil, ErrIncomingWebhookNotFound
+}
+
+func (r *InMemIncomingWebhookRepository) InsertIncomingWebhook(ctx context.Context, incomingWebhook *IncomingWebhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *incomingWebhook
+	inserted.Token = ""
+	r.incomingWebhooks = append(r.incomingWebhooks, &inserted)
+	return nil
+}
+
+func (r *InMemIncomingWebhookRepository) DeleteIncomingWebhook(ctx context.Context, organizationID, incomingWebhookID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, incomingWebhook := range r.incomingWebhooks {
+		if incomingWebhook.ID == incomingWebhookID && incomingWebhook.OrganizationID == organizationID {
+			r.incomingWebhooks = append(r.incomingWebhooks[:i], r.incomingWebhooks[i+1:]...)
+			return nil
+		}
+	}
+	return ErrIncomingWebhookNotFound
+}
+
+func (r *InMemIncomingWebhookRepository) IsMember(ctx context.Context, organizationID uuid.UUID, username string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Contains(r.members[organizationID], username), nil
+}
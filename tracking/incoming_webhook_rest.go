@@ -0,0 +1,215 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"schneider.vip/problem"
+)
+
+// maxIncomingWebhookPayloadSize is the maximum size of payloads posted to incoming webhooks
+const maxIncomingWebhookPayloadSize = 64 * 1024
+
+type incomingWebhookModel struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Target    string            `json:"target"`
+	Username  string            `json:"username"`
+	Mapping   map[string]string `json:"mapping"`
+	URL       string            `json:"url,omitempty"`
+	CreatedBy string            `json:"createdBy"`
+	CreatedAt string            `json:"createdAt"`
+	Links     *hal.Links        `json:"_links"`
+}
+
+type incomingWebhooksModel struct {
+	Embedded *embeddedIncomingWebhooks `json:"_embedded"`
+	Links    *hal.Links                `json:"_links"`
+}
+
+type embeddedIncomingWebhooks struct {
+	IncomingWebhookModels []*incomingWebhookModel `json:"incomingWebhooks"`
+}
+
+type IncomingWebhookRestHandlers struct {
+	config                 *shared.Config
+	incomingWebhookService *IncomingWebhookService
+}
+
+func NewIncomingWebhookRestHandlers(config *shared.Config, incomingWebhookService *IncomingWebhookService) *IncomingWebhookRestHandlers {
+	return &IncomingWebhookRestHandlers{
+		config:                 config,
+		incomingWebhookService: incomingWebhookService,
+	}
+}
+
+func (a *IncomingWebhookRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/incoming-webhooks", a.HandleGetIncomingWebhooks())
+	r.Post("/incoming-webhooks", a.HandleCreateIncomingWebhook())
+	r.Delete("/incoming-webhooks/{incoming-webhook-id}", a.HandleDeleteIncomingWebhook())
+}
+
+func (a *IncomingWebhookRestHandlers) RegisterOpen(r chi.Router) {
+	r.Post("/hooks/{token}", a.HandleIncomingWebhook())
+}
+
+// HandleGetIncomingWebhooks reads the incoming webhooks of the organization
+func (a *IncomingWebhookRestHandlers) HandleGetIncomingWebhooks() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	incomingWebhookService := a.incomingWebhookService
+	webroot := a.config.Webroot
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		incomingWebhooks, err := incomingWebhookService.ReadIncomingWebhooks(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		incomingWebhookModels := make([]*incomingWebhookModel, len(incomingWebhooks))
+		for i, incomingWebhook := range incomingWebhooks {
+			incomingWebhookModels[i] = mapToIncomingWebhookModel(incomingWebhook, webroot)
+		}
+
+		shared.RenderJSON(w, &incomingWebhooksModel{
+			Embedded: &embeddedIncomingWebhooks{
+				IncomingWebhookModels: incomingWebhookModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleCreateIncomingWebhook creates an incoming webhook, its secret url is only shown in the response
+func (a *IncomingWebhookRestHandlers) HandleCreateIncomingWebhook() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	incomingWebhookService := a.incomingWebhookService
+	webroot := a.config.Webroot
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var incomingWebhookModel incomingWebhookModel
+		err := json.NewDecoder(r.Body).Decode(&incomingWebhookModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "incoming webhook not valid", err)
+			return
+		}
+
+		incomingWebhook := &IncomingWebhook{
+			Name:     incomingWebhookModel.Name,
+			Target:   incomingWebhookModel.Target,
+			Username: incomingWebhookModel.Username,
+			Mapping:  incomingWebhookModel.Mapping,
+		}
+		err = incomingWebhook.Validate()
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "incoming webhook not valid", err)
+			return
+		}
+
+		incomingWebhook, err = incomingWebhookService.CreateIncomingWebhook(r.Context(), principal, incomingWebhook, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToIncomingWebhookModel(incomingWebhook, webroot))
+	}
+}
+
+// HandleDeleteIncomingWebhook removes an incoming webhook
+func (a *IncomingWebhookRestHandlers) HandleDeleteIncomingWebhook() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	incomingWebhookService := a.incomingWebhookService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		incomingWebhookID, err := uuid.Parse(chi.URLParam(r, "incoming-webhook-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = incomingWebhookService.DeleteIncomingWebhook(r.Context(), principal, incomingWebhookID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+// HandleIncomingWebhook creates the activity or project of the JSON payload posted to the secret url of the webhook
+func (a *IncomingWebhookRestHandlers) HandleIncomingWebhook() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	incomingWebhookService := a.incomingWebhookService
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIncomingWebhookPayloadSize))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		// near duplicates are created with a warning, senders opt in to reject them instead
+		rejectDuplicate := r.URL.Query().Get("rejectDuplicate") == "true"
+
+		delivery, err := incomingWebhookService.ReceivePayload(r.Context(), chi.URLParam(r, "token"), payload, rejectDuplicate)
+		var invalidParam *shared.InvalidParam
+		if errors.As(err, &invalidParam) {
+			shared.RenderValidationProblemJSON(w, "payload not valid", err)
+			return
+		}
+		var nearDuplicateError *ActivityNearDuplicateError
+		if errors.As(err, &nearDuplicateError) {
+			renderNearDuplicateProblemJSON(w, nearDuplicateError)
+			return
+		}
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		if delivery.Project != nil {
+			w.WriteHeader(http.StatusCreated)
+			// the sender holds no credentials, so the project is rendered without the links of admins
+			shared.RenderJSON(w, mapToProjectModel(&shared.Principal{}, delivery.Project))
+			return
+		}
+
+		setNearDuplicateWarning(w, delivery.NearDuplicates)
+		w.WriteHeader(http.StatusCreated)
+		renderActivityModel(w, r, delivery.Activity)
+	}
+}
+
+func mapToIncomingWebhookModel(incomingWebhook *IncomingWebhook, webroot string) *incomingWebhookModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/incoming-webhooks/%s", incomingWebhook.ID))
+	incomingWebhookModel := &incomingWebhookModel{
+		ID:        incomingWebhook.ID.String(),
+		Name:      incomingWebhook.Name,
+		Target:    incomingWebhook.Target,
+		Username:  incomingWebhook.Username,
+		Mapping:   incomingWebhook.Mapping,
+		CreatedBy: incomingWebhook.CreatedBy,
+		CreatedAt: incomingWebhook.CreatedAt.UTC().Format(time.RFC3339),
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+	if incomingWebhook.Token != "" {
+		incomingWebhookModel.URL = fmt.Sprintf("%s/api/hooks/%s", webroot, incomingWebhook.Token)
+	}
+	return incomingWebhookModel
+}
@@ -0,0 +1,74 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleIncomingWebhooks(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{Webroot: "https://baralga.com"}
+	a := NewIncomingWebhookRestHandlers(config, newIncomingWebhookServiceForTest())
+
+	request := func(method, url, body string, roles ...string) *http.Request {
+		r, _ := http.NewRequest(method, url, strings.NewReader(body))
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "admin",
+			Roles:          roles,
+		}))
+	}
+
+	router := chi.NewRouter()
+	a.RegisterProtected(router)
+	a.RegisterOpen(router)
+
+	httpRec := httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("POST", "/incoming-webhooks", `{"name": "Jira", "target": "invoice"}`, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("POST", "/incoming-webhooks", `{"name": "Jira", "target": "activity"}`, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("POST", "/incoming-webhooks", `{"name": "Jira", "target": "activity", "mapping": {"description": "summary"}}`, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	var created incomingWebhookModel
+	err := json.NewDecoder(httpRec.Body).Decode(&created)
+	is.NoErr(err)
+	is.True(strings.HasPrefix(created.URL, "https://baralga.com/api/hooks/"))
+	hookPath := strings.TrimPrefix(created.URL, "https://baralga.com/api")
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, httptest.NewRequest("POST", hookPath, strings.NewReader(`{"start": "2024-03-04T10:00:00Z", "end": "2024-03-04T11:00:00Z", "summary": "Standup", "project": "My Project"}`)))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+	is.True(strings.Contains(httpRec.Body.String(), "Standup"))
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, httptest.NewRequest("POST", hookPath, strings.NewReader(`{"start": "2024-03-04T10:00:00Z", "end": "2024-03-04T09:00:00Z"}`)))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, httptest.NewRequest("POST", "/hooks/unknown", strings.NewReader(`{}`)))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("GET", "/incoming-webhooks", "", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.True(!strings.Contains(httpRec.Body.String(), "/api/hooks/"))
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("DELETE", "/incoming-webhooks/"+created.ID, "", "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+}
@@ -0,0 +1,263 @@
+package tracking
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// incomingWebhookTokenBytes is the length of the random webhook tokens
+const incomingWebhookTokenBytes = 32
+
+// IncomingWebhookDelivery is the activity or project created for a payload posted to an incoming webhook,
+// an activity created anyway as near duplicate comes with its duplicates
+type IncomingWebhookDelivery struct {
+	Activity       *Activity
+	NearDuplicates []*Activity
+	Project        *Project
+}
+
+// IncomingWebhookService manages the incoming webhooks of an organization and creates the activities
+// and projects of the payloads posted to them
+type IncomingWebhookService struct {
+	repositoryTxer            shared.RepositoryTxer
+	incomingWebhookRepository IncomingWebhookRepository
+	activityService           *ActitivityService
+	projectService            *ProjectService
+	projectRepository         ProjectRepository
+}
+
+// NewIncomingWebhookService creates a new service for incoming webhooks
+func NewIncomingWebhookService(
+	repositoryTxer shared.RepositoryTxer,
+	incomingWebhookRepository IncomingWebhookRepository,
+	activityService *ActitivityService,
+	projectService *ProjectService,
+	projectRepository ProjectRepository,
+) *IncomingWebhookService {
+	return &IncomingWebhookService{
+		repositoryTxer:            repositoryTxer,
+		incomingWebhookRepository: incomingWebhookRepository,
+		activityService:           activityService,
+		projectService:            projectService,
+		projectRepository:         projectRepository,
+	}
+}
+
+// ReadIncomingWebhooks reads the incoming webhooks of the organization, only admins manage incoming webhooks
+func (s *IncomingWebhookService) ReadIncomingWebhooks(ctx context.Context, principal *shared.Principal) ([]*IncomingWebhook, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.incomingWebhookRepository.FindIncomingWebhooks(ctx, principal.OrganizationID)
+}
+
+// CreateIncomingWebhook creates an incoming webhook with a new secret token, the token is only returned here.
+// Activities are tracked for the user of the webhook, which is the admin creating it unless set to a member of the organization.
+func (s *IncomingWebhookService) CreateIncomingWebhook(ctx context.Context, principal *shared.Principal, incomingWebhook *IncomingWebhook, now time.Time) (*IncomingWebhook, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	err := incomingWebhook.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newIncomingWebhookToken()
+	if err != nil {
+		return nil, err
+	}
+
+	incomingWebhook.ID = uuid.New()
+	incomingWebhook.OrganizationID = principal.OrganizationID
+	incomingWebhook.TokenHash = shared.HashToken(token)
+	incomingWebhook.CreatedBy = principal.Username
+	incomingWebhook.CreatedAt = now
+	incomingWebhook.Token = token
+	if incomingWebhook.Username == "" || incomingWebhook.Target == IncomingWebhookTargetProject {
+		incomingWebhook.Username = principal.Username
+	}
+
+	isMember, err := s.incomingWebhookRepository.IsMember(ctx, principal.OrganizationID, incomingWebhook.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, ErrIncomingWebhookUserNotMember
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.incomingWebhookRepository.InsertIncomingWebhook(ctx, incomingWebhook)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return incomingWebhook, nil
+}
+
+// DeleteIncomingWebhook removes an incoming webhook, payloads posted to its url are rejected from now on
+func (s *IncomingWebhookService) DeleteIncomingWebhook(ctx context.Context, principal *shared.Principal, incomingWebhookID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.incomingWebhookRepository.DeleteIncomingWebhook(ctx, principal.OrganizationID, incomingWebhookID)
+		},
+	)
+}
+
+// ReceivePayload creates the activity or project of the payload posted to the incoming webhook of the token,
+// near duplicate activities are created with their duplicates unless they are rejected
+func (s *IncomingWebhookService) ReceivePayload(ctx context.Context, token string, payload []byte, rejectDuplicate bool) (*IncomingWebhookDelivery, error) {
+	incomingWebhook, err := s.incomingWebhookRepository.FindIncomingWebhookByTokenHash(ctx, shared.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := incomingWebhook.ValuesOf(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = shared.WithOrganizationID(ctx, incomingWebhook.OrganizationID)
+	principal := incomingWebhook.Principal()
+	ctx = context.WithValue(ctx, shared.ContextKeyPrincipal, principal)
+
+	if incomingWebhook.Target == IncomingWebhookTargetProject {
+		project, err := s.createProject(ctx, principal, values)
+		if err != nil {
+			return nil, err
+		}
+		return &IncomingWebhookDelivery{Project: project}, nil
+	}
+
+	activity, err := s.createActivity(ctx, principal, values, rejectDuplicate)
+	if err != nil {
+		return nil, err
+	}
+
+	nearDuplicates, err := s.activityService.ReadNearDuplicates(ctx, principal, activity)
+	if err != nil {
+		return nil, err
+	}
+	return &IncomingWebhookDelivery{Activity: activity, NearDuplicates: nearDuplicates}, nil
+}
+
+// createActivity creates the activity from start and end or duration in minutes, the project is
+// given by its id, alias or title or else assigned by the project assignment rules
+func (s *IncomingWebhookService) createActivity(ctx context.Context, principal *shared.Principal, values map[string]string, rejectDuplicate bool) (*Activity, error) {
+	start, err := time.Parse(time.RFC3339, values["start"])
+	if err != nil {
+		return nil, shared.NewInvalidParam("start", "datetime", "start must be a date time like 2021-11-05T10:00:00Z")
+	}
+
+	var end time.Time
+	switch {
+	case values["end"] != "":
+		end, err = time.Parse(time.RFC3339, values["end"])
+		if err != nil {
+			return nil, shared.NewInvalidParam("end", "datetime", "end must be a date time like 2021-11-05T11:00:00Z")
+		}
+	case values["duration"] != "":
+		minutes, err := strconv.Atoi(values["duration"])
+		if err != nil {
+			return nil, shared.NewInvalidParam("duration", "number", "duration must be minutes")
+		}
+		end = start.Add(time.Duration(minutes) * time.Minute)
+	default:
+		return nil, shared.NewInvalidParam("end", "required", "end or duration is required")
+	}
+	if !end.After(start) {
+		return nil, shared.NewInvalidParam("end", "gtfield", "end must be after start")
+	}
+
+	activity := &Activity{
+		Start:       start,
+		End:         end,
+		Description: values["description"],
+	}
+
+	if values["project"] != "" {
+		project, err := s.findProject(ctx, principal.OrganizationID, values["project"])
+		if err != nil {
+			return nil, err
+		}
+		activity.ProjectID = project.ID
+	}
+
+	return s.activityService.CreateActivityFromSource(ctx, principal, activity, ActivitySourceWebhook, !rejectDuplicate)
+}
+
+func (s *IncomingWebhookService) createProject(ctx context.Context, principal *shared.Principal, values map[string]string) (*Project, error) {
+	if values["title"] == "" {
+		return nil, shared.NewInvalidParam("title", "required", "title is required")
+	}
+
+	project := &Project{
+		Title:       values["title"],
+		Description: values["description"],
+		Status:      ProjectStatusActive,
+	}
+	if values["billable"] != "" {
+		billable, err := strconv.ParseBool(values["billable"])
+		if err != nil {
+			return nil, shared.NewInvalidParam("billable", "boolean", "billable must be true or false")
+		}
+		project.Billable = billable
+	}
+
+	return s.projectService.CreateProject(ctx, principal, project)
+}
+
+// findProject finds the project by its id, its alias or its title
+func (s *IncomingWebhookService) findProject(ctx context.Context, organizationID uuid.UUID, project string) (*Project, error) {
+	if projectID, err := uuid.Parse(project); err == nil {
+		return s.projectRepository.FindProjectByID(ctx, organizationID, projectID)
+	}
+
+	if IsValidProjectAlias(project) {
+		found, err := s.projectRepository.FindProjectByAlias(ctx, organizationID, project)
+		if err != nil && !errors.Is(err, ErrProjectNotFound) {
+			return nil, err
+		}
+		if found != nil {
+			return found, nil
+		}
+	}
+
+	projectsPaged, err := s.projectRepository.FindProjects(ctx, organizationID, &paged.PageParams{Page: 0, Size: 100})
+	if err != nil {
+		return nil, err
+	}
+	for _, found := range projectsPaged.Projects {
+		if strings.EqualFold(found.Title, project) {
+			return found, nil
+		}
+	}
+	return nil, ErrProjectNotFound
+}
+
+func newIncomingWebhookToken() (string, error) {
+	token := make([]byte, incomingWebhookTokenBytes)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
@@ -0,0 +1,161 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newIncomingWebhookServiceForTest() *IncomingWebhookService {
+	projectRepository := NewInMemProjectRepository()
+	activityService := &ActitivityService{
+		repositoryTxer:               shared.NewInMemRepositoryTxer(),
+		activityRepository:           NewInMemActivityRepository(),
+		locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+		projectAssignmentRepository:  NewInMemProjectAssignmentRepository(),
+		breakPolicyRepository:        NewInMemBreakPolicyRepository(),
+		categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+	}
+	projectService := NewProjectService(
+		shared.NewInMemRepositoryTxer(),
+		projectRepository,
+		shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+	)
+
+	return NewIncomingWebhookService(
+		shared.NewInMemRepositoryTxer(),
+		NewInMemIncomingWebhookRepository(),
+		activityService,
+		projectService,
+		projectRepository,
+	)
+}
+
+func TestIncomingWebhookActivity(t *testing.T) {
+	is := is.New(t)
+
+	s := newIncomingWebhookServiceForTest()
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+	now := time.Date(2024, 3, 4, 17, 0, 0, 0, time.UTC)
+
+	_, err := s.CreateIncomingWebhook(context.Background(), user, &IncomingWebhook{Name: "Jira", Target: IncomingWebhookTargetActivity}, now)
+	is.True(errors.Is(err, shared.ErrForbidden))
+
+	incomingWebhook, err := s.CreateIncomingWebhook(context.Background(), admin, &IncomingWebhook{
+		Name:     "Jira",
+		Target:   IncomingWebhookTargetActivity,
+		Username: "user1",
+		Mapping:  map[string]string{"description": "issue.summary", "project": "issue.project"},
+	}, now)
+	is.NoErr(err)
+	is.True(incomingWebhook.Token != "")
+	is.Equal(incomingWebhook.CreatedBy, "admin")
+
+	delivery, err := s.ReceivePayload(context.Background(), incomingWebhook.Token, []byte(`{
+		"start": "2024-03-04T10:00:00Z",
+		"duration": 90,
+		"issue": {"summary": "Fix login", "project": "my project"}
+	}`), false)
+	is.NoErr(err)
+	is.Equal(delivery.Activity.Username, "user1")
+	is.Equal(delivery.Activity.Description, "Fix login")
+	is.Equal(delivery.Activity.ProjectID, shared.ProjectIDSample)
+	is.Equal(delivery.Activity.End, time.Date(2024, 3, 4, 11, 30, 0, 0, time.UTC))
+
+	_, err = s.ReceivePayload(context.Background(), incomingWebhook.Token, []byte(`{"start": "2024-03-04T10:00:00Z"}`), false)
+	var invalidParam *shared.InvalidParam
+	is.True(errors.As(err, &invalidParam))
+
+	_, err = s.ReceivePayload(context.Background(), incomingWebhook.Token, []byte(`{"start": "2024-03-05T10:00:00Z", "duration": 30, "issue": {"project": "unknown"}}`), false)
+	is.True(errors.Is(err, ErrProjectNotFound))
+
+	_, err = s.ReceivePayload(context.Background(), "unknown", []byte(`{}`), false)
+	is.True(errors.Is(err, ErrIncomingWebhookNotFound))
+
+	incomingWebhooks, err := s.ReadIncomingWebhooks(context.Background(), admin)
+	is.NoErr(err)
+	is.Equal(len(incomingWebhooks), 1)
+	is.Equal(incomingWebhooks[0].Token, "")
+
+	err = s.DeleteIncomingWebhook(context.Background(), admin, incomingWebhook.ID)
+	is.NoErr(err)
+
+	_, err = s.ReceivePayload(context.Background(), incomingWebhook.Token, []byte(`{}`), false)
+	is.True(errors.Is(err, ErrIncomingWebhookNotFound))
+}
+
+func TestIncomingWebhookProject(t *testing.T) {
+	is := is.New(t)
+
+	s := newIncomingWebhookServiceForTest()
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	incomingWebhook, err := s.CreateIncomingWebhook(context.Background(), admin, &IncomingWebhook{
+		Name:     "CRM",
+		Target:   IncomingWebhookTargetProject,
+		Username: "user1",
+		Mapping:  map[string]string{"title": "deal.name"},
+	}, time.Now())
+	is.NoErr(err)
+
+	// project webhooks act as the admin creating them
+	is.Equal(incomingWebhook.Username, "admin")
+
+	delivery, err := s.ReceivePayload(context.Background(), incomingWebhook.Token, []byte(`{"deal": {"name": "ACME Relaunch"}, "billable": true}`), false)
+	is.NoErr(err)
+	is.Equal(delivery.Project.Title, "ACME Relaunch")
+	is.Equal(delivery.Project.Status, ProjectStatusActive)
+	is.True(delivery.Project.Billable)
+
+	_, err = s.ReceivePayload(context.Background(), incomingWebhook.Token, []byte(`{"billable": "yes"}`), false)
+	var invalidParam *shared.InvalidParam
+	is.True(errors.As(err, &invalidParam))
+}
+
+func TestIncomingWebhookUserNotMember(t *testing.T) {
+	is := is.New(t)
+
+	s := newIncomingWebhookServiceForTest()
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := s.CreateIncomingWebhook(context.Background(), admin, &IncomingWebhook{
+		Name:     "Jira",
+		Target:   IncomingWebhookTargetActivity,
+		Username: "someone-else",
+	}, time.Now())
+	is.True(errors.Is(err, ErrIncomingWebhookUserNotMember))
+}
+
+func TestIncomingWebhookNearDuplicate(t *testing.T) {
+	is := is.New(t)
+
+	s := newIncomingWebhookServiceForTest()
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	incomingWebhook, err := s.CreateIncomingWebhook(context.Background(), admin, &IncomingWebhook{
+		Name:     "Jira",
+		Target:   IncomingWebhookTargetActivity,
+		Username: "user1",
+		Mapping:  map[string]string{"description": "issue.summary", "project": "issue.project"},
+	}, time.Now())
+	is.NoErr(err)
+
+	payload := []byte(`{"start": "2024-03-04T10:00:00Z", "duration": 60, "issue": {"summary": "Fix login", "project": "my project"}}`)
+
+	delivery, err := s.ReceivePayload(context.Background(), incomingWebhook.Token, payload, false)
+	is.NoErr(err)
+	is.Equal(len(delivery.NearDuplicates), 0)
+
+	// near duplicates are created with a warning by default
+	delivery, err = s.ReceivePayload(context.Background(), incomingWebhook.Token, payload, false)
+	is.NoErr(err)
+	is.Equal(len(delivery.NearDuplicates), 1)
+
+	_, err = s.ReceivePayload(context.Background(), incomingWebhook.Token, payload, true)
+	is.True(errors.Is(err, ErrActivityNearDuplicate))
+}
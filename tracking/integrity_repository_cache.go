@@ -0,0 +1,29 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+)
+
+// ReportInvalidatingIntegrityRepository invalidates the cached reports of the organization
+// once the repair of an integrity problem committed, as repairs change activities directly
+type ReportInvalidatingIntegrityRepository struct {
+	shared.IntegrityRepository
+	reportCache *CachedActivityRepository
+}
+
+var _ shared.IntegrityRepository = (*ReportInvalidatingIntegrityRepository)(nil)
+
+// NewReportInvalidatingIntegrityRepository creates a new integrity repository which invalidates the report cache on repairs
+func NewReportInvalidatingIntegrityRepository(integrityRepository shared.IntegrityRepository, reportCache *CachedActivityRepository) *ReportInvalidatingIntegrityRepository {
+	return &ReportInvalidatingIntegrityRepository{
+		IntegrityRepository: integrityRepository,
+		reportCache:         reportCache,
+	}
+}
+
+func (r *ReportInvalidatingIntegrityRepository) RepairIntegrityProblem(ctx context.Context, problem *shared.IntegrityProblem) error {
+	r.reportCache.invalidateAfterCommit(ctx, problem.OrganizationID)
+	return r.IntegrityRepository.RepairIntegrityProblem(ctx, problem)
+}
@@ -0,0 +1,193 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	InvoiceStatusDraft      = "draft"
+	InvoiceStatusSent       = "sent"
+	InvoiceStatusPaid       = "paid"
+	InvoiceStatusOverdue    = "overdue"
+	InvoiceStatusWrittenOff = "written-off"
+)
+
+// invoiceReminderIntervalDays are the days between reminders about an overdue invoice
+const invoiceReminderIntervalDays = 7
+
+var (
+	ErrInvoiceNotFound                 = shared.NewDomainError("invoice:not-found", http.StatusNotFound, "invoice not found")
+	ErrInvoiceNumberTaken              = shared.NewDomainError("invoice:number-taken", http.StatusConflict, "invoice number is already used in the organization")
+	ErrInvoiceWithoutBillableTime      = shared.NewDomainError("invoice:no-billable-time", http.StatusConflict, "client has no billable time in the month")
+	ErrInvalidInvoiceStatusTransition  = shared.NewDomainError("invoice:invalid-status-transition", http.StatusConflict, "invalid invoice status transition")
+	ErrInvoicePaymentExceedsOpenAmount = shared.NewDomainError("invoice:payment-exceeds-open-amount", http.StatusConflict, "payment exceeds the open amount of the invoice")
+	ErrInvoicePaymentNotFound          = shared.NewDomainError("invoice-payment:not-found", http.StatusNotFound, "invoice payment not found")
+	ErrInvoiceNotDeletable             = shared.NewDomainError("invoice:not-deletable", http.StatusConflict, "only draft invoices can be deleted")
+)
+
+// Invoice is the monthly statement of a client issued as invoice, once sent it is paid by one or more payments
+// or written off, a sent invoice not fully paid after its due day is overdue
+type Invoice struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ClientID       uuid.UUID
+	ClientName     string
+	Number         string
+	Day            time.Time
+	DueDay         time.Time
+	Currency       string
+	NetAmountCents int
+	TaxAmountCents int
+	RecipientEMail string
+	Status         string
+	SentAt         *time.Time
+	LastReminderAt *time.Time
+	CreatedAt      time.Time
+	Payments       []*InvoicePayment
+}
+
+// InvoicePayment is a full or partial payment received for an invoice
+type InvoicePayment struct {
+	ID             uuid.UUID
+	InvoiceID      uuid.UUID
+	OrganizationID uuid.UUID
+	AmountCents    int
+	PaidOn         time.Time
+	Note           string
+}
+
+type InvoiceRepository interface {
+	FindInvoices(ctx context.Context, organizationID uuid.UUID) ([]*Invoice, error)
+	FindInvoiceByID(ctx context.Context, organizationID, invoiceID uuid.UUID) (*Invoice, error)
+	InsertInvoice(ctx context.Context, invoice *Invoice) error
+	UpdateInvoice(ctx context.Context, invoice *Invoice) error
+	DeleteInvoice(ctx context.Context, organizationID, invoiceID uuid.UUID) error
+	InsertInvoicePayment(ctx context.Context, payment *InvoicePayment) error
+	DeleteInvoicePayment(ctx context.Context, organizationID, invoiceID, paymentID uuid.UUID) error
+	FindInvoicesDueForReminder(ctx context.Context, today, lastReminderBefore time.Time) ([]*Invoice, error)
+}
+
+// NewInvoice creates a draft invoice of the monthly statement of a client
+func NewInvoice(organizationID uuid.UUID, accountingInvoice *AccountingInvoice, recipientEMail string, now time.Time) *Invoice {
+	client := accountingInvoice.Statement.Client
+	return &Invoice{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		ClientID:       client.ID,
+		ClientName:     client.Name,
+		Number:         accountingInvoice.Number,
+		Day:            accountingInvoice.Day,
+		DueDay:         accountingInvoice.DueDay,
+		Currency:       client.Currency,
+		NetAmountCents: accountingInvoice.NetAmountCents(),
+		TaxAmountCents: accountingInvoice.TaxAmountCents(),
+		RecipientEMail: recipientEMail,
+		Status:         InvoiceStatusDraft,
+		CreatedAt:      now,
+	}
+}
+
+// IsValidInvoiceStatus checks whether the status is known
+func IsValidInvoiceStatus(status string) bool {
+	switch status {
+	case InvoiceStatusDraft, InvoiceStatusSent, InvoiceStatusPaid, InvoiceStatusOverdue, InvoiceStatusWrittenOff:
+		return true
+	default:
+		return false
+	}
+}
+
+// GrossAmountCents is the amount of the invoice including taxes
+func (i *Invoice) GrossAmountCents() int {
+	return i.NetAmountCents + i.TaxAmountCents
+}
+
+// PaidAmountCents is the sum of the payments of the invoice
+func (i *Invoice) PaidAmountCents() int {
+	paid := 0
+	for _, payment := range i.Payments {
+		paid += payment.AmountCents
+	}
+	return paid
+}
+
+// OpenAmountCents is the amount of the invoice not yet paid
+func (i *Invoice) OpenAmountCents() int {
+	return i.GrossAmountCents() - i.PaidAmountCents()
+}
+
+// StatusOn is the status of the invoice at the time, a sent invoice is overdue after its due day
+func (i *Invoice) StatusOn(now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if i.Status == InvoiceStatusSent && today.After(i.DueDay) {
+		return InvoiceStatusOverdue
+	}
+	return i.Status
+}
+
+// IsDueForReminder checks whether the client is to be reminded about the overdue invoice,
+// reminders are sent at most once per interval
+func (i *Invoice) IsDueForReminder(now time.Time) bool {
+	if i.StatusOn(now) != InvoiceStatusOverdue || i.RecipientEMail == "" {
+		return false
+	}
+	return i.LastReminderAt == nil || !i.LastReminderAt.After(now.AddDate(0, 0, -invoiceReminderIntervalDays))
+}
+
+// Send marks the draft invoice as sent to the client
+func (i *Invoice) Send(now time.Time) error {
+	if i.Status != InvoiceStatusDraft {
+		return ErrInvalidInvoiceStatusTransition
+	}
+
+	i.Status = InvoiceStatusSent
+	i.SentAt = &now
+	return nil
+}
+
+// WriteOff gives up on the open amount of a sent invoice
+func (i *Invoice) WriteOff() error {
+	if i.Status != InvoiceStatusSent {
+		return ErrInvalidInvoiceStatusTransition
+	}
+
+	i.Status = InvoiceStatusWrittenOff
+	return nil
+}
+
+// AddPayment adds a payment to the sent invoice, the invoice is paid when no amount is open anymore
+func (i *Invoice) AddPayment(payment *InvoicePayment) error {
+	if i.Status != InvoiceStatusSent {
+		return ErrInvalidInvoiceStatusTransition
+	}
+	if payment.AmountCents > i.OpenAmountCents() {
+		return ErrInvoicePaymentExceedsOpenAmount
+	}
+
+	i.Payments = append(i.Payments, payment)
+	if i.OpenAmountCents() == 0 {
+		i.Status = InvoiceStatusPaid
+	}
+	return nil
+}
+
+// RemovePayment removes a payment of the invoice, a paid invoice is sent again if an amount is open
+func (i *Invoice) RemovePayment(paymentID uuid.UUID) error {
+	for j, payment := range i.Payments {
+		if payment.ID != paymentID {
+			continue
+		}
+
+		i.Payments = append(i.Payments[:j], i.Payments[j+1:]...)
+		if i.Status == InvoiceStatusPaid && i.OpenAmountCents() > 0 {
+			i.Status = InvoiceStatusSent
+		}
+		return nil
+	}
+	return ErrInvoicePaymentNotFound
+}
@@ -0,0 +1,74 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestInvoicePayments(t *testing.T) {
+	is := is.New(t)
+
+	invoice := &Invoice{
+		ID:             uuid.New(),
+		DueDay:         time.Date(2024, 4, 14, 0, 0, 0, 0, time.UTC),
+		NetAmountCents: 10000,
+		TaxAmountCents: 1900,
+		RecipientEMail: "billing@acme.com",
+		Status:         InvoiceStatusDraft,
+	}
+
+	err := invoice.AddPayment(&InvoicePayment{ID: uuid.New(), AmountCents: 5000})
+	is.Equal(err, ErrInvalidInvoiceStatusTransition)
+
+	is.NoErr(invoice.Send(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)))
+	is.Equal(invoice.Send(time.Now()), ErrInvalidInvoiceStatusTransition)
+
+	is.Equal(invoice.StatusOn(time.Date(2024, 4, 14, 18, 0, 0, 0, time.UTC)), InvoiceStatusSent)
+	is.Equal(invoice.StatusOn(time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)), InvoiceStatusOverdue)
+
+	partialPayment := &InvoicePayment{ID: uuid.New(), AmountCents: 5000}
+	is.NoErr(invoice.AddPayment(partialPayment))
+	is.Equal(invoice.Status, InvoiceStatusSent)
+	is.Equal(invoice.OpenAmountCents(), 6900)
+
+	err = invoice.AddPayment(&InvoicePayment{ID: uuid.New(), AmountCents: 7000})
+	is.Equal(err, ErrInvoicePaymentExceedsOpenAmount)
+
+	is.NoErr(invoice.AddPayment(&InvoicePayment{ID: uuid.New(), AmountCents: 6900}))
+	is.Equal(invoice.Status, InvoiceStatusPaid)
+	is.Equal(invoice.StatusOn(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)), InvoiceStatusPaid)
+
+	is.NoErr(invoice.RemovePayment(partialPayment.ID))
+	is.Equal(invoice.Status, InvoiceStatusSent)
+	is.Equal(invoice.RemovePayment(uuid.New()), ErrInvoicePaymentNotFound)
+
+	is.NoErr(invoice.WriteOff())
+	is.Equal(invoice.Status, InvoiceStatusWrittenOff)
+}
+
+func TestInvoiceIsDueForReminder(t *testing.T) {
+	is := is.New(t)
+
+	sentAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	invoice := &Invoice{
+		DueDay:         time.Date(2024, 4, 14, 0, 0, 0, 0, time.UTC),
+		NetAmountCents: 10000,
+		RecipientEMail: "billing@acme.com",
+		Status:         InvoiceStatusSent,
+		SentAt:         &sentAt,
+	}
+
+	is.True(!invoice.IsDueForReminder(time.Date(2024, 4, 14, 12, 0, 0, 0, time.UTC)))
+	is.True(invoice.IsDueForReminder(time.Date(2024, 4, 15, 12, 0, 0, 0, time.UTC)))
+
+	lastReminderAt := time.Date(2024, 4, 15, 12, 0, 0, 0, time.UTC)
+	invoice.LastReminderAt = &lastReminderAt
+	is.True(!invoice.IsDueForReminder(time.Date(2024, 4, 20, 12, 0, 0, 0, time.UTC)))
+	is.True(invoice.IsDueForReminder(time.Date(2024, 4, 22, 12, 0, 0, 0, time.UTC)))
+
+	invoice.RecipientEMail = ""
+	is.True(!invoice.IsDueForReminder(time.Date(2024, 4, 22, 12, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,298 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbInvoiceRepository is a SQL database repository for invoices and their payments
+type DbInvoiceRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ InvoiceRepository = (*DbInvoiceRepository)(nil)
+
+// NewDbInvoiceRepository creates a new SQL database repository for invoices
+func NewDbInvoiceRepository(connPool *pgxpool.Pool) *DbInvoiceRepository {
+	return &DbInvoiceRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbInvoiceRepository) FindInvoices(ctx context.Context, organizationID uuid.UUID) ([]*Invoice, error) {
+	rows, err := shared.SelectAll[invoiceRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[invoiceRow]()+`
+		 FROM invoices
+		 WHERE org_id = $1
+		 ORDER BY invoice_day DESC, invoice_number DESC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.withPayments(ctx, rows)
+}
+
+func (r *DbInvoiceRepository) FindInvoiceByID(ctx context.Context, organizationID, invoiceID uuid.UUID) (*Invoice, error) {
+	row, err := shared.SelectOne[invoiceRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[invoiceRow]()+`
+		 FROM invoices
+		 WHERE invoice_id = $1 AND org_id = $2`,
+		invoiceID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvoiceNotFound
+		}
+
+		return nil, err
+	}
+
+	invoices, err := r.withPayments(ctx, []*invoiceRow{row})
+	if err != nil {
+		return nil, err
+	}
+	return invoices[0], nil
+}
+
+// InsertInvoice adds an invoice, the number must be unique within the organization
+func (r *DbInvoiceRepository) InsertInvoice(ctx context.Context, invoice *Invoice) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`INSERT INTO invoices
+		   (invoice_id, org_id, client_id, client_name, invoice_number, invoice_day, due_day, currency,
+		    net_amount_cents, tax_amount_cents, recipient_email, status, sent_at, last_reminder_at, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		 ON CONFLICT (org_id, invoice_number) DO NOTHING`,
+		invoice.ID,
+		invoice.OrganizationID,
+		invoice.ClientID,
+		invoice.ClientName,
+		invoice.Number,
+		invoice.Day,
+		invoice.DueDay,
+		invoice.Currency,
+		invoice.NetAmountCents,
+		invoice.TaxAmountCents,
+		invoice.RecipientEMail,
+		invoice.Status,
+		invoice.SentAt,
+		invoice.LastReminderAt,
+		invoice.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrInvoiceNumberTaken
+	}
+	return nil
+}
+
+// UpdateInvoice changes the due day, recipient and payment status of an invoice
+func (r *DbInvoiceRepository) UpdateInvoice(ctx context.Context, invoice *Invoice) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE invoices
+		 SET due_day = $3, recipient_email = $4, status = $5, sent_at = $6, last_reminder_at = $7
+		 WHERE invoice_id = $1 AND org_id = $2`,
+		invoice.ID,
+		invoice.OrganizationID,
+		invoice.DueDay,
+		invoice.RecipientEMail,
+		invoice.Status,
+		invoice.SentAt,
+		invoice.LastReminderAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrInvoiceNotFound
+	}
+	return nil
+}
+
+func (r *DbInvoiceRepository) DeleteInvoice(ctx context.Context, organizationID, invoiceID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM invoices
+		 WHERE invoice_id = $1 AND org_id = $2`,
+		invoiceID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrInvoiceNotFound
+	}
+	return nil
+}
+
+func (r *DbInvoiceRepository) InsertInvoicePayment(ctx context.Context, payment *InvoicePayment) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO invoice_payments
+		   (payment_id, invoice_id, org_id, amount_cents, paid_on, note)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6)`,
+		payment.ID,
+		payment.InvoiceID,
+		payment.OrganizationID,
+		payment.AmountCents,
+		payment.PaidOn,
+		payment.Note,
+	)
+	return err
+}
+
+func (r *DbInvoiceRepository) DeleteInvoicePayment(ctx context.Context, organizationID, invoiceID, paymentID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM invoice_payments
+		 WHERE payment_id = $1 AND invoice_id = $2 AND org_id = $3`,
+		paymentID, invoiceID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrInvoicePaymentNotFound
+	}
+	return nil
+}
+
+// FindInvoicesDueForReminder reads the sent invoices of all organizations overdue on the day
+// whose client was not reminded since the time
+func (r *DbInvoiceRepository) FindInvoicesDueForReminder(ctx context.Context, today, lastReminderBefore time.Time) ([]*Invoice, error) {
+	rows, err := shared.SelectAll[invoiceRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[invoiceRow]()+`
+		 FROM invoices
+		 WHERE status = $1 AND due_day < $2 AND recipient_email <> ''
+		 AND (last_reminder_at IS NULL OR last_reminder_at <= $3)
+		 ORDER BY org_id, due_day`,
+		InvoiceStatusSent, today, lastReminderBefore,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.withPayments(ctx, rows)
+}
+
+// withPayments maps the rows to invoices with their payments
+func (r *DbInvoiceRepository) withPayments(ctx context.Context, rows []*invoiceRow) ([]*Invoice, error) {
+	invoices := make([]*Invoice, len(rows))
+	invoicesByID := make(map[uuid.UUID]*Invoice, len(rows))
+	invoiceIDs := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		invoices[i] = row.toInvoice()
+		invoicesByID[row.ID] = invoices[i]
+		invoiceIDs[i] = row.ID
+	}
+
+	if len(invoiceIDs) == 0 {
+		return invoices, nil
+	}
+
+	paymentRows, err := shared.SelectAll[invoicePaymentRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[invoicePaymentRow]()+`
+		 FROM invoice_payments
+		 WHERE invoice_id = ANY($1)
+		 ORDER BY paid_on, created_at`,
+		invoiceIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, paymentRow := range paymentRows {
+		invoice := invoicesByID[paymentRow.InvoiceID]
+		invoice.Payments = append(invoice.Payments, &InvoicePayment{
+			ID:             paymentRow.ID,
+			InvoiceID:      paymentRow.InvoiceID,
+			OrganizationID: paymentRow.OrganizationID,
+			AmountCents:    paymentRow.AmountCents,
+			PaidOn:         paymentRow.PaidOn,
+			Note:           paymentRow.Note,
+		})
+	}
+	return invoices, nil
+}
+
+type invoiceRow struct {
+	ID             uuid.UUID  `db:"invoice_id"`
+	OrganizationID uuid.UUID  `db:"org_id"`
+	ClientID       uuid.UUID  `db:"client_id"`
+	ClientName     string     `db:"client_name"`
+	Number         string     `db:"invoice_number"`
+	Day            time.Time  `db:"invoice_day"`
+	DueDay         time.Time  `db:"due_day"`
+	Currency       string     `db:"currency"`
+	NetAmountCents int        `db:"net_amount_cents"`
+	TaxAmountCents int        `db:"tax_amount_cents"`
+	RecipientEMail string     `db:"recipient_email"`
+	Status         string     `db:"status"`
+	SentAt         *time.Time `db:"sent_at"`
+	LastReminderAt *time.Time `db:"last_reminder_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+type invoicePaymentRow struct {
+	ID             uuid.UUID `db:"payment_id"`
+	InvoiceID      uuid.UUID `db:"invoice_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	AmountCents    int       `db:"amount_cents"`
+	PaidOn         time.Time `db:"paid_on"`
+	Note           string    `db:"note"`
+}
+
+func (r *invoiceRow) toInvoice() *Invoice {
+	return &Invoice{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		ClientID:       r.ClientID,
+		ClientName:     r.ClientName,
+		Number:         r.Number,
+		Day:            r.Day,
+		DueDay:         r.DueDay,
+		Currency:       r.Currency,
+		NetAmountCents: r.NetAmountCents,
+		TaxAmountCents: r.TaxAmountCents,
+		RecipientEMail: r.RecipientEMail,
+		Status:         r.Status,
+		SentAt:         r.SentAt,
+		LastReminderAt: r.LastReminderAt,
+		CreatedAt:      r.CreatedAt,
+	}
+}
@@ -0,0 +1,133 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestInvoiceRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	clientRepository := NewDbClientRepository(connPool)
+	invoiceRepository := NewDbInvoiceRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	client := &Client{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Name:           "ACME Corp.",
+		Currency:       "EUR",
+	}
+	invoice := &Invoice{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		ClientID:       client.ID,
+		ClientName:     client.Name,
+		Number:         "2024-03-01",
+		Day:            time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		DueDay:         time.Date(2024, 4, 14, 0, 0, 0, 0, time.UTC),
+		Currency:       "EUR",
+		NetAmountCents: 13500,
+		TaxAmountCents: 2565,
+		RecipientEMail: "billing@acme.com",
+		Status:         InvoiceStatusDraft,
+		CreatedAt:      time.Now(),
+	}
+
+	t.Run("InsertInvoice", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := clientRepository.InsertClient(ctx, client)
+				if err != nil {
+					return err
+				}
+				return invoiceRepository.InsertInvoice(ctx, invoice)
+			},
+		)
+		is.NoErr(err)
+
+		invoices, err := invoiceRepository.FindInvoices(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(invoices), 1)
+		is.Equal(invoices[0].Number, "2024-03-01")
+	})
+
+	t.Run("InsertInvoiceWithTakenNumber", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				takenNumber := *invoice
+				takenNumber.ID = uuid.New()
+				return invoiceRepository.InsertInvoice(ctx, &takenNumber)
+			},
+		)
+		is.Equal(err, ErrInvoiceNumberTaken)
+	})
+
+	t.Run("InsertInvoicePayment", func(t *testing.T) {
+		sentAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+		invoice.Status = InvoiceStatusSent
+		invoice.SentAt = &sentAt
+
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				err := invoiceRepository.UpdateInvoice(ctx, invoice)
+				if err != nil {
+					return err
+				}
+				return invoiceRepository.InsertInvoicePayment(ctx, &InvoicePayment{
+					ID:             uuid.New(),
+					InvoiceID:      invoice.ID,
+					OrganizationID: shared.OrganizationIDSample,
+					AmountCents:    6065,
+					PaidOn:         time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+				})
+			},
+		)
+		is.NoErr(err)
+
+		invoiceRead, err := invoiceRepository.FindInvoiceByID(context.Background(), shared.OrganizationIDSample, invoice.ID)
+		is.NoErr(err)
+		is.Equal(invoiceRead.Status, InvoiceStatusSent)
+		is.Equal(invoiceRead.OpenAmountCents(), 10000)
+	})
+
+	t.Run("FindInvoicesDueForReminder", func(t *testing.T) {
+		now := time.Date(2024, 4, 15, 12, 0, 0, 0, time.UTC)
+
+		invoices, err := invoiceRepository.FindInvoicesDueForReminder(context.Background(), time.Date(2024, 4, 14, 0, 0, 0, 0, time.UTC), now)
+		is.NoErr(err)
+		is.Equal(len(invoices), 0)
+
+		invoices, err = invoiceRepository.FindInvoicesDueForReminder(context.Background(), time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC), now)
+		is.NoErr(err)
+		is.Equal(len(invoices), 1)
+		is.Equal(len(invoices[0].Payments), 1)
+	})
+}
@@ -0,0 +1,165 @@
+package tracking
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemInvoiceRepository struct {
+	mu       sync.Mutex
+	invoices []*Invoice
+}
+
+var _ InvoiceRepository = (*InMemInvoiceRepository)(nil)
+
+func NewInMemInvoiceRepository() *InMemInvoiceRepository {
+	return &InMemInvoiceRepository{}
+}
+
+func (r *InMemInvoiceRepository) FindInvoices(ctx context.Context, organizationID uuid.UUID) ([]*Invoice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var invoices []*Invoice
+	for _, invoice := range r.invoices {
+		if invoice.OrganizationID == organizationID {
+			invoices = append(invoices, copyInvoice(invoice))
+		}
+	}
+	sort.Slice(invoices, func(i, j int) bool {
+		if !invoices[i].Day.Equal(invoices[j].Day) {
+			return invoices[i].Day.After(invoices[j].Day)
+		}
+		return invoices[i].Number > invoices[j].Number
+	})
+	return invoices, nil
+}
+
+func (r *InMemInvoiceRepository) FindInvoiceByID(ctx context.Context, organizationID, invoiceID uuid.UUID) (*Invoice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invoice, ok := r.findInvoice(organizationID, invoiceID)
+	if !ok {
+		return nil, ErrInvoiceNotFound
+	}
+	return copyInvoice(invoice), nil
+}
+
+func (r *InMemInvoiceRepository) InsertInvoice(ctx context.Context, invoice *Invoice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, i := range r.invoices {
+		if i.OrganizationID == invoice.OrganizationID && i.Number == invoice.Number {
+			return ErrInvoiceNumberTaken
+		}
+	}
+
+	copied := copyInvoice(invoice)
+	copied.Payments = nil
+	r.invoices = append(r.invoices, copied)
+	return nil
+}
+
+func (r *InMemInvoiceRepository) UpdateInvoice(ctx context.Context, invoice *Invoice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.findInvoice(invoice.OrganizationID, invoice.ID)
+	if !ok {
+		return ErrInvoiceNotFound
+	}
+
+	current.DueDay = invoice.DueDay
+	current.RecipientEMail = invoice.RecipientEMail
+	current.Status = invoice.Status
+	current.SentAt = invoice.SentAt
+	current.LastReminderAt = invoice.LastReminderAt
+	return nil
+}
+
+func (r *InMemInvoiceRepository) DeleteInvoice(ctx context.Context, organizationID, invoiceID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, invoice := range r.invoices {
+		if invoice.OrganizationID == organizationID && invoice.ID == invoiceID {
+			r.invoices = append(r.invoices[:i], r.invoices[i+1:]...)
+			return nil
+		}
+	}
+	return ErrInvoiceNotFound
+}
+
+func (r *InMemInvoiceRepository) InsertInvoicePayment(ctx context.Context, payment *InvoicePayment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invoice, ok := r.findInvoice(payment.OrganizationID, payment.InvoiceID)
+	if !ok {
+		return ErrInvoiceNotFound
+	}
+
+	copied := *payment
+	invoice.Payments = append(invoice.Payments, &copied)
+	return nil
+}
+
+func (r *InMemInvoiceRepository) DeleteInvoicePayment(ctx context.Context, organizationID, invoiceID, paymentID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invoice, ok := r.findInvoice(organizationID, invoiceID)
+	if !ok {
+		return ErrInvoicePaymentNotFound
+	}
+
+	for i, payment := range invoice.Payments {
+		if payment.ID == paymentID {
+			invoice.Payments = append(invoice.Payments[:i], invoice.Payments[i+1:]...)
+			return nil
+		}
+	}
+	return ErrInvoicePaymentNotFound
+}
+
+func (r *InMemInvoiceRepository) FindInvoicesDueForReminder(ctx context.Context, today, lastReminderBefore time.Time) ([]*Invoice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var invoices []*Invoice
+	for _, invoice := range r.invoices {
+		if invoice.Status != InvoiceStatusSent || !invoice.DueDay.Before(today) || invoice.RecipientEMail == "" {
+			continue
+		}
+		if invoice.LastReminderAt != nil && invoice.LastReminderAt.After(lastReminderBefore) {
+			continue
+		}
+		invoices = append(invoices, copyInvoice(invoice))
+	}
+	return invoices, nil
+}
+
+func (r *InMemInvoiceRepository) findInvoice(organizationID, invoiceID uuid.UUID) (*Invoice, bool) {
+	for _, invoice := range r.invoices {
+		if invoice.OrganizationID == organizationID && invoice.ID == invoiceID {
+			return invoice, true
+		}
+	}
+	return nil, false
+}
+
+func copyInvoice(invoice *Invoice) *Invoice {
+	copied := *invoice
+	copied.Payments = make([]*InvoicePayment, len(invoice.Payments))
+	for i, payment := range invoice.Payments {
+		copiedPayment := *payment
+		copied.Payments[i] = &copiedPayment
+	}
+	return &copied
+}
@@ -0,0 +1,414 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type invoicePaymentModel struct {
+	ID     string     `json:"id,omitempty"`
+	Amount float64    `json:"amount" validate:"gt=0"`
+	PaidOn string     `json:"paidOn" validate:"required"`
+	Note   string     `json:"note" validate:"max=255"`
+	Links  *hal.Links `json:"_links,omitempty"`
+}
+
+type invoiceModel struct {
+	ID             string                 `json:"id"`
+	ClientID       string                 `json:"clientId"`
+	ClientName     string                 `json:"clientName"`
+	Number         string                 `json:"number"`
+	Day            string                 `json:"day"`
+	DueDay         string                 `json:"dueDay"`
+	Currency       string                 `json:"currency"`
+	NetAmount      string                 `json:"netAmount"`
+	TaxAmount      string                 `json:"taxAmount"`
+	GrossAmount    string                 `json:"grossAmount"`
+	PaidAmount     string                 `json:"paidAmount"`
+	OpenAmount     string                 `json:"openAmount"`
+	RecipientEMail string                 `json:"recipientEmail"`
+	Status         string                 `json:"status"`
+	SentAt         *time.Time             `json:"sentAt,omitempty"`
+	LastReminderAt *time.Time             `json:"lastReminderAt,omitempty"`
+	Payments       []*invoicePaymentModel `json:"payments"`
+	Links          *hal.Links             `json:"_links"`
+}
+
+type invoicesModel struct {
+	Embedded struct {
+		InvoiceModels []*invoiceModel `json:"invoices"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type createInvoiceModel struct {
+	ClientID       string `json:"clientId" validate:"required,uuid"`
+	Month          string `json:"month" validate:"required"`
+	RecipientEMail string `json:"recipientEmail" validate:"omitempty,email,max=255"`
+}
+
+type updateInvoiceModel struct {
+	DueDay         string `json:"dueDay" validate:"required"`
+	RecipientEMail string `json:"recipientEmail" validate:"omitempty,email,max=255"`
+}
+
+type InvoiceRestHandlers struct {
+	config         *shared.Config
+	invoiceService *InvoiceService
+}
+
+func NewInvoiceRestHandlers(config *shared.Config, invoiceService *InvoiceService) *InvoiceRestHandlers {
+	return &InvoiceRestHandlers{
+		config:         config,
+		invoiceService: invoiceService,
+	}
+}
+
+func (a *InvoiceRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/invoices", a.HandleGetInvoices())
+	r.Post("/invoices", a.HandleCreateInvoice())
+	r.Get("/invoices/{invoice-id}", a.HandleGetInvoice())
+	r.Patch("/invoices/{invoice-id}", a.HandleUpdateInvoice())
+	r.Delete("/invoices/{invoice-id}", a.HandleDeleteInvoice())
+	r.Post("/invoices/{invoice-id}/send", a.HandleSendInvoice())
+	r.Post("/invoices/{invoice-id}/write-off", a.HandleWriteOffInvoice())
+	r.Post("/invoices/{invoice-id}/payments", a.HandleAddInvoicePayment())
+	r.Delete("/invoices/{invoice-id}/payments/{payment-id}", a.HandleDeleteInvoicePayment())
+}
+
+func (a *InvoiceRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetInvoices reads the invoices of the organization, only those with the query param status if given
+func (a *InvoiceRestHandlers) HandleGetInvoices() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	invoiceService := a.invoiceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		status := r.URL.Query().Get("status")
+		if status != "" && !IsValidInvoiceStatus(status) {
+			shared.RenderValidationProblemJSON(w, "invalid query params", shared.NewInvalidParam("status", "oneof", "status must be draft, sent, paid, overdue or written-off"))
+			return
+		}
+
+		invoices, err := invoiceService.ReadInvoices(r.Context(), principal, status)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		now := time.Now()
+		invoiceModels := make([]*invoiceModel, len(invoices))
+		for i, invoice := range invoices {
+			invoiceModels[i] = mapToInvoiceModel(invoice, now)
+		}
+
+		invoicesModel := &invoicesModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		invoicesModel.Embedded.InvoiceModels = invoiceModels
+
+		shared.RenderJSON(w, invoicesModel)
+	}
+}
+
+// HandleGetInvoice reads an invoice with its payments
+func (a *InvoiceRestHandlers) HandleGetInvoice() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	invoiceService := a.invoiceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		invoiceID, err := uuid.Parse(chi.URLParam(r, "invoice-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		invoice, err := invoiceService.ReadInvoice(r.Context(), principal, invoiceID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToInvoiceModel(invoice, time.Now()))
+	}
+}
+
+// HandleCreateInvoice issues the statement of a client in a month as draft invoice
+func (a *InvoiceRestHandlers) HandleCreateInvoice() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	invoiceService := a.invoiceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var createInvoiceModel createInvoiceModel
+		err := json.NewDecoder(r.Body).Decode(&createInvoiceModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invoice not valid", err)
+			return
+		}
+
+		err = validator.Struct(createInvoiceModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invoice not valid", err)
+			return
+		}
+
+		month, err := time.Parse("2006-01", createInvoiceModel.Month)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invoice not valid", shared.NewInvalidParam("month", "month", "month must be a month like 2024-03"))
+			return
+		}
+
+		invoice, err := invoiceService.CreateInvoice(r.Context(), principal, uuid.MustParse(createInvoiceModel.ClientID), month, createInvoiceModel.RecipientEMail)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToInvoiceModel(invoice, time.Now()))
+	}
+}
+
+// HandleUpdateInvoice changes the due day and the recipient of reminders of an invoice
+func (a *InvoiceRestHandlers) HandleUpdateInvoice() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	invoiceService := a.invoiceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		invoiceID, err := uuid.Parse(chi.URLParam(r, "invoice-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var updateInvoiceModel updateInvoiceModel
+		err = json.NewDecoder(r.Body).Decode(&updateInvoiceModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invoice not valid", err)
+			return
+		}
+
+		err = validator.Struct(updateInvoiceModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invoice not valid", err)
+			return
+		}
+
+		dueDay, err := time.Parse("2006-01-02", updateInvoiceModel.DueDay)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invoice not valid", shared.NewInvalidParam("dueDay", "date", "dueDay must be a day like 2024-04-14"))
+			return
+		}
+
+		invoice, err := invoiceService.UpdateInvoice(r.Context(), principal, invoiceID, dueDay, updateInvoiceModel.RecipientEMail)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToInvoiceModel(invoice, time.Now()))
+	}
+}
+
+// HandleDeleteInvoice removes a draft invoice
+func (a *InvoiceRestHandlers) HandleDeleteInvoice() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	invoiceService := a.invoiceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		invoiceID, err := uuid.Parse(chi.URLParam(r, "invoice-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = invoiceService.DeleteInvoice(r.Context(), principal, invoiceID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleSendInvoice marks a draft invoice as sent to the client
+func (a *InvoiceRestHandlers) HandleSendInvoice() http.HandlerFunc {
+	return a.handleInvoiceAction(a.invoiceService.SendInvoice)
+}
+
+// HandleWriteOffInvoice gives up on the open amount of a sent invoice
+func (a *InvoiceRestHandlers) HandleWriteOffInvoice() http.HandlerFunc {
+	return a.handleInvoiceAction(a.invoiceService.WriteOffInvoice)
+}
+
+// HandleAddInvoicePayment records a full or partial payment of a sent invoice
+func (a *InvoiceRestHandlers) HandleAddInvoicePayment() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	invoiceService := a.invoiceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		invoiceID, err := uuid.Parse(chi.URLParam(r, "invoice-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var paymentModel invoicePaymentModel
+		err = json.NewDecoder(r.Body).Decode(&paymentModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "payment not valid", err)
+			return
+		}
+
+		err = validator.Struct(paymentModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "payment not valid", err)
+			return
+		}
+
+		paidOn, err := time.Parse("2006-01-02", paymentModel.PaidOn)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "payment not valid", shared.NewInvalidParam("paidOn", "date", "paidOn must be a day like 2024-04-10"))
+			return
+		}
+
+		invoice, err := invoiceService.AddInvoicePayment(r.Context(), principal, invoiceID, &InvoicePayment{
+			AmountCents: int(math.Round(paymentModel.Amount * 100)),
+			PaidOn:      paidOn,
+			Note:        paymentModel.Note,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToInvoiceModel(invoice, time.Now()))
+	}
+}
+
+// HandleDeleteInvoicePayment removes a payment recorded by mistake
+func (a *InvoiceRestHandlers) HandleDeleteInvoicePayment() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	invoiceService := a.invoiceService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		invoiceID, err := uuid.Parse(chi.URLParam(r, "invoice-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		paymentID, err := uuid.Parse(chi.URLParam(r, "payment-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		_, err = invoiceService.DeleteInvoicePayment(r.Context(), principal, invoiceID, paymentID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (a *InvoiceRestHandlers) handleInvoiceAction(action func(ctx context.Context, principal *shared.Principal, invoiceID uuid.UUID) (*Invoice, error)) http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		invoiceID, err := uuid.Parse(chi.URLParam(r, "invoice-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		invoice, err := action(r.Context(), principal, invoiceID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToInvoiceModel(invoice, time.Now()))
+	}
+}
+
+func mapToInvoiceModel(invoice *Invoice, now time.Time) *invoiceModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/invoices/%s", invoice.ID))
+
+	invoiceModel := &invoiceModel{
+		ID:             invoice.ID.String(),
+		ClientID:       invoice.ClientID.String(),
+		ClientName:     invoice.ClientName,
+		Number:         invoice.Number,
+		Day:            invoice.Day.Format("2006-01-02"),
+		DueDay:         invoice.DueDay.Format("2006-01-02"),
+		Currency:       invoice.Currency,
+		NetAmount:      formatCents(invoice.NetAmountCents),
+		TaxAmount:      formatCents(invoice.TaxAmountCents),
+		GrossAmount:    formatCents(invoice.GrossAmountCents()),
+		PaidAmount:     formatCents(invoice.PaidAmountCents()),
+		OpenAmount:     formatCents(invoice.OpenAmountCents()),
+		RecipientEMail: invoice.RecipientEMail,
+		Status:         invoice.StatusOn(now),
+		SentAt:         invoice.SentAt,
+		LastReminderAt: invoice.LastReminderAt,
+		Payments:       make([]*invoicePaymentModel, len(invoice.Payments)),
+	}
+	for i, payment := range invoice.Payments {
+		invoiceModel.Payments[i] = &invoicePaymentModel{
+			ID:     payment.ID.String(),
+			Amount: float64(payment.AmountCents) / 100,
+			PaidOn: payment.PaidOn.Format("2006-01-02"),
+			Note:   payment.Note,
+			Links: hal.NewLinks(
+				hal.NewLink("delete", fmt.Sprintf("%s/payments/%s", selfLink.Href(), payment.ID)),
+			),
+		}
+	}
+
+	links := []*hal.Links{selfLink, hal.NewLink("client", fmt.Sprintf("/api/clients/%s", invoice.ClientID))}
+	switch invoice.Status {
+	case InvoiceStatusDraft:
+		links = append(links,
+			hal.NewLink("edit", selfLink.Href()),
+			hal.NewLink("send", selfLink.Href()+"/send"),
+			hal.NewLink("delete", selfLink.Href()),
+		)
+	case InvoiceStatusSent:
+		links = append(links,
+			hal.NewLink("edit", selfLink.Href()),
+			hal.NewLink("payments", selfLink.Href()+"/payments"),
+			hal.NewLink("write-off", selfLink.Href()+"/write-off"),
+		)
+	}
+	invoiceModel.Links = hal.NewLinks(links...)
+	return invoiceModel
+}
@@ -0,0 +1,119 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleInvoices(t *testing.T) {
+	is := is.New(t)
+
+	a := NewInvoiceRestHandlers(&shared.Config{}, newInMemInvoiceService(shared.NewInMemMailResource(), NewInMemInvoiceRepository()))
+	admin := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, admin)))
+		})
+	})
+	a.RegisterProtected(router)
+
+	var invoiceID string
+
+	t.Run("CreateInvoice", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/invoices", strings.NewReader(`{"clientId": "`+clientIDSample.String()+`", "month": "2024-03", "recipientEmail": "billing@acme.com"}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+		var invoiceModel invoiceModel
+		err := json.NewDecoder(httpRec.Body).Decode(&invoiceModel)
+		is.NoErr(err)
+		is.Equal(invoiceModel.Number, "2024-03-01")
+		is.Equal(invoiceModel.Status, InvoiceStatusDraft)
+		is.Equal(invoiceModel.GrossAmount, "160.65")
+		invoiceID = invoiceModel.ID
+	})
+
+	t.Run("CreateInvoiceNotValid", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/invoices", strings.NewReader(`{"clientId": "`+clientIDSample.String()+`", "month": "March"}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("SendInvoice", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/invoices/"+invoiceID+"/send", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	})
+
+	t.Run("AddInvoicePayment", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/invoices/"+invoiceID+"/payments", strings.NewReader(`{"amount": 60.65, "paidOn": "2024-04-10", "note": "partial payment"}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+		var invoiceModel invoiceModel
+		err := json.NewDecoder(httpRec.Body).Decode(&invoiceModel)
+		is.NoErr(err)
+		is.Equal(invoiceModel.PaidAmount, "60.65")
+		is.Equal(invoiceModel.OpenAmount, "100.00")
+		is.Equal(len(invoiceModel.Payments), 1)
+	})
+
+	t.Run("AddInvoicePaymentExceedingOpenAmount", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/invoices/"+invoiceID+"/payments", strings.NewReader(`{"amount": 100.01, "paidOn": "2024-04-12"}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusConflict)
+	})
+
+	t.Run("GetOverdueInvoices", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/invoices?status=overdue", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		var invoicesModel invoicesModel
+		err := json.NewDecoder(httpRec.Body).Decode(&invoicesModel)
+		is.NoErr(err)
+		is.Equal(len(invoicesModel.Embedded.InvoiceModels), 1)
+	})
+
+	t.Run("GetInvoicesStatusNotValid", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/invoices?status=cancelled", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("DeleteSentInvoice", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("DELETE", "/invoices/"+invoiceID, nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusConflict)
+	})
+}
@@ -0,0 +1,351 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"text/template"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const invoiceReminderJobType = "invoice-reminder"
+
+var invoiceReminderTemplate = template.Must(template.New("invoice-reminder").Parse(
+	`Dear {{ .Invoice.ClientName }},
+
+our invoice {{ .Invoice.Number }} of {{ .Invoice.Day.Format "January 2, 2006" }} was due on {{ .Invoice.DueDay.Format "January 2, 2006" }}.
+The open amount is {{ .OpenAmount }} {{ .Invoice.Currency }}.
+
+Please transfer the open amount at your earliest convenience. If you already paid, please disregard this reminder.
+`))
+
+// invoiceReminder is the mail to a client about an overdue invoice
+type invoiceReminder struct {
+	Invoice    *Invoice
+	OpenAmount string
+}
+
+// InvoiceService issues the monthly statements of clients as invoices, tracks their payments
+// and reminds clients about overdue invoices
+type InvoiceService struct {
+	config            *shared.Config
+	repositoryTxer    shared.RepositoryTxer
+	outbox            shared.Outbox
+	invoiceRepository InvoiceRepository
+	clientRepository  ClientRepository
+	accountingService *AccountingService
+}
+
+// NewInvoiceService creates a new service for invoices, reminding clients about overdue invoices in the background if enabled
+func NewInvoiceService(
+	config *shared.Config,
+	repositoryTxer shared.RepositoryTxer,
+	outbox shared.Outbox,
+	jobService *shared.JobService,
+	invoiceRepository InvoiceRepository,
+	clientRepository ClientRepository,
+	accountingService *AccountingService,
+) *InvoiceService {
+	s := &InvoiceService{
+		config:            config,
+		repositoryTxer:    repositoryTxer,
+		outbox:            outbox,
+		invoiceRepository: invoiceRepository,
+		clientRepository:  clientRepository,
+		accountingService: accountingService,
+	}
+
+	if config.InvoiceReminders {
+		jobService.RegisterHandler(invoiceReminderJobType, s.handleInvoiceReminderJob)
+		jobService.Schedule(invoiceReminderJobType, time.Hour)
+	}
+
+	return s
+}
+
+// ReadInvoices reads the invoices of the organization, only those with the status if given
+func (s *InvoiceService) ReadInvoices(ctx context.Context, principal *shared.Principal, status string) ([]*Invoice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	invoices, err := s.invoiceRepository.FindInvoices(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		return invoices, nil
+	}
+
+	now := time.Now()
+	var invoicesWithStatus []*Invoice
+	for _, invoice := range invoices {
+		if invoice.StatusOn(now) == status {
+			invoicesWithStatus = append(invoicesWithStatus, invoice)
+		}
+	}
+	return invoicesWithStatus, nil
+}
+
+// ReadInvoice reads an invoice with its payments
+func (s *InvoiceService) ReadInvoice(ctx context.Context, principal *shared.Principal, invoiceID uuid.UUID) (*Invoice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.invoiceRepository.FindInvoiceByID(ctx, principal.OrganizationID, invoiceID)
+}
+
+// CreateInvoice issues the statement of the client in the month as draft invoice, with the same number
+// and amounts as in the accounting export
+func (s *InvoiceService) CreateInvoice(ctx context.Context, principal *shared.Principal, clientID uuid.UUID, month time.Time, recipientEMail string) (*Invoice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	accountingInvoice, err := s.accountingService.findAccountingInvoiceOfClient(ctx, principal.OrganizationID, clientID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := NewInvoice(principal.OrganizationID, accountingInvoice, recipientEMail, time.Now())
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.invoiceRepository.InsertInvoice(ctx, invoice)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// UpdateInvoice changes the due day and the recipient of reminders of an invoice not yet paid or written off
+func (s *InvoiceService) UpdateInvoice(ctx context.Context, principal *shared.Principal, invoiceID uuid.UUID, dueDay time.Time, recipientEMail string) (*Invoice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	invoice, err := s.invoiceRepository.FindInvoiceByID(ctx, principal.OrganizationID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status != InvoiceStatusDraft && invoice.Status != InvoiceStatusSent {
+		return nil, ErrInvalidInvoiceStatusTransition
+	}
+
+	invoice.DueDay = dueDay
+	invoice.RecipientEMail = recipientEMail
+
+	return s.updateInvoice(ctx, invoice)
+}
+
+// DeleteInvoice removes a draft invoice
+func (s *InvoiceService) DeleteInvoice(ctx context.Context, principal *shared.Principal, invoiceID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	invoice, err := s.invoiceRepository.FindInvoiceByID(ctx, principal.OrganizationID, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	if invoice.Status != InvoiceStatusDraft {
+		return ErrInvoiceNotDeletable
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.invoiceRepository.DeleteInvoice(ctx, principal.OrganizationID, invoiceID)
+		},
+	)
+}
+
+// SendInvoice marks a draft invoice as sent to the client
+func (s *InvoiceService) SendInvoice(ctx context.Context, principal *shared.Principal, invoiceID uuid.UUID) (*Invoice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	invoice, err := s.invoiceRepository.FindInvoiceByID(ctx, principal.OrganizationID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = invoice.Send(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateInvoice(ctx, invoice)
+}
+
+// WriteOffInvoice gives up on the open amount of a sent invoice
+func (s *InvoiceService) WriteOffInvoice(ctx context.Context, principal *shared.Principal, invoiceID uuid.UUID) (*Invoice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	invoice, err := s.invoiceRepository.FindInvoiceByID(ctx, principal.OrganizationID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = invoice.WriteOff()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateInvoice(ctx, invoice)
+}
+
+// AddInvoicePayment records a full or partial payment of a sent invoice
+func (s *InvoiceService) AddInvoicePayment(ctx context.Context, principal *shared.Principal, invoiceID uuid.UUID, payment *InvoicePayment) (*Invoice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	invoice, err := s.invoiceRepository.FindInvoiceByID(ctx, principal.OrganizationID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment.ID = uuid.New()
+	payment.InvoiceID = invoice.ID
+	payment.OrganizationID = principal.OrganizationID
+
+	err = invoice.AddPayment(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.invoiceRepository.InsertInvoicePayment(ctx, payment)
+		},
+		func(ctx context.Context) error {
+			return s.invoiceRepository.UpdateInvoice(ctx, invoice)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// DeleteInvoicePayment removes a payment recorded by mistake
+func (s *InvoiceService) DeleteInvoicePayment(ctx context.Context, principal *shared.Principal, invoiceID, paymentID uuid.UUID) (*Invoice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	invoice, err := s.invoiceRepository.FindInvoiceByID(ctx, principal.OrganizationID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = invoice.RemovePayment(paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.invoiceRepository.DeleteInvoicePayment(ctx, principal.OrganizationID, invoiceID, paymentID)
+		},
+		func(ctx context.Context) error {
+			return s.invoiceRepository.UpdateInvoice(ctx, invoice)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// RemindOverdueInvoices emails the clients of all organizations about their overdue invoices,
+// once per reminder interval
+func (s *InvoiceService) RemindOverdueInvoices(ctx context.Context, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	invoices, err := s.invoiceRepository.FindInvoicesDueForReminder(ctx, today, now.AddDate(0, 0, -invoiceReminderIntervalDays))
+	if err != nil {
+		return err
+	}
+
+	reminded := 0
+	for _, invoice := range invoices {
+		if !invoice.IsDueForReminder(now) {
+			continue
+		}
+
+		err := s.remindOverdueInvoice(ctx, invoice, now)
+		if err != nil {
+			return err
+		}
+		reminded++
+	}
+
+	if reminded > 0 {
+		log.Printf("reminded clients about %v overdue invoices", reminded)
+	}
+	return nil
+}
+
+func (s *InvoiceService) remindOverdueInvoice(ctx context.Context, invoice *Invoice, now time.Time) error {
+	body := &bytes.Buffer{}
+	err := invoiceReminderTemplate.Execute(body, &invoiceReminder{
+		Invoice:    invoice,
+		OpenAmount: formatCents(invoice.OpenAmountCents()),
+	})
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Payment reminder for invoice %s", invoice.Number)
+	invoice.LastReminderAt = &now
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.outbox.SendMail(ctx, invoice.OrganizationID, invoice.RecipientEMail, subject, body.String())
+		},
+		func(ctx context.Context) error {
+			return s.invoiceRepository.UpdateInvoice(ctx, invoice)
+		},
+	)
+}
+
+func (s *InvoiceService) updateInvoice(ctx context.Context, invoice *Invoice) (*Invoice, error) {
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.invoiceRepository.UpdateInvoice(ctx, invoice)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (s *InvoiceService) handleInvoiceReminderJob(ctx context.Context, job *shared.Job) error {
+	return s.RemindOverdueInvoices(ctx, time.Now())
+}
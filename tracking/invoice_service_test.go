@@ -0,0 +1,136 @@
+package tracking
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func newInMemInvoiceService(mailResource shared.MailResource, invoiceRepository InvoiceRepository) *InvoiceService {
+	repositoryTxer := shared.NewInMemRepositoryTxer()
+	clientRepository := NewInMemClientRepository()
+	taxRepository := NewInMemTaxRepository()
+	_ = taxRepository.UpdateTaxSettings(context.Background(), &TaxSettings{OrganizationID: shared.OrganizationIDSample, RateBasisPoints: 1900})
+
+	return NewInvoiceService(
+		&shared.Config{InvoiceReminders: true},
+		repositoryTxer,
+		shared.NewInMemOutbox(mailResource),
+		shared.NewJobService(repositoryTxer, shared.NewInMemJobRepository()),
+		invoiceRepository,
+		clientRepository,
+		NewAccountingService(repositoryTxer, NewInMemAccountingRepository(), NewInMemEInvoiceRepository(), taxRepository, clientRepository),
+	)
+}
+
+func TestInvoiceService(t *testing.T) {
+	is := is.New(t)
+
+	s := newInMemInvoiceService(shared.NewInMemMailResource(), NewInMemInvoiceRepository())
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.CreateInvoice(context.Background(), user, clientIDSample, month, "billing@acme.com")
+	is.Equal(err, shared.ErrForbidden)
+
+	_, err = s.CreateInvoice(context.Background(), admin, uuid.New(), month, "billing@acme.com")
+	is.Equal(err, ErrClientNotFound)
+
+	invoice, err := s.CreateInvoice(context.Background(), admin, clientIDSample, month, "billing@acme.com")
+	is.NoErr(err)
+	is.Equal(invoice.Number, "2024-03-01")
+	is.Equal(invoice.Status, InvoiceStatusDraft)
+	is.Equal(invoice.GrossAmountCents(), 16065)
+
+	_, err = s.CreateInvoice(context.Background(), admin, clientIDSample, month, "billing@acme.com")
+	is.Equal(err, ErrInvoiceNumberTaken)
+
+	_, err = s.AddInvoicePayment(context.Background(), admin, invoice.ID, &InvoicePayment{AmountCents: 1000, PaidOn: month})
+	is.Equal(err, ErrInvalidInvoiceStatusTransition)
+
+	invoice, err = s.SendInvoice(context.Background(), admin, invoice.ID)
+	is.NoErr(err)
+	is.Equal(invoice.Status, InvoiceStatusSent)
+
+	err = s.DeleteInvoice(context.Background(), admin, invoice.ID)
+	is.Equal(err, ErrInvoiceNotDeletable)
+
+	invoice, err = s.AddInvoicePayment(context.Background(), admin, invoice.ID, &InvoicePayment{AmountCents: 10000, PaidOn: month})
+	is.NoErr(err)
+	is.Equal(invoice.OpenAmountCents(), 6065)
+
+	invoice, err = s.AddInvoicePayment(context.Background(), admin, invoice.ID, &InvoicePayment{AmountCents: 6065, PaidOn: month})
+	is.NoErr(err)
+	is.Equal(invoice.Status, InvoiceStatusPaid)
+
+	paidInvoices, err := s.ReadInvoices(context.Background(), admin, InvoiceStatusPaid)
+	is.NoErr(err)
+	is.Equal(len(paidInvoices), 1)
+	is.Equal(len(paidInvoices[0].Payments), 2)
+
+	invoice, err = s.DeleteInvoicePayment(context.Background(), admin, invoice.ID, invoice.Payments[1].ID)
+	is.NoErr(err)
+	is.Equal(invoice.Status, InvoiceStatusSent)
+
+	invoice, err = s.WriteOffInvoice(context.Background(), admin, invoice.ID)
+	is.NoErr(err)
+	is.Equal(invoice.Status, InvoiceStatusWrittenOff)
+
+	_, err = s.UpdateInvoice(context.Background(), admin, invoice.ID, month, "")
+	is.Equal(err, ErrInvalidInvoiceStatusTransition)
+}
+
+func TestRemindOverdueInvoices(t *testing.T) {
+	is := is.New(t)
+
+	invoiceRepository := NewInMemInvoiceRepository()
+	sentAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	invoiceRepository.invoices = []*Invoice{
+		{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			ClientID:       clientIDSample,
+			ClientName:     "ACME Corp.",
+			Number:         "2024-03-01",
+			Day:            time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+			DueDay:         time.Date(2024, 4, 14, 0, 0, 0, 0, time.UTC),
+			Currency:       "EUR",
+			NetAmountCents: 13500,
+			TaxAmountCents: 2565,
+			RecipientEMail: "billing@acme.com",
+			Status:         InvoiceStatusSent,
+			SentAt:         &sentAt,
+			Payments:       []*InvoicePayment{{ID: uuid.New(), AmountCents: 6065}},
+		},
+	}
+
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemInvoiceService(mailResource, invoiceRepository)
+
+	// not overdue on the due day
+	err := s.RemindOverdueInvoices(context.Background(), time.Date(2024, 4, 14, 12, 0, 0, 0, time.UTC))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 0)
+
+	now := time.Date(2024, 4, 15, 12, 0, 0, 0, time.UTC)
+	err = s.RemindOverdueInvoices(context.Background(), now)
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 1)
+	is.True(strings.HasPrefix(mailResource.Mails[0], "billing@acme.com"))
+	is.True(strings.Contains(mailResource.Mails[0], "The open amount is 100.00 EUR."))
+
+	// reminded once per interval
+	err = s.RemindOverdueInvoices(context.Background(), now.AddDate(0, 0, 3))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 1)
+
+	err = s.RemindOverdueInvoices(context.Background(), now.AddDate(0, 0, invoiceReminderIntervalDays))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 2)
+}
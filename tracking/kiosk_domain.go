@@ -0,0 +1,103 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	maxKioskDeviceNameLength = 100
+	maxKioskBadgeCodeLength  = 100
+
+	// maxKioskFailedAttemptsPerUser is the number of failed identifications of a user until the user is locked out
+	maxKioskFailedAttemptsPerUser = 5
+	// maxKioskFailedAttemptsPerDevice is the number of failed identifications at a device until the device is locked out
+	maxKioskFailedAttemptsPerDevice = 20
+	// kioskLockoutDuration is the window of the failed identifications and how long a user or device is locked out
+	kioskLockoutDuration = 15 * time.Minute
+)
+
+var kioskPINPattern = regexp.MustCompile(`^[0-9]{4,8}$`)
+
+var (
+	ErrKioskDeviceNotFound       = shared.NewDomainError("kiosk:device-not-found", http.StatusNotFound, "kiosk device not found")
+	ErrKioskDeviceUnauthorized   = shared.NewDomainError("kiosk:device-unauthorized", http.StatusUnauthorized, "kiosk device token not valid")
+	ErrKioskCredentialNotFound   = shared.NewDomainError("kiosk:credential-not-found", http.StatusNotFound, "kiosk credential not found")
+	ErrKioskIdentificationFailed = shared.NewDomainError("kiosk:identification-failed", http.StatusUnauthorized, "user could not be identified by PIN or badge code")
+	ErrKioskLockedOut            = shared.NewDomainError("kiosk:locked-out", http.StatusTooManyRequests, "too many failed identifications, try again later")
+)
+
+// KioskDevice is a shared device like a tablet that clocks users in and out, it's authenticated by its token
+type KioskDevice struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Name           string
+	TokenHash      string
+	CreatedAt      time.Time
+	LastUsedAt     *time.Time
+
+	// Token is only known right after the device is registered as just the hash is stored
+	Token string
+}
+
+// KioskCredential identifies a user at kiosk devices by the username and PIN or by the badge code alone
+type KioskCredential struct {
+	OrganizationID uuid.UUID
+	Username       string
+	PINHash        string
+	BadgeCodeHash  string
+
+	// BadgeCode is only known right after the badge code is set as just the hash is stored
+	BadgeCode string
+}
+
+// KioskIdentification is what a user enters at a kiosk device, either the username and PIN or the badge code
+type KioskIdentification struct {
+	Username  string
+	PIN       string
+	BadgeCode string
+}
+
+type KioskRepository interface {
+	FindKioskDevices(ctx context.Context, organizationID uuid.UUID) ([]*KioskDevice, error)
+	FindKioskDeviceByTokenHash(ctx context.Context, tokenHash string) (*KioskDevice, error)
+	InsertKioskDevice(ctx context.Context, device *KioskDevice) error
+	MarkKioskDeviceUsed(ctx context.Context, organizationID, deviceID uuid.UUID, usedAt time.Time) error
+	DeleteKioskDevice(ctx context.Context, organizationID, deviceID uuid.UUID) error
+	FindKioskCredential(ctx context.Context, organizationID uuid.UUID, username string) (*KioskCredential, error)
+	FindKioskCredentialByBadgeCodeHash(ctx context.Context, organizationID uuid.UUID, badgeCodeHash string) (*KioskCredential, error)
+	UpdateKioskCredential(ctx context.Context, credential *KioskCredential) error
+	DeleteKioskCredential(ctx context.Context, organizationID uuid.UUID, username string) error
+}
+
+// ValidateKioskDeviceName checks that the name is given and not too long
+func ValidateKioskDeviceName(name string) error {
+	if name == "" {
+		return shared.NewInvalidParam("name", "required", "name is required")
+	}
+	if utf8.RuneCountInString(name) > maxKioskDeviceNameLength {
+		return shared.NewInvalidParam("name", "max", "name must not be longer than 100 characters")
+	}
+	return nil
+}
+
+// ValidateKioskCredential checks that the PIN has 4 to 8 digits and the badge code is not too long,
+// at least one of them is required
+func ValidateKioskCredential(pin, badgeCode string) error {
+	if pin == "" && badgeCode == "" {
+		return shared.NewInvalidParam("pin", "required", "pin or badge code is required")
+	}
+	if pin != "" && !kioskPINPattern.MatchString(pin) {
+		return shared.NewInvalidParam("pin", "pattern", "pin must have 4 to 8 digits")
+	}
+	if utf8.RuneCountInString(badgeCode) > maxKioskBadgeCodeLength {
+		return shared.NewInvalidParam("badgeCode", "max", "badge code must not be longer than 100 characters")
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestValidateKioskCredential(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(ValidateKioskCredential("1234", ""))
+	is.NoErr(ValidateKioskCredential("", "B-42"))
+	is.True(ValidateKioskCredential("", "") != nil)
+	is.True(ValidateKioskCredential("123", "") != nil)
+	is.True(ValidateKioskCredential("12ab", "") != nil)
+	is.True(ValidateKioskCredential("", strings.Repeat("x", 101)) != nil)
+}
@@ -0,0 +1,245 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbKioskRepository is a SQL database repository for kiosk devices and the kiosk credentials of users
+type DbKioskRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ KioskRepository = (*DbKioskRepository)(nil)
+
+// NewDbKioskRepository creates a new SQL database repository for kiosk devices
+func NewDbKioskRepository(connPool *pgxpool.Pool) *DbKioskRepository {
+	return &DbKioskRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbKioskRepository) FindKioskDevices(ctx context.Context, organizationID uuid.UUID) ([]*KioskDevice, error) {
+	rows, err := shared.SelectAll[kioskDeviceRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[kioskDeviceRow]()+`
+		 FROM kiosk_devices
+		 WHERE org_id = $1
+		 ORDER BY name ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]*KioskDevice, len(rows))
+	for i, row := range rows {
+		devices[i] = row.toKioskDevice()
+	}
+	return devices, nil
+}
+
+// FindKioskDeviceByTokenHash reads the device of the token across all organizations
+func (r *DbKioskRepository) FindKioskDeviceByTokenHash(ctx context.Context, tokenHash string) (*KioskDevice, error) {
+	row, err := shared.SelectOne[kioskDeviceRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[kioskDeviceRow]()+`
+		 FROM kiosk_devices
+		 WHERE token_hash = $1`,
+		tokenHash,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrKioskDeviceNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toKioskDevice(), nil
+}
+
+func (r *DbKioskRepository) InsertKioskDevice(ctx context.Context, device *KioskDevice) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO kiosk_devices
+		   (device_id, org_id, name, token_hash, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5)`,
+		device.ID,
+		device.OrganizationID,
+		device.Name,
+		device.TokenHash,
+		device.CreatedAt,
+	)
+	return err
+}
+
+func (r *DbKioskRepository) MarkKioskDeviceUsed(ctx context.Context, organizationID, deviceID uuid.UUID, usedAt time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE kiosk_devices
+		 SET last_used_at = $3
+		 WHERE device_id = $1 AND org_id = $2`,
+		deviceID, organizationID, usedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrKioskDeviceNotFound
+	}
+	return nil
+}
+
+func (r *DbKioskRepository) DeleteKioskDevice(ctx context.Context, organizationID, deviceID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM kiosk_devices
+		 WHERE device_id = $1 AND org_id = $2`,
+		deviceID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrKioskDeviceNotFound
+	}
+	return nil
+}
+
+func (r *DbKioskRepository) FindKioskCredential(ctx context.Context, organizationID uuid.UUID, username string) (*KioskCredential, error) {
+	row, err := shared.SelectOne[kioskCredentialRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[kioskCredentialRow]()+`
+		 FROM kiosk_credentials
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrKioskCredentialNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toKioskCredential(), nil
+}
+
+func (r *DbKioskRepository) FindKioskCredentialByBadgeCodeHash(ctx context.Context, organizationID uuid.UUID, badgeCodeHash string) (*KioskCredential, error) {
+	row, err := shared.SelectOne[kioskCredentialRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[kioskCredentialRow]()+`
+		 FROM kiosk_credentials
+		 WHERE org_id = $1 AND badge_code_hash = $2`,
+		organizationID, badgeCodeHash,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrKioskCredentialNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toKioskCredential(), nil
+}
+
+func (r *DbKioskRepository) UpdateKioskCredential(ctx context.Context, credential *KioskCredential) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO kiosk_credentials
+		   (org_id, username, pin_hash, badge_code_hash)
+		 VALUES
+		   ($1, $2, $3, $4)
+		 ON CONFLICT (org_id, username) DO UPDATE
+		 SET pin_hash = $3, badge_code_hash = $4`,
+		credential.OrganizationID,
+		credential.Username,
+		sql.NullString{String: credential.PINHash, Valid: credential.PINHash != ""},
+		sql.NullString{String: credential.BadgeCodeHash, Valid: credential.BadgeCodeHash != ""},
+	)
+	return err
+}
+
+func (r *DbKioskRepository) DeleteKioskCredential(ctx context.Context, organizationID uuid.UUID, username string) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM kiosk_credentials
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrKioskCredentialNotFound
+	}
+	return nil
+}
+
+type kioskDeviceRow struct {
+	ID             uuid.UUID  `db:"device_id"`
+	OrganizationID uuid.UUID  `db:"org_id"`
+	Name           string     `db:"name"`
+	TokenHash      string     `db:"token_hash"`
+	CreatedAt      time.Time  `db:"created_at"`
+	LastUsedAt     *time.Time `db:"last_used_at"`
+}
+
+func (r *kioskDeviceRow) toKioskDevice() *KioskDevice {
+	return &KioskDevice{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Name:           r.Name,
+		TokenHash:      r.TokenHash,
+		CreatedAt:      r.CreatedAt,
+		LastUsedAt:     r.LastUsedAt,
+	}
+}
+
+type kioskCredentialRow struct {
+	OrganizationID uuid.UUID `db:"org_id"`
+	Username       string    `db:"username"`
+	PINHash        *string   `db:"pin_hash"`
+	BadgeCodeHash  *string   `db:"badge_code_hash"`
+}
+
+func (r *kioskCredentialRow) toKioskCredential() *KioskCredential {
+	credential := &KioskCredential{
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+	}
+	if r.PINHash != nil {
+		credential.PINHash = *r.PINHash
+	}
+	if r.BadgeCodeHash != nil {
+		credential.BadgeCodeHash = *r.BadgeCodeHash
+	}
+	return credential
+}
@@ -0,0 +1,93 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestKioskRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	kioskRepository := NewDbKioskRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("RegisterAndDeleteDevice", func(t *testing.T) {
+		device := &KioskDevice{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			Name:           "Entrance",
+			TokenHash:      shared.HashToken("my-token"),
+			CreatedAt:      time.Now(),
+		}
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return kioskRepository.InsertKioskDevice(ctx, device)
+			},
+			func(ctx context.Context) error {
+				return kioskRepository.MarkKioskDeviceUsed(ctx, device.OrganizationID, device.ID, time.Now())
+			},
+		)
+		is.NoErr(err)
+
+		found, err := kioskRepository.FindKioskDeviceByTokenHash(context.Background(), shared.HashToken("my-token"))
+		is.NoErr(err)
+		is.Equal(found.ID, device.ID)
+		is.True(found.LastUsedAt != nil)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return kioskRepository.DeleteKioskDevice(ctx, device.OrganizationID, device.ID)
+			},
+		)
+		is.NoErr(err)
+
+		devices, err := kioskRepository.FindKioskDevices(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(devices), 0)
+	})
+
+	t.Run("UpdateCredential", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return kioskRepository.UpdateKioskCredential(ctx, &KioskCredential{
+					OrganizationID: shared.OrganizationIDSample,
+					Username:       "user1",
+					BadgeCodeHash:  shared.HashToken("B-42"),
+				})
+			},
+		)
+		is.NoErr(err)
+
+		credential, err := kioskRepository.FindKioskCredentialByBadgeCodeHash(context.Background(), shared.OrganizationIDSample, shared.HashToken("B-42"))
+		is.NoErr(err)
+		is.Equal(credential.Username, "user1")
+		is.Equal(credential.PINHash, "")
+	})
+}
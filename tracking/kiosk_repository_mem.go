@@ -0,0 +1,138 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemKioskRepository struct {
+	mu          sync.Mutex
+	devices     []*KioskDevice
+	credentials []*KioskCredential
+}
+
+var _ KioskRepository = (*InMemKioskRepository)(nil)
+
+func NewInMemKioskRepository() *InMemKioskRepository {
+	return &InMemKioskRepository{}
+}
+
+func (r *InMemKioskRepository) FindKioskDevices(ctx context.Context, organizationID uuid.UUID) ([]*KioskDevice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var devices []*KioskDevice
+	for _, device := range r.devices {
+		if device.OrganizationID == organizationID {
+			found := *device
+			devices = append(devices, &found)
+		}
+	}
+	return devices, nil
+}
+
+func (r *InMemKioskRepository) FindKioskDeviceByTokenHash(ctx context.Context, tokenHash string) (*KioskDevice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, device := range r.devices {
+		if device.TokenHash == tokenHash {
+			found := *device
+			return &found, nil
+		}
+	}
+	return nil, ErrKioskDeviceNotFound
+}
+
+func (r *InMemKioskRepository) InsertKioskDevice(ctx context.Context, device *KioskDevice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *device
+	inserted.Token = ""
+	r.devices = append(r.devices, &inserted)
+	return nil
+}
+
+func (r *InMemKioskRepository) MarkKioskDeviceUsed(ctx context.Context, organizationID, deviceID uuid.UUID, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, device := range r.devices {
+		if device.OrganizationID == organizationID && device.ID == deviceID {
+			device.LastUsedAt = &usedAt
+			return nil
+		}
+	}
+	return ErrKioskDeviceNotFound
+}
+
+func (r *InMemKioskRepository) DeleteKioskDevice(ctx context.Context, organizationID, deviceID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, device := range r.devices {
+		if device.OrganizationID == organizationID && device.ID == deviceID {
+			r.devices = append(r.devices[:i], r.devices[i+1:]...)
+			return nil
+		}
+	}
+	return ErrKioskDeviceNotFound
+}
+
+func (r *InMemKioskRepository) FindKioskCredential(ctx context.Context, organizationID uuid.UUID, username string) (*KioskCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, credential := range r.credentials {
+		if credential.OrganizationID == organizationID && credential.Username == username {
+			found := *credential
+			return &found, nil
+		}
+	}
+	return nil, ErrKioskCredentialNotFound
+}
+
+func (r *InMemKioskRepository) FindKioskCredentialByBadgeCodeHash(ctx context.Context, organizationID uuid.UUID, badgeCodeHash string) (*KioskCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, credential := range r.credentials {
+		if credential.OrganizationID == organizationID && credential.BadgeCodeHash != "" && credential.BadgeCodeHash == badgeCodeHash {
+			found := *credential
+			return &found, nil
+		}
+	}
+	return nil, ErrKioskCredentialNotFound
+}
+
+func (r *InMemKioskRepository) UpdateKioskCredential(ctx context.Context, credential *KioskCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := *credential
+	for i, c := range r.credentials {
+		if c.OrganizationID == credential.OrganizationID && c.Username == credential.Username {
+			r.credentials[i] = &updated
+			return nil
+		}
+	}
+	r.credentials = append(r.credentials, &updated)
+	return nil
+}
+
+func (r *InMemKioskRepository) DeleteKioskCredential(ctx context.Context, organizationID uuid.UUID, username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, credential := range r.credentials {
+		if credential.OrganizationID == organizationID && credential.Username == username {
+			r.credentials = append(r.credentials[:i], r.credentials[i+1:]...)
+			return nil
+		}
+	}
+	return ErrKioskCredentialNotFound
+}
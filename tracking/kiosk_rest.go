@@ -0,0 +1,369 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type kioskContextKey int
+
+// contextKeyKioskDevice is the device authenticated by its token on kiosk requests
+const contextKeyKioskDevice kioskContextKey = 0
+
+type kioskDeviceModel struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Token      string     `json:"token,omitempty"`
+	CreatedAt  string     `json:"createdAt"`
+	LastUsedAt string     `json:"lastUsedAt,omitempty"`
+	Links      *hal.Links `json:"_links"`
+}
+
+type kioskDevicesModel struct {
+	Embedded *embeddedKioskDevices `json:"_embedded"`
+	Links    *hal.Links            `json:"_links"`
+}
+
+type embeddedKioskDevices struct {
+	KioskDeviceModels []*kioskDeviceModel `json:"devices"`
+}
+
+type kioskCredentialModel struct {
+	Username  string `json:"username"`
+	PIN       string `json:"pin,omitempty"`
+	BadgeCode string `json:"badgeCode,omitempty"`
+}
+
+type kioskIdentificationModel struct {
+	Username  string `json:"username"`
+	PIN       string `json:"pin"`
+	BadgeCode string `json:"badgeCode"`
+	TagCode   string `json:"tagCode,omitempty"`
+}
+
+type KioskRestHandlers struct {
+	config       *shared.Config
+	kioskService *KioskService
+}
+
+func NewKioskRestHandlers(config *shared.Config, kioskService *KioskService) *KioskRestHandlers {
+	return &KioskRestHandlers{
+		config:       config,
+		kioskService: kioskService,
+	}
+}
+
+func (a *KioskRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/kiosk/devices", a.HandleGetKioskDevices())
+	r.Post("/kiosk/devices", a.HandleRegisterKioskDevice())
+	r.Delete("/kiosk/devices/{device-id}", a.HandleDeleteKioskDevice())
+	r.Put("/kiosk/credentials/{username}", a.HandleUpdateKioskCredential())
+	r.Delete("/kiosk/credentials/{username}", a.HandleDeleteKioskCredential())
+	r.Post("/kiosk/credentials/{username}/badge", a.HandleProvisionKioskBadge())
+	r.Delete("/kiosk/credentials/{username}/badge", a.HandleRevokeKioskBadge())
+}
+
+func (a *KioskRestHandlers) RegisterOpen(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(a.KioskDeviceMiddleware())
+		r.Post("/kiosk/clock-in", a.HandleKioskClockIn())
+		r.Post("/kiosk/clock-out", a.HandleKioskClockOut())
+		r.Post("/kiosk/scan", a.HandleKioskScan())
+	})
+}
+
+// KioskDeviceMiddleware authenticates the device by the bearer token
+func (a *KioskRestHandlers) KioskDeviceMiddleware() func(next http.Handler) http.Handler {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+			device, err := kioskService.AuthenticateKioskDevice(r.Context(), token, time.Now())
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+
+			ctx := shared.WithOrganizationID(r.Context(), device.OrganizationID)
+			ctx = context.WithValue(ctx, contextKeyKioskDevice, device)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// HandleGetKioskDevices reads the kiosk devices of the organization
+func (a *KioskRestHandlers) HandleGetKioskDevices() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		devices, err := kioskService.ReadKioskDevices(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		deviceModels := make([]*kioskDeviceModel, len(devices))
+		for i, device := range devices {
+			deviceModels[i] = mapToKioskDeviceModel(device)
+		}
+
+		shared.RenderJSON(w, &kioskDevicesModel{
+			Embedded: &embeddedKioskDevices{
+				KioskDeviceModels: deviceModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleRegisterKioskDevice registers a kiosk device, the token of the device is only shown in the response
+func (a *KioskRestHandlers) HandleRegisterKioskDevice() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var deviceModel kioskDeviceModel
+		err := json.NewDecoder(r.Body).Decode(&deviceModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "kiosk device not valid", err)
+			return
+		}
+
+		err = ValidateKioskDeviceName(deviceModel.Name)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "kiosk device not valid", err)
+			return
+		}
+
+		device, err := kioskService.RegisterKioskDevice(r.Context(), principal, deviceModel.Name)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToKioskDeviceModel(device))
+	}
+}
+
+// HandleDeleteKioskDevice removes a kiosk device
+func (a *KioskRestHandlers) HandleDeleteKioskDevice() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		deviceID, err := uuid.Parse(chi.URLParam(r, "device-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = kioskService.DeleteKioskDevice(r.Context(), principal, deviceID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+// HandleUpdateKioskCredential sets the PIN and badge code of a user
+func (a *KioskRestHandlers) HandleUpdateKioskCredential() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var credentialModel kioskCredentialModel
+		err := json.NewDecoder(r.Body).Decode(&credentialModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "kiosk credential not valid", err)
+			return
+		}
+
+		err = ValidateKioskCredential(credentialModel.PIN, credentialModel.BadgeCode)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "kiosk credential not valid", err)
+			return
+		}
+
+		credential, err := kioskService.UpdateKioskCredential(r.Context(), principal, chi.URLParam(r, "username"), credentialModel.PIN, credentialModel.BadgeCode)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, &kioskCredentialModel{
+			Username:  credential.Username,
+			BadgeCode: credential.BadgeCode,
+		})
+	}
+}
+
+// HandleDeleteKioskCredential removes the PIN and badge code of a user
+func (a *KioskRestHandlers) HandleDeleteKioskCredential() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		err := kioskService.DeleteKioskCredential(r.Context(), principal, chi.URLParam(r, "username"))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+// HandleProvisionKioskBadge sets a new badge code of a user
+func (a *KioskRestHandlers) HandleProvisionKioskBadge() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		credential, err := kioskService.ProvisionKioskBadge(r.Context(), principal, chi.URLParam(r, "username"))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, &kioskCredentialModel{
+			Username:  credential.Username,
+			BadgeCode: credential.BadgeCode,
+		})
+	}
+}
+
+// HandleRevokeKioskBadge removes the badge code of a user
+func (a *KioskRestHandlers) HandleRevokeKioskBadge() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		err := kioskService.RevokeKioskBadge(r.Context(), principal, chi.URLParam(r, "username"))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+// HandleKioskClockIn clocks in the user identified by PIN or badge code at the device
+func (a *KioskRestHandlers) HandleKioskClockIn() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(w http.ResponseWriter, r *http.Request) {
+		device := r.Context().Value(contextKeyKioskDevice).(*KioskDevice)
+
+		var identificationModel kioskIdentificationModel
+		err := json.NewDecoder(r.Body).Decode(&identificationModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "identification not valid", err)
+			return
+		}
+
+		stamp, err := kioskService.KioskClockIn(r.Context(), device, mapToKioskIdentification(&identificationModel), time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToAttendanceStampModel(stamp))
+	}
+}
+
+// HandleKioskClockOut clocks out the user identified by PIN or badge code at the device
+func (a *KioskRestHandlers) HandleKioskClockOut() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(w http.ResponseWriter, r *http.Request) {
+		device := r.Context().Value(contextKeyKioskDevice).(*KioskDevice)
+
+		var identificationModel kioskIdentificationModel
+		err := json.NewDecoder(r.Body).Decode(&identificationModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "identification not valid", err)
+			return
+		}
+
+		stamp, err := kioskService.KioskClockOut(r.Context(), device, mapToKioskIdentification(&identificationModel), time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToAttendanceStampModel(stamp))
+	}
+}
+
+// HandleKioskScan starts or stops tracking of the user identified at the device for the project of the scanned tag
+func (a *KioskRestHandlers) HandleKioskScan() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	kioskService := a.kioskService
+	return func(w http.ResponseWriter, r *http.Request) {
+		device := r.Context().Value(contextKeyKioskDevice).(*KioskDevice)
+
+		var identificationModel kioskIdentificationModel
+		err := json.NewDecoder(r.Body).Decode(&identificationModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "identification not valid", err)
+			return
+		}
+
+		result, err := kioskService.KioskScan(r.Context(), device, mapToKioskIdentification(&identificationModel), identificationModel.TagCode, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToScanResultModel(result))
+	}
+}
+
+func mapToKioskDeviceModel(device *KioskDevice) *kioskDeviceModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/kiosk/devices/%s", device.ID))
+	deviceModel := &kioskDeviceModel{
+		ID:        device.ID.String(),
+		Name:      device.Name,
+		Token:     device.Token,
+		CreatedAt: time_utils.FormatDateTime(device.CreatedAt),
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+	if device.LastUsedAt != nil {
+		deviceModel.LastUsedAt = time_utils.FormatDateTime(*device.LastUsedAt)
+	}
+	return deviceModel
+}
+
+func mapToKioskIdentification(identificationModel *kioskIdentificationModel) *KioskIdentification {
+	return &KioskIdentification{
+		Username:  identificationModel.Username,
+		PIN:       identificationModel.PIN,
+		BadgeCode: identificationModel.BadgeCode,
+	}
+}
@@ -0,0 +1,67 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleKioskClockIn(t *testing.T) {
+	is := is.New(t)
+
+	kioskService := NewKioskService(shared.NewInMemRepositoryTxer(), NewInMemKioskRepository(), newAttendanceServiceForTest(t, true), nil)
+	a := NewKioskRestHandlers(&shared.Config{}, kioskService)
+
+	r := chi.NewRouter()
+	a.RegisterOpen(r)
+	r.Group(func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+					OrganizationID: shared.OrganizationIDSample,
+					Username:       "admin",
+					Roles:          []string{"ROLE_ADMIN"},
+				})))
+			})
+		})
+		a.RegisterProtected(r)
+	})
+
+	httpRec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/kiosk/devices", strings.NewReader(`{"name": "Entrance"}`))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	deviceModel := &kioskDeviceModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(deviceModel)
+	is.NoErr(err)
+	is.True(deviceModel.Token != "")
+
+	httpRec = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/kiosk/credentials/user1", strings.NewReader(`{"badgeCode": "B-42"}`))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/kiosk/clock-in", strings.NewReader(`{"badgeCode": "B-42"}`))
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusUnauthorized)
+
+	httpRec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/kiosk/clock-in", strings.NewReader(`{"badgeCode": "B-42"}`))
+	req.Header.Set("Authorization", "Bearer "+deviceModel.Token)
+	r.ServeHTTP(httpRec, req)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	stampModel := &attendanceStampModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(stampModel)
+	is.NoErr(err)
+	is.Equal(stampModel.Username, "user1")
+}
@@ -0,0 +1,381 @@
+package tracking
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// kioskTokenBytes is the length of the random device tokens
+	kioskTokenBytes = 32
+
+	// kioskUnknownUserPINHash is compared for users without a PIN, so unknown users take as long as wrong PINs
+	kioskUnknownUserPINHash = "$2a$10$RctDUz8UfBVIo6s1tE.4we8pcBqNj63qnnkZTQWJAsxhwhGqNFCAW"
+)
+
+// KioskService manages the kiosk devices of an organization and clocks users in and out at the devices
+type KioskService struct {
+	repositoryTxer    shared.RepositoryTxer
+	kioskRepository   KioskRepository
+	attendanceService *AttendanceService
+	scanTagService    *ScanTagService
+	failedAttempts    *kioskFailedAttempts
+}
+
+// NewKioskService creates a new service for kiosk devices
+func NewKioskService(repositoryTxer shared.RepositoryTxer, kioskRepository KioskRepository, attendanceService *AttendanceService, scanTagService *ScanTagService) *KioskService {
+	return &KioskService{
+		repositoryTxer:    repositoryTxer,
+		kioskRepository:   kioskRepository,
+		attendanceService: attendanceService,
+		scanTagService:    scanTagService,
+		failedAttempts:    newKioskFailedAttempts(),
+	}
+}
+
+// ReadKioskDevices reads the devices of the organization, only admins manage devices
+func (s *KioskService) ReadKioskDevices(ctx context.Context, principal *shared.Principal) ([]*KioskDevice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.kioskRepository.FindKioskDevices(ctx, principal.OrganizationID)
+}
+
+// RegisterKioskDevice registers a device with a new token, the token is only returned here
+func (s *KioskService) RegisterKioskDevice(ctx context.Context, principal *shared.Principal, name string) (*KioskDevice, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	token, err := newKioskToken()
+	if err != nil {
+		return nil, err
+	}
+
+	device := &KioskDevice{
+		ID:             uuid.New(),
+		OrganizationID: principal.OrganizationID,
+		Name:           name,
+		TokenHash:      shared.HashToken(token),
+		CreatedAt:      time.Now(),
+		Token:          token,
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.kioskRepository.InsertKioskDevice(ctx, device)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// DeleteKioskDevice removes the device, its token is no longer valid
+func (s *KioskService) DeleteKioskDevice(ctx context.Context, principal *shared.Principal, deviceID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.kioskRepository.DeleteKioskDevice(ctx, principal.OrganizationID, deviceID)
+		},
+	)
+}
+
+// UpdateKioskCredential sets the PIN and badge code of a user, both are stored as hash
+func (s *KioskService) UpdateKioskCredential(ctx context.Context, principal *shared.Principal, username, pin, badgeCode string) (*KioskCredential, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	credential := &KioskCredential{
+		OrganizationID: principal.OrganizationID,
+		Username:       username,
+	}
+	if badgeCode != "" {
+		credential.BadgeCode = badgeCode
+		credential.BadgeCodeHash = shared.HashToken(badgeCode)
+	}
+	if pin != "" {
+		pinHash, err := bcrypt.GenerateFromPassword([]byte(pin), 10)
+		if err != nil {
+			return nil, err
+		}
+		credential.PINHash = string(pinHash)
+	}
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.kioskRepository.UpdateKioskCredential(ctx, credential)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+// DeleteKioskCredential removes the PIN and badge code of a user so the user can no longer clock in at devices
+func (s *KioskService) DeleteKioskCredential(ctx context.Context, principal *shared.Principal, username string) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.kioskRepository.DeleteKioskCredential(ctx, principal.OrganizationID, username)
+		},
+	)
+}
+
+// ProvisionKioskBadge sets a new random badge code for the user to print as QR code or write to an NFC tag,
+// a previous badge of the user is no longer valid
+func (s *KioskService) ProvisionKioskBadge(ctx context.Context, principal *shared.Principal, username string) (*KioskCredential, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	badgeCode, err := newKioskToken()
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.kioskRepository.FindKioskCredential(ctx, principal.OrganizationID, username)
+	if errors.Is(err, ErrKioskCredentialNotFound) {
+		credential = &KioskCredential{
+			OrganizationID: principal.OrganizationID,
+			Username:       username,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	credential.BadgeCode = badgeCode
+	credential.BadgeCodeHash = shared.HashToken(badgeCode)
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.kioskRepository.UpdateKioskCredential(ctx, credential)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+// RevokeKioskBadge removes the badge code of the user, the PIN of the user stays valid
+func (s *KioskService) RevokeKioskBadge(ctx context.Context, principal *shared.Principal, username string) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	credential, err := s.kioskRepository.FindKioskCredential(ctx, principal.OrganizationID, username)
+	if err != nil {
+		return err
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			if credential.PINHash == "" {
+				return s.kioskRepository.DeleteKioskCredential(ctx, principal.OrganizationID, username)
+			}
+
+			credential.BadgeCodeHash = ""
+			return s.kioskRepository.UpdateKioskCredential(ctx, credential)
+		},
+	)
+}
+
+// AuthenticateKioskDevice finds the device of the token and records its use
+func (s *KioskService) AuthenticateKioskDevice(ctx context.Context, token string, now time.Time) (*KioskDevice, error) {
+	if token == "" {
+		return nil, ErrKioskDeviceUnauthorized
+	}
+
+	device, err := s.kioskRepository.FindKioskDeviceByTokenHash(ctx, shared.HashToken(token))
+	if errors.Is(err, ErrKioskDeviceNotFound) {
+		return nil, ErrKioskDeviceUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = shared.WithOrganizationID(ctx, device.OrganizationID)
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.kioskRepository.MarkKioskDeviceUsed(ctx, device.OrganizationID, device.ID, now)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// KioskClockIn clocks in the user identified at the device
+func (s *KioskService) KioskClockIn(ctx context.Context, device *KioskDevice, identification *KioskIdentification, now time.Time) (*AttendanceStamp, error) {
+	principal, err := s.identify(ctx, device, identification, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.attendanceService.ClockIn(ctx, principal, now)
+}
+
+// KioskClockOut clocks out the user identified at the device
+func (s *KioskService) KioskClockOut(ctx context.Context, device *KioskDevice, identification *KioskIdentification, now time.Time) (*AttendanceStamp, error) {
+	principal, err := s.identify(ctx, device, identification, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.attendanceService.ClockOut(ctx, principal, now)
+}
+
+// KioskScan starts or stops tracking of the user identified at the device for the project of the scanned tag
+func (s *KioskService) KioskScan(ctx context.Context, device *KioskDevice, identification *KioskIdentification, code string, now time.Time) (*ScanResult, error) {
+	principal, err := s.identify(ctx, device, identification, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scanTagService.Scan(ctx, principal, code, now)
+}
+
+// identify finds the user by the badge code or by the username and PIN, the user acts as principal of the device's organization.
+// Devices and users are locked out after too many failed identifications, unknown users fail like wrong PINs.
+func (s *KioskService) identify(ctx context.Context, device *KioskDevice, identification *KioskIdentification, now time.Time) (*shared.Principal, error) {
+	deviceKey := "device:" + device.ID.String()
+	userKey := "user:" + device.OrganizationID.String() + ":" + identification.Username
+	if s.failedAttempts.isLockedOut(deviceKey, maxKioskFailedAttemptsPerDevice, now) {
+		return nil, ErrKioskLockedOut
+	}
+
+	var (
+		credential *KioskCredential
+		err        error
+	)
+
+	switch {
+	case identification.BadgeCode != "":
+		credential, err = s.kioskRepository.FindKioskCredentialByBadgeCodeHash(ctx, device.OrganizationID, shared.HashToken(identification.BadgeCode))
+	case identification.Username != "" && identification.PIN != "":
+		if s.failedAttempts.isLockedOut(userKey, maxKioskFailedAttemptsPerUser, now) {
+			return nil, ErrKioskLockedOut
+		}
+
+		credential, err = s.kioskRepository.FindKioskCredential(ctx, device.OrganizationID, identification.Username)
+		if err != nil && !errors.Is(err, ErrKioskCredentialNotFound) {
+			return nil, err
+		}
+
+		pinHash, hasPIN := kioskUnknownUserPINHash, false
+		if err == nil && credential.PINHash != "" {
+			pinHash, hasPIN = credential.PINHash, true
+		}
+		if bcrypt.CompareHashAndPassword([]byte(pinHash), []byte(identification.PIN)) != nil || !hasPIN {
+			s.failedAttempts.record(userKey, now)
+			err = ErrKioskIdentificationFailed
+		}
+	default:
+		err = ErrKioskIdentificationFailed
+	}
+
+	if errors.Is(err, ErrKioskCredentialNotFound) || errors.Is(err, ErrKioskIdentificationFailed) {
+		s.failedAttempts.record(deviceKey, now)
+		return nil, ErrKioskIdentificationFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.failedAttempts.reset(userKey)
+
+	return &shared.Principal{
+		Username:       credential.Username,
+		OrganizationID: device.OrganizationID,
+		Roles:          []string{"ROLE_USER"},
+	}, nil
+}
+
+func newKioskToken() (string, error) {
+	token := make([]byte, kioskTokenBytes)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// kioskFailedAttempts counts the failed identifications of devices and users within the lockout window,
+// the attempts are kept in memory
+type kioskFailedAttempts struct {
+	mu       sync.Mutex
+	attempts map[string][]time.Time
+}
+
+func newKioskFailedAttempts() *kioskFailedAttempts {
+	return &kioskFailedAttempts{
+		attempts: make(map[string][]time.Time),
+	}
+}
+
+// isLockedOut checks whether the key has at least max failed attempts within the lockout window
+func (a *kioskFailedAttempts) isLockedOut(key string, max int, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return len(a.recentAttempts(key, now)) >= max
+}
+
+// record records a failed attempt for the key
+func (a *kioskFailedAttempts) record(key string, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.attempts[key] = append(a.recentAttempts(key, now), now)
+}
+
+// reset forgets the failed attempts of the key like after a successful identification
+func (a *kioskFailedAttempts) reset(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.attempts, key)
+}
+
+func (a *kioskFailedAttempts) recentAttempts(key string, now time.Time) []time.Time {
+	attempts := a.attempts[key]
+
+	windowStart := now.Add(-kioskLockoutDuration)
+	for len(attempts) > 0 && !attempts[0].After(windowStart) {
+		attempts = attempts[1:]
+	}
+
+	if len(attempts) == 0 {
+		delete(a.attempts, key)
+		return nil
+	}
+
+	a.attempts[key] = attempts
+	return attempts
+}
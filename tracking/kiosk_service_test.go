@@ -0,0 +1,128 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestKioskClockInAndClockOut(t *testing.T) {
+	is := is.New(t)
+
+	k := NewKioskService(shared.NewInMemRepositoryTxer(), NewInMemKioskRepository(), newAttendanceServiceForTest(t, true), nil)
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	registered, err := k.RegisterKioskDevice(context.Background(), admin, "Entrance")
+	is.NoErr(err)
+	is.True(registered.Token != "")
+
+	_, err = k.AuthenticateKioskDevice(context.Background(), "invalid", now)
+	is.Equal(err, ErrKioskDeviceUnauthorized)
+
+	device, err := k.AuthenticateKioskDevice(context.Background(), registered.Token, now)
+	is.NoErr(err)
+	is.Equal(device.ID, registered.ID)
+
+	_, err = k.UpdateKioskCredential(context.Background(), admin, "user1", "1234", "B-42")
+	is.NoErr(err)
+
+	_, err = k.KioskClockIn(context.Background(), device, &KioskIdentification{Username: "user1", PIN: "4321"}, now)
+	is.Equal(err, ErrKioskIdentificationFailed)
+
+	_, err = k.KioskClockIn(context.Background(), device, &KioskIdentification{BadgeCode: "B-1"}, now)
+	is.Equal(err, ErrKioskIdentificationFailed)
+
+	stamp, err := k.KioskClockIn(context.Background(), device, &KioskIdentification{Username: "user1", PIN: "1234"}, now)
+	is.NoErr(err)
+	is.Equal(stamp.Username, "user1")
+
+	stamp, err = k.KioskClockOut(context.Background(), device, &KioskIdentification{BadgeCode: "B-42"}, now.Add(4*time.Hour))
+	is.NoErr(err)
+	is.Equal(stamp.DurationInMinutes(), 4*60)
+}
+
+func TestKioskLockoutAfterFailedPINs(t *testing.T) {
+	is := is.New(t)
+
+	k := NewKioskService(shared.NewInMemRepositoryTxer(), NewInMemKioskRepository(), newAttendanceServiceForTest(t, true), nil)
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	device := &KioskDevice{ID: uuid.New(), OrganizationID: shared.OrganizationIDSample}
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	_, err := k.UpdateKioskCredential(context.Background(), admin, "user1", "1234", "")
+	is.NoErr(err)
+
+	t.Run("unknown user fails like wrong PIN", func(t *testing.T) {
+		_, err := k.KioskClockIn(context.Background(), device, &KioskIdentification{Username: "unknown", PIN: "1234"}, now)
+		is.Equal(err, ErrKioskIdentificationFailed)
+	})
+
+	t.Run("user is locked out after failed PINs", func(t *testing.T) {
+		for i := 0; i < maxKioskFailedAttemptsPerUser; i++ {
+			_, err := k.KioskClockIn(context.Background(), device, &KioskIdentification{Username: "user1", PIN: "4321"}, now)
+			is.Equal(err, ErrKioskIdentificationFailed)
+		}
+
+		_, err := k.KioskClockIn(context.Background(), device, &KioskIdentification{Username: "user1", PIN: "1234"}, now)
+		is.Equal(err, ErrKioskLockedOut)
+
+		stamp, err := k.KioskClockIn(context.Background(), device, &KioskIdentification{Username: "user1", PIN: "1234"}, now.Add(kioskLockoutDuration))
+		is.NoErr(err)
+		is.Equal(stamp.Username, "user1")
+	})
+
+	t.Run("device is locked out after failed identifications", func(t *testing.T) {
+		for i := 0; i < maxKioskFailedAttemptsPerDevice; i++ {
+			_, err := k.KioskClockIn(context.Background(), device, &KioskIdentification{BadgeCode: "B-1"}, now)
+			is.Equal(err, ErrKioskIdentificationFailed)
+		}
+
+		_, err := k.KioskClockOut(context.Background(), device, &KioskIdentification{Username: "user1", PIN: "1234"}, now)
+		is.Equal(err, ErrKioskLockedOut)
+	})
+}
+
+func TestManageKioskDevicesAsUser(t *testing.T) {
+	is := is.New(t)
+
+	k := NewKioskService(shared.NewInMemRepositoryTxer(), NewInMemKioskRepository(), newAttendanceServiceForTest(t, true), nil)
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+
+	_, err := k.RegisterKioskDevice(context.Background(), user, "Entrance")
+	is.Equal(err, shared.ErrForbidden)
+
+	_, err = k.UpdateKioskCredential(context.Background(), user, "user1", "1234", "")
+	is.Equal(err, shared.ErrForbidden)
+}
+
+func TestKioskScanWithProvisionedBadge(t *testing.T) {
+	is := is.New(t)
+
+	scanTagService := newScanTagServiceForTest()
+	k := NewKioskService(shared.NewInMemRepositoryTxer(), NewInMemKioskRepository(), newAttendanceServiceForTest(t, true), scanTagService)
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	device := &KioskDevice{OrganizationID: shared.OrganizationIDSample}
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	tag, err := scanTagService.CreateScanTag(context.Background(), admin, &ScanTag{ProjectID: shared.ProjectIDSample, Name: "Room 1"})
+	is.NoErr(err)
+
+	credential, err := k.ProvisionKioskBadge(context.Background(), admin, "user1")
+	is.NoErr(err)
+	is.True(credential.BadgeCode != "")
+
+	result, err := k.KioskScan(context.Background(), device, &KioskIdentification{BadgeCode: credential.BadgeCode}, tag.Code, now)
+	is.NoErr(err)
+	is.Equal(result.Started.Username, "user1")
+
+	err = k.RevokeKioskBadge(context.Background(), admin, "user1")
+	is.NoErr(err)
+
+	_, err = k.KioskScan(context.Background(), device, &KioskIdentification{BadgeCode: credential.BadgeCode}, tag.Code, now.Add(time.Hour))
+	is.Equal(err, ErrKioskIdentificationFailed)
+}
@@ -0,0 +1,90 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+)
+
+const (
+	LocationOffice     string = "office"
+	LocationHome       string = "home"
+	LocationClientSite string = "client-site"
+)
+
+const (
+	LocationPolicyOff      string = "off"
+	LocationPolicyOptional string = "optional"
+	LocationPolicyRequired string = "required"
+)
+
+// LocationUnspecified is the location of the report item for activities without a location
+const LocationUnspecified string = "unspecified"
+
+var ErrLocationRequired = shared.NewDomainError("activity:location-required", http.StatusBadRequest, "location of activity required")
+
+// LocationPolicy controls how the locations of activities are captured in an organization
+type LocationPolicy struct {
+	OrganizationID   uuid.UUID
+	Mode             string
+	AllowCoordinates bool
+}
+
+// ActivityLocationReportItem is the tracked time at a location
+type ActivityLocationReportItem struct {
+	Location               string
+	DurationInMinutesTotal int
+}
+
+type LocationPolicyRepository interface {
+	FindLocationPolicy(ctx context.Context, organizationID uuid.UUID) (*LocationPolicy, error)
+	UpdateLocationPolicy(ctx context.Context, policy *LocationPolicy) error
+}
+
+// IsValidLocation checks if the location is a known kind of work location
+func IsValidLocation(location string) bool {
+	return location == LocationOffice || location == LocationHome || location == LocationClientSite
+}
+
+// IsValidLocationPolicyMode checks if the mode is a known mode of a location policy
+func IsValidLocationPolicyMode(mode string) bool {
+	return mode == LocationPolicyOff || mode == LocationPolicyOptional || mode == LocationPolicyRequired
+}
+
+// NewDefaultLocationPolicy is the policy of organizations without a policy, locations are optional and without coordinates
+func NewDefaultLocationPolicy(organizationID uuid.UUID) *LocationPolicy {
+	return &LocationPolicy{
+		OrganizationID: organizationID,
+		Mode:           LocationPolicyOptional,
+	}
+}
+
+// Apply enforces the policy on the location of the activity, so locations are dropped
+// if not captured and coordinates are dropped if not allowed
+func (p *LocationPolicy) Apply(activity *Activity) error {
+	if p.Mode == LocationPolicyOff {
+		activity.Location = ""
+		activity.Latitude = nil
+		activity.Longitude = nil
+		return nil
+	}
+
+	if !p.AllowCoordinates {
+		activity.Latitude = nil
+		activity.Longitude = nil
+	}
+
+	if p.Mode == LocationPolicyRequired && !activity.HasLocation() {
+		return ErrLocationRequired
+	}
+
+	return nil
+}
+
+// DurationFormatted is the tracked time as formatted string (e.g. 1:15 h)
+func (i *ActivityLocationReportItem) DurationFormatted() string {
+	return time_utils.FormatMinutesAsDuration(float64(i.DurationInMinutesTotal))
+}
@@ -0,0 +1,60 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestLocationPolicyApply(t *testing.T) {
+	is := is.New(t)
+
+	latitude, longitude := 52.52, 13.405
+	newActivity := func() *Activity {
+		return &Activity{
+			Location:  LocationHome,
+			Latitude:  &latitude,
+			Longitude: &longitude,
+		}
+	}
+
+	t.Run("off drops the location", func(t *testing.T) {
+		activity := newActivity()
+		err := (&LocationPolicy{Mode: LocationPolicyOff, AllowCoordinates: true}).Apply(activity)
+		is.NoErr(err)
+		is.Equal(activity.Location, "")
+		is.True(activity.Latitude == nil)
+		is.True(!activity.HasLocation())
+	})
+
+	t.Run("optional drops coordinates if not allowed", func(t *testing.T) {
+		activity := newActivity()
+		err := (&LocationPolicy{Mode: LocationPolicyOptional}).Apply(activity)
+		is.NoErr(err)
+		is.Equal(activity.Location, LocationHome)
+		is.True(activity.Latitude == nil)
+		is.True(activity.Longitude == nil)
+	})
+
+	t.Run("optional keeps allowed coordinates", func(t *testing.T) {
+		activity := newActivity()
+		err := (&LocationPolicy{Mode: LocationPolicyOptional, AllowCoordinates: true}).Apply(activity)
+		is.NoErr(err)
+		is.Equal(*activity.Latitude, latitude)
+	})
+
+	t.Run("required without location", func(t *testing.T) {
+		err := (&LocationPolicy{Mode: LocationPolicyRequired}).Apply(&Activity{})
+		is.Equal(err, ErrLocationRequired)
+	})
+
+	t.Run("required with coordinates only", func(t *testing.T) {
+		activity := &Activity{Latitude: &latitude, Longitude: &longitude}
+		err := (&LocationPolicy{Mode: LocationPolicyRequired, AllowCoordinates: true}).Apply(activity)
+		is.NoErr(err)
+
+		activity = &Activity{Latitude: &latitude, Longitude: &longitude}
+		err = (&LocationPolicy{Mode: LocationPolicyRequired}).Apply(activity)
+		is.Equal(err, ErrLocationRequired)
+	})
+}
@@ -0,0 +1,66 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbLocationPolicyRepository is a SQL database repository for location policies
+type DbLocationPolicyRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ LocationPolicyRepository = (*DbLocationPolicyRepository)(nil)
+
+// NewDbLocationPolicyRepository creates a new SQL database repository for location policies
+func NewDbLocationPolicyRepository(connPool *pgxpool.Pool) *DbLocationPolicyRepository {
+	return &DbLocationPolicyRepository{
+		connPool: connPool,
+	}
+}
+
+// FindLocationPolicy reads the location policy of the organization, organizations without a policy get the default policy
+func (r *DbLocationPolicyRepository) FindLocationPolicy(ctx context.Context, organizationID uuid.UUID) (*LocationPolicy, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT mode, allow_coordinates 
+		 FROM location_policies 
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+
+	policy := &LocationPolicy{OrganizationID: organizationID}
+	err := row.Scan(&policy.Mode, &policy.AllowCoordinates)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewDefaultLocationPolicy(organizationID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// UpdateLocationPolicy sets the location policy of the organization
+func (r *DbLocationPolicyRepository) UpdateLocationPolicy(ctx context.Context, policy *LocationPolicy) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO location_policies 
+		   (org_id, mode, allow_coordinates) 
+		 VALUES 
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id) DO UPDATE 
+		 SET mode = EXCLUDED.mode, allow_coordinates = EXCLUDED.allow_coordinates`,
+		policy.OrganizationID,
+		policy.Mode,
+		policy.AllowCoordinates,
+	)
+	return err
+}
@@ -0,0 +1,61 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestLocationPolicyRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	locationPolicyRepository := NewDbLocationPolicyRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("FindDefaultLocationPolicy", func(t *testing.T) {
+		policy, err := locationPolicyRepository.FindLocationPolicy(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(policy.Mode, LocationPolicyOptional)
+		is.True(!policy.AllowCoordinates)
+	})
+
+	t.Run("UpdateLocationPolicy", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return locationPolicyRepository.UpdateLocationPolicy(ctx, &LocationPolicy{
+					OrganizationID:   shared.OrganizationIDSample,
+					Mode:             LocationPolicyRequired,
+					AllowCoordinates: true,
+				})
+			},
+		)
+		is.NoErr(err)
+
+		policy, err := locationPolicyRepository.FindLocationPolicy(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(policy.Mode, LocationPolicyRequired)
+		is.True(policy.AllowCoordinates)
+	})
+}
@@ -0,0 +1,42 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemLocationPolicyRepository struct {
+	mu       sync.Mutex
+	policies map[uuid.UUID]*LocationPolicy
+}
+
+var _ LocationPolicyRepository = (*InMemLocationPolicyRepository)(nil)
+
+func NewInMemLocationPolicyRepository() *InMemLocationPolicyRepository {
+	return &InMemLocationPolicyRepository{
+		policies: make(map[uuid.UUID]*LocationPolicy),
+	}
+}
+
+func (r *InMemLocationPolicyRepository) FindLocationPolicy(ctx context.Context, organizationID uuid.UUID) (*LocationPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	policy, ok := r.policies[organizationID]
+	if !ok {
+		return NewDefaultLocationPolicy(organizationID), nil
+	}
+	found := *policy
+	return &found, nil
+}
+
+func (r *InMemLocationPolicyRepository) UpdateLocationPolicy(ctx context.Context, policy *LocationPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := *policy
+	r.policies[policy.OrganizationID] = &updated
+	return nil
+}
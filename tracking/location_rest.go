@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type locationPolicyModel struct {
+	Mode             string     `json:"mode"`
+	AllowCoordinates bool       `json:"allowCoordinates"`
+	Links            *hal.Links `json:"_links,omitempty"`
+}
+
+type LocationRestHandlers struct {
+	config          *shared.Config
+	activityService *ActitivityService
+}
+
+func NewLocationRestHandlers(config *shared.Config, activityService *ActitivityService) *LocationRestHandlers {
+	return &LocationRestHandlers{
+		config:          config,
+		activityService: activityService,
+	}
+}
+
+func (a *LocationRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/location-policy", a.HandleGetLocationPolicy())
+	r.Put("/location-policy", a.HandleUpdateLocationPolicy())
+}
+
+func (a *LocationRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetLocationPolicy reads the location policy of the organization, so clients know which location to capture
+func (a *LocationRestHandlers) HandleGetLocationPolicy() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		policy, err := activityService.ReadLocationPolicy(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToLocationPolicyModel(policy, r.RequestURI))
+	}
+}
+
+// HandleUpdateLocationPolicy sets the location policy of the organization
+func (a *LocationRestHandlers) HandleUpdateLocationPolicy() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var policyModel locationPolicyModel
+		err := json.NewDecoder(r.Body).Decode(&policyModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "location policy not valid", err)
+			return
+		}
+
+		if !IsValidLocationPolicyMode(policyModel.Mode) {
+			shared.RenderValidationProblemJSON(w, "location policy not valid", shared.NewInvalidParam("mode", "oneof", "mode must be off, optional or required"))
+			return
+		}
+
+		policy, err := activityService.UpdateLocationPolicy(r.Context(), principal, &LocationPolicy{
+			Mode:             policyModel.Mode,
+			AllowCoordinates: policyModel.AllowCoordinates,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToLocationPolicyModel(policy, r.RequestURI))
+	}
+}
+
+func mapToLocationPolicyModel(policy *LocationPolicy, selfLink string) *locationPolicyModel {
+	return &locationPolicyModel{
+		Mode:             policy.Mode,
+		AllowCoordinates: policy.AllowCoordinates,
+		Links: hal.NewLinks(
+			hal.NewSelfLink(selfLink),
+		),
+	}
+}
@@ -0,0 +1,58 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleLocationPolicy(t *testing.T) {
+	is := is.New(t)
+
+	a := NewLocationRestHandlers(&shared.Config{}, &ActitivityService{
+		repositoryTxer:           shared.NewInMemRepositoryTxer(),
+		activityRepository:       NewInMemActivityRepository(),
+		locationPolicyRepository: NewInMemLocationPolicyRepository(),
+	})
+
+	updatePolicy := func(body string, roles ...string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/api/location-policy", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          roles,
+		}))
+		a.HandleUpdateLocationPolicy()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := updatePolicy(`{"mode": "required", "allowCoordinates": true}`, "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = updatePolicy(`{"mode": "sometimes"}`, "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = updatePolicy(`{"mode": "off"}`, "ROLE_USER")
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/api/location-policy", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_USER"},
+	}))
+	a.HandleGetLocationPolicy()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	policyModel := &locationPolicyModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(policyModel)
+	is.NoErr(err)
+	is.Equal(policyModel.Mode, LocationPolicyRequired)
+	is.True(policyModel.AllowCoordinates)
+}
@@ -0,0 +1,85 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+)
+
+// TeamMember is a user of an organization
+type TeamMember struct {
+	Username string
+	Name     string
+	EMail    string
+	Admin    bool
+}
+
+// ManagerDigestSettings configure the manager digest of an organization, without recipients the digest is sent to the admins
+type ManagerDigestSettings struct {
+	OrganizationID uuid.UUID
+	Enabled        bool
+	Recipients     []string
+}
+
+// ManagerDigestMember is the tracked time of a team member in the week of the manager digest
+type ManagerDigestMember struct {
+	Member         *TeamMember
+	TrackedMinutes int
+}
+
+// ManagerDigest summarizes the tracked time of the team of an organization in a week,
+// an aggregated digest contains the time of the whole team only
+type ManagerDigest struct {
+	OrganizationID    uuid.UUID
+	WeekStart         time.Time
+	TargetMinutes     int
+	Members           []*ManagerDigestMember
+	MissingTimesheets []*TeamMember
+	ReportLink        string
+	Aggregated        bool
+	TeamSize          int
+	TeamMinutes       int
+}
+
+type ManagerDigestRepository interface {
+	FindOrganizationsDueForManagerDigest(ctx context.Context, weekStart time.Time) ([]uuid.UUID, error)
+	FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error)
+	FindManagerDigestSettings(ctx context.Context, organizationID uuid.UUID) (*ManagerDigestSettings, error)
+	UpdateManagerDigestSettings(ctx context.Context, settings *ManagerDigestSettings) error
+	MarkManagerDigestSent(ctx context.Context, organizationID uuid.UUID, weekStart time.Time) error
+}
+
+// WeekEnd is the last day of the week of the digest
+func (d *ManagerDigest) WeekEnd() time.Time {
+	return d.WeekStart.AddDate(0, 0, 6)
+}
+
+// TrackedMinutesTotal is the time tracked by the whole team
+func (d *ManagerDigest) TrackedMinutesTotal() int {
+	if d.Aggregated {
+		return d.TeamMinutes
+	}
+
+	total := 0
+	for _, member := range d.Members {
+		total += member.TrackedMinutes
+	}
+	return total
+}
+
+// TrackedFormatted is the time tracked by the whole team as formatted string (e.g. 38:15 h)
+func (d *ManagerDigest) TrackedFormatted() string {
+	return time_utils.FormatMinutesAsDuration(float64(d.TrackedMinutesTotal()))
+}
+
+// TargetFormatted is the target time per member as formatted string (e.g. 40:00 h)
+func (d *ManagerDigest) TargetFormatted() string {
+	return time_utils.FormatMinutesAsDuration(float64(d.TargetMinutes))
+}
+
+// TrackedFormatted is the tracked time of the member as formatted string (e.g. 38:15 h)
+func (m *ManagerDigestMember) TrackedFormatted() string {
+	return time_utils.FormatMinutesAsDuration(float64(m.TrackedMinutes))
+}
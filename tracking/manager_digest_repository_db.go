@@ -0,0 +1,163 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbManagerDigestRepository is a SQL database repository for manager digests
+type DbManagerDigestRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ ManagerDigestRepository = (*DbManagerDigestRepository)(nil)
+
+// NewDbManagerDigestRepository creates a new SQL database repository for manager digests
+func NewDbManagerDigestRepository(connPool *pgxpool.Pool) *DbManagerDigestRepository {
+	return &DbManagerDigestRepository{
+		connPool: connPool,
+	}
+}
+
+// FindOrganizationsDueForManagerDigest reads the organizations with enabled manager digest that did not yet receive the digest of the week
+func (r *DbManagerDigestRepository) FindOrganizationsDueForManagerDigest(ctx context.Context, weekStart time.Time) ([]uuid.UUID, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT o.org_id 
+		 FROM organizations o 
+		 LEFT JOIN manager_digest_settings s 
+		 ON s.org_id = o.org_id 
+		 WHERE COALESCE(s.enabled, true) AND (s.last_sent_week IS NULL OR s.last_sent_week < $1) 
+		 ORDER BY o.org_id`,
+		weekStart,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var organizationIDs []uuid.UUID
+	for rows.Next() {
+		var organizationID uuid.UUID
+		err := rows.Scan(&organizationID)
+		if err != nil {
+			return nil, err
+		}
+		organizationIDs = append(organizationIDs, organizationID)
+	}
+
+	return organizationIDs, rows.Err()
+}
+
+// FindTeamMembers reads the enabled users of the organization
+func (r *DbManagerDigestRepository) FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error) {
+	return findTeamMembers(ctx, r.connPool, organizationID)
+}
+
+// FindManagerDigestSettings reads the settings of the manager digest, the digest is enabled for organizations without settings
+func (r *DbManagerDigestRepository) FindManagerDigestSettings(ctx context.Context, organizationID uuid.UUID) (*ManagerDigestSettings, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT enabled, recipients 
+		 FROM manager_digest_settings 
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+
+	var recipients pgtype.Varchar
+	settings := &ManagerDigestSettings{OrganizationID: organizationID, Enabled: true}
+	err := row.Scan(&settings.Enabled, &recipients)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if recipients.String != "" {
+		settings.Recipients = strings.Split(recipients.String, ",")
+	}
+	return settings, nil
+}
+
+// UpdateManagerDigestSettings sets the settings of the manager digest
+func (r *DbManagerDigestRepository) UpdateManagerDigestSettings(ctx context.Context, settings *ManagerDigestSettings) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	recipients := strings.Join(settings.Recipients, ",")
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO manager_digest_settings 
+		   (org_id, enabled, recipients) 
+		 VALUES 
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id) DO UPDATE 
+		 SET enabled = EXCLUDED.enabled, recipients = EXCLUDED.recipients`,
+		settings.OrganizationID,
+		settings.Enabled,
+		sql.NullString{String: recipients, Valid: recipients != ""},
+	)
+	return err
+}
+
+// MarkManagerDigestSent marks the manager digest of the week as sent for the organization
+func (r *DbManagerDigestRepository) MarkManagerDigestSent(ctx context.Context, organizationID uuid.UUID, weekStart time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO manager_digest_settings 
+		   (org_id, last_sent_week) 
+		 VALUES 
+		   ($1, $2)
+		 ON CONFLICT (org_id) DO UPDATE 
+		 SET last_sent_week = EXCLUDED.last_sent_week`,
+		organizationID,
+		weekStart,
+	)
+	return err
+}
+
+// findTeamMembers reads the enabled users of the organization
+func findTeamMembers(ctx context.Context, connPool *pgxpool.Pool, organizationID uuid.UUID) ([]*TeamMember, error) {
+	rows, err := connPool.Query(
+		ctx,
+		`SELECT u.username, u.name, u.email, 
+		        EXISTS (SELECT 1 FROM roles r WHERE r.user_id = u.user_id AND r.role = 'ROLE_ADMIN') 
+		 FROM users u 
+		 WHERE u.org_id = $1 AND u.enabled = 1 
+		 ORDER BY u.name, u.username`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teamMembers []*TeamMember
+	for rows.Next() {
+		var (
+			name  pgtype.Varchar
+			email pgtype.Varchar
+		)
+		teamMember := &TeamMember{}
+		err := rows.Scan(&teamMember.Username, &name, &email, &teamMember.Admin)
+		if err != nil {
+			return nil, err
+		}
+		teamMember.Name = name.String
+		teamMember.EMail = email.String
+		teamMembers = append(teamMembers, teamMember)
+	}
+
+	return teamMembers, rows.Err()
+}
@@ -0,0 +1,89 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestManagerDigestRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	managerDigestRepository := NewDbManagerDigestRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+	weekStart := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("FindTeamMembers", func(t *testing.T) {
+		teamMembers, err := managerDigestRepository.FindTeamMembers(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(len(teamMembers) > 1)
+
+		admins := 0
+		for _, teamMember := range teamMembers {
+			if teamMember.Admin {
+				admins++
+			}
+		}
+		is.True(admins > 0)
+	})
+
+	t.Run("MarkManagerDigestSent", func(t *testing.T) {
+		organizationIDs, err := managerDigestRepository.FindOrganizationsDueForManagerDigest(context.Background(), weekStart)
+		is.NoErr(err)
+		is.True(len(organizationIDs) > 0)
+		organizationCount := len(organizationIDs)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return managerDigestRepository.MarkManagerDigestSent(ctx, shared.OrganizationIDSample, weekStart)
+			},
+		)
+		is.NoErr(err)
+
+		organizationIDs, err = managerDigestRepository.FindOrganizationsDueForManagerDigest(context.Background(), weekStart)
+		is.NoErr(err)
+		is.Equal(len(organizationIDs), organizationCount-1)
+	})
+
+	t.Run("UpdateManagerDigestSettings", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return managerDigestRepository.UpdateManagerDigestSettings(ctx, &ManagerDigestSettings{
+					OrganizationID: shared.OrganizationIDSample,
+					Enabled:        false,
+					Recipients:     []string{"lead1@baralga.com", "lead2@baralga.com"},
+				})
+			},
+		)
+		is.NoErr(err)
+
+		settings, err := managerDigestRepository.FindManagerDigestSettings(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(!settings.Enabled)
+		is.Equal(len(settings.Recipients), 2)
+	})
+}
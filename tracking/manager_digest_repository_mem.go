@@ -0,0 +1,90 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+type InMemManagerDigestRepository struct {
+	mu           sync.Mutex
+	teamMembers  []*TeamMember
+	settings     map[uuid.UUID]*ManagerDigestSettings
+	lastSentWeek map[uuid.UUID]time.Time
+}
+
+var _ ManagerDigestRepository = (*InMemManagerDigestRepository)(nil)
+
+func NewInMemManagerDigestRepository() *InMemManagerDigestRepository {
+	return &InMemManagerDigestRepository{
+		teamMembers: []*TeamMember{
+			{Username: "admin", Name: "Ed Admin", EMail: "admin@baralga.com", Admin: true},
+			{Username: "user1", Name: "Ulani User", EMail: "user1@baralga.com"},
+			{Username: "user2", Name: "Uriah User", EMail: "user2@baralga.com"},
+		},
+		settings:     make(map[uuid.UUID]*ManagerDigestSettings),
+		lastSentWeek: make(map[uuid.UUID]time.Time),
+	}
+}
+
+func (r *InMemManagerDigestRepository) FindOrganizationsDueForManagerDigest(ctx context.Context, weekStart time.Time) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	organizationID := shared.OrganizationIDSample
+	if settings, ok := r.settings[organizationID]; ok && !settings.Enabled {
+		return nil, nil
+	}
+	if lastSentWeek, ok := r.lastSentWeek[organizationID]; ok && !lastSentWeek.Before(weekStart) {
+		return nil, nil
+	}
+	return []uuid.UUID{organizationID}, nil
+}
+
+func (r *InMemManagerDigestRepository) FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if organizationID != shared.OrganizationIDSample {
+		return nil, nil
+	}
+
+	teamMembers := make([]*TeamMember, len(r.teamMembers))
+	for i, teamMember := range r.teamMembers {
+		found := *teamMember
+		teamMembers[i] = &found
+	}
+	return teamMembers, nil
+}
+
+func (r *InMemManagerDigestRepository) FindManagerDigestSettings(ctx context.Context, organizationID uuid.UUID) (*ManagerDigestSettings, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings, ok := r.settings[organizationID]
+	if !ok {
+		return &ManagerDigestSettings{OrganizationID: organizationID, Enabled: true}, nil
+	}
+	found := *settings
+	return &found, nil
+}
+
+func (r *InMemManagerDigestRepository) UpdateManagerDigestSettings(ctx context.Context, settings *ManagerDigestSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *settings
+	r.settings[settings.OrganizationID] = &stored
+	return nil
+}
+
+func (r *InMemManagerDigestRepository) MarkManagerDigestSent(ctx context.Context, organizationID uuid.UUID, weekStart time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastSentWeek[organizationID] = weekStart
+	return nil
+}
@@ -0,0 +1,106 @@
+package tracking
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type managerDigestSettingsModel struct {
+	Enabled    *bool      `json:"enabled"`
+	Recipients []string   `json:"recipients"`
+	Links      *hal.Links `json:"_links,omitempty"`
+}
+
+type ManagerDigestRestHandlers struct {
+	config               *shared.Config
+	managerDigestService *ManagerDigestService
+}
+
+func NewManagerDigestRestHandlers(config *shared.Config, managerDigestService *ManagerDigestService) *ManagerDigestRestHandlers {
+	return &ManagerDigestRestHandlers{
+		config:               config,
+		managerDigestService: managerDigestService,
+	}
+}
+
+func (a *ManagerDigestRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/admin/manager-digest", a.HandleGetManagerDigestSettings())
+	r.Put("/admin/manager-digest", a.HandleUpdateManagerDigestSettings())
+}
+
+func (a *ManagerDigestRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetManagerDigestSettings reads the settings of the manager digest of the organization
+func (a *ManagerDigestRestHandlers) HandleGetManagerDigestSettings() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	managerDigestService := a.managerDigestService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		settings, err := managerDigestService.ReadManagerDigestSettings(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToManagerDigestSettingsModel(settings, r.RequestURI))
+	}
+}
+
+// HandleUpdateManagerDigestSettings enables or disables the manager digest of the organization and sets its recipients
+func (a *ManagerDigestRestHandlers) HandleUpdateManagerDigestSettings() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	managerDigestService := a.managerDigestService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var settingsModel managerDigestSettingsModel
+		err := json.NewDecoder(r.Body).Decode(&settingsModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "manager digest settings not valid", err)
+			return
+		}
+
+		if settingsModel.Enabled == nil {
+			shared.RenderValidationProblemJSON(w, "manager digest settings not valid", shared.NewInvalidParam("enabled", "required", "enabled is required"))
+			return
+		}
+
+		err = validateManagerDigestRecipients(settingsModel.Recipients)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "manager digest settings not valid", err)
+			return
+		}
+
+		settings, err := managerDigestService.UpdateManagerDigestSettings(r.Context(), principal, &ManagerDigestSettings{
+			Enabled:    *settingsModel.Enabled,
+			Recipients: settingsModel.Recipients,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToManagerDigestSettingsModel(settings, r.RequestURI))
+	}
+}
+
+func mapToManagerDigestSettingsModel(settings *ManagerDigestSettings, selfLink string) *managerDigestSettingsModel {
+	enabled := settings.Enabled
+	recipients := settings.Recipients
+	if recipients == nil {
+		recipients = []string{}
+	}
+	return &managerDigestSettingsModel{
+		Enabled:    &enabled,
+		Recipients: recipients,
+		Links: hal.NewLinks(
+			hal.NewSelfLink(selfLink),
+		),
+	}
+}
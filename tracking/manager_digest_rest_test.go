@@ -0,0 +1,45 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleUpdateManagerDigestSettings(t *testing.T) {
+	is := is.New(t)
+
+	a := NewManagerDigestRestHandlers(&shared.Config{}, newInMemManagerDigestService(shared.NewInMemMailResource()))
+
+	updateSettings := func(body string, roles ...string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/api/admin/manager-digest", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          roles,
+		}))
+		a.HandleUpdateManagerDigestSettings()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := updateSettings(`{"enabled": true, "recipients": ["lead@baralga.com"]}`, "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	settingsModel := &managerDigestSettingsModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(settingsModel)
+	is.NoErr(err)
+	is.True(*settingsModel.Enabled)
+	is.Equal(settingsModel.Recipients, []string{"lead@baralga.com"})
+
+	httpRec = updateSettings(`{"enabled": true, "recipients": ["no email"]}`, "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = updateSettings(`{"enabled": false}`, "ROLE_USER")
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
@@ -0,0 +1,251 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"text/template"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const managerDigestJobType = "manager-digest"
+
+// errTeamBelowMinGroupSize signals that the team is too small for a digest in the works council mode
+var errTeamBelowMinGroupSize = errors.New("team below minimum group size")
+
+var managerDigestTemplate = template.Must(template.New("manager-digest").Parse(
+	`Hello,
+
+here is the summary of your team for the week from {{ .WeekStart.Format "2006-01-02" }} to {{ .WeekEnd.Format "2006-01-02" }}.
+
+Tracked by the team: {{ .TrackedFormatted }}{{ if .Aggregated }} by {{ .TeamSize }} members{{ end }}
+{{ range .Members }}- {{ .Member.Name }}: {{ .TrackedFormatted }}{{ if $.TargetMinutes }} of {{ $.TargetFormatted }}{{ end }}
+{{ end }}{{ if .MissingTimesheets }}
+Missing timesheets:
+{{ range .MissingTimesheets }}- {{ .Name }}
+{{ end }}{{ end }}
+See the report of the week at {{ .ReportLink }}
+
+You receive this digest as team lead of your organization.
+`))
+
+// ManagerDigestService sends a weekly digest of the time of the team to the team leads of an organization
+type ManagerDigestService struct {
+	config                     *shared.Config
+	repositoryTxer             shared.RepositoryTxer
+	outbox                     shared.Outbox
+	managerDigestRepository    ManagerDigestRepository
+	activityRepository         ActivityRepository
+	compliancePolicyRepository CompliancePolicyRepository
+}
+
+// NewManagerDigestService creates a new service for manager digests, sending them in the background if enabled
+func NewManagerDigestService(
+	config *shared.Config,
+	repositoryTxer shared.RepositoryTxer,
+	outbox shared.Outbox,
+	jobService *shared.JobService,
+	managerDigestRepository ManagerDigestRepository,
+	activityRepository ActivityRepository,
+	compliancePolicyRepository CompliancePolicyRepository,
+) *ManagerDigestService {
+	s := &ManagerDigestService{
+		config:                     config,
+		repositoryTxer:             repositoryTxer,
+		outbox:                     outbox,
+		managerDigestRepository:    managerDigestRepository,
+		activityRepository:         activityRepository,
+		compliancePolicyRepository: compliancePolicyRepository,
+	}
+
+	if config.ManagerDigest {
+		jobService.RegisterHandler(managerDigestJobType, s.handleManagerDigestJob)
+		jobService.Schedule(managerDigestJobType, time.Hour)
+	}
+
+	return s
+}
+
+// ReadManagerDigestSettings reads the settings of the manager digest of the principal's organization
+func (s *ManagerDigestService) ReadManagerDigestSettings(ctx context.Context, principal *shared.Principal) (*ManagerDigestSettings, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+	return s.managerDigestRepository.FindManagerDigestSettings(ctx, principal.OrganizationID)
+}
+
+// UpdateManagerDigestSettings sets the settings of the manager digest of the principal's organization
+func (s *ManagerDigestService) UpdateManagerDigestSettings(ctx context.Context, principal *shared.Principal, settings *ManagerDigestSettings) (*ManagerDigestSettings, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	settings.OrganizationID = principal.OrganizationID
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.managerDigestRepository.UpdateManagerDigestSettings(ctx, settings)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// BuildManagerDigest summarizes the tracked time of the team of the organization in the week,
+// in the works council mode only the time of the whole team is summarized if the team has the minimum group size
+func (s *ManagerDigestService) BuildManagerDigest(ctx context.Context, organizationID uuid.UUID, teamMembers []*TeamMember, weekStart time.Time) (*ManagerDigest, error) {
+	compliancePolicy, err := s.compliancePolicyRepository.FindCompliancePolicy(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !compliancePolicy.AllowsTeamAggregate(len(teamMembers)) {
+		return nil, errTeamBelowMinGroupSize
+	}
+
+	utilizationItems, err := s.activityRepository.UtilizationReportByUser(ctx, &ActivitiesFilter{
+		Start:          weekStart,
+		End:            weekStart.AddDate(0, 0, 7),
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trackedMinutesByUsername := make(map[string]int, len(utilizationItems))
+	for _, utilizationItem := range utilizationItems {
+		trackedMinutesByUsername[utilizationItem.Username] += utilizationItem.DurationInMinutesTotal
+	}
+
+	year, week := weekStart.ISOWeek()
+	digest := &ManagerDigest{
+		OrganizationID: organizationID,
+		WeekStart:      weekStart,
+		TargetMinutes:  s.config.WorkingHoursPerWeek * 60,
+		ReportLink:     fmt.Sprintf("%s/reports?t=week&v=%d-%d&c=general", s.config.Webroot, year, week),
+	}
+
+	if compliancePolicy.WorksCouncilMode {
+		digest.Aggregated = true
+		digest.TeamSize = len(teamMembers)
+		for _, teamMember := range teamMembers {
+			digest.TeamMinutes += trackedMinutesByUsername[teamMember.Username]
+		}
+		return digest, nil
+	}
+
+	for _, teamMember := range teamMembers {
+		trackedMinutes := trackedMinutesByUsername[teamMember.Username]
+		digest.Members = append(digest.Members, &ManagerDigestMember{
+			Member:         teamMember,
+			TrackedMinutes: trackedMinutes,
+		})
+		if trackedMinutes == 0 {
+			digest.MissingTimesheets = append(digest.MissingTimesheets, teamMember)
+		}
+	}
+
+	return digest, nil
+}
+
+// SendManagerDigests sends the manager digest of the last complete week to the team leads of all organizations that did not yet receive it
+func (s *ManagerDigestService) SendManagerDigests(ctx context.Context, now time.Time) error {
+	weekStart := truncateToBucket(now, "week").AddDate(0, 0, -7)
+
+	organizationIDs, err := s.managerDigestRepository.FindOrganizationsDueForManagerDigest(ctx, weekStart)
+	if err != nil {
+		return err
+	}
+
+	for _, organizationID := range organizationIDs {
+		err := s.sendManagerDigest(ctx, organizationID, weekStart)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(organizationIDs) > 0 {
+		log.Printf("sent manager digest of %v organizations", len(organizationIDs))
+	}
+	return nil
+}
+
+func (s *ManagerDigestService) sendManagerDigest(ctx context.Context, organizationID uuid.UUID, weekStart time.Time) error {
+	settings, err := s.managerDigestRepository.FindManagerDigestSettings(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	teamMembers, err := s.managerDigestRepository.FindTeamMembers(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	recipients := settings.Recipients
+	if len(recipients) == 0 {
+		for _, teamMember := range teamMembers {
+			if teamMember.Admin && teamMember.EMail != "" {
+				recipients = append(recipients, teamMember.EMail)
+			}
+		}
+	}
+
+	digest, err := s.BuildManagerDigest(ctx, organizationID, teamMembers, weekStart)
+	if errors.Is(err, errTeamBelowMinGroupSize) {
+		return s.repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return s.managerDigestRepository.MarkManagerDigestSent(ctx, organizationID, weekStart)
+			},
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	body := &bytes.Buffer{}
+	err = managerDigestTemplate.Execute(body, digest)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Your team's week from %s", weekStart.Format("2006-01-02"))
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			for _, recipient := range recipients {
+				err := s.outbox.SendMail(ctx, organizationID, recipient, subject, body.String())
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			return s.managerDigestRepository.MarkManagerDigestSent(ctx, organizationID, weekStart)
+		},
+	)
+}
+
+func (s *ManagerDigestService) handleManagerDigestJob(ctx context.Context, job *shared.Job) error {
+	return s.SendManagerDigests(ctx, time.Now())
+}
+
+// validateManagerDigestRecipients checks that the recipients are email addresses
+func validateManagerDigestRecipients(recipients []string) error {
+	for _, recipient := range recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return shared.NewInvalidParam("recipients", "email", fmt.Sprintf("%s is not an email address", recipient))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package tracking
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newInMemManagerDigestService(mailResource shared.MailResource) *ManagerDigestService {
+	repositoryTxer := shared.NewInMemRepositoryTxer()
+	return NewManagerDigestService(
+		&shared.Config{Webroot: "http://localhost:8080", WorkingHoursPerWeek: 40, ManagerDigest: true},
+		repositoryTxer,
+		shared.NewInMemOutbox(mailResource),
+		shared.NewJobService(repositoryTxer, shared.NewInMemJobRepository()),
+		NewInMemManagerDigestRepository(),
+		NewInMemActivityRepository(),
+		NewInMemCompliancePolicyRepository(),
+	)
+}
+
+func TestSendManagerDigests(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemManagerDigestService(mailResource)
+	now := time.Date(2024, 3, 13, 10, 0, 0, 0, time.UTC)
+
+	err := s.SendManagerDigests(context.Background(), now)
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 1)
+
+	mail := mailResource.Mails[0]
+	is.True(strings.HasPrefix(mail, "admin@baralga.com"))
+	is.True(strings.Contains(mail, "from 2024-03-04 to 2024-03-10"))
+	is.True(strings.Contains(mail, "- Uriah User: 0:00 h of 40:00 h"))
+	is.True(strings.Contains(mail, "Missing timesheets:\n- Ed Admin"))
+	is.True(strings.Contains(mail, "/reports?t=week&v=2024-10&c=general"))
+
+	// digest of the week is sent only once
+	err = s.SendManagerDigests(context.Background(), now.Add(time.Hour))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 1)
+}
+
+func TestSendManagerDigestsToRecipients(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemManagerDigestService(mailResource)
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := s.UpdateManagerDigestSettings(context.Background(), admin, &ManagerDigestSettings{
+		Enabled:    true,
+		Recipients: []string{"lead1@baralga.com", "lead2@baralga.com"},
+	})
+	is.NoErr(err)
+
+	err = s.SendManagerDigests(context.Background(), time.Now())
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 2)
+	is.True(strings.HasPrefix(mailResource.Mails[1], "lead2@baralga.com"))
+}
+
+func TestSendManagerDigestsDisabled(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemManagerDigestService(mailResource)
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := s.UpdateManagerDigestSettings(context.Background(), admin, &ManagerDigestSettings{Enabled: false})
+	is.NoErr(err)
+
+	err = s.SendManagerDigests(context.Background(), time.Now())
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 0)
+
+	_, err = s.UpdateManagerDigestSettings(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample}, &ManagerDigestSettings{Enabled: true})
+	is.Equal(err, shared.ErrForbidden)
+}
+
+func TestSendManagerDigestsInWorksCouncilMode(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemManagerDigestService(mailResource)
+	now := time.Date(2024, 3, 13, 10, 0, 0, 0, time.UTC)
+
+	err := s.compliancePolicyRepository.UpdateCompliancePolicy(context.Background(), &CompliancePolicy{
+		OrganizationID:   shared.OrganizationIDSample,
+		WorksCouncilMode: true,
+		MinGroupSize:     3,
+	})
+	is.NoErr(err)
+
+	err = s.SendManagerDigests(context.Background(), now)
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 1)
+
+	mail := mailResource.Mails[0]
+	is.True(strings.Contains(mail, "Tracked by the team: 0:00 h by 3 members"))
+	is.True(!strings.Contains(mail, "Uriah User"))
+	is.True(!strings.Contains(mail, "Missing timesheets"))
+}
+
+func TestSendManagerDigestsBelowMinGroupSize(t *testing.T) {
+	is := is.New(t)
+
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemManagerDigestService(mailResource)
+	now := time.Date(2024, 3, 13, 10, 0, 0, 0, time.UTC)
+
+	err := s.compliancePolicyRepository.UpdateCompliancePolicy(context.Background(), &CompliancePolicy{
+		OrganizationID:   shared.OrganizationIDSample,
+		WorksCouncilMode: true,
+		MinGroupSize:     5,
+	})
+	is.NoErr(err)
+
+	err = s.SendManagerDigests(context.Background(), now)
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 0)
+
+	// digest of the week is not retried
+	err = s.SendManagerDigests(context.Background(), now.Add(time.Hour))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 0)
+}
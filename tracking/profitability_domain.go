@@ -0,0 +1,200 @@
+package tracking
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	ProfitabilityByProject = "project"
+	ProfitabilityByClient  = "client"
+)
+
+var ErrCostRateNotFound = shared.NewDomainError("cost-rate:not-found", http.StatusNotFound, "cost rate not found")
+
+// CostRate is the internal hourly cost of a user, which is distinct from the rate the time is billed at
+type CostRate struct {
+	OrganizationID  uuid.UUID
+	Username        string
+	HourlyCostCents int
+}
+
+// ProfitabilityReportItem is the time a user tracked for a project together with the rate of the project's client
+type ProfitabilityReportItem struct {
+	ProjectID              uuid.UUID
+	ProjectTitle           string
+	Billable               bool
+	ClientID               *uuid.UUID
+	ClientName             string
+	HourlyRateCents        int
+	Currency               string
+	Username               string
+	DurationInMinutesTotal int
+}
+
+// ProfitabilityLine is the revenue, cost and margin of a project or client
+type ProfitabilityLine struct {
+	ID                     *uuid.UUID
+	Name                   string
+	Currency               string
+	DurationInMinutesTotal int
+	RevenueCents           int
+	CostCents              int
+}
+
+// ProfitabilityReport is the revenue, cost and margin per project or client in a timespan
+type ProfitabilityReport struct {
+	Start            time.Time
+	End              time.Time
+	GroupBy          string
+	Lines            []*ProfitabilityLine
+	Recognitions     []*RevenueRecognition
+	MissingCostRates []string
+}
+
+type CostRateRepository interface {
+	FindCostRates(ctx context.Context, organizationID uuid.UUID) ([]*CostRate, error)
+	UpdateCostRate(ctx context.Context, costRate *CostRate) error
+	DeleteCostRate(ctx context.Context, organizationID uuid.UUID, username string) error
+	ProfitabilityReport(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*ProfitabilityReportItem, error)
+}
+
+// IsValidProfitabilityGroupBy checks whether the profitability can be reported by the group
+func IsValidProfitabilityGroupBy(groupBy string) bool {
+	return groupBy == ProfitabilityByProject || groupBy == ProfitabilityByClient
+}
+
+// MarginCents is the revenue left after the cost
+func (l *ProfitabilityLine) MarginCents() int {
+	return l.RevenueCents - l.CostCents
+}
+
+// MarginPercent is the margin as percentage of the revenue, without revenue there is no margin
+func (l *ProfitabilityLine) MarginPercent() float64 {
+	if l.RevenueCents == 0 {
+		return 0
+	}
+	return math.Round(float64(l.MarginCents())*1000/float64(l.RevenueCents)) / 10
+}
+
+// NewProfitabilityReport sums up revenue and cost per project or client, only billable time of projects
+// with a client has revenue, time of users without cost rate has no cost and these users are reported as missing,
+// the revenue of fixed-price projects is the recognized revenue instead of the tracked time at the rate of the client
+func NewProfitabilityReport(start, end time.Time, groupBy string, costRates []*CostRate, items []*ProfitabilityReportItem, fixedPrices []*FixedPrice, recognitions []*RevenueRecognition) *ProfitabilityReport {
+	report := &ProfitabilityReport{
+		Start:        start,
+		End:          end,
+		GroupBy:      groupBy,
+		Recognitions: recognitions,
+	}
+	sortRevenueRecognitions(report.Recognitions)
+
+	fixedPricesByProject := make(map[uuid.UUID]*FixedPrice, len(fixedPrices))
+	for _, fixedPrice := range fixedPrices {
+		fixedPricesByProject[fixedPrice.ProjectID] = fixedPrice
+	}
+
+	costRatesByUser := make(map[string]int, len(costRates))
+	for _, costRate := range costRates {
+		costRatesByUser[costRate.Username] = costRate.HourlyCostCents
+	}
+
+	linesByKey := make(map[string]*ProfitabilityLine)
+	lineOf := func(item *ProfitabilityReportItem) *ProfitabilityLine {
+		key, line := profitabilityLineOf(groupBy, item)
+		if existing, ok := linesByKey[key]; ok {
+			return existing
+		}
+		linesByKey[key] = line
+		report.Lines = append(report.Lines, line)
+		return line
+	}
+
+	missingCostRates := make(map[string]bool)
+	for _, item := range items {
+		line := lineOf(item)
+
+		line.DurationInMinutesTotal += item.DurationInMinutesTotal
+		_, fixedPrice := fixedPricesByProject[item.ProjectID]
+		if item.Billable && item.ClientID != nil && !fixedPrice {
+			line.RevenueCents += amountCentsOf(item.DurationInMinutesTotal, item.HourlyRateCents)
+		}
+
+		hourlyCostCents, ok := costRatesByUser[item.Username]
+		if !ok {
+			missingCostRates[item.Username] = true
+			continue
+		}
+		line.CostCents += amountCentsOf(item.DurationInMinutesTotal, hourlyCostCents)
+	}
+
+	for _, recognition := range report.Recognitions {
+		fixedPrice, ok := fixedPricesByProject[recognition.ProjectID]
+		if !ok {
+			continue
+		}
+		line := lineOf(&ProfitabilityReportItem{
+			ProjectID:    fixedPrice.ProjectID,
+			ProjectTitle: fixedPrice.ProjectTitle,
+			ClientID:     fixedPrice.ClientID,
+			ClientName:   fixedPrice.ClientName,
+			Currency:     fixedPrice.Currency,
+		})
+		line.RevenueCents += recognition.AmountCents
+	}
+
+	for username := range missingCostRates {
+		report.MissingCostRates = append(report.MissingCostRates, username)
+	}
+	sort.Strings(report.MissingCostRates)
+
+	return report
+}
+
+// RevenueCents is the revenue of all lines
+func (r *ProfitabilityReport) RevenueCents() int {
+	total := 0
+	for _, line := range r.Lines {
+		total += line.RevenueCents
+	}
+	return total
+}
+
+// CostCents is the cost of all lines
+func (r *ProfitabilityReport) CostCents() int {
+	total := 0
+	for _, line := range r.Lines {
+		total += line.CostCents
+	}
+	return total
+}
+
+func profitabilityLineOf(groupBy string, item *ProfitabilityReportItem) (string, *ProfitabilityLine) {
+	if groupBy == ProfitabilityByClient {
+		if item.ClientID == nil {
+			return "", &ProfitabilityLine{}
+		}
+		return item.ClientID.String(), &ProfitabilityLine{
+			ID:       item.ClientID,
+			Name:     item.ClientName,
+			Currency: item.Currency,
+		}
+	}
+
+	projectID := item.ProjectID
+	return projectID.String(), &ProfitabilityLine{
+		ID:       &projectID,
+		Name:     item.ProjectTitle,
+		Currency: item.Currency,
+	}
+}
+
+func amountCentsOf(minutes, hourlyCents int) int {
+	return int(math.Round(float64(minutes) * float64(hourlyCents) / 60))
+}
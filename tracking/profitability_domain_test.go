@@ -0,0 +1,52 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestNewProfitabilityReport(t *testing.T) {
+	is := is.New(t)
+
+	clientID := uuid.New()
+	projectID := uuid.New()
+	internalProjectID := uuid.New()
+
+	costRates := []*CostRate{
+		{Username: "user1", HourlyCostCents: 4000},
+	}
+	items := []*ProfitabilityReportItem{
+		{ProjectID: projectID, ProjectTitle: "Web", Billable: true, ClientID: &clientID, ClientName: "ACME", HourlyRateCents: 10000, Currency: "EUR", Username: "user1", DurationInMinutesTotal: 120},
+		{ProjectID: projectID, ProjectTitle: "Web", Billable: true, ClientID: &clientID, ClientName: "ACME", HourlyRateCents: 10000, Currency: "EUR", Username: "user2", DurationInMinutesTotal: 60},
+		{ProjectID: internalProjectID, ProjectTitle: "Internal", Username: "user1", DurationInMinutesTotal: 30},
+	}
+
+	t.Run("ByProject", func(t *testing.T) {
+		report := NewProfitabilityReport(time.Time{}, time.Time{}, ProfitabilityByProject, costRates, items, nil, nil)
+		is.Equal(len(report.Lines), 2)
+
+		is.Equal(report.Lines[0].Name, "Web")
+		is.Equal(report.Lines[0].RevenueCents, 30000)
+		is.Equal(report.Lines[0].CostCents, 8000)
+		is.Equal(report.Lines[0].MarginCents(), 22000)
+		is.Equal(report.Lines[0].MarginPercent(), 73.3)
+
+		is.Equal(report.Lines[1].RevenueCents, 0)
+		is.Equal(report.Lines[1].CostCents, 2000)
+		is.Equal(report.Lines[1].MarginPercent(), 0.0)
+
+		is.Equal(report.MissingCostRates, []string{"user2"})
+		is.Equal(report.RevenueCents()-report.CostCents(), 20000)
+	})
+
+	t.Run("ByClient", func(t *testing.T) {
+		report := NewProfitabilityReport(time.Time{}, time.Time{}, ProfitabilityByClient, costRates, items, nil, nil)
+		is.Equal(len(report.Lines), 2)
+		is.Equal(*report.Lines[0].ID, clientID)
+		is.Equal(report.Lines[0].Currency, "EUR")
+		is.True(report.Lines[1].ID == nil)
+	})
+}
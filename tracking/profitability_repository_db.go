@@ -0,0 +1,138 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DbCostRateRepository is a SQL database repository for the cost rates of users and the profitability of projects
+type DbCostRateRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ CostRateRepository = (*DbCostRateRepository)(nil)
+
+// NewDbCostRateRepository creates a new SQL database repository for cost rates
+func NewDbCostRateRepository(connPool *pgxpool.Pool) *DbCostRateRepository {
+	return &DbCostRateRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbCostRateRepository) FindCostRates(ctx context.Context, organizationID uuid.UUID) ([]*CostRate, error) {
+	rows, err := shared.SelectAll[costRateRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[costRateRow]()+`
+		 FROM cost_rates
+		 WHERE org_id = $1
+		 ORDER BY username ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	costRates := make([]*CostRate, len(rows))
+	for i, row := range rows {
+		costRates[i] = &CostRate{
+			OrganizationID:  row.OrganizationID,
+			Username:        row.Username,
+			HourlyCostCents: row.HourlyCostCents,
+		}
+	}
+	return costRates, nil
+}
+
+// UpdateCostRate sets the cost rate of the user
+func (r *DbCostRateRepository) UpdateCostRate(ctx context.Context, costRate *CostRate) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO cost_rates
+		   (org_id, username, hourly_cost_cents)
+		 VALUES
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id, username) DO UPDATE
+		 SET hourly_cost_cents = EXCLUDED.hourly_cost_cents`,
+		costRate.OrganizationID,
+		costRate.Username,
+		costRate.HourlyCostCents,
+	)
+	return err
+}
+
+func (r *DbCostRateRepository) DeleteCostRate(ctx context.Context, organizationID uuid.UUID, username string) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM cost_rates
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCostRateNotFound
+	}
+	return nil
+}
+
+// ProfitabilityReport reports the tracked time per project and user with the rate of the project's client
+func (r *DbCostRateRepository) ProfitabilityReport(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*ProfitabilityReportItem, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT p.project_id, p.title, p.billable, c.client_id, COALESCE(c.name, ''), COALESCE(c.hourly_rate_cents, 0), COALESCE(c.currency, ''),
+		        ag.username, sum(ag.duration_minutes_total) as duration_minutes_total
+		 FROM activities_agg ag
+		 INNER JOIN projects p
+		 ON p.project_id = ag.project_id AND p.org_id = ag.org_id
+		 LEFT JOIN clients c
+		 ON c.client_id = p.client_id
+		 WHERE ag.org_id = $1 AND $2 <= ag.start_time AND ag.start_time < $3
+		 GROUP BY p.project_id, p.title, p.billable, c.client_id, c.name, c.hourly_rate_cents, c.currency, ag.username
+		 ORDER BY p.title ASC, ag.username ASC`,
+		organizationID, start, end,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reportItems []*ProfitabilityReportItem
+	for rows.Next() {
+		reportItem := &ProfitabilityReportItem{}
+		err := rows.Scan(
+			&reportItem.ProjectID,
+			&reportItem.ProjectTitle,
+			&reportItem.Billable,
+			&reportItem.ClientID,
+			&reportItem.ClientName,
+			&reportItem.HourlyRateCents,
+			&reportItem.Currency,
+			&reportItem.Username,
+			&reportItem.DurationInMinutesTotal,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reportItems = append(reportItems, reportItem)
+	}
+
+	return reportItems, rows.Err()
+}
+
+type costRateRow struct {
+	OrganizationID  uuid.UUID `db:"org_id"`
+	Username        string    `db:"username"`
+	HourlyCostCents int       `db:"hourly_cost_cents"`
+}
@@ -0,0 +1,68 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestCostRateRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	costRateRepository := NewDbCostRateRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("UpdateCostRate", func(t *testing.T) {
+		for _, hourlyCostCents := range []int{4000, 5000} {
+			err := repositoryTxer.InTx(
+				context.Background(),
+				func(ctx context.Context) error {
+					return costRateRepository.UpdateCostRate(ctx, &CostRate{OrganizationID: shared.OrganizationIDSample, Username: "admin", HourlyCostCents: hourlyCostCents})
+				},
+			)
+			is.NoErr(err)
+		}
+
+		costRates, err := costRateRepository.FindCostRates(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(costRates), 1)
+		is.Equal(costRates[0].HourlyCostCents, 5000)
+	})
+
+	t.Run("ProfitabilityReport", func(t *testing.T) {
+		// sample activity of 10 minutes on 2021-10-14 of a project without client
+		items, err := costRateRepository.ProfitabilityReport(
+			context.Background(),
+			shared.OrganizationIDSample,
+			time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC),
+		)
+		is.NoErr(err)
+		is.Equal(len(items), 1)
+		is.Equal(items[0].Username, "admin")
+		is.Equal(items[0].DurationInMinutesTotal, 10)
+		is.True(items[0].ClientID == nil)
+	})
+}
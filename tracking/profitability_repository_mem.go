@@ -0,0 +1,89 @@
+package tracking
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+type InMemCostRateRepository struct {
+	mu        sync.Mutex
+	costRates []*CostRate
+}
+
+var _ CostRateRepository = (*InMemCostRateRepository)(nil)
+
+func NewInMemCostRateRepository() *InMemCostRateRepository {
+	return &InMemCostRateRepository{}
+}
+
+func (r *InMemCostRateRepository) FindCostRates(ctx context.Context, organizationID uuid.UUID) ([]*CostRate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var costRates []*CostRate
+	for _, costRate := range r.costRates {
+		if costRate.OrganizationID == organizationID {
+			found := *costRate
+			costRates = append(costRates, &found)
+		}
+	}
+	sort.Slice(costRates, func(i, j int) bool {
+		return costRates[i].Username < costRates[j].Username
+	})
+	return costRates, nil
+}
+
+func (r *InMemCostRateRepository) UpdateCostRate(ctx context.Context, costRate *CostRate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := *costRate
+	for i, c := range r.costRates {
+		if c.OrganizationID == costRate.OrganizationID && c.Username == costRate.Username {
+			r.costRates[i] = &updated
+			return nil
+		}
+	}
+	r.costRates = append(r.costRates, &updated)
+	return nil
+}
+
+func (r *InMemCostRateRepository) DeleteCostRate(ctx context.Context, organizationID uuid.UUID, username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, costRate := range r.costRates {
+		if costRate.OrganizationID == organizationID && costRate.Username == username {
+			r.costRates = append(r.costRates[:i], r.costRates[i+1:]...)
+			return nil
+		}
+	}
+	return ErrCostRateNotFound
+}
+
+func (r *InMemCostRateRepository) ProfitabilityReport(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]*ProfitabilityReportItem, error) {
+	if organizationID != shared.OrganizationIDSample {
+		return nil, nil
+	}
+
+	return []*ProfitabilityReportItem{
+		{
+			ProjectID:              shared.ProjectIDSample,
+			ProjectTitle:           "My Project",
+			Billable:               true,
+			ClientID:               &clientIDProfitabilitySample,
+			ClientName:             "ACME",
+			HourlyRateCents:        10000,
+			Currency:               "EUR",
+			Username:               "user1",
+			DurationInMinutesTotal: 90,
+		},
+	}, nil
+}
+
+var clientIDProfitabilitySample = uuid.MustParse("00000000-0000-0000-3333-000000000001")
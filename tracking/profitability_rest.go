@@ -0,0 +1,248 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+)
+
+type costRateModel struct {
+	Username        string     `json:"username"`
+	HourlyCostCents int        `json:"hourlyCostCents" validate:"min=0,max=10000000"`
+	Links           *hal.Links `json:"_links,omitempty"`
+}
+
+type costRatesModel struct {
+	Embedded struct {
+		CostRateModels []*costRateModel `json:"costRates"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type profitabilityLineModel struct {
+	ID                     string  `json:"id,omitempty"`
+	Name                   string  `json:"name"`
+	Currency               string  `json:"currency,omitempty"`
+	DurationInMinutesTotal int     `json:"durationInMinutesTotal"`
+	RevenueCents           int     `json:"revenueCents"`
+	CostCents              int     `json:"costCents"`
+	MarginCents            int     `json:"marginCents"`
+	MarginPercent          float64 `json:"marginPercent"`
+}
+
+type revenueRecognitionModel struct {
+	ProjectID   string `json:"projectId"`
+	Day         string `json:"day"`
+	Title       string `json:"title"`
+	AmountCents int    `json:"amountCents"`
+}
+
+type profitabilityReportModel struct {
+	Start            string                     `json:"start"`
+	End              string                     `json:"end"`
+	GroupBy          string                     `json:"groupBy"`
+	Lines            []*profitabilityLineModel  `json:"lines"`
+	Recognitions     []*revenueRecognitionModel `json:"recognitions"`
+	RevenueCents     int                        `json:"revenueCents"`
+	CostCents        int                        `json:"costCents"`
+	MarginCents      int                        `json:"marginCents"`
+	MissingCostRates []string                   `json:"missingCostRates"`
+	Links            *hal.Links                 `json:"_links"`
+}
+
+type ProfitabilityRestHandlers struct {
+	config               *shared.Config
+	profitabilityService *ProfitabilityService
+}
+
+func NewProfitabilityRestHandlers(config *shared.Config, profitabilityService *ProfitabilityService) *ProfitabilityRestHandlers {
+	return &ProfitabilityRestHandlers{
+		config:               config,
+		profitabilityService: profitabilityService,
+	}
+}
+
+func (a *ProfitabilityRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/cost-rates", a.HandleGetCostRates())
+	r.Put("/cost-rates/{username}", a.HandleUpdateCostRate())
+	r.Delete("/cost-rates/{username}", a.HandleDeleteCostRate())
+	r.Get("/reports/profitability", a.HandleProfitabilityReport())
+}
+
+func (a *ProfitabilityRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetCostRates reads the cost rates of the users
+func (a *ProfitabilityRestHandlers) HandleGetCostRates() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	profitabilityService := a.profitabilityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		costRates, err := profitabilityService.ReadCostRates(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		costRateModels := make([]*costRateModel, len(costRates))
+		for i, costRate := range costRates {
+			costRateModels[i] = mapToCostRateModel(costRate)
+		}
+
+		costRatesModel := &costRatesModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		costRatesModel.Embedded.CostRateModels = costRateModels
+
+		shared.RenderJSON(w, costRatesModel)
+	}
+}
+
+// HandleUpdateCostRate sets the cost rate of a user
+func (a *ProfitabilityRestHandlers) HandleUpdateCostRate() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	profitabilityService := a.profitabilityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var costRateModel costRateModel
+		err := json.NewDecoder(r.Body).Decode(&costRateModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "cost rate not valid", err)
+			return
+		}
+
+		err = validator.Struct(costRateModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "cost rate not valid", err)
+			return
+		}
+
+		costRate, err := profitabilityService.UpdateCostRate(r.Context(), principal, &CostRate{
+			Username:        chi.URLParam(r, "username"),
+			HourlyCostCents: costRateModel.HourlyCostCents,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToCostRateModel(costRate))
+	}
+}
+
+// HandleDeleteCostRate removes the cost rate of a user
+func (a *ProfitabilityRestHandlers) HandleDeleteCostRate() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	profitabilityService := a.profitabilityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		err := profitabilityService.DeleteCostRate(r.Context(), principal, chi.URLParam(r, "username"))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleProfitabilityReport reports the revenue, cost and margin per project or client in the timespan
+func (a *ProfitabilityRestHandlers) HandleProfitabilityReport() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	profitabilityService := a.profitabilityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		filter, err := filterFromQueryParams(r.URL.Query())
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		groupBy := r.URL.Query().Get("groupBy")
+		if groupBy == "" {
+			groupBy = ProfitabilityByProject
+		}
+		if !IsValidProfitabilityGroupBy(groupBy) {
+			shared.RenderValidationProblemJSON(w, "invalid query params", shared.NewInvalidParam("groupBy", "oneof", "groupBy must be project or client"))
+			return
+		}
+
+		report, err := profitabilityService.ProfitabilityReport(r.Context(), principal, filter.Start(), filter.End(), groupBy)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		reportModel := &profitabilityReportModel{
+			Start:            time_utils.FormatDate(report.Start),
+			End:              time_utils.FormatDate(report.End),
+			GroupBy:          report.GroupBy,
+			Lines:            make([]*profitabilityLineModel, len(report.Lines)),
+			Recognitions:     make([]*revenueRecognitionModel, len(report.Recognitions)),
+			RevenueCents:     report.RevenueCents(),
+			CostCents:        report.CostCents(),
+			MarginCents:      report.RevenueCents() - report.CostCents(),
+			MissingCostRates: report.MissingCostRates,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		if reportModel.MissingCostRates == nil {
+			reportModel.MissingCostRates = []string{}
+		}
+		for i, line := range report.Lines {
+			reportModel.Lines[i] = mapToProfitabilityLineModel(line)
+		}
+		for i, recognition := range report.Recognitions {
+			reportModel.Recognitions[i] = &revenueRecognitionModel{
+				ProjectID:   recognition.ProjectID.String(),
+				Day:         time_utils.FormatDate(recognition.Day),
+				Title:       recognition.Title,
+				AmountCents: recognition.AmountCents,
+			}
+		}
+
+		shared.RenderJSON(w, reportModel)
+	}
+}
+
+func mapToCostRateModel(costRate *CostRate) *costRateModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/cost-rates/%s", costRate.Username))
+	return &costRateModel{
+		Username:        costRate.Username,
+		HourlyCostCents: costRate.HourlyCostCents,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("edit", selfLink.Href()),
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+}
+
+func mapToProfitabilityLineModel(line *ProfitabilityLine) *profitabilityLineModel {
+	lineModel := &profitabilityLineModel{
+		Name:                   line.Name,
+		Currency:               line.Currency,
+		DurationInMinutesTotal: line.DurationInMinutesTotal,
+		RevenueCents:           line.RevenueCents,
+		CostCents:              line.CostCents,
+		MarginCents:            line.MarginCents(),
+		MarginPercent:          line.MarginPercent(),
+	}
+	if line.ID != nil {
+		lineModel.ID = line.ID.String()
+	}
+	return lineModel
+}
@@ -0,0 +1,62 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleProfitabilityReport(t *testing.T) {
+	is := is.New(t)
+
+	a := NewProfitabilityRestHandlers(&shared.Config{}, NewProfitabilityService(shared.NewInMemRepositoryTxer(), NewInMemCostRateRepository(), NewInMemFixedPriceRepository()))
+	admin := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+
+	t.Run("ByClient", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/reports/profitability?t=month&v=2024-03&groupBy=client", nil)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, admin))
+
+		a.HandleProfitabilityReport()(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		var reportModel profitabilityReportModel
+		err := json.NewDecoder(httpRec.Body).Decode(&reportModel)
+		is.NoErr(err)
+		is.Equal(reportModel.GroupBy, ProfitabilityByClient)
+		is.Equal(len(reportModel.Lines), 1)
+		is.Equal(reportModel.Lines[0].Name, "ACME")
+		is.Equal(reportModel.RevenueCents, 15000)
+		is.Equal(reportModel.MissingCostRates, []string{"user1"})
+	})
+
+	t.Run("GroupByNotValid", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/reports/profitability?groupBy=user", nil)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, admin))
+
+		a.HandleProfitabilityReport()(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("AsUser", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/reports/profitability", nil)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+		}))
+
+		a.HandleProfitabilityReport()(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	})
+}
@@ -0,0 +1,137 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// ProfitabilityService manages the internal cost rates of users and reports the revenue, cost
+// and margin of projects and clients, both only admins may see
+type ProfitabilityService struct {
+	repositoryTxer       shared.RepositoryTxer
+	costRateRepository   CostRateRepository
+	fixedPriceRepository FixedPriceRepository
+}
+
+// NewProfitabilityService creates a new service for cost rates and profitability
+func NewProfitabilityService(repositoryTxer shared.RepositoryTxer, costRateRepository CostRateRepository, fixedPriceRepository FixedPriceRepository) *ProfitabilityService {
+	return &ProfitabilityService{
+		repositoryTxer:       repositoryTxer,
+		costRateRepository:   costRateRepository,
+		fixedPriceRepository: fixedPriceRepository,
+	}
+}
+
+// ReadCostRates reads the cost rates of the users of the organization
+func (s *ProfitabilityService) ReadCostRates(ctx context.Context, principal *shared.Principal) ([]*CostRate, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.costRateRepository.FindCostRates(ctx, principal.OrganizationID)
+}
+
+// UpdateCostRate sets the cost rate of a user
+func (s *ProfitabilityService) UpdateCostRate(ctx context.Context, principal *shared.Principal, costRate *CostRate) (*CostRate, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	costRate.OrganizationID = principal.OrganizationID
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.costRateRepository.UpdateCostRate(ctx, costRate)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return costRate, nil
+}
+
+// DeleteCostRate removes the cost rate of a user
+func (s *ProfitabilityService) DeleteCostRate(ctx context.Context, principal *shared.Principal, username string) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.costRateRepository.DeleteCostRate(ctx, principal.OrganizationID, username)
+		},
+	)
+}
+
+// ProfitabilityReport reports the revenue, cost and margin per project or client from start to end,
+// the revenue of fixed-price projects is recognized by milestones or by completion of the budget
+func (s *ProfitabilityService) ProfitabilityReport(ctx context.Context, principal *shared.Principal, start, end time.Time, groupBy string) (*ProfitabilityReport, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	costRates, err := s.costRateRepository.FindCostRates(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.costRateRepository.ProfitabilityReport(ctx, principal.OrganizationID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	fixedPrices, err := s.fixedPriceRepository.FindFixedPrices(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	recognitions, err := s.recognizeRevenue(ctx, principal, start, end, fixedPrices, items)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProfitabilityReport(start, end, groupBy, costRates, items, fixedPrices, recognitions), nil
+}
+
+func (s *ProfitabilityService) recognizeRevenue(ctx context.Context, principal *shared.Principal, start, end time.Time, fixedPrices []*FixedPrice, items []*ProfitabilityReportItem) ([]*RevenueRecognition, error) {
+	if len(fixedPrices) == 0 {
+		return nil, nil
+	}
+
+	milestones, err := s.fixedPriceRepository.FindMilestones(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	minutesBefore, err := s.fixedPriceRepository.FindTrackedMinutesBefore(ctx, principal.OrganizationID, start)
+	if err != nil {
+		return nil, err
+	}
+
+	milestonesByProject := make(map[uuid.UUID][]*Milestone)
+	for _, milestone := range milestones {
+		milestonesByProject[milestone.ProjectID] = append(milestonesByProject[milestone.ProjectID], milestone)
+	}
+
+	minutesInTimespan := make(map[uuid.UUID]int)
+	for _, item := range items {
+		minutesInTimespan[item.ProjectID] += item.DurationInMinutesTotal
+	}
+
+	var recognitions []*RevenueRecognition
+	for _, fixedPrice := range fixedPrices {
+		recognitions = append(recognitions, fixedPrice.Recognize(
+			start,
+			end,
+			milestonesByProject[fixedPrice.ProjectID],
+			minutesBefore[fixedPrice.ProjectID],
+			minutesInTimespan[fixedPrice.ProjectID],
+		)...)
+	}
+	return recognitions, nil
+}
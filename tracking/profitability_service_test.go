@@ -0,0 +1,52 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestCostRates(t *testing.T) {
+	is := is.New(t)
+
+	s := NewProfitabilityService(shared.NewInMemRepositoryTxer(), NewInMemCostRateRepository(), NewInMemFixedPriceRepository())
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := s.UpdateCostRate(context.Background(), user, &CostRate{Username: "user1", HourlyCostCents: 4000})
+	is.Equal(err, shared.ErrForbidden)
+
+	_, err = s.ReadCostRates(context.Background(), user)
+	is.Equal(err, shared.ErrForbidden)
+
+	_, err = s.UpdateCostRate(context.Background(), admin, &CostRate{Username: "user1", HourlyCostCents: 4000})
+	is.NoErr(err)
+	_, err = s.UpdateCostRate(context.Background(), admin, &CostRate{Username: "user1", HourlyCostCents: 5000})
+	is.NoErr(err)
+
+	costRates, err := s.ReadCostRates(context.Background(), admin)
+	is.NoErr(err)
+	is.Equal(len(costRates), 1)
+	is.Equal(costRates[0].HourlyCostCents, 5000)
+
+	t.Run("ProfitabilityReport", func(t *testing.T) {
+		_, err := s.ProfitabilityReport(context.Background(), user, time.Now(), time.Now(), ProfitabilityByProject)
+		is.Equal(err, shared.ErrForbidden)
+
+		report, err := s.ProfitabilityReport(context.Background(), admin, time.Now(), time.Now(), ProfitabilityByProject)
+		is.NoErr(err)
+		is.Equal(len(report.Lines), 1)
+		is.Equal(report.Lines[0].RevenueCents, 15000)
+		is.Equal(report.Lines[0].CostCents, 7500)
+		is.Equal(len(report.MissingCostRates), 0)
+	})
+
+	err = s.DeleteCostRate(context.Background(), admin, "user1")
+	is.NoErr(err)
+
+	err = s.DeleteCostRate(context.Background(), admin, "user1")
+	is.Equal(err, ErrCostRateNotFound)
+}
@@ -0,0 +1,65 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProjectRequired               = shared.NewDomainError("activity:project-required", http.StatusBadRequest, "project of activity required, no default project or rule matches")
+	ErrProjectAssignmentRuleNotFound = shared.NewDomainError("project-assignment-rule:not-found", http.StatusNotFound, "project assignment rule not found")
+)
+
+// ProjectAssignmentRule assigns activities without project to the project if their description contains the keyword
+type ProjectAssignmentRule struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Keyword        string
+	ProjectID      uuid.UUID
+}
+
+// ProjectAssignment assigns activities without project by the rules of the organization,
+// activities not matching any rule are assigned to the default project of the user
+type ProjectAssignment struct {
+	Rules            []*ProjectAssignmentRule
+	DefaultProjectID *uuid.UUID
+}
+
+type ProjectAssignmentRepository interface {
+	FindDefaultProject(ctx context.Context, organizationID uuid.UUID, username string) (*uuid.UUID, error)
+	UpdateDefaultProject(ctx context.Context, organizationID uuid.UUID, username string, projectID *uuid.UUID) error
+	FindProjectAssignmentRules(ctx context.Context, organizationID uuid.UUID) ([]*ProjectAssignmentRule, error)
+	InsertProjectAssignmentRule(ctx context.Context, rule *ProjectAssignmentRule) (*ProjectAssignmentRule, error)
+	DeleteProjectAssignmentRuleByID(ctx context.Context, organizationID, ruleID uuid.UUID) error
+}
+
+// Matches checks whether the description contains the keyword of the rule, ignoring case
+func (r *ProjectAssignmentRule) Matches(description string) bool {
+	return strings.Contains(strings.ToLower(description), strings.ToLower(r.Keyword))
+}
+
+// Assign assigns the activity to the project of the first matching rule or the default project,
+// activities with project are left as they are
+func (p *ProjectAssignment) Assign(activity *Activity) error {
+	if activity.ProjectID != uuid.Nil {
+		return nil
+	}
+
+	for _, rule := range p.Rules {
+		if rule.Matches(activity.Description) {
+			activity.ProjectID = rule.ProjectID
+			return nil
+		}
+	}
+
+	if p.DefaultProjectID == nil {
+		return ErrProjectRequired
+	}
+
+	activity.ProjectID = *p.DefaultProjectID
+	return nil
+}
@@ -0,0 +1,65 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestProjectAssignmentRuleMatches(t *testing.T) {
+	is := is.New(t)
+
+	rule := &ProjectAssignmentRule{Keyword: "Standup"}
+
+	is.True(rule.Matches("Daily standup with team"))
+	is.True(rule.Matches("STANDUP"))
+	is.True(!rule.Matches("Planning"))
+	is.True(!rule.Matches(""))
+}
+
+func TestProjectAssignmentAssign(t *testing.T) {
+	is := is.New(t)
+
+	standupProjectID := uuid.New()
+	reviewProjectID := uuid.New()
+	defaultProjectID := uuid.New()
+
+	projectAssignment := &ProjectAssignment{
+		Rules: []*ProjectAssignmentRule{
+			{Keyword: "standup", ProjectID: standupProjectID},
+			{Keyword: "review", ProjectID: reviewProjectID},
+			{Keyword: "standup review", ProjectID: uuid.New()},
+		},
+		DefaultProjectID: &defaultProjectID,
+	}
+
+	t.Run("first matching rule", func(t *testing.T) {
+		activity := &Activity{Description: "Standup review"}
+		err := projectAssignment.Assign(activity)
+		is.NoErr(err)
+		is.Equal(activity.ProjectID, standupProjectID)
+	})
+
+	t.Run("default project", func(t *testing.T) {
+		activity := &Activity{Description: "Planning"}
+		err := projectAssignment.Assign(activity)
+		is.NoErr(err)
+		is.Equal(activity.ProjectID, defaultProjectID)
+	})
+
+	t.Run("activity with project", func(t *testing.T) {
+		projectID := uuid.New()
+		activity := &Activity{Description: "Standup", ProjectID: projectID}
+		err := projectAssignment.Assign(activity)
+		is.NoErr(err)
+		is.Equal(activity.ProjectID, projectID)
+	})
+
+	t.Run("no default project", func(t *testing.T) {
+		activity := &Activity{Description: "Planning"}
+		err := (&ProjectAssignment{}).Assign(activity)
+		is.Equal(err, ErrProjectRequired)
+		is.Equal(activity.ProjectID, uuid.Nil)
+	})
+}
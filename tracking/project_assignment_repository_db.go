@@ -0,0 +1,158 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbProjectAssignmentRepository is a SQL database repository for default projects and project assignment rules
+type DbProjectAssignmentRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ ProjectAssignmentRepository = (*DbProjectAssignmentRepository)(nil)
+
+// NewDbProjectAssignmentRepository creates a new SQL database repository for default projects and project assignment rules
+func NewDbProjectAssignmentRepository(connPool *pgxpool.Pool) *DbProjectAssignmentRepository {
+	return &DbProjectAssignmentRepository{
+		connPool: connPool,
+	}
+}
+
+// FindDefaultProject reads the default project of the user, nil if the user has none
+func (r *DbProjectAssignmentRepository) FindDefaultProject(ctx context.Context, organizationID uuid.UUID, username string) (*uuid.UUID, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT project_id 
+		 FROM default_projects 
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+
+	var projectID uuid.UUID
+	err := row.Scan(&projectID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &projectID, nil
+}
+
+// UpdateDefaultProject sets the default project of the user, without project the default is removed
+func (r *DbProjectAssignmentRepository) UpdateDefaultProject(ctx context.Context, organizationID uuid.UUID, username string, projectID *uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	if projectID == nil {
+		_, err := tx.Exec(
+			ctx,
+			`DELETE FROM default_projects 
+			 WHERE org_id = $1 AND username = $2`,
+			organizationID, username,
+		)
+		return err
+	}
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO default_projects 
+		   (org_id, username, project_id) 
+		 VALUES 
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id, username) DO UPDATE 
+		 SET project_id = EXCLUDED.project_id`,
+		organizationID, username, *projectID,
+	)
+	return err
+}
+
+// FindProjectAssignmentRules reads the rules of the organization in the order they are applied
+func (r *DbProjectAssignmentRepository) FindProjectAssignmentRules(ctx context.Context, organizationID uuid.UUID) ([]*ProjectAssignmentRule, error) {
+	rows, err := shared.SelectAll[projectAssignmentRuleRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[projectAssignmentRuleRow]()+` 
+		 FROM project_assignment_rules 
+		 WHERE org_id = $1 
+		 ORDER BY created_at ASC, rule_id ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*ProjectAssignmentRule, len(rows))
+	for i, row := range rows {
+		rules[i] = row.toProjectAssignmentRule()
+	}
+	return rules, nil
+}
+
+func (r *DbProjectAssignmentRepository) InsertProjectAssignmentRule(ctx context.Context, rule *ProjectAssignmentRule) (*ProjectAssignmentRule, error) {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO project_assignment_rules 
+		   (rule_id, org_id, keyword, project_id) 
+		 VALUES 
+		   ($1, $2, $3, $4)`,
+		rule.ID,
+		rule.OrganizationID,
+		rule.Keyword,
+		rule.ProjectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (r *DbProjectAssignmentRepository) DeleteProjectAssignmentRuleByID(ctx context.Context, organizationID, ruleID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	row := tx.QueryRow(ctx,
+		`DELETE 
+         FROM project_assignment_rules 
+	     WHERE rule_id = $1 AND org_id = $2
+		 RETURNING rule_id`,
+		ruleID, organizationID,
+	)
+
+	var id string
+	err := row.Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProjectAssignmentRuleNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// projectAssignmentRuleRow is a row of the project_assignment_rules table
+type projectAssignmentRuleRow struct {
+	ID             string `db:"rule_id"`
+	OrganizationID string `db:"org_id"`
+	Keyword        string `db:"keyword"`
+	ProjectID      string `db:"project_id"`
+}
+
+func (r *projectAssignmentRuleRow) toProjectAssignmentRule() *ProjectAssignmentRule {
+	return &ProjectAssignmentRule{
+		ID:             uuid.MustParse(r.ID),
+		OrganizationID: uuid.MustParse(r.OrganizationID),
+		Keyword:        r.Keyword,
+		ProjectID:      uuid.MustParse(r.ProjectID),
+	}
+}
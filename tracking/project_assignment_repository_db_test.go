@@ -0,0 +1,106 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestProjectAssignmentRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	projectAssignmentRepository := NewDbProjectAssignmentRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("DefaultProject", func(t *testing.T) {
+		projectID, err := projectAssignmentRepository.FindDefaultProject(context.Background(), shared.OrganizationIDSample, "user1")
+		is.NoErr(err)
+		is.True(projectID == nil)
+
+		defaultProjectID := shared.ProjectIDSample
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return projectAssignmentRepository.UpdateDefaultProject(ctx, shared.OrganizationIDSample, "user1", &defaultProjectID)
+			},
+		)
+		is.NoErr(err)
+
+		projectID, err = projectAssignmentRepository.FindDefaultProject(context.Background(), shared.OrganizationIDSample, "user1")
+		is.NoErr(err)
+		is.Equal(*projectID, shared.ProjectIDSample)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return projectAssignmentRepository.UpdateDefaultProject(ctx, shared.OrganizationIDSample, "user1", nil)
+			},
+		)
+		is.NoErr(err)
+
+		projectID, err = projectAssignmentRepository.FindDefaultProject(context.Background(), shared.OrganizationIDSample, "user1")
+		is.NoErr(err)
+		is.True(projectID == nil)
+	})
+
+	t.Run("ProjectAssignmentRules", func(t *testing.T) {
+		rule := &ProjectAssignmentRule{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			Keyword:        "standup",
+			ProjectID:      shared.ProjectIDSample,
+		}
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := projectAssignmentRepository.InsertProjectAssignmentRule(ctx, rule)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		rules, err := projectAssignmentRepository.FindProjectAssignmentRules(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(rules), 1)
+		is.Equal(rules[0].Keyword, "standup")
+		is.Equal(rules[0].ProjectID, shared.ProjectIDSample)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return projectAssignmentRepository.DeleteProjectAssignmentRuleByID(ctx, shared.OrganizationIDSample, rule.ID)
+			},
+		)
+		is.NoErr(err)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return projectAssignmentRepository.DeleteProjectAssignmentRuleByID(ctx, shared.OrganizationIDSample, rule.ID)
+			},
+		)
+		is.Equal(err, ErrProjectAssignmentRuleNotFound)
+	})
+}
@@ -0,0 +1,80 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemProjectAssignmentRepository struct {
+	mu              sync.Mutex
+	defaultProjects map[string]uuid.UUID
+	rules           []*ProjectAssignmentRule
+}
+
+var _ ProjectAssignmentRepository = (*InMemProjectAssignmentRepository)(nil)
+
+func NewInMemProjectAssignmentRepository() *InMemProjectAssignmentRepository {
+	return &InMemProjectAssignmentRepository{
+		defaultProjects: make(map[string]uuid.UUID),
+	}
+}
+
+func (r *InMemProjectAssignmentRepository) FindDefaultProject(ctx context.Context, organizationID uuid.UUID, username string) (*uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	projectID, ok := r.defaultProjects[organizationID.String()+"/"+username]
+	if !ok {
+		return nil, nil
+	}
+	return &projectID, nil
+}
+
+func (r *InMemProjectAssignmentRepository) UpdateDefaultProject(ctx context.Context, organizationID uuid.UUID, username string, projectID *uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := organizationID.String() + "/" + username
+	if projectID == nil {
+		delete(r.defaultProjects, key)
+		return nil
+	}
+	r.defaultProjects[key] = *projectID
+	return nil
+}
+
+func (r *InMemProjectAssignmentRepository) FindProjectAssignmentRules(ctx context.Context, organizationID uuid.UUID) ([]*ProjectAssignmentRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var rules []*ProjectAssignmentRule
+	for _, rule := range r.rules {
+		if rule.OrganizationID == organizationID {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (r *InMemProjectAssignmentRepository) InsertProjectAssignmentRule(ctx context.Context, rule *ProjectAssignmentRule) (*ProjectAssignmentRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules = append(r.rules, rule)
+	return rule, nil
+}
+
+func (r *InMemProjectAssignmentRepository) DeleteProjectAssignmentRuleByID(ctx context.Context, organizationID, ruleID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, rule := range r.rules {
+		if rule.ID == ruleID && rule.OrganizationID == organizationID {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			return nil
+		}
+	}
+	return ErrProjectAssignmentRuleNotFound
+}
@@ -0,0 +1,249 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type defaultProjectModel struct {
+	ProjectID string     `json:"projectId" validate:"omitempty,uuid"`
+	Links     *hal.Links `json:"_links,omitempty"`
+}
+
+type projectAssignmentRuleModel struct {
+	ID        string     `json:"id"`
+	Keyword   string     `json:"keyword" validate:"required,min=2,max=100"`
+	ProjectID string     `json:"projectId" validate:"required,uuid"`
+	Links     *hal.Links `json:"_links"`
+}
+
+type projectAssignmentRulesModel struct {
+	Embedded *embeddedProjectAssignmentRules `json:"_embedded"`
+	Links    *hal.Links                      `json:"_links"`
+}
+
+type embeddedProjectAssignmentRules struct {
+	RuleModels []*projectAssignmentRuleModel `json:"rules"`
+}
+
+type ProjectAssignmentRestHandlers struct {
+	config                   *shared.Config
+	projectAssignmentService *ProjectAssignmentService
+}
+
+func NewProjectAssignmentRestHandlers(config *shared.Config, projectAssignmentService *ProjectAssignmentService) *ProjectAssignmentRestHandlers {
+	return &ProjectAssignmentRestHandlers{
+		config:                   config,
+		projectAssignmentService: projectAssignmentService,
+	}
+}
+
+func (a *ProjectAssignmentRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/default-project", a.HandleGetDefaultProject())
+	r.Put("/default-project", a.HandleUpdateDefaultProject())
+	r.Get("/project-assignment-rules", a.HandleGetProjectAssignmentRules())
+	r.Post("/project-assignment-rules", a.HandleCreateProjectAssignmentRule())
+	r.Delete("/project-assignment-rules/{rule-id}", a.HandleDeleteProjectAssignmentRule())
+}
+
+func (a *ProjectAssignmentRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetDefaultProject reads the default project of the user
+func (a *ProjectAssignmentRestHandlers) HandleGetDefaultProject() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectAssignmentService := a.projectAssignmentService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := projectAssignmentService.ReadDefaultProject(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToDefaultProjectModel(projectID, r.RequestURI))
+	}
+}
+
+// HandleUpdateDefaultProject sets the default project of the user, an empty project removes the default
+func (a *ProjectAssignmentRestHandlers) HandleUpdateDefaultProject() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	projectAssignmentService := a.projectAssignmentService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var defaultProjectModel defaultProjectModel
+		err := json.NewDecoder(r.Body).Decode(&defaultProjectModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "default project not valid", err)
+			return
+		}
+
+		err = validator.Struct(defaultProjectModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "default project not valid", err)
+			return
+		}
+
+		var projectID *uuid.UUID
+		if defaultProjectModel.ProjectID != "" {
+			pID := uuid.MustParse(defaultProjectModel.ProjectID)
+			projectID = &pID
+		}
+
+		err = projectAssignmentService.UpdateDefaultProject(r.Context(), principal, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToDefaultProjectModel(projectID, r.RequestURI))
+	}
+}
+
+// HandleGetProjectAssignmentRules reads the project assignment rules of the organization
+func (a *ProjectAssignmentRestHandlers) HandleGetProjectAssignmentRules() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectAssignmentService := a.projectAssignmentService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		rules, err := projectAssignmentService.ReadProjectAssignmentRules(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		ruleModels := make([]*projectAssignmentRuleModel, len(rules))
+		for i, rule := range rules {
+			ruleModels[i] = mapToProjectAssignmentRuleModel(principal, rule)
+		}
+
+		rulesModel := &projectAssignmentRulesModel{
+			Embedded: &embeddedProjectAssignmentRules{
+				RuleModels: ruleModels,
+			},
+		}
+
+		selfLink := hal.NewSelfLink(r.RequestURI)
+		if principal.HasRole("ROLE_ADMIN") {
+			rulesModel.Links = hal.NewLinks(
+				selfLink,
+				hal.NewLink("create", "/api/project-assignment-rules"),
+			)
+		} else {
+			rulesModel.Links = hal.NewLinks(
+				selfLink,
+			)
+		}
+
+		shared.RenderJSON(w, rulesModel)
+	}
+}
+
+// HandleCreateProjectAssignmentRule creates a project assignment rule
+func (a *ProjectAssignmentRestHandlers) HandleCreateProjectAssignmentRule() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	projectAssignmentService := a.projectAssignmentService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var ruleModel projectAssignmentRuleModel
+		err := json.NewDecoder(r.Body).Decode(&ruleModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project assignment rule not valid", err)
+			return
+		}
+
+		err = validator.Struct(ruleModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project assignment rule not valid", err)
+			return
+		}
+
+		rule, err := projectAssignmentService.CreateProjectAssignmentRule(r.Context(), principal, &ProjectAssignmentRule{
+			Keyword:   ruleModel.Keyword,
+			ProjectID: uuid.MustParse(ruleModel.ProjectID),
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToProjectAssignmentRuleModel(principal, rule))
+	}
+}
+
+// HandleDeleteProjectAssignmentRule deletes a project assignment rule
+func (a *ProjectAssignmentRestHandlers) HandleDeleteProjectAssignmentRule() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectAssignmentService := a.projectAssignmentService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		ruleID, err := uuid.Parse(chi.URLParam(r, "rule-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = projectAssignmentService.DeleteProjectAssignmentRuleByID(r.Context(), principal, ruleID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+func mapToDefaultProjectModel(projectID *uuid.UUID, selfHref string) *defaultProjectModel {
+	if projectID == nil {
+		return &defaultProjectModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(selfHref),
+			),
+		}
+	}
+
+	return &defaultProjectModel{
+		ProjectID: projectID.String(),
+		Links: hal.NewLinks(
+			hal.NewSelfLink(selfHref),
+			hal.NewLink("project", fmt.Sprintf("/api/projects/%s", projectID)),
+		),
+	}
+}
+
+func mapToProjectAssignmentRuleModel(principal *shared.Principal, rule *ProjectAssignmentRule) *projectAssignmentRuleModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/project-assignment-rules/%s", rule.ID))
+	projectLink := hal.NewLink("project", fmt.Sprintf("/api/projects/%s", rule.ProjectID))
+
+	ruleModel := &projectAssignmentRuleModel{
+		ID:        rule.ID.String(),
+		Keyword:   rule.Keyword,
+		ProjectID: rule.ProjectID.String(),
+	}
+	if principal.HasRole("ROLE_ADMIN") {
+		ruleModel.Links = hal.NewLinks(
+			selfLink,
+			projectLink,
+			hal.NewLink("delete", selfLink.Href()),
+		)
+	} else {
+		ruleModel.Links = hal.NewLinks(
+			selfLink,
+			projectLink,
+		)
+	}
+	return ruleModel
+}
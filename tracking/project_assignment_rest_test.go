@@ -0,0 +1,131 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleDefaultProject(t *testing.T) {
+	is := is.New(t)
+
+	a := NewProjectAssignmentRestHandlers(&shared.Config{}, &ProjectAssignmentService{
+		repositoryTxer:              shared.NewInMemRepositoryTxer(),
+		projectAssignmentRepository: NewInMemProjectAssignmentRepository(),
+		projectRepository:           NewInMemProjectRepository(),
+	})
+
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}
+
+	updateDefaultProject := func(body string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/api/default-project", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+		a.HandleUpdateDefaultProject()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := updateDefaultProject(fmt.Sprintf(`{"projectId": "%s"}`, shared.ProjectIDSample))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = updateDefaultProject(`{"projectId": "no-uuid"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = updateDefaultProject(`{"projectId": "7b8f1ae5-77b4-4bd3-a9d7-8e6d3b4cbd23"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+
+	httpRec = httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/api/default-project", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal))
+	a.HandleGetDefaultProject()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	defaultProjectModel := &defaultProjectModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(defaultProjectModel)
+	is.NoErr(err)
+	is.Equal(defaultProjectModel.ProjectID, shared.ProjectIDSample.String())
+	is.Equal(defaultProjectModel.Links.HrefOf("project"), fmt.Sprintf("/api/projects/%s", shared.ProjectIDSample))
+
+	httpRec = updateDefaultProject(`{"projectId": ""}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.True(!strings.Contains(httpRec.Body.String(), `"project"`))
+}
+
+func TestHandleProjectAssignmentRules(t *testing.T) {
+	is := is.New(t)
+
+	a := NewProjectAssignmentRestHandlers(&shared.Config{}, &ProjectAssignmentService{
+		repositoryTxer:              shared.NewInMemRepositoryTxer(),
+		projectAssignmentRepository: NewInMemProjectAssignmentRepository(),
+		projectRepository:           NewInMemProjectRepository(),
+	})
+
+	requestWithRoles := func(method, url, body string, roles ...string) *http.Request {
+		r, _ := http.NewRequest(method, url, strings.NewReader(body))
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          roles,
+		}))
+	}
+
+	body := fmt.Sprintf(`{"keyword": "standup", "projectId": "%s"}`, shared.ProjectIDSample)
+
+	httpRec := httptest.NewRecorder()
+	a.HandleCreateProjectAssignmentRule()(httpRec, requestWithRoles("POST", "/api/project-assignment-rules", body, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreateProjectAssignmentRule()(httpRec, requestWithRoles("POST", "/api/project-assignment-rules", `{"keyword": "standup"}`, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreateProjectAssignmentRule()(httpRec, requestWithRoles("POST", "/api/project-assignment-rules", body, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	ruleModel := &projectAssignmentRuleModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(ruleModel)
+	is.NoErr(err)
+	is.Equal(ruleModel.Keyword, "standup")
+	is.Equal(ruleModel.ProjectID, shared.ProjectIDSample.String())
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetProjectAssignmentRules()(httpRec, requestWithRoles("GET", "/api/project-assignment-rules", "", "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	rulesModel := &projectAssignmentRulesModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(rulesModel)
+	is.NoErr(err)
+	is.Equal(len(rulesModel.Embedded.RuleModels), 1)
+	is.Equal(rulesModel.Links.HrefOf("create"), "")
+
+	deleteRule := func(ruleID string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r := requestWithRoles("DELETE", fmt.Sprintf("/api/project-assignment-rules/%s", ruleID), "", "ROLE_ADMIN")
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("rule-id", ruleID)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, chiCtx))
+		a.HandleDeleteProjectAssignmentRule()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec = deleteRule("no-uuid")
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = deleteRule(ruleModel.ID)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = deleteRule(ruleModel.ID)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
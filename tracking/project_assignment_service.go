@@ -0,0 +1,110 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// ProjectAssignmentService manages the default projects of users and the project assignment rules of an organization
+type ProjectAssignmentService struct {
+	repositoryTxer              shared.RepositoryTxer
+	projectAssignmentRepository ProjectAssignmentRepository
+	projectRepository           ProjectRepository
+}
+
+// NewProjectAssignmentService creates a new service for default projects and project assignment rules
+func NewProjectAssignmentService(repositoryTxer shared.RepositoryTxer, projectAssignmentRepository ProjectAssignmentRepository, projectRepository ProjectRepository) *ProjectAssignmentService {
+	return &ProjectAssignmentService{
+		repositoryTxer:              repositoryTxer,
+		projectAssignmentRepository: projectAssignmentRepository,
+		projectRepository:           projectRepository,
+	}
+}
+
+// ReadDefaultProject reads the default project of the principal, nil if none is set
+func (s *ProjectAssignmentService) ReadDefaultProject(ctx context.Context, principal *shared.Principal) (*uuid.UUID, error) {
+	return s.projectAssignmentRepository.FindDefaultProject(ctx, principal.OrganizationID, principal.Username)
+}
+
+// UpdateDefaultProject sets the default project of the principal, without project the default is removed
+func (s *ProjectAssignmentService) UpdateDefaultProject(ctx context.Context, principal *shared.Principal, projectID *uuid.UUID) error {
+	if projectID != nil {
+		err := s.checkOpenProject(ctx, principal, *projectID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.projectAssignmentRepository.UpdateDefaultProject(ctx, principal.OrganizationID, principal.Username, projectID)
+		},
+	)
+}
+
+// ReadProjectAssignmentRules reads the rules of the organization in the order they are applied
+func (s *ProjectAssignmentService) ReadProjectAssignmentRules(ctx context.Context, principal *shared.Principal) ([]*ProjectAssignmentRule, error) {
+	return s.projectAssignmentRepository.FindProjectAssignmentRules(ctx, principal.OrganizationID)
+}
+
+// CreateProjectAssignmentRule adds a rule, it's applied after the existing rules
+func (s *ProjectAssignmentService) CreateProjectAssignmentRule(ctx context.Context, principal *shared.Principal, rule *ProjectAssignmentRule) (*ProjectAssignmentRule, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	err := s.checkOpenProject(ctx, principal, rule.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.ID = uuid.New()
+	rule.OrganizationID = principal.OrganizationID
+
+	var newRule *ProjectAssignmentRule
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			r, err := s.projectAssignmentRepository.InsertProjectAssignmentRule(ctx, rule)
+			if err != nil {
+				return err
+			}
+			newRule = r
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return newRule, nil
+}
+
+// DeleteProjectAssignmentRuleByID deletes a rule
+func (s *ProjectAssignmentService) DeleteProjectAssignmentRuleByID(ctx context.Context, principal *shared.Principal, ruleID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.projectAssignmentRepository.DeleteProjectAssignmentRuleByID(ctx, principal.OrganizationID, ruleID)
+		},
+	)
+}
+
+// checkOpenProject checks that activities can be assigned to the project, done projects are not
+func (s *ProjectAssignmentService) checkOpenProject(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) error {
+	project, err := s.projectRepository.FindProjectByID(ctx, principal.OrganizationID, projectID)
+	if err != nil {
+		return err
+	}
+
+	if !project.IsOpen() {
+		return ErrProjectNotFound
+	}
+	return nil
+}
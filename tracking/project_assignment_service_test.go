@@ -0,0 +1,181 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestProjectAssignmentServiceDefaultProject(t *testing.T) {
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	projectAssignmentService := &ProjectAssignmentService{
+		repositoryTxer:              shared.NewInMemRepositoryTxer(),
+		projectAssignmentRepository: NewInMemProjectAssignmentRepository(),
+		projectRepository:           projectRepository,
+	}
+
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}
+
+	t.Run("no default project", func(t *testing.T) {
+		projectID, err := projectAssignmentService.ReadDefaultProject(context.Background(), principal)
+		is.NoErr(err)
+		is.True(projectID == nil)
+	})
+
+	t.Run("set default project", func(t *testing.T) {
+		projectID := shared.ProjectIDSample
+		err := projectAssignmentService.UpdateDefaultProject(context.Background(), principal, &projectID)
+		is.NoErr(err)
+
+		defaultProjectID, err := projectAssignmentService.ReadDefaultProject(context.Background(), principal)
+		is.NoErr(err)
+		is.Equal(*defaultProjectID, shared.ProjectIDSample)
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		projectID := uuid.New()
+		err := projectAssignmentService.UpdateDefaultProject(context.Background(), principal, &projectID)
+		is.Equal(err, ErrProjectNotFound)
+	})
+
+	t.Run("done project", func(t *testing.T) {
+		project, err := projectRepository.InsertProject(context.Background(), &Project{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			Title:          "Done Project",
+			Status:         ProjectStatusDone,
+		})
+		is.NoErr(err)
+
+		err = projectAssignmentService.UpdateDefaultProject(context.Background(), principal, &project.ID)
+		is.Equal(err, ErrProjectNotFound)
+	})
+
+	t.Run("remove default project", func(t *testing.T) {
+		err := projectAssignmentService.UpdateDefaultProject(context.Background(), principal, nil)
+		is.NoErr(err)
+
+		projectID, err := projectAssignmentService.ReadDefaultProject(context.Background(), principal)
+		is.NoErr(err)
+		is.True(projectID == nil)
+	})
+}
+
+func TestProjectAssignmentServiceRules(t *testing.T) {
+	is := is.New(t)
+
+	projectAssignmentService := &ProjectAssignmentService{
+		repositoryTxer:              shared.NewInMemRepositoryTxer(),
+		projectAssignmentRepository: NewInMemProjectAssignmentRepository(),
+		projectRepository:           NewInMemProjectRepository(),
+	}
+
+	adminPrincipal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+	userPrincipal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_USER"},
+	}
+
+	var rule *ProjectAssignmentRule
+
+	t.Run("admin creates rule", func(t *testing.T) {
+		r, err := projectAssignmentService.CreateProjectAssignmentRule(context.Background(), adminPrincipal, &ProjectAssignmentRule{
+			Keyword:   "standup",
+			ProjectID: shared.ProjectIDSample,
+		})
+		is.NoErr(err)
+		is.True(r.ID != uuid.Nil)
+		is.Equal(r.OrganizationID, shared.OrganizationIDSample)
+		rule = r
+
+		rules, err := projectAssignmentService.ReadProjectAssignmentRules(context.Background(), userPrincipal)
+		is.NoErr(err)
+		is.Equal(len(rules), 1)
+	})
+
+	t.Run("user must not create rule", func(t *testing.T) {
+		_, err := projectAssignmentService.CreateProjectAssignmentRule(context.Background(), userPrincipal, &ProjectAssignmentRule{
+			Keyword:   "standup",
+			ProjectID: shared.ProjectIDSample,
+		})
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	t.Run("rule for unknown project", func(t *testing.T) {
+		_, err := projectAssignmentService.CreateProjectAssignmentRule(context.Background(), adminPrincipal, &ProjectAssignmentRule{
+			Keyword:   "standup",
+			ProjectID: uuid.New(),
+		})
+		is.Equal(err, ErrProjectNotFound)
+	})
+
+	t.Run("user must not delete rule", func(t *testing.T) {
+		err := projectAssignmentService.DeleteProjectAssignmentRuleByID(context.Background(), userPrincipal, rule.ID)
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	t.Run("admin deletes rule", func(t *testing.T) {
+		err := projectAssignmentService.DeleteProjectAssignmentRuleByID(context.Background(), adminPrincipal, rule.ID)
+		is.NoErr(err)
+
+		err = projectAssignmentService.DeleteProjectAssignmentRuleByID(context.Background(), adminPrincipal, rule.ID)
+		is.Equal(err, ErrProjectAssignmentRuleNotFound)
+	})
+}
+
+func TestCreateActivitiesWithProjectAssignment(t *testing.T) {
+	is := is.New(t)
+
+	standupProjectID := uuid.New()
+	projectAssignmentRepository := NewInMemProjectAssignmentRepository()
+	_, err := projectAssignmentRepository.InsertProjectAssignmentRule(context.Background(), &ProjectAssignmentRule{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Keyword:        "standup",
+		ProjectID:      standupProjectID,
+	})
+	is.NoErr(err)
+
+	defaultProjectID := shared.ProjectIDSample
+	err = projectAssignmentRepository.UpdateDefaultProject(context.Background(), shared.OrganizationIDSample, "user1", &defaultProjectID)
+	is.NoErr(err)
+
+	activityRepository := NewInMemActivityRepository()
+	actitivityService := &ActitivityService{
+		repositoryTxer:               shared.NewInMemRepositoryTxer(),
+		activityRepository:           activityRepository,
+		locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+		projectAssignmentRepository:  projectAssignmentRepository,
+		categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+	}
+
+	principal := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}
+
+	start := time.Date(2021, 11, 6, 9, 0, 0, 0, time.UTC)
+	activities := []*Activity{
+		{Start: start, End: start.Add(15 * time.Minute), Description: "Daily Standup"},
+		{Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Description: "Planning"},
+	}
+
+	rejected, err := actitivityService.CreateActivities(context.Background(), principal, activities, ActivitySourceImport, false)
+	is.NoErr(err)
+	is.Equal(len(rejected), 0)
+	is.Equal(activities[0].ProjectID, standupProjectID)
+	is.Equal(activities[1].ProjectID, shared.ProjectIDSample)
+}
@@ -2,20 +2,170 @@ package tracking
 
 import (
 	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/baralga/shared"
 	"github.com/baralga/shared/paged"
 	"github.com/google/uuid"
-	"github.com/pkg/errors"
 )
 
-var ErrProjectNotFound = errors.New("project not found")
+const (
+	ProjectStatusProposed = "proposed"
+	ProjectStatusActive   = "active"
+	ProjectStatusOnHold   = "on-hold"
+	ProjectStatusDone     = "done"
+)
+
+var (
+	ErrProjectNotFound                = shared.NewDomainError("project:not-found", http.StatusNotFound, "project not found")
+	ErrInvalidProjectStatusTransition = shared.NewDomainError("project:invalid-status-transition", http.StatusConflict, "invalid project status transition")
+	ErrProjectAliasNotFound           = shared.NewDomainError("project:alias-not-found", http.StatusNotFound, "project alias not found")
+	ErrProjectAliasTaken              = shared.NewDomainError("project:alias-taken", http.StatusConflict, "project alias is already used in the organization")
+)
+
+// projectAliasPattern are the short alphanumeric codes allowed as project aliases like WEB or ACME24
+var projectAliasPattern = regexp.MustCompile(`^[A-Za-z0-9]{2,10}$`)
+
+// projectStatusTransitions are the statuses a project may change to from a status
+var projectStatusTransitions = map[string][]string{
+	ProjectStatusProposed: {ProjectStatusActive, ProjectStatusOnHold, ProjectStatusDone},
+	ProjectStatusActive:   {ProjectStatusOnHold, ProjectStatusDone},
+	ProjectStatusOnHold:   {ProjectStatusActive, ProjectStatusDone},
+	ProjectStatusDone:     {ProjectStatusActive},
+}
+
+// maxProjectTitleLength is the maximum length of project titles
+const maxProjectTitleLength = 100
+
+// OpenProjectStatuses are the statuses of projects that are not done yet
+var OpenProjectStatuses = []string{ProjectStatusProposed, ProjectStatusActive, ProjectStatusOnHold}
 
 type Project struct {
 	ID             uuid.UUID
 	Title          string
 	Description    string
-	Active         bool
+	Status         string
+	StartedAt      *time.Time
+	CompletedAt    *time.Time
+	Billable       bool
+	BudgetMinutes  int
+	OrganizationID uuid.UUID
+}
+
+// ProjectStatusChange is an entry in the audit trail of the project lifecycle,
+// the first change of a project has no previous status
+type ProjectStatusChange struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ProjectID      uuid.UUID
+	FromStatus     string
+	ToStatus       string
+	ChangedBy      string
+	ChangedAt      time.Time
+}
+
+// ProjectAlias is a short code of a project, unique within the organization,
+// which can be used instead of the project id
+type ProjectAlias struct {
 	OrganizationID uuid.UUID
+	ProjectID      uuid.UUID
+	Alias          string
+}
+
+// ProjectBurndownItem is the tracked time of a project in a day or week against its budget
+type ProjectBurndownItem struct {
+	Date                   time.Time
+	DurationInMinutesTotal int
+	CumulativeMinutes      int
+	RemainingMinutes       int
+	Forecast               bool
+}
+
+// HasBudget checks whether a budget is set for the project
+func (p *Project) HasBudget() bool {
+	return p.BudgetMinutes > 0
+}
+
+// CanChangeStatusTo checks whether the project may change to the status
+func (p *Project) CanChangeStatusTo(status string) bool {
+	for _, s := range projectStatusTransitions[p.Status] {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeStatus changes the status of the project if allowed, the project is started
+// when it becomes active for the first time and completed when it is done
+func (p *Project) ChangeStatus(status string, now time.Time) error {
+	if !p.CanChangeStatusTo(status) {
+		return ErrInvalidProjectStatusTransition
+	}
+
+	p.Status = status
+	p.stampStatus(now)
+	return nil
+}
+
+// IsOpen checks whether the project is not done yet
+func (p *Project) IsOpen() bool {
+	return p.Status != ProjectStatusDone
+}
+
+func (p *Project) stampStatus(now time.Time) {
+	if p.Status == ProjectStatusActive && p.StartedAt == nil {
+		p.StartedAt = &now
+	}
+
+	if p.Status == ProjectStatusDone {
+		p.CompletedAt = &now
+	} else {
+		p.CompletedAt = nil
+	}
+}
+
+// IsValidProjectStatus checks whether the status is known
+func IsValidProjectStatus(status string) bool {
+	_, ok := projectStatusTransitions[status]
+	return ok
+}
+
+// truncateToBucket truncates the time to the start of its day or ISO week
+func truncateToBucket(t time.Time, aggregateBy string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if aggregateBy != "week" {
+		return day
+	}
+	weekday := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -weekday)
+}
+
+// IsValidProjectSortField checks whether projects can be sorted by the field
+func IsValidProjectSortField(f string) bool {
+	switch strings.ToLower(f) {
+	case "title":
+		return true
+	case "created_at":
+		return true
+	case "last-used":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidProjectAlias checks whether the alias is a short alphanumeric code
+func IsValidProjectAlias(alias string) bool {
+	return projectAliasPattern.MatchString(alias)
+}
+
+// NormalizeProjectAlias is the alias as stored, aliases are not case sensitive
+func NormalizeProjectAlias(alias string) string {
+	return strings.ToUpper(alias)
 }
 
 type ProjectsPaged struct {
@@ -25,10 +175,17 @@ type ProjectsPaged struct {
 
 type ProjectRepository interface {
 	FindProjects(ctx context.Context, organizationID uuid.UUID, pageParams *paged.PageParams) (*ProjectsPaged, error)
+	FindProjectsWithStatus(ctx context.Context, organizationID uuid.UUID, statuses []string, pageParams *paged.PageParams) (*ProjectsPaged, error)
 	FindProjectsByIDs(ctx context.Context, organizationID uuid.UUID, projectIDs []uuid.UUID) ([]*Project, error)
 	FindProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) (*Project, error)
 	InsertProject(ctx context.Context, project *Project) (*Project, error)
+	CloneProject(ctx context.Context, organizationID, sourceProjectID uuid.UUID, project *Project) (*Project, error)
 	UpdateProject(ctx context.Context, organizationID uuid.UUID, project *Project) (*Project, error)
-	ArchiveProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) error
 	DeleteProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) error
+	InsertProjectStatusChange(ctx context.Context, statusChange *ProjectStatusChange) error
+	FindProjectStatusChanges(ctx context.Context, organizationID, projectID uuid.UUID) ([]*ProjectStatusChange, error)
+	FindProjectByAlias(ctx context.Context, organizationID uuid.UUID, alias string) (*Project, error)
+	FindProjectAliases(ctx context.Context, organizationID, projectID uuid.UUID) ([]*ProjectAlias, error)
+	InsertProjectAlias(ctx context.Context, projectAlias *ProjectAlias) error
+	DeleteProjectAlias(ctx context.Context, organizationID, projectID uuid.UUID, alias string) error
 }
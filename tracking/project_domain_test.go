@@ -0,0 +1,60 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestProjectChangeStatus(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	project := &Project{Status: ProjectStatusProposed}
+
+	err := project.ChangeStatus(ProjectStatusActive, now)
+	is.NoErr(err)
+	is.Equal(*project.StartedAt, now)
+
+	err = project.ChangeStatus(ProjectStatusOnHold, now.AddDate(0, 1, 0))
+	is.NoErr(err)
+
+	err = project.ChangeStatus(ProjectStatusActive, now.AddDate(0, 2, 0))
+	is.NoErr(err)
+	is.Equal(*project.StartedAt, now)
+
+	err = project.ChangeStatus(ProjectStatusDone, now.AddDate(0, 3, 0))
+	is.NoErr(err)
+	is.Equal(*project.CompletedAt, now.AddDate(0, 3, 0))
+	is.True(!project.IsOpen())
+
+	err = project.ChangeStatus(ProjectStatusOnHold, now.AddDate(0, 4, 0))
+	is.Equal(err, ErrInvalidProjectStatusTransition)
+
+	err = project.ChangeStatus(ProjectStatusActive, now.AddDate(0, 4, 0))
+	is.NoErr(err)
+	is.True(project.CompletedAt == nil)
+	is.True(project.IsOpen())
+}
+
+func TestIsValidProjectStatus(t *testing.T) {
+	is := is.New(t)
+
+	is.True(IsValidProjectStatus(ProjectStatusProposed))
+	is.True(IsValidProjectStatus(ProjectStatusOnHold))
+	is.True(!IsValidProjectStatus("archived"))
+	is.True(!IsValidProjectStatus(""))
+}
+
+func TestIsValidProjectAlias(t *testing.T) {
+	is := is.New(t)
+
+	is.True(IsValidProjectAlias("WEB"))
+	is.True(IsValidProjectAlias("acme24"))
+	is.True(!IsValidProjectAlias("W"))
+	is.True(!IsValidProjectAlias("ACME-WEB"))
+	is.True(!IsValidProjectAlias("ACMEWEBSITE"))
+	is.True(!IsValidProjectAlias(shared.ProjectIDSample.String()))
+}
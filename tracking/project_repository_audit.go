@@ -0,0 +1,132 @@
+package tracking
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	AuditEntityProject = "project"
+
+	AuditActionProjectCreated = "project:created"
+	AuditActionProjectUpdated = "project:updated"
+	AuditActionProjectDeleted = "project:deleted"
+)
+
+// AuditedProjectRepository records every change of a project with the changed fields
+// in the audit log, within the transaction of the change
+type AuditedProjectRepository struct {
+	ProjectRepository
+	auditRepository shared.AuditRepository
+}
+
+var _ ProjectRepository = (*AuditedProjectRepository)(nil)
+
+// NewAuditedProjectRepository creates a new project repository which records changes in the audit log
+func NewAuditedProjectRepository(projectRepository ProjectRepository, auditRepository shared.AuditRepository) *AuditedProjectRepository {
+	return &AuditedProjectRepository{
+		ProjectRepository: projectRepository,
+		auditRepository:   auditRepository,
+	}
+}
+
+func (r *AuditedProjectRepository) InsertProject(ctx context.Context, project *Project) (*Project, error) {
+	inserted, err := r.ProjectRepository.InsertProject(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.recordChange(ctx, AuditActionProjectCreated, inserted.OrganizationID, inserted.ID, nil, inserted)
+	if err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+func (r *AuditedProjectRepository) CloneProject(ctx context.Context, organizationID, sourceProjectID uuid.UUID, project *Project) (*Project, error) {
+	cloned, err := r.ProjectRepository.CloneProject(ctx, organizationID, sourceProjectID, project)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.recordChange(ctx, AuditActionProjectCreated, organizationID, cloned.ID, nil, cloned)
+	if err != nil {
+		return nil, err
+	}
+	return cloned, nil
+}
+
+func (r *AuditedProjectRepository) UpdateProject(ctx context.Context, organizationID uuid.UUID, project *Project) (*Project, error) {
+	before, err := r.ProjectRepository.FindProjectByID(ctx, organizationID, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.ProjectRepository.UpdateProject(ctx, organizationID, project)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.recordChange(ctx, AuditActionProjectUpdated, organizationID, updated.ID, before, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *AuditedProjectRepository) DeleteProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) error {
+	before, err := r.ProjectRepository.FindProjectByID(ctx, organizationID, projectID)
+	if err != nil {
+		return err
+	}
+
+	err = r.ProjectRepository.DeleteProjectByID(ctx, organizationID, projectID)
+	if err != nil {
+		return err
+	}
+
+	return r.recordChange(ctx, AuditActionProjectDeleted, organizationID, projectID, before, nil)
+}
+
+// recordChange adds the changed fields of the project to the audit log, updates without changes are not recorded
+func (r *AuditedProjectRepository) recordChange(ctx context.Context, action string, organizationID, projectID uuid.UUID, before, after *Project) error {
+	auditEntry := shared.NewEntityAuditEntry(
+		auditActorOf(ctx, organizationID),
+		action,
+		AuditEntityProject,
+		projectID,
+		auditFieldsOfProject(before),
+		auditFieldsOfProject(after),
+		time.Now(),
+	)
+	if action == AuditActionProjectUpdated && len(auditEntry.Changes) == 0 {
+		return nil
+	}
+
+	return r.auditRepository.InsertAuditEntry(ctx, auditEntry)
+}
+
+func auditFieldsOfProject(project *Project) map[string]string {
+	if project == nil {
+		return nil
+	}
+
+	fields := map[string]string{
+		"title":         project.Title,
+		"description":   project.Description,
+		"status":        project.Status,
+		"billable":      strconv.FormatBool(project.Billable),
+		"budgetMinutes": strconv.Itoa(project.BudgetMinutes),
+	}
+	if project.StartedAt != nil {
+		fields["startedAt"] = project.StartedAt.UTC().Format(time.RFC3339)
+	}
+	if project.CompletedAt != nil {
+		fields["completedAt"] = project.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	return fields
+}
@@ -0,0 +1,34 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ReportInvalidatingProjectRepository invalidates the cached reports of the organization once changes of projects
+// committed, as the reports contain the title and billable flag of the projects and deleting a project deletes its activities
+type ReportInvalidatingProjectRepository struct {
+	ProjectRepository
+	reportCache *CachedActivityRepository
+}
+
+var _ ProjectRepository = (*ReportInvalidatingProjectRepository)(nil)
+
+// NewReportInvalidatingProjectRepository creates a new project repository which invalidates the report cache on changes
+func NewReportInvalidatingProjectRepository(projectRepository ProjectRepository, reportCache *CachedActivityRepository) *ReportInvalidatingProjectRepository {
+	return &ReportInvalidatingProjectRepository{
+		ProjectRepository: projectRepository,
+		reportCache:       reportCache,
+	}
+}
+
+func (r *ReportInvalidatingProjectRepository) UpdateProject(ctx context.Context, organizationID uuid.UUID, project *Project) (*Project, error) {
+	r.reportCache.invalidateAfterCommit(ctx, organizationID)
+	return r.ProjectRepository.UpdateProject(ctx, organizationID, project)
+}
+
+func (r *ReportInvalidatingProjectRepository) DeleteProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) error {
+	r.reportCache.invalidateAfterCommit(ctx, organizationID)
+	return r.ProjectRepository.DeleteProjectByID(ctx, organizationID, projectID)
+}
@@ -0,0 +1,186 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/paged"
+	"github.com/baralga/shared/testkit"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+// projectRepositoryContractSetup provides the project repository under test with a function
+// creating a new organization without projects
+type projectRepositoryContractSetup func(t *testing.T) (ProjectRepository, shared.RepositoryTxer, func() uuid.UUID)
+
+func TestInMemProjectRepositoryContract(t *testing.T) {
+	testProjectRepositoryContract(t, func(t *testing.T) (ProjectRepository, shared.RepositoryTxer, func() uuid.UUID) {
+		return NewInMemProjectRepository(), shared.NewInMemRepositoryTxer(), uuid.New
+	})
+}
+
+func TestDbProjectRepositoryContract(t *testing.T) {
+	testProjectRepositoryContract(t, func(t *testing.T) (ProjectRepository, shared.RepositoryTxer, func() uuid.UUID) {
+		db := testkit.NewDatabase(t)
+		return NewDbProjectRepository(db.ConnPool), db.RepositoryTxer, func() uuid.UUID {
+			return db.Organization().Insert(t).ID
+		}
+	})
+}
+
+// testProjectRepositoryContract verifies the behavior all implementations of the project repository share
+func testProjectRepositoryContract(t *testing.T, setup projectRepositoryContractSetup) {
+	is := is.New(t)
+	projectRepository, repositoryTxer, newOrganizationID := setup(t)
+	ctx := context.Background()
+
+	insertProject := func(organizationID uuid.UUID, title, status string) *Project {
+		project := &Project{
+			ID:             uuid.New(),
+			Title:          title,
+			Status:         status,
+			OrganizationID: organizationID,
+		}
+		err := repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				_, err := projectRepository.InsertProject(ctx, project)
+				return err
+			},
+		)
+		is.NoErr(err)
+		return project
+	}
+
+	t.Run("FindProjectsPaged", func(t *testing.T) {
+		organizationID := newOrganizationID()
+		insertProject(organizationID, "Charlie", ProjectStatusActive)
+		insertProject(organizationID, "Alpha", ProjectStatusActive)
+		insertProject(organizationID, "Bravo", ProjectStatusActive)
+
+		projectsPaged, err := projectRepository.FindProjects(ctx, organizationID, &paged.PageParams{Page: 0, Size: 2})
+		is.NoErr(err)
+		is.Equal(len(projectsPaged.Projects), 2)
+		is.Equal(projectsPaged.Projects[0].Title, "Alpha")
+		is.Equal(projectsPaged.Projects[1].Title, "Bravo")
+		is.Equal(projectsPaged.Page.TotalElements, 3)
+		is.Equal(projectsPaged.Page.TotalPages, 2)
+
+		projectsPaged, err = projectRepository.FindProjects(ctx, organizationID, &paged.PageParams{Page: 1, Size: 2})
+		is.NoErr(err)
+		is.Equal(len(projectsPaged.Projects), 1)
+		is.Equal(projectsPaged.Projects[0].Title, "Charlie")
+	})
+
+	t.Run("FindProjectsWithStatus", func(t *testing.T) {
+		organizationID := newOrganizationID()
+		insertProject(organizationID, "Open", ProjectStatusActive)
+		done := insertProject(organizationID, "Done", ProjectStatusDone)
+
+		projectsPaged, err := projectRepository.FindProjects(ctx, organizationID, &paged.PageParams{Size: 10})
+		is.NoErr(err)
+		is.Equal(len(projectsPaged.Projects), 1)
+		is.Equal(projectsPaged.Projects[0].Title, "Open")
+
+		projectsPaged, err = projectRepository.FindProjectsWithStatus(ctx, organizationID, []string{ProjectStatusDone}, &paged.PageParams{Size: 10})
+		is.NoErr(err)
+		is.Equal(len(projectsPaged.Projects), 1)
+		is.Equal(projectsPaged.Projects[0].ID, done.ID)
+	})
+
+	t.Run("FindProjectByID", func(t *testing.T) {
+		organizationID := newOrganizationID()
+		project := insertProject(organizationID, "Alpha", ProjectStatusActive)
+
+		found, err := projectRepository.FindProjectByID(ctx, organizationID, project.ID)
+		is.NoErr(err)
+		is.Equal(found.ID, project.ID)
+		is.Equal(found.Title, "Alpha")
+		is.Equal(found.OrganizationID, organizationID)
+
+		_, err = projectRepository.FindProjectByID(ctx, organizationID, uuid.New())
+		is.Equal(err, ErrProjectNotFound)
+	})
+
+	t.Run("ProjectsOfOtherOrganization", func(t *testing.T) {
+		organizationID := newOrganizationID()
+		other := insertProject(newOrganizationID(), "Other", ProjectStatusActive)
+
+		projectsPaged, err := projectRepository.FindProjects(ctx, organizationID, &paged.PageParams{Size: 10})
+		is.NoErr(err)
+		is.Equal(len(projectsPaged.Projects), 0)
+		is.Equal(projectsPaged.Page.TotalElements, 0)
+
+		_, err = projectRepository.FindProjectByID(ctx, organizationID, other.ID)
+		is.Equal(err, ErrProjectNotFound)
+
+		projects, err := projectRepository.FindProjectsByIDs(ctx, organizationID, []uuid.UUID{other.ID})
+		is.NoErr(err)
+		is.Equal(len(projects), 0)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				_, err := projectRepository.UpdateProject(ctx, organizationID, &Project{ID: other.ID, Title: "Changed", Status: ProjectStatusActive})
+				return err
+			},
+		)
+		is.Equal(err, ErrProjectNotFound)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return projectRepository.DeleteProjectByID(ctx, organizationID, other.ID)
+			},
+		)
+		is.Equal(err, ErrProjectNotFound)
+
+		found, err := projectRepository.FindProjectByID(ctx, other.OrganizationID, other.ID)
+		is.NoErr(err)
+		is.Equal(found.Title, "Other")
+	})
+
+	t.Run("UpdateProject", func(t *testing.T) {
+		organizationID := newOrganizationID()
+		project := insertProject(organizationID, "Alpha", ProjectStatusActive)
+
+		err := repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				_, err := projectRepository.UpdateProject(ctx, organizationID, &Project{ID: project.ID, Title: "Changed", Status: ProjectStatusActive, OrganizationID: organizationID})
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		found, err := projectRepository.FindProjectByID(ctx, organizationID, project.ID)
+		is.NoErr(err)
+		is.Equal(found.Title, "Changed")
+	})
+
+	t.Run("DeleteProjectByID", func(t *testing.T) {
+		organizationID := newOrganizationID()
+		project := insertProject(organizationID, "Alpha", ProjectStatusActive)
+
+		err := repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return projectRepository.DeleteProjectByID(ctx, organizationID, project.ID)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = projectRepository.FindProjectByID(ctx, organizationID, project.ID)
+		is.Equal(err, ErrProjectNotFound)
+
+		err = repositoryTxer.InTx(
+			ctx,
+			func(ctx context.Context) error {
+				return projectRepository.DeleteProjectByID(ctx, organizationID, project.ID)
+			},
+		)
+		is.Equal(err, ErrProjectNotFound)
+	})
+}
@@ -3,6 +3,9 @@ package tracking
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/baralga/shared"
 	"github.com/baralga/shared/paged"
@@ -26,53 +29,45 @@ func NewDbProjectRepository(connPool *pgxpool.Pool) *DbProjectRepository {
 	}
 }
 
+// FindProjects reads the open projects, done projects are left out
 func (r *DbProjectRepository) FindProjects(ctx context.Context, organizationID uuid.UUID, pageParams *paged.PageParams) (*ProjectsPaged, error) {
-	rows, err := r.connPool.Query(
-		ctx,
-		`SELECT project_id as id, title, description, active 
+	return r.FindProjectsWithStatus(ctx, organizationID, OpenProjectStatuses, pageParams)
+}
+
+func (r *DbProjectRepository) FindProjectsWithStatus(ctx context.Context, organizationID uuid.UUID, statuses []string, pageParams *paged.PageParams) (*ProjectsPaged, error) {
+	findSql := fmt.Sprintf(
+		`SELECT %s 
 		 FROM projects 
-		 WHERE org_id = $1 AND active = true
-		 ORDER BY title ASC 
-		 LIMIT $2 OFFSET $3`,
-		organizationID, pageParams.Size, pageParams.Offset(),
+		 WHERE org_id = $1 AND status = any($2)
+		 ORDER BY %s
+		 LIMIT $3 OFFSET $4`,
+		shared.Columns[projectRow](),
+		projectsOrderBy(pageParams),
 	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
 
 	var projects []*Project
-	for rows.Next() {
-		var (
-			id          string
-			title       string
-			description sql.NullString
-			active      bool
+	var total int
+	err := shared.InSnapshot(ctx, r.connPool, func(tx pgx.Tx) error {
+		rows, err := shared.SelectAll[projectRow](
+			ctx,
+			tx,
+			findSql,
+			organizationID, statuses, pageParams.Size, pageParams.Offset(),
 		)
-
-		err = rows.Scan(&id, &title, &description, &active)
 		if err != nil {
-			return nil, err
-		}
-
-		project := &Project{
-			ID:          uuid.MustParse(id),
-			Title:       title,
-			Description: description.String,
-			Active:      active,
+			return err
 		}
-		projects = append(projects, project)
-	}
-
-	row := r.connPool.QueryRow(
-		ctx,
-		`SELECT count(*) as total 
-		 FROM projects 
-		 WHERE org_id = $1 AND active = true`,
-		organizationID,
-	)
-	var total int
-	err = row.Scan(&total)
+		projects = mapToProjects(rows)
+
+		row := tx.QueryRow(
+			ctx,
+			`SELECT count(*) as total 
+			 FROM projects 
+			 WHERE org_id = $1 AND status = any($2)`,
+			organizationID, statuses,
+		)
+		return row.Scan(&total)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -86,9 +81,10 @@ func (r *DbProjectRepository) FindProjects(ctx context.Context, organizationID u
 }
 
 func (r *DbProjectRepository) FindProjectsByIDs(ctx context.Context, organizationID uuid.UUID, projectIDs []uuid.UUID) ([]*Project, error) {
-	rows, err := r.connPool.Query(
+	rows, err := shared.SelectAll[projectRow](
 		ctx,
-		`SELECT project_id as id, title, description, active 
+		r.connPool,
+		`SELECT `+shared.Columns[projectRow]()+` 
 		 FROM projects 
 		 WHERE org_id = $1 AND project_id = any($2) 
 		 ORDER by title ASC`,
@@ -97,49 +93,19 @@ func (r *DbProjectRepository) FindProjectsByIDs(ctx context.Context, organizatio
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var projects []*Project
-	for rows.Next() {
-		var (
-			id          string
-			title       string
-			description sql.NullString
-			active      bool
-		)
-
-		err = rows.Scan(&id, &title, &description, &active)
-		if err != nil {
-			return nil, err
-		}
-
-		project := &Project{
-			ID:          uuid.MustParse(id),
-			Title:       title,
-			Description: description.String,
-			Active:      active,
-		}
-		projects = append(projects, project)
-	}
 
-	return projects, nil
+	return mapToProjects(rows), nil
 }
 
 func (r *DbProjectRepository) FindProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) (*Project, error) {
-	row := r.connPool.QueryRow(ctx,
-		`SELECT project_id as id, title, description, active  
-         FROM projects 
-	     WHERE project_id = $1 AND org_id = $2`,
-		projectID, organizationID)
-
-	var (
-		id          string
-		title       string
-		description sql.NullString
-		active      bool
+	row, err := shared.SelectOne[projectRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[projectRow]()+` 
+		 FROM projects 
+		 WHERE project_id = $1 AND org_id = $2`,
+		projectID, organizationID,
 	)
-
-	err := row.Scan(&id, &title, &description, &active)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrProjectNotFound
@@ -148,14 +114,7 @@ func (r *DbProjectRepository) FindProjectByID(ctx context.Context, organizationI
 		return nil, err
 	}
 
-	project := &Project{
-		ID:          uuid.MustParse(id),
-		Title:       title,
-		Description: description.String,
-		Active:      active,
-	}
-
-	return project, nil
+	return row.toProject(), nil
 }
 
 func (r *DbProjectRepository) InsertProject(ctx context.Context, project *Project) (*Project, error) {
@@ -164,16 +123,56 @@ func (r *DbProjectRepository) InsertProject(ctx context.Context, project *Projec
 	_, err := tx.Exec(
 		ctx,
 		`INSERT INTO projects 
-		   (project_id, title, active, description, org_id) 
+		   (project_id, title, status, started_at, completed_at, description, org_id, billable, budget_minutes) 
 		 VALUES 
-		   ($1, $2, $3, $4, $5)`,
+		   ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
 		project.ID,
 		project.Title,
-		project.Active,
+		project.Status,
+		project.StartedAt,
+		project.CompletedAt,
 		project.Description,
 		project.OrganizationID,
+		project.Billable,
+		project.BudgetMinutes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// CloneProject inserts the project with the structure of the source project, like its client
+func (r *DbProjectRepository) CloneProject(ctx context.Context, organizationID, sourceProjectID uuid.UUID, project *Project) (*Project, error) {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	row := tx.QueryRow(
+		ctx,
+		`INSERT INTO projects 
+		   (project_id, title, status, started_at, completed_at, description, org_id, billable, budget_minutes, client_id) 
+		 SELECT $3, $4, $5, $6, $7, $8, org_id, $9, $10, client_id 
+		 FROM projects 
+		 WHERE project_id = $1 AND org_id = $2
+		 RETURNING project_id`,
+		sourceProjectID, organizationID,
+		project.ID,
+		project.Title,
+		project.Status,
+		project.StartedAt,
+		project.CompletedAt,
+		project.Description,
+		project.Billable,
+		project.BudgetMinutes,
 	)
+
+	var id string
+	err := row.Scan(&id)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProjectNotFound
+		}
+
 		return nil, err
 	}
 
@@ -185,11 +184,11 @@ func (r *DbProjectRepository) UpdateProject(ctx context.Context, organizationID
 
 	row := tx.QueryRow(ctx,
 		`UPDATE projects 
-		 SET title = $3, description = $4, active = $5 
+		 SET title = $3, description = $4, status = $5, started_at = $6, completed_at = $7, billable = $8, budget_minutes = $9 
 		 WHERE project_id = $1 AND org_id = $2
 		 RETURNING project_id`,
 		project.ID, organizationID,
-		project.Title, project.Description, project.Active,
+		project.Title, project.Description, project.Status, project.StartedAt, project.CompletedAt, project.Billable, project.BudgetMinutes,
 	)
 
 	var id string
@@ -242,29 +241,230 @@ func (r *DbProjectRepository) DeleteProjectByID(ctx context.Context, organizatio
 	return nil
 }
 
-func (r *DbProjectRepository) ArchiveProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) error {
+func (r *DbProjectRepository) InsertProjectStatusChange(ctx context.Context, statusChange *ProjectStatusChange) error {
 	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
 
-	row := tx.QueryRow(ctx,
-		`UPDATE projects 
-		 SET active = false 
-		 WHERE project_id = $1 AND org_id = $2
-		 RETURNING project_id`,
-		projectID, organizationID)
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO project_status_changes 
+		   (status_change_id, org_id, project_id, from_status, to_status, changed_by, changed_at) 
+		 VALUES 
+		   ($1, $2, $3, $4, $5, $6, $7)`,
+		statusChange.ID,
+		statusChange.OrganizationID,
+		statusChange.ProjectID,
+		sql.NullString{String: statusChange.FromStatus, Valid: statusChange.FromStatus != ""},
+		statusChange.ToStatus,
+		statusChange.ChangedBy,
+		statusChange.ChangedAt,
+	)
+	return err
+}
 
-	var id string
-	err := row.Scan(&id)
+func (r *DbProjectRepository) FindProjectStatusChanges(ctx context.Context, organizationID, projectID uuid.UUID) ([]*ProjectStatusChange, error) {
+	rows, err := shared.SelectAll[projectStatusChangeRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[projectStatusChangeRow]()+` 
+		 FROM project_status_changes 
+		 WHERE project_id = $1 AND org_id = $2 
+		 ORDER BY changed_at ASC`,
+		projectID, organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	statusChanges := make([]*ProjectStatusChange, len(rows))
+	for i, row := range rows {
+		statusChanges[i] = row.toProjectStatusChange()
+	}
+	return statusChanges, nil
+}
+
+func (r *DbProjectRepository) FindProjectByAlias(ctx context.Context, organizationID uuid.UUID, alias string) (*Project, error) {
+	row, err := shared.SelectOne[projectRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[projectRow]()+` 
+		 FROM projects 
+		 WHERE org_id = $1 AND project_id = (
+		   SELECT project_id FROM project_aliases WHERE org_id = $1 AND alias = $2
+		 )`,
+		organizationID, NormalizeProjectAlias(alias),
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrProjectNotFound
+			return nil, ErrProjectNotFound
 		}
 
+		return nil, err
+	}
+
+	return row.toProject(), nil
+}
+
+func (r *DbProjectRepository) FindProjectAliases(ctx context.Context, organizationID, projectID uuid.UUID) ([]*ProjectAlias, error) {
+	rows, err := shared.SelectAll[projectAliasRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[projectAliasRow]()+` 
+		 FROM project_aliases 
+		 WHERE project_id = $1 AND org_id = $2 
+		 ORDER BY alias ASC`,
+		projectID, organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	projectAliases := make([]*ProjectAlias, len(rows))
+	for i, row := range rows {
+		projectAliases[i] = row.toProjectAlias()
+	}
+	return projectAliases, nil
+}
+
+func (r *DbProjectRepository) InsertProjectAlias(ctx context.Context, projectAlias *ProjectAlias) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`INSERT INTO project_aliases 
+		   (org_id, alias, project_id) 
+		 VALUES 
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id, alias) DO NOTHING`,
+		projectAlias.OrganizationID,
+		NormalizeProjectAlias(projectAlias.Alias),
+		projectAlias.ProjectID,
+	)
+	if err != nil {
 		return err
 	}
 
-	if id != projectID.String() {
-		return ErrProjectNotFound
+	if result.RowsAffected() == 0 {
+		return ErrProjectAliasTaken
+	}
+
+	return nil
+}
+
+func (r *DbProjectRepository) DeleteProjectAlias(ctx context.Context, organizationID, projectID uuid.UUID, alias string) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE 
+		 FROM project_aliases 
+		 WHERE org_id = $1 AND project_id = $2 AND alias = $3`,
+		organizationID, projectID, NormalizeProjectAlias(alias),
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrProjectAliasNotFound
 	}
 
 	return nil
 }
+
+// projectsOrderBy maps the whitelisted sort field of the page params to the order by clause,
+// ties are ordered by title and id so that paging is stable
+func projectsOrderBy(pageParams *paged.PageParams) string {
+	sortOrder := "ASC"
+	if strings.ToLower(pageParams.SortOrder) == SortOrderDesc {
+		sortOrder = "DESC"
+	}
+
+	if !IsValidProjectSortField(pageParams.SortBy) {
+		return "title ASC, project_id ASC"
+	}
+
+	switch strings.ToLower(pageParams.SortBy) {
+	case "created_at":
+		return fmt.Sprintf("created_at %s, title ASC, project_id ASC", sortOrder)
+	case "last-used":
+		return fmt.Sprintf(
+			`(SELECT max(start_time) FROM activities WHERE activities.project_id = projects.project_id) %s NULLS LAST, title ASC, project_id ASC`,
+			sortOrder,
+		)
+	default:
+		return fmt.Sprintf("title %s, project_id ASC", sortOrder)
+	}
+}
+
+// projectRow is a row of the projects table
+type projectRow struct {
+	ID             string         `db:"project_id"`
+	OrganizationID string         `db:"org_id"`
+	Title          string         `db:"title"`
+	Description    sql.NullString `db:"description"`
+	Status         string         `db:"status"`
+	StartedAt      *time.Time     `db:"started_at"`
+	CompletedAt    *time.Time     `db:"completed_at"`
+	Billable       bool           `db:"billable"`
+	BudgetMinutes  int            `db:"budget_minutes"`
+}
+
+func (r *projectRow) toProject() *Project {
+	return &Project{
+		ID:             uuid.MustParse(r.ID),
+		OrganizationID: uuid.MustParse(r.OrganizationID),
+		Title:          r.Title,
+		Description:    r.Description.String,
+		Status:         r.Status,
+		StartedAt:      r.StartedAt,
+		CompletedAt:    r.CompletedAt,
+		Billable:       r.Billable,
+		BudgetMinutes:  r.BudgetMinutes,
+	}
+}
+
+func mapToProjects(rows []*projectRow) []*Project {
+	projects := make([]*Project, len(rows))
+	for i, row := range rows {
+		projects[i] = row.toProject()
+	}
+	return projects
+}
+
+// projectStatusChangeRow is a row of the project_status_changes table
+type projectStatusChangeRow struct {
+	ID             string         `db:"status_change_id"`
+	OrganizationID string         `db:"org_id"`
+	ProjectID      string         `db:"project_id"`
+	FromStatus     sql.NullString `db:"from_status"`
+	ToStatus       string         `db:"to_status"`
+	ChangedBy      string         `db:"changed_by"`
+	ChangedAt      time.Time      `db:"changed_at"`
+}
+
+func (r *projectStatusChangeRow) toProjectStatusChange() *ProjectStatusChange {
+	return &ProjectStatusChange{
+		ID:             uuid.MustParse(r.ID),
+		OrganizationID: uuid.MustParse(r.OrganizationID),
+		ProjectID:      uuid.MustParse(r.ProjectID),
+		FromStatus:     r.FromStatus.String,
+		ToStatus:       r.ToStatus,
+		ChangedBy:      r.ChangedBy,
+		ChangedAt:      r.ChangedAt,
+	}
+}
+
+// projectAliasRow is a row of the project_aliases table
+type projectAliasRow struct {
+	OrganizationID string `db:"org_id"`
+	ProjectID      string `db:"project_id"`
+	Alias          string `db:"alias"`
+}
+
+func (r *projectAliasRow) toProjectAlias() *ProjectAlias {
+	return &ProjectAlias{
+		OrganizationID: uuid.MustParse(r.OrganizationID),
+		ProjectID:      uuid.MustParse(r.ProjectID),
+		Alias:          r.Alias,
+	}
+}
@@ -3,6 +3,7 @@ package tracking
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/baralga/shared"
 	"github.com/baralga/shared/paged"
@@ -52,6 +53,22 @@ func TestProjectRepository(t *testing.T) {
 		is.True(projectsPage != nil)
 	})
 
+	t.Run("FindProjectsSortedByLastUsed", func(t *testing.T) {
+		projectsPage, err := projectRepository.FindProjects(
+			context.Background(),
+			shared.OrganizationIDSample,
+			&paged.PageParams{
+				Page:      0,
+				Size:      50,
+				SortBy:    "last-used",
+				SortOrder: "desc",
+			},
+		)
+
+		is.NoErr(err)
+		is.Equal(len(projectsPage.Projects), 1)
+	})
+
 	t.Run("FindProjectsByIDs", func(t *testing.T) {
 		projects, err := projectRepository.FindProjectsByIDs(
 			context.Background(),
@@ -69,6 +86,7 @@ func TestProjectRepository(t *testing.T) {
 			Title:          "My Title",
 			OrganizationID: shared.OrganizationIDSample,
 			Description:    "My Description",
+			Status:         ProjectStatusActive,
 		}
 
 		err = repositoryTxer.InTx(
@@ -102,14 +120,14 @@ func TestProjectRepository(t *testing.T) {
 		is.Equal("My updated Description", projectUpdate.Description)
 	})
 
-	t.Run("ArchiveProject", func(t *testing.T) {
+	t.Run("CompleteProject", func(t *testing.T) {
 		// Arrange
 		project := &Project{
 			ID:             uuid.New(),
 			Title:          "My Title",
 			OrganizationID: shared.OrganizationIDSample,
 			Description:    "My Description",
-			Active:         true,
+			Status:         ProjectStatusActive,
 		}
 
 		err = repositoryTxer.InTx(
@@ -125,19 +143,131 @@ func TestProjectRepository(t *testing.T) {
 		is.NoErr(err)
 
 		// Act
+		err = project.ChangeStatus(ProjectStatusDone, time.Now())
+		is.NoErr(err)
+
 		err = repositoryTxer.InTx(
 			context.Background(),
 			func(ctx context.Context) error {
-				err := projectRepository.ArchiveProjectByID(ctx, shared.OrganizationIDSample, project.ID)
+				_, err := projectRepository.UpdateProject(ctx, shared.OrganizationIDSample, project)
 				if err != nil {
 					return err
 				}
-				return nil
+				return projectRepository.InsertProjectStatusChange(ctx, &ProjectStatusChange{
+					ID:             uuid.New(),
+					OrganizationID: shared.OrganizationIDSample,
+					ProjectID:      project.ID,
+					FromStatus:     ProjectStatusActive,
+					ToStatus:       ProjectStatusDone,
+					ChangedBy:      "admin",
+					ChangedAt:      time.Now(),
+				})
 			},
 		)
 
 		// Assert
 		is.NoErr(err)
+
+		projectCompleted, err := projectRepository.FindProjectByID(context.Background(), shared.OrganizationIDSample, project.ID)
+		is.NoErr(err)
+		is.Equal(projectCompleted.Status, ProjectStatusDone)
+		is.True(projectCompleted.CompletedAt != nil)
+
+		statusChanges, err := projectRepository.FindProjectStatusChanges(context.Background(), shared.OrganizationIDSample, project.ID)
+		is.NoErr(err)
+		is.Equal(len(statusChanges), 1)
+		is.Equal(statusChanges[0].FromStatus, ProjectStatusActive)
+
+		projectsPage, err := projectRepository.FindProjectsWithStatus(
+			context.Background(),
+			shared.OrganizationIDSample,
+			[]string{ProjectStatusDone},
+			&paged.PageParams{
+				Page: 0,
+				Size: 50,
+			},
+		)
+		is.NoErr(err)
+		is.Equal(projectsPage.Page.TotalElements, 1)
+	})
+
+	t.Run("CloneProject", func(t *testing.T) {
+		project := &Project{
+			ID:             uuid.New(),
+			Title:          "My Project (Copy)",
+			OrganizationID: shared.OrganizationIDSample,
+			Status:         ProjectStatusProposed,
+			BudgetMinutes:  600,
+		}
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := projectRepository.CloneProject(ctx, shared.OrganizationIDSample, shared.ProjectIDSample, project)
+				return err
+			},
+		)
+		is.NoErr(err)
+
+		projectCloned, err := projectRepository.FindProjectByID(context.Background(), shared.OrganizationIDSample, project.ID)
+		is.NoErr(err)
+		is.Equal(projectCloned.Title, "My Project (Copy)")
+		is.Equal(projectCloned.BudgetMinutes, 600)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := projectRepository.CloneProject(ctx, shared.OrganizationIDSample, uuid.New(), &Project{ID: uuid.New(), Title: "Not cloned"})
+				return err
+			},
+		)
+		is.True(errors.Is(err, ErrProjectNotFound))
+	})
+
+	t.Run("ProjectAliases", func(t *testing.T) {
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return projectRepository.InsertProjectAlias(ctx, &ProjectAlias{
+					OrganizationID: shared.OrganizationIDSample,
+					ProjectID:      shared.ProjectIDSample,
+					Alias:          "web",
+				})
+			},
+		)
+		is.NoErr(err)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return projectRepository.InsertProjectAlias(ctx, &ProjectAlias{
+					OrganizationID: shared.OrganizationIDSample,
+					ProjectID:      shared.ProjectIDSample,
+					Alias:          "WEB",
+				})
+			},
+		)
+		is.True(errors.Is(err, ErrProjectAliasTaken))
+
+		project, err := projectRepository.FindProjectByAlias(context.Background(), shared.OrganizationIDSample, "Web")
+		is.NoErr(err)
+		is.Equal(project.ID, shared.ProjectIDSample)
+
+		projectAliases, err := projectRepository.FindProjectAliases(context.Background(), shared.OrganizationIDSample, shared.ProjectIDSample)
+		is.NoErr(err)
+		is.Equal(len(projectAliases), 1)
+		is.Equal(projectAliases[0].Alias, "WEB")
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return projectRepository.DeleteProjectAlias(ctx, shared.OrganizationIDSample, shared.ProjectIDSample, "web")
+			},
+		)
+		is.NoErr(err)
+
+		_, err = projectRepository.FindProjectByAlias(context.Background(), shared.OrganizationIDSample, "WEB")
+		is.True(errors.Is(err, ErrProjectNotFound))
 	})
 }
 
@@ -216,3 +346,12 @@ func TestProjectRepositoryDeleteProject(t *testing.T) {
 		is.NoErr(err)
 	})
 }
+
+func TestProjectsOrderBy(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(projectsOrderBy(&paged.PageParams{}), "title ASC, project_id ASC")
+	is.Equal(projectsOrderBy(&paged.PageParams{SortBy: "title", SortOrder: "desc"}), "title DESC, project_id ASC")
+	is.Equal(projectsOrderBy(&paged.PageParams{SortBy: "created_at"}), "created_at ASC, title ASC, project_id ASC")
+	is.Equal(projectsOrderBy(&paged.PageParams{SortBy: "title; DROP TABLE projects"}), "title ASC, project_id ASC")
+}
@@ -0,0 +1,139 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	EventTypeProjectCreated  = "project.created"
+	EventTypeProjectArchived = "project.archived"
+	EventTypeProjectDeleted  = "project.deleted"
+)
+
+// projectEventData is the data of the events of a project
+type projectEventData struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	Status        string `json:"status"`
+	Billable      bool   `json:"billable"`
+	BudgetMinutes int    `json:"budgetMinutes"`
+	StartedAt     string `json:"startedAt,omitempty"`
+	CompletedAt   string `json:"completedAt,omitempty"`
+}
+
+// EventPublishingProjectRepository publishes the creation, archiving and deletion of a project
+// as event via the outbox, within the transaction of the change
+type EventPublishingProjectRepository struct {
+	ProjectRepository
+	outbox shared.Outbox
+}
+
+var _ ProjectRepository = (*EventPublishingProjectRepository)(nil)
+
+// NewEventPublishingProjectRepository creates a new project repository which publishes changes as events
+func NewEventPublishingProjectRepository(projectRepository ProjectRepository, outbox shared.Outbox) *EventPublishingProjectRepository {
+	return &EventPublishingProjectRepository{
+		ProjectRepository: projectRepository,
+		outbox:            outbox,
+	}
+}
+
+func (r *EventPublishingProjectRepository) InsertProject(ctx context.Context, project *Project) (*Project, error) {
+	inserted, err := r.ProjectRepository.InsertProject(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.publishEvent(ctx, EventTypeProjectCreated, inserted.OrganizationID, inserted)
+	if err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+func (r *EventPublishingProjectRepository) CloneProject(ctx context.Context, organizationID, sourceProjectID uuid.UUID, project *Project) (*Project, error) {
+	cloned, err := r.ProjectRepository.CloneProject(ctx, organizationID, sourceProjectID, project)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.publishEvent(ctx, EventTypeProjectCreated, organizationID, cloned)
+	if err != nil {
+		return nil, err
+	}
+	return cloned, nil
+}
+
+// UpdateProject publishes the archiving of the project when its status becomes done, other updates are not published
+func (r *EventPublishingProjectRepository) UpdateProject(ctx context.Context, organizationID uuid.UUID, project *Project) (*Project, error) {
+	before, err := r.ProjectRepository.FindProjectByID(ctx, organizationID, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.ProjectRepository.UpdateProject(ctx, organizationID, project)
+	if err != nil {
+		return nil, err
+	}
+
+	if before.Status == ProjectStatusDone || updated.Status != ProjectStatusDone {
+		return updated, nil
+	}
+
+	err = r.publishEvent(ctx, EventTypeProjectArchived, organizationID, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *EventPublishingProjectRepository) DeleteProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) error {
+	deleted, err := r.ProjectRepository.FindProjectByID(ctx, organizationID, projectID)
+	if err != nil {
+		return err
+	}
+
+	err = r.ProjectRepository.DeleteProjectByID(ctx, organizationID, projectID)
+	if err != nil {
+		return err
+	}
+
+	return r.publishEvent(ctx, EventTypeProjectDeleted, organizationID, deleted)
+}
+
+func (r *EventPublishingProjectRepository) publishEvent(ctx context.Context, eventType string, organizationID uuid.UUID, project *Project) error {
+	data := &projectEventData{
+		ID:            project.ID.String(),
+		Title:         project.Title,
+		Description:   project.Description,
+		Status:        project.Status,
+		Billable:      project.Billable,
+		BudgetMinutes: project.BudgetMinutes,
+	}
+	if project.StartedAt != nil {
+		data.StartedAt = project.StartedAt.UTC().Format(time.RFC3339)
+	}
+	if project.CompletedAt != nil {
+		data.CompletedAt = project.CompletedAt.UTC().Format(time.RFC3339)
+	}
+
+	event, err := shared.NewCloudEvent(
+		organizationID,
+		eventType,
+		fmt.Sprintf("projects/%s", project.ID),
+		data,
+		auditActorOf(ctx, organizationID),
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.outbox.PublishEvent(ctx, event)
+}
@@ -0,0 +1,52 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestEventPublishingProjectRepository(t *testing.T) {
+	is := is.New(t)
+
+	outbox := shared.NewInMemOutbox(shared.NewInMemMailResource())
+	projectRepository := NewEventPublishingProjectRepository(NewInMemProjectRepository(), outbox)
+
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin"}
+	ctx := context.WithValue(context.Background(), shared.ContextKeyPrincipal, principal)
+
+	project := &Project{
+		ID:             uuid.New(),
+		Title:          "Event Project",
+		Status:         ProjectStatusActive,
+		OrganizationID: shared.OrganizationIDSample,
+	}
+	_, err := projectRepository.InsertProject(ctx, project)
+	is.NoErr(err)
+
+	renamed := *project
+	renamed.Title = "Renamed Project"
+	_, err = projectRepository.UpdateProject(ctx, shared.OrganizationIDSample, &renamed)
+	is.NoErr(err)
+
+	archived := renamed
+	archived.Status = ProjectStatusDone
+	_, err = projectRepository.UpdateProject(ctx, shared.OrganizationIDSample, &archived)
+	is.NoErr(err)
+
+	// already done, so not archived again
+	_, err = projectRepository.UpdateProject(ctx, shared.OrganizationIDSample, &archived)
+	is.NoErr(err)
+
+	err = projectRepository.DeleteProjectByID(ctx, shared.OrganizationIDSample, project.ID)
+	is.NoErr(err)
+
+	is.Equal(len(outbox.Events), 3)
+	is.Equal(outbox.Events[0].Type, EventTypeProjectCreated)
+	is.Equal(outbox.Events[1].Type, EventTypeProjectArchived)
+	is.Equal(outbox.Events[1].Subject, "projects/"+project.ID.String())
+	is.Equal(outbox.Events[2].Type, EventTypeProjectDeleted)
+}
@@ -2,6 +2,7 @@ package tracking
 
 import (
 	"context"
+	"sort"
 
 	"github.com/baralga/shared"
 	"github.com/baralga/shared/paged"
@@ -9,7 +10,9 @@ import (
 )
 
 type InMemProjectRepository struct {
-	projects []*Project
+	projects      []*Project
+	statusChanges []*ProjectStatusChange
+	aliases       []*ProjectAlias
 }
 
 var _ ProjectRepository = (*InMemProjectRepository)(nil)
@@ -20,6 +23,7 @@ func NewInMemProjectRepository() *InMemProjectRepository {
 			{
 				ID:             shared.ProjectIDSample,
 				Title:          "My Project",
+				Status:         ProjectStatusActive,
 				OrganizationID: shared.OrganizationIDSample,
 			},
 		},
@@ -31,10 +35,52 @@ func (r *InMemProjectRepository) InsertProject(ctx context.Context, project *Pro
 	return project, nil
 }
 
+func (r *InMemProjectRepository) CloneProject(ctx context.Context, organizationID, sourceProjectID uuid.UUID, project *Project) (*Project, error) {
+	_, err := r.FindProjectByID(ctx, organizationID, sourceProjectID)
+	if err != nil {
+		return nil, err
+	}
+	return r.InsertProject(ctx, project)
+}
+
 func (r *InMemProjectRepository) FindProjects(ctx context.Context, organizationID uuid.UUID, pageParams *paged.PageParams) (*ProjectsPaged, error) {
+	return r.FindProjectsWithStatus(ctx, organizationID, OpenProjectStatuses, pageParams)
+}
+
+func (r *InMemProjectRepository) FindProjectsWithStatus(ctx context.Context, organizationID uuid.UUID, statuses []string, pageParams *paged.PageParams) (*ProjectsPaged, error) {
+	var projects []*Project
+	for _, p := range r.projects {
+		if p.OrganizationID != organizationID {
+			continue
+		}
+		for _, status := range statuses {
+			if p.Status == status {
+				projects = append(projects, p)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		if projects[i].Title != projects[j].Title {
+			return projects[i].Title < projects[j].Title
+		}
+		return projects[i].ID.String() < projects[j].ID.String()
+	})
+
+	total := len(projects)
+	offset := pageParams.Offset()
+	if offset > total {
+		offset = total
+	}
+	end := offset + pageParams.Size
+	if pageParams.Size <= 0 || end > total {
+		end = total
+	}
+
 	projectsPaged := &ProjectsPaged{
-		Projects: r.projects,
-		Page:     pageParams.PageOfTotal(len(r.projects)),
+		Projects: projects[offset:end],
+		Page:     pageParams.PageOfTotal(total),
 	}
 	return projectsPaged, nil
 }
@@ -44,7 +90,7 @@ func (r *InMemProjectRepository) FindProjectsByIDs(ctx context.Context, organiza
 
 	for _, projectID := range projectIDs {
 		for _, p := range r.projects {
-			if p.ID == projectID {
+			if p.ID == projectID && p.OrganizationID == organizationID {
 				projects = append(projects, p)
 				break
 			}
@@ -56,8 +102,10 @@ func (r *InMemProjectRepository) FindProjectsByIDs(ctx context.Context, organiza
 
 func (r *InMemProjectRepository) UpdateProject(ctx context.Context, organizationID uuid.UUID, project *Project) (*Project, error) {
 	for i, p := range r.projects {
-		if p.ID == project.ID {
-			r.projects[i] = project
+		if p.ID == project.ID && p.OrganizationID == organizationID {
+			updated := *project
+			updated.OrganizationID = p.OrganizationID
+			r.projects[i] = &updated
 			return project, nil
 		}
 	}
@@ -66,7 +114,7 @@ func (r *InMemProjectRepository) UpdateProject(ctx context.Context, organization
 
 func (r *InMemProjectRepository) DeleteProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) error {
 	for i, a := range r.projects {
-		if a.ID == projectID {
+		if a.ID == projectID && a.OrganizationID == organizationID {
 			r.projects = append(r.projects[:i], r.projects[i+1:]...)
 			return nil
 		}
@@ -74,21 +122,70 @@ func (r *InMemProjectRepository) DeleteProjectByID(ctx context.Context, organiza
 	return ErrProjectNotFound
 }
 
-func (r *InMemProjectRepository) ArchiveProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) error {
-	for i, a := range r.projects {
-		if a.ID == projectID {
-			r.projects[i].Active = false
-			return nil
+func (r *InMemProjectRepository) InsertProjectStatusChange(ctx context.Context, statusChange *ProjectStatusChange) error {
+	r.statusChanges = append(r.statusChanges, statusChange)
+	return nil
+}
+
+func (r *InMemProjectRepository) FindProjectStatusChanges(ctx context.Context, organizationID, projectID uuid.UUID) ([]*ProjectStatusChange, error) {
+	var statusChanges []*ProjectStatusChange
+	for _, c := range r.statusChanges {
+		if c.ProjectID == projectID && c.OrganizationID == organizationID {
+			statusChanges = append(statusChanges, c)
 		}
 	}
-	return ErrProjectNotFound
+	return statusChanges, nil
 }
 
 func (r *InMemProjectRepository) FindProjectByID(ctx context.Context, organizationID, projectID uuid.UUID) (*Project, error) {
 	for _, a := range r.projects {
-		if a.ID == projectID {
+		if a.ID == projectID && a.OrganizationID == organizationID {
 			return a, nil
 		}
 	}
 	return nil, ErrProjectNotFound
 }
+
+func (r *InMemProjectRepository) FindProjectByAlias(ctx context.Context, organizationID uuid.UUID, alias string) (*Project, error) {
+	for _, a := range r.aliases {
+		if a.OrganizationID == organizationID && a.Alias == NormalizeProjectAlias(alias) {
+			return r.FindProjectByID(ctx, organizationID, a.ProjectID)
+		}
+	}
+	return nil, ErrProjectNotFound
+}
+
+func (r *InMemProjectRepository) FindProjectAliases(ctx context.Context, organizationID, projectID uuid.UUID) ([]*ProjectAlias, error) {
+	var projectAliases []*ProjectAlias
+	for _, a := range r.aliases {
+		if a.OrganizationID == organizationID && a.ProjectID == projectID {
+			projectAliases = append(projectAliases, a)
+		}
+	}
+	return projectAliases, nil
+}
+
+func (r *InMemProjectRepository) InsertProjectAlias(ctx context.Context, projectAlias *ProjectAlias) error {
+	for _, a := range r.aliases {
+		if a.OrganizationID == projectAlias.OrganizationID && a.Alias == NormalizeProjectAlias(projectAlias.Alias) {
+			return ErrProjectAliasTaken
+		}
+	}
+
+	r.aliases = append(r.aliases, &ProjectAlias{
+		OrganizationID: projectAlias.OrganizationID,
+		ProjectID:      projectAlias.ProjectID,
+		Alias:          NormalizeProjectAlias(projectAlias.Alias),
+	})
+	return nil
+}
+
+func (r *InMemProjectRepository) DeleteProjectAlias(ctx context.Context, organizationID, projectID uuid.UUID, alias string) error {
+	for i, a := range r.aliases {
+		if a.OrganizationID == organizationID && a.ProjectID == projectID && a.Alias == NormalizeProjectAlias(alias) {
+			r.aliases = append(r.aliases[:i], r.aliases[i+1:]...)
+			return nil
+		}
+	}
+	return ErrProjectAliasNotFound
+}
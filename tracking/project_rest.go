@@ -3,13 +3,16 @@ package tracking
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"strings"
 
 	"github.com/baralga/shared"
 	"github.com/baralga/shared/hal"
 	"github.com/baralga/shared/paged"
+	time_utils "github.com/baralga/tracking/time"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"schneider.vip/problem"
@@ -19,10 +22,42 @@ type projectModel struct {
 	ID          string     `json:"id"`
 	Title       string     `json:"title" validate:"required,min=3,max=100"`
 	Description string     `json:"description" validate:"max=500"`
-	Active      bool       `json:"active"`
+	Status      string     `json:"status" validate:"omitempty,oneof=proposed active on-hold done"`
+	Active      *bool      `json:"active,omitempty"`
+	StartedAt   string     `json:"startedAt,omitempty"`
+	CompletedAt string     `json:"completedAt,omitempty"`
+	Billable    bool       `json:"billable"`
+	BudgetHours float64    `json:"budgetHours" validate:"min=0"`
 	Links       *hal.Links `json:"_links"`
 }
 
+type cloneProjectModel struct {
+	Title  string `json:"title" validate:"omitempty,min=3,max=100"`
+	Status string `json:"status" validate:"omitempty,oneof=proposed active on-hold done"`
+}
+
+type projectAliasModel struct {
+	Alias string     `json:"alias" validate:"required,alphanum,min=2,max=10"`
+	Links *hal.Links `json:"_links"`
+}
+
+type projectAliasesModel struct {
+	Aliases []*projectAliasModel `json:"aliases"`
+	Links   *hal.Links           `json:"_links"`
+}
+
+type projectStatusChangeModel struct {
+	FromStatus string `json:"fromStatus,omitempty"`
+	ToStatus   string `json:"toStatus"`
+	ChangedBy  string `json:"changedBy"`
+	ChangedAt  string `json:"changedAt"`
+}
+
+type projectStatusChangesModel struct {
+	StatusChanges []*projectStatusChangeModel `json:"statusChanges"`
+	Links         *hal.Links                  `json:"_links"`
+}
+
 type EmbeddedProjects struct {
 	ProjectModels []*projectModel `json:"projects"`
 }
@@ -53,12 +88,17 @@ func (a *ProjectRestHandlers) RegisterProtected(r chi.Router) {
 	r.Get("/projects/{project-id}", a.HandleGetProject())
 	r.Delete("/projects/{project-id}", a.HandleDeleteProject())
 	r.Patch("/projects/{project-id}", a.HandleUpdateProject())
+	r.Get("/projects/{project-id}/status-changes", a.HandleGetProjectStatusChanges())
+	r.Post("/projects/{project-id}/clone", a.HandleCloneProject())
+	r.Get("/projects/{project-id}/aliases", a.HandleGetProjectAliases())
+	r.Post("/projects/{project-id}/aliases", a.HandleCreateProjectAlias())
+	r.Delete("/projects/{project-id}/aliases/{alias}", a.HandleDeleteProjectAlias())
 }
 
 func (a *ProjectRestHandlers) RegisterOpen(r chi.Router) {
 }
 
-// HandleGetProjects reads projects
+// HandleGetProjects reads projects, the open projects unless filtered by status (e.g. status=on-hold,done)
 func (a *ProjectRestHandlers) HandleGetProjects() http.HandlerFunc {
 	isProduction := a.config.IsProduction()
 	projectRepository := a.projectRepository
@@ -66,7 +106,13 @@ func (a *ProjectRestHandlers) HandleGetProjects() http.HandlerFunc {
 		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
 		pageParams := paged.PageParamsOf(r)
 
-		projectsPaged, err := projectRepository.FindProjects(r.Context(), principal.OrganizationID, pageParams)
+		statuses, err := projectStatusesOf(r.URL.Query().Get("status"))
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		projectsPaged, err := projectRepository.FindProjectsWithStatus(r.Context(), principal.OrganizationID, statuses, pageParams)
 		if err != nil {
 			shared.RenderProblemJSON(w, isProduction, err)
 			return
@@ -97,11 +143,11 @@ func (a *ProjectRestHandlers) HandleGetProjects() http.HandlerFunc {
 			)
 		}
 
-		shared.RenderJSON(w, projectsModel)
+		shared.RenderSparseJSON(w, projectsModel, "projects", shared.FieldsOf(r))
 	}
 }
 
-// HandleGetProject reads a project
+// HandleGetProject reads a project by its id or one of its aliases
 func (a *ProjectRestHandlers) HandleGetProject() http.HandlerFunc {
 	isProduction := a.config.IsProduction()
 	projectRepository := a.projectRepository
@@ -109,17 +155,17 @@ func (a *ProjectRestHandlers) HandleGetProject() http.HandlerFunc {
 		projectIDParam := chi.URLParam(r, "project-id")
 		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
 
-		projectID, err := uuid.Parse(projectIDParam)
+		projectID, err := projectIDOf(r.Context(), projectRepository, principal.OrganizationID, projectIDParam)
+		if errors.Is(err, ErrProjectNotFound) {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
 		if err != nil {
 			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
 			return
 		}
 
 		project, err := projectRepository.FindProjectByID(r.Context(), principal.OrganizationID, projectID)
-		if errors.Is(err, ErrProjectNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
 		if err != nil {
 			shared.RenderProblemJSON(w, isProduction, err)
 			return
@@ -134,7 +180,7 @@ func (a *ProjectRestHandlers) HandleGetProject() http.HandlerFunc {
 // HandleCreateProject creates a project
 func (a *ProjectRestHandlers) HandleCreateProject() http.HandlerFunc {
 	isProduction := a.config.IsProduction()
-	validator := validator.New()
+	validator := shared.NewValidator()
 	projectService := a.projectService
 	return func(w http.ResponseWriter, r *http.Request) {
 		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
@@ -142,24 +188,24 @@ func (a *ProjectRestHandlers) HandleCreateProject() http.HandlerFunc {
 		var projectModel projectModel
 		err := json.NewDecoder(r.Body).Decode(&projectModel)
 		if err != nil {
-			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
 			return
 		}
 
 		if !principal.HasRole("ROLE_ADMIN") {
-			w.WriteHeader(http.StatusForbidden)
+			shared.RenderProblemJSON(w, isProduction, shared.ErrForbidden)
 			return
 		}
 
 		err = validator.Struct(projectModel)
 		if err != nil {
-			http.Error(w, problem.New(problem.Title("project not valid")).JSONString(), http.StatusBadRequest)
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
 			return
 		}
 
 		projectToCreate, err := mapToProject(&projectModel)
 		if err != nil {
-			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
 			return
 		}
 
@@ -176,11 +222,58 @@ func (a *ProjectRestHandlers) HandleCreateProject() http.HandlerFunc {
 	}
 }
 
-// HandleUpdateProject updates a project
+// HandleCloneProject creates a new project with the structure of a project, optionally with another title and status
+func (a *ProjectRestHandlers) HandleCloneProject() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	projectService := a.projectService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
+			return
+		}
+
+		var cloneProjectModel cloneProjectModel
+		err = json.NewDecoder(r.Body).Decode(&cloneProjectModel)
+		if err != nil && !errors.Is(err, io.EOF) {
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
+			return
+		}
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			shared.RenderProblemJSON(w, isProduction, shared.ErrForbidden)
+			return
+		}
+
+		err = validator.Struct(cloneProjectModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
+			return
+		}
+
+		project, err := projectService.CloneProject(r.Context(), principal, projectID, cloneProjectModel.Title, cloneProjectModel.Status)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		projectModelCreated := mapToProjectModel(principal, project)
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, projectModelCreated)
+	}
+}
+
+// HandleUpdateProject updates a project, either in full or with a JSON Merge Patch
 func (a *ProjectRestHandlers) HandleUpdateProject() http.HandlerFunc {
 	isProduction := a.config.IsProduction()
-	validator := validator.New()
+	validator := shared.NewValidator()
 	projectService := a.projectService
+	projectRepository := a.projectRepository
 	return func(w http.ResponseWriter, r *http.Request) {
 		projectIDParam := chi.URLParam(r, "project-id")
 		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
@@ -191,37 +284,57 @@ func (a *ProjectRestHandlers) HandleUpdateProject() http.HandlerFunc {
 			return
 		}
 
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
+			return
+		}
+
+		if shared.IsMergePatch(r) {
+			currentProject, err := projectRepository.FindProjectByID(r.Context(), principal.OrganizationID, projectID)
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+
+			// the active flag is derived from the status, it only changes the status if given in the patch
+			currentProjectModel := mapToProjectModel(principal, currentProject)
+			currentProjectModel.Active = nil
+
+			body, err = shared.MergePatchModel(currentProjectModel, body)
+			if err != nil {
+				shared.RenderValidationProblemJSON(w, "project not valid", err)
+				return
+			}
+		}
+
 		var projectModel projectModel
-		err = json.NewDecoder(r.Body).Decode(&projectModel)
+		err = json.Unmarshal(body, &projectModel)
 		if err != nil {
-			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
 			return
 		}
 
 		if !principal.HasRole("ROLE_ADMIN") {
-			w.WriteHeader(http.StatusForbidden)
+			shared.RenderProblemJSON(w, isProduction, shared.ErrForbidden)
 			return
 		}
 
 		err = validator.Struct(projectModel)
 		if err != nil {
-			http.Error(w, problem.New(problem.Title("project not valid")).JSONString(), http.StatusBadRequest)
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
 			return
 		}
 
 		project, err := mapToProject(&projectModel)
 		if err != nil {
-			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
 			return
 		}
 
 		project.ID = projectID
 
-		projectUpdate, err := projectService.UpdateProject(r.Context(), principal.OrganizationID, project)
-		if errors.Is(err, ErrProjectNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
+		projectUpdate, err := projectService.UpdateProject(r.Context(), principal, project)
 		if err != nil {
 			shared.RenderProblemJSON(w, isProduction, err)
 			return
@@ -247,49 +360,245 @@ func (a *ProjectRestHandlers) HandleDeleteProject() http.HandlerFunc {
 		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
 
 		if !principal.HasRole("ROLE_ADMIN") {
-			w.WriteHeader(http.StatusForbidden)
+			shared.RenderProblemJSON(w, isProduction, shared.ErrForbidden)
 			return
 		}
 
 		err = projectService.DeleteProjectByID(r.Context(), principal, projectID)
-		if errors.Is(err, ErrProjectNotFound) {
-			w.WriteHeader(http.StatusNotFound)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("HX-Trigger", "{ \"baralga__activities-changed\": true, \"baralga__projects-changed\": true } ")
+	}
+}
+
+// HandleGetProjectStatusChanges reads the audit trail of the project lifecycle
+func (a *ProjectRestHandlers) HandleGetProjectStatusChanges() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectService := a.projectService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
 			return
 		}
+
+		statusChanges, err := projectService.ReadProjectStatusChanges(r.Context(), principal, projectID)
 		if err != nil {
 			shared.RenderProblemJSON(w, isProduction, err)
 			return
 		}
 
-		w.Header().Set("HX-Trigger", "{ \"baralga__activities-changed\": true, \"baralga__projects-changed\": true } ")
+		statusChangeModels := make([]*projectStatusChangeModel, len(statusChanges))
+		for i, statusChange := range statusChanges {
+			statusChangeModels[i] = &projectStatusChangeModel{
+				FromStatus: statusChange.FromStatus,
+				ToStatus:   statusChange.ToStatus,
+				ChangedBy:  statusChange.ChangedBy,
+				ChangedAt:  time_utils.FormatDateTime(statusChange.ChangedAt),
+			}
+		}
+
+		shared.RenderJSON(w, &projectStatusChangesModel{
+			StatusChanges: statusChangeModels,
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+				hal.NewLink("project", fmt.Sprintf("/api/projects/%s", projectID)),
+			),
+		})
+	}
+}
+
+// HandleGetProjectAliases reads the short codes of a project
+func (a *ProjectRestHandlers) HandleGetProjectAliases() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectService := a.projectService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
+			return
+		}
+
+		projectAliases, err := projectService.ReadProjectAliases(r.Context(), principal, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		aliasModels := make([]*projectAliasModel, len(projectAliases))
+		for i, projectAlias := range projectAliases {
+			aliasModels[i] = mapToProjectAliasModel(principal, projectAlias)
+		}
+
+		aliasesModel := &projectAliasesModel{
+			Aliases: aliasModels,
+		}
+		if principal.HasRole("ROLE_ADMIN") {
+			aliasesModel.Links = hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+				hal.NewLink("create", fmt.Sprintf("/api/projects/%s/aliases", projectID)),
+				hal.NewLink("project", fmt.Sprintf("/api/projects/%s", projectID)),
+			)
+		} else {
+			aliasesModel.Links = hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+				hal.NewLink("project", fmt.Sprintf("/api/projects/%s", projectID)),
+			)
+		}
+
+		shared.RenderJSON(w, aliasesModel)
 	}
 }
 
+// HandleCreateProjectAlias adds a short code to a project
+func (a *ProjectRestHandlers) HandleCreateProjectAlias() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	projectService := a.projectService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
+			return
+		}
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			shared.RenderProblemJSON(w, isProduction, shared.ErrForbidden)
+			return
+		}
+
+		var aliasModel projectAliasModel
+		err = json.NewDecoder(r.Body).Decode(&aliasModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project alias not valid", err)
+			return
+		}
+
+		err = validator.Struct(aliasModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project alias not valid", err)
+			return
+		}
+
+		projectAlias, err := projectService.AddProjectAlias(r.Context(), principal, projectID, aliasModel.Alias)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToProjectAliasModel(principal, projectAlias))
+	}
+}
+
+// HandleDeleteProjectAlias removes a short code of a project
+func (a *ProjectRestHandlers) HandleDeleteProjectAlias() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectService := a.projectService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
+			return
+		}
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			shared.RenderProblemJSON(w, isProduction, shared.ErrForbidden)
+			return
+		}
+
+		err = projectService.DeleteProjectAlias(r.Context(), principal, projectID, chi.URLParam(r, "alias"))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+// projectStatusesOf parses the comma separated statuses of the query param, by default the open statuses
+func projectStatusesOf(statusParam string) ([]string, error) {
+	if statusParam == "" {
+		return OpenProjectStatuses, nil
+	}
+
+	statuses := strings.Split(statusParam, ",")
+	for _, status := range statuses {
+		if !IsValidProjectStatus(status) {
+			return nil, shared.NewInvalidParam("status", "oneof", "must be one of proposed, active, on-hold or done")
+		}
+	}
+	return statuses, nil
+}
+
 func mapToProject(projectModel *projectModel) (*Project, error) {
 	var projectID uuid.UUID
 
 	if projectModel.ID != "" {
 		pID, err := uuid.Parse(projectModel.ID)
 		if err != nil {
-			return nil, err
+			return nil, shared.NewInvalidParam("id", "uuid", "must be a valid id")
 		}
 		projectID = pID
 	}
 
 	return &Project{
-		ID:          projectID,
-		Title:       projectModel.Title,
-		Description: projectModel.Description,
-		Active:      projectModel.Active,
+		ID:            projectID,
+		Title:         projectModel.Title,
+		Description:   projectModel.Description,
+		Status:        projectStatusOfModel(projectModel),
+		Billable:      projectModel.Billable,
+		BudgetMinutes: int(math.Round(projectModel.BudgetHours * 60)),
 	}, nil
 }
 
+// projectStatusOfModel is the status of the project model, clients of the first version of the api
+// still set the active flag of projects, which activates a project or finishes an active project
+func projectStatusOfModel(projectModel *projectModel) string {
+	if projectModel.Active == nil {
+		return projectModel.Status
+	}
+
+	switch {
+	case *projectModel.Active && projectModel.Status != ProjectStatusActive:
+		return ProjectStatusActive
+	case !*projectModel.Active && (projectModel.Status == ProjectStatusActive || projectModel.Status == ""):
+		return ProjectStatusDone
+	default:
+		return projectModel.Status
+	}
+}
+
 func mapToProjectModel(principal *shared.Principal, project *Project) *projectModel {
+	active := project.Status == ProjectStatusActive
 	projectModel := &projectModel{
 		ID:          project.ID.String(),
 		Title:       project.Title,
 		Description: project.Description,
-		Active:      project.Active,
+		Status:      project.Status,
+		Active:      &active,
+		Billable:    project.Billable,
+		BudgetHours: float64(project.BudgetMinutes) / 60.0,
+	}
+	if project.StartedAt != nil {
+		projectModel.StartedAt = time_utils.FormatDateTime(*project.StartedAt)
+	}
+	if project.CompletedAt != nil {
+		projectModel.CompletedAt = time_utils.FormatDateTime(*project.CompletedAt)
 	}
 	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/projects/%s", projectModel.ID))
 	if principal.HasRole("ROLE_ADMIN") {
@@ -306,3 +615,25 @@ func mapToProjectModel(principal *shared.Principal, project *Project) *projectMo
 	}
 	return projectModel
 }
+
+func mapToProjectAliasModel(principal *shared.Principal, projectAlias *ProjectAlias) *projectAliasModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/projects/%s/aliases/%s", projectAlias.ProjectID, projectAlias.Alias))
+	projectLink := hal.NewLink("project", fmt.Sprintf("/api/projects/%s", projectAlias.ProjectID))
+
+	aliasModel := &projectAliasModel{
+		Alias: projectAlias.Alias,
+	}
+	if principal.HasRole("ROLE_ADMIN") {
+		aliasModel.Links = hal.NewLinks(
+			selfLink,
+			projectLink,
+			hal.NewLink("delete", selfLink.Href()),
+		)
+	} else {
+		aliasModel.Links = hal.NewLinks(
+			selfLink,
+			projectLink,
+		)
+	}
+	return aliasModel
+}
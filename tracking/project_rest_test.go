@@ -62,7 +62,7 @@ func TestMapToProject(t *testing.T) {
 		ID:          "00000000-0000-0000-1111-000000000001",
 		Title:       "Title",
 		Description: "Description",
-		Active:      true,
+		Status:      ProjectStatusOnHold,
 	}
 
 	project, err := mapToProject(projectModel)
@@ -71,7 +71,32 @@ func TestMapToProject(t *testing.T) {
 	is.Equal(projectModel.ID, project.ID.String())
 	is.Equal(projectModel.Title, project.Title)
 	is.Equal(projectModel.Description, project.Description)
-	is.Equal(projectModel.Active, project.Active)
+	is.Equal(projectModel.Status, project.Status)
+}
+
+func TestMapToProjectWithActiveFlag(t *testing.T) {
+	is := is.New(t)
+
+	active := true
+	inactive := false
+
+	project, err := mapToProject(&projectModel{Title: "Title", Active: &active})
+	is.NoErr(err)
+	is.Equal(project.Status, ProjectStatusActive)
+
+	project, err = mapToProject(&projectModel{Title: "Title", Active: &inactive})
+	is.NoErr(err)
+	is.Equal(project.Status, ProjectStatusDone)
+
+	project, err = mapToProject(&projectModel{Title: "Title", Status: ProjectStatusOnHold, Active: &inactive})
+	is.NoErr(err)
+	is.Equal(project.Status, ProjectStatusOnHold)
+
+	projectModel := mapToProjectModel(&shared.Principal{}, &Project{ID: uuid.New(), Status: ProjectStatusActive})
+	is.Equal(*projectModel.Active, true)
+
+	projectModel = mapToProjectModel(&shared.Principal{}, &Project{ID: uuid.New(), Status: ProjectStatusOnHold})
+	is.Equal(*projectModel.Active, false)
 }
 
 func TestMapToProjectWithInvalidId(t *testing.T) {
@@ -81,7 +106,7 @@ func TestMapToProjectWithInvalidId(t *testing.T) {
 		ID:          "not-a-uuid",
 		Title:       "Title",
 		Description: "Description",
-		Active:      true,
+		Status:      ProjectStatusActive,
 	}
 
 	_, err := mapToProject(projectModel)
@@ -99,7 +124,9 @@ func TestHandleGetProjects(t *testing.T) {
 	}
 
 	r, _ := http.NewRequest("GET", "/api/projects", nil)
-	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
 
 	a.HandleGetProjects()(httpRec, r)
 	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
@@ -140,7 +167,9 @@ func TestHandleGetProject(t *testing.T) {
 	}
 
 	r, _ := http.NewRequest("GET", fmt.Sprintf("/api/projects/%v", shared.ProjectIDSample), nil)
-	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
 
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
@@ -173,6 +202,7 @@ func TestHandleGetNonExistingProject(t *testing.T) {
 
 	a.HandleGetProject()(httpRec, r)
 	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+	is.True(strings.Contains(httpRec.Body.String(), `"code":"project:not-found"`))
 }
 
 func TestHandleUpdateProject(t *testing.T) {
@@ -183,6 +213,7 @@ func TestHandleUpdateProject(t *testing.T) {
 	c := &ProjectRestHandlers{
 		config: &shared.Config{},
 		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
 			repositoryTxer:    shared.NewInMemRepositoryTxer(),
 			projectRepository: projectRepository,
 		},
@@ -199,7 +230,8 @@ func TestHandleUpdateProject(t *testing.T) {
 
 	r, _ := http.NewRequest("PATCH", fmt.Sprintf("/api/projects/%v", shared.ProjectIDSample), strings.NewReader(body))
 	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
-		Roles: []string{"ROLE_ADMIN"},
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
 	}))
 
 	rctx := chi.NewRouteContext()
@@ -214,6 +246,81 @@ func TestHandleUpdateProject(t *testing.T) {
 	is.NoErr(err)
 }
 
+func TestHandleUpdateProjectWithMergePatch(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	projectRepository := NewInMemProjectRepository()
+	c := &ProjectRestHandlers{
+		config: &shared.Config{},
+		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+			repositoryTxer:    shared.NewInMemRepositoryTxer(),
+			projectRepository: projectRepository,
+		},
+		projectRepository: projectRepository,
+	}
+
+	body := `{"description": "My patched Description"}`
+
+	r, _ := http.NewRequest("PATCH", fmt.Sprintf("/api/projects/%v", shared.ProjectIDSample), strings.NewReader(body))
+	r.Header.Set("Content-Type", shared.MergePatchContentType)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	c.HandleUpdateProject()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	project, err := projectRepository.FindProjectByID(context.Background(), shared.OrganizationIDSample, shared.ProjectIDSample)
+	is.NoErr(err)
+	is.Equal(project.Title, "My Project")
+	is.Equal(project.Description, "My patched Description")
+}
+
+func TestHandleUpdateProjectWithMergePatchOfActiveFlag(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	projectRepository := NewInMemProjectRepository()
+	c := &ProjectRestHandlers{
+		config: &shared.Config{},
+		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+			repositoryTxer:    shared.NewInMemRepositoryTxer(),
+			projectRepository: projectRepository,
+		},
+		projectRepository: projectRepository,
+	}
+
+	body := `{"active": false}`
+
+	r, _ := http.NewRequest("PATCH", fmt.Sprintf("/api/projects/%v", shared.ProjectIDSample), strings.NewReader(body))
+	r.Header.Set("Content-Type", shared.MergePatchContentType)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	c.HandleUpdateProject()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	projectModel := &projectModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(projectModel)
+	is.NoErr(err)
+	is.Equal(projectModel.Status, ProjectStatusDone)
+	is.Equal(*projectModel.Active, false)
+}
+
 func TestHandleUpdateInvalidProject(t *testing.T) {
 	is := is.New(t)
 	httpRec := httptest.NewRecorder()
@@ -280,6 +387,7 @@ func TestHandleUpdateNonExistingProject(t *testing.T) {
 	c := &ProjectRestHandlers{
 		config: &shared.Config{},
 		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
 			repositoryTxer:    shared.NewInMemRepositoryTxer(),
 			projectRepository: projectRepository,
 		},
@@ -331,7 +439,7 @@ func TestHandleUpdateProjectWithInvalidBody(t *testing.T) {
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
 	a.HandleUpdateProject()(httpRec, r)
-	is.Equal(httpRec.Result().StatusCode, http.StatusNotAcceptable)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
 }
 
 func TestHandleUpdateWithIdNotValid(t *testing.T) {
@@ -370,6 +478,7 @@ func TestHandleCreateProject(t *testing.T) {
 	c := &ProjectRestHandlers{
 		config: &shared.Config{},
 		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
 			repositoryTxer:    shared.NewInMemRepositoryTxer(),
 			projectRepository: repo,
 		},
@@ -474,6 +583,7 @@ func TestHandleDeleteProjectAsAdmin(t *testing.T) {
 	c := &ProjectRestHandlers{
 		config: &shared.Config{},
 		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
 			repositoryTxer:    shared.NewInMemRepositoryTxer(),
 			projectRepository: repo,
 		},
@@ -482,8 +592,9 @@ func TestHandleDeleteProjectAsAdmin(t *testing.T) {
 
 	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/api/projects/%v", shared.ProjectIDSample), nil)
 	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
-		Username: "admin",
-		Roles:    []string{"ROLE_ADMIN"},
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
 	}))
 
 	rctx := chi.NewRouteContext()
@@ -504,6 +615,7 @@ func TestHandleDeleteProjectAsUser(t *testing.T) {
 	c := &ProjectRestHandlers{
 		config: &shared.Config{},
 		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
 			repositoryTxer:    shared.NewInMemRepositoryTxer(),
 			projectRepository: repo,
 		},
@@ -521,6 +633,7 @@ func TestHandleDeleteProjectAsUser(t *testing.T) {
 
 	c.HandleDeleteProject()(httpRec, r)
 	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	is.True(strings.Contains(httpRec.Body.String(), `"code":"auth:forbidden"`))
 	is.Equal(1, len(repo.projects))
 }
 
@@ -545,3 +658,279 @@ func TestHandleDeleteProjectIdNotValid(t *testing.T) {
 	c.HandleDeleteProject()(httpRec, r)
 	is.Equal(httpRec.Result().StatusCode, http.StatusNotAcceptable)
 }
+
+func TestHandleGetProjectsWithStatus(t *testing.T) {
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	projectRepository.projects = append(projectRepository.projects, &Project{
+		ID:             uuid.New(),
+		Title:          "My Done Project",
+		Status:         ProjectStatusDone,
+		OrganizationID: shared.OrganizationIDSample,
+	})
+
+	a := &ProjectRestHandlers{
+		config:            &shared.Config{},
+		projectRepository: projectRepository,
+	}
+
+	getProjects := func(url string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", url, nil)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+		}))
+		a.HandleGetProjects()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := getProjects("/api/projects")
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	openProjectsModel := &projectsModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(openProjectsModel)
+	is.NoErr(err)
+	is.Equal(len(openProjectsModel.EmbeddedProjects.ProjectModels), 1)
+
+	httpRec = getProjects("/api/projects?status=done")
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	doneProjectsModel := &projectsModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(doneProjectsModel)
+	is.NoErr(err)
+	is.Equal(len(doneProjectsModel.EmbeddedProjects.ProjectModels), 1)
+	is.Equal(doneProjectsModel.EmbeddedProjects.ProjectModels[0].Status, ProjectStatusDone)
+
+	httpRec = getProjects("/api/projects?status=active,done")
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = getProjects("/api/projects?status=archived")
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleUpdateProjectStatus(t *testing.T) {
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	c := &ProjectRestHandlers{
+		config: &shared.Config{},
+		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+			repositoryTxer:    shared.NewInMemRepositoryTxer(),
+			projectRepository: projectRepository,
+		},
+		projectRepository: projectRepository,
+	}
+
+	patchStatus := func(status string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"status": "%s"}`, status)
+		r, _ := http.NewRequest("PATCH", fmt.Sprintf("/api/projects/%v", shared.ProjectIDSample), strings.NewReader(body))
+		r.Header.Set("Content-Type", shared.MergePatchContentType)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "admin",
+			Roles:          []string{"ROLE_ADMIN"},
+		}))
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		c.HandleUpdateProject()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := patchStatus(ProjectStatusDone)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	projectModel := &projectModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(projectModel)
+	is.NoErr(err)
+	is.Equal(projectModel.Status, ProjectStatusDone)
+	is.True(projectModel.CompletedAt != "")
+
+	httpRec = patchStatus(ProjectStatusOnHold)
+	is.Equal(httpRec.Result().StatusCode, http.StatusConflict)
+
+	httpRec = patchStatus("archived")
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/api/projects/%v/status-changes", shared.ProjectIDSample), nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	c.HandleGetProjectStatusChanges()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	statusChangesModel := &projectStatusChangesModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(statusChangesModel)
+	is.NoErr(err)
+	is.Equal(len(statusChangesModel.StatusChanges), 1)
+	is.Equal(statusChangesModel.StatusChanges[0].FromStatus, ProjectStatusActive)
+	is.Equal(statusChangesModel.StatusChanges[0].ToStatus, ProjectStatusDone)
+	is.Equal(statusChangesModel.StatusChanges[0].ChangedBy, "admin")
+}
+
+func TestHandleCloneProject(t *testing.T) {
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	projectRepository.projects[0].Description = "My Description"
+	projectRepository.projects[0].Billable = true
+	projectRepository.projects[0].BudgetMinutes = 600
+
+	c := &ProjectRestHandlers{
+		config: &shared.Config{},
+		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+			repositoryTxer:    shared.NewInMemRepositoryTxer(),
+			projectRepository: projectRepository,
+		},
+		projectRepository: projectRepository,
+	}
+
+	cloneProject := func(projectID, body string, roles ...string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", fmt.Sprintf("/api/projects/%v/clone", projectID), strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          roles,
+		}))
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("project-id", projectID)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		c.HandleCloneProject()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := cloneProject(shared.ProjectIDSample.String(), "", "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	clonedProjectModel := &projectModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(clonedProjectModel)
+	is.NoErr(err)
+	is.True(clonedProjectModel.ID != shared.ProjectIDSample.String())
+	is.Equal(clonedProjectModel.Title, "My Project (Copy)")
+	is.Equal(clonedProjectModel.Description, "My Description")
+	is.Equal(clonedProjectModel.Status, ProjectStatusActive)
+	is.True(clonedProjectModel.Billable)
+	is.Equal(clonedProjectModel.BudgetHours, 10.0)
+
+	httpRec = cloneProject(shared.ProjectIDSample.String(), `{"title": "My Project 2025", "status": "proposed"}`, "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	proposedProjectModel := &projectModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(proposedProjectModel)
+	is.NoErr(err)
+	is.Equal(proposedProjectModel.Title, "My Project 2025")
+	is.Equal(proposedProjectModel.Status, ProjectStatusProposed)
+
+	httpRec = cloneProject(shared.ProjectIDSample.String(), `{"status": "archived"}`, "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = cloneProject(uuid.New().String(), "", "ROLE_ADMIN")
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+
+	httpRec = cloneProject(shared.ProjectIDSample.String(), "", "ROLE_USER")
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
+
+func TestHandleProjectAliases(t *testing.T) {
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	c := &ProjectRestHandlers{
+		config: &shared.Config{},
+		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+			repositoryTxer:    shared.NewInMemRepositoryTxer(),
+			projectRepository: projectRepository,
+		},
+		projectRepository: projectRepository,
+	}
+
+	requestOf := func(method, url, body string, urlParams map[string]string, roles ...string) *http.Request {
+		r, _ := http.NewRequest(method, url, strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Roles:          roles,
+		}))
+
+		rctx := chi.NewRouteContext()
+		for key, value := range urlParams {
+			rctx.URLParams.Add(key, value)
+		}
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	projectIDParams := map[string]string{"project-id": shared.ProjectIDSample.String()}
+	aliasesURL := fmt.Sprintf("/api/projects/%s/aliases", shared.ProjectIDSample)
+
+	httpRec := httptest.NewRecorder()
+	c.HandleCreateProjectAlias()(httpRec, requestOf("POST", aliasesURL, `{"alias": "web"}`, projectIDParams, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	c.HandleCreateProjectAlias()(httpRec, requestOf("POST", aliasesURL, `{"alias": "web-site"}`, projectIDParams, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	c.HandleCreateProjectAlias()(httpRec, requestOf("POST", aliasesURL, `{"alias": "web"}`, projectIDParams, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	aliasModel := &projectAliasModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(aliasModel)
+	is.NoErr(err)
+	is.Equal(aliasModel.Alias, "WEB")
+
+	httpRec = httptest.NewRecorder()
+	c.HandleCreateProjectAlias()(httpRec, requestOf("POST", aliasesURL, `{"alias": "WEB"}`, projectIDParams, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusConflict)
+
+	httpRec = httptest.NewRecorder()
+	c.HandleGetProjectAliases()(httpRec, requestOf("GET", aliasesURL, "", projectIDParams, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	aliasesModel := &projectAliasesModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(aliasesModel)
+	is.NoErr(err)
+	is.Equal(len(aliasesModel.Aliases), 1)
+	is.Equal(aliasesModel.Aliases[0].Alias, "WEB")
+
+	httpRec = httptest.NewRecorder()
+	c.HandleGetProject()(httpRec, requestOf("GET", "/api/projects/web", "", map[string]string{"project-id": "web"}, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	projectModelOfAlias := &projectModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(projectModelOfAlias)
+	is.NoErr(err)
+	is.Equal(projectModelOfAlias.ID, shared.ProjectIDSample.String())
+
+	httpRec = httptest.NewRecorder()
+	c.HandleGetProject()(httpRec, requestOf("GET", "/api/projects/app", "", map[string]string{"project-id": "app"}, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+
+	aliasParams := map[string]string{"project-id": shared.ProjectIDSample.String(), "alias": "web"}
+
+	httpRec = httptest.NewRecorder()
+	c.HandleDeleteProjectAlias()(httpRec, requestOf("DELETE", aliasesURL+"/web", "", aliasParams, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	c.HandleDeleteProjectAlias()(httpRec, requestOf("DELETE", aliasesURL+"/web", "", aliasParams, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	httpRec = httptest.NewRecorder()
+	c.HandleDeleteProjectAlias()(httpRec, requestOf("DELETE", aliasesURL+"/web", "", aliasParams, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
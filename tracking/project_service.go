@@ -2,56 +2,156 @@ package tracking
 
 import (
 	"context"
+	"time"
 
 	"github.com/baralga/shared"
+	"github.com/baralga/shared/paged"
 	"github.com/google/uuid"
 )
 
 type ProjectService struct {
 	repositoryTxer    shared.RepositoryTxer
 	projectRepository ProjectRepository
+	planService       *shared.PlanService
 }
 
-func NewProjectService(repositoryTxer shared.RepositoryTxer, projectRepository ProjectRepository) *ProjectService {
+func NewProjectService(repositoryTxer shared.RepositoryTxer, projectRepository ProjectRepository, planService *shared.PlanService) *ProjectService {
 	return &ProjectService{
 		repositoryTxer:    repositoryTxer,
 		projectRepository: projectRepository,
+		planService:       planService,
 	}
 }
 
+// CreateProject creates a new project within the project limit of the organization's plan
 func (a *ProjectService) CreateProject(ctx context.Context, principal *shared.Principal, project *Project) (*Project, error) {
-	project.ID = uuid.New()
-	project.OrganizationID = principal.OrganizationID
+	return a.createProject(ctx, principal, project, a.projectRepository.InsertProject)
+}
+
+// CloneProject creates a new project with the settings, budget and client of the project, its activities are not copied
+func (a *ProjectService) CloneProject(ctx context.Context, principal *shared.Principal, projectID uuid.UUID, title, status string) (*Project, error) {
+	sourceProject, err := a.projectRepository.FindProjectByID(ctx, principal.OrganizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if title == "" {
+		title = cloneTitleOf(sourceProject.Title)
+	}
+
+	project := &Project{
+		Title:         title,
+		Description:   sourceProject.Description,
+		Status:        status,
+		Billable:      sourceProject.Billable,
+		BudgetMinutes: sourceProject.BudgetMinutes,
+	}
+
+	return a.createProject(ctx, principal, project, func(ctx context.Context, project *Project) (*Project, error) {
+		return a.projectRepository.CloneProject(ctx, principal.OrganizationID, sourceProject.ID, project)
+	})
+}
+
+// CreateProjectsFromTemplate creates a project with the settings of the template and a sub-project for each of its tasks
+func (a *ProjectService) CreateProjectsFromTemplate(ctx context.Context, principal *shared.Principal, template *ProjectTemplate, title, status string) ([]*Project, error) {
+	return a.createProjects(ctx, principal, template.ProjectsOf(title, status), a.projectRepository.InsertProject)
+}
 
-	var projectCreated *Project
-	err := a.repositoryTxer.InTx(
-		context.Background(),
+func (a *ProjectService) createProject(ctx context.Context, principal *shared.Principal, project *Project, insertProject func(ctx context.Context, project *Project) (*Project, error)) (*Project, error) {
+	projectsCreated, err := a.createProjects(ctx, principal, []*Project{project}, insertProject)
+	if err != nil {
+		return nil, err
+	}
+	return projectsCreated[0], nil
+}
+
+// createProjects creates all projects in one transaction if the project limit of the organization's plan allows all of them
+func (a *ProjectService) createProjects(ctx context.Context, principal *shared.Principal, projects []*Project, insertProject func(ctx context.Context, project *Project) (*Project, error)) ([]*Project, error) {
+	projectsPaged, err := a.projectRepository.FindProjects(ctx, principal.OrganizationID, &paged.PageParams{Page: 0, Size: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.planService.CheckLimit(ctx, principal.OrganizationID, shared.PlanLimitProjects, projectsPaged.Page.TotalElements+len(projects)-1)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, project := range projects {
+		if project.Status == "" {
+			project.Status = ProjectStatusActive
+		}
+		if !IsValidProjectStatus(project.Status) {
+			return nil, ErrInvalidProjectStatusTransition
+		}
+
+		project.ID = uuid.New()
+		project.OrganizationID = principal.OrganizationID
+		project.stampStatus(now)
+	}
+
+	projectsCreated := make([]*Project, 0, len(projects))
+	err = a.repositoryTxer.InTx(
+		ctx,
 		func(ctx context.Context) error {
-			a, err := a.projectRepository.InsertProject(ctx, project)
-			if err != nil {
-				return err
+			for _, project := range projects {
+				p, err := insertProject(ctx, project)
+				if err != nil {
+					return err
+				}
+				projectsCreated = append(projectsCreated, p)
+
+				err = a.insertStatusChange(ctx, principal, project, "", now)
+				if err != nil {
+					return err
+				}
 			}
-			projectCreated = a
 			return nil
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
-	return projectCreated, nil
+	return projectsCreated, nil
 }
 
-func (a *ProjectService) UpdateProject(ctx context.Context, organizationID uuid.UUID, project *Project) (*Project, error) {
+// UpdateProject updates a project, a changed status has to follow the project lifecycle
+func (a *ProjectService) UpdateProject(ctx context.Context, principal *shared.Principal, project *Project) (*Project, error) {
+	currentProject, err := a.projectRepository.FindProjectByID(ctx, principal.OrganizationID, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := project.Status
+	project.OrganizationID = principal.OrganizationID
+	project.Status = currentProject.Status
+	project.StartedAt = currentProject.StartedAt
+	project.CompletedAt = currentProject.CompletedAt
+
+	now := time.Now()
+	statusChanged := status != "" && status != currentProject.Status
+	if statusChanged {
+		err := project.ChangeStatus(status, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var projectUpdated *Project
-	err := a.repositoryTxer.InTx(
-		context.Background(),
+	err = a.repositoryTxer.InTx(
+		ctx,
 		func(ctx context.Context) error {
-			p, err := a.projectRepository.UpdateProject(ctx, organizationID, project)
+			p, err := a.projectRepository.UpdateProject(ctx, principal.OrganizationID, project)
 			if err != nil {
 				return err
 			}
 			projectUpdated = p
-			return nil
+
+			if !statusChanged {
+				return nil
+			}
+			return a.insertStatusChange(ctx, principal, project, currentProject.Status, now)
 		},
 	)
 	if err != nil {
@@ -60,21 +160,77 @@ func (a *ProjectService) UpdateProject(ctx context.Context, organizationID uuid.
 	return projectUpdated, nil
 }
 
-func (a *ProjectService) ArchiveProject(ctx context.Context, organizationID, projectID uuid.UUID) error {
-	err := a.repositoryTxer.InTx(
-		context.Background(),
+// ChangeProjectStatus changes the status of a project following the project lifecycle
+func (a *ProjectService) ChangeProjectStatus(ctx context.Context, principal *shared.Principal, projectID uuid.UUID, status string) (*Project, error) {
+	project, err := a.projectRepository.FindProjectByID(ctx, principal.OrganizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectToUpdate := *project
+	projectToUpdate.Status = status
+	return a.UpdateProject(ctx, principal, &projectToUpdate)
+}
+
+// ArchiveProject completes a project, so it's no longer offered for tracking
+func (a *ProjectService) ArchiveProject(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) error {
+	_, err := a.ChangeProjectStatus(ctx, principal, projectID, ProjectStatusDone)
+	return err
+}
+
+// ReadProjectStatusChanges reads the audit trail of the project lifecycle
+func (a *ProjectService) ReadProjectStatusChanges(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) ([]*ProjectStatusChange, error) {
+	_, err := a.projectRepository.FindProjectByID(ctx, principal.OrganizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.projectRepository.FindProjectStatusChanges(ctx, principal.OrganizationID, projectID)
+}
+
+// ReadProjectAliases reads the short codes of a project
+func (a *ProjectService) ReadProjectAliases(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) ([]*ProjectAlias, error) {
+	_, err := a.projectRepository.FindProjectByID(ctx, principal.OrganizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.projectRepository.FindProjectAliases(ctx, principal.OrganizationID, projectID)
+}
+
+// AddProjectAlias adds a short code to a project, which must not be used by another project of the organization
+func (a *ProjectService) AddProjectAlias(ctx context.Context, principal *shared.Principal, projectID uuid.UUID, alias string) (*ProjectAlias, error) {
+	_, err := a.projectRepository.FindProjectByID(ctx, principal.OrganizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectAlias := &ProjectAlias{
+		OrganizationID: principal.OrganizationID,
+		ProjectID:      projectID,
+		Alias:          NormalizeProjectAlias(alias),
+	}
+
+	err = a.repositoryTxer.InTx(
+		ctx,
 		func(ctx context.Context) error {
-			err := a.projectRepository.ArchiveProjectByID(ctx, organizationID, projectID)
-			if err != nil {
-				return err
-			}
-			return nil
+			return a.projectRepository.InsertProjectAlias(ctx, projectAlias)
 		},
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return projectAlias, nil
+}
+
+// DeleteProjectAlias removes a short code of a project
+func (a *ProjectService) DeleteProjectAlias(ctx context.Context, principal *shared.Principal, projectID uuid.UUID, alias string) error {
+	return a.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return a.projectRepository.DeleteProjectAlias(ctx, principal.OrganizationID, projectID, alias)
+		},
+	)
 }
 
 func (a *ProjectService) DeleteProjectByID(ctx context.Context, principal *shared.Principal, projectID uuid.UUID) error {
@@ -92,9 +248,10 @@ func (a *ProjectService) OrganizationInitializer() func(ctx context.Context, org
 		project := &Project{
 			ID:             uuid.New(),
 			Title:          "My Project",
-			Active:         true,
+			Status:         ProjectStatusActive,
 			OrganizationID: organizationID,
 		}
+		project.stampStatus(time.Now())
 
 		_, err := a.projectRepository.InsertProject(ctx, project)
 		if err != nil {
@@ -103,3 +260,43 @@ func (a *ProjectService) OrganizationInitializer() func(ctx context.Context, org
 		return nil
 	}
 }
+
+func (a *ProjectService) insertStatusChange(ctx context.Context, principal *shared.Principal, project *Project, fromStatus string, now time.Time) error {
+	return a.projectRepository.InsertProjectStatusChange(ctx, &ProjectStatusChange{
+		ID:             uuid.New(),
+		OrganizationID: project.OrganizationID,
+		ProjectID:      project.ID,
+		FromStatus:     fromStatus,
+		ToStatus:       project.Status,
+		ChangedBy:      principal.Username,
+		ChangedAt:      now,
+	})
+}
+
+// projectIDOf resolves the id of a project referenced by its id or by one of its aliases
+func projectIDOf(ctx context.Context, projectRepository ProjectRepository, organizationID uuid.UUID, reference string) (uuid.UUID, error) {
+	projectID, err := uuid.Parse(reference)
+	if err == nil {
+		return projectID, nil
+	}
+
+	if !IsValidProjectAlias(reference) {
+		return uuid.Nil, err
+	}
+
+	project, err := projectRepository.FindProjectByAlias(ctx, organizationID, reference)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return project.ID, nil
+}
+
+// cloneTitleOf is the title of a clone of the project, shortened to the maximum length of project titles
+func cloneTitleOf(title string) string {
+	const suffix = " (Copy)"
+	runes := []rune(title)
+	if len(runes)+len(suffix) > maxProjectTitleLength {
+		runes = runes[:maxProjectTitleLength-len(suffix)]
+	}
+	return string(runes) + suffix
+}
@@ -2,10 +2,13 @@ package tracking
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/baralga/shared"
+	"github.com/google/uuid"
 	"github.com/matryer/is"
+	"github.com/pkg/errors"
 )
 
 func TestArchiveProject(t *testing.T) {
@@ -14,14 +17,165 @@ func TestArchiveProject(t *testing.T) {
 
 	projectRepository := NewInMemProjectRepository()
 	a := &ProjectService{
+		planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
 		repositoryTxer:    shared.NewInMemRepositoryTxer(),
 		projectRepository: projectRepository,
 	}
 
 	// Act
-	err := a.ArchiveProject(context.Background(), shared.OrganizationIDSample, shared.ProjectIDSample)
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin"}
+	err := a.ArchiveProject(context.Background(), principal, shared.ProjectIDSample)
 
 	// Assert
 	is.NoErr(err)
-	is.Equal(projectRepository.projects[0].Active, false)
+	is.Equal(projectRepository.projects[0].Status, ProjectStatusDone)
+	is.True(projectRepository.projects[0].CompletedAt != nil)
+	is.Equal(len(projectRepository.statusChanges), 1)
+}
+
+func TestCreateProjectWithinPlanLimit(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	planRepository := shared.NewInMemPlanRepository()
+	planRepository.SetPlanOfOrganization(shared.OrganizationIDSample, shared.PlanFree)
+
+	projectRepository := NewInMemProjectRepository()
+	a := NewProjectService(
+		shared.NewInMemRepositoryTxer(),
+		projectRepository,
+		shared.NewPlanService(&shared.Config{}, planRepository),
+	)
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample}
+
+	// Act
+	var err error
+	for i := len(projectRepository.projects); i < 3; i++ {
+		_, err = a.CreateProject(context.Background(), principal, &Project{Title: "My Project"})
+		is.NoErr(err)
+	}
+	_, err = a.CreateProject(context.Background(), principal, &Project{Title: "My Project"})
+
+	// Assert
+	is.True(errors.Is(err, shared.ErrPlanLimitExceeded))
+	is.Equal(len(projectRepository.projects), 3)
+}
+
+func TestCreateProjectWithStatus(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	a := &ProjectService{
+		planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+		repositoryTxer:    shared.NewInMemRepositoryTxer(),
+		projectRepository: projectRepository,
+	}
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin"}
+
+	// Act
+	activeProject, err := a.CreateProject(context.Background(), principal, &Project{Title: "My Active Project"})
+	is.NoErr(err)
+	proposedProject, err := a.CreateProject(context.Background(), principal, &Project{Title: "My Proposed Project", Status: ProjectStatusProposed})
+	is.NoErr(err)
+
+	// Assert
+	is.Equal(activeProject.Status, ProjectStatusActive)
+	is.True(activeProject.StartedAt != nil)
+	is.Equal(proposedProject.Status, ProjectStatusProposed)
+	is.True(proposedProject.StartedAt == nil)
+
+	statusChanges, err := a.ReadProjectStatusChanges(context.Background(), principal, proposedProject.ID)
+	is.NoErr(err)
+	is.Equal(len(statusChanges), 1)
+	is.Equal(statusChanges[0].FromStatus, "")
+	is.Equal(statusChanges[0].ToStatus, ProjectStatusProposed)
+}
+
+func TestChangeProjectStatus(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	a := &ProjectService{
+		planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+		repositoryTxer:    shared.NewInMemRepositoryTxer(),
+		projectRepository: projectRepository,
+	}
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin"}
+
+	// Act & Assert
+	project, err := a.ChangeProjectStatus(context.Background(), principal, shared.ProjectIDSample, ProjectStatusOnHold)
+	is.NoErr(err)
+	is.Equal(project.Status, ProjectStatusOnHold)
+
+	_, err = a.ChangeProjectStatus(context.Background(), principal, shared.ProjectIDSample, ProjectStatusProposed)
+	is.True(errors.Is(err, ErrInvalidProjectStatusTransition))
+
+	project, err = a.ChangeProjectStatus(context.Background(), principal, shared.ProjectIDSample, ProjectStatusDone)
+	is.NoErr(err)
+	is.True(project.CompletedAt != nil)
+
+	project, err = a.ChangeProjectStatus(context.Background(), principal, shared.ProjectIDSample, ProjectStatusActive)
+	is.NoErr(err)
+	is.True(project.CompletedAt == nil)
+
+	statusChanges, err := a.ReadProjectStatusChanges(context.Background(), principal, shared.ProjectIDSample)
+	is.NoErr(err)
+	is.Equal(len(statusChanges), 3)
+}
+
+func TestProjectAliases(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	a := &ProjectService{
+		planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+		repositoryTxer:    shared.NewInMemRepositoryTxer(),
+		projectRepository: projectRepository,
+	}
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin"}
+
+	// Act & Assert
+	projectAlias, err := a.AddProjectAlias(context.Background(), principal, shared.ProjectIDSample, "web")
+	is.NoErr(err)
+	is.Equal(projectAlias.Alias, "WEB")
+
+	_, err = a.AddProjectAlias(context.Background(), principal, shared.ProjectIDSample, "Web")
+	is.True(errors.Is(err, ErrProjectAliasTaken))
+
+	_, err = a.AddProjectAlias(context.Background(), principal, uuid.New(), "APP")
+	is.True(errors.Is(err, ErrProjectNotFound))
+
+	projectAliases, err := a.ReadProjectAliases(context.Background(), principal, shared.ProjectIDSample)
+	is.NoErr(err)
+	is.Equal(len(projectAliases), 1)
+
+	projectID, err := projectIDOf(context.Background(), projectRepository, shared.OrganizationIDSample, "web")
+	is.NoErr(err)
+	is.Equal(projectID, shared.ProjectIDSample)
+
+	projectID, err = projectIDOf(context.Background(), projectRepository, shared.OrganizationIDSample, shared.ProjectIDSample.String())
+	is.NoErr(err)
+	is.Equal(projectID, shared.ProjectIDSample)
+
+	_, err = projectIDOf(context.Background(), projectRepository, shared.OrganizationIDSample, "no-alias")
+	is.True(err != nil)
+
+	err = a.DeleteProjectAlias(context.Background(), principal, shared.ProjectIDSample, "WEB")
+	is.NoErr(err)
+
+	_, err = projectIDOf(context.Background(), projectRepository, shared.OrganizationIDSample, "WEB")
+	is.True(errors.Is(err, ErrProjectNotFound))
+
+	err = a.DeleteProjectAlias(context.Background(), principal, shared.ProjectIDSample, "WEB")
+	is.True(errors.Is(err, ErrProjectAliasNotFound))
+}
+
+func TestCloneTitleOf(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(cloneTitleOf("My Project"), "My Project (Copy)")
+	is.Equal(len([]rune(cloneTitleOf(strings.Repeat("ä", 100)))), maxProjectTitleLength)
 }
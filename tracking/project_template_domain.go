@@ -0,0 +1,107 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProjectTemplateNotFound = shared.NewDomainError("project-template:not-found", http.StatusNotFound, "project template not found")
+	ErrProjectTemplateBuiltIn  = shared.NewDomainError("project-template:built-in", http.StatusConflict, "built-in project templates cannot be changed")
+)
+
+// ProjectTemplate is a blueprint for new projects with their settings and default tasks,
+// every task becomes a sub-project titled after the project
+type ProjectTemplate struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Name           string
+	Description    string
+	Billable       bool
+	BudgetMinutes  int
+	Tasks          []string
+	BuiltIn        bool
+}
+
+// builtInProjectTemplates are the templates for common setups every organization can use
+var builtInProjectTemplates = []*ProjectTemplate{
+	{
+		ID:          uuid.MustParse("7d3c1f5e-2a41-4c8e-9b0d-000000000001"),
+		Name:        "Software Development",
+		Description: "Billable development project",
+		Billable:    true,
+		Tasks:       []string{"Development", "Code Review", "Testing", "Meetings"},
+		BuiltIn:     true,
+	},
+	{
+		ID:            uuid.MustParse("7d3c1f5e-2a41-4c8e-9b0d-000000000002"),
+		Name:          "Consulting",
+		Description:   "Billable consulting engagement with a budget of 40 hours",
+		Billable:      true,
+		BudgetMinutes: 40 * 60,
+		Tasks:         []string{"Workshops", "Analysis", "Documentation"},
+		BuiltIn:       true,
+	},
+	{
+		ID:          uuid.MustParse("7d3c1f5e-2a41-4c8e-9b0d-000000000003"),
+		Name:        "Internal",
+		Description: "Non-billable internal work",
+		Tasks:       []string{"Administration", "Training"},
+		BuiltIn:     true,
+	},
+}
+
+type ProjectTemplateRepository interface {
+	FindProjectTemplates(ctx context.Context, organizationID uuid.UUID) ([]*ProjectTemplate, error)
+	FindProjectTemplateByID(ctx context.Context, organizationID, templateID uuid.UUID) (*ProjectTemplate, error)
+	InsertProjectTemplate(ctx context.Context, template *ProjectTemplate) error
+	UpdateProjectTemplate(ctx context.Context, template *ProjectTemplate) error
+	DeleteProjectTemplate(ctx context.Context, organizationID, templateID uuid.UUID) error
+}
+
+// BuiltInProjectTemplateOf finds the built-in template with the id
+func BuiltInProjectTemplateOf(templateID uuid.UUID) (*ProjectTemplate, bool) {
+	for _, template := range builtInProjectTemplates {
+		if template.ID == templateID {
+			return template, true
+		}
+	}
+	return nil, false
+}
+
+// ProjectsOf are the project and its sub-projects for the default tasks as created from the template,
+// only the project itself has the budget of the template
+func (t *ProjectTemplate) ProjectsOf(title, status string) []*Project {
+	projects := []*Project{
+		{
+			Title:         title,
+			Description:   t.Description,
+			Status:        status,
+			Billable:      t.Billable,
+			BudgetMinutes: t.BudgetMinutes,
+		},
+	}
+
+	for _, task := range t.Tasks {
+		projects = append(projects, &Project{
+			Title:    subProjectTitleOf(title, task),
+			Status:   status,
+			Billable: t.Billable,
+		})
+	}
+	return projects
+}
+
+// subProjectTitleOf is the title of the sub-project for the task, the title of the project
+// is shortened so that the title fits
+func subProjectTitleOf(title, task string) string {
+	suffix := []rune(" / " + task)
+	runes := []rune(title)
+	if len(runes)+len(suffix) > maxProjectTitleLength {
+		runes = runes[:max(0, maxProjectTitleLength-len(suffix))]
+	}
+	return string(runes) + string(suffix)
+}
@@ -0,0 +1,48 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestProjectsOfTemplate(t *testing.T) {
+	is := is.New(t)
+
+	template := &ProjectTemplate{
+		Description:   "Consulting",
+		Billable:      true,
+		BudgetMinutes: 600,
+		Tasks:         []string{"Workshops", "Analysis"},
+	}
+
+	projects := template.ProjectsOf("ACME", ProjectStatusProposed)
+	is.Equal(len(projects), 3)
+	is.Equal(projects[0].Title, "ACME")
+	is.Equal(projects[0].BudgetMinutes, 600)
+	is.Equal(projects[1].Title, "ACME / Workshops")
+	is.Equal(projects[1].BudgetMinutes, 0)
+	is.True(projects[2].Billable)
+	is.Equal(projects[2].Status, ProjectStatusProposed)
+}
+
+func TestSubProjectTitleOf(t *testing.T) {
+	is := is.New(t)
+
+	title := subProjectTitleOf(strings.Repeat("a", maxProjectTitleLength), "Testing")
+	is.Equal(len([]rune(title)), maxProjectTitleLength)
+	is.True(strings.HasSuffix(title, " / Testing"))
+}
+
+func TestBuiltInProjectTemplateOf(t *testing.T) {
+	is := is.New(t)
+
+	template, ok := BuiltInProjectTemplateOf(builtInProjectTemplates[0].ID)
+	is.True(ok)
+	is.True(template.BuiltIn)
+
+	_, ok = BuiltInProjectTemplateOf(uuid.New())
+	is.True(!ok)
+}
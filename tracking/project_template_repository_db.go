@@ -0,0 +1,153 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbProjectTemplateRepository is a SQL database repository for the project templates of organizations
+type DbProjectTemplateRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ ProjectTemplateRepository = (*DbProjectTemplateRepository)(nil)
+
+// NewDbProjectTemplateRepository creates a new SQL database repository for project templates
+func NewDbProjectTemplateRepository(connPool *pgxpool.Pool) *DbProjectTemplateRepository {
+	return &DbProjectTemplateRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbProjectTemplateRepository) FindProjectTemplates(ctx context.Context, organizationID uuid.UUID) ([]*ProjectTemplate, error) {
+	rows, err := shared.SelectAll[projectTemplateRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[projectTemplateRow]()+`
+		 FROM project_templates
+		 WHERE org_id = $1
+		 ORDER BY name ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]*ProjectTemplate, len(rows))
+	for i, row := range rows {
+		templates[i] = row.toProjectTemplate()
+	}
+	return templates, nil
+}
+
+func (r *DbProjectTemplateRepository) FindProjectTemplateByID(ctx context.Context, organizationID, templateID uuid.UUID) (*ProjectTemplate, error) {
+	row, err := shared.SelectOne[projectTemplateRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[projectTemplateRow]()+`
+		 FROM project_templates
+		 WHERE template_id = $1 AND org_id = $2`,
+		templateID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProjectTemplateNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toProjectTemplate(), nil
+}
+
+func (r *DbProjectTemplateRepository) InsertProjectTemplate(ctx context.Context, template *ProjectTemplate) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO project_templates
+		   (template_id, org_id, name, description, billable, budget_minutes, tasks)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7)`,
+		template.ID,
+		template.OrganizationID,
+		template.Name,
+		template.Description,
+		template.Billable,
+		template.BudgetMinutes,
+		template.Tasks,
+	)
+	return err
+}
+
+func (r *DbProjectTemplateRepository) UpdateProjectTemplate(ctx context.Context, template *ProjectTemplate) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE project_templates
+		 SET name = $3, description = $4, billable = $5, budget_minutes = $6, tasks = $7
+		 WHERE template_id = $1 AND org_id = $2`,
+		template.ID,
+		template.OrganizationID,
+		template.Name,
+		template.Description,
+		template.Billable,
+		template.BudgetMinutes,
+		template.Tasks,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrProjectTemplateNotFound
+	}
+	return nil
+}
+
+func (r *DbProjectTemplateRepository) DeleteProjectTemplate(ctx context.Context, organizationID, templateID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM project_templates
+		 WHERE template_id = $1 AND org_id = $2`,
+		templateID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrProjectTemplateNotFound
+	}
+	return nil
+}
+
+type projectTemplateRow struct {
+	ID             uuid.UUID `db:"template_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	Name           string    `db:"name"`
+	Description    string    `db:"description"`
+	Billable       bool      `db:"billable"`
+	BudgetMinutes  int       `db:"budget_minutes"`
+	Tasks          []string  `db:"tasks"`
+}
+
+func (r *projectTemplateRow) toProjectTemplate() *ProjectTemplate {
+	return &ProjectTemplate{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		Name:           r.Name,
+		Description:    r.Description,
+		Billable:       r.Billable,
+		BudgetMinutes:  r.BudgetMinutes,
+		Tasks:          r.Tasks,
+	}
+}
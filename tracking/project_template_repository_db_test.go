@@ -0,0 +1,82 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestProjectTemplateRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	projectTemplateRepository := NewDbProjectTemplateRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	template := &ProjectTemplate{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Name:           "Agency",
+		Billable:       true,
+		BudgetMinutes:  600,
+		Tasks:          []string{"Design", "Copy"},
+	}
+
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return projectTemplateRepository.InsertProjectTemplate(ctx, template)
+		},
+	)
+	is.NoErr(err)
+
+	templates, err := projectTemplateRepository.FindProjectTemplates(context.Background(), shared.OrganizationIDSample)
+	is.NoErr(err)
+	is.Equal(len(templates), 1)
+	is.Equal(templates[0].Tasks, []string{"Design", "Copy"})
+
+	template.Tasks = []string{"Design"}
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return projectTemplateRepository.UpdateProjectTemplate(ctx, template)
+		},
+	)
+	is.NoErr(err)
+
+	templateFound, err := projectTemplateRepository.FindProjectTemplateByID(context.Background(), shared.OrganizationIDSample, template.ID)
+	is.NoErr(err)
+	is.Equal(templateFound.Tasks, []string{"Design"})
+
+	err = repositoryTxer.InTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return projectTemplateRepository.DeleteProjectTemplate(ctx, shared.OrganizationIDSample, template.ID)
+		},
+	)
+	is.NoErr(err)
+
+	_, err = projectTemplateRepository.FindProjectTemplateByID(context.Background(), shared.OrganizationIDSample, template.ID)
+	is.Equal(err, ErrProjectTemplateNotFound)
+}
@@ -0,0 +1,89 @@
+package tracking
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemProjectTemplateRepository struct {
+	mu        sync.Mutex
+	templates []*ProjectTemplate
+}
+
+var _ ProjectTemplateRepository = (*InMemProjectTemplateRepository)(nil)
+
+func NewInMemProjectTemplateRepository() *InMemProjectTemplateRepository {
+	return &InMemProjectTemplateRepository{}
+}
+
+func (r *InMemProjectTemplateRepository) FindProjectTemplates(ctx context.Context, organizationID uuid.UUID) ([]*ProjectTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var templates []*ProjectTemplate
+	for _, template := range r.templates {
+		if template.OrganizationID == organizationID {
+			templates = append(templates, copyProjectTemplate(template))
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+func (r *InMemProjectTemplateRepository) FindProjectTemplateByID(ctx context.Context, organizationID, templateID uuid.UUID) (*ProjectTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, template := range r.templates {
+		if template.OrganizationID == organizationID && template.ID == templateID {
+			return copyProjectTemplate(template), nil
+		}
+	}
+	return nil, ErrProjectTemplateNotFound
+}
+
+func (r *InMemProjectTemplateRepository) InsertProjectTemplate(ctx context.Context, template *ProjectTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.templates = append(r.templates, copyProjectTemplate(template))
+	return nil
+}
+
+func (r *InMemProjectTemplateRepository) UpdateProjectTemplate(ctx context.Context, template *ProjectTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, t := range r.templates {
+		if t.OrganizationID == template.OrganizationID && t.ID == template.ID {
+			r.templates[i] = copyProjectTemplate(template)
+			return nil
+		}
+	}
+	return ErrProjectTemplateNotFound
+}
+
+func (r *InMemProjectTemplateRepository) DeleteProjectTemplate(ctx context.Context, organizationID, templateID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, template := range r.templates {
+		if template.OrganizationID == organizationID && template.ID == templateID {
+			r.templates = append(r.templates[:i], r.templates[i+1:]...)
+			return nil
+		}
+	}
+	return ErrProjectTemplateNotFound
+}
+
+func copyProjectTemplate(template *ProjectTemplate) *ProjectTemplate {
+	copied := *template
+	copied.Tasks = slices.Clone(template.Tasks)
+	return &copied
+}
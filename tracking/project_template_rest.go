@@ -0,0 +1,307 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type projectTemplateModel struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name" validate:"required,min=3,max=100"`
+	Description string     `json:"description" validate:"max=500"`
+	Billable    bool       `json:"billable"`
+	BudgetHours float64    `json:"budgetHours" validate:"min=0"`
+	Tasks       []string   `json:"tasks" validate:"max=20,dive,required,max=50"`
+	BuiltIn     bool       `json:"builtIn"`
+	Links       *hal.Links `json:"_links,omitempty"`
+}
+
+type projectTemplatesModel struct {
+	Embedded struct {
+		ProjectTemplateModels []*projectTemplateModel `json:"projectTemplates"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type projectFromTemplateModel struct {
+	Title  string `json:"title" validate:"required,min=3,max=100"`
+	Status string `json:"status" validate:"omitempty,oneof=proposed active on-hold done"`
+}
+
+type projectsFromTemplateModel struct {
+	Embedded struct {
+		ProjectModels []*projectModel `json:"projects"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type ProjectTemplateRestHandlers struct {
+	config                 *shared.Config
+	projectTemplateService *ProjectTemplateService
+}
+
+func NewProjectTemplateRestHandlers(config *shared.Config, projectTemplateService *ProjectTemplateService) *ProjectTemplateRestHandlers {
+	return &ProjectTemplateRestHandlers{
+		config:                 config,
+		projectTemplateService: projectTemplateService,
+	}
+}
+
+func (a *ProjectTemplateRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/project-templates", a.HandleGetProjectTemplates())
+	r.Post("/project-templates", a.HandleCreateProjectTemplate())
+	r.Get("/project-templates/{template-id}", a.HandleGetProjectTemplate())
+	r.Put("/project-templates/{template-id}", a.HandleUpdateProjectTemplate())
+	r.Delete("/project-templates/{template-id}", a.HandleDeleteProjectTemplate())
+	r.Post("/project-templates/{template-id}/projects", a.HandleCreateProjectFromTemplate())
+}
+
+func (a *ProjectTemplateRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetProjectTemplates reads the built-in templates and the templates of the organization
+func (a *ProjectTemplateRestHandlers) HandleGetProjectTemplates() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectTemplateService := a.projectTemplateService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		templates, err := projectTemplateService.ReadProjectTemplates(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		templateModels := make([]*projectTemplateModel, len(templates))
+		for i, template := range templates {
+			templateModels[i] = mapToProjectTemplateModel(principal, template)
+		}
+
+		templatesModel := &projectTemplatesModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		templatesModel.Embedded.ProjectTemplateModels = templateModels
+
+		shared.RenderJSON(w, templatesModel)
+	}
+}
+
+// HandleGetProjectTemplate reads a built-in template or a template of the organization
+func (a *ProjectTemplateRestHandlers) HandleGetProjectTemplate() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectTemplateService := a.projectTemplateService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		templateID, err := uuid.Parse(chi.URLParam(r, "template-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		template, err := projectTemplateService.ReadProjectTemplate(r.Context(), principal, templateID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToProjectTemplateModel(principal, template))
+	}
+}
+
+// HandleCreateProjectTemplate adds a template to the organization
+func (a *ProjectTemplateRestHandlers) HandleCreateProjectTemplate() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	projectTemplateService := a.projectTemplateService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var templateModel projectTemplateModel
+		err := json.NewDecoder(r.Body).Decode(&templateModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project template not valid", err)
+			return
+		}
+
+		err = validator.Struct(templateModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project template not valid", err)
+			return
+		}
+
+		template, err := projectTemplateService.CreateProjectTemplate(r.Context(), principal, mapToProjectTemplate(&templateModel))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToProjectTemplateModel(principal, template))
+	}
+}
+
+// HandleUpdateProjectTemplate changes a template of the organization
+func (a *ProjectTemplateRestHandlers) HandleUpdateProjectTemplate() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	projectTemplateService := a.projectTemplateService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		templateID, err := uuid.Parse(chi.URLParam(r, "template-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var templateModel projectTemplateModel
+		err = json.NewDecoder(r.Body).Decode(&templateModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project template not valid", err)
+			return
+		}
+
+		err = validator.Struct(templateModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project template not valid", err)
+			return
+		}
+
+		templateToUpdate := mapToProjectTemplate(&templateModel)
+		templateToUpdate.ID = templateID
+
+		template, err := projectTemplateService.UpdateProjectTemplate(r.Context(), principal, templateToUpdate)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToProjectTemplateModel(principal, template))
+	}
+}
+
+// HandleDeleteProjectTemplate removes a template of the organization
+func (a *ProjectTemplateRestHandlers) HandleDeleteProjectTemplate() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectTemplateService := a.projectTemplateService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		templateID, err := uuid.Parse(chi.URLParam(r, "template-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = projectTemplateService.DeleteProjectTemplate(r.Context(), principal, templateID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleCreateProjectFromTemplate creates a project and its sub-projects for the tasks from a template
+func (a *ProjectTemplateRestHandlers) HandleCreateProjectFromTemplate() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	projectTemplateService := a.projectTemplateService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		templateID, err := uuid.Parse(chi.URLParam(r, "template-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		var fromTemplateModel projectFromTemplateModel
+		err = json.NewDecoder(r.Body).Decode(&fromTemplateModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
+			return
+		}
+
+		err = validator.Struct(fromTemplateModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "project not valid", err)
+			return
+		}
+
+		projects, err := projectTemplateService.CreateProjectFromTemplate(r.Context(), principal, templateID, fromTemplateModel.Title, fromTemplateModel.Status)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		projectModels := make([]*projectModel, len(projects))
+		for i, project := range projects {
+			projectModels[i] = mapToProjectModel(principal, project)
+		}
+
+		projectsModel := &projectsFromTemplateModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(fmt.Sprintf("/api/projects/%s", projects[0].ID)),
+			),
+		}
+		projectsModel.Embedded.ProjectModels = projectModels
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, projectsModel)
+	}
+}
+
+func mapToProjectTemplate(templateModel *projectTemplateModel) *ProjectTemplate {
+	return &ProjectTemplate{
+		Name:          templateModel.Name,
+		Description:   templateModel.Description,
+		Billable:      templateModel.Billable,
+		BudgetMinutes: int(math.Round(templateModel.BudgetHours * 60)),
+		Tasks:         templateModel.Tasks,
+	}
+}
+
+func mapToProjectTemplateModel(principal *shared.Principal, template *ProjectTemplate) *projectTemplateModel {
+	tasks := template.Tasks
+	if tasks == nil {
+		tasks = []string{}
+	}
+
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/project-templates/%s", template.ID))
+	links := []*hal.Links{
+		selfLink,
+	}
+	if principal.HasRole("ROLE_ADMIN") {
+		links = append(links, hal.NewLink("projects", selfLink.Href()+"/projects"))
+		if !template.BuiltIn {
+			links = append(links,
+				hal.NewLink("edit", selfLink.Href()),
+				hal.NewLink("delete", selfLink.Href()),
+			)
+		}
+	}
+
+	return &projectTemplateModel{
+		ID:          template.ID.String(),
+		Name:        template.Name,
+		Description: template.Description,
+		Billable:    template.Billable,
+		BudgetHours: float64(template.BudgetMinutes) / 60.0,
+		Tasks:       tasks,
+		BuiltIn:     template.BuiltIn,
+		Links:       hal.NewLinks(links...),
+	}
+}
@@ -0,0 +1,86 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleGetProjectTemplates(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	s, _ := newProjectTemplateServiceForTest(shared.NewInMemPlanRepository())
+	a := NewProjectTemplateRestHandlers(&shared.Config{}, s)
+
+	r, _ := http.NewRequest("GET", "/api/project-templates", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}))
+
+	a.HandleGetProjectTemplates()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	var templatesModel projectTemplatesModel
+	err := json.NewDecoder(httpRec.Body).Decode(&templatesModel)
+	is.NoErr(err)
+	is.Equal(len(templatesModel.Embedded.ProjectTemplateModels), len(builtInProjectTemplates))
+	is.True(templatesModel.Embedded.ProjectTemplateModels[0].BuiltIn)
+}
+
+func TestHandleCreateProjectTemplateNotValid(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	s, _ := newProjectTemplateServiceForTest(shared.NewInMemPlanRepository())
+	a := NewProjectTemplateRestHandlers(&shared.Config{}, s)
+
+	r, _ := http.NewRequest("POST", "/api/project-templates", strings.NewReader(`{"name": "Agency", "tasks": [""]}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleCreateProjectTemplate()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleCreateProjectFromTemplate(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	s, _ := newProjectTemplateServiceForTest(shared.NewInMemPlanRepository())
+	a := NewProjectTemplateRestHandlers(&shared.Config{}, s)
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+				OrganizationID: shared.OrganizationIDSample,
+				Username:       "admin",
+				Roles:          []string{"ROLE_ADMIN"},
+			})))
+		})
+	})
+	a.RegisterProtected(router)
+
+	r, _ := http.NewRequest("POST", "/project-templates/"+builtInProjectTemplates[2].ID.String()+"/projects", strings.NewReader(`{"title": "Back Office"}`))
+
+	router.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	var projectsModel projectsFromTemplateModel
+	err := json.NewDecoder(httpRec.Body).Decode(&projectsModel)
+	is.NoErr(err)
+	is.Equal(len(projectsModel.Embedded.ProjectModels), 3)
+	is.Equal(projectsModel.Embedded.ProjectModels[1].Title, "Back Office / Administration")
+	is.True(!projectsModel.Embedded.ProjectModels[0].Billable)
+}
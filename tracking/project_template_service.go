@@ -0,0 +1,127 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// ProjectTemplateService manages the project templates of an organization and creates projects from them,
+// the built-in templates are available to every organization
+type ProjectTemplateService struct {
+	repositoryTxer            shared.RepositoryTxer
+	projectTemplateRepository ProjectTemplateRepository
+	projectService            *ProjectService
+}
+
+// NewProjectTemplateService creates a new service for project templates
+func NewProjectTemplateService(repositoryTxer shared.RepositoryTxer, projectTemplateRepository ProjectTemplateRepository, projectService *ProjectService) *ProjectTemplateService {
+	return &ProjectTemplateService{
+		repositoryTxer:            repositoryTxer,
+		projectTemplateRepository: projectTemplateRepository,
+		projectService:            projectService,
+	}
+}
+
+// ReadProjectTemplates reads the built-in templates followed by the templates of the organization
+func (s *ProjectTemplateService) ReadProjectTemplates(ctx context.Context, principal *shared.Principal) ([]*ProjectTemplate, error) {
+	templates, err := s.projectTemplateRepository.FindProjectTemplates(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	allTemplates := make([]*ProjectTemplate, 0, len(builtInProjectTemplates)+len(templates))
+	for _, template := range builtInProjectTemplates {
+		allTemplates = append(allTemplates, copyProjectTemplate(template))
+	}
+	return append(allTemplates, templates...), nil
+}
+
+// ReadProjectTemplate reads a built-in template or a template of the organization
+func (s *ProjectTemplateService) ReadProjectTemplate(ctx context.Context, principal *shared.Principal, templateID uuid.UUID) (*ProjectTemplate, error) {
+	if template, ok := BuiltInProjectTemplateOf(templateID); ok {
+		return copyProjectTemplate(template), nil
+	}
+	return s.projectTemplateRepository.FindProjectTemplateByID(ctx, principal.OrganizationID, templateID)
+}
+
+// CreateProjectTemplate adds a template to the organization
+func (s *ProjectTemplateService) CreateProjectTemplate(ctx context.Context, principal *shared.Principal, template *ProjectTemplate) (*ProjectTemplate, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	template.ID = uuid.New()
+	template.OrganizationID = principal.OrganizationID
+	template.BuiltIn = false
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.projectTemplateRepository.InsertProjectTemplate(ctx, template)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// UpdateProjectTemplate changes a template of the organization, built-in templates cannot be changed
+func (s *ProjectTemplateService) UpdateProjectTemplate(ctx context.Context, principal *shared.Principal, template *ProjectTemplate) (*ProjectTemplate, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	if _, ok := BuiltInProjectTemplateOf(template.ID); ok {
+		return nil, ErrProjectTemplateBuiltIn
+	}
+
+	template.OrganizationID = principal.OrganizationID
+	template.BuiltIn = false
+
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.projectTemplateRepository.UpdateProjectTemplate(ctx, template)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// DeleteProjectTemplate removes a template of the organization, built-in templates cannot be removed
+func (s *ProjectTemplateService) DeleteProjectTemplate(ctx context.Context, principal *shared.Principal, templateID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	if _, ok := BuiltInProjectTemplateOf(templateID); ok {
+		return ErrProjectTemplateBuiltIn
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.projectTemplateRepository.DeleteProjectTemplate(ctx, principal.OrganizationID, templateID)
+		},
+	)
+}
+
+// CreateProjectFromTemplate creates a project with the title from the template, the first of the
+// created projects is the project itself followed by the sub-projects for the tasks
+func (s *ProjectTemplateService) CreateProjectFromTemplate(ctx context.Context, principal *shared.Principal, templateID uuid.UUID, title, status string) ([]*Project, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	template, err := s.ReadProjectTemplate(ctx, principal, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.projectService.CreateProjectsFromTemplate(ctx, principal, template, title, status)
+}
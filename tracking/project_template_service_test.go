@@ -0,0 +1,100 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newProjectTemplateServiceForTest(planRepository *shared.InMemPlanRepository) (*ProjectTemplateService, *InMemProjectRepository) {
+	projectRepository := NewInMemProjectRepository()
+	projectService := NewProjectService(
+		shared.NewInMemRepositoryTxer(),
+		projectRepository,
+		shared.NewPlanService(&shared.Config{}, planRepository),
+	)
+	return NewProjectTemplateService(shared.NewInMemRepositoryTxer(), NewInMemProjectTemplateRepository(), projectService), projectRepository
+}
+
+func TestProjectTemplates(t *testing.T) {
+	is := is.New(t)
+
+	s, _ := newProjectTemplateServiceForTest(shared.NewInMemPlanRepository())
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := s.CreateProjectTemplate(context.Background(), user, &ProjectTemplate{Name: "Agency"})
+	is.Equal(err, shared.ErrForbidden)
+
+	template, err := s.CreateProjectTemplate(context.Background(), admin, &ProjectTemplate{Name: "Agency", Tasks: []string{"Design"}})
+	is.NoErr(err)
+
+	t.Run("ReadProjectTemplates", func(t *testing.T) {
+		templates, err := s.ReadProjectTemplates(context.Background(), user)
+		is.NoErr(err)
+		is.Equal(len(templates), len(builtInProjectTemplates)+1)
+		is.True(templates[0].BuiltIn)
+	})
+
+	t.Run("UpdateBuiltInTemplate", func(t *testing.T) {
+		_, err := s.UpdateProjectTemplate(context.Background(), admin, &ProjectTemplate{ID: builtInProjectTemplates[0].ID, Name: "Changed"})
+		is.Equal(err, ErrProjectTemplateBuiltIn)
+
+		err = s.DeleteProjectTemplate(context.Background(), admin, builtInProjectTemplates[0].ID)
+		is.Equal(err, ErrProjectTemplateBuiltIn)
+	})
+
+	t.Run("UpdateTemplate", func(t *testing.T) {
+		_, err := s.UpdateProjectTemplate(context.Background(), admin, &ProjectTemplate{ID: template.ID, Name: "Agency", Tasks: []string{"Design", "Copy"}})
+		is.NoErr(err)
+
+		updated, err := s.ReadProjectTemplate(context.Background(), user, template.ID)
+		is.NoErr(err)
+		is.Equal(len(updated.Tasks), 2)
+	})
+
+	err = s.DeleteProjectTemplate(context.Background(), admin, template.ID)
+	is.NoErr(err)
+
+	_, err = s.ReadProjectTemplate(context.Background(), admin, template.ID)
+	is.Equal(err, ErrProjectTemplateNotFound)
+}
+
+func TestCreateProjectFromTemplate(t *testing.T) {
+	is := is.New(t)
+
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+
+	t.Run("BuiltInTemplate", func(t *testing.T) {
+		s, projectRepository := newProjectTemplateServiceForTest(shared.NewInMemPlanRepository())
+		projectsBefore := len(projectRepository.projects)
+
+		projects, err := s.CreateProjectFromTemplate(context.Background(), admin, builtInProjectTemplates[1].ID, "ACME Consulting", "")
+		is.NoErr(err)
+		is.Equal(len(projects), 1+len(builtInProjectTemplates[1].Tasks))
+		is.Equal(projects[0].BudgetMinutes, builtInProjectTemplates[1].BudgetMinutes)
+		is.Equal(projects[0].Status, ProjectStatusActive)
+		is.Equal(len(projectRepository.projects), projectsBefore+len(projects))
+	})
+
+	t.Run("BeyondPlanLimit", func(t *testing.T) {
+		planRepository := shared.NewInMemPlanRepository()
+		planRepository.SetPlanOfOrganization(shared.OrganizationIDSample, shared.PlanFree)
+		s, projectRepository := newProjectTemplateServiceForTest(planRepository)
+		projectsBefore := len(projectRepository.projects)
+
+		_, err := s.CreateProjectFromTemplate(context.Background(), admin, builtInProjectTemplates[0].ID, "ACME Web", "")
+		is.True(errors.Is(err, shared.ErrPlanLimitExceeded))
+		is.Equal(len(projectRepository.projects), projectsBefore)
+	})
+
+	t.Run("AsUser", func(t *testing.T) {
+		s, _ := newProjectTemplateServiceForTest(shared.NewInMemPlanRepository())
+
+		_, err := s.CreateProjectFromTemplate(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample}, builtInProjectTemplates[0].ID, "ACME Web", "")
+		is.Equal(err, shared.ErrForbidden)
+	})
+}
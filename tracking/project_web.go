@@ -23,6 +23,7 @@ type projectFormModel struct {
 	CSRFToken string
 	ID        string
 	Title     string ` validate:"required,min=3,max=50"`
+	Billable  bool
 }
 
 type ProjectWeb struct {
@@ -43,6 +44,7 @@ func (a *ProjectWeb) RegisterProtected(r chi.Router) {
 	r.Get("/projects", a.HandleProjectsPage())
 	r.Post("/projects/new", a.HandleProjectForm())
 	r.Get("/projects/{project-id}/archive", a.HandleArchiveProject())
+	r.Post("/projects/{project-id}/status/{status}", a.HandleChangeProjectStatus())
 	r.Get("/projects/{project-id}", a.HandleProjectView())
 	r.Get("/projects/{project-id}/edit", a.HandleProjectEdit())
 	r.Post("/projects/{project-id}/edit", a.HandleProjectEditForm())
@@ -71,6 +73,8 @@ func (a *ProjectWeb) HandleProjectsPage() http.HandlerFunc {
 			pageContext := &shared.PageContext{
 				Principal:   principal,
 				CurrentPath: r.URL.Path,
+				CSRFToken:   csrf.Token(r),
+				Appearance:  shared.AppearanceOf(r.Context()),
 				Title:       "Projects",
 			}
 
@@ -158,6 +162,7 @@ func (a *ProjectWeb) HandleProjectEditForm() http.HandlerFunc {
 	isProduction := a.config.IsProduction()
 	validator := validator.New()
 	projectService := a.projectService
+	projectRepository := a.projectRepository
 	return func(w http.ResponseWriter, r *http.Request) {
 		projectIDParam := chi.URLParam(r, "project-id")
 		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
@@ -192,15 +197,22 @@ func (a *ProjectWeb) HandleProjectEditForm() http.HandlerFunc {
 			return
 		}
 
-		projectToUpdate := mapFormToProject(formModel)
-		projectToUpdate.ID = projectID
-		_, err = projectService.UpdateProject(r.Context(), principal.OrganizationID, &projectToUpdate)
+		projectToUpdate, err := projectRepository.FindProjectByID(r.Context(), principal.OrganizationID, projectID)
 		if err != nil {
 			shared.RenderProblemHTML(w, isProduction, err)
 			return
 		}
 
-		shared.RenderHTML(w, ProjectRow(principal, &projectToUpdate))
+		projectOfForm := mapFormToProject(formModel)
+		projectToUpdate.Title = projectOfForm.Title
+		projectToUpdate.Billable = projectOfForm.Billable
+		_, err = projectService.UpdateProject(r.Context(), principal, projectToUpdate)
+		if err != nil {
+			shared.RenderProblemHTML(w, isProduction, err)
+			return
+		}
+
+		shared.RenderHTML(w, ProjectRow(principal, projectToUpdate))
 
 		if err != nil {
 			shared.RenderProblemHTML(w, isProduction, err)
@@ -299,7 +311,7 @@ func (a *ProjectWeb) HandleArchiveProject() http.HandlerFunc {
 			return
 		}
 
-		err = projectService.ArchiveProject(r.Context(), principal.OrganizationID, projectID)
+		err = projectService.ArchiveProject(r.Context(), principal, projectID)
 		if errors.Is(err, ErrProjectNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 			return
@@ -313,6 +325,40 @@ func (a *ProjectWeb) HandleArchiveProject() http.HandlerFunc {
 	}
 }
 
+// HandleChangeProjectStatus changes the status of a project, e.g. puts it on hold
+func (a *ProjectWeb) HandleChangeProjectStatus() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	projectService := a.projectService
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusNotAcceptable)
+			return
+		}
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		project, err := projectService.ChangeProjectStatus(r.Context(), principal, projectID, chi.URLParam(r, "status"))
+		if errors.Is(err, ErrProjectNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			shared.RenderProblemHTML(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("HX-Trigger", "baralga__projects-changed")
+		shared.RenderHTML(w, ProjectRow(principal, project))
+	}
+}
+
 func (a *ProjectWeb) renderProjectsView(w http.ResponseWriter, r *http.Request, principal *shared.Principal, isProduction bool, formModel projectFormModel) error {
 	pageParams := &paged.PageParams{
 		Page: 0,
@@ -335,7 +381,9 @@ func ProjectsPage(pageContext *shared.PageContext, formModel projectFormModel, p
 	return shared.Page(
 		pageContext.Title,
 		pageContext.CurrentPath,
+		pageContext.Appearance,
 		[]g.Node{
+			shared.CSRFHeaders(pageContext.CSRFToken),
 			shared.Navbar(pageContext),
 			Section(
 				Class("full-center"),
@@ -398,6 +446,24 @@ func ProjectRow(principal *shared.Principal, project *Project) g.Node {
 					Span(
 						Class("flex-grow-1"),
 						g.Text(project.Title),
+						g.If(
+							project.Status != ProjectStatusActive,
+							Span(
+								Class("badge rounded-pill bg-secondary fw-normal ms-2"),
+								g.Text(projectStatusTitle(project.Status)),
+							),
+						),
+					),
+					g.If(
+						principal.HasRole("ROLE_ADMIN"),
+						g.Group(g.Map(projectStatusActionsOf(project), func(status string) g.Node {
+							return A(
+								ghx.Post(fmt.Sprintf("/projects/%v/status/%v", project.ID, status)),
+								Class("btn btn-outline-secondary btn-sm ms-1"),
+								TitleAttr(projectStatusTitle(status)),
+								I(Class(projectStatusIcon(status))),
+							)
+						})),
 					),
 					g.If(
 						principal.HasRole("ROLE_ADMIN"),
@@ -490,6 +556,19 @@ func ProjectForm(formModel projectFormModel, editMode bool, errorMessage string)
 				Class("form-control"),
 				g.Attr("placeholder", "My new Project"),
 			),
+			Div(
+				Class("input-group-text"),
+				TitleAttr("Billable"),
+				Input(
+					ID("ProjectBillable"),
+					Type("checkbox"),
+					Name("Billable"),
+					Value("true"),
+					Class("form-check-input mt-0"),
+					g.If(formModel.Billable, g.Attr("checked", "checked")),
+				),
+				I(Class("bi-currency-euro ms-1")),
+			),
 			g.If(
 				editMode,
 				g.Group(
@@ -525,14 +604,47 @@ func ProjectForm(formModel projectFormModel, editMode bool, errorMessage string)
 
 func mapFormToProject(projectFormModel projectFormModel) Project {
 	return Project{
-		Title:  projectFormModel.Title,
-		Active: true,
+		Title:    projectFormModel.Title,
+		Billable: projectFormModel.Billable,
+	}
+}
+
+func projectStatusTitle(status string) string {
+	switch status {
+	case ProjectStatusProposed:
+		return "Proposed"
+	case ProjectStatusActive:
+		return "Active"
+	case ProjectStatusOnHold:
+		return "On hold"
+	case ProjectStatusDone:
+		return "Done"
+	}
+	return status
+}
+
+// projectStatusActionsOf are the statuses an admin may change the project to, done is offered as archive
+func projectStatusActionsOf(project *Project) []string {
+	var statuses []string
+	for _, status := range projectStatusTransitions[project.Status] {
+		if status != ProjectStatusDone {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+func projectStatusIcon(status string) string {
+	if status == ProjectStatusOnHold {
+		return "bi-pause"
 	}
+	return "bi-play"
 }
 
 func mapProjectToForm(project Project) projectFormModel {
 	return projectFormModel{
-		ID:    project.ID.String(),
-		Title: project.Title,
+		ID:       project.ID.String(),
+		Title:    project.Title,
+		Billable: project.Billable,
 	}
 }
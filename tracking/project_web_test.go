@@ -45,8 +45,9 @@ func TestHandleProjectsPageAsAdmin(t *testing.T) {
 
 	r, _ := http.NewRequest("GET", "/projects", nil)
 	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
-		Username: "admin",
-		Roles:    []string{"ROLE_ADMIN"},
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
 	}))
 
 	a.HandleProjectsPage()(httpRec, r)
@@ -96,6 +97,7 @@ func TestHandleCreateProjectWithValidProject(t *testing.T) {
 		config:            &shared.Config{},
 		projectRepository: repo,
 		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
 			repositoryTxer:    shared.NewInMemRepositoryTxer(),
 			projectRepository: repo,
 		},
@@ -183,13 +185,15 @@ func TestHandleArchiveProjectAsAdmin(t *testing.T) {
 		config:            &shared.Config{},
 		projectRepository: repo,
 		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
 			repositoryTxer:    shared.NewInMemRepositoryTxer(),
 			projectRepository: repo,
 		},
 	}
 	r, _ := http.NewRequest("POST", fmt.Sprintf("/projects/%v/archive", shared.ProjectIDSample), nil)
 	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
-		Roles: []string{"ROLE_ADMIN"},
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
 	}))
 
 	rctx := chi.NewRouteContext()
@@ -210,6 +214,7 @@ func TestHandleArchiveProjectAsUser(t *testing.T) {
 		config:            &shared.Config{},
 		projectRepository: repo,
 		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
 			repositoryTxer:    shared.NewInMemRepositoryTxer(),
 			projectRepository: repo,
 		},
@@ -228,6 +233,70 @@ func TestHandleArchiveProjectAsUser(t *testing.T) {
 	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
 }
 
+func TestHandleChangeProjectStatusAsAdmin(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	repo := NewInMemProjectRepository()
+
+	w := &ProjectWeb{
+		config:            &shared.Config{},
+		projectRepository: repo,
+		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+			repositoryTxer:    shared.NewInMemRepositoryTxer(),
+			projectRepository: repo,
+		},
+	}
+	r, _ := http.NewRequest("POST", fmt.Sprintf("/projects/%v/status/%v", shared.ProjectIDSample, ProjectStatusOnHold), nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
+	rctx.URLParams.Add("status", ProjectStatusOnHold)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	w.HandleChangeProjectStatus()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	htmlBody := httpRec.Body.String()
+	is.True(strings.Contains(htmlBody, "On hold"))
+	is.True(strings.Contains(htmlBody, "bi-play"))
+	is.Equal(repo.projects[0].Status, ProjectStatusOnHold)
+}
+
+func TestHandleChangeProjectStatusAsUser(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	repo := NewInMemProjectRepository()
+
+	w := &ProjectWeb{
+		config:            &shared.Config{},
+		projectRepository: repo,
+		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+			repositoryTxer:    shared.NewInMemRepositoryTxer(),
+			projectRepository: repo,
+		},
+	}
+	r, _ := http.NewRequest("POST", fmt.Sprintf("/projects/%v/status/%v", shared.ProjectIDSample, ProjectStatusOnHold), nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		Roles: []string{"ROLE_USER"},
+	}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
+	rctx.URLParams.Add("status", ProjectStatusOnHold)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	w.HandleChangeProjectStatus()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
+
 func TestHandleProjectViewAsUser(t *testing.T) {
 	is := is.New(t)
 	httpRec := httptest.NewRecorder()
@@ -238,7 +307,9 @@ func TestHandleProjectViewAsUser(t *testing.T) {
 	}
 
 	r, _ := http.NewRequest("GET", fmt.Sprintf("/projects/%s", shared.ProjectIDSample.String()), nil)
-	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
 
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
@@ -282,8 +353,9 @@ func TestHandleProjectEditAsAdmin(t *testing.T) {
 
 	r, _ := http.NewRequest("GET", fmt.Sprintf("/projects/%s/edit", shared.ProjectIDSample.String()), nil)
 	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
-		Username: "admin",
-		Roles:    []string{"ROLE_ADMIN"},
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
 	}))
 
 	rctx := chi.NewRouteContext()
@@ -326,6 +398,7 @@ func TestHandleProjectEditFormAsAdmin(t *testing.T) {
 		config:            &shared.Config{},
 		projectRepository: projectRepository,
 		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
 			repositoryTxer:    shared.NewInMemRepositoryTxer(),
 			projectRepository: projectRepository,
 		},
@@ -339,8 +412,9 @@ func TestHandleProjectEditFormAsAdmin(t *testing.T) {
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
-		Username: "admin",
-		Roles:    []string{"ROLE_ADMIN"},
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
 	}))
 
 	rctx := chi.NewRouteContext()
@@ -352,4 +426,44 @@ func TestHandleProjectEditFormAsAdmin(t *testing.T) {
 
 	htmlBody := httpRec.Body.String()
 	is.True(strings.Contains(htmlBody, "card"))
-}
\ No newline at end of file
+}
+func TestHandleCreateProjectWithCSRFProtection(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{CSRFSecret: "CSRFsecret-of-32-bytes-for-tests"}
+	repo := NewInMemProjectRepository()
+	w := &ProjectWeb{
+		config:            config,
+		projectRepository: repo,
+		projectService: &ProjectService{
+			planService:       shared.NewPlanService(&shared.Config{}, shared.NewInMemPlanRepository()),
+			repositoryTxer:    shared.NewInMemRepositoryTxer(),
+			projectRepository: repo,
+		},
+	}
+	handler := shared.CSRFMiddleware(config)(w.HandleProjectForm())
+
+	countBefore := len(repo.projects)
+
+	newRequest := func() *http.Request {
+		data := url.Values{}
+		data["Title"] = []string{"My new Title"}
+
+		r, _ := http.NewRequest("POST", "/projects/new", strings.NewReader(data.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			Roles: []string{"ROLE_ADMIN"},
+		}))
+	}
+
+	httpRec := httptest.NewRecorder()
+	handler.ServeHTTP(httpRec, newRequest())
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	is.Equal(countBefore, len(repo.projects))
+
+	httpRec = httptest.NewRecorder()
+	handler.ServeHTTP(httpRec, shared.WithTestCSRFToken(config, newRequest()))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(countBefore+1, len(repo.projects))
+}
@@ -0,0 +1,264 @@
+package tracking
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// quickAddDefaultStartHour is the start of activities on past days without time range
+const quickAddDefaultStartHour = 9
+
+var ErrQuickAddProjectNotFound = shared.NewDomainError("quick-add:project-not-found", http.StatusBadRequest, "no project matches the tag of the quick add")
+
+// quickAddMessageSeparators separate the duration and project from the description in messages like "3h Project X — workshop prep"
+var quickAddMessageSeparators = []string{" — ", " – ", " - ", ": "}
+
+var (
+	quickAddDurationPattern  = regexp.MustCompile(`^(\d+([.,]\d+)?h)?(\d+m(in)?)?$`)
+	quickAddTimeRangePattern = regexp.MustCompile(`^(\d{1,2})(:(\d{2}))?-(\d{1,2})(:(\d{2}))?$`)
+)
+
+// QuickAdd is an activity parsed from a quick add text like "2h #website fixing login bug yesterday"
+type QuickAdd struct {
+	Text        string
+	Start       time.Time
+	End         time.Time
+	Description string
+	ProjectTag  string
+	ProjectID   uuid.UUID
+}
+
+// ParseQuickAdd parses the duration or time range, the project tag, the date and the description of a quick add text,
+// the date is relative to now
+func ParseQuickAdd(text string, now time.Time) (*QuickAdd, error) {
+	quickAdd := &QuickAdd{Text: text}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	date := today
+
+	var duration time.Duration
+	var start, end *time.Duration
+	var dateFound bool
+	var description []string
+	for _, word := range strings.Fields(text) {
+		lowerWord := strings.ToLower(word)
+
+		if strings.HasPrefix(word, "#") && len(word) > 1 && quickAdd.ProjectTag == "" {
+			quickAdd.ProjectTag = word[1:]
+			continue
+		}
+
+		if duration == 0 && lowerWord != "" && quickAddDurationPattern.MatchString(lowerWord) {
+			d, err := parseQuickAddDuration(lowerWord)
+			if err != nil {
+				return nil, err
+			}
+			duration = d
+			continue
+		}
+
+		if start == nil {
+			if s, e, ok := parseQuickAddTimeRange(lowerWord); ok {
+				start, end = &s, &e
+				continue
+			}
+		}
+
+		if !dateFound {
+			if d, ok := parseQuickAddDate(lowerWord, today); ok {
+				date = d
+				dateFound = true
+				continue
+			}
+		}
+
+		description = append(description, word)
+	}
+
+	quickAdd.Description = strings.Join(description, " ")
+
+	switch {
+	case start != nil:
+		if duration != 0 && duration != *end-*start {
+			return nil, shared.NewInvalidParam("text", "duration", "duration does not match the time range")
+		}
+		quickAdd.Start = date.Add(*start)
+		quickAdd.End = date.Add(*end)
+	case duration == 0:
+		return nil, shared.NewInvalidParam("text", "duration", "must contain a duration like 2h or 1h30m or a time range like 9:00-11:00")
+	case date.Equal(today):
+		quickAdd.End = now.Truncate(time.Minute)
+		quickAdd.Start = quickAdd.End.Add(-duration)
+		if quickAdd.Start.Before(today) {
+			quickAdd.Start = today
+			quickAdd.End = today.Add(duration)
+		}
+	default:
+		quickAdd.Start = date.Add(quickAddDefaultStartHour * time.Hour)
+		quickAdd.End = quickAdd.Start.Add(duration)
+	}
+
+	if quickAdd.End.Sub(quickAdd.Start) > 24*time.Hour {
+		return nil, shared.NewInvalidParam("text", "duration", "must not be longer than 24h")
+	}
+
+	return quickAdd, nil
+}
+
+// MatchesProject checks whether the project tag matches the title of the project,
+// ignoring case, spaces and punctuation like #acme-relaunch for "ACME Relaunch"
+func (q *QuickAdd) MatchesProject(project *Project) bool {
+	return q.ProjectTag != "" && normalizedQuickAddTag(q.ProjectTag) == normalizedQuickAddTag(project.Title)
+}
+
+// DurationMinutes is the duration of the quick add in minutes
+func (q *QuickAdd) DurationMinutes() int {
+	return int(q.End.Sub(q.Start).Minutes())
+}
+
+// Activity is the activity of the quick add
+func (q *QuickAdd) Activity() *Activity {
+	return &Activity{
+		Start:       q.Start,
+		End:         q.End,
+		Description: q.Description,
+		ProjectID:   q.ProjectID,
+	}
+}
+
+func parseQuickAddDuration(word string) (time.Duration, error) {
+	duration, err := time.ParseDuration(strings.TrimSuffix(strings.ReplaceAll(word, ",", "."), "in"))
+	if err != nil || duration <= 0 {
+		return 0, shared.NewInvalidParam("text", "duration", fmt.Sprintf("duration %s is not valid like 2h or 1h30m", word))
+	}
+	return duration.Truncate(time.Minute), nil
+}
+
+func parseQuickAddTimeRange(word string) (time.Duration, time.Duration, bool) {
+	matches := quickAddTimeRangePattern.FindStringSubmatch(word)
+	if matches == nil {
+		return 0, 0, false
+	}
+
+	start, ok := quickAddTimeOfDay(matches[1], matches[3])
+	if !ok {
+		return 0, 0, false
+	}
+	end, ok := quickAddTimeOfDay(matches[4], matches[6])
+	if !ok || end <= start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+func quickAddTimeOfDay(hours, minutes string) (time.Duration, bool) {
+	h, _ := strconv.Atoi(hours)
+	m := 0
+	if minutes != "" {
+		m, _ = strconv.Atoi(minutes)
+	}
+	if h > 24 || m > 59 || (h == 24 && m > 0) {
+		return 0, false
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, true
+}
+
+// parseQuickAddDate parses today, yesterday, a weekday of the last 7 days or a date like 2021-12-31
+func parseQuickAddDate(word string, today time.Time) (time.Time, bool) {
+	switch word {
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	}
+
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if word == strings.ToLower(weekday.String()) {
+			daysAgo := (int(today.Weekday()) - int(weekday) + 7) % 7
+			return today.AddDate(0, 0, -daysAgo), true
+		}
+	}
+
+	date, err := time_utils.ParseDate(word)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, today.Location()), true
+}
+
+func normalizedQuickAddTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, tag)
+}
+
+// quickAddTextOfMessage converts a time entry sent by mail or message like "3h Project X — workshop prep" into the quick add text
+// "3h #Project-X workshop prep", the words before the separator which are no duration, time range or date
+// are the title of the project. Lines without separator or with a tag are quick add texts already.
+func quickAddTextOfMessage(line string, today time.Time) string {
+	if strings.Contains(line, "#") {
+		return line
+	}
+
+	var head, description string
+	for _, separator := range quickAddMessageSeparators {
+		if h, d, ok := strings.Cut(line, separator); ok {
+			head, description = h, d
+			break
+		}
+	}
+	if head == "" {
+		return line
+	}
+
+	var timing, project []string
+	for _, word := range strings.Fields(head) {
+		lowerWord := strings.ToLower(word)
+		if _, _, ok := parseQuickAddTimeRange(lowerWord); ok || quickAddDurationPattern.MatchString(lowerWord) {
+			timing = append(timing, word)
+			continue
+		}
+		if _, ok := parseQuickAddDate(lowerWord, today); ok {
+			timing = append(timing, word)
+			continue
+		}
+		project = append(project, word)
+	}
+
+	words := timing
+	if len(project) > 0 {
+		words = append(words, "#"+strings.Join(project, "-"))
+	}
+	if strings.TrimSpace(description) != "" {
+		words = append(words, strings.TrimSpace(description))
+	}
+	return strings.Join(words, " ")
+}
+
+// quickAddErrorMessage is the message of an error of a quick add for the user,
+// internal errors have no message for the user and are returned
+func quickAddErrorMessage(err error) (string, error) {
+	var invalidParam *shared.InvalidParam
+	if errors.As(err, &invalidParam) {
+		return invalidParam.Message, nil
+	}
+
+	domainError := shared.DomainErrorOf(err)
+	if domainError == shared.ErrInternal {
+		return "", err
+	}
+	return domainError.Title, nil
+}
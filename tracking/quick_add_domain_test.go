@@ -0,0 +1,92 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	// Wednesday
+	now := time.Date(2021, 12, 15, 16, 30, 42, 0, time.UTC)
+
+	t.Run("duration, tag, description and date", func(t *testing.T) {
+		is := is.New(t)
+
+		quickAdd, err := ParseQuickAdd("2h #website fixing login bug yesterday", now)
+		is.NoErr(err)
+		is.Equal(quickAdd.ProjectTag, "website")
+		is.Equal(quickAdd.Description, "fixing login bug")
+		is.Equal(quickAdd.Start, time.Date(2021, 12, 14, 9, 0, 0, 0, time.UTC))
+		is.Equal(quickAdd.End, time.Date(2021, 12, 14, 11, 0, 0, 0, time.UTC))
+		is.Equal(quickAdd.DurationMinutes(), 120)
+	})
+
+	t.Run("today ends now", func(t *testing.T) {
+		is := is.New(t)
+
+		quickAdd, err := ParseQuickAdd("1h30m code review", now)
+		is.NoErr(err)
+		is.Equal(quickAdd.ProjectTag, "")
+		is.Equal(quickAdd.Description, "code review")
+		is.Equal(quickAdd.Start, time.Date(2021, 12, 15, 15, 0, 0, 0, time.UTC))
+		is.Equal(quickAdd.End, time.Date(2021, 12, 15, 16, 30, 0, 0, time.UTC))
+	})
+
+	t.Run("time range on weekday", func(t *testing.T) {
+		is := is.New(t)
+
+		quickAdd, err := ParseQuickAdd("monday 9:30-11 #acme-relaunch planning", now)
+		is.NoErr(err)
+		is.Equal(quickAdd.Start, time.Date(2021, 12, 13, 9, 30, 0, 0, time.UTC))
+		is.Equal(quickAdd.End, time.Date(2021, 12, 13, 11, 0, 0, 0, time.UTC))
+		is.Equal(quickAdd.Description, "planning")
+		is.True(quickAdd.MatchesProject(&Project{Title: "ACME Relaunch"}))
+		is.True(!quickAdd.MatchesProject(&Project{Title: "ACME"}))
+	})
+
+	t.Run("decimal duration on date", func(t *testing.T) {
+		is := is.New(t)
+
+		quickAdd, err := ParseQuickAdd("1,5h 2021-12-01 support", now)
+		is.NoErr(err)
+		is.Equal(quickAdd.Start, time.Date(2021, 12, 1, 9, 0, 0, 0, time.UTC))
+		is.Equal(quickAdd.DurationMinutes(), 90)
+	})
+
+	t.Run("only first duration and date are parsed", func(t *testing.T) {
+		is := is.New(t)
+
+		quickAdd, err := ParseQuickAdd("45min prepare 2h workshop today", now)
+		is.NoErr(err)
+		is.Equal(quickAdd.DurationMinutes(), 45)
+		is.Equal(quickAdd.Description, "prepare 2h workshop")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		is := is.New(t)
+
+		texts := []string{
+			"",
+			"#website fixing login bug",
+			"25h #website",
+			"2h 9:00-10:00 standup",
+		}
+		for _, text := range texts {
+			_, err := ParseQuickAdd(text, now)
+			is.True(err != nil)
+		}
+	})
+}
+
+func TestQuickAddTextOfMessage(t *testing.T) {
+	is := is.New(t)
+
+	today := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	is.Equal(quickAddTextOfMessage("3h Project X — workshop prep", today), "3h #Project-X workshop prep")
+	is.Equal(quickAddTextOfMessage("9:00-11:30 yesterday Project X - review", today), "9:00-11:30 yesterday #Project-X review")
+	is.Equal(quickAddTextOfMessage("2h — planning", today), "2h planning")
+	is.Equal(quickAddTextOfMessage("1h30m #project-x planning - more", today), "1h30m #project-x planning - more")
+	is.Equal(quickAddTextOfMessage("2h planning", today), "2h planning")
+}
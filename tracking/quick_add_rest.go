@@ -0,0 +1,107 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type quickAddModel struct {
+	Text            string     `json:"text" validate:"required,max=500"`
+	Start           string     `json:"start,omitempty"`
+	End             string     `json:"end,omitempty"`
+	DurationMinutes int        `json:"durationMinutes,omitempty"`
+	Description     string     `json:"description,omitempty"`
+	ProjectTag      string     `json:"projectTag,omitempty"`
+	ProjectID       string     `json:"projectId,omitempty"`
+	Links           *hal.Links `json:"_links,omitempty"`
+}
+
+type QuickAddRestHandlers struct {
+	config          *shared.Config
+	quickAddService *QuickAddService
+}
+
+func NewQuickAddRestHandlers(config *shared.Config, quickAddService *QuickAddService) *QuickAddRestHandlers {
+	return &QuickAddRestHandlers{
+		config:          config,
+		quickAddService: quickAddService,
+	}
+}
+
+func (a *QuickAddRestHandlers) RegisterProtected(r chi.Router) {
+	r.Post("/activities/quick-add", a.HandleParseQuickAdd())
+}
+
+func (a *QuickAddRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleParseQuickAdd parses a quick add text like "2h #website fixing login bug yesterday"
+// into an activity, which is returned for confirmation but not created
+func (a *QuickAddRestHandlers) HandleParseQuickAdd() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	quickAddService := a.quickAddService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var quickAddModel quickAddModel
+		err := json.NewDecoder(r.Body).Decode(&quickAddModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "quick add not valid", err)
+			return
+		}
+
+		err = validator.Struct(quickAddModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "quick add not valid", err)
+			return
+		}
+
+		quickAdd, err := quickAddService.ParseQuickAdd(r.Context(), principal, quickAddModel.Text, time.Now())
+		var invalidParam *shared.InvalidParam
+		if errors.As(err, &invalidParam) {
+			shared.RenderValidationProblemJSON(w, "quick add not valid", err)
+			return
+		}
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToQuickAddModel(quickAdd))
+	}
+}
+
+func mapToQuickAddModel(quickAdd *QuickAdd) *quickAddModel {
+	quickAddModel := &quickAddModel{
+		Text:            quickAdd.Text,
+		Start:           time_utils.FormatDateTime(quickAdd.Start),
+		End:             time_utils.FormatDateTime(quickAdd.End),
+		DurationMinutes: quickAdd.DurationMinutes(),
+		Description:     quickAdd.Description,
+		ProjectTag:      quickAdd.ProjectTag,
+	}
+
+	if quickAdd.ProjectID == uuid.Nil {
+		quickAddModel.Links = hal.NewLinks(
+			hal.NewLink("create", "/api/activities"),
+		)
+		return quickAddModel
+	}
+
+	quickAddModel.ProjectID = quickAdd.ProjectID.String()
+	quickAddModel.Links = hal.NewLinks(
+		hal.NewLink("create", "/api/activities"),
+		hal.NewLink("project", fmt.Sprintf("/api/projects/%s", quickAdd.ProjectID)),
+	)
+	return quickAddModel
+}
@@ -0,0 +1,110 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleParseQuickAdd(t *testing.T) {
+	is := is.New(t)
+
+	projectAssignmentRepository := NewInMemProjectAssignmentRepository()
+	a := NewQuickAddRestHandlers(&shared.Config{}, &QuickAddService{
+		actitivityService: &ActitivityService{
+			repositoryTxer:               shared.NewInMemRepositoryTxer(),
+			activityRepository:           NewInMemActivityRepository(),
+			locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+			projectAssignmentRepository:  projectAssignmentRepository,
+			categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+		},
+		projectRepository: NewInMemProjectRepository(),
+	})
+
+	parseQuickAdd := func(body string) *httptest.ResponseRecorder {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/api/activities/quick-add", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+		}))
+		a.HandleParseQuickAdd()(httpRec, r)
+		return httpRec
+	}
+
+	httpRec := parseQuickAdd(`{"text": "2h #myproject fixing login bug yesterday"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	quickAddModel := &quickAddModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(quickAddModel)
+	is.NoErr(err)
+	is.Equal(quickAddModel.ProjectID, shared.ProjectIDSample.String())
+	is.Equal(quickAddModel.Description, "fixing login bug")
+	is.Equal(quickAddModel.DurationMinutes, 120)
+	is.Equal(quickAddModel.Links.HrefOf("create"), "/api/activities")
+
+	httpRec = parseQuickAdd(`{"text": "2h #unknown fixing login bug"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	is.True(strings.Contains(httpRec.Body.String(), "quick-add:project-not-found"))
+
+	httpRec = parseQuickAdd(`{"text": "fixing login bug"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	is.True(strings.Contains(httpRec.Body.String(), `"field":"text"`))
+
+	httpRec = parseQuickAdd(`{"text": ""}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = parseQuickAdd(`{"text": "30m standup"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.True(!strings.Contains(httpRec.Body.String(), "projectId"))
+
+	projectID := shared.ProjectIDSample
+	err = projectAssignmentRepository.UpdateDefaultProject(context.Background(), shared.OrganizationIDSample, "user1", &projectID)
+	is.NoErr(err)
+
+	httpRec = parseQuickAdd(`{"text": "30m standup"}`)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.True(strings.Contains(httpRec.Body.String(), shared.ProjectIDSample.String()))
+}
+
+func TestHandleParseQuickAddWithProjectAlias(t *testing.T) {
+	is := is.New(t)
+
+	projectRepository := NewInMemProjectRepository()
+	err := projectRepository.InsertProjectAlias(context.Background(), &ProjectAlias{
+		OrganizationID: shared.OrganizationIDSample,
+		ProjectID:      shared.ProjectIDSample,
+		Alias:          "WEB",
+	})
+	is.NoErr(err)
+
+	a := NewQuickAddRestHandlers(&shared.Config{}, &QuickAddService{
+		actitivityService: &ActitivityService{
+			repositoryTxer:               shared.NewInMemRepositoryTxer(),
+			activityRepository:           NewInMemActivityRepository(),
+			locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+			projectAssignmentRepository:  NewInMemProjectAssignmentRepository(),
+			categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+		},
+		projectRepository: projectRepository,
+	})
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("POST", "/api/activities/quick-add", strings.NewReader(`{"text": "2h #web fixing login bug"}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+	}))
+	a.HandleParseQuickAdd()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	quickAddModel := &quickAddModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(quickAddModel)
+	is.NoErr(err)
+	is.Equal(quickAddModel.ProjectID, shared.ProjectIDSample.String())
+}
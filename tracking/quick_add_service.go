@@ -0,0 +1,83 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/paged"
+	"github.com/pkg/errors"
+)
+
+// QuickAddService parses quick add texts into activities for confirmation, nothing is stored
+type QuickAddService struct {
+	actitivityService *ActitivityService
+	projectRepository ProjectRepository
+}
+
+// NewQuickAddService creates a new service to parse quick add texts
+func NewQuickAddService(actitivityService *ActitivityService, projectRepository ProjectRepository) *QuickAddService {
+	return &QuickAddService{
+		actitivityService: actitivityService,
+		projectRepository: projectRepository,
+	}
+}
+
+// ParseQuickAdd parses the quick add text, the project is given by its tag, which is an alias
+// or the title of the project, or else assigned by the project assignment rules if possible
+func (s *QuickAddService) ParseQuickAdd(ctx context.Context, principal *shared.Principal, text string, now time.Time) (*QuickAdd, error) {
+	quickAdd, err := ParseQuickAdd(text, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if IsValidProjectAlias(quickAdd.ProjectTag) {
+		project, err := s.projectRepository.FindProjectByAlias(ctx, principal.OrganizationID, quickAdd.ProjectTag)
+		if err != nil && !errors.Is(err, ErrProjectNotFound) {
+			return nil, err
+		}
+		if project != nil {
+			quickAdd.ProjectID = project.ID
+			return quickAdd, nil
+		}
+	}
+
+	if quickAdd.ProjectTag != "" {
+		projectsPaged, err := s.projectRepository.FindProjects(ctx, principal.OrganizationID, &paged.PageParams{Page: 0, Size: 100})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, project := range projectsPaged.Projects {
+			if quickAdd.MatchesProject(project) {
+				quickAdd.ProjectID = project.ID
+				return quickAdd, nil
+			}
+		}
+		return nil, ErrQuickAddProjectNotFound
+	}
+
+	projectAssignment, err := s.actitivityService.projectAssignmentOf(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := quickAdd.Activity()
+	err = projectAssignment.Assign(activity)
+	if err != nil && !errors.Is(err, ErrProjectRequired) {
+		return nil, err
+	}
+	quickAdd.ProjectID = activity.ProjectID
+
+	return quickAdd, nil
+}
+
+// TrackQuickAddMessage creates the activity of a time entry sent by mail or message like "3h Project X — workshop prep"
+func (s *QuickAddService) TrackQuickAddMessage(ctx context.Context, principal *shared.Principal, message string, now time.Time) (*Activity, error) {
+	quickAdd, err := s.ParseQuickAdd(ctx, principal, quickAddTextOfMessage(message, now), now)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.actitivityService.CreateActivityFromSource(ctx, principal, quickAdd.Activity(), ActivitySourceQuickAdd, true)
+}
@@ -0,0 +1,218 @@
+package tracking
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/baralga/shared"
+)
+
+const (
+	ReceiptRecognizerTesseract    = "tesseract"
+	ReceiptRecognizerGoogleVision = "google-vision"
+
+	// maxReceiptSize is the maximum size of uploaded receipt images
+	maxReceiptSize = 10 << 20
+)
+
+var (
+	ErrReceiptRecognitionDisabled = shared.NewDomainError("receipt:recognition-disabled", http.StatusNotFound, "recognition of receipts is not enabled")
+	ErrReceiptImageNotSupported   = shared.NewDomainError("receipt:image-not-supported", http.StatusUnsupportedMediaType, "receipt must be a png, jpeg or tiff image")
+)
+
+// receiptImageContentTypes are the image formats all recognizers support
+var receiptImageContentTypes = []string{"image/png", "image/jpeg", "image/tiff"}
+
+// receiptTotalKeywords mark the line with the total of the receipt, subtotals are skipped
+var (
+	receiptTotalKeywords    = []string{"total", "summe", "gesamt", "betrag", "amount due", "to pay", "zu zahlen"}
+	receiptSubtotalKeywords = []string{"subtotal", "sub-total", "sub total", "zwischensumme", "netto"}
+	receiptTitleKeywords    = []string{"receipt", "invoice", "rechnung", "quittung", "beleg", "kassenbon"}
+)
+
+var (
+	receiptAmountPattern  = regexp.MustCompile(`(\d{1,3}(?:[.,' ]\d{3})+|\d+)[.,](\d{2})\b`)
+	receiptISODatePattern = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	receiptDotDatePattern = regexp.MustCompile(`\b(\d{1,2})\.(\d{1,2})\.(\d{4}|\d{2})\b`)
+	receiptSlashPattern   = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})/(\d{4}|\d{2})\b`)
+)
+
+// receiptCurrencies maps the symbols and codes on receipts to currency codes
+var receiptCurrencies = []struct {
+	Symbol   string
+	Currency string
+}{
+	{"€", "EUR"}, {"EUR", "EUR"},
+	{"CHF", "CHF"},
+	{"£", "GBP"}, {"GBP", "GBP"},
+	{"US$", "USD"}, {"USD", "USD"}, {"$", "USD"},
+}
+
+// TextRecognizer recognizes the text of an image like Tesseract or a cloud api
+type TextRecognizer interface {
+	RecognizeText(ctx context.Context, image io.Reader, contentType string) (string, error)
+}
+
+// ReceiptDraft is an expense recognized on a receipt, which is returned for confirmation
+type ReceiptDraft struct {
+	AmountCents int
+	Currency    string
+	Date        *time.Time
+	Vendor      string
+	Text        string
+}
+
+// ParseReceipt extracts the total amount, the date and the vendor from the recognized text of a receipt,
+// values which are not found are left empty for the user to fill in
+func ParseReceipt(text string) *ReceiptDraft {
+	receiptDraft := &ReceiptDraft{Text: text}
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	receiptDraft.AmountCents = parseReceiptTotal(lines)
+	receiptDraft.Currency = parseReceiptCurrency(text)
+	receiptDraft.Vendor = parseReceiptVendor(lines)
+
+	for _, line := range lines {
+		if date, ok := parseReceiptDate(line); ok {
+			receiptDraft.Date = &date
+			break
+		}
+	}
+
+	return receiptDraft
+}
+
+// parseReceiptTotal takes the largest amount of the lines with the total, or the largest amount of the receipt
+// outside of lines with dates as a date like 04.03.2024 also reads as amount
+func parseReceiptTotal(lines []string) int {
+	total := 0
+	for _, line := range lines {
+		lowerLine := strings.ToLower(line)
+		if !containsAny(lowerLine, receiptTotalKeywords) || containsAny(lowerLine, receiptSubtotalKeywords) {
+			continue
+		}
+		for _, amount := range parseReceiptAmounts(line) {
+			total = max(total, amount)
+		}
+	}
+	if total > 0 {
+		return total
+	}
+
+	for _, line := range lines {
+		if _, ok := parseReceiptDate(line); ok {
+			continue
+		}
+		for _, amount := range parseReceiptAmounts(line) {
+			total = max(total, amount)
+		}
+	}
+	return total
+}
+
+// parseReceiptAmounts parses the amounts with two decimals like 1.234,56 or 1,234.56 in cents
+func parseReceiptAmounts(line string) []int {
+	var amounts []int
+	for _, match := range receiptAmountPattern.FindAllStringSubmatch(line, -1) {
+		units := strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, match[1])
+
+		amount, err := strconv.Atoi(units + match[2])
+		if err != nil {
+			continue
+		}
+		amounts = append(amounts, amount)
+	}
+	return amounts
+}
+
+func parseReceiptCurrency(text string) string {
+	for _, receiptCurrency := range receiptCurrencies {
+		if strings.Contains(text, receiptCurrency.Symbol) {
+			return receiptCurrency.Currency
+		}
+	}
+	return ""
+}
+
+// parseReceiptDate parses dates like 2024-03-04, 04.03.2024 or 03/04/2024, which is read as month first
+// unless the first number can only be a day
+func parseReceiptDate(line string) (time.Time, bool) {
+	if match := receiptISODatePattern.FindStringSubmatch(line); match != nil {
+		return receiptDateOf(match[1], match[2], match[3])
+	}
+	if match := receiptDotDatePattern.FindStringSubmatch(line); match != nil {
+		return receiptDateOf(match[3], match[2], match[1])
+	}
+	if match := receiptSlashPattern.FindStringSubmatch(line); match != nil {
+		if date, ok := receiptDateOf(match[3], match[1], match[2]); ok {
+			return date, true
+		}
+		return receiptDateOf(match[3], match[2], match[1])
+	}
+	return time.Time{}, false
+}
+
+func receiptDateOf(yearText, monthText, dayText string) (time.Time, bool) {
+	year, _ := strconv.Atoi(yearText)
+	month, _ := strconv.Atoi(monthText)
+	day, _ := strconv.Atoi(dayText)
+	if year < 100 {
+		year += 2000
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Year() != year || int(date.Month()) != month || date.Day() != day {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// parseReceiptVendor takes the first line with a name, which is usually the shop on top of the receipt
+func parseReceiptVendor(lines []string) string {
+	for _, line := range lines {
+		lowerLine := strings.ToLower(line)
+		if containsAny(lowerLine, receiptTitleKeywords) || receiptAmountPattern.MatchString(line) {
+			continue
+		}
+		if _, ok := parseReceiptDate(line); ok {
+			continue
+		}
+
+		letters := 0
+		for _, r := range line {
+			if unicode.IsLetter(r) {
+				letters++
+			}
+		}
+		if letters >= 3 {
+			return line
+		}
+	}
+	return ""
+}
+
+func containsAny(text string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestParseReceipt(t *testing.T) {
+	is := is.New(t)
+
+	receiptDraft := ParseReceipt(`
+		Café Central
+		Hauptstraße 1, 10115 Berlin
+		Rechnung Nr. 4711
+		04.03.2024 12:31
+		Cappuccino        3,90
+		Croissant         2,60
+		Zwischensumme     6,50
+		Summe EUR         6,50
+		MwSt 19%          1,04
+	`)
+	is.Equal(receiptDraft.AmountCents, 650)
+	is.Equal(receiptDraft.Currency, "EUR")
+	is.Equal(*receiptDraft.Date, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	is.Equal(receiptDraft.Vendor, "Café Central")
+}
+
+func TestParseReceiptInEnglish(t *testing.T) {
+	is := is.New(t)
+
+	receiptDraft := ParseReceipt(`
+		RECEIPT
+		Office Supplies Inc.
+		03/14/2024
+		Paper             $1,024.50
+		Subtotal          $1,024.50
+		Tax                  $81.96
+		Total             $1,106.46
+	`)
+	is.Equal(receiptDraft.AmountCents, 110646)
+	is.Equal(receiptDraft.Currency, "USD")
+	is.Equal(*receiptDraft.Date, time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC))
+	is.Equal(receiptDraft.Vendor, "Office Supplies Inc.")
+}
+
+func TestParseReceiptWithoutTotal(t *testing.T) {
+	is := is.New(t)
+
+	receiptDraft := ParseReceipt("Bakery\n2024-03-04\nBread 3.20\nCake 12.40\n")
+	is.Equal(receiptDraft.AmountCents, 1240)
+	is.Equal(receiptDraft.Currency, "")
+	is.Equal(*receiptDraft.Date, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	is.Equal(receiptDraft.Vendor, "Bakery")
+}
+
+func TestParseReceiptNotRecognized(t *testing.T) {
+	is := is.New(t)
+
+	receiptDraft := ParseReceipt("")
+	is.Equal(receiptDraft.AmountCents, 0)
+	is.True(receiptDraft.Date == nil)
+	is.Equal(receiptDraft.Vendor, "")
+}
+
+func TestParseReceiptDate(t *testing.T) {
+	is := is.New(t)
+
+	date, ok := parseReceiptDate("Date: 31/12/23")
+	is.True(ok)
+	is.Equal(date, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC))
+
+	_, ok = parseReceiptDate("32.13.2024")
+	is.True(!ok)
+}
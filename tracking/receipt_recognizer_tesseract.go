@@ -0,0 +1,55 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const tesseractTimeout = 30 * time.Second
+
+// TesseractRecognizer recognizes text with the command line of Tesseract installed on the host
+type TesseractRecognizer struct {
+	command   string
+	languages string
+}
+
+var _ TextRecognizer = (*TesseractRecognizer)(nil)
+
+// NewTesseractRecognizer creates a recognizer for the Tesseract command with languages like eng+deu
+func NewTesseractRecognizer(command, languages string) *TesseractRecognizer {
+	return &TesseractRecognizer{
+		command:   command,
+		languages: languages,
+	}
+}
+
+// RecognizeText pipes the image through Tesseract, which reads the image from stdin and writes the text to stdout
+func (r *TesseractRecognizer) RecognizeText(ctx context.Context, image io.Reader, contentType string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tesseractTimeout)
+	defer cancel()
+
+	args := []string{"stdin", "stdout"}
+	if r.languages != "" {
+		args = append(args, "-l", r.languages)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, r.command, args...)
+	cmd.Stdin = image
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "tesseract failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
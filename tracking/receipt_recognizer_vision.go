@@ -0,0 +1,114 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const googleVisionAPIURL = "https://vision.googleapis.com/v1/images:annotate"
+
+// GoogleVisionRecognizer recognizes text with the document text detection of the Google Cloud Vision api
+type GoogleVisionRecognizer struct {
+	apiURL     string
+	apiKey     string
+	httpClient *http.Client
+}
+
+var _ TextRecognizer = (*GoogleVisionRecognizer)(nil)
+
+// NewGoogleVisionRecognizer creates a recognizer for the Google Cloud Vision api with the api key
+func NewGoogleVisionRecognizer(apiKey string) *GoogleVisionRecognizer {
+	return &GoogleVisionRecognizer{
+		apiURL:     googleVisionAPIURL,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type googleVisionRequest struct {
+	Requests []*googleVisionImageRequest `json:"requests"`
+}
+
+type googleVisionImageRequest struct {
+	Image struct {
+		Content string `json:"content"`
+	} `json:"image"`
+	Features []*googleVisionFeature `json:"features"`
+}
+
+type googleVisionFeature struct {
+	Type string `json:"type"`
+}
+
+type googleVisionResponse struct {
+	Responses []struct {
+		FullTextAnnotation *struct {
+			Text string `json:"text"`
+		} `json:"fullTextAnnotation"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"responses"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// RecognizeText sends the image to the api, images without text result in an empty text
+func (r *GoogleVisionRecognizer) RecognizeText(ctx context.Context, image io.Reader, contentType string) (string, error) {
+	content, err := io.ReadAll(image)
+	if err != nil {
+		return "", err
+	}
+
+	imageRequest := &googleVisionImageRequest{
+		Features: []*googleVisionFeature{{Type: "DOCUMENT_TEXT_DETECTION"}},
+	}
+	imageRequest.Image.Content = base64.StdEncoding.EncodeToString(content)
+
+	body, err := json.Marshal(&googleVisionRequest{Requests: []*googleVisionImageRequest{imageRequest}})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.apiURL+"?key="+url.QueryEscape(r.apiKey), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var visionResponse googleVisionResponse
+	err = json.NewDecoder(res.Body).Decode(&visionResponse)
+	if err != nil {
+		return "", errors.Wrapf(err, "google vision responded with status %v", res.StatusCode)
+	}
+	if visionResponse.Error != nil {
+		return "", errors.Errorf("google vision failed: %s", visionResponse.Error.Message)
+	}
+	if len(visionResponse.Responses) == 0 {
+		return "", nil
+	}
+
+	imageResponse := visionResponse.Responses[0]
+	if imageResponse.Error != nil {
+		return "", errors.Errorf("google vision failed: %s", imageResponse.Error.Message)
+	}
+	if imageResponse.FullTextAnnotation == nil {
+		return "", nil
+	}
+	return imageResponse.FullTextAnnotation.Text, nil
+}
@@ -0,0 +1,45 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestGoogleVisionRecognizer(t *testing.T) {
+	is := is.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "key" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": {"message": "API key not valid"}}`))
+			return
+		}
+
+		var visionRequest googleVisionRequest
+		_ = json.NewDecoder(r.Body).Decode(&visionRequest)
+		if len(visionRequest.Requests) != 1 || visionRequest.Requests[0].Image.Content == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"responses": [{"fullTextAnnotation": {"text": "Bakery\nTotal 12,40"}}]}`))
+	}))
+	defer server.Close()
+
+	recognizer := NewGoogleVisionRecognizer("key")
+	recognizer.apiURL = server.URL
+
+	text, err := recognizer.RecognizeText(context.Background(), strings.NewReader("image"), "image/png")
+	is.NoErr(err)
+	is.Equal(text, "Bakery\nTotal 12,40")
+
+	recognizer.apiKey = "other"
+	_, err = recognizer.RecognizeText(context.Background(), strings.NewReader("image"), "image/png")
+	is.True(err != nil)
+}
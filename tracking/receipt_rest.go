@@ -0,0 +1,72 @@
+package tracking
+
+import (
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type receiptDraftModel struct {
+	Amount   float64    `json:"amount,omitempty"`
+	Currency string     `json:"currency,omitempty"`
+	Date     string     `json:"date,omitempty"`
+	Vendor   string     `json:"vendor,omitempty"`
+	Text     string     `json:"text"`
+	Links    *hal.Links `json:"_links"`
+}
+
+type ReceiptRestHandlers struct {
+	config         *shared.Config
+	receiptService *ReceiptService
+}
+
+func NewReceiptRestHandlers(config *shared.Config, receiptService *ReceiptService) *ReceiptRestHandlers {
+	return &ReceiptRestHandlers{
+		config:         config,
+		receiptService: receiptService,
+	}
+}
+
+func (a *ReceiptRestHandlers) RegisterProtected(r chi.Router) {
+	r.Post("/receipts/recognition", a.HandleRecognizeReceipt())
+}
+
+func (a *ReceiptRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleRecognizeReceipt recognizes the amount, date and vendor of the receipt image posted as body,
+// the expense is returned for confirmation but not stored
+func (a *ReceiptRestHandlers) HandleRecognizeReceipt() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	receiptService := a.receiptService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		image := http.MaxBytesReader(w, r.Body, maxReceiptSize)
+		receiptDraft, err := receiptService.RecognizeReceipt(r.Context(), principal, image, r.Header.Get("Content-Type"))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToReceiptDraftModel(receiptDraft))
+	}
+}
+
+func mapToReceiptDraftModel(receiptDraft *ReceiptDraft) *receiptDraftModel {
+	receiptDraftModel := &receiptDraftModel{
+		Amount:   float64(receiptDraft.AmountCents) / 100,
+		Currency: receiptDraft.Currency,
+		Vendor:   receiptDraft.Vendor,
+		Text:     receiptDraft.Text,
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/receipts/recognition"),
+		),
+	}
+	if receiptDraft.Date != nil {
+		receiptDraftModel.Date = receiptDraft.Date.Format("2006-01-02")
+	}
+	return receiptDraftModel
+}
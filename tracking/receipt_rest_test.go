@@ -0,0 +1,52 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleRecognizeReceipt(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewReceiptRestHandlers(&shared.Config{}, newReceiptServiceForTest(&inMemTextRecognizer{}))
+
+	r, _ := http.NewRequest("POST", "/api/receipts/recognition", strings.NewReader("Bakery\n04.03.2024\nTotal 12,40 EUR"))
+	r.Header.Set("Content-Type", "image/jpeg")
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+	}))
+
+	a.HandleRecognizeReceipt()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	var receiptDraft receiptDraftModel
+	err := json.NewDecoder(httpRec.Body).Decode(&receiptDraft)
+	is.NoErr(err)
+	is.Equal(receiptDraft.Amount, 12.40)
+	is.Equal(receiptDraft.Currency, "EUR")
+	is.Equal(receiptDraft.Date, "2024-03-04")
+	is.Equal(receiptDraft.Vendor, "Bakery")
+}
+
+func TestHandleRecognizeReceiptNotSupported(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := NewReceiptRestHandlers(&shared.Config{}, newReceiptServiceForTest(&inMemTextRecognizer{}))
+
+	r, _ := http.NewRequest("POST", "/api/receipts/recognition", strings.NewReader("Bakery"))
+	r.Header.Set("Content-Type", "text/plain")
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleRecognizeReceipt()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusUnsupportedMediaType)
+}
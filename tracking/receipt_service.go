@@ -0,0 +1,72 @@
+package tracking
+
+import (
+	"context"
+	"io"
+	"mime"
+	"slices"
+
+	"github.com/baralga/shared"
+)
+
+// ReceiptService recognizes the expenses on photos or scans of receipts
+type ReceiptService struct {
+	scanService *shared.ScanService
+	recognizer  TextRecognizer
+}
+
+// NewReceiptService creates a new service to recognize receipts, receipts are not recognized if the recognizer is nil
+func NewReceiptService(scanService *shared.ScanService, recognizer TextRecognizer) *ReceiptService {
+	return &ReceiptService{
+		scanService: scanService,
+		recognizer:  recognizer,
+	}
+}
+
+// NewTextRecognizer creates the recognizer of the config, nil if receipts are not recognized
+func NewTextRecognizer(config *shared.Config) TextRecognizer {
+	switch config.ReceiptRecognizer {
+	case ReceiptRecognizerTesseract:
+		return NewTesseractRecognizer(config.ReceiptTesseractCommand, config.ReceiptTesseractLanguages)
+	case ReceiptRecognizerGoogleVision:
+		return NewGoogleVisionRecognizer(config.ReceiptGoogleVisionAPIKey)
+	default:
+		return nil
+	}
+}
+
+// RecognizeReceipt scans the uploaded receipt for malware, recognizes its text and extracts the expense,
+// which is returned for confirmation but not stored
+func (s *ReceiptService) RecognizeReceipt(ctx context.Context, principal *shared.Principal, image io.Reader, contentType string) (*ReceiptDraft, error) {
+	if s.recognizer == nil {
+		return nil, ErrReceiptRecognitionDisabled
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !slices.Contains(receiptImageContentTypes, mediaType) {
+		return nil, ErrReceiptImageNotSupported
+	}
+
+	receipt, err := shared.BufferUpload(image)
+	if err != nil {
+		return nil, err
+	}
+	defer receipt.Close()
+
+	err = s.scanService.ScanUpload(ctx, &shared.Upload{
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		FileName:       "receipt",
+		Content:        receipt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := s.recognizer.RecognizeText(ctx, receipt, mediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseReceipt(text), nil
+}
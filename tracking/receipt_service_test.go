@@ -0,0 +1,71 @@
+package tracking
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+// inMemTextRecognizer recognizes the image as text
+type inMemTextRecognizer struct{}
+
+func (r *inMemTextRecognizer) RecognizeText(ctx context.Context, image io.Reader, contentType string) (string, error) {
+	text, err := io.ReadAll(image)
+	return string(text), err
+}
+
+func newReceiptServiceForTest(recognizer TextRecognizer) *ReceiptService {
+	mailResource := shared.NewInMemMailResource()
+	return NewReceiptService(
+		shared.NewScanService(&shared.Config{}, shared.NewInMemRepositoryTxer(), shared.NewInMemOutbox(mailResource), shared.NewInMemStorage(), shared.NewInMemScanner()),
+		recognizer,
+	)
+}
+
+func TestRecognizeReceipt(t *testing.T) {
+	is := is.New(t)
+
+	s := newReceiptServiceForTest(&inMemTextRecognizer{})
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+
+	receiptDraft, err := s.RecognizeReceipt(context.Background(), principal, strings.NewReader("Bakery\nTotal 12,40 EUR"), "image/png")
+	is.NoErr(err)
+	is.Equal(receiptDraft.AmountCents, 1240)
+	is.Equal(receiptDraft.Vendor, "Bakery")
+
+	t.Run("ImageNotSupported", func(t *testing.T) {
+		_, err := s.RecognizeReceipt(context.Background(), principal, strings.NewReader("%PDF"), "application/pdf")
+		is.Equal(err, ErrReceiptImageNotSupported)
+	})
+
+	t.Run("Infected", func(t *testing.T) {
+		_, err := s.RecognizeReceipt(context.Background(), principal, strings.NewReader(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`), "image/jpeg")
+		is.True(errors.Is(err, shared.ErrUploadInfected))
+	})
+}
+
+func TestRecognizeReceiptDisabled(t *testing.T) {
+	is := is.New(t)
+
+	s := newReceiptServiceForTest(nil)
+
+	_, err := s.RecognizeReceipt(context.Background(), &shared.Principal{}, strings.NewReader("Bakery"), "image/png")
+	is.Equal(err, ErrReceiptRecognitionDisabled)
+}
+
+func TestNewTextRecognizer(t *testing.T) {
+	is := is.New(t)
+
+	is.True(NewTextRecognizer(&shared.Config{}) == nil)
+
+	_, ok := NewTextRecognizer(&shared.Config{ReceiptRecognizer: ReceiptRecognizerTesseract}).(*TesseractRecognizer)
+	is.True(ok)
+
+	_, ok = NewTextRecognizer(&shared.Config{ReceiptRecognizer: ReceiptRecognizerGoogleVision, ReceiptGoogleVisionAPIKey: "key"}).(*GoogleVisionRecognizer)
+	is.True(ok)
+}
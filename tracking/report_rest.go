@@ -0,0 +1,442 @@
+package tracking
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/chart"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+var ErrChartNotFound = shared.NewDomainError("report:chart-not-found", http.StatusNotFound, "chart not found")
+
+type utilizationReportModel struct {
+	Start         string                   `json:"start"`
+	End           string                   `json:"end"`
+	TargetMinutes int                      `json:"targetMinutesPerUser"`
+	Team          *utilizationModel        `json:"team"`
+	Users         []*utilizationModel      `json:"users"`
+	Trend         []*utilizationTrendModel `json:"trend"`
+	Links         *hal.Links               `json:"_links"`
+}
+
+type utilizationModel struct {
+	Username             string  `json:"username,omitempty"`
+	MinutesTotal         int     `json:"minutesTotal"`
+	BillableMinutes      int     `json:"billableMinutes"`
+	NonBillableMinutes   int     `json:"nonBillableMinutes"`
+	BillablePercentage   float64 `json:"billablePercentage"`
+	TargetPercentage     float64 `json:"targetPercentage"`
+	DurationFormatted    string  `json:"durationFormatted"`
+	BillableFormatted    string  `json:"billableFormatted"`
+	NonBillableFormatted string  `json:"nonBillableFormatted"`
+}
+
+type utilizationTrendModel struct {
+	Year               int     `json:"year"`
+	Week               int     `json:"week"`
+	MinutesTotal       int     `json:"minutesTotal"`
+	BillableMinutes    int     `json:"billableMinutes"`
+	NonBillableMinutes int     `json:"nonBillableMinutes"`
+	BillablePercentage float64 `json:"billablePercentage"`
+}
+
+type locationReportModel struct {
+	Start     string                     `json:"start"`
+	End       string                     `json:"end"`
+	Locations []*locationReportItemModel `json:"locations"`
+	Links     *hal.Links                 `json:"_links"`
+}
+
+type locationReportItemModel struct {
+	Location          string  `json:"location"`
+	MinutesTotal      int     `json:"minutesTotal"`
+	Percentage        float64 `json:"percentage"`
+	DurationFormatted string  `json:"durationFormatted"`
+}
+
+type anomalyReportModel struct {
+	Start     string                  `json:"start"`
+	End       string                  `json:"end"`
+	Anomalies []*activityAnomalyModel `json:"anomalies"`
+	Links     *hal.Links              `json:"_links"`
+}
+
+type activityAnomalyModel struct {
+	Type              string     `json:"type"`
+	ActivityID        string     `json:"activityId"`
+	Username          string     `json:"username"`
+	ProjectID         string     `json:"projectId"`
+	Start             string     `json:"start"`
+	End               string     `json:"end"`
+	DurationFormatted string     `json:"durationFormatted"`
+	Description       string     `json:"description"`
+	DuplicateOf       string     `json:"duplicateOf,omitempty"`
+	Links             *hal.Links `json:"_links"`
+}
+
+type projectBurndownModel struct {
+	ProjectID     string                      `json:"projectId"`
+	BudgetMinutes int                         `json:"budgetMinutes"`
+	Granularity   string                      `json:"granularity"`
+	Series        []*projectBurndownItemModel `json:"series"`
+	Links         *hal.Links                  `json:"_links"`
+}
+
+type projectBurndownItemModel struct {
+	Date              string `json:"date"`
+	Minutes           int    `json:"minutes"`
+	CumulativeMinutes int    `json:"cumulativeMinutes"`
+	RemainingMinutes  int    `json:"remainingMinutes"`
+	Forecast          bool   `json:"forecast"`
+}
+
+type ReportRestHandlers struct {
+	config            *shared.Config
+	activityService   *ActitivityService
+	projectRepository ProjectRepository
+}
+
+func NewReportRestHandlers(config *shared.Config, activityService *ActitivityService, projectRepository ProjectRepository) *ReportRestHandlers {
+	return &ReportRestHandlers{
+		config:            config,
+		activityService:   activityService,
+		projectRepository: projectRepository,
+	}
+}
+
+func (a *ReportRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/reports/utilization", a.HandleUtilizationReport())
+	r.Get("/reports/locations", a.HandleLocationReport())
+	r.Get("/reports/anomalies", a.HandleAnomalyReport())
+	r.Get("/reports/charts/{chart}", a.HandleReportChart())
+	r.Get("/projects/{project-id}/burndown", a.HandleProjectBurndown())
+}
+
+func (a *ReportRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleUtilizationReport reads the billable vs. non-billable utilization per user and team
+func (a *ReportRestHandlers) HandleUtilizationReport() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	workingHoursPerWeek := a.config.WorkingHoursPerWeek
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		filter, err := filterFromQueryParams(r.URL.Query())
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		utilizationReport, err := activityService.UtilizationReport(r.Context(), principal, filter, workingHoursPerWeek)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		utilizationReportModel := mapToUtilizationReportModel(filter, utilizationReport)
+		utilizationReportModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+		)
+
+		shared.RenderJSON(w, utilizationReportModel)
+	}
+}
+
+// HandleLocationReport reads the tracked time per location
+func (a *ReportRestHandlers) HandleLocationReport() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		filter, err := filterFromQueryParams(r.URL.Query())
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		reportItems, err := activityService.LocationReport(r.Context(), principal, filter)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		locationReportModel := mapToLocationReportModel(filter, reportItems)
+		locationReportModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+		)
+
+		shared.RenderJSON(w, locationReportModel)
+	}
+}
+
+// HandleAnomalyReport reads the unusual activities which are likely mistakes
+func (a *ReportRestHandlers) HandleAnomalyReport() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		filter, err := filterFromQueryParams(r.URL.Query())
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		anomalies, err := activityService.AnomalyReport(r.Context(), principal, filter)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		anomalyReportModel := mapToAnomalyReportModel(filter, anomalies)
+		anomalyReportModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+		)
+
+		shared.RenderJSON(w, anomalyReportModel)
+	}
+}
+
+// HandleReportChart renders a report as SVG chart
+func (a *ReportRestHandlers) HandleReportChart() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	workingHoursPerWeek := a.config.WorkingHoursPerWeek
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+		chartParam := chi.URLParam(r, "chart")
+
+		filter, err := filterFromQueryParams(r.URL.Query())
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		buf := &bytes.Buffer{}
+		switch chartParam {
+		case "projects":
+			projectReports, err := activityService.ProjectReports(r.Context(), principal, filter)
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+			err = activityService.WriteProjectChart(projectReports, buf)
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+		case "weeks":
+			timeReports, err := activityService.TimeReports(r.Context(), principal, filter, "week")
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+			err = activityService.WriteWeeklyChart(timeReports, buf)
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+		case "utilization":
+			utilizationReport, err := activityService.UtilizationReport(r.Context(), principal, filter, workingHoursPerWeek)
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+			err = activityService.WriteUtilizationChart(utilizationReport, buf)
+			if err != nil {
+				shared.RenderProblemJSON(w, isProduction, err)
+				return
+			}
+		default:
+			shared.RenderProblemJSON(w, isProduction, ErrChartNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", chart.ContentType)
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+// HandleProjectBurndown reads the cumulative tracked time of a project against its budget
+func (a *ReportRestHandlers) HandleProjectBurndown() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityService := a.activityService
+	projectRepository := a.projectRepository
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectIDParam := chi.URLParam(r, "project-id")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		if !principal.HasRole("ROLE_ADMIN") {
+			shared.RenderProblemJSON(w, isProduction, shared.ErrForbidden)
+			return
+		}
+
+		projectID, err := uuid.Parse(projectIDParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		granularity := r.URL.Query().Get("granularity")
+		if granularity == "" {
+			granularity = "day"
+		}
+		if granularity != "day" && granularity != "week" {
+			http.Error(w, problem.New(problem.Title("granularity not valid")).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		forecast := r.URL.Query().Get("forecast") == "true"
+
+		project, err := projectRepository.FindProjectByID(r.Context(), principal.OrganizationID, projectID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		burndown, err := activityService.ProjectBurndown(r.Context(), principal, project, granularity, forecast, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		projectBurndownModel := mapToProjectBurndownModel(project, granularity, burndown)
+		projectBurndownModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+			hal.NewLink("project", fmt.Sprintf("/api/projects/%s", project.ID)),
+		)
+
+		shared.RenderJSON(w, projectBurndownModel)
+	}
+}
+
+func mapToProjectBurndownModel(project *Project, granularity string, burndown []*ProjectBurndownItem) *projectBurndownModel {
+	itemModels := make([]*projectBurndownItemModel, len(burndown))
+	for i, item := range burndown {
+		itemModels[i] = &projectBurndownItemModel{
+			Date:              time_utils.FormatDate(item.Date),
+			Minutes:           item.DurationInMinutesTotal,
+			CumulativeMinutes: item.CumulativeMinutes,
+			RemainingMinutes:  item.RemainingMinutes,
+			Forecast:          item.Forecast,
+		}
+	}
+
+	return &projectBurndownModel{
+		ProjectID:     project.ID.String(),
+		BudgetMinutes: project.BudgetMinutes,
+		Granularity:   granularity,
+		Series:        itemModels,
+	}
+}
+
+func mapToAnomalyReportModel(filter *ActivityFilter, anomalies []*ActivityAnomaly) *anomalyReportModel {
+	anomalyModels := make([]*activityAnomalyModel, len(anomalies))
+	for i, anomaly := range anomalies {
+		activity := anomaly.Activity
+		anomalyModels[i] = &activityAnomalyModel{
+			Type:              anomaly.Type,
+			ActivityID:        activity.ID.String(),
+			Username:          activity.Username,
+			ProjectID:         activity.ProjectID.String(),
+			Start:             time_utils.FormatDateTime(activity.Start),
+			End:               time_utils.FormatDateTime(activity.End),
+			DurationFormatted: activity.DurationFormatted(),
+			Description:       activity.Description,
+			Links: hal.NewLinks(
+				hal.NewLink("activity", fmt.Sprintf("/api/activities/%s", activity.ID)),
+			),
+		}
+		if anomaly.DuplicateOf != nil {
+			anomalyModels[i].DuplicateOf = anomaly.DuplicateOf.String()
+		}
+	}
+
+	return &anomalyReportModel{
+		Start:     time_utils.FormatDate(filter.Start()),
+		End:       time_utils.FormatDate(filter.End()),
+		Anomalies: anomalyModels,
+	}
+}
+
+func mapToUtilizationReportModel(filter *ActivityFilter, utilizationReport *UtilizationReport) *utilizationReportModel {
+	userModels := make([]*utilizationModel, len(utilizationReport.Users))
+	for i, user := range utilizationReport.Users {
+		userModels[i] = mapToUtilizationModel(user, utilizationReport.TargetPercentage(user, 1))
+	}
+
+	trendModels := make([]*utilizationTrendModel, len(utilizationReport.Trend))
+	for i, week := range utilizationReport.Trend {
+		trendModels[i] = &utilizationTrendModel{
+			Year:               week.Year,
+			Week:               week.Week,
+			MinutesTotal:       week.DurationInMinutesTotal,
+			BillableMinutes:    week.BillableDurationInMinutesTotal,
+			NonBillableMinutes: week.NonBillableDurationInMinutesTotal(),
+			BillablePercentage: week.BillablePercentage(),
+		}
+	}
+
+	team := utilizationReport.Team
+	return &utilizationReportModel{
+		Start:         time_utils.FormatDate(filter.Start()),
+		End:           time_utils.FormatDate(filter.End()),
+		TargetMinutes: utilizationReport.TargetMinutesPerUser,
+		Team:          mapToUtilizationModel(team, utilizationReport.TargetPercentage(team, len(utilizationReport.Users))),
+		Users:         userModels,
+		Trend:         trendModels,
+	}
+}
+
+func mapToLocationReportModel(filter *ActivityFilter, reportItems []*ActivityLocationReportItem) *locationReportModel {
+	minutesTotal := 0
+	for _, item := range reportItems {
+		minutesTotal += item.DurationInMinutesTotal
+	}
+
+	itemModels := make([]*locationReportItemModel, len(reportItems))
+	for i, item := range reportItems {
+		percentage := 0.0
+		if minutesTotal > 0 {
+			percentage = math.Round(float64(item.DurationInMinutesTotal)/float64(minutesTotal)*1000) / 10
+		}
+		itemModels[i] = &locationReportItemModel{
+			Location:          item.Location,
+			MinutesTotal:      item.DurationInMinutesTotal,
+			Percentage:        percentage,
+			DurationFormatted: item.DurationFormatted(),
+		}
+	}
+
+	return &locationReportModel{
+		Start:     time_utils.FormatDate(filter.Start()),
+		End:       time_utils.FormatDate(filter.End()),
+		Locations: itemModels,
+	}
+}
+
+func mapToUtilizationModel(item *ActivityUtilizationReportItem, targetPercentage float64) *utilizationModel {
+	return &utilizationModel{
+		Username:             item.Username,
+		MinutesTotal:         item.DurationInMinutesTotal,
+		BillableMinutes:      item.BillableDurationInMinutesTotal,
+		NonBillableMinutes:   item.NonBillableDurationInMinutesTotal(),
+		BillablePercentage:   item.BillablePercentage(),
+		TargetPercentage:     targetPercentage,
+		DurationFormatted:    time_utils.FormatMinutesAsDuration(float64(item.DurationInMinutesTotal)),
+		BillableFormatted:    time_utils.FormatMinutesAsDuration(float64(item.BillableDurationInMinutesTotal)),
+		NonBillableFormatted: time_utils.FormatMinutesAsDuration(float64(item.NonBillableDurationInMinutesTotal())),
+	}
+}
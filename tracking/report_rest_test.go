@@ -0,0 +1,298 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestHandleUtilizationReport(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ReportRestHandlers{
+		config: &shared.Config{
+			WorkingHoursPerWeek: 40,
+		},
+		activityService: &ActitivityService{
+			activityRepository:         NewInMemActivityRepository(),
+			locationPolicyRepository:   NewInMemLocationPolicyRepository(),
+			compliancePolicyRepository: NewInMemCompliancePolicyRepository(),
+			breakPolicyRepository:      NewInMemBreakPolicyRepository(),
+		},
+	}
+
+	r, _ := http.NewRequest("GET", "/api/reports/utilization?t=month&v=2021-10", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleUtilizationReport()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	utilizationReportModel := &utilizationReportModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(utilizationReportModel)
+	is.NoErr(err)
+	is.Equal(utilizationReportModel.Start, "2021-10-01")
+	is.Equal(len(utilizationReportModel.Users), 1)
+	is.True(utilizationReportModel.TargetMinutes > 0)
+}
+
+func TestHandleUtilizationReportAnonymized(t *testing.T) {
+	is := is.New(t)
+
+	a := &ReportRestHandlers{
+		config: &shared.Config{
+			WorkingHoursPerWeek: 40,
+		},
+		activityService: &ActitivityService{
+			activityRepository:         NewInMemActivityRepository(),
+			locationPolicyRepository:   NewInMemLocationPolicyRepository(),
+			compliancePolicyRepository: NewInMemCompliancePolicyRepository(),
+			breakPolicyRepository:      NewInMemBreakPolicyRepository(),
+		},
+	}
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/api/reports/utilization?t=month&v=2021-10&anonymize=true", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{Roles: []string{"ROLE_ADMIN"}}))
+
+	a.HandleUtilizationReport()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	utilizationReportModel := &utilizationReportModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(utilizationReportModel)
+	is.NoErr(err)
+	is.Equal(len(utilizationReportModel.Users), 1)
+	is.Equal(utilizationReportModel.Users[0].Username, "User A")
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("GET", "/api/reports/utilization?t=month&v=2021-10&anonymize=maybe", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{Roles: []string{"ROLE_ADMIN"}}))
+
+	a.HandleUtilizationReport()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
+
+func TestHandleUtilizationReportInvalidFilter(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ReportRestHandlers{
+		config: &shared.Config{},
+		activityService: &ActitivityService{
+			activityRepository:         NewInMemActivityRepository(),
+			locationPolicyRepository:   NewInMemLocationPolicyRepository(),
+			compliancePolicyRepository: NewInMemCompliancePolicyRepository(),
+		},
+	}
+
+	r, _ := http.NewRequest("GET", "/api/reports/utilization?t=month&v=no-month", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleUtilizationReport()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	is.True(strings.Contains(httpRec.Body.String(), `"field":"v"`))
+}
+
+func TestHandleLocationReport(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ReportRestHandlers{
+		config: &shared.Config{},
+		activityService: &ActitivityService{
+			activityRepository:         NewInMemActivityRepository(),
+			locationPolicyRepository:   NewInMemLocationPolicyRepository(),
+			compliancePolicyRepository: NewInMemCompliancePolicyRepository(),
+		},
+	}
+
+	r, _ := http.NewRequest("GET", "/api/reports/locations?t=year&v=2021", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleLocationReport()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.True(strings.Contains(httpRec.Body.String(), `"location":"unspecified"`))
+}
+
+func TestHandleReportChart(t *testing.T) {
+	is := is.New(t)
+
+	a := &ReportRestHandlers{
+		config: &shared.Config{
+			WorkingHoursPerWeek: 40,
+		},
+		activityService: &ActitivityService{
+			activityRepository:         NewInMemActivityRepository(),
+			locationPolicyRepository:   NewInMemLocationPolicyRepository(),
+			compliancePolicyRepository: NewInMemCompliancePolicyRepository(),
+			breakPolicyRepository:      NewInMemBreakPolicyRepository(),
+		},
+	}
+
+	for _, chartName := range []string{"projects", "weeks", "utilization"} {
+		httpRec := httptest.NewRecorder()
+
+		r, _ := http.NewRequest("GET", "/api/reports/charts/"+chartName+"?t=year&v=2021", nil)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("chart", chartName)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		a.HandleReportChart()(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Result().Header.Get("Content-Type"), "image/svg+xml")
+		is.True(strings.HasPrefix(httpRec.Body.String(), "<svg"))
+	}
+}
+
+func TestHandleReportChartNotFound(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ReportRestHandlers{
+		config: &shared.Config{},
+		activityService: &ActitivityService{
+			activityRepository:         NewInMemActivityRepository(),
+			locationPolicyRepository:   NewInMemLocationPolicyRepository(),
+			compliancePolicyRepository: NewInMemCompliancePolicyRepository(),
+		},
+	}
+
+	r, _ := http.NewRequest("GET", "/api/reports/charts/unknown", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("chart", "unknown")
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	a.HandleReportChart()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
+
+func TestHandleProjectBurndown(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ReportRestHandlers{
+		config: &shared.Config{},
+		activityService: &ActitivityService{
+			activityRepository:         NewInMemActivityRepository(),
+			locationPolicyRepository:   NewInMemLocationPolicyRepository(),
+			compliancePolicyRepository: NewInMemCompliancePolicyRepository(),
+		},
+		projectRepository: NewInMemProjectRepository(),
+	}
+
+	r, _ := http.NewRequest("GET", "/api/projects/"+shared.ProjectIDSample.String()+"/burndown?granularity=week", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleProjectBurndown()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	projectBurndownModel := &projectBurndownModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(projectBurndownModel)
+	is.NoErr(err)
+	is.Equal(projectBurndownModel.Granularity, "week")
+	is.True(len(projectBurndownModel.Series) > 0)
+}
+
+func TestHandleProjectBurndownAsUser(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ReportRestHandlers{
+		config:            &shared.Config{},
+		projectRepository: NewInMemProjectRepository(),
+	}
+
+	r, _ := http.NewRequest("GET", "/api/projects/"+shared.ProjectIDSample.String()+"/burndown", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project-id", shared.ProjectIDSample.String())
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		Roles: []string{"ROLE_USER"},
+	}))
+
+	a.HandleProjectBurndown()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
+
+func TestHandleProjectBurndownNotFound(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ReportRestHandlers{
+		config:            &shared.Config{},
+		projectRepository: NewInMemProjectRepository(),
+	}
+
+	projectID := "00000000-0000-0000-1111-999999999999"
+	r, _ := http.NewRequest("GET", "/api/projects/"+projectID+"/burndown", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project-id", projectID)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		Roles: []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleProjectBurndown()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+}
+
+func TestHandleAnomalyReport(t *testing.T) {
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	a := &ReportRestHandlers{
+		config: &shared.Config{},
+		activityService: &ActitivityService{
+			activityRepository: activityRepository,
+		},
+	}
+
+	start, _ := time.Parse(time.RFC3339, "2021-10-04T10:00:00.000Z")
+	activityRepository.activities = append(activityRepository.activities, &Activity{
+		ID:        uuid.New(),
+		Start:     start,
+		End:       start.Add(13 * time.Hour),
+		ProjectID: shared.ProjectIDSample,
+		Username:  "user1",
+	})
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/api/reports/anomalies?t=month&v=2021-10", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{Roles: []string{"ROLE_ADMIN"}}))
+
+	a.HandleAnomalyReport()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	anomalyReportModel := &anomalyReportModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(anomalyReportModel)
+	is.NoErr(err)
+	is.Equal(anomalyReportModel.Start, "2021-10-01")
+	is.Equal(len(anomalyReportModel.Anomalies), 1)
+	is.Equal(anomalyReportModel.Anomalies[0].Type, ActivityAnomalyOverlong)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("GET", "/api/reports/anomalies?t=month&v=2021-10", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleAnomalyReport()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+}
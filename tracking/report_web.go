@@ -1,6 +1,7 @@
 package tracking
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -12,6 +13,7 @@ import (
 	time_utils "github.com/baralga/tracking/time"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/gorilla/csrf"
 	g "github.com/maragudk/gomponents"
 	ghx "github.com/maragudk/gomponents-htmx"
 	. "github.com/maragudk/gomponents/html"
@@ -45,6 +47,8 @@ func (a *ReportWeb) HandleReportPage() http.HandlerFunc {
 			Ctx:          r.Context(),
 			Principal:    principal,
 			CurrentPath:  r.URL.Path,
+			CSRFToken:    csrf.Token(r),
+			Appearance:   shared.AppearanceOf(r.Context()),
 			CurrentQuery: r.URL.Query(),
 			Title:        "Report Activities",
 		}
@@ -78,7 +82,7 @@ func (a *ReportWeb) ReportView(pageContext *shared.PageContext, view *reportView
 	homeFilter := filter.Home()
 	nextFilter := filter.Next()
 
-	var reportGeneralView, reportTimeView, reportProjectView g.Node
+	var reportGeneralView, reportTimeView, reportProjectView, reportLocationView g.Node
 	var err error
 	if view.main == "general" {
 		reportGeneralView, err = a.reportGeneralView(pageContext, filter, view)
@@ -98,6 +102,12 @@ func (a *ReportWeb) ReportView(pageContext *shared.PageContext, view *reportView
 			return nil, err
 		}
 	}
+	if view.main == "location" {
+		reportLocationView, err = a.reportLocationView(pageContext, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return Div(
 		ID("baralga__report_content"),
@@ -271,6 +281,23 @@ func (a *ReportWeb) ReportView(pageContext *shared.PageContext, view *reportView
 						g.Text("Project"),
 						Class("nav-link"),
 					),
+					A(
+						g.If(view.main == "location",
+							Class("nav-link active"),
+						),
+						g.If(view.main != "location",
+							g.Group([]g.Node{
+								Class("btn nav-link"),
+								ghx.Get(reportHrefForView(filter, "location", "")),
+								ghx.PushURL("true"),
+								ghx.Target("#baralga__report_content"),
+								ghx.Swap("outerHTML"),
+							}),
+						),
+						I(Class("bi-geo-alt me-2")),
+						g.Text("Location"),
+						Class("nav-link"),
+					),
 				),
 			),
 		),
@@ -283,6 +310,9 @@ func (a *ReportWeb) ReportView(pageContext *shared.PageContext, view *reportView
 		g.If(view.main == "project",
 			reportProjectView,
 		),
+		g.If(view.main == "location",
+			reportLocationView,
+		),
 	), nil
 }
 
@@ -315,7 +345,16 @@ func (a *ReportWeb) reportTimeView(pageContext *shared.PageContext, view *report
 
 	switch view.sub {
 	case "w":
-		reportView = reportByWeekView(timeReports)
+		weeklyChart := &bytes.Buffer{}
+		err = a.activityService.WriteWeeklyChart(timeReports, weeklyChart)
+		reportView = g.Group([]g.Node{
+			Div(
+				ID("time-report-by-week-chart"),
+				Class("text-center mb-3"),
+				g.Raw(weeklyChart.String()),
+			),
+			reportByWeekView(timeReports),
+		})
 	case "m":
 		reportView = reportByMonthView(timeReports)
 	case "q":
@@ -434,7 +473,18 @@ func (a *ReportWeb) reportProjectView(pageContext *shared.PageContext, view *rep
 		), nil
 	}
 
+	projectChart := &bytes.Buffer{}
+	err = a.activityService.WriteProjectChart(projectReports, projectChart)
+	if err != nil {
+		return nil, err
+	}
+
 	return g.Group([]g.Node{
+		Div(
+			ID("project-report-chart"),
+			Class("text-center mb-3"),
+			g.Raw(projectChart.String()),
+		),
 		Div(
 			Class("table-responsive"),
 			Table(
@@ -455,7 +505,16 @@ func (a *ReportWeb) reportProjectView(pageContext *shared.PageContext, view *rep
 							ghx.Target("this"),
 							ghx.Swap("outerHTML"),
 
-							Td(g.Text(activity.ProjectTitle)),
+							Td(
+								g.Text(activity.ProjectTitle),
+								g.If(
+									activity.ProjectStatus != "" && activity.ProjectStatus != ProjectStatusActive,
+									Span(
+										Class("badge rounded-pill bg-secondary fw-normal ms-2"),
+										g.Text(projectStatusTitle(activity.ProjectStatus)),
+									),
+								),
+							),
 							Td(
 								Class("text-end"),
 								g.Text(activity.DurationFormatted()),
@@ -469,6 +528,63 @@ func (a *ReportWeb) reportProjectView(pageContext *shared.PageContext, view *rep
 	}), nil
 }
 
+func (a *ReportWeb) reportLocationView(pageContext *shared.PageContext, filter *ActivityFilter) (g.Node, error) {
+	locationReports, err := a.activityService.LocationReport(pageContext.Ctx, pageContext.Principal, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(locationReports) == 0 {
+		return Div(
+			Class("alert alert-info"),
+			Role("alert"),
+			g.Text(fmt.Sprintf("No activities found in %v.", filter.String())),
+		), nil
+	}
+
+	return Div(
+		Class("table-responsive"),
+		Table(
+			ID("location-report"),
+			Class("table table-striped"),
+			THead(
+				Tr(
+					Th(g.Text("Location")),
+					Th(
+						Class("text-end"),
+						g.Text("Duration"),
+					),
+				),
+			),
+			TBody(
+				g.Group(g.Map(locationReports, func(reportItem *ActivityLocationReportItem) g.Node {
+					return Tr(
+						Td(g.Text(locationTitle(reportItem.Location))),
+						Td(
+							Class("text-end"),
+							g.Text(reportItem.DurationFormatted()),
+						),
+					)
+				}),
+				),
+			),
+		),
+	), nil
+}
+
+func locationTitle(location string) string {
+	switch location {
+	case LocationOffice:
+		return "Office"
+	case LocationHome:
+		return "Home"
+	case LocationClientSite:
+		return "Client Site"
+	default:
+		return "Not specified"
+	}
+}
+
 func reportByDayView(timeReports []*ActivityTimeReportItem) g.Node {
 	return Table(
 		ID("time-report-by-day"),
@@ -669,7 +785,7 @@ func (a *ReportWeb) reportGeneralView(pageContext *shared.PageContext, filter *A
 							ghx.Target("this"),
 							ghx.Swap("outerHTML"),
 
-							TitleAttr(activity.Description),
+							TitleAttr(shared.MarkdownToPlainText(activity.Description)),
 
 							Td(g.Text(projectsById[activity.ProjectID].Title)),
 							Td(g.Text(time_utils.FormatDateDEShort(activity.Start))),
@@ -747,7 +863,7 @@ func (a *ReportWeb) reportGeneralView(pageContext *shared.PageContext, filter *A
 							ghx.Target("this"),
 							ghx.Swap("outerHTML"),
 
-							TitleAttr(activity.Description),
+							TitleAttr(shared.MarkdownToPlainText(activity.Description)),
 
 							Td(g.Text(projectsById[activity.ProjectID].Title)),
 							Td(g.Text(time_utils.FormatDateDE(activity.Start))),
@@ -911,7 +1027,9 @@ func (a *ReportWeb) ReportPage(pageContext *shared.PageContext, reportView g.Nod
 	return shared.Page(
 		pageContext.Title,
 		pageContext.CurrentPath,
+		pageContext.Appearance,
 		[]g.Node{
+			shared.CSRFHeaders(pageContext.CSRFToken),
 			shared.Navbar(pageContext),
 			reportView,
 			shared.ModalView(),
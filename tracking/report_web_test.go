@@ -19,7 +19,8 @@ func TestHandleReportPage(t *testing.T) {
 	a := &ReportWeb{
 		config: &shared.Config{},
 		activityService: &ActitivityService{
-			activityRepository: NewInMemActivityRepository(),
+			activityRepository:       NewInMemActivityRepository(),
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -40,7 +41,9 @@ func TestHandleReportPageWithTimeByDay(t *testing.T) {
 	a := &ReportWeb{
 		config: &shared.Config{},
 		activityService: &ActitivityService{
-			activityRepository: NewInMemActivityRepository(),
+			activityRepository:       NewInMemActivityRepository(),
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+			breakPolicyRepository:    NewInMemBreakPolicyRepository(),
 		},
 	}
 
@@ -63,7 +66,9 @@ func TestHandleReportPageWithTimeByWeek(t *testing.T) {
 	a := &ReportWeb{
 		config: &shared.Config{},
 		activityService: &ActitivityService{
-			activityRepository: NewInMemActivityRepository(),
+			activityRepository:       NewInMemActivityRepository(),
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+			breakPolicyRepository:    NewInMemBreakPolicyRepository(),
 		},
 	}
 
@@ -77,6 +82,7 @@ func TestHandleReportPageWithTimeByWeek(t *testing.T) {
 
 	htmlBody := httpRec.Body.String()
 	is.True(strings.Contains(htmlBody, "id=\"time-report-by-week\""))
+	is.True(strings.Contains(htmlBody, "id=\"time-report-by-week-chart\""))
 }
 
 func TestHandleReportPageWithTimeByMonth(t *testing.T) {
@@ -86,7 +92,9 @@ func TestHandleReportPageWithTimeByMonth(t *testing.T) {
 	a := &ReportWeb{
 		config: &shared.Config{},
 		activityService: &ActitivityService{
-			activityRepository: NewInMemActivityRepository(),
+			activityRepository:       NewInMemActivityRepository(),
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+			breakPolicyRepository:    NewInMemBreakPolicyRepository(),
 		},
 	}
 
@@ -109,7 +117,9 @@ func TestHandleReportPageWithTimeByQuarter(t *testing.T) {
 	a := &ReportWeb{
 		config: &shared.Config{},
 		activityService: &ActitivityService{
-			activityRepository: NewInMemActivityRepository(),
+			activityRepository:       NewInMemActivityRepository(),
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+			breakPolicyRepository:    NewInMemBreakPolicyRepository(),
 		},
 	}
 
@@ -132,7 +142,8 @@ func TestHandleReportPageWithProject(t *testing.T) {
 	a := &ReportWeb{
 		config: &shared.Config{},
 		activityService: &ActitivityService{
-			activityRepository: NewInMemActivityRepository(),
+			activityRepository:       NewInMemActivityRepository(),
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
 		},
 	}
 
@@ -146,6 +157,8 @@ func TestHandleReportPageWithProject(t *testing.T) {
 
 	htmlBody := httpRec.Body.String()
 	is.True(strings.Contains(htmlBody, "id=\"project-report\""))
+	is.True(strings.Contains(htmlBody, "id=\"project-report-chart\""))
+	is.True(strings.Contains(htmlBody, "<svg"))
 }
 
 func TestReportViewFromQueryParams(t *testing.T) {
@@ -260,3 +273,28 @@ func TestReportViewFromQueryParams(t *testing.T) {
 		is.Equal(view.sub, "d")
 	})
 }
+
+func TestHandleReportPageWithLocation(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	a := &ReportWeb{
+		config: &shared.Config{},
+		activityService: &ActitivityService{
+			activityRepository:       NewInMemActivityRepository(),
+			locationPolicyRepository: NewInMemLocationPolicyRepository(),
+		},
+	}
+
+	r, _ := http.NewRequest("GET", "/reports?c=location", nil)
+	r.Header.Add("HX-Request", "true")
+	r.Header.Add("HX-Target", "baralga__report_content")
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{}))
+
+	a.HandleReportPage()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	htmlBody := httpRec.Body.String()
+	is.True(strings.Contains(htmlBody, "id=\"location-report\""))
+	is.True(strings.Contains(htmlBody, "Not specified"))
+}
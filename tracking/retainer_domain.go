@@ -0,0 +1,131 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+var ErrRetainerNotFound = shared.NewDomainError("retainer:not-found", http.StatusNotFound, "retainer not found")
+
+// Retainer is an agreement with a client about hours per month bought in advance, with rollover
+// the unused time of a month is added to the next month, time beyond the available time is overage
+type Retainer struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	ClientID        uuid.UUID
+	ClientName      string
+	MinutesPerMonth int
+	Rollover        bool
+	StartMonth      time.Time
+	EndMonth        *time.Time
+	AlertPercent    int
+}
+
+// RetainerConsumptionItem is the billable time tracked for the projects of the client of a retainer in a month
+type RetainerConsumptionItem struct {
+	Year                   int
+	Month                  int
+	DurationInMinutesTotal int
+}
+
+// RetainerMonth is the time available and consumed of a retainer in a month
+type RetainerMonth struct {
+	Month           time.Time
+	IncludedMinutes int
+	RolloverMinutes int
+	ConsumedMinutes int
+}
+
+type RetainerRepository interface {
+	FindRetainers(ctx context.Context, organizationID uuid.UUID) ([]*Retainer, error)
+	FindRetainerByID(ctx context.Context, organizationID, retainerID uuid.UUID) (*Retainer, error)
+	InsertRetainer(ctx context.Context, retainer *Retainer) error
+	UpdateRetainer(ctx context.Context, retainer *Retainer) error
+	DeleteRetainer(ctx context.Context, organizationID, retainerID uuid.UUID) error
+	FindConsumption(ctx context.Context, organizationID, clientID uuid.UUID, start, end time.Time) ([]*RetainerConsumptionItem, error)
+	FindRetainersDueForAlert(ctx context.Context, month time.Time) ([]*Retainer, error)
+	InsertRetainerAlert(ctx context.Context, retainer *Retainer, month time.Time) error
+	FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error)
+}
+
+// monthOf is the first day of the month of the time
+func monthOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// IsActive checks whether the retainer covers the month
+func (r *Retainer) IsActive(month time.Time) bool {
+	month = monthOf(month)
+	if month.Before(r.StartMonth) {
+		return false
+	}
+	return r.EndMonth == nil || !month.After(*r.EndMonth)
+}
+
+// Consumption calculates the time available and consumed in each month from the start of the retainer
+// up to the month of until, the unused time of a month rolls over to the next month if enabled
+func (r *Retainer) Consumption(until time.Time, items []*RetainerConsumptionItem) []*RetainerMonth {
+	last := monthOf(until)
+	if r.EndMonth != nil && r.EndMonth.Before(last) {
+		last = *r.EndMonth
+	}
+
+	consumedByMonth := make(map[time.Time]int, len(items))
+	for _, item := range items {
+		month := time.Date(item.Year, time.Month(item.Month), 1, 0, 0, 0, 0, time.UTC)
+		consumedByMonth[month] += item.DurationInMinutesTotal
+	}
+
+	var months []*RetainerMonth
+	rolloverMinutes := 0
+	for month := r.StartMonth; !month.After(last); month = month.AddDate(0, 1, 0) {
+		retainerMonth := &RetainerMonth{
+			Month:           month,
+			IncludedMinutes: r.MinutesPerMonth,
+			RolloverMinutes: rolloverMinutes,
+			ConsumedMinutes: consumedByMonth[month],
+		}
+		months = append(months, retainerMonth)
+
+		rolloverMinutes = 0
+		if r.Rollover {
+			rolloverMinutes = retainerMonth.RemainingMinutes()
+		}
+	}
+	return months
+}
+
+// AvailableMinutes is the time included in the month plus the time rolled over from the month before
+func (m *RetainerMonth) AvailableMinutes() int {
+	return m.IncludedMinutes + m.RolloverMinutes
+}
+
+// RemainingMinutes is the available time not yet consumed
+func (m *RetainerMonth) RemainingMinutes() int {
+	return max(m.AvailableMinutes()-m.ConsumedMinutes, 0)
+}
+
+// OverageMinutes is the time consumed beyond the available time
+func (m *RetainerMonth) OverageMinutes() int {
+	return max(m.ConsumedMinutes-m.AvailableMinutes(), 0)
+}
+
+// ConsumedPercent is the consumed time as percentage of the available time
+func (m *RetainerMonth) ConsumedPercent() int {
+	if m.AvailableMinutes() == 0 {
+		if m.ConsumedMinutes > 0 {
+			return 100
+		}
+		return 0
+	}
+	return m.ConsumedMinutes * 100 / m.AvailableMinutes()
+}
+
+// IsNearlyExhausted checks whether the consumed time reached the alert percentage of the available time
+func (m *RetainerMonth) IsNearlyExhausted(alertPercent int) bool {
+	return m.ConsumedMinutes > 0 && m.ConsumedPercent() >= alertPercent
+}
@@ -0,0 +1,63 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestRetainerConsumption(t *testing.T) {
+	is := is.New(t)
+
+	items := []*RetainerConsumptionItem{
+		{Year: 2024, Month: 1, DurationInMinutesTotal: 300},
+		{Year: 2024, Month: 2, DurationInMinutesTotal: 900},
+		{Year: 2024, Month: 3, DurationInMinutesTotal: 550},
+	}
+	until := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("WithRollover", func(t *testing.T) {
+		retainer := &Retainer{MinutesPerMonth: 600, Rollover: true, StartMonth: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+		months := retainer.Consumption(until, items)
+		is.Equal(len(months), 3)
+
+		is.Equal(months[0].AvailableMinutes(), 600)
+		is.Equal(months[0].RemainingMinutes(), 300)
+
+		is.Equal(months[1].RolloverMinutes, 300)
+		is.Equal(months[1].AvailableMinutes(), 900)
+		is.Equal(months[1].RemainingMinutes(), 0)
+
+		is.Equal(months[2].RolloverMinutes, 0)
+		is.Equal(months[2].ConsumedPercent(), 91)
+		is.True(months[2].IsNearlyExhausted(80))
+	})
+
+	t.Run("WithoutRollover", func(t *testing.T) {
+		retainer := &Retainer{MinutesPerMonth: 600, StartMonth: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+		months := retainer.Consumption(until, items)
+		is.Equal(len(months), 3)
+		is.Equal(months[1].RolloverMinutes, 0)
+		is.Equal(months[1].OverageMinutes(), 300)
+		is.True(!months[0].IsNearlyExhausted(80))
+	})
+
+	t.Run("Ended", func(t *testing.T) {
+		endMonth := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		retainer := &Retainer{MinutesPerMonth: 600, StartMonth: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), EndMonth: &endMonth}
+
+		months := retainer.Consumption(until, items)
+		is.Equal(len(months), 2)
+		is.True(!retainer.IsActive(until))
+	})
+
+	t.Run("NotStarted", func(t *testing.T) {
+		retainer := &Retainer{MinutesPerMonth: 600, StartMonth: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}
+
+		months := retainer.Consumption(until, items)
+		is.Equal(len(months), 0)
+	})
+}
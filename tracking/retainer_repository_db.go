@@ -0,0 +1,244 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbRetainerRepository is a SQL database repository for retainers
+type DbRetainerRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ RetainerRepository = (*DbRetainerRepository)(nil)
+
+// NewDbRetainerRepository creates a new SQL database repository for retainers
+func NewDbRetainerRepository(connPool *pgxpool.Pool) *DbRetainerRepository {
+	return &DbRetainerRepository{
+		connPool: connPool,
+	}
+}
+
+const retainerSelect = `SELECT r.retainer_id, r.org_id, r.client_id, c.name as client_name, r.minutes_per_month,
+		        r.rollover, r.start_month, r.end_month, r.alert_percent
+		 FROM retainers r
+		 INNER JOIN clients c
+		 ON c.client_id = r.client_id`
+
+func (r *DbRetainerRepository) FindRetainers(ctx context.Context, organizationID uuid.UUID) ([]*Retainer, error) {
+	rows, err := shared.SelectAll[retainerRow](
+		ctx,
+		r.connPool,
+		retainerSelect+`
+		 WHERE r.org_id = $1
+		 ORDER BY c.name ASC, r.start_month ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRetainers(rows), nil
+}
+
+func (r *DbRetainerRepository) FindRetainerByID(ctx context.Context, organizationID, retainerID uuid.UUID) (*Retainer, error) {
+	row, err := shared.SelectOne[retainerRow](
+		ctx,
+		r.connPool,
+		retainerSelect+`
+		 WHERE r.retainer_id = $1 AND r.org_id = $2`,
+		retainerID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRetainerNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toRetainer(), nil
+}
+
+func (r *DbRetainerRepository) InsertRetainer(ctx context.Context, retainer *Retainer) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO retainers
+		   (retainer_id, org_id, client_id, minutes_per_month, rollover, start_month, end_month, alert_percent)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		retainer.ID,
+		retainer.OrganizationID,
+		retainer.ClientID,
+		retainer.MinutesPerMonth,
+		retainer.Rollover,
+		retainer.StartMonth,
+		retainer.EndMonth,
+		retainer.AlertPercent,
+	)
+	return err
+}
+
+func (r *DbRetainerRepository) UpdateRetainer(ctx context.Context, retainer *Retainer) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE retainers
+		 SET minutes_per_month = $3, rollover = $4, start_month = $5, end_month = $6, alert_percent = $7
+		 WHERE retainer_id = $1 AND org_id = $2`,
+		retainer.ID,
+		retainer.OrganizationID,
+		retainer.MinutesPerMonth,
+		retainer.Rollover,
+		retainer.StartMonth,
+		retainer.EndMonth,
+		retainer.AlertPercent,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrRetainerNotFound
+	}
+	return nil
+}
+
+func (r *DbRetainerRepository) DeleteRetainer(ctx context.Context, organizationID, retainerID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM retainers
+		 WHERE retainer_id = $1 AND org_id = $2`,
+		retainerID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrRetainerNotFound
+	}
+	return nil
+}
+
+// FindConsumption sums up the billable time tracked for the projects of the client per month from start to end
+func (r *DbRetainerRepository) FindConsumption(ctx context.Context, organizationID, clientID uuid.UUID, start, end time.Time) ([]*RetainerConsumptionItem, error) {
+	rows, err := shared.SelectAll[retainerConsumptionRow](
+		ctx,
+		r.connPool,
+		`SELECT ag.year, ag.month, sum(ag.duration_minutes_total) as duration_minutes_total
+		 FROM projects p
+		 INNER JOIN activities_agg ag
+		 ON ag.project_id = p.project_id AND ag.org_id = p.org_id
+		 WHERE p.org_id = $1 AND p.client_id = $2 AND p.billable = true AND $3 <= ag.start_time AND ag.start_time < $4
+		 GROUP BY ag.year, ag.month
+		 ORDER BY ag.year ASC, ag.month ASC`,
+		organizationID, clientID, start, end,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*RetainerConsumptionItem, len(rows))
+	for i, row := range rows {
+		items[i] = &RetainerConsumptionItem{
+			Year:                   row.Year,
+			Month:                  row.Month,
+			DurationInMinutesTotal: row.DurationInMinutesTotal,
+		}
+	}
+	return items, nil
+}
+
+// FindRetainersDueForAlert reads the retainers of all organizations active in the month whose admins
+// were not yet alerted about the month
+func (r *DbRetainerRepository) FindRetainersDueForAlert(ctx context.Context, month time.Time) ([]*Retainer, error) {
+	rows, err := shared.SelectAll[retainerRow](
+		ctx,
+		r.connPool,
+		retainerSelect+`
+		 WHERE r.start_month <= $1 AND (r.end_month IS NULL OR r.end_month >= $1)
+		 AND NOT EXISTS (SELECT 1 FROM retainer_alerts a WHERE a.retainer_id = r.retainer_id AND a.alert_month = $1)
+		 ORDER BY r.org_id, r.retainer_id`,
+		month,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRetainers(rows), nil
+}
+
+// InsertRetainerAlert remembers that the admins were alerted about the retainer in the month
+func (r *DbRetainerRepository) InsertRetainerAlert(ctx context.Context, retainer *Retainer, month time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO retainer_alerts
+		   (retainer_id, org_id, alert_month)
+		 VALUES
+		   ($1, $2, $3)
+		 ON CONFLICT DO NOTHING`,
+		retainer.ID,
+		retainer.OrganizationID,
+		month,
+	)
+	return err
+}
+
+// FindTeamMembers reads the enabled users of the organization
+func (r *DbRetainerRepository) FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error) {
+	return findTeamMembers(ctx, r.connPool, organizationID)
+}
+
+type retainerRow struct {
+	ID              uuid.UUID  `db:"retainer_id"`
+	OrganizationID  uuid.UUID  `db:"org_id"`
+	ClientID        uuid.UUID  `db:"client_id"`
+	ClientName      string     `db:"client_name"`
+	MinutesPerMonth int        `db:"minutes_per_month"`
+	Rollover        bool       `db:"rollover"`
+	StartMonth      time.Time  `db:"start_month"`
+	EndMonth        *time.Time `db:"end_month"`
+	AlertPercent    int        `db:"alert_percent"`
+}
+
+type retainerConsumptionRow struct {
+	Year                   int `db:"year"`
+	Month                  int `db:"month"`
+	DurationInMinutesTotal int `db:"duration_minutes_total"`
+}
+
+func (r *retainerRow) toRetainer() *Retainer {
+	return &Retainer{
+		ID:              r.ID,
+		OrganizationID:  r.OrganizationID,
+		ClientID:        r.ClientID,
+		ClientName:      r.ClientName,
+		MinutesPerMonth: r.MinutesPerMonth,
+		Rollover:        r.Rollover,
+		StartMonth:      monthOf(r.StartMonth),
+		EndMonth:        r.EndMonth,
+		AlertPercent:    r.AlertPercent,
+	}
+}
+
+func toRetainers(rows []*retainerRow) []*Retainer {
+	retainers := make([]*Retainer, len(rows))
+	for i, row := range rows {
+		retainers[i] = row.toRetainer()
+	}
+	return retainers
+}
@@ -0,0 +1,121 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestRetainerRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	clientRepository := NewDbClientRepository(connPool)
+	projectRepository := NewDbProjectRepository(connPool)
+	retainerRepository := NewDbRetainerRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	client := &Client{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Name:           "ACME Corp.",
+		Currency:       "EUR",
+	}
+	retainer := &Retainer{
+		ID:              uuid.New(),
+		OrganizationID:  shared.OrganizationIDSample,
+		ClientID:        client.ID,
+		MinutesPerMonth: 600,
+		Rollover:        true,
+		StartMonth:      time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		AlertPercent:    80,
+	}
+
+	t.Run("InsertRetainer", func(t *testing.T) {
+		project, err := projectRepository.FindProjectByID(context.Background(), shared.OrganizationIDSample, shared.ProjectIDSample)
+		is.NoErr(err)
+		project.Billable = true
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := projectRepository.UpdateProject(ctx, shared.OrganizationIDSample, project)
+				if err != nil {
+					return err
+				}
+				_, err = clientRepository.InsertClient(ctx, client)
+				if err != nil {
+					return err
+				}
+				err = clientRepository.AssignProjectToClient(ctx, shared.OrganizationIDSample, shared.ProjectIDSample, &client.ID)
+				if err != nil {
+					return err
+				}
+				return retainerRepository.InsertRetainer(ctx, retainer)
+			},
+		)
+		is.NoErr(err)
+
+		retainers, err := retainerRepository.FindRetainers(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(retainers), 1)
+		is.Equal(retainers[0].ClientName, "ACME Corp.")
+	})
+
+	t.Run("FindConsumption", func(t *testing.T) {
+		// sample activity of 10 minutes on 2021-10-14 of the billable sample project
+		items, err := retainerRepository.FindConsumption(
+			context.Background(),
+			shared.OrganizationIDSample,
+			client.ID,
+			time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC),
+		)
+		is.NoErr(err)
+		is.Equal(len(items), 1)
+		is.Equal(items[0].Month, 10)
+		is.Equal(items[0].DurationInMinutesTotal, 10)
+	})
+
+	t.Run("RetainerAlert", func(t *testing.T) {
+		month := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+
+		retainers, err := retainerRepository.FindRetainersDueForAlert(context.Background(), month)
+		is.NoErr(err)
+		is.Equal(len(retainers), 1)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return retainerRepository.InsertRetainerAlert(ctx, retainer, month)
+			},
+		)
+		is.NoErr(err)
+
+		retainers, err = retainerRepository.FindRetainersDueForAlert(context.Background(), month)
+		is.NoErr(err)
+		is.Equal(len(retainers), 0)
+	})
+}
@@ -0,0 +1,152 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+type InMemRetainerRepository struct {
+	mu          sync.Mutex
+	retainers   []*Retainer
+	alerts      map[uuid.UUID]map[time.Time]bool
+	consumption []*RetainerConsumptionItem
+	teamMembers []*TeamMember
+}
+
+var _ RetainerRepository = (*InMemRetainerRepository)(nil)
+
+func NewInMemRetainerRepository() *InMemRetainerRepository {
+	return &InMemRetainerRepository{
+		alerts: make(map[uuid.UUID]map[time.Time]bool),
+		teamMembers: []*TeamMember{
+			{Username: "admin", Name: "Ed Admin", EMail: "admin@baralga.com", Admin: true},
+			{Username: "user1", Name: "Ulani User", EMail: "user1@baralga.com"},
+		},
+	}
+}
+
+func (r *InMemRetainerRepository) FindRetainers(ctx context.Context, organizationID uuid.UUID) ([]*Retainer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var retainers []*Retainer
+	for _, retainer := range r.retainers {
+		if retainer.OrganizationID == organizationID {
+			found := *retainer
+			retainers = append(retainers, &found)
+		}
+	}
+	return retainers, nil
+}
+
+func (r *InMemRetainerRepository) FindRetainerByID(ctx context.Context, organizationID, retainerID uuid.UUID) (*Retainer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, retainer := range r.retainers {
+		if retainer.OrganizationID == organizationID && retainer.ID == retainerID {
+			found := *retainer
+			return &found, nil
+		}
+	}
+	return nil, ErrRetainerNotFound
+}
+
+func (r *InMemRetainerRepository) InsertRetainer(ctx context.Context, retainer *Retainer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *retainer
+	r.retainers = append(r.retainers, &inserted)
+	return nil
+}
+
+func (r *InMemRetainerRepository) UpdateRetainer(ctx context.Context, retainer *Retainer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.retainers {
+		if existing.OrganizationID == retainer.OrganizationID && existing.ID == retainer.ID {
+			updated := *retainer
+			r.retainers[i] = &updated
+			return nil
+		}
+	}
+	return ErrRetainerNotFound
+}
+
+func (r *InMemRetainerRepository) DeleteRetainer(ctx context.Context, organizationID, retainerID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, retainer := range r.retainers {
+		if retainer.OrganizationID == organizationID && retainer.ID == retainerID {
+			r.retainers = append(r.retainers[:i], r.retainers[i+1:]...)
+			delete(r.alerts, retainerID)
+			return nil
+		}
+	}
+	return ErrRetainerNotFound
+}
+
+func (r *InMemRetainerRepository) FindConsumption(ctx context.Context, organizationID, clientID uuid.UUID, start, end time.Time) ([]*RetainerConsumptionItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var items []*RetainerConsumptionItem
+	for _, item := range r.consumption {
+		month := time.Date(item.Year, time.Month(item.Month), 1, 0, 0, 0, 0, time.UTC)
+		if month.Before(monthOf(start)) || !month.Before(end) {
+			continue
+		}
+		found := *item
+		items = append(items, &found)
+	}
+	return items, nil
+}
+
+func (r *InMemRetainerRepository) FindRetainersDueForAlert(ctx context.Context, month time.Time) ([]*Retainer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var retainers []*Retainer
+	for _, retainer := range r.retainers {
+		if !retainer.IsActive(month) || r.alerts[retainer.ID][month] {
+			continue
+		}
+		found := *retainer
+		retainers = append(retainers, &found)
+	}
+	return retainers, nil
+}
+
+func (r *InMemRetainerRepository) InsertRetainerAlert(ctx context.Context, retainer *Retainer, month time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.alerts[retainer.ID] == nil {
+		r.alerts[retainer.ID] = make(map[time.Time]bool)
+	}
+	r.alerts[retainer.ID][month] = true
+	return nil
+}
+
+func (r *InMemRetainerRepository) FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if organizationID != shared.OrganizationIDSample {
+		return nil, nil
+	}
+
+	teamMembers := make([]*TeamMember, len(r.teamMembers))
+	for i, teamMember := range r.teamMembers {
+		found := *teamMember
+		teamMembers[i] = &found
+	}
+	return teamMembers, nil
+}
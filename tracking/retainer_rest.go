@@ -0,0 +1,338 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+// defaultRetainerAlertPercent is the consumed percentage of a month admins are alerted at by default
+const defaultRetainerAlertPercent = 80
+
+type retainerModel struct {
+	ID            string     `json:"id,omitempty"`
+	ClientID      string     `json:"clientId"`
+	ClientName    string     `json:"clientName,omitempty"`
+	HoursPerMonth float64    `json:"hoursPerMonth" validate:"gt=0,max=744"`
+	Rollover      bool       `json:"rollover"`
+	StartMonth    string     `json:"startMonth"`
+	EndMonth      string     `json:"endMonth,omitempty"`
+	AlertPercent  int        `json:"alertPercent" validate:"omitempty,min=1,max=100"`
+	Links         *hal.Links `json:"_links,omitempty"`
+}
+
+type retainersModel struct {
+	Embedded struct {
+		RetainerModels []*retainerModel `json:"retainers"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type retainerMonthModel struct {
+	Month            string `json:"month"`
+	IncludedMinutes  int    `json:"includedMinutes"`
+	RolloverMinutes  int    `json:"rolloverMinutes"`
+	AvailableMinutes int    `json:"availableMinutes"`
+	ConsumedMinutes  int    `json:"consumedMinutes"`
+	RemainingMinutes int    `json:"remainingMinutes"`
+	OverageMinutes   int    `json:"overageMinutes"`
+	ConsumedPercent  int    `json:"consumedPercent"`
+	NearlyExhausted  bool   `json:"nearlyExhausted"`
+}
+
+type retainerConsumptionModel struct {
+	RetainerID string                `json:"retainerId"`
+	ClientName string                `json:"clientName"`
+	Months     []*retainerMonthModel `json:"months"`
+	Links      *hal.Links            `json:"_links"`
+}
+
+type RetainerRestHandlers struct {
+	config          *shared.Config
+	retainerService *RetainerService
+}
+
+func NewRetainerRestHandlers(config *shared.Config, retainerService *RetainerService) *RetainerRestHandlers {
+	return &RetainerRestHandlers{
+		config:          config,
+		retainerService: retainerService,
+	}
+}
+
+func (a *RetainerRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/retainers", a.HandleGetRetainers())
+	r.Post("/retainers", a.HandleCreateRetainer())
+	r.Get("/retainers/{retainer-id}", a.HandleGetRetainer())
+	r.Put("/retainers/{retainer-id}", a.HandleUpdateRetainer())
+	r.Delete("/retainers/{retainer-id}", a.HandleDeleteRetainer())
+	r.Get("/retainers/{retainer-id}/consumption", a.HandleGetRetainerConsumption())
+}
+
+func (a *RetainerRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetRetainers reads the retainers of the organization
+func (a *RetainerRestHandlers) HandleGetRetainers() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	retainerService := a.retainerService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		retainers, err := retainerService.ReadRetainers(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		retainerModels := make([]*retainerModel, len(retainers))
+		for i, retainer := range retainers {
+			retainerModels[i] = mapToRetainerModel(retainer)
+		}
+
+		retainersModel := &retainersModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		retainersModel.Embedded.RetainerModels = retainerModels
+
+		shared.RenderJSON(w, retainersModel)
+	}
+}
+
+// HandleGetRetainer reads a retainer
+func (a *RetainerRestHandlers) HandleGetRetainer() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	retainerService := a.retainerService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		retainerID, err := uuid.Parse(chi.URLParam(r, "retainer-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		retainer, err := retainerService.ReadRetainer(r.Context(), principal, retainerID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToRetainerModel(retainer))
+	}
+}
+
+// HandleCreateRetainer adds a retainer for a client
+func (a *RetainerRestHandlers) HandleCreateRetainer() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	retainerService := a.retainerService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		retainerModel, retainerToCreate, ok := decodeRetainer(w, r, validator)
+		if !ok {
+			return
+		}
+
+		clientID, err := uuid.Parse(retainerModel.ClientID)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "retainer not valid", shared.NewInvalidParam("clientId", "uuid", "clientId must be a client id"))
+			return
+		}
+		retainerToCreate.ClientID = clientID
+
+		retainer, err := retainerService.CreateRetainer(r.Context(), principal, retainerToCreate)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToRetainerModel(retainer))
+	}
+}
+
+// HandleUpdateRetainer changes a retainer
+func (a *RetainerRestHandlers) HandleUpdateRetainer() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	validator := shared.NewValidator()
+	retainerService := a.retainerService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		retainerID, err := uuid.Parse(chi.URLParam(r, "retainer-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		_, retainerToUpdate, ok := decodeRetainer(w, r, validator)
+		if !ok {
+			return
+		}
+		retainerToUpdate.ID = retainerID
+
+		retainer, err := retainerService.UpdateRetainer(r.Context(), principal, retainerToUpdate)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToRetainerModel(retainer))
+	}
+}
+
+// HandleDeleteRetainer removes a retainer
+func (a *RetainerRestHandlers) HandleDeleteRetainer() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	retainerService := a.retainerService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		retainerID, err := uuid.Parse(chi.URLParam(r, "retainer-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = retainerService.DeleteRetainer(r.Context(), principal, retainerID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleGetRetainerConsumption reads the time available and consumed of a retainer per month,
+// up to the month of the query param month like 2024-03 or the current month
+func (a *RetainerRestHandlers) HandleGetRetainerConsumption() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	retainerService := a.retainerService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		retainerID, err := uuid.Parse(chi.URLParam(r, "retainer-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		until := time.Now()
+		if monthParam := r.URL.Query().Get("month"); monthParam != "" {
+			until, err = time.Parse("2006-01", monthParam)
+			if err != nil {
+				shared.RenderValidationProblemJSON(w, "invalid query params", shared.NewInvalidParam("month", "month", "month must be a month like 2024-03"))
+				return
+			}
+		}
+
+		retainer, months, err := retainerService.ReadRetainerConsumption(r.Context(), principal, retainerID, until)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		consumptionModel := &retainerConsumptionModel{
+			RetainerID: retainer.ID.String(),
+			ClientName: retainer.ClientName,
+			Months:     make([]*retainerMonthModel, len(months)),
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+				hal.NewLink("retainer", fmt.Sprintf("/api/retainers/%s", retainer.ID)),
+			),
+		}
+		for i, month := range months {
+			consumptionModel.Months[i] = &retainerMonthModel{
+				Month:            month.Month.Format("2006-01"),
+				IncludedMinutes:  month.IncludedMinutes,
+				RolloverMinutes:  month.RolloverMinutes,
+				AvailableMinutes: month.AvailableMinutes(),
+				ConsumedMinutes:  month.ConsumedMinutes,
+				RemainingMinutes: month.RemainingMinutes(),
+				OverageMinutes:   month.OverageMinutes(),
+				ConsumedPercent:  month.ConsumedPercent(),
+				NearlyExhausted:  month.IsNearlyExhausted(retainer.AlertPercent),
+			}
+		}
+
+		shared.RenderJSON(w, consumptionModel)
+	}
+}
+
+func decodeRetainer(w http.ResponseWriter, r *http.Request, validator *validator.Validate) (*retainerModel, *Retainer, bool) {
+	var retainerModel retainerModel
+	err := json.NewDecoder(r.Body).Decode(&retainerModel)
+	if err != nil {
+		shared.RenderValidationProblemJSON(w, "retainer not valid", err)
+		return nil, nil, false
+	}
+
+	err = validator.Struct(retainerModel)
+	if err != nil {
+		shared.RenderValidationProblemJSON(w, "retainer not valid", err)
+		return nil, nil, false
+	}
+
+	startMonth, err := time.Parse("2006-01", retainerModel.StartMonth)
+	if err != nil {
+		shared.RenderValidationProblemJSON(w, "retainer not valid", shared.NewInvalidParam("startMonth", "month", "startMonth must be a month like 2024-03"))
+		return nil, nil, false
+	}
+
+	retainer := &Retainer{
+		MinutesPerMonth: int(math.Round(retainerModel.HoursPerMonth * 60)),
+		Rollover:        retainerModel.Rollover,
+		StartMonth:      startMonth,
+		AlertPercent:    retainerModel.AlertPercent,
+	}
+	if retainer.AlertPercent == 0 {
+		retainer.AlertPercent = defaultRetainerAlertPercent
+	}
+
+	if retainerModel.EndMonth != "" {
+		endMonth, err := time.Parse("2006-01", retainerModel.EndMonth)
+		if err != nil || endMonth.Before(startMonth) {
+			shared.RenderValidationProblemJSON(w, "retainer not valid", shared.NewInvalidParam("endMonth", "month", "endMonth must be a month like 2024-03 not before startMonth"))
+			return nil, nil, false
+		}
+		retainer.EndMonth = &endMonth
+	}
+	return &retainerModel, retainer, true
+}
+
+func mapToRetainerModel(retainer *Retainer) *retainerModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/retainers/%s", retainer.ID))
+
+	retainerModel := &retainerModel{
+		ID:            retainer.ID.String(),
+		ClientID:      retainer.ClientID.String(),
+		ClientName:    retainer.ClientName,
+		HoursPerMonth: float64(retainer.MinutesPerMonth) / 60.0,
+		Rollover:      retainer.Rollover,
+		StartMonth:    retainer.StartMonth.Format("2006-01"),
+		AlertPercent:  retainer.AlertPercent,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("consumption", selfLink.Href()+"/consumption"),
+			hal.NewLink("edit", selfLink.Href()),
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+	if retainer.EndMonth != nil {
+		retainerModel.EndMonth = retainer.EndMonth.Format("2006-01")
+	}
+	return retainerModel
+}
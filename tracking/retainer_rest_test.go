@@ -0,0 +1,91 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleRetainers(t *testing.T) {
+	is := is.New(t)
+
+	retainerRepository := NewInMemRetainerRepository()
+	retainerRepository.consumption = []*RetainerConsumptionItem{
+		{Year: 2024, Month: 1, DurationInMinutesTotal: 300},
+		{Year: 2024, Month: 2, DurationInMinutesTotal: 540},
+	}
+	a := NewRetainerRestHandlers(&shared.Config{}, newInMemRetainerService(shared.NewInMemMailResource(), retainerRepository))
+	admin := &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "admin",
+		Roles:          []string{"ROLE_ADMIN"},
+	}
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, admin)))
+		})
+	})
+	a.RegisterProtected(router)
+
+	var retainerID string
+
+	t.Run("CreateRetainer", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/retainers", strings.NewReader(`{"clientId": "`+clientIDSample.String()+`", "hoursPerMonth": 10, "rollover": true, "startMonth": "2024-01"}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+		var retainerModel retainerModel
+		err := json.NewDecoder(httpRec.Body).Decode(&retainerModel)
+		is.NoErr(err)
+		is.Equal(retainerModel.AlertPercent, defaultRetainerAlertPercent)
+		retainerID = retainerModel.ID
+	})
+
+	t.Run("CreateRetainerEndBeforeStart", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/retainers", strings.NewReader(`{"clientId": "`+clientIDSample.String()+`", "hoursPerMonth": 10, "startMonth": "2024-03", "endMonth": "2024-01"}`))
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("GetRetainerConsumption", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/retainers/"+retainerID+"/consumption?month=2024-02", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		var consumptionModel retainerConsumptionModel
+		err := json.NewDecoder(httpRec.Body).Decode(&consumptionModel)
+		is.NoErr(err)
+		is.Equal(len(consumptionModel.Months), 2)
+		is.Equal(consumptionModel.Months[1].Month, "2024-02")
+		is.Equal(consumptionModel.Months[1].AvailableMinutes, 900)
+		is.Equal(consumptionModel.Months[1].ConsumedPercent, 60)
+		is.True(!consumptionModel.Months[1].NearlyExhausted)
+	})
+
+	t.Run("AsUser", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/retainers", nil)
+		r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+		}))
+
+		a.HandleGetRetainers()(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+	})
+}
@@ -0,0 +1,271 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"text/template"
+	"time"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+)
+
+const retainerAlertJobType = "retainer-alert"
+
+var retainerAlertTemplate = template.Must(template.New("retainer-alert").Parse(
+	`Hello {{ .Name }},
+
+the retainer of {{ .Retainer.ClientName }} is nearly exhausted in {{ .Month.Format "January 2006" }}:
+{{ .Consumed }} of {{ .Available }} consumed ({{ .Percent }}%).
+
+See the consumption at {{ .ConsumptionLink }}
+`))
+
+// retainerAlert is the mail to an admin about a nearly exhausted retainer
+type retainerAlert struct {
+	EMail           string
+	Name            string
+	Retainer        *Retainer
+	Month           time.Time
+	Consumed        string
+	Available       string
+	Percent         int
+	ConsumptionLink string
+}
+
+// RetainerService manages the retainers of clients, tracks their consumption and alerts admins
+// when a retainer is nearly exhausted
+type RetainerService struct {
+	config             *shared.Config
+	repositoryTxer     shared.RepositoryTxer
+	outbox             shared.Outbox
+	retainerRepository RetainerRepository
+	clientRepository   ClientRepository
+}
+
+// NewRetainerService creates a new service for retainers, alerting about nearly exhausted retainers in the background if enabled
+func NewRetainerService(
+	config *shared.Config,
+	repositoryTxer shared.RepositoryTxer,
+	outbox shared.Outbox,
+	jobService *shared.JobService,
+	retainerRepository RetainerRepository,
+	clientRepository ClientRepository,
+) *RetainerService {
+	s := &RetainerService{
+		config:             config,
+		repositoryTxer:     repositoryTxer,
+		outbox:             outbox,
+		retainerRepository: retainerRepository,
+		clientRepository:   clientRepository,
+	}
+
+	if config.RetainerAlerts {
+		jobService.RegisterHandler(retainerAlertJobType, s.handleRetainerAlertJob)
+		jobService.Schedule(retainerAlertJobType, time.Hour)
+	}
+
+	return s
+}
+
+// ReadRetainers reads the retainers of the organization
+func (s *RetainerService) ReadRetainers(ctx context.Context, principal *shared.Principal) ([]*Retainer, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.retainerRepository.FindRetainers(ctx, principal.OrganizationID)
+}
+
+// ReadRetainer reads a retainer of the organization
+func (s *RetainerService) ReadRetainer(ctx context.Context, principal *shared.Principal, retainerID uuid.UUID) (*Retainer, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.retainerRepository.FindRetainerByID(ctx, principal.OrganizationID, retainerID)
+}
+
+// CreateRetainer adds a retainer for a client
+func (s *RetainerService) CreateRetainer(ctx context.Context, principal *shared.Principal, retainer *Retainer) (*Retainer, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	client, err := s.clientRepository.FindClientByID(ctx, principal.OrganizationID, retainer.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	retainer.ID = uuid.New()
+	retainer.OrganizationID = principal.OrganizationID
+	retainer.ClientName = client.Name
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.retainerRepository.InsertRetainer(ctx, retainer)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return retainer, nil
+}
+
+// UpdateRetainer changes the hours, rollover, months and alert of a retainer, the client stays the same
+func (s *RetainerService) UpdateRetainer(ctx context.Context, principal *shared.Principal, retainer *Retainer) (*Retainer, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	currentRetainer, err := s.retainerRepository.FindRetainerByID(ctx, principal.OrganizationID, retainer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	retainer.OrganizationID = principal.OrganizationID
+	retainer.ClientID = currentRetainer.ClientID
+	retainer.ClientName = currentRetainer.ClientName
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.retainerRepository.UpdateRetainer(ctx, retainer)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return retainer, nil
+}
+
+// DeleteRetainer removes a retainer
+func (s *RetainerService) DeleteRetainer(ctx context.Context, principal *shared.Principal, retainerID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.retainerRepository.DeleteRetainer(ctx, principal.OrganizationID, retainerID)
+		},
+	)
+}
+
+// ReadRetainerConsumption reads the time available and consumed of a retainer in each month up to the month of until
+func (s *RetainerService) ReadRetainerConsumption(ctx context.Context, principal *shared.Principal, retainerID uuid.UUID, until time.Time) (*Retainer, []*RetainerMonth, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, nil, shared.ErrForbidden
+	}
+
+	retainer, err := s.retainerRepository.FindRetainerByID(ctx, principal.OrganizationID, retainerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	months, err := s.consumption(ctx, retainer, until)
+	if err != nil {
+		return nil, nil, err
+	}
+	return retainer, months, nil
+}
+
+// NotifyNearlyExhaustedRetainers alerts the admins once a month about each retainer whose consumed time
+// reached the alert percentage of the time available in the current month
+func (s *RetainerService) NotifyNearlyExhaustedRetainers(ctx context.Context, now time.Time) error {
+	month := monthOf(now)
+
+	retainers, err := s.retainerRepository.FindRetainersDueForAlert(ctx, month)
+	if err != nil {
+		return err
+	}
+
+	alerted := 0
+	for _, retainer := range retainers {
+		months, err := s.consumption(ctx, retainer, now)
+		if err != nil {
+			return err
+		}
+		if len(months) == 0 {
+			continue
+		}
+
+		currentMonth := months[len(months)-1]
+		if !currentMonth.Month.Equal(month) || !currentMonth.IsNearlyExhausted(retainer.AlertPercent) {
+			continue
+		}
+
+		err = s.notifyNearlyExhaustedRetainer(ctx, retainer, currentMonth)
+		if err != nil {
+			return err
+		}
+		alerted++
+	}
+
+	if alerted > 0 {
+		log.Printf("alerted about %v nearly exhausted retainers", alerted)
+	}
+	return nil
+}
+
+func (s *RetainerService) notifyNearlyExhaustedRetainer(ctx context.Context, retainer *Retainer, retainerMonth *RetainerMonth) error {
+	teamMembers, err := s.retainerRepository.FindTeamMembers(ctx, retainer.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Retainer of %s nearly exhausted", retainer.ClientName)
+	consumptionLink := fmt.Sprintf("%s/api/retainers/%s/consumption", s.config.Webroot, retainer.ID)
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			for _, teamMember := range teamMembers {
+				if !teamMember.Admin || teamMember.EMail == "" {
+					continue
+				}
+
+				body := &bytes.Buffer{}
+				err := retainerAlertTemplate.Execute(body, &retainerAlert{
+					EMail:           teamMember.EMail,
+					Name:            teamMember.Name,
+					Retainer:        retainer,
+					Month:           retainerMonth.Month,
+					Consumed:        time_utils.FormatMinutesAsDuration(float64(retainerMonth.ConsumedMinutes)),
+					Available:       time_utils.FormatMinutesAsDuration(float64(retainerMonth.AvailableMinutes())),
+					Percent:         retainerMonth.ConsumedPercent(),
+					ConsumptionLink: consumptionLink,
+				})
+				if err != nil {
+					return err
+				}
+
+				err = s.outbox.SendMail(ctx, retainer.OrganizationID, teamMember.EMail, subject, body.String())
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			return s.retainerRepository.InsertRetainerAlert(ctx, retainer, retainerMonth.Month)
+		},
+	)
+}
+
+func (s *RetainerService) consumption(ctx context.Context, retainer *Retainer, until time.Time) ([]*RetainerMonth, error) {
+	items, err := s.retainerRepository.FindConsumption(ctx, retainer.OrganizationID, retainer.ClientID, retainer.StartMonth, monthOf(until).AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+	return retainer.Consumption(until, items), nil
+}
+
+func (s *RetainerService) handleRetainerAlertJob(ctx context.Context, job *shared.Job) error {
+	return s.NotifyNearlyExhaustedRetainers(ctx, time.Now())
+}
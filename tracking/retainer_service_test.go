@@ -0,0 +1,104 @@
+package tracking
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func newInMemRetainerService(mailResource shared.MailResource, retainerRepository RetainerRepository) *RetainerService {
+	repositoryTxer := shared.NewInMemRepositoryTxer()
+	return NewRetainerService(
+		&shared.Config{Webroot: "http://localhost:8080", RetainerAlerts: true},
+		repositoryTxer,
+		shared.NewInMemOutbox(mailResource),
+		shared.NewJobService(repositoryTxer, shared.NewInMemJobRepository()),
+		retainerRepository,
+		NewInMemClientRepository(),
+	)
+}
+
+func TestRetainerService(t *testing.T) {
+	is := is.New(t)
+
+	s := newInMemRetainerService(shared.NewInMemMailResource(), NewInMemRetainerRepository())
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	startMonth := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.CreateRetainer(context.Background(), user, &Retainer{ClientID: clientIDSample, MinutesPerMonth: 600, StartMonth: startMonth})
+	is.Equal(err, shared.ErrForbidden)
+
+	_, err = s.CreateRetainer(context.Background(), admin, &Retainer{ClientID: uuid.New(), MinutesPerMonth: 600, StartMonth: startMonth})
+	is.Equal(err, ErrClientNotFound)
+
+	retainer, err := s.CreateRetainer(context.Background(), admin, &Retainer{ClientID: clientIDSample, MinutesPerMonth: 600, StartMonth: startMonth, AlertPercent: 80})
+	is.NoErr(err)
+	is.Equal(retainer.ClientName, "ACME Corp.")
+
+	retainer.Rollover = true
+	retainer.ClientID = uuid.New()
+	updated, err := s.UpdateRetainer(context.Background(), admin, retainer)
+	is.NoErr(err)
+	is.True(updated.Rollover)
+	is.Equal(updated.ClientID, clientIDSample)
+
+	retainers, err := s.ReadRetainers(context.Background(), admin)
+	is.NoErr(err)
+	is.Equal(len(retainers), 1)
+
+	_, months, err := s.ReadRetainerConsumption(context.Background(), admin, retainer.ID, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	is.NoErr(err)
+	is.Equal(len(months), 3)
+
+	err = s.DeleteRetainer(context.Background(), admin, retainer.ID)
+	is.NoErr(err)
+
+	_, err = s.ReadRetainer(context.Background(), admin, retainer.ID)
+	is.Equal(err, ErrRetainerNotFound)
+}
+
+func TestNotifyNearlyExhaustedRetainers(t *testing.T) {
+	is := is.New(t)
+
+	retainerRepository := NewInMemRetainerRepository()
+	retainerRepository.retainers = []*Retainer{
+		{
+			ID:              uuid.New(),
+			OrganizationID:  shared.OrganizationIDSample,
+			ClientID:        clientIDSample,
+			ClientName:      "ACME Corp.",
+			MinutesPerMonth: 600,
+			StartMonth:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			AlertPercent:    80,
+		},
+	}
+	retainerRepository.consumption = []*RetainerConsumptionItem{
+		{Year: 2024, Month: 3, DurationInMinutesTotal: 510},
+	}
+
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemRetainerService(mailResource, retainerRepository)
+
+	// not exhausted in february
+	err := s.NotifyNearlyExhaustedRetainers(context.Background(), time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 0)
+
+	now := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	err = s.NotifyNearlyExhaustedRetainers(context.Background(), now)
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 1)
+	is.True(strings.HasPrefix(mailResource.Mails[0], "admin@baralga.com"))
+	is.True(strings.Contains(mailResource.Mails[0], "8:30 h of 10:00 h consumed (85%)"))
+
+	// alerted only once a month
+	err = s.NotifyNearlyExhaustedRetainers(context.Background(), now.Add(time.Hour))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 1)
+}
@@ -0,0 +1,87 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const maxScanTagNameLength = 100
+
+var (
+	ErrScanTagNotFound     = shared.NewDomainError("scan-tag:not-found", http.StatusNotFound, "scan tag not found")
+	ErrScanSessionNotFound = shared.NewDomainError("scan-tag:session-not-found", http.StatusNotFound, "scan session not found")
+)
+
+// ScanTag is a QR code or NFC tag of a project, like a tag per project room or client site,
+// scanning it starts or stops tracking for the project
+type ScanTag struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ProjectID      uuid.UUID
+	Name           string
+	Code           string
+	Location       string
+	CreatedAt      time.Time
+}
+
+// ScanSession is the tracking of a user started by scanning a tag, it's open until the next scan
+type ScanSession struct {
+	OrganizationID uuid.UUID
+	Username       string
+	TagID          uuid.UUID
+	Start          time.Time
+}
+
+// ScanResult is the outcome of a scan, the activity of the stopped session and the started session
+type ScanResult struct {
+	Stopped *Activity
+	Started *ScanSession
+}
+
+type ScanTagRepository interface {
+	FindScanTags(ctx context.Context, organizationID uuid.UUID) ([]*ScanTag, error)
+	FindScanTagByID(ctx context.Context, organizationID, tagID uuid.UUID) (*ScanTag, error)
+	FindScanTagByCode(ctx context.Context, organizationID uuid.UUID, code string) (*ScanTag, error)
+	InsertScanTag(ctx context.Context, tag *ScanTag) error
+	DeleteScanTag(ctx context.Context, organizationID, tagID uuid.UUID) error
+	FindScanSession(ctx context.Context, organizationID uuid.UUID, username string) (*ScanSession, error)
+	InsertScanSession(ctx context.Context, session *ScanSession) error
+	DeleteScanSession(ctx context.Context, organizationID uuid.UUID, username string) error
+}
+
+// Validate checks that the tag has a name and a known location if any
+func (t *ScanTag) Validate() error {
+	if t.Name == "" {
+		return shared.NewInvalidParam("name", "required", "name is required")
+	}
+	if utf8.RuneCountInString(t.Name) > maxScanTagNameLength {
+		return shared.NewInvalidParam("name", "max", fmt.Sprintf("name must not be longer than %v characters", maxScanTagNameLength))
+	}
+	if t.Location != "" && !IsValidLocation(t.Location) {
+		return shared.NewInvalidParam("location", "enum", "location must be office, home or client-site")
+	}
+	return nil
+}
+
+// ToActivity is the activity tracked from the start of the session until the end, sessions
+// shorter than a minute track no activity
+func (s *ScanSession) ToActivity(tag *ScanTag, end time.Time) *Activity {
+	end = end.Truncate(time.Minute)
+	if !end.After(s.Start) {
+		return nil
+	}
+
+	return &Activity{
+		Start:       s.Start,
+		End:         end,
+		Description: tag.Name,
+		ProjectID:   tag.ProjectID,
+		Location:    tag.Location,
+	}
+}
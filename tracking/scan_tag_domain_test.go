@@ -0,0 +1,30 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestScanSessionToActivity(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	session := &ScanSession{Start: start}
+	tag := &ScanTag{Name: "Room 1", Location: LocationOffice}
+
+	activity := session.ToActivity(tag, start.Add(90*time.Minute+30*time.Second))
+	is.Equal(activity.End, start.Add(90*time.Minute))
+	is.Equal(activity.Description, "Room 1")
+
+	is.True(session.ToActivity(tag, start.Add(30*time.Second)) == nil)
+}
+
+func TestValidateScanTag(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr((&ScanTag{Name: "Room 1"}).Validate())
+	is.True((&ScanTag{}).Validate() != nil)
+	is.True((&ScanTag{Name: "Room 1", Location: "moon"}).Validate() != nil)
+}
@@ -0,0 +1,224 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbScanTagRepository is a SQL database repository for scan tags and the sessions started by scanning them
+type DbScanTagRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ ScanTagRepository = (*DbScanTagRepository)(nil)
+
+// NewDbScanTagRepository creates a new SQL database repository for scan tags
+func NewDbScanTagRepository(connPool *pgxpool.Pool) *DbScanTagRepository {
+	return &DbScanTagRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbScanTagRepository) FindScanTags(ctx context.Context, organizationID uuid.UUID) ([]*ScanTag, error) {
+	rows, err := shared.SelectAll[scanTagRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[scanTagRow]()+`
+		 FROM scan_tags
+		 WHERE org_id = $1
+		 ORDER BY name ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]*ScanTag, len(rows))
+	for i, row := range rows {
+		tags[i] = row.toScanTag()
+	}
+	return tags, nil
+}
+
+func (r *DbScanTagRepository) FindScanTagByID(ctx context.Context, organizationID, tagID uuid.UUID) (*ScanTag, error) {
+	row, err := shared.SelectOne[scanTagRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[scanTagRow]()+`
+		 FROM scan_tags
+		 WHERE tag_id = $1 AND org_id = $2`,
+		tagID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScanTagNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toScanTag(), nil
+}
+
+func (r *DbScanTagRepository) FindScanTagByCode(ctx context.Context, organizationID uuid.UUID, code string) (*ScanTag, error) {
+	row, err := shared.SelectOne[scanTagRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[scanTagRow]()+`
+		 FROM scan_tags
+		 WHERE code = $1 AND org_id = $2`,
+		code, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScanTagNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toScanTag(), nil
+}
+
+func (r *DbScanTagRepository) InsertScanTag(ctx context.Context, tag *ScanTag) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO scan_tags
+		   (tag_id, org_id, project_id, name, code, location, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7)`,
+		tag.ID,
+		tag.OrganizationID,
+		tag.ProjectID,
+		tag.Name,
+		tag.Code,
+		sql.NullString{String: tag.Location, Valid: tag.Location != ""},
+		tag.CreatedAt,
+	)
+	return err
+}
+
+func (r *DbScanTagRepository) DeleteScanTag(ctx context.Context, organizationID, tagID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM scan_tags
+		 WHERE tag_id = $1 AND org_id = $2`,
+		tagID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrScanTagNotFound
+	}
+	return nil
+}
+
+func (r *DbScanTagRepository) FindScanSession(ctx context.Context, organizationID uuid.UUID, username string) (*ScanSession, error) {
+	row, err := shared.SelectOne[scanSessionRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[scanSessionRow]()+`
+		 FROM scan_sessions
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScanSessionNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toScanSession(), nil
+}
+
+func (r *DbScanTagRepository) InsertScanSession(ctx context.Context, session *ScanSession) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO scan_sessions
+		   (org_id, username, tag_id, started_at)
+		 VALUES
+		   ($1, $2, $3, $4)`,
+		session.OrganizationID,
+		session.Username,
+		session.TagID,
+		session.Start,
+	)
+	return err
+}
+
+func (r *DbScanTagRepository) DeleteScanSession(ctx context.Context, organizationID uuid.UUID, username string) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM scan_sessions
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrScanSessionNotFound
+	}
+	return nil
+}
+
+type scanTagRow struct {
+	ID             uuid.UUID `db:"tag_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	ProjectID      uuid.UUID `db:"project_id"`
+	Name           string    `db:"name"`
+	Code           string    `db:"code"`
+	Location       *string   `db:"location"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (r *scanTagRow) toScanTag() *ScanTag {
+	tag := &ScanTag{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		ProjectID:      r.ProjectID,
+		Name:           r.Name,
+		Code:           r.Code,
+		CreatedAt:      r.CreatedAt,
+	}
+	if r.Location != nil {
+		tag.Location = *r.Location
+	}
+	return tag
+}
+
+type scanSessionRow struct {
+	OrganizationID uuid.UUID `db:"org_id"`
+	Username       string    `db:"username"`
+	TagID          uuid.UUID `db:"tag_id"`
+	Start          time.Time `db:"started_at"`
+}
+
+func (r *scanSessionRow) toScanSession() *ScanSession {
+	return &ScanSession{
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		TagID:          r.TagID,
+		Start:          r.Start,
+	}
+}
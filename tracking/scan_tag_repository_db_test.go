@@ -0,0 +1,86 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestScanTagRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	scanTagRepository := NewDbScanTagRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	tag := &ScanTag{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		ProjectID:      shared.ProjectIDSample,
+		Name:           "Room 1",
+		Code:           "my-code",
+		Location:       LocationOffice,
+		CreatedAt:      time.Now(),
+	}
+
+	t.Run("InsertScanTagAndSession", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return scanTagRepository.InsertScanTag(ctx, tag)
+			},
+			func(ctx context.Context) error {
+				return scanTagRepository.InsertScanSession(ctx, &ScanSession{
+					OrganizationID: shared.OrganizationIDSample,
+					Username:       "user1",
+					TagID:          tag.ID,
+					Start:          time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC),
+				})
+			},
+		)
+		is.NoErr(err)
+
+		found, err := scanTagRepository.FindScanTagByCode(context.Background(), shared.OrganizationIDSample, "my-code")
+		is.NoErr(err)
+		is.Equal(found.Location, LocationOffice)
+
+		session, err := scanTagRepository.FindScanSession(context.Background(), shared.OrganizationIDSample, "user1")
+		is.NoErr(err)
+		is.Equal(session.TagID, tag.ID)
+	})
+
+	t.Run("DeleteScanTag", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return scanTagRepository.DeleteScanTag(ctx, shared.OrganizationIDSample, tag.ID)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = scanTagRepository.FindScanSession(context.Background(), shared.OrganizationIDSample, "user1")
+		is.Equal(err, ErrScanSessionNotFound)
+	})
+}
@@ -0,0 +1,125 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemScanTagRepository struct {
+	mu       sync.Mutex
+	tags     []*ScanTag
+	sessions []*ScanSession
+}
+
+var _ ScanTagRepository = (*InMemScanTagRepository)(nil)
+
+func NewInMemScanTagRepository() *InMemScanTagRepository {
+	return &InMemScanTagRepository{}
+}
+
+func (r *InMemScanTagRepository) FindScanTags(ctx context.Context, organizationID uuid.UUID) ([]*ScanTag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var tags []*ScanTag
+	for _, tag := range r.tags {
+		if tag.OrganizationID == organizationID {
+			found := *tag
+			tags = append(tags, &found)
+		}
+	}
+	return tags, nil
+}
+
+func (r *InMemScanTagRepository) FindScanTagByID(ctx context.Context, organizationID, tagID uuid.UUID) (*ScanTag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, tag := range r.tags {
+		if tag.OrganizationID == organizationID && tag.ID == tagID {
+			found := *tag
+			return &found, nil
+		}
+	}
+	return nil, ErrScanTagNotFound
+}
+
+func (r *InMemScanTagRepository) FindScanTagByCode(ctx context.Context, organizationID uuid.UUID, code string) (*ScanTag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, tag := range r.tags {
+		if tag.OrganizationID == organizationID && tag.Code == code {
+			found := *tag
+			return &found, nil
+		}
+	}
+	return nil, ErrScanTagNotFound
+}
+
+func (r *InMemScanTagRepository) InsertScanTag(ctx context.Context, tag *ScanTag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *tag
+	r.tags = append(r.tags, &inserted)
+	return nil
+}
+
+func (r *InMemScanTagRepository) DeleteScanTag(ctx context.Context, organizationID, tagID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, tag := range r.tags {
+		if tag.OrganizationID == organizationID && tag.ID == tagID {
+			r.tags = append(r.tags[:i], r.tags[i+1:]...)
+
+			var sessions []*ScanSession
+			for _, session := range r.sessions {
+				if session.TagID != tagID {
+					sessions = append(sessions, session)
+				}
+			}
+			r.sessions = sessions
+			return nil
+		}
+	}
+	return ErrScanTagNotFound
+}
+
+func (r *InMemScanTagRepository) FindScanSession(ctx context.Context, organizationID uuid.UUID, username string) (*ScanSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, session := range r.sessions {
+		if session.OrganizationID == organizationID && session.Username == username {
+			found := *session
+			return &found, nil
+		}
+	}
+	return nil, ErrScanSessionNotFound
+}
+
+func (r *InMemScanTagRepository) InsertScanSession(ctx context.Context, session *ScanSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *session
+	r.sessions = append(r.sessions, &inserted)
+	return nil
+}
+
+func (r *InMemScanTagRepository) DeleteScanSession(ctx context.Context, organizationID uuid.UUID, username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, session := range r.sessions {
+		if session.OrganizationID == organizationID && session.Username == username {
+			r.sessions = append(r.sessions[:i], r.sessions[i+1:]...)
+			return nil
+		}
+	}
+	return ErrScanSessionNotFound
+}
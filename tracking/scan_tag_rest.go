@@ -0,0 +1,215 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type scanTagModel struct {
+	ID        string     `json:"id"`
+	ProjectID string     `json:"projectId"`
+	Name      string     `json:"name"`
+	Code      string     `json:"code"`
+	Location  string     `json:"location,omitempty"`
+	Links     *hal.Links `json:"_links"`
+}
+
+type scanTagsModel struct {
+	Embedded *embeddedScanTags `json:"_embedded"`
+	Links    *hal.Links        `json:"_links"`
+}
+
+type embeddedScanTags struct {
+	ScanTagModels []*scanTagModel `json:"scanTags"`
+}
+
+type scanModel struct {
+	Code string `json:"code"`
+}
+
+type scanSessionModel struct {
+	TagID string `json:"tagId"`
+	Start string `json:"start"`
+}
+
+type scanResultModel struct {
+	Stopped *activityModel    `json:"stopped,omitempty"`
+	Started *scanSessionModel `json:"started,omitempty"`
+}
+
+type ScanTagRestHandlers struct {
+	config         *shared.Config
+	scanTagService *ScanTagService
+}
+
+func NewScanTagRestHandlers(config *shared.Config, scanTagService *ScanTagService) *ScanTagRestHandlers {
+	return &ScanTagRestHandlers{
+		config:         config,
+		scanTagService: scanTagService,
+	}
+}
+
+func (a *ScanTagRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/scan-tags", a.HandleGetScanTags())
+	r.Post("/scan-tags", a.HandleCreateScanTag())
+	r.Delete("/scan-tags/{tag-id}", a.HandleDeleteScanTag())
+	r.Post("/scan-tags/scan", a.HandleScan())
+}
+
+func (a *ScanTagRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetScanTags reads the scan tags of the organization
+func (a *ScanTagRestHandlers) HandleGetScanTags() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	scanTagService := a.scanTagService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		tags, err := scanTagService.ReadScanTags(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		tagModels := make([]*scanTagModel, len(tags))
+		for i, tag := range tags {
+			tagModels[i] = mapToScanTagModel(tag)
+		}
+
+		shared.RenderJSON(w, &scanTagsModel{
+			Embedded: &embeddedScanTags{
+				ScanTagModels: tagModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleCreateScanTag provisions a scan tag for a project
+func (a *ScanTagRestHandlers) HandleCreateScanTag() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	scanTagService := a.scanTagService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var tagModel scanTagModel
+		err := json.NewDecoder(r.Body).Decode(&tagModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "scan tag not valid", err)
+			return
+		}
+
+		projectID, err := uuid.Parse(tagModel.ProjectID)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "scan tag not valid", shared.NewInvalidParam("projectId", "uuid", "project id must be a uuid"))
+			return
+		}
+
+		tag := &ScanTag{
+			ProjectID: projectID,
+			Name:      tagModel.Name,
+			Location:  tagModel.Location,
+		}
+		err = tag.Validate()
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "scan tag not valid", err)
+			return
+		}
+
+		tag, err = scanTagService.CreateScanTag(r.Context(), principal, tag)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToScanTagModel(tag))
+	}
+}
+
+// HandleDeleteScanTag revokes a scan tag
+func (a *ScanTagRestHandlers) HandleDeleteScanTag() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	scanTagService := a.scanTagService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		tagID, err := uuid.Parse(chi.URLParam(r, "tag-id"))
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		err = scanTagService.DeleteScanTag(r.Context(), principal, tagID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+// HandleScan starts or stops tracking for the project of the tag scanned with a mobile device
+func (a *ScanTagRestHandlers) HandleScan() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	scanTagService := a.scanTagService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var scan scanModel
+		err := json.NewDecoder(r.Body).Decode(&scan)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "scan not valid", err)
+			return
+		}
+
+		result, err := scanTagService.Scan(r.Context(), principal, scan.Code, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToScanResultModel(result))
+	}
+}
+
+func mapToScanTagModel(tag *ScanTag) *scanTagModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/scan-tags/%s", tag.ID))
+	return &scanTagModel{
+		ID:        tag.ID.String(),
+		ProjectID: tag.ProjectID.String(),
+		Name:      tag.Name,
+		Code:      tag.Code,
+		Location:  tag.Location,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("project", fmt.Sprintf("/api/projects/%s", tag.ProjectID)),
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+}
+
+func mapToScanResultModel(result *ScanResult) *scanResultModel {
+	resultModel := &scanResultModel{}
+	if result.Stopped != nil {
+		resultModel.Stopped = mapToActivityModel(result.Stopped)
+	}
+	if result.Started != nil {
+		resultModel.Started = &scanSessionModel{
+			TagID: result.Started.TagID.String(),
+			Start: time_utils.FormatDateTime(result.Started.Start),
+		}
+	}
+	return resultModel
+}
@@ -0,0 +1,54 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleScan(t *testing.T) {
+	is := is.New(t)
+
+	a := NewScanTagRestHandlers(&shared.Config{}, newScanTagServiceForTest())
+
+	request := func(method, url, body string, roles ...string) *http.Request {
+		r, _ := http.NewRequest(method, url, strings.NewReader(body))
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Roles:          roles,
+		}))
+	}
+
+	httpRec := httptest.NewRecorder()
+	a.HandleCreateScanTag()(httpRec, request("POST", "/api/scan-tags", fmt.Sprintf(`{"projectId": "%s", "name": "Room 1"}`, shared.ProjectIDSample), "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreateScanTag()(httpRec, request("POST", "/api/scan-tags", `{"projectId": "invalid", "name": "Room 1"}`, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleCreateScanTag()(httpRec, request("POST", "/api/scan-tags", fmt.Sprintf(`{"projectId": "%s", "name": "Room 1"}`, shared.ProjectIDSample), "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+
+	tagModel := &scanTagModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(tagModel)
+	is.NoErr(err)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleScan()(httpRec, request("POST", "/api/scan-tags/scan", fmt.Sprintf(`{"code": "%s"}`, tagModel.Code), "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	resultModel := &scanResultModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(resultModel)
+	is.NoErr(err)
+	is.Equal(resultModel.Started.TagID, tagModel.ID)
+}
@@ -0,0 +1,161 @@
+package tracking
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// scanTagCodeBytes is the length of the random codes of scan tags
+const scanTagCodeBytes = 16
+
+// ScanTagService manages the scan tags of projects and starts and stops tracking when users scan them
+type ScanTagService struct {
+	repositoryTxer    shared.RepositoryTxer
+	scanTagRepository ScanTagRepository
+	projectRepository ProjectRepository
+	activityService   *ActitivityService
+}
+
+// NewScanTagService creates a new service for scan tags
+func NewScanTagService(repositoryTxer shared.RepositoryTxer, scanTagRepository ScanTagRepository, projectRepository ProjectRepository, activityService *ActitivityService) *ScanTagService {
+	return &ScanTagService{
+		repositoryTxer:    repositoryTxer,
+		scanTagRepository: scanTagRepository,
+		projectRepository: projectRepository,
+		activityService:   activityService,
+	}
+}
+
+// ReadScanTags reads the scan tags of the organization, only admins manage tags
+func (s *ScanTagService) ReadScanTags(ctx context.Context, principal *shared.Principal) ([]*ScanTag, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.scanTagRepository.FindScanTags(ctx, principal.OrganizationID)
+}
+
+// CreateScanTag provisions a tag with a new code for the project, the code is printed as QR code or written to an NFC tag
+func (s *ScanTagService) CreateScanTag(ctx context.Context, principal *shared.Principal, tag *ScanTag) (*ScanTag, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	_, err := s.projectRepository.FindProjectByID(ctx, principal.OrganizationID, tag.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := newScanTagCode()
+	if err != nil {
+		return nil, err
+	}
+
+	tag.ID = uuid.New()
+	tag.OrganizationID = principal.OrganizationID
+	tag.Code = code
+	tag.CreatedAt = time.Now()
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.scanTagRepository.InsertScanTag(ctx, tag)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// DeleteScanTag revokes the tag, scanning its code no longer tracks and open sessions of the tag are dropped
+func (s *ScanTagService) DeleteScanTag(ctx context.Context, principal *shared.Principal, tagID uuid.UUID) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.scanTagRepository.DeleteScanTag(ctx, principal.OrganizationID, tagID)
+		},
+	)
+}
+
+// Scan starts tracking for the project of the tag, scanning again stops tracking with an activity
+// from the first scan until now. Scanning another tag stops the tracking and starts tracking for the other tag.
+func (s *ScanTagService) Scan(ctx context.Context, principal *shared.Principal, code string, now time.Time) (*ScanResult, error) {
+	tag, err := s.scanTagRepository.FindScanTagByCode(ctx, principal.OrganizationID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScanResult{}
+
+	session, err := s.scanTagRepository.FindScanSession(ctx, principal.OrganizationID, principal.Username)
+	if err != nil && !errors.Is(err, ErrScanSessionNotFound) {
+		return nil, err
+	}
+
+	if session != nil {
+		sessionTag := tag
+		if session.TagID != tag.ID {
+			sessionTag, err = s.scanTagRepository.FindScanTagByID(ctx, principal.OrganizationID, session.TagID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		activity := session.ToActivity(sessionTag, now)
+		if activity != nil {
+			result.Stopped, err = s.activityService.CreateActivityFromSource(ctx, principal, activity, ActivitySourceScanTag, true)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if session == nil || session.TagID != tag.ID {
+		result.Started = &ScanSession{
+			OrganizationID: principal.OrganizationID,
+			Username:       principal.Username,
+			TagID:          tag.ID,
+			Start:          now.Truncate(time.Minute),
+		}
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			if session == nil {
+				return nil
+			}
+			return s.scanTagRepository.DeleteScanSession(ctx, principal.OrganizationID, principal.Username)
+		},
+		func(ctx context.Context) error {
+			if result.Started == nil {
+				return nil
+			}
+			return s.scanTagRepository.InsertScanSession(ctx, result.Started)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func newScanTagCode() (string, error) {
+	code := make([]byte, scanTagCodeBytes)
+	_, err := rand.Read(code)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(code), nil
+}
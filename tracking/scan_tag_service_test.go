@@ -0,0 +1,83 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newScanTagServiceForTest() *ScanTagService {
+	return NewScanTagService(
+		shared.NewInMemRepositoryTxer(),
+		NewInMemScanTagRepository(),
+		NewInMemProjectRepository(),
+		&ActitivityService{
+			repositoryTxer:               shared.NewInMemRepositoryTxer(),
+			activityRepository:           NewInMemActivityRepository(),
+			locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+			breakPolicyRepository:        NewInMemBreakPolicyRepository(),
+			categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+		},
+	)
+}
+
+func TestScanStartsAndStopsTracking(t *testing.T) {
+	is := is.New(t)
+
+	s := newScanTagServiceForTest()
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	room, err := s.CreateScanTag(context.Background(), admin, &ScanTag{ProjectID: shared.ProjectIDSample, Name: "Room 1", Location: LocationOffice})
+	is.NoErr(err)
+	is.True(room.Code != "")
+
+	site, err := s.CreateScanTag(context.Background(), admin, &ScanTag{ProjectID: shared.ProjectIDSample, Name: "Client Site", Location: LocationClientSite})
+	is.NoErr(err)
+
+	result, err := s.Scan(context.Background(), user, room.Code, now)
+	is.NoErr(err)
+	is.True(result.Stopped == nil)
+	is.Equal(result.Started.TagID, room.ID)
+
+	// scanning another tag switches the tracking
+	result, err = s.Scan(context.Background(), user, site.Code, now.Add(2*time.Hour))
+	is.NoErr(err)
+	is.Equal(result.Stopped.Description, "Room 1")
+	is.Equal(result.Stopped.Location, LocationOffice)
+	is.Equal(result.Stopped.End.Sub(result.Stopped.Start), 2*time.Hour)
+	is.Equal(result.Started.TagID, site.ID)
+
+	// scanning the same tag stops the tracking
+	result, err = s.Scan(context.Background(), user, site.Code, now.Add(3*time.Hour))
+	is.NoErr(err)
+	is.Equal(result.Stopped.Description, "Client Site")
+	is.True(result.Started == nil)
+
+	_, err = s.Scan(context.Background(), user, "unknown", now)
+	is.Equal(err, ErrScanTagNotFound)
+}
+
+func TestRevokeScanTag(t *testing.T) {
+	is := is.New(t)
+
+	s := newScanTagServiceForTest()
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+
+	tag, err := s.CreateScanTag(context.Background(), admin, &ScanTag{ProjectID: shared.ProjectIDSample, Name: "Room 1"})
+	is.NoErr(err)
+
+	err = s.DeleteScanTag(context.Background(), user, tag.ID)
+	is.Equal(err, shared.ErrForbidden)
+
+	err = s.DeleteScanTag(context.Background(), admin, tag.ID)
+	is.NoErr(err)
+
+	_, err = s.Scan(context.Background(), user, tag.Code, time.Now())
+	is.Equal(err, ErrScanTagNotFound)
+}
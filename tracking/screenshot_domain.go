@@ -0,0 +1,123 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	ScreenshotBlurNone string = "none"
+	ScreenshotBlurLow  string = "low"
+	ScreenshotBlurHigh string = "high"
+
+	// maxScreenshotSize is the maximum size of uploaded screenshots
+	maxScreenshotSize = 5 << 20
+
+	// screenshotClockSkew is the tolerance for screenshots taken shortly before or after the activity by the clock of the client
+	screenshotClockSkew = 5 * time.Minute
+
+	screenshotCleanupJobType    = "screenshot-cleanup"
+	auditActionScreenshotViewed = "screenshot:viewed"
+)
+
+// screenshotBlurs are the blur levels of screenshots ordered from the sharpest to the most blurred
+var screenshotBlurs = []string{ScreenshotBlurNone, ScreenshotBlurLow, ScreenshotBlurHigh}
+
+// screenshotContentTypes are the image formats of screenshots
+var screenshotContentTypes = []string{"image/png", "image/jpeg", "image/webp"}
+
+var (
+	ErrScreenshotsDisabled         = shared.NewDomainError("screenshot:disabled", http.StatusConflict, "screenshots are not enabled for the organization")
+	ErrScreenshotConsentMissing    = shared.NewDomainError("screenshot:consent-missing", http.StatusForbidden, "user did not consent to screenshots")
+	ErrScreenshotNotFound          = shared.NewDomainError("screenshot:not-found", http.StatusNotFound, "screenshot not found")
+	ErrScreenshotBlurTooLow        = shared.NewDomainError("screenshot:blur-too-low", http.StatusBadRequest, "screenshot is less blurred than the policy requires")
+	ErrScreenshotOutsideActivity   = shared.NewDomainError("screenshot:outside-activity", http.StatusBadRequest, "screenshot was not taken during the activity")
+	ErrScreenshotImageNotSupported = shared.NewDomainError("screenshot:image-not-supported", http.StatusUnsupportedMediaType, "screenshot must be a png, jpeg or webp image")
+	ErrScreenshotConsentNotFound   = shared.NewDomainError("screenshot:consent-not-found", http.StatusNotFound, "consent to screenshots not found")
+)
+
+// ScreenshotPolicy controls whether and how often desktop clients take screenshots of the running activity,
+// how blurred the screenshots are at least and how long they are kept
+type ScreenshotPolicy struct {
+	OrganizationID  uuid.UUID
+	Enabled         bool
+	IntervalMinutes int
+	MinBlur         string
+	RetentionDays   int
+}
+
+// ScreenshotConsent is the consent of a user to screenshots while tracking, without consent no screenshots are taken
+type ScreenshotConsent struct {
+	OrganizationID uuid.UUID
+	Username       string
+	ConsentedAt    time.Time
+}
+
+// ActivityScreenshot is a screenshot taken by the desktop client while the activity was running
+type ActivityScreenshot struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	ActivityID     uuid.UUID
+	Username       string
+	Blur           string
+	ContentType    string
+	StorageKey     string
+	Size           int64
+	TakenAt        time.Time
+	CreatedAt      time.Time
+}
+
+type ScreenshotRepository interface {
+	FindScreenshotPolicy(ctx context.Context, organizationID uuid.UUID) (*ScreenshotPolicy, error)
+	UpdateScreenshotPolicy(ctx context.Context, policy *ScreenshotPolicy) error
+	FindScreenshotConsent(ctx context.Context, organizationID uuid.UUID, username string) (*ScreenshotConsent, error)
+	InsertScreenshotConsent(ctx context.Context, consent *ScreenshotConsent) error
+	DeleteScreenshotConsent(ctx context.Context, organizationID uuid.UUID, username string) error
+	FindScreenshotsByActivityID(ctx context.Context, organizationID, activityID uuid.UUID) ([]*ActivityScreenshot, error)
+	FindScreenshotByID(ctx context.Context, organizationID, screenshotID uuid.UUID) (*ActivityScreenshot, error)
+	FindExpiredScreenshots(ctx context.Context, now time.Time) ([]*ActivityScreenshot, error)
+	InsertScreenshot(ctx context.Context, screenshot *ActivityScreenshot) error
+	DeleteScreenshot(ctx context.Context, organizationID, screenshotID uuid.UUID) error
+}
+
+// NewDefaultScreenshotPolicy is the policy of organizations without a policy, no screenshots are taken
+func NewDefaultScreenshotPolicy(organizationID uuid.UUID) *ScreenshotPolicy {
+	return &ScreenshotPolicy{
+		OrganizationID:  organizationID,
+		Enabled:         false,
+		IntervalMinutes: 10,
+		MinBlur:         ScreenshotBlurHigh,
+		RetentionDays:   30,
+	}
+}
+
+// IsValidScreenshotBlur checks if the blur is a known blur level
+func IsValidScreenshotBlur(blur string) bool {
+	return slices.Contains(screenshotBlurs, blur)
+}
+
+// Allows checks whether a screenshot with the blur meets the minimum blur of the policy
+func (p *ScreenshotPolicy) Allows(blur string) bool {
+	return slices.Index(screenshotBlurs, blur) >= slices.Index(screenshotBlurs, p.MinBlur)
+}
+
+// IsExpired checks whether the screenshot is older than the retention of the policy
+func (p *ScreenshotPolicy) IsExpired(screenshot *ActivityScreenshot, now time.Time) bool {
+	return screenshot.CreatedAt.AddDate(0, 0, p.RetentionDays).Before(now)
+}
+
+// WasTakenDuring checks whether the screenshot was taken while the activity was running,
+// tolerating a skewed clock of the client
+func (s *ActivityScreenshot) WasTakenDuring(activity *Activity) bool {
+	return !s.TakenAt.Before(activity.Start.Add(-screenshotClockSkew)) && !s.TakenAt.After(activity.End.Add(screenshotClockSkew))
+}
+
+func screenshotStorageKeyOf(screenshot *ActivityScreenshot) string {
+	return fmt.Sprintf("screenshots/%s/%s/%s", screenshot.OrganizationID, screenshot.ActivityID, screenshot.ID)
+}
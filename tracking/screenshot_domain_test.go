@@ -0,0 +1,41 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestScreenshotPolicyAllows(t *testing.T) {
+	is := is.New(t)
+
+	policy := &ScreenshotPolicy{MinBlur: ScreenshotBlurLow}
+
+	is.True(!policy.Allows(ScreenshotBlurNone))
+	is.True(policy.Allows(ScreenshotBlurLow))
+	is.True(policy.Allows(ScreenshotBlurHigh))
+	is.True(!policy.Allows("sharp"))
+}
+
+func TestScreenshotPolicyIsExpired(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	policy := &ScreenshotPolicy{RetentionDays: 30}
+
+	is.True(policy.IsExpired(&ActivityScreenshot{CreatedAt: now.AddDate(0, 0, -31)}, now))
+	is.True(!policy.IsExpired(&ActivityScreenshot{CreatedAt: now.AddDate(0, 0, -29)}, now))
+}
+
+func TestScreenshotWasTakenDuring(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	activity := &Activity{Start: start, End: start.Add(2 * time.Hour)}
+
+	is.True((&ActivityScreenshot{TakenAt: start.Add(time.Hour)}).WasTakenDuring(activity))
+	is.True((&ActivityScreenshot{TakenAt: start.Add(-time.Minute)}).WasTakenDuring(activity))
+	is.True(!(&ActivityScreenshot{TakenAt: start.Add(-time.Hour)}).WasTakenDuring(activity))
+	is.True(!(&ActivityScreenshot{TakenAt: start.Add(3 * time.Hour)}).WasTakenDuring(activity))
+}
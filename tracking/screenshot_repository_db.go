@@ -0,0 +1,277 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbScreenshotRepository is a SQL database repository for screenshot policies, consents and screenshots
+type DbScreenshotRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ ScreenshotRepository = (*DbScreenshotRepository)(nil)
+
+// NewDbScreenshotRepository creates a new SQL database repository for screenshots
+func NewDbScreenshotRepository(connPool *pgxpool.Pool) *DbScreenshotRepository {
+	return &DbScreenshotRepository{
+		connPool: connPool,
+	}
+}
+
+// FindScreenshotPolicy reads the screenshot policy of the organization, organizations without a policy get the default policy
+func (r *DbScreenshotRepository) FindScreenshotPolicy(ctx context.Context, organizationID uuid.UUID) (*ScreenshotPolicy, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT enabled, interval_minutes, min_blur, retention_days
+		 FROM screenshot_policies
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+
+	policy := &ScreenshotPolicy{OrganizationID: organizationID}
+	err := row.Scan(&policy.Enabled, &policy.IntervalMinutes, &policy.MinBlur, &policy.RetentionDays)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewDefaultScreenshotPolicy(organizationID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// UpdateScreenshotPolicy sets the screenshot policy of the organization
+func (r *DbScreenshotRepository) UpdateScreenshotPolicy(ctx context.Context, policy *ScreenshotPolicy) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO screenshot_policies
+		   (org_id, enabled, interval_minutes, min_blur, retention_days)
+		 VALUES
+		   ($1, $2, $3, $4, $5)
+		 ON CONFLICT (org_id) DO UPDATE
+		 SET enabled = EXCLUDED.enabled, interval_minutes = EXCLUDED.interval_minutes,
+		     min_blur = EXCLUDED.min_blur, retention_days = EXCLUDED.retention_days`,
+		policy.OrganizationID,
+		policy.Enabled,
+		policy.IntervalMinutes,
+		policy.MinBlur,
+		policy.RetentionDays,
+	)
+	return err
+}
+
+func (r *DbScreenshotRepository) FindScreenshotConsent(ctx context.Context, organizationID uuid.UUID, username string) (*ScreenshotConsent, error) {
+	row, err := shared.SelectOne[screenshotConsentRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[screenshotConsentRow]()+`
+		 FROM screenshot_consents
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScreenshotConsentNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toScreenshotConsent(), nil
+}
+
+// InsertScreenshotConsent records the consent of the user, consenting again keeps the first consent
+func (r *DbScreenshotRepository) InsertScreenshotConsent(ctx context.Context, consent *ScreenshotConsent) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO screenshot_consents
+		   (org_id, username, consented_at)
+		 VALUES
+		   ($1, $2, $3)
+		 ON CONFLICT (org_id, username) DO NOTHING`,
+		consent.OrganizationID,
+		consent.Username,
+		consent.ConsentedAt,
+	)
+	return err
+}
+
+func (r *DbScreenshotRepository) DeleteScreenshotConsent(ctx context.Context, organizationID uuid.UUID, username string) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM screenshot_consents
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrScreenshotConsentNotFound
+	}
+	return nil
+}
+
+func (r *DbScreenshotRepository) FindScreenshotsByActivityID(ctx context.Context, organizationID, activityID uuid.UUID) ([]*ActivityScreenshot, error) {
+	rows, err := shared.SelectAll[activityScreenshotRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[activityScreenshotRow]()+`
+		 FROM activity_screenshots
+		 WHERE org_id = $1 AND activity_id = $2
+		 ORDER BY taken_at ASC`,
+		organizationID, activityID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	screenshots := make([]*ActivityScreenshot, len(rows))
+	for i, row := range rows {
+		screenshots[i] = row.toActivityScreenshot()
+	}
+	return screenshots, nil
+}
+
+func (r *DbScreenshotRepository) FindScreenshotByID(ctx context.Context, organizationID, screenshotID uuid.UUID) (*ActivityScreenshot, error) {
+	row, err := shared.SelectOne[activityScreenshotRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[activityScreenshotRow]()+`
+		 FROM activity_screenshots
+		 WHERE screenshot_id = $1 AND org_id = $2`,
+		screenshotID, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScreenshotNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toActivityScreenshot(), nil
+}
+
+// FindExpiredScreenshots reads the screenshots of all organizations older than the retention of their organization,
+// organizations without a policy keep screenshots as long as the default policy
+func (r *DbScreenshotRepository) FindExpiredScreenshots(ctx context.Context, now time.Time) ([]*ActivityScreenshot, error) {
+	rows, err := shared.SelectAll[activityScreenshotRow](
+		ctx,
+		r.connPool,
+		`SELECT s.screenshot_id, s.org_id, s.activity_id, s.username, s.blur, s.content_type,
+		        s.storage_key, s.size, s.taken_at, s.created_at
+		 FROM activity_screenshots s
+		 LEFT JOIN screenshot_policies p ON p.org_id = s.org_id
+		 WHERE s.created_at < $1 - make_interval(days => COALESCE(p.retention_days, $2))
+		 ORDER BY s.created_at ASC`,
+		now, NewDefaultScreenshotPolicy(uuid.Nil).RetentionDays,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	screenshots := make([]*ActivityScreenshot, len(rows))
+	for i, row := range rows {
+		screenshots[i] = row.toActivityScreenshot()
+	}
+	return screenshots, nil
+}
+
+func (r *DbScreenshotRepository) InsertScreenshot(ctx context.Context, screenshot *ActivityScreenshot) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO activity_screenshots
+		   (screenshot_id, org_id, activity_id, username, blur, content_type, storage_key, size, taken_at, created_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		screenshot.ID,
+		screenshot.OrganizationID,
+		screenshot.ActivityID,
+		screenshot.Username,
+		screenshot.Blur,
+		screenshot.ContentType,
+		screenshot.StorageKey,
+		screenshot.Size,
+		screenshot.TakenAt,
+		screenshot.CreatedAt,
+	)
+	return err
+}
+
+func (r *DbScreenshotRepository) DeleteScreenshot(ctx context.Context, organizationID, screenshotID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM activity_screenshots
+		 WHERE screenshot_id = $1 AND org_id = $2`,
+		screenshotID, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrScreenshotNotFound
+	}
+	return nil
+}
+
+type screenshotConsentRow struct {
+	OrganizationID uuid.UUID `db:"org_id"`
+	Username       string    `db:"username"`
+	ConsentedAt    time.Time `db:"consented_at"`
+}
+
+func (r *screenshotConsentRow) toScreenshotConsent() *ScreenshotConsent {
+	return &ScreenshotConsent{
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		ConsentedAt:    r.ConsentedAt,
+	}
+}
+
+type activityScreenshotRow struct {
+	ID             uuid.UUID `db:"screenshot_id"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	ActivityID     uuid.UUID `db:"activity_id"`
+	Username       string    `db:"username"`
+	Blur           string    `db:"blur"`
+	ContentType    string    `db:"content_type"`
+	StorageKey     string    `db:"storage_key"`
+	Size           int64     `db:"size"`
+	TakenAt        time.Time `db:"taken_at"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (r *activityScreenshotRow) toActivityScreenshot() *ActivityScreenshot {
+	return &ActivityScreenshot{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		ActivityID:     r.ActivityID,
+		Username:       r.Username,
+		Blur:           r.Blur,
+		ContentType:    r.ContentType,
+		StorageKey:     r.StorageKey,
+		Size:           r.Size,
+		TakenAt:        r.TakenAt,
+		CreatedAt:      r.CreatedAt,
+	}
+}
@@ -0,0 +1,148 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestScreenshotRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	screenshotRepository := NewDbScreenshotRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("FindDefaultScreenshotPolicy", func(t *testing.T) {
+		policy, err := screenshotRepository.FindScreenshotPolicy(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(!policy.Enabled)
+	})
+
+	t.Run("UpdateScreenshotPolicy", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return screenshotRepository.UpdateScreenshotPolicy(ctx, &ScreenshotPolicy{
+					OrganizationID:  shared.OrganizationIDSample,
+					Enabled:         true,
+					IntervalMinutes: 5,
+					MinBlur:         ScreenshotBlurLow,
+					RetentionDays:   7,
+				})
+			},
+		)
+		is.NoErr(err)
+
+		policy, err := screenshotRepository.FindScreenshotPolicy(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(policy.Enabled)
+		is.Equal(policy.IntervalMinutes, 5)
+		is.Equal(policy.MinBlur, ScreenshotBlurLow)
+		is.Equal(policy.RetentionDays, 7)
+	})
+
+	t.Run("InsertAndDeleteScreenshotConsent", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return screenshotRepository.InsertScreenshotConsent(ctx, &ScreenshotConsent{
+					OrganizationID: shared.OrganizationIDSample,
+					Username:       "user1",
+					ConsentedAt:    time.Now(),
+				})
+			},
+		)
+		is.NoErr(err)
+
+		consent, err := screenshotRepository.FindScreenshotConsent(context.Background(), shared.OrganizationIDSample, "user1")
+		is.NoErr(err)
+		is.Equal(consent.Username, "user1")
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return screenshotRepository.DeleteScreenshotConsent(ctx, shared.OrganizationIDSample, "user1")
+			},
+		)
+		is.NoErr(err)
+
+		_, err = screenshotRepository.FindScreenshotConsent(context.Background(), shared.OrganizationIDSample, "user1")
+		is.Equal(err, ErrScreenshotConsentNotFound)
+	})
+
+	t.Run("InsertAndExpireScreenshots", func(t *testing.T) {
+		activityID := uuid.New()
+		recent := &ActivityScreenshot{
+			ID:             uuid.New(),
+			OrganizationID: shared.OrganizationIDSample,
+			ActivityID:     activityID,
+			Username:       "user1",
+			Blur:           ScreenshotBlurHigh,
+			ContentType:    "image/png",
+			StorageKey:     "screenshots/recent",
+			Size:           3,
+			TakenAt:        time.Now(),
+			CreatedAt:      time.Now(),
+		}
+		expired := *recent
+		expired.ID = uuid.New()
+		expired.StorageKey = "screenshots/expired"
+		expired.TakenAt = time.Now().AddDate(0, 0, -8)
+		expired.CreatedAt = time.Now().AddDate(0, 0, -8)
+
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return screenshotRepository.InsertScreenshot(ctx, recent)
+			},
+			func(ctx context.Context) error {
+				return screenshotRepository.InsertScreenshot(ctx, &expired)
+			},
+		)
+		is.NoErr(err)
+
+		screenshots, err := screenshotRepository.FindScreenshotsByActivityID(context.Background(), shared.OrganizationIDSample, activityID)
+		is.NoErr(err)
+		is.Equal(len(screenshots), 2)
+		is.Equal(screenshots[0].ID, expired.ID)
+
+		expiredScreenshots, err := screenshotRepository.FindExpiredScreenshots(context.Background(), time.Now())
+		is.NoErr(err)
+		is.Equal(len(expiredScreenshots), 1)
+		is.Equal(expiredScreenshots[0].ID, expired.ID)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return screenshotRepository.DeleteScreenshot(ctx, shared.OrganizationIDSample, expired.ID)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = screenshotRepository.FindScreenshotByID(context.Background(), shared.OrganizationIDSample, expired.ID)
+		is.Equal(err, ErrScreenshotNotFound)
+	})
+}
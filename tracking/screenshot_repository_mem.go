@@ -0,0 +1,156 @@
+package tracking
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemScreenshotRepository struct {
+	mu          sync.Mutex
+	policies    map[uuid.UUID]*ScreenshotPolicy
+	consents    []*ScreenshotConsent
+	screenshots []*ActivityScreenshot
+}
+
+var _ ScreenshotRepository = (*InMemScreenshotRepository)(nil)
+
+func NewInMemScreenshotRepository() *InMemScreenshotRepository {
+	return &InMemScreenshotRepository{
+		policies: make(map[uuid.UUID]*ScreenshotPolicy),
+	}
+}
+
+func (r *InMemScreenshotRepository) FindScreenshotPolicy(ctx context.Context, organizationID uuid.UUID) (*ScreenshotPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	policy, ok := r.policies[organizationID]
+	if !ok {
+		return NewDefaultScreenshotPolicy(organizationID), nil
+	}
+	found := *policy
+	return &found, nil
+}
+
+func (r *InMemScreenshotRepository) UpdateScreenshotPolicy(ctx context.Context, policy *ScreenshotPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := *policy
+	r.policies[policy.OrganizationID] = &updated
+	return nil
+}
+
+func (r *InMemScreenshotRepository) FindScreenshotConsent(ctx context.Context, organizationID uuid.UUID, username string) (*ScreenshotConsent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, consent := range r.consents {
+		if consent.OrganizationID == organizationID && consent.Username == username {
+			found := *consent
+			return &found, nil
+		}
+	}
+	return nil, ErrScreenshotConsentNotFound
+}
+
+func (r *InMemScreenshotRepository) InsertScreenshotConsent(ctx context.Context, consent *ScreenshotConsent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.consents {
+		if c.OrganizationID == consent.OrganizationID && c.Username == consent.Username {
+			return nil
+		}
+	}
+	inserted := *consent
+	r.consents = append(r.consents, &inserted)
+	return nil
+}
+
+func (r *InMemScreenshotRepository) DeleteScreenshotConsent(ctx context.Context, organizationID uuid.UUID, username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, consent := range r.consents {
+		if consent.OrganizationID == organizationID && consent.Username == username {
+			r.consents = append(r.consents[:i], r.consents[i+1:]...)
+			return nil
+		}
+	}
+	return ErrScreenshotConsentNotFound
+}
+
+func (r *InMemScreenshotRepository) FindScreenshotsByActivityID(ctx context.Context, organizationID, activityID uuid.UUID) ([]*ActivityScreenshot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var screenshots []*ActivityScreenshot
+	for _, screenshot := range r.screenshots {
+		if screenshot.OrganizationID == organizationID && screenshot.ActivityID == activityID {
+			found := *screenshot
+			screenshots = append(screenshots, &found)
+		}
+	}
+	sort.Slice(screenshots, func(i, j int) bool {
+		return screenshots[i].TakenAt.Before(screenshots[j].TakenAt)
+	})
+	return screenshots, nil
+}
+
+func (r *InMemScreenshotRepository) FindScreenshotByID(ctx context.Context, organizationID, screenshotID uuid.UUID) (*ActivityScreenshot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, screenshot := range r.screenshots {
+		if screenshot.OrganizationID == organizationID && screenshot.ID == screenshotID {
+			found := *screenshot
+			return &found, nil
+		}
+	}
+	return nil, ErrScreenshotNotFound
+}
+
+func (r *InMemScreenshotRepository) FindExpiredScreenshots(ctx context.Context, now time.Time) ([]*ActivityScreenshot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var screenshots []*ActivityScreenshot
+	for _, screenshot := range r.screenshots {
+		policy, ok := r.policies[screenshot.OrganizationID]
+		if !ok {
+			policy = NewDefaultScreenshotPolicy(screenshot.OrganizationID)
+		}
+		if policy.IsExpired(screenshot, now) {
+			found := *screenshot
+			screenshots = append(screenshots, &found)
+		}
+	}
+	return screenshots, nil
+}
+
+func (r *InMemScreenshotRepository) InsertScreenshot(ctx context.Context, screenshot *ActivityScreenshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *screenshot
+	r.screenshots = append(r.screenshots, &inserted)
+	return nil
+}
+
+func (r *InMemScreenshotRepository) DeleteScreenshot(ctx context.Context, organizationID, screenshotID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, screenshot := range r.screenshots {
+		if screenshot.OrganizationID == organizationID && screenshot.ID == screenshotID {
+			r.screenshots = append(r.screenshots[:i], r.screenshots[i+1:]...)
+			return nil
+		}
+	}
+	return ErrScreenshotNotFound
+}
@@ -0,0 +1,343 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type screenshotPolicyModel struct {
+	Enabled         bool       `json:"enabled"`
+	IntervalMinutes int        `json:"intervalMinutes"`
+	MinBlur         string     `json:"minBlur"`
+	RetentionDays   int        `json:"retentionDays"`
+	Links           *hal.Links `json:"_links,omitempty"`
+}
+
+type screenshotConsentModel struct {
+	ConsentedAt string     `json:"consentedAt"`
+	Links       *hal.Links `json:"_links"`
+}
+
+type activityScreenshotModel struct {
+	ID          string     `json:"id"`
+	ActivityID  string     `json:"activityId"`
+	Username    string     `json:"username"`
+	Blur        string     `json:"blur"`
+	ContentType string     `json:"contentType"`
+	Size        int64      `json:"size"`
+	TakenAt     string     `json:"takenAt"`
+	Links       *hal.Links `json:"_links"`
+}
+
+type activityScreenshotsModel struct {
+	Embedded struct {
+		ScreenshotModels []*activityScreenshotModel `json:"screenshots"`
+	} `json:"_embedded"`
+	Links *hal.Links `json:"_links"`
+}
+
+type ScreenshotRestHandlers struct {
+	config            *shared.Config
+	screenshotService *ScreenshotService
+}
+
+func NewScreenshotRestHandlers(config *shared.Config, screenshotService *ScreenshotService) *ScreenshotRestHandlers {
+	return &ScreenshotRestHandlers{
+		config:            config,
+		screenshotService: screenshotService,
+	}
+}
+
+func (a *ScreenshotRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/screenshot-policy", a.HandleGetScreenshotPolicy())
+	r.Put("/screenshot-policy", a.HandleUpdateScreenshotPolicy())
+	r.Get("/screenshot-consent", a.HandleGetScreenshotConsent())
+	r.Put("/screenshot-consent", a.HandleConsentToScreenshots())
+	r.Delete("/screenshot-consent", a.HandleRevokeScreenshotConsent())
+	r.Get("/activities/{activity-id}/screenshots", a.HandleGetScreenshots())
+	r.Post("/activities/{activity-id}/screenshots", a.HandleUploadScreenshot())
+	r.Get("/activities/{activity-id}/screenshots/{screenshot-id}", a.HandleGetScreenshotImage())
+	r.Delete("/activities/{activity-id}/screenshots/{screenshot-id}", a.HandleDeleteScreenshot())
+}
+
+func (a *ScreenshotRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetScreenshotPolicy reads the screenshot policy of the organization, so desktop clients know whether,
+// how often and how blurred to take screenshots
+func (a *ScreenshotRestHandlers) HandleGetScreenshotPolicy() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	screenshotService := a.screenshotService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		policy, err := screenshotService.ReadScreenshotPolicy(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToScreenshotPolicyModel(policy))
+	}
+}
+
+// HandleUpdateScreenshotPolicy sets the screenshot policy of the organization
+func (a *ScreenshotRestHandlers) HandleUpdateScreenshotPolicy() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	screenshotService := a.screenshotService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var policyModel screenshotPolicyModel
+		err := json.NewDecoder(r.Body).Decode(&policyModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "screenshot policy not valid", err)
+			return
+		}
+
+		if !IsValidScreenshotBlur(policyModel.MinBlur) {
+			shared.RenderValidationProblemJSON(w, "screenshot policy not valid", shared.NewInvalidParam("minBlur", "oneof", "minimum blur must be none, low or high"))
+			return
+		}
+		if policyModel.IntervalMinutes < 1 {
+			shared.RenderValidationProblemJSON(w, "screenshot policy not valid", shared.NewInvalidParam("intervalMinutes", "min", "interval must be at least 1 minute"))
+			return
+		}
+		if policyModel.RetentionDays < 1 {
+			shared.RenderValidationProblemJSON(w, "screenshot policy not valid", shared.NewInvalidParam("retentionDays", "min", "retention must be at least 1 day"))
+			return
+		}
+
+		policy, err := screenshotService.UpdateScreenshotPolicy(r.Context(), principal, &ScreenshotPolicy{
+			Enabled:         policyModel.Enabled,
+			IntervalMinutes: policyModel.IntervalMinutes,
+			MinBlur:         policyModel.MinBlur,
+			RetentionDays:   policyModel.RetentionDays,
+		})
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToScreenshotPolicyModel(policy))
+	}
+}
+
+// HandleGetScreenshotConsent reads the consent of the user to screenshots
+func (a *ScreenshotRestHandlers) HandleGetScreenshotConsent() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	screenshotService := a.screenshotService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		consent, err := screenshotService.ReadScreenshotConsent(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToScreenshotConsentModel(consent))
+	}
+}
+
+// HandleConsentToScreenshots records the consent of the user to screenshots while tracking
+func (a *ScreenshotRestHandlers) HandleConsentToScreenshots() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	screenshotService := a.screenshotService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		consent, err := screenshotService.ConsentToScreenshots(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToScreenshotConsentModel(consent))
+	}
+}
+
+// HandleRevokeScreenshotConsent revokes the consent of the user, so the desktop client stops taking screenshots
+func (a *ScreenshotRestHandlers) HandleRevokeScreenshotConsent() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	screenshotService := a.screenshotService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		err := screenshotService.RevokeScreenshotConsent(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleGetScreenshots reads the screenshots of an activity
+func (a *ScreenshotRestHandlers) HandleGetScreenshots() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	screenshotService := a.screenshotService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, err := uuid.Parse(chi.URLParam(r, "activity-id"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		screenshots, err := screenshotService.ReadScreenshots(r.Context(), principal, activityID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		screenshotModels := make([]*activityScreenshotModel, len(screenshots))
+		for i, screenshot := range screenshots {
+			screenshotModels[i] = mapToActivityScreenshotModel(screenshot)
+		}
+
+		screenshotsModel := &activityScreenshotsModel{
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		}
+		screenshotsModel.Embedded.ScreenshotModels = screenshotModels
+
+		shared.RenderJSON(w, screenshotsModel)
+	}
+}
+
+// HandleUploadScreenshot stores the screenshot image posted as body, the query parameters takenAt and blur
+// tell when and how blurred the desktop client took the screenshot
+func (a *ScreenshotRestHandlers) HandleUploadScreenshot() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	screenshotService := a.screenshotService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		activityID, err := uuid.Parse(chi.URLParam(r, "activity-id"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		takenAt, err := time.Parse(time.RFC3339, r.URL.Query().Get("takenAt"))
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "screenshot not valid", shared.NewInvalidParam("takenAt", "datetime", "taken at must be a RFC 3339 date time"))
+			return
+		}
+
+		blur := r.URL.Query().Get("blur")
+		if !IsValidScreenshotBlur(blur) {
+			shared.RenderValidationProblemJSON(w, "screenshot not valid", shared.NewInvalidParam("blur", "oneof", "blur must be none, low or high"))
+			return
+		}
+
+		image := http.MaxBytesReader(w, r.Body, maxScreenshotSize)
+		screenshot, err := screenshotService.UploadScreenshot(r.Context(), principal, activityID, takenAt, blur, image, r.Header.Get("Content-Type"))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		shared.RenderJSON(w, mapToActivityScreenshotModel(screenshot))
+	}
+}
+
+// HandleGetScreenshotImage streams the image of a screenshot
+func (a *ScreenshotRestHandlers) HandleGetScreenshotImage() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	screenshotService := a.screenshotService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		screenshotID, err := uuid.Parse(chi.URLParam(r, "screenshot-id"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		screenshot, content, err := screenshotService.OpenScreenshot(r.Context(), principal, screenshotID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+		defer content.Close()
+
+		w.Header().Set("Content-Type", screenshot.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(screenshot.Size, 10))
+		w.Header().Set("Cache-Control", "private, no-store")
+		_, _ = io.Copy(w, content)
+	}
+}
+
+// HandleDeleteScreenshot deletes a screenshot
+func (a *ScreenshotRestHandlers) HandleDeleteScreenshot() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	screenshotService := a.screenshotService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		screenshotID, err := uuid.Parse(chi.URLParam(r, "screenshot-id"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err = screenshotService.DeleteScreenshot(r.Context(), principal, screenshotID)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func mapToScreenshotPolicyModel(policy *ScreenshotPolicy) *screenshotPolicyModel {
+	return &screenshotPolicyModel{
+		Enabled:         policy.Enabled,
+		IntervalMinutes: policy.IntervalMinutes,
+		MinBlur:         policy.MinBlur,
+		RetentionDays:   policy.RetentionDays,
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/screenshot-policy"),
+		),
+	}
+}
+
+func mapToScreenshotConsentModel(consent *ScreenshotConsent) *screenshotConsentModel {
+	return &screenshotConsentModel{
+		ConsentedAt: consent.ConsentedAt.Format(time.RFC3339),
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/screenshot-consent"),
+		),
+	}
+}
+
+func mapToActivityScreenshotModel(screenshot *ActivityScreenshot) *activityScreenshotModel {
+	return &activityScreenshotModel{
+		ID:          screenshot.ID.String(),
+		ActivityID:  screenshot.ActivityID.String(),
+		Username:    screenshot.Username,
+		Blur:        screenshot.Blur,
+		ContentType: screenshot.ContentType,
+		Size:        screenshot.Size,
+		TakenAt:     screenshot.TakenAt.Format(time.RFC3339),
+		Links: hal.NewLinks(
+			hal.NewSelfLink(fmt.Sprintf("/api/activities/%s/screenshots/%s", screenshot.ActivityID, screenshot.ID)),
+		),
+	}
+}
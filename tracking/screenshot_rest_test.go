@@ -0,0 +1,125 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleScreenshots(t *testing.T) {
+	is := is.New(t)
+
+	s, _ := newScreenshotServiceForTest()
+	a := NewScreenshotRestHandlers(&shared.Config{}, s)
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+				OrganizationID: shared.OrganizationIDSample,
+				Username:       "user1",
+			})))
+		})
+	})
+	a.RegisterProtected(router)
+
+	t.Run("ConsentToScreenshots", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/screenshot-consent", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	})
+
+	t.Run("UploadScreenshot", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/activities/"+activityIDScreenshotSample.String()+"/screenshots?blur=high&takenAt="+time.Now().UTC().Format(time.RFC3339), strings.NewReader("png"))
+		r.Header.Set("Content-Type", "image/png")
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusCreated)
+	})
+
+	t.Run("UploadScreenshotInvalidBlur", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/activities/"+activityIDScreenshotSample.String()+"/screenshots?blur=sharp&takenAt="+time.Now().UTC().Format(time.RFC3339), strings.NewReader("png"))
+		r.Header.Set("Content-Type", "image/png")
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+	})
+
+	var screenshotsModel activityScreenshotsModel
+	t.Run("GetScreenshots", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/activities/"+activityIDScreenshotSample.String()+"/screenshots", nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+		err := json.NewDecoder(httpRec.Body).Decode(&screenshotsModel)
+		is.NoErr(err)
+		is.Equal(len(screenshotsModel.Embedded.ScreenshotModels), 1)
+		is.Equal(screenshotsModel.Embedded.ScreenshotModels[0].Blur, ScreenshotBlurHigh)
+	})
+
+	t.Run("GetScreenshotImage", func(t *testing.T) {
+		httpRec := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", strings.TrimPrefix(screenshotsModel.Embedded.ScreenshotModels[0].Links.HrefOf("self"), "/api"), nil)
+
+		router.ServeHTTP(httpRec, r)
+		is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+		is.Equal(httpRec.Header().Get("Content-Type"), "image/png")
+		is.Equal(httpRec.Body.String(), "png")
+	})
+}
+
+func TestHandleUpdateScreenshotPolicy(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	s, _ := newScreenshotServiceForTest()
+	a := NewScreenshotRestHandlers(&shared.Config{}, s)
+
+	r, _ := http.NewRequest("PUT", "/api/screenshot-policy", strings.NewReader(`{"enabled": true, "intervalMinutes": 5, "minBlur": "high", "retentionDays": 14}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleUpdateScreenshotPolicy()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	var policyModel screenshotPolicyModel
+	err := json.NewDecoder(httpRec.Body).Decode(&policyModel)
+	is.NoErr(err)
+	is.True(policyModel.Enabled)
+	is.Equal(policyModel.IntervalMinutes, 5)
+	is.Equal(policyModel.MinBlur, ScreenshotBlurHigh)
+	is.Equal(policyModel.RetentionDays, 14)
+}
+
+func TestHandleUpdateScreenshotPolicyNotValid(t *testing.T) {
+	is := is.New(t)
+	httpRec := httptest.NewRecorder()
+
+	s, _ := newScreenshotServiceForTest()
+	a := NewScreenshotRestHandlers(&shared.Config{}, s)
+
+	r, _ := http.NewRequest("PUT", "/api/screenshot-policy", strings.NewReader(`{"enabled": true, "intervalMinutes": 5, "minBlur": "sharp", "retentionDays": 14}`))
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Roles:          []string{"ROLE_ADMIN"},
+	}))
+
+	a.HandleUpdateScreenshotPolicy()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
@@ -0,0 +1,313 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ScreenshotService keeps the screenshots the desktop client takes of running activities as proof of work,
+// screenshots are only taken if the organization enabled them and the user consented
+type ScreenshotService struct {
+	repositoryTxer       shared.RepositoryTxer
+	storage              shared.Storage
+	scanService          *shared.ScanService
+	auditService         *shared.AuditService
+	screenshotRepository ScreenshotRepository
+	activityRepository   ActivityRepository
+}
+
+// NewScreenshotService creates a new service for screenshots of activities
+func NewScreenshotService(
+	repositoryTxer shared.RepositoryTxer,
+	jobService *shared.JobService,
+	storage shared.Storage,
+	scanService *shared.ScanService,
+	auditService *shared.AuditService,
+	screenshotRepository ScreenshotRepository,
+	activityRepository ActivityRepository,
+) *ScreenshotService {
+	s := &ScreenshotService{
+		repositoryTxer:       repositoryTxer,
+		storage:              storage,
+		scanService:          scanService,
+		auditService:         auditService,
+		screenshotRepository: screenshotRepository,
+		activityRepository:   activityRepository,
+	}
+
+	jobService.RegisterHandler(screenshotCleanupJobType, s.handleScreenshotCleanupJob)
+	jobService.Schedule(screenshotCleanupJobType, time.Hour)
+
+	return s
+}
+
+// ReadScreenshotPolicy reads the screenshot policy of the principal's organization, so clients know whether to take screenshots
+func (s *ScreenshotService) ReadScreenshotPolicy(ctx context.Context, principal *shared.Principal) (*ScreenshotPolicy, error) {
+	return s.screenshotRepository.FindScreenshotPolicy(ctx, principal.OrganizationID)
+}
+
+// UpdateScreenshotPolicy sets the screenshot policy of the principal's organization
+func (s *ScreenshotService) UpdateScreenshotPolicy(ctx context.Context, principal *shared.Principal, policy *ScreenshotPolicy) (*ScreenshotPolicy, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	policy.OrganizationID = principal.OrganizationID
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.screenshotRepository.UpdateScreenshotPolicy(ctx, policy)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// ReadScreenshotConsent reads the consent of the principal to screenshots
+func (s *ScreenshotService) ReadScreenshotConsent(ctx context.Context, principal *shared.Principal) (*ScreenshotConsent, error) {
+	return s.screenshotRepository.FindScreenshotConsent(ctx, principal.OrganizationID, principal.Username)
+}
+
+// ConsentToScreenshots records the consent of the principal to screenshots while tracking,
+// consent is only given by the user and never on behalf of the user
+func (s *ScreenshotService) ConsentToScreenshots(ctx context.Context, principal *shared.Principal) (*ScreenshotConsent, error) {
+	if principal.IsImpersonated() {
+		return nil, shared.ErrForbidden
+	}
+
+	consent := &ScreenshotConsent{
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		ConsentedAt:    time.Now(),
+	}
+	err := s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.screenshotRepository.InsertScreenshotConsent(ctx, consent)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.screenshotRepository.FindScreenshotConsent(ctx, principal.OrganizationID, principal.Username)
+}
+
+// RevokeScreenshotConsent revokes the consent of the principal, so no more screenshots are taken,
+// screenshots already taken are kept until the retention of the organization expires
+func (s *ScreenshotService) RevokeScreenshotConsent(ctx context.Context, principal *shared.Principal) error {
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.screenshotRepository.DeleteScreenshotConsent(ctx, principal.OrganizationID, principal.Username)
+		},
+	)
+}
+
+// UploadScreenshot scans and stores a screenshot of an activity of the principal, the screenshot must meet the
+// policy of the organization, be taken with consent and while the activity was running
+func (s *ScreenshotService) UploadScreenshot(ctx context.Context, principal *shared.Principal, activityID uuid.UUID, takenAt time.Time, blur string, image io.Reader, contentType string) (*ActivityScreenshot, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !slices.Contains(screenshotContentTypes, mediaType) {
+		return nil, ErrScreenshotImageNotSupported
+	}
+
+	policy, err := s.screenshotRepository.FindScreenshotPolicy(ctx, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Enabled {
+		return nil, ErrScreenshotsDisabled
+	}
+	if !policy.Allows(blur) {
+		return nil, ErrScreenshotBlurTooLow
+	}
+
+	_, err = s.screenshotRepository.FindScreenshotConsent(ctx, principal.OrganizationID, principal.Username)
+	if errors.Is(err, ErrScreenshotConsentNotFound) {
+		return nil, ErrScreenshotConsentMissing
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := s.activityRepository.FindActivityByID(ctx, activityID, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if activity.Username != principal.Username {
+		return nil, ErrActivityNotFound
+	}
+
+	screenshot := &ActivityScreenshot{
+		ID:             uuid.New(),
+		OrganizationID: principal.OrganizationID,
+		ActivityID:     activity.ID,
+		Username:       principal.Username,
+		Blur:           blur,
+		ContentType:    mediaType,
+		TakenAt:        takenAt,
+		CreatedAt:      time.Now(),
+	}
+	if !screenshot.WasTakenDuring(activity) {
+		return nil, ErrScreenshotOutsideActivity
+	}
+
+	content, err := shared.BufferUpload(image)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	err = s.scanService.ScanUpload(ctx, &shared.Upload{
+		OrganizationID: principal.OrganizationID,
+		Username:       principal.Username,
+		FileName:       "screenshot",
+		Content:        content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := content.Stat()
+	if err != nil {
+		return nil, err
+	}
+	screenshot.Size = info.Size()
+	screenshot.StorageKey = screenshotStorageKeyOf(screenshot)
+
+	err = s.storage.Put(ctx, screenshot.StorageKey, content, screenshot.Size, screenshot.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.screenshotRepository.InsertScreenshot(ctx, screenshot)
+		},
+	)
+	if err != nil {
+		if deleteErr := s.storage.Delete(ctx, screenshot.StorageKey); deleteErr != nil {
+			log.Printf("could not delete screenshot %v: %v", screenshot.StorageKey, deleteErr)
+		}
+		return nil, err
+	}
+
+	return screenshot, nil
+}
+
+// ReadScreenshots reads the screenshots of an activity, which only the user of the activity and admins may see
+func (s *ScreenshotService) ReadScreenshots(ctx context.Context, principal *shared.Principal, activityID uuid.UUID) ([]*ActivityScreenshot, error) {
+	activity, err := s.activityRepository.FindActivityByID(ctx, activityID, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if activity.Username != principal.Username && !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrActivityNotFound
+	}
+
+	return s.screenshotRepository.FindScreenshotsByActivityID(ctx, principal.OrganizationID, activityID)
+}
+
+// OpenScreenshot opens the image of a screenshot for the user of the screenshot or an admin,
+// admins viewing the screenshots of other users are recorded in the audit log
+func (s *ScreenshotService) OpenScreenshot(ctx context.Context, principal *shared.Principal, screenshotID uuid.UUID) (*ActivityScreenshot, io.ReadCloser, error) {
+	screenshot, err := s.readScreenshot(ctx, principal, screenshotID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if screenshot.Username != principal.Username {
+		err = s.auditService.RecordAuditEntry(ctx, &shared.AuditEntry{
+			OrganizationID: principal.OrganizationID,
+			Username:       principal.Username,
+			ImpersonatedBy: principal.ImpersonatedBy,
+			Action:         auditActionScreenshotViewed,
+			Method:         http.MethodGet,
+			Path:           fmt.Sprintf("/api/activities/%s/screenshots/%s", screenshot.ActivityID, screenshot.ID),
+			Status:         http.StatusOK,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	content, err := s.storage.Get(ctx, screenshot.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return screenshot, content, nil
+}
+
+// DeleteScreenshot deletes a screenshot of the user of the screenshot or by an admin
+func (s *ScreenshotService) DeleteScreenshot(ctx context.Context, principal *shared.Principal, screenshotID uuid.UUID) error {
+	screenshot, err := s.readScreenshot(ctx, principal, screenshotID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.screenshotRepository.DeleteScreenshot(ctx, principal.OrganizationID, screenshot.ID)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Delete(ctx, screenshot.StorageKey)
+	if errors.Is(err, shared.ErrStorageObjectNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *ScreenshotService) readScreenshot(ctx context.Context, principal *shared.Principal, screenshotID uuid.UUID) (*ActivityScreenshot, error) {
+	screenshot, err := s.screenshotRepository.FindScreenshotByID(ctx, principal.OrganizationID, screenshotID)
+	if err != nil {
+		return nil, err
+	}
+	if screenshot.Username != principal.Username && !principal.HasRole("ROLE_ADMIN") {
+		return nil, ErrScreenshotNotFound
+	}
+	return screenshot, nil
+}
+
+func (s *ScreenshotService) handleScreenshotCleanupJob(ctx context.Context, job *shared.Job) error {
+	screenshots, err := s.screenshotRepository.FindExpiredScreenshots(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, screenshot := range screenshots {
+		err = s.storage.Delete(ctx, screenshot.StorageKey)
+		if err != nil && !errors.Is(err, shared.ErrStorageObjectNotFound) {
+			return err
+		}
+
+		err = s.repositoryTxer.InTx(
+			shared.WithOrganizationID(ctx, screenshot.OrganizationID),
+			func(ctx context.Context) error {
+				return s.screenshotRepository.DeleteScreenshot(ctx, screenshot.OrganizationID, screenshot.ID)
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
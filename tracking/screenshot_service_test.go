@@ -0,0 +1,215 @@
+package tracking
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/paged"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+var activityIDScreenshotSample = uuid.MustParse("00000000-0000-0000-2222-000000000001")
+
+func newScreenshotServiceForTest() (*ScreenshotService, *shared.AuditService) {
+	repositoryTxer := shared.NewInMemRepositoryTxer()
+	storage := shared.NewInMemStorage()
+	auditService := shared.NewAuditService(repositoryTxer, shared.NewInMemAuditRepository())
+
+	activityRepository := NewInMemActivityRepository()
+	activity, _ := activityRepository.FindActivityByID(context.Background(), activityIDScreenshotSample, shared.OrganizationIDSample)
+	activity.Start = time.Now().Add(-time.Hour)
+	activity.End = time.Now().Add(time.Hour)
+
+	screenshotRepository := NewInMemScreenshotRepository()
+	_ = screenshotRepository.UpdateScreenshotPolicy(context.Background(), &ScreenshotPolicy{
+		OrganizationID:  shared.OrganizationIDSample,
+		Enabled:         true,
+		IntervalMinutes: 10,
+		MinBlur:         ScreenshotBlurLow,
+		RetentionDays:   30,
+	})
+
+	mailResource := shared.NewInMemMailResource()
+	return NewScreenshotService(
+		repositoryTxer,
+		shared.NewJobService(repositoryTxer, shared.NewInMemJobRepository()),
+		storage,
+		shared.NewScanService(&shared.Config{}, repositoryTxer, shared.NewInMemOutbox(mailResource), storage, shared.NewInMemScanner()),
+		auditService,
+		screenshotRepository,
+		activityRepository,
+	), auditService
+}
+
+func TestUploadScreenshot(t *testing.T) {
+	is := is.New(t)
+
+	s, auditService := newScreenshotServiceForTest()
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	other := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user2"}
+
+	t.Run("ConsentMissing", func(t *testing.T) {
+		_, err := s.UploadScreenshot(context.Background(), user, activityIDScreenshotSample, time.Now(), ScreenshotBlurHigh, strings.NewReader("png"), "image/png")
+		is.Equal(err, ErrScreenshotConsentMissing)
+	})
+
+	_, err := s.ConsentToScreenshots(context.Background(), user)
+	is.NoErr(err)
+
+	t.Run("BlurTooLow", func(t *testing.T) {
+		_, err := s.UploadScreenshot(context.Background(), user, activityIDScreenshotSample, time.Now(), ScreenshotBlurNone, strings.NewReader("png"), "image/png")
+		is.Equal(err, ErrScreenshotBlurTooLow)
+	})
+
+	t.Run("OutsideActivity", func(t *testing.T) {
+		_, err := s.UploadScreenshot(context.Background(), user, activityIDScreenshotSample, time.Now().Add(-24*time.Hour), ScreenshotBlurHigh, strings.NewReader("png"), "image/png")
+		is.Equal(err, ErrScreenshotOutsideActivity)
+	})
+
+	t.Run("ActivityOfOtherUser", func(t *testing.T) {
+		_, err := s.ConsentToScreenshots(context.Background(), other)
+		is.NoErr(err)
+
+		_, err = s.UploadScreenshot(context.Background(), other, activityIDScreenshotSample, time.Now(), ScreenshotBlurHigh, strings.NewReader("png"), "image/png")
+		is.Equal(err, ErrActivityNotFound)
+	})
+
+	t.Run("Infected", func(t *testing.T) {
+		_, err := s.UploadScreenshot(context.Background(), user, activityIDScreenshotSample, time.Now(), ScreenshotBlurHigh, strings.NewReader(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`), "image/png")
+		is.True(errors.Is(err, shared.ErrUploadInfected))
+	})
+
+	screenshot, err := s.UploadScreenshot(context.Background(), user, activityIDScreenshotSample, time.Now(), ScreenshotBlurHigh, strings.NewReader("png"), "image/png")
+	is.NoErr(err)
+	is.Equal(screenshot.Size, int64(3))
+
+	t.Run("ReadScreenshots", func(t *testing.T) {
+		screenshots, err := s.ReadScreenshots(context.Background(), user, activityIDScreenshotSample)
+		is.NoErr(err)
+		is.Equal(len(screenshots), 1)
+
+		_, err = s.ReadScreenshots(context.Background(), other, activityIDScreenshotSample)
+		is.Equal(err, ErrActivityNotFound)
+	})
+
+	t.Run("OpenScreenshotAsOwner", func(t *testing.T) {
+		_, content, err := s.OpenScreenshot(context.Background(), user, screenshot.ID)
+		is.NoErr(err)
+		defer content.Close()
+
+		image, err := io.ReadAll(content)
+		is.NoErr(err)
+		is.Equal(string(image), "png")
+
+		auditEntries, err := auditService.ReadAuditEntries(context.Background(), admin, &paged.PageParams{Page: 0, Size: 10})
+		is.NoErr(err)
+		is.Equal(len(auditEntries.AuditEntries), 0)
+	})
+
+	t.Run("OpenScreenshotAsOtherUser", func(t *testing.T) {
+		_, _, err := s.OpenScreenshot(context.Background(), other, screenshot.ID)
+		is.Equal(err, ErrScreenshotNotFound)
+	})
+
+	t.Run("OpenScreenshotAsAdmin", func(t *testing.T) {
+		_, content, err := s.OpenScreenshot(context.Background(), admin, screenshot.ID)
+		is.NoErr(err)
+		content.Close()
+
+		auditEntries, err := auditService.ReadAuditEntries(context.Background(), admin, &paged.PageParams{Page: 0, Size: 10})
+		is.NoErr(err)
+		is.Equal(len(auditEntries.AuditEntries), 1)
+		is.Equal(auditEntries.AuditEntries[0].Action, auditActionScreenshotViewed)
+		is.Equal(auditEntries.AuditEntries[0].Username, "admin")
+	})
+
+	t.Run("DeleteScreenshot", func(t *testing.T) {
+		err := s.DeleteScreenshot(context.Background(), user, screenshot.ID)
+		is.NoErr(err)
+
+		_, err = s.storage.Get(context.Background(), screenshot.StorageKey)
+		is.Equal(err, shared.ErrStorageObjectNotFound)
+	})
+}
+
+func TestUploadScreenshotDisabled(t *testing.T) {
+	is := is.New(t)
+
+	s, _ := newScreenshotServiceForTest()
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_ADMIN"}}
+
+	_, err := s.UpdateScreenshotPolicy(context.Background(), admin, NewDefaultScreenshotPolicy(shared.OrganizationIDSample))
+	is.NoErr(err)
+
+	_, err = s.UploadScreenshot(context.Background(), admin, activityIDScreenshotSample, time.Now(), ScreenshotBlurHigh, strings.NewReader("png"), "image/png")
+	is.Equal(err, ErrScreenshotsDisabled)
+}
+
+func TestScreenshotConsent(t *testing.T) {
+	is := is.New(t)
+
+	s, _ := newScreenshotServiceForTest()
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+
+	t.Run("NotOnBehalfOfUser", func(t *testing.T) {
+		_, err := s.ConsentToScreenshots(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", ImpersonatedBy: "ops@baralga.com"})
+		is.Equal(err, shared.ErrForbidden)
+	})
+
+	_, err := s.ConsentToScreenshots(context.Background(), user)
+	is.NoErr(err)
+
+	consent, err := s.ReadScreenshotConsent(context.Background(), user)
+	is.NoErr(err)
+	is.Equal(consent.Username, "user1")
+
+	err = s.RevokeScreenshotConsent(context.Background(), user)
+	is.NoErr(err)
+
+	_, err = s.ReadScreenshotConsent(context.Background(), user)
+	is.Equal(err, ErrScreenshotConsentNotFound)
+}
+
+func TestUpdateScreenshotPolicyForbidden(t *testing.T) {
+	is := is.New(t)
+
+	s, _ := newScreenshotServiceForTest()
+
+	_, err := s.UpdateScreenshotPolicy(context.Background(), &shared.Principal{OrganizationID: shared.OrganizationIDSample}, NewDefaultScreenshotPolicy(shared.OrganizationIDSample))
+	is.Equal(err, shared.ErrForbidden)
+}
+
+func TestScreenshotCleanupJob(t *testing.T) {
+	is := is.New(t)
+
+	s, _ := newScreenshotServiceForTest()
+
+	expired := &ActivityScreenshot{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		ActivityID:     activityIDScreenshotSample,
+		Username:       "user1",
+		CreatedAt:      time.Now().AddDate(0, 0, -31),
+	}
+	expired.StorageKey = screenshotStorageKeyOf(expired)
+	err := s.storage.Put(context.Background(), expired.StorageKey, strings.NewReader("png"), 3, "image/png")
+	is.NoErr(err)
+	err = s.screenshotRepository.InsertScreenshot(context.Background(), expired)
+	is.NoErr(err)
+
+	err = s.handleScreenshotCleanupJob(context.Background(), nil)
+	is.NoErr(err)
+
+	_, err = s.screenshotRepository.FindScreenshotByID(context.Background(), shared.OrganizationIDSample, expired.ID)
+	is.Equal(err, ErrScreenshotNotFound)
+
+	_, err = s.storage.Get(context.Background(), expired.StorageKey)
+	is.Equal(err, shared.ErrStorageObjectNotFound)
+}
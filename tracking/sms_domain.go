@@ -0,0 +1,76 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	// smsWhatsAppPrefix is the prefix of phone numbers of WhatsApp messages
+	smsWhatsAppPrefix = "whatsapp:"
+
+	smsHelpText                = "Text a time entry like: 3h Project X - workshop prep"
+	smsPhoneNumberNotFoundText = "Your phone number is not set up for time tracking, please ask your admin."
+)
+
+var (
+	ErrSMSDisabled            = shared.NewDomainError("sms:disabled", http.StatusNotFound, "sms is not enabled")
+	ErrSMSPhoneNumberNotFound = shared.NewDomainError("sms:phone-number-not-found", http.StatusNotFound, "phone number not found")
+	ErrSMSPhoneNumberTaken    = shared.NewDomainError("sms:phone-number-taken", http.StatusConflict, "phone number is set for another user")
+	ErrSMSSignatureNotValid   = shared.NewDomainError("sms:signature-not-valid", http.StatusForbidden, "signature of the message not valid")
+)
+
+var (
+	smsPhoneNumberPattern    = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+	smsPhoneNumberSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", "/", "")
+)
+
+// SMSPhoneNumber maps the phone number of a user to the user for logging time by SMS or WhatsApp
+type SMSPhoneNumber struct {
+	OrganizationID uuid.UUID
+	Username       string
+	PhoneNumber    string
+}
+
+// InboundMessage is a SMS or WhatsApp message received by the webhook
+type InboundMessage struct {
+	From string
+	Body string
+}
+
+type SMSRepository interface {
+	FindSMSPhoneNumbers(ctx context.Context, organizationID uuid.UUID) ([]*SMSPhoneNumber, error)
+	FindSMSPhoneNumber(ctx context.Context, phoneNumber string) (*SMSPhoneNumber, error)
+	UpsertSMSPhoneNumber(ctx context.Context, phoneNumber *SMSPhoneNumber) error
+	DeleteSMSPhoneNumber(ctx context.Context, organizationID uuid.UUID, username string) error
+}
+
+// NormalizePhoneNumber is the phone number in E.164 format like +4917612345678 without separators
+// or the prefix of WhatsApp
+func NormalizePhoneNumber(phoneNumber string) string {
+	phoneNumber = strings.TrimPrefix(strings.TrimSpace(phoneNumber), smsWhatsAppPrefix)
+	phoneNumber = smsPhoneNumberSeparators.Replace(phoneNumber)
+	if strings.HasPrefix(phoneNumber, "00") {
+		phoneNumber = "+" + phoneNumber[2:]
+	}
+	return phoneNumber
+}
+
+// Validate checks that the phone number is in E.164 format
+func (p *SMSPhoneNumber) Validate() error {
+	if !smsPhoneNumberPattern.MatchString(p.PhoneNumber) {
+		return shared.NewInvalidParam("phoneNumber", "e164", "phone number must be in international format like +4917612345678")
+	}
+	return nil
+}
+
+// IsHelp checks whether the message asks for help
+func (m *InboundMessage) IsHelp() bool {
+	body := strings.ToLower(strings.TrimSpace(m.Body))
+	return body == "" || body == "help" || body == "?"
+}
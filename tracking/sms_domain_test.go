@@ -0,0 +1,32 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(NormalizePhoneNumber("whatsapp:+4917612345678"), "+4917612345678")
+	is.Equal(NormalizePhoneNumber(" +49 (176) 123-456/78 "), "+4917612345678")
+	is.Equal(NormalizePhoneNumber("004917612345678"), "+4917612345678")
+}
+
+func TestValidateSMSPhoneNumber(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr((&SMSPhoneNumber{PhoneNumber: "+4917612345678"}).Validate())
+	is.True((&SMSPhoneNumber{PhoneNumber: "017612345678"}).Validate() != nil)
+	is.True((&SMSPhoneNumber{PhoneNumber: "+49"}).Validate() != nil)
+}
+
+func TestInboundMessageIsHelp(t *testing.T) {
+	is := is.New(t)
+
+	is.True((&InboundMessage{Body: " Help "}).IsHelp())
+	is.True((&InboundMessage{Body: "?"}).IsHelp())
+	is.True((&InboundMessage{}).IsHelp())
+	is.True(!(&InboundMessage{Body: "2h review"}).IsHelp())
+}
@@ -0,0 +1,129 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbSMSRepository is a SQL database repository for the phone numbers of users logging time by SMS
+type DbSMSRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ SMSRepository = (*DbSMSRepository)(nil)
+
+// NewDbSMSRepository creates a new SQL database repository for phone numbers
+func NewDbSMSRepository(connPool *pgxpool.Pool) *DbSMSRepository {
+	return &DbSMSRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbSMSRepository) FindSMSPhoneNumbers(ctx context.Context, organizationID uuid.UUID) ([]*SMSPhoneNumber, error) {
+	rows, err := shared.SelectAll[smsPhoneNumberRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[smsPhoneNumberRow]()+`
+		 FROM sms_phone_numbers
+		 WHERE org_id = $1
+		 ORDER BY username ASC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	phoneNumbers := make([]*SMSPhoneNumber, len(rows))
+	for i, row := range rows {
+		phoneNumbers[i] = row.toSMSPhoneNumber()
+	}
+	return phoneNumbers, nil
+}
+
+// FindSMSPhoneNumber reads the user of the phone number across all organizations,
+// phone numbers of disabled users are not found
+func (r *DbSMSRepository) FindSMSPhoneNumber(ctx context.Context, phoneNumber string) (*SMSPhoneNumber, error) {
+	row, err := shared.SelectOne[smsPhoneNumberRow](
+		ctx,
+		r.connPool,
+		`SELECT p.org_id, p.username, p.phone_number
+		 FROM sms_phone_numbers p
+		 JOIN users u ON u.org_id = p.org_id AND u.username = p.username
+		 WHERE p.phone_number = $1 AND u.enabled = 1`,
+		phoneNumber,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSMSPhoneNumberNotFound
+		}
+
+		return nil, err
+	}
+
+	return row.toSMSPhoneNumber(), nil
+}
+
+func (r *DbSMSRepository) UpsertSMSPhoneNumber(ctx context.Context, phoneNumber *SMSPhoneNumber) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO sms_phone_numbers
+		   (org_id, username, phone_number, created_at)
+		 VALUES
+		   ($1, $2, $3, now())
+		 ON CONFLICT (org_id, username) DO UPDATE
+		 SET phone_number = $3, created_at = now()`,
+		phoneNumber.OrganizationID,
+		phoneNumber.Username,
+		phoneNumber.PhoneNumber,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrSMSPhoneNumberTaken
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *DbSMSRepository) DeleteSMSPhoneNumber(ctx context.Context, organizationID uuid.UUID, username string) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM sms_phone_numbers
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID,
+		username,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrSMSPhoneNumberNotFound
+	}
+	return nil
+}
+
+type smsPhoneNumberRow struct {
+	OrganizationID uuid.UUID `db:"org_id"`
+	Username       string    `db:"username"`
+	PhoneNumber    string    `db:"phone_number"`
+}
+
+func (r *smsPhoneNumberRow) toSMSPhoneNumber() *SMSPhoneNumber {
+	return &SMSPhoneNumber{
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		PhoneNumber:    r.PhoneNumber,
+	}
+}
@@ -0,0 +1,81 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestSMSRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	smsRepository := NewDbSMSRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("UpsertSMSPhoneNumber", func(t *testing.T) {
+		_, err := smsRepository.FindSMSPhoneNumber(context.Background(), "+4917612345678")
+		is.True(errors.Is(err, ErrSMSPhoneNumberNotFound))
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return smsRepository.UpsertSMSPhoneNumber(ctx, &SMSPhoneNumber{
+					OrganizationID: shared.OrganizationIDSample,
+					Username:       "user1",
+					PhoneNumber:    "+4917612345678",
+				})
+			},
+		)
+		is.NoErr(err)
+
+		phoneNumber, err := smsRepository.FindSMSPhoneNumber(context.Background(), "+4917612345678")
+		is.NoErr(err)
+		is.Equal(phoneNumber.Username, "user1")
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return smsRepository.UpsertSMSPhoneNumber(ctx, &SMSPhoneNumber{
+					OrganizationID: shared.OrganizationIDSample,
+					Username:       "admin",
+					PhoneNumber:    "+4917612345678",
+				})
+			},
+		)
+		is.True(errors.Is(err, ErrSMSPhoneNumberTaken))
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return smsRepository.DeleteSMSPhoneNumber(ctx, shared.OrganizationIDSample, "user1")
+			},
+		)
+		is.NoErr(err)
+
+		phoneNumbers, err := smsRepository.FindSMSPhoneNumbers(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(len(phoneNumbers), 0)
+	})
+}
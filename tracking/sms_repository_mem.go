@@ -0,0 +1,80 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemSMSRepository struct {
+	mu           sync.Mutex
+	phoneNumbers []*SMSPhoneNumber
+}
+
+var _ SMSRepository = (*InMemSMSRepository)(nil)
+
+func NewInMemSMSRepository() *InMemSMSRepository {
+	return &InMemSMSRepository{}
+}
+
+func (r *InMemSMSRepository) FindSMSPhoneNumbers(ctx context.Context, organizationID uuid.UUID) ([]*SMSPhoneNumber, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var phoneNumbers []*SMSPhoneNumber
+	for _, p := range r.phoneNumbers {
+		if p.OrganizationID == organizationID {
+			found := *p
+			phoneNumbers = append(phoneNumbers, &found)
+		}
+	}
+	return phoneNumbers, nil
+}
+
+func (r *InMemSMSRepository) FindSMSPhoneNumber(ctx context.Context, phoneNumber string) (*SMSPhoneNumber, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.phoneNumbers {
+		if p.PhoneNumber == phoneNumber {
+			found := *p
+			return &found, nil
+		}
+	}
+	return nil, ErrSMSPhoneNumberNotFound
+}
+
+func (r *InMemSMSRepository) UpsertSMSPhoneNumber(ctx context.Context, phoneNumber *SMSPhoneNumber) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.phoneNumbers {
+		if p.PhoneNumber == phoneNumber.PhoneNumber && (p.OrganizationID != phoneNumber.OrganizationID || p.Username != phoneNumber.Username) {
+			return ErrSMSPhoneNumberTaken
+		}
+	}
+
+	upserted := *phoneNumber
+	for i, p := range r.phoneNumbers {
+		if p.OrganizationID == phoneNumber.OrganizationID && p.Username == phoneNumber.Username {
+			r.phoneNumbers[i] = &upserted
+			return nil
+		}
+	}
+	r.phoneNumbers = append(r.phoneNumbers, &upserted)
+	return nil
+}
+
+func (r *InMemSMSRepository) DeleteSMSPhoneNumber(ctx context.Context, organizationID uuid.UUID, username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, p := range r.phoneNumbers {
+		if p.OrganizationID == organizationID && p.Username == username {
+			r.phoneNumbers = append(r.phoneNumbers[:i], r.phoneNumbers[i+1:]...)
+			return nil
+		}
+	}
+	return ErrSMSPhoneNumberNotFound
+}
@@ -0,0 +1,182 @@
+package tracking
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type smsPhoneNumberModel struct {
+	Username    string     `json:"username"`
+	PhoneNumber string     `json:"phoneNumber"`
+	Links       *hal.Links `json:"_links"`
+}
+
+type smsPhoneNumbersModel struct {
+	Embedded *embeddedSMSPhoneNumbers `json:"_embedded"`
+	Links    *hal.Links               `json:"_links"`
+}
+
+type embeddedSMSPhoneNumbers struct {
+	SMSPhoneNumberModels []*smsPhoneNumberModel `json:"phoneNumbers"`
+}
+
+// twimlResponse is the response of the webhook in the markup of Twilio, the message is sent back to the user
+type twimlResponse struct {
+	XMLName xml.Name `xml:"Response"`
+	Message string   `xml:"Message,omitempty"`
+}
+
+type SMSRestHandlers struct {
+	config     *shared.Config
+	smsService *SMSService
+}
+
+func NewSMSRestHandlers(config *shared.Config, smsService *SMSService) *SMSRestHandlers {
+	return &SMSRestHandlers{
+		config:     config,
+		smsService: smsService,
+	}
+}
+
+func (a *SMSRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/sms/phone-numbers", a.HandleGetSMSPhoneNumbers())
+	r.Put("/sms/phone-numbers/{username}", a.HandleUpdateSMSPhoneNumber())
+	r.Delete("/sms/phone-numbers/{username}", a.HandleDeleteSMSPhoneNumber())
+}
+
+func (a *SMSRestHandlers) RegisterOpen(r chi.Router) {
+	r.Post("/sms/webhook", a.HandleSMSWebhook())
+}
+
+// HandleGetSMSPhoneNumbers reads the phone numbers of the users of the organization
+func (a *SMSRestHandlers) HandleGetSMSPhoneNumbers() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	smsService := a.smsService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		phoneNumbers, err := smsService.ReadSMSPhoneNumbers(r.Context(), principal)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		phoneNumberModels := make([]*smsPhoneNumberModel, len(phoneNumbers))
+		for i, phoneNumber := range phoneNumbers {
+			phoneNumberModels[i] = mapToSMSPhoneNumberModel(phoneNumber)
+		}
+
+		shared.RenderJSON(w, &smsPhoneNumbersModel{
+			Embedded: &embeddedSMSPhoneNumbers{
+				SMSPhoneNumberModels: phoneNumberModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+// HandleUpdateSMSPhoneNumber sets the phone number of a user
+func (a *SMSRestHandlers) HandleUpdateSMSPhoneNumber() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	smsService := a.smsService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var phoneNumberModel smsPhoneNumberModel
+		err := json.NewDecoder(r.Body).Decode(&phoneNumberModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "phone number not valid", err)
+			return
+		}
+
+		phoneNumber := &SMSPhoneNumber{
+			Username:    chi.URLParam(r, "username"),
+			PhoneNumber: NormalizePhoneNumber(phoneNumberModel.PhoneNumber),
+		}
+		err = phoneNumber.Validate()
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "phone number not valid", err)
+			return
+		}
+
+		phoneNumber, err = smsService.UpdateSMSPhoneNumber(r.Context(), principal, phoneNumber)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToSMSPhoneNumberModel(phoneNumber))
+	}
+}
+
+// HandleDeleteSMSPhoneNumber removes the phone number of a user
+func (a *SMSRestHandlers) HandleDeleteSMSPhoneNumber() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	smsService := a.smsService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		err := smsService.DeleteSMSPhoneNumber(r.Context(), principal, chi.URLParam(r, "username"))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+	}
+}
+
+// HandleSMSWebhook receives the SMS and WhatsApp messages posted by Twilio and responds with the message
+// sent back to the user. The signature of Twilio is over the public url of the webhook.
+func (a *SMSRestHandlers) HandleSMSWebhook() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	smsService := a.smsService
+	webroot := strings.TrimSuffix(a.config.Webroot, "/")
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "message not valid", err)
+			return
+		}
+
+		err = smsService.VerifyTwilioSignature(webroot+r.RequestURI, r.PostForm, r.Header.Get("X-Twilio-Signature"))
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		reply, err := smsService.ReceiveMessage(r.Context(), &InboundMessage{
+			From: r.PostForm.Get("From"),
+			Body: r.PostForm.Get("Body"),
+		}, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write([]byte(xml.Header))
+		_ = xml.NewEncoder(w).Encode(&twimlResponse{Message: reply})
+	}
+}
+
+func mapToSMSPhoneNumberModel(phoneNumber *SMSPhoneNumber) *smsPhoneNumberModel {
+	selfLink := hal.NewSelfLink(fmt.Sprintf("/api/sms/phone-numbers/%s", url.PathEscape(phoneNumber.Username)))
+	return &smsPhoneNumberModel{
+		Username:    phoneNumber.Username,
+		PhoneNumber: phoneNumber.PhoneNumber,
+		Links: hal.NewLinks(
+			selfLink,
+			hal.NewLink("delete", selfLink.Href()),
+		),
+	}
+}
@@ -0,0 +1,69 @@
+package tracking
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleSMS(t *testing.T) {
+	is := is.New(t)
+
+	config := &shared.Config{Webroot: "https://baralga.com", TwilioAuthToken: "token"}
+	a := NewSMSRestHandlers(config, newSMSServiceForTest(config))
+
+	request := func(method, url, body string, roles ...string) *http.Request {
+		r, _ := http.NewRequest(method, url, strings.NewReader(body))
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "admin",
+			Roles:          roles,
+		}))
+	}
+
+	router := chi.NewRouter()
+	a.RegisterProtected(router)
+
+	httpRec := httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("PUT", "/sms/phone-numbers/user1", `{"phoneNumber": "0176"}`, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("PUT", "/sms/phone-numbers/user1", `{"phoneNumber": "+4917612345678"}`, "ROLE_USER"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("PUT", "/sms/phone-numbers/user1", `{"phoneNumber": "+4917612345678"}`, "ROLE_ADMIN"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	webhook := func(authToken string) *http.Request {
+		form := url.Values{"From": {"+4917612345678"}, "Body": {"2h My Project - review"}}
+		mac := hmac.New(sha1.New, []byte(authToken))
+		mac.Write([]byte("https://baralga.com/api/sms/webhookBody2h My Project - reviewFrom+4917612345678"))
+
+		r := httptest.NewRequest("POST", "/api/sms/webhook", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("X-Twilio-Signature", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+		return r
+	}
+
+	httpRec = httptest.NewRecorder()
+	a.HandleSMSWebhook()(httpRec, webhook("other"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusForbidden)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleSMSWebhook()(httpRec, webhook("token"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+	is.Equal(httpRec.Header().Get("Content-Type"), "text/xml; charset=utf-8")
+	is.True(strings.Contains(httpRec.Body.String(), "<Response><Message>Tracked 2:00 h on "))
+}
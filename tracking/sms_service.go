@@ -0,0 +1,162 @@
+package tracking
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/pkg/errors"
+)
+
+// SMSService tracks the time entries users text from their phone by SMS or WhatsApp
+type SMSService struct {
+	config          *shared.Config
+	repositoryTxer  shared.RepositoryTxer
+	smsRepository   SMSRepository
+	quickAddService *QuickAddService
+}
+
+// NewSMSService creates a new service for time entries by SMS or WhatsApp
+func NewSMSService(config *shared.Config, repositoryTxer shared.RepositoryTxer, smsRepository SMSRepository, quickAddService *QuickAddService) *SMSService {
+	return &SMSService{
+		config:          config,
+		repositoryTxer:  repositoryTxer,
+		smsRepository:   smsRepository,
+		quickAddService: quickAddService,
+	}
+}
+
+// ReadSMSPhoneNumbers reads the phone numbers of the users of the organization, only admins manage phone numbers
+func (s *SMSService) ReadSMSPhoneNumbers(ctx context.Context, principal *shared.Principal) ([]*SMSPhoneNumber, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	return s.smsRepository.FindSMSPhoneNumbers(ctx, principal.OrganizationID)
+}
+
+// UpdateSMSPhoneNumber sets the phone number of the user, the user logs time by texting from the phone number
+func (s *SMSService) UpdateSMSPhoneNumber(ctx context.Context, principal *shared.Principal, phoneNumber *SMSPhoneNumber) (*SMSPhoneNumber, error) {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return nil, shared.ErrForbidden
+	}
+
+	phoneNumber.OrganizationID = principal.OrganizationID
+	phoneNumber.PhoneNumber = NormalizePhoneNumber(phoneNumber.PhoneNumber)
+	err := phoneNumber.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.smsRepository.UpsertSMSPhoneNumber(ctx, phoneNumber)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return phoneNumber, nil
+}
+
+// DeleteSMSPhoneNumber removes the phone number of the user
+func (s *SMSService) DeleteSMSPhoneNumber(ctx context.Context, principal *shared.Principal, username string) error {
+	if !principal.HasRole("ROLE_ADMIN") {
+		return shared.ErrForbidden
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.smsRepository.DeleteSMSPhoneNumber(ctx, principal.OrganizationID, username)
+		},
+	)
+}
+
+// VerifyTwilioSignature verifies the signature of Twilio over the url and the posted params of the webhook
+func (s *SMSService) VerifyTwilioSignature(webhookURL string, params url.Values, signature string) error {
+	if s.config.TwilioAuthToken == "" {
+		return ErrSMSDisabled
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	signed := &strings.Builder{}
+	signed.WriteString(webhookURL)
+	for _, key := range keys {
+		for _, value := range params[key] {
+			signed.WriteString(key)
+			signed.WriteString(value)
+		}
+	}
+
+	expectedSignature, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrSMSSignatureNotValid
+	}
+
+	mac := hmac.New(sha1.New, []byte(s.config.TwilioAuthToken))
+	mac.Write([]byte(signed.String()))
+	if !hmac.Equal(mac.Sum(nil), expectedSignature) {
+		return ErrSMSSignatureNotValid
+	}
+	return nil
+}
+
+// ReceiveMessage tracks the time entry of the message for the user of the phone number,
+// the response message confirms the tracked activity or tells what went wrong
+func (s *SMSService) ReceiveMessage(ctx context.Context, message *InboundMessage, now time.Time) (string, error) {
+	if s.config.TwilioAuthToken == "" {
+		return "", ErrSMSDisabled
+	}
+
+	phoneNumber, err := s.smsRepository.FindSMSPhoneNumber(ctx, NormalizePhoneNumber(message.From))
+	if errors.Is(err, ErrSMSPhoneNumberNotFound) {
+		return smsPhoneNumberNotFoundText, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if message.IsHelp() {
+		return smsHelpText, nil
+	}
+
+	ctx = shared.WithOrganizationID(ctx, phoneNumber.OrganizationID)
+	principal := &shared.Principal{
+		Username:       phoneNumber.Username,
+		OrganizationID: phoneNumber.OrganizationID,
+		Roles:          []string{"ROLE_USER"},
+	}
+
+	activity, err := s.quickAddService.TrackQuickAddMessage(ctx, principal, strings.Join(strings.Fields(message.Body), " "), now)
+	if err != nil {
+		errorMessage, err := quickAddErrorMessage(err)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Not tracked: %s. %s", errorMessage, smsHelpText), nil
+	}
+
+	return fmt.Sprintf(
+		"Tracked %s on %s %s-%s: %s",
+		time_utils.FormatMinutesAsDuration(activity.End.Sub(activity.Start).Minutes()),
+		time_utils.FormatDateDE(activity.Start),
+		time_utils.FormatTime(activity.Start),
+		time_utils.FormatTime(activity.End),
+		activity.Description,
+	), nil
+}
@@ -0,0 +1,94 @@
+package tracking
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func newSMSServiceForTest(config *shared.Config) *SMSService {
+	activityService := &ActitivityService{
+		repositoryTxer:               shared.NewInMemRepositoryTxer(),
+		activityRepository:           NewInMemActivityRepository(),
+		locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+		projectAssignmentRepository:  NewInMemProjectAssignmentRepository(),
+		breakPolicyRepository:        NewInMemBreakPolicyRepository(),
+		categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+	}
+
+	return NewSMSService(
+		config,
+		shared.NewInMemRepositoryTxer(),
+		NewInMemSMSRepository(),
+		NewQuickAddService(activityService, NewInMemProjectRepository()),
+	)
+}
+
+func TestReceiveMessage(t *testing.T) {
+	is := is.New(t)
+
+	s := newSMSServiceForTest(&shared.Config{TwilioAuthToken: "token"})
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	user := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+	now := time.Date(2024, 3, 4, 17, 0, 0, 0, time.UTC)
+
+	_, err := s.UpdateSMSPhoneNumber(context.Background(), user, &SMSPhoneNumber{Username: "user1", PhoneNumber: "+4917612345678"})
+	is.True(errors.Is(err, shared.ErrForbidden))
+
+	phoneNumber, err := s.UpdateSMSPhoneNumber(context.Background(), admin, &SMSPhoneNumber{Username: "user1", PhoneNumber: "+49 176 12345678"})
+	is.NoErr(err)
+	is.Equal(phoneNumber.PhoneNumber, "+4917612345678")
+
+	_, err = s.UpdateSMSPhoneNumber(context.Background(), admin, &SMSPhoneNumber{Username: "user2", PhoneNumber: "+4917612345678"})
+	is.True(errors.Is(err, ErrSMSPhoneNumberTaken))
+
+	reply, err := s.ReceiveMessage(context.Background(), &InboundMessage{From: "whatsapp:+4917612345678", Body: "3h My Project - workshop prep"}, now)
+	is.NoErr(err)
+	is.Equal(reply, "Tracked 3:00 h on 04.03.2024 14:00-17:00: workshop prep")
+
+	reply, err = s.ReceiveMessage(context.Background(), &InboundMessage{From: "+4917612345678", Body: "workshop prep"}, now)
+	is.NoErr(err)
+	is.True(strings.HasPrefix(reply, "Not tracked: "))
+	is.True(strings.HasSuffix(reply, smsHelpText))
+
+	reply, err = s.ReceiveMessage(context.Background(), &InboundMessage{From: "+4917612345678", Body: "help"}, now)
+	is.NoErr(err)
+	is.Equal(reply, smsHelpText)
+
+	reply, err = s.ReceiveMessage(context.Background(), &InboundMessage{From: "+4915100000000", Body: "3h My Project - workshop prep"}, now)
+	is.NoErr(err)
+	is.Equal(reply, smsPhoneNumberNotFoundText)
+
+	err = s.DeleteSMSPhoneNumber(context.Background(), admin, "user1")
+	is.NoErr(err)
+
+	phoneNumbers, err := s.ReadSMSPhoneNumbers(context.Background(), admin)
+	is.NoErr(err)
+	is.Equal(len(phoneNumbers), 0)
+}
+
+func TestVerifyTwilioSignature(t *testing.T) {
+	is := is.New(t)
+
+	s := newSMSServiceForTest(&shared.Config{TwilioAuthToken: "token"})
+	params := url.Values{"From": {"+4917612345678"}, "Body": {"2h review"}}
+
+	mac := hmac.New(sha1.New, []byte("token"))
+	mac.Write([]byte("https://baralga.com/api/sms/webhookBody2h reviewFrom+4917612345678"))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	is.NoErr(s.VerifyTwilioSignature("https://baralga.com/api/sms/webhook", params, signature))
+	is.True(errors.Is(s.VerifyTwilioSignature("https://other.com/api/sms/webhook", params, signature), ErrSMSSignatureNotValid))
+
+	disabled := newSMSServiceForTest(&shared.Config{})
+	is.True(errors.Is(disabled.VerifyTwilioSignature("https://baralga.com/api/sms/webhook", params, signature), ErrSMSDisabled))
+}
@@ -0,0 +1,138 @@
+package tracking
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxActivitySuggestions = 5
+	// activitySuggestionsLookback is how far back the activities of the user are taken into account
+	activitySuggestionsLookback = 90 * 24 * time.Hour
+	// activitySuggestionsHistorySize is the maximum number of recent activities taken into account
+	activitySuggestionsHistorySize = 500
+	// activitySuggestionHalfLife is the age after which an activity counts only half as much as one of today
+	activitySuggestionHalfLife = 14 * 24 * time.Hour
+)
+
+// ActivitySuggestion is a project and description the user is likely to track next,
+// the score is higher the more often and the more recently the user tracked it
+type ActivitySuggestion struct {
+	ProjectID     uuid.UUID
+	ProjectTitle  string
+	Description   string
+	Score         float64
+	Count         int
+	LastTrackedAt time.Time
+}
+
+// TimeOfDay is the time of day like 09:30 for which activities are suggested
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+}
+
+// ParseTimeOfDay parses the time of day like 09:30
+func ParseTimeOfDay(s string) (*TimeOfDay, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return nil, err
+	}
+	return &TimeOfDay{Hour: t.Hour(), Minute: t.Minute()}, nil
+}
+
+// minutesApart is the distance of the times of day in minutes, across midnight if closer
+func (t *TimeOfDay) minutesApart(other time.Time) float64 {
+	diff := math.Abs(float64((t.Hour*60 + t.Minute) - (other.Hour()*60 + other.Minute())))
+	return math.Min(diff, 24*60-diff)
+}
+
+// suggestActivitiesOf scores the pairs of project and description of the activities by frequency and recency,
+// activities started around the time of day count more. With a query only descriptions or projects containing
+// the query are suggested, descriptions starting with it are preferred. Activities of projects which are
+// unknown or done are left out.
+func suggestActivitiesOf(activities []*Activity, projects []*Project, query string, timeOfDay *TimeOfDay, now time.Time) []*ActivitySuggestion {
+	projectsByID := make(map[uuid.UUID]*Project)
+	for _, project := range projects {
+		projectsByID[project.ID] = project
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	var suggestions []*ActivitySuggestion
+	suggestionsByKey := make(map[string]*ActivitySuggestion)
+	for _, activity := range activities {
+		project, ok := projectsByID[activity.ProjectID]
+		if !ok || project.Status == ProjectStatusDone {
+			continue
+		}
+
+		description := strings.TrimSpace(activity.Description)
+		matchWeight := activitySuggestionMatchWeight(description, project.Title, query)
+		if matchWeight == 0 {
+			continue
+		}
+
+		age := now.Sub(activity.Start)
+		if age < 0 {
+			age = 0
+		}
+		weight := matchWeight * math.Pow(0.5, float64(age)/float64(activitySuggestionHalfLife))
+		if timeOfDay != nil {
+			weight *= 1 / (1 + timeOfDay.minutesApart(activity.Start)/60)
+		}
+
+		key := activity.ProjectID.String() + "/" + strings.ToLower(description)
+		suggestion, ok := suggestionsByKey[key]
+		if !ok {
+			suggestion = &ActivitySuggestion{
+				ProjectID:    project.ID,
+				ProjectTitle: project.Title,
+				Description:  description,
+			}
+			suggestionsByKey[key] = suggestion
+			suggestions = append(suggestions, suggestion)
+		}
+
+		suggestion.Score += weight
+		suggestion.Count++
+		if activity.Start.After(suggestion.LastTrackedAt) {
+			// the description is suggested as written most recently
+			suggestion.Description = description
+			suggestion.LastTrackedAt = activity.Start
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].LastTrackedAt.After(suggestions[j].LastTrackedAt)
+	})
+
+	if len(suggestions) > maxActivitySuggestions {
+		suggestions = suggestions[:maxActivitySuggestions]
+	}
+	return suggestions
+}
+
+// activitySuggestionMatchWeight is how well the activity matches the query, 0 if not at all
+func activitySuggestionMatchWeight(description, projectTitle, query string) float64 {
+	if query == "" {
+		return 1
+	}
+
+	description = strings.ToLower(description)
+	switch {
+	case strings.HasPrefix(description, query):
+		return 2
+	case strings.Contains(description, query), strings.Contains(strings.ToLower(projectTitle), query):
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,97 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestSuggestActivitiesOf(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	project := &Project{ID: uuid.New(), Title: "Baralga", Status: ProjectStatusActive}
+	otherProject := &Project{ID: uuid.New(), Title: "Website", Status: ProjectStatusActive}
+	doneProject := &Project{ID: uuid.New(), Title: "Legacy", Status: ProjectStatusDone}
+	projects := []*Project{project, otherProject, doneProject}
+
+	activity := func(project *Project, description string, start time.Time) *Activity {
+		return &Activity{ProjectID: project.ID, Description: description, Start: start, End: start.Add(time.Hour)}
+	}
+
+	activities := []*Activity{
+		activity(project, "Daily Standup", time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)),
+		activity(project, "daily standup", time.Date(2024, 3, 13, 9, 0, 0, 0, time.UTC)),
+		activity(project, "Daily standup", time.Date(2024, 3, 12, 9, 0, 0, 0, time.UTC)),
+		activity(otherProject, "Write blog post", time.Date(2024, 3, 14, 15, 0, 0, 0, time.UTC)),
+		activity(otherProject, "Standup notes", time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)),
+		activity(doneProject, "Daily Standup", time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)),
+	}
+
+	t.Run("most frequent and recent first", func(t *testing.T) {
+		is := is.New(t)
+
+		suggestions := suggestActivitiesOf(activities, projects, "", nil, now)
+		is.Equal(len(suggestions), 3)
+		is.Equal(suggestions[0].ProjectID, project.ID)
+		is.Equal(suggestions[0].Description, "Daily Standup")
+		is.Equal(suggestions[0].Count, 3)
+		is.Equal(suggestions[0].LastTrackedAt, time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC))
+		is.Equal(suggestions[1].Description, "Write blog post")
+		is.Equal(suggestions[2].Description, "Standup notes")
+	})
+
+	t.Run("partial description", func(t *testing.T) {
+		is := is.New(t)
+
+		suggestions := suggestActivitiesOf(activities, projects, "stand", nil, now)
+		is.Equal(len(suggestions), 2)
+		is.Equal(suggestions[0].Description, "Daily Standup")
+		is.Equal(suggestions[1].Description, "Standup notes")
+
+		suggestions = suggestActivitiesOf(activities, projects, "websi", nil, now)
+		is.Equal(len(suggestions), 2)
+		is.Equal(suggestions[0].ProjectTitle, "Website")
+
+		suggestions = suggestActivitiesOf(activities, projects, "invoice", nil, now)
+		is.Equal(len(suggestions), 0)
+	})
+
+	t.Run("time of day", func(t *testing.T) {
+		is := is.New(t)
+
+		suggestions := suggestActivitiesOf(activities, projects, "", &TimeOfDay{Hour: 15, Minute: 30}, now)
+		is.Equal(suggestions[0].Description, "Write blog post")
+
+		suggestions = suggestActivitiesOf(activities, projects, "", &TimeOfDay{Hour: 9, Minute: 0}, now)
+		is.Equal(suggestions[0].Description, "Daily Standup")
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		is := is.New(t)
+
+		var manyActivities []*Activity
+		for i := 0; i < maxActivitySuggestions+3; i++ {
+			manyActivities = append(manyActivities, activity(project, uuid.NewString(), now.Add(-time.Duration(i)*time.Hour)))
+		}
+
+		suggestions := suggestActivitiesOf(manyActivities, projects, "", nil, now)
+		is.Equal(len(suggestions), maxActivitySuggestions)
+	})
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	is := is.New(t)
+
+	timeOfDay, err := ParseTimeOfDay("09:30")
+	is.NoErr(err)
+	is.Equal(timeOfDay.Hour, 9)
+	is.Equal(timeOfDay.Minute, 30)
+	is.Equal(timeOfDay.minutesApart(time.Date(2024, 3, 15, 23, 30, 0, 0, time.UTC)), 600.0)
+	is.Equal((&TimeOfDay{Hour: 0, Minute: 15}).minutesApart(time.Date(2024, 3, 15, 23, 45, 0, 0, time.UTC)), 30.0)
+
+	_, err = ParseTimeOfDay("9.30")
+	is.True(err != nil)
+}
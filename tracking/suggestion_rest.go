@@ -0,0 +1,104 @@
+package tracking
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+)
+
+type activitySuggestionModel struct {
+	ProjectID     string     `json:"projectId"`
+	ProjectTitle  string     `json:"projectTitle"`
+	Description   string     `json:"description"`
+	Score         float64    `json:"score"`
+	Count         int        `json:"count"`
+	LastTrackedAt string     `json:"lastTrackedAt"`
+	Links         *hal.Links `json:"_links"`
+}
+
+type activitySuggestionsModel struct {
+	Embedded *embeddedActivitySuggestions `json:"_embedded"`
+	Links    *hal.Links                   `json:"_links"`
+}
+
+type embeddedActivitySuggestions struct {
+	ActivitySuggestionModels []*activitySuggestionModel `json:"suggestions"`
+}
+
+type SuggestionRestHandlers struct {
+	config          *shared.Config
+	activityService *ActitivityService
+}
+
+func NewSuggestionRestHandlers(config *shared.Config, activityService *ActitivityService) *SuggestionRestHandlers {
+	return &SuggestionRestHandlers{
+		config:          config,
+		activityService: activityService,
+	}
+}
+
+func (a *SuggestionRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/activities/suggestions", a.HandleGetActivitySuggestions())
+}
+
+func (a *SuggestionRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetActivitySuggestions suggests the projects and descriptions the user is likely to track,
+// for the partial description of the query param q and the time of day of the query param at like 09:30
+func (a *SuggestionRestHandlers) HandleGetActivitySuggestions() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	activityService := a.activityService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var timeOfDay *TimeOfDay
+		if r.URL.Query().Get("at") != "" {
+			var err error
+			timeOfDay, err = ParseTimeOfDay(r.URL.Query().Get("at"))
+			if err != nil {
+				shared.RenderValidationProblemJSON(w, "invalid query params", shared.NewInvalidParam("at", "time", "at must be a time of day like 09:30"))
+				return
+			}
+		}
+
+		suggestions, err := activityService.SuggestActivities(r.Context(), principal, r.URL.Query().Get("q"), timeOfDay, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		suggestionModels := make([]*activitySuggestionModel, len(suggestions))
+		for i, suggestion := range suggestions {
+			suggestionModels[i] = mapToActivitySuggestionModel(suggestion)
+		}
+
+		shared.RenderJSON(w, &activitySuggestionsModel{
+			Embedded: &embeddedActivitySuggestions{
+				ActivitySuggestionModels: suggestionModels,
+			},
+			Links: hal.NewLinks(
+				hal.NewSelfLink(r.RequestURI),
+			),
+		})
+	}
+}
+
+func mapToActivitySuggestionModel(suggestion *ActivitySuggestion) *activitySuggestionModel {
+	return &activitySuggestionModel{
+		ProjectID:     suggestion.ProjectID.String(),
+		ProjectTitle:  suggestion.ProjectTitle,
+		Description:   suggestion.Description,
+		Score:         math.Round(suggestion.Score*1000) / 1000,
+		Count:         suggestion.Count,
+		LastTrackedAt: suggestion.LastTrackedAt.Format(time.RFC3339),
+		Links: hal.NewLinks(
+			hal.NewLink("project", fmt.Sprintf("/api/projects/%s", suggestion.ProjectID)),
+		),
+	}
+}
@@ -0,0 +1,57 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/matryer/is"
+)
+
+func TestHandleGetActivitySuggestions(t *testing.T) {
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	activityRepository.activities = []*Activity{
+		{
+			ProjectID:      shared.ProjectIDSample,
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Description:    "Daily Standup",
+			Start:          time.Now().Add(-24 * time.Hour),
+			End:            time.Now().Add(-23 * time.Hour),
+		},
+	}
+	a := NewSuggestionRestHandlers(&shared.Config{}, &ActitivityService{activityRepository: activityRepository})
+
+	router := chi.NewRouter()
+	a.RegisterProtected(router)
+
+	request := func(url string) *http.Request {
+		r, _ := http.NewRequest("GET", url, nil)
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+		}))
+	}
+
+	httpRec := httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("/activities/suggestions?q=daily&at=09:00"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	var suggestions activitySuggestionsModel
+	err := json.NewDecoder(httpRec.Body).Decode(&suggestions)
+	is.NoErr(err)
+	is.Equal(len(suggestions.Embedded.ActivitySuggestionModels), 1)
+	is.Equal(suggestions.Embedded.ActivitySuggestionModels[0].Description, "Daily Standup")
+	is.Equal(suggestions.Embedded.ActivitySuggestionModels[0].ProjectTitle, "My Project")
+
+	httpRec = httptest.NewRecorder()
+	router.ServeHTTP(httpRec, request("/activities/suggestions?at=nine"))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
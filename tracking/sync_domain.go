@@ -0,0 +1,154 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+// Commands of desktop clients on the timer
+const (
+	SyncCommandStart = "start"
+	SyncCommandStop  = "stop"
+)
+
+// Status of offline entries after a sync
+const (
+	SyncEntryCreated   = "created"
+	SyncEntryDuplicate = "duplicate"
+	SyncEntryRejected  = "rejected"
+)
+
+const (
+	maxSyncEntries            = 100
+	maxSyncDescriptionLength  = 500
+	maxTimerStateWait         = 55 * time.Second
+	timerStateRecheckInterval = 5 * time.Second
+)
+
+var (
+	ErrTimerNotRunning    = shared.NewDomainError("sync:timer-not-running", http.StatusConflict, "timer is not running")
+	ErrTimerStateConflict = shared.NewDomainError("sync:timer-state-conflict", http.StatusConflict, "timer was changed concurrently")
+	ErrSyncEntryNotFound  = shared.NewDomainError("sync:entry-not-found", http.StatusNotFound, "sync entry not found")
+)
+
+// TimerState is the running timer of a user shared by all desktop clients of the user,
+// the version changes with every change so clients wait for the next version
+type TimerState struct {
+	OrganizationID uuid.UUID
+	Username       string
+	ProjectID      uuid.UUID
+	Description    string
+	Start          *time.Time
+	Version        int
+}
+
+// SyncCommand is a command of a desktop client on the timer, commands queued offline
+// are executed at the time they were given
+type SyncCommand struct {
+	Type        string
+	ProjectID   uuid.UUID
+	Description string
+	At          time.Time
+}
+
+// SyncEntry is an activity tracked offline by a desktop client, the id is given by the client
+// so an entry synced twice is created once
+type SyncEntry struct {
+	ID          uuid.UUID
+	ProjectID   uuid.UUID
+	Start       time.Time
+	End         time.Time
+	Description string
+}
+
+// SyncEntryResult is the outcome of syncing an entry
+type SyncEntryResult struct {
+	EntryID    uuid.UUID
+	ActivityID uuid.UUID
+	Status     string
+	Message    string
+}
+
+type SyncRepository interface {
+	FindTimerState(ctx context.Context, organizationID uuid.UUID, username string) (*TimerState, error)
+	UpdateTimerState(ctx context.Context, state *TimerState, previousVersion int) error
+	FindSyncedEntry(ctx context.Context, organizationID uuid.UUID, username string, entryID uuid.UUID) (uuid.UUID, error)
+	InsertSyncedEntry(ctx context.Context, organizationID uuid.UUID, username string, entryID, activityID uuid.UUID) error
+}
+
+// NewTimerState is the state of users who never started a timer
+func NewTimerState(organizationID uuid.UUID, username string) *TimerState {
+	return &TimerState{
+		OrganizationID: organizationID,
+		Username:       username,
+	}
+}
+
+// IsRunning checks whether the timer is started
+func (s *TimerState) IsRunning() bool {
+	return s.Start != nil
+}
+
+// ToActivity is the activity tracked by the timer until the end, timers running less than a minute track no activity
+func (s *TimerState) ToActivity(end time.Time) *Activity {
+	end = end.Truncate(time.Minute)
+	if !s.IsRunning() || !end.After(*s.Start) {
+		return nil
+	}
+
+	return &Activity{
+		Start:       *s.Start,
+		End:         end,
+		Description: s.Description,
+		ProjectID:   s.ProjectID,
+	}
+}
+
+// Validate checks that the command is known and its description not too long
+func (c *SyncCommand) Validate() error {
+	if c.Type != SyncCommandStart && c.Type != SyncCommandStop {
+		return shared.NewInvalidParam("type", "enum", "type must be start or stop")
+	}
+	if utf8.RuneCountInString(c.Description) > maxSyncDescriptionLength {
+		return shared.NewInvalidParam("description", "max", fmt.Sprintf("description must not be longer than %v characters", maxSyncDescriptionLength))
+	}
+	return nil
+}
+
+// ValidateSyncEntries checks that not too many entries are synced at once
+func ValidateSyncEntries(entries []*SyncEntry) error {
+	if len(entries) > maxSyncEntries {
+		return shared.NewInvalidParam("entries", "max", fmt.Sprintf("at most %v entries can be synced at once", maxSyncEntries))
+	}
+	return nil
+}
+
+// Validate checks that the entry ends after it starts and its description is not too long
+func (e *SyncEntry) Validate() error {
+	if e.ID == uuid.Nil {
+		return shared.NewInvalidParam("id", "required", "id is required")
+	}
+	if !e.End.After(e.Start) {
+		return shared.NewInvalidParam("end", "after", "end must be after start")
+	}
+	if utf8.RuneCountInString(e.Description) > maxSyncDescriptionLength {
+		return shared.NewInvalidParam("description", "max", fmt.Sprintf("description must not be longer than %v characters", maxSyncDescriptionLength))
+	}
+	return nil
+}
+
+// ToActivity is the activity of the entry
+func (e *SyncEntry) ToActivity() *Activity {
+	return &Activity{
+		Start:       e.Start,
+		End:         e.End,
+		Description: e.Description,
+		ProjectID:   e.ProjectID,
+	}
+}
@@ -0,0 +1,49 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestTimerStateToActivity(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	state := NewTimerState(shared.OrganizationIDSample, "user1")
+	is.True(!state.IsRunning())
+	is.True(state.ToActivity(start) == nil)
+
+	state.Start = &start
+	state.Description = "Desk"
+	activity := state.ToActivity(start.Add(45*time.Minute + 20*time.Second))
+	is.Equal(activity.End, start.Add(45*time.Minute))
+	is.Equal(activity.Description, "Desk")
+
+	is.True(state.ToActivity(start.Add(30*time.Second)) == nil)
+}
+
+func TestValidateSyncCommand(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr((&SyncCommand{Type: SyncCommandStart}).Validate())
+	is.NoErr((&SyncCommand{Type: SyncCommandStop}).Validate())
+	is.True((&SyncCommand{Type: "pause"}).Validate() != nil)
+	is.True((&SyncCommand{Type: SyncCommandStart, Description: strings.Repeat("a", 501)}).Validate() != nil)
+}
+
+func TestValidateSyncEntry(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	is.NoErr((&SyncEntry{ID: uuid.New(), Start: start, End: start.Add(time.Hour)}).Validate())
+	is.True((&SyncEntry{Start: start, End: start.Add(time.Hour)}).Validate() != nil)
+	is.True((&SyncEntry{ID: uuid.New(), Start: start, End: start}).Validate() != nil)
+
+	is.NoErr(ValidateSyncEntries(make([]*SyncEntry, 100)))
+	is.True(ValidateSyncEntries(make([]*SyncEntry, 101)) != nil)
+}
@@ -0,0 +1,145 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbSyncRepository is a SQL database repository for the timers and offline entries of desktop clients
+type DbSyncRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ SyncRepository = (*DbSyncRepository)(nil)
+
+// NewDbSyncRepository creates a new SQL database repository for desktop client sync
+func NewDbSyncRepository(connPool *pgxpool.Pool) *DbSyncRepository {
+	return &DbSyncRepository{
+		connPool: connPool,
+	}
+}
+
+// FindTimerState reads the timer of the user, users who never started a timer have a stopped timer
+func (r *DbSyncRepository) FindTimerState(ctx context.Context, organizationID uuid.UUID, username string) (*TimerState, error) {
+	row, err := shared.SelectOne[timerStateRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[timerStateRow]()+`
+		 FROM sync_timers
+		 WHERE org_id = $1 AND username = $2`,
+		organizationID, username,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return NewTimerState(organizationID, username), nil
+		}
+
+		return nil, err
+	}
+
+	return row.toTimerState(), nil
+}
+
+// UpdateTimerState writes the timer if it's still at the previous version
+func (r *DbSyncRepository) UpdateTimerState(ctx context.Context, state *TimerState, previousVersion int) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	var projectID *uuid.UUID
+	if state.ProjectID != uuid.Nil {
+		projectID = &state.ProjectID
+	}
+
+	result, err := tx.Exec(
+		ctx,
+		`INSERT INTO sync_timers
+		   (org_id, username, project_id, description, started_at, version)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (org_id, username) DO UPDATE
+		 SET project_id = $3, description = $4, started_at = $5, version = $6
+		 WHERE sync_timers.version = $7`,
+		state.OrganizationID,
+		state.Username,
+		projectID,
+		sql.NullString{String: state.Description, Valid: state.Description != ""},
+		state.Start,
+		state.Version,
+		previousVersion,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrTimerStateConflict
+	}
+	return nil
+}
+
+func (r *DbSyncRepository) FindSyncedEntry(ctx context.Context, organizationID uuid.UUID, username string, entryID uuid.UUID) (uuid.UUID, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT activity_id
+		 FROM sync_entries
+		 WHERE org_id = $1 AND username = $2 AND entry_id = $3`,
+		organizationID, username, entryID,
+	)
+
+	var activityID uuid.UUID
+	err := row.Scan(&activityID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrSyncEntryNotFound
+		}
+
+		return uuid.Nil, err
+	}
+
+	return activityID, nil
+}
+
+func (r *DbSyncRepository) InsertSyncedEntry(ctx context.Context, organizationID uuid.UUID, username string, entryID, activityID uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO sync_entries
+		   (org_id, username, entry_id, activity_id)
+		 VALUES
+		   ($1, $2, $3, $4)`,
+		organizationID, username, entryID, activityID,
+	)
+	return err
+}
+
+type timerStateRow struct {
+	OrganizationID uuid.UUID  `db:"org_id"`
+	Username       string     `db:"username"`
+	ProjectID      *uuid.UUID `db:"project_id"`
+	Description    *string    `db:"description"`
+	Start          *time.Time `db:"started_at"`
+	Version        int        `db:"version"`
+}
+
+func (r *timerStateRow) toTimerState() *TimerState {
+	state := &TimerState{
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		Start:          r.Start,
+		Version:        r.Version,
+	}
+	if r.ProjectID != nil {
+		state.ProjectID = *r.ProjectID
+	}
+	if r.Description != nil {
+		state.Description = *r.Description
+	}
+	return state
+}
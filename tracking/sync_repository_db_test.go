@@ -0,0 +1,90 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestSyncRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	syncRepository := NewDbSyncRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	t.Run("UpdateTimerState", func(t *testing.T) {
+		start := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+		state := NewTimerState(shared.OrganizationIDSample, "user1")
+		state.ProjectID = shared.ProjectIDSample
+		state.Description = "Desk"
+		state.Start = &start
+		state.Version = 1
+
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return syncRepository.UpdateTimerState(ctx, state, 0)
+			},
+		)
+		is.NoErr(err)
+
+		found, err := syncRepository.FindTimerState(context.Background(), shared.OrganizationIDSample, "user1")
+		is.NoErr(err)
+		is.Equal(found.Version, 1)
+		is.Equal(found.Description, "Desk")
+		is.True(found.IsRunning())
+
+		// outdated version
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return syncRepository.UpdateTimerState(ctx, state, 0)
+			},
+		)
+		is.True(errors.Is(err, ErrTimerStateConflict))
+	})
+
+	t.Run("InsertSyncedEntry", func(t *testing.T) {
+		entryID := uuid.New()
+		activityID := uuid.New()
+
+		_, err := syncRepository.FindSyncedEntry(context.Background(), shared.OrganizationIDSample, "user1", entryID)
+		is.True(errors.Is(err, ErrSyncEntryNotFound))
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return syncRepository.InsertSyncedEntry(ctx, shared.OrganizationIDSample, "user1", entryID, activityID)
+			},
+		)
+		is.NoErr(err)
+
+		found, err := syncRepository.FindSyncedEntry(context.Background(), shared.OrganizationIDSample, "user1", entryID)
+		is.NoErr(err)
+		is.Equal(found, activityID)
+	})
+}
@@ -0,0 +1,76 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemSyncRepository struct {
+	mu            sync.Mutex
+	timerStates   []*TimerState
+	syncedEntries map[uuid.UUID]uuid.UUID
+}
+
+var _ SyncRepository = (*InMemSyncRepository)(nil)
+
+func NewInMemSyncRepository() *InMemSyncRepository {
+	return &InMemSyncRepository{
+		syncedEntries: make(map[uuid.UUID]uuid.UUID),
+	}
+}
+
+func (r *InMemSyncRepository) FindTimerState(ctx context.Context, organizationID uuid.UUID, username string) (*TimerState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, state := range r.timerStates {
+		if state.OrganizationID == organizationID && state.Username == username {
+			found := *state
+			return &found, nil
+		}
+	}
+	return NewTimerState(organizationID, username), nil
+}
+
+func (r *InMemSyncRepository) UpdateTimerState(ctx context.Context, state *TimerState, previousVersion int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := *state
+	for i, s := range r.timerStates {
+		if s.OrganizationID == state.OrganizationID && s.Username == state.Username {
+			if s.Version != previousVersion {
+				return ErrTimerStateConflict
+			}
+			r.timerStates[i] = &updated
+			return nil
+		}
+	}
+
+	if previousVersion != 0 {
+		return ErrTimerStateConflict
+	}
+	r.timerStates = append(r.timerStates, &updated)
+	return nil
+}
+
+func (r *InMemSyncRepository) FindSyncedEntry(ctx context.Context, organizationID uuid.UUID, username string, entryID uuid.UUID) (uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	activityID, ok := r.syncedEntries[entryID]
+	if !ok {
+		return uuid.Nil, ErrSyncEntryNotFound
+	}
+	return activityID, nil
+}
+
+func (r *InMemSyncRepository) InsertSyncedEntry(ctx context.Context, organizationID uuid.UUID, username string, entryID, activityID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.syncedEntries[entryID] = activityID
+	return nil
+}
@@ -0,0 +1,258 @@
+package tracking
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type timerStateModel struct {
+	Running     bool       `json:"running"`
+	ProjectID   string     `json:"projectId,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Start       string     `json:"start,omitempty"`
+	Version     int        `json:"version"`
+	Links       *hal.Links `json:"_links"`
+}
+
+type syncCommandModel struct {
+	Type        string `json:"type"`
+	ProjectID   string `json:"projectId"`
+	Description string `json:"description"`
+	At          string `json:"at"`
+}
+
+type syncCommandResultModel struct {
+	Timer   *timerStateModel `json:"timer"`
+	Stopped *activityModel   `json:"stopped,omitempty"`
+}
+
+type syncEntryModel struct {
+	ID          string `json:"id"`
+	ProjectID   string `json:"projectId"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	Description string `json:"description"`
+}
+
+type syncEntriesModel struct {
+	Entries []*syncEntryModel `json:"entries"`
+}
+
+type syncEntryResultModel struct {
+	ID         string `json:"id"`
+	ActivityID string `json:"activityId,omitempty"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+}
+
+type syncEntryResultsModel struct {
+	Results []*syncEntryResultModel `json:"results"`
+}
+
+type SyncRestHandlers struct {
+	config      *shared.Config
+	syncService *SyncService
+}
+
+func NewSyncRestHandlers(config *shared.Config, syncService *SyncService) *SyncRestHandlers {
+	return &SyncRestHandlers{
+		config:      config,
+		syncService: syncService,
+	}
+}
+
+func (a *SyncRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/sync/timer", a.HandleGetTimerState())
+	r.Post("/sync/commands", a.HandleSyncCommand())
+	r.Post("/sync/entries", a.HandleSyncEntries())
+}
+
+func (a *SyncRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleGetTimerState reads the timer of the user, with the query params version and wait (in seconds)
+// the request is held until the timer is no longer at the version or the wait is over (long polling)
+func (a *SyncRestHandlers) HandleGetTimerState() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	syncService := a.syncService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var (
+			state *TimerState
+			err   error
+		)
+
+		if r.URL.Query().Get("version") == "" {
+			state, err = syncService.ReadTimerState(r.Context(), principal)
+		} else {
+			version, convErr := strconv.Atoi(r.URL.Query().Get("version"))
+			if convErr != nil {
+				shared.RenderValidationProblemJSON(w, "invalid version", shared.NewInvalidParam("version", "number", "must be a number"))
+				return
+			}
+
+			wait := 0
+			if r.URL.Query().Get("wait") != "" {
+				wait, convErr = strconv.Atoi(r.URL.Query().Get("wait"))
+				if convErr != nil || wait < 0 {
+					shared.RenderValidationProblemJSON(w, "invalid wait", shared.NewInvalidParam("wait", "number", "must be a number of seconds"))
+					return
+				}
+			}
+
+			state, err = syncService.WaitForTimerState(r.Context(), principal, version, time.Duration(wait)*time.Second)
+		}
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		shared.RenderJSON(w, mapToTimerStateModel(state))
+	}
+}
+
+// HandleSyncCommand starts or stops the timer of the user
+func (a *SyncRestHandlers) HandleSyncCommand() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	syncService := a.syncService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var commandModel syncCommandModel
+		err := json.NewDecoder(r.Body).Decode(&commandModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "sync command not valid", err)
+			return
+		}
+
+		command, err := mapToSyncCommand(&commandModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "sync command not valid", err)
+			return
+		}
+
+		state, stopped, err := syncService.ExecuteSyncCommand(r.Context(), principal, command, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		resultModel := &syncCommandResultModel{
+			Timer: mapToTimerStateModel(state),
+		}
+		if stopped != nil {
+			resultModel.Stopped = mapToActivityModel(stopped)
+		}
+		shared.RenderJSON(w, resultModel)
+	}
+}
+
+// HandleSyncEntries creates activities of the entries tracked offline by the desktop client
+func (a *SyncRestHandlers) HandleSyncEntries() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	syncService := a.syncService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		var entriesModel syncEntriesModel
+		err := json.NewDecoder(r.Body).Decode(&entriesModel)
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "sync entries not valid", err)
+			return
+		}
+
+		entries := make([]*SyncEntry, len(entriesModel.Entries))
+		for i, entryModel := range entriesModel.Entries {
+			entries[i] = mapToSyncEntry(entryModel)
+		}
+
+		results, err := syncService.SyncEntries(r.Context(), principal, entries)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		resultModels := make([]*syncEntryResultModel, len(results))
+		for i, result := range results {
+			resultModels[i] = &syncEntryResultModel{
+				ID:      result.EntryID.String(),
+				Status:  result.Status,
+				Message: result.Message,
+			}
+			if result.ActivityID != uuid.Nil {
+				resultModels[i].ActivityID = result.ActivityID.String()
+			}
+		}
+		shared.RenderJSON(w, &syncEntryResultsModel{Results: resultModels})
+	}
+}
+
+func mapToTimerStateModel(state *TimerState) *timerStateModel {
+	stateModel := &timerStateModel{
+		Running:     state.IsRunning(),
+		Description: state.Description,
+		Version:     state.Version,
+		Links: hal.NewLinks(
+			hal.NewSelfLink("/api/sync/timer"),
+		),
+	}
+	if state.ProjectID != uuid.Nil {
+		stateModel.ProjectID = state.ProjectID.String()
+	}
+	if state.Start != nil {
+		stateModel.Start = time_utils.FormatDateTime(*state.Start)
+	}
+	return stateModel
+}
+
+func mapToSyncCommand(commandModel *syncCommandModel) (*SyncCommand, error) {
+	command := &SyncCommand{
+		Type:        commandModel.Type,
+		Description: commandModel.Description,
+	}
+
+	if commandModel.ProjectID != "" {
+		projectID, err := uuid.Parse(commandModel.ProjectID)
+		if err != nil {
+			return nil, shared.NewInvalidParam("projectId", "uuid", "project id must be a uuid")
+		}
+		command.ProjectID = projectID
+	}
+
+	if commandModel.At != "" {
+		at, err := time_utils.ParseDateTime(commandModel.At)
+		if err != nil {
+			return nil, shared.NewInvalidParam("at", "datetime", "must be a date time like 2021-12-31T10:00:00")
+		}
+		command.At = *at
+	}
+
+	return command, command.Validate()
+}
+
+// mapToSyncEntry maps the entry leniently, entries not valid are rejected by the sync one by one
+func mapToSyncEntry(entryModel *syncEntryModel) *SyncEntry {
+	entry := &SyncEntry{
+		Description: entryModel.Description,
+	}
+
+	entry.ID, _ = uuid.Parse(entryModel.ID)
+	entry.ProjectID, _ = uuid.Parse(entryModel.ProjectID)
+
+	if start, err := time_utils.ParseDateTime(entryModel.Start); err == nil {
+		entry.Start = *start
+	}
+	if end, err := time_utils.ParseDateTime(entryModel.End); err == nil {
+		entry.End = *end
+	}
+	return entry
+}
@@ -0,0 +1,73 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestHandleSync(t *testing.T) {
+	is := is.New(t)
+
+	a := NewSyncRestHandlers(&shared.Config{}, newSyncServiceForTest())
+
+	request := func(method, url, body string) *http.Request {
+		r, _ := http.NewRequest(method, url, strings.NewReader(body))
+		return r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Roles:          []string{"ROLE_USER"},
+		}))
+	}
+
+	httpRec := httptest.NewRecorder()
+	a.HandleSyncCommand()(httpRec, request("POST", "/api/sync/commands", `{"type": "pause"}`))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleSyncCommand()(httpRec, request("POST", "/api/sync/commands", `{"type": "stop"}`))
+	is.Equal(httpRec.Result().StatusCode, http.StatusConflict)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleSyncCommand()(httpRec, request("POST", "/api/sync/commands", fmt.Sprintf(`{"type": "start", "projectId": "%s", "description": "Desk"}`, shared.ProjectIDSample)))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	resultModel := &syncCommandResultModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(resultModel)
+	is.NoErr(err)
+	is.True(resultModel.Timer.Running)
+	is.Equal(resultModel.Timer.Version, 1)
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetTimerState()(httpRec, request("GET", "/api/sync/timer?version=0&wait=10", ""))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	stateModel := &timerStateModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(stateModel)
+	is.NoErr(err)
+	is.Equal(stateModel.Description, "Desk")
+
+	httpRec = httptest.NewRecorder()
+	a.HandleGetTimerState()(httpRec, request("GET", "/api/sync/timer?version=x", ""))
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+
+	entryID := uuid.New()
+	httpRec = httptest.NewRecorder()
+	a.HandleSyncEntries()(httpRec, request("POST", "/api/sync/entries", fmt.Sprintf(`{"entries": [{"id": "%s", "projectId": "%s", "start": "2024-03-04T08:00:00", "end": "2024-03-04T09:00:00"}, {"id": "invalid"}]}`, entryID, shared.ProjectIDSample)))
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	resultsModel := &syncEntryResultsModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(resultsModel)
+	is.NoErr(err)
+	is.Equal(resultsModel.Results[0].ID, entryID.String())
+	is.Equal(resultsModel.Results[0].Status, SyncEntryCreated)
+	is.Equal(resultsModel.Results[1].Status, SyncEntryRejected)
+}
@@ -0,0 +1,256 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// SyncService keeps the desktop clients of a user in sync, clients wait for changes of the timer,
+// start and stop the timer and sync the entries tracked offline
+type SyncService struct {
+	repositoryTxer  shared.RepositoryTxer
+	syncRepository  SyncRepository
+	activityService *ActitivityService
+	notifier        *timerStateNotifier
+}
+
+// NewSyncService creates a new service for the sync of desktop clients
+func NewSyncService(repositoryTxer shared.RepositoryTxer, syncRepository SyncRepository, activityService *ActitivityService) *SyncService {
+	return &SyncService{
+		repositoryTxer:  repositoryTxer,
+		syncRepository:  syncRepository,
+		activityService: activityService,
+		notifier:        newTimerStateNotifier(),
+	}
+}
+
+// ReadTimerState reads the timer of the principal
+func (s *SyncService) ReadTimerState(ctx context.Context, principal *shared.Principal) (*TimerState, error) {
+	return s.syncRepository.FindTimerState(ctx, principal.OrganizationID, principal.Username)
+}
+
+// WaitForTimerState reads the timer of the principal as soon as it's no longer at the version, but waits at most
+// for the duration. Changes on other instances of the app are noticed by checking the timer again periodically.
+func (s *SyncService) WaitForTimerState(ctx context.Context, principal *shared.Principal, version int, wait time.Duration) (*TimerState, error) {
+	if wait > maxTimerStateWait {
+		wait = maxTimerStateWait
+	}
+
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+
+	for {
+		changed, unsubscribe := s.notifier.subscribe(principal.OrganizationID, principal.Username)
+
+		state, err := s.syncRepository.FindTimerState(ctx, principal.OrganizationID, principal.Username)
+		if err != nil || state.Version != version || wait <= 0 {
+			unsubscribe()
+			return state, err
+		}
+
+		select {
+		case <-ctx.Done():
+			unsubscribe()
+			return nil, ctx.Err()
+		case <-deadline.C:
+			unsubscribe()
+			return state, nil
+		case <-changed:
+		case <-time.After(timerStateRecheckInterval):
+		}
+		unsubscribe()
+	}
+}
+
+// ExecuteSyncCommand starts or stops the timer of the principal at the time of the command, commands
+// queued offline are executed at the time they were given. Stopping the timer tracks an activity from
+// its start, starting a running timer stops it first.
+func (s *SyncService) ExecuteSyncCommand(ctx context.Context, principal *shared.Principal, command *SyncCommand, now time.Time) (*TimerState, *Activity, error) {
+	at := command.At
+	if at.IsZero() || at.After(now) {
+		at = now
+	}
+
+	state, err := s.syncRepository.FindTimerState(ctx, principal.OrganizationID, principal.Username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if command.Type == SyncCommandStop && !state.IsRunning() {
+		return nil, nil, ErrTimerNotRunning
+	}
+
+	newState := NewTimerState(principal.OrganizationID, principal.Username)
+	newState.Version = state.Version + 1
+	if command.Type == SyncCommandStart {
+		start := at.Truncate(time.Minute)
+		newState.ProjectID = command.ProjectID
+		newState.Description = command.Description
+		newState.Start = &start
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.syncRepository.UpdateTimerState(ctx, newState, state.Version)
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var stopped *Activity
+	if activity := state.ToActivity(at); activity != nil {
+		stopped, err = s.activityService.CreateActivityFromSource(ctx, principal, activity, ActivitySourceSync, true)
+		if err != nil {
+			// restore the timer so the tracked time is not lost
+			state.Version = newState.Version + 1
+			restoreErr := s.repositoryTxer.InTx(
+				ctx,
+				func(ctx context.Context) error {
+					return s.syncRepository.UpdateTimerState(ctx, state, newState.Version)
+				},
+			)
+			if restoreErr == nil {
+				s.notifier.notify(principal.OrganizationID, principal.Username)
+			}
+			return nil, nil, err
+		}
+	}
+
+	s.notifier.notify(principal.OrganizationID, principal.Username)
+	return newState, stopped, nil
+}
+
+// SyncEntries creates activities of the entries tracked offline at once, entries already synced are skipped
+// and entries not valid are rejected without stopping the sync of the other entries
+func (s *SyncService) SyncEntries(ctx context.Context, principal *shared.Principal, entries []*SyncEntry) ([]*SyncEntryResult, error) {
+	err := ValidateSyncEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*SyncEntryResult, len(entries))
+	var (
+		newEntries    []*SyncEntry
+		newResults    []*SyncEntryResult
+		newActivities []*Activity
+	)
+	for i, entry := range entries {
+		result := &SyncEntryResult{
+			EntryID: entry.ID,
+		}
+		results[i] = result
+
+		err := entry.Validate()
+		if err != nil {
+			result.Status = SyncEntryRejected
+			result.Message = err.Error()
+			continue
+		}
+
+		activityID, err := s.syncRepository.FindSyncedEntry(ctx, principal.OrganizationID, principal.Username, entry.ID)
+		if err == nil {
+			result.ActivityID = activityID
+			result.Status = SyncEntryDuplicate
+			continue
+		}
+		if !errors.Is(err, ErrSyncEntryNotFound) {
+			return nil, err
+		}
+
+		newEntries = append(newEntries, entry)
+		newResults = append(newResults, result)
+		newActivities = append(newActivities, entry.ToActivity())
+	}
+
+	if len(newActivities) == 0 {
+		return results, nil
+	}
+
+	rejected, err := s.activityService.CreateActivities(ctx, principal, newActivities, ActivitySourceSync, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var syncedEntries []func(ctx context.Context) error
+	for i, entry := range newEntries {
+		result := newResults[i]
+		if err, ok := rejected[i]; ok {
+			domainError := shared.DomainErrorOf(err)
+			if domainError == shared.ErrInternal {
+				return nil, err
+			}
+
+			result.Status = SyncEntryRejected
+			result.Message = domainError.Title
+			continue
+		}
+
+		entryID, activityID := entry.ID, newActivities[i].ID
+		syncedEntries = append(syncedEntries, func(ctx context.Context) error {
+			return s.syncRepository.InsertSyncedEntry(ctx, principal.OrganizationID, principal.Username, entryID, activityID)
+		})
+		result.ActivityID = activityID
+		result.Status = SyncEntryCreated
+	}
+
+	if len(syncedEntries) > 0 {
+		err = s.repositoryTxer.InTx(ctx, syncedEntries...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// timerStateNotifier wakes up the clients waiting for changes of the timer of a user on this instance of the app
+type timerStateNotifier struct {
+	mu      sync.Mutex
+	waiters map[string]map[chan struct{}]struct{}
+}
+
+func newTimerStateNotifier() *timerStateNotifier {
+	return &timerStateNotifier{
+		waiters: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+func (n *timerStateNotifier) subscribe(organizationID uuid.UUID, username string) (<-chan struct{}, func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	key := organizationID.String() + "/" + username
+	changed := make(chan struct{}, 1)
+	if n.waiters[key] == nil {
+		n.waiters[key] = make(map[chan struct{}]struct{})
+	}
+	n.waiters[key][changed] = struct{}{}
+
+	return changed, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		delete(n.waiters[key], changed)
+		if len(n.waiters[key]) == 0 {
+			delete(n.waiters, key)
+		}
+	}
+}
+
+func (n *timerStateNotifier) notify(organizationID uuid.UUID, username string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for changed := range n.waiters[organizationID.String()+"/"+username] {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func newSyncServiceForTest() *SyncService {
+	return NewSyncService(
+		shared.NewInMemRepositoryTxer(),
+		NewInMemSyncRepository(),
+		&ActitivityService{
+			repositoryTxer:               shared.NewInMemRepositoryTxer(),
+			activityRepository:           NewInMemActivityRepository(),
+			locationPolicyRepository:     NewInMemLocationPolicyRepository(),
+			breakPolicyRepository:        NewInMemBreakPolicyRepository(),
+			categorizationRuleRepository: NewInMemCategorizationRuleRepository(),
+		},
+	)
+}
+
+func TestExecuteSyncCommand(t *testing.T) {
+	is := is.New(t)
+
+	s := newSyncServiceForTest()
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	_, _, err := s.ExecuteSyncCommand(context.Background(), principal, &SyncCommand{Type: SyncCommandStop}, now)
+	is.True(errors.Is(err, ErrTimerNotRunning))
+
+	state, stopped, err := s.ExecuteSyncCommand(context.Background(), principal, &SyncCommand{Type: SyncCommandStart, ProjectID: shared.ProjectIDSample, Description: "Desk"}, now)
+	is.NoErr(err)
+	is.True(stopped == nil)
+	is.True(state.IsRunning())
+	is.Equal(state.Version, 1)
+
+	// starting a running timer stops it first
+	state, stopped, err = s.ExecuteSyncCommand(context.Background(), principal, &SyncCommand{Type: SyncCommandStart, ProjectID: shared.ProjectIDSample, Description: "Call"}, now.Add(time.Hour))
+	is.NoErr(err)
+	is.Equal(stopped.Description, "Desk")
+	is.Equal(stopped.End.Sub(stopped.Start), time.Hour)
+	is.Equal(state.Description, "Call")
+	is.Equal(state.Version, 2)
+
+	// commands queued offline are executed at the time they were given
+	state, stopped, err = s.ExecuteSyncCommand(context.Background(), principal, &SyncCommand{Type: SyncCommandStop, At: now.Add(90 * time.Minute)}, now.Add(3*time.Hour))
+	is.NoErr(err)
+	is.Equal(stopped.End, now.Add(90*time.Minute))
+	is.True(!state.IsRunning())
+	is.Equal(state.Version, 3)
+}
+
+func TestWaitForTimerState(t *testing.T) {
+	is := is.New(t)
+
+	s := newSyncServiceForTest()
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+
+	// returns at once when the version is outdated
+	state, err := s.WaitForTimerState(context.Background(), principal, 7, time.Minute)
+	is.NoErr(err)
+	is.Equal(state.Version, 0)
+
+	// returns at once without wait
+	state, err = s.WaitForTimerState(context.Background(), principal, 0, 0)
+	is.NoErr(err)
+	is.Equal(state.Version, 0)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _, _ = s.ExecuteSyncCommand(context.Background(), principal, &SyncCommand{Type: SyncCommandStart, ProjectID: shared.ProjectIDSample}, time.Now())
+	}()
+
+	state, err = s.WaitForTimerState(context.Background(), principal, 0, time.Minute)
+	is.NoErr(err)
+	is.Equal(state.Version, 1)
+	is.True(state.IsRunning())
+}
+
+func TestSyncEntries(t *testing.T) {
+	is := is.New(t)
+
+	s := newSyncServiceForTest()
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+	start := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	entry := &SyncEntry{ID: uuid.New(), ProjectID: shared.ProjectIDSample, Start: start, End: start.Add(time.Hour), Description: "Offline"}
+	invalid := &SyncEntry{ID: uuid.New(), ProjectID: shared.ProjectIDSample, Start: start, End: start}
+
+	results, err := s.SyncEntries(context.Background(), principal, []*SyncEntry{entry, invalid})
+	is.NoErr(err)
+	is.Equal(results[0].Status, SyncEntryCreated)
+	is.True(results[0].ActivityID != uuid.Nil)
+	is.Equal(results[1].Status, SyncEntryRejected)
+
+	// entries synced twice are created once
+	again, err := s.SyncEntries(context.Background(), principal, []*SyncEntry{entry})
+	is.NoErr(err)
+	is.Equal(again[0].Status, SyncEntryDuplicate)
+	is.Equal(again[0].ActivityID, results[0].ActivityID)
+
+	_, err = s.SyncEntries(context.Background(), principal, make([]*SyncEntry, 101))
+	is.True(err != nil)
+}
@@ -0,0 +1,132 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// maxTaxRateBasisPoints is the maximum tax rate of 100 %
+const maxTaxRateBasisPoints = 10000
+
+// TaxSettings are the default tax rate of an organization and the rates of clients deviating from it,
+// rates are in basis points so 1900 is 19 %
+type TaxSettings struct {
+	OrganizationID  uuid.UUID
+	RateBasisPoints int
+	ClientTaxRates  map[uuid.UUID]*ClientTaxRate
+}
+
+// ClientTaxRate is the tax rate of a client, with reverse charge the client owes the tax
+// instead of the organization so invoices carry no tax but the VAT ID of the client
+type ClientTaxRate struct {
+	RateBasisPoints int
+	ReverseCharge   bool
+	VATID           string
+}
+
+// TaxRate is the tax rate applied to an invoice
+type TaxRate struct {
+	RateBasisPoints int
+	ReverseCharge   bool
+	BuyerVATID      string
+}
+
+// VATSummaryItem sums up the net amounts and taxes of the invoices with the same tax rate
+type VATSummaryItem struct {
+	RateBasisPoints int
+	ReverseCharge   bool
+	Currency        string
+	Invoices        int
+	NetAmountCents  int
+	TaxAmountCents  int
+}
+
+type TaxRepository interface {
+	FindTaxSettings(ctx context.Context, organizationID uuid.UUID) (*TaxSettings, error)
+	UpdateTaxSettings(ctx context.Context, taxSettings *TaxSettings) error
+}
+
+// NewTaxSettings creates the tax settings of an organization without taxes
+func NewTaxSettings(organizationID uuid.UUID) *TaxSettings {
+	return &TaxSettings{
+		OrganizationID: organizationID,
+		ClientTaxRates: make(map[uuid.UUID]*ClientTaxRate),
+	}
+}
+
+// TaxRateOf is the tax rate of the client or the default rate if the client has none
+func (s *TaxSettings) TaxRateOf(clientID uuid.UUID) *TaxRate {
+	clientTaxRate, ok := s.ClientTaxRates[clientID]
+	if !ok {
+		return &TaxRate{RateBasisPoints: s.RateBasisPoints}
+	}
+
+	if clientTaxRate.ReverseCharge {
+		return &TaxRate{ReverseCharge: true, BuyerVATID: clientTaxRate.VATID}
+	}
+	return &TaxRate{RateBasisPoints: clientTaxRate.RateBasisPoints, BuyerVATID: clientTaxRate.VATID}
+}
+
+// TaxCents is the tax on the net amount in cents, rounded half away from zero to full cents
+func (r *TaxRate) TaxCents(netCents int) int {
+	if r.ReverseCharge {
+		return 0
+	}
+
+	tax := netCents * r.RateBasisPoints
+	if tax < 0 {
+		return -((-tax + maxTaxRateBasisPoints/2) / maxTaxRateBasisPoints)
+	}
+	return (tax + maxTaxRateBasisPoints/2) / maxTaxRateBasisPoints
+}
+
+// Percent formats the rate as percentage (e.g. 19.00)
+func (r *TaxRate) Percent() string {
+	return formatBasisPoints(r.RateBasisPoints)
+}
+
+// NewVATSummary sums up the invoices per currency and tax rate, ordered by currency and rate
+func NewVATSummary(invoices []*AccountingInvoice) []*VATSummaryItem {
+	type summaryKey struct {
+		currency        string
+		rateBasisPoints int
+		reverseCharge   bool
+	}
+
+	itemsByKey := make(map[summaryKey]*VATSummaryItem)
+	var items []*VATSummaryItem
+	for _, invoice := range invoices {
+		key := summaryKey{invoice.Statement.Client.Currency, invoice.Tax.RateBasisPoints, invoice.Tax.ReverseCharge}
+		item, ok := itemsByKey[key]
+		if !ok {
+			item = &VATSummaryItem{
+				RateBasisPoints: key.rateBasisPoints,
+				ReverseCharge:   key.reverseCharge,
+				Currency:        key.currency,
+			}
+			itemsByKey[key] = item
+			items = append(items, item)
+		}
+		item.Invoices++
+		item.NetAmountCents += invoice.NetAmountCents()
+		item.TaxAmountCents += invoice.TaxAmountCents()
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Currency != items[j].Currency {
+			return items[i].Currency < items[j].Currency
+		}
+		if items[i].ReverseCharge != items[j].ReverseCharge {
+			return !items[i].ReverseCharge
+		}
+		return items[i].RateBasisPoints > items[j].RateBasisPoints
+	})
+	return items
+}
+
+func formatBasisPoints(basisPoints int) string {
+	return fmt.Sprintf("%d.%02d", basisPoints/100, basisPoints%100)
+}
@@ -0,0 +1,84 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestTaxRateTaxCents(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal((&TaxRate{RateBasisPoints: 1900}).TaxCents(13500), 2565)
+	is.Equal((&TaxRate{RateBasisPoints: 700}).TaxCents(1050), 74)
+	is.Equal((&TaxRate{RateBasisPoints: 700}).TaxCents(-1050), -74)
+	is.Equal((&TaxRate{RateBasisPoints: 810}).TaxCents(1234), 100)
+	is.Equal((&TaxRate{RateBasisPoints: 1900, ReverseCharge: true}).TaxCents(13500), 0)
+	is.Equal((&TaxRate{RateBasisPoints: 810}).Percent(), "8.10")
+}
+
+func TestTaxSettingsTaxRateOf(t *testing.T) {
+	is := is.New(t)
+
+	clientID := uuid.New()
+	reverseChargeClientID := uuid.New()
+	taxSettings := NewTaxSettings(uuid.New())
+	taxSettings.RateBasisPoints = 1900
+	taxSettings.ClientTaxRates[clientID] = &ClientTaxRate{RateBasisPoints: 700}
+	taxSettings.ClientTaxRates[reverseChargeClientID] = &ClientTaxRate{RateBasisPoints: 1900, ReverseCharge: true, VATID: "ATU12345678"}
+
+	is.Equal(taxSettings.TaxRateOf(uuid.New()).RateBasisPoints, 1900)
+	is.Equal(taxSettings.TaxRateOf(clientID).RateBasisPoints, 700)
+	is.Equal(taxSettings.TaxRateOf(reverseChargeClientID), &TaxRate{ReverseCharge: true, BuyerVATID: "ATU12345678"})
+}
+
+func TestAccountingInvoiceLineTaxCents(t *testing.T) {
+	is := is.New(t)
+
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	clientID := uuid.New()
+	taxSettings := NewTaxSettings(uuid.New())
+	taxSettings.RateBasisPoints = 1900
+
+	// 3 lines of 0.25 EUR each have 0.05 EUR tax rounded but the invoice only 0.14 EUR
+	invoices := NewAccountingInvoices(month, []*ClientStatement{
+		{
+			Client: &Client{ID: clientID, Name: "ACME", HourlyRateCents: 100},
+			Items: []*ClientReportItem{
+				{ProjectTitle: "Web", Billable: true, DurationInMinutesTotal: 15},
+				{ProjectTitle: "App", Billable: true, DurationInMinutesTotal: 15},
+				{ProjectTitle: "API", Billable: true, DurationInMinutesTotal: 15},
+			},
+		},
+	}, taxSettings)
+
+	invoice := invoices[0]
+	is.Equal(invoice.NetAmountCents(), 75)
+	is.Equal(invoice.TaxAmountCents(), 14)
+	is.Equal(invoice.GrossAmountCents(), 89)
+	is.Equal(invoice.LineTaxCents(), []int{5, 5, 4})
+}
+
+func TestNewVATSummary(t *testing.T) {
+	is := is.New(t)
+
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	reducedClientID := uuid.New()
+	taxSettings := NewTaxSettings(uuid.New())
+	taxSettings.RateBasisPoints = 1900
+	taxSettings.ClientTaxRates[reducedClientID] = &ClientTaxRate{RateBasisPoints: 700}
+
+	items := []*ClientReportItem{{ProjectTitle: "Web", Billable: true, DurationInMinutesTotal: 60}}
+	invoices := NewAccountingInvoices(month, []*ClientStatement{
+		{Client: &Client{ID: uuid.New(), Name: "ACME", HourlyRateCents: 10000, Currency: "EUR"}, Items: items},
+		{Client: &Client{ID: reducedClientID, Name: "Initech", HourlyRateCents: 10000, Currency: "EUR"}, Items: items},
+		{Client: &Client{ID: uuid.New(), Name: "Globex", HourlyRateCents: 5000, Currency: "EUR"}, Items: items},
+	}, taxSettings)
+
+	vatSummary := NewVATSummary(invoices)
+	is.Equal(len(vatSummary), 2)
+	is.Equal(vatSummary[0], &VATSummaryItem{RateBasisPoints: 1900, Currency: "EUR", Invoices: 2, NetAmountCents: 15000, TaxAmountCents: 2850})
+	is.Equal(vatSummary[1], &VATSummaryItem{RateBasisPoints: 700, Currency: "EUR", Invoices: 1, NetAmountCents: 10000, TaxAmountCents: 700})
+}
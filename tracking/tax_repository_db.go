@@ -0,0 +1,128 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbTaxRepository is a SQL database repository for the tax rates of organizations and clients
+type DbTaxRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ TaxRepository = (*DbTaxRepository)(nil)
+
+// NewDbTaxRepository creates a new SQL database repository for tax rates
+func NewDbTaxRepository(connPool *pgxpool.Pool) *DbTaxRepository {
+	return &DbTaxRepository{
+		connPool: connPool,
+	}
+}
+
+// FindTaxSettings reads the tax rates of the organization, without settings no taxes are applied
+func (r *DbTaxRepository) FindTaxSettings(ctx context.Context, organizationID uuid.UUID) (*TaxSettings, error) {
+	taxSettings := NewTaxSettings(organizationID)
+
+	row, err := shared.SelectOne[taxSettingsRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[taxSettingsRow]()+`
+		 FROM tax_settings
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		taxSettings.RateBasisPoints = row.RateBasisPoints
+	}
+
+	clientTaxRateRows, err := shared.SelectAll[clientTaxRateRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[clientTaxRateRow]()+`
+		 FROM client_tax_rates
+		 WHERE org_id = $1`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, clientTaxRateRow := range clientTaxRateRows {
+		taxSettings.ClientTaxRates[clientTaxRateRow.ClientID] = &ClientTaxRate{
+			RateBasisPoints: clientTaxRateRow.RateBasisPoints,
+			ReverseCharge:   clientTaxRateRow.ReverseCharge,
+			VATID:           clientTaxRateRow.VATID,
+		}
+	}
+
+	return taxSettings, nil
+}
+
+// UpdateTaxSettings sets the default tax rate of the organization and replaces the tax rates of its clients
+func (r *DbTaxRepository) UpdateTaxSettings(ctx context.Context, taxSettings *TaxSettings) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO tax_settings
+		   (org_id, rate_basis_points)
+		 VALUES
+		   ($1, $2)
+		 ON CONFLICT (org_id) DO UPDATE
+		 SET rate_basis_points = EXCLUDED.rate_basis_points`,
+		taxSettings.OrganizationID,
+		taxSettings.RateBasisPoints,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`DELETE FROM client_tax_rates
+		 WHERE org_id = $1`,
+		taxSettings.OrganizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	for clientID, clientTaxRate := range taxSettings.ClientTaxRates {
+		_, err := tx.Exec(
+			ctx,
+			`INSERT INTO client_tax_rates
+			   (client_id, org_id, rate_basis_points, reverse_charge, vat_id)
+			 VALUES
+			   ($1, $2, $3, $4, $5)`,
+			clientID,
+			taxSettings.OrganizationID,
+			clientTaxRate.RateBasisPoints,
+			clientTaxRate.ReverseCharge,
+			clientTaxRate.VATID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type taxSettingsRow struct {
+	OrganizationID  uuid.UUID `db:"org_id"`
+	RateBasisPoints int       `db:"rate_basis_points"`
+}
+
+type clientTaxRateRow struct {
+	ClientID        uuid.UUID `db:"client_id"`
+	OrganizationID  uuid.UUID `db:"org_id"`
+	RateBasisPoints int       `db:"rate_basis_points"`
+	ReverseCharge   bool      `db:"reverse_charge"`
+	VATID           string    `db:"vat_id"`
+}
@@ -0,0 +1,77 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestTaxRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	clientRepository := NewDbClientRepository(connPool)
+	taxRepository := NewDbTaxRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	client := &Client{
+		ID:             uuid.New(),
+		OrganizationID: shared.OrganizationIDSample,
+		Name:           "ACME GmbH",
+		Currency:       "EUR",
+	}
+
+	t.Run("FindTaxSettingsWithoutSettings", func(t *testing.T) {
+		taxSettings, err := taxRepository.FindTaxSettings(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(taxSettings.RateBasisPoints, 0)
+		is.Equal(len(taxSettings.ClientTaxRates), 0)
+	})
+
+	t.Run("UpdateTaxSettings", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				_, err := clientRepository.InsertClient(ctx, client)
+				if err != nil {
+					return err
+				}
+				return taxRepository.UpdateTaxSettings(ctx, &TaxSettings{
+					OrganizationID:  shared.OrganizationIDSample,
+					RateBasisPoints: 1900,
+					ClientTaxRates: map[uuid.UUID]*ClientTaxRate{
+						client.ID: {ReverseCharge: true, VATID: "ATU12345678"},
+					},
+				})
+			},
+		)
+		is.NoErr(err)
+
+		taxSettings, err := taxRepository.FindTaxSettings(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.Equal(taxSettings.RateBasisPoints, 1900)
+		is.Equal(taxSettings.ClientTaxRates[client.ID].VATID, "ATU12345678")
+		is.True(taxSettings.ClientTaxRates[client.ID].ReverseCharge)
+	})
+}
@@ -0,0 +1,50 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type InMemTaxRepository struct {
+	mu          sync.Mutex
+	taxSettings map[uuid.UUID]*TaxSettings
+}
+
+var _ TaxRepository = (*InMemTaxRepository)(nil)
+
+func NewInMemTaxRepository() *InMemTaxRepository {
+	return &InMemTaxRepository{
+		taxSettings: make(map[uuid.UUID]*TaxSettings),
+	}
+}
+
+func (r *InMemTaxRepository) FindTaxSettings(ctx context.Context, organizationID uuid.UUID) (*TaxSettings, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	taxSettings, ok := r.taxSettings[organizationID]
+	if !ok {
+		return NewTaxSettings(organizationID), nil
+	}
+	return copyTaxSettings(taxSettings), nil
+}
+
+func (r *InMemTaxRepository) UpdateTaxSettings(ctx context.Context, taxSettings *TaxSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.taxSettings[taxSettings.OrganizationID] = copyTaxSettings(taxSettings)
+	return nil
+}
+
+func copyTaxSettings(taxSettings *TaxSettings) *TaxSettings {
+	copied := *taxSettings
+	copied.ClientTaxRates = make(map[uuid.UUID]*ClientTaxRate, len(taxSettings.ClientTaxRates))
+	for clientID, clientTaxRate := range taxSettings.ClientTaxRates {
+		copiedClientTaxRate := *clientTaxRate
+		copied.ClientTaxRates[clientID] = &copiedClientTaxRate
+	}
+	return &copied
+}
@@ -0,0 +1,59 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUndoTokenNotFound = shared.NewDomainError("undo:not-found", http.StatusNotFound, "undo token not found")
+	ErrUndoTokenExpired  = shared.NewDomainError("undo:expired", http.StatusGone, "undo token expired")
+)
+
+// DeletedActivity is a deleted activity kept in the trash, so the deletion can be undone until the token expires
+type DeletedActivity struct {
+	UndoToken uuid.UUID
+	Activity  *Activity
+	GoalIDs   []uuid.UUID
+	Links     []*ActivityLink
+	DeletedBy string
+	ExpiresAt time.Time
+}
+
+type UndoRepository interface {
+	InsertDeletedActivity(ctx context.Context, deletedActivity *DeletedActivity) error
+	FindDeletedActivity(ctx context.Context, organizationID, undoToken uuid.UUID) (*DeletedActivity, error)
+	DeleteDeletedActivity(ctx context.Context, organizationID, undoToken uuid.UUID) error
+	DeleteExpiredDeletedActivities(ctx context.Context, now time.Time) error
+}
+
+// NewDeletedActivity puts the activity with its goals and links into the trash for the undo window
+func NewDeletedActivity(activity *Activity, goals []*Goal, links []*ActivityLink, deletedBy string, now time.Time, undoWindow time.Duration) *DeletedActivity {
+	goalIDs := make([]uuid.UUID, len(goals))
+	for i, goal := range goals {
+		goalIDs[i] = goal.ID
+	}
+
+	return &DeletedActivity{
+		UndoToken: uuid.New(),
+		Activity:  activity,
+		GoalIDs:   goalIDs,
+		Links:     links,
+		DeletedBy: deletedBy,
+		ExpiresAt: now.Add(undoWindow),
+	}
+}
+
+// IsExpired checks whether the deletion can no longer be undone
+func (d *DeletedActivity) IsExpired(now time.Time) bool {
+	return !now.Before(d.ExpiresAt)
+}
+
+// CanBeUndoneBy checks whether the principal may undo the deletion, users only undo their own deletions
+func (d *DeletedActivity) CanBeUndoneBy(principal *shared.Principal) bool {
+	return principal.HasRole("ROLE_ADMIN") || d.DeletedBy == principal.Username
+}
@@ -0,0 +1,40 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestNewDeletedActivity(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Date(2021, 10, 4, 10, 0, 0, 0, time.UTC)
+	activity := &Activity{ID: uuid.New(), Username: "user1"}
+	goal := &Goal{ID: uuid.New()}
+	link := &ActivityLink{ID: uuid.New(), ActivityID: activity.ID, URL: "https://example.com/tickets/1"}
+
+	deletedActivity := NewDeletedActivity(activity, []*Goal{goal}, []*ActivityLink{link}, "user1", now, 5*time.Minute)
+
+	is.True(deletedActivity.UndoToken != uuid.Nil)
+	is.Equal(deletedActivity.Activity, activity)
+	is.Equal(deletedActivity.GoalIDs, []uuid.UUID{goal.ID})
+	is.Equal(deletedActivity.Links, []*ActivityLink{link})
+	is.Equal(deletedActivity.ExpiresAt, now.Add(5*time.Minute))
+
+	is.True(!deletedActivity.IsExpired(now.Add(4 * time.Minute)))
+	is.True(deletedActivity.IsExpired(now.Add(5 * time.Minute)))
+}
+
+func TestDeletedActivityCanBeUndoneBy(t *testing.T) {
+	is := is.New(t)
+
+	deletedActivity := &DeletedActivity{DeletedBy: "user1"}
+
+	is.True(deletedActivity.CanBeUndoneBy(&shared.Principal{Username: "user1"}))
+	is.True(deletedActivity.CanBeUndoneBy(&shared.Principal{Username: "admin", Roles: []string{"ROLE_ADMIN"}}))
+	is.True(!deletedActivity.CanBeUndoneBy(&shared.Principal{Username: "user2"}))
+}
@@ -0,0 +1,238 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// DbUndoRepository is a SQL database repository for the trash of deleted activities
+type DbUndoRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ UndoRepository = (*DbUndoRepository)(nil)
+
+// NewDbUndoRepository creates a new SQL database repository for the trash of deleted activities
+func NewDbUndoRepository(connPool *pgxpool.Pool) *DbUndoRepository {
+	return &DbUndoRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *DbUndoRepository) InsertDeletedActivity(ctx context.Context, deletedActivity *DeletedActivity) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	activity := deletedActivity.Activity
+	goalIDs := make([]string, len(deletedActivity.GoalIDs))
+	for i, goalID := range deletedActivity.GoalIDs {
+		goalIDs[i] = goalID.String()
+	}
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO deleted_activities
+		   (undo_token, org_id, activity_id, description, username, start_time, end_time, project_id,
+		    location, latitude, longitude, goal_ids, deleted_by, expires_at)
+		 VALUES
+		   ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		deletedActivity.UndoToken,
+		activity.OrganizationID,
+		activity.ID,
+		activity.Description,
+		activity.Username,
+		activity.Start,
+		activity.End,
+		activity.ProjectID,
+		nullableLocation(activity),
+		activity.Latitude,
+		activity.Longitude,
+		sql.NullString{String: strings.Join(goalIDs, ","), Valid: len(goalIDs) > 0},
+		deletedActivity.DeletedBy,
+		deletedActivity.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range deletedActivity.Links {
+		_, err := tx.Exec(
+			ctx,
+			`INSERT INTO deleted_activity_links
+			   (undo_token, link_id, org_id, url, title, favicon_url, created_by, created_at)
+			 VALUES
+			   ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			deletedActivity.UndoToken,
+			link.ID,
+			link.OrganizationID,
+			link.URL,
+			nullableString(link.Title),
+			nullableString(link.FaviconURL),
+			link.CreatedBy,
+			link.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *DbUndoRepository) FindDeletedActivity(ctx context.Context, organizationID, undoToken uuid.UUID) (*DeletedActivity, error) {
+	row, err := shared.SelectOne[deletedActivityRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[deletedActivityRow]()+`
+		 FROM deleted_activities
+		 WHERE undo_token = $1 AND org_id = $2`,
+		undoToken, organizationID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUndoTokenNotFound
+		}
+
+		return nil, err
+	}
+
+	deletedActivity, err := row.toDeletedActivity()
+	if err != nil {
+		return nil, err
+	}
+
+	linkRows, err := shared.SelectAll[deletedActivityLinkRow](
+		ctx,
+		r.connPool,
+		`SELECT `+shared.Columns[deletedActivityLinkRow]()+`
+		 FROM deleted_activity_links
+		 WHERE undo_token = $1 AND org_id = $2
+		 ORDER BY created_at`,
+		undoToken, organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, linkRow := range linkRows {
+		link := linkRow.toActivityLink()
+		link.ActivityID = deletedActivity.Activity.ID
+		deletedActivity.Links = append(deletedActivity.Links, link)
+	}
+	return deletedActivity, nil
+}
+
+func (r *DbUndoRepository) DeleteDeletedActivity(ctx context.Context, organizationID, undoToken uuid.UUID) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`DELETE FROM deleted_activities
+		 WHERE undo_token = $1 AND org_id = $2`,
+		undoToken, organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUndoTokenNotFound
+	}
+	return nil
+}
+
+// DeleteExpiredDeletedActivities empties the trash of the activities whose deletion can no longer be undone
+func (r *DbUndoRepository) DeleteExpiredDeletedActivities(ctx context.Context, now time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`DELETE FROM deleted_activities
+		 WHERE expires_at <= $1`,
+		now,
+	)
+	return err
+}
+
+type deletedActivityRow struct {
+	UndoToken      uuid.UUID `db:"undo_token"`
+	OrganizationID uuid.UUID `db:"org_id"`
+	ActivityID     uuid.UUID `db:"activity_id"`
+	Description    *string   `db:"description"`
+	Username       string    `db:"username"`
+	Start          time.Time `db:"start_time"`
+	End            time.Time `db:"end_time"`
+	ProjectID      uuid.UUID `db:"project_id"`
+	Location       *string   `db:"location"`
+	Latitude       *float64  `db:"latitude"`
+	Longitude      *float64  `db:"longitude"`
+	GoalIDs        *string   `db:"goal_ids"`
+	DeletedBy      string    `db:"deleted_by"`
+	ExpiresAt      time.Time `db:"expires_at"`
+}
+
+type deletedActivityLinkRow struct {
+	ID             uuid.UUID      `db:"link_id"`
+	OrganizationID uuid.UUID      `db:"org_id"`
+	URL            string         `db:"url"`
+	Title          sql.NullString `db:"title"`
+	FaviconURL     sql.NullString `db:"favicon_url"`
+	CreatedBy      string         `db:"created_by"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+func (r *deletedActivityLinkRow) toActivityLink() *ActivityLink {
+	return &ActivityLink{
+		ID:             r.ID,
+		OrganizationID: r.OrganizationID,
+		URL:            r.URL,
+		Title:          r.Title.String,
+		FaviconURL:     r.FaviconURL.String,
+		CreatedBy:      r.CreatedBy,
+		CreatedAt:      r.CreatedAt,
+	}
+}
+
+func (r *deletedActivityRow) toDeletedActivity() (*DeletedActivity, error) {
+	activity := &Activity{
+		ID:             r.ActivityID,
+		Start:          r.Start,
+		End:            r.End,
+		ProjectID:      r.ProjectID,
+		OrganizationID: r.OrganizationID,
+		Username:       r.Username,
+		Latitude:       r.Latitude,
+		Longitude:      r.Longitude,
+	}
+	if r.Description != nil {
+		activity.Description = *r.Description
+	}
+	if r.Location != nil {
+		activity.Location = *r.Location
+	}
+
+	var goalIDs []uuid.UUID
+	if r.GoalIDs != nil && *r.GoalIDs != "" {
+		for _, goalID := range strings.Split(*r.GoalIDs, ",") {
+			id, err := uuid.Parse(goalID)
+			if err != nil {
+				return nil, err
+			}
+			goalIDs = append(goalIDs, id)
+		}
+	}
+
+	return &DeletedActivity{
+		UndoToken: r.UndoToken,
+		Activity:  activity,
+		GoalIDs:   goalIDs,
+		DeletedBy: r.DeletedBy,
+		ExpiresAt: r.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+	"github.com/pkg/errors"
+)
+
+func TestUndoRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	undoRepository := NewDbUndoRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+
+	start, _ := time.Parse(time.RFC3339, "2021-11-12T11:00:00.000Z")
+	now := time.Date(2021, 11, 12, 12, 0, 0, 0, time.UTC)
+	goalID := uuid.New()
+	deletedActivity := &DeletedActivity{
+		UndoToken: uuid.New(),
+		Activity: &Activity{
+			ID:             uuid.New(),
+			Start:          start,
+			End:            start.Add(30 * time.Minute),
+			Description:    "Meeting",
+			ProjectID:      shared.ProjectIDSample,
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Location:       LocationOffice,
+		},
+		GoalIDs:   []uuid.UUID{goalID},
+		DeletedBy: "user1",
+		Links: []*ActivityLink{
+			{
+				ID:             uuid.New(),
+				OrganizationID: shared.OrganizationIDSample,
+				URL:            "https://example.com/tickets/1",
+				Title:          "Ticket 1",
+				CreatedBy:      "user1",
+				CreatedAt:      now,
+			},
+		},
+		ExpiresAt: now.Add(5 * time.Minute),
+	}
+
+	t.Run("InsertAndFindDeletedActivity", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return undoRepository.InsertDeletedActivity(ctx, deletedActivity)
+			},
+		)
+		is.NoErr(err)
+
+		found, err := undoRepository.FindDeletedActivity(context.Background(), shared.OrganizationIDSample, deletedActivity.UndoToken)
+		is.NoErr(err)
+		is.Equal(found.Activity.ID, deletedActivity.Activity.ID)
+		is.Equal(found.Activity.Description, "Meeting")
+		is.Equal(found.Activity.Location, LocationOffice)
+		is.Equal(found.GoalIDs, []uuid.UUID{goalID})
+		is.Equal(found.DeletedBy, "user1")
+		is.Equal(len(found.Links), 1)
+		is.Equal(found.Links[0].Title, "Ticket 1")
+		is.Equal(found.Links[0].ActivityID, deletedActivity.Activity.ID)
+	})
+
+	t.Run("DeleteExpiredDeletedActivities", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return undoRepository.DeleteExpiredDeletedActivities(ctx, now)
+			},
+		)
+		is.NoErr(err)
+
+		_, err = undoRepository.FindDeletedActivity(context.Background(), shared.OrganizationIDSample, deletedActivity.UndoToken)
+		is.NoErr(err)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return undoRepository.DeleteExpiredDeletedActivities(ctx, now.Add(5*time.Minute))
+			},
+		)
+		is.NoErr(err)
+
+		_, err = undoRepository.FindDeletedActivity(context.Background(), shared.OrganizationIDSample, deletedActivity.UndoToken)
+		is.True(errors.Is(err, ErrUndoTokenNotFound))
+	})
+
+	t.Run("DeleteNonExistingDeletedActivity", func(t *testing.T) {
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return undoRepository.DeleteDeletedActivity(ctx, shared.OrganizationIDSample, uuid.New())
+			},
+		)
+		is.True(errors.Is(err, ErrUndoTokenNotFound))
+	})
+}
@@ -0,0 +1,69 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InMemUndoRepository struct {
+	mu                sync.Mutex
+	deletedActivities []*DeletedActivity
+}
+
+var _ UndoRepository = (*InMemUndoRepository)(nil)
+
+func NewInMemUndoRepository() *InMemUndoRepository {
+	return &InMemUndoRepository{}
+}
+
+func (r *InMemUndoRepository) InsertDeletedActivity(ctx context.Context, deletedActivity *DeletedActivity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := *deletedActivity
+	r.deletedActivities = append(r.deletedActivities, &inserted)
+	return nil
+}
+
+func (r *InMemUndoRepository) FindDeletedActivity(ctx context.Context, organizationID, undoToken uuid.UUID) (*DeletedActivity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, deletedActivity := range r.deletedActivities {
+		if deletedActivity.UndoToken == undoToken && deletedActivity.Activity.OrganizationID == organizationID {
+			found := *deletedActivity
+			return &found, nil
+		}
+	}
+	return nil, ErrUndoTokenNotFound
+}
+
+func (r *InMemUndoRepository) DeleteDeletedActivity(ctx context.Context, organizationID, undoToken uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, deletedActivity := range r.deletedActivities {
+		if deletedActivity.UndoToken == undoToken && deletedActivity.Activity.OrganizationID == organizationID {
+			r.deletedActivities = append(r.deletedActivities[:i], r.deletedActivities[i+1:]...)
+			return nil
+		}
+	}
+	return ErrUndoTokenNotFound
+}
+
+func (r *InMemUndoRepository) DeleteExpiredDeletedActivities(ctx context.Context, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deletedActivities []*DeletedActivity
+	for _, deletedActivity := range r.deletedActivities {
+		if !deletedActivity.IsExpired(now) {
+			deletedActivities = append(deletedActivities, deletedActivity)
+		}
+	}
+	r.deletedActivities = deletedActivities
+	return nil
+}
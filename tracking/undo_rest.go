@@ -0,0 +1,73 @@
+package tracking
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"schneider.vip/problem"
+)
+
+type undoModel struct {
+	UndoToken string     `json:"undoToken"`
+	ExpiresAt string     `json:"expiresAt"`
+	Links     *hal.Links `json:"_links"`
+}
+
+type UndoRestHandlers struct {
+	config      *shared.Config
+	undoService *UndoService
+}
+
+func NewUndoRestHandlers(config *shared.Config, undoService *UndoService) *UndoRestHandlers {
+	return &UndoRestHandlers{
+		config:      config,
+		undoService: undoService,
+	}
+}
+
+func (a *UndoRestHandlers) RegisterProtected(r chi.Router) {
+	r.Post("/undo/{token}", a.HandleUndo())
+}
+
+func (a *UndoRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleUndo undoes the deletion of the undo token and renders the restored activity
+func (a *UndoRestHandlers) HandleUndo() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	undoService := a.undoService
+	return func(w http.ResponseWriter, r *http.Request) {
+		tokenParam := chi.URLParam(r, "token")
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		undoToken, err := uuid.Parse(tokenParam)
+		if err != nil {
+			http.Error(w, problem.New(problem.Wrap(err)).JSONString(), http.StatusBadRequest)
+			return
+		}
+
+		activity, err := undoService.Undo(r.Context(), principal, undoToken, time.Now())
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		w.Header().Set("HX-Trigger", "baralga__activities-changed")
+		renderActivityModel(w, r, activity)
+	}
+}
+
+func mapToUndoModel(deletedActivity *DeletedActivity) *undoModel {
+	return &undoModel{
+		UndoToken: deletedActivity.UndoToken.String(),
+		ExpiresAt: deletedActivity.ExpiresAt.UTC().Format(time.RFC3339),
+		Links: hal.NewLinks(
+			hal.NewLink("undo", fmt.Sprintf("/api/undo/%s", deletedActivity.UndoToken)),
+		),
+	}
+}
@@ -0,0 +1,59 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestHandleUndo(t *testing.T) {
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	undoService := newInMemUndoService(activityRepository, NewInMemGoalRepository())
+	a := &UndoRestHandlers{
+		config:      &shared.Config{},
+		undoService: undoService,
+	}
+
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	deletedActivity, err := undoService.DeleteActivityByID(context.Background(), principal, uuid.MustParse("00000000-0000-0000-2222-000000000001"), time.Now())
+	is.NoErr(err)
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, principal)))
+		})
+	})
+	a.RegisterProtected(router)
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("POST", "/undo/"+deletedActivity.UndoToken.String(), nil)
+	router.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	activityModel := &activityModel{}
+	err = json.NewDecoder(httpRec.Body).Decode(activityModel)
+	is.NoErr(err)
+	is.Equal(activityModel.ID, "00000000-0000-0000-2222-000000000001")
+	is.Equal(len(activityRepository.activities), 1)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("POST", "/undo/"+deletedActivity.UndoToken.String(), nil)
+	router.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusNotFound)
+
+	httpRec = httptest.NewRecorder()
+	r, _ = http.NewRequest("POST", "/undo/not-a-uuid", nil)
+	router.ServeHTTP(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusBadRequest)
+}
@@ -0,0 +1,156 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// UndoService deletes activities into a trash, so deletions can be undone with their undo token for a short time
+type UndoService struct {
+	config                 *shared.Config
+	repositoryTxer         shared.RepositoryTxer
+	undoRepository         UndoRepository
+	activityRepository     ActivityRepository
+	goalRepository         GoalRepository
+	activityLinkRepository ActivityLinkRepository
+	projectRepository      ProjectRepository
+}
+
+// NewUndoService creates a new service to undo deletions
+func NewUndoService(
+	config *shared.Config,
+	repositoryTxer shared.RepositoryTxer,
+	undoRepository UndoRepository,
+	activityRepository ActivityRepository,
+	goalRepository GoalRepository,
+	activityLinkRepository ActivityLinkRepository,
+	projectRepository ProjectRepository,
+) *UndoService {
+	return &UndoService{
+		config:                 config,
+		repositoryTxer:         repositoryTxer,
+		undoRepository:         undoRepository,
+		activityRepository:     activityRepository,
+		goalRepository:         goalRepository,
+		activityLinkRepository: activityLinkRepository,
+		projectRepository:      projectRepository,
+	}
+}
+
+// DeleteActivityByID deletes an activity, users delete only their own activities. The activity is kept
+// in the trash for the undo window, so the deletion can be undone with the undo token.
+func (s *UndoService) DeleteActivityByID(ctx context.Context, principal *shared.Principal, activityID uuid.UUID, now time.Time) (*DeletedActivity, error) {
+	activity, err := s.activityRepository.FindActivityByID(ctx, activityID, principal.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !principal.HasRole("ROLE_ADMIN") && activity.Username != principal.Username {
+		return nil, ErrActivityNotFound
+	}
+
+	goals, err := s.goalRepository.FindGoalsOfActivity(ctx, principal.OrganizationID, activityID)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.activityLinkRepository.FindActivityLinks(ctx, principal.OrganizationID, activityID)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedActivity := NewDeletedActivity(activity, goals, links, principal.Username, now, s.config.UndoWindowDuration())
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.undoRepository.DeleteExpiredDeletedActivities(ctx, now)
+		},
+		func(ctx context.Context) error {
+			return s.undoRepository.InsertDeletedActivity(ctx, deletedActivity)
+		},
+		func(ctx context.Context) error {
+			if principal.HasRole("ROLE_ADMIN") {
+				return s.activityRepository.DeleteActivityByID(ctx, principal.OrganizationID, activityID)
+			}
+			return s.activityRepository.DeleteActivityByIDAndUsername(ctx, principal.OrganizationID, activityID, principal.Username)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return deletedActivity, nil
+}
+
+// Undo restores the deleted activity of the undo token with its goals and links, users undo only their own deletions
+func (s *UndoService) Undo(ctx context.Context, principal *shared.Principal, undoToken uuid.UUID, now time.Time) (*Activity, error) {
+	deletedActivity, err := s.undoRepository.FindDeletedActivity(ctx, principal.OrganizationID, undoToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !deletedActivity.CanBeUndoneBy(principal) {
+		return nil, ErrUndoTokenNotFound
+	}
+
+	if deletedActivity.IsExpired(now) {
+		return nil, ErrUndoTokenExpired
+	}
+
+	activity := deletedActivity.Activity
+	_, err = s.projectRepository.FindProjectByID(ctx, principal.OrganizationID, activity.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// goals deleted in the meantime are not linked again
+	var goalIDs []uuid.UUID
+	for _, goalID := range deletedActivity.GoalIDs {
+		_, err := s.goalRepository.FindGoalByID(ctx, principal.OrganizationID, goalID)
+		if errors.Is(err, ErrGoalNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		goalIDs = append(goalIDs, goalID)
+	}
+
+	err = s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			return s.undoRepository.DeleteDeletedActivity(ctx, principal.OrganizationID, undoToken)
+		},
+		func(ctx context.Context) error {
+			_, err := s.activityRepository.InsertActivity(ctx, activity)
+			return err
+		},
+		func(ctx context.Context) error {
+			for _, goalID := range goalIDs {
+				err := s.goalRepository.InsertActivityGoal(ctx, principal.OrganizationID, activity.ID, goalID)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			for _, link := range deletedActivity.Links {
+				err := s.activityLinkRepository.InsertActivityLink(ctx, link)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
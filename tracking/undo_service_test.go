@@ -0,0 +1,116 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func newInMemUndoService(activityRepository *InMemActivityRepository, goalRepository *InMemGoalRepository) *UndoService {
+	return &UndoService{
+		config:                 &shared.Config{UndoWindow: "5m"},
+		repositoryTxer:         shared.NewInMemRepositoryTxer(),
+		undoRepository:         NewInMemUndoRepository(),
+		activityRepository:     activityRepository,
+		goalRepository:         goalRepository,
+		activityLinkRepository: NewInMemActivityLinkRepository(),
+		projectRepository:      NewInMemProjectRepository(),
+	}
+}
+
+func TestDeleteActivityAndUndo(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	goalRepository := NewInMemGoalRepository()
+	s := newInMemUndoService(activityRepository, goalRepository)
+
+	activityID := uuid.MustParse("00000000-0000-0000-2222-000000000001")
+	goal := &Goal{ID: uuid.New(), OrganizationID: shared.OrganizationIDSample, Title: "Grow revenue"}
+	goalRepository.goals = append(goalRepository.goals, goal)
+	err := goalRepository.InsertActivityGoal(context.Background(), shared.OrganizationIDSample, activityID, goal.ID)
+	is.NoErr(err)
+	activityLinkRepository := s.activityLinkRepository.(*InMemActivityLinkRepository)
+	err = activityLinkRepository.InsertActivityLink(context.Background(), &ActivityLink{ID: uuid.New(), OrganizationID: shared.OrganizationIDSample, ActivityID: activityID, URL: "https://example.com/tickets/1"})
+	is.NoErr(err)
+
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	now := time.Date(2021, 10, 4, 10, 0, 0, 0, time.UTC)
+
+	// Act
+	deletedActivity, errDelete := s.DeleteActivityByID(context.Background(), principal, activityID, now)
+	countAfterDelete := len(activityRepository.activities)
+	// the database removes the goals and links together with the activity
+	goalRepository.activityGoals = nil
+	activityLinkRepository.links = nil
+	activity, errUndo := s.Undo(context.Background(), principal, deletedActivity.UndoToken, now.Add(time.Minute))
+	_, errUndoAgain := s.Undo(context.Background(), principal, deletedActivity.UndoToken, now.Add(time.Minute))
+
+	// Assert
+	is.NoErr(errDelete)
+	is.Equal(countAfterDelete, 0)
+	is.Equal(deletedActivity.ExpiresAt, now.Add(5*time.Minute))
+	is.NoErr(errUndo)
+	is.Equal(activity.ID, activityID)
+	is.Equal(len(activityRepository.activities), 1)
+	is.Equal(errUndoAgain, ErrUndoTokenNotFound)
+
+	goals, err := goalRepository.FindGoalsOfActivity(context.Background(), shared.OrganizationIDSample, activityID)
+	is.NoErr(err)
+	is.Equal(len(goals), 1)
+
+	links, err := activityLinkRepository.FindActivityLinks(context.Background(), shared.OrganizationIDSample, activityID)
+	is.NoErr(err)
+	is.Equal(len(links), 1)
+}
+
+func TestUndoExpired(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	s := newInMemUndoService(activityRepository, NewInMemGoalRepository())
+
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	now := time.Date(2021, 10, 4, 10, 0, 0, 0, time.UTC)
+	deletedActivity, err := s.DeleteActivityByID(context.Background(), principal, uuid.MustParse("00000000-0000-0000-2222-000000000001"), now)
+	is.NoErr(err)
+
+	// Act
+	_, errExpired := s.Undo(context.Background(), principal, deletedActivity.UndoToken, now.Add(5*time.Minute))
+
+	// Assert
+	is.Equal(errExpired, ErrUndoTokenExpired)
+	is.Equal(len(activityRepository.activities), 0)
+}
+
+func TestDeleteActivityAndUndoAsOtherUser(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	activityRepository := NewInMemActivityRepository()
+	s := newInMemUndoService(activityRepository, NewInMemGoalRepository())
+
+	activityID := uuid.MustParse("00000000-0000-0000-2222-000000000001")
+	owner := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1"}
+	otherUser := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user2"}
+	admin := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "admin", Roles: []string{"ROLE_ADMIN"}}
+	now := time.Date(2021, 10, 4, 10, 0, 0, 0, time.UTC)
+
+	// Act
+	_, errDeleteOther := s.DeleteActivityByID(context.Background(), otherUser, activityID, now)
+	deletedActivity, errDelete := s.DeleteActivityByID(context.Background(), owner, activityID, now)
+	_, errUndoOther := s.Undo(context.Background(), otherUser, deletedActivity.UndoToken, now)
+	_, errUndoAdmin := s.Undo(context.Background(), admin, deletedActivity.UndoToken, now)
+
+	// Assert
+	is.Equal(errDeleteOther, ErrActivityNotFound)
+	is.NoErr(errDelete)
+	is.Equal(errUndoOther, ErrUndoTokenNotFound)
+	is.NoErr(errUndoAdmin)
+}
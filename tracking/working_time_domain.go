@@ -0,0 +1,172 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/google/uuid"
+)
+
+// Rules of the EU working time directive checked for the tracked activities
+const (
+	WorkingTimeRuleMaxDailyTime  = "max-daily-time"
+	WorkingTimeRuleMinRestPeriod = "min-rest-period"
+	WorkingTimeRuleMissingBreak  = "missing-break"
+)
+
+const (
+	// maxDailyWorkingMinutes is the maximum working time per day
+	maxDailyWorkingMinutes = 10 * 60
+
+	// minRestPeriodMinutes is the minimum rest period between two working days
+	minRestPeriodMinutes = 11 * 60
+
+	// minBreakSegmentMinutes is the minimum length of a gap between activities to count as break
+	minBreakSegmentMinutes = 15
+)
+
+// workingTimeBreakRules are the breaks required after a working time, the longest working time first
+var workingTimeBreakRules = []struct {
+	workingMinutes int
+	breakMinutes   int
+}{
+	{workingMinutes: 9 * 60, breakMinutes: 45},
+	{workingMinutes: 6 * 60, breakMinutes: 30},
+}
+
+// WorkingTimeViolation is a day of a user that violates a rule of the working time directive
+type WorkingTimeViolation struct {
+	Username       string
+	Date           time.Time
+	Rule           string
+	ActualMinutes  int
+	LimitMinutes   int
+	OrganizationID uuid.UUID
+}
+
+type WorkingTimeRepository interface {
+	FindOrganizationsDueForWorkingTimeCheck(ctx context.Context, day time.Time) ([]uuid.UUID, error)
+	FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error)
+	MarkWorkingTimeChecked(ctx context.Context, organizationID uuid.UUID, day time.Time) error
+}
+
+// Message describes the violation for humans
+func (v *WorkingTimeViolation) Message() string {
+	actual := time_utils.FormatMinutesAsDuration(float64(v.ActualMinutes))
+	limit := time_utils.FormatMinutesAsDuration(float64(v.LimitMinutes))
+	switch v.Rule {
+	case WorkingTimeRuleMaxDailyTime:
+		return fmt.Sprintf("worked %s, more than the maximum of %s", actual, limit)
+	case WorkingTimeRuleMinRestPeriod:
+		return fmt.Sprintf("rested %s, less than the minimum of %s", actual, limit)
+	case WorkingTimeRuleMissingBreak:
+		return fmt.Sprintf("took a break of %s, less than the required %s", actual, limit)
+	}
+	return v.Rule
+}
+
+// workingDay is the time a user worked on a day, breaks are the gaps of at least 15 minutes between the activities
+type workingDay struct {
+	Username       string
+	OrganizationID uuid.UUID
+	Date           time.Time
+	Start          time.Time
+	End            time.Time
+	WorkingMinutes int
+	BreakMinutes   int
+}
+
+// CheckWorkingTime checks the activities of each user day by day for too long working days,
+// too short rest periods between two days and missing breaks,
+// breaks deducted by the break policy count as taken and not as working time
+func CheckWorkingTime(activities []*Activity, breakPolicy *BreakPolicy) []*WorkingTimeViolation {
+	var violations []*WorkingTimeViolation
+	var previousDay *workingDay
+	for _, day := range workingDaysOf(activities) {
+		violation := func(rule string, actualMinutes, limitMinutes int) *WorkingTimeViolation {
+			return &WorkingTimeViolation{
+				Username:       day.Username,
+				OrganizationID: day.OrganizationID,
+				Date:           day.Date,
+				Rule:           rule,
+				ActualMinutes:  actualMinutes,
+				LimitMinutes:   limitMinutes,
+			}
+		}
+
+		if previousDay != nil && previousDay.Username == day.Username {
+			restMinutes := int(day.Start.Sub(previousDay.End).Minutes())
+			if restMinutes < minRestPeriodMinutes {
+				violations = append(violations, violation(WorkingTimeRuleMinRestPeriod, restMinutes, minRestPeriodMinutes))
+			}
+		}
+		previousDay = day
+
+		deductionMinutes := breakPolicy.DeductionMinutes(day.WorkingMinutes, day.BreakMinutes)
+		workingMinutes := day.WorkingMinutes - deductionMinutes
+		breakMinutes := day.BreakMinutes + deductionMinutes
+
+		if workingMinutes > maxDailyWorkingMinutes {
+			violations = append(violations, violation(WorkingTimeRuleMaxDailyTime, workingMinutes, maxDailyWorkingMinutes))
+		}
+
+		for _, breakRule := range workingTimeBreakRules {
+			if workingMinutes > breakRule.workingMinutes {
+				if breakMinutes < breakRule.breakMinutes {
+					violations = append(violations, violation(WorkingTimeRuleMissingBreak, breakMinutes, breakRule.breakMinutes))
+				}
+				break
+			}
+		}
+	}
+
+	return violations
+}
+
+// workingDaysOf sums up the activities per user and day, ordered by user and day
+func workingDaysOf(activities []*Activity) []*workingDay {
+	activitiesByUsername := make(map[string][]*Activity)
+	var usernames []string
+	for _, activity := range activities {
+		if _, ok := activitiesByUsername[activity.Username]; !ok {
+			usernames = append(usernames, activity.Username)
+		}
+		activitiesByUsername[activity.Username] = append(activitiesByUsername[activity.Username], activity)
+	}
+	sort.Strings(usernames)
+
+	var workingDays []*workingDay
+	for _, username := range usernames {
+		userActivities := activitiesByUsername[username]
+		sort.SliceStable(userActivities, func(i, j int) bool {
+			return userActivities[i].Start.Before(userActivities[j].Start)
+		})
+
+		var day *workingDay
+		for _, activity := range userActivities {
+			date := truncateToBucket(activity.Start, "day")
+			if day == nil || !day.Date.Equal(date) {
+				day = &workingDay{
+					Username:       activity.Username,
+					OrganizationID: activity.OrganizationID,
+					Date:           date,
+					Start:          activity.Start,
+					End:            activity.End,
+				}
+				workingDays = append(workingDays, day)
+			} else if gapMinutes := int(activity.Start.Sub(day.End).Minutes()); gapMinutes >= minBreakSegmentMinutes {
+				day.BreakMinutes += gapMinutes
+			}
+
+			day.WorkingMinutes += int(activity.End.Sub(activity.Start).Minutes())
+			if activity.End.After(day.End) {
+				day.End = activity.End
+			}
+		}
+	}
+
+	return workingDays
+}
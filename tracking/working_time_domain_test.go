@@ -0,0 +1,115 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func workingTimeActivity(username string, start, end time.Time) *Activity {
+	return &Activity{Username: username, Start: start, End: end}
+}
+
+func TestCheckWorkingTime(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	at := func(days, hours, minutes int) time.Time {
+		return day.AddDate(0, 0, days).Add(time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute)
+	}
+
+	t.Run("compliant day", func(t *testing.T) {
+		is := is.New(t)
+
+		violations := CheckWorkingTime([]*Activity{
+			workingTimeActivity("user1", at(0, 8, 0), at(0, 12, 0)),
+			workingTimeActivity("user1", at(0, 12, 30), at(0, 17, 0)),
+			workingTimeActivity("user1", at(1, 8, 0), at(1, 12, 0)),
+		}, nil)
+		is.Equal(len(violations), 0)
+	})
+
+	t.Run("day exceeding maximum working time", func(t *testing.T) {
+		is := is.New(t)
+
+		violations := CheckWorkingTime([]*Activity{
+			workingTimeActivity("user1", at(0, 7, 0), at(0, 12, 0)),
+			workingTimeActivity("user1", at(0, 13, 0), at(0, 19, 0)),
+		}, nil)
+		is.Equal(len(violations), 1)
+		is.Equal(violations[0].Rule, WorkingTimeRuleMaxDailyTime)
+		is.Equal(violations[0].ActualMinutes, 11*60)
+		is.Equal(violations[0].Date, day)
+		is.Equal(violations[0].Message(), "worked 11:00 h, more than the maximum of 10:00 h")
+	})
+
+	t.Run("insufficient rest period", func(t *testing.T) {
+		is := is.New(t)
+
+		violations := CheckWorkingTime([]*Activity{
+			workingTimeActivity("user1", at(0, 14, 0), at(0, 22, 0)),
+			workingTimeActivity("user1", at(1, 6, 0), at(1, 8, 0)),
+		}, nil)
+		is.Equal(len(violations), 2)
+		is.Equal(violations[0].Rule, WorkingTimeRuleMissingBreak)
+		is.Equal(violations[1].Rule, WorkingTimeRuleMinRestPeriod)
+		is.Equal(violations[1].ActualMinutes, 8*60)
+		is.Equal(violations[1].Date, day.AddDate(0, 0, 1))
+	})
+
+	t.Run("missing break", func(t *testing.T) {
+		is := is.New(t)
+
+		violations := CheckWorkingTime([]*Activity{
+			workingTimeActivity("user1", at(0, 8, 0), at(0, 12, 0)),
+			workingTimeActivity("user1", at(0, 12, 10), at(0, 15, 0)),
+		}, nil)
+		is.Equal(len(violations), 1)
+		is.Equal(violations[0].Rule, WorkingTimeRuleMissingBreak)
+		is.Equal(violations[0].ActualMinutes, 0)
+		is.Equal(violations[0].LimitMinutes, 30)
+	})
+
+	t.Run("longer break after 9 hours", func(t *testing.T) {
+		is := is.New(t)
+
+		violations := CheckWorkingTime([]*Activity{
+			workingTimeActivity("user1", at(0, 7, 0), at(0, 12, 0)),
+			workingTimeActivity("user1", at(0, 12, 30), at(0, 17, 0)),
+		}, nil)
+		is.Equal(len(violations), 1)
+		is.Equal(violations[0].Rule, WorkingTimeRuleMissingBreak)
+		is.Equal(violations[0].ActualMinutes, 30)
+		is.Equal(violations[0].LimitMinutes, 45)
+	})
+
+	t.Run("users are checked separately", func(t *testing.T) {
+		is := is.New(t)
+
+		violations := CheckWorkingTime([]*Activity{
+			workingTimeActivity("user2", at(0, 14, 0), at(0, 20, 0)),
+			workingTimeActivity("user1", at(1, 6, 0), at(1, 8, 0)),
+		}, nil)
+		is.Equal(len(violations), 0)
+	})
+}
+
+func TestCheckWorkingTimeWithBreakDeduction(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	policy := NewDefaultBreakPolicy(shared.OrganizationIDSample)
+	policy.AutoDeduction = true
+
+	violations := CheckWorkingTime([]*Activity{
+		workingTimeActivity("user1", day.Add(8*time.Hour), day.Add(15*time.Hour)),
+	}, policy)
+	is.Equal(len(violations), 0)
+
+	violations = CheckWorkingTime([]*Activity{
+		workingTimeActivity("user1", day.Add(7*time.Hour), day.Add(18*time.Hour)),
+	}, policy)
+	is.Equal(len(violations), 1)
+	is.Equal(violations[0].Rule, WorkingTimeRuleMaxDailyTime)
+	is.Equal(violations[0].ActualMinutes, 11*60-45)
+}
@@ -0,0 +1,78 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DbWorkingTimeRepository is a SQL database repository for working time checks
+type DbWorkingTimeRepository struct {
+	connPool *pgxpool.Pool
+}
+
+var _ WorkingTimeRepository = (*DbWorkingTimeRepository)(nil)
+
+// NewDbWorkingTimeRepository creates a new SQL database repository for working time checks
+func NewDbWorkingTimeRepository(connPool *pgxpool.Pool) *DbWorkingTimeRepository {
+	return &DbWorkingTimeRepository{
+		connPool: connPool,
+	}
+}
+
+// FindOrganizationsDueForWorkingTimeCheck reads the organizations whose activities of the day were not yet checked
+func (r *DbWorkingTimeRepository) FindOrganizationsDueForWorkingTimeCheck(ctx context.Context, day time.Time) ([]uuid.UUID, error) {
+	rows, err := r.connPool.Query(
+		ctx,
+		`SELECT o.org_id 
+		 FROM organizations o 
+		 LEFT JOIN working_time_checks c 
+		 ON c.org_id = o.org_id 
+		 WHERE c.last_checked_day IS NULL OR c.last_checked_day < $1 
+		 ORDER BY o.org_id`,
+		day,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var organizationIDs []uuid.UUID
+	for rows.Next() {
+		var organizationID uuid.UUID
+		err := rows.Scan(&organizationID)
+		if err != nil {
+			return nil, err
+		}
+		organizationIDs = append(organizationIDs, organizationID)
+	}
+
+	return organizationIDs, rows.Err()
+}
+
+// FindTeamMembers reads the enabled users of the organization
+func (r *DbWorkingTimeRepository) FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error) {
+	return findTeamMembers(ctx, r.connPool, organizationID)
+}
+
+// MarkWorkingTimeChecked remembers that the activities of the day were checked
+func (r *DbWorkingTimeRepository) MarkWorkingTimeChecked(ctx context.Context, organizationID uuid.UUID, day time.Time) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO working_time_checks 
+		   (org_id, last_checked_day) 
+		 VALUES 
+		   ($1, $2)
+		 ON CONFLICT (org_id) DO UPDATE 
+		 SET last_checked_day = EXCLUDED.last_checked_day`,
+		organizationID,
+		day,
+	)
+	return err
+}
@@ -0,0 +1,66 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestWorkingTimeRepository(t *testing.T) {
+	// skip in short mode
+	if testing.Short() {
+		return
+	}
+
+	is := is.New(t)
+
+	// Setup database
+	ctx := context.Background()
+	cleanupFunc, connPool, err := shared.SetupTestDatabase(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	defer func() {
+		err := cleanupFunc()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	workingTimeRepository := NewDbWorkingTimeRepository(connPool)
+	repositoryTxer := shared.NewDbRepositoryTxer(connPool)
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("FindTeamMembers", func(t *testing.T) {
+		teamMembers, err := workingTimeRepository.FindTeamMembers(context.Background(), shared.OrganizationIDSample)
+		is.NoErr(err)
+		is.True(len(teamMembers) > 1)
+	})
+
+	t.Run("MarkWorkingTimeChecked", func(t *testing.T) {
+		organizationIDs, err := workingTimeRepository.FindOrganizationsDueForWorkingTimeCheck(context.Background(), day)
+		is.NoErr(err)
+		is.True(len(organizationIDs) > 0)
+		organizationCount := len(organizationIDs)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return workingTimeRepository.MarkWorkingTimeChecked(ctx, shared.OrganizationIDSample, day)
+			},
+		)
+		is.NoErr(err)
+
+		organizationIDs, err = workingTimeRepository.FindOrganizationsDueForWorkingTimeCheck(context.Background(), day)
+		is.NoErr(err)
+		is.Equal(len(organizationIDs), organizationCount-1)
+
+		organizationIDs, err = workingTimeRepository.FindOrganizationsDueForWorkingTimeCheck(context.Background(), day.AddDate(0, 0, 1))
+		is.NoErr(err)
+		is.Equal(len(organizationIDs), organizationCount)
+	})
+}
@@ -0,0 +1,63 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+type InMemWorkingTimeRepository struct {
+	mu             sync.Mutex
+	teamMembers    []*TeamMember
+	lastCheckedDay map[uuid.UUID]time.Time
+}
+
+var _ WorkingTimeRepository = (*InMemWorkingTimeRepository)(nil)
+
+func NewInMemWorkingTimeRepository() *InMemWorkingTimeRepository {
+	return &InMemWorkingTimeRepository{
+		teamMembers: []*TeamMember{
+			{Username: "admin", Name: "Ed Admin", EMail: "admin@baralga.com", Admin: true},
+			{Username: "user1", Name: "Ulani User", EMail: "user1@baralga.com"},
+		},
+		lastCheckedDay: make(map[uuid.UUID]time.Time),
+	}
+}
+
+func (r *InMemWorkingTimeRepository) FindOrganizationsDueForWorkingTimeCheck(ctx context.Context, day time.Time) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	organizationID := shared.OrganizationIDSample
+	if lastCheckedDay, ok := r.lastCheckedDay[organizationID]; ok && !lastCheckedDay.Before(day) {
+		return nil, nil
+	}
+	return []uuid.UUID{organizationID}, nil
+}
+
+func (r *InMemWorkingTimeRepository) FindTeamMembers(ctx context.Context, organizationID uuid.UUID) ([]*TeamMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if organizationID != shared.OrganizationIDSample {
+		return nil, nil
+	}
+
+	teamMembers := make([]*TeamMember, len(r.teamMembers))
+	for i, teamMember := range r.teamMembers {
+		found := *teamMember
+		teamMembers[i] = &found
+	}
+	return teamMembers, nil
+}
+
+func (r *InMemWorkingTimeRepository) MarkWorkingTimeChecked(ctx context.Context, organizationID uuid.UUID, day time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastCheckedDay[organizationID] = day
+	return nil
+}
@@ -0,0 +1,93 @@
+package tracking
+
+import (
+	"net/http"
+
+	"github.com/baralga/shared"
+	"github.com/baralga/shared/hal"
+	time_utils "github.com/baralga/tracking/time"
+	"github.com/go-chi/chi/v5"
+)
+
+type workingTimeReportModel struct {
+	Start      string                       `json:"start"`
+	End        string                       `json:"end"`
+	Violations []*workingTimeViolationModel `json:"violations"`
+	Links      *hal.Links                   `json:"_links"`
+}
+
+type workingTimeViolationModel struct {
+	Username      string `json:"username"`
+	Date          string `json:"date"`
+	Rule          string `json:"rule"`
+	ActualMinutes int    `json:"actualMinutes"`
+	LimitMinutes  int    `json:"limitMinutes"`
+	Message       string `json:"message"`
+}
+
+type WorkingTimeRestHandlers struct {
+	config             *shared.Config
+	workingTimeService *WorkingTimeService
+}
+
+func NewWorkingTimeRestHandlers(config *shared.Config, workingTimeService *WorkingTimeService) *WorkingTimeRestHandlers {
+	return &WorkingTimeRestHandlers{
+		config:             config,
+		workingTimeService: workingTimeService,
+	}
+}
+
+func (a *WorkingTimeRestHandlers) RegisterProtected(r chi.Router) {
+	r.Get("/reports/working-time", a.HandleWorkingTimeReport())
+}
+
+func (a *WorkingTimeRestHandlers) RegisterOpen(r chi.Router) {
+}
+
+// HandleWorkingTimeReport reads the violations of the working time directive like too long days, too short rest periods and missing breaks
+func (a *WorkingTimeRestHandlers) HandleWorkingTimeReport() http.HandlerFunc {
+	isProduction := a.config.IsProduction()
+	workingTimeService := a.workingTimeService
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := r.Context().Value(shared.ContextKeyPrincipal).(*shared.Principal)
+
+		filter, err := filterFromQueryParams(r.URL.Query())
+		if err != nil {
+			shared.RenderValidationProblemJSON(w, "invalid query params", err)
+			return
+		}
+
+		violations, err := workingTimeService.WorkingTimeReport(r.Context(), principal, filter)
+		if err != nil {
+			shared.RenderProblemJSON(w, isProduction, err)
+			return
+		}
+
+		workingTimeReportModel := mapToWorkingTimeReportModel(filter, violations)
+		workingTimeReportModel.Links = hal.NewLinks(
+			hal.NewSelfLink(r.RequestURI),
+		)
+
+		shared.RenderJSON(w, workingTimeReportModel)
+	}
+}
+
+func mapToWorkingTimeReportModel(filter *ActivityFilter, violations []*WorkingTimeViolation) *workingTimeReportModel {
+	violationModels := make([]*workingTimeViolationModel, len(violations))
+	for i, violation := range violations {
+		violationModels[i] = &workingTimeViolationModel{
+			Username:      violation.Username,
+			Date:          time_utils.FormatDate(violation.Date),
+			Rule:          violation.Rule,
+			ActualMinutes: violation.ActualMinutes,
+			LimitMinutes:  violation.LimitMinutes,
+			Message:       violation.Message(),
+		}
+	}
+
+	return &workingTimeReportModel{
+		Start:      time_utils.FormatDate(filter.Start()),
+		End:        time_utils.FormatDate(filter.End()),
+		Violations: violationModels,
+	}
+}
@@ -0,0 +1,38 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/matryer/is"
+)
+
+func TestHandleWorkingTimeReport(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	a := NewWorkingTimeRestHandlers(&shared.Config{}, newInMemWorkingTimeService(shared.NewInMemMailResource(), newInMemWorkingTimeActivityRepository(day)))
+
+	httpRec := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/api/reports/working-time?t=week&v=2024-10", nil)
+	r = r.WithContext(context.WithValue(r.Context(), shared.ContextKeyPrincipal, &shared.Principal{
+		OrganizationID: shared.OrganizationIDSample,
+		Username:       "user1",
+		Roles:          []string{"ROLE_USER"},
+	}))
+	a.HandleWorkingTimeReport()(httpRec, r)
+	is.Equal(httpRec.Result().StatusCode, http.StatusOK)
+
+	reportModel := &workingTimeReportModel{}
+	err := json.NewDecoder(httpRec.Body).Decode(reportModel)
+	is.NoErr(err)
+	is.Equal(len(reportModel.Violations), 2)
+	is.Equal(reportModel.Violations[0].Date, "2024-03-04")
+	is.Equal(reportModel.Violations[0].Rule, WorkingTimeRuleMaxDailyTime)
+	is.Equal(reportModel.Violations[0].ActualMinutes, 660)
+}
@@ -0,0 +1,200 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"text/template"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+)
+
+const workingTimeCheckJobType = "working-time-check"
+
+var workingTimeNotificationTemplate = template.Must(template.New("working-time-notification").Parse(
+	`Hello {{ .Name }},
+
+the activities tracked on {{ .Day.Format "2006-01-02" }} violate the working time directive:
+{{ range .Violations }}
+- {{ .Username }} {{ .Message }}{{ end }}
+
+See the working time report at {{ .ReportLink }}
+`))
+
+// workingTimeNotification is the mail about the violations of a day to an employee or admin
+type workingTimeNotification struct {
+	EMail      string
+	Name       string
+	Day        time.Time
+	Violations []*WorkingTimeViolation
+	ReportLink string
+}
+
+// WorkingTimeService checks the tracked activities against the working time directive
+type WorkingTimeService struct {
+	config                *shared.Config
+	repositoryTxer        shared.RepositoryTxer
+	outbox                shared.Outbox
+	workingTimeRepository WorkingTimeRepository
+	activityRepository    ActivityRepository
+	breakPolicyRepository BreakPolicyRepository
+}
+
+// NewWorkingTimeService creates a new service for working time checks, notifying about violations in the background if enabled
+func NewWorkingTimeService(
+	config *shared.Config,
+	repositoryTxer shared.RepositoryTxer,
+	outbox shared.Outbox,
+	jobService *shared.JobService,
+	workingTimeRepository WorkingTimeRepository,
+	activityRepository ActivityRepository,
+	breakPolicyRepository BreakPolicyRepository,
+) *WorkingTimeService {
+	s := &WorkingTimeService{
+		config:                config,
+		repositoryTxer:        repositoryTxer,
+		outbox:                outbox,
+		workingTimeRepository: workingTimeRepository,
+		activityRepository:    activityRepository,
+		breakPolicyRepository: breakPolicyRepository,
+	}
+
+	if config.WorkingTimeNotifications {
+		jobService.RegisterHandler(workingTimeCheckJobType, s.handleWorkingTimeCheckJob)
+		jobService.Schedule(workingTimeCheckJobType, time.Hour)
+	}
+
+	return s
+}
+
+// WorkingTimeReport checks the activities in the time of the filter for violations of the working time directive,
+// users who are no admins only get their own violations
+func (s *WorkingTimeService) WorkingTimeReport(ctx context.Context, principal *shared.Principal, filter *ActivityFilter) ([]*WorkingTimeViolation, error) {
+	activitiesFilter := toFilter(principal, filter)
+	return s.checkWorkingTime(ctx, activitiesFilter)
+}
+
+// NotifyWorkingTimeViolations checks the activities of yesterday of all organizations not yet checked
+// and notifies employees about their violations and admins about all violations
+func (s *WorkingTimeService) NotifyWorkingTimeViolations(ctx context.Context, now time.Time) error {
+	day := truncateToBucket(now, "day").AddDate(0, 0, -1)
+
+	organizationIDs, err := s.workingTimeRepository.FindOrganizationsDueForWorkingTimeCheck(ctx, day)
+	if err != nil {
+		return err
+	}
+
+	for _, organizationID := range organizationIDs {
+		err := s.notifyWorkingTimeViolations(ctx, organizationID, day)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(organizationIDs) > 0 {
+		log.Printf("checked working time of %v organizations", len(organizationIDs))
+	}
+	return nil
+}
+
+func (s *WorkingTimeService) notifyWorkingTimeViolations(ctx context.Context, organizationID uuid.UUID, day time.Time) error {
+	violations, err := s.checkWorkingTime(ctx, &ActivitiesFilter{
+		Start:          day,
+		End:            day.AddDate(0, 0, 1),
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return err
+	}
+
+	teamMembers, err := s.workingTimeRepository.FindTeamMembers(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	violationsByUsername := make(map[string][]*WorkingTimeViolation)
+	for _, violation := range violations {
+		violationsByUsername[violation.Username] = append(violationsByUsername[violation.Username], violation)
+	}
+
+	date := day.Format("2006-01-02")
+	reportLink := fmt.Sprintf("%s/api/reports/working-time?t=day&v=%s", s.config.Webroot, date)
+	subject := fmt.Sprintf("Working time violations on %s", date)
+
+	var notifications []*workingTimeNotification
+	for _, teamMember := range teamMembers {
+		notification := &workingTimeNotification{
+			EMail:      teamMember.EMail,
+			Name:       teamMember.Name,
+			Day:        day,
+			Violations: violationsByUsername[teamMember.Username],
+			ReportLink: reportLink,
+		}
+		if teamMember.Admin {
+			notification.Violations = violations
+		}
+		if len(notification.Violations) == 0 || teamMember.EMail == "" {
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return s.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			for _, notification := range notifications {
+				body := &bytes.Buffer{}
+				err := workingTimeNotificationTemplate.Execute(body, notification)
+				if err != nil {
+					return err
+				}
+
+				err = s.outbox.SendMail(ctx, organizationID, notification.EMail, subject, body.String())
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			return s.workingTimeRepository.MarkWorkingTimeChecked(ctx, organizationID, day)
+		},
+	)
+}
+
+// checkWorkingTime checks the activities of the filter, including the day before for the rest period of the first day
+func (s *WorkingTimeService) checkWorkingTime(ctx context.Context, activitiesFilter *ActivitiesFilter) ([]*WorkingTimeViolation, error) {
+	breakPolicy, err := s.breakPolicyRepository.FindBreakPolicy(ctx, activitiesFilter.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := activitiesFilter.Start
+	streamFilter := *activitiesFilter
+	streamFilter.Start = start.AddDate(0, 0, -1)
+
+	var activities []*Activity
+	err = s.activityRepository.StreamActivities(ctx, &streamFilter, func(activity *Activity, project *Project) error {
+		activities = append(activities, activity)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []*WorkingTimeViolation
+	for _, violation := range CheckWorkingTime(activities, breakPolicy) {
+		if violation.Date.Before(truncateToBucket(start, "day")) || !violation.Date.Before(activitiesFilter.End) {
+			continue
+		}
+		violations = append(violations, violation)
+	}
+	return violations, nil
+}
+
+func (s *WorkingTimeService) handleWorkingTimeCheckJob(ctx context.Context, job *shared.Job) error {
+	return s.NotifyWorkingTimeViolations(ctx, time.Now())
+}
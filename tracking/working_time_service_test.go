@@ -0,0 +1,81 @@
+package tracking
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baralga/shared"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func newInMemWorkingTimeService(mailResource shared.MailResource, activityRepository ActivityRepository) *WorkingTimeService {
+	repositoryTxer := shared.NewInMemRepositoryTxer()
+	return NewWorkingTimeService(
+		&shared.Config{Webroot: "http://localhost:8080", WorkingTimeNotifications: true},
+		repositoryTxer,
+		shared.NewInMemOutbox(mailResource),
+		shared.NewJobService(repositoryTxer, shared.NewInMemJobRepository()),
+		NewInMemWorkingTimeRepository(),
+		activityRepository,
+		NewInMemBreakPolicyRepository(),
+	)
+}
+
+func newInMemWorkingTimeActivityRepository(day time.Time) *InMemActivityRepository {
+	activityRepository := NewInMemActivityRepository()
+	activityRepository.activities = []*Activity{
+		{
+			ID:             uuid.New(),
+			ProjectID:      shared.ProjectIDSample,
+			OrganizationID: shared.OrganizationIDSample,
+			Username:       "user1",
+			Start:          day.Add(7 * time.Hour),
+			End:            day.Add(18 * time.Hour),
+		},
+	}
+	return activityRepository
+}
+
+func TestWorkingTimeReport(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	s := newInMemWorkingTimeService(shared.NewInMemMailResource(), newInMemWorkingTimeActivityRepository(day))
+	principal := &shared.Principal{OrganizationID: shared.OrganizationIDSample, Username: "user1", Roles: []string{"ROLE_USER"}}
+
+	violations, err := s.WorkingTimeReport(context.Background(), principal, &ActivityFilter{Timespan: TimespanWeek, start: day, end: day.AddDate(0, 0, 7)})
+	is.NoErr(err)
+	is.Equal(len(violations), 2)
+	is.Equal(violations[0].Rule, WorkingTimeRuleMaxDailyTime)
+	is.Equal(violations[1].Rule, WorkingTimeRuleMissingBreak)
+
+	violations, err = s.WorkingTimeReport(context.Background(), principal, &ActivityFilter{Timespan: TimespanWeek, start: day.AddDate(0, 0, 7), end: day.AddDate(0, 0, 14)})
+	is.NoErr(err)
+	is.Equal(len(violations), 0)
+}
+
+func TestNotifyWorkingTimeViolations(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	mailResource := shared.NewInMemMailResource()
+	s := newInMemWorkingTimeService(mailResource, newInMemWorkingTimeActivityRepository(day))
+	now := day.AddDate(0, 0, 1).Add(6 * time.Hour)
+
+	err := s.NotifyWorkingTimeViolations(context.Background(), now)
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 2)
+
+	is.True(strings.HasPrefix(mailResource.Mails[0], "admin@baralga.com"))
+	is.True(strings.HasPrefix(mailResource.Mails[1], "user1@baralga.com"))
+	is.True(strings.Contains(mailResource.Mails[1], "user1 worked 11:00 h, more than the maximum of 10:00 h"))
+	is.True(strings.Contains(mailResource.Mails[1], "/api/reports/working-time?t=day&v=2024-03-04"))
+
+	// day is checked only once
+	err = s.NotifyWorkingTimeViolations(context.Background(), now.Add(time.Hour))
+	is.NoErr(err)
+	is.Equal(len(mailResource.Mails), 2)
+}
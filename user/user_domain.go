@@ -2,12 +2,15 @@ package user
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
 
+	"github.com/baralga/shared"
 	"github.com/google/uuid"
-	"github.com/pkg/errors"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var ErrUserNotFound = shared.NewDomainError("user:not-found", http.StatusNotFound, "user not found")
 
 type User struct {
 	ID             uuid.UUID
@@ -24,14 +27,43 @@ type Organization struct {
 	Title string
 }
 
+// demoNames are the names of the users of demo organizations
+var demoNames = []string{
+	"Anna Schmidt", "Ben Hoffmann", "Clara Weber", "David Becker", "Emma Fischer", "Felix Wagner",
+	"Greta Schulz", "Hannes Koch", "Ida Richter", "Jonas Wolf", "Lea Neumann", "Max Braun",
+}
+
 type UserRepository interface {
 	ConfirmUser(ctx context.Context, userID uuid.UUID) error
 	FindUserIDByConfirmationID(ctx context.Context, confirmationID string) (uuid.UUID, error)
 	InsertUserWithConfirmationID(ctx context.Context, user *User, confirmationID uuid.UUID) (*User, error)
 	FindUserByUsername(ctx context.Context, username string) (*User, error)
+	FindUserByEMail(ctx context.Context, organizationID uuid.UUID, email string) (*User, error)
 	FindRolesByUserID(ctx context.Context, organizationID, userID uuid.UUID) ([]string, error)
+	UpdatePassword(ctx context.Context, organizationID, userID uuid.UUID, password string) error
 }
 
 type OrganizationRepository interface {
 	InsertOrganization(ctx context.Context, organization *Organization) (*Organization, error)
-}
\ No newline at end of file
+}
+
+// NewDemoUsers creates the users of a demo organization with the encrypted password,
+// the usernames are derived from the seed so the users of different seeds do not collide
+func NewDemoUsers(organizationID uuid.UUID, seed int64, count int, encryptedPassword string) []*User {
+	random := rand.New(rand.NewSource(seed))
+	names := random.Perm(len(demoNames))
+
+	users := make([]*User, count)
+	for i := range users {
+		id, _ := uuid.NewRandomFromReader(random)
+		users[i] = &User{
+			ID:             id,
+			Name:           demoNames[names[i%len(names)]],
+			Username:       fmt.Sprintf("demo%v-%v", seed, i+1),
+			Password:       encryptedPassword,
+			Origin:         "demo",
+			OrganizationID: organizationID,
+		}
+	}
+	return users
+}
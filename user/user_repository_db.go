@@ -145,7 +145,7 @@ func (r *DbUserRepository) ConfirmUser(ctx context.Context, userID uuid.UUID) er
 func (r *DbUserRepository) FindUserByUsername(ctx context.Context, username string) (*User, error) {
 	row := r.connPool.QueryRow(
 		ctx,
-		`SELECT user_id, name, password, org_id 
+		`SELECT user_id, name, email, password, org_id 
 		 FROM users 
 		 WHERE username = $1 AND enabled = 1`, username,
 	)
@@ -153,11 +153,12 @@ func (r *DbUserRepository) FindUserByUsername(ctx context.Context, username stri
 	var (
 		id             string
 		name           string
+		email          *string
 		password       string
 		organizationID string
 	)
 
-	err := row.Scan(&id, &name, &password, &organizationID)
+	err := row.Scan(&id, &name, &email, &password, &organizationID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -173,9 +174,69 @@ func (r *DbUserRepository) FindUserByUsername(ctx context.Context, username stri
 		Password:       password,
 		OrganizationID: uuid.MustParse(organizationID),
 	}
+	if email != nil {
+		user.EMail = *email
+	}
 	return user, nil
 }
 
+// FindUserByEMail finds the enabled user of the organization with the email address, ignoring the case
+func (r *DbUserRepository) FindUserByEMail(ctx context.Context, organizationID uuid.UUID, email string) (*User, error) {
+	row := r.connPool.QueryRow(
+		ctx,
+		`SELECT user_id, name, username, password 
+		 FROM users 
+		 WHERE org_id = $1 AND lower(email) = lower($2) AND enabled = 1`, organizationID, email,
+	)
+
+	var (
+		id       string
+		name     string
+		username string
+		password string
+	)
+
+	err := row.Scan(&id, &name, &username, &password)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	user := &User{
+		ID:             uuid.MustParse(id),
+		Name:           name,
+		Username:       username,
+		EMail:          email,
+		Password:       password,
+		OrganizationID: organizationID,
+	}
+	return user, nil
+}
+
+// UpdatePassword replaces the password hash of the user like when rehashing with another algorithm
+func (r *DbUserRepository) UpdatePassword(ctx context.Context, organizationID, userID uuid.UUID, password string) error {
+	tx := ctx.Value(shared.ContextKeyTx).(pgx.Tx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE users
+		 SET password = $3
+		 WHERE user_id = $1 AND org_id = $2`,
+		userID, organizationID, password,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
 func (r *DbUserRepository) FindRolesByUserID(ctx context.Context, organizationID, userID uuid.UUID) ([]string, error) {
 	rows, err := r.connPool.Query(
 		ctx,
@@ -2,14 +2,19 @@ package user
 
 import (
 	"context"
+	"strings"
 
 	"github.com/baralga/shared"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
 
+// clientUserIDSample is the id of the sample client user of the in memory repository
+var clientUserIDSample = uuid.MustParse("00000000-0000-0000-1111-000000000002")
+
 type InMemUserRepository struct {
 	users []*User
+	roles map[uuid.UUID][]string
 }
 
 var _ UserRepository = (*InMemUserRepository)(nil)
@@ -24,6 +29,16 @@ func NewInMemUserRepository() *InMemUserRepository {
 				Password:       "$2a$10$NuzYobDOSTCx/EKBClGwGe0A9c8/yC7D4IP75hwz1jn.RCBfdEtb2",
 				OrganizationID: shared.OrganizationIDSample,
 			},
+			{
+				ID:             clientUserIDSample,
+				Username:       "client@acme.com",
+				EMail:          "client@acme.com",
+				Password:       "$2a$10$NuzYobDOSTCx/EKBClGwGe0A9c8/yC7D4IP75hwz1jn.RCBfdEtb2",
+				OrganizationID: shared.OrganizationIDSample,
+			},
+		},
+		roles: map[uuid.UUID][]string{
+			clientUserIDSample: {"ROLE_CLIENT"},
 		},
 	}
 }
@@ -37,7 +52,19 @@ func (r *InMemUserRepository) FindUserByUsername(ctx context.Context, username s
 	return nil, ErrUserNotFound
 }
 
+func (r *InMemUserRepository) FindUserByEMail(ctx context.Context, organizationID uuid.UUID, email string) (*User, error) {
+	for _, a := range r.users {
+		if a.OrganizationID == organizationID && strings.EqualFold(a.EMail, email) {
+			return a, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
 func (r *InMemUserRepository) FindRolesByUserID(ctx context.Context, organizationID, userID uuid.UUID) ([]string, error) {
+	if roles, ok := r.roles[userID]; ok {
+		return roles, nil
+	}
 	return []string{"ROLE_ADMIN"}, nil
 }
 
@@ -59,3 +86,13 @@ func (r *InMemUserRepository) FindUserIDByConfirmationID(ctx context.Context, co
 func (r *InMemUserRepository) ConfirmUser(ctx context.Context, userID uuid.UUID) error {
 	return nil
 }
+
+func (r *InMemUserRepository) UpdatePassword(ctx context.Context, organizationID, userID uuid.UUID, password string) error {
+	for _, a := range r.users {
+		if a.ID == userID && a.OrganizationID == organizationID {
+			a.Password = password
+			return nil
+		}
+	}
+	return ErrUserNotFound
+}
@@ -54,6 +54,27 @@ func TestUserRepository(t *testing.T) {
 		is.True(errors.Is(err, ErrUserNotFound))
 	})
 
+	t.Run("FindExistingUserByEMail", func(t *testing.T) {
+		adminUser, err := userRepository.FindUserByEMail(
+			context.Background(),
+			shared.OrganizationIDSample,
+			"Admin@Baralga.com",
+		)
+
+		is.NoErr(err)
+		is.Equal(adminUser.Username, "admin@baralga.com")
+	})
+
+	t.Run("FindNotExistingUserByEMail", func(t *testing.T) {
+		_, err := userRepository.FindUserByEMail(
+			context.Background(),
+			shared.OrganizationIDSample,
+			"-not here-",
+		)
+
+		is.True(errors.Is(err, ErrUserNotFound))
+	})
+
 	t.Run("FindRolesByExistingUserID", func(t *testing.T) {
 		roles, err := userRepository.FindRolesByUserID(
 			context.Background(),
@@ -77,6 +98,43 @@ func TestUserRepository(t *testing.T) {
 		is.Equal(len(roles), 0)
 	})
 
+	t.Run("UpdatePassword", func(t *testing.T) {
+		password := "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U"
+
+		err := repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return userRepository.UpdatePassword(
+					ctx,
+					shared.OrganizationIDSample,
+					shared.UserIDAdminSample,
+					password,
+				)
+			},
+		)
+		is.NoErr(err)
+
+		adminUser, err := userRepository.FindUserByUsername(
+			context.Background(),
+			"admin@baralga.com",
+		)
+		is.NoErr(err)
+		is.Equal(adminUser.Password, password)
+
+		err = repositoryTxer.InTx(
+			context.Background(),
+			func(ctx context.Context) error {
+				return userRepository.UpdatePassword(
+					ctx,
+					uuid.New(),
+					shared.UserIDAdminSample,
+					password,
+				)
+			},
+		)
+		is.True(errors.Is(err, ErrUserNotFound))
+	})
+
 	t.Run("InsertUserWithConfirmationID", func(t *testing.T) {
 		user := &User{
 			ID:             uuid.New(),
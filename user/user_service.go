@@ -6,38 +6,41 @@ import (
 
 	"github.com/baralga/shared"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService struct {
 	config                  *shared.Config
 	repositoryTxer          shared.RepositoryTxer
-	mailResource            shared.MailResource
+	outbox                  shared.Outbox
 	userRepository          UserRepository
 	organizationRepository  OrganizationRepository
+	passwordHasher          shared.PasswordHasher
 	organizationInitializer func(ctxWithTx context.Context, organizationID uuid.UUID) error
 }
 
 func NewInMemUserService() *UserService {
 	return &UserService{
 		userRepository: NewInMemUserRepository(),
+		passwordHasher: shared.NewBcryptPasswordHasher(10),
 	}
 }
 
 func NewUserService(
 	config *shared.Config,
 	repositoryTxer shared.RepositoryTxer,
-	mailResource shared.MailResource,
+	outbox shared.Outbox,
 	userRepository UserRepository,
 	organizationRepository OrganizationRepository,
+	passwordHasher shared.PasswordHasher,
 	organizationInitializer func(ctxWithTx context.Context, organizationID uuid.UUID) error,
 ) *UserService {
 	return &UserService{
 		config:                  config,
 		repositoryTxer:          repositoryTxer,
-		mailResource:            mailResource,
+		outbox:                  outbox,
 		userRepository:          userRepository,
 		organizationRepository:  organizationRepository,
+		passwordHasher:          passwordHasher,
 		organizationInitializer: organizationInitializer,
 	}
 }
@@ -51,9 +54,9 @@ func (a *UserService) ConfirmUser(ctx context.Context, userID uuid.UUID) error {
 	)
 }
 
-func (a *UserService) EncryptPassword(password string) string {
-	encryptedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
-	return string(encryptedPassword)
+// EncryptPassword hashes the password with the configured password hasher
+func (a *UserService) EncryptPassword(password string) (string, error) {
+	return a.passwordHasher.Hash(password)
 }
 
 func (a *UserService) SetUpNewUser(ctx context.Context, user *User, confirmationID uuid.UUID) error {
@@ -101,7 +104,30 @@ func (a *UserService) SetUpNewUser(ctx context.Context, user *User, confirmation
 			if user.EMail == "" {
 				return nil
 			}
-			return a.mailResource.SendMail(user.EMail, subject, body)
+			return a.outbox.SendMail(ctx, organization.ID, user.EMail, subject, body)
+		},
+	)
+}
+
+// SetUpDemoOrganization creates the organization with its confirmed users and seeds it within the same transaction
+func (a *UserService) SetUpDemoOrganization(ctx context.Context, organization *Organization, users []*User, seedOrganization func(ctxWithTx context.Context, organizationID uuid.UUID) error) error {
+	return a.repositoryTxer.InTx(
+		ctx,
+		func(ctx context.Context) error {
+			_, err := a.organizationRepository.InsertOrganization(ctx, organization)
+			return err
+		},
+		func(ctx context.Context) error {
+			for _, user := range users {
+				_, err := a.userRepository.InsertUserWithConfirmationID(ctx, user, uuid.Nil)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			return seedOrganization(ctx, organization.ID)
 		},
 	)
 }
@@ -25,7 +25,7 @@ func TestSetUpNewUser(t *testing.T) {
 	a := &UserService{
 		config:                 &shared.Config{},
 		repositoryTxer:         shared.NewInMemRepositoryTxer(),
-		mailResource:           mailResource,
+		outbox:                 shared.NewInMemOutbox(mailResource),
 		userRepository:         userRepository,
 		organizationRepository: organizationRepository,
 		organizationInitializer: func(ctxWithTx context.Context, organizationID uuid.UUID) error {
@@ -64,7 +64,7 @@ func TestSetUpNewUserWithUserRepositoryError(t *testing.T) {
 	a := &UserService{
 		config:                 &shared.Config{},
 		repositoryTxer:         shared.NewInMemRepositoryTxer(),
-		mailResource:           mailResource,
+		outbox:                 shared.NewInMemOutbox(mailResource),
 		userRepository:         userRepository,
 		organizationRepository: organizationRepository,
 		organizationInitializer: func(ctxWithTx context.Context, organizationID uuid.UUID) error {
@@ -85,3 +85,39 @@ func TestSetUpNewUserWithUserRepositoryError(t *testing.T) {
 	is.True(err != nil)
 	is.Equal(len(mailResource.Mails), mailCount)
 }
+
+func TestSetUpDemoOrganization(t *testing.T) {
+	// Arrange
+	is := is.New(t)
+
+	userRepository := NewInMemUserRepository()
+	userCount := len(userRepository.users)
+
+	organizationRepository := NewInMemOrganizationRepository()
+	organizationCount := len(organizationRepository.organizations)
+
+	a := &UserService{
+		config:                 &shared.Config{},
+		repositoryTxer:         shared.NewInMemRepositoryTxer(),
+		userRepository:         userRepository,
+		organizationRepository: organizationRepository,
+	}
+
+	organization := &Organization{ID: uuid.New(), Title: "Demo Organization"}
+	users := NewDemoUsers(organization.ID, 42, 3, "encrypted")
+	var seededOrganizationID uuid.UUID
+
+	// Act
+	err := a.SetUpDemoOrganization(context.Background(), organization, users, func(ctxWithTx context.Context, organizationID uuid.UUID) error {
+		seededOrganizationID = organizationID
+		return nil
+	})
+
+	// Assert
+	is.NoErr(err)
+	is.Equal(len(organizationRepository.organizations), organizationCount+1)
+	is.Equal(len(userRepository.users), userCount+3)
+	is.Equal(seededOrganizationID, organization.ID)
+	is.Equal(users[0].Username, "demo42-1")
+	is.Equal(NewDemoUsers(organization.ID, 42, 3, "encrypted")[2].Name, users[2].Name)
+}
@@ -23,19 +23,23 @@ type signupFormModel struct {
 	EMail            string `validate:"required,email"`
 	Password         string `validate:"required,min=8,max=100"`
 	AcceptConditions bool
+
+	captcha g.Node
 }
 
 type UserWebHandlers struct {
 	config         *shared.Config
 	userService    *UserService
 	userRepository UserRepository
+	captchaGuard   *shared.CaptchaGuard
 }
 
-func NewUserWeb(config *shared.Config, userService *UserService, userRepository UserRepository) *UserWebHandlers {
+func NewUserWeb(config *shared.Config, userService *UserService, userRepository UserRepository, captchaGuard *shared.CaptchaGuard) *UserWebHandlers {
 	return &UserWebHandlers{
 		config:         config,
 		userService:    userService,
 		userRepository: userRepository,
+		captchaGuard:   captchaGuard,
 	}
 }
 
@@ -115,14 +119,16 @@ func (a *UserWebHandlers) HandleSignUpFormValidate() http.HandlerFunc {
 		if err != nil {
 			formModel := signupFormModel{}
 			formModel.CSRFToken = csrf.Token(r)
+			formModel.captcha = a.signupCaptcha(r)
 			shared.RenderHTML(w, a.SignupForm(formModel, "", nil))
 			return
 		}
 
 		var formModel signupFormModel
-		err = schema.NewDecoder().Decode(&formModel, r.PostForm)
+		err = schema.NewDecoder().Decode(&formModel, shared.WithoutCaptchaResponse(r.PostForm))
 		if err != nil {
 			formModel.CSRFToken = csrf.Token(r)
+			formModel.captcha = a.signupCaptcha(r)
 			shared.RenderHTML(w, a.SignupForm(formModel, "", nil))
 			return
 		}
@@ -130,11 +136,13 @@ func (a *UserWebHandlers) HandleSignUpFormValidate() http.HandlerFunc {
 		fieldErrors, err := validate(r.Context(), formModel)
 		if err != nil {
 			formModel.CSRFToken = csrf.Token(r)
+			formModel.captcha = a.signupCaptcha(r)
 			shared.RenderHTML(w, a.SignupForm(formModel, "", fieldErrors))
 			return
 		}
 
 		formModel.CSRFToken = csrf.Token(r)
+		formModel.captcha = a.signupCaptcha(r)
 		shared.RenderHTML(w, a.SignupForm(formModel, "", fieldErrors))
 	}
 }
@@ -143,6 +151,7 @@ func (a *UserWebHandlers) HandleSignUpPage() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		formModel := signupFormModel{}
 		formModel.CSRFToken = csrf.Token(r)
+		formModel.captcha = a.signupCaptcha(r)
 		shared.RenderHTML(w, a.SignUpPage(r.URL.Path, formModel))
 	}
 }
@@ -151,19 +160,22 @@ func (a *UserWebHandlers) HandleSignUpForm() http.HandlerFunc {
 	isProduction := a.config.IsProduction()
 	validate := a.signupFormValidator(false)
 	userService := a.userService
+	captchaGuard := a.captchaGuard
 	return func(w http.ResponseWriter, r *http.Request) {
 		err := r.ParseForm()
 		if err != nil {
 			formModel := signupFormModel{}
 			formModel.CSRFToken = csrf.Token(r)
+			formModel.captcha = a.signupCaptcha(r)
 			shared.RenderHTML(w, a.SignupForm(formModel, "", nil))
 			return
 		}
 
 		var formModel signupFormModel
-		err = schema.NewDecoder().Decode(&formModel, r.PostForm)
+		err = schema.NewDecoder().Decode(&formModel, shared.WithoutCaptchaResponse(r.PostForm))
 		if err != nil {
 			formModel.CSRFToken = csrf.Token(r)
+			formModel.captcha = a.signupCaptcha(r)
 			shared.RenderHTML(w, a.SignupForm(formModel, "", nil))
 			return
 		}
@@ -171,11 +183,29 @@ func (a *UserWebHandlers) HandleSignUpForm() http.HandlerFunc {
 		fieldErrors, err := validate(r.Context(), formModel)
 		if err != nil {
 			formModel.CSRFToken = csrf.Token(r)
+			formModel.captcha = a.signupCaptcha(r)
 			shared.RenderHTML(w, a.SignupForm(formModel, "", fieldErrors))
 			return
 		}
 
-		user := mapSignUpFormToUser(formModel, userService.EncryptPassword(formModel.Password))
+		err = captchaGuard.CheckRequest(r, shared.RemoteIP(r))
+		if err != nil {
+			formModel.CSRFToken = csrf.Token(r)
+			formModel.captcha = a.signupCaptcha(r)
+			shared.RenderHTML(w, a.SignupForm(formModel, "Please confirm that you are not a robot.", nil))
+			return
+		}
+
+		// many signups of the same ip address are suspicious and require a captcha
+		captchaGuard.RecordAttempt(shared.RemoteIP(r))
+
+		encryptedPassword, err := userService.EncryptPassword(formModel.Password)
+		if err != nil {
+			shared.RenderProblemHTML(w, isProduction, err)
+			return
+		}
+
+		user := mapSignUpFormToUser(formModel, encryptedPassword)
 		confirmationID := uuid.New()
 		err = userService.SetUpNewUser(r.Context(), &user, confirmationID)
 		if err != nil {
@@ -191,6 +221,7 @@ func (a *UserWebHandlers) SignUpPage(currentPath string, formModel signupFormMod
 	return shared.Page(
 		"Sign Up",
 		currentPath,
+		nil,
 		[]g.Node{
 			Section(
 				Class("full-center"),
@@ -222,6 +253,11 @@ func (a *UserWebHandlers) SignUpPage(currentPath string, formModel signupFormMod
 	)
 }
 
+// signupCaptcha renders the captcha if it's required for signups of the ip address of the request
+func (a *UserWebHandlers) signupCaptcha(r *http.Request) g.Node {
+	return a.captchaGuard.Widget(shared.RemoteIP(r))
+}
+
 func SignupSuccess(formModel signupFormModel) g.Node {
 	return Div(
 		Class("alert alert-success"),
@@ -352,6 +388,7 @@ func (a *UserWebHandlers) SignupForm(formModel signupFormModel, errorMessage str
 				),
 			),
 		),
+		formModel.captcha,
 		Div(
 			Class("container-fluid text-center"),
 			Button(
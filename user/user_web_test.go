@@ -20,7 +20,8 @@ func TestHandleSignUpPage(t *testing.T) {
 	httpRec := httptest.NewRecorder()
 
 	a := &UserWebHandlers{
-		config: &shared.Config{},
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
 	}
 
 	r, _ := http.NewRequest("GET", "/signup", nil)
@@ -41,12 +42,14 @@ func TestHandleSignUpFormWithSuccessfullSignUp(t *testing.T) {
 
 	config := &shared.Config{}
 	w := &UserWebHandlers{
-		config: config,
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       config,
 		userService: &UserService{
 			config:                 config,
 			repositoryTxer:         shared.NewInMemRepositoryTxer(),
-			mailResource:           mailService,
+			outbox:                 shared.NewInMemOutbox(mailService),
 			organizationRepository: NewInMemOrganizationRepository(),
+			passwordHasher:         shared.NewBcryptPasswordHasher(4),
 			organizationInitializer: func(ctxWithTx context.Context, organizationID uuid.UUID) error {
 				organizationInitializerCalled = true
 				return nil
@@ -75,7 +78,8 @@ func TestHandleSignUpFormWithInvalidData(t *testing.T) {
 	httpRec := httptest.NewRecorder()
 
 	a := &UserWebHandlers{
-		config: &shared.Config{},
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
 	}
 
 	data := url.Values{}
@@ -94,7 +98,8 @@ func TestHandleSignUpFormWithInvalidFormData(t *testing.T) {
 	httpRec := httptest.NewRecorder()
 
 	a := &UserWebHandlers{
-		config: &shared.Config{},
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
 	}
 
 	r, _ := http.NewRequest("POST", "/signup", strings.NewReader("Not a form!!"))
@@ -110,7 +115,8 @@ func TestHandleSignUpFormValidation(t *testing.T) {
 
 	userRepository := NewInMemUserRepository()
 	a := &UserWebHandlers{
-		config: &shared.Config{},
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
 		userService: &UserService{
 			repositoryTxer:         shared.NewInMemRepositoryTxer(),
 			organizationRepository: NewInMemOrganizationRepository(),
@@ -142,7 +148,8 @@ func TestHandleSignUpConfirmWithExistingConfirmation(t *testing.T) {
 	config := &shared.Config{}
 
 	w := &UserWebHandlers{
-		config: config,
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       config,
 		userService: &UserService{
 			config:                 config,
 			repositoryTxer:         shared.NewInMemRepositoryTxer(),
@@ -174,7 +181,8 @@ func TestHandleSignUpConfirmWithoutConfirmation(t *testing.T) {
 	userRepository := NewInMemUserRepository()
 
 	a := &UserWebHandlers{
-		config: &shared.Config{},
+		captchaGuard: shared.NewCaptchaGuard(nil, 0, 0),
+		config:       &shared.Config{},
 		userService: &UserService{
 			repositoryTxer:         shared.NewInMemRepositoryTxer(),
 			organizationRepository: NewInMemOrganizationRepository(),